@@ -2,6 +2,7 @@ package acl
 
 import (
 	"fmt"
+	"strings"
 
 	iradix "github.com/hashicorp/go-immutable-radix"
 )
@@ -44,12 +45,30 @@ type ACL struct {
 	// namespaces maps a namespace to a capabilitySet
 	namespaces *iradix.Tree
 
+	// variables maps a namespace to the path rules of its variables
+	variables map[string][]*variablesPathRule
+
 	agent    string
 	node     string
 	operator string
 	quota    string
 }
 
+// variablesPathRule is the capabilitySet granted on the variables matching
+// a path spec
+type variablesPathRule struct {
+	pathSpec     string
+	capabilities capabilitySet
+}
+
+// matches returns whether the path matches the rule's path spec
+func (r *variablesPathRule) matches(path string) bool {
+	if strings.HasSuffix(r.pathSpec, "*") {
+		return strings.HasPrefix(path, strings.TrimSuffix(r.pathSpec, "*"))
+	}
+	return path == r.pathSpec
+}
+
 // maxPrivilege returns the policy which grants the most privilege
 // This handles the case of Deny always taking maximum precedence.
 func maxPrivilege(a, b string) string {
@@ -73,12 +92,25 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	}
 
 	// Create the ACL object
-	acl := &ACL{}
+	acl := &ACL{
+		variables: make(map[string][]*variablesPathRule),
+	}
 	nsTxn := iradix.New().Txn()
 
 	for _, policy := range policies {
 	NAMESPACES:
 		for _, ns := range policy.Namespaces {
+			// Add the variables rules, including the ones granted by the
+			// short hand policy
+			if ns.Policy != "" {
+				acl.addVariablesRule(ns.Name, "*", expandVariablesPolicy(ns.Policy))
+			}
+			if ns.Variables != nil {
+				for _, path := range ns.Variables.Paths {
+					acl.addVariablesRule(ns.Name, path.PathSpec, path.Capabilities)
+				}
+			}
+
 			// Check for existing capabilities
 			var capabilities capabilitySet
 			raw, ok := nsTxn.Get([]byte(ns.Name))
@@ -126,6 +158,19 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	return acl, nil
 }
 
+// addVariablesRule adds a rule granting the capabilities on the variables of
+// the namespace matching the path spec.
+func (a *ACL) addVariablesRule(ns, pathSpec string, caps []string) {
+	rule := &variablesPathRule{
+		pathSpec:     pathSpec,
+		capabilities: make(capabilitySet, len(caps)),
+	}
+	for _, cap := range caps {
+		rule.capabilities.Set(cap)
+	}
+	a.variables[ns] = append(a.variables[ns], rule)
+}
+
 // AllowNsOp is shorthand for AllowNamespaceOperation
 func (a *ACL) AllowNsOp(ns string, op string) bool {
 	return a.AllowNamespaceOperation(ns, op)
@@ -171,6 +216,46 @@ func (a *ACL) AllowNamespace(ns string) bool {
 	return !capabilities.Check(PolicyDeny)
 }
 
+// AllowVariableOperation checks if a given operation is allowed on the
+// variable at a path of a namespace. The capabilities of all the rules
+// matching the path are combined, unless one of them denies access.
+func (a *ACL) AllowVariableOperation(ns, path, op string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	allowed := false
+	for _, rule := range a.variables[ns] {
+		if !rule.matches(path) {
+			continue
+		}
+		if rule.capabilities.Check(VariablesCapabilityDeny) {
+			return false
+		}
+		if rule.capabilities.Check(op) {
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+// AllowVariableSearch checks if variables of a namespace may be listed. The
+// listed variables must still be filtered with AllowVariableOperation.
+func (a *ACL) AllowVariableSearch(ns string) bool {
+	// Hot path management tokens
+	if a.management {
+		return true
+	}
+
+	for _, rule := range a.variables[ns] {
+		if rule.capabilities.Check(VariablesCapabilityList) {
+			return true
+		}
+	}
+	return false
+}
+
 // AllowAgentRead checks if read operations are allowed for an agent
 func (a *ACL) AllowAgentRead() bool {
 	switch {
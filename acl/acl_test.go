@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCapabilitySet(t *testing.T) {
@@ -262,3 +263,64 @@ func TestAllowNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowVariableOperation(t *testing.T) {
+	policy, err := Parse(`
+namespace "default" {
+	policy = "read"
+	variables {
+		path "app/*" {
+			capabilities = ["write"]
+		}
+		path "app/secret" {
+			capabilities = ["deny"]
+		}
+	}
+}
+namespace "other" {
+	variables {
+		path "team/db" {
+			capabilities = ["read"]
+		}
+	}
+}
+`)
+	require.NoError(t, err)
+
+	acl, err := NewACL(false, []*Policy{policy})
+	require.NoError(t, err)
+
+	tests := []struct {
+		Namespace string
+		Path      string
+		Op        string
+		Allow     bool
+	}{
+		// The namespace policy grants read on every path
+		{"default", "foo", VariablesCapabilityRead, true},
+		{"default", "foo", VariablesCapabilityList, true},
+		{"default", "foo", VariablesCapabilityWrite, false},
+
+		// The capabilities of the matching paths are combined
+		{"default", "app/web", VariablesCapabilityWrite, true},
+		{"default", "app/web", VariablesCapabilityRead, true},
+		{"default", "app/web", VariablesCapabilityDestroy, false},
+
+		// Deny takes precedence
+		{"default", "app/secret", VariablesCapabilityRead, false},
+
+		// Exact paths only match themselves
+		{"other", "team/db", VariablesCapabilityRead, true},
+		{"other", "team/db2", VariablesCapabilityRead, false},
+		{"other", "team/db", VariablesCapabilityList, false},
+		{"missing", "team/db", VariablesCapabilityRead, false},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.Allow, acl.AllowVariableOperation(tc.Namespace, tc.Path, tc.Op), "%#v", tc)
+	}
+
+	require.True(t, acl.AllowVariableSearch("default"))
+	require.False(t, acl.AllowVariableSearch("other"))
+	require.True(t, ManagementACL.AllowVariableOperation("other", "team/db", VariablesCapabilityDestroy))
+}
@@ -28,9 +28,24 @@ const (
 	NamespaceCapabilityDispatchJob      = "dispatch-job"
 	NamespaceCapabilityReadLogs         = "read-logs"
 	NamespaceCapabilityReadFS           = "read-fs"
+	NamespaceCapabilityAllocExec        = "alloc-exec"
+	NamespaceCapabilityAllocLifecycle   = "alloc-lifecycle"
 	NamespaceCapabilitySentinelOverride = "sentinel-override"
 )
 
+const (
+	// The following are the capabilities that can be granted on the paths of
+	// the variables of a namespace. The namespace policy grants the list and
+	// read capabilities on every path for "read", and all of them for
+	// "write". If the deny capability is present on a matching path, it
+	// takes precedence and overwrites all other capabilities.
+	VariablesCapabilityDeny    = "deny"
+	VariablesCapabilityList    = "list"
+	VariablesCapabilityRead    = "read"
+	VariablesCapabilityWrite   = "write"
+	VariablesCapabilityDestroy = "destroy"
+)
+
 var (
 	validNamespace = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
 )
@@ -60,6 +75,19 @@ type NamespacePolicy struct {
 	Name         string `hcl:",key"`
 	Policy       string
 	Capabilities []string
+	Variables    *VariablesPolicy `hcl:"variables"`
+}
+
+// VariablesPolicy is the policy for the variables of a namespace
+type VariablesPolicy struct {
+	Paths []*VariablesPathPolicy `hcl:"path,expand"`
+}
+
+// VariablesPathPolicy grants capabilities on the variables matching the path
+// spec. A path spec ending with "*" matches every path with that prefix.
+type VariablesPathPolicy struct {
+	PathSpec     string `hcl:",key"`
+	Capabilities []string
 }
 
 type AgentPolicy struct {
@@ -93,7 +121,7 @@ func isNamespaceCapabilityValid(cap string) bool {
 	switch cap {
 	case NamespaceCapabilityDeny, NamespaceCapabilityListJobs, NamespaceCapabilityReadJob,
 		NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob, NamespaceCapabilityReadLogs,
-		NamespaceCapabilityReadFS:
+		NamespaceCapabilityReadFS, NamespaceCapabilityAllocExec, NamespaceCapabilityAllocLifecycle:
 		return true
 	// Separate the enterprise-only capabilities
 	case NamespaceCapabilitySentinelOverride:
@@ -103,6 +131,41 @@ func isNamespaceCapabilityValid(cap string) bool {
 	}
 }
 
+// isVariablesCapabilityValid ensures the given capability is valid for a
+// variables path policy
+func isVariablesCapabilityValid(cap string) bool {
+	switch cap {
+	case VariablesCapabilityDeny, VariablesCapabilityList, VariablesCapabilityRead,
+		VariablesCapabilityWrite, VariablesCapabilityDestroy:
+		return true
+	default:
+		return false
+	}
+}
+
+// expandVariablesPolicy provides the equivalent set of variables
+// capabilities for a namespace policy
+func expandVariablesPolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{VariablesCapabilityDeny}
+	case PolicyRead:
+		return []string{
+			VariablesCapabilityList,
+			VariablesCapabilityRead,
+		}
+	case PolicyWrite:
+		return []string{
+			VariablesCapabilityList,
+			VariablesCapabilityRead,
+			VariablesCapabilityWrite,
+			VariablesCapabilityDestroy,
+		}
+	default:
+		return nil
+	}
+}
+
 // expandNamespacePolicy provides the equivalent set of capabilities for
 // a namespace policy
 func expandNamespacePolicy(policy string) []string {
@@ -122,6 +185,8 @@ func expandNamespacePolicy(policy string) []string {
 			NamespaceCapabilityDispatchJob,
 			NamespaceCapabilityReadLogs,
 			NamespaceCapabilityReadFS,
+			NamespaceCapabilityAllocExec,
+			NamespaceCapabilityAllocLifecycle,
 		}
 	default:
 		return nil
@@ -170,6 +235,22 @@ func Parse(rules string) (*Policy, error) {
 			extraCap := expandNamespacePolicy(ns.Policy)
 			ns.Capabilities = append(ns.Capabilities, extraCap...)
 		}
+
+		if ns.Variables != nil {
+			if len(ns.Variables.Paths) == 0 {
+				return nil, fmt.Errorf("Invalid variables policy, no paths: %#v", ns)
+			}
+			for _, path := range ns.Variables.Paths {
+				if path.PathSpec == "" {
+					return nil, fmt.Errorf("Invalid variables path policy, empty path: %#v", ns)
+				}
+				for _, cap := range path.Capabilities {
+					if !isVariablesCapabilityValid(cap) {
+						return nil, fmt.Errorf("Invalid variables capability '%s': %#v", cap, path)
+					}
+				}
+			}
+		}
 	}
 
 	if p.Agent != nil && !isPolicyValid(p.Agent.Policy) {
@@ -80,6 +80,8 @@ func TestParse(t *testing.T) {
 							NamespaceCapabilityDispatchJob,
 							NamespaceCapabilityReadLogs,
 							NamespaceCapabilityReadFS,
+							NamespaceCapabilityAllocExec,
+							NamespaceCapabilityAllocLifecycle,
 						},
 					},
 					{
@@ -178,6 +180,49 @@ func TestParse(t *testing.T) {
 			"Invalid namespace name",
 			nil,
 		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "app/*" {
+						capabilities = ["read", "list"]
+					}
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "default",
+						Variables: &VariablesPolicy{
+							Paths: []*VariablesPathPolicy{
+								{
+									PathSpec: "app/*",
+									Capabilities: []string{
+										VariablesCapabilityRead,
+										VariablesCapabilityList,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			namespace "default" {
+				variables {
+					path "app/*" {
+						capabilities = ["submit-job"]
+					}
+				}
+			}
+			`,
+			"Invalid variables capability",
+			nil,
+		},
 		{
 			`
 			namespace "default" {
@@ -62,6 +62,243 @@ func (a *ACLPolicies) Info(policyName string, q *QueryOptions) (*ACLPolicy, *Que
 	return &resp, wm, nil
 }
 
+// ACLRoles is used to query the ACL Role endpoints.
+type ACLRoles struct {
+	client *Client
+}
+
+// ACLRoles returns a new handle on the ACL roles.
+func (c *Client) ACLRoles() *ACLRoles {
+	return &ACLRoles{client: c}
+}
+
+// List is used to dump all of the roles.
+func (a *ACLRoles) List(q *QueryOptions) ([]*ACLRoleListStub, *QueryMeta, error) {
+	var resp []*ACLRoleListStub
+	qm, err := a.client.query("/v1/acl/roles", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Upsert is used to create or update a role
+func (a *ACLRoles) Upsert(role *ACLRole, q *WriteOptions) (*WriteMeta, error) {
+	if role == nil || role.Name == "" {
+		return nil, fmt.Errorf("missing role name")
+	}
+	wm, err := a.client.write("/v1/acl/role/"+role.Name, role, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete a role
+func (a *ACLRoles) Delete(roleName string, q *WriteOptions) (*WriteMeta, error) {
+	if roleName == "" {
+		return nil, fmt.Errorf("missing role name")
+	}
+	wm, err := a.client.delete("/v1/acl/role/"+roleName, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Info is used to query a specific role
+func (a *ACLRoles) Info(roleName string, q *QueryOptions) (*ACLRole, *QueryMeta, error) {
+	if roleName == "" {
+		return nil, nil, fmt.Errorf("missing role name")
+	}
+	var resp ACLRole
+	wm, err := a.client.query("/v1/acl/role/"+roleName, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// ACLAuthMethods is used to query the ACL auth method endpoints.
+type ACLAuthMethods struct {
+	client *Client
+}
+
+// ACLAuthMethods returns a new handle on the ACL auth methods.
+func (c *Client) ACLAuthMethods() *ACLAuthMethods {
+	return &ACLAuthMethods{client: c}
+}
+
+// List is used to dump all of the auth methods.
+func (a *ACLAuthMethods) List(q *QueryOptions) ([]*ACLAuthMethodListStub, *QueryMeta, error) {
+	var resp []*ACLAuthMethodListStub
+	qm, err := a.client.query("/v1/acl/auth-methods", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Upsert is used to create or update an auth method
+func (a *ACLAuthMethods) Upsert(method *ACLAuthMethod, q *WriteOptions) (*WriteMeta, error) {
+	if method == nil || method.Name == "" {
+		return nil, fmt.Errorf("missing auth method name")
+	}
+	wm, err := a.client.write("/v1/acl/auth-method/"+method.Name, method, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete an auth method along with its binding rules
+func (a *ACLAuthMethods) Delete(methodName string, q *WriteOptions) (*WriteMeta, error) {
+	if methodName == "" {
+		return nil, fmt.Errorf("missing auth method name")
+	}
+	wm, err := a.client.delete("/v1/acl/auth-method/"+methodName, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Info is used to query a specific auth method
+func (a *ACLAuthMethods) Info(methodName string, q *QueryOptions) (*ACLAuthMethod, *QueryMeta, error) {
+	if methodName == "" {
+		return nil, nil, fmt.Errorf("missing auth method name")
+	}
+	var resp ACLAuthMethod
+	wm, err := a.client.query("/v1/acl/auth-method/"+methodName, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// ACLBindingRules is used to query the ACL binding rule endpoints.
+type ACLBindingRules struct {
+	client *Client
+}
+
+// ACLBindingRules returns a new handle on the ACL binding rules.
+func (c *Client) ACLBindingRules() *ACLBindingRules {
+	return &ACLBindingRules{client: c}
+}
+
+// List is used to dump the binding rules. Setting the auth_method query
+// parameter lists the rules of a single auth method.
+func (a *ACLBindingRules) List(q *QueryOptions) ([]*ACLBindingRuleListStub, *QueryMeta, error) {
+	var resp []*ACLBindingRuleListStub
+	qm, err := a.client.query("/v1/acl/binding-rules", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Create is used to create a binding rule
+func (a *ACLBindingRules) Create(rule *ACLBindingRule, q *WriteOptions) (*ACLBindingRule, *WriteMeta, error) {
+	if rule.ID != "" {
+		return nil, nil, fmt.Errorf("cannot specify ID")
+	}
+	var resp ACLBindingRule
+	wm, err := a.client.write("/v1/acl/binding-rule", rule, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Update is used to update an existing binding rule
+func (a *ACLBindingRules) Update(rule *ACLBindingRule, q *WriteOptions) (*ACLBindingRule, *WriteMeta, error) {
+	if rule.ID == "" {
+		return nil, nil, fmt.Errorf("missing binding rule ID")
+	}
+	var resp ACLBindingRule
+	wm, err := a.client.write("/v1/acl/binding-rule/"+rule.ID, rule, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete a binding rule
+func (a *ACLBindingRules) Delete(ruleID string, q *WriteOptions) (*WriteMeta, error) {
+	if ruleID == "" {
+		return nil, fmt.Errorf("missing binding rule ID")
+	}
+	wm, err := a.client.delete("/v1/acl/binding-rule/"+ruleID, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Info is used to query a binding rule
+func (a *ACLBindingRules) Info(ruleID string, q *QueryOptions) (*ACLBindingRule, *QueryMeta, error) {
+	if ruleID == "" {
+		return nil, nil, fmt.Errorf("missing binding rule ID")
+	}
+	var resp ACLBindingRule
+	wm, err := a.client.query("/v1/acl/binding-rule/"+ruleID, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// ACLAuth is used to log in with an auth method.
+type ACLAuth struct {
+	client *Client
+}
+
+// ACLAuth returns a new handle on the ACL login endpoints.
+func (c *Client) ACLAuth() *ACLAuth {
+	return &ACLAuth{client: c}
+}
+
+// Login exchanges a JWT for an ACL token using a JWT auth method
+func (a *ACLAuth) Login(req *ACLLoginRequest, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	if req == nil || req.LoginToken == "" {
+		return nil, nil, fmt.Errorf("missing login token")
+	}
+	var resp ACLToken
+	wm, err := a.client.write("/v1/acl/login", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// OIDCAuthURL starts a login with an OIDC auth method and returns the URL
+// the user must visit to log in with the provider
+func (a *ACLAuth) OIDCAuthURL(req *ACLOIDCAuthURLRequest, q *WriteOptions) (*ACLOIDCAuthURLResponse, *WriteMeta, error) {
+	if req == nil || req.RedirectURI == "" || req.ClientNonce == "" {
+		return nil, nil, fmt.Errorf("missing redirect URI or client nonce")
+	}
+	var resp ACLOIDCAuthURLResponse
+	wm, err := a.client.write("/v1/acl/oidc/auth-url", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// OIDCCompleteAuth completes a login with an OIDC auth method using the
+// parameters the provider passed to the redirect URI
+func (a *ACLAuth) OIDCCompleteAuth(req *ACLOIDCCompleteAuthRequest, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	if req == nil || req.Code == "" || req.State == "" {
+		return nil, nil, fmt.Errorf("missing code or state")
+	}
+	var resp ACLToken
+	wm, err := a.client.write("/v1/acl/oidc/complete-auth", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // ACLTokens is used to query the ACL token endpoints.
 type ACLTokens struct {
 	client *Client
@@ -171,26 +408,171 @@ type ACLPolicy struct {
 	ModifyIndex uint64
 }
 
+// ACLRoleListStub is used to for listing ACL roles
+type ACLRoleListStub struct {
+	Name        string
+	Description string
+	Policies    []string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLRole is used to represent an ACL role, a named set of policies
+type ACLRole struct {
+	Name        string
+	Description string
+	Policies    []string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
 // ACLToken represents a client token which is used to Authenticate
 type ACLToken struct {
-	AccessorID  string
-	SecretID    string
+	AccessorID     string
+	SecretID       string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []string
+	Global         bool
+	CreateTime     time.Time
+	ExpirationTime *time.Time `json:",omitempty"`
+
+	// ExpirationTTL sets the expiration time of a new token relative to
+	// its creation time.
+	ExpirationTTL time.Duration `json:",omitempty"`
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+type ACLTokenListStub struct {
+	AccessorID     string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []string
+	Global         bool
+	CreateTime     time.Time
+	ExpirationTime *time.Time `json:",omitempty"`
+	CreateIndex    uint64
+	ModifyIndex    uint64
+}
+
+const (
+	// ACLAuthMethodTypeJWT and ACLAuthMethodTypeOIDC are the types of auth
+	// methods
+	ACLAuthMethodTypeJWT  = "JWT"
+	ACLAuthMethodTypeOIDC = "OIDC"
+
+	// ACLBindingRuleBindTypeRole, ACLBindingRuleBindTypePolicy and
+	// ACLBindingRuleBindTypeManagement are what a binding rule grants
+	ACLBindingRuleBindTypeRole       = "role"
+	ACLBindingRuleBindTypePolicy     = "policy"
+	ACLBindingRuleBindTypeManagement = "management"
+)
+
+// ACLAuthMethodListStub is used to for listing auth methods
+type ACLAuthMethodListStub struct {
 	Name        string
 	Type        string
-	Policies    []string
-	Global      bool
-	CreateTime  time.Time
+	Default     bool
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
-type ACLTokenListStub struct {
-	AccessorID  string
+// ACLAuthMethod is used to represent a way of logging in with an external
+// identity provider
+type ACLAuthMethod struct {
 	Name        string
 	Type        string
-	Policies    []string
-	Global      bool
-	CreateTime  time.Time
+	MaxTokenTTL time.Duration
+	Default     bool
+	Config      *ACLAuthMethodConfig
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig is the provider specific configuration of an auth
+// method
+type ACLAuthMethodConfig struct {
+	OIDCDiscoveryURL     string            `json:",omitempty"`
+	OIDCClientID         string            `json:",omitempty"`
+	OIDCClientSecret     string            `json:",omitempty"`
+	OIDCScopes           []string          `json:",omitempty"`
+	AllowedRedirectURIs  []string          `json:",omitempty"`
+	JWKSURL              string            `json:",omitempty"`
+	JWTValidationPubKeys []string          `json:",omitempty"`
+	BoundIssuer          string            `json:",omitempty"`
+	BoundAudiences       []string          `json:",omitempty"`
+	ClockSkewLeeway      time.Duration     `json:",omitempty"`
+	ClaimMappings        map[string]string `json:",omitempty"`
+	ListClaimMappings    map[string]string `json:",omitempty"`
+}
+
+// ACLBindingRuleListStub is used to for listing binding rules
+type ACLBindingRuleListStub struct {
+	ID          string
+	Description string
+	AuthMethod  string
+	BindType    string
+	BindName    string
 	CreateIndex uint64
 	ModifyIndex uint64
 }
+
+// ACLBindingRule is used to represent a rule that decides what the tokens
+// created by a login with an auth method are granted
+type ACLBindingRule struct {
+	ID          string
+	Description string
+	AuthMethod  string
+	Selector    string
+	BindType    string
+	BindName    string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLLoginRequest is used to log in with a JWT auth method
+type ACLLoginRequest struct {
+	// AuthMethodName is the method to log in with. The default JWT method
+	// is used if it is empty.
+	AuthMethodName string
+
+	// LoginToken is the JWT issued by the identity provider
+	LoginToken string
+}
+
+// ACLOIDCAuthURLRequest is used to start a login with an OIDC auth method
+type ACLOIDCAuthURLRequest struct {
+	// AuthMethodName is the method to log in with. The default OIDC method
+	// is used if it is empty.
+	AuthMethodName string
+
+	// RedirectURI is where the provider sends the user after the login
+	RedirectURI string
+
+	// ClientNonce is a random value that must be passed again to complete
+	// the login
+	ClientNonce string
+}
+
+// ACLOIDCAuthURLResponse is the URL the user must visit to log in
+type ACLOIDCAuthURLResponse struct {
+	AuthURL string
+
+	// State is passed back by the provider to the redirect URI and must be
+	// given to complete the login
+	State string
+}
+
+// ACLOIDCCompleteAuthRequest is used to complete a login with an OIDC auth
+// method
+type ACLOIDCCompleteAuthRequest struct {
+	AuthMethodName string
+	ClientNonce    string
+	State          string
+	Code           string
+	RedirectURI    string
+}
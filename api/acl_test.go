@@ -2,6 +2,7 @@ package api
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -109,6 +110,104 @@ func TestACLPolicies_Info(t *testing.T) {
 	assert.Equal(t, policy.Name, out.Name)
 }
 
+func TestACLRoles_CRUD(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	ar := c.ACLRoles()
+
+	// Register a role
+	role := &ACLRole{
+		Name:        "test",
+		Description: "test",
+		Policies:    []string{"foo"},
+	}
+	wm, err := ar.Upsert(role, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	// Query the role
+	out, qm, err := ar.Info(role.Name, nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Equal(t, role.Policies, out.Policies)
+
+	// List the roles
+	roles, qm, err := ar.List(nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Len(t, roles, 1)
+
+	// Delete the role
+	wm, err = ar.Delete(role.Name, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	roles, _, err = ar.List(nil)
+	assert.Nil(t, err)
+	assert.Len(t, roles, 0)
+}
+
+func TestACLAuthMethods_CRUD(t *testing.T) {
+	t.Parallel()
+	c, s, _ := makeACLClient(t, nil, nil)
+	defer s.Stop()
+	am := c.ACLAuthMethods()
+
+	// Register an auth method
+	method := &ACLAuthMethod{
+		Name:        "test",
+		Type:        ACLAuthMethodTypeJWT,
+		MaxTokenTTL: time.Hour,
+		Config: &ACLAuthMethodConfig{
+			JWKSURL:        "https://example.com/keys",
+			BoundAudiences: []string{"nomad"},
+		},
+	}
+	wm, err := am.Upsert(method, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	// Query the auth method
+	out, qm, err := am.Info(method.Name, nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Equal(t, method.Config.JWKSURL, out.Config.JWKSURL)
+
+	// Create a binding rule for the method
+	br := c.ACLBindingRules()
+	rule, wm, err := br.Create(&ACLBindingRule{
+		AuthMethod: method.Name,
+		BindType:   ACLBindingRuleBindTypeManagement,
+	}, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+	assert.NotEmpty(t, rule.ID)
+
+	rules, qm, err := br.List(nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Len(t, rules, 1)
+
+	// List the auth methods
+	methods, qm, err := am.List(nil)
+	assert.Nil(t, err)
+	assertQueryMeta(t, qm)
+	assert.Len(t, methods, 1)
+
+	// Deleting the auth method deletes its binding rules
+	wm, err = am.Delete(method.Name, nil)
+	assert.Nil(t, err)
+	assertWriteMeta(t, wm)
+
+	methods, _, err = am.List(nil)
+	assert.Nil(t, err)
+	assert.Len(t, methods, 0)
+	rules, _, err = br.List(nil)
+	assert.Nil(t, err)
+	assert.Len(t, rules, 0)
+}
+
 func TestACLTokens_List(t *testing.T) {
 	t.Parallel()
 	c, s, _ := makeACLClient(t, nil, nil)
@@ -65,37 +65,87 @@ func (a *Allocations) GC(alloc *Allocation, q *QueryOptions) error {
 	return err
 }
 
+// Signal sends a signal to a task of the allocation, or to all of its tasks if
+// task is empty.
+func (a *Allocations) Signal(alloc *Allocation, q *QueryOptions, task, signal string) error {
+	nodeClient, err := a.client.GetNodeClient(alloc.NodeID, q)
+	if err != nil {
+		return err
+	}
+
+	req := AllocSignalRequest{
+		Signal: signal,
+		Task:   task,
+	}
+
+	var resp GenericResponse
+	_, err = nodeClient.putQuery("/v1/client/allocation/"+alloc.ID+"/signal", &req, &resp, q)
+	return err
+}
+
+// Restart restarts a task of the allocation, or all of its tasks if taskName
+// is empty.
+func (a *Allocations) Restart(alloc *Allocation, taskName string, q *QueryOptions) error {
+	nodeClient, err := a.client.GetNodeClient(alloc.NodeID, q)
+	if err != nil {
+		return err
+	}
+
+	req := AllocRestartRequest{
+		TaskName: taskName,
+	}
+
+	var resp GenericResponse
+	_, err = nodeClient.putQuery("/v1/client/allocation/"+alloc.ID+"/restart", &req, &resp, q)
+	return err
+}
+
+// Stop stops the allocation and creates an evaluation that places a
+// replacement for it.
+func (a *Allocations) Stop(alloc *Allocation, q *WriteOptions) (*AllocStopResponse, error) {
+	var resp AllocStopResponse
+	wm, err := a.client.write("/v1/allocation/"+alloc.ID+"/stop", nil, &resp, q)
+	if err != nil {
+		return nil, err
+	}
+	resp.WriteMeta = *wm
+	return &resp, nil
+}
+
 // Allocation is used for serialization of allocations.
 type Allocation struct {
-	ID                 string
-	Namespace          string
-	EvalID             string
-	Name               string
-	NodeID             string
-	JobID              string
-	Job                *Job
-	TaskGroup          string
-	Resources          *Resources
-	TaskResources      map[string]*Resources
-	Services           map[string]string
-	Metrics            *AllocationMetric
-	DesiredStatus      string
-	DesiredDescription string
-	DesiredTransition  DesiredTransition
-	ClientStatus       string
-	ClientDescription  string
-	TaskStates         map[string]*TaskState
-	DeploymentID       string
-	DeploymentStatus   *AllocDeploymentStatus
-	FollowupEvalID     string
-	PreviousAllocation string
-	NextAllocation     string
-	RescheduleTracker  *RescheduleTracker
-	CreateIndex        uint64
-	ModifyIndex        uint64
-	AllocModifyIndex   uint64
-	CreateTime         int64
-	ModifyTime         int64
+	ID                    string
+	Namespace             string
+	EvalID                string
+	Name                  string
+	NodeID                string
+	JobID                 string
+	Job                   *Job
+	TaskGroup             string
+	Resources             *Resources
+	TaskResources         map[string]*Resources
+	TaskDevices           map[string][]*AllocatedDeviceResource
+	Services              map[string]string
+	Metrics               *AllocationMetric
+	DesiredStatus         string
+	DesiredDescription    string
+	DesiredTransition     DesiredTransition
+	ClientStatus          string
+	ClientDescription     string
+	TaskStates            map[string]*TaskState
+	DeploymentID          string
+	DeploymentStatus      *AllocDeploymentStatus
+	FollowupEvalID        string
+	PreviousAllocation    string
+	NextAllocation        string
+	RescheduleTracker     *RescheduleTracker
+	PreemptedAllocations  []string
+	PreemptedByAllocation string
+	CreateIndex           uint64
+	ModifyIndex           uint64
+	AllocModifyIndex      uint64
+	CreateTime            int64
+	ModifyTime            int64
 }
 
 // AllocationMetric is used to deserialize allocation metrics.
@@ -128,6 +178,7 @@ type NodeScoreMeta struct {
 // during list operations.
 type AllocationListStub struct {
 	ID                 string
+	Namespace          string
 	EvalID             string
 	Name               string
 	NodeID             string
@@ -158,6 +209,32 @@ type AllocDeploymentStatus struct {
 	ModifyIndex uint64
 }
 
+// AllocSignalRequest is used to signal the tasks of an allocation.
+type AllocSignalRequest struct {
+	Task   string
+	Signal string
+}
+
+// AllocRestartRequest is used to restart the tasks of an allocation.
+type AllocRestartRequest struct {
+	TaskName string
+}
+
+// AllocStopResponse is the response to stopping an allocation.
+type AllocStopResponse struct {
+	// EvalID is the id of the evaluation that places a replacement for the
+	// allocation.
+	EvalID string
+
+	WriteMeta
+}
+
+// GenericResponse is used to respond to a request where no specific response
+// information is needed.
+type GenericResponse struct {
+	WriteMeta
+}
+
 // AllocIndexSort reverse sorts allocs by CreateIndex.
 type AllocIndexSort []*AllocationListStub
 
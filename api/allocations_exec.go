@@ -0,0 +1,213 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// execIdleTimeout is how long an exec session waits for a message or a
+	// ping from the agent before failing. Agents ping at half of it.
+	execIdleTimeout = 60 * time.Second
+)
+
+// ExecStreamingIOOperation is a chunk of a stream of a command, or the close
+// of the stream.
+type ExecStreamingIOOperation struct {
+	Data  []byte `json:"data,omitempty"`
+	Close bool   `json:"close,omitempty"`
+}
+
+// TerminalSize is the size of the terminal of a command.
+type TerminalSize struct {
+	Height int `json:"height,omitempty"`
+	Width  int `json:"width,omitempty"`
+}
+
+// ExecStreamingInput is a message sent to a running command.
+type ExecStreamingInput struct {
+	Stdin   *ExecStreamingIOOperation `json:"stdin,omitempty"`
+	TTYSize *TerminalSize             `json:"tty_size,omitempty"`
+}
+
+// ExecStreamingExitResult is the result of a command that exited.
+type ExecStreamingExitResult struct {
+	ExitCode int `json:"exit_code"`
+}
+
+// ExecStreamingOutput is a message sent by a running command. The final
+// message has Exited set along with the result of the command.
+type ExecStreamingOutput struct {
+	Stdout *ExecStreamingIOOperation `json:"stdout,omitempty"`
+	Stderr *ExecStreamingIOOperation `json:"stderr,omitempty"`
+
+	Exited bool                     `json:"exited,omitempty"`
+	Result *ExecStreamingExitResult `json:"result,omitempty"`
+}
+
+// Exec runs a command inside a running task of the allocation. The command's
+// input is read from stdin, which may be nil, and its output is written to
+// stdout and stderr. When tty is set a pseudo-terminal is allocated for the
+// command and terminal size changes are read from terminalSizeCh. Exec blocks
+// until the command exits and returns its exit code.
+func (a *Allocations) Exec(ctx context.Context,
+	alloc *Allocation, task string, tty bool, command []string,
+	stdin io.Reader, stdout, stderr io.Writer,
+	terminalSizeCh <-chan TerminalSize, q *QueryOptions) (int, error) {
+
+	cmd, err := json.Marshal(command)
+	if err != nil {
+		return -1, err
+	}
+
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+
+	q.Params["task"] = task
+	q.Params["tty"] = strconv.FormatBool(tty)
+	q.Params["command"] = string(cmd)
+
+	reqPath := fmt.Sprintf("/v1/client/allocation/%s/exec", alloc.ID)
+
+	nodeClient, err := a.client.GetNodeClientWithTimeout(alloc.NodeID, ClientConnTimeout, q)
+	if err != nil {
+		return -1, err
+	}
+
+	conn, err := nodeClient.websocket(reqPath, q)
+	if err != nil {
+		// There was a networking error when talking directly to the client.
+		if _, ok := err.(net.Error); !ok {
+			return -1, err
+		}
+
+		// Try via the server
+		conn, err = a.client.websocket(reqPath, q)
+		if err != nil {
+			return -1, err
+		}
+	}
+	defer conn.Close()
+
+	// Fail the session if the agent stops pinging it
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(execIdleTimeout))
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+		if err == websocket.ErrCloseSent {
+			return nil
+		} else if e, ok := err.(net.Error); ok && e.Temporary() {
+			return nil
+		}
+		return err
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Stop the session if the context is cancelled
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	var sendLock sync.Mutex
+	send := func(in *ExecStreamingInput) error {
+		buf, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return conn.WriteMessage(websocket.TextMessage, buf)
+	}
+
+	// Forward the input of the command
+	go func() {
+		if stdin == nil {
+			send(&ExecStreamingInput{Stdin: &ExecStreamingIOOperation{Close: true}})
+			return
+		}
+
+		buf := make([]byte, 2048)
+		for {
+			n, err := stdin.Read(buf)
+			if n > 0 {
+				data := make([]byte, n)
+				copy(data, buf[:n])
+				if err := send(&ExecStreamingInput{Stdin: &ExecStreamingIOOperation{Data: data}}); err != nil {
+					return
+				}
+			}
+
+			if err != nil {
+				send(&ExecStreamingInput{Stdin: &ExecStreamingIOOperation{Close: true}})
+				return
+			}
+		}
+	}()
+
+	// Forward terminal size changes
+	go func() {
+		for {
+			select {
+			case size, ok := <-terminalSizeCh:
+				if !ok {
+					return
+				}
+				s := size
+				if err := send(&ExecStreamingInput{TTYSize: &s}); err != nil {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(execIdleTimeout))
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if ce, ok := err.(*websocket.CloseError); ok && ce.Code != websocket.CloseNormalClosure {
+				return -1, fmt.Errorf("exec failed: %s", ce.Text)
+			}
+			if ctx.Err() != nil {
+				return -1, ctx.Err()
+			}
+			return -1, fmt.Errorf("exec session ended before the command exited: %v", err)
+		}
+
+		var out ExecStreamingOutput
+		if err := json.Unmarshal(msg, &out); err != nil {
+			return -1, fmt.Errorf("failed to decode exec output: %v", err)
+		}
+
+		if out.Stdout != nil && len(out.Stdout.Data) != 0 {
+			if _, err := stdout.Write(out.Stdout.Data); err != nil {
+				return -1, err
+			}
+		}
+		if out.Stderr != nil && len(out.Stderr.Data) != 0 {
+			if _, err := stderr.Write(out.Stderr.Data); err != nil {
+				return -1, err
+			}
+		}
+
+		if out.Exited && out.Result != nil {
+			return out.Result.ExitCode, nil
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/hashicorp/go-cleanhttp"
 	rootcerts "github.com/hashicorp/go-rootcerts"
 )
@@ -655,6 +656,57 @@ func (c *Client) rawQuery(endpoint string, q *QueryOptions) (io.ReadCloser, erro
 	return resp.Body, nil
 }
 
+// websocket makes a websocket request to the specific endpoint
+func (c *Client) websocket(endpoint string, q *QueryOptions) (*websocket.Conn, error) {
+	transport, ok := c.config.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("unsupported transport")
+	}
+
+	// Dial like the HTTP client does
+	dialer := websocket.Dialer{
+		HandshakeTimeout: c.config.httpClient.Timeout,
+		NetDialContext:   transport.DialContext,
+		Proxy:            transport.Proxy,
+		TLSClientConfig:  transport.TLSClientConfig,
+	}
+
+	r, err := c.newRequest("GET", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	r.setQueryOptions(q)
+
+	req, err := r.toHTTP()
+	if err != nil {
+		return nil, err
+	}
+
+	// Websockets are not compressed
+	req.Header.Del("Accept-Encoding")
+
+	u := *req.URL
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q for a websocket", u.Scheme)
+	}
+
+	conn, resp, err := dialer.Dial(u.String(), req.Header)
+
+	// The response code is more informative than the handshake error
+	if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+		var buf bytes.Buffer
+		io.Copy(&buf, resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("Unexpected response code: %d (%s)", resp.StatusCode, strings.TrimSpace(buf.String()))
+	}
+	return conn, err
+}
+
 // query is used to do a GET request against an endpoint
 // and deserialize the response into an interface using
 // standard Nomad conventions.
@@ -23,7 +23,7 @@ func TestCompose(t *testing.T) {
 				{
 					CIDR:          "0.0.0.0/0",
 					MBits:         helper.IntToPtr(100),
-					ReservedPorts: []Port{{"", 80}, {"", 443}},
+					ReservedPorts: []Port{{Label: "", Value: 80}, {Label: "", Value: 443}},
 				},
 			},
 		})
@@ -115,8 +115,8 @@ func TestCompose(t *testing.T) {
 									CIDR:  "0.0.0.0/0",
 									MBits: helper.IntToPtr(100),
 									ReservedPorts: []Port{
-										{"", 80},
-										{"", 443},
+										{Label: "", Value: 80},
+										{Label: "", Value: 443},
 									},
 								},
 							},
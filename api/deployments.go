@@ -169,6 +169,7 @@ type Deployment struct {
 type DeploymentState struct {
 	PlacedCanaries    []string
 	AutoRevert        bool
+	AutoPromote       bool
 	ProgressDeadline  time.Duration
 	RequireProgressBy time.Time
 	Promoted          bool
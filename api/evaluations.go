@@ -78,10 +78,33 @@ type Evaluation struct {
 	AnnotatePlan         bool
 	QueuedAllocations    map[string]int
 	SnapshotIndex        uint64
+	RelatedEvals         []*EvaluationStub
 	CreateIndex          uint64
 	ModifyIndex          uint64
 }
 
+// EvaluationStub is a summary of an evaluation, used for the evaluations
+// related to another one. They are returned by Info when the "related"
+// query parameter is set.
+type EvaluationStub struct {
+	ID                string
+	Namespace         string
+	Priority          int
+	Type              string
+	TriggeredBy       string
+	JobID             string
+	NodeID            string
+	DeploymentID      string
+	Status            string
+	StatusDescription string
+	WaitUntil         time.Time
+	NextEval          string
+	PreviousEval      string
+	BlockedEval       string
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
 // EvalIndexSort is a wrapper to sort evaluations by CreateIndex.
 // We reverse the test so that we get the highest index first.
 type EvalIndexSort []*Evaluation
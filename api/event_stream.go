@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Topic is the kind of object the events of the event stream are about
+type Topic string
+
+const (
+	TopicJob        Topic = "Job"
+	TopicAllocation Topic = "Allocation"
+	TopicEvaluation Topic = "Evaluation"
+	TopicDeployment Topic = "Deployment"
+	TopicNode       Topic = "Node"
+
+	// TopicAll matches the events of all the topics
+	TopicAll Topic = "*"
+)
+
+// Event is a change of the state of the cluster
+type Event struct {
+	Topic     Topic
+	Type      string
+	Key       string
+	Namespace string
+	Index     uint64
+	Payload   *EventPayload
+}
+
+// EventPayload holds the object of an event, depending on its topic
+type EventPayload struct {
+	Job        *Job
+	Allocation *Allocation
+	Evaluation *Evaluation
+	Deployment *Deployment
+	Node       *Node
+}
+
+// Events are the events of a raft index, or the error ending the stream
+type Events struct {
+	Index  uint64
+	Events []Event
+	Err    error `json:"-"`
+}
+
+// IsHeartbeat returns whether the events are a heartbeat of an idle stream
+func (e *Events) IsHeartbeat() bool {
+	return e.Index == 0 && len(e.Events) == 0
+}
+
+// EventStream is used to stream the events of the changes of the cluster.
+type EventStream struct {
+	client *Client
+}
+
+// EventStream returns a handle to the event stream.
+func (c *Client) EventStream() *EventStream {
+	return &EventStream{client: c}
+}
+
+// Stream streams the events of the given topics, mapped to the keys of the
+// events to stream ("*" for all of them), until the context is done. All the
+// events are streamed if no topic is given. Only the events after index are
+// streamed, and only new events if it is zero. An error ending the stream is
+// sent as the last Events on the channel.
+func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, index uint64, q *QueryOptions) (<-chan *Events, error) {
+	r, err := e.client.newRequest("GET", "/v1/event/stream")
+	if err != nil {
+		return nil, err
+	}
+	r.setQueryOptions(q)
+	r.params.Set("index", strconv.FormatUint(index, 10))
+	for topic, keys := range topics {
+		for _, key := range keys {
+			r.params.Add("topic", fmt.Sprintf("%s:%s", topic, key))
+		}
+	}
+
+	_, resp, err := requireOK(e.client.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+
+	// Close the body to unblock the decoder once the context is done
+	go func() {
+		<-ctx.Done()
+		resp.Body.Close()
+	}()
+
+	eventsCh := make(chan *Events, 10)
+	go func() {
+		defer resp.Body.Close()
+		defer close(eventsCh)
+
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var events Events
+			if err := dec.Decode(&events); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				events = Events{Err: err}
+			}
+			if events.IsHeartbeat() && events.Err == nil {
+				continue
+			}
+
+			select {
+			case eventsCh <- &events:
+			case <-ctx.Done():
+				return
+			}
+			if events.Err != nil {
+				return
+			}
+		}
+	}()
+
+	return eventsCh, nil
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	topics := map[Topic][]string{TopicJob: {"*"}}
+	streamCh, err := c.EventStream().Stream(ctx, topics, 0, nil)
+	require.NoError(err)
+
+	job := testJob()
+	resp, _, err := c.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	select {
+	case events := <-streamCh:
+		require.NoError(events.Err)
+		require.Equal(resp.JobModifyIndex, events.Index)
+		require.Len(events.Events, 1)
+		require.Equal(TopicJob, events.Events[0].Topic)
+		require.Equal(*job.ID, events.Events[0].Key)
+		require.Equal(*job.ID, *events.Events[0].Payload.Job.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for events")
+	}
+}
+
+func TestEventStream_InvalidTopic(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	topics := map[Topic][]string{"Unknown": {"*"}}
+	_, err := c.EventStream().Stream(context.Background(), topics, 0, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid topic")
+}
@@ -79,6 +79,10 @@ type RegisterOptions struct {
 	EnforceIndex   bool
 	ModifyIndex    uint64
 	PolicyOverride bool
+
+	// Submission is the source the job was parsed from, stored with the
+	// registered job version
+	Submission *JobSubmission
 }
 
 // Register is used to register a new job. It returns the ID
@@ -108,6 +112,7 @@ func (j *Jobs) RegisterOpts(job *Job, opts *RegisterOptions, q *WriteOptions) (*
 		if opts.PolicyOverride {
 			req.PolicyOverride = true
 		}
+		req.Submission = opts.Submission
 	}
 
 	var resp JobRegisterResponse
@@ -156,6 +161,27 @@ func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*J
 	return resp.Versions, resp.Diffs, qm, nil
 }
 
+// Submission is used to retrieve the source a version of the job was
+// submitted from. The current version is used if version is nil.
+func (j *Jobs) Submission(jobID string, version *uint64, q *QueryOptions) (*JobSubmission, *QueryMeta, error) {
+	u, err := url.Parse("/v1/job/" + jobID + "/submission")
+	if err != nil {
+		return nil, nil, err
+	}
+	if version != nil {
+		v := u.Query()
+		v.Add("version", strconv.FormatUint(*version, 10))
+		u.RawQuery = v.Encode()
+	}
+
+	var resp JobSubmission
+	qm, err := j.client.query(u.String(), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Allocations is used to return the allocs for a given job ID.
 func (j *Jobs) Allocations(jobID string, allAllocs bool, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
 	var resp []*AllocationListStub
@@ -366,6 +392,7 @@ type UpdateStrategy struct {
 	HealthyDeadline  *time.Duration `mapstructure:"healthy_deadline"`
 	ProgressDeadline *time.Duration `mapstructure:"progress_deadline"`
 	AutoRevert       *bool          `mapstructure:"auto_revert"`
+	AutoPromote      *bool          `mapstructure:"auto_promote"`
 	Canary           *int           `mapstructure:"canary"`
 }
 
@@ -380,6 +407,7 @@ func DefaultUpdateStrategy() *UpdateStrategy {
 		HealthyDeadline:  helper.TimeToPtr(5 * time.Minute),
 		ProgressDeadline: helper.TimeToPtr(10 * time.Minute),
 		AutoRevert:       helper.BoolToPtr(false),
+		AutoPromote:      helper.BoolToPtr(false),
 		Canary:           helper.IntToPtr(0),
 	}
 }
@@ -419,6 +447,10 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.AutoRevert = helper.BoolToPtr(*u.AutoRevert)
 	}
 
+	if u.AutoPromote != nil {
+		copy.AutoPromote = helper.BoolToPtr(*u.AutoPromote)
+	}
+
 	if u.Canary != nil {
 		copy.Canary = helper.IntToPtr(*u.Canary)
 	}
@@ -459,6 +491,10 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 		u.AutoRevert = helper.BoolToPtr(*o.AutoRevert)
 	}
 
+	if o.AutoPromote != nil {
+		u.AutoPromote = helper.BoolToPtr(*o.AutoPromote)
+	}
+
 	if o.Canary != nil {
 		u.Canary = helper.IntToPtr(*o.Canary)
 	}
@@ -495,6 +531,10 @@ func (u *UpdateStrategy) Canonicalize() {
 		u.AutoRevert = d.AutoRevert
 	}
 
+	if u.AutoPromote == nil {
+		u.AutoPromote = d.AutoPromote
+	}
+
 	if u.Canary == nil {
 		u.Canary = d.Canary
 	}
@@ -534,6 +574,10 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.AutoPromote != nil && *u.AutoPromote {
+		return false
+	}
+
 	if u.Canary != nil && *u.Canary != 0 {
 		return false
 	}
@@ -597,6 +641,43 @@ type ParameterizedJobConfig struct {
 	MetaOptional []string `mapstructure:"meta_optional"`
 }
 
+// Multiregion is used to deploy a job to multiple federated regions.
+type Multiregion struct {
+	Strategy *MultiregionStrategy
+	Regions  []*MultiregionRegion
+}
+
+func (m *Multiregion) Canonicalize() {
+	if m.Strategy == nil {
+		m.Strategy = &MultiregionStrategy{}
+	}
+	if m.Strategy.MaxParallel == nil {
+		m.Strategy.MaxParallel = helper.IntToPtr(0)
+	}
+	if m.Strategy.OnFailure == nil {
+		m.Strategy.OnFailure = helper.StringToPtr("")
+	}
+	for _, region := range m.Regions {
+		if region.Count == nil {
+			region.Count = helper.IntToPtr(0)
+		}
+	}
+}
+
+// MultiregionStrategy configures the rollout of a multiregion job.
+type MultiregionStrategy struct {
+	MaxParallel *int    `mapstructure:"max_parallel"`
+	OnFailure   *string `mapstructure:"on_failure"`
+}
+
+// MultiregionRegion is a region a multiregion job is deployed to.
+type MultiregionRegion struct {
+	Name        string
+	Count       *int
+	Datacenters []string
+	Meta        map[string]string
+}
+
 // Job is used to serialize a job.
 type Job struct {
 	Stop              *bool
@@ -609,10 +690,12 @@ type Job struct {
 	Priority          *int
 	AllAtOnce         *bool `mapstructure:"all_at_once"`
 	Datacenters       []string
+	NodePool          *string `mapstructure:"node_pool"`
 	Constraints       []*Constraint
 	Affinities        []*Affinity
 	TaskGroups        []*TaskGroup
 	Update            *UpdateStrategy
+	Multiregion       *Multiregion
 	Spreads           []*Spread
 	Periodic          *PeriodicConfig
 	ParameterizedJob  *ParameterizedJobConfig
@@ -642,6 +725,11 @@ func (j *Job) IsParameterized() bool {
 	return j.ParameterizedJob != nil && !j.Dispatched
 }
 
+// IsMultiregion returns whether a job is deployed to multiple regions.
+func (j *Job) IsMultiregion() bool {
+	return j.Multiregion != nil && len(j.Multiregion.Regions) != 0
+}
+
 func (j *Job) Canonicalize() {
 	if j.ID == nil {
 		j.ID = helper.StringToPtr("")
@@ -703,6 +791,9 @@ func (j *Job) Canonicalize() {
 	if j.Update != nil {
 		j.Update.Canonicalize()
 	}
+	if j.Multiregion != nil {
+		j.Multiregion.Canonicalize()
+	}
 
 	for _, tg := range j.TaskGroups {
 		tg.Canonicalize(j)
@@ -761,6 +852,7 @@ type TaskGroupSummary struct {
 // jobs during list operations.
 type JobListStub struct {
 	ID                string
+	Namespace         string
 	ParentID          string
 	Name              string
 	Type              string
@@ -887,6 +979,10 @@ type JobValidateResponse struct {
 	// ValidationErrors is a list of validation errors
 	ValidationErrors []string
 
+	// ValidationWarnings is a list of the warnings about the job, such as
+	// deprecations
+	ValidationWarnings []string
+
 	// Error is a string version of any error that may have occurred
 	Error string
 
@@ -919,6 +1015,7 @@ type JobRegisterRequest struct {
 	EnforceIndex   bool
 	JobModifyIndex uint64
 	PolicyOverride bool
+	Submission     *JobSubmission
 
 	WriteRequest
 }
@@ -926,9 +1023,34 @@ type JobRegisterRequest struct {
 // RegisterJobRequest is used to serialize a job registration
 type RegisterJobRequest struct {
 	Job            *Job
-	EnforceIndex   bool   `json:",omitempty"`
-	JobModifyIndex uint64 `json:",omitempty"`
-	PolicyOverride bool   `json:",omitempty"`
+	EnforceIndex   bool           `json:",omitempty"`
+	JobModifyIndex uint64         `json:",omitempty"`
+	PolicyOverride bool           `json:",omitempty"`
+	Submission     *JobSubmission `json:",omitempty"`
+}
+
+// JobSubmission is the source a job version was parsed from
+type JobSubmission struct {
+	// Source is the job specification as submitted
+	Source string
+
+	// Format is the format of the source, "hcl1", "hcl2" or "json"
+	Format string
+
+	// VariableFlags are the values of the input variables given with the
+	// -var flag
+	VariableFlags map[string]string
+
+	// Variables are the values of the input variables assigned by the
+	// environment, the variable files and the -var flag, as the content of
+	// an HCL variable file
+	Variables string
+
+	Namespace   string
+	JobID       string
+	Version     uint64
+	CreateIndex uint64
+	ModifyIndex uint64
 }
 
 // JobRegisterResponse is used to respond to a job registration
@@ -941,6 +1063,10 @@ type JobRegisterResponse struct {
 	// deprecation warnings.
 	Warnings string
 
+	// RegionResults are the results of the registration of a multiregion job
+	// in each of the job's regions, keyed by region.
+	RegionResults map[string]*JobRegisterResponse
+
 	QueryMeta
 }
 
@@ -100,6 +100,9 @@ func TestJobs_Validate(t *testing.T) {
 	if len(resp.ValidationErrors) != 0 {
 		t.Fatalf("bad %v", resp)
 	}
+	if len(resp.ValidationWarnings) != 0 {
+		t.Fatalf("bad %v", resp)
+	}
 
 	job.ID = nil
 	resp1, _, err := jobs.Validate(job, nil)
@@ -362,6 +365,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					HealthyDeadline:  helper.TimeToPtr(5 * time.Minute),
 					ProgressDeadline: helper.TimeToPtr(10 * time.Minute),
 					AutoRevert:       helper.BoolToPtr(false),
+					AutoPromote:      helper.BoolToPtr(false),
 					Canary:           helper.IntToPtr(0),
 				},
 				TaskGroups: []*TaskGroup{
@@ -396,6 +400,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							HealthyDeadline:  helper.TimeToPtr(5 * time.Minute),
 							ProgressDeadline: helper.TimeToPtr(10 * time.Minute),
 							AutoRevert:       helper.BoolToPtr(false),
+							AutoPromote:      helper.BoolToPtr(false),
 							Canary:           helper.IntToPtr(0),
 						},
 						Migrate: DefaultMigrateStrategy(),
@@ -431,6 +436,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 										CanaryTags:  []string{"canary", "global", "cache"},
 										PortLabel:   "db",
 										AddressMode: "auto",
+										Provider:    "consul",
 										Checks: []ServiceCheck{
 											{
 												Name:     "alive",
@@ -526,6 +532,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					HealthyDeadline:  helper.TimeToPtr(6 * time.Minute),
 					ProgressDeadline: helper.TimeToPtr(7 * time.Minute),
 					AutoRevert:       helper.BoolToPtr(false),
+					AutoPromote:      helper.BoolToPtr(false),
 					Canary:           helper.IntToPtr(0),
 				},
 				TaskGroups: []*TaskGroup{
@@ -581,6 +588,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					HealthyDeadline:  helper.TimeToPtr(6 * time.Minute),
 					ProgressDeadline: helper.TimeToPtr(7 * time.Minute),
 					AutoRevert:       helper.BoolToPtr(false),
+					AutoPromote:      helper.BoolToPtr(false),
 					Canary:           helper.IntToPtr(0),
 				},
 				TaskGroups: []*TaskGroup{
@@ -614,6 +622,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							HealthyDeadline:  helper.TimeToPtr(6 * time.Minute),
 							ProgressDeadline: helper.TimeToPtr(7 * time.Minute),
 							AutoRevert:       helper.BoolToPtr(true),
+							AutoPromote:      helper.BoolToPtr(false),
 							Canary:           helper.IntToPtr(1),
 						},
 						Migrate: DefaultMigrateStrategy(),
@@ -656,6 +665,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							HealthyDeadline:  helper.TimeToPtr(6 * time.Minute),
 							ProgressDeadline: helper.TimeToPtr(7 * time.Minute),
 							AutoRevert:       helper.BoolToPtr(false),
+							AutoPromote:      helper.BoolToPtr(false),
 							Canary:           helper.IntToPtr(0),
 						},
 						Migrate: DefaultMigrateStrategy(),
@@ -846,6 +856,50 @@ func TestJobs_Versions(t *testing.T) {
 	}
 }
 
+func TestJobs_Submission(t *testing.T) {
+	t.Parallel()
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	jobs := c.Jobs()
+
+	// Trying to retrieve a source before the job exists returns an error
+	_, _, err := jobs.Submission("job1", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got: %#v", err)
+	}
+
+	// Register the job with its source
+	job := testJob()
+	opts := &RegisterOptions{
+		Submission: &JobSubmission{
+			Source: `job "job1" {}`,
+			Format: "hcl2",
+		},
+	}
+	_, wm, err := jobs.RegisterOpts(job, opts, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assertWriteMeta(t, wm)
+
+	// Query the source of the current version
+	sub, qm, err := jobs.Submission("job1", nil, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	assertQueryMeta(t, qm)
+	if sub.Source != opts.Submission.Source || sub.Format != "hcl2" || sub.Version != 0 {
+		t.Fatalf("bad: %#v", sub)
+	}
+
+	// Query the source of a version that doesn't exist
+	version := uint64(1)
+	_, _, err = jobs.Submission("job1", &version, nil)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("expected not found error, got: %#v", err)
+	}
+}
+
 func TestJobs_PrefixList(t *testing.T) {
 	t.Parallel()
 	c, s := makeClient(t, nil, nil)
@@ -5,6 +5,12 @@ import (
 	"sort"
 )
 
+const (
+	// AllNamespacesNamespace is the namespace of the list queries returning
+	// the objects of all the namespaces the token may read.
+	AllNamespacesNamespace = "*"
+)
+
 // Namespaces is used to query the namespace endpoints.
 type Namespaces struct {
 	client *Client
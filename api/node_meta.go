@@ -0,0 +1,60 @@
+package api
+
+import "fmt"
+
+// NodeMeta is used to read and update the metadata of nodes at runtime.
+type NodeMeta struct {
+	client *Client
+}
+
+// Meta returns a handle on the node metadata endpoints.
+func (n *Nodes) Meta() *NodeMeta {
+	return &NodeMeta{client: n.client}
+}
+
+// NodeMetaApplyRequest is used to set the metadata of a node.
+type NodeMetaApplyRequest struct {
+	// NodeID is the node to update. If empty the node of the agent that
+	// receives the request is updated.
+	NodeID string
+
+	// Meta are the keys to set. A nil value unsets the key, including keys
+	// set in the client configuration.
+	Meta map[string]*string
+}
+
+// NodeMetaResponse is the metadata of a node.
+type NodeMetaResponse struct {
+	// Meta is the effective metadata of the node
+	Meta map[string]string
+
+	// Dynamic is the metadata that was set at runtime
+	Dynamic map[string]*string
+
+	// Static is the metadata from the client configuration
+	Static map[string]string
+}
+
+// Apply sets the metadata keys of a node. The metadata is persisted by the
+// client and takes effect without restarting it.
+func (n *NodeMeta) Apply(req *NodeMetaApplyRequest, q *QueryOptions) (*NodeMetaResponse, error) {
+	var resp NodeMetaResponse
+	if _, err := n.client.putQuery("/v1/client/metadata", req, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Read returns the metadata of a node. If nodeID is empty the metadata of the
+// node of the agent that receives the request is returned.
+func (n *NodeMeta) Read(nodeID string, q *QueryOptions) (*NodeMetaResponse, error) {
+	var resp NodeMetaResponse
+	path := "/v1/client/metadata"
+	if nodeID != "" {
+		path = fmt.Sprintf("%s?node_id=%s", path, nodeID)
+	}
+	if _, err := n.client.query(path, &resp, q); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -324,7 +324,7 @@ func (n *Nodes) monitorDrainAllocs(ctx context.Context, nodeID string, ignoreSys
 			}
 
 			// Track how many allocs are still running
-			if ignoreSys && a.Job.Type != nil && *a.Job.Type == structs.JobTypeSystem {
+			if ignoreSys && a.Job.Type != nil && structs.IsSystemJobType(*a.Job.Type) {
 				continue
 			}
 
@@ -436,6 +436,35 @@ type DriverInfo struct {
 	UpdateTime        time.Time
 }
 
+// HostVolumeInfo is used to deserialize a host volume exposed by a node
+type HostVolumeInfo struct {
+	Path     string
+	ReadOnly bool
+}
+
+// NodeDeviceResource is used to deserialize a group of devices fingerprinted
+// on a node
+type NodeDeviceResource struct {
+	Vendor     string
+	Type       string
+	Name       string
+	Instances  []*NodeDevice
+	Attributes map[string]string
+}
+
+// NodeDevice is used to deserialize a single device instance
+type NodeDevice struct {
+	ID                string
+	Healthy           bool
+	HealthDescription string
+	Locality          *NodeDeviceLocality
+}
+
+// NodeDeviceLocality is used to deserialize the hardware locality of a device
+type NodeDeviceLocality struct {
+	PciBusID string
+}
+
 // Node is used to deserialize a node entry.
 type Node struct {
 	ID                    string
@@ -449,6 +478,7 @@ type Node struct {
 	Links                 map[string]string
 	Meta                  map[string]string
 	NodeClass             string
+	NodePool              string
 	Drain                 bool
 	DrainStrategy         *DrainStrategy
 	SchedulingEligibility string
@@ -457,6 +487,8 @@ type Node struct {
 	StatusUpdatedAt       int64
 	Events                []*NodeEvent
 	Drivers               map[string]*DriverInfo
+	HostVolumes           map[string]*HostVolumeInfo
+	Devices               []*NodeDeviceResource
 	CreateIndex           uint64
 	ModifyIndex           uint64
 }
@@ -565,6 +597,7 @@ type NodeListStub struct {
 	Datacenter            string
 	Name                  string
 	NodeClass             string
+	NodePool              string
 	Version               string
 	Drain                 bool
 	SchedulingEligibility string
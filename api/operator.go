@@ -1,5 +1,16 @@
 package api
 
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
 // Operator can be used to perform low-level operator tasks for Nomad.
 type Operator struct {
 	c *Client
@@ -106,3 +117,176 @@ func (op *Operator) RaftRemovePeerByID(id string, q *WriteOptions) error {
 	resp.Body.Close()
 	return nil
 }
+
+// RaftTransferLeadership is used to make the leader step down so that another
+// server takes over the leadership. The former leader is a non-voter until
+// autopilot promotes it back once it is stable.
+func (op *Operator) RaftTransferLeadership(q *WriteOptions) error {
+	r, err := op.c.newRequest("PUT", "/v1/operator/raft/transfer-leadership")
+	if err != nil {
+		return err
+	}
+	r.setWriteOptions(q)
+
+	_, resp, err := requireOK(op.c.doRequest(r))
+	if err != nil {
+		return err
+	}
+
+	resp.Body.Close()
+	return nil
+}
+
+// Snapshot is used to capture a snapshot of the state of the cluster. The
+// returned reader must be consumed fully and closed; it returns an error at
+// the end of the snapshot if its checksum doesn't match.
+func (op *Operator) Snapshot(q *QueryOptions) (io.ReadCloser, error) {
+	r, err := op.c.newRequest("GET", "/v1/operator/snapshot")
+	if err != nil {
+		return nil, err
+	}
+	r.setQueryOptions(q)
+	_, resp, err := requireOK(op.c.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+
+	cr, err := newChecksumValidatingReader(resp.Body, resp.Header.Get("Digest"))
+	if err != nil {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, err
+	}
+	return cr, nil
+}
+
+// SnapshotRestore is used to restore the state of the cluster from a
+// snapshot. The snapshot is verified in full before being restored.
+func (op *Operator) SnapshotRestore(in io.Reader, q *WriteOptions) (*WriteMeta, error) {
+	r, err := op.c.newRequest("PUT", "/v1/operator/snapshot")
+	if err != nil {
+		return nil, err
+	}
+	r.setWriteOptions(q)
+	r.body = in
+	rtt, resp, err := requireOK(op.c.doRequest(r))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	parseWriteMeta(resp, wm)
+	return wm, nil
+}
+
+// checksumValidatingReader is a reader checking the SHA-256 checksum of the
+// data read once fully read.
+type checksumValidatingReader struct {
+	r        io.ReadCloser
+	hash     hash.Hash
+	checksum string
+}
+
+// newChecksumValidatingReader returns a reader checking the data against the
+// checksum of a Digest header, in the "sha-256=<base64 sum>" format.
+func newChecksumValidatingReader(r io.ReadCloser, digest string) (io.ReadCloser, error) {
+	parts := strings.SplitN(digest, "=", 2)
+	if len(parts) != 2 || parts[0] != "sha-256" {
+		return nil, fmt.Errorf("unsupported digest %q", digest)
+	}
+
+	return &checksumValidatingReader{
+		r:        r,
+		hash:     sha256.New(),
+		checksum: parts[1],
+	}, nil
+}
+
+func (r *checksumValidatingReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if n > 0 {
+		r.hash.Write(b[:n])
+	}
+
+	if err == io.EOF {
+		sum := base64.StdEncoding.EncodeToString(r.hash.Sum(nil))
+		if sum != r.checksum {
+			return n, fmt.Errorf("snapshot checksum mismatch, expected %s got %s", r.checksum, sum)
+		}
+	}
+	return n, err
+}
+
+func (r *checksumValidatingReader) Close() error {
+	return r.r.Close()
+}
+
+// SchedulerAlgorithm is the algorithm the scheduler uses to score nodes
+type SchedulerAlgorithm string
+
+const (
+	SchedulerAlgorithmBinpack SchedulerAlgorithm = "binpack"
+	SchedulerAlgorithmSpread  SchedulerAlgorithm = "spread"
+)
+
+// SchedulerConfiguration is the config for controlling scheduler behavior
+type SchedulerConfiguration struct {
+	// SchedulerAlgorithm is the default algorithm used to score nodes, either
+	// binpack or spread.
+	SchedulerAlgorithm SchedulerAlgorithm
+
+	// NodePoolSchedulerAlgorithms overrides the scheduler algorithm for the
+	// jobs of the given node pools.
+	NodePoolSchedulerAlgorithms map[string]SchedulerAlgorithm
+
+	// PreemptionConfig specifies whether to enable eviction of lower
+	// priority jobs to place higher priority jobs.
+	PreemptionConfig PreemptionConfig
+
+	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// PreemptionConfig specifies whether preemption is enabled for each
+// scheduler type.
+type PreemptionConfig struct {
+	SystemSchedulerEnabled   bool
+	SysBatchSchedulerEnabled bool
+	BatchSchedulerEnabled    bool
+	ServiceSchedulerEnabled  bool
+}
+
+// SchedulerGetConfiguration is used to query the current Scheduler configuration.
+func (op *Operator) SchedulerGetConfiguration(q *QueryOptions) (*SchedulerConfiguration, *QueryMeta, error) {
+	var resp SchedulerConfiguration
+	qm, err := op.c.query("/v1/operator/scheduler/configuration", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// SchedulerSetConfiguration is used to set the current Scheduler configuration.
+func (op *Operator) SchedulerSetConfiguration(conf *SchedulerConfiguration, q *WriteOptions) (*WriteMeta, error) {
+	var out bool
+	wm, err := op.c.write("/v1/operator/scheduler/configuration", conf, &out, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// SchedulerCASConfiguration is used to perform a Check-And-Set update on the
+// Scheduler configuration. The ModifyIndex value will be respected. Returns
+// true on success or false on failures.
+func (op *Operator) SchedulerCASConfiguration(conf *SchedulerConfiguration, q *WriteOptions) (bool, *WriteMeta, error) {
+	var out bool
+	wm, err := op.c.write("/v1/operator/scheduler/configuration?cas="+strconv.FormatUint(conf.ModifyIndex, 10), conf, &out, q)
+	if err != nil {
+		return false, nil, err
+	}
+
+	return out, wm, nil
+}
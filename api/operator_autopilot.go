@@ -27,7 +27,7 @@ type AutopilotConfiguration struct {
 	// applicable with Raft protocol version 3 or higher.
 	ServerStabilizationTime time.Duration
 
-	// (Enterprise-only) EnableRedundancyZones specifies whether to enable redundancy zones.
+	// EnableRedundancyZones specifies whether to enable redundancy zones.
 	EnableRedundancyZones bool
 
 	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
@@ -0,0 +1,38 @@
+package api
+
+// RootKeyMeta is the metadata of a root key the servers use to sign workload
+// identities and encrypt variables.
+type RootKeyMeta struct {
+	KeyID       string
+	Algorithm   string
+	Active      bool
+	CreateTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// KeyringList is used to list the root keys.
+func (op *Operator) KeyringList(q *QueryOptions) ([]*RootKeyMeta, *QueryMeta, error) {
+	var resp []*RootKeyMeta
+	qm, err := op.c.query("/v1/operator/keyring/keys", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// KeyringRotate is used to replace the active root key by a new one. A full
+// rotation also re-encrypts all the variables with the new key.
+func (op *Operator) KeyringRotate(full bool, q *WriteOptions) (*RootKeyMeta, *WriteMeta, error) {
+	endpoint := "/v1/operator/keyring/rotate"
+	if full {
+		endpoint += "?full=true"
+	}
+
+	var resp RootKeyMeta
+	wm, err := op.c.write(endpoint, nil, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
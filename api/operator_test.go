@@ -1,8 +1,14 @@
 package api
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"io/ioutil"
 	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestOperator_RaftGetConfiguration(t *testing.T) {
@@ -51,3 +57,62 @@ func TestOperator_RaftRemovePeerByID(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 }
+
+func TestOperator_Snapshot(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	job := testJob()
+	_, _, err := c.Jobs().Register(job, nil)
+	require.NoError(err)
+
+	// Save a snapshot, checking its checksum while reading it
+	snap, err := c.Operator().Snapshot(nil)
+	require.NoError(err)
+	data, err := ioutil.ReadAll(snap)
+	require.NoError(err)
+	require.NoError(snap.Close())
+	require.NotEmpty(data)
+
+	// Purge the job and restore it from the snapshot
+	_, _, err = c.Jobs().Deregister(*job.ID, true, nil)
+	require.NoError(err)
+
+	wm, err := c.Operator().SnapshotRestore(bytes.NewReader(data), nil)
+	require.NoError(err)
+	require.NotZero(wm.LastIndex)
+
+	out, _, err := c.Jobs().Info(*job.ID, nil)
+	require.NoError(err)
+	require.Equal(*job.ID, *out.ID)
+
+	// Garbage is rejected
+	_, err = c.Operator().SnapshotRestore(strings.NewReader("not a snapshot"), nil)
+	require.Error(err)
+}
+
+func TestOperator_checksumValidatingReader(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	data := []byte("snapshot data")
+	sum := sha256.Sum256(data)
+	digest := "sha-256=" + base64.StdEncoding.EncodeToString(sum[:])
+
+	r, err := newChecksumValidatingReader(ioutil.NopCloser(bytes.NewReader(data)), digest)
+	require.NoError(err)
+	out, err := ioutil.ReadAll(r)
+	require.NoError(err)
+	require.Equal(data, out)
+
+	r, err = newChecksumValidatingReader(ioutil.NopCloser(strings.NewReader("corrupted")), digest)
+	require.NoError(err)
+	_, err = ioutil.ReadAll(r)
+	require.Error(err)
+	require.Contains(err.Error(), "checksum mismatch")
+
+	_, err = newChecksumValidatingReader(ioutil.NopCloser(bytes.NewReader(data)), "md5=abc")
+	require.Error(err)
+}
@@ -5,11 +5,13 @@ import "github.com/hashicorp/nomad/helper"
 // Resources encapsulates the required resources of
 // a given task or task group.
 type Resources struct {
-	CPU      *int
-	MemoryMB *int `mapstructure:"memory"`
-	DiskMB   *int `mapstructure:"disk"`
-	IOPS     *int
-	Networks []*NetworkResource
+	CPU         *int
+	MemoryMB    *int `mapstructure:"memory"`
+	MemoryMaxMB *int `mapstructure:"memory_max"`
+	DiskMB      *int `mapstructure:"disk"`
+	IOPS        *int
+	Networks    []*NetworkResource
+	Devices     []*RequestedDevice
 }
 
 // Canonicalize will supply missing values in the cases
@@ -28,6 +30,9 @@ func (r *Resources) Canonicalize() {
 	for _, n := range r.Networks {
 		n.Canonicalize()
 	}
+	for _, d := range r.Devices {
+		d.Canonicalize()
+	}
 }
 
 // DefaultResources is a small resources object that contains the
@@ -66,6 +71,9 @@ func (r *Resources) Merge(other *Resources) {
 	if other.MemoryMB != nil {
 		r.MemoryMB = other.MemoryMB
 	}
+	if other.MemoryMaxMB != nil {
+		r.MemoryMaxMB = other.MemoryMaxMB
+	}
 	if other.DiskMB != nil {
 		r.DiskMB = other.DiskMB
 	}
@@ -75,16 +83,23 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Networks) != 0 {
 		r.Networks = other.Networks
 	}
+	if len(other.Devices) != 0 {
+		r.Devices = other.Devices
+	}
 }
 
 type Port struct {
-	Label string
-	Value int `mapstructure:"static"`
+	Label       string
+	Value       int    `mapstructure:"static"`
+	To          int    `mapstructure:"to"`
+	HostNetwork string `mapstructure:"host_network"`
+	HostIP      string
 }
 
 // NetworkResource is used to describe required network
 // resources of a given task.
 type NetworkResource struct {
+	Mode          string
 	Device        string
 	CIDR          string
 	IP            string
@@ -98,3 +113,42 @@ func (n *NetworkResource) Canonicalize() {
 		n.MBits = helper.IntToPtr(10)
 	}
 }
+
+// RequestedDevice is used to request a device for a task.
+type RequestedDevice struct {
+	// Name is the request name. The possible values are as follows:
+	// * <type>: A single value only specifies the type of request.
+	// * <vendor>/<type>: A single slash delimiter assumes the vendor and type of device is specified.
+	// * <vendor>/<type>/<name>: Two slash delimiters assume vendor, type and specific model are specified.
+	//
+	// Examples are as follows:
+	// * "gpu"
+	// * "nvidia/gpu"
+	// * "nvidia/gpu/GTX2080Ti"
+	Name string
+
+	// Count is the number of requested devices
+	Count *uint64
+
+	// Constraints are a set of constraints to apply when selecting the device
+	// to use.
+	Constraints []*Constraint
+
+	// Affinities are a set of affinites to apply when selecting the device
+	// to use.
+	Affinities []*Affinity
+}
+
+func (d *RequestedDevice) Canonicalize() {
+	if d.Count == nil {
+		d.Count = helper.Uint64ToPtr(1)
+	}
+}
+
+// AllocatedDeviceResource is the set of device instances assigned to a task
+type AllocatedDeviceResource struct {
+	Vendor    string
+	Type      string
+	Name      string
+	DeviceIDs []string
+}
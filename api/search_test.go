@@ -30,3 +30,24 @@ func TestSearch_List(t *testing.T) {
 	require.Equal(1, len(jobMatches))
 	require.Equal(id, jobMatches[0])
 }
+
+func TestSearch_Fuzzy(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+
+	job := testJob()
+	_, _, err := c.Jobs().Register(job, nil)
+	require.Nil(err)
+
+	resp, qm, err := c.Search().FuzzySearch("ob1", contexts.All, nil)
+	require.Nil(err)
+	require.NotNil(qm)
+
+	jobMatches := resp.Matches[contexts.Jobs]
+	require.Equal(1, len(jobMatches))
+	require.Equal(*job.Name, jobMatches[0].ID)
+	require.Equal([]string{"default", *job.ID}, jobMatches[0].Scope)
+}
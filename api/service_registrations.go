@@ -0,0 +1,85 @@
+package api
+
+import (
+	"fmt"
+)
+
+// Services is used to query the service registrations stored by Nomad for
+// the services using the nomad provider.
+type Services struct {
+	client *Client
+}
+
+// Services returns a new handle on the service registrations.
+func (c *Client) Services() *Services {
+	return &Services{client: c}
+}
+
+// ServiceRegistration is an instance of a service registered by a Nomad
+// client for one of its allocations.
+type ServiceRegistration struct {
+	ID          string
+	ServiceName string
+	Namespace   string
+	NodeID      string
+	Datacenter  string
+	JobID       string
+	AllocID     string
+	Tags        []string
+	Address     string
+	Port        int
+	Checks      []*ServiceRegistrationCheck
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ServiceRegistrationCheck is the last known status of a check of a service
+// registration.
+type ServiceRegistrationCheck struct {
+	Name   string
+	Type   string
+	Status string
+	Output string
+}
+
+// ServiceRegistrationListStub is the name of a registered service with the
+// tags of all its instances.
+type ServiceRegistrationListStub struct {
+	ServiceName string
+	Tags        []string
+}
+
+// List is used to list the names of the registered services
+func (s *Services) List(q *QueryOptions) ([]*ServiceRegistrationListStub, *QueryMeta, error) {
+	var resp []*ServiceRegistrationListStub
+	qm, err := s.client.query("/v1/services", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Get is used to query the registered instances of a service
+func (s *Services) Get(name string, q *QueryOptions) ([]*ServiceRegistration, *QueryMeta, error) {
+	if name == "" {
+		return nil, nil, fmt.Errorf("missing service name")
+	}
+	var resp []*ServiceRegistration
+	qm, err := s.client.query("/v1/service/"+name, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Delete is used to remove a registration of a service
+func (s *Services) Delete(name, id string, q *WriteOptions) (*WriteMeta, error) {
+	if name == "" || id == "" {
+		return nil, fmt.Errorf("missing service name or registration ID")
+	}
+	wm, err := s.client.delete("/v1/service/"+name+"/"+id, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
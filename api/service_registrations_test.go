@@ -0,0 +1,30 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServices_Empty(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	services := c.Services()
+
+	list, qm, err := services.List(nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Empty(list)
+
+	instances, _, err := services.Get("example", nil)
+	require.NoError(err)
+	require.Empty(instances)
+
+	_, err = services.Delete("example", "unknown", nil)
+	require.Error(err)
+
+	_, _, err = services.Get("", nil)
+	require.Error(err)
+}
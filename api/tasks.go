@@ -187,7 +187,7 @@ func NewDefaultReschedulePolicy(jobType string) *ReschedulePolicy {
 			Unlimited:     helper.BoolToPtr(structs.DefaultBatchJobReschedulePolicy.Unlimited),
 		}
 
-	case "system":
+	case "system", "sysbatch":
 		dp = &ReschedulePolicy{
 			Attempts:      helper.IntToPtr(0),
 			Interval:      helper.TimeToPtr(0),
@@ -345,11 +345,19 @@ type Service struct {
 	AddressMode  string   `mapstructure:"address_mode"`
 	Checks       []ServiceCheck
 	CheckRestart *CheckRestart `mapstructure:"check_restart"`
+	Provider     string
+	Connect      *ConsulConnect
 }
 
+// Canonicalize canonicalizes the service of a task, or of the task group if
+// the task is nil.
 func (s *Service) Canonicalize(t *Task, tg *TaskGroup, job *Job) {
 	if s.Name == "" {
-		s.Name = fmt.Sprintf("%s-%s-%s", *job.Name, *tg.Name, t.Name)
+		if t != nil {
+			s.Name = fmt.Sprintf("%s-%s-%s", *job.Name, *tg.Name, t.Name)
+		} else {
+			s.Name = fmt.Sprintf("%s-%s", *job.Name, *tg.Name)
+		}
 	}
 
 	// Default to AddressModeAuto
@@ -357,12 +365,80 @@ func (s *Service) Canonicalize(t *Task, tg *TaskGroup, job *Job) {
 		s.AddressMode = "auto"
 	}
 
+	// Default to registering the service in Consul
+	if s.Provider == "" {
+		s.Provider = "consul"
+	}
+
 	// Canonicalize CheckRestart on Checks and merge Service.CheckRestart
 	// into each check.
 	for i, check := range s.Checks {
 		s.Checks[i].CheckRestart = s.CheckRestart.Merge(check.CheckRestart)
 		s.Checks[i].CheckRestart.Canonicalize()
 	}
+
+	s.Connect.Canonicalize()
+}
+
+// ConsulConnect registers a service in the Consul Connect service mesh.
+type ConsulConnect struct {
+	SidecarService *ConsulSidecarService `mapstructure:"sidecar_service"`
+	SidecarTask    *SidecarTask          `mapstructure:"sidecar_task"`
+}
+
+func (c *ConsulConnect) Canonicalize() {
+	if c == nil {
+		return
+	}
+	c.SidecarTask.Canonicalize()
+}
+
+// ConsulSidecarService is the sidecar proxy service of a Connect service.
+type ConsulSidecarService struct {
+	Tags  []string
+	Port  string
+	Proxy *ConsulProxy
+}
+
+// ConsulProxy configures the sidecar proxy of a Connect service.
+type ConsulProxy struct {
+	LocalServiceAddress string `mapstructure:"local_service_address"`
+	LocalServicePort    int    `mapstructure:"local_service_port"`
+	Upstreams           []*ConsulUpstream
+	Config              map[string]interface{}
+}
+
+// ConsulUpstream is a service the sidecar proxy exposes locally.
+type ConsulUpstream struct {
+	DestinationName string `mapstructure:"destination_name"`
+	LocalBindPort   int    `mapstructure:"local_bind_port"`
+}
+
+// SidecarTask overrides the task running the sidecar proxy of a Connect
+// service.
+type SidecarTask struct {
+	Driver        string
+	User          string
+	Config        map[string]interface{}
+	Env           map[string]string
+	Resources     *Resources
+	Meta          map[string]string
+	KillTimeout   *time.Duration `mapstructure:"kill_timeout"`
+	LogConfig     *LogConfig     `mapstructure:"logs"`
+	ShutdownDelay *time.Duration `mapstructure:"shutdown_delay"`
+	KillSignal    string         `mapstructure:"kill_signal"`
+}
+
+func (t *SidecarTask) Canonicalize() {
+	if t == nil {
+		return
+	}
+	if t.Resources != nil {
+		t.Resources.Canonicalize()
+	}
+	if t.LogConfig != nil {
+		t.LogConfig.Canonicalize()
+	}
 }
 
 // EphemeralDisk is an ephemeral disk object
@@ -467,6 +543,9 @@ type TaskGroup struct {
 	Update           *UpdateStrategy
 	Migrate          *MigrateStrategy
 	Meta             map[string]string
+	Volumes          map[string]*VolumeRequest
+	Networks         []*NetworkResource
+	Services         []*Service
 }
 
 // NewTaskGroup creates a new TaskGroup.
@@ -492,6 +571,12 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 	} else {
 		g.EphemeralDisk.Canonicalize()
 	}
+	for _, n := range g.Networks {
+		n.Canonicalize()
+	}
+	for _, s := range g.Services {
+		s.Canonicalize(nil, g, job)
+	}
 
 	// Merge the update policy from the job
 	if ju, tu := job.Update != nil, g.Update != nil; ju && tu {
@@ -519,7 +604,7 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 		g.ReschedulePolicy = jobReschedule
 	}
 	// Only use default reschedule policy for non system jobs
-	if g.ReschedulePolicy == nil && *job.Type != "system" {
+	if g.ReschedulePolicy == nil && *job.Type != "system" && *job.Type != "sysbatch" {
 		g.ReschedulePolicy = NewDefaultReschedulePolicy(*job.Type)
 	}
 	if g.ReschedulePolicy != nil {
@@ -635,6 +720,30 @@ type DispatchPayloadConfig struct {
 	File string
 }
 
+// TaskLifecycle configures when a task is run relative to the main tasks of
+// its group.
+type TaskLifecycle struct {
+	Hook    string `mapstructure:"hook"`
+	Sidecar bool   `mapstructure:"sidecar"`
+}
+
+// VolumeRequest is a representation of a storage volume that a TaskGroup
+// wishes to use.
+type VolumeRequest struct {
+	Name     string
+	Type     string
+	Source   string
+	ReadOnly bool `mapstructure:"read_only"`
+}
+
+// VolumeMount represents the relationship between a destination path in a
+// task and the task group volume that should be mounted there.
+type VolumeMount struct {
+	Volume      string
+	Destination string
+	ReadOnly    bool `mapstructure:"read_only"`
+}
+
 // Task is a single process in a task group.
 type Task struct {
 	Name            string
@@ -656,6 +765,9 @@ type Task struct {
 	Leader          bool
 	ShutdownDelay   time.Duration `mapstructure:"shutdown_delay"`
 	KillSignal      string        `mapstructure:"kill_signal"`
+	Lifecycle       *TaskLifecycle
+	VolumeMounts    []*VolumeMount
+	Kind            string
 }
 
 func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
@@ -874,6 +986,7 @@ const (
 	TaskSignaling              = "Signaling"
 	TaskRestartSignal          = "Restart Signaled"
 	TaskLeaderDead             = "Leader Task Dead"
+	TaskMainDead               = "Main Tasks Dead"
 	TaskBuildingTaskDir        = "Building Task Directory"
 )
 
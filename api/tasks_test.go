@@ -271,7 +271,7 @@ func TestTask_Require(t *testing.T) {
 			{
 				CIDR:          "0.0.0.0/0",
 				MBits:         helper.IntToPtr(100),
-				ReservedPorts: []Port{{"", 80}, {"", 443}},
+				ReservedPorts: []Port{{Label: "", Value: 80}, {Label: "", Value: 443}},
 			},
 		},
 	}
@@ -376,6 +376,7 @@ func TestTaskGroup_Canonicalize_Update(t *testing.T) {
 		ID: helper.StringToPtr("test"),
 		Update: &UpdateStrategy{
 			AutoRevert:       helper.BoolToPtr(false),
+			AutoPromote:      helper.BoolToPtr(false),
 			Canary:           helper.IntToPtr(0),
 			HealthCheck:      helper.StringToPtr(""),
 			HealthyDeadline:  helper.TimeToPtr(0),
@@ -753,3 +754,23 @@ func TestService_CheckRestart(t *testing.T) {
 	assert.Equal(t, *service.Checks[2].CheckRestart.Grace, 11*time.Second)
 	assert.True(t, service.Checks[2].CheckRestart.IgnoreWarnings)
 }
+
+// TestService_Canonicalize_Group asserts the services of task groups are
+// named after the group and canonicalize their Connect sidecar task.
+func TestService_Canonicalize_Group(t *testing.T) {
+	job := &Job{Name: helper.StringToPtr("job")}
+	tg := &TaskGroup{Name: helper.StringToPtr("group")}
+	service := &Service{
+		Connect: &ConsulConnect{
+			SidecarService: &ConsulSidecarService{},
+			SidecarTask: &SidecarTask{
+				LogConfig: &LogConfig{MaxFiles: helper.IntToPtr(3)},
+			},
+		},
+	}
+
+	service.Canonicalize(nil, tg, job)
+	assert.Equal(t, "job-group", service.Name)
+	assert.Equal(t, 3, *service.Connect.SidecarTask.LogConfig.MaxFiles)
+	assert.Equal(t, 10, *service.Connect.SidecarTask.LogConfig.MaxFileSizeMB)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+)
+
+// Variables is used to query the variables endpoints.
+type Variables struct {
+	client *Client
+}
+
+// Variables returns a new handle on the variables.
+func (c *Client) Variables() *Variables {
+	return &Variables{client: c}
+}
+
+// VariableMetadata is the metadata of a variable
+type VariableMetadata struct {
+	Namespace   string
+	Path        string
+	CreateTime  int64
+	ModifyTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Variable is a variable with its items
+type Variable struct {
+	Namespace   string
+	Path        string
+	CreateTime  int64
+	ModifyTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+
+	// Items are the key value pairs stored encrypted by the servers
+	Items map[string]string
+}
+
+// List is used to list the variables whose path starts with the prefix of
+// the query options.
+func (v *Variables) List(q *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	var resp []*VariableMetadata
+	qm, err := v.client.query("/v1/vars", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Read is used to query the variable at a path
+func (v *Variables) Read(path string, q *QueryOptions) (*Variable, *QueryMeta, error) {
+	if path == "" {
+		return nil, nil, fmt.Errorf("missing variable path")
+	}
+	var resp Variable
+	qm, err := v.client.query("/v1/var/"+path, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Upsert is used to create or update a variable
+func (v *Variables) Upsert(variable *Variable, q *WriteOptions) (*VariableMetadata, *WriteMeta, error) {
+	if variable == nil || variable.Path == "" {
+		return nil, nil, fmt.Errorf("missing variable path")
+	}
+	var resp VariableMetadata
+	wm, err := v.client.write("/v1/var/"+variable.Path, variable, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// Delete is used to delete the variable at a path
+func (v *Variables) Delete(path string, q *WriteOptions) (*WriteMeta, error) {
+	if path == "" {
+		return nil, fmt.Errorf("missing variable path")
+	}
+	wm, err := v.client.delete("/v1/var/"+path, nil, q)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
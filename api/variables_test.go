@@ -0,0 +1,62 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariables_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	vars := c.Variables()
+
+	// Create a variable
+	sv := &Variable{
+		Path:  "app/web",
+		Items: map[string]string{"password": "hunter2"},
+	}
+	meta, wm, err := vars.Upsert(sv, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+	require.Equal(sv.Path, meta.Path)
+
+	// Read it back
+	out, qm, err := vars.Read(sv.Path, nil)
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Equal(sv.Items, out.Items)
+
+	// List it
+	list, qm, err := vars.List(&QueryOptions{Prefix: "app/"})
+	require.NoError(err)
+	assertQueryMeta(t, qm)
+	require.Len(list, 1)
+
+	// Delete it
+	wm, err = vars.Delete(sv.Path, nil)
+	require.NoError(err)
+	assertWriteMeta(t, wm)
+
+	list, _, err = vars.List(nil)
+	require.NoError(err)
+	require.Len(list, 0)
+}
+
+func TestOperator_KeyringRotate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	op := c.Operator()
+
+	key, _, err := op.KeyringRotate(false, nil)
+	require.NoError(err)
+	require.True(key.Active)
+
+	keys, _, err := op.KeyringList(nil)
+	require.NoError(err)
+	require.Len(keys, 2)
+}
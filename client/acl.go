@@ -14,6 +14,10 @@ const (
 	// so we keep the hot policies cached to reduce the ACL token resolution time.
 	policyCacheSize = 64
 
+	// roleCacheSize is the number of ACL roles to keep cached. Roles have a fetching cost
+	// so we keep the hot roles cached to reduce the ACL token resolution time.
+	roleCacheSize = 64
+
 	// aclCacheSize is the number of ACL objects to keep cached. ACLs have a parsing and
 	// construction cost, so we keep the hot objects cached to reduce the ACL token resolution time.
 	aclCacheSize = 64
@@ -32,6 +36,9 @@ type clientACLResolver struct {
 	// policyCache is used to maintain the fetched policy objects
 	policyCache *lru.TwoQueueCache
 
+	// roleCache is used to maintain the fetched role objects
+	roleCache *lru.TwoQueueCache
+
 	// tokenCache is used to maintain the fetched token objects
 	tokenCache *lru.TwoQueueCache
 }
@@ -48,6 +55,10 @@ func (c *clientACLResolver) init() error {
 	if err != nil {
 		return err
 	}
+	c.roleCache, err = lru.New2Q(roleCacheSize)
+	if err != nil {
+		return err
+	}
 	c.tokenCache, err = lru.New2Q(tokenCacheSize)
 	if err != nil {
 		return err
@@ -55,10 +66,11 @@ func (c *clientACLResolver) init() error {
 	return nil
 }
 
-// cachedACLValue is used to manage ACL Token or Policy TTLs
+// cachedACLValue is used to manage ACL Token, Policy or Role TTLs
 type cachedACLValue struct {
 	Token     *structs.ACLToken
 	Policy    *structs.ACLPolicy
+	Role      *structs.ACLRole
 	CacheTime time.Time
 }
 
@@ -84,14 +96,27 @@ func (c *Client) ResolveToken(secretID string) (*acl.ACL, error) {
 	if token == nil {
 		return nil, structs.ErrTokenNotFound
 	}
+	if token.IsExpired(time.Now().UTC()) {
+		return nil, structs.ErrTokenExpired
+	}
 
 	// Check if this is a management token
 	if token.Type == structs.ACLManagementToken {
 		return acl.ManagementACL, nil
 	}
 
+	// Resolve the roles, and add the policies they grant
+	policyNames := token.Policies
+	if len(token.Roles) != 0 {
+		roles, err := c.resolveRoles(token.SecretID, token.Roles)
+		if err != nil {
+			return nil, err
+		}
+		policyNames = rolePolicyNames(token.Policies, roles)
+	}
+
 	// Resolve the policies
-	policies, err := c.resolvePolicies(token.SecretID, token.Policies)
+	policies, err := c.resolvePolicies(token.SecretID, policyNames)
 	if err != nil {
 		return nil, err
 	}
@@ -104,6 +129,28 @@ func (c *Client) ResolveToken(secretID string) (*acl.ACL, error) {
 	return aclObj, nil
 }
 
+// ResolveSecretToken is used to translate an ACL Token Secret ID into the
+// ACL token, nil if ACLs are disabled, or an error. The token is cached like
+// the ones resolved by ResolveToken.
+func (c *Client) ResolveSecretToken(secretID string) (*structs.ACLToken, error) {
+	// Fast-path if ACLs are disabled
+	if !c.config.ACLEnabled {
+		return nil, nil
+	}
+
+	token, err := c.resolveTokenValue(secretID)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, structs.ErrTokenNotFound
+	}
+	if token.IsExpired(time.Now().UTC()) {
+		return nil, structs.ErrTokenExpired
+	}
+	return token, nil
+}
+
 // resolveTokenValue is used to translate a secret ID into an ACL token with caching
 // We use a local cache up to the TTL limit, and then resolve via a server. If we cannot
 // reach a server, but have a cached value we extend the TTL to gracefully handle outages.
@@ -217,3 +264,92 @@ func (c *Client) resolvePolicies(secretID string, policies []string) ([]*structs
 	// Return the valid policies
 	return out, nil
 }
+
+// resolveRoles is used to translate a set of named ACL roles into the objects.
+// Roles are cached the same way as policies, and share the policy cache TTL.
+func (c *Client) resolveRoles(secretID string, roles []string) ([]*structs.ACLRole, error) {
+	var out []*structs.ACLRole
+	var expired []*structs.ACLRole
+	var missing []string
+
+	// Scan the cache for each role
+	for _, roleName := range roles {
+		// Lookup the role in the cache
+		raw, ok := c.roleCache.Get(roleName)
+		if !ok {
+			missing = append(missing, roleName)
+			continue
+		}
+
+		// Check if the cached value is valid or expired
+		cached := raw.(*cachedACLValue)
+		if cached.Age() <= c.config.ACLPolicyTTL {
+			out = append(out, cached.Role)
+		} else {
+			expired = append(expired, cached.Role)
+		}
+	}
+
+	// Hot-path if we have no missing or expired roles
+	if len(missing)+len(expired) == 0 {
+		return out, nil
+	}
+
+	// Lookup the missing and expired roles
+	fetch := missing
+	for _, r := range expired {
+		fetch = append(fetch, r.Name)
+	}
+	req := structs.ACLRoleSetRequest{
+		Names: fetch,
+		QueryOptions: structs.QueryOptions{
+			Region:     c.Region(),
+			AuthToken:  secretID,
+			AllowStale: true,
+		},
+	}
+	var resp structs.ACLRoleSetResponse
+	if err := c.RPC("ACL.GetRoles", &req, &resp); err != nil {
+		// If we encounter an error but have cached roles, mask the error and extend the cache
+		if len(missing) == 0 {
+			c.logger.Printf("[WARN] client: failed to resolve roles, using expired cached value: %v", err)
+			out = append(out, expired...)
+			return out, nil
+		}
+		return nil, err
+	}
+
+	// Handle each output
+	for _, role := range resp.Roles {
+		c.roleCache.Add(role.Name, &cachedACLValue{
+			Role:      role,
+			CacheTime: time.Now(),
+		})
+		out = append(out, role)
+	}
+
+	// Return the valid roles
+	return out, nil
+}
+
+// rolePolicyNames returns the policy names merged with those granted by the
+// roles, without duplicates.
+func rolePolicyNames(policies []string, roles []*structs.ACLRole) []string {
+	seen := make(map[string]struct{}, len(policies))
+	var out []string
+	for _, name := range policies {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			out = append(out, name)
+		}
+	}
+	for _, role := range roles {
+		for _, name := range role.Policies {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				out = append(out, name)
+			}
+		}
+	}
+	return out
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/config"
@@ -10,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestClient_ACL_resolveTokenValue(t *testing.T) {
@@ -165,3 +167,78 @@ func TestClient_ACL_ResolveToken(t *testing.T) {
 	assert.Equal(t, structs.ErrTokenNotFound, err)
 	assert.Nil(t, out4)
 }
+
+func TestClient_ACL_ResolveToken_RolesAndExpiration(t *testing.T) {
+	s1, _, _ := testACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1 := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = s1
+		c.ACLEnabled = true
+	})
+	defer c1.Shutdown()
+
+	// Create a token granted a policy through a role, and an expired token
+	policy := mock.ACLPolicy()
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name}
+	expired := mock.ACLToken()
+	past := time.Now().UTC().Add(-time.Minute)
+	expired.ExpirationTime = &past
+
+	require := require.New(t)
+	require.NoError(s1.State().UpsertACLPolicies(100, []*structs.ACLPolicy{policy}))
+	require.NoError(s1.State().UpsertACLRoles(105, []*structs.ACLRole{role}))
+	require.NoError(s1.State().UpsertACLTokens(110, []*structs.ACLToken{token, expired}))
+
+	out, err := c1.ResolveToken(token.SecretID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.True(out.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+
+	// The role should be cached
+	_, ok := c1.roleCache.Get(role.Name)
+	require.True(ok)
+
+	out, err = c1.ResolveToken(expired.SecretID)
+	require.Equal(structs.ErrTokenExpired, err)
+	require.Nil(out)
+}
+
+func TestClient_ACL_ResolveSecretToken(t *testing.T) {
+	s1, _, _ := testACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	c1 := TestClient(t, func(c *config.Config) {
+		c.RPCHandler = s1
+		c.ACLEnabled = true
+		c.ACLTokenTTL = time.Hour
+	})
+	defer c1.Shutdown()
+
+	token := mock.ACLToken()
+	require := require.New(t)
+	require.NoError(s1.State().UpsertACLTokens(110, []*structs.ACLToken{token}))
+
+	out, err := c1.ResolveSecretToken(token.SecretID)
+	require.NoError(err)
+	require.Equal(token.AccessorID, out.AccessorID)
+
+	// The token is cached
+	require.NoError(s1.State().DeleteACLTokens(120, []string{token.AccessorID}))
+	out, err = c1.ResolveSecretToken(token.SecretID)
+	require.NoError(err)
+	require.Equal(token.AccessorID, out.AccessorID)
+
+	out, err = c1.ResolveSecretToken("")
+	require.NoError(err)
+	require.Equal(structs.AnonymousACLToken, out)
+
+	_, err = c1.ResolveSecretToken(uuid.Generate())
+	require.Equal(structs.ErrTokenNotFound, err)
+}
@@ -1,12 +1,25 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/driver"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ugorji/go/codec"
+)
+
+var (
+	commandNotPresentErr = fmt.Errorf("must provide a command")
 )
 
 // Allocations endpoint is used for interacting with client allocations
@@ -14,6 +27,12 @@ type Allocations struct {
 	c *Client
 }
 
+func NewAllocationsEndpoint(c *Client) *Allocations {
+	a := &Allocations{c}
+	a.c.streamingRpcs.Register("Allocations.Exec", a.exec)
+	return a
+}
+
 // GarbageCollectAll is used to garbage collect all allocations on a client.
 func (a *Allocations) GarbageCollectAll(args *nstructs.NodeSpecificRequest, reply *nstructs.GenericResponse) error {
 	defer metrics.MeasureSince([]string{"client", "allocations", "garbage_collect_all"}, time.Now())
@@ -48,6 +67,34 @@ func (a *Allocations) GarbageCollect(args *nstructs.AllocSpecificRequest, reply
 	return nil
 }
 
+// Signal is used to send a signal to the tasks of an allocation.
+func (a *Allocations) Signal(args *nstructs.AllocSignalRequest, reply *nstructs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "signal"}, time.Now())
+
+	// Check alloc lifecycle permissions
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return a.c.SignalAllocation(args.AllocID, args.Task, args.Signal)
+}
+
+// Restart is used to restart the tasks of an allocation.
+func (a *Allocations) Restart(args *nstructs.AllocRestartRequest, reply *nstructs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "allocations", "restart"}, time.Now())
+
+	// Check alloc lifecycle permissions
+	if aclObj, err := a.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+		return nstructs.ErrPermissionDenied
+	}
+
+	return a.c.RestartAllocation(args.AllocID, args.TaskName)
+}
+
 // Stats is used to collect allocation statistics
 func (a *Allocations) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.AllocStatsResponse) error {
 	defer metrics.MeasureSince([]string{"client", "allocations", "stats"}, time.Now())
@@ -73,3 +120,187 @@ func (a *Allocations) Stats(args *cstructs.AllocStatsRequest, reply *cstructs.Al
 	reply.Stats = stats
 	return nil
 }
+
+// exec is used to run an interactive command inside a running task of an
+// allocation. After the request, the remote side sends ExecStreamingInput
+// messages and receives StreamErrWrapper messages whose payload is a JSON
+// encoded ExecStreamingOutput.
+func (a *Allocations) exec(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "allocations", "exec"}, time.Now())
+	defer conn.Close()
+
+	// Decode the arguments
+	var req cstructs.AllocExecRequest
+	decoder := codec.NewDecoder(conn, nstructs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, nstructs.MsgpackHandle)
+
+	if err := decoder.Decode(&req); err != nil {
+		a.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Resolve the token
+	aclObj, err := a.c.ResolveToken(req.QueryOptions.AuthToken)
+	if err != nil {
+		a.handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	// Validate the arguments
+	if req.AllocID == "" {
+		a.handleStreamResultError(allocIDNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	if req.Task == "" {
+		a.handleStreamResultError(taskNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	if len(req.Cmd) == 0 {
+		a.handleStreamResultError(commandNotPresentErr, helper.Int64ToPtr(400), encoder)
+		return
+	}
+
+	ar, ok := a.c.getAllocRunners()[req.AllocID]
+	if !ok {
+		a.handleStreamResultError(nstructs.NewErrUnknownAllocation(req.AllocID), helper.Int64ToPtr(404), encoder)
+		return
+	}
+
+	// Check exec permissions in the namespace of the allocation rather than
+	// the one given in the request
+	alloc := ar.Alloc()
+	if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) {
+		a.handleStreamResultError(nstructs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	// Check that the task is there
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		a.handleStreamResultError(fmt.Errorf("Failed to lookup task group for allocation"),
+			helper.Int64ToPtr(500), encoder)
+		return
+	} else if taskStruct := tg.LookupTask(req.Task); taskStruct == nil {
+		a.handleStreamResultError(
+			fmt.Errorf("task group %q does not have task with name %q", alloc.TaskGroup, req.Task),
+			helper.Int64ToPtr(400),
+			encoder)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stdinR, stdinW := io.Pipe()
+	defer stdinR.Close()
+	resizeCh := make(chan driver.TerminalSize, 1)
+
+	// Create a goroutine to forward the input of the remote side. The
+	// command is stopped if the remote side goes away.
+	go func() {
+		defer stdinW.Close()
+		for {
+			var in cstructs.ExecStreamingInput
+			if err := decoder.Decode(&in); err != nil {
+				cancel()
+				return
+			}
+
+			if in.Stdin != nil {
+				if len(in.Stdin.Data) != 0 {
+					if _, err := stdinW.Write(in.Stdin.Data); err != nil {
+						continue
+					}
+				}
+				if in.Stdin.Close {
+					stdinW.Close()
+				}
+			}
+
+			if in.TTYSize != nil {
+				size := driver.TerminalSize{Height: in.TTYSize.Height, Width: in.TTYSize.Width}
+				select {
+				case resizeCh <- size:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	out := &execOutputWriter{encoder: encoder, conn: conn}
+	opts := &driver.ExecStreamingOptions{
+		Command:  req.Cmd,
+		Tty:      req.Tty,
+		Stdin:    stdinR,
+		Stdout:   out.stream(false),
+		Stderr:   out.stream(true),
+		ResizeCh: resizeCh,
+	}
+
+	code, err := ar.ExecTask(ctx, req.Task, opts)
+	if err != nil {
+		a.handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	out.send(&cstructs.ExecStreamingOutput{
+		Exited: true,
+		Result: &cstructs.ExecStreamingExitResult{ExitCode: code},
+	})
+}
+
+func (a *Allocations) handleStreamResultError(err error, code *int64, encoder *codec.Encoder) {
+	// Nothing to do as the conn is closed
+	if err == io.EOF || strings.Contains(err.Error(), "closed") {
+		return
+	}
+
+	encoder.Encode(&cstructs.StreamErrWrapper{
+		Error: cstructs.NewRpcError(err, code),
+	})
+}
+
+// execOutputWriter encodes the output of a command as ExecStreamingOutput
+// messages. The output streams of a command may be written concurrently.
+type execOutputWriter struct {
+	encoder *codec.Encoder
+	conn    io.Writer
+	lock    sync.Mutex
+}
+
+// send writes a single message to the remote side.
+func (w *execOutputWriter) send(out *cstructs.ExecStreamingOutput) error {
+	payload, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if err := w.encoder.Encode(&cstructs.StreamErrWrapper{Payload: payload}); err != nil {
+		return err
+	}
+	w.encoder.Reset(w.conn)
+	return nil
+}
+
+// stream returns a writer for the stdout or stderr stream of the command.
+func (w *execOutputWriter) stream(stderr bool) io.Writer {
+	return execStreamWriterFunc(func(p []byte) (int, error) {
+		op := &cstructs.ExecStreamingIOOperation{Data: p}
+		out := &cstructs.ExecStreamingOutput{Stdout: op}
+		if stderr {
+			out = &cstructs.ExecStreamingOutput{Stderr: op}
+		}
+		if err := w.send(out); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	})
+}
+
+// execStreamWriterFunc adapts a function to an io.Writer.
+type execStreamWriterFunc func(p []byte) (int, error)
+
+func (f execStreamWriterFunc) Write(p []byte) (int, error) { return f(p) }
@@ -1,8 +1,13 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/config"
@@ -11,6 +16,7 @@ import (
 	nstructs "github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
 )
 
 func TestAllocations_GarbageCollectAll(t *testing.T) {
@@ -173,6 +179,225 @@ func TestAllocations_GarbageCollect_ACL(t *testing.T) {
 	}
 }
 
+func TestAllocations_Signal(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client := TestClient(t, nil)
+	defer client.Shutdown()
+
+	a := mock.Alloc()
+	a.Job.TaskGroups[0].Tasks[0].Driver = "mock_driver"
+	a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	require.Nil(client.addAlloc(a, ""))
+
+	// Try with bad alloc
+	req := &nstructs.AllocSignalRequest{Signal: "SIGHUP"}
+	var resp nstructs.GenericResponse
+	err := client.ClientRPC("Allocations.Signal", &req, &resp)
+	require.True(nstructs.IsErrUnknownAllocation(err))
+
+	// Try with an unknown signal
+	req.AllocID = a.ID
+	req.Signal = "SIGFOO"
+	err = client.ClientRPC("Allocations.Signal", &req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "not supported")
+
+	// Try with an unknown task
+	req.Signal = "hup"
+	req.Task = "foo"
+	err = client.ClientRPC("Allocations.Signal", &req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "unknown task")
+
+	// Try with good alloc once its tasks are started
+	req.Task = a.Job.TaskGroups[0].Tasks[0].Name
+	testutil.WaitForResult(func() (bool, error) {
+		var resp2 nstructs.GenericResponse
+		if err := client.ClientRPC("Allocations.Signal", &req, &resp2); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestAllocations_Signal_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer client.Shutdown()
+
+	// Try request without a token and expect failure
+	{
+		req := &nstructs.AllocSignalRequest{Signal: "SIGHUP"}
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with an invalid token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "invalid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req := &nstructs.AllocSignalRequest{Signal: "SIGHUP"}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a valid token
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "test-valid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+		req := &nstructs.AllocSignalRequest{Signal: "SIGHUP"}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+
+	// Try request with a management token
+	{
+		req := &nstructs.AllocSignalRequest{Signal: "SIGHUP"}
+		req.AuthToken = root.SecretID
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Signal", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+}
+
+func TestAllocations_Restart(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client := TestClient(t, nil)
+	defer client.Shutdown()
+
+	a := mock.Alloc()
+	a.Job.TaskGroups[0].Tasks[0].Driver = "mock_driver"
+	a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	require.Nil(client.addAlloc(a, ""))
+
+	// Try with bad alloc
+	req := &nstructs.AllocRestartRequest{}
+	var resp nstructs.GenericResponse
+	err := client.ClientRPC("Allocations.Restart", &req, &resp)
+	require.True(nstructs.IsErrUnknownAllocation(err))
+
+	// Try with an unknown task
+	req.AllocID = a.ID
+	req.TaskName = "foo"
+	err = client.ClientRPC("Allocations.Restart", &req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "unknown task")
+
+	// Restart the task once it is running. Restarts of tasks that haven't
+	// started yet are skipped so retry until the task records the restart.
+	taskName := a.Job.TaskGroups[0].Tasks[0].Name
+	req.TaskName = taskName
+	testutil.WaitForResult(func() (bool, error) {
+		var resp2 nstructs.GenericResponse
+		if err := client.ClientRPC("Allocations.Restart", &req, &resp2); err != nil {
+			return false, err
+		}
+
+		ar, ok := client.getAllocRunners()[a.ID]
+		if !ok {
+			return false, fmt.Errorf("alloc runner not found")
+		}
+		state := ar.Alloc().TaskStates[taskName]
+		if state == nil {
+			return false, fmt.Errorf("no task state")
+		}
+		for _, e := range state.Events {
+			if e.Type == nstructs.TaskRestartSignal {
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("no restart event in %v", state.Events)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+func TestAllocations_Restart_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer client.Shutdown()
+
+	// Try request without a token and expect failure
+	{
+		req := &nstructs.AllocRestartRequest{}
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with an invalid token and expect failure
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "invalid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+		req := &nstructs.AllocRestartRequest{}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.NotNil(err)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// Try request with a valid token
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "test-valid",
+			mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+		req := &nstructs.AllocRestartRequest{}
+		req.AuthToken = token.SecretID
+		req.Namespace = nstructs.DefaultNamespace
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+
+	// Try request with a management token
+	{
+		req := &nstructs.AllocRestartRequest{}
+		req.AuthToken = root.SecretID
+
+		var resp nstructs.GenericResponse
+		err := client.ClientRPC("Allocations.Restart", &req, &resp)
+		require.True(nstructs.IsErrUnknownAllocation(err))
+	}
+}
+
 func TestAllocations_Stats(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -262,3 +487,219 @@ func TestAllocations_Stats_ACL(t *testing.T) {
 		require.True(nstructs.IsErrUnknownAllocation(err))
 	}
 }
+
+// execStream runs the exec streaming RPC and returns the messages it sends
+// until the stream is closed.
+func execStream(t *testing.T, c *Client, req *cstructs.AllocExecRequest) []*cstructs.StreamErrWrapper {
+	handler, err := c.StreamingRpcHandler("Allocations.Exec")
+	require.Nil(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	go handler(p2)
+
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	require.Nil(t, encoder.Encode(req))
+
+	msgCh := make(chan []*cstructs.StreamErrWrapper, 1)
+	go func() {
+		var msgs []*cstructs.StreamErrWrapper
+		decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err != io.EOF && !strings.Contains(err.Error(), "closed") {
+					t.Errorf("error decoding: %v", err)
+				}
+				msgCh <- msgs
+				return
+			}
+			msgs = append(msgs, &msg)
+		}
+	}()
+
+	select {
+	case msgs := <-msgCh:
+		return msgs
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout")
+	}
+	return nil
+}
+
+func TestAllocations_Exec_NoAlloc(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client := TestClient(t, nil)
+	defer client.Shutdown()
+
+	req := &cstructs.AllocExecRequest{
+		AllocID: "26470238-5CF2-438F-8772-DC67CFB0705C",
+		Task:    "web",
+		Cmd:     []string{"/bin/sh"},
+	}
+
+	msgs := execStream(t, client, req)
+	require.Len(msgs, 1)
+	require.NotNil(msgs[0].Error)
+	require.EqualValues(404, *msgs[0].Error.Code)
+	require.True(nstructs.IsErrUnknownAllocation(msgs[0].Error))
+}
+
+func TestAllocations_Exec(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	client := TestClient(t, nil)
+	defer client.Shutdown()
+
+	a := mock.Alloc()
+	a.Job.TaskGroups[0].Tasks[0].Driver = "mock_driver"
+	a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	require.Nil(client.addAlloc(a, ""))
+
+	// Wait for the task to start
+	task := a.Job.TaskGroups[0].Tasks[0].Name
+	testutil.WaitForResult(func() (bool, error) {
+		alloc, err := client.GetClientAlloc(a.ID)
+		if err != nil {
+			return false, err
+		}
+		state := alloc.TaskStates[task]
+		if state == nil || state.State != nstructs.TaskStateRunning {
+			return false, fmt.Errorf("task not running: %v", state)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Interactive commands are not supported by the mock driver
+	req := &cstructs.AllocExecRequest{
+		AllocID: a.ID,
+		Task:    task,
+		Tty:     true,
+		Cmd:     []string{"/bin/sh"},
+	}
+	msgs := execStream(t, client, req)
+	require.Len(msgs, 1)
+	require.NotNil(msgs[0].Error)
+	require.Contains(msgs[0].Error.Error(), "does not support interactive commands")
+
+	// Buffered commands are
+	req.Tty = false
+	req.Cmd = []string{"echo", "hello"}
+	msgs = execStream(t, client, req)
+	require.Len(msgs, 2)
+
+	var out cstructs.ExecStreamingOutput
+	require.Nil(msgs[0].Error)
+	require.Nil(json.Unmarshal(msgs[0].Payload, &out))
+	require.NotNil(out.Stdout)
+	require.Equal(`Exec("echo", ["hello"])`, string(out.Stdout.Data))
+
+	out = cstructs.ExecStreamingOutput{}
+	require.Nil(msgs[1].Error)
+	require.Nil(json.Unmarshal(msgs[1].Payload, &out))
+	require.True(out.Exited)
+	require.Equal(0, out.Result.ExitCode)
+}
+
+func TestAllocations_Exec_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer client.Shutdown()
+
+	// Wait for the client to connect
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := server.State().NodeByID(nil, client.NodeID())
+		if err != nil {
+			return false, err
+		}
+		if node == nil {
+			return false, fmt.Errorf("unknown node")
+		}
+
+		return node.Status == nstructs.NodeStatusReady, fmt.Errorf("bad node status")
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Run an allocation in a namespace other than the default one
+	ns := mock.Namespace()
+	ns.Name = "other"
+	a := mock.Alloc()
+	a.NodeID = client.NodeID()
+	a.Namespace = ns.Name
+	a.Job.Namespace = ns.Name
+	a.Job.TaskGroups[0].Tasks[0].Driver = "mock_driver"
+	a.Job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+	state := server.State()
+	require.Nil(state.UpsertNamespaces(997, []*nstructs.Namespace{ns}))
+	require.Nil(state.UpsertJob(999, a.Job))
+	require.Nil(state.UpsertAllocs(1003, []*nstructs.Allocation{a}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		_, ok := client.getAllocRunners()[a.ID]
+		return ok, fmt.Errorf("alloc %q not running on the client", a.ID)
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Ask for a task that doesn't exist so that requests passing the ACL
+	// check fail right after it
+	req := &cstructs.AllocExecRequest{
+		AllocID: a.ID,
+		Task:    "missing",
+		Cmd:     []string{"/bin/sh"},
+	}
+	req.Namespace = nstructs.DefaultNamespace
+
+	// Try request without a token and expect failure
+	msgs := execStream(t, client, req)
+	require.Len(msgs, 1)
+	require.EqualError(msgs[0].Error, nstructs.ErrPermissionDenied.Error())
+
+	// Try request with a token that can only read the job
+	token := mock.CreatePolicyAndToken(t, state, 1005, "invalid",
+		mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityReadJob}))
+	req.AuthToken = token.SecretID
+	msgs = execStream(t, client, req)
+	require.Len(msgs, 1)
+	require.EqualError(msgs[0].Error, nstructs.ErrPermissionDenied.Error())
+
+	// Try request with a token that can exec in the namespace of the
+	// request but not in the one of the allocation
+	token = mock.CreatePolicyAndToken(t, state, 1007, "other-namespace",
+		mock.NamespacePolicy(nstructs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocExec}))
+	req.AuthToken = token.SecretID
+	msgs = execStream(t, client, req)
+	require.Len(msgs, 1)
+	require.EqualError(msgs[0].Error, nstructs.ErrPermissionDenied.Error())
+
+	// Try request with a valid token
+	token = mock.CreatePolicyAndToken(t, state, 1009, "test-valid",
+		mock.NamespacePolicy("other", "", []string{acl.NamespaceCapabilityAllocExec}))
+	req.AuthToken = token.SecretID
+	msgs = execStream(t, client, req)
+	require.Len(msgs, 1)
+	require.Contains(msgs[0].Error.Error(), "does not have task")
+
+	// Try request with a management token
+	req.AuthToken = root.SecretID
+	msgs = execStream(t, client, req)
+	require.Len(msgs, 1)
+	require.Contains(msgs[0].Error.Error(), "does not have task")
+}
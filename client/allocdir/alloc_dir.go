@@ -9,6 +9,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -43,6 +44,10 @@ var (
 	// task.
 	TmpDirName = "tmp"
 
+	// ConsulGRPCSocket is the path of the unix socket in the shared alloc
+	// directory proxying to the gRPC endpoint of the local Consul agent.
+	ConsulGRPCSocket = filepath.Join(TmpDirName, "consul_grpc.sock")
+
 	// The set of directories that exist inside each shared alloc directory.
 	SharedAllocDirs = []string{LogDirName, TmpDirName, SharedDataDir}
 
@@ -247,12 +252,28 @@ func (d *AllocDir) Move(other *AllocDir, tasks []*structs.Task) error {
 // Tears down previously build directory structure.
 func (d *AllocDir) Destroy() error {
 
-	// Unmount all mounted shared alloc dirs.
+	// Unmount all mounted shared alloc dirs and volumes.
 	var mErr multierror.Error
 	if err := d.UnmountAll(); err != nil {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
+	// The alloc dir isn't removed while a volume is still mounted into a
+	// task dir so the contents of the volume aren't deleted.
+	for _, dir := range d.TaskDirs {
+		volumes, err := dir.mountedVolumes()
+		if err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("not removing alloc dir %q: failed to find volumes mounted in %q: %v",
+				d.AllocDir, dir.Dir, err))
+			return mErr.ErrorOrNil()
+		}
+		if len(volumes) > 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("not removing alloc dir %q: volumes still mounted: %s",
+				d.AllocDir, strings.Join(volumes, ", ")))
+			return mErr.ErrorOrNil()
+		}
+	}
+
 	if err := os.RemoveAll(d.AllocDir); err != nil {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("failed to remove alloc dir %q: %v", d.AllocDir, err))
 	}
@@ -288,6 +309,11 @@ func (d *AllocDir) UnmountAll() error {
 		if err := dir.unmountSpecialDirs(); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
 		}
+
+		// Unmount the volumes mounted into the task dir
+		if err := dir.unmountVolumes(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
 	}
 
 	return mErr.ErrorOrNil()
@@ -328,13 +354,11 @@ func (d *AllocDir) Build() error {
 
 // List returns the list of files at a path relative to the alloc dir
 func (d *AllocDir) List(path string) ([]*cstructs.AllocFileInfo, error) {
-	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
-		return nil, fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
-	} else if escapes {
-		return nil, fmt.Errorf("Path escapes the alloc directory")
+	p, err := d.resolvePath(path)
+	if err != nil {
+		return nil, err
 	}
 
-	p := filepath.Join(d.AllocDir, path)
 	finfos, err := ioutil.ReadDir(p)
 	if err != nil {
 		return []*cstructs.AllocFileInfo{}, err
@@ -354,13 +378,11 @@ func (d *AllocDir) List(path string) ([]*cstructs.AllocFileInfo, error) {
 
 // Stat returns information about the file at a path relative to the alloc dir
 func (d *AllocDir) Stat(path string) (*cstructs.AllocFileInfo, error) {
-	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
-		return nil, fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
-	} else if escapes {
-		return nil, fmt.Errorf("Path escapes the alloc directory")
+	p, err := d.resolvePath(path)
+	if err != nil {
+		return nil, err
 	}
 
-	p := filepath.Join(d.AllocDir, path)
 	info, err := os.Stat(p)
 	if err != nil {
 		return nil, err
@@ -377,27 +399,29 @@ func (d *AllocDir) Stat(path string) (*cstructs.AllocFileInfo, error) {
 
 // ReadAt returns a reader for a file at the path relative to the alloc dir
 func (d *AllocDir) ReadAt(path string, offset int64) (io.ReadCloser, error) {
-	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
-		return nil, fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
-	} else if escapes {
-		return nil, fmt.Errorf("Path escapes the alloc directory")
+	p, err := d.resolvePath(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
 	}
 
-	p := filepath.Join(d.AllocDir, path)
-
-	// Check if it is trying to read into a secret directory
+	// Check if it is trying to read into a secret directory. Compare whole
+	// path components so siblings sharing the prefix remain readable.
 	for _, dir := range d.TaskDirs {
-		if filepath.HasPrefix(p, dir.SecretsDir) {
+		if p == dir.SecretsDir || strings.HasPrefix(p, dir.SecretsDir+string(filepath.Separator)) {
 			return nil, fmt.Errorf("Reading secret file prohibited: %s", path)
 		}
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	f, err := os.Open(p)
 	if err != nil {
 		return nil, err
 	}
 	if _, err := f.Seek(offset, 0); err != nil {
-		return nil, fmt.Errorf("can't seek to offset %q: %v", offset, err)
+		f.Close()
+		return nil, fmt.Errorf("can't seek to offset %d: %v", offset, err)
 	}
 	return f, nil
 }
@@ -405,14 +429,13 @@ func (d *AllocDir) ReadAt(path string, offset int64) (io.ReadCloser, error) {
 // BlockUntilExists blocks until the passed file relative the allocation
 // directory exists. The block can be cancelled with the passed context.
 func (d *AllocDir) BlockUntilExists(ctx context.Context, path string) (chan error, error) {
-	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
-		return nil, fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
-	} else if escapes {
-		return nil, fmt.Errorf("Path escapes the alloc directory")
+	// Get the path relative to the alloc directory. The file may not exist
+	// yet.
+	p, err := d.resolvePath(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
 	}
 
-	// Get the path relative to the alloc directory
-	p := filepath.Join(d.AllocDir, path)
 	watcher := getFileWatcher(p)
 	returnCh := make(chan error, 1)
 	t := &tomb.Tomb{}
@@ -431,10 +454,10 @@ func (d *AllocDir) BlockUntilExists(ctx context.Context, path string) (chan erro
 // allocation directory. The offset should be the last read offset. The context is
 // used to clean up the watch.
 func (d *AllocDir) ChangeEvents(ctx context.Context, path string, curOffset int64) (*watch.FileChanges, error) {
-	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
-		return nil, fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
-	} else if escapes {
-		return nil, fmt.Errorf("Path escapes the alloc directory")
+	// Get the path relative to the alloc directory
+	p, err := d.resolvePath(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
 	}
 
 	t := &tomb.Tomb{}
@@ -443,12 +466,86 @@ func (d *AllocDir) ChangeEvents(ctx context.Context, path string, curOffset int6
 		t.Kill(nil)
 	}()
 
-	// Get the path relative to the alloc directory
-	p := filepath.Join(d.AllocDir, path)
 	watcher := getFileWatcher(p)
 	return watcher.ChangeEvents(t, curOffset)
 }
 
+// resolvePath returns the path of a file relative to the alloc dir. Symlinks
+// are followed so that files linked from outside of the alloc dir can not be
+// accessed. If the file does not exist, the unresolved path is returned along
+// with the error.
+func (d *AllocDir) resolvePath(path string) (string, error) {
+	if escapes, err := structs.PathEscapesAllocDir("", path); err != nil {
+		return "", fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return "", fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	p := filepath.Join(d.AllocDir, path)
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return p, err
+	}
+
+	root, err := filepath.EvalSymlinks(d.AllocDir)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil {
+		return "", fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	return filepath.Join(d.AllocDir, rel), nil
+}
+
+// ResolveWithin returns the given absolute path with the symlinks of its
+// existing prefix resolved. An error is returned if the path resolves outside
+// of root, which must itself be resolved.
+func ResolveWithin(root, path string) (string, error) {
+	// The path is resolved one component at a time, as cleaning it first
+	// would drop a ".." following a symlink
+	vol := filepath.VolumeName(path)
+	resolved := vol + string(filepath.Separator)
+	parts := strings.Split(path[len(vol):], string(filepath.Separator))
+	for i, part := range parts {
+		if part == "" || part == "." {
+			continue
+		}
+		if part == ".." {
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, part)
+		if _, err := os.Lstat(next); err != nil {
+			if !os.IsNotExist(err) {
+				return "", err
+			}
+
+			// The rest of the path doesn't exist so it has no symlinks
+			resolved = filepath.Join(append([]string{next}, parts[i+1:]...)...)
+			break
+		}
+
+		r, err := filepath.EvalSymlinks(next)
+		if err != nil {
+			return "", err
+		}
+		resolved = r
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes %q", path, root)
+	}
+	return resolved, nil
+}
+
 // getFileWatcher returns a FileWatcher for the given path.
 func getFileWatcher(path string) watch.FileWatcher {
 	return watch.NewPollingFileWatcher(path)
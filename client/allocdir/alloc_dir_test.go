@@ -317,6 +317,50 @@ func TestAllocDir_EscapeChecking(t *testing.T) {
 	}
 }
 
+// Test that symlinks can't be used to escape the alloc dir
+func TestAllocDir_EscapeChecking_Symlink(t *testing.T) {
+	require := require.New(t)
+
+	tmp, err := ioutil.TempDir("", "AllocDir")
+	require.NoError(err)
+	defer os.RemoveAll(tmp)
+
+	outside, err := ioutil.TempDir("", "AllocDirOutside")
+	require.NoError(err)
+	defer os.RemoveAll(outside)
+	require.NoError(ioutil.WriteFile(filepath.Join(outside, "foo"), []byte("foo"), 0666))
+
+	d := NewAllocDir(testlog.Logger(t), tmp)
+	require.NoError(d.Build())
+	defer d.Destroy()
+
+	require.NoError(os.Symlink(outside, filepath.Join(d.SharedDir, "outside")))
+	require.NoError(os.Symlink(filepath.Join(outside, "foo"), filepath.Join(d.SharedDir, "foo")))
+
+	_, err = d.List(filepath.Join(SharedAllocName, "outside"))
+	require.Error(err)
+	require.Contains(err.Error(), "escapes")
+
+	_, err = d.Stat(filepath.Join(SharedAllocName, "foo"))
+	require.Error(err)
+	require.Contains(err.Error(), "escapes")
+
+	_, err = d.ReadAt(filepath.Join(SharedAllocName, "outside", "foo"), 0)
+	require.Error(err)
+	require.Contains(err.Error(), "escapes")
+
+	_, err = d.ChangeEvents(context.Background(), filepath.Join(SharedAllocName, "foo"), 0)
+	require.Error(err)
+	require.Contains(err.Error(), "escapes")
+
+	// Symlinks within the alloc dir can be followed
+	require.NoError(ioutil.WriteFile(filepath.Join(d.SharedDir, "bar"), []byte("bar"), 0666))
+	require.NoError(os.Symlink(filepath.Join(d.SharedDir, "bar"), filepath.Join(d.SharedDir, "baz")))
+	r, err := d.ReadAt(filepath.Join(SharedAllocName, "baz"), 0)
+	require.NoError(err)
+	r.Close()
+}
+
 // Test that `nomad fs` can't read secrets
 func TestAllocDir_ReadAt_SecretDir(t *testing.T) {
 	tmp, err := ioutil.TempDir("", "AllocDir")
@@ -341,6 +385,22 @@ func TestAllocDir_ReadAt_SecretDir(t *testing.T) {
 	if _, err := d.ReadAt(secret, 0); err == nil || !strings.Contains(err.Error(), "secret file prohibited") {
 		t.Fatalf("ReadAt of secret file didn't error: %v", err)
 	}
+
+	// ReadAt of the secret dir itself should fail
+	if _, err := d.ReadAt(filepath.Join(t1.Name, TaskSecrets), 0); err == nil || !strings.Contains(err.Error(), "secret file prohibited") {
+		t.Fatalf("ReadAt of secret dir didn't error: %v", err)
+	}
+
+	// ReadAt of a sibling sharing the secret dir's prefix should succeed
+	sibling := filepath.Join(t1.Name, TaskSecrets+"-public")
+	if err := ioutil.WriteFile(filepath.Join(tmp, sibling), []byte("foo"), 0666); err != nil {
+		t.Fatalf("Couldn't write file: %v", err)
+	}
+	r, err := d.ReadAt(sibling, 0)
+	if err != nil {
+		t.Fatalf("ReadAt of non-secret file failed: %v", err)
+	}
+	r.Close()
 }
 
 func TestAllocDir_SplitPath(t *testing.T) {
@@ -460,3 +520,32 @@ func TestPathFuncs(t *testing.T) {
 		t.Errorf("%q is not empty. empty=%v error=%v", dir, empty, err)
 	}
 }
+
+func TestResolveWithin(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	tmp, err := ioutil.TempDir("", "nomadtest")
+	require.NoError(err)
+	defer os.RemoveAll(tmp)
+	tmp, err = filepath.EvalSymlinks(tmp)
+	require.NoError(err)
+
+	root := filepath.Join(tmp, "root")
+	require.NoError(os.MkdirAll(filepath.Join(root, "sub"), 0777))
+	require.NoError(os.Symlink(".", filepath.Join(root, "self")))
+	require.NoError(os.Symlink(tmp, filepath.Join(root, "parent")))
+
+	// Symlinks and missing components are resolved within root
+	resolved, err := ResolveWithin(root, filepath.Join(root, "self", "sub", "missing"))
+	require.NoError(err)
+	require.Equal(filepath.Join(root, "sub", "missing"), resolved)
+
+	// A ".." following a symlink is applied to the symlink's target
+	_, err = ResolveWithin(root, root+"/self/..")
+	require.Error(err)
+	require.Contains(err.Error(), "escapes")
+
+	_, err = ResolveWithin(root, filepath.Join(root, "parent", "file"))
+	require.Error(err)
+}
@@ -40,6 +40,19 @@ type TaskDir struct {
 	logger *log.Logger
 }
 
+// VolumeMount is a path on the host that is mounted into a task.
+type VolumeMount struct {
+	// HostPath is the path of the volume on the host
+	HostPath string
+
+	// TaskPath is the path the volume is mounted at, relative to the root of
+	// the task's filesystem
+	TaskPath string
+
+	// ReadOnly mounts the volume read only
+	ReadOnly bool
+}
+
 // newTaskDir creates a TaskDir struct with paths set. Call Build() to
 // create paths on disk.
 //
@@ -132,6 +145,35 @@ func (t *TaskDir) Build(chrootCreated bool, chroot map[string]string, fsi cstruc
 	return nil
 }
 
+// MountVolumes mounts the given volumes into the task directory of a task using
+// chroot filesystem isolation. It is safe to call multiple times as volumes
+// that are already mounted are skipped.
+func (t *TaskDir) MountVolumes(mounts []*VolumeMount) error {
+	if len(mounts) == 0 {
+		return nil
+	}
+
+	root, err := filepath.EvalSymlinks(t.Dir)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mounts {
+		// The chroot may contain symlinks so ensure the volume is mounted
+		// within the task dir
+		dst, err := ResolveWithin(root, filepath.Join(root, m.TaskPath))
+		if err != nil {
+			return fmt.Errorf("Failed to mount volume %q: %v", m.HostPath, err)
+		}
+
+		if err := t.mountVolume(m.HostPath, dst, m.ReadOnly); err != nil {
+			return fmt.Errorf("Failed to mount volume %q at %q: %v", m.HostPath, m.TaskPath, err)
+		}
+	}
+
+	return nil
+}
+
 // buildChroot takes a mapping of absolute directory or file paths on the host
 // to their intended, relative location within the task directory. This
 // attempts hardlink and then defaults to copying. If the path exists on the
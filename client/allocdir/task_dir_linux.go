@@ -1,9 +1,13 @@
 package allocdir
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/hashicorp/go-multierror"
@@ -75,3 +79,145 @@ func (t *TaskDir) unmountSpecialDirs() error {
 
 	return errs.ErrorOrNil()
 }
+
+// mountVolume bind mounts the src path at dst, creating dst if necessary. No
+// error is returned if dst is already mounted.
+func (t *TaskDir) mountVolume(src, dst string, readOnly bool) error {
+	mounts, err := mountPoints()
+	if err != nil {
+		return err
+	}
+	for _, m := range mounts {
+		if m == dst {
+			return nil
+		}
+	}
+
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		if err := os.MkdirAll(dst, 0777); err != nil {
+			return err
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(dst), 0777); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(dst, os.O_CREATE|os.O_RDONLY, 0666)
+		if err != nil {
+			return err
+		}
+		f.Close()
+	}
+
+	if err := syscall.Mount(src, dst, "", syscall.MS_BIND, ""); err != nil {
+		return os.NewSyscallError("mount", err)
+	}
+
+	// Bind mounts can only be made read only by remounting them
+	if readOnly {
+		flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY)
+		if err := syscall.Mount("", dst, "", flags, ""); err != nil {
+			syscall.Unmount(dst, 0)
+			return os.NewSyscallError("mount", err)
+		}
+	}
+
+	return nil
+}
+
+// unmountVolumes unmounts the volumes mounted into the task dir. It must be
+// called once the other directories mounted into the task dir are unmounted
+// as every remaining mount is assumed to be a volume. Mounts are found in the
+// mount table so that volumes mounted before the client restarted are
+// unmounted too.
+func (t *TaskDir) unmountVolumes() error {
+	volumes, err := t.mountedVolumes()
+	if err != nil {
+		return err
+	}
+
+	// Unmount nested volumes first
+	sort.Sort(sort.Reverse(sort.StringSlice(volumes)))
+
+	errs := new(multierror.Error)
+	for _, v := range volumes {
+		if err := unlinkDir(v); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("Failed to unmount volume %q: %v", v, err))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// mountedVolumes returns the mount points under the task dir. Once the other
+// directories mounted into the task dir are unmounted these are volumes.
+func (t *TaskDir) mountedVolumes() ([]string, error) {
+	if !pathExists(t.Dir) {
+		return nil, nil
+	}
+
+	root, err := filepath.EvalSymlinks(t.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	mounts, err := mountPoints()
+	if err != nil {
+		return nil, err
+	}
+
+	var volumes []string
+	for _, m := range mounts {
+		if strings.HasPrefix(m, root+string(filepath.Separator)) {
+			volumes = append(volumes, m)
+		}
+	}
+	return volumes, nil
+}
+
+// mountPoints returns the mount points of the mount table of the process.
+func mountPoints() ([]string, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var mounts []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// The mount point is the fifth field, with whitespace octal escaped
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("unexpected mountinfo line: %q", scanner.Text())
+		}
+		mounts = append(mounts, unescapeMountPoint(fields[4]))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mounts, nil
+}
+
+// unescapeMountPoint replaces the octal escape sequences of a mount point
+// from the mount table.
+func unescapeMountPoint(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -4,8 +4,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"golang.org/x/sys/unix"
 )
@@ -71,3 +73,149 @@ func TestLinuxSpecialDirs(t *testing.T) {
 		t.Fatalf("error re-unmounting special dirs in %q: %v", td.Dir, err)
 	}
 }
+
+// TestLinuxVolumeMounts asserts volumes are mounted into the task dir and
+// unmounted before the alloc dir is removed.
+func TestLinuxVolumeMounts(t *testing.T) {
+	if unix.Geteuid() != 0 {
+		t.Skip("Must be run as root")
+	}
+
+	tmp, err := ioutil.TempDir("", "nomadtest-volumes")
+	if err != nil {
+		t.Fatalf("unable to create tempdir for test: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	// Create the host volumes
+	dataVol := filepath.Join(tmp, "data")
+	certsVol := filepath.Join(tmp, "certs")
+	for _, dir := range []string{dataVol, certsVol} {
+		if err := os.Mkdir(dir, 0777); err != nil {
+			t.Fatalf("error creating volume %q: %v", dir, err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(certsVol, "ca.pem"), []byte("ca"), 0666); err != nil {
+		t.Fatalf("error writing volume file: %v", err)
+	}
+
+	d := NewAllocDir(testlog.Logger(t), filepath.Join(tmp, "alloc"))
+	if err := d.Build(); err != nil {
+		t.Fatalf("error building alloc dir: %v", err)
+	}
+	td := d.NewTaskDir("test")
+	if err := td.Build(false, nil, cstructs.FSIsolationNone); err != nil {
+		t.Fatalf("error building task dir: %v", err)
+	}
+
+	mounts := []*VolumeMount{
+		{HostPath: dataVol, TaskPath: "/srv/data"},
+		{HostPath: certsVol, TaskPath: "/etc/ssl/certs", ReadOnly: true},
+	}
+	if err := td.MountVolumes(mounts); err != nil {
+		t.Fatalf("error mounting volumes: %v", err)
+	}
+
+	// Mounting again should be fine
+	if err := td.MountVolumes(mounts); err != nil {
+		t.Fatalf("error remounting volumes: %v", err)
+	}
+
+	// Writes to the task dir are written to the volume
+	if err := ioutil.WriteFile(filepath.Join(td.Dir, "srv/data/foo"), []byte("foo"), 0666); err != nil {
+		t.Fatalf("error writing to volume: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataVol, "foo")); err != nil {
+		t.Fatalf("expected file to be written to the volume: %v", err)
+	}
+
+	// Read only volumes can be read but not written
+	if _, err := ioutil.ReadFile(filepath.Join(td.Dir, "etc/ssl/certs/ca.pem")); err != nil {
+		t.Fatalf("error reading read only volume: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(td.Dir, "etc/ssl/certs/foo"), []byte("foo"), 0666); err == nil {
+		t.Fatalf("expected writing to a read only volume to fail")
+	}
+
+	// Volumes can't be mounted outside of the task dir
+	err = td.MountVolumes([]*VolumeMount{{HostPath: dataVol, TaskPath: "../../escape"}})
+	if err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Fatalf("expected an escape error but found: %v", err)
+	}
+
+	// Destroying the alloc dir unmounts the volumes without removing their
+	// contents
+	if err := d.Destroy(); err != nil {
+		t.Fatalf("error destroying alloc dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataVol, "foo")); err != nil {
+		t.Fatalf("expected volume contents to be kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(certsVol, "ca.pem")); err != nil {
+		t.Fatalf("expected volume contents to be kept: %v", err)
+	}
+	if pathExists(d.AllocDir) {
+		t.Fatalf("expected alloc dir %q to be removed", d.AllocDir)
+	}
+}
+
+// TestLinuxVolumeMounts_Busy asserts the alloc dir isn't removed while a
+// volume can't be unmounted.
+func TestLinuxVolumeMounts_Busy(t *testing.T) {
+	if unix.Geteuid() != 0 {
+		t.Skip("Must be run as root")
+	}
+
+	tmp, err := ioutil.TempDir("", "nomadtest-volumes")
+	if err != nil {
+		t.Fatalf("unable to create tempdir for test: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	dataVol := filepath.Join(tmp, "data")
+	if err := os.Mkdir(dataVol, 0777); err != nil {
+		t.Fatalf("error creating volume %q: %v", dataVol, err)
+	}
+
+	d := NewAllocDir(testlog.Logger(t), filepath.Join(tmp, "alloc"))
+	if err := d.Build(); err != nil {
+		t.Fatalf("error building alloc dir: %v", err)
+	}
+	td := d.NewTaskDir("test")
+	if err := td.Build(false, nil, cstructs.FSIsolationNone); err != nil {
+		t.Fatalf("error building task dir: %v", err)
+	}
+	if err := td.MountVolumes([]*VolumeMount{{HostPath: dataVol, TaskPath: "/srv/data"}}); err != nil {
+		t.Fatalf("error mounting volumes: %v", err)
+	}
+
+	// An open file keeps the volume busy
+	f, err := os.Create(filepath.Join(td.Dir, "srv/data/foo"))
+	if err != nil {
+		t.Fatalf("error writing to volume: %v", err)
+	}
+	defer f.Close()
+
+	err = d.Destroy()
+	if err == nil || !strings.Contains(err.Error(), "volumes still mounted") {
+		t.Fatalf("expected a mounted volume error but found: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataVol, "foo")); err != nil {
+		t.Fatalf("expected volume contents to be kept: %v", err)
+	}
+	if !pathExists(d.AllocDir) {
+		t.Fatalf("expected alloc dir %q to be kept", d.AllocDir)
+	}
+
+	// Once the volume isn't busy the alloc dir is removed
+	f.Close()
+	if err := d.Destroy(); err != nil {
+		t.Fatalf("error destroying alloc dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataVol, "foo")); err != nil {
+		t.Fatalf("expected volume contents to be kept: %v", err)
+	}
+	if pathExists(d.AllocDir) {
+		t.Fatalf("expected alloc dir %q to be removed", d.AllocDir)
+	}
+}
@@ -2,6 +2,11 @@
 
 package allocdir
 
+import (
+	"fmt"
+	"runtime"
+)
+
 // currently a noop on non-Linux platforms
 func (d *TaskDir) mountSpecialDirs() error {
 	return nil
@@ -11,3 +16,18 @@ func (d *TaskDir) mountSpecialDirs() error {
 func (d *TaskDir) unmountSpecialDirs() error {
 	return nil
 }
+
+// mountVolume returns an error as volumes can only be mounted on Linux
+func (d *TaskDir) mountVolume(src, dst string, readOnly bool) error {
+	return fmt.Errorf("volumes can't be mounted on %s", runtime.GOOS)
+}
+
+// currently a noop on non-Linux platforms
+func (d *TaskDir) unmountVolumes() error {
+	return nil
+}
+
+// currently a noop on non-Linux platforms
+func (d *TaskDir) mountedVolumes() ([]string, error) {
+	return nil, nil
+}
@@ -4,17 +4,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
 	"github.com/boltdb/bolt"
+	"github.com/hashicorp/consul-template/signals"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner"
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
+	"github.com/hashicorp/nomad/client/driver"
+	"github.com/hashicorp/nomad/client/network"
 	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/vaultclient"
 	"github.com/hashicorp/nomad/helper"
@@ -67,13 +72,50 @@ type AllocRunner struct {
 	restored   map[string]struct{}
 	taskLock   sync.RWMutex
 
+	// tasksStopped is set once the task runners are being destroyed and
+	// guards against starting tasks that were waiting on init tasks.
+	tasksStopped bool
+
 	taskStatusLock sync.RWMutex
 
+	// groupTasks are the tasks of the alloc's task group. They are used to
+	// decide how the other tasks are affected once a task is dead without
+	// acquiring the taskLock, which task runner callbacks must not do. It
+	// is set before the task runners are started and guarded by the
+	// taskStatusLock.
+	groupTasks []*structs.Task
+
+	// mainTasksRunningCh is closed once all the main tasks of the alloc have
+	// been started, at which point its poststart tasks are started.
+	mainTasksRunningCh     chan struct{}
+	mainTasksRunningClosed bool
+
+	// mainTasksDeadCh is closed once all the main tasks of the alloc are
+	// dead, at which point its poststop tasks are started.
+	mainTasksDeadCh     chan struct{}
+	mainTasksDeadClosed bool
+
 	updateCh chan *structs.Allocation
 
 	vaultClient  vaultclient.VaultClient
 	consulClient consulApi.ConsulServiceAPI
 
+	// rpc is used by the task runners to read their variables
+	rpc taskrunner.RPCer
+
+	// deviceReserver is used by the task runners to reserve their devices
+	deviceReserver taskrunner.DeviceReserver
+
+	// networkManager sets up the network namespace of the alloc when its
+	// task group network isn't in host mode
+	networkManager *network.Manager
+
+	// consulGRPCProxy forwards the connections of the Connect sidecar
+	// proxies to the gRPC endpoint of Consul. It is nil if the task group
+	// has no Connect service.
+	consulGRPCProxy   *consulGRPCProxy
+	groupServicesLock sync.Mutex
+
 	// prevAlloc allows for Waiting until a previous allocation exits and
 	// the migrates it data. If sticky volumes aren't used and there's no
 	// previous allocation a noop implementation is used so it always safe
@@ -134,26 +176,31 @@ type allocRunnerMutableState struct {
 // NewAllocRunner is used to create a new allocation context
 func NewAllocRunner(logger *log.Logger, config *config.Config, stateDB *bolt.DB, updater AllocStateUpdater,
 	alloc *structs.Allocation, vaultClient vaultclient.VaultClient, consulClient consulApi.ConsulServiceAPI,
-	prevAlloc prevAllocWatcher) *AllocRunner {
+	rpc taskrunner.RPCer, deviceReserver taskrunner.DeviceReserver, prevAlloc prevAllocWatcher) *AllocRunner {
 
 	ar := &AllocRunner{
-		config:         config,
-		stateDB:        stateDB,
-		updater:        updater,
-		logger:         logger,
-		alloc:          alloc,
-		allocID:        alloc.ID,
-		allocBroadcast: cstructs.NewAllocBroadcaster(8),
-		prevAlloc:      prevAlloc,
-		dirtyCh:        make(chan struct{}, 1),
-		allocDir:       allocdir.NewAllocDir(logger, filepath.Join(config.AllocDir, alloc.ID)),
-		tasks:          make(map[string]*taskrunner.TaskRunner),
-		taskStates:     copyTaskStates(alloc.TaskStates),
-		restored:       make(map[string]struct{}),
-		updateCh:       make(chan *structs.Allocation, 64),
-		waitCh:         make(chan struct{}),
-		vaultClient:    vaultClient,
-		consulClient:   consulClient,
+		config:             config,
+		stateDB:            stateDB,
+		updater:            updater,
+		logger:             logger,
+		alloc:              alloc,
+		allocID:            alloc.ID,
+		allocBroadcast:     cstructs.NewAllocBroadcaster(8),
+		prevAlloc:          prevAlloc,
+		dirtyCh:            make(chan struct{}, 1),
+		allocDir:           allocdir.NewAllocDir(logger, filepath.Join(config.AllocDir, alloc.ID)),
+		tasks:              make(map[string]*taskrunner.TaskRunner),
+		taskStates:         copyTaskStates(alloc.TaskStates),
+		restored:           make(map[string]struct{}),
+		updateCh:           make(chan *structs.Allocation, 64),
+		mainTasksRunningCh: make(chan struct{}),
+		mainTasksDeadCh:    make(chan struct{}),
+		waitCh:             make(chan struct{}),
+		vaultClient:        vaultClient,
+		consulClient:       consulClient,
+		rpc:                rpc,
+		deviceReserver:     deviceReserver,
+		networkManager:     network.NewManager(logger, config),
 	}
 
 	// TODO Should be passed a context
@@ -254,6 +301,7 @@ func (r *AllocRunner) RestoreState() error {
 	if tg == nil {
 		return fmt.Errorf("restored allocation doesn't contain task group %q", r.alloc.TaskGroup)
 	}
+	r.setGroupTasks(tg)
 
 	// Restore the task runners
 	taskDestroyEvent := structs.NewTaskEvent(structs.TaskKilled)
@@ -263,8 +311,9 @@ func (r *AllocRunner) RestoreState() error {
 		state := r.taskStates[name]
 
 		// Nomad exited before task could start, nothing to restore.
-		// AllocRunner.Run will start a new TaskRunner for this task
-		if state == nil {
+		// AllocRunner.Run will start a new TaskRunner for this task. Tasks
+		// waiting on init tasks are pending without any events.
+		if state == nil || (state.State == structs.TaskStatePending && len(state.Events) == 0) {
 			continue
 		}
 
@@ -285,7 +334,7 @@ func (r *AllocRunner) RestoreState() error {
 			continue
 		}
 
-		tr := taskrunner.NewTaskRunner(r.logger, r.config, r.stateDB, r.setTaskState, td, r.Alloc(), task, r.vaultClient, r.consulClient)
+		tr := taskrunner.NewTaskRunner(r.logger, r.config, r.stateDB, r.setTaskState, td, r.Alloc(), task, r.vaultClient, r.consulClient, r.rpc, r.deviceReserver)
 		r.tasks[name] = tr
 
 		if restartReason, err := tr.RestoreState(); err != nil {
@@ -692,20 +741,6 @@ func (r *AllocRunner) setTaskState(taskName, state string, event *structs.TaskEv
 			taskState.FinishedAt = time.Now().UTC()
 		}
 
-		// Find all tasks that are not the one that is dead and check if the one
-		// that is dead is a leader
-		var otherTaskRunners []*taskrunner.TaskRunner
-		var otherTaskNames []string
-		leader := false
-		for task, tr := range r.tasks {
-			if task != taskName {
-				otherTaskRunners = append(otherTaskRunners, tr)
-				otherTaskNames = append(otherTaskNames, task)
-			} else if tr.IsLeader() {
-				leader = true
-			}
-		}
-
 		// Emitting metrics to indicate task complete and failures
 		if taskState.Failed {
 			if !r.config.DisableTaggedMetrics {
@@ -724,35 +759,164 @@ func (r *AllocRunner) setTaskState(taskName, state string, event *structs.TaskEv
 				metrics.IncrCounter([]string{"client", "allocs", r.alloc.Job.Name, r.alloc.TaskGroup, taskName, "complete"}, 1)
 			}
 		}
-
-		// If the task failed, we should kill all the other tasks in the task group.
-		if taskState.Failed {
-			for _, tr := range otherTaskRunners {
-				tr.Destroy(structs.NewTaskEvent(structs.TaskSiblingFailed).SetFailedSibling(taskName))
-			}
-			if len(otherTaskRunners) > 0 {
-				r.logger.Printf("[DEBUG] client: task %q failed, destroying other tasks in task group: %v", taskName, otherTaskNames)
-			}
-		} else if leader {
-			// If the task was a leader task we should kill all the other tasks.
-			for _, tr := range otherTaskRunners {
-				tr.Destroy(structs.NewTaskEvent(structs.TaskLeaderDead))
-			}
-			if len(otherTaskRunners) > 0 {
-				r.logger.Printf("[DEBUG] client: leader task %q is dead, destroying other tasks in task group: %v", taskName, otherTaskNames)
-			}
-		}
 	}
 
 	// Store the new state
 	taskState.State = state
 
+	if state == structs.TaskStateRunning && r.mainTasksRunning() {
+		r.closeMainTasksRunning()
+	}
+	if state == structs.TaskStateDead {
+		r.handleTaskDead(taskName, taskState)
+	}
+
 	select {
 	case r.dirtyCh <- struct{}{}:
 	default:
 	}
 }
 
+// handleTaskDead decides how the other tasks of the alloc are affected by the
+// given task being dead. It must be called with the taskStatusLock held.
+func (r *AllocRunner) handleTaskDead(taskName string, taskState *structs.TaskState) {
+	var task *structs.Task
+	for _, t := range r.groupTasks {
+		if t.Name == taskName {
+			task = t
+			break
+		}
+	}
+	if task == nil {
+		return
+	}
+
+	// If the task failed, we should kill all the other tasks in the task
+	// group. If the task was a leader task we should kill all the other
+	// tasks.
+	var event *structs.TaskEvent
+	sidecarsOnly := false
+	if taskState.Failed {
+		event = structs.NewTaskEvent(structs.TaskSiblingFailed).SetFailedSibling(taskName)
+	} else if task.Leader {
+		event = structs.NewTaskEvent(structs.TaskLeaderDead)
+	}
+
+	// Sidecars and poststart tasks only run alongside the main tasks, so stop
+	// them once the last main task has finished, and start the poststop tasks.
+	if task.Lifecycle == nil && r.mainTasksDead() {
+		r.closeMainTasksDead()
+		if event == nil {
+			event = structs.NewTaskEvent(structs.TaskMainDead)
+			sidecarsOnly = true
+		}
+	}
+
+	// Task runners call setTaskState, so they must not be destroyed while
+	// holding the taskStatusLock or by acquiring the taskLock from here.
+	if event != nil {
+		go r.destroyOtherTasks(taskName, event, sidecarsOnly)
+	}
+}
+
+// destroyOtherTasks destroys the task runners of the alloc other than the
+// given task. Poststop tasks are left to run to completion. If sidecarsOnly is
+// set only the sidecar and poststart tasks are destroyed.
+func (r *AllocRunner) destroyOtherTasks(taskName string, event *structs.TaskEvent, sidecarsOnly bool) {
+	var destroyed []string
+	for _, tr := range r.getTaskRunners() {
+		if tr.Name() == taskName || tr.IsPoststop() || (sidecarsOnly && !tr.IsSidecar() && !tr.IsPoststart()) {
+			continue
+		}
+		tr.Destroy(event)
+		destroyed = append(destroyed, tr.Name())
+	}
+	if len(destroyed) == 0 {
+		return
+	}
+
+	switch event.Type {
+	case structs.TaskSiblingFailed:
+		r.logger.Printf("[DEBUG] client: task %q failed, destroying other tasks in task group: %v", taskName, destroyed)
+	case structs.TaskLeaderDead:
+		r.logger.Printf("[DEBUG] client: leader task %q is dead, destroying other tasks in task group: %v", taskName, destroyed)
+	default:
+		r.logger.Printf("[DEBUG] client: main tasks of alloc %q are dead, destroying sidecar and poststart tasks: %v", r.allocID, destroyed)
+	}
+}
+
+// mainTasksRunning returns whether all the main tasks of the alloc have been
+// started without failing and at least one of them is still running. It must
+// be called with the taskStatusLock held.
+func (r *AllocRunner) mainTasksRunning() bool {
+	running := false
+	for _, task := range r.groupTasks {
+		if task.Lifecycle != nil {
+			continue
+		}
+		state, ok := r.taskStates[task.Name]
+		if !ok || state.StartedAt.IsZero() || state.Failed {
+			return false
+		}
+		if state.State != structs.TaskStateDead {
+			running = true
+		}
+	}
+
+	return running
+}
+
+// closeMainTasksRunning notifies that all the main tasks of the alloc have
+// been started. It must be called with the taskStatusLock held.
+func (r *AllocRunner) closeMainTasksRunning() {
+	if !r.mainTasksRunningClosed {
+		r.mainTasksRunningClosed = true
+		close(r.mainTasksRunningCh)
+	}
+}
+
+// mainTasksDead returns whether all the main tasks of the alloc are dead. It
+// must be called with the taskStatusLock held.
+func (r *AllocRunner) mainTasksDead() bool {
+	for _, task := range r.groupTasks {
+		if task.Lifecycle != nil {
+			continue
+		}
+		if state, ok := r.taskStates[task.Name]; !ok || state.State != structs.TaskStateDead {
+			return false
+		}
+	}
+
+	return true
+}
+
+// closeMainTasksDead notifies that all the main tasks of the alloc are dead.
+// It must be called with the taskStatusLock held.
+func (r *AllocRunner) closeMainTasksDead() {
+	if !r.mainTasksDeadClosed {
+		r.mainTasksDeadClosed = true
+		close(r.mainTasksDeadCh)
+	}
+}
+
+// setGroupTasks stores the tasks of the alloc's task group and whether its
+// main tasks were already running or dead when restoring the alloc.
+func (r *AllocRunner) setGroupTasks(tg *structs.TaskGroup) {
+	r.taskStatusLock.Lock()
+	defer r.taskStatusLock.Unlock()
+
+	r.groupTasks = tg.Tasks
+	if len(r.taskStates) == 0 {
+		return
+	}
+	if r.mainTasksRunning() {
+		r.closeMainTasksRunning()
+	}
+	if r.mainTasksDead() {
+		r.closeMainTasksDead()
+	}
+}
+
 // appendTaskEvent updates the task status by appending the new event.
 func (r *AllocRunner) appendTaskEvent(state *structs.TaskState, event *structs.TaskEvent) {
 	capacity := 10
@@ -838,6 +1002,23 @@ func (r *AllocRunner) Run() {
 		return
 	}
 
+	// Set up the network of the task group before starting its tasks
+	if err := r.networkManager.Setup(alloc); err != nil {
+		r.logger.Printf("[ERR] client: alloc %q failed to set up network: %v", r.allocID, err)
+		r.setStatus(structs.AllocClientStatusFailed, fmt.Sprintf("failed to set up network: %v", err))
+		return
+	}
+
+	// Register the services of the task group, which the sidecar proxies of
+	// its tasks are bootstrapped from
+	if err := r.registerGroupServices(alloc, tg); err != nil {
+		r.logger.Printf("[ERR] client: alloc %q failed to register group services: %v", r.allocID, err)
+		r.setStatus(structs.AllocClientStatusFailed, fmt.Sprintf("failed to register group services: %v", err))
+		r.removeGroupServices(alloc)
+		r.stopConsulGRPCProxy()
+		return
+	}
+
 	// Increment alloc runner start counter. Incr'd even when restoring existing tasks so 1 start != 1 task execution
 	if !r.config.DisableTaggedMetrics {
 		metrics.IncrCounterWithLabels([]string{"client", "allocs", "start"},
@@ -851,30 +1032,63 @@ func (r *AllocRunner) Run() {
 	wCtx, watcherCancel := context.WithCancel(r.ctx)
 	go r.watchHealth(wCtx)
 
-	// Start the task runners
+	// Start the task runners. Init tasks have to complete before the main
+	// tasks are started, while prestart sidecars are started along with the
+	// init tasks. Poststart tasks are started once the main tasks are running
+	// and poststop tasks once the main tasks are dead.
 	r.logger.Printf("[DEBUG] client: starting task runners for alloc '%s'", r.allocID)
-	r.taskLock.Lock()
+	r.setGroupTasks(tg)
+	var initTasks, sidecarTasks, tasks, poststartTasks, poststopTasks []*structs.Task
 	for _, task := range tg.Tasks {
-		if _, ok := r.restored[task.Name]; ok {
-			continue
+		switch {
+		case task.IsInit():
+			initTasks = append(initTasks, task)
+		case task.IsPoststart():
+			poststartTasks = append(poststartTasks, task)
+		case task.IsPoststop():
+			poststopTasks = append(poststopTasks, task)
+		case task.IsSidecar():
+			sidecarTasks = append(sidecarTasks, task)
+		default:
+			tasks = append(tasks, task)
 		}
+	}
 
-		r.allocDirLock.Lock()
-		taskdir := r.allocDir.NewTaskDir(task.Name)
-		r.allocDirLock.Unlock()
-
-		tr := taskrunner.NewTaskRunner(r.logger, r.config, r.stateDB, r.setTaskState, taskdir, r.Alloc(), task.Copy(), r.vaultClient, r.consulClient)
-		r.tasks[task.Name] = tr
-		tr.MarkReceived()
+	// Mark the waiting tasks as pending so the allocation isn't considered
+	// complete before they are run.
+	waiting := append([]*structs.Task{}, poststartTasks...)
+	waiting = append(waiting, poststopTasks...)
+	if len(initTasks) != 0 {
+		waiting = append(waiting, tasks...)
+	}
+	for _, task := range waiting {
+		if _, ok := r.restored[task.Name]; !ok {
+			r.setTaskState(task.Name, structs.TaskStatePending, nil, false)
+		}
+	}
 
-		go tr.Run()
+	r.startTaskRunners(sidecarTasks)
+	if len(initTasks) == 0 {
+		r.startTaskRunners(tasks)
+	} else {
+		r.startTaskRunners(initTasks)
+		go r.startAfterInitTasks(initTasks, tasks)
+	}
+	if len(poststartTasks) != 0 {
+		go r.startPoststartTasks(poststartTasks)
+	}
+	if len(poststopTasks) != 0 {
+		go r.startPoststopTasks(poststopTasks)
 	}
-	r.taskLock.Unlock()
 
 	// taskDestroyEvent contains an event that caused the destruction of a task
 	// in the allocation.
 	var taskDestroyEvent *structs.TaskEvent
 
+	// runPoststop is set if the alloc is stopped rather than destroyed, in
+	// which case its poststop tasks are still run.
+	runPoststop := false
+
 OUTER:
 	// Wait for updates
 	for {
@@ -889,6 +1103,7 @@ OUTER:
 				r.allocHealthTime = time.Time{}
 			}
 
+			oldAlloc := r.alloc
 			r.alloc = update
 			r.allocLock.Unlock()
 
@@ -900,9 +1115,15 @@ OUTER:
 			// Check if we're in a terminal status
 			if update.TerminalStatus() {
 				taskDestroyEvent = structs.NewTaskEvent(structs.TaskKilled)
+				runPoststop = true
 				break OUTER
 			}
 
+			// Update the services of the task group
+			if err := r.updateGroupServices(oldAlloc, update); err != nil {
+				r.logger.Printf("[WARN] client: alloc %q failed to update group services: %v", r.allocID, err)
+			}
+
 			// Update the task groups
 			runners := r.getTaskRunners()
 			for _, tr := range runners {
@@ -920,8 +1141,11 @@ OUTER:
 		}
 	}
 
+	// Deregister the services of the task group before killing its tasks
+	r.removeGroupServices(r.Alloc())
+
 	// Kill the task runners
-	r.destroyTaskRunners(taskDestroyEvent)
+	r.destroyTaskRunners(taskDestroyEvent, runPoststop)
 
 	// Block until we should destroy the state of the alloc
 	r.handleDestroy()
@@ -932,11 +1156,170 @@ OUTER:
 	r.logger.Printf("[DEBUG] client: terminating runner for alloc '%s'", r.allocID)
 }
 
+// startTaskRunners creates and starts task runners for the given tasks unless
+// they were restored or the task runners are being destroyed.
+func (r *AllocRunner) startTaskRunners(tasks []*structs.Task) {
+	// The task states must not be updated while holding the taskLock, so
+	// get the alloc first and start the task runners after releasing it.
+	alloc := r.Alloc()
+
+	r.taskLock.Lock()
+	if r.tasksStopped {
+		r.taskLock.Unlock()
+		return
+	}
+	runners := r.newTaskRunners(alloc, tasks)
+	r.taskLock.Unlock()
+
+	for _, tr := range runners {
+		tr.MarkReceived()
+		go tr.Run()
+	}
+}
+
+// newTaskRunners creates task runners for the given tasks that weren't
+// restored or started already. It must be called with the taskLock held.
+func (r *AllocRunner) newTaskRunners(alloc *structs.Allocation, tasks []*structs.Task) []*taskrunner.TaskRunner {
+	var runners []*taskrunner.TaskRunner
+	for _, task := range tasks {
+		if _, ok := r.restored[task.Name]; ok {
+			continue
+		}
+		if _, ok := r.tasks[task.Name]; ok {
+			continue
+		}
+
+		r.allocDirLock.Lock()
+		taskdir := r.allocDir.NewTaskDir(task.Name)
+		r.allocDirLock.Unlock()
+
+		tr := taskrunner.NewTaskRunner(r.logger, r.config, r.stateDB, r.setTaskState, taskdir, alloc, task.Copy(), r.vaultClient, r.consulClient, r.rpc, r.deviceReserver)
+		r.tasks[task.Name] = tr
+		runners = append(runners, tr)
+	}
+	return runners
+}
+
+// startAfterInitTasks waits for the init tasks to complete and then starts the
+// remaining tasks. If an init task doesn't complete successfully the remaining
+// tasks are marked as dead instead.
+func (r *AllocRunner) startAfterInitTasks(initTasks, tasks []*structs.Task) {
+	for _, task := range initTasks {
+		r.taskLock.RLock()
+		tr, ok := r.tasks[task.Name]
+		r.taskLock.RUnlock()
+
+		// Init tasks that completed before a restore have no task runner
+		if ok {
+			select {
+			case <-tr.WaitCh():
+			case <-r.ctx.Done():
+				return
+			}
+		}
+
+		r.taskStatusLock.RLock()
+		state, ok := r.taskStates[task.Name]
+		failed := !ok || state.State != structs.TaskStateDead || state.Failed
+		r.taskStatusLock.RUnlock()
+
+		if failed {
+			r.logger.Printf("[DEBUG] client: init task %q of alloc %q did not complete, not starting remaining tasks",
+				task.Name, r.allocID)
+			event := structs.NewTaskEvent(structs.TaskSiblingFailed).SetFailedSibling(task.Name)
+			r.stopWaitingTasks(tasks, event)
+			return
+		}
+	}
+
+	r.startTaskRunners(tasks)
+}
+
+// startPoststartTasks starts the poststart tasks once the main tasks are
+// running. If the main tasks are dead before that, the poststart tasks are
+// marked as dead instead.
+func (r *AllocRunner) startPoststartTasks(tasks []*structs.Task) {
+	select {
+	case <-r.mainTasksRunningCh:
+	case <-r.mainTasksDeadCh:
+		r.stopWaitingTasks(tasks, structs.NewTaskEvent(structs.TaskMainDead))
+		return
+	case <-r.ctx.Done():
+		return
+	}
+
+	r.startTaskRunners(tasks)
+
+	// The main tasks may have died while the poststart tasks were started,
+	// in which case they were missed when the other tasks were destroyed.
+	select {
+	case <-r.mainTasksDeadCh:
+		for _, task := range tasks {
+			r.taskLock.RLock()
+			tr, ok := r.tasks[task.Name]
+			r.taskLock.RUnlock()
+			if ok {
+				tr.Destroy(structs.NewTaskEvent(structs.TaskMainDead))
+			}
+		}
+	default:
+	}
+}
+
+// startPoststopTasks starts the poststop tasks once the main tasks are dead.
+func (r *AllocRunner) startPoststopTasks(tasks []*structs.Task) {
+	select {
+	case <-r.mainTasksDeadCh:
+	case <-r.ctx.Done():
+		return
+	}
+
+	r.startTaskRunners(tasks)
+}
+
+// stopWaitingTasks marks the given tasks that don't have a task runner, as
+// they were waiting on init tasks, as dead.
+func (r *AllocRunner) stopWaitingTasks(tasks []*structs.Task, event *structs.TaskEvent) {
+	for _, task := range tasks {
+		r.taskLock.RLock()
+		_, started := r.tasks[task.Name]
+		r.taskLock.RUnlock()
+		if started {
+			continue
+		}
+
+		r.taskStatusLock.RLock()
+		state, ok := r.taskStates[task.Name]
+		waiting := ok && state.State == structs.TaskStatePending
+		r.taskStatusLock.RUnlock()
+		if waiting {
+			r.setTaskState(task.Name, structs.TaskStateDead, event, false)
+		}
+	}
+}
+
 // destroyTaskRunners destroys the task runners, waits for them to terminate and
-// then saves state.
-func (r *AllocRunner) destroyTaskRunners(destroyEvent *structs.TaskEvent) {
-	// First destroy the leader if one exists
+// then saves state. If runPoststop is set the poststop tasks are run to
+// completion once the other tasks are dead.
+func (r *AllocRunner) destroyTaskRunners(destroyEvent *structs.TaskEvent, runPoststop bool) {
+	// Ensure no more tasks are started
+	r.taskLock.Lock()
+	r.tasksStopped = true
+	r.taskLock.Unlock()
+
+	// Tasks still waiting to be started will never be started
 	tg := r.alloc.Job.LookupTaskGroup(r.alloc.TaskGroup)
+	var waiting, poststopTasks []*structs.Task
+	for _, task := range tg.Tasks {
+		if runPoststop && task.IsPoststop() {
+			poststopTasks = append(poststopTasks, task)
+		} else {
+			waiting = append(waiting, task)
+		}
+	}
+	r.stopWaitingTasks(waiting, destroyEvent)
+
+	// First destroy the leader if one exists
 	leader := ""
 	for _, task := range tg.Tasks {
 		if task.Leader {
@@ -962,18 +1345,51 @@ func (r *AllocRunner) destroyTaskRunners(destroyEvent *structs.TaskEvent) {
 		}
 	}
 
-	// Then destroy non-leader tasks concurrently
-	r.taskLock.RLock()
-	for name, tr := range r.tasks {
-		if name != leader {
+	// Then destroy non-leader tasks concurrently, leaving any running
+	// poststop tasks to complete
+	runners := r.getTaskRunners()
+	for _, tr := range runners {
+		if tr.Name() != leader && !(runPoststop && tr.IsPoststop()) {
 			tr.Destroy(destroyEvent)
 		}
 	}
-	r.taskLock.RUnlock()
 
 	// Wait for termination of the task runners
+	for _, tr := range runners {
+		if !(runPoststop && tr.IsPoststop()) {
+			<-tr.WaitCh()
+		}
+	}
+
+	if runPoststop {
+		r.runPoststopTasks(poststopTasks, destroyEvent)
+	}
+}
+
+// runPoststopTasks runs the poststop tasks of a stopped alloc to completion,
+// unless the alloc is destroyed meanwhile.
+func (r *AllocRunner) runPoststopTasks(tasks []*structs.Task, destroyEvent *structs.TaskEvent) {
+	alloc := r.Alloc()
+	r.taskLock.Lock()
+	runners := r.newTaskRunners(alloc, tasks)
+	r.taskLock.Unlock()
+
+	for _, tr := range runners {
+		tr.MarkReceived()
+		go tr.Run()
+	}
+
 	for _, tr := range r.getTaskRunners() {
-		<-tr.WaitCh()
+		if !tr.IsPoststop() {
+			continue
+		}
+
+		select {
+		case <-tr.WaitCh():
+		case <-r.ctx.Done():
+			tr.Destroy(destroyEvent)
+			<-tr.WaitCh()
+		}
 	}
 }
 
@@ -1006,6 +1422,12 @@ func (r *AllocRunner) handleDestroy() {
 		r.logger.Printf("[ERR] client: alloc %q unable unmount task directories: %v", r.allocID, err)
 	}
 
+	// Release the network of the alloc now that none of its tasks run
+	r.stopConsulGRPCProxy()
+	if err := r.networkManager.Teardown(alloc); err != nil {
+		r.logger.Printf("[ERR] client: alloc %q unable to tear down network: %v", r.allocID, err)
+	}
+
 	// Update the server with the alloc's status -- also marks the alloc as
 	// being eligible for GC, so from this point on the alloc can be gc'd
 	// at any time.
@@ -1070,6 +1492,98 @@ func (r *AllocRunner) getTaskRunners() []*taskrunner.TaskRunner {
 	return runners
 }
 
+// Signal sends the named signal to a task of the allocation, or to all of its
+// tasks if taskName is empty.
+func (r *AllocRunner) Signal(taskName, signal string) error {
+	sig, err := lookupSignal(signal)
+	if err != nil {
+		return err
+	}
+
+	reason := fmt.Sprintf("%s requested", signal)
+
+	// Signaling blocks until the task runners handle the signal so it must
+	// not be done while holding the taskLock
+	r.taskLock.RLock()
+	runners := make(map[string]*taskrunner.TaskRunner, len(r.tasks))
+	for name, tr := range r.tasks {
+		if taskName == "" || name == taskName {
+			runners[name] = tr
+		}
+	}
+	r.taskLock.RUnlock()
+
+	if taskName != "" {
+		tr, ok := runners[taskName]
+		if !ok {
+			return fmt.Errorf("unknown task %q", taskName)
+		}
+		return tr.Signal("client", reason, sig)
+	}
+
+	var mErr multierror.Error
+	for name, tr := range runners {
+		if err := tr.Signal("client", reason, sig); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("failed to signal task %q: %v", name, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Restart restarts a task of the allocation, or all of its tasks if taskName
+// is empty. Restarts requested this way do not count against the restart
+// policy of the task group.
+func (r *AllocRunner) Restart(taskName string) error {
+	const reason = "Restart requested"
+
+	// Restarting blocks until the task runners handle the restart so it
+	// must not be done while holding the taskLock
+	var runners []*taskrunner.TaskRunner
+	if taskName != "" {
+		r.taskLock.RLock()
+		tr, ok := r.tasks[taskName]
+		r.taskLock.RUnlock()
+		if !ok {
+			return fmt.Errorf("unknown task %q", taskName)
+		}
+		runners = append(runners, tr)
+	} else {
+		runners = r.getTaskRunners()
+	}
+
+	for _, tr := range runners {
+		tr.Restart("client", reason, false)
+	}
+	return nil
+}
+
+// lookupSignal returns the signal with the given name. The name may omit the
+// SIG prefix and is case insensitive.
+func lookupSignal(name string) (os.Signal, error) {
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+
+	sig, ok := signals.SignalLookup[name]
+	if !ok || sig == nil {
+		return nil, fmt.Errorf("signal %q is not supported", name)
+	}
+	return sig, nil
+}
+
+// ExecTask runs a command inside the running task of the allocation.
+func (r *AllocRunner) ExecTask(ctx context.Context, task string, opts *driver.ExecStreamingOptions) (int, error) {
+	r.taskLock.RLock()
+	tr, ok := r.tasks[task]
+	r.taskLock.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("unknown task %q", task)
+	}
+
+	return tr.Exec(ctx, opts)
+}
+
 // LatestAllocStats returns the latest allocation stats. If the optional taskFilter is set
 // the allocation stats will only include the given task.
 func (r *AllocRunner) LatestAllocStats(taskFilter string) (*cstructs.AllocResourceUsage, error) {
@@ -214,9 +214,17 @@ func newAllocHealthTracker(parentCtx context.Context, logger *log.Logger, alloc
 
 	for _, task := range a.tg.Tasks {
 		for _, s := range task.Services {
+			// Only the checks of the services registered in Consul are
+			// watched, the checks of Nomad services are run by the client
+			if s.Provider == structs.ServiceProviderNomad {
+				continue
+			}
 			a.consulCheckCount += len(s.Checks)
 		}
 	}
+	for _, s := range a.tg.Services {
+		a.consulCheckCount += len(s.Checks)
+	}
 
 	a.ctx, a.cancelFn = context.WithCancel(parentCtx)
 	return a
@@ -460,7 +468,10 @@ OUTER:
 		// Store the task registrations
 		a.l.Lock()
 		for task, reg := range allocReg.Tasks {
-			a.taskHealth[task].taskRegistrations = reg
+			// The services of the group are registered apart from the tasks
+			if th, ok := a.taskHealth[task]; ok {
+				th.taskRegistrations = reg
+			}
 		}
 		a.l.Unlock()
 
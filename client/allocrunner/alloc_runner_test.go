@@ -827,7 +827,7 @@ func TestAllocRunner_SaveRestoreState(t *testing.T) {
 	alloc2 := &structs.Allocation{ID: ar.alloc.ID}
 	prevAlloc := NewAllocWatcher(alloc2, ar, nil, ar.config, l2, "")
 	ar2 := NewAllocRunner(l2, ar.config, ar.stateDB, upd.Update,
-		alloc2, ar.vaultClient, ar.consulClient, prevAlloc)
+		alloc2, ar.vaultClient, ar.consulClient, ar.rpc, ar.deviceReserver, prevAlloc)
 	err = ar2.RestoreState()
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -922,7 +922,7 @@ func TestAllocRunner_SaveRestoreState_TerminalAlloc(t *testing.T) {
 	alloc2 := &structs.Allocation{ID: ar.alloc.ID}
 	prevAlloc := NewAllocWatcher(alloc2, ar, nil, ar.config, l2, "")
 	ar2 := NewAllocRunner(l2, ar.config, ar.stateDB, upd.Update,
-		alloc2, ar.vaultClient, ar.consulClient, prevAlloc)
+		alloc2, ar.vaultClient, ar.consulClient, ar.rpc, ar.deviceReserver, prevAlloc)
 	err = ar2.RestoreState()
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -1133,6 +1133,403 @@ func TestAllocRunner_TaskLeader_KillTG(t *testing.T) {
 	})
 }
 
+// TestAllocRunner_Lifecycle_Init asserts that main tasks are only started once
+// the init tasks have completed.
+func TestAllocRunner_Lifecycle_Init(t *testing.T) {
+	t.Parallel()
+	upd, ar := TestAllocRunner(t, false)
+
+	main := ar.alloc.Job.TaskGroups[0].Tasks[0]
+	main.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	init := main.Copy()
+	init.Name = "init"
+	init.Lifecycle = &structs.TaskLifecycleConfig{Hook: structs.TaskLifecycleHookPrestart}
+	init.Config = map[string]interface{}{
+		"run_for": "500ms",
+	}
+	ar.alloc.Job.TaskGroups[0].Tasks = append(ar.alloc.Job.TaskGroups[0].Tasks, init)
+	ar.alloc.TaskResources[init.Name] = init.Resources
+	go ar.Run()
+	defer ar.Destroy()
+
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		if last == nil {
+			return false, fmt.Errorf("No updates")
+		}
+		if last.ClientStatus != structs.AllocClientStatusRunning {
+			return false, fmt.Errorf("got status %v; want %v", last.ClientStatus, structs.AllocClientStatusRunning)
+		}
+
+		initState := last.TaskStates[init.Name]
+		if initState.State != structs.TaskStateDead || initState.Failed {
+			return false, fmt.Errorf("init task state %v (failed %v); want successful %v",
+				initState.State, initState.Failed, structs.TaskStateDead)
+		}
+
+		mainState := last.TaskStates[main.Name]
+		if mainState.State != structs.TaskStateRunning {
+			return false, fmt.Errorf("got state %v; want %v", mainState.State, structs.TaskStateRunning)
+		}
+		if mainState.StartedAt.Before(initState.FinishedAt) {
+			return false, fmt.Errorf("main task started at %v before init task finished at %v",
+				mainState.StartedAt, initState.FinishedAt)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+// TestAllocRunner_Lifecycle_InitFailed asserts that main tasks aren't started
+// if an init task fails.
+func TestAllocRunner_Lifecycle_InitFailed(t *testing.T) {
+	t.Parallel()
+	upd, ar := TestAllocRunner(t, false)
+
+	main := ar.alloc.Job.TaskGroups[0].Tasks[0]
+	main.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	init := main.Copy()
+	init.Name = "init"
+	init.Lifecycle = &structs.TaskLifecycleConfig{Hook: structs.TaskLifecycleHookPrestart}
+	init.Config = map[string]interface{}{
+		"run_for":   "10ms",
+		"exit_code": "1",
+	}
+	ar.alloc.Job.TaskGroups[0].Tasks = append(ar.alloc.Job.TaskGroups[0].Tasks, init)
+	ar.alloc.TaskResources[init.Name] = init.Resources
+	go ar.Run()
+	defer ar.Destroy()
+
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		if last == nil {
+			return false, fmt.Errorf("No updates")
+		}
+		if last.ClientStatus != structs.AllocClientStatusFailed {
+			return false, fmt.Errorf("got status %v; want %v", last.ClientStatus, structs.AllocClientStatusFailed)
+		}
+
+		mainState := last.TaskStates[main.Name]
+		if mainState.State != structs.TaskStateDead {
+			return false, fmt.Errorf("got state %v; want %v", mainState.State, structs.TaskStateDead)
+		}
+		if !mainState.StartedAt.IsZero() {
+			return false, fmt.Errorf("main task should not have been started")
+		}
+		if n := len(mainState.Events); n == 0 || mainState.Events[n-1].Type != structs.TaskSiblingFailed {
+			return false, fmt.Errorf("expected last event to be %q: %v", structs.TaskSiblingFailed, mainState.Events)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+// TestAllocRunner_Lifecycle_Sidecar asserts that sidecar tasks are stopped
+// once the main tasks are dead.
+func TestAllocRunner_Lifecycle_Sidecar(t *testing.T) {
+	t.Parallel()
+	upd, ar := TestAllocRunner(t, false)
+
+	main := ar.alloc.Job.TaskGroups[0].Tasks[0]
+	main.Config = map[string]interface{}{
+		"run_for": "500ms",
+	}
+
+	sidecar := main.Copy()
+	sidecar.Name = "sidecar"
+	sidecar.KillTimeout = 10 * time.Millisecond
+	sidecar.Lifecycle = &structs.TaskLifecycleConfig{
+		Hook:    structs.TaskLifecycleHookPrestart,
+		Sidecar: true,
+	}
+	sidecar.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+	ar.alloc.Job.TaskGroups[0].Tasks = append(ar.alloc.Job.TaskGroups[0].Tasks, sidecar)
+	ar.alloc.TaskResources[sidecar.Name] = sidecar.Resources
+	go ar.Run()
+	defer ar.Destroy()
+
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		if last == nil {
+			return false, fmt.Errorf("No updates")
+		}
+		if last.ClientStatus != structs.AllocClientStatusComplete {
+			return false, fmt.Errorf("got status %v; want %v", last.ClientStatus, structs.AllocClientStatusComplete)
+		}
+
+		sidecarState := last.TaskStates[sidecar.Name]
+		if sidecarState.State != structs.TaskStateDead {
+			return false, fmt.Errorf("got state %v; want %v", sidecarState.State, structs.TaskStateDead)
+		}
+
+		found := false
+		for _, e := range sidecarState.Events {
+			if e.Type == structs.TaskMainDead {
+				found = true
+			}
+		}
+		if !found {
+			return false, fmt.Errorf("Did not find event %v", structs.TaskMainDead)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+// TestAllocRunner_Lifecycle_InitSidecar asserts that prestart sidecars are
+// started along with the init tasks.
+func TestAllocRunner_Lifecycle_InitSidecar(t *testing.T) {
+	t.Parallel()
+	upd, ar := TestAllocRunner(t, false)
+
+	main := ar.alloc.Job.TaskGroups[0].Tasks[0]
+	main.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	init := main.Copy()
+	init.Name = "init"
+	init.Lifecycle = &structs.TaskLifecycleConfig{Hook: structs.TaskLifecycleHookPrestart}
+	init.Config = map[string]interface{}{
+		"run_for": "1s",
+	}
+
+	sidecar := main.Copy()
+	sidecar.Name = "sidecar"
+	sidecar.Lifecycle = &structs.TaskLifecycleConfig{
+		Hook:    structs.TaskLifecycleHookPrestart,
+		Sidecar: true,
+	}
+	ar.alloc.Job.TaskGroups[0].Tasks = append(ar.alloc.Job.TaskGroups[0].Tasks, init, sidecar)
+	ar.alloc.TaskResources[init.Name] = init.Resources
+	ar.alloc.TaskResources[sidecar.Name] = sidecar.Resources
+	go ar.Run()
+	defer ar.Destroy()
+
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		if last == nil {
+			return false, fmt.Errorf("No updates")
+		}
+
+		initState := last.TaskStates[init.Name]
+		if initState.State != structs.TaskStateDead || initState.Failed {
+			return false, fmt.Errorf("init task state %v (failed %v); want successful %v",
+				initState.State, initState.Failed, structs.TaskStateDead)
+		}
+
+		sidecarState := last.TaskStates[sidecar.Name]
+		if sidecarState.State != structs.TaskStateRunning {
+			return false, fmt.Errorf("got state %v; want %v", sidecarState.State, structs.TaskStateRunning)
+		}
+		if !sidecarState.StartedAt.Before(initState.FinishedAt) {
+			return false, fmt.Errorf("sidecar task started at %v after init task finished at %v",
+				sidecarState.StartedAt, initState.FinishedAt)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+// TestAllocRunner_Lifecycle_Poststart asserts that poststart tasks are only
+// started once the main tasks are running and that poststart sidecars are
+// stopped once the main tasks are dead.
+func TestAllocRunner_Lifecycle_Poststart(t *testing.T) {
+	t.Parallel()
+	upd, ar := TestAllocRunner(t, false)
+
+	main := ar.alloc.Job.TaskGroups[0].Tasks[0]
+	main.Config = map[string]interface{}{
+		"start_block_for": "200ms",
+		"run_for":         "1s",
+	}
+
+	poststart := main.Copy()
+	poststart.Name = "poststart"
+	poststart.Lifecycle = &structs.TaskLifecycleConfig{Hook: structs.TaskLifecycleHookPoststart}
+	poststart.Config = map[string]interface{}{
+		"run_for": "10ms",
+	}
+
+	sidecar := main.Copy()
+	sidecar.Name = "sidecar"
+	sidecar.KillTimeout = 10 * time.Millisecond
+	sidecar.Lifecycle = &structs.TaskLifecycleConfig{
+		Hook:    structs.TaskLifecycleHookPoststart,
+		Sidecar: true,
+	}
+	sidecar.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	tg := ar.alloc.Job.TaskGroups[0]
+	tg.Tasks = append(tg.Tasks, poststart, sidecar)
+	ar.alloc.TaskResources[poststart.Name] = poststart.Resources
+	ar.alloc.TaskResources[sidecar.Name] = sidecar.Resources
+	go ar.Run()
+	defer ar.Destroy()
+
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		if last == nil {
+			return false, fmt.Errorf("No updates")
+		}
+		if last.ClientStatus != structs.AllocClientStatusComplete {
+			return false, fmt.Errorf("got status %v; want %v", last.ClientStatus, structs.AllocClientStatusComplete)
+		}
+
+		mainState := last.TaskStates[main.Name]
+		poststartState := last.TaskStates[poststart.Name]
+		if poststartState.State != structs.TaskStateDead || poststartState.Failed {
+			return false, fmt.Errorf("poststart task state %v (failed %v); want successful %v",
+				poststartState.State, poststartState.Failed, structs.TaskStateDead)
+		}
+		if poststartState.StartedAt.Before(mainState.StartedAt) {
+			return false, fmt.Errorf("poststart task started at %v before main task started at %v",
+				poststartState.StartedAt, mainState.StartedAt)
+		}
+
+		sidecarState := last.TaskStates[sidecar.Name]
+		if sidecarState.State != structs.TaskStateDead {
+			return false, fmt.Errorf("got state %v; want %v", sidecarState.State, structs.TaskStateDead)
+		}
+		found := false
+		for _, e := range sidecarState.Events {
+			if e.Type == structs.TaskMainDead {
+				found = true
+			}
+		}
+		if !found {
+			return false, fmt.Errorf("Did not find event %v", structs.TaskMainDead)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+// TestAllocRunner_Lifecycle_Poststop asserts that poststop tasks are only
+// started once the main tasks are dead.
+func TestAllocRunner_Lifecycle_Poststop(t *testing.T) {
+	t.Parallel()
+	upd, ar := TestAllocRunner(t, false)
+
+	main := ar.alloc.Job.TaskGroups[0].Tasks[0]
+	main.Config = map[string]interface{}{
+		"run_for": "500ms",
+	}
+
+	poststop := main.Copy()
+	poststop.Name = "poststop"
+	poststop.Lifecycle = &structs.TaskLifecycleConfig{Hook: structs.TaskLifecycleHookPoststop}
+	poststop.Config = map[string]interface{}{
+		"run_for": "10ms",
+	}
+	ar.alloc.Job.TaskGroups[0].Tasks = append(ar.alloc.Job.TaskGroups[0].Tasks, poststop)
+	ar.alloc.TaskResources[poststop.Name] = poststop.Resources
+	go ar.Run()
+	defer ar.Destroy()
+
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		if last == nil {
+			return false, fmt.Errorf("No updates")
+		}
+		if last.ClientStatus != structs.AllocClientStatusComplete {
+			return false, fmt.Errorf("got status %v; want %v", last.ClientStatus, structs.AllocClientStatusComplete)
+		}
+
+		mainState := last.TaskStates[main.Name]
+		poststopState := last.TaskStates[poststop.Name]
+		if poststopState.State != structs.TaskStateDead || poststopState.Failed {
+			return false, fmt.Errorf("poststop task state %v (failed %v); want successful %v",
+				poststopState.State, poststopState.Failed, structs.TaskStateDead)
+		}
+		if poststopState.StartedAt.Before(mainState.FinishedAt) {
+			return false, fmt.Errorf("poststop task started at %v before main task finished at %v",
+				poststopState.StartedAt, mainState.FinishedAt)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
+// TestAllocRunner_Lifecycle_PoststopOnStop asserts that poststop tasks are run
+// when the alloc is stopped.
+func TestAllocRunner_Lifecycle_PoststopOnStop(t *testing.T) {
+	t.Parallel()
+	upd, ar := TestAllocRunner(t, false)
+
+	main := ar.alloc.Job.TaskGroups[0].Tasks[0]
+	main.KillTimeout = 10 * time.Millisecond
+	main.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	poststop := main.Copy()
+	poststop.Name = "poststop"
+	poststop.Lifecycle = &structs.TaskLifecycleConfig{Hook: structs.TaskLifecycleHookPoststop}
+	poststop.Config = map[string]interface{}{
+		"run_for": "10ms",
+	}
+	ar.alloc.Job.TaskGroups[0].Tasks = append(ar.alloc.Job.TaskGroups[0].Tasks, poststop)
+	ar.alloc.TaskResources[poststop.Name] = poststop.Resources
+	go ar.Run()
+	defer ar.Destroy()
+
+	// Wait for the main task to be running
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		if last == nil {
+			return false, fmt.Errorf("No updates")
+		}
+		if s := last.TaskStates[main.Name].State; s != structs.TaskStateRunning {
+			return false, fmt.Errorf("got state %v; want %v", s, structs.TaskStateRunning)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Stop alloc
+	update := ar.Alloc()
+	update.DesiredStatus = structs.AllocDesiredStatusStop
+	ar.Update(update)
+
+	testutil.WaitForResult(func() (bool, error) {
+		last := upd.Last()
+		mainState := last.TaskStates[main.Name]
+		if mainState.State != structs.TaskStateDead {
+			return false, fmt.Errorf("got state %v; want %v", mainState.State, structs.TaskStateDead)
+		}
+
+		poststopState := last.TaskStates[poststop.Name]
+		if poststopState.State != structs.TaskStateDead || poststopState.Failed {
+			return false, fmt.Errorf("poststop task state %v (failed %v); want successful %v",
+				poststopState.State, poststopState.Failed, structs.TaskStateDead)
+		}
+		if poststopState.StartedAt.IsZero() {
+			return false, fmt.Errorf("poststop task should have been started")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
 // TestAllocRunner_TaskLeader_StopTG asserts that when stopping a task group
 // with a leader the leader is stopped before other tasks.
 func TestAllocRunner_TaskLeader_StopTG(t *testing.T) {
@@ -1259,10 +1656,10 @@ func TestAllocRunner_TaskLeader_StopRestoredTG(t *testing.T) {
 	ar.tasks = map[string]*taskrunner.TaskRunner{
 		"leader": taskrunner.NewTaskRunner(ar.logger, ar.config, ar.stateDB, ar.setTaskState,
 			ar.allocDir.NewTaskDir(task2.Name), ar.Alloc(), task2.Copy(),
-			ar.vaultClient, ar.consulClient),
+			ar.vaultClient, ar.consulClient, ar.rpc, ar.deviceReserver),
 		"follower1": taskrunner.NewTaskRunner(ar.logger, ar.config, ar.stateDB, ar.setTaskState,
 			ar.allocDir.NewTaskDir(task.Name), ar.Alloc(), task.Copy(),
-			ar.vaultClient, ar.consulClient),
+			ar.vaultClient, ar.consulClient, ar.rpc, ar.deviceReserver),
 	}
 	ar.taskStates = map[string]*structs.TaskState{
 		"leader":    {State: structs.TaskStateDead},
@@ -1275,7 +1672,7 @@ func TestAllocRunner_TaskLeader_StopRestoredTG(t *testing.T) {
 	// Create a new AllocRunner to test RestoreState and Run
 	upd2 := &MockAllocStateUpdater{}
 	ar2 := NewAllocRunner(ar.logger, ar.config, ar.stateDB, upd2.Update, ar.alloc,
-		ar.vaultClient, ar.consulClient, ar.prevAlloc)
+		ar.vaultClient, ar.consulClient, ar.rpc, ar.deviceReserver, ar.prevAlloc)
 	defer ar2.Destroy()
 
 	if err := ar2.RestoreState(); err != nil {
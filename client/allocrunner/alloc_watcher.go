@@ -474,6 +474,13 @@ func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser
 		}
 	}
 
+	// Entries are resolved against the real path of the destination so
+	// symlinks created by the snapshot can't be used to escape it
+	root, err := filepath.EvalSymlinks(dest)
+	if err != nil {
+		return fmt.Errorf("error resolving alloc dir %q: %v", dest, err)
+	}
+
 	// if we see this file, there was an error on the remote side
 	errorFilename := allocdir.SnapshotErrorFilename(p.prevAllocID)
 
@@ -504,9 +511,39 @@ func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser
 				p.prevAllocID, p.allocID, string(errBuf))
 		}
 
+		// Ensure the entry is written within the destination alloc dir
+		if escapes, err := structs.PathEscapesAllocDir("", hdr.Name); err != nil || escapes {
+			return fmt.Errorf("error streaming previous alloc %q for new alloc %q: path %q escapes the alloc dir",
+				p.prevAllocID, p.allocID, hdr.Name)
+		}
+
+		// Resolve the parent directories of the entry, which may be
+		// symlinks created by earlier entries. Entries that can't be written
+		// within the alloc dir are skipped so the rest is still migrated.
+		parent, err := allocdir.ResolveWithin(root, filepath.Dir(filepath.Join(root, hdr.Name)))
+		if err != nil {
+			p.logger.Printf("[WARN] client: alloc %q skipping %q of previous alloc %q: %v",
+				p.allocID, hdr.Name, p.prevAllocID, err)
+			continue
+		}
+		name := filepath.Join(parent, filepath.Base(hdr.Name))
+
+		// The parent directory is missing if it was skipped
+		if fi, err := os.Stat(parent); err != nil || !fi.IsDir() {
+			p.logger.Printf("[WARN] client: alloc %q skipping %q of previous alloc %q: parent directory is missing",
+				p.allocID, hdr.Name, p.prevAllocID)
+			continue
+		}
+
+		// Never write through an existing symlink
+		if fi, err := os.Lstat(name); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+			p.logger.Printf("[WARN] client: alloc %q skipping %q of previous alloc %q: path is a symlink",
+				p.allocID, hdr.Name, p.prevAllocID)
+			continue
+		}
+
 		// If the header is for a directory we create the directory
 		if hdr.Typeflag == tar.TypeDir {
-			name := filepath.Join(dest, hdr.Name)
 			os.MkdirAll(name, os.FileMode(hdr.Mode))
 
 			// Can't change owner if not root or on Windows.
@@ -519,14 +556,25 @@ func (p *remotePrevAlloc) streamAllocDir(ctx context.Context, resp io.ReadCloser
 		}
 		// If the header is for a symlink we create the symlink
 		if hdr.Typeflag == tar.TypeSymlink {
-			if err = os.Symlink(hdr.Linkname, filepath.Join(dest, hdr.Name)); err != nil {
+			// The target isn't cleaned as a ".." may follow a symlink
+			target := hdr.Linkname
+			if !filepath.IsAbs(target) {
+				target = parent + string(filepath.Separator) + target
+			}
+			if _, err := allocdir.ResolveWithin(root, target); err != nil {
+				p.logger.Printf("[WARN] client: alloc %q skipping symlink %q of previous alloc %q: %v",
+					p.allocID, hdr.Name, p.prevAllocID, err)
+				continue
+			}
+
+			if err = os.Symlink(hdr.Linkname, name); err != nil {
 				return fmt.Errorf("error creating symlink: %v", err)
 			}
 			continue
 		}
 		// If the header is a file, we write to a file
 		if hdr.Typeflag == tar.TypeReg {
-			f, err := os.Create(filepath.Join(dest, hdr.Name))
+			f, err := os.Create(name)
 			if err != nil {
 				return fmt.Errorf("error creating file: %v", err)
 			}
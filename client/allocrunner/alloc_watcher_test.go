@@ -288,3 +288,162 @@ func TestPrevAlloc_StreamAllocDir_Error(t *testing.T) {
 		t.Fatalf("expected foo.txt to be size 1 but found %d", fi.Size())
 	}
 }
+
+// TestPrevAlloc_StreamAllocDir_Escape asserts that snapshot entries can't be
+// written outside of the destination alloc dir.
+func TestPrevAlloc_StreamAllocDir_Escape(t *testing.T) {
+	t.Parallel()
+	tmp, err := ioutil.TempDir("", "nomadtest-")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer os.RemoveAll(tmp)
+	dest := filepath.Join(tmp, "dest")
+	if err := os.Mkdir(dest, 0777); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	prevAlloc := &remotePrevAlloc{
+		logger:      testlog.Logger(t),
+		allocID:     "123",
+		prevAllocID: "abc",
+		migrate:     true,
+	}
+
+	tarBuf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(tarBuf)
+	err = tw.WriteHeader(&tar.Header{
+		Name:     "../escaped.txt",
+		Mode:     0666,
+		Size:     1,
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		t.Fatalf("error writing file header: %v", err)
+	}
+	if _, err := tw.Write([]byte{'a'}); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	tw.Close()
+
+	err = prevAlloc.streamAllocDir(context.Background(), ioutil.NopCloser(tarBuf), dest)
+	if err == nil || !strings.Contains(err.Error(), "escapes the alloc dir") {
+		t.Fatalf("expected an escape error from streamAllocDir but found: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmp, "escaped.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected escaped.txt to not be written: %v", err)
+	}
+}
+
+// TestPrevAlloc_StreamAllocDir_SymlinkEscape asserts that a malicious snapshot
+// can't use symlinks to write or link outside of the destination alloc dir,
+// and that the offending entries are skipped without failing the migration.
+func TestPrevAlloc_StreamAllocDir_SymlinkEscape(t *testing.T) {
+	t.Parallel()
+
+	type entry struct {
+		name     string
+		linkname string
+	}
+	cases := []struct {
+		name    string
+		entries []entry
+	}{
+		{
+			name:    "absolute symlink",
+			entries: []entry{{name: "escape", linkname: "/etc"}},
+		},
+		{
+			name:    "relative symlink",
+			entries: []entry{{name: "escape", linkname: "../outside"}},
+		},
+		{
+			name: "write through symlinked parent",
+			entries: []entry{
+				// The link target looks to be within the alloc dir but
+				// resolves to its parent since dir is a symlink
+				{name: "dir", linkname: "."},
+				{name: "escape", linkname: "dir/.."},
+				{name: "escape/escaped.txt"},
+			},
+		},
+		{
+			name: "write through symlink",
+			entries: []entry{
+				{name: "link", linkname: "target.txt"},
+				{name: "link"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tmp, err := ioutil.TempDir("", "nomadtest-")
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			defer os.RemoveAll(tmp)
+			dest := filepath.Join(tmp, "dest")
+			if err := os.Mkdir(dest, 0777); err != nil {
+				t.Fatalf("err: %v", err)
+			}
+
+			prevAlloc := &remotePrevAlloc{
+				logger:      testlog.Logger(t),
+				allocID:     "123",
+				prevAllocID: "abc",
+				migrate:     true,
+			}
+
+			tarBuf := bytes.NewBuffer(nil)
+			tw := tar.NewWriter(tarBuf)
+			// The offending entries are skipped and the rest is migrated
+			for _, e := range append(c.entries, entry{name: "data.txt"}) {
+				hdr := &tar.Header{
+					Name:     e.name,
+					Mode:     0666,
+					ModTime:  time.Now(),
+					Typeflag: tar.TypeReg,
+				}
+				if e.linkname != "" {
+					hdr.Typeflag = tar.TypeSymlink
+					hdr.Linkname = e.linkname
+				} else {
+					hdr.Size = 1
+				}
+				if err := tw.WriteHeader(hdr); err != nil {
+					t.Fatalf("error writing header: %v", err)
+				}
+				if hdr.Size != 0 {
+					if _, err := tw.Write([]byte{'a'}); err != nil {
+						t.Fatalf("error writing file: %v", err)
+					}
+				}
+			}
+			tw.Close()
+
+			err = prevAlloc.streamAllocDir(context.Background(), ioutil.NopCloser(tarBuf), dest)
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dest, "data.txt")); err != nil {
+				t.Fatalf("expected data.txt to be migrated: %v", err)
+			}
+			if _, err := os.Lstat(filepath.Join(dest, "escape")); !os.IsNotExist(err) {
+				t.Fatalf("expected the escaping symlink to be skipped: %v", err)
+			}
+
+			// Nothing was written outside of the alloc dir
+			for _, name := range []string{"escaped.txt", "outside", "target.txt"} {
+				if _, err := os.Lstat(filepath.Join(tmp, name)); !os.IsNotExist(err) {
+					t.Fatalf("expected %q to not be written: %v", name, err)
+				}
+			}
+			if _, err := os.Lstat(filepath.Join(dest, "target.txt")); !os.IsNotExist(err) {
+				t.Fatalf("expected target.txt to not be written: %v", err)
+			}
+		})
+	}
+}
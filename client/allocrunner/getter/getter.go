@@ -95,8 +95,19 @@ func GetArtifact(taskEnv EnvReplacer, artifact *structs.TaskArtifact, taskDir st
 		return newGetError(artifact.GetterSource, err, false)
 	}
 
+	// Interpolate the destination and verify it still doesn't escape the
+	// allocation directory since it could only be checked uninterpolated at
+	// job submission.
+	relDest := taskEnv.ReplaceEnv(artifact.RelativeDest)
+	escapes, err := structs.PathEscapesAllocDir("task", relDest)
+	if err != nil {
+		return newGetError(artifact.GetterSource, fmt.Errorf("invalid destination path %q: %v", relDest, err), false)
+	} else if escapes {
+		return newGetError(artifact.GetterSource, fmt.Errorf("destination %q escapes allocation directory", relDest), false)
+	}
+
 	// Download the artifact
-	dest := filepath.Join(taskDir, artifact.RelativeDest)
+	dest := filepath.Join(taskDir, relDest)
 
 	// Convert from string getter mode to go-getter const
 	mode := gg.ClientModeAny
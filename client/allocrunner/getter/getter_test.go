@@ -91,6 +91,52 @@ func TestGetArtifact_File_RelativeDest(t *testing.T) {
 	}
 }
 
+func TestGetArtifact_File_InterpolatedDest(t *testing.T) {
+	// Create the test server hosting the file to download
+	ts := httptest.NewServer(http.FileServer(http.Dir(filepath.Dir("./test-fixtures/"))))
+	defer ts.Close()
+
+	// Create a temp directory to download into
+	taskDir, err := ioutil.TempDir("", "nomad-test")
+	if err != nil {
+		t.Fatalf("failed to make temp directory: %v", err)
+	}
+	defer os.RemoveAll(taskDir)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Meta = map[string]string{"dest": "foo/"}
+	taskEnv := env.NewBuilder(mock.Node(), alloc, task, "global").Build()
+
+	// Create the artifact
+	file := "test.sh"
+	artifact := &structs.TaskArtifact{
+		GetterSource: fmt.Sprintf("%s/%s", ts.URL, file),
+		RelativeDest: "${NOMAD_META_DEST}",
+	}
+
+	// Download the artifact
+	if err := GetArtifact(taskEnv, artifact, taskDir); err != nil {
+		t.Fatalf("GetArtifact failed: %v", err)
+	}
+
+	// Verify artifact was downloaded to the interpolated path
+	if _, err := os.Stat(filepath.Join(taskDir, "foo", file)); err != nil {
+		t.Fatalf("file not found: %s", err)
+	}
+
+	// A destination that only escapes once interpolated is rejected
+	task.Meta = map[string]string{"dest": "../../../"}
+	taskEnv = env.NewBuilder(mock.Node(), alloc, task, "global").Build()
+	err = GetArtifact(taskEnv, artifact, taskDir)
+	if err == nil || !strings.Contains(err.Error(), "escapes") {
+		t.Fatalf("expected escaping destination to fail: %v", err)
+	}
+	if err.(*GetError).IsRecoverable() {
+		t.Fatalf("expected escaping destination to not be recoverable")
+	}
+}
+
 func TestGetGetterUrl_Interpolation(t *testing.T) {
 	// Create the artifact
 	artifact := &structs.TaskArtifact{
@@ -0,0 +1,208 @@
+package allocrunner
+
+import (
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// registerGroupServices registers the services of the task group in Consul,
+// and starts the proxy to the gRPC endpoint of Consul if some of them have
+// a sidecar proxy.
+func (r *AllocRunner) registerGroupServices(alloc *structs.Allocation, tg *structs.TaskGroup) error {
+	if len(tg.Services) == 0 {
+		return nil
+	}
+
+	for _, service := range tg.Services {
+		if !service.Connect.HasSidecar() {
+			continue
+		}
+
+		path := filepath.Join(r.allocDir.SharedDir, allocdir.ConsulGRPCSocket)
+		proxy, err := newConsulGRPCProxy(r.logger, path, r.config.ConsulConfig.GRPCAddr)
+		if err != nil {
+			return err
+		}
+		r.groupServicesLock.Lock()
+		r.consulGRPCProxy = proxy
+		r.groupServicesLock.Unlock()
+		break
+	}
+
+	return r.consulClient.RegisterTask(consul.NewGroupServices(alloc, tg))
+}
+
+// updateGroupServices updates the services of the task group in Consul.
+func (r *AllocRunner) updateGroupServices(oldAlloc, newAlloc *structs.Allocation) error {
+	oldTG := oldAlloc.Job.LookupTaskGroup(oldAlloc.TaskGroup)
+	newTG := newAlloc.Job.LookupTaskGroup(newAlloc.TaskGroup)
+	if oldTG == nil || newTG == nil {
+		return nil
+	}
+	if len(oldTG.Services) == 0 && len(newTG.Services) == 0 {
+		return nil
+	}
+	return r.consulClient.UpdateTask(consul.NewGroupServices(oldAlloc, oldTG), consul.NewGroupServices(newAlloc, newTG))
+}
+
+// removeGroupServices deregisters the services of the task group.
+func (r *AllocRunner) removeGroupServices(alloc *structs.Allocation) {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil || len(tg.Services) == 0 {
+		return
+	}
+
+	services := consul.NewGroupServices(alloc, tg)
+	r.consulClient.RemoveTask(services)
+
+	// Remove the services with the canary flag flipped as well, in case it
+	// changed without the alloc runner being updated
+	services.Canary = !services.Canary
+	r.consulClient.RemoveTask(services)
+}
+
+// stopConsulGRPCProxy stops the proxy to the gRPC endpoint of Consul once no
+// sidecar proxy runs anymore.
+func (r *AllocRunner) stopConsulGRPCProxy() {
+	r.groupServicesLock.Lock()
+	proxy := r.consulGRPCProxy
+	r.consulGRPCProxy = nil
+	r.groupServicesLock.Unlock()
+	if proxy != nil {
+		proxy.Stop()
+	}
+}
+
+// consulGRPCProxy forwards the connections on a unix socket in the alloc dir
+// to the gRPC endpoint of the local Consul agent, which the tasks can't
+// reach from the network namespace of the alloc.
+type consulGRPCProxy struct {
+	logger   *log.Logger
+	addr     string
+	listener net.Listener
+
+	conns    map[net.Conn]struct{}
+	connsL   sync.Mutex
+	stopped  bool
+	stopOnce sync.Once
+}
+
+// newConsulGRPCProxy listens on the unix socket at path and starts forwarding
+// its connections to the Consul gRPC address.
+func newConsulGRPCProxy(logger *log.Logger, path, addr string) (*consulGRPCProxy, error) {
+	// Remove the socket of a previous run of the client
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The tasks may run as any user
+	if err := os.Chmod(path, 0777); err != nil {
+		l.Close()
+		return nil, err
+	}
+
+	p := &consulGRPCProxy{
+		logger:   logger,
+		addr:     addr,
+		listener: l,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+func (p *consulGRPCProxy) run() {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			p.connsL.Lock()
+			stopped := p.stopped
+			p.connsL.Unlock()
+			if !stopped {
+				p.logger.Printf("[ERR] client: consul gRPC proxy failed to accept connection: %v", err)
+			}
+			return
+		}
+		go p.forward(conn)
+	}
+}
+
+// forward copies the data between the connection and a new connection to
+// Consul until either is closed.
+func (p *consulGRPCProxy) forward(conn net.Conn) {
+	upstream, err := net.Dial("tcp", p.addr)
+	if err != nil {
+		p.logger.Printf("[WARN] client: consul gRPC proxy failed to connect to %q: %v", p.addr, err)
+		conn.Close()
+		return
+	}
+
+	if !p.track(conn, upstream) {
+		return
+	}
+	defer p.untrack(conn, upstream)
+
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(upstream, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// track records the connections so they are closed when the proxy stops. It
+// closes them and returns false if it is already stopped.
+func (p *consulGRPCProxy) track(conns ...net.Conn) bool {
+	p.connsL.Lock()
+	defer p.connsL.Unlock()
+	if p.stopped {
+		for _, c := range conns {
+			c.Close()
+		}
+		return false
+	}
+	for _, c := range conns {
+		p.conns[c] = struct{}{}
+	}
+	return true
+}
+
+// untrack closes the connections and forgets them.
+func (p *consulGRPCProxy) untrack(conns ...net.Conn) {
+	p.connsL.Lock()
+	defer p.connsL.Unlock()
+	for _, c := range conns {
+		c.Close()
+		delete(p.conns, c)
+	}
+}
+
+// Stop stops listening and closes the forwarded connections.
+func (p *consulGRPCProxy) Stop() {
+	p.stopOnce.Do(func() {
+		p.connsL.Lock()
+		p.stopped = true
+		for c := range p.conns {
+			c.Close()
+		}
+		p.connsL.Unlock()
+		p.listener.Close()
+	})
+}
@@ -2,6 +2,7 @@ package taskrunner
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -38,6 +39,14 @@ const (
 	// DefaultMaxTemplateEventRate is the default maximum rate at which a
 	// template event should be fired.
 	DefaultMaxTemplateEventRate = 3 * time.Second
+
+	// variableEnvPrefix is the prefix of the environment variables exposing
+	// the task's variables to its templates
+	variableEnvPrefix = "NOMAD_VAR_"
+
+	// serviceEnvPrefix is the prefix of the environment variables exposing
+	// the addresses of the services registered in Nomad to the templates
+	serviceEnvPrefix = "NOMAD_SERVICE_"
 )
 
 // TaskHooks is an interface which provides hooks into the tasks life-cycle
@@ -99,6 +108,22 @@ type TaskTemplateManagerConfig struct {
 	// VaultToken is the Vault token for the task.
 	VaultToken string
 
+	// Variables are the items of the task's variables. They are available to
+	// the templates as environment variables prefixed by NOMAD_VAR_.
+	Variables map[string]string
+
+	// Services are the comma separated addresses of the passing instances of
+	// the services registered in Nomad, keyed by service name. They are
+	// available to the templates as environment variables prefixed by
+	// NOMAD_SERVICE_.
+	Services map[string]string
+
+	// TaskStarted is set when the template manager replaces the one of a
+	// running task, such as when the addresses of the services change. The
+	// change mode of the templates whose content changes on the first render
+	// is then applied.
+	TaskStarted bool
+
 	// TaskDir is the task's directory
 	TaskDir string
 
@@ -200,6 +225,7 @@ func (tm *TaskTemplateManager) run() {
 	}
 
 	// Start the runner
+	start := time.Now()
 	go tm.runner.Start()
 
 	// Block till all the templates have been rendered
@@ -223,6 +249,12 @@ func (tm *TaskTemplateManager) run() {
 	// Unblock the task
 	tm.config.Hooks.UnblockStart(consulTemplateSourceName)
 
+	// The task is already running, so the templates whose content changed
+	// were re-rendered
+	if tm.config.TaskStarted && !tm.handleChangedTemplates(start) {
+		return
+	}
+
 	// If all our templates are change mode no-op, then we can exit here
 	if tm.allTemplatesNoop() {
 		return
@@ -415,48 +447,93 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 			}
 
 			if restart || len(signals) != 0 {
-				if splay != 0 {
-					ns := splay.Nanoseconds()
-					offset := rand.Int63n(ns)
-					t := time.Duration(offset)
-
-					select {
-					case <-time.After(t):
-					case <-tm.shutdownCh:
-						return
-					}
-				}
-
 				// Update handle time
 				for _, id := range handling {
 					handledRenders[id] = events[id].LastDidRender
 				}
 
-				if restart {
-					const failure = false
-					tm.config.Hooks.Restart(consulTemplateSourceName, "template with change_mode restart re-rendered", failure)
-				} else if len(signals) != 0 {
-					var mErr multierror.Error
-					for signal := range signals {
-						err := tm.config.Hooks.Signal(consulTemplateSourceName, "template re-rendered", tm.signals[signal])
-						if err != nil {
-							multierror.Append(&mErr, err)
-						}
-					}
-
-					if err := mErr.ErrorOrNil(); err != nil {
-						flat := make([]os.Signal, 0, len(signals))
-						for signal := range signals {
-							flat = append(flat, tm.signals[signal])
-						}
-						tm.config.Hooks.Kill(consulTemplateSourceName, fmt.Sprintf("Sending signals %v failed: %v", flat, err), true)
-					}
+				if !tm.applyChangeModes(restart, signals, splay) {
+					return
 				}
 			}
 		}
 	}
 }
 
+// handleChangedTemplates applies the change mode of the templates written to
+// disk since the given time, as their content changed. It returns false if
+// the template manager was shutdown.
+func (tm *TaskTemplateManager) handleChangedTemplates(since time.Time) bool {
+	signals := make(map[string]struct{})
+	restart := false
+	var splay time.Duration
+
+	for id, event := range tm.runner.RenderEvents() {
+		if !event.DidRender || event.LastDidRender.Before(since) {
+			continue
+		}
+
+		for _, tmpl := range tm.lookup[id] {
+			switch tmpl.ChangeMode {
+			case structs.TemplateChangeModeSignal:
+				signals[tmpl.ChangeSignal] = struct{}{}
+			case structs.TemplateChangeModeRestart:
+				restart = true
+			case structs.TemplateChangeModeNoop:
+				continue
+			}
+
+			if tmpl.Splay > splay {
+				splay = tmpl.Splay
+			}
+		}
+	}
+
+	if !restart && len(signals) == 0 {
+		return true
+	}
+	return tm.applyChangeModes(restart, signals, splay)
+}
+
+// applyChangeModes restarts the task or sends it the given signals after a
+// random delay of up to the splay. It returns false if the template manager
+// was shutdown while waiting.
+func (tm *TaskTemplateManager) applyChangeModes(restart bool, signals map[string]struct{}, splay time.Duration) bool {
+	if splay != 0 {
+		ns := splay.Nanoseconds()
+		offset := rand.Int63n(ns)
+		t := time.Duration(offset)
+
+		select {
+		case <-time.After(t):
+		case <-tm.shutdownCh:
+			return false
+		}
+	}
+
+	if restart {
+		const failure = false
+		tm.config.Hooks.Restart(consulTemplateSourceName, "template with change_mode restart re-rendered", failure)
+	} else if len(signals) != 0 {
+		var mErr multierror.Error
+		for signal := range signals {
+			err := tm.config.Hooks.Signal(consulTemplateSourceName, "template re-rendered", tm.signals[signal])
+			if err != nil {
+				multierror.Append(&mErr, err)
+			}
+		}
+
+		if err := mErr.ErrorOrNil(); err != nil {
+			flat := make([]os.Signal, 0, len(signals))
+			for signal := range signals {
+				flat = append(flat, tm.signals[signal])
+			}
+			tm.config.Hooks.Kill(consulTemplateSourceName, fmt.Sprintf("Sending signals %v failed: %v", flat, err), true)
+		}
+	}
+	return true
+}
+
 // allTemplatesNoop returns whether all the managed templates have change mode noop.
 func (tm *TaskTemplateManager) allTemplatesNoop() bool {
 	for _, tmpl := range tm.config.Templates {
@@ -468,6 +545,31 @@ func (tm *TaskTemplateManager) allTemplatesNoop() bool {
 	return true
 }
 
+// templatesReferTo returns whether the templates refer to the environment
+// variables with the given prefix. The templates read from a file which
+// can't be read are skipped, as rendering them fails anyway.
+func templatesReferTo(templates []*structs.Template, taskDir string, taskEnv *env.TaskEnv, prefix string) bool {
+	for _, tmpl := range templates {
+		data := tmpl.EmbeddedTmpl
+		if tmpl.SourcePath != "" {
+			src := taskEnv.ReplaceEnv(tmpl.SourcePath)
+			if !filepath.IsAbs(src) {
+				src = filepath.Join(taskDir, src)
+			}
+			contents, err := ioutil.ReadFile(src)
+			if err != nil {
+				continue
+			}
+			data = string(contents)
+		}
+
+		if strings.Contains(data, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // templateRunner returns a consul-template runner for the given templates and a
 // lookup by destination to the template. If no templates are in the config, a
 // nil template runner and lookup is returned.
@@ -495,8 +597,14 @@ func templateRunner(config *TaskTemplateManagerConfig) (
 		return nil, nil, err
 	}
 
-	// Set Nomad's environment variables
+	// Set Nomad's environment variables and the task's variables
 	runner.Env = config.EnvBuilder.Build().All()
+	for k, v := range config.Variables {
+		runner.Env[variableEnvPrefix+k] = v
+	}
+	for k, v := range config.Services {
+		runner.Env[serviceEnvPrefix+k] = v
+	}
 
 	// Build the lookup
 	idMap := runner.TemplateConfigMapping()
@@ -529,11 +637,23 @@ func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[ctconf.Templat
 
 				src = tmpl.SourcePath
 			} else {
-				src = filepath.Join(config.TaskDir, taskEnv.ReplaceEnv(tmpl.SourcePath))
+				// An interpolated relative path may still point at the host
+				// filesystem by escaping the allocation directory.
+				relSrc := taskEnv.ReplaceEnv(tmpl.SourcePath)
+				if !allowAbs {
+					if escapes, err := structs.PathEscapesAllocDir("task", relSrc); err != nil || escapes {
+						return nil, fmt.Errorf("template source path escapes alloc directory: %q", relSrc)
+					}
+				}
+				src = filepath.Join(config.TaskDir, relSrc)
 			}
 		}
 		if tmpl.DestPath != "" {
-			dest = filepath.Join(config.TaskDir, taskEnv.ReplaceEnv(tmpl.DestPath))
+			relDest := taskEnv.ReplaceEnv(tmpl.DestPath)
+			if escapes, err := structs.PathEscapesAllocDir("task", relDest); err != nil || escapes {
+				return nil, fmt.Errorf("template destination path escapes alloc directory: %q", relDest)
+			}
+			dest = filepath.Join(config.TaskDir, relDest)
 		}
 
 		ct := ctconf.DefaultTemplateConfig()
@@ -103,17 +103,20 @@ func (m *MockTaskHooks) EmitEvent(source, message string) {
 // testHarness is used to test the TaskTemplateManager by spinning up
 // Consul/Vault as needed
 type testHarness struct {
-	manager    *TaskTemplateManager
-	mockHooks  *MockTaskHooks
-	templates  []*structs.Template
-	envBuilder *env.Builder
-	node       *structs.Node
-	config     *config.Config
-	vaultToken string
-	taskDir    string
-	vault      *testutil.TestVault
-	consul     *ctestutil.TestServer
-	emitRate   time.Duration
+	manager     *TaskTemplateManager
+	mockHooks   *MockTaskHooks
+	templates   []*structs.Template
+	envBuilder  *env.Builder
+	node        *structs.Node
+	config      *config.Config
+	vaultToken  string
+	variables   map[string]string
+	services    map[string]string
+	taskStarted bool
+	taskDir     string
+	vault       *testutil.TestVault
+	consul      *ctestutil.TestServer
+	emitRate    time.Duration
 }
 
 // newTestHarness returns a harness starting a dev consul and vault server,
@@ -173,6 +176,9 @@ func (h *testHarness) startWithErr() error {
 		Templates:            h.templates,
 		ClientConfig:         h.config,
 		VaultToken:           h.vaultToken,
+		Variables:            h.variables,
+		Services:             h.services,
+		TaskStarted:          h.taskStarted,
 		TaskDir:              h.taskDir,
 		EnvBuilder:           h.envBuilder,
 		MaxTemplateEventRate: h.emitRate,
@@ -475,6 +481,191 @@ func TestTaskTemplateManager_Unblock_Static_NomadEnv(t *testing.T) {
 	}
 }
 
+func TestTaskTemplateManager_Unblock_Static_Variables(t *testing.T) {
+	t.Parallel()
+	// Make a template that will render immediately
+	content := `password={{env "NOMAD_VAR_password"}}`
+	expected := "password=hunter2"
+	file := "my.tmpl"
+	template := &structs.Template{
+		EmbeddedTmpl: content,
+		DestPath:     file,
+		ChangeMode:   structs.TemplateChangeModeNoop,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	harness.variables = map[string]string{"password": "hunter2"}
+	harness.start(t)
+	defer harness.stop()
+
+	// Wait for the unblock
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	// Check the file is there
+	path := filepath.Join(harness.taskDir, file)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read rendered template from %q: %v", path, err)
+	}
+
+	if s := string(raw); s != expected {
+		t.Fatalf("Unexpected template data; got %q, want %q", s, expected)
+	}
+
+	// The variables must not leak into the task's environment
+	if _, ok := harness.envBuilder.Build().Map()["NOMAD_VAR_password"]; ok {
+		t.Fatalf("variables shouldn't be in the task environment")
+	}
+}
+
+func TestTaskTemplateManager_Unblock_Static_Services(t *testing.T) {
+	t.Parallel()
+	// Make a template that will render immediately
+	content := `upstream={{env "NOMAD_SERVICE_countdash-api"}}`
+	expected := "upstream=10.0.0.1:9001,10.0.0.2:9001"
+	file := "my.tmpl"
+	template := &structs.Template{
+		EmbeddedTmpl: content,
+		DestPath:     file,
+		ChangeMode:   structs.TemplateChangeModeNoop,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	harness.services = map[string]string{"countdash-api": "10.0.0.1:9001,10.0.0.2:9001"}
+	harness.start(t)
+	defer harness.stop()
+
+	// Wait for the unblock
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	// Check the file is there
+	path := filepath.Join(harness.taskDir, file)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read rendered template from %q: %v", path, err)
+	}
+
+	if s := string(raw); s != expected {
+		t.Fatalf("Unexpected template data; got %q, want %q", s, expected)
+	}
+}
+
+func TestTaskTemplateManager_TaskStarted_Services(t *testing.T) {
+	t.Parallel()
+	// Make a template that will render immediately and restart the task
+	content := `upstream={{env "NOMAD_SERVICE_countdash-api"}}`
+	file := "my.tmpl"
+	template := &structs.Template{
+		EmbeddedTmpl: content,
+		DestPath:     file,
+		ChangeMode:   structs.TemplateChangeModeRestart,
+	}
+
+	harness := newTestHarness(t, []*structs.Template{template}, false, false)
+	harness.services = map[string]string{"countdash-api": "10.0.0.1:9001"}
+	harness.start(t)
+	defer harness.stop()
+
+	// Wait for the unblock
+	select {
+	case <-harness.mockHooks.UnblockCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task unblock should have been called")
+	}
+
+	// Replacing the manager of the running task with the same services
+	// doesn't restart it
+	harness.manager.Stop()
+	harness.taskStarted = true
+	harness.start(t)
+	select {
+	case <-harness.mockHooks.RestartCh:
+		t.Fatalf("Task shouldn't have been restarted")
+	case <-time.After(time.Duration(1*testutil.TestMultiplier()) * time.Second):
+	}
+
+	// The task is restarted once the template changes
+	harness.manager.Stop()
+	harness.services = map[string]string{"countdash-api": "10.0.0.2:9001"}
+	harness.start(t)
+	select {
+	case <-harness.mockHooks.RestartCh:
+	case <-time.After(time.Duration(5*testutil.TestMultiplier()) * time.Second):
+		t.Fatalf("Task should have been restarted")
+	}
+
+	path := filepath.Join(harness.taskDir, file)
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read rendered template from %q: %v", path, err)
+	}
+
+	if s, expected := string(raw), "upstream=10.0.0.2:9001"; s != expected {
+		t.Fatalf("Unexpected template data; got %q, want %q", s, expected)
+	}
+}
+
+func TestTemplatesReferTo(t *testing.T) {
+	t.Parallel()
+	taskDir, err := ioutil.TempDir("", "ct_test")
+	if err != nil {
+		t.Fatalf("Failed to make tmpdir: %v", err)
+	}
+	defer os.RemoveAll(taskDir)
+
+	src := filepath.Join(taskDir, "upstreams.tmpl")
+	if err := ioutil.WriteFile(src, []byte(`{{env "NOMAD_SERVICE_api"}}`), 0666); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	a := mock.Alloc()
+	taskEnv := env.NewBuilder(mock.Node(), a, a.Job.TaskGroups[0].Tasks[0], "global").Build()
+
+	cases := []struct {
+		name      string
+		templates []*structs.Template
+		services  bool
+	}{
+		{
+			name:      "embedded",
+			templates: []*structs.Template{{EmbeddedTmpl: `{{env "NOMAD_SERVICE_api"}}`}},
+			services:  true,
+		},
+		{
+			name:      "source",
+			templates: []*structs.Template{{SourcePath: "upstreams.tmpl"}},
+			services:  true,
+		},
+		{
+			name:      "missing source",
+			templates: []*structs.Template{{SourcePath: "missing.tmpl"}},
+		},
+		{
+			name:      "unrelated",
+			templates: []*structs.Template{{EmbeddedTmpl: `{{key "foo"}}`}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := templatesReferTo(c.templates, taskDir, taskEnv, serviceEnvPrefix); got != c.services {
+				t.Fatalf("got %v; want %v", got, c.services)
+			}
+			if templatesReferTo(c.templates, taskDir, taskEnv, variableEnvPrefix) {
+				t.Fatalf("templates shouldn't refer to variables")
+			}
+		})
+	}
+}
+
 func TestTaskTemplateManager_Unblock_Static_AlreadyRendered(t *testing.T) {
 	t.Parallel()
 	// Make a template that will render immediately
@@ -1264,6 +1455,54 @@ func TestTaskTemplateManager_Config_VaultGrace(t *testing.T) {
 	assert.Equal(10*time.Second, *ctconf.Vault.Grace, "Vault Grace Value")
 }
 
+// TestTaskTemplateManager_Config_EscapingPaths asserts that interpolated
+// template paths can't escape the allocation directory.
+func TestTaskTemplateManager_Config_EscapingPaths(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	c := config.DefaultConfig()
+	c.Node = mock.Node()
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Meta = map[string]string{"path": "../../../etc/passwd"}
+	config := &TaskTemplateManagerConfig{
+		ClientConfig: c,
+		TaskDir:      "/alloc/web",
+		EnvBuilder:   env.NewBuilder(c.Node, alloc, task, c.Region),
+	}
+
+	// Escaping destination
+	config.Templates = []*structs.Template{
+		{
+			EmbeddedTmpl: "bar",
+			DestPath:     "${NOMAD_META_PATH}",
+			ChangeMode:   structs.TemplateChangeModeNoop,
+		},
+	}
+	_, err := parseTemplateConfigs(config)
+	require.Error(err)
+	require.Contains(err.Error(), "destination path escapes")
+
+	// Escaping source is only allowed when host sources are
+	config.Templates = []*structs.Template{
+		{
+			SourcePath: "${NOMAD_META_PATH}",
+			DestPath:   "local/foo",
+			ChangeMode: structs.TemplateChangeModeNoop,
+		},
+	}
+	_, err = parseTemplateConfigs(config)
+	require.NoError(err)
+
+	c.Options = map[string]string{
+		hostSrcOption: "false",
+	}
+	_, err = parseTemplateConfigs(config)
+	require.Error(err)
+	require.Contains(err.Error(), "source path escapes")
+}
+
 func TestTaskTemplateManager_BlockedEvents(t *testing.T) {
 	t.Parallel()
 	// Make a template that will render based on a key in Consul
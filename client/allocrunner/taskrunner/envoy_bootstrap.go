@@ -0,0 +1,186 @@
+package taskrunner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/driver/env"
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
+	"github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// envoyBootstrapFile is the name of the file in the secrets dir of the
+	// sidecar proxy tasks their Envoy bootstrap configuration is written to.
+	envoyBootstrapFile = "envoy_bootstrap.json"
+
+	// envoyBaseAdminPort is the port the admin API of the Envoy proxy of the
+	// first Connect service of a task group listens on, in the network
+	// namespace of the alloc. The next ones use the next ports.
+	envoyBaseAdminPort = 19000
+
+	// envoyBootstrapTimeout is how long the bootstrap configuration is
+	// retried for, as the service is registered asynchronously.
+	envoyBootstrapTimeout = 60 * time.Second
+
+	// envoyBootstrapRetryInterval is the interval between the attempts to
+	// generate the bootstrap configuration.
+	envoyBootstrapRetryInterval = 2 * time.Second
+)
+
+// bootstrapEnvoy writes the bootstrap configuration of the Envoy sidecar
+// proxy of a Connect service, generated by the consul CLI from the service
+// registered by the alloc runner, to the secrets dir of the task. The
+// configuration is generated without an ACL token, so the token of the agent
+// never reaches the task.
+func (r *TaskRunner) bootstrapEnvoy(alloc *structs.Allocation, task *structs.Task) error {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return fmt.Errorf("task group %q not found", alloc.TaskGroup)
+	}
+
+	// The admin port of each proxy depends on the index of its service
+	var service *structs.Service
+	adminPort := envoyBaseAdminPort
+	for _, s := range tg.Services {
+		if !s.Connect.HasSidecar() {
+			continue
+		}
+		if s.Name == task.Kind.Value() {
+			service = s
+			break
+		}
+		adminPort++
+	}
+	if service == nil {
+		return fmt.Errorf("Connect service %q not found in task group %q", task.Kind.Value(), tg.Name)
+	}
+
+	// The gRPC address is used by Envoy, so it is the path of the socket as
+	// seen by the task
+	taskEnv := r.envBuilder.Build()
+	grpcAddr := "unix://" + filepath.Join(taskEnv.EnvMap[env.AllocDir], allocdir.ConsulGRPCSocket)
+
+	args := []string{
+		"connect", "envoy",
+		"-grpc-addr", grpcAddr,
+		"-admin-bind", "localhost:" + strconv.Itoa(adminPort),
+		"-bootstrap",
+		"-sidecar-for", consul.GroupServiceID(alloc.ID, tg.Name, service),
+	}
+	if addr := r.config.ConsulConfig.Addr; addr != "" {
+		args = append(args, "-http-addr", addr)
+	}
+
+	deadline := time.Now().Add(envoyBootstrapTimeout)
+	for {
+		bootstrap, err := runConsulBootstrap(r.config.ConsulConfig, args)
+		if err == nil {
+			path := filepath.Join(r.taskDir.SecretsDir, envoyBootstrapFile)
+			return writeTaskFile(path, bootstrap, task)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("failed to generate Envoy bootstrap configuration: %v", err)
+		}
+		r.logger.Printf("[DEBUG] client: alloc %q, task %q failed to generate Envoy bootstrap configuration, retrying: %v", alloc.ID, task.Name, err)
+
+		select {
+		case <-time.After(envoyBootstrapRetryInterval):
+		case <-r.waitCh:
+			return fmt.Errorf("task runner exited")
+		}
+	}
+}
+
+// writeTaskFile writes the file only readable by the user the task runs as.
+func writeTaskFile(path string, data []byte, task *structs.Task) error {
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	// The file may have been written with other permissions before
+	if err := os.Chmod(path, 0600); err != nil {
+		return err
+	}
+
+	// Can't change owner if not root.
+	if os.Geteuid() != 0 {
+		return nil
+	}
+
+	username := task.User
+	if username == "" {
+		username = dstructs.DefaultUnprivilegedUser
+	}
+	u, err := user.Lookup(username)
+	if err != nil {
+		return err
+	}
+	uid, err := strconv.Atoi(u.Uid)
+	if err != nil {
+		return fmt.Errorf("Unable to convert Uid to an int: %v", err)
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return fmt.Errorf("Unable to convert Gid to an int: %v", err)
+	}
+	return os.Chown(path, uid, gid)
+}
+
+// runConsulBootstrap runs the consul CLI with the arguments and returns its
+// output. The TLS settings are passed in its environment.
+func runConsulBootstrap(conf *config.ConsulConfig, args []string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The environment is always set, so that the CLI doesn't inherit an ACL
+	// token from the environment of the agent
+	cmd := exec.CommandContext(ctx, "consul", args...)
+	cmd.Env = append([]string{}, consulEnv(conf)...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// consulEnv returns the environment variables configuring the consul CLI
+// like the Consul client of the agent. The ACL token of the agent is left out,
+// as the CLI embeds it in the bootstrap configuration readable by the task.
+func consulEnv(conf *config.ConsulConfig) []string {
+	var envs []string
+	if conf.Auth != "" {
+		envs = append(envs, "CONSUL_HTTP_AUTH="+conf.Auth)
+	}
+	if conf.EnableSSL != nil && *conf.EnableSSL {
+		envs = append(envs, "CONSUL_HTTP_SSL=true")
+		if conf.VerifySSL != nil {
+			envs = append(envs, "CONSUL_HTTP_SSL_VERIFY="+strconv.FormatBool(*conf.VerifySSL))
+		}
+	}
+	if conf.CAFile != "" {
+		envs = append(envs, "CONSUL_CACERT="+conf.CAFile)
+	}
+	if conf.CertFile != "" {
+		envs = append(envs, "CONSUL_CLIENT_CERT="+conf.CertFile)
+	}
+	if conf.KeyFile != "" {
+		envs = append(envs, "CONSUL_CLIENT_KEY="+conf.KeyFile)
+	}
+	return envs
+}
@@ -0,0 +1,93 @@
+package taskrunner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeConsul puts a consul CLI in the $PATH that prints its ACL token and
+// arguments instead of a bootstrap configuration
+func fakeConsul(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "consul")
+	require.NoError(t, err)
+
+	script := "#!/bin/sh\necho \"{\\\"token\\\": \\\"$CONSUL_HTTP_TOKEN\\\", \\\"args\\\": \\\"$*\\\"}\"\n"
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "consul"), []byte(script), 0755))
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	return func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestTaskRunner_BootstrapEnvoy_NoAgentToken(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a shell")
+	}
+	defer fakeConsul(t)()
+
+	// The token of the agent can be set in its config or environment
+	const configToken = "config-token-4d5f7a"
+	const envToken = "env-token-9c3e1b"
+	os.Setenv("CONSUL_HTTP_TOKEN", envToken)
+	defer os.Unsetenv("CONSUL_HTTP_TOKEN")
+
+	alloc := mock.Alloc()
+	tg := alloc.Job.TaskGroups[0]
+	tg.Services = []*structs.Service{
+		{
+			Name:      "web",
+			PortLabel: "http",
+			Connect: &structs.ConsulConnect{
+				SidecarService: &structs.ConsulSidecarService{},
+			},
+		},
+	}
+	task := tg.Tasks[0]
+	task.Name = structs.ConnectProxyName("web")
+	task.Kind = structs.NewTaskKind(structs.ConnectProxyPrefix, "web")
+	task.Driver = "mock_driver"
+	task.User = "nobody"
+
+	ctx := testTaskRunnerFromAlloc(t, false, alloc)
+	defer ctx.Cleanup()
+	ctx.tr.config.ConsulConfig.Token = configToken
+
+	require.NoError(t, ctx.tr.bootstrapEnvoy(alloc, task))
+
+	// The configuration is written by the CLI and only readable by the task
+	path := filepath.Join(ctx.tr.taskDir.SecretsDir, envoyBootstrapFile)
+	bootstrap, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(bootstrap), "-sidecar-for")
+
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+
+	// The token of the agent is nowhere in the task dir
+	err = filepath.Walk(ctx.tr.taskDir.Dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || !fi.Mode().IsRegular() {
+			return err
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if strings.Contains(string(b), configToken) || strings.Contains(string(b), envToken) {
+			t.Errorf("agent token found in %q", path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
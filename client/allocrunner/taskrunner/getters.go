@@ -17,3 +17,43 @@ func (r *TaskRunner) IsLeader() bool {
 
 	return r.task.Leader
 }
+
+// IsInit returns whether the task must complete before the main tasks of the
+// group are started
+func (r *TaskRunner) IsInit() bool {
+	if r == nil || r.task == nil {
+		return false
+	}
+
+	return r.task.IsInit()
+}
+
+// IsSidecar returns whether the task runs alongside the main tasks of the
+// group
+func (r *TaskRunner) IsSidecar() bool {
+	if r == nil || r.task == nil {
+		return false
+	}
+
+	return r.task.IsSidecar()
+}
+
+// IsPoststart returns whether the task is started once the main tasks of the
+// group are running
+func (r *TaskRunner) IsPoststart() bool {
+	if r == nil || r.task == nil {
+		return false
+	}
+
+	return r.task.IsPoststart()
+}
+
+// IsPoststop returns whether the task is run once the main tasks of the group
+// are dead
+func (r *TaskRunner) IsPoststop() bool {
+	if r == nil || r.task == nil {
+		return false
+	}
+
+	return r.task.IsPoststop()
+}
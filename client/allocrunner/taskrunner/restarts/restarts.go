@@ -22,7 +22,7 @@ const (
 
 func NewRestartTracker(policy *structs.RestartPolicy, jobType string) *RestartTracker {
 	onSuccess := true
-	if jobType == structs.JobTypeBatch {
+	if jobType == structs.JobTypeBatch || jobType == structs.JobTypeSysBatch {
 		onSuccess = false
 	}
 	return &RestartTracker{
@@ -146,20 +146,20 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		return structs.TaskNotRestarting, 0
 	}
 
-	r.count++
+	// Handle restarts due to failures
+	if !r.failure {
+		return "", 0
+	}
 
-	// Check if we have entered a new interval.
+	// Check if we have entered a new interval. The current failure is the
+	// first attempt of the new interval.
 	end := r.startTime.Add(r.policy.Interval)
 	now := time.Now()
 	if now.After(end) {
 		r.count = 0
 		r.startTime = now
 	}
-
-	// Handle restarts due to failures
-	if !r.failure {
-		return "", 0
-	}
+	r.count++
 
 	if r.startErr != nil {
 		// If the error is not recoverable, do not restart.
@@ -75,6 +75,30 @@ func TestClient_RestartTracker_ModeFail(t *testing.T) {
 	}
 }
 
+func TestClient_RestartTracker_ModeFail_NewInterval(t *testing.T) {
+	t.Parallel()
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+	rt := NewRestartTracker(p, structs.JobTypeSystem)
+	for i := 0; i < p.Attempts; i++ {
+		if state, _ := rt.SetWaitResult(testWaitResult(127)).GetState(); state != structs.TaskRestarting {
+			t.Fatalf("NextRestart() returned %v, want %v", state, structs.TaskRestarting)
+		}
+	}
+
+	// Move past the interval so the attempts reset
+	rt.startTime = time.Now().Add(-2 * p.Interval)
+
+	// The new interval allows exactly the policy's attempts
+	for i := 0; i < p.Attempts; i++ {
+		if state, _ := rt.SetWaitResult(testWaitResult(127)).GetState(); state != structs.TaskRestarting {
+			t.Fatalf("NextRestart() returned %v, want %v", state, structs.TaskRestarting)
+		}
+	}
+	if state, _ := rt.SetWaitResult(testWaitResult(127)).GetState(); state != structs.TaskNotRestarting {
+		t.Fatalf("NextRestart() returned %v; want %v", state, structs.TaskNotRestarting)
+	}
+}
+
 func TestClient_RestartTracker_NoRestartOnSuccess(t *testing.T) {
 	t.Parallel()
 	p := testPolicy(false, structs.RestartPolicyModeDelay)
@@ -2,14 +2,18 @@ package taskrunner
 
 import (
 	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,10 +30,12 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/driver"
+	"github.com/hashicorp/nomad/client/network"
 	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/vaultclient"
 	"github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/device"
 	"github.com/ugorji/go/codec"
 
 	"github.com/hashicorp/nomad/client/driver/env"
@@ -61,6 +67,14 @@ const (
 	// vaultTokenFile is the name of the file holding the Vault token inside the
 	// task's secret directory
 	vaultTokenFile = "vault_token"
+
+	// identityTokenFile is the name of the file holding the task's workload
+	// identity inside the task's secret directory
+	identityTokenFile = "nomad_identity_token"
+
+	// servicesRetryInterval is how long to wait before watching the services
+	// registered in Nomad again after failing to read them
+	servicesRetryInterval = 10 * time.Second
 )
 
 var (
@@ -130,6 +144,18 @@ type TaskRunner struct {
 	// Must acquire persistLock when accessing
 	taskDirBuilt bool
 
+	// volumeMounts are the host paths of the volumes mounted into the task.
+	// They are resolved before the task dir is built.
+	volumeMounts []*allocdir.VolumeMount
+
+	// deviceReserver is used to reserve the devices assigned to the task
+	deviceReserver DeviceReserver
+
+	// deviceMounts and devices are the host paths and devices of the
+	// devices reserved for the task. They are set in prestart.
+	deviceMounts []*allocdir.VolumeMount
+	devices      []*device.DeviceSpec
+
 	// createdResources are all the resources created by the task driver
 	// across all attempts to start the task.
 	// Simple gets and sets should use {get,set}CreatedResources
@@ -148,6 +174,18 @@ type TaskRunner struct {
 	// vaultClient is used to retrieve and renew any needed Vault token
 	vaultClient vaultclient.VaultClient
 
+	// rpc is used to read the task's variables and services from the servers
+	rpc RPCer
+
+	// variables are the items of the task's variables, exposed to its
+	// templates. They are read once before the templates are first rendered.
+	variables map[string]string
+
+	// services are the addresses of the passing instances of the services
+	// registered in Nomad, exposed to the task's templates. They are watched
+	// for changes once the templates are first rendered.
+	services map[string]string
+
 	// templateManager is used to manage any consul-templates this task may have
 	templateManager *TaskTemplateManager
 
@@ -217,6 +255,18 @@ func (s *taskRunnerState) Hash() []byte {
 // is set the event won't be immediately pushed to the server.
 type TaskStateUpdater func(taskName, state string, event *structs.TaskEvent, lazySync bool)
 
+// RPCer is the interface needed by a TaskRunner to make RPC calls to the
+// servers.
+type RPCer interface {
+	RPC(method string, args interface{}, reply interface{}) error
+}
+
+// DeviceReserver is the interface needed by a TaskRunner to reserve the
+// devices assigned to its task with the device plugins.
+type DeviceReserver interface {
+	Reserve(d *structs.AllocatedDeviceResource) (*device.ContainerReservation, error)
+}
+
 // SignalEvent is a tuple of the signal and the event generating it
 type SignalEvent struct {
 	// s is the signal to be sent
@@ -233,7 +283,8 @@ type SignalEvent struct {
 func NewTaskRunner(logger *log.Logger, config *config.Config,
 	stateDB *bolt.DB, updater TaskStateUpdater, taskDir *allocdir.TaskDir,
 	alloc *structs.Allocation, task *structs.Task,
-	vaultClient vaultclient.VaultClient, consulClient consulApi.ConsulServiceAPI) *TaskRunner {
+	vaultClient vaultclient.VaultClient, consulClient consulApi.ConsulServiceAPI,
+	rpc RPCer, deviceReserver DeviceReserver) *TaskRunner {
 
 	// Merge in the task resources
 	task.Resources = alloc.TaskResources[task.Name]
@@ -244,7 +295,14 @@ func NewTaskRunner(logger *log.Logger, config *config.Config,
 		logger.Printf("[ERR] client: alloc %q for missing task group %q", alloc.ID, alloc.TaskGroup)
 		return nil
 	}
-	restartTracker := restarts.NewRestartTracker(tg.RestartPolicy, alloc.Job.Type)
+	// Init, poststart and poststop tasks that aren't sidecars are expected
+	// to run to completion so they are only restarted on failure, regardless
+	// of the job type.
+	jobType := alloc.Job.Type
+	if task.IsInit() || task.IsPoststop() || (task.IsPoststart() && !task.IsSidecar()) {
+		jobType = structs.JobTypeBatch
+	}
+	restartTracker := restarts.NewRestartTracker(tg.RestartPolicy, jobType)
 
 	// Initialize the environment builder
 	envBuilder := env.NewBuilder(config.Node, alloc, task, config.Region)
@@ -262,6 +320,8 @@ func NewTaskRunner(logger *log.Logger, config *config.Config,
 		createdResources: driver.NewCreatedResources(),
 		consul:           consulClient,
 		vaultClient:      vaultClient,
+		rpc:              rpc,
+		deviceReserver:   deviceReserver,
 		vaultFuture:      NewTokenFuture().Set(""),
 		updateCh:         make(chan *structs.Allocation, 64),
 		destroyCh:        make(chan struct{}),
@@ -403,7 +463,7 @@ func (r *TaskRunner) RestoreState() (string, error) {
 		r.envBuilder.SetDriverNetwork(r.driverNet)
 
 		// Open a connection to the driver handle
-		ctx := driver.NewExecContext(r.taskDir, r.envBuilder.Build())
+		ctx := r.newExecContext()
 		handle, err := d.Open(ctx, snap.HandleID)
 
 		// In the case it fails, we relaunch the task in the Run() method.
@@ -604,6 +664,18 @@ func (r *TaskRunner) Run() {
 		return
 	}
 
+	// Resolve the volumes to mount into the task
+	volumeMounts, err := r.resolveVolumeMounts()
+	if err != nil {
+		e := fmt.Errorf("failed to resolve volume mounts of task %q for alloc %q: %v", r.task.Name, r.alloc.ID, err)
+		r.setState(
+			structs.TaskStateDead,
+			structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(e).SetFailsTask(),
+			false)
+		return
+	}
+	r.volumeMounts = volumeMounts
+
 	// Build base task directory structure regardless of FS isolation abilities.
 	// This needs to happen before we start the Vault manager and call prestart
 	// as both those can write to the task directories
@@ -902,6 +974,150 @@ func (r *TaskRunner) writeToken(token string) error {
 	return nil
 }
 
+// readVariables reads the items of the variables the task can access with its
+// workload identity. The items of more specific paths override the items of
+// the job's variable.
+func (r *TaskRunner) readVariables(alloc *structs.Allocation, task *structs.Task) (map[string]string, error) {
+	identity, ok := alloc.SignedIdentities[task.Name]
+	if !ok || r.rpc == nil {
+		return nil, nil
+	}
+
+	variables := make(map[string]string)
+	for _, path := range structs.VariablesTaskPaths(alloc.JobID, alloc.TaskGroup, task.Name) {
+		req := structs.VariablesReadRequest{
+			Path: path,
+			QueryOptions: structs.QueryOptions{
+				Region:     r.config.Region,
+				Namespace:  alloc.Namespace,
+				AuthToken:  identity,
+				AllowStale: true,
+			},
+		}
+		var resp structs.VariablesReadResponse
+		if err := r.rpc.RPC("Variables.Read", &req, &resp); err != nil {
+			return nil, err
+		}
+		if resp.Data == nil {
+			continue
+		}
+		for k, v := range resp.Data.Items {
+			variables[k] = v
+		}
+	}
+	return variables, nil
+}
+
+// readServices reads the addresses of the instances of the services registered
+// in Nomad in the namespace of the allocation, keyed by service name. Only
+// the instances whose checks are passing are returned. The services are read
+// once their index is greater than the given index, which is returned along
+// with the services.
+func (r *TaskRunner) readServices(alloc *structs.Allocation, index uint64) (map[string]string, uint64, error) {
+	opts := structs.QueryOptions{
+		Region:     r.config.Region,
+		Namespace:  alloc.Namespace,
+		AuthToken:  r.config.Node.SecretID,
+		AllowStale: true,
+	}
+	req := structs.ServiceRegistrationListRequest{QueryOptions: opts}
+	req.MinQueryIndex = index
+	var resp structs.ServiceRegistrationListResponse
+	if err := r.rpc.RPC("ServiceRegistration.List", &req, &resp); err != nil {
+		return nil, 0, err
+	}
+
+	services := make(map[string]string, len(resp.Services))
+	for _, stub := range resp.Services {
+		req := structs.ServiceRegistrationByNameRequest{
+			ServiceName:  stub.ServiceName,
+			QueryOptions: opts,
+		}
+		var resp structs.ServiceRegistrationByNameResponse
+		if err := r.rpc.RPC("ServiceRegistration.GetService", &req, &resp); err != nil {
+			return nil, 0, err
+		}
+
+		var addrs []string
+		for _, service := range resp.Services {
+			if service.Healthy() {
+				addrs = append(addrs, net.JoinHostPort(service.Address, strconv.Itoa(service.Port)))
+			}
+		}
+		if len(addrs) != 0 {
+			services[stub.ServiceName] = strings.Join(addrs, ",")
+		}
+	}
+	return services, resp.Index, nil
+}
+
+// servicesWatcher should be called in a go-routine and watches the services
+// registered in Nomad from the given index, rebuilding the template manager
+// when the addresses of their passing instances change.
+func (r *TaskRunner) servicesWatcher(index uint64) {
+	for {
+		services, newIndex, err := r.readServices(r.alloc, index)
+		if err != nil {
+			r.logger.Printf("[WARN] client: failed to read services for task %v on alloc %q: %v; retrying in %v",
+				r.task.Name, r.alloc.ID, err, servicesRetryInterval)
+
+			select {
+			case <-r.waitCh:
+				return
+			case <-time.After(servicesRetryInterval):
+			}
+			continue
+		}
+
+		select {
+		case <-r.waitCh:
+			return
+		default:
+		}
+
+		index = newIndex
+		if reflect.DeepEqual(services, r.services) {
+			continue
+		}
+		r.services = services
+		r.updatedServicesHandler()
+	}
+}
+
+// updatedServicesHandler is called when the addresses of the services
+// registered in Nomad change. The templates are rendered again with the new
+// addresses, applying their change mode if their content changes.
+func (r *TaskRunner) updatedServicesHandler() {
+	if r.templateManager == nil {
+		return
+	}
+	r.templateManager.Stop()
+
+	// Create a new templateManager
+	var err error
+	r.templateManager, err = NewTaskTemplateManager(&TaskTemplateManagerConfig{
+		Hooks:                r,
+		Templates:            r.task.Templates,
+		ClientConfig:         r.config,
+		VaultToken:           r.vaultFuture.Get(),
+		Variables:            r.variables,
+		Services:             r.services,
+		TaskStarted:          true,
+		TaskDir:              r.taskDir.Dir,
+		EnvBuilder:           r.envBuilder,
+		MaxTemplateEventRate: DefaultMaxTemplateEventRate,
+	})
+
+	if err != nil {
+		err := fmt.Errorf("failed to build task's template manager: %v", err)
+		r.setState(structs.TaskStateDead,
+			structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(err).SetFailsTask(),
+			false)
+		r.logger.Printf("[ERR] client: alloc %q, task %q %v", r.alloc.ID, r.task.Name, err)
+		r.Kill("template", err.Error(), true)
+	}
+}
+
 // updatedTokenHandler is called when a new Vault token is retrieved. Things
 // that rely on the token should be updated here.
 func (r *TaskRunner) updatedTokenHandler() {
@@ -919,6 +1135,8 @@ func (r *TaskRunner) updatedTokenHandler() {
 			Templates:            r.task.Templates,
 			ClientConfig:         r.config,
 			VaultToken:           r.vaultFuture.Get(),
+			Variables:            r.variables,
+			Services:             r.services,
 			TaskDir:              r.taskDir.Dir,
 			EnvBuilder:           r.envBuilder,
 			MaxTemplateEventRate: DefaultMaxTemplateEventRate,
@@ -954,6 +1172,32 @@ func (r *TaskRunner) prestart(alloc *structs.Allocation, task *structs.Task, res
 		r.envBuilder.SetVaultToken(r.vaultFuture.Get(), task.Vault.Env)
 	}
 
+	// Reserve the devices assigned to the task
+	if err := r.reserveDevices(alloc, task); err != nil {
+		wrapped := fmt.Errorf("failed to reserve devices: %v", err)
+		r.logger.Printf("[ERR] client: alloc %q, task %q %v", alloc.ID, task.Name, wrapped)
+		r.setState(
+			structs.TaskStateDead,
+			structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(wrapped).SetFailsTask(),
+			false)
+		resultCh <- false
+		return
+	}
+
+	// Write the workload identity signed by the servers
+	if identity, ok := alloc.SignedIdentities[task.Name]; ok {
+		identityPath := filepath.Join(r.taskDir.SecretsDir, identityTokenFile)
+		if err := ioutil.WriteFile(identityPath, []byte(identity), 0777); err != nil {
+			wrapped := fmt.Errorf("failed to save workload identity to secret dir: %v", err)
+			r.setState(
+				structs.TaskStateDead,
+				structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(wrapped).SetFailsTask(),
+				false)
+			resultCh <- false
+			return
+		}
+	}
+
 	// If the job is a dispatch job and there is a payload write it to disk
 	requirePayload := len(alloc.Job.Payload) != 0 &&
 		(r.task.DispatchPayload != nil && r.task.DispatchPayload.File != "")
@@ -1015,6 +1259,18 @@ func (r *TaskRunner) prestart(alloc *structs.Allocation, task *structs.Task, res
 			r.persistLock.Unlock()
 		}
 
+		// Bootstrap the sidecar proxy of a Connect service
+		if task.Kind.IsConnectProxy() {
+			if err := r.bootstrapEnvoy(alloc, task); err != nil {
+				wrapped := fmt.Errorf("failed to bootstrap Envoy: %v", err)
+				r.logger.Printf("[DEBUG] client: alloc %q, task %q %v", alloc.ID, task.Name, wrapped)
+				r.setState(structs.TaskStatePending,
+					structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(wrapped), false)
+				r.restartTracker.SetStartError(structs.NewRecoverableError(wrapped, true))
+				goto RESTART
+			}
+		}
+
 		// We don't have to wait for any template
 		if len(task.Templates) == 0 {
 			// Send the start signal
@@ -1029,12 +1285,48 @@ func (r *TaskRunner) prestart(alloc *structs.Allocation, task *structs.Task, res
 
 		// Build the template manager
 		if r.templateManager == nil {
+			// The variables and services are only read from the servers if
+			// the templates refer to them
+			taskEnv := r.envBuilder.Build()
+			useVariables := r.rpc != nil && templatesReferTo(task.Templates, r.taskDir.Dir, taskEnv, variableEnvPrefix)
+			useServices := r.rpc != nil && templatesReferTo(task.Templates, r.taskDir.Dir, taskEnv, serviceEnvPrefix)
+
+			if useVariables {
+				variables, err := r.readVariables(alloc, task)
+				if err != nil {
+					wrapped := fmt.Errorf("failed to read variables: %v", err)
+					r.logger.Printf("[DEBUG] client: alloc %q, task %q %v", alloc.ID, task.Name, wrapped)
+					r.setState(structs.TaskStatePending,
+						structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(wrapped), false)
+					r.restartTracker.SetStartError(structs.NewRecoverableError(wrapped, true))
+					goto RESTART
+				}
+				r.variables = variables
+			}
+
+			var servicesIndex uint64
+			if useServices {
+				services, index, err := r.readServices(alloc, 0)
+				if err != nil {
+					wrapped := fmt.Errorf("failed to read services: %v", err)
+					r.logger.Printf("[DEBUG] client: alloc %q, task %q %v", alloc.ID, task.Name, wrapped)
+					r.setState(structs.TaskStatePending,
+						structs.NewTaskEvent(structs.TaskSetupFailure).SetSetupError(wrapped), false)
+					r.restartTracker.SetStartError(structs.NewRecoverableError(wrapped, true))
+					goto RESTART
+				}
+				r.services = services
+				servicesIndex = index
+			}
+
 			var err error
 			r.templateManager, err = NewTaskTemplateManager(&TaskTemplateManagerConfig{
 				Hooks:                r,
 				Templates:            r.task.Templates,
 				ClientConfig:         r.config,
 				VaultToken:           r.vaultFuture.Get(),
+				Variables:            r.variables,
+				Services:             r.services,
 				TaskDir:              r.taskDir.Dir,
 				EnvBuilder:           r.envBuilder,
 				MaxTemplateEventRate: DefaultMaxTemplateEventRate,
@@ -1046,6 +1338,11 @@ func (r *TaskRunner) prestart(alloc *structs.Allocation, task *structs.Task, res
 				resultCh <- false
 				return
 			}
+
+			// Render the templates again when the services change
+			if useServices {
+				go r.servicesWatcher(servicesIndex)
+			}
 		}
 
 		// Block for consul-template
@@ -1287,7 +1584,7 @@ func (r *TaskRunner) cleanup() {
 
 	res := r.getCreatedResources()
 
-	ctx := driver.NewExecContext(r.taskDir, r.envBuilder.Build())
+	ctx := r.newExecContext()
 	attempts := 1
 	var cleanupErr error
 	for retry := true; retry; attempts++ {
@@ -1405,6 +1702,96 @@ func (r *TaskRunner) killTask(killingEvent *structs.TaskEvent) {
 	r.setState("", structs.NewTaskEvent(structs.TaskKilled).SetKillError(err), true)
 }
 
+// newExecContext returns the context of the driver calls for the task, which
+// joins the network namespace of the alloc if any.
+func (r *TaskRunner) newExecContext() *driver.ExecContext {
+	ctx := driver.NewExecContext(r.taskDir, r.envBuilder.Build())
+	ctx.NetNSPath = network.AllocNetNSPath(r.alloc)
+	ctx.VolumeMounts = append(ctx.VolumeMounts, r.volumeMounts...)
+	ctx.VolumeMounts = append(ctx.VolumeMounts, r.deviceMounts...)
+	ctx.Devices = r.devices
+	return ctx
+}
+
+// reserveDevices reserves the devices assigned to the task with the device
+// plugins. The task is given the environment, mounts and devices of the
+// reservations.
+func (r *TaskRunner) reserveDevices(alloc *structs.Allocation, task *structs.Task) error {
+	assigned := alloc.TaskDevices[task.Name]
+	if len(assigned) == 0 {
+		return nil
+	}
+	if r.deviceReserver == nil {
+		return fmt.Errorf("no device plugins are running")
+	}
+
+	envs := make(map[string]string)
+	var mounts []*allocdir.VolumeMount
+	var devices []*device.DeviceSpec
+	for _, d := range assigned {
+		res, err := r.deviceReserver.Reserve(d)
+		if err != nil {
+			return fmt.Errorf("failed to reserve devices %q: %v", d.ID(), err)
+		}
+		if res == nil {
+			continue
+		}
+
+		for k, v := range res.Envs {
+			envs[k] = v
+		}
+		for _, m := range res.Mounts {
+			mounts = append(mounts, &allocdir.VolumeMount{
+				HostPath: m.HostPath,
+				TaskPath: m.TaskPath,
+				ReadOnly: m.ReadOnly,
+			})
+		}
+		devices = append(devices, res.Devices...)
+	}
+
+	r.envBuilder.SetDeviceEnv(envs)
+	r.deviceMounts = mounts
+	r.devices = devices
+	return nil
+}
+
+// resolveVolumeMounts returns the host paths of the group volumes mounted into
+// the task.
+func (r *TaskRunner) resolveVolumeMounts() ([]*allocdir.VolumeMount, error) {
+	if len(r.task.VolumeMounts) == 0 {
+		return nil, nil
+	}
+
+	tg := r.alloc.Job.LookupTaskGroup(r.alloc.TaskGroup)
+	if tg == nil {
+		return nil, fmt.Errorf("task group %q not found", r.alloc.TaskGroup)
+	}
+
+	mounts := make([]*allocdir.VolumeMount, 0, len(r.task.VolumeMounts))
+	for _, vm := range r.task.VolumeMounts {
+		req, ok := tg.Volumes[vm.Volume]
+		if !ok {
+			return nil, fmt.Errorf("volume %q is not requested by the task group", vm.Volume)
+		}
+		if req.Type != structs.VolumeTypeHost {
+			return nil, fmt.Errorf("volume %q of type %q can't be mounted", vm.Volume, req.Type)
+		}
+
+		hostVolume, ok := r.config.HostVolumes[req.Source]
+		if !ok {
+			return nil, fmt.Errorf("host volume %q of volume %q not found", req.Source, vm.Volume)
+		}
+
+		mounts = append(mounts, &allocdir.VolumeMount{
+			HostPath: hostVolume.Path,
+			TaskPath: vm.Destination,
+			ReadOnly: vm.ReadOnly || req.ReadOnly || hostVolume.ReadOnly,
+		})
+	}
+	return mounts, nil
+}
+
 // startTask creates the driver, task dir, and starts the task.
 func (r *TaskRunner) startTask() error {
 	// Create a driver
@@ -1414,8 +1801,34 @@ func (r *TaskRunner) startTask() error {
 			r.task.Name, r.alloc.ID, err)
 	}
 
+	// Drivers which can't join the network namespace of the alloc would run
+	// the task in the network of the host
+	if network.AllocNetNSPath(r.alloc) != "" && !drv.Abilities().NetNS {
+		return structs.NewRecoverableError(fmt.Errorf("driver %q of task %q doesn't support task group networks",
+			r.task.Driver, r.task.Name), false)
+	}
+
+	// Volumes and devices would silently be missing from tasks of drivers
+	// that can't mount them
+	if len(r.volumeMounts)+len(r.deviceMounts) != 0 && !drv.Abilities().VolumeMounts {
+		return structs.NewRecoverableError(fmt.Errorf("driver %q of task %q doesn't support volume mounts",
+			r.task.Driver, r.task.Name), false)
+	}
+	if len(r.devices) != 0 && !drv.Abilities().Devices {
+		return structs.NewRecoverableError(fmt.Errorf("driver %q of task %q doesn't support devices",
+			r.task.Driver, r.task.Name), false)
+	}
+
+	// The mounts of the reserved devices are only known once prestart ran
+	if drv.FSIsolation() == cstructs.FSIsolationChroot {
+		if err := r.taskDir.MountVolumes(r.deviceMounts); err != nil {
+			return structs.NewRecoverableError(fmt.Errorf("failed to mount devices of task %q: %v",
+				r.task.Name, err), false)
+		}
+	}
+
 	// Run prestart
-	ctx := driver.NewExecContext(r.taskDir, r.envBuilder.Build())
+	ctx := r.newExecContext()
 	presp, err := drv.Prestart(ctx, r.task)
 
 	// Merge newly created resources into previously created resources
@@ -1436,7 +1849,7 @@ func (r *TaskRunner) startTask() error {
 	}
 
 	// Create a new context for Start since the environment may have been updated.
-	ctx = driver.NewExecContext(r.taskDir, r.envBuilder.Build())
+	ctx = r.newExecContext()
 
 	// Start the job
 	sresp, err := drv.Start(ctx, r.task)
@@ -1563,6 +1976,14 @@ func (r *TaskRunner) buildTaskDir(fsi cstructs.FSIsolation) error {
 		return err
 	}
 
+	// Tasks isolated in a chroot of the task dir find their volumes mounted
+	// in it, other drivers mount the volumes themselves
+	if fsi == cstructs.FSIsolationChroot {
+		if err := r.taskDir.MountVolumes(r.volumeMounts); err != nil {
+			return err
+		}
+	}
+
 	// Mark task dir as successfully built
 	r.persistLock.Lock()
 	r.taskDirBuilt = true
@@ -1783,11 +2204,40 @@ func (r *TaskRunner) Signal(source, reason string, s os.Signal) error {
 	select {
 	case r.signalCh <- se:
 	case <-r.waitCh:
+		// The task has exited so there is nothing to signal
+		return nil
 	}
 
 	return <-resCh
 }
 
+// Exec runs a command inside the running task. Drivers that can stream the
+// input and output of a command do so; other drivers run the command without
+// a terminal or input and write its combined output to Stdout once it exits.
+func (r *TaskRunner) Exec(ctx context.Context, opts *driver.ExecStreamingOptions) (int, error) {
+	handle := r.getHandle()
+	if handle == nil {
+		return 0, fmt.Errorf("task %q is not running", r.task.Name)
+	}
+
+	if se, ok := handle.(driver.StreamingExecutor); ok {
+		return se.ExecStreaming(ctx, opts)
+	}
+
+	if opts.Tty {
+		return 0, fmt.Errorf("task %q driver does not support interactive commands", r.task.Name)
+	}
+
+	out, code, err := handle.Exec(ctx, opts.Command[0], opts.Command[1:])
+	if err != nil {
+		return 0, err
+	}
+	if _, err := opts.Stdout.Write(out); err != nil {
+		return 0, err
+	}
+	return code, nil
+}
+
 // Kill will kill a task and store the error, no longer restarting the task. If
 // fail is set, the task is marked as having failed.
 func (r *TaskRunner) Kill(source, reason string, fail bool) {
@@ -1828,6 +2278,7 @@ func (r *TaskRunner) waitErrorToEvent(res *dstructs.WaitResult) *structs.TaskEve
 	return structs.NewTaskEvent(structs.TaskTerminated).
 		SetExitCode(res.ExitCode).
 		SetSignal(res.Signal).
+		SetOOMKilled(res.OOMKilled).
 		SetExitMessage(res.Err)
 }
 
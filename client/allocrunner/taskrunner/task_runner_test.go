@@ -26,6 +26,7 @@ import (
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/device"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/kr/pretty"
 )
@@ -136,7 +137,7 @@ func testTaskRunnerFromAlloc(t *testing.T, restarts bool, alloc *structs.Allocat
 	cclient := consul.NewMockAgent()
 	serviceClient := consul.NewServiceClient(cclient, logger, true)
 	go serviceClient.Run()
-	tr := NewTaskRunner(logger, conf, db, upd.Update, taskDir, alloc, task, vclient, serviceClient)
+	tr := NewTaskRunner(logger, conf, db, upd.Update, taskDir, alloc, task, vclient, serviceClient, nil, nil)
 	if !restarts {
 		tr.restartTracker = noRestartsTracker()
 	}
@@ -441,7 +442,7 @@ func TestTaskRunner_SaveRestoreState(t *testing.T) {
 	// Create a new task runner
 	task2 := &structs.Task{Name: ctx.tr.task.Name, Driver: ctx.tr.task.Driver, Vault: ctx.tr.task.Vault}
 	tr2 := NewTaskRunner(ctx.tr.logger, ctx.tr.config, ctx.tr.stateDB, ctx.upd.Update,
-		ctx.tr.taskDir, ctx.tr.alloc, task2, ctx.tr.vaultClient, ctx.tr.consul)
+		ctx.tr.taskDir, ctx.tr.alloc, task2, ctx.tr.vaultClient, ctx.tr.consul, ctx.tr.rpc, ctx.tr.deviceReserver)
 	tr2.restartTracker = noRestartsTracker()
 	if _, err := tr2.RestoreState(); err != nil {
 		t.Fatalf("err: %v", err)
@@ -664,6 +665,202 @@ func TestTaskRunner_UnregisterConsul_Retries(t *testing.T) {
 	}
 }
 
+func TestTaskRunner_ResolveVolumeMounts(t *testing.T) {
+	t.Parallel()
+	alloc := mock.Alloc()
+	tg := alloc.Job.TaskGroups[0]
+	tg.Volumes = map[string]*structs.VolumeRequest{
+		"certs": {Name: "certs", Type: structs.VolumeTypeHost, Source: "ca-certs"},
+		"data":  {Name: "data", Type: structs.VolumeTypeHost, Source: "shared-data", ReadOnly: true},
+	}
+	task := tg.Tasks[0]
+	task.Driver = "mock_driver"
+	task.VolumeMounts = []*structs.VolumeMount{
+		{Volume: "certs", Destination: "/etc/ssl/certs"},
+		{Volume: "data", Destination: "/data"},
+	}
+
+	ctx := testTaskRunnerFromAlloc(t, false, alloc)
+	defer ctx.Cleanup()
+	ctx.tr.config.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"ca-certs":    {Name: "ca-certs", Path: "/etc/ssl/certs"},
+		"shared-data": {Name: "shared-data", Path: "/srv/data"},
+	}
+
+	mounts, err := ctx.tr.resolveVolumeMounts()
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expected := []*allocdir.VolumeMount{
+		{HostPath: "/etc/ssl/certs", TaskPath: "/etc/ssl/certs"},
+		{HostPath: "/srv/data", TaskPath: "/data", ReadOnly: true},
+	}
+	if !reflect.DeepEqual(mounts, expected) {
+		t.Fatalf("unexpected mounts: %s", pretty.Diff(mounts, expected))
+	}
+
+	// Volumes must be declared by the client
+	delete(ctx.tr.config.HostVolumes, "shared-data")
+	if _, err := ctx.tr.resolveVolumeMounts(); err == nil || !strings.Contains(err.Error(), `host volume "shared-data"`) {
+		t.Fatalf("expected a missing host volume error but found: %v", err)
+	}
+}
+
+func TestTaskRunner_VolumeMounts_Unsupported(t *testing.T) {
+	t.Parallel()
+	alloc := mock.Alloc()
+	tg := alloc.Job.TaskGroups[0]
+	tg.Volumes = map[string]*structs.VolumeRequest{
+		"data": {Name: "data", Type: structs.VolumeTypeHost, Source: "shared-data"},
+	}
+	task := tg.Tasks[0]
+	task.Driver = "mock_driver"
+	task.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+	task.VolumeMounts = []*structs.VolumeMount{
+		{Volume: "data", Destination: "/data"},
+	}
+
+	ctx := testTaskRunnerFromAlloc(t, false, alloc)
+	ctx.tr.config.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"shared-data": {Name: "shared-data", Path: os.TempDir()},
+	}
+	ctx.tr.MarkReceived()
+	go ctx.tr.Run()
+	defer ctx.Cleanup()
+
+	select {
+	case <-ctx.tr.WaitCh():
+	case <-time.After(time.Duration(testutil.TestMultiplier()*15) * time.Second):
+		t.Fatalf("timeout")
+	}
+
+	if ctx.upd.state != structs.TaskStateDead || !ctx.upd.failed {
+		t.Fatalf("expected the task to fail; got state %v (failed %v)", ctx.upd.state, ctx.upd.failed)
+	}
+
+	found := false
+	for _, e := range ctx.upd.events {
+		if e.Type == structs.TaskDriverFailure && strings.Contains(e.DriverError, "doesn't support volume mounts") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a driver failure event: %#v", ctx.upd.events)
+	}
+}
+
+// mockDeviceReserver reserves devices by calling the function.
+type mockDeviceReserver func(*structs.AllocatedDeviceResource) (*device.ContainerReservation, error)
+
+func (f mockDeviceReserver) Reserve(d *structs.AllocatedDeviceResource) (*device.ContainerReservation, error) {
+	return f(d)
+}
+
+func TestTaskRunner_ReserveDevices(t *testing.T) {
+	t.Parallel()
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Driver = "mock_driver"
+	alloc.TaskDevices = map[string][]*structs.AllocatedDeviceResource{
+		task.Name: {
+			{Vendor: "nvidia", Type: "gpu", Name: "1080ti", DeviceIDs: []string{"GPU-1", "GPU-2"}},
+		},
+	}
+
+	ctx := testTaskRunnerFromAlloc(t, false, alloc)
+	defer ctx.Cleanup()
+
+	var reserved []string
+	ctx.tr.deviceReserver = mockDeviceReserver(func(d *structs.AllocatedDeviceResource) (*device.ContainerReservation, error) {
+		reserved = d.DeviceIDs
+		return &device.ContainerReservation{
+			Envs:    map[string]string{"NVIDIA_VISIBLE_DEVICES": "GPU-1,GPU-2"},
+			Mounts:  []*device.Mount{{HostPath: "/usr/lib/nvidia", TaskPath: "/usr/local/nvidia", ReadOnly: true}},
+			Devices: []*device.DeviceSpec{{HostPath: "/dev/nvidia0", TaskPath: "/dev/nvidia0", CgroupPerms: "rwm"}},
+		}, nil
+	})
+
+	if err := ctx.tr.reserveDevices(alloc, task); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !reflect.DeepEqual(reserved, []string{"GPU-1", "GPU-2"}) {
+		t.Fatalf("unexpected reserved devices: %v", reserved)
+	}
+
+	// The reservation is passed to the driver
+	execCtx := ctx.tr.newExecContext()
+	if v := execCtx.TaskEnv.Map()["NVIDIA_VISIBLE_DEVICES"]; v != "GPU-1,GPU-2" {
+		t.Fatalf("unexpected device env: %q", v)
+	}
+	expectedMounts := []*allocdir.VolumeMount{
+		{HostPath: "/usr/lib/nvidia", TaskPath: "/usr/local/nvidia", ReadOnly: true},
+	}
+	if !reflect.DeepEqual(execCtx.VolumeMounts, expectedMounts) {
+		t.Fatalf("unexpected mounts: %s", pretty.Diff(execCtx.VolumeMounts, expectedMounts))
+	}
+	expectedDevices := []*device.DeviceSpec{
+		{HostPath: "/dev/nvidia0", TaskPath: "/dev/nvidia0", CgroupPerms: "rwm"},
+	}
+	if !reflect.DeepEqual(execCtx.Devices, expectedDevices) {
+		t.Fatalf("unexpected devices: %s", pretty.Diff(execCtx.Devices, expectedDevices))
+	}
+
+	// Errors of the device plugins fail the reservation
+	ctx.tr.deviceReserver = mockDeviceReserver(func(*structs.AllocatedDeviceResource) (*device.ContainerReservation, error) {
+		return nil, fmt.Errorf("unknown device")
+	})
+	if err := ctx.tr.reserveDevices(alloc, task); err == nil || !strings.Contains(err.Error(), "nvidia/gpu/1080ti") {
+		t.Fatalf("expected a reservation error but found: %v", err)
+	}
+}
+
+func TestTaskRunner_Devices_Unsupported(t *testing.T) {
+	t.Parallel()
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Driver = "mock_driver"
+	task.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+	alloc.TaskDevices = map[string][]*structs.AllocatedDeviceResource{
+		task.Name: {
+			{Vendor: "nvidia", Type: "gpu", Name: "1080ti", DeviceIDs: []string{"GPU-1"}},
+		},
+	}
+
+	ctx := testTaskRunnerFromAlloc(t, false, alloc)
+	ctx.tr.deviceReserver = mockDeviceReserver(func(*structs.AllocatedDeviceResource) (*device.ContainerReservation, error) {
+		return &device.ContainerReservation{
+			Devices: []*device.DeviceSpec{{HostPath: "/dev/nvidia0", TaskPath: "/dev/nvidia0"}},
+		}, nil
+	})
+	ctx.tr.MarkReceived()
+	go ctx.tr.Run()
+	defer ctx.Cleanup()
+
+	select {
+	case <-ctx.tr.WaitCh():
+	case <-time.After(time.Duration(testutil.TestMultiplier()*15) * time.Second):
+		t.Fatalf("timeout")
+	}
+
+	if ctx.upd.state != structs.TaskStateDead || !ctx.upd.failed {
+		t.Fatalf("expected the task to fail; got state %v (failed %v)", ctx.upd.state, ctx.upd.failed)
+	}
+
+	found := false
+	for _, e := range ctx.upd.events {
+		if e.Type == structs.TaskDriverFailure && strings.Contains(e.DriverError, "doesn't support devices") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a driver failure event: %#v", ctx.upd.events)
+	}
+}
+
 func TestTaskRunner_Validate_UserEnforcement(t *testing.T) {
 	t.Parallel()
 	ctx := testTaskRunner(t, false)
@@ -2028,3 +2225,125 @@ func TestTaskRunner_DriverNetwork(t *testing.T) {
 		t.Fatalf("error: %v", err)
 	})
 }
+
+// mockVariablesRPC serves Variables.Read requests from a map of paths to items
+type mockVariablesRPC struct {
+	vars  map[string]map[string]string
+	token string
+}
+
+func (m *mockVariablesRPC) RPC(method string, args interface{}, reply interface{}) error {
+	if method != "Variables.Read" {
+		return fmt.Errorf("unexpected method %q", method)
+	}
+	req := args.(*structs.VariablesReadRequest)
+	if req.AuthToken != m.token {
+		return structs.ErrPermissionDenied
+	}
+	if items, ok := m.vars[req.Path]; ok {
+		resp := reply.(*structs.VariablesReadResponse)
+		resp.Data = &structs.VariableDecrypted{Items: items}
+	}
+	return nil
+}
+
+func TestTaskRunner_ReadVariables(t *testing.T) {
+	t.Parallel()
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	jobPath := "nomad/jobs/" + alloc.JobID
+
+	rpc := &mockVariablesRPC{
+		token: "identity",
+		vars: map[string]map[string]string{
+			jobPath:                         {"db": "job", "user": "job"},
+			jobPath + "/" + alloc.TaskGroup: {"db": "group"},
+			jobPath + "/" + alloc.TaskGroup + "/" + task.Name: {"user": "task"},
+			"nomad/jobs/other": {"secret": "other"},
+		},
+	}
+	tr := &TaskRunner{config: &config.Config{Region: "global"}, rpc: rpc}
+
+	// Tasks without an identity have no variables
+	vars, err := tr.readVariables(alloc, task)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if vars != nil {
+		t.Fatalf("expected no variables: %v", vars)
+	}
+
+	// The most specific path wins
+	alloc.SignedIdentities = map[string]string{task.Name: "identity"}
+	vars, err = tr.readVariables(alloc, task)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	expected := map[string]string{"db": "group", "user": "task"}
+	if !reflect.DeepEqual(vars, expected) {
+		t.Fatalf("got %v; want %v", vars, expected)
+	}
+
+	// Errors are returned
+	alloc.SignedIdentities[task.Name] = "bad"
+	if _, err := tr.readVariables(alloc, task); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+// mockServicesRPC serves the service registrations of a single service
+type mockServicesRPC struct {
+	index     uint64
+	minIndex  uint64
+	instances []*structs.ServiceRegistration
+}
+
+func (m *mockServicesRPC) RPC(method string, args interface{}, reply interface{}) error {
+	switch method {
+	case "ServiceRegistration.List":
+		m.minIndex = args.(*structs.ServiceRegistrationListRequest).MinQueryIndex
+		resp := reply.(*structs.ServiceRegistrationListResponse)
+		resp.Services = []*structs.ServiceRegistrationListStub{{ServiceName: "api"}}
+		resp.Index = m.index
+	case "ServiceRegistration.GetService":
+		resp := reply.(*structs.ServiceRegistrationByNameResponse)
+		resp.Services = m.instances
+	default:
+		return fmt.Errorf("unexpected method %q", method)
+	}
+	return nil
+}
+
+func TestTaskRunner_ReadServices(t *testing.T) {
+	t.Parallel()
+	alloc := mock.Alloc()
+	rpc := &mockServicesRPC{
+		index: 20,
+		instances: []*structs.ServiceRegistration{
+			{ServiceName: "api", Address: "10.0.0.1", Port: 9001},
+			{
+				ServiceName: "api",
+				Address:     "10.0.0.2",
+				Port:        9001,
+				Checks:      []*structs.ServiceRegistrationCheck{{Status: "critical"}},
+			},
+		},
+	}
+	tr := &TaskRunner{config: &config.Config{Region: "global", Node: mock.Node()}, rpc: rpc}
+
+	// Only the passing instances are returned, with the index of the services
+	services, index, err := tr.readServices(alloc, 10)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if rpc.minIndex != 10 {
+		t.Fatalf("got min query index %d; want 10", rpc.minIndex)
+	}
+	if index != 20 {
+		t.Fatalf("got index %d; want 20", index)
+	}
+	expected := map[string]string{"api": "10.0.0.1:9001"}
+	if !reflect.DeepEqual(services, expected) {
+		t.Fatalf("got %v; want %v", services, expected)
+	}
+}
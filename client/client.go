@@ -9,6 +9,7 @@ import (
 	"net/rpc"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -24,7 +25,9 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner"
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
+	"github.com/hashicorp/nomad/client/driver"
 	"github.com/hashicorp/nomad/client/servers"
+	"github.com/hashicorp/nomad/client/servicereg"
 	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/client/stats"
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -155,7 +158,8 @@ type Client struct {
 	allocUpdates chan *structs.Allocation
 
 	// consulService is Nomad's custom Consul client for managing services
-	// and checks.
+	// and checks. The services using the nomad provider are registered with
+	// the servers instead.
 	consulService consulApi.ConsulServiceAPI
 
 	// consulCatalog is the subset of Consul's Catalog API Nomad uses.
@@ -175,6 +179,20 @@ type Client struct {
 	// in the node automatically
 	garbageCollector *AllocGarbageCollector
 
+	// deviceManager runs the device plugins and fingerprints the devices of
+	// the node
+	deviceManager *DeviceManager
+
+	// externalDrivers are the names of the drivers launched from the plugin
+	// directory
+	externalDrivers []string
+
+	// staticNodeMeta is the node metadata from the client configuration and
+	// dynamicNodeMeta the metadata set at runtime, which is persisted in the
+	// state DB. They are protected by the configLock.
+	staticNodeMeta  map[string]string
+	dynamicNodeMeta map[string]*string
+
 	// clientACLResolver holds the ACL resolution state
 	clientACLResolver
 
@@ -256,6 +274,7 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		Interval:            cfg.GCInterval,
 		ParallelDestroys:    cfg.GCParallelDestroys,
 		ReservedDiskMB:      cfg.Node.Reserved.DiskMB,
+		MaxAge:              cfg.GCMaxAge,
 	}
 	c.garbageCollector = NewAllocGarbageCollector(logger, statsCollector, c, gcConfig)
 	go c.garbageCollector.Run()
@@ -271,6 +290,18 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 	c.configCopy = c.config.Copy()
 	c.configLock.Unlock()
 
+	// Register the services using the nomad provider with the servers
+	nomadService := servicereg.NewNomadServiceClient(c.logger, c, c.Region(), c.configCopy.Node)
+	c.consulService = servicereg.NewHandler(consulService, nomadService)
+
+	// Launch the driver plugins of the plugin directory so that they are
+	// fingerprinted along with the built-in drivers
+	externalDrivers, err := driver.LoadExternalDrivers(c.configCopy, c.logger)
+	if err != nil {
+		c.logger.Printf("[ERR] client: failed to load driver plugins: %v", err)
+	}
+	c.externalDrivers = externalDrivers
+
 	fingerprintManager := NewFingerprintManager(c.GetConfig, c.configCopy.Node,
 		c.shutdownCh, c.updateNodeFromFingerprint, c.updateNodeFromDriver,
 		c.logger)
@@ -280,6 +311,10 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulServic
 		return nil, fmt.Errorf("fingerprinting failed: %v", err)
 	}
 
+	// Fingerprint the devices of the node
+	c.deviceManager = NewDeviceManager(c.configCopy, c.updateNodeFromDevices, c.logger)
+	c.deviceManager.Run()
+
 	// Setup the reserved resources
 	c.reservePorts()
 
@@ -518,6 +553,11 @@ func (c *Client) Shutdown() error {
 	// Stop Garbage collector
 	c.garbageCollector.Stop()
 
+	// Stop fingerprinting devices
+	if c.deviceManager != nil {
+		c.deviceManager.Shutdown()
+	}
+
 	// Destroy all the running allocations.
 	if c.config.DevMode {
 		var wg sync.WaitGroup
@@ -535,6 +575,9 @@ func (c *Client) Shutdown() error {
 	c.shutdown = true
 	close(c.shutdownCh)
 	c.connPool.Shutdown()
+
+	// Stop the driver plugins once fingerprinting has stopped
+	driver.KillExternalDrivers(c.externalDrivers)
 	return c.saveState()
 }
 
@@ -562,6 +605,28 @@ func (c *Client) CollectAllocation(allocID string) bool {
 	return c.garbageCollector.Collect(allocID)
 }
 
+// SignalAllocation sends a signal to the tasks of an allocation. If task is
+// empty all of the allocation's tasks are signalled.
+func (c *Client) SignalAllocation(allocID, task, signal string) error {
+	ar, ok := c.getAllocRunners()[allocID]
+	if !ok {
+		return structs.NewErrUnknownAllocation(allocID)
+	}
+
+	return ar.Signal(task, signal)
+}
+
+// RestartAllocation restarts a task of an allocation. If task is empty all of
+// the allocation's tasks are restarted.
+func (c *Client) RestartAllocation(allocID, task string) error {
+	ar, ok := c.getAllocRunners()[allocID]
+	if !ok {
+		return structs.NewErrUnknownAllocation(allocID)
+	}
+
+	return ar.Restart(task)
+}
+
 // CollectAllAllocs garbage collects all allocations on a node in the terminal
 // state
 func (c *Client) CollectAllAllocs() {
@@ -757,7 +822,7 @@ func (c *Client) restoreState() error {
 		watcher := allocrunner.NoopPrevAlloc{}
 
 		c.configLock.RLock()
-		ar := allocrunner.NewAllocRunner(c.logger, c.configCopy.Copy(), c.stateDB, c.updateAllocStatus, alloc, c.vaultClient, c.consulService, watcher)
+		ar := allocrunner.NewAllocRunner(c.logger, c.configCopy.Copy(), c.stateDB, c.updateAllocStatus, alloc, c.vaultClient, c.consulService, c, c.deviceManager, watcher)
 		c.configLock.RUnlock()
 
 		c.allocLock.Lock()
@@ -933,15 +998,33 @@ func (c *Client) setupNode() error {
 	if node.Meta == nil {
 		node.Meta = make(map[string]string)
 	}
+
+	// Merge the metadata set at runtime on top of the configured metadata
+	c.staticNodeMeta = helper.CopyMapStringString(node.Meta)
+	if err := c.stateDB.View(func(tx *bolt.Tx) error {
+		meta, err := state.GetNodeMeta(tx)
+		c.dynamicNodeMeta = meta
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to restore node metadata: %v", err)
+	}
+	node.Meta = mergeNodeMeta(c.staticNodeMeta, c.dynamicNodeMeta)
+
 	if node.Resources == nil {
 		node.Resources = &structs.Resources{}
 	}
 	if node.Reserved == nil {
 		node.Reserved = &structs.Resources{}
 	}
+	if node.HostVolumes == nil && len(c.config.HostVolumes) != 0 {
+		node.HostVolumes = structs.CopyMapStringClientHostVolumeConfig(c.config.HostVolumes)
+	}
 	if node.Datacenter == "" {
 		node.Datacenter = "dc1"
 	}
+	if node.NodePool == "" {
+		node.NodePool = structs.NodePoolDefault
+	}
 	if node.Name == "" {
 		node.Name, _ = os.Hostname()
 	}
@@ -972,8 +1055,11 @@ func (c *Client) reservePorts() {
 	for _, net := range networks {
 		res, ok := reservedIndex[net.IP]
 		if !ok {
+			// The reserved ports of host networks are already reserved by
+			// their network resource
 			res = net.Copy()
 			res.MBits = 0
+			res.ReservedPorts = nil
 			reservedIndex[net.IP] = res
 		}
 
@@ -1049,6 +1135,93 @@ func (c *Client) updateNodeFromFingerprint(response *cstructs.FingerprintRespons
 	return c.configCopy.Node
 }
 
+// updateNodeFromDevices updates the node with the devices fingerprinted by the
+// device plugins
+func (c *Client) updateNodeFromDevices(devices []*structs.NodeDeviceResource) *structs.Node {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	if !reflect.DeepEqual(c.config.Node.Devices, devices) {
+		c.config.Node.Devices = devices
+		c.updateNodeLocked()
+	}
+
+	return c.configCopy.Node
+}
+
+// NodeMeta returns the effective, dynamic and static metadata of the node.
+func (c *Client) NodeMeta() (meta map[string]string, dynamic map[string]*string, static map[string]string) {
+	c.configLock.RLock()
+	defer c.configLock.RUnlock()
+
+	return helper.CopyMapStringString(c.config.Node.Meta),
+		copyNodeMeta(c.dynamicNodeMeta),
+		helper.CopyMapStringString(c.staticNodeMeta)
+}
+
+// UpdateNodeMeta sets the given metadata keys of the node, persists them and
+// re-registers the node. A nil value unsets the key.
+func (c *Client) UpdateNodeMeta(meta map[string]*string) error {
+	c.configLock.Lock()
+	defer c.configLock.Unlock()
+
+	dynamic := copyNodeMeta(c.dynamicNodeMeta)
+	if dynamic == nil {
+		dynamic = make(map[string]*string, len(meta))
+	}
+	for k, v := range meta {
+		dynamic[k] = v
+	}
+
+	if err := c.stateDB.Update(func(tx *bolt.Tx) error {
+		return state.PutNodeMeta(tx, dynamic)
+	}); err != nil {
+		return fmt.Errorf("failed to persist node metadata: %v", err)
+	}
+
+	c.dynamicNodeMeta = dynamic
+	newMeta := mergeNodeMeta(c.staticNodeMeta, dynamic)
+	if !reflect.DeepEqual(c.config.Node.Meta, newMeta) {
+		c.config.Node.Meta = newMeta
+		c.updateNodeLocked()
+	}
+	return nil
+}
+
+// mergeNodeMeta returns the static metadata overridden by the dynamic
+// metadata. Dynamic keys with a nil value are removed.
+func mergeNodeMeta(static map[string]string, dynamic map[string]*string) map[string]string {
+	meta := make(map[string]string, len(static)+len(dynamic))
+	for k, v := range static {
+		meta[k] = v
+	}
+	for k, v := range dynamic {
+		if v == nil {
+			delete(meta, k)
+		} else {
+			meta[k] = *v
+		}
+	}
+	return meta
+}
+
+// copyNodeMeta returns a copy of dynamic node metadata.
+func copyNodeMeta(meta map[string]*string) map[string]*string {
+	if meta == nil {
+		return nil
+	}
+
+	c := make(map[string]*string, len(meta))
+	for k, v := range meta {
+		if v == nil {
+			c[k] = nil
+		} else {
+			c[k] = helper.StringToPtr(*v)
+		}
+	}
+	return c
+}
+
 // updateNodeFromDriver receives either a fingerprint of the driver or its
 // health and merges this into a single DriverInfo object
 func (c *Client) updateNodeFromDriver(name string, fingerprint, health *structs.DriverInfo) *structs.Node {
@@ -1735,8 +1908,11 @@ OUTER:
 			}
 
 			// Ensure that we received all the allocations we wanted
+			// The workload identities of the allocations are only returned
+			// along with their modify indexes
 			pulledAllocs = make(map[string]*structs.Allocation, len(allocsResp.Allocs))
 			for _, alloc := range allocsResp.Allocs {
+				alloc.SignedIdentities = resp.SignedIdentities[alloc.ID]
 				pulledAllocs[alloc.ID] = alloc
 			}
 
@@ -1933,7 +2109,7 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 	// Copy the config since the node can be swapped out as it is being updated.
 	// The long term fix is to pass in the config and node separately and then
 	// we don't have to do a copy.
-	ar := allocrunner.NewAllocRunner(c.logger, c.configCopy.Copy(), c.stateDB, c.updateAllocStatus, alloc, c.vaultClient, c.consulService, prevAlloc)
+	ar := allocrunner.NewAllocRunner(c.logger, c.configCopy.Copy(), c.stateDB, c.updateAllocStatus, alloc, c.vaultClient, c.consulService, c, c.deviceManager, prevAlloc)
 	c.configLock.RUnlock()
 
 	// Store the alloc runner.
@@ -2171,6 +2347,7 @@ DISCOLOOP:
 				addr, err := net.ResolveTCPAddr("tcp", p)
 				if err != nil {
 					mErr.Errors = append(mErr.Errors, err)
+					continue
 				}
 				srv := &servers.Server{Addr: addr}
 				nomadServers = append(nomadServers, srv)
@@ -3,16 +3,19 @@ package client
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	consulapi "github.com/hashicorp/consul/api"
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/client/config"
 	consulApi "github.com/hashicorp/nomad/client/consul"
 	"github.com/hashicorp/nomad/client/driver"
 	"github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad"
@@ -21,6 +24,7 @@ import (
 	nconfig "github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	ctestutil "github.com/hashicorp/nomad/client/testutil"
 )
@@ -661,6 +665,20 @@ func TestClient_Init(t *testing.T) {
 	}
 }
 
+func TestClient_HostVolumes(t *testing.T) {
+	t.Parallel()
+
+	hostVolumes := map[string]*structs.ClientHostVolumeConfig{
+		"shared": {Name: "shared", Path: "/tmp", ReadOnly: true},
+	}
+	c1 := TestClient(t, func(c *config.Config) {
+		c.HostVolumes = hostVolumes
+	})
+	defer c1.Shutdown()
+
+	assert.Equal(t, hostVolumes, c1.Node().HostVolumes)
+}
+
 func TestClient_BlockedAllocations(t *testing.T) {
 	t.Parallel()
 	s1, _ := testServer(t, nil)
@@ -990,3 +1008,66 @@ func TestClient_ServerList(t *testing.T) {
 		t.Fatalf("expected 2 servers but received: %+q", s)
 	}
 }
+
+// testDiscoveryCatalog is a Consul catalog advertising the given Nomad
+// servers in each datacenter.
+type testDiscoveryCatalog struct {
+	dcs      []string
+	services map[string][]*consulapi.CatalogService
+}
+
+func (c *testDiscoveryCatalog) Datacenters() ([]string, error) {
+	return c.dcs, nil
+}
+
+func (c *testDiscoveryCatalog) Service(service, tag string, q *consulapi.QueryOptions) ([]*consulapi.CatalogService, *consulapi.QueryMeta, error) {
+	return c.services[q.Datacenter], nil, nil
+}
+
+// TestClient_ConsulDiscovery_SkipsFailedDatacenter asserts that discovery
+// moves on to the next datacenter when the servers of the first can't be
+// contacted.
+func TestClient_ConsulDiscovery_SkipsFailedDatacenter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, addr := testServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Nothing listens on the address of the server advertised in dc1
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	deadAddr := l.Addr().(*net.TCPAddr)
+	l.Close()
+
+	liveAddr, err := net.ResolveTCPAddr("tcp", addr)
+	require.NoError(err)
+
+	catalog := &testDiscoveryCatalog{
+		dcs: []string{"dc1", "dc2"},
+		services: map[string][]*consulapi.CatalogService{
+			"dc1": {{Address: "127.0.0.1", ServicePort: deadAddr.Port}},
+			"dc2": {{Address: "127.0.0.1", ServicePort: liveAddr.Port}},
+		},
+	}
+
+	conf := config.DefaultConfig()
+	conf.DevMode = true
+	conf.Node = &structs.Node{Reserved: &structs.Resources{}}
+	conf.VaultConfig.Enabled = helper.BoolToPtr(false)
+	conf.ConsulConfig.ClientAutoJoin = helper.BoolToPtr(false)
+
+	logger := testlog.Logger(t)
+	mockService := consulApi.NewMockConsulServiceClient(t)
+	mockService.Logger = logger
+	c, err := NewClient(conf, catalog, mockService, logger)
+	require.NoError(err)
+	defer c.Shutdown()
+
+	require.NoError(c.consulDiscoveryImpl())
+
+	servers := c.GetServers()
+	require.Len(servers, 1)
+	require.Equal(addr, servers[0])
+}
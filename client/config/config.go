@@ -73,6 +73,14 @@ type Config struct {
 	// AllocDir is where we store data for allocations
 	AllocDir string
 
+	// PluginDir is where the client looks for external driver and device
+	// plugins
+	PluginDir string
+
+	// PluginConfigs are the arguments and configuration of the plugins in
+	// the plugin directory, keyed by the name of the plugin binary
+	PluginConfigs []*config.PluginConfig
+
 	// LogOutput is the destination for logs
 	LogOutput io.Writer
 
@@ -175,6 +183,10 @@ type Config struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int
 
+	// GCMaxAge is the duration after which terminal allocations are garbage
+	// collected regardless of the other thresholds. Zero disables it.
+	GCMaxAge time.Duration
+
 	// LogLevel is the level of the logs to putout
 	LogLevel string
 
@@ -205,6 +217,28 @@ type Config struct {
 	// This period is meant to be long enough for a leader election to take
 	// place, and a small jitter is applied to avoid a thundering herd.
 	RPCHoldTimeout time.Duration
+
+	// HostVolumes is a map of the configured host volumes by name.
+	HostVolumes map[string]*structs.ClientHostVolumeConfig
+
+	// HostNetworks is a map of the configured host networks by name.
+	HostNetworks map[string]*structs.ClientHostNetworkConfig
+
+	// CNIPath is the directory of the CNI plugins used to set up the
+	// networks of the allocations.
+	CNIPath string
+
+	// CNIConfigDir is the directory of the CNI network configurations that
+	// can be used by task groups in the "cni/<name>" network mode.
+	CNIConfigDir string
+
+	// BridgeNetworkName is the name of the bridge created by the CNI plugins
+	// for the task groups in bridge network mode.
+	BridgeNetworkName string
+
+	// BridgeNetworkSubnet is the subnet the addresses of the allocations in
+	// bridge network mode are allocated from.
+	BridgeNetworkSubnet string
 }
 
 func (c *Config) Copy() *Config {
@@ -216,6 +250,14 @@ func (c *Config) Copy() *Config {
 	nc.GloballyReservedPorts = helper.CopySliceInt(c.GloballyReservedPorts)
 	nc.ConsulConfig = c.ConsulConfig.Copy()
 	nc.VaultConfig = c.VaultConfig.Copy()
+	nc.HostVolumes = structs.CopyMapStringClientHostVolumeConfig(c.HostVolumes)
+	nc.HostNetworks = structs.CopyMapStringClientHostNetworkConfig(c.HostNetworks)
+	if c.PluginConfigs != nil {
+		nc.PluginConfigs = make([]*config.PluginConfig, len(c.PluginConfigs))
+		for i, p := range c.PluginConfigs {
+			nc.PluginConfigs[i] = p.Copy()
+		}
+	}
 	return nc
 }
 
@@ -239,6 +281,10 @@ func DefaultConfig() *Config {
 		DisableTaggedMetrics:       false,
 		BackwardsCompatibleMetrics: false,
 		RPCHoldTimeout:             5 * time.Second,
+		CNIPath:                    "/opt/cni/bin",
+		CNIConfigDir:               "/opt/cni/config",
+		BridgeNetworkName:          "nomad",
+		BridgeNetworkSubnet:        "172.26.64.0/20",
 	}
 }
 
@@ -333,14 +379,15 @@ func (c *Config) ReadStringListToMap(key string) map[string]struct{} {
 	list := make(map[string]struct{})
 	if s != "" {
 		for _, e := range strings.Split(s, ",") {
-			trimmed := strings.TrimSpace(e)
-			list[trimmed] = struct{}{}
+			if trimmed := strings.TrimSpace(e); trimmed != "" {
+				list[trimmed] = struct{}{}
+			}
 		}
 	}
 	return list
 }
 
-// ReadStringListToMap tries to parse the specified option as a comma separated list.
+// ReadStringListToMapDefault tries to parse the specified option as a comma separated list.
 // If there is an error in parsing, an empty list is returned.
 func (c *Config) ReadStringListToMapDefault(key, defaultValue string) map[string]struct{} {
 	val, ok := c.Options[key]
@@ -351,8 +398,9 @@ func (c *Config) ReadStringListToMapDefault(key, defaultValue string) map[string
 	list := make(map[string]struct{})
 	if val != "" {
 		for _, e := range strings.Split(val, ",") {
-			trimmed := strings.TrimSpace(e)
-			list[trimmed] = struct{}{}
+			if trimmed := strings.TrimSpace(e); trimmed != "" {
+				list[trimmed] = struct{}{}
+			}
 		}
 	}
 	return list
@@ -1,6 +1,9 @@
 package config
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestConfigRead(t *testing.T) {
 	config := Config{}
@@ -34,3 +37,24 @@ func TestConfigReadDefault(t *testing.T) {
 		t.Errorf("Expected %s, found %s", expected, actual)
 	}
 }
+
+func TestConfigReadStringListToMap(t *testing.T) {
+	config := Config{}
+	config.Options = map[string]string{"drivers": " docker, exec ,,raw_exec, "}
+
+	expected := map[string]struct{}{
+		"docker":   {},
+		"exec":     {},
+		"raw_exec": {},
+	}
+	actual := config.ReadStringListToMap("drivers")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, found %v", expected, actual)
+	}
+
+	actual = config.ReadStringListToMapDefault("missing", "docker,,exec")
+	delete(expected, "raw_exec")
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v, found %v", expected, actual)
+	}
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/devices/gpu/nvidia"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/device"
+)
+
+// builtinDevicePlugins is the set of device plugins that ship with the client
+var builtinDevicePlugins = map[string]func(hclog.Logger) device.DevicePlugin{
+	"nvidia-gpu": func(l hclog.Logger) device.DevicePlugin { return nvidia.NewNvidiaDevice(l) },
+}
+
+// DeviceManager runs the device plugins of the client and updates the node
+// with the devices they fingerprint.
+type DeviceManager struct {
+	// plugins are the configured device plugins keyed by name
+	plugins map[string]device.DevicePlugin
+
+	// devices stores the last fingerprinted devices of each plugin
+	devices     map[string][]*structs.NodeDeviceResource
+	devicesLock sync.Mutex
+
+	// updateNodeFromDevices is a callback to the client to update the devices
+	// of the node
+	updateNodeFromDevices func([]*structs.NodeDeviceResource) *structs.Node
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger *log.Logger
+}
+
+// NewDeviceManager returns a device manager for the built-in device plugins
+// that are enabled by the client configuration. Plugins can be disabled with
+// the "device.blacklist" client option.
+func NewDeviceManager(cfg *config.Config,
+	updateNodeFromDevices func([]*structs.NodeDeviceResource) *structs.Node,
+	logger *log.Logger) *DeviceManager {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	dm := &DeviceManager{
+		plugins:               make(map[string]device.DevicePlugin),
+		devices:               make(map[string][]*structs.NodeDeviceResource),
+		updateNodeFromDevices: updateNodeFromDevices,
+		ctx:                   ctx,
+		cancel:                cancel,
+		logger:                logger,
+	}
+
+	blacklist := cfg.ReadStringListToMap("device.blacklist")
+	pluginLogger := hclog.New(&hclog.LoggerOptions{
+		Name:   "client.device_manager",
+		Level:  hclog.LevelFromString(cfg.LogLevel),
+		Output: cfg.LogOutput,
+	})
+	for name, factory := range builtinDevicePlugins {
+		if _, ok := blacklist[name]; ok {
+			logger.Printf("[DEBUG] client.device_manager: device plugin %q disabled", name)
+			continue
+		}
+		dm.plugins[name] = factory(pluginLogger)
+	}
+
+	return dm
+}
+
+// Run configures the device plugins and starts fingerprinting devices. A
+// plugin that fails to configure is skipped.
+func (dm *DeviceManager) Run() {
+	for name, plugin := range dm.plugins {
+		if err := plugin.SetConfig(nil); err != nil {
+			dm.logger.Printf("[WARN] client.device_manager: failed to configure device plugin %q: %v", name, err)
+			continue
+		}
+
+		outCh, err := plugin.Fingerprint(dm.ctx)
+		if err != nil {
+			dm.logger.Printf("[WARN] client.device_manager: failed to fingerprint devices of plugin %q: %v", name, err)
+			continue
+		}
+
+		go dm.watchFingerprint(name, outCh)
+	}
+}
+
+// Shutdown stops fingerprinting devices.
+func (dm *DeviceManager) Shutdown() {
+	dm.cancel()
+}
+
+// Reserve reserves the assigned device instances with the plugin that
+// fingerprinted them and returns how to expose them to the task.
+func (dm *DeviceManager) Reserve(d *structs.AllocatedDeviceResource) (*device.ContainerReservation, error) {
+	id := d.ID()
+
+	var plugin device.DevicePlugin
+	dm.devicesLock.Lock()
+	for name, devices := range dm.devices {
+		for _, res := range devices {
+			if id.Matches(res.ID()) {
+				plugin = dm.plugins[name]
+			}
+		}
+	}
+	dm.devicesLock.Unlock()
+
+	if plugin == nil {
+		return nil, fmt.Errorf("no device plugin fingerprinted devices %q", id)
+	}
+	return plugin.Reserve(d.DeviceIDs)
+}
+
+// watchFingerprint consumes the fingerprints of a plugin until the stream
+// ends.
+func (dm *DeviceManager) watchFingerprint(name string, outCh <-chan *device.FingerprintResponse) {
+	for resp := range outCh {
+		if resp.Error != nil {
+			// Plugins whose devices are missing on the host stop streaming
+			// after reporting the error
+			dm.logger.Printf("[DEBUG] client.device_manager: device plugin %q failed to fingerprint: %v", name, resp.Error)
+			dm.setDevices(name, nil)
+			continue
+		}
+
+		dm.setDevices(name, convertDeviceGroups(resp.Devices))
+	}
+}
+
+// setDevices stores the devices of a plugin and updates the node with the
+// devices of all plugins.
+func (dm *DeviceManager) setDevices(name string, devices []*structs.NodeDeviceResource) {
+	dm.devicesLock.Lock()
+	if len(devices) == 0 {
+		delete(dm.devices, name)
+	} else {
+		dm.devices[name] = devices
+	}
+
+	names := make([]string, 0, len(dm.devices))
+	for n := range dm.devices {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var all []*structs.NodeDeviceResource
+	for _, n := range names {
+		all = append(all, dm.devices[n]...)
+	}
+	dm.devicesLock.Unlock()
+
+	dm.updateNodeFromDevices(all)
+}
+
+// convertDeviceGroups converts the device groups of a plugin to the devices
+// of a node.
+func convertDeviceGroups(groups []*device.DeviceGroup) []*structs.NodeDeviceResource {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	out := make([]*structs.NodeDeviceResource, 0, len(groups))
+	for _, group := range groups {
+		res := &structs.NodeDeviceResource{
+			Vendor:     group.Vendor,
+			Type:       group.Type,
+			Name:       group.Name,
+			Instances:  make([]*structs.NodeDevice, 0, len(group.Devices)),
+			Attributes: group.Attributes,
+		}
+
+		for _, d := range group.Devices {
+			instance := &structs.NodeDevice{
+				ID:                d.ID,
+				Healthy:           d.Healthy,
+				HealthDescription: d.HealthDesc,
+			}
+			if d.HwLocality != nil {
+				instance.Locality = &structs.NodeDeviceLocality{
+					PciBusID: d.HwLocality.PciBusID,
+				}
+			}
+			res.Instances = append(res.Instances, instance)
+		}
+
+		out = append(out, res)
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceManager_Blacklist(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	cfg := config.DefaultConfig()
+	cfg.Options = map[string]string{"device.blacklist": "nvidia-gpu"}
+
+	dm := NewDeviceManager(cfg, nil, testlog.Logger(t))
+	require.Empty(dm.plugins)
+
+	cfg.Options = nil
+	dm = NewDeviceManager(cfg, nil, testlog.Logger(t))
+	require.Contains(dm.plugins, "nvidia-gpu")
+}
+
+func TestDeviceManager_SetDevices(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	var updated []*structs.NodeDeviceResource
+	update := func(devices []*structs.NodeDeviceResource) *structs.Node {
+		updated = devices
+		return nil
+	}
+
+	dm := NewDeviceManager(config.DefaultConfig(), update, testlog.Logger(t))
+
+	groups := []*device.DeviceGroup{
+		{
+			Vendor: "nvidia",
+			Type:   "gpu",
+			Name:   "Tesla K80",
+			Devices: []*device.Device{
+				{
+					ID:         "GPU-1",
+					Healthy:    true,
+					HwLocality: &device.DeviceLocality{PciBusID: "0000:04"},
+				},
+				{
+					ID:         "GPU-2",
+					HealthDesc: "overheating",
+				},
+			},
+			Attributes: map[string]string{"memory": "11441"},
+		},
+	}
+	dm.setDevices("b", convertDeviceGroups(groups))
+	require.Len(updated, 1)
+
+	res := updated[0]
+	require.Equal("nvidia/gpu/Tesla K80", res.ID().String())
+	require.Equal("11441", res.Attributes["memory"])
+	require.Len(res.Instances, 2)
+	require.Equal("0000:04", res.Instances[0].Locality.PciBusID)
+	require.False(res.Instances[1].Healthy)
+	require.Equal("overheating", res.Instances[1].HealthDescription)
+	require.Equal(1, res.HealthyInstances())
+
+	// Devices of all plugins are reported, ordered by plugin name
+	dm.setDevices("a", []*structs.NodeDeviceResource{{Vendor: "intel", Type: "fpga", Name: "arria"}})
+	require.Len(updated, 2)
+	require.Equal("intel", updated[0].Vendor)
+
+	// A plugin without devices is removed
+	dm.setDevices("b", nil)
+	require.Len(updated, 1)
+	require.Equal("intel", updated[0].Vendor)
+}
+
+func TestDeviceManager_Reserve(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	update := func([]*structs.NodeDeviceResource) *structs.Node { return nil }
+	dm := NewDeviceManager(config.DefaultConfig(), update, testlog.Logger(t))
+
+	var reserved []string
+	dm.plugins["mock"] = &device.MockDevicePlugin{
+		ReserveF: func(ids []string) (*device.ContainerReservation, error) {
+			reserved = ids
+			return &device.ContainerReservation{
+				Envs: map[string]string{"MOCK_DEVICES": "1"},
+			}, nil
+		},
+	}
+	dm.setDevices("mock", []*structs.NodeDeviceResource{{Vendor: "mock", Type: "gpu", Name: "m1"}})
+
+	res, err := dm.Reserve(&structs.AllocatedDeviceResource{
+		Vendor:    "mock",
+		Type:      "gpu",
+		Name:      "m1",
+		DeviceIDs: []string{"1", "2"},
+	})
+	require.NoError(err)
+	require.Equal([]string{"1", "2"}, reserved)
+	require.Equal("1", res.Envs["MOCK_DEVICES"])
+
+	// Devices no plugin fingerprinted can't be reserved
+	_, err = dm.Reserve(&structs.AllocatedDeviceResource{Vendor: "mock", Type: "gpu", Name: "m2"})
+	require.Error(err)
+	require.Contains(err.Error(), "mock/gpu/m2")
+}
@@ -477,12 +477,26 @@ type dockerPID struct {
 	ContainerID    string
 	KillTimeout    time.Duration
 	MaxKillTimeout time.Duration
-	PluginConfig   *PluginReattachConfig
+
+	// PluginConfig is only set for containers started by older clients,
+	// whose logs are collected by an executor.
+	PluginConfig *PluginReattachConfig
+
+	// LogConfig is set if the driver collects the container's logs itself
+	// rather than through a logging driver configured by the job.
+	LogConfig *structs.LogConfig
+
+	// LogsSince is the timestamp, as Unix time in nanoseconds, of the last
+	// log line collected, so the logs are resumed from there when
+	// re-attaching.
+	LogsSince int64
+
+	// LogsTTY is set if the container was started with a TTY and its logs
+	// are a single raw stream.
+	LogsTTY bool
 }
 
 type DockerHandle struct {
-	pluginClient          *plugin.Client
-	executor              executor.Executor
 	client                *docker.Client
 	waitClient            *docker.Client
 	logger                *log.Logger
@@ -500,6 +514,15 @@ type DockerHandle struct {
 	waitCh                chan *dstructs.WaitResult
 	doneCh                chan bool
 	removeContainerOnExit bool
+	logConfig             *structs.LogConfig
+	logCollector          *dockerLogCollector
+
+	// executor and pluginClient are only set for containers started by
+	// older clients, which ran an executor to collect the container's logs
+	// over syslog. The executor keeps collecting them until the container
+	// exits and is then shut down.
+	executor     executor.Executor
+	pluginClient *plugin.Client
 }
 
 func NewDockerDriver(ctx *DriverContext) Driver {
@@ -766,8 +789,11 @@ func (d *DockerDriver) Validate(config map[string]interface{}) error {
 
 func (d *DockerDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: true,
-		Exec:        true,
+		SendSignals:  true,
+		Exec:         true,
+		NetNS:        false,
+		VolumeMounts: true,
+		Devices:      true,
 	}
 }
 
@@ -823,50 +849,13 @@ func (d *DockerDriver) Prestart(ctx *ExecContext, task *structs.Task) (*Prestart
 }
 
 func (d *DockerDriver) Start(ctx *ExecContext, task *structs.Task) (*StartResponse, error) {
-	pluginLogFile := filepath.Join(ctx.TaskDir.Dir, "executor.out")
-	executorConfig := &dstructs.ExecutorConfig{
-		LogFile:  pluginLogFile,
-		LogLevel: d.config.LogLevel,
-	}
+	// The user hasn't specified any logging options so collect the
+	// container's logs into the task's log directory ourselves.
+	collectLogs := len(d.driverConfig.Logging) == 0
 
-	exec, pluginClient, err := createExecutor(d.config.LogOutput, d.config, executorConfig)
-	if err != nil {
-		return nil, err
-	}
-	executorCtx := &executor.ExecutorContext{
-		TaskEnv:        ctx.TaskEnv,
-		Task:           task,
-		Driver:         "docker",
-		LogDir:         ctx.TaskDir.LogDir,
-		TaskDir:        ctx.TaskDir.Dir,
-		PortLowerBound: d.config.ClientMinPort,
-		PortUpperBound: d.config.ClientMaxPort,
-	}
-	if err := exec.SetContext(executorCtx); err != nil {
-		pluginClient.Kill()
-		return nil, fmt.Errorf("failed to set executor context: %v", err)
-	}
-
-	// The user hasn't specified any logging options so launch our own syslog
-	// server if possible.
-	syslogAddr := ""
-	if len(d.driverConfig.Logging) == 0 {
-		if runtime.GOOS == "darwin" {
-			d.logger.Printf("[DEBUG] driver.docker: disabling syslog driver as Docker for Mac workaround")
-		} else {
-			ss, err := exec.LaunchSyslogServer()
-			if err != nil {
-				pluginClient.Kill()
-				return nil, fmt.Errorf("failed to start syslog collector: %v", err)
-			}
-			syslogAddr = ss.Addr
-		}
-	}
-
-	config, err := d.createContainerConfig(ctx, task, d.driverConfig, syslogAddr)
+	config, err := d.createContainerConfig(ctx, task, d.driverConfig)
 	if err != nil {
 		d.logger.Printf("[ERR] driver.docker: failed to create container configuration for image %q (%q): %v", d.driverConfig.ImageName, d.imageID, err)
-		pluginClient.Kill()
 		return nil, fmt.Errorf("Failed to create container configuration for image %q (%q): %v", d.driverConfig.ImageName, d.imageID, err)
 	}
 
@@ -874,7 +863,6 @@ func (d *DockerDriver) Start(ctx *ExecContext, task *structs.Task) (*StartRespon
 	if err != nil {
 		wrapped := fmt.Sprintf("Failed to create container: %v", err)
 		d.logger.Printf("[ERR] driver.docker: %s", wrapped)
-		pluginClient.Kill()
 		return nil, structs.WrapRecoverable(wrapped, err)
 	}
 
@@ -887,7 +875,6 @@ func (d *DockerDriver) Start(ctx *ExecContext, task *structs.Task) (*StartRespon
 		// Start the container
 		if err := d.startContainer(container); err != nil {
 			d.logger.Printf("[ERR] driver.docker: failed to start container %s: %s", container.ID, err)
-			pluginClient.Kill()
 			return nil, structs.NewRecoverableError(fmt.Errorf("Failed to start container %s: %s", container.ID, err), structs.IsRecoverable(err))
 		}
 
@@ -898,7 +885,6 @@ func (d *DockerDriver) Start(ctx *ExecContext, task *structs.Task) (*StartRespon
 		if err != nil {
 			err = fmt.Errorf("failed to inspect started container %s: %s", container.ID, err)
 			d.logger.Printf("[ERR] driver.docker: %v", err)
-			pluginClient.Kill()
 			return nil, structs.NewRecoverableError(err, true)
 		}
 		container = runningContainer
@@ -913,8 +899,6 @@ func (d *DockerDriver) Start(ctx *ExecContext, task *structs.Task) (*StartRespon
 	h := &DockerHandle{
 		client:                client,
 		waitClient:            waitClient,
-		executor:              exec,
-		pluginClient:          pluginClient,
 		logger:                d.logger,
 		jobName:               d.DriverContext.jobName,
 		taskGroupName:         d.DriverContext.taskGroupName,
@@ -929,6 +913,21 @@ func (d *DockerDriver) Start(ctx *ExecContext, task *structs.Task) (*StartRespon
 		waitCh:                make(chan *dstructs.WaitResult, 1),
 		removeContainerOnExit: d.config.ReadBoolDefault(dockerCleanupContainerConfigOption, dockerCleanupContainerConfigDefault),
 	}
+
+	// Follow the container's logs through the Docker API so they are written
+	// to the task's log directory
+	if collectLogs {
+		// The container was just started, so collect all of its logs
+		lc, err := newDockerLogCollector(client, container.ID, ctx.TaskDir.LogDir, task.Name, task.LogConfig,
+			d.driverConfig.TTY, 0, d.logger)
+		if err != nil {
+			d.logger.Printf("[ERR] driver.docker: failed to start log collector for container %s: %v", container.ID, err)
+		} else {
+			h.logConfig = task.LogConfig
+			h.logCollector = lc
+		}
+	}
+
 	go h.collectStats()
 	go h.run()
 
@@ -1171,6 +1170,16 @@ func (d *DockerDriver) containerBinds(driverConfig *DockerDriverConfig, ctx *Exe
 		binds = append(binds, strings.Join(parts, ":"))
 	}
 
+	// Volumes of the task group are declared by the operator so they are
+	// mounted even if arbitrary host paths can't be
+	for _, m := range ctx.VolumeMounts {
+		bind := fmt.Sprintf("%s:%s", m.HostPath, filepath.Join("/", m.TaskPath))
+		if m.ReadOnly {
+			bind += ":ro"
+		}
+		binds = append(binds, bind)
+	}
+
 	if selinuxLabel := d.config.Read(dockerSELinuxLabelConfigOption); selinuxLabel != "" {
 		// Apply SELinux Label to each volume
 		for i := range binds {
@@ -1183,7 +1192,7 @@ func (d *DockerDriver) containerBinds(driverConfig *DockerDriverConfig, ctx *Exe
 
 // createContainerConfig initializes a struct needed to call docker.client.CreateContainer()
 func (d *DockerDriver) createContainerConfig(ctx *ExecContext, task *structs.Task,
-	driverConfig *DockerDriverConfig, syslogAddr string) (docker.CreateContainerOptions, error) {
+	driverConfig *DockerDriverConfig) (docker.CreateContainerOptions, error) {
 	var c docker.CreateContainerOptions
 	if task.Resources == nil {
 		// Guard against missing resources. We should never have been able to
@@ -1213,22 +1222,34 @@ func (d *DockerDriver) createContainerConfig(ctx *ExecContext, task *structs.Tas
 		config.WorkingDir = driverConfig.WorkDir
 	}
 
+	// Tasks with a memory max may use memory above their reservation, which
+	// becomes the soft limit of the container
 	memLimit := int64(task.Resources.MemoryMB) * 1024 * 1024
+	var memReservation int64
+	if task.Resources.MemoryMaxMB > task.Resources.MemoryMB {
+		memReservation = memLimit
+		memLimit = int64(task.Resources.MemoryMaxMB) * 1024 * 1024
+	}
 
+	// Without logging options the log collector follows the container's
+	// logs, so the daemon only has to keep a bounded buffer of them
 	if len(driverConfig.Logging) == 0 {
-		if runtime.GOOS == "darwin" {
-			d.logger.Printf("[DEBUG] driver.docker: deferring logging to docker on Docker for Mac")
-		} else {
-			d.logger.Printf("[DEBUG] driver.docker: Setting default logging options to syslog and %s", syslogAddr)
-			driverConfig.Logging = []DockerLoggingOpts{
-				{Type: "syslog", Config: map[string]string{"syslog-address": syslogAddr}},
-			}
+		d.logger.Printf("[DEBUG] driver.docker: setting default logging options to bounded %s", dockerLogDriver)
+		driverConfig.Logging = []DockerLoggingOpts{
+			{
+				Type: dockerLogDriver,
+				Config: map[string]string{
+					"max-file": dockerLogDriverMaxFiles,
+					"max-size": dockerLogDriverMaxSize,
+				},
+			},
 		}
 	}
 
 	hostConfig := &docker.HostConfig{
 		// Convert MB to bytes. This is an absolute value.
-		Memory: memLimit,
+		Memory:            memLimit,
+		MemoryReservation: memReservation,
 		// Convert Mhz to shares. This is a relative value.
 		CPUShares: int64(task.Resources.CPU),
 
@@ -1247,16 +1268,14 @@ func (d *DockerDriver) createContainerConfig(ctx *ExecContext, task *structs.Tas
 	// multiply the time by the number of cores available
 	// See https://access.redhat.com/documentation/en-us/red_hat_enterprise_linux/6/html/resource_management_guide/sec-cpu
 	if driverConfig.CPUHardLimit {
-		numCores := runtime.NumCPU()
-		percentTicks := float64(task.Resources.CPU) / float64(d.node.Resources.CPU)
-		if driverConfig.CPUCFSPeriod < 0 || driverConfig.CPUCFSPeriod > 1000000 {
-			return c, fmt.Errorf("invalid value for cpu_cfs_period")
-		}
-		if driverConfig.CPUCFSPeriod == 0 {
-			driverConfig.CPUCFSPeriod = defaultCFSPeriodUS
+		quota, period, err := cpuHardLimit(task.Resources.CPU, d.node.Resources.CPU,
+			driverConfig.CPUCFSPeriod, runtime.NumCPU())
+		if err != nil {
+			return c, err
 		}
-		hostConfig.CPUPeriod = driverConfig.CPUCFSPeriod
-		hostConfig.CPUQuota = int64(percentTicks*float64(driverConfig.CPUCFSPeriod)) * int64(numCores)
+		driverConfig.CPUCFSPeriod = period
+		hostConfig.CPUPeriod = period
+		hostConfig.CPUQuota = quota
 	}
 
 	// Windows does not support MemorySwap/MemorySwappiness #2193
@@ -1278,7 +1297,7 @@ func (d *DockerDriver) createContainerConfig(ctx *ExecContext, task *structs.Tas
 	d.logger.Printf("[DEBUG] driver.docker: using %d bytes memory for %s", hostConfig.Memory, task.Name)
 	d.logger.Printf("[DEBUG] driver.docker: using %d cpu shares for %s", hostConfig.CPUShares, task.Name)
 	if driverConfig.CPUHardLimit {
-		d.logger.Printf("[DEBUG] driver.docker: using %dms cpu quota and %dms cpu period for %s", hostConfig.CPUQuota, defaultCFSPeriodUS, task.Name)
+		d.logger.Printf("[DEBUG] driver.docker: using %dus cpu quota and %dus cpu period for %s", hostConfig.CPUQuota, hostConfig.CPUPeriod, task.Name)
 	}
 	d.logger.Printf("[DEBUG] driver.docker: binding directories %#v for %s", hostConfig.Binds, task.Name)
 
@@ -1348,6 +1367,15 @@ func (d *DockerDriver) createContainerConfig(ctx *ExecContext, task *structs.Tas
 		hostConfig.Devices = devices
 	}
 
+	// Expose the devices reserved by device plugins
+	for _, device := range ctx.Devices {
+		hostConfig.Devices = append(hostConfig.Devices, docker.Device{
+			PathOnHost:        device.HostPath,
+			PathInContainer:   device.TaskPath,
+			CgroupPermissions: device.CgroupPerms,
+		})
+	}
+
 	// Setup mounts
 	for _, m := range driverConfig.Mounts {
 		hm := docker.HostMount{
@@ -1423,9 +1451,9 @@ func (d *DockerDriver) createContainerConfig(ctx *ExecContext, task *structs.Tas
 			hostPortStr := strconv.Itoa(port.Value)
 			containerPort := docker.Port(strconv.Itoa(containerPortInt))
 
-			publishedPorts[containerPort+"/tcp"] = getPortBinding(network.IP, hostPortStr)
-			publishedPorts[containerPort+"/udp"] = getPortBinding(network.IP, hostPortStr)
-			d.logger.Printf("[DEBUG] driver.docker: allocated port %s:%d -> %d (static)", network.IP, port.Value, port.Value)
+			publishedPorts[containerPort+"/tcp"] = getPortBinding(network.PortIP(port), hostPortStr)
+			publishedPorts[containerPort+"/udp"] = getPortBinding(network.PortIP(port), hostPortStr)
+			d.logger.Printf("[DEBUG] driver.docker: allocated port %s:%d -> %d (static)", network.PortIP(port), port.Value, port.Value)
 
 			exposedPorts[containerPort+"/tcp"] = struct{}{}
 			exposedPorts[containerPort+"/udp"] = struct{}{}
@@ -1444,9 +1472,9 @@ func (d *DockerDriver) createContainerConfig(ctx *ExecContext, task *structs.Tas
 			hostPortStr := strconv.Itoa(port.Value)
 			containerPort := docker.Port(strconv.Itoa(containerPortInt))
 
-			publishedPorts[containerPort+"/tcp"] = getPortBinding(network.IP, hostPortStr)
-			publishedPorts[containerPort+"/udp"] = getPortBinding(network.IP, hostPortStr)
-			d.logger.Printf("[DEBUG] driver.docker: allocated port %s:%d -> %d (mapped)", network.IP, port.Value, containerPortInt)
+			publishedPorts[containerPort+"/tcp"] = getPortBinding(network.PortIP(port), hostPortStr)
+			publishedPorts[containerPort+"/udp"] = getPortBinding(network.PortIP(port), hostPortStr)
+			d.logger.Printf("[DEBUG] driver.docker: allocated port %s:%d -> %d (mapped)", network.PortIP(port), port.Value, containerPortInt)
 
 			exposedPorts[containerPort+"/tcp"] = struct{}{}
 			exposedPorts[containerPort+"/udp"] = struct{}{}
@@ -1747,9 +1775,6 @@ func (d *DockerDriver) Open(ctx *ExecContext, handleID string) (DriverHandle, er
 	}
 	d.logger.Printf("[INFO] driver.docker: re-attaching to docker process: %s", pid.ContainerID)
 	d.logger.Printf("[DEBUG] driver.docker: re-attached to handle: %s", handleID)
-	pluginConfig := &plugin.ClientConfig{
-		Reattach: pid.PluginConfig.PluginConfig(),
-	}
 
 	client, waitClient, err := d.dockerClients()
 	if err != nil {
@@ -1775,18 +1800,28 @@ func (d *DockerDriver) Open(ctx *ExecContext, handleID string) (DriverHandle, er
 	if !found {
 		return nil, fmt.Errorf("Failed to find container %s", pid.ContainerID)
 	}
-	exec, pluginClient, err := createExecutorWithConfig(pluginConfig, d.config.LogOutput)
-	if err != nil {
-		d.logger.Printf("[INFO] driver.docker: couldn't re-attach to the plugin process: %v", err)
-		d.logger.Printf("[DEBUG] driver.docker: stopping container %q", pid.ContainerID)
-		if e := client.StopContainer(pid.ContainerID, uint(pid.KillTimeout.Seconds())); e != nil {
-			d.logger.Printf("[DEBUG] driver.docker: couldn't stop container: %v", e)
+
+	// Containers started by older clients log to the syslog server of an
+	// executor, so re-attach to it to keep their logs
+	var exec executor.Executor
+	var pluginClient *plugin.Client
+	if pid.PluginConfig != nil {
+		pluginConfig := &plugin.ClientConfig{
+			Reattach: pid.PluginConfig.PluginConfig(),
+		}
+		exec, pluginClient, err = createExecutorWithConfig(pluginConfig, d.config.LogOutput)
+		if err != nil {
+			d.logger.Printf("[INFO] driver.docker: couldn't re-attach to the plugin process: %v", err)
+			d.logger.Printf("[DEBUG] driver.docker: stopping container %q", pid.ContainerID)
+			if e := client.StopContainer(pid.ContainerID, uint(pid.KillTimeout.Seconds())); e != nil {
+				d.logger.Printf("[DEBUG] driver.docker: couldn't stop container: %v", e)
+			}
+			return nil, err
 		}
-		return nil, err
-	}
 
-	ver, _ := exec.Version()
-	d.logger.Printf("[DEBUG] driver.docker: version of executor: %v", ver.Version)
+		ver, _ := exec.Version()
+		d.logger.Printf("[DEBUG] driver.docker: version of executor: %v", ver.Version)
+	}
 
 	// Increment the reference count since we successfully attached to this
 	// container
@@ -1812,6 +1847,18 @@ func (d *DockerDriver) Open(ctx *ExecContext, handleID string) (DriverHandle, er
 		doneCh:         make(chan bool),
 		waitCh:         make(chan *dstructs.WaitResult, 1),
 	}
+
+	if pid.LogConfig != nil {
+		lc, err := newDockerLogCollector(client, pid.ContainerID, ctx.TaskDir.LogDir, d.DriverContext.taskName,
+			pid.LogConfig, pid.LogsTTY, pid.LogsSince, d.logger)
+		if err != nil {
+			d.logger.Printf("[ERR] driver.docker: failed to start log collector for container %s: %v", pid.ContainerID, err)
+		} else {
+			h.logConfig = pid.LogConfig
+			h.logCollector = lc
+		}
+	}
+
 	go h.collectStats()
 	go h.run()
 	return h, nil
@@ -1826,7 +1873,14 @@ func (h *DockerHandle) ID() string {
 		ImageID:        h.ImageID,
 		KillTimeout:    h.killTimeout,
 		MaxKillTimeout: h.maxKillTimeout,
-		PluginConfig:   NewPluginReattachConfig(h.pluginClient.ReattachConfig()),
+		LogConfig:      h.logConfig,
+	}
+	if h.pluginClient != nil {
+		pid.PluginConfig = NewPluginReattachConfig(h.pluginClient.ReattachConfig())
+	}
+	if h.logCollector != nil {
+		pid.LogsSince = h.logCollector.LastRead()
+		pid.LogsTTY = h.logCollector.tty
 	}
 	data, err := json.Marshal(pid)
 	if err != nil {
@@ -1846,8 +1900,10 @@ func (h *DockerHandle) WaitCh() chan *dstructs.WaitResult {
 func (h *DockerHandle) Update(task *structs.Task) error {
 	// Store the updated kill timeout.
 	h.killTimeout = GetKillTimeout(task.KillTimeout, h.maxKillTimeout)
-	if err := h.executor.UpdateTask(task); err != nil {
-		h.logger.Printf("[DEBUG] driver.docker: failed to update log config: %v", err)
+	if h.executor != nil {
+		if err := h.executor.UpdateTask(task); err != nil {
+			h.logger.Printf("[DEBUG] driver.docker: failed to update log config: %v", err)
+		}
 	}
 
 	// Update is not possible
@@ -1890,6 +1946,73 @@ func (h *DockerHandle) Exec(ctx context.Context, cmd string, args []string) ([]b
 	return output.Bytes(), res.ExitCode, nil
 }
 
+// ExecStreaming runs a command inside the container, streaming its input and
+// output. It returns the exit code of the command once it exits.
+func (h *DockerHandle) ExecStreaming(ctx context.Context, opts *ExecStreamingOptions) (int, error) {
+	if len(opts.Command) == 0 {
+		return 0, fmt.Errorf("command is required")
+	}
+
+	createExecOpts := docker.CreateExecOptions{
+		AttachStdin:  opts.Stdin != nil,
+		AttachStdout: true,
+		AttachStderr: true,
+		Tty:          opts.Tty,
+		Cmd:          opts.Command,
+		Container:    h.containerID,
+		Context:      ctx,
+	}
+	exec, err := h.client.CreateExec(createExecOpts)
+	if err != nil {
+		return 0, err
+	}
+
+	// Forward terminal resizes until the command exits
+	if opts.Tty && opts.ResizeCh != nil {
+		resizeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			for {
+				select {
+				case <-resizeCtx.Done():
+					return
+				case size, ok := <-opts.ResizeCh:
+					if !ok {
+						return
+					}
+					if err := h.client.ResizeExecTTY(exec.ID, size.Height, size.Width); err != nil {
+						h.logger.Printf("[WARN] driver.docker: failed to resize tty of exec %s: %v", exec.ID, err)
+					}
+				}
+			}
+		}()
+	}
+
+	stderr := opts.Stderr
+	if opts.Tty {
+		stderr = opts.Stdout
+	}
+
+	startOpts := docker.StartExecOptions{
+		Detach:       false,
+		Tty:          opts.Tty,
+		RawTerminal:  opts.Tty,
+		InputStream:  opts.Stdin,
+		OutputStream: opts.Stdout,
+		ErrorStream:  stderr,
+		Context:      ctx,
+	}
+	if err := h.client.StartExec(exec.ID, startOpts); err != nil {
+		return 0, err
+	}
+
+	res, err := h.client.InspectExec(exec.ID)
+	if err != nil {
+		return 0, err
+	}
+	return res.ExitCode, nil
+}
+
 func (h *DockerHandle) Signal(s os.Signal) error {
 	// Convert types
 	sysSig, ok := s.(syscall.Signal)
@@ -1915,8 +2038,7 @@ func (h *DockerHandle) Kill() error {
 	// Stop the container
 	err := h.waitClient.StopContainer(h.containerID, uint(h.killTimeout.Seconds()))
 	if err != nil {
-		h.executor.Exit()
-		h.pluginClient.Kill()
+		h.shutdownExecutor()
 
 		// Container has already been removed.
 		if strings.Contains(err.Error(), NoSuchContainerError) {
@@ -1930,6 +2052,18 @@ func (h *DockerHandle) Kill() error {
 	return nil
 }
 
+// shutdownExecutor stops the executor collecting the logs of a container
+// started by an older client, if any.
+func (h *DockerHandle) shutdownExecutor() {
+	if h.executor == nil {
+		return
+	}
+	if err := h.executor.Exit(); err != nil {
+		h.logger.Printf("[ERR] driver.docker: failed to kill the executor: %v", err)
+	}
+	h.pluginClient.Kill()
+}
+
 func (h *DockerHandle) Stats() (*cstructs.TaskResourceUsage, error) {
 	h.resourceUsageLock.RLock()
 	defer h.resourceUsageLock.RUnlock()
@@ -1951,10 +2085,12 @@ func (h *DockerHandle) run() {
 		werr = fmt.Errorf("Docker container exited with non-zero exit code: %d", exitCode)
 	}
 
+	var oomKilled bool
 	container, ierr := h.waitClient.InspectContainer(h.containerID)
 	if ierr != nil {
 		h.logger.Printf("[ERR] driver.docker: failed to inspect container %s: %v", h.containerID, ierr)
 	} else if container.State.OOMKilled {
+		oomKilled = true
 		werr = fmt.Errorf("OOM Killed")
 		labels := []metrics.Label{
 			{
@@ -1975,11 +2111,7 @@ func (h *DockerHandle) run() {
 
 	close(h.doneCh)
 
-	// Shutdown the syslog collector
-	if err := h.executor.Exit(); err != nil {
-		h.logger.Printf("[ERR] driver.docker: failed to kill the syslog collector: %v", err)
-	}
-	h.pluginClient.Kill()
+	h.shutdownExecutor()
 
 	// Stop the container just incase the docker daemon's wait returned
 	// incorrectly
@@ -1991,6 +2123,12 @@ func (h *DockerHandle) run() {
 		}
 	}
 
+	// Flush the remaining logs of the container before it is removed along
+	// with its logs
+	if h.logCollector != nil {
+		h.logCollector.Stop(dockerLogStopTimeout)
+	}
+
 	// Remove the container
 	if h.removeContainerOnExit == true {
 		if err := h.client.RemoveContainer(docker.RemoveContainerOptions{ID: h.containerID, RemoveVolumes: true, Force: true}); err != nil {
@@ -2001,7 +2139,9 @@ func (h *DockerHandle) run() {
 	}
 
 	// Send the results
-	h.waitCh <- dstructs.NewWaitResult(exitCode, 0, werr)
+	res := dstructs.NewWaitResult(exitCode, 0, werr)
+	res.OOMKilled = oomKilled
+	h.waitCh <- res
 	close(h.waitCh)
 }
 
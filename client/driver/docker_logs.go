@@ -0,0 +1,227 @@
+package driver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync/atomic"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/client/driver/logging"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// dockerLogRetryInterval is how long the log collector waits before
+	// reconnecting to the Docker daemon if following the logs fails.
+	dockerLogRetryInterval = 5 * time.Second
+
+	// dockerLogStopTimeout is how long to wait for the remaining logs of an
+	// exited container to be written before closing the log files.
+	dockerLogStopTimeout = 5 * time.Second
+
+	// dockerLogDriver is the logging driver of containers whose logs are
+	// collected by the driver. Its files only buffer the logs until they are
+	// collected, so they are kept small: if the collector falls more than
+	// 4 MB behind, for example while the client is restarting, the older
+	// output is rotated away by the daemon and lost.
+	dockerLogDriver         = "json-file"
+	dockerLogDriverMaxFiles = "2"
+	dockerLogDriverMaxSize  = "2m"
+)
+
+// dockerLogCollector follows the stdout and stderr of a container through the
+// Docker API and writes them into the task's log directory, using the same
+// rotation policy as tasks run by the executor. It is used unless the job
+// configures its own logging driver, so task logs don't depend on the
+// daemon's own log storage.
+type dockerLogCollector struct {
+	// lastRead is the Docker timestamp, as Unix time in nanoseconds, of the
+	// last log line written. It is accessed atomically and kept first in the
+	// struct so it is 64-bit aligned.
+	lastRead int64
+
+	client      *docker.Client
+	containerID string
+	logger      *log.Logger
+
+	// tty is set if the container was started with a TTY, in which case
+	// Docker sends its output as a single raw stream.
+	tty bool
+
+	stdout *logging.FileRotator
+	stderr *logging.FileRotator
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+}
+
+// newDockerLogCollector creates the stdout and stderr log files for the task
+// in logDir and starts following the container's logs written after the given
+// Unix time in nanoseconds. A zero since collects the logs from the start of
+// the container.
+func newDockerLogCollector(client *docker.Client, containerID, logDir, taskName string,
+	logConfig *structs.LogConfig, tty bool, since int64, logger *log.Logger) (*dockerLogCollector, error) {
+
+	logFileSize := int64(logConfig.MaxFileSizeMB * 1024 * 1024)
+	stdout, err := logging.NewFileRotator(logDir, fmt.Sprintf("%v.stdout", taskName),
+		logConfig.MaxFiles, logFileSize, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error creating new stdout log file for %q: %v", taskName, err)
+	}
+
+	stderr, err := logging.NewFileRotator(logDir, fmt.Sprintf("%v.stderr", taskName),
+		logConfig.MaxFiles, logFileSize, logger)
+	if err != nil {
+		stdout.Close()
+		return nil, fmt.Errorf("error creating new stderr log file for %q: %v", taskName, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &dockerLogCollector{
+		lastRead:    since,
+		client:      client,
+		containerID: containerID,
+		logger:      logger,
+		tty:         tty,
+		stdout:      stdout,
+		stderr:      stderr,
+		cancel:      cancel,
+		doneCh:      make(chan struct{}),
+	}
+
+	go c.run(ctx)
+	return c, nil
+}
+
+// run follows the container logs until the container exits or the collector
+// is stopped. After a failure the logs are followed again from the last line
+// written so nothing in between is lost or written twice.
+func (c *dockerLogCollector) run(ctx context.Context) {
+	defer close(c.doneCh)
+
+	for {
+		// Docker only filters logs by whole seconds, so lines of the second
+		// the logs were last read in are received again and skipped by
+		// their timestamp.
+		after := c.LastRead()
+		opts := docker.LogsOptions{
+			Context:      ctx,
+			Container:    c.containerID,
+			OutputStream: &dockerLogWriter{c: c, w: c.stdout, after: after},
+			ErrorStream:  &dockerLogWriter{c: c, w: c.stderr, after: after},
+			Since:        after / int64(time.Second),
+			Follow:       true,
+			Stdout:       true,
+			Stderr:       true,
+			Timestamps:   true,
+			RawTerminal:  c.tty,
+		}
+
+		err := c.client.Logs(opts)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// The logs stream ends without an error once the container exits
+		if err == nil {
+			return
+		}
+
+		c.logger.Printf("[WARN] driver.docker: failed to collect logs of container %s: %v", c.containerID, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dockerLogRetryInterval):
+		}
+	}
+}
+
+// LastRead returns the Docker timestamp, as Unix time in nanoseconds, of the
+// last log line written by the collector.
+func (c *dockerLogCollector) LastRead() int64 {
+	return atomic.LoadInt64(&c.lastRead)
+}
+
+// Stop waits for the remaining logs of an exited container to be written, up
+// to the given timeout, and then closes the log files.
+func (c *dockerLogCollector) Stop(timeout time.Duration) {
+	select {
+	case <-c.doneCh:
+	case <-time.After(timeout):
+		c.cancel()
+		<-c.doneCh
+	}
+
+	c.cancel()
+	c.stdout.Close()
+	c.stderr.Close()
+}
+
+// dockerLogWriter strips the timestamp Docker prefixes each log line with
+// before writing the line to the underlying log file, and records it as the
+// time logs were last read. Lines at or before the given time were already
+// written and are skipped.
+type dockerLogWriter struct {
+	c     *dockerLogCollector
+	w     io.Writer
+	after int64
+
+	// inLine is set once the timestamp of the current line was read. Until
+	// then ts buffers the timestamp, which may be split across writes. skip
+	// is set if the rest of the current line is dropped.
+	inLine bool
+	ts     []byte
+	skip   bool
+}
+
+// dockerLogTimestampMax bounds the timestamp buffered for a line, so a stream
+// without timestamps isn't buffered indefinitely.
+const dockerLogTimestampMax = 64
+
+func (w *dockerLogWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if !w.inLine {
+			i := bytes.IndexByte(p, ' ')
+			if i < 0 && len(w.ts)+len(p) < dockerLogTimestampMax {
+				w.ts = append(w.ts, p...)
+				return n, nil
+			}
+			if i < 0 {
+				return n, fmt.Errorf("log line doesn't start with a timestamp: %q", w.ts)
+			}
+
+			w.ts = append(w.ts, p[:i]...)
+			p = p[i+1:]
+			t, err := time.Parse(time.RFC3339Nano, string(w.ts))
+			if err != nil {
+				return n, fmt.Errorf("failed to parse log timestamp %q: %v", w.ts, err)
+			}
+			w.ts = w.ts[:0]
+			w.inLine = true
+			w.skip = t.UnixNano() <= w.after
+			if !w.skip {
+				atomic.StoreInt64(&w.c.lastRead, t.UnixNano())
+			}
+		}
+
+		line := p
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			line = p[:i+1]
+			w.inLine = false
+		}
+		p = p[len(line):]
+		if w.skip {
+			continue
+		}
+		if _, err := w.w.Write(line); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,196 @@
+package driver
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// writeDockerLogFrame writes data to w in the multiplexed format of the
+// Docker logs API, where stream 1 is stdout and 2 is stderr.
+func writeDockerLogFrame(w http.ResponseWriter, stream byte, data string) {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(data)))
+	w.Write(header)
+	w.Write([]byte(data))
+	w.(http.Flusher).Flush()
+}
+
+func testDockerLogCollector(t *testing.T, tty bool, since int64, handler http.HandlerFunc) (*dockerLogCollector, string, func()) {
+	srv := httptest.NewServer(handler)
+	client, err := docker.NewClient(srv.URL)
+	require.NoError(t, err)
+
+	logDir, err := ioutil.TempDir("", "nomad_docker_logs")
+	require.NoError(t, err)
+
+	logConfig := &structs.LogConfig{MaxFiles: 2, MaxFileSizeMB: 1}
+	c, err := newDockerLogCollector(client, "abc123", logDir, "web", logConfig, tty, since, testlog.Logger(t))
+	require.NoError(t, err)
+
+	return c, logDir, func() {
+		srv.Close()
+		os.RemoveAll(logDir)
+	}
+}
+
+func TestDockerLogCollector_WritesLogs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	queryCh := make(chan string, 1)
+	c, logDir, cleanup := testDockerLogCollector(t, false, 0, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/containers/abc123/logs") {
+			http.NotFound(w, r)
+			return
+		}
+		queryCh <- r.URL.RawQuery
+
+		// The stream ends once the container has exited
+		writeDockerLogFrame(w, 1, "2018-01-02T15:04:05.1Z hello stdout\n")
+		writeDockerLogFrame(w, 2, "2018-01-02T15:04:05.2Z hello stderr\n")
+	})
+	defer cleanup()
+
+	select {
+	case <-c.doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("log collector didn't stop when the stream ended")
+	}
+	c.Stop(dockerLogStopTimeout)
+
+	stdout, err := ioutil.ReadFile(filepath.Join(logDir, "web.stdout.0"))
+	require.NoError(err)
+	require.Equal("hello stdout\n", string(stdout))
+
+	stderr, err := ioutil.ReadFile(filepath.Join(logDir, "web.stderr.0"))
+	require.NoError(err)
+	require.Equal("hello stderr\n", string(stderr))
+
+	// All logs of a new container are followed
+	query := <-queryCh
+	require.Contains(query, "follow=1")
+	require.Contains(query, "timestamps=1")
+	require.NotContains(query, "since=1")
+
+	// The timestamp of the last line is tracked for re-attaching
+	last, err := time.Parse(time.RFC3339Nano, "2018-01-02T15:04:05.2Z")
+	require.NoError(err)
+	require.Equal(last.UnixNano(), c.LastRead())
+}
+
+func TestDockerLogCollector_ResumesSince(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	since := time.Unix(1500000000, int64(500*time.Millisecond))
+	queryCh := make(chan string, 1)
+	c, logDir, cleanup := testDockerLogCollector(t, false, since.UnixNano(), func(w http.ResponseWriter, r *http.Request) {
+		queryCh <- r.URL.RawQuery
+
+		// Docker sends the whole second the logs were last read in
+		writeDockerLogFrame(w, 1, "2017-07-14T02:40:00.2Z already written\n")
+		writeDockerLogFrame(w, 2, "2017-07-14T02:40:00.5Z already written\n")
+		writeDockerLogFrame(w, 1, "2017-07-14T02:40:00.7Z new stdout\n")
+		writeDockerLogFrame(w, 2, "2017-07-14T02:40:01Z new stderr\n")
+	})
+	defer cleanup()
+
+	select {
+	case <-c.doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("log collector didn't stop when the stream ended")
+	}
+	c.Stop(dockerLogStopTimeout)
+
+	// Logs are followed from the second they were last read in
+	query := <-queryCh
+	require.Contains(query, "since=1500000000")
+
+	// Lines written before re-attaching are skipped
+	stdout, err := ioutil.ReadFile(filepath.Join(logDir, "web.stdout.0"))
+	require.NoError(err)
+	require.Equal("new stdout\n", string(stdout))
+
+	stderr, err := ioutil.ReadFile(filepath.Join(logDir, "web.stderr.0"))
+	require.NoError(err)
+	require.Equal("new stderr\n", string(stderr))
+
+	require.Equal(time.Unix(1500000001, 0).UnixNano(), c.LastRead())
+}
+
+func TestDockerLogCollector_TTY(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	c, logDir, cleanup := testDockerLogCollector(t, true, 0, func(w http.ResponseWriter, r *http.Request) {
+		// The logs of a container with a TTY aren't multiplexed, and lines
+		// may be split across writes
+		w.Write([]byte("2018-01-02T15:04:05.1Z hello "))
+		w.(http.Flusher).Flush()
+		w.Write([]byte("tty\r\n2018-01-02T15"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte(":04:05.2Z bye tty\r\n"))
+	})
+	defer cleanup()
+
+	select {
+	case <-c.doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("log collector didn't stop when the stream ended")
+	}
+	c.Stop(dockerLogStopTimeout)
+
+	stdout, err := ioutil.ReadFile(filepath.Join(logDir, "web.stdout.0"))
+	require.NoError(err)
+	require.Equal("hello tty\r\nbye tty\r\n", string(stdout))
+}
+
+func TestDockerLogCollector_Stop(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	c, logDir, cleanup := testDockerLogCollector(t, false, 0, func(w http.ResponseWriter, r *http.Request) {
+		writeDockerLogFrame(w, 1, "2018-01-02T15:04:05Z still running\n")
+
+		// Block as the logs of a running container
+		<-r.Context().Done()
+	})
+	defer cleanup()
+
+	testLogFile := filepath.Join(logDir, "web.stdout.0")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := ioutil.ReadFile(testLogFile)
+		if string(data) == "still running\n" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("logs not written: %q", data)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Stopping gives up on the stream of a container that is still running
+	start := time.Now()
+	c.Stop(100 * time.Millisecond)
+	require.True(time.Since(start) < 5*time.Second)
+
+	select {
+	case <-c.doneCh:
+	default:
+		t.Fatalf("log collector still running")
+	}
+}
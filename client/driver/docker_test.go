@@ -1,6 +1,8 @@
 package driver
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
@@ -337,6 +339,11 @@ func TestDockerDriver_StartOpen_Wait(t *testing.T) {
 	}
 	defer resp.Handle.Kill()
 
+	// The container's logs are collected without an executor
+	if h := resp.Handle.(*DockerHandle); h.executor != nil || h.pluginClient != nil {
+		t.Fatalf("docker handle has an executor")
+	}
+
 	// Attempt to open
 	resp2, err := d.Open(ctx.ExecCtx, resp.Handle.ID())
 	if err != nil {
@@ -1891,6 +1898,28 @@ func TestDockerDriver_VolumesEnabled(t *testing.T) {
 	}
 }
 
+func TestDockerDriver_VolumeMounts(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	task, _, _ := dockerTask(t)
+	tctx := testDockerDriverContexts(t, task)
+	defer tctx.AllocDir.Destroy()
+
+	// Host volumes are mounted even if arbitrary host paths can't be
+	tctx.DriverCtx.config.Options[dockerVolumesConfigOption] = "false"
+	tctx.ExecCtx.VolumeMounts = []*allocdir.VolumeMount{
+		{HostPath: "/srv/certs", TaskPath: "/etc/ssl/certs", ReadOnly: true},
+		{HostPath: "/srv/data", TaskPath: "data"},
+	}
+
+	driver := NewDockerDriver(tctx.DriverCtx).(*DockerDriver)
+	binds, err := driver.containerBinds(&DockerDriverConfig{}, tctx.ExecCtx, task)
+	require.NoError(err)
+	require.Contains(binds, "/srv/certs:/etc/ssl/certs:ro")
+	require.Contains(binds, "/srv/data:/data")
+}
+
 func TestDockerDriver_Mounts(t *testing.T) {
 	if !tu.IsTravis() {
 		t.Parallel()
@@ -2194,6 +2223,47 @@ func TestDockerDriver_AuthConfiguration(t *testing.T) {
 	}
 }
 
+func TestDockerDriver_ExecStreaming(t *testing.T) {
+	if !tu.IsTravis() {
+		t.Parallel()
+	}
+	if !testutil.DockerIsConnected(t) {
+		t.Skip("Docker not connected")
+	}
+	require := require.New(t)
+
+	task, _, _ := dockerTask(t)
+	_, handle, cleanup := dockerSetup(t, task)
+	defer cleanup()
+
+	// Input is streamed to the command
+	var stdout, stderr bytes.Buffer
+	code, err := handle.ExecStreaming(context.Background(), &ExecStreamingOptions{
+		Command: []string{"/bin/sh", "-c", "cat; echo oops >&2; exit 3"},
+		Stdin:   strings.NewReader("hello from stdin"),
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+	})
+	require.NoError(err)
+	require.Equal(3, code)
+	require.Equal("hello from stdin", stdout.String())
+	require.Equal("oops\n", stderr.String())
+
+	// With a tty both streams are written to stdout
+	stdout.Reset()
+	stderr.Reset()
+	code, err = handle.ExecStreaming(context.Background(), &ExecStreamingOptions{
+		Command: []string{"/bin/sh", "-c", "echo oops >&2"},
+		Tty:     true,
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+	})
+	require.NoError(err)
+	require.Zero(code)
+	require.Contains(stdout.String(), "oops")
+	require.Empty(stderr.String())
+}
+
 func TestDockerDriver_OOMKilled(t *testing.T) {
 	if !tu.IsTravis() {
 		t.Parallel()
@@ -2237,6 +2307,10 @@ func TestDockerDriver_OOMKilled(t *testing.T) {
 			t.Fatalf("not killed by OOM killer: %s", res.Err)
 		}
 
+		if !res.OOMKilled {
+			t.Fatalf("expected wait result to be marked as OOM killed")
+		}
+
 		t.Logf("Successfully killed by OOM killer")
 
 	case <-time.After(time.Duration(tu.TestMultiplier()*5) * time.Second):
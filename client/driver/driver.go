@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/client/driver/env"
 	"github.com/hashicorp/nomad/client/fingerprint"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/device"
 
 	dstructs "github.com/hashicorp/nomad/client/driver/structs"
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -40,7 +41,7 @@ var (
 // given the name and a logger
 func NewDriver(name string, ctx *DriverContext) (Driver, error) {
 	// Lookup the factory function
-	factory, ok := BuiltinDrivers[name]
+	factory, ok := lookupDriver(name)
 	if !ok {
 		return nil, fmt.Errorf("unknown driver '%s'", name)
 	}
@@ -250,6 +251,20 @@ type DriverAbilities struct {
 	// Exec marks the driver as being able to execute arbitrary commands
 	// such as health checks. Used by the ScriptExecutor interface.
 	Exec bool
+
+	// NetNS marks the driver as being able to run tasks in the network
+	// namespace of their allocation, as required by group networks in
+	// bridge or CNI mode.
+	NetNS bool
+
+	// VolumeMounts marks the driver as being able to mount the volumes of
+	// the task group into tasks, either by running tasks in a chroot of the
+	// task dir or by mounting them itself.
+	VolumeMounts bool
+
+	// Devices marks the driver as being able to expose the host devices
+	// reserved by device plugins to tasks.
+	Devices bool
 }
 
 // LogEventFn is a callback which allows Drivers to emit task events.
@@ -328,6 +343,40 @@ type ScriptExecutor interface {
 	Exec(ctx context.Context, cmd string, args []string) ([]byte, int, error)
 }
 
+// StreamingExecutor is an optional interface implemented by DriverHandles
+// that can run interactive commands inside the task, streaming the command's
+// input and output rather than buffering it.
+type StreamingExecutor interface {
+	ExecStreaming(ctx context.Context, opts *ExecStreamingOptions) (int, error)
+}
+
+// ExecStreamingOptions are the options for running a command with
+// StreamingExecutor.
+type ExecStreamingOptions struct {
+	// Command is the command and its arguments.
+	Command []string
+
+	// Tty allocates a pseudo-terminal for the command. When set, Stderr is
+	// not used as the terminal multiplexes both output streams onto Stdout.
+	Tty bool
+
+	// Stdin is streamed to the command if set.
+	Stdin io.Reader
+
+	// Stdout and Stderr receive the command's output.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// ResizeCh receives terminal size changes when Tty is set.
+	ResizeCh <-chan TerminalSize
+}
+
+// TerminalSize is the size of a pseudo-terminal.
+type TerminalSize struct {
+	Height int
+	Width  int
+}
+
 // ExecContext is a task's execution context
 type ExecContext struct {
 	// TaskDir contains information about the task directory structure.
@@ -335,6 +384,17 @@ type ExecContext struct {
 
 	// TaskEnv contains the task's environment variables.
 	TaskEnv *env.TaskEnv
+
+	// NetNSPath is the path of the network namespace of the allocation the
+	// task must join, if any.
+	NetNSPath string
+
+	// VolumeMounts are the volumes to mount into the task. Drivers using
+	// chroot filesystem isolation find them mounted in the task dir.
+	VolumeMounts []*allocdir.VolumeMount
+
+	// Devices are the host devices reserved for the task by device plugins.
+	Devices []*device.DeviceSpec
 }
 
 // NewExecContext is used to create a new execution context
@@ -30,6 +30,11 @@ func init() {
 }
 
 func TestMain(m *testing.M) {
+	if os.Getenv(testExternalDriverEnv) != "" {
+		serveTestExternalDriver()
+		os.Exit(0)
+	}
+
 	if !testtask.Run() {
 		os.Exit(m.Run())
 	}
@@ -232,6 +237,7 @@ func setupTaskEnv(t *testing.T, driver string) (*allocdir.TaskDir, map[string]st
 		"NOMAD_TASK_NAME":               task.Name,
 		"NOMAD_GROUP_NAME":              alloc.TaskGroup,
 		"NOMAD_JOB_NAME":                alloc.Job.Name,
+		"NOMAD_NAMESPACE":               alloc.Namespace,
 		"NOMAD_DC":                      "dc1",
 		"NOMAD_REGION":                  "global",
 	}
@@ -50,6 +50,14 @@ const (
 	// JobName is the environment variable for passing the job name.
 	JobName = "NOMAD_JOB_NAME"
 
+	// JobParentID is the environment variable for passing the ID of the
+	// parent job of a dispatched or periodic job.
+	JobParentID = "NOMAD_JOB_PARENT_ID"
+
+	// Namespace is the environment variable for passing the namespace of the
+	// job.
+	Namespace = "NOMAD_NAMESPACE"
+
 	// AllocIndex is the environment variable for passing the allocation index.
 	AllocIndex = "NOMAD_ALLOC_INDEX"
 
@@ -84,6 +92,10 @@ const (
 
 	// VaultToken is the environment variable for passing the Vault token
 	VaultToken = "VAULT_TOKEN"
+
+	// WorkloadIdentity is the environment variable for passing the task's
+	// signed workload identity
+	WorkloadIdentity = "NOMAD_IDENTITY_TOKEN"
 )
 
 // The node values that can be interpreted.
@@ -189,6 +201,10 @@ type Builder struct {
 	// templateEnv are env vars set from templates
 	templateEnv map[string]string
 
+	// deviceEnv are env vars set by the device plugins of the reserved
+	// devices
+	deviceEnv map[string]string
+
 	// hostEnv are environment variables filtered from the host
 	hostEnv map[string]string
 
@@ -218,7 +234,10 @@ type Builder struct {
 	groupName        string
 	vaultToken       string
 	injectVaultToken bool
+	identityToken    string
 	jobName          string
+	jobParentID      string
+	namespace        string
 
 	// otherPorts for tasks in the same alloc
 	otherPorts map[string]string
@@ -232,6 +251,10 @@ type Builder struct {
 	// and affect network env vars.
 	networks []*structs.NetworkResource
 
+	// groupNetworks are the network resources shared by the tasks of the
+	// alloc, whose ports are mapped into the network namespace of the alloc.
+	groupNetworks []*structs.NetworkResource
+
 	mu *sync.RWMutex
 }
 
@@ -297,6 +320,12 @@ func (b *Builder) Build() *TaskEnv {
 	if b.jobName != "" {
 		envMap[JobName] = b.jobName
 	}
+	if b.jobParentID != "" {
+		envMap[JobParentID] = b.jobParentID
+	}
+	if b.namespace != "" {
+		envMap[Namespace] = b.namespace
+	}
 	if b.datacenter != "" {
 		envMap[Datacenter] = b.datacenter
 	}
@@ -308,6 +337,7 @@ func (b *Builder) Build() *TaskEnv {
 	}
 
 	// Build the network related env vars
+	buildGroupNetworkEnv(envMap, b.groupNetworks)
 	buildNetworkEnv(envMap, b.networks, b.driverNetwork)
 
 	// Build the addr of the other tasks
@@ -320,6 +350,11 @@ func (b *Builder) Build() *TaskEnv {
 		envMap[VaultToken] = b.vaultToken
 	}
 
+	// Build the workload identity
+	if b.identityToken != "" {
+		envMap[WorkloadIdentity] = b.identityToken
+	}
+
 	// Copy task meta
 	for k, v := range b.taskMeta {
 		envMap[k] = v
@@ -340,6 +375,11 @@ func (b *Builder) Build() *TaskEnv {
 		envMap[k] = hargs.ReplaceEnv(v, nodeAttrs, envMap)
 	}
 
+	// Copy device env vars as they override task env vars
+	for k, v := range b.deviceEnv {
+		envMap[k] = v
+	}
+
 	// Copy template env vars third as they override task env vars
 	for k, v := range b.templateEnv {
 		envMap[k] = v
@@ -394,6 +434,9 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 	b.groupName = alloc.TaskGroup
 	b.allocIndex = int(alloc.Index())
 	b.jobName = alloc.Job.Name
+	b.jobParentID = alloc.Job.ParentID
+	b.namespace = alloc.Namespace
+	b.identityToken = alloc.SignedIdentities[b.taskName]
 
 	// Set meta
 	combined := alloc.Job.CombinedTaskMeta(alloc.TaskGroup, b.taskName)
@@ -419,6 +462,15 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 		b.taskMeta[fmt.Sprintf("%s%s", MetaPrefix, k)] = v
 	}
 
+	// Copy the group networks to prevent sharing
+	b.groupNetworks = nil
+	if alloc.SharedResources != nil {
+		b.groupNetworks = make([]*structs.NetworkResource, len(alloc.SharedResources.Networks))
+		for i, n := range alloc.SharedResources.Networks {
+			b.groupNetworks[i] = n.Copy()
+		}
+	}
+
 	// Add ports from other tasks
 	b.otherPorts = make(map[string]string, len(alloc.TaskResources)*2)
 	for taskName, resources := range alloc.TaskResources {
@@ -427,10 +479,10 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 		}
 		for _, nw := range resources.Networks {
 			for _, p := range nw.ReservedPorts {
-				addPort(b.otherPorts, taskName, nw.IP, p.Label, p.Value)
+				addPort(b.otherPorts, taskName, nw.PortIP(p), p.Label, p.Value)
 			}
 			for _, p := range nw.DynamicPorts {
-				addPort(b.otherPorts, taskName, nw.IP, p.Label, p.Value)
+				addPort(b.otherPorts, taskName, nw.PortIP(p), p.Label, p.Value)
 			}
 		}
 	}
@@ -500,10 +552,32 @@ func (b *Builder) SetDriverNetwork(n *cstructs.DriverNetwork) *Builder {
 func buildNetworkEnv(envMap map[string]string, nets structs.Networks, driverNet *cstructs.DriverNetwork) {
 	for _, n := range nets {
 		for _, p := range n.ReservedPorts {
-			buildPortEnv(envMap, p, n.IP, driverNet)
+			buildPortEnv(envMap, p, n.PortIP(p), driverNet)
 		}
 		for _, p := range n.DynamicPorts {
-			buildPortEnv(envMap, p, n.IP, driverNet)
+			buildPortEnv(envMap, p, n.PortIP(p), driverNet)
+		}
+	}
+}
+
+// buildGroupNetworkEnv env vars for the ports of the task group network in the
+// given map. The port the tasks listen on is the port mapped into the network
+// namespace of the alloc, the host values are the ones mapped on the host.
+func buildGroupNetworkEnv(envMap map[string]string, nets structs.Networks) {
+	for _, n := range nets {
+		for _, ports := range [][]structs.Port{n.ReservedPorts, n.DynamicPorts} {
+			for _, p := range ports {
+				portStr := strconv.Itoa(p.Value)
+				ip := n.PortIP(p)
+				envMap[IpPrefix+p.Label] = ip
+				envMap[HostPortPrefix+p.Label] = portStr
+				envMap[AddrPrefix+p.Label] = net.JoinHostPort(ip, portStr)
+				if p.To != 0 {
+					envMap[PortPrefix+p.Label] = strconv.Itoa(p.To)
+				} else {
+					envMap[PortPrefix+p.Label] = portStr
+				}
+			}
 		}
 	}
 }
@@ -559,6 +633,14 @@ func (b *Builder) SetTemplateEnv(m map[string]string) *Builder {
 	return b
 }
 
+// SetDeviceEnv sets the env vars of the devices reserved for the task.
+func (b *Builder) SetDeviceEnv(m map[string]string) *Builder {
+	b.mu.Lock()
+	b.deviceEnv = m
+	b.mu.Unlock()
+	return b
+}
+
 func (b *Builder) SetVaultToken(token string, inject bool) *Builder {
 	b.mu.Lock()
 	b.vaultToken = token
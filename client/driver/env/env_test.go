@@ -204,6 +204,7 @@ func TestEnvironment_AsList(t *testing.T) {
 		"NOMAD_META_foo=bar",
 		"NOMAD_META_owner=armon",
 		"NOMAD_JOB_NAME=my-job",
+		"NOMAD_NAMESPACE=default",
 		fmt.Sprintf("NOMAD_ALLOC_ID=%s", a.ID),
 		"NOMAD_ALLOC_INDEX=0",
 	}
@@ -220,6 +221,21 @@ func TestEnvironment_AsList(t *testing.T) {
 	}
 }
 
+func TestEnvironment_JobParentID(t *testing.T) {
+	n := mock.Node()
+	a := mock.Alloc()
+	a.Job.ParentID = "parent-job"
+	task := a.Job.TaskGroups[0].Tasks[0]
+
+	act := NewBuilder(n, a, task, "global").Build().Map()
+	if v := act[JobParentID]; v != "parent-job" {
+		t.Fatalf("expected %s=parent-job but found %q", JobParentID, v)
+	}
+	if v := act[Namespace]; v != a.Namespace {
+		t.Fatalf("expected %s=%s but found %q", Namespace, a.Namespace, v)
+	}
+}
+
 func TestEnvironment_VaultToken(t *testing.T) {
 	n := mock.Node()
 	a := mock.Alloc()
@@ -249,6 +265,57 @@ func TestEnvironment_VaultToken(t *testing.T) {
 	}
 }
 
+func TestEnvironment_WorkloadIdentity(t *testing.T) {
+	n := mock.Node()
+	a := mock.Alloc()
+	task := a.Job.TaskGroups[0].Tasks[0]
+
+	act := NewBuilder(n, a, task, "global").Build().All()
+	if _, ok := act[WorkloadIdentity]; ok {
+		t.Fatalf("Unexpected environment variable: %s=%q", WorkloadIdentity, act[WorkloadIdentity])
+	}
+
+	a.SignedIdentities = map[string]string{task.Name: "token", "other": "other-token"}
+	act = NewBuilder(n, a, task, "global").Build().All()
+	if act[WorkloadIdentity] != "token" {
+		t.Fatalf("Unexpected environment variable: %s=%q", WorkloadIdentity, act[WorkloadIdentity])
+	}
+}
+
+func TestEnvironment_GroupNetwork(t *testing.T) {
+	n := mock.Node()
+	a := mock.Alloc()
+	a.SharedResources.Networks = []*structs.NetworkResource{
+		{
+			Mode:          structs.NetworkModeBridge,
+			IP:            "192.168.0.100",
+			ReservedPorts: []structs.Port{{Label: "http", Value: 8080, To: 80}},
+			DynamicPorts: []structs.Port{
+				{Label: "admin", Value: 25000},
+				{Label: "metrics", Value: 25001, HostNetwork: "private", HostIP: "10.0.0.5"},
+			},
+		},
+	}
+	task := a.Job.TaskGroups[0].Tasks[0]
+	task.Resources.Networks = nil
+
+	act := NewBuilder(n, a, task, "global").Build().All()
+	exp := map[string]string{
+		"NOMAD_PORT_http":       "80",
+		"NOMAD_HOST_PORT_http":  "8080",
+		"NOMAD_IP_http":         "192.168.0.100",
+		"NOMAD_ADDR_http":       "192.168.0.100:8080",
+		"NOMAD_PORT_admin":      "25000",
+		"NOMAD_HOST_PORT_admin": "25000",
+		"NOMAD_ADDR_admin":      "192.168.0.100:25000",
+		"NOMAD_IP_metrics":      "10.0.0.5",
+		"NOMAD_ADDR_metrics":    "10.0.0.5:25001",
+	}
+	for k, v := range exp {
+		require.Equal(t, v, act[k], k)
+	}
+}
+
 func TestEnvironment_Envvars(t *testing.T) {
 	envMap := map[string]string{"foo": "baz", "bar": "bang"}
 	n := mock.Node()
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -31,8 +32,10 @@ type ExecDriver struct {
 }
 
 type ExecDriverConfig struct {
-	Command string   `mapstructure:"command"`
-	Args    []string `mapstructure:"args"`
+	Command      string   `mapstructure:"command"`
+	Args         []string `mapstructure:"args"`
+	CPUHardLimit bool     `mapstructure:"cpu_hard_limit"` // Enforce CPU hard limit.
+	CPUCFSPeriod int64    `mapstructure:"cpu_cfs_period"` // Set the period for the CFS scheduler for the cgroup.
 }
 
 // execHandle is returned from Start/Open as a handle to the PID
@@ -67,6 +70,12 @@ func (d *ExecDriver) Validate(config map[string]interface{}) error {
 			"args": {
 				Type: fields.TypeArray,
 			},
+			"cpu_hard_limit": {
+				Type: fields.TypeBool,
+			},
+			"cpu_cfs_period": {
+				Type: fields.TypeInt,
+			},
 		},
 	}
 
@@ -79,8 +88,11 @@ func (d *ExecDriver) Validate(config map[string]interface{}) error {
 
 func (d *ExecDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: true,
-		Exec:        true,
+		SendSignals:  true,
+		Exec:         true,
+		NetNS:        true,
+		VolumeMounts: true,
+		Devices:      false,
 	}
 }
 
@@ -141,6 +153,20 @@ func (d *ExecDriver) Start(ctx *ExecContext, task *structs.Task) (*StartResponse
 		FSIsolation:    true,
 		ResourceLimits: true,
 		User:           getExecutorUser(task),
+		NetNSPath:      ctx.NetNSPath,
+	}
+
+	// Cap the task to its share of the node's CPU instead of only setting
+	// relative shares
+	if driverConfig.CPUHardLimit {
+		quota, period, err := cpuHardLimit(task.Resources.CPU, d.node.Resources.CPU,
+			driverConfig.CPUCFSPeriod, runtime.NumCPU())
+		if err != nil {
+			pluginClient.Kill()
+			return nil, err
+		}
+		execCmd.CPUQuota = quota
+		execCmd.CPUPeriod = period
 	}
 
 	ps, err := exec.LaunchCmd(execCmd)
@@ -25,6 +25,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/driver/env"
 	"github.com/hashicorp/nomad/client/driver/logging"
+	"github.com/hashicorp/nomad/client/network"
 	"github.com/hashicorp/nomad/client/stats"
 	shelpers "github.com/hashicorp/nomad/helper/stats"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -123,6 +124,16 @@ type ExecCommand struct {
 	// doesn't enforce resource limits. To enforce limits, set ResourceLimits.
 	// Using the cgroup does allow more precise cleanup of processes.
 	BasicProcessCgroup bool
+
+	// CPUQuota and CPUPeriod set a CFS hard limit on the task's CPU usage
+	// when ResourceLimits are enforced. Both are in microseconds and a zero
+	// quota only sets relative CPU shares.
+	CPUQuota  int64
+	CPUPeriod int64
+
+	// NetNSPath is the path of the network namespace the command is started
+	// in. The command shares the network of the host if it is empty.
+	NetNSPath string
 }
 
 // ProcessState holds information about the state of a user process.
@@ -291,8 +302,12 @@ func (e *UniversalExecutor) LaunchCmd(command *ExecCommand) (*ProcessState, erro
 	e.cmd.Args = append([]string{e.cmd.Path}, e.ctx.TaskEnv.ParseAndReplace(command.Args)...)
 	e.cmd.Env = e.ctx.TaskEnv.List()
 
-	// Start the process
-	if err := e.cmd.Start(); err != nil {
+	// Start the process, in the network namespace of the alloc if any
+	start := e.cmd.Start
+	if command.NetNSPath != "" {
+		start = func() error { return network.WithNetNS(command.NetNSPath, e.cmd.Start) }
+	}
+	if err := start(); err != nil {
 		return nil, fmt.Errorf("failed to start command path=%q --- args=%q: %v", path, e.cmd.Args, err)
 	}
 
@@ -310,6 +325,18 @@ func (e *UniversalExecutor) LaunchCmd(command *ExecCommand) (*ProcessState, erro
 func (e *UniversalExecutor) Exec(deadline time.Time, name string, args []string) ([]byte, int, error) {
 	ctx, cancel := context.WithDeadline(context.Background(), deadline)
 	defer cancel()
+
+	// Run the script in the network namespace of the task
+	if e.command != nil && e.command.NetNSPath != "" {
+		var out []byte
+		var code int
+		err := network.WithNetNS(e.command.NetNSPath, func() error {
+			var err error
+			out, code, err = ExecScript(ctx, e.cmd.Dir, e.ctx.TaskEnv, e.cmd.SysProcAttr, name, args)
+			return err
+		})
+		return out, code, err
+	}
 	return ExecScript(ctx, e.cmd.Dir, e.ctx.TaskEnv, e.cmd.SysProcAttr, name, args)
 }
 
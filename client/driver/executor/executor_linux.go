@@ -139,6 +139,15 @@ func (e *UniversalExecutor) configureCgroups(resources *structs.Resources) error
 	if resources.MemoryMB > 0 {
 		// Total amount of memory allowed to consume
 		e.resConCtx.groups.Resources.Memory = int64(resources.MemoryMB * 1024 * 1024)
+
+		// Allow the task to use memory above its reservation up to the max.
+		// The reservation becomes the soft limit the kernel reclaims memory
+		// down to when the host is under memory pressure.
+		if resources.MemoryMaxMB > resources.MemoryMB {
+			e.resConCtx.groups.Resources.Memory = int64(resources.MemoryMaxMB * 1024 * 1024)
+			e.resConCtx.groups.Resources.MemoryReservation = int64(resources.MemoryMB * 1024 * 1024)
+		}
+
 		// Disable swap to avoid issues on the machine
 		var memSwappiness int64 = 0
 		e.resConCtx.groups.Resources.MemorySwappiness = &memSwappiness
@@ -151,6 +160,12 @@ func (e *UniversalExecutor) configureCgroups(resources *structs.Resources) error
 	// Set the relative CPU shares for this cgroup.
 	e.resConCtx.groups.Resources.CpuShares = int64(resources.CPU)
 
+	// Set the hard limit on CPU usage if requested
+	if e.command.CPUQuota > 0 {
+		e.resConCtx.groups.Resources.CpuQuota = e.command.CPUQuota
+		e.resConCtx.groups.Resources.CpuPeriod = e.command.CPUPeriod
+	}
+
 	if resources.IOPS != 0 {
 		// Validate it is in an acceptable range.
 		if resources.IOPS < 10 || resources.IOPS > 1000 {
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/nomad/client/testutil"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // testExecutorContextWithChroot returns an ExecutorContext and AllocDir with
@@ -193,3 +194,30 @@ func TestExecutor_ClientCleanup(t *testing.T) {
 		t.Fatalf("Expected size: %v, actual: %v", finfo.Size(), finfo1.Size())
 	}
 }
+
+func TestExecutor_ConfigureCgroups_MemoryMax(t *testing.T) {
+	t.Parallel()
+	e := &UniversalExecutor{command: &ExecCommand{ResourceLimits: true}}
+
+	// Without a max the reservation is the hard limit
+	if err := e.configureCgroups(&structs.Resources{CPU: 100, MemoryMB: 256}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if got := e.resConCtx.groups.Resources.Memory; got != 256*1024*1024 {
+		t.Fatalf("bad memory limit: %d", got)
+	}
+	if got := e.resConCtx.groups.Resources.MemoryReservation; got != 0 {
+		t.Fatalf("bad memory reservation: %d", got)
+	}
+
+	// With a max the reservation becomes the soft limit
+	if err := e.configureCgroups(&structs.Resources{CPU: 100, MemoryMB: 256, MemoryMaxMB: 1024}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if got := e.resConCtx.groups.Resources.Memory; got != 1024*1024*1024 {
+		t.Fatalf("bad memory limit: %d", got)
+	}
+	if got := e.resConCtx.groups.Resources.MemoryReservation; got != 256*1024*1024 {
+		t.Fatalf("bad memory reservation: %d", got)
+	}
+}
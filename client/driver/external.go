@@ -0,0 +1,620 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-template/signals"
+	hclog "github.com/hashicorp/go-hclog"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/driver/logging"
+	dstructs "github.com/hashicorp/nomad/client/driver/structs"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	nconfig "github.com/hashicorp/nomad/nomad/structs/config"
+	baseplugin "github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers/base"
+)
+
+const (
+	// externalDriverFingerprintTimeout is how long to wait for a driver
+	// plugin to send its fingerprint
+	externalDriverFingerprintTimeout = 10 * time.Second
+)
+
+var (
+	// externalDrivers are the driver plugins launched from the plugin
+	// directory of the client, keyed by driver name
+	externalDrivers     = make(map[string]*externalPlugin)
+	externalDriversLock sync.RWMutex
+)
+
+// externalPlugin is a running driver plugin. A single plugin process runs the
+// tasks of every allocation using the driver.
+type externalPlugin struct {
+	name   string
+	client *plugin.Client
+	driver base.DriverPlugin
+}
+
+// LoadExternalDrivers launches the driver plugins found in the plugin
+// directory of the client, after which they can be used as any built-in
+// driver. Files which are not driver plugins are skipped, as are plugins
+// named after a built-in or an already loaded driver. The names of the loaded
+// drivers are returned.
+func LoadExternalDrivers(cfg *config.Config, logger *log.Logger) ([]string, error) {
+	if cfg.PluginDir == "" {
+		return nil, nil
+	}
+
+	files, err := ioutil.ReadDir(cfg.PluginDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list plugin directory: %v", err)
+	}
+
+	var loaded []string
+	for _, f := range files {
+		path := filepath.Join(cfg.PluginDir, f.Name())
+
+		// Follow symlinks to the plugin binaries
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+			continue
+		}
+
+		p, err := launchExternalDriver(cfg, path, externalPluginConfig(cfg, f.Name()))
+		if err != nil {
+			logger.Printf("[WARN] driver: skipping plugin %q: %v", path, err)
+			continue
+		}
+
+		externalDriversLock.Lock()
+		_, builtin := BuiltinDrivers[p.name]
+		_, exists := externalDrivers[p.name]
+		if !builtin && !exists {
+			externalDrivers[p.name] = p
+		}
+		externalDriversLock.Unlock()
+
+		if builtin || exists {
+			logger.Printf("[WARN] driver: skipping plugin %q: driver %q is already loaded", path, p.name)
+			p.client.Kill()
+			continue
+		}
+
+		logger.Printf("[DEBUG] driver: loaded driver %q from plugin %q", p.name, path)
+		loaded = append(loaded, p.name)
+	}
+
+	return loaded, nil
+}
+
+// externalPluginConfig returns the plugin stanza of the given plugin binary,
+// whose name omits any extension such as ".exe".
+func externalPluginConfig(cfg *config.Config, file string) *nconfig.PluginConfig {
+	name := strings.TrimSuffix(file, filepath.Ext(file))
+	for _, p := range cfg.PluginConfigs {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// launchExternalDriver launches the plugin binary at path and dispenses its
+// driver, configured from the plugin stanza if any.
+func launchExternalDriver(cfg *config.Config, path string, pconf *nconfig.PluginConfig) (*externalPlugin, error) {
+	cmd := exec.Command(path)
+	var driverConfig map[string]interface{}
+	if pconf != nil {
+		cmd.Args = append(cmd.Args, pconf.Args...)
+		driverConfig = pconf.Config
+	}
+
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: baseplugin.Handshake,
+		Plugins: map[string]plugin.Plugin{
+			baseplugin.PluginTypeBase:   &baseplugin.PluginBase{},
+			baseplugin.PluginTypeDriver: &base.PluginDriver{},
+		},
+		Cmd:              cmd,
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:   "driver.plugin",
+			Level:  hclog.LevelFromString(cfg.LogLevel),
+			Output: cfg.LogOutput,
+		}),
+	})
+
+	p, err := dispenseExternalDriver(client, driverConfig)
+	if err != nil {
+		client.Kill()
+		return nil, err
+	}
+	return p, nil
+}
+
+func dispenseExternalDriver(client *plugin.Client, config map[string]interface{}) (*externalPlugin, error) {
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := rpcClient.Dispense(baseplugin.PluginTypeBase)
+	if err != nil {
+		return nil, err
+	}
+	info, err := raw.(baseplugin.BasePlugin).PluginInfo()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plugin info: %v", err)
+	}
+	if info.Type != baseplugin.PluginTypeDriver {
+		return nil, fmt.Errorf("plugin of type %q is not a driver", info.Type)
+	}
+	if info.Name == "" {
+		return nil, fmt.Errorf("driver plugin has no name")
+	}
+
+	raw, err = rpcClient.Dispense(baseplugin.PluginTypeDriver)
+	if err != nil {
+		return nil, err
+	}
+	driver := raw.(base.DriverPlugin)
+	var data []byte
+	if config != nil {
+		if err := baseplugin.MsgPackEncode(&data, config); err != nil {
+			return nil, fmt.Errorf("failed to encode driver config: %v", err)
+		}
+	}
+	if err := driver.SetConfig(data); err != nil {
+		return nil, fmt.Errorf("failed to configure driver: %v", err)
+	}
+
+	return &externalPlugin{
+		name:   info.Name,
+		client: client,
+		driver: driver,
+	}, nil
+}
+
+// KillExternalDrivers stops the plugins of the given drivers, which can no
+// longer be used.
+func KillExternalDrivers(names []string) {
+	externalDriversLock.Lock()
+	defer externalDriversLock.Unlock()
+
+	for _, name := range names {
+		if p, ok := externalDrivers[name]; ok {
+			p.client.Kill()
+			delete(externalDrivers, name)
+		}
+	}
+}
+
+// DriverNames returns the names of the built-in and external drivers.
+func DriverNames() []string {
+	names := make([]string, 0, len(BuiltinDrivers))
+	for name := range BuiltinDrivers {
+		names = append(names, name)
+	}
+
+	externalDriversLock.RLock()
+	for name := range externalDrivers {
+		names = append(names, name)
+	}
+	externalDriversLock.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// lookupDriver returns the factory of the built-in or external driver.
+func lookupDriver(name string) (Factory, bool) {
+	if factory, ok := BuiltinDrivers[name]; ok {
+		return factory, true
+	}
+
+	externalDriversLock.RLock()
+	p, ok := externalDrivers[name]
+	externalDriversLock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	return func(ctx *DriverContext) Driver {
+		return &ExternalDriver{DriverContext: *ctx, plugin: p}
+	}, true
+}
+
+// ExternalDriver runs tasks through a driver plugin launched from the plugin
+// directory of the client.
+type ExternalDriver struct {
+	DriverContext
+
+	plugin *externalPlugin
+}
+
+// externalDriverID is the handle ID of a task run by a driver plugin
+type externalDriverID struct {
+	Handle         *base.TaskHandle
+	TaskName       string
+	KillTimeout    time.Duration
+	MaxKillTimeout time.Duration
+	KillSignal     string
+	LogMaxFiles    int
+	LogMaxFileSize int64
+}
+
+func (d *ExternalDriver) Fingerprint(req *cstructs.FingerprintRequest, resp *cstructs.FingerprintResponse) error {
+	attr := fmt.Sprintf("driver.%s", d.plugin.name)
+
+	fp, err := d.fingerprint()
+	if err != nil {
+		d.logger.Printf("[WARN] driver.%s: failed to fingerprint driver plugin: %v", d.plugin.name, err)
+		resp.RemoveAttribute(attr)
+		return nil
+	}
+	if fp.Health == base.HealthStateUndetected {
+		resp.RemoveAttribute(attr)
+		return nil
+	}
+
+	resp.AddAttribute(attr, "1")
+	for k, v := range fp.Attributes {
+		resp.AddAttribute(k, v)
+	}
+	resp.Detected = true
+	return nil
+}
+
+func (d *ExternalDriver) Periodic() (bool, time.Duration) {
+	return true, 15 * time.Second
+}
+
+// HealthCheck reports the health of the driver as fingerprinted by the
+// plugin.
+func (d *ExternalDriver) HealthCheck(req *cstructs.HealthCheckRequest, resp *cstructs.HealthCheckResponse) error {
+	dinfo := &structs.DriverInfo{
+		UpdateTime: time.Now(),
+	}
+
+	fp, err := d.fingerprint()
+	if err != nil {
+		dinfo.HealthDescription = fmt.Sprintf("Failed to fingerprint driver plugin: %v", err)
+	} else {
+		dinfo.Healthy = fp.Health == base.HealthStateHealthy
+		dinfo.HealthDescription = fp.HealthDescription
+	}
+
+	resp.AddDriverInfo(d.plugin.name, dinfo)
+	return nil
+}
+
+func (d *ExternalDriver) GetHealthCheckInterval(req *cstructs.HealthCheckIntervalRequest, resp *cstructs.HealthCheckIntervalResponse) error {
+	resp.Eligible = true
+	resp.Period = 1 * time.Minute
+	return nil
+}
+
+// fingerprint returns the first fingerprint sent by the plugin
+func (d *ExternalDriver) fingerprint() (*base.Fingerprint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), externalDriverFingerprintTimeout)
+	defer cancel()
+
+	ch, err := d.plugin.driver.Fingerprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case fp, ok := <-ch:
+		if !ok {
+			return nil, fmt.Errorf("fingerprint stream closed")
+		}
+		if fp.Err != nil {
+			return nil, fp.Err
+		}
+		return fp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (d *ExternalDriver) Validate(config map[string]interface{}) error {
+	return nil
+}
+
+func (d *ExternalDriver) Abilities() DriverAbilities {
+	caps, err := d.plugin.driver.Capabilities()
+	if err != nil {
+		d.logger.Printf("[WARN] driver.%s: failed to get driver capabilities: %v", d.plugin.name, err)
+		return DriverAbilities{}
+	}
+
+	// Mounts and devices are passed to the plugin in the task config
+	return DriverAbilities{
+		SendSignals:  caps.SendSignals,
+		Exec:         caps.Exec,
+		VolumeMounts: true,
+		Devices:      true,
+	}
+}
+
+func (d *ExternalDriver) FSIsolation() cstructs.FSIsolation {
+	caps, err := d.plugin.driver.Capabilities()
+	if err != nil {
+		return cstructs.FSIsolationNone
+	}
+
+	switch caps.FSIsolation {
+	case base.FSIsolationChroot:
+		return cstructs.FSIsolationChroot
+	case base.FSIsolationImage:
+		return cstructs.FSIsolationImage
+	default:
+		return cstructs.FSIsolationNone
+	}
+}
+
+func (d *ExternalDriver) Prestart(*ExecContext, *structs.Task) (*PrestartResponse, error) {
+	return nil, nil
+}
+
+func (d *ExternalDriver) Start(ctx *ExecContext, task *structs.Task) (*StartResponse, error) {
+	cfg := &base.TaskConfig{
+		ID:       fmt.Sprintf("%s/%s/%s", d.DriverContext.allocID, task.Name, uuid.Generate()[:8]),
+		Name:     task.Name,
+		Env:      ctx.TaskEnv.Map(),
+		User:     task.User,
+		AllocDir: filepath.Dir(ctx.TaskDir.Dir),
+	}
+	for _, m := range ctx.VolumeMounts {
+		cfg.Mounts = append(cfg.Mounts, &base.MountConfig{
+			TaskPath: m.TaskPath,
+			HostPath: m.HostPath,
+			Readonly: m.ReadOnly,
+		})
+	}
+	for _, d := range ctx.Devices {
+		cfg.Devices = append(cfg.Devices, &base.DeviceConfig{
+			TaskPath:    d.TaskPath,
+			HostPath:    d.HostPath,
+			Permissions: d.CgroupPerms,
+		})
+	}
+	if r := task.Resources; r != nil {
+		cfg.Resources = &base.Resources{
+			CPU:      int64(r.CPU),
+			MemoryMB: int64(r.MemoryMB),
+			DiskMB:   int64(r.DiskMB),
+			IOPS:     int64(r.IOPS),
+		}
+	}
+	if err := cfg.EncodeDriverConfig(task.Config); err != nil {
+		return nil, fmt.Errorf("failed to encode driver config: %v", err)
+	}
+
+	handle, err := d.plugin.driver.StartTask(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if handle.Config == nil {
+		handle.Config = cfg
+	}
+
+	maxKill := d.DriverContext.config.MaxKillTimeout
+	id := &externalDriverID{
+		Handle:         handle,
+		TaskName:       task.Name,
+		KillTimeout:    GetKillTimeout(task.KillTimeout, maxKill),
+		MaxKillTimeout: maxKill,
+		KillSignal:     task.KillSignal,
+	}
+	if task.LogConfig != nil {
+		id.LogMaxFiles = task.LogConfig.MaxFiles
+		id.LogMaxFileSize = int64(task.LogConfig.MaxFileSizeMB) * 1024 * 1024
+	}
+
+	h := d.newHandle(ctx, id)
+	go h.run()
+	go h.collectLogs()
+	return &StartResponse{Handle: h}, nil
+}
+
+func (d *ExternalDriver) Open(ctx *ExecContext, handleID string) (DriverHandle, error) {
+	id := &externalDriverID{}
+	if err := json.Unmarshal([]byte(handleID), id); err != nil {
+		return nil, fmt.Errorf("Failed to parse handle '%s': %v", handleID, err)
+	}
+	if id.Handle == nil || id.Handle.Config == nil {
+		return nil, fmt.Errorf("Handle '%s' has no task", handleID)
+	}
+
+	if err := d.plugin.driver.RecoverTask(id.Handle); err != nil {
+		return nil, fmt.Errorf("failed to recover task: %v", err)
+	}
+
+	h := d.newHandle(ctx, id)
+	go h.run()
+	go h.collectLogs()
+	return h, nil
+}
+
+func (d *ExternalDriver) Cleanup(*ExecContext, *CreatedResources) error { return nil }
+
+func (d *ExternalDriver) newHandle(ctx *ExecContext, id *externalDriverID) *externalHandle {
+	return &externalHandle{
+		id:     id,
+		taskID: id.Handle.Config.ID,
+		driver: d.plugin.driver,
+		name:   d.plugin.name,
+		logDir: ctx.TaskDir.LogDir,
+		logger: d.logger,
+		doneCh: make(chan struct{}),
+		waitCh: make(chan *dstructs.WaitResult, 1),
+	}
+}
+
+// externalHandle is the handle of a task run by a driver plugin
+type externalHandle struct {
+	id     *externalDriverID
+	taskID string
+	driver base.DriverPlugin
+	name   string
+	logDir string
+	logger *log.Logger
+	doneCh chan struct{}
+	waitCh chan *dstructs.WaitResult
+}
+
+func (h *externalHandle) ID() string {
+	data, err := json.Marshal(h.id)
+	if err != nil {
+		h.logger.Printf("[ERR] driver.%s: failed to marshal ID to JSON: %s", h.name, err)
+	}
+	return string(data)
+}
+
+func (h *externalHandle) WaitCh() chan *dstructs.WaitResult {
+	return h.waitCh
+}
+
+func (h *externalHandle) Update(task *structs.Task) error {
+	// Store the updated kill timeout.
+	h.id.KillTimeout = GetKillTimeout(task.KillTimeout, h.id.MaxKillTimeout)
+	h.id.KillSignal = task.KillSignal
+	return nil
+}
+
+func (h *externalHandle) Kill() error {
+	select {
+	case <-h.doneCh:
+		return nil
+	default:
+	}
+
+	return h.driver.StopTask(h.taskID, h.id.KillTimeout, h.id.KillSignal)
+}
+
+func (h *externalHandle) Stats() (*cstructs.TaskResourceUsage, error) {
+	return h.driver.TaskStats(h.taskID)
+}
+
+func (h *externalHandle) Signal(s os.Signal) error {
+	for name, sig := range signals.SignalLookup {
+		if sig == s {
+			return h.driver.SignalTask(h.taskID, name)
+		}
+	}
+	return fmt.Errorf("unsupported signal %v", s)
+}
+
+func (h *externalHandle) Exec(ctx context.Context, cmd string, args []string) ([]byte, int, error) {
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	res, err := h.driver.ExecTask(h.taskID, append([]string{cmd}, args...), timeout)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	out := append(res.Stdout, res.Stderr...)
+	if res.ExitResult == nil {
+		return out, 0, nil
+	}
+	return out, res.ExitResult.ExitCode, res.ExitResult.Err
+}
+
+// run waits for the task to exit and destroys it in the plugin
+func (h *externalHandle) run() {
+	var result *base.ExitResult
+	ch, err := h.driver.WaitTask(context.Background(), h.taskID)
+	if err == nil {
+		result = <-ch
+	}
+	if result == nil {
+		if err == nil {
+			err = fmt.Errorf("failed to wait for task")
+		}
+		result = &base.ExitResult{Err: err}
+	}
+	close(h.doneCh)
+
+	if err := h.driver.DestroyTask(h.taskID); err != nil {
+		h.logger.Printf("[WARN] driver.%s: failed to destroy task: %v", h.name, err)
+	}
+
+	h.waitCh <- dstructs.NewWaitResult(result.ExitCode, result.Signal, result.Err)
+	close(h.waitCh)
+}
+
+// collectLogs writes the output of the task streamed by the plugin to the
+// log directory of the task, rotating the files as for the other drivers.
+// Plugins which don't support streaming logs must write them there
+// themselves.
+func (h *externalHandle) collectLogs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := h.driver.TaskLogs(ctx, h.taskID)
+	if err != nil {
+		h.logger.Printf("[DEBUG] driver.%s: not collecting task logs: %v", h.name, err)
+		return
+	}
+
+	maxFiles, fileSize := h.id.LogMaxFiles, h.id.LogMaxFileSize
+	if maxFiles <= 0 {
+		maxFiles = structs.DefaultLogConfig().MaxFiles
+	}
+	if fileSize <= 0 {
+		fileSize = int64(structs.DefaultLogConfig().MaxFileSizeMB) * 1024 * 1024
+	}
+
+	writers := make(map[base.LogStream]io.Writer, 2)
+	for _, stream := range []base.LogStream{base.LogStreamStdout, base.LogStreamStderr} {
+		baseFile := fmt.Sprintf("%s.%s", h.id.TaskName, stream)
+		rotator, err := logging.NewFileRotator(h.logDir, baseFile, maxFiles, fileSize, h.logger)
+		if err != nil {
+			h.logger.Printf("[ERR] driver.%s: failed to create log file %q: %v", h.name, baseFile, err)
+			return
+		}
+		defer rotator.Close()
+		writers[stream] = rotator
+	}
+
+	for log := range ch {
+		if log.Err != nil {
+			if log.Err != context.Canceled {
+				h.logger.Printf("[DEBUG] driver.%s: stopped collecting task logs: %v", h.name, log.Err)
+			}
+			return
+		}
+		if w, ok := writers[log.Stream]; ok {
+			if _, err := w.Write(log.Data); err != nil {
+				h.logger.Printf("[WARN] driver.%s: failed to write task logs: %v", h.name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package driver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	nconfig "github.com/hashicorp/nomad/nomad/structs/config"
+	baseplugin "github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers/base"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// testExternalDriverEnv makes the test binary serve the test driver
+	// as a driver plugin instead of running the tests
+	testExternalDriverEnv = "NOMAD_TEST_EXTERNAL_DRIVER"
+
+	testExternalDriverName = "test-external"
+)
+
+// serveTestExternalDriver serves a driver plugin whose tasks write their
+// message to stdout and exit shortly after being started.
+func serveTestExternalDriver() {
+	var lock sync.Mutex
+	messages := make(map[string]string)
+	var greeting string
+
+	d := &base.MockDriverPlugin{
+		MockPlugin: &baseplugin.MockPlugin{
+			PluginInfoF: func() (*baseplugin.PluginInfoResponse, error) {
+				return &baseplugin.PluginInfoResponse{
+					Type:             baseplugin.PluginTypeDriver,
+					PluginApiVersion: "v0.1.0",
+					PluginVersion:    "v0.1.0",
+					Name:             testExternalDriverName,
+				}, nil
+			},
+			ConfigSchemaF: func() (*hclspec.Spec, error) { return nil, nil },
+			SetConfigF: func(data []byte) error {
+				var conf struct {
+					Greeting string `codec:"greeting"`
+				}
+				if err := baseplugin.MsgPackDecode(data, &conf); err != nil {
+					return err
+				}
+				greeting = conf.Greeting
+				return nil
+			},
+		},
+		CapabilitiesF: func() (*base.Capabilities, error) {
+			return &base.Capabilities{FSIsolation: base.FSIsolationNone}, nil
+		},
+		FingerprintF: func(ctx context.Context) (<-chan *base.Fingerprint, error) {
+			ch := make(chan *base.Fingerprint, 1)
+			ch <- &base.Fingerprint{
+				Attributes: map[string]string{
+					"driver.test-external.version":  "0.1.0",
+					"driver.test-external.greeting": greeting,
+				},
+				Health:            base.HealthStateHealthy,
+				HealthDescription: "healthy",
+			}
+			return ch, nil
+		},
+		StartTaskF: func(cfg *base.TaskConfig) (*base.TaskHandle, error) {
+			var conf struct {
+				Message string `codec:"message"`
+			}
+			if err := cfg.DecodeDriverConfig(&conf); err != nil {
+				return nil, err
+			}
+
+			lock.Lock()
+			messages[cfg.ID] = conf.Message
+			lock.Unlock()
+			return &base.TaskHandle{Config: cfg, State: base.TaskStateRunning}, nil
+		},
+		RecoverTaskF: func(*base.TaskHandle) error { return nil },
+		WaitTaskF: func(ctx context.Context, id string) (<-chan *base.ExitResult, error) {
+			ch := make(chan *base.ExitResult, 1)
+			go func() {
+				time.Sleep(500 * time.Millisecond)
+				ch <- &base.ExitResult{}
+			}()
+			return ch, nil
+		},
+		TaskLogsF: func(ctx context.Context, id string) (<-chan *base.TaskLog, error) {
+			lock.Lock()
+			msg := messages[id]
+			lock.Unlock()
+
+			ch := make(chan *base.TaskLog, 1)
+			ch <- &base.TaskLog{Stream: base.LogStreamStdout, Data: []byte(msg)}
+			close(ch)
+			return ch, nil
+		},
+		StopTaskF:    func(string, time.Duration, string) error { return nil },
+		DestroyTaskF: func(string) error { return nil },
+		TaskStatsF: func(string) (*cstructs.TaskResourceUsage, error) {
+			return &cstructs.TaskResourceUsage{}, nil
+		},
+	}
+
+	base.Serve(d, hclog.NewNullLogger())
+}
+
+func TestExternalDriver_LoadAndRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin directory test relies on symlinks")
+	}
+	require := require.New(t)
+
+	// The test binary serves the test driver when launched as a plugin
+	os.Setenv(testExternalDriverEnv, "1")
+	defer os.Unsetenv(testExternalDriverEnv)
+
+	bin, err := filepath.Abs(os.Args[0])
+	require.NoError(err)
+
+	pluginDir, err := ioutil.TempDir("", "nomad_driver_plugins")
+	require.NoError(err)
+	defer os.RemoveAll(pluginDir)
+
+	require.NoError(os.Symlink(bin, filepath.Join(pluginDir, testExternalDriverName)))
+	require.NoError(ioutil.WriteFile(filepath.Join(pluginDir, "README"), []byte("not a plugin"), 0644))
+
+	cfg := testConfig(t)
+	cfg.PluginDir = pluginDir
+	cfg.PluginConfigs = []*nconfig.PluginConfig{
+		{
+			Name:   testExternalDriverName,
+			Config: map[string]interface{}{"greeting": "hi"},
+		},
+	}
+
+	loaded, err := LoadExternalDrivers(cfg, testlog.Logger(t))
+	require.NoError(err)
+	require.Equal([]string{testExternalDriverName}, loaded)
+	defer KillExternalDrivers(loaded)
+	require.Contains(DriverNames(), testExternalDriverName)
+
+	task := &structs.Task{
+		Name:   "foo",
+		Driver: testExternalDriverName,
+		Config: map[string]interface{}{
+			"message": "hello from the plugin",
+		},
+		LogConfig: structs.DefaultLogConfig(),
+		Resources: basicResources,
+	}
+	ctx := testDriverContexts(t, task)
+	defer ctx.AllocDir.Destroy()
+
+	d, err := NewDriver(task.Driver, ctx.DriverCtx)
+	require.NoError(err)
+
+	// Fingerprint the driver
+	request := &cstructs.FingerprintRequest{Config: cfg, Node: &structs.Node{}}
+	var response cstructs.FingerprintResponse
+	require.NoError(d.Fingerprint(request, &response))
+	require.True(response.Detected)
+	require.Equal("1", response.Attributes["driver.test-external"])
+	require.Equal("0.1.0", response.Attributes["driver.test-external.version"])
+	require.Equal("hi", response.Attributes["driver.test-external.greeting"])
+
+	// Run the task
+	resp, err := d.Start(ctx.ExecCtx, task)
+	require.NoError(err)
+
+	select {
+	case res := <-resp.Handle.WaitCh():
+		require.True(res.Successful(), "task failed: %v", res)
+	case <-time.After(time.Duration(testutil.TestMultiplier()*5) * time.Second):
+		t.Fatalf("timeout")
+	}
+
+	// The task logs are written to the log directory
+	stdout := filepath.Join(ctx.ExecCtx.TaskDir.LogDir, "foo.stdout.0")
+	testutil.WaitForResult(func() (bool, error) {
+		data, err := ioutil.ReadFile(stdout)
+		if err != nil {
+			return false, err
+		}
+		return strings.Contains(string(data), "hello from the plugin"), nil
+	}, func(err error) {
+		t.Fatalf("task logs not written: %v", err)
+	})
+}
+
+func TestExternalDriver_NoPluginDir(t *testing.T) {
+	t.Parallel()
+	cfg := testConfig(t)
+	cfg.PluginDir = filepath.Join(cfg.StateDir, "does-not-exist")
+
+	loaded, err := LoadExternalDrivers(cfg, testlog.Logger(t))
+	require.NoError(t, err)
+	require.Empty(t, loaded)
+
+	_, err = NewDriver(testExternalDriverName, NewEmptyDriverContext())
+	require.Error(t, err)
+}
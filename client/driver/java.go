@@ -106,8 +106,11 @@ func (d *JavaDriver) Validate(config map[string]interface{}) error {
 
 func (d *JavaDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: true,
-		Exec:        true,
+		SendSignals:  true,
+		Exec:         true,
+		NetNS:        true,
+		VolumeMounts: d.FSIsolation() == cstructs.FSIsolationChroot,
+		Devices:      false,
 	}
 }
 
@@ -278,6 +281,7 @@ func (d *JavaDriver) Start(ctx *ExecContext, task *structs.Task) (*StartResponse
 		ResourceLimits: true,
 		User:           getExecutorUser(task),
 		TaskKillSignal: taskKillSignal,
+		NetNSPath:      ctx.NetNSPath,
 	}
 	ps, err := execIntf.LaunchCmd(execCmd)
 	if err != nil {
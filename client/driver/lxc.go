@@ -173,8 +173,11 @@ func (d *LxcDriver) Validate(config map[string]interface{}) error {
 
 func (d *LxcDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: false,
-		Exec:        false,
+		SendSignals:  false,
+		Exec:         false,
+		NetNS:        false,
+		VolumeMounts: false,
+		Devices:      false,
 	}
 }
 
@@ -443,7 +446,7 @@ func (h *lxcDriverHandle) WaitCh() chan *dstructs.WaitResult {
 }
 
 func (h *lxcDriverHandle) Update(task *structs.Task) error {
-	h.killTimeout = GetKillTimeout(task.KillTimeout, h.killTimeout)
+	h.killTimeout = GetKillTimeout(task.KillTimeout, h.maxKillTimeout)
 	return nil
 }
 
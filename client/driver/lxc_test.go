@@ -346,3 +346,24 @@ func TestLxcDriver_Start_NoVolumes(t *testing.T) {
 	}
 
 }
+
+func TestLxcDriver_HandleUpdate_MaxKillTimeout(t *testing.T) {
+	t.Parallel()
+
+	h := &lxcDriverHandle{maxKillTimeout: 10 * time.Second}
+
+	// The kill timeout of the task is capped at the max kill timeout
+	if err := h.Update(&structs.Task{KillTimeout: time.Minute}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if h.killTimeout != 10*time.Second {
+		t.Fatalf("expected the kill timeout to be capped at 10s; got %v", h.killTimeout)
+	}
+
+	if err := h.Update(&structs.Task{KillTimeout: 5 * time.Second}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if h.killTimeout != 5*time.Second {
+		t.Fatalf("expected a kill timeout of 5s; got %v", h.killTimeout)
+	}
+}
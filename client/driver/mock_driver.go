@@ -121,8 +121,11 @@ func NewMockDriver(ctx *DriverContext) Driver {
 
 func (d *MockDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: false,
-		Exec:        true,
+		SendSignals:  false,
+		Exec:         true,
+		NetNS:        true,
+		VolumeMounts: false,
+		Devices:      false,
 	}
 }
 
@@ -145,8 +145,11 @@ func (d *QemuDriver) Validate(config map[string]interface{}) error {
 
 func (d *QemuDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: false,
-		Exec:        false,
+		SendSignals:  false,
+		Exec:         false,
+		NetNS:        true,
+		VolumeMounts: false,
+		Devices:      false,
 	}
 }
 
@@ -332,9 +335,10 @@ func (d *QemuDriver) Start(ctx *ExecContext, task *structs.Task) (*StartResponse
 	}
 
 	execCmd := &executor.ExecCommand{
-		Cmd:  args[0],
-		Args: args[1:],
-		User: task.User,
+		Cmd:       args[0],
+		Args:      args[1:],
+		User:      task.User,
+		NetNSPath: ctx.NetNSPath,
 	}
 	ps, err := exec.LaunchCmd(execCmd)
 	if err != nil {
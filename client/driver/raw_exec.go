@@ -93,8 +93,11 @@ func (d *RawExecDriver) Validate(config map[string]interface{}) error {
 
 func (d *RawExecDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: true,
-		Exec:        true,
+		SendSignals:  true,
+		Exec:         true,
+		NetNS:        true,
+		VolumeMounts: false,
+		Devices:      false,
 	}
 }
 
@@ -174,6 +177,7 @@ func (d *RawExecDriver) Start(ctx *ExecContext, task *structs.Task) (*StartRespo
 		User:               task.User,
 		TaskKillSignal:     taskKillSignal,
 		BasicProcessCgroup: d.useCgroup,
+		NetNSPath:          ctx.NetNSPath,
 	}
 	ps, err := exec.LaunchCmd(execCmd)
 	if err != nil {
@@ -327,8 +327,11 @@ func (d *RktDriver) Validate(config map[string]interface{}) error {
 
 func (d *RktDriver) Abilities() DriverAbilities {
 	return DriverAbilities{
-		SendSignals: false,
-		Exec:        true,
+		SendSignals:  false,
+		Exec:         true,
+		NetNS:        false,
+		VolumeMounts: false,
+		Devices:      false,
 	}
 }
 
@@ -30,6 +30,25 @@ func cgroupsMounted(node *structs.Node) bool {
 	return ok
 }
 
+// cpuHardLimit returns the CFS quota and period that cap a task to its share
+// of the node's CPU. The quota is scaled by the number of cores since it
+// applies across all of them. A zero period uses the default CFS period.
+func cpuHardLimit(taskCPU, nodeCPU int, period int64, numCores int) (int64, int64, error) {
+	if period < 0 || period > 1000000 {
+		return 0, 0, fmt.Errorf("invalid value for cpu_cfs_period")
+	}
+	if period == 0 {
+		period = defaultCFSPeriodUS
+	}
+	if nodeCPU <= 0 {
+		return 0, 0, fmt.Errorf("node CPU resources unknown, cannot enforce a cpu hard limit")
+	}
+
+	percentTicks := float64(taskCPU) / float64(nodeCPU)
+	quota := int64(percentTicks*float64(period)) * int64(numCores)
+	return quota, period, nil
+}
+
 // createExecutor launches an executor plugin and returns an instance of the
 // Executor interface
 func createExecutor(w io.Writer, clientConfig *config.Config,
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDriver_KillTimeout(t *testing.T) {
@@ -27,6 +28,33 @@ func TestDriver_KillTimeout(t *testing.T) {
 	}
 }
 
+func TestDriver_cpuHardLimit(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Half of the node's CPU on four cores with the default period
+	quota, period, err := cpuHardLimit(1000, 2000, 0, 4)
+	require.NoError(err)
+	require.Equal(int64(defaultCFSPeriodUS), period)
+	require.Equal(int64(200000), quota)
+
+	// Custom period
+	quota, period, err = cpuHardLimit(500, 2000, 10000, 2)
+	require.NoError(err)
+	require.Equal(int64(10000), period)
+	require.Equal(int64(5000), quota)
+
+	// Invalid periods
+	_, _, err = cpuHardLimit(500, 2000, -1, 2)
+	require.Error(err)
+	_, _, err = cpuHardLimit(500, 2000, 1000001, 2)
+	require.Error(err)
+
+	// Unknown node resources
+	_, _, err = cpuHardLimit(500, 0, 0, 2)
+	require.Error(err)
+}
+
 func TestDriver_getTaskKillSignal(t *testing.T) {
 	assert := assert.New(t)
 	t.Parallel()
@@ -0,0 +1,75 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/client/network"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+)
+
+const (
+	// cniPluginAttrPrefix prefixes the attributes of the CNI plugins found
+	// in the CNI path, set to the version of the CNI specification they
+	// support
+	cniPluginAttrPrefix = "plugins.cni.version."
+
+	// cniConfigAttrPrefix prefixes the attributes of the CNI network
+	// configurations found in the CNI configuration directory
+	cniConfigAttrPrefix = "plugins.cni.config."
+)
+
+// CNIFingerprint is used to fingerprint the CNI plugins and network
+// configurations available to the group networks
+type CNIFingerprint struct {
+	StaticFingerprinter
+	logger *log.Logger
+}
+
+// NewCNIFingerprint is used to create a CNI fingerprint
+func NewCNIFingerprint(logger *log.Logger) Fingerprint {
+	return &CNIFingerprint{logger: logger}
+}
+
+func (f *CNIFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *cstructs.FingerprintResponse) error {
+	cfg := req.Config
+	for _, dir := range filepath.SplitList(cfg.CNIPath) {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				f.logger.Printf("[WARN] fingerprint.cni: failed to read CNI path %q: %v", dir, err)
+			}
+			continue
+		}
+		for _, file := range files {
+			if !file.Mode().IsRegular() || file.Mode()&0111 == 0 {
+				continue
+			}
+			attr := cniPluginAttrPrefix + file.Name()
+			if _, ok := resp.Attributes[attr]; ok {
+				// The first plugin in the CNI path is the one invoked
+				continue
+			}
+			version, err := network.PluginVersion(filepath.Join(dir, file.Name()))
+			if err != nil {
+				f.logger.Printf("[DEBUG] fingerprint.cni: %v", err)
+				continue
+			}
+			resp.AddAttribute(attr, version)
+			resp.Detected = true
+		}
+	}
+
+	configs, err := network.LoadConfigs(cfg.CNIConfigDir)
+	if err != nil {
+		f.logger.Printf("[WARN] fingerprint.cni: %v", err)
+		return nil
+	}
+	for name := range configs {
+		resp.AddAttribute(cniConfigAttrPrefix+name, "1")
+		resp.Detected = true
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package fingerprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCNIFingerprint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake plugins are shell scripts")
+	}
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	binDir := filepath.Join(dir, "bin")
+	confDir := filepath.Join(dir, "conf")
+	require.NoError(os.Mkdir(binDir, 0755))
+	require.NoError(os.Mkdir(confDir, 0755))
+
+	plugin := "#!/bin/sh\necho '{\"cniVersion\":\"0.4.0\",\"supportedVersions\":[\"0.3.1\",\"0.4.0\"]}'\n"
+	require.NoError(ioutil.WriteFile(filepath.Join(binDir, "bridge"), []byte(plugin), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(binDir, "broken"), []byte("#!/bin/sh\nexit 1\n"), 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(binDir, "README"), []byte("not a plugin"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(confDir, "mynet.conflist"),
+		[]byte(`{"name":"mynet","cniVersion":"0.4.0","plugins":[{"type":"bridge"}]}`), 0644))
+
+	fp := NewCNIFingerprint(testlog.Logger(t))
+	request := &cstructs.FingerprintRequest{
+		Config: &config.Config{CNIPath: binDir, CNIConfigDir: confDir},
+		Node:   &structs.Node{Attributes: make(map[string]string)},
+	}
+	var response cstructs.FingerprintResponse
+	require.NoError(fp.Fingerprint(request, &response))
+	require.True(response.Detected)
+	require.Equal(map[string]string{
+		"plugins.cni.version.bridge": "0.4.0",
+		"plugins.cni.config.mynet":   "1",
+	}, response.Attributes)
+
+	// Nothing is detected without plugins or configurations
+	request.Config = &config.Config{CNIPath: filepath.Join(dir, "missing"), CNIConfigDir: filepath.Join(dir, "missing")}
+	response = cstructs.FingerprintResponse{}
+	require.NoError(fp.Fingerprint(request, &response))
+	require.False(response.Detected)
+	require.Empty(response.Attributes)
+}
@@ -33,11 +33,6 @@ func (f *CPUFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *cst
 		f.logger.Printf("[WARN] fingerprint.cpu: %v", err)
 	}
 
-	if cfg.CpuCompute != 0 {
-		setResourcesCPU(cfg.CpuCompute)
-		return nil
-	}
-
 	if modelName := stats.CPUModelName(); modelName != "" {
 		resp.AddAttribute("cpu.modelname", modelName)
 	}
@@ -1,6 +1,7 @@
 package fingerprint
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/nomad/client/config"
@@ -91,8 +92,20 @@ func TestCPUFingerprint_OverrideCompute(t *testing.T) {
 			t.Fatalf("err: %v", err)
 		}
 
+		if !response.Detected {
+			t.Fatalf("expected response to be applicable")
+		}
+
 		if response.Resources.CPU != cfg.CpuCompute {
 			t.Fatalf("expected override cpu of %d but found %d", cfg.CpuCompute, response.Resources.CPU)
 		}
+
+		// The detected CPU attributes are still set
+		if response.Attributes["cpu.numcores"] == "" {
+			t.Fatalf("Missing Num Cores")
+		}
+		if expected := fmt.Sprintf("%d", cfg.CpuCompute); response.Attributes["cpu.totalcompute"] != expected {
+			t.Fatalf("expected cpu.totalcompute of %s but found %s", expected, response.Attributes["cpu.totalcompute"])
+		}
 	}
 }
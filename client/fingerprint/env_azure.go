@@ -0,0 +1,241 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/useragent"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// This is where the Azure instance metadata service normally resides.
+	DEFAULT_AZURE_URL = "http://169.254.169.254/metadata/instance/"
+
+	// AzureMetadataTimeout is the timeout used when contacting the Azure
+	// instance metadata service
+	AzureMetadataTimeout = 2 * time.Second
+
+	// azureMetadataAPIVersion is the version of the instance metadata API
+	// that is queried.
+	azureMetadataAPIVersion = "2017-08-01"
+)
+
+// AzureMetadataCompute is the compute section of the Azure instance metadata
+type AzureMetadataCompute struct {
+	Location             string `json:"location"`
+	Name                 string `json:"name"`
+	PlatformFaultDomain  string `json:"platformFaultDomain"`
+	PlatformUpdateDomain string `json:"platformUpdateDomain"`
+	ResourceGroupName    string `json:"resourceGroupName"`
+	Tags                 string `json:"tags"`
+	VMID                 string `json:"vmId"`
+	VMSize               string `json:"vmSize"`
+	Zone                 string `json:"zone"`
+}
+
+// AzureMetadataNetwork is the network section of the Azure instance metadata
+type AzureMetadataNetwork struct {
+	Interface []struct {
+		IPv4 struct {
+			IPAddress []struct {
+				PrivateIPAddress string `json:"privateIpAddress"`
+				PublicIPAddress  string `json:"publicIpAddress"`
+			} `json:"ipAddress"`
+		} `json:"ipv4"`
+	} `json:"interface"`
+}
+
+// EnvAzureFingerprint is used to fingerprint Azure metadata
+type EnvAzureFingerprint struct {
+	StaticFingerprinter
+	client      *http.Client
+	logger      *log.Logger
+	metadataURL string
+}
+
+// NewEnvAzureFingerprint is used to create a fingerprint from Azure metadata
+func NewEnvAzureFingerprint(logger *log.Logger) Fingerprint {
+	// Read the internal metadata URL from the environment, allowing test files to
+	// provide their own
+	metadataURL := os.Getenv("AZURE_ENV_URL")
+	if metadataURL == "" {
+		metadataURL = DEFAULT_AZURE_URL
+	}
+
+	// assume 2 seconds is enough time for inside Azure network
+	client := &http.Client{
+		Timeout:   AzureMetadataTimeout,
+		Transport: cleanhttp.DefaultTransport(),
+	}
+
+	return &EnvAzureFingerprint{
+		client:      client,
+		logger:      logger,
+		metadataURL: metadataURL,
+	}
+}
+
+// Get returns the value of the given metadata attribute, either as plain text
+// for leaf values or as JSON for whole sections.
+func (f *EnvAzureFingerprint) Get(attribute string, format string) (string, error) {
+	reqUrl := fmt.Sprintf("%s%s?api-version=%s&format=%s", f.metadataURL, attribute, azureMetadataAPIVersion, format)
+	parsedUrl, err := url.Parse(reqUrl)
+	if err != nil {
+		return "", err
+	}
+
+	req := &http.Request{
+		Method: "GET",
+		URL:    parsedUrl,
+		Header: http.Header{
+			"Metadata":   []string{"true"},
+			"User-Agent": []string{useragent.String()},
+		},
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		f.logger.Printf("[DEBUG] fingerprint.env_azure: Could not read value for attribute %q", attribute)
+		return "", err
+	}
+
+	resp, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		f.logger.Printf("[ERR] fingerprint.env_azure: Error reading response body for Azure %s", attribute)
+		return "", err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return "", ReqError{res.StatusCode}
+	}
+
+	return string(resp), nil
+}
+
+func (f *EnvAzureFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *cstructs.FingerprintResponse) error {
+	cfg := req.Config
+
+	// Check if we should tighten the timeout
+	if cfg.ReadBoolDefault(TightenNetworkTimeoutsConfig, false) {
+		f.client.Timeout = 1 * time.Millisecond
+	}
+
+	if !f.isAzure() {
+		return nil
+	}
+
+	value, err := f.Get("compute", "json")
+	if err != nil {
+		return f.checkError(err, "compute")
+	}
+
+	var compute AzureMetadataCompute
+	if err := json.Unmarshal([]byte(value), &compute); err != nil {
+		return fmt.Errorf("error decoding Azure compute metadata: %v", err)
+	}
+
+	// Attributes whose value uniquely identifies a node are namespaced as
+	// unique so they aren't included in the computed node class.
+	resp.AddAttribute(structs.UniqueNamespace("platform.azure.id"), compute.VMID)
+	resp.AddAttribute(structs.UniqueNamespace("platform.azure.name"), compute.Name)
+	resp.AddAttribute("platform.azure.location", compute.Location)
+	resp.AddAttribute("platform.azure.vm-size", compute.VMSize)
+	resp.AddAttribute("platform.azure.resource-group", compute.ResourceGroupName)
+	resp.AddAttribute("platform.azure.fault-domain", compute.PlatformFaultDomain)
+	resp.AddAttribute("platform.azure.update-domain", compute.PlatformUpdateDomain)
+	if compute.Zone != "" {
+		resp.AddAttribute("platform.azure.zone", compute.Zone)
+	}
+
+	// Tags are formatted as "key1:value1;key2:value2"
+	for _, tag := range strings.Split(compute.Tags, ";") {
+		if tag == "" {
+			continue
+		}
+
+		parts := strings.SplitN(tag, ":", 2)
+		k, v := parts[0], ""
+		if len(parts) == 2 {
+			v = parts[1]
+		}
+
+		attr := "platform.azure.tag."
+		var key string
+
+		// If the tag is namespaced as unique, we strip it from the tag and
+		// prepend to the whole attribute.
+		if structs.IsUniqueNamespace(k) {
+			k = strings.TrimPrefix(k, structs.NodeUniqueNamespace)
+			key = fmt.Sprintf("%s%s%s", structs.NodeUniqueNamespace, attr, k)
+		} else {
+			key = fmt.Sprintf("%s%s", attr, k)
+		}
+
+		resp.AddAttribute(key, v)
+	}
+
+	// Get internal and external IPs (if they exist)
+	value, err = f.Get("network", "json")
+	if err != nil {
+		f.logger.Printf("[WARN] fingerprint.env_azure: Error retrieving network interface information: %s", err)
+	} else {
+		var network AzureMetadataNetwork
+		if err := json.Unmarshal([]byte(value), &network); err != nil {
+			f.logger.Printf("[WARN] fingerprint.env_azure: Error decoding network interface information: %s", err.Error())
+		}
+
+		for i, intf := range network.Interface {
+			prefix := "unique.platform.azure.network." + strconv.Itoa(i)
+			for j, addr := range intf.IPv4.IPAddress {
+				if addr.PrivateIPAddress != "" {
+					resp.AddAttribute(prefix+".ip."+strconv.Itoa(j), addr.PrivateIPAddress)
+				}
+				if addr.PublicIPAddress != "" {
+					resp.AddAttribute(prefix+".external-ip."+strconv.Itoa(j), addr.PublicIPAddress)
+				}
+			}
+		}
+	}
+
+	// populate Links
+	resp.AddLink("azure", compute.VMID)
+	resp.Detected = true
+
+	return nil
+}
+
+// checkError treats errors contacting the metadata service as not being in
+// an Azure environment, rather than as a fingerprinting failure.
+func (f *EnvAzureFingerprint) checkError(err error, desc string) error {
+	if _, ok := err.(*url.Error); ok {
+		f.logger.Printf("[DEBUG] fingerprint.env_azure: Error querying Azure %s, skipping", desc)
+		return nil
+	}
+	return err
+}
+
+func (f *EnvAzureFingerprint) isAzure() bool {
+	// Query the metadata url for the VM ID, to verify we're on Azure
+	vmID, err := f.Get("compute/vmId", "text")
+	if err != nil {
+		if re, ok := err.(ReqError); !ok || re.StatusCode != 404 {
+			// If it wasn't a 404 error, print an error message.
+			f.logger.Printf("[DEBUG] fingerprint.env_azure: Error querying Azure Metadata URL, skipping")
+		}
+		return false
+	}
+
+	return strings.TrimSpace(vmID) != ""
+}
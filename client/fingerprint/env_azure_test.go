@@ -0,0 +1,117 @@
+package fingerprint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureFingerprint_nonAzure(t *testing.T) {
+	os.Setenv("AZURE_ENV_URL", "http://127.0.0.1/metadata/instance/")
+	defer os.Unsetenv("AZURE_ENV_URL")
+	f := NewEnvAzureFingerprint(testlog.Logger(t))
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	request := &cstructs.FingerprintRequest{Config: &config.Config{}, Node: node}
+	var response cstructs.FingerprintResponse
+	err := f.Fingerprint(request, &response)
+	require.NoError(t, err)
+	require.False(t, response.Detected)
+	require.Empty(t, response.Attributes)
+}
+
+func TestAzureFingerprint(t *testing.T) {
+	require := require.New(t)
+
+	endpoints := map[string]string{
+		"/metadata/instance/compute/vmId?api-version=2017-08-01&format=text": azureVMID,
+		"/metadata/instance/compute?api-version=2017-08-01&format=json":      azureComputeJSON,
+		"/metadata/instance/network?api-version=2017-08-01&format=json":      azureNetworkJSON,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			t.Errorf("Metadata header not set in HTTP request")
+		}
+
+		body, ok := endpoints[r.RequestURI]
+		if !ok {
+			w.WriteHeader(404)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer ts.Close()
+
+	os.Setenv("AZURE_ENV_URL", ts.URL+"/metadata/instance/")
+	defer os.Unsetenv("AZURE_ENV_URL")
+	f := NewEnvAzureFingerprint(testlog.Logger(t))
+
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	request := &cstructs.FingerprintRequest{Config: &config.Config{}, Node: node}
+	var response cstructs.FingerprintResponse
+	require.NoError(f.Fingerprint(request, &response))
+	require.True(response.Detected)
+
+	expected := map[string]string{
+		"unique.platform.azure.id":                      azureVMID,
+		"unique.platform.azure.name":                    "nomad-client-1",
+		"platform.azure.location":                       "westus2",
+		"platform.azure.vm-size":                        "Standard_D2s_v3",
+		"platform.azure.resource-group":                 "nomad",
+		"platform.azure.fault-domain":                   "0",
+		"platform.azure.update-domain":                  "1",
+		"platform.azure.zone":                           "2",
+		"platform.azure.tag.env":                        "prod",
+		"unique.platform.azure.tag.owner":               "ops",
+		"unique.platform.azure.network.0.ip.0":          "10.0.0.4",
+		"unique.platform.azure.network.0.external-ip.0": "52.1.2.3",
+	}
+	for k, v := range expected {
+		require.Equal(v, response.Attributes[k], "attribute %q", k)
+	}
+
+	require.Equal(azureVMID, response.Links["azure"])
+}
+
+const azureVMID = "13f56399-bd52-4150-9748-7190aae1ff21"
+
+const azureComputeJSON = `{
+  "location": "westus2",
+  "name": "nomad-client-1",
+  "platformFaultDomain": "0",
+  "platformUpdateDomain": "1",
+  "resourceGroupName": "nomad",
+  "tags": "env:prod;unique.owner:ops",
+  "vmId": "13f56399-bd52-4150-9748-7190aae1ff21",
+  "vmSize": "Standard_D2s_v3",
+  "zone": "2"
+}`
+
+const azureNetworkJSON = `{
+  "interface": [
+    {
+      "ipv4": {
+        "ipAddress": [
+          {
+            "privateIpAddress": "10.0.0.4",
+            "publicIpAddress": "52.1.2.3"
+          }
+        ]
+      },
+      "macAddress": "000D3AF806EC"
+    }
+  ]
+}`
@@ -46,6 +46,7 @@ func (f *MemoryFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp *
 		resp.Resources = &structs.Resources{
 			MemoryMB: totalMemory / bytesInMB,
 		}
+		resp.Detected = true
 	}
 
 	return nil
@@ -24,6 +24,10 @@ func TestMemoryFingerprint(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
+	if !response.Detected {
+		t.Fatalf("expected response to be applicable")
+	}
+
 	assertNodeAttributeContains(t, response.Attributes, "memory.totalbytes")
 
 	if response.Resources == nil {
@@ -50,6 +54,7 @@ func TestMemoryFingerprint_Override(t *testing.T) {
 
 	assertNodeAttributeContains(t, response.Attributes, "memory.totalbytes")
 	require := require.New(t)
+	require.True(response.Detected)
 	require.NotNil(response.Resources)
 	require.Equal(response.Resources.MemoryMB, memoryMB)
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"sort"
+	"strings"
 
 	sockaddr "github.com/hashicorp/go-sockaddr"
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -15,6 +17,10 @@ const (
 	// be detected.
 	defaultNetworkSpeed = 1000
 
+	// hostNetworkAttrPrefix prefixes the attributes of the host networks
+	// of the node, set to the interfaces of their addresses.
+	hostNetworkAttrPrefix = "network.host_network."
+
 	// networkDisallowLinkLocalOption/Default are used to allow the operator to
 	// decide how the fingerprinter handles an interface that only contains link
 	// local addresses.
@@ -95,9 +101,6 @@ func (f *NetworkFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp
 		return err
 	}
 
-	resp.Resources = &structs.Resources{
-		Networks: nwResources,
-	}
 	for _, nwResource := range nwResources {
 		f.logger.Printf("[DEBUG] fingerprint.network: Detected interface %v with IP: %v", intf.Name, nwResource.IP)
 	}
@@ -106,6 +109,38 @@ func (f *NetworkFingerprint) Fingerprint(req *cstructs.FingerprintRequest, resp
 	if len(nwResources) > 0 {
 		resp.AddAttribute("unique.network.ip-address", nwResources[0].IP)
 	}
+
+	// Add the addresses of the host networks
+	names := make([]string, 0, len(cfg.HostNetworks))
+	for name := range cfg.HostNetworks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		hostResources, err := f.createHostNetworkResources(cfg.HostNetworks[name])
+		if err != nil {
+			return fmt.Errorf("Error while detecting host network %q during fingerprinting: %v", name, err)
+		}
+		if len(hostResources) == 0 {
+			f.logger.Printf("[WARN] fingerprint.network: no address found for host network %q", name)
+			continue
+		}
+
+		devices := make([]string, 0, len(hostResources))
+		for _, nwResource := range hostResources {
+			f.logger.Printf("[DEBUG] fingerprint.network: Detected host network %q on interface %v with IP: %v",
+				name, nwResource.Device, nwResource.IP)
+			if len(devices) == 0 || devices[len(devices)-1] != nwResource.Device {
+				devices = append(devices, nwResource.Device)
+			}
+		}
+		resp.AddAttribute(hostNetworkAttrPrefix+name, strings.Join(devices, ","))
+		nwResources = append(nwResources, hostResources...)
+	}
+
+	resp.Resources = &structs.Resources{
+		Networks: nwResources,
+	}
 	resp.Detected = true
 
 	return nil
@@ -167,6 +202,70 @@ func (f *NetworkFingerprint) createNetworkResources(throughput int, intf *net.In
 	return nwResources, nil
 }
 
+// createHostNetworkResources creates network resources for every IP of a host
+// network: the addresses of its interface, or of all interfaces, in its CIDR.
+func (f *NetworkFingerprint) createHostNetworkResources(hostNetwork *structs.ClientHostNetworkConfig) ([]*structs.NetworkResource, error) {
+	var cidr *net.IPNet
+	if hostNetwork.CIDR != "" {
+		var err error
+		if _, cidr, err = net.ParseCIDR(hostNetwork.CIDR); err != nil {
+			return nil, err
+		}
+	}
+	reserved, err := structs.ParsePortRanges(hostNetwork.ReservedPorts)
+	if err != nil {
+		return nil, err
+	}
+
+	var intfs []net.Interface
+	if hostNetwork.Interface != "" {
+		intf, err := f.interfaceDetector.InterfaceByName(hostNetwork.Interface)
+		if err != nil {
+			return nil, err
+		}
+		intfs = []net.Interface{*intf}
+	} else if intfs, err = f.interfaceDetector.Interfaces(); err != nil {
+		return nil, err
+	}
+
+	var nwResources []*structs.NetworkResource
+	for i := range intfs {
+		intf := &intfs[i]
+		addrs, err := f.interfaceDetector.Addrs(intf)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			var ip net.IP
+			switch v := (addr).(type) {
+			case *net.IPNet:
+				ip = v.IP
+			case *net.IPAddr:
+				ip = v.IP
+			}
+			if ip == nil || (cidr != nil && !cidr.Contains(ip)) {
+				continue
+			}
+
+			newNetwork := &structs.NetworkResource{
+				HostNetwork: hostNetwork.Name,
+				Device:      intf.Name,
+				IP:          ip.String(),
+			}
+			if ip.To4() != nil {
+				newNetwork.CIDR = newNetwork.IP + "/32"
+			} else {
+				newNetwork.CIDR = newNetwork.IP + "/128"
+			}
+			for _, port := range reserved {
+				newNetwork.ReservedPorts = append(newNetwork.ReservedPorts, structs.Port{Label: "reserved", Value: port})
+			}
+			nwResources = append(nwResources, newNetwork)
+		}
+	}
+	return nwResources, nil
+}
+
 // Returns the interface with the name passed by user. If the name is blank, we
 // use the interface attached to the default route.
 func (f *NetworkFingerprint) findInterface(deviceName string) (*net.Interface, error) {
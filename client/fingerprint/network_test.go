@@ -10,6 +10,7 @@ import (
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
 )
 
 // Set skipOnlineTestEnvVar to a non-empty value to skip network tests.  Useful
@@ -438,3 +439,54 @@ func TestNetworkFingerPrint_LinkLocal_Disallowed(t *testing.T) {
 		t.Fatalf("should not apply attributes")
 	}
 }
+
+func TestNetworkFingerPrint_HostNetworks(t *testing.T) {
+	f := &NetworkFingerprint{logger: testlog.Logger(t), interfaceDetector: &NetworkInterfaceDetectorMultipleInterfaces{}}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg := &config.Config{
+		NetworkSpeed:     100,
+		NetworkInterface: "eth0",
+		HostNetworks: map[string]*structs.ClientHostNetworkConfig{
+			"private": {Name: "private", Interface: "eth1", CIDR: "100.64.0.0/10", ReservedPorts: "22"},
+			"public":  {Name: "public", CIDR: "2003:db8::/32"},
+			"missing": {Name: "missing", CIDR: "192.0.2.0/24"},
+		},
+	}
+
+	request := &cstructs.FingerprintRequest{Config: cfg, Node: node}
+	var response cstructs.FingerprintResponse
+	require.NoError(t, f.Fingerprint(request, &response))
+	require.True(t, response.Detected)
+
+	require.Equal(t, "eth1", response.Attributes["network.host_network.private"])
+	require.Equal(t, "eth1", response.Attributes["network.host_network.public"])
+	require.NotContains(t, response.Attributes, "network.host_network.missing")
+
+	var hostNetworks []*structs.NetworkResource
+	for _, n := range response.Resources.Networks {
+		if n.HostNetwork != "" {
+			hostNetworks = append(hostNetworks, n)
+		}
+	}
+	require.Equal(t, []*structs.NetworkResource{
+		{
+			HostNetwork:   "private",
+			Device:        "eth1",
+			IP:            "100.64.0.0",
+			CIDR:          "100.64.0.0/32",
+			ReservedPorts: []structs.Port{{Label: "reserved", Value: 22}},
+		},
+		{
+			HostNetwork: "public",
+			Device:      "eth1",
+			IP:          "2003:db8::",
+			CIDR:        "2003:db8::/128",
+		},
+	}, hostNetworks)
+
+	// An unknown interface fails the fingerprint
+	cfg.HostNetworks["private"].Interface = "eth9"
+	require.Error(t, f.Fingerprint(request, &cstructs.FingerprintResponse{}))
+}
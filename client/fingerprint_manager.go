@@ -107,10 +107,26 @@ func (fp *FingerprintManager) Run() error {
 	whitelistDriversEnabled := len(whitelistDrivers) > 0
 	blacklistDrivers := cfg.ReadStringListToMap("driver.blacklist")
 
+	drivers := driver.DriverNames()
+	knownDrivers := make(map[string]struct{}, len(drivers))
+	for _, name := range drivers {
+		knownDrivers[name] = struct{}{}
+	}
+
+	// Warn about unknown drivers since a typo in the whitelist would
+	// otherwise silently disable every driver on the node.
+	for _, list := range []map[string]struct{}{whitelistDrivers, blacklistDrivers} {
+		for name := range list {
+			if _, ok := knownDrivers[name]; !ok {
+				fp.logger.Printf("[WARN] client.fingerprint_manager: unknown driver %q in driver white/blacklist", name)
+			}
+		}
+	}
+
 	var availDrivers []string
 	var skippedDrivers []string
 
-	for name := range driver.BuiltinDrivers {
+	for _, name := range drivers {
 		// Skip fingerprinting drivers that are not in the whitelist if it is
 		// enabled.
 		if _, ok := whitelistDrivers[name]; whitelistDriversEnabled && !ok {
@@ -27,6 +27,10 @@ type GCConfig struct {
 	Interval            time.Duration
 	ReservedDiskMB      int
 	ParallelDestroys    int
+
+	// MaxAge is how long a terminal allocation is kept before it is garbage
+	// collected regardless of the other thresholds. Zero disables it.
+	MaxAge time.Duration
 }
 
 // AllocCounter is used by AllocGarbageCollector to discover how many un-GC'd
@@ -97,12 +101,38 @@ func (a *AllocGarbageCollector) Run() {
 			return
 		}
 
+		a.collectExpired()
+
 		if err := a.keepUsageBelowThreshold(); err != nil {
 			a.logger.Printf("[ERR] client.gc: error garbage collecting allocation: %v", err)
 		}
 	}
 }
 
+// collectExpired garbage collects allocations that have been terminal for
+// longer than the configured max age.
+func (a *AllocGarbageCollector) collectExpired() {
+	if a.config.MaxAge <= 0 {
+		return
+	}
+
+	reason := fmt.Sprintf("being terminal for longer than %v", a.config.MaxAge)
+	for {
+		select {
+		case <-a.shutdownCh:
+			return
+		default:
+		}
+
+		gcAlloc := a.allocRunners.PopOlderThan(time.Now().Add(-a.config.MaxAge))
+		if gcAlloc == nil {
+			return
+		}
+
+		a.destroyAllocRunner(gcAlloc.allocRunner, reason)
+	}
+}
+
 // Force the garbage collector to run.
 func (a *AllocGarbageCollector) Trigger() {
 	select {
@@ -428,6 +458,21 @@ func (i *IndexedGCAllocPQ) Pop() *GCAlloc {
 	return gcAlloc
 }
 
+// PopOlderThan pops the oldest alloc runner if it was marked for GC before the
+// given time. Returns nil otherwise.
+func (i *IndexedGCAllocPQ) PopOlderThan(t time.Time) *GCAlloc {
+	i.pqLock.Lock()
+	defer i.pqLock.Unlock()
+
+	if len(i.heap) == 0 || !i.heap[0].timeStamp.Before(t) {
+		return nil
+	}
+
+	gcAlloc := heap.Pop(&i.heap).(*GCAlloc)
+	delete(i.index, gcAlloc.allocRunner.Alloc().ID)
+	return gcAlloc
+}
+
 // Remove alloc from GC. Returns nil if alloc doesn't exist.
 func (i *IndexedGCAllocPQ) Remove(allocID string) *GCAlloc {
 	i.pqLock.Lock()
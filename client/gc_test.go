@@ -74,6 +74,26 @@ func TestIndexedGCAllocPQ(t *testing.T) {
 	}
 }
 
+func TestIndexedGCAllocPQ_PopOlderThan(t *testing.T) {
+	t.Parallel()
+	pq := NewIndexedGCAllocPQ()
+
+	_, ar1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc(), false)
+	pq.Push(ar1)
+
+	if gcAlloc := pq.PopOlderThan(time.Now().Add(-time.Minute)); gcAlloc != nil {
+		t.Fatalf("expected nil, got %v", gcAlloc)
+	}
+
+	gcAlloc := pq.PopOlderThan(time.Now().Add(time.Minute))
+	if gcAlloc == nil || gcAlloc.allocRunner != ar1 {
+		t.Fatalf("bad gcAlloc: %v", gcAlloc)
+	}
+	if l := pq.Length(); l != 0 {
+		t.Fatalf("expected empty queue, found %d", l)
+	}
+}
+
 // MockAllocCounter implements AllocCounter interface.
 type MockAllocCounter struct {
 	allocs int
@@ -152,6 +172,37 @@ func TestAllocGarbageCollector_Collect(t *testing.T) {
 	}
 }
 
+func TestAllocGarbageCollector_CollectExpired(t *testing.T) {
+	t.Parallel()
+	logger := testlog.Logger(t)
+	conf := gcConfig()
+	conf.MaxAge = time.Hour
+	gc := NewAllocGarbageCollector(logger, &MockStatsCollector{}, &MockAllocCounter{}, conf)
+
+	_, ar1 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc(), false)
+	_, ar2 := allocrunner.TestAllocRunnerFromAlloc(t, mock.Alloc(), false)
+	go ar1.Run()
+	go ar2.Run()
+
+	gc.MarkForCollection(ar1)
+	gc.MarkForCollection(ar2)
+
+	// Exit the alloc runners
+	exitAllocRunner(ar1, ar2)
+
+	// Age the first alloc past the max age
+	gc.allocRunners.index[ar1.Alloc().ID].timeStamp = time.Now().Add(-2 * time.Hour)
+
+	gc.collectExpired()
+	if l := gc.allocRunners.Length(); l != 1 {
+		t.Fatalf("expected 1 alloc left, found %d", l)
+	}
+	gcAlloc := gc.allocRunners.Pop()
+	if gcAlloc == nil || gcAlloc.allocRunner != ar2 {
+		t.Fatalf("bad gcAlloc: %v", gcAlloc)
+	}
+}
+
 func TestAllocGarbageCollector_CollectAll(t *testing.T) {
 	t.Parallel()
 	logger := testlog.Logger(t)
@@ -0,0 +1,325 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// cniVersion is the version of the CNI specification used by the
+	// built-in network configurations and to invoke the plugins
+	cniVersion = "0.4.0"
+
+	// pluginTimeout is how long a CNI plugin may run
+	pluginTimeout = 30 * time.Second
+)
+
+// Conflist is a CNI network configuration list: the plugins invoked in order
+// to set up a network.
+type Conflist struct {
+	Name       string                   `json:"name"`
+	CNIVersion string                   `json:"cniVersion"`
+	Plugins    []map[string]interface{} `json:"plugins"`
+}
+
+// PortMapping is a port mapped from the host into a network namespace by the
+// plugins supporting the portMappings capability.
+type PortMapping struct {
+	HostPort      int    `json:"hostPort"`
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol"`
+	HostIP        string `json:"hostIP,omitempty"`
+}
+
+// cniResult is the part of the result of the plugins Nomad uses
+type cniResult struct {
+	IPs []struct {
+		Address string `json:"address"`
+	} `json:"ips"`
+}
+
+// addresses returns the addresses assigned to the network namespace
+func (r *cniResult) addresses() []string {
+	addrs := make([]string, 0, len(r.IPs))
+	for _, ip := range r.IPs {
+		addrs = append(addrs, ip.Address)
+	}
+	return addrs
+}
+
+// bridgeConflist returns the configuration of the bridge network mode:
+// allocations are attached to a bridge on the host and get an address in
+// the given subnet, and their ports are mapped from the host.
+func bridgeConflist(bridge, subnet string) *Conflist {
+	return &Conflist{
+		Name:       bridge,
+		CNIVersion: cniVersion,
+		Plugins: []map[string]interface{}{
+			{
+				"type":        "bridge",
+				"bridge":      bridge,
+				"isGateway":   true,
+				"ipMasq":      true,
+				"hairpinMode": true,
+				"ipam": map[string]interface{}{
+					"type": "host-local",
+					"ranges": [][]map[string]interface{}{
+						{{"subnet": subnet}},
+					},
+					"routes": []map[string]interface{}{
+						{"dst": "0.0.0.0/0"},
+					},
+				},
+			},
+			{
+				"type":         "portmap",
+				"capabilities": map[string]interface{}{"portMappings": true},
+				"snat":         true,
+			},
+		},
+	}
+}
+
+// loopbackConflist returns the configuration bringing up the loopback
+// interface of a network namespace.
+func loopbackConflist() *Conflist {
+	return &Conflist{
+		Name:       "lo",
+		CNIVersion: cniVersion,
+		Plugins:    []map[string]interface{}{{"type": "loopback"}},
+	}
+}
+
+// LoadConfigs loads the CNI network configurations of a directory, keyed by
+// network name. Both configuration lists (.conflist) and single plugin
+// configurations (.conf and .json) are loaded. When several files configure
+// the same network, the first one in lexical order is used.
+func LoadConfigs(dir string) (map[string]*Conflist, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]*Conflist{}, nil
+		}
+		return nil, fmt.Errorf("failed to read CNI configuration directory: %v", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if !f.IsDir() {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	configs := make(map[string]*Conflist, len(names))
+	for _, name := range names {
+		var list *Conflist
+		switch filepath.Ext(name) {
+		case ".conflist":
+			list, err = loadConflist(filepath.Join(dir, name))
+		case ".conf", ".json":
+			list, err = loadConf(filepath.Join(dir, name))
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if list.Name == "" || len(list.Plugins) == 0 {
+			continue
+		}
+		if _, ok := configs[list.Name]; !ok {
+			configs[list.Name] = list
+		}
+	}
+	return configs, nil
+}
+
+// loadConflist loads a CNI network configuration list
+func loadConflist(path string) (*Conflist, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CNI configuration %q: %v", path, err)
+	}
+	var list Conflist
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse CNI configuration %q: %v", path, err)
+	}
+	return &list, nil
+}
+
+// loadConf loads a single plugin CNI network configuration as a list
+func loadConf(path string) (*Conflist, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CNI configuration %q: %v", path, err)
+	}
+	var conf map[string]interface{}
+	if err := json.Unmarshal(raw, &conf); err != nil {
+		return nil, fmt.Errorf("failed to parse CNI configuration %q: %v", path, err)
+	}
+	list := &Conflist{Plugins: []map[string]interface{}{conf}}
+	list.Name, _ = conf["name"].(string)
+	list.CNIVersion, _ = conf["cniVersion"].(string)
+	return list, nil
+}
+
+// invoker invokes the CNI plugins on a network namespace
+type invoker struct {
+	cniPath     string
+	containerID string
+	netns       string
+}
+
+// add sets up the network of a configuration list, passing the result of each
+// plugin to the next one. It returns the result of the last plugin.
+func (i *invoker) add(ctx context.Context, list *Conflist, ifName string, ports []PortMapping) (*cniResult, error) {
+	var prevResult json.RawMessage
+	for _, plugin := range list.Plugins {
+		conf := pluginConf(list, plugin, ports)
+		if prevResult != nil {
+			conf["prevResult"] = prevResult
+		}
+		out, err := i.exec(ctx, "ADD", ifName, conf)
+		if err != nil {
+			return nil, err
+		}
+		prevResult = out
+	}
+
+	var result cniResult
+	if len(prevResult) != 0 {
+		if err := json.Unmarshal(prevResult, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse CNI result: %v", err)
+		}
+	}
+	return &result, nil
+}
+
+// del tears down the network of a configuration list, invoking the plugins in
+// reverse order. All the plugins are invoked even if some of them fail.
+func (i *invoker) del(ctx context.Context, list *Conflist, ifName string, ports []PortMapping) error {
+	var mErr multierror.Error
+	for idx := len(list.Plugins) - 1; idx >= 0; idx-- {
+		conf := pluginConf(list, list.Plugins[idx], ports)
+		if _, err := i.exec(ctx, "DEL", ifName, conf); err != nil {
+			multierror.Append(&mErr, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// exec runs the plugin of a network configuration with the given command and
+// returns its output.
+func (i *invoker) exec(ctx context.Context, command, ifName string, conf map[string]interface{}) ([]byte, error) {
+	pluginType, _ := conf["type"].(string)
+	if pluginType == "" {
+		return nil, fmt.Errorf("CNI network %q has a plugin without type", conf["name"])
+	}
+	bin, err := findPlugin(i.cniPath, pluginType)
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := json.Marshal(conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration of CNI plugin %q: %v", pluginType, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pluginTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Env = append(os.Environ(),
+		"CNI_COMMAND="+command,
+		"CNI_CONTAINERID="+i.containerID,
+		"CNI_NETNS="+i.netns,
+		"CNI_IFNAME="+ifName,
+		"CNI_PATH="+i.cniPath,
+	)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// Plugins report their errors as JSON on stdout
+		var pluginErr struct {
+			Code    int    `json:"code"`
+			Msg     string `json:"msg"`
+			Details string `json:"details"`
+		}
+		if json.Unmarshal(stdout.Bytes(), &pluginErr) == nil && pluginErr.Msg != "" {
+			msg := pluginErr.Msg
+			if pluginErr.Details != "" {
+				msg += ": " + pluginErr.Details
+			}
+			return nil, fmt.Errorf("CNI plugin %q failed (code %d): %s", pluginType, pluginErr.Code, msg)
+		}
+		return nil, fmt.Errorf("CNI plugin %q failed: %v: %s", pluginType, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// pluginConf returns the configuration passed to a plugin of a configuration
+// list, including the port mappings if the plugin supports them.
+func pluginConf(list *Conflist, plugin map[string]interface{}, ports []PortMapping) map[string]interface{} {
+	conf := make(map[string]interface{}, len(plugin)+3)
+	for k, v := range plugin {
+		conf[k] = v
+	}
+	conf["name"] = list.Name
+	conf["cniVersion"] = list.CNIVersion
+
+	if caps, ok := plugin["capabilities"].(map[string]interface{}); ok {
+		if enabled, _ := caps["portMappings"].(bool); enabled && len(ports) != 0 {
+			conf["runtimeConfig"] = map[string]interface{}{"portMappings": ports}
+		}
+	}
+	return conf
+}
+
+// findPlugin returns the path of a plugin in the CNI path, which may list
+// several directories.
+func findPlugin(cniPath, name string) (string, error) {
+	for _, dir := range filepath.SplitList(cniPath) {
+		path := filepath.Join(dir, name)
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("failed to find CNI plugin %q in %q", name, cniPath)
+}
+
+// PluginVersion returns the latest version of the CNI specification supported
+// by a plugin.
+func PluginVersion(path string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = append(os.Environ(), "CNI_COMMAND=VERSION")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf(`{"cniVersion":%q}`, cniVersion))
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to get version of CNI plugin %q: %v", path, err)
+	}
+
+	var version struct {
+		CNIVersion string `json:"cniVersion"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &version); err != nil || version.CNIVersion == "" {
+		return "", fmt.Errorf("invalid version of CNI plugin %q: %q", path, stdout.String())
+	}
+	return version.CNIVersion, nil
+}
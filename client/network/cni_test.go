@@ -0,0 +1,170 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlugin installs a CNI plugin in dir which records its invocations in
+// the "calls" file and its configuration in "<name>.<command>.json".
+func fakePlugin(t *testing.T, dir, name, output string, exitCode int) {
+	script := fmt.Sprintf(`#!/bin/sh
+cat > %[1]s/%[2]s.$CNI_COMMAND.json
+echo "%[2]s $CNI_COMMAND $CNI_CONTAINERID $CNI_NETNS $CNI_IFNAME" >> %[1]s/calls
+echo '%[3]s'
+exit %[4]d
+`, dir, name, output, exitCode)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(script), 0755))
+}
+
+func readConf(t *testing.T, dir, name, command string) map[string]interface{} {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, name+"."+command+".json"))
+	require.NoError(t, err)
+	var conf map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &conf))
+	return conf
+}
+
+func readCalls(t *testing.T, dir string) []string {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "calls"))
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(raw)), "\n")
+}
+
+func TestInvoker_AddDel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake plugins are shell scripts")
+	}
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fakePlugin(t, dir, "bridge", `{"cniVersion":"0.4.0","ips":[{"version":"4","address":"10.0.0.2/24"}]}`, 0)
+	fakePlugin(t, dir, "portmap", `{"cniVersion":"0.4.0","ips":[{"version":"4","address":"10.0.0.2/24"}]}`, 0)
+
+	inv := &invoker{cniPath: dir, containerID: "alloc", netns: "/var/run/netns/alloc"}
+	ports := []PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}
+	list := bridgeConflist("nomad", "172.26.64.0/20")
+
+	result, err := inv.add(context.Background(), list, "eth0", ports)
+	require.NoError(err)
+	require.Equal([]string{"10.0.0.2/24"}, result.addresses())
+
+	bridge := readConf(t, dir, "bridge", "ADD")
+	require.Equal("nomad", bridge["name"])
+	require.Equal(cniVersion, bridge["cniVersion"])
+	require.Nil(bridge["prevResult"])
+	require.Nil(bridge["runtimeConfig"])
+
+	portmap := readConf(t, dir, "portmap", "ADD")
+	require.NotNil(portmap["prevResult"])
+	require.Equal(map[string]interface{}{
+		"portMappings": []interface{}{
+			map[string]interface{}{"hostPort": 8080.0, "containerPort": 80.0, "protocol": "tcp"},
+		},
+	}, portmap["runtimeConfig"])
+
+	require.NoError(inv.del(context.Background(), list, "eth0", ports))
+	require.Equal([]string{
+		"bridge ADD alloc /var/run/netns/alloc eth0",
+		"portmap ADD alloc /var/run/netns/alloc eth0",
+		"portmap DEL alloc /var/run/netns/alloc eth0",
+		"bridge DEL alloc /var/run/netns/alloc eth0",
+	}, readCalls(t, dir))
+}
+
+func TestInvoker_Error(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake plugins are shell scripts")
+	}
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	fakePlugin(t, dir, "bridge", `{"code":11,"msg":"no more addresses"}`, 1)
+
+	inv := &invoker{cniPath: dir, containerID: "alloc", netns: "/var/run/netns/alloc"}
+	_, err = inv.add(context.Background(), bridgeConflist("nomad", "172.26.64.0/20"), "eth0", nil)
+	require.Error(err)
+	require.Contains(err.Error(), "no more addresses")
+
+	// The missing portmap plugin fails the teardown but the bridge plugin is
+	// still invoked
+	err = inv.del(context.Background(), bridgeConflist("nomad", "172.26.64.0/20"), "eth0", nil)
+	require.Error(err)
+	require.Contains(err.Error(), `failed to find CNI plugin "portmap"`)
+	require.Contains(readCalls(t, dir), "bridge DEL alloc /var/run/netns/alloc eth0")
+}
+
+func TestLoadConfigs(t *testing.T) {
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "cni")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"10-mynet.conflist": `{"name":"mynet","cniVersion":"0.4.0","plugins":[{"type":"bridge"},{"type":"portmap"}]}`,
+		"20-mynet.conf":     `{"name":"mynet","cniVersion":"0.3.1","type":"macvlan"}`,
+		"30-other.conf":     `{"name":"other","cniVersion":"0.3.1","type":"macvlan"}`,
+		"40-unnamed.json":   `{"cniVersion":"0.3.1","type":"macvlan"}`,
+		"README.md":         `not a configuration`,
+	}
+	for name, content := range files {
+		require.NoError(ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	configs, err := LoadConfigs(dir)
+	require.NoError(err)
+	require.Len(configs, 2)
+	require.Len(configs["mynet"].Plugins, 2)
+	require.Equal("0.4.0", configs["mynet"].CNIVersion)
+	require.Equal("macvlan", configs["other"].Plugins[0]["type"])
+
+	// A missing directory has no configuration
+	configs, err = LoadConfigs(filepath.Join(dir, "missing"))
+	require.NoError(err)
+	require.Empty(configs)
+
+	// An invalid configuration is an error
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "50-invalid.conf"), []byte("{"), 0644))
+	_, err = LoadConfigs(dir)
+	require.Error(err)
+}
+
+func TestPortMappings(t *testing.T) {
+	n := &structs.NetworkResource{
+		Mode:          structs.NetworkModeBridge,
+		IP:            "10.0.0.1",
+		ReservedPorts: []structs.Port{{Label: "http", Value: 8080, To: 80}},
+		DynamicPorts:  []structs.Port{{Label: "admin", Value: 25000}},
+	}
+	require.Equal(t, []PortMapping{
+		{HostPort: 8080, ContainerPort: 80, Protocol: "tcp", HostIP: "10.0.0.1"},
+		{HostPort: 8080, ContainerPort: 80, Protocol: "udp", HostIP: "10.0.0.1"},
+		{HostPort: 25000, ContainerPort: 25000, Protocol: "tcp", HostIP: "10.0.0.1"},
+		{HostPort: 25000, ContainerPort: 25000, Protocol: "udp", HostIP: "10.0.0.1"},
+	}, portMappings(n))
+}
+
+func TestAllocNetNSPath(t *testing.T) {
+	alloc := &structs.Allocation{ID: "alloc", SharedResources: &structs.Resources{}}
+	require.Empty(t, AllocNetNSPath(alloc))
+
+	alloc.SharedResources.Networks = []*structs.NetworkResource{{Mode: structs.NetworkModeHost}}
+	require.Empty(t, AllocNetNSPath(alloc))
+
+	alloc.SharedResources.Networks[0].Mode = structs.NetworkModeBridge
+	require.Equal(t, "/var/run/netns/alloc", AllocNetNSPath(alloc))
+}
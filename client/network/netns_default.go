@@ -0,0 +1,23 @@
+// +build !linux
+
+package network
+
+import "errors"
+
+// errNetNSUnsupported is returned on the platforms without network namespaces
+var errNetNSUnsupported = errors.New("network namespaces are only supported on Linux")
+
+// CreateNetNS is not supported on non-Linux platforms
+func CreateNetNS(path string) error {
+	return errNetNSUnsupported
+}
+
+// DeleteNetNS is not supported on non-Linux platforms
+func DeleteNetNS(path string) error {
+	return errNetNSUnsupported
+}
+
+// WithNetNS is not supported on non-Linux platforms
+func WithNetNS(path string, fn func() error) error {
+	return errNetNSUnsupported
+}
@@ -0,0 +1,94 @@
+package network
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"golang.org/x/sys/unix"
+)
+
+// CreateNetNS creates a network namespace and persists it by bind mounting it
+// at the given path, the same way "ip netns add" does.
+func CreateNetNS(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create network namespace directory: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE|os.O_EXCL, 0444)
+	if err != nil {
+		return fmt.Errorf("failed to create network namespace file: %v", err)
+	}
+	f.Close()
+
+	// Create the namespace on a dedicated thread which is never unlocked, so
+	// that the runtime terminates it with the goroutine instead of scheduling
+	// other goroutines in the new namespace.
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+			errCh <- fmt.Errorf("failed to create network namespace: %v", err)
+			return
+		}
+		ns := fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid())
+		if err := unix.Mount(ns, path, "none", unix.MS_BIND, ""); err != nil {
+			errCh <- fmt.Errorf("failed to bind mount network namespace: %v", err)
+			return
+		}
+		errCh <- nil
+	}()
+
+	if err := <-errCh; err != nil {
+		os.Remove(path)
+		return err
+	}
+	return nil
+}
+
+// DeleteNetNS unmounts and removes a network namespace created by
+// CreateNetNS. The namespace is destroyed once no process runs in it.
+func DeleteNetNS(path string) error {
+	if err := unix.Unmount(path, unix.MNT_DETACH); err != nil && err != unix.EINVAL && err != unix.ENOENT {
+		return fmt.Errorf("failed to unmount network namespace: %v", err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove network namespace file: %v", err)
+	}
+	return nil
+}
+
+// WithNetNS runs fn on a thread switched to the network namespace at the given
+// path, so that the processes started by fn run in the namespace.
+func WithNetNS(path string, fn func() error) error {
+	ns, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open network namespace: %v", err)
+	}
+	defer ns.Close()
+
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		origin, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+		if err != nil {
+			errCh <- fmt.Errorf("failed to open current network namespace: %v", err)
+			return
+		}
+		defer origin.Close()
+
+		if err := unix.Setns(int(ns.Fd()), unix.CLONE_NEWNET); err != nil {
+			errCh <- fmt.Errorf("failed to enter network namespace: %v", err)
+			return
+		}
+		err = fn()
+
+		// Only give the thread back to the runtime once it is back in its
+		// original namespace, otherwise it is terminated with the goroutine
+		if unix.Setns(int(origin.Fd()), unix.CLONE_NEWNET) == nil {
+			runtime.UnlockOSThread()
+		}
+		errCh <- err
+	}()
+	return <-errCh
+}
@@ -0,0 +1,40 @@
+package network
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetNS(t *testing.T) {
+	if syscall.Geteuid() != 0 {
+		t.Skip("Must run as root")
+	}
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "netns")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "alloc")
+	require.NoError(CreateNetNS(path))
+	require.Error(CreateNetNS(path))
+
+	// Only the loopback interface exists in the namespace
+	var ifaces []net.Interface
+	require.NoError(WithNetNS(path, func() error {
+		ifaces, err = net.Interfaces()
+		return err
+	}))
+	require.Len(ifaces, 1)
+	require.Equal("lo", ifaces[0].Name)
+
+	require.NoError(DeleteNetNS(path))
+	_, err = os.Stat(path)
+	require.True(os.IsNotExist(err))
+	require.NoError(DeleteNetNS(path))
+}
@@ -0,0 +1,187 @@
+// Package network sets up the network namespaces of the allocations whose
+// task group network isn't in host mode, using CNI plugins.
+package network
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// netnsDir is the directory of the network namespaces of the
+	// allocations, shared with the "ip netns" command
+	netnsDir = "/var/run/netns"
+
+	// ifName is the name of the interface the CNI plugins create in the
+	// network namespaces
+	ifName = "eth0"
+)
+
+// Manager sets up and tears down the networks of the allocations.
+type Manager struct {
+	logger *log.Logger
+
+	cniPath      string
+	cniConfigDir string
+	bridgeName   string
+	bridgeSubnet string
+
+	// netnsDir is the directory of the network namespaces
+	netnsDir string
+}
+
+// NewManager returns a network manager using the CNI settings of the client.
+func NewManager(logger *log.Logger, config *config.Config) *Manager {
+	return &Manager{
+		logger:       logger,
+		cniPath:      config.CNIPath,
+		cniConfigDir: config.CNIConfigDir,
+		bridgeName:   config.BridgeNetworkName,
+		bridgeSubnet: config.BridgeNetworkSubnet,
+		netnsDir:     netnsDir,
+	}
+}
+
+// AllocNetNSPath returns the path of the network namespace of an allocation,
+// or an empty string if its tasks use the network of the host.
+func AllocNetNSPath(alloc *structs.Allocation) string {
+	if groupNetwork(alloc) == nil {
+		return ""
+	}
+	return filepath.Join(netnsDir, alloc.ID)
+}
+
+// groupNetwork returns the task group network of an allocation, or nil if its
+// tasks use the network of the host.
+func groupNetwork(alloc *structs.Allocation) *structs.NetworkResource {
+	if alloc.SharedResources == nil || len(alloc.SharedResources.Networks) == 0 {
+		return nil
+	}
+	n := alloc.SharedResources.Networks[0]
+	if n.Mode == "" || n.Mode == structs.NetworkModeHost {
+		return nil
+	}
+	return n
+}
+
+// Setup creates the network namespace of an allocation and sets up its network
+// with the CNI plugins. It is a no-op if the tasks of the allocation use the
+// network of the host, or if the namespace already exists because the client
+// restarted.
+func (m *Manager) Setup(alloc *structs.Allocation) error {
+	n := groupNetwork(alloc)
+	if n == nil {
+		return nil
+	}
+	path := filepath.Join(m.netnsDir, alloc.ID)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+
+	list, err := m.conflist(n.Mode)
+	if err != nil {
+		return err
+	}
+	if err := CreateNetNS(path); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	inv := &invoker{cniPath: m.cniPath, containerID: alloc.ID, netns: path}
+	ports := portMappings(n)
+	if _, err := inv.add(ctx, loopbackConflist(), "lo", nil); err != nil {
+		DeleteNetNS(path)
+		return fmt.Errorf("failed to set up loopback interface: %v", err)
+	}
+	result, err := inv.add(ctx, list, ifName, ports)
+	if err != nil {
+		// Release what the plugins already set up
+		inv.del(ctx, list, ifName, ports)
+		DeleteNetNS(path)
+		return fmt.Errorf("failed to set up %s network: %v", n.Mode, err)
+	}
+
+	m.logger.Printf("[DEBUG] client.network: set up %s network of alloc %q with addresses %v",
+		n.Mode, alloc.ID, result.addresses())
+	return nil
+}
+
+// Teardown releases the network of an allocation with the CNI plugins and
+// deletes its network namespace.
+func (m *Manager) Teardown(alloc *structs.Allocation) error {
+	n := groupNetwork(alloc)
+	if n == nil {
+		return nil
+	}
+	path := filepath.Join(m.netnsDir, alloc.ID)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	// Delete the namespace even if its network can't be released
+	var mErr multierror.Error
+	if list, err := m.conflist(n.Mode); err != nil {
+		multierror.Append(&mErr, err)
+	} else {
+		inv := &invoker{cniPath: m.cniPath, containerID: alloc.ID, netns: path}
+		if err := inv.del(context.Background(), list, ifName, portMappings(n)); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("failed to tear down %s network: %v", n.Mode, err))
+		}
+	}
+	if err := DeleteNetNS(path); err != nil {
+		multierror.Append(&mErr, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// conflist returns the CNI configuration of a network mode
+func (m *Manager) conflist(mode string) (*Conflist, error) {
+	if mode == structs.NetworkModeBridge {
+		return bridgeConflist(m.bridgeName, m.bridgeSubnet), nil
+	}
+	if !strings.HasPrefix(mode, structs.NetworkModeCNIPrefix) {
+		return nil, fmt.Errorf("unsupported network mode %q", mode)
+	}
+
+	name := strings.TrimPrefix(mode, structs.NetworkModeCNIPrefix)
+	configs, err := LoadConfigs(m.cniConfigDir)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := configs[name]
+	if !ok {
+		return nil, fmt.Errorf("CNI network %q not found in %q", name, m.cniConfigDir)
+	}
+	return list, nil
+}
+
+// portMappings returns the ports of a task group network mapped from the host
+// into the network namespace, for both TCP and UDP.
+func portMappings(n *structs.NetworkResource) []PortMapping {
+	var mappings []PortMapping
+	for _, ports := range [][]structs.Port{n.ReservedPorts, n.DynamicPorts} {
+		for _, p := range ports {
+			to := p.To
+			if to == 0 {
+				to = p.Value
+			}
+			for _, proto := range []string{"tcp", "udp"} {
+				mappings = append(mappings, PortMapping{
+					HostPort:      p.Value,
+					ContainerPort: to,
+					Protocol:      proto,
+					HostIP:        n.PortIP(p),
+				})
+			}
+		}
+	}
+	return mappings
+}
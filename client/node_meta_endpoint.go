@@ -0,0 +1,60 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/client/structs"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+)
+
+// NodeMeta endpoint is used for reading and updating the metadata of the node
+// at runtime
+type NodeMeta struct {
+	c *Client
+}
+
+// Apply sets the given metadata keys of the node and returns the resulting
+// metadata.
+func (n *NodeMeta) Apply(args *structs.NodeMetaApplyRequest, reply *structs.NodeMetaResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "apply"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeWrite() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	if len(args.Meta) == 0 {
+		return fmt.Errorf("missing metadata to apply")
+	}
+	for k := range args.Meta {
+		if k == "" {
+			return fmt.Errorf("metadata keys must not be empty")
+		}
+	}
+
+	if err := n.c.UpdateNodeMeta(args.Meta); err != nil {
+		return err
+	}
+
+	reply.Meta, reply.Dynamic, reply.Static = n.c.NodeMeta()
+	return nil
+}
+
+// Read returns the metadata of the node.
+func (n *NodeMeta) Read(args *nstructs.NodeSpecificRequest, reply *structs.NodeMetaResponse) error {
+	defer metrics.MeasureSince([]string{"client", "node_meta", "read"}, time.Now())
+
+	// Check node read permissions
+	if aclObj, err := n.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNodeRead() {
+		return nstructs.ErrPermissionDenied
+	}
+
+	reply.Meta, reply.Dynamic, reply.Static = n.c.NodeMeta()
+	return nil
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	nstructs "github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMeta_Apply(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	stateDir, err := ioutil.TempDir("", "nomad-node-meta")
+	require.NoError(err)
+	defer os.RemoveAll(stateDir)
+
+	cb := func(c *config.Config) {
+		c.StateDir = stateDir
+		c.Node.Meta = map[string]string{
+			"rack": "r1",
+			"zone": "z1",
+		}
+	}
+	client := TestClient(t, cb)
+
+	// Applying nothing fails
+	req := &structs.NodeMetaApplyRequest{}
+	var resp structs.NodeMetaResponse
+	err = client.ClientRPC("NodeMeta.Apply", &req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing metadata")
+
+	// Set a new key, override a configured key and unset another one
+	req.Meta = map[string]*string{
+		"canary": helper.StringToPtr("true"),
+		"rack":   helper.StringToPtr("r2"),
+		"zone":   nil,
+	}
+	require.NoError(client.ClientRPC("NodeMeta.Apply", &req, &resp))
+
+	expected := map[string]string{
+		"canary": "true",
+		"rack":   "r2",
+	}
+	require.Equal(expected, resp.Meta)
+	require.Equal(req.Meta, resp.Dynamic)
+	require.Equal(map[string]string{"rack": "r1", "zone": "z1"}, resp.Static)
+	require.Equal(expected, client.Node().Meta)
+
+	// Reading returns the same metadata
+	var readResp structs.NodeMetaResponse
+	require.NoError(client.ClientRPC("NodeMeta.Read", &nstructs.NodeSpecificRequest{}, &readResp))
+	require.Equal(resp.Meta, readResp.Meta)
+	require.Equal(resp.Dynamic, readResp.Dynamic)
+
+	// The metadata is restored by a new client using the same state
+	client.Shutdown()
+	client = TestClient(t, cb)
+	defer client.Shutdown()
+	require.Equal(expected, client.Node().Meta)
+}
+
+func TestNodeMeta_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	server, addr, root := testACLServer(t, nil)
+	defer server.Shutdown()
+
+	client := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{addr}
+		c.ACLEnabled = true
+	})
+	defer client.Shutdown()
+
+	applyReq := func(token string) *structs.NodeMetaApplyRequest {
+		req := &structs.NodeMetaApplyRequest{
+			Meta: map[string]*string{"canary": helper.StringToPtr("true")},
+		}
+		req.AuthToken = token
+		return req
+	}
+
+	// Try requests without a token and expect failure
+	{
+		var resp structs.NodeMetaResponse
+		err := client.ClientRPC("NodeMeta.Apply", applyReq(""), &resp)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+
+		err = client.ClientRPC("NodeMeta.Read", &nstructs.NodeSpecificRequest{}, &resp)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+	}
+
+	// A read token can read but not apply
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1005, "read", mock.NodePolicy(acl.PolicyRead))
+
+		var resp structs.NodeMetaResponse
+		err := client.ClientRPC("NodeMeta.Apply", applyReq(token.SecretID), &resp)
+		require.EqualError(err, nstructs.ErrPermissionDenied.Error())
+
+		req := &nstructs.NodeSpecificRequest{}
+		req.AuthToken = token.SecretID
+		require.NoError(client.ClientRPC("NodeMeta.Read", req, &resp))
+	}
+
+	// A write token can apply
+	{
+		token := mock.CreatePolicyAndToken(t, server.State(), 1007, "write", mock.NodePolicy(acl.PolicyWrite))
+
+		var resp structs.NodeMetaResponse
+		require.NoError(client.ClientRPC("NodeMeta.Apply", applyReq(token.SecretID), &resp))
+		require.Equal("true", resp.Meta["canary"])
+	}
+
+	// Try request with a management token
+	{
+		var resp structs.NodeMetaResponse
+		require.NoError(client.ClientRPC("NodeMeta.Apply", applyReq(root.SecretID), &resp))
+	}
+}
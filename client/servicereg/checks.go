@@ -0,0 +1,164 @@
+package servicereg
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// checkResult is the result of a run of a check
+type checkResult struct {
+	// service and check are the indexes of the check in the registrations
+	// of the task
+	service int
+	check   int
+
+	status string
+	output string
+}
+
+// checkRunner periodically runs an http or tcp check of a service
+type checkRunner struct {
+	service int
+	check   int
+
+	def    *structs.ServiceCheck
+	target string
+	client *http.Client
+}
+
+// newCheckRunner returns a runner for a check of the service at the given
+// indexes.
+func newCheckRunner(service, check int, def *structs.ServiceCheck, host string, port int) (*checkRunner, error) {
+	if port == 0 {
+		return nil, fmt.Errorf("%s checks require an address", def.Type)
+	}
+
+	r := &checkRunner{
+		service: service,
+		check:   check,
+		def:     def,
+		target:  net.JoinHostPort(host, strconv.Itoa(port)),
+	}
+
+	switch def.Type {
+	case structs.ServiceCheckHTTP:
+		proto := def.Protocol
+		if proto == "" {
+			proto = "http"
+		}
+		base := url.URL{
+			Scheme: proto,
+			Host:   r.target,
+		}
+		relative, err := url.Parse(def.Path)
+		if err != nil {
+			return nil, err
+		}
+		r.target = base.ResolveReference(relative).String()
+
+		transport := &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: def.TLSSkipVerify},
+		}
+		r.client = &http.Client{Transport: transport, Timeout: def.Timeout}
+
+	case structs.ServiceCheckTCP:
+
+	default:
+		return nil, fmt.Errorf("check type %+q not supported", def.Type)
+	}
+	return r, nil
+}
+
+// run runs the check at its interval and sends its results until the context
+// is canceled.
+func (r *checkRunner) run(ctx context.Context, results chan<- *checkResult) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		result := r.exec(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case results <- result:
+		}
+		timer.Reset(r.def.Interval)
+	}
+}
+
+// exec runs the check once
+func (r *checkRunner) exec(ctx context.Context) *checkResult {
+	result := &checkResult{
+		service: r.service,
+		check:   r.check,
+	}
+
+	switch r.def.Type {
+	case structs.ServiceCheckHTTP:
+		result.status, result.output = r.execHTTP(ctx)
+	case structs.ServiceCheckTCP:
+		result.status, result.output = r.execTCP(ctx)
+	}
+	return result
+}
+
+// execHTTP runs an http check. Like in Consul, 2xx responses are passing,
+// 429 responses are warning and any other response is critical.
+func (r *checkRunner) execHTTP(ctx context.Context) (string, string) {
+	method := r.def.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	req, err := http.NewRequest(method, r.target, nil)
+	if err != nil {
+		return api.HealthCritical, err.Error()
+	}
+	for name, values := range r.def.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return api.HealthCritical, err.Error()
+	}
+	resp.Body.Close()
+
+	output := fmt.Sprintf("HTTP %s %s: %s", method, r.target, resp.Status)
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return api.HealthPassing, output
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return api.HealthWarning, output
+	default:
+		return api.HealthCritical, output
+	}
+}
+
+// execTCP runs a tcp check, which passes if a connection can be established.
+func (r *checkRunner) execTCP(ctx context.Context) (string, string) {
+	dialer := net.Dialer{Timeout: r.def.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", r.target)
+	if err != nil {
+		return api.HealthCritical, err.Error()
+	}
+	conn.Close()
+	return api.HealthPassing, fmt.Sprintf("TCP connect %s: Success", r.target)
+}
@@ -0,0 +1,307 @@
+package servicereg
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// nomadTaskPrefix is the prefix of the IDs of the services registered
+	// by the tasks, matching the IDs of the services registered in Consul.
+	nomadTaskPrefix = "_nomad-task-"
+
+	// retryInterval is how long to wait before retrying to register the
+	// services of a task after a failure.
+	retryInterval = 5 * time.Second
+)
+
+// RPCer is the interface used to send the service registrations to the
+// servers.
+type RPCer interface {
+	RPC(method string, args, reply interface{}) error
+}
+
+// NomadServiceClient registers the services using the nomad provider with
+// the Nomad servers, and runs their checks.
+type NomadServiceClient struct {
+	logger *log.Logger
+	rpc    RPCer
+	region string
+	node   *structs.Node
+
+	tasks     map[string]*taskRegistrations
+	tasksLock sync.Mutex
+}
+
+// taskRegistrations are the service registrations of a task
+type taskRegistrations struct {
+	services []*structs.ServiceRegistration
+
+	// cancel stops the checks and the registration of the services
+	cancel context.CancelFunc
+
+	// lock serializes the upserts and deletions of the registrations, so
+	// that services can't be upserted once they have been removed
+	lock sync.Mutex
+}
+
+// NewNomadServiceClient returns a client registering the services of the
+// tasks running on the node.
+func NewNomadServiceClient(logger *log.Logger, rpc RPCer, region string, node *structs.Node) *NomadServiceClient {
+	return &NomadServiceClient{
+		logger: logger,
+		rpc:    rpc,
+		region: region,
+		node:   node,
+		tasks:  make(map[string]*taskRegistrations),
+	}
+}
+
+// RegisterTask registers the services of a task and starts their checks.
+func (c *NomadServiceClient) RegisterTask(task *agentconsul.TaskServices) error {
+	services, checks, err := c.taskRegistrations(task)
+	if err != nil {
+		return err
+	}
+	c.startTask(task, services, checks)
+	return nil
+}
+
+// UpdateTask replaces the services of a task, removing the registrations of
+// the services that are no longer defined.
+func (c *NomadServiceClient) UpdateTask(old, newTask *agentconsul.TaskServices) error {
+	services, checks, err := c.taskRegistrations(newTask)
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[string]struct{}, len(services))
+	for _, service := range services {
+		keep[service.ID] = struct{}{}
+	}
+	c.removeTask(old, keep)
+	c.startTask(newTask, services, checks)
+	return nil
+}
+
+// RemoveTask stops the checks of a task and removes the registrations of its
+// services.
+func (c *NomadServiceClient) RemoveTask(task *agentconsul.TaskServices) {
+	c.removeTask(task, nil)
+}
+
+// taskRegistrations builds the registrations of the services of a task and
+// the runners of their checks.
+func (c *NomadServiceClient) taskRegistrations(task *agentconsul.TaskServices) ([]*structs.ServiceRegistration, []*checkRunner, error) {
+	var services []*structs.ServiceRegistration
+	var checks []*checkRunner
+	for i, service := range task.Services {
+		ip, port, err := agentconsul.GetAddress(service.AddressMode, service.PortLabel, task.Networks, task.DriverNetwork)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to get address for service %q: %v", service.Name, err)
+		}
+
+		tags := service.Tags
+		if task.Canary && len(service.CanaryTags) > 0 {
+			tags = service.CanaryTags
+		}
+
+		reg := &structs.ServiceRegistration{
+			ID:          nomadTaskPrefix + service.Hash(task.AllocID, task.Name, task.Canary),
+			ServiceName: service.Name,
+			Namespace:   task.Namespace,
+			NodeID:      c.node.ID,
+			Datacenter:  c.node.Datacenter,
+			JobID:       task.JobID,
+			AllocID:     task.AllocID,
+			Tags:        tags,
+			Address:     ip,
+			Port:        port,
+		}
+
+		for j, check := range service.Checks {
+			// Default to the service's port but allow check to override
+			portLabel := check.PortLabel
+			if portLabel == "" {
+				portLabel = service.PortLabel
+			}
+
+			// Checks address mode defaults to host like in Consul
+			addrMode := check.AddressMode
+			if addrMode == "" {
+				addrMode = structs.AddressModeHost
+			}
+
+			ip, port, err := agentconsul.GetAddress(addrMode, portLabel, task.Networks, task.DriverNetwork)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error getting address for check %q: %v", check.Name, err)
+			}
+
+			runner, err := newCheckRunner(i, j, check, ip, port)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid check %q: %v", check.Name, err)
+			}
+			checks = append(checks, runner)
+
+			status := check.InitialStatus
+			if status == "" {
+				status = api.HealthCritical
+			}
+			reg.Checks = append(reg.Checks, &structs.ServiceRegistrationCheck{
+				Name:   check.Name,
+				Type:   check.Type,
+				Status: status,
+			})
+		}
+		services = append(services, reg)
+	}
+	return services, checks, nil
+}
+
+// startTask registers the services of a task and starts their checks,
+// replacing the previous registrations of the task.
+func (c *NomadServiceClient) startTask(task *agentconsul.TaskServices,
+	services []*structs.ServiceRegistration, checks []*checkRunner) {
+	if len(services) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &taskRegistrations{
+		services: services,
+		cancel:   cancel,
+	}
+
+	c.tasksLock.Lock()
+	existing := c.tasks[taskKey(task)]
+	c.tasks[taskKey(task)] = t
+	c.tasksLock.Unlock()
+
+	if existing != nil {
+		existing.lock.Lock()
+		existing.cancel()
+		existing.lock.Unlock()
+	}
+
+	go c.run(ctx, t, checks)
+}
+
+// removeTask stops the checks of a task and removes the registrations of its
+// services, except the ones to keep.
+func (c *NomadServiceClient) removeTask(task *agentconsul.TaskServices, keep map[string]struct{}) {
+	c.tasksLock.Lock()
+	t := c.tasks[taskKey(task)]
+	delete(c.tasks, taskKey(task))
+	c.tasksLock.Unlock()
+
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.cancel()
+
+	for _, service := range t.services {
+		if _, ok := keep[service.ID]; ok {
+			continue
+		}
+		req := structs.ServiceRegistrationDeleteByIDRequest{
+			ID: service.ID,
+			WriteRequest: structs.WriteRequest{
+				Region:    c.region,
+				Namespace: service.Namespace,
+				AuthToken: c.node.SecretID,
+			},
+		}
+		var resp structs.GenericResponse
+		if err := c.rpc.RPC("ServiceRegistration.DeleteByID", &req, &resp); err != nil {
+			// The servers remove the services of stopped allocations
+			// anyway, so only log the failure
+			if !strings.HasSuffix(err.Error(), "not found") {
+				c.logger.Printf("[WARN] client.service_registration: failed to remove service %q of task %q in alloc %q: %v",
+					service.ServiceName, task.Name, task.AllocID, err)
+			}
+		}
+	}
+}
+
+// run registers the services of a task, and updates them when the status
+// of their checks change, until the context is canceled.
+func (c *NomadServiceClient) run(ctx context.Context, t *taskRegistrations, checks []*checkRunner) {
+	results := make(chan *checkResult)
+	for _, check := range checks {
+		go check.run(ctx, results)
+	}
+
+	var retryCh <-chan time.Time
+	upsert := func() {
+		if err := c.upsert(ctx, t); err != nil {
+			c.logger.Printf("[WARN] client.service_registration: failed to register services of alloc %q: %v",
+				t.services[0].AllocID, err)
+			retryCh = time.After(retryInterval)
+			return
+		}
+		retryCh = nil
+	}
+	upsert()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-retryCh:
+			upsert()
+		case result := <-results:
+			check := t.services[result.service].Checks[result.check]
+			if check.Status == result.status && check.Output == result.output {
+				continue
+			}
+
+			// Replace the check so that in flight upserts aren't modified
+			t.lock.Lock()
+			t.services[result.service].Checks[result.check] = &structs.ServiceRegistrationCheck{
+				Name:   check.Name,
+				Type:   check.Type,
+				Status: result.status,
+				Output: result.output,
+			}
+			t.lock.Unlock()
+			upsert()
+		}
+	}
+}
+
+// upsert sends the registrations of a task to the servers, unless the task
+// has been removed.
+func (c *NomadServiceClient) upsert(ctx context.Context, t *taskRegistrations) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	req := structs.ServiceRegistrationUpsertRequest{
+		Services: t.services,
+		WriteRequest: structs.WriteRequest{
+			Region:    c.region,
+			AuthToken: c.node.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	return c.rpc.RPC("ServiceRegistration.Upsert", &req, &resp)
+}
+
+// taskKey returns the key of a task in the registrations
+func taskKey(task *agentconsul.TaskServices) string {
+	return task.AllocID + "/" + task.Name
+}
@@ -0,0 +1,71 @@
+package servicereg
+
+import (
+	"github.com/hashicorp/nomad/client/consul"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Handler implements the ConsulServiceAPI the task runners use to register
+// their services. It registers the services using the nomad provider in
+// Nomad and the other services in Consul.
+type Handler struct {
+	consul consul.ConsulServiceAPI
+	nomad  *NomadServiceClient
+}
+
+// NewHandler returns a handler routing the services between the Consul and
+// Nomad service clients.
+func NewHandler(consul consul.ConsulServiceAPI, nomad *NomadServiceClient) *Handler {
+	return &Handler{
+		consul: consul,
+		nomad:  nomad,
+	}
+}
+
+// RegisterTask registers the services of a task and starts their checks.
+func (h *Handler) RegisterTask(task *agentconsul.TaskServices) error {
+	consulTask, nomadTask := splitTaskServices(task)
+	if err := h.consul.RegisterTask(consulTask); err != nil {
+		return err
+	}
+	return h.nomad.RegisterTask(nomadTask)
+}
+
+// UpdateTask updates the services of a task.
+func (h *Handler) UpdateTask(old, newTask *agentconsul.TaskServices) error {
+	oldConsul, oldNomad := splitTaskServices(old)
+	newConsul, newNomad := splitTaskServices(newTask)
+	if err := h.consul.UpdateTask(oldConsul, newConsul); err != nil {
+		return err
+	}
+	return h.nomad.UpdateTask(oldNomad, newNomad)
+}
+
+// RemoveTask removes the services of a task and stops their checks.
+func (h *Handler) RemoveTask(task *agentconsul.TaskServices) {
+	consulTask, nomadTask := splitTaskServices(task)
+	h.consul.RemoveTask(consulTask)
+	h.nomad.RemoveTask(nomadTask)
+}
+
+// AllocRegistrations returns the Consul registrations of an allocation, which
+// are used to watch the health of its checks during deployments.
+func (h *Handler) AllocRegistrations(allocID string) (*agentconsul.AllocRegistration, error) {
+	return h.consul.AllocRegistrations(allocID)
+}
+
+// splitTaskServices splits the services of a task between the ones
+// registered in Consul and the ones registered in Nomad.
+func splitTaskServices(task *agentconsul.TaskServices) (*agentconsul.TaskServices, *agentconsul.TaskServices) {
+	consulTask, nomadTask := *task, *task
+	consulTask.Services, nomadTask.Services = nil, nil
+	for _, service := range task.Services {
+		if service.Provider == structs.ServiceProviderNomad {
+			nomadTask.Services = append(nomadTask.Services, service)
+		} else {
+			consulTask.Services = append(consulTask.Services, service)
+		}
+	}
+	return &consulTask, &nomadTask
+}
@@ -0,0 +1,149 @@
+package servicereg
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/nomad/client/consul"
+	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRPC records the registrations sent to the servers
+type mockRPC struct {
+	services map[string]*structs.ServiceRegistration
+	lock     sync.Mutex
+}
+
+func (m *mockRPC) RPC(method string, args, reply interface{}) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	switch method {
+	case "ServiceRegistration.Upsert":
+		for _, service := range args.(*structs.ServiceRegistrationUpsertRequest).Services {
+			m.services[service.ID] = service.Copy()
+		}
+	case "ServiceRegistration.DeleteByID":
+		delete(m.services, args.(*structs.ServiceRegistrationDeleteByIDRequest).ID)
+	}
+	return nil
+}
+
+func (m *mockRPC) registrations() []*structs.ServiceRegistration {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var services []*structs.ServiceRegistration
+	for _, service := range m.services {
+		services = append(services, service.Copy())
+	}
+	return services
+}
+
+func TestHandler_RegisterTask(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Serve the http check
+	var healthy bool
+	var healthyLock sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		healthyLock.Lock()
+		defer healthyLock.Unlock()
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+	_, portStr, err := net.SplitHostPort(ts.Listener.Addr().String())
+	require.NoError(err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(err)
+
+	rpc := &mockRPC{services: make(map[string]*structs.ServiceRegistration)}
+	consulClient := consul.NewMockConsulServiceClient(t)
+	node := mock.Node()
+	h := NewHandler(consulClient, NewNomadServiceClient(testlog.Logger(t), rpc, "global", node))
+
+	task := &agentconsul.TaskServices{
+		AllocID:   uuid.Generate(),
+		Namespace: structs.DefaultNamespace,
+		JobID:     "example",
+		Name:      "web",
+		Networks: structs.Networks{{
+			IP:            "127.0.0.1",
+			ReservedPorts: []structs.Port{{Label: "http", Value: port}},
+		}},
+		Services: []*structs.Service{
+			{
+				Name:      "web-consul",
+				PortLabel: "http",
+				Provider:  structs.ServiceProviderConsul,
+			},
+			{
+				Name:        "web",
+				PortLabel:   "http",
+				AddressMode: structs.AddressModeHost,
+				Provider:    structs.ServiceProviderNomad,
+				Checks: []*structs.ServiceCheck{{
+					Name:     "alive",
+					Type:     structs.ServiceCheckHTTP,
+					Path:     "/health",
+					Interval: 50 * time.Millisecond,
+					Timeout:  time.Second,
+				}},
+			},
+		},
+	}
+	require.NoError(h.RegisterTask(task))
+
+	// Only the consul service is registered in Consul
+	require.Len(consulClient.Ops, 1)
+	require.Equal("add", consulClient.Ops[0].Op)
+
+	// The nomad service is registered with a critical check
+	testutil.WaitForResult(func() (bool, error) {
+		services := rpc.registrations()
+		if len(services) != 1 {
+			return false, nil
+		}
+		return services[0].Checks[0].Output != "", nil
+	}, func(err error) {
+		t.Fatalf("service not registered")
+	})
+	service := rpc.registrations()[0]
+	require.Equal("web", service.ServiceName)
+	require.Equal(node.ID, service.NodeID)
+	require.Equal(task.AllocID, service.AllocID)
+	require.Equal("127.0.0.1", service.Address)
+	require.Equal(port, service.Port)
+	require.Equal(api.HealthCritical, service.Checks[0].Status)
+
+	// The registration is updated when the check passes
+	healthyLock.Lock()
+	healthy = true
+	healthyLock.Unlock()
+	testutil.WaitForResult(func() (bool, error) {
+		return rpc.registrations()[0].Healthy(), nil
+	}, func(err error) {
+		t.Fatalf("check not passing")
+	})
+
+	// The registration is removed with the task
+	h.RemoveTask(task)
+	require.Empty(rpc.registrations())
+	require.Len(consulClient.Ops, 2)
+	require.Equal("remove", consulClient.Ops[1].Op)
+}
@@ -17,12 +17,22 @@ allocations/ (bucket)
     |--> alloc_runner persisted objects (k/v)
 	|--> <task-name>/ (bucket)
         |--> task_runner persisted objects (k/v)
+
+nodemeta/ (bucket)
+|--> meta -> map[string]*string
 */
 
 var (
 	// allocationsBucket is the bucket name containing all allocation related
 	// data
 	allocationsBucket = []byte("allocations")
+
+	// nodeMetaBucket is the bucket name containing the metadata of the node
+	// that was set at runtime
+	nodeMetaBucket = []byte("nodemeta")
+
+	// nodeMetaKey is the key of the dynamic node metadata
+	nodeMetaKey = []byte("meta")
 )
 
 func PutObject(bkt *bolt.Bucket, key []byte, obj interface{}) error {
@@ -184,6 +194,8 @@ func DeleteTaskBucket(tx *bolt.Tx, allocID, taskName string) error {
 	return alloc.DeleteBucket(key)
 }
 
+// GetAllAllocationIDs returns the IDs of all allocations that have persisted
+// state.
 func GetAllAllocationIDs(tx *bolt.Tx) ([]string, error) {
 	allocationsBkt := tx.Bucket(allocationsBucket)
 	if allocationsBkt == nil {
@@ -194,10 +206,44 @@ func GetAllAllocationIDs(tx *bolt.Tx) ([]string, error) {
 	var allocIDs []string
 	c := allocationsBkt.Cursor()
 
-	// Iterate over all the buckets
-	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+	// Iterate over all the buckets. Nested buckets have a nil value, so any
+	// other keys are skipped as they can't hold allocation state.
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if v != nil {
+			continue
+		}
 		allocIDs = append(allocIDs, string(k))
 	}
 
 	return allocIDs, nil
 }
+
+// PutNodeMeta stores the dynamic metadata of the node. Keys with a nil value
+// unset the metadata of the same key in the client configuration.
+func PutNodeMeta(tx *bolt.Tx, meta map[string]*string) error {
+	if !tx.Writable() {
+		return fmt.Errorf("transaction must be writable")
+	}
+
+	bkt, err := tx.CreateBucketIfNotExists(nodeMetaBucket)
+	if err != nil {
+		return err
+	}
+
+	return PutObject(bkt, nodeMetaKey, meta)
+}
+
+// GetNodeMeta returns the dynamic metadata of the node or nil if none has been
+// stored.
+func GetNodeMeta(tx *bolt.Tx) (map[string]*string, error) {
+	bkt := tx.Bucket(nodeMetaBucket)
+	if bkt == nil || bkt.Get(nodeMetaKey) == nil {
+		return nil, nil
+	}
+
+	var meta map[string]*string
+	if err := GetObject(bkt, nodeMetaKey, &meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
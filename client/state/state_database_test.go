@@ -0,0 +1,120 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/stretchr/testify/require"
+)
+
+func testDB(t *testing.T) (*bolt.DB, func()) {
+	tmp, err := ioutil.TempFile("", "state-db")
+	require.NoError(t, err)
+	tmp.Close()
+
+	db, err := bolt.Open(tmp.Name(), 0600, nil)
+	require.NoError(t, err)
+
+	return db, func() {
+		db.Close()
+		os.Remove(tmp.Name())
+	}
+}
+
+func TestStateDatabase_ObjectRoundTrip(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	type taskState struct {
+		Name    string
+		Restart int
+	}
+	in := taskState{Name: "web", Restart: 2}
+
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		bkt, err := GetTaskBucket(tx, "alloc1", "web")
+		if err != nil {
+			return err
+		}
+		return PutObject(bkt, []byte("state"), &in)
+	}))
+
+	var out taskState
+	require.NoError(db.View(func(tx *bolt.Tx) error {
+		bkt, err := GetTaskBucket(tx, "alloc1", "web")
+		if err != nil {
+			return err
+		}
+		return GetObject(bkt, []byte("state"), &out)
+	}))
+	require.Equal(in, out)
+
+	// Missing buckets aren't created by read-only transactions
+	require.Error(db.View(func(tx *bolt.Tx) error {
+		_, err := GetTaskBucket(tx, "alloc1", "missing")
+		return err
+	}))
+}
+
+func TestStateDatabase_GetAllAllocationIDs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		for _, id := range []string{"alloc1", "alloc2", "alloc3"} {
+			if _, err := GetAllocationBucket(tx, id); err != nil {
+				return err
+			}
+		}
+
+		// Non-bucket keys in the allocations bucket are ignored
+		return PutData(tx.Bucket(allocationsBucket), []byte("version"), []byte("1"))
+	}))
+
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		return DeleteAllocationBucket(tx, "alloc2")
+	}))
+
+	var ids []string
+	require.NoError(db.View(func(tx *bolt.Tx) error {
+		var err error
+		ids, err = GetAllAllocationIDs(tx)
+		return err
+	}))
+	require.Equal([]string{"alloc1", "alloc3"}, ids)
+}
+
+func TestStateDatabase_NodeMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	db, cleanup := testDB(t)
+	defer cleanup()
+
+	// Nothing is returned before the metadata is stored
+	require.NoError(db.View(func(tx *bolt.Tx) error {
+		meta, err := GetNodeMeta(tx)
+		require.Nil(meta)
+		return err
+	}))
+
+	rack := "r1"
+	in := map[string]*string{
+		"rack":   &rack,
+		"canary": nil,
+	}
+	require.NoError(db.Update(func(tx *bolt.Tx) error {
+		return PutNodeMeta(tx, in)
+	}))
+
+	require.NoError(db.View(func(tx *bolt.Tx) error {
+		out, err := GetNodeMeta(tx)
+		require.Equal(in, out)
+		return err
+	}))
+}
@@ -36,6 +36,32 @@ type ClientStatsResponse struct {
 	structs.QueryMeta
 }
 
+// NodeMetaApplyRequest is used to set the metadata of a node at runtime.
+type NodeMetaApplyRequest struct {
+	structs.QueryOptions
+
+	// NodeID is the node whose metadata is updated
+	NodeID string
+
+	// Meta are the keys to set. A nil value unsets the key, including keys
+	// set in the client configuration.
+	Meta map[string]*string
+}
+
+// NodeMetaResponse is used to return the metadata of a node.
+type NodeMetaResponse struct {
+	// Meta is the effective metadata of the node
+	Meta map[string]string
+
+	// Dynamic is the metadata that was set at runtime
+	Dynamic map[string]*string
+
+	// Static is the metadata from the client configuration
+	Static map[string]string
+
+	structs.QueryMeta
+}
+
 // AllocFileInfo holds information about a file inside the AllocDir
 type AllocFileInfo struct {
 	Name     string
@@ -165,6 +191,58 @@ type AllocStatsResponse struct {
 	structs.QueryMeta
 }
 
+// AllocExecRequest is used to run a command in the context of a running task
+// of an allocation
+type AllocExecRequest struct {
+	// AllocID is the allocation the task belongs to
+	AllocID string
+
+	// Task is the task to run the command in
+	Task string
+
+	// Tty allocates a pseudo-terminal for the command
+	Tty bool
+
+	// Cmd is the command to run and its arguments
+	Cmd []string
+
+	structs.QueryOptions
+}
+
+// ExecStreamingIOOperation is a chunk of a stream of the command, or the close
+// of the stream.
+type ExecStreamingIOOperation struct {
+	Data  []byte `json:"data,omitempty"`
+	Close bool   `json:"close,omitempty"`
+}
+
+// TerminalSize is the size of the terminal of the command.
+type TerminalSize struct {
+	Height int `json:"height,omitempty"`
+	Width  int `json:"width,omitempty"`
+}
+
+// ExecStreamingInput is a message sent to a running command.
+type ExecStreamingInput struct {
+	Stdin   *ExecStreamingIOOperation `json:"stdin,omitempty"`
+	TTYSize *TerminalSize             `json:"tty_size,omitempty"`
+}
+
+// ExecStreamingExitResult is the result of a command that exited.
+type ExecStreamingExitResult struct {
+	ExitCode int `json:"exit_code"`
+}
+
+// ExecStreamingOutput is a message sent by a running command. The final
+// message has Exited set along with the result of the command.
+type ExecStreamingOutput struct {
+	Stdout *ExecStreamingIOOperation `json:"stdout,omitempty"`
+	Stderr *ExecStreamingIOOperation `json:"stderr,omitempty"`
+
+	Exited bool                     `json:"exited,omitempty"`
+	Result *ExecStreamingExitResult `json:"result,omitempty"`
+}
+
 // MemoryStats holds memory usage related stats
 type MemoryStats struct {
 	RSS            uint64
@@ -0,0 +1,48 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type ACLAuthMethodCommand struct {
+	Meta
+}
+
+func (f *ACLAuthMethodCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with ACL auth methods. An
+  auth method allows users to log in to Nomad with an external OIDC or JWT
+  identity provider. The binding rules of the auth method decide which roles
+  and policies the resulting ACL tokens are granted.
+  For a full guide see: https://www.nomadproject.io/guides/acl.html
+
+  Create an ACL auth method:
+
+      $ nomad acl auth-method apply -type OIDC -config <file> <name>
+
+  List ACL auth methods:
+
+      $ nomad acl auth-method list
+
+  Inspect an ACL auth method:
+
+      $ nomad acl auth-method info <name>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ACLAuthMethodCommand) Synopsis() string {
+	return "Interact with ACL auth methods"
+}
+
+func (f *ACLAuthMethodCommand) Name() string { return "acl auth-method" }
+
+func (f *ACLAuthMethodCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,159 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLAuthMethodApplyCommand struct {
+	Meta
+}
+
+func (c *ACLAuthMethodApplyCommand) Help() string {
+	helpText := `
+Usage: nomad acl auth-method apply [options] <name>
+
+  Apply is used to create or update an ACL auth method. Requires a management
+  token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Apply Options:
+
+  -type="OIDC"
+    Specifies the type of the auth method. Must be "OIDC" or "JWT".
+
+  -max-token-ttl="1h"
+    Specifies how long the tokens created by logging in with the auth method
+    are valid for.
+
+  -default
+    Marks the auth method as the default method of its type, which is used
+    when logging in without naming an auth method.
+
+  -config=<path>
+    Specifies the path to a JSON file holding the configuration of the auth
+    method. Required.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLAuthMethodApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"type":          complete.PredictSet("OIDC", "JWT"),
+			"max-token-ttl": complete.PredictAnything,
+			"default":       complete.PredictNothing,
+			"config":        complete.PredictFiles("*.json"),
+		})
+}
+
+func (c *ACLAuthMethodApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLAuthMethodApplyCommand) Synopsis() string {
+	return "Create or update an ACL auth method"
+}
+
+func (c *ACLAuthMethodApplyCommand) Name() string { return "acl auth-method apply" }
+
+func (c *ACLAuthMethodApplyCommand) Run(args []string) int {
+	var methodType, configPath string
+	var maxTokenTTL time.Duration
+	var isDefault bool
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&methodType, "type", api.ACLAuthMethodTypeOIDC, "")
+	flags.DurationVar(&maxTokenTTL, "max-token-ttl", time.Hour, "")
+	flags.BoolVar(&isDefault, "default", false, "")
+	flags.StringVar(&configPath, "config", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the auth method name
+	methodName := args[0]
+
+	if configPath == "" {
+		c.Ui.Error("The auth method configuration must be specified with -config")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	config, err := parseAuthMethodConfig(configPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading auth method configuration: %s", err))
+		return 1
+	}
+
+	// Construct the auth method
+	method := &api.ACLAuthMethod{
+		Name:        methodName,
+		Type:        strings.ToUpper(methodType),
+		MaxTokenTTL: maxTokenTTL,
+		Default:     isDefault,
+		Config:      config,
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Upsert the auth method
+	_, err = client.ACLAuthMethods().Upsert(method, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing ACL auth method: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully wrote %q ACL auth method!",
+		methodName))
+	return 0
+}
+
+// parseAuthMethodConfig reads an auth method configuration from a JSON file.
+// The clock skew leeway is given as a duration string such as "30s".
+func parseAuthMethodConfig(path string) (*api.ACLAuthMethodConfig, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config struct {
+		api.ACLAuthMethodConfig
+		ClockSkewLeeway string
+	}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, err
+	}
+
+	if config.ClockSkewLeeway != "" {
+		leeway, err := time.ParseDuration(config.ClockSkewLeeway)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ClockSkewLeeway: %v", err)
+		}
+		config.ACLAuthMethodConfig.ClockSkewLeeway = leeway
+	}
+
+	return &config.ACLAuthMethodConfig, nil
+}
@@ -0,0 +1,67 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodApplyCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	f, err := ioutil.TempFile("", "nomad-test")
+	require.NoError(err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`{
+  "JWKSURL": "https://example.com/keys",
+  "BoundAudiences": ["nomad"],
+  "ClockSkewLeeway": "30s",
+  "ClaimMappings": {"email": "email"}
+}`)
+	require.NoError(err)
+	f.Close()
+
+	ui := new(cli.MockUi)
+	cmd := &ACLAuthMethodApplyCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// An auth method requires a configuration
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "test"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "-config")
+
+	// Applying without a valid management token fails
+	code = cmd.Run([]string{"-address=" + url, "-token=foo", "-type=jwt", "-config=" + f.Name(), "test"})
+	require.Equal(1, code)
+
+	// Apply the auth method with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-type=jwt", "-max-token-ttl=30m", "-default", "-config=" + f.Name(), "test"})
+	require.Equal(0, code)
+	require.Contains(ui.OutputWriter.String(), `Successfully wrote "test" ACL auth method`)
+
+	method, err := state.ACLAuthMethodByName(nil, "test")
+	require.NoError(err)
+	require.NotNil(method)
+	require.Equal("JWT", method.Type)
+	require.True(method.Default)
+	require.Equal(30*time.Minute, method.MaxTokenTTL)
+	require.Equal(30*time.Second, method.Config.ClockSkewLeeway)
+	require.Equal(map[string]string{"email": "email"}, method.Config.ClaimMappings)
+}
@@ -0,0 +1,55 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodDeleteCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a test auth method with a binding rule
+	method := mock.ACLAuthMethod()
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLAuthMethodDeleteCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Deleting the auth method without a valid token fails
+	code := cmd.Run([]string{"-address=" + url, "-token=foo", method.Name})
+	require.Equal(1, code)
+
+	// Delete the auth method with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, method.Name})
+	require.Equal(0, code)
+	require.Contains(ui.OutputWriter.String(), "Successfully deleted")
+
+	out, err := state.ACLAuthMethodByName(nil, method.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	// The binding rules of the auth method are deleted with it
+	outRule, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Nil(outRule)
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLAuthMethodInfoCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a test auth method
+	method := mock.ACLAuthMethod()
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLAuthMethodInfoCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Fetching the auth method without a valid token fails
+	code := cmd.Run([]string{"-address=" + url, "-token=foo", method.Name})
+	require.Equal(1, code)
+
+	// Fetch the auth method with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, method.Name})
+	require.Equal(0, code)
+	out := ui.OutputWriter.String()
+	require.Contains(out, method.Name)
+	require.Contains(out, "https://example.com/keys")
+	require.Contains(out, "sub=user")
+}
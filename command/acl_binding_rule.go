@@ -0,0 +1,49 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type ACLBindingRuleCommand struct {
+	Meta
+}
+
+func (f *ACLBindingRuleCommand) Help() string {
+	helpText := `
+Usage: nomad acl binding-rule <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with ACL binding rules. A
+  binding rule decides which roles or policies are granted to the ACL token
+  created when a user logs in with an auth method, based on the claims of the
+  user's identity.
+  For a full guide see: https://www.nomadproject.io/guides/acl.html
+
+  Create an ACL binding rule:
+
+      $ nomad acl binding-rule apply -auth-method <name> -bind-type role \
+          -bind-name <role> -selector '"engineering" in list.groups'
+
+  List ACL binding rules:
+
+      $ nomad acl binding-rule list
+
+  Inspect an ACL binding rule:
+
+      $ nomad acl binding-rule info <id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *ACLBindingRuleCommand) Synopsis() string {
+	return "Interact with ACL binding rules"
+}
+
+func (f *ACLBindingRuleCommand) Name() string { return "acl binding-rule" }
+
+func (f *ACLBindingRuleCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ACLBindingRuleApplyCommand struct {
+	Meta
+}
+
+func (c *ACLBindingRuleApplyCommand) Help() string {
+	helpText := `
+Usage: nomad acl binding-rule apply [options]
+
+  Apply is used to create or update an ACL binding rule. Requires a management
+  token.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Apply Options:
+
+  -id=""
+    Specifies the ID of an existing binding rule to update. A new binding rule
+    is created if it is not set.
+
+  -auth-method=""
+    Specifies the name of the auth method the binding rule applies to.
+    Required.
+
+  -selector=""
+    Specifies an expression matched against the claims of a login, such as
+    '"engineering" in list.groups'. The rule applies to every login of the auth
+    method if it is not set.
+
+  -bind-type="role"
+    Specifies what the binding rule grants. Must be "role", "policy" or
+    "management".
+
+  -bind-name=""
+    Specifies the name of the role or policy to grant. It may interpolate
+    claim values, such as "team-${value.team}".
+
+  -description=""
+    Specifies a human readable description for the binding rule.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLBindingRuleApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"id":          complete.PredictAnything,
+			"auth-method": complete.PredictAnything,
+			"selector":    complete.PredictAnything,
+			"bind-type":   complete.PredictSet("role", "policy", "management"),
+			"bind-name":   complete.PredictAnything,
+			"description": complete.PredictAnything,
+		})
+}
+
+func (c *ACLBindingRuleApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLBindingRuleApplyCommand) Synopsis() string {
+	return "Create or update an ACL binding rule"
+}
+
+func (c *ACLBindingRuleApplyCommand) Name() string { return "acl binding-rule apply" }
+
+func (c *ACLBindingRuleApplyCommand) Run(args []string) int {
+	rule := &api.ACLBindingRule{}
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&rule.ID, "id", "", "")
+	flags.StringVar(&rule.AuthMethod, "auth-method", "", "")
+	flags.StringVar(&rule.Selector, "selector", "", "")
+	flags.StringVar(&rule.BindType, "bind-type", api.ACLBindingRuleBindTypeRole, "")
+	flags.StringVar(&rule.BindName, "bind-name", "", "")
+	flags.StringVar(&rule.Description, "description", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if rule.AuthMethod == "" {
+		c.Ui.Error("The auth method must be specified with -auth-method")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Create or update the binding rule
+	if rule.ID == "" {
+		rule, _, err = client.ACLBindingRules().Create(rule, nil)
+	} else {
+		rule, _, err = client.ACLBindingRules().Update(rule, nil)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing ACL binding rule: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatKVBindingRule(rule))
+	return 0
+}
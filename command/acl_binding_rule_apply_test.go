@@ -0,0 +1,71 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleApplyCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a test auth method
+	method := mock.ACLAuthMethod()
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLBindingRuleApplyCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// A binding rule requires an auth method
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-bind-name=foo"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "-auth-method")
+
+	// Applying without a valid management token fails
+	code = cmd.Run([]string{"-address=" + url, "-token=foo", "-auth-method=" + method.Name, "-bind-name=foo"})
+	require.Equal(1, code)
+
+	// An invalid selector is rejected
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-auth-method=" + method.Name, "-bind-name=foo", "-selector=value.team"})
+	require.Equal(1, code)
+
+	// Create the binding rule with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-auth-method=" + method.Name, "-bind-name=foo", `-selector="dev" in list.groups`})
+	require.Equal(0, code)
+
+	iter, err := state.ACLBindingRulesByAuthMethod(nil, method.Name)
+	require.NoError(err)
+	raw := iter.Next()
+	require.NotNil(raw)
+	rule := raw.(*structs.ACLBindingRule)
+	require.Equal("foo", rule.BindName)
+	require.Contains(ui.OutputWriter.String(), rule.ID)
+
+	// Update the binding rule
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-id=" + rule.ID,
+		"-auth-method=" + method.Name, "-bind-type=policy", "-bind-name=bar"})
+	require.Equal(0, code)
+
+	rule, err = state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Equal(structs.ACLBindingRuleBindTypePolicy, rule.BindType)
+	require.Equal("bar", rule.BindName)
+}
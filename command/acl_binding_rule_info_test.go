@@ -0,0 +1,45 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleInfoCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a test binding rule
+	rule := mock.ACLBindingRule()
+	require.NoError(state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLBindingRuleInfoCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Fetching the binding rule without a valid token fails
+	code := cmd.Run([]string{"-address=" + url, "-token=foo", rule.ID})
+	require.Equal(1, code)
+
+	// Fetch the binding rule with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, rule.ID})
+	require.Equal(0, code)
+	out := ui.OutputWriter.String()
+	require.Contains(out, rule.ID)
+	require.Contains(out, rule.Selector)
+}
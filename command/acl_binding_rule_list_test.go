@@ -0,0 +1,55 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLBindingRuleListCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create test binding rules for two auth methods
+	rule1 := mock.ACLBindingRule()
+	rule2 := mock.ACLBindingRule()
+	rule2.AuthMethod = "other"
+	require.NoError(state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule1, rule2}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLBindingRuleListCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// List the binding rules
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID})
+	require.Equal(0, code)
+	require.Contains(ui.OutputWriter.String(), rule1.ID)
+	require.Contains(ui.OutputWriter.String(), rule2.ID)
+	ui.OutputWriter.Reset()
+
+	// List the binding rules of one auth method
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-auth-method=other"})
+	require.Equal(0, code)
+	require.NotContains(ui.OutputWriter.String(), rule1.ID)
+	require.Contains(ui.OutputWriter.String(), rule2.ID)
+	ui.OutputWriter.Reset()
+
+	// List json
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-json"})
+	require.Equal(0, code)
+	require.Contains(ui.OutputWriter.String(), "CreateIndex")
+}
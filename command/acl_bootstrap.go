@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
@@ -98,18 +99,93 @@ func formatKVACLToken(token *api.ACLToken) string {
 		fmt.Sprintf("Global|%v", token.Global),
 	}
 
-	// Special case the policy output
+	// Special case the policy and role output
 	if token.Type == "management" {
-		output = append(output, "Policies|n/a")
+		output = append(output, "Policies|n/a", "Roles|n/a")
 	} else {
-		output = append(output, fmt.Sprintf("Policies|%v", token.Policies))
+		output = append(output,
+			fmt.Sprintf("Policies|%v", token.Policies),
+			fmt.Sprintf("Roles|%v", token.Roles))
 	}
 
 	// Add the generic output
+	expiration := "never"
+	if token.ExpirationTime != nil {
+		expiration = token.ExpirationTime.String()
+	}
 	output = append(output,
 		fmt.Sprintf("Create Time|%v", token.CreateTime),
+		fmt.Sprintf("Expiration Time|%s", expiration),
 		fmt.Sprintf("Create Index|%d", token.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", token.ModifyIndex),
 	)
 	return formatKV(output)
 }
+
+// formatKVRole returns a K/V formatted ACL role
+func formatKVRole(role *api.ACLRole) string {
+	output := []string{
+		fmt.Sprintf("Name|%s", role.Name),
+		fmt.Sprintf("Description|%s", role.Description),
+		fmt.Sprintf("Policies|%s", strings.Join(role.Policies, ",")),
+		fmt.Sprintf("CreateIndex|%v", role.CreateIndex),
+		fmt.Sprintf("ModifyIndex|%v", role.ModifyIndex),
+	}
+	return formatKV(output)
+}
+
+// formatKVAuthMethod returns a K/V formatted ACL auth method
+func formatKVAuthMethod(method *api.ACLAuthMethod) string {
+	output := []string{
+		fmt.Sprintf("Name|%s", method.Name),
+		fmt.Sprintf("Type|%s", method.Type),
+		fmt.Sprintf("Default|%v", method.Default),
+		fmt.Sprintf("Max Token TTL|%v", method.MaxTokenTTL),
+	}
+
+	if c := method.Config; c != nil {
+		output = append(output,
+			fmt.Sprintf("OIDC Discovery URL|%s", c.OIDCDiscoveryURL),
+			fmt.Sprintf("OIDC Client ID|%s", c.OIDCClientID),
+			fmt.Sprintf("OIDC Scopes|%s", strings.Join(c.OIDCScopes, ",")),
+			fmt.Sprintf("Allowed Redirect URIs|%s", strings.Join(c.AllowedRedirectURIs, ",")),
+			fmt.Sprintf("JWKS URL|%s", c.JWKSURL),
+			fmt.Sprintf("Bound Issuer|%s", c.BoundIssuer),
+			fmt.Sprintf("Bound Audiences|%s", strings.Join(c.BoundAudiences, ",")),
+			fmt.Sprintf("Claim Mappings|%s", formatClaimMappings(c.ClaimMappings)),
+			fmt.Sprintf("List Claim Mappings|%s", formatClaimMappings(c.ListClaimMappings)),
+		)
+	}
+
+	output = append(output,
+		fmt.Sprintf("CreateIndex|%v", method.CreateIndex),
+		fmt.Sprintf("ModifyIndex|%v", method.ModifyIndex),
+	)
+	return formatKV(output)
+}
+
+// formatClaimMappings returns the claim mappings of an auth method as a
+// sorted list of claim=name pairs
+func formatClaimMappings(m map[string]string) string {
+	mappings := make([]string, 0, len(m))
+	for claim, name := range m {
+		mappings = append(mappings, claim+"="+name)
+	}
+	sort.Strings(mappings)
+	return strings.Join(mappings, ",")
+}
+
+// formatKVBindingRule returns a K/V formatted ACL binding rule
+func formatKVBindingRule(rule *api.ACLBindingRule) string {
+	output := []string{
+		fmt.Sprintf("ID|%s", rule.ID),
+		fmt.Sprintf("Description|%s", rule.Description),
+		fmt.Sprintf("Auth Method|%s", rule.AuthMethod),
+		fmt.Sprintf("Selector|%s", rule.Selector),
+		fmt.Sprintf("Bind Type|%s", rule.BindType),
+		fmt.Sprintf("Bind Name|%s", rule.BindName),
+		fmt.Sprintf("CreateIndex|%v", rule.CreateIndex),
+		fmt.Sprintf("ModifyIndex|%v", rule.ModifyIndex),
+	}
+	return formatKV(output)
+}
@@ -0,0 +1,49 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleApplyCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	ui := new(cli.MockUi)
+	cmd := &ACLRoleApplyCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// A role requires a policy
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "test"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "At least one policy")
+
+	// Applying without a valid management token fails
+	code = cmd.Run([]string{"-address=" + url, "-token=foo", "-policy=foo", "test"})
+	require.Equal(1, code)
+
+	// Apply the role with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID,
+		"-policy=foo", "-policy=bar", "-description=testing", "test"})
+	require.Equal(0, code)
+	require.Contains(ui.OutputWriter.String(), `Successfully wrote "test" ACL role`)
+
+	role, err := state.ACLRoleByName(nil, "test")
+	require.NoError(err)
+	require.NotNil(role)
+	require.Equal([]string{"foo", "bar"}, role.Policies)
+	require.Equal("testing", role.Description)
+}
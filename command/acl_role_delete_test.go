@@ -0,0 +1,48 @@
+package command
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleDeleteCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a test role
+	role := mock.ACLRole()
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLRoleDeleteCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Delete the role without a valid token fails
+	code := cmd.Run([]string{"-address=" + url, "-token=foo", role.Name})
+	require.Equal(1, code)
+
+	// Delete the role with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, role.Name})
+	require.Equal(0, code)
+	require.Contains(ui.OutputWriter.String(), fmt.Sprintf("Successfully deleted %s role", role.Name))
+
+	out, err := state.ACLRoleByName(nil, role.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleInfoCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a test role
+	role := mock.ACLRole()
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLRoleInfoCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Fetching the role without a valid token fails
+	code := cmd.Run([]string{"-address=" + url, "-token=foo", role.Name})
+	require.Equal(1, code)
+
+	// Fetch the role with a valid management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, role.Name})
+	require.Equal(0, code)
+	out := ui.OutputWriter.String()
+	require.Contains(out, role.Name)
+	require.Contains(out, "foo,bar")
+}
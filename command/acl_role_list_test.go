@@ -0,0 +1,46 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestACLRoleListCommand(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	require.NotNil(token, "failed to bootstrap ACL token")
+
+	// Create a test role
+	role := mock.ACLRole()
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role}))
+
+	ui := new(cli.MockUi)
+	cmd := &ACLRoleListCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// List the roles with a valid management token
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID})
+	require.Equal(0, code)
+	require.Contains(ui.OutputWriter.String(), role.Name)
+	require.Contains(ui.OutputWriter.String(), "foo,bar")
+	ui.OutputWriter.Reset()
+
+	// List json
+	code = cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, "-json"})
+	require.Equal(0, code)
+	require.Contains(ui.OutputWriter.String(), "CreateIndex")
+}
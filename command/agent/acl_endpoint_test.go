@@ -5,9 +5,11 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/hashicorp/nomad/lib/auth"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_ACLPolicyList(t *testing.T) {
@@ -187,6 +189,188 @@ func TestHTTP_ACLPolicyDelete(t *testing.T) {
 	})
 }
 
+func TestHTTP_ACLRoleCRUD(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the role
+		r1 := mock.ACLRole()
+		req, err := http.NewRequest("PUT", "/v1/acl/role/"+r1.Name, encodeReq(r1))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Nil(obj)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Query the role
+		req, err = http.NewRequest("GET", "/v1/acl/role/"+r1.Name, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(err)
+		out := obj.(*structs.ACLRole)
+		require.Equal(r1.Name, out.Name)
+		require.Equal(r1.Policies, out.Policies)
+
+		// List the roles
+		req, err = http.NewRequest("GET", "/v1/acl/roles", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLRolesRequest(respW, req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.ACLRoleListStub), 1)
+
+		// Delete the role
+		req, err = http.NewRequest("DELETE", "/v1/acl/role/"+r1.Name, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		_, err = s.Server.ACLRoleSpecificRequest(respW, req)
+		require.NoError(err)
+
+		role, err := s.Agent.server.State().ACLRoleByName(nil, r1.Name)
+		require.NoError(err)
+		require.Nil(role)
+	})
+}
+
+func TestHTTP_ACLAuthMethodAndBindingRuleCRUD(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create the auth method
+		m1 := mock.ACLAuthMethod()
+		req, err := http.NewRequest("PUT", "/v1/acl/auth-method/"+m1.Name, encodeReq(m1))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err := s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Nil(obj)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Query the auth method
+		req, err = http.NewRequest("GET", "/v1/acl/auth-method/"+m1.Name, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Equal(m1.Config, obj.(*structs.ACLAuthMethod).Config)
+
+		// List the auth methods without a token
+		req, err = http.NewRequest("GET", "/v1/acl/auth-methods", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		obj, err = s.Server.ACLAuthMethodsRequest(respW, req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.ACLAuthMethodListStub), 1)
+
+		// Create a binding rule
+		r1 := mock.ACLBindingRule()
+		r1.ID = ""
+		r1.AuthMethod = m1.Name
+		req, err = http.NewRequest("PUT", "/v1/acl/binding-rule", encodeReq(r1))
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(err)
+		rule := obj.(*structs.ACLBindingRule)
+		require.NotEmpty(rule.ID)
+
+		// List the binding rules of the method
+		req, err = http.NewRequest("GET", "/v1/acl/binding-rules?auth_method="+m1.Name, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLBindingRulesRequest(respW, req)
+		require.NoError(err)
+		require.Len(obj.([]*structs.ACLBindingRuleListStub), 1)
+
+		// Query the binding rule
+		req, err = http.NewRequest("GET", "/v1/acl/binding-rule/"+rule.ID, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+
+		obj, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Equal(rule.Selector, obj.(*structs.ACLBindingRule).Selector)
+
+		// Delete the binding rule and the auth method
+		req, err = http.NewRequest("DELETE", "/v1/acl/binding-rule/"+rule.ID, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLBindingRuleSpecificRequest(respW, req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("DELETE", "/v1/acl/auth-method/"+m1.Name, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		setToken(req, s.RootToken)
+		_, err = s.Server.ACLAuthMethodSpecificRequest(respW, req)
+		require.NoError(err)
+
+		method, err := s.Agent.server.State().ACLAuthMethodByName(nil, m1.Name)
+		require.NoError(err)
+		require.Nil(method)
+	})
+}
+
+func TestHTTP_ACLLogin(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		provider := auth.NewTestProvider(t)
+		defer provider.Stop()
+
+		state := s.Agent.server.State()
+		method := mock.ACLAuthMethod()
+		method.Default = true
+		method.Config = &structs.ACLAuthMethodConfig{
+			JWTValidationPubKeys: []string{provider.PublicKeyPEM()},
+			ListClaimMappings:    map[string]string{"groups": "groups"},
+		}
+		require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+		rule := mock.ACLBindingRule()
+		rule.AuthMethod = method.Name
+		rule.BindType = structs.ACLBindingRuleBindTypePolicy
+		require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+		args := structs.ACLLoginRequest{
+			LoginToken: provider.SignToken(auth.Claims{"groups": []string{"engineering"}}),
+		}
+		req, err := http.NewRequest("POST", "/v1/acl/login", encodeReq(args))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.ACLLoginRequest(respW, req)
+		require.NoError(err)
+		token := obj.(*structs.ACLToken)
+		require.Equal([]string{"engineering"}, token.Policies)
+		require.NotNil(token.ExpirationTime)
+	})
+}
+
 func TestHTTP_ACLTokenBootstrap(t *testing.T) {
 	t.Parallel()
 	conf := func(c *Config) {
@@ -20,6 +20,7 @@ import (
 	uuidparse "github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/nomad/client"
 	clientconfig "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/command/agent/audit"
 	"github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad"
@@ -64,6 +65,9 @@ type Agent struct {
 
 	server *nomad.Server
 
+	// auditor writes the audit log of the API requests, if enabled
+	auditor *audit.Auditor
+
 	shutdown     bool
 	shutdownCh   chan struct{}
 	shutdownLock sync.Mutex
@@ -90,6 +94,9 @@ func NewAgent(config *Config, logOutput io.Writer, inmem *metrics.InmemSink) (*A
 
 	// TODO setup plugin loader
 
+	if err := a.setupAudit(); err != nil {
+		return nil, err
+	}
 	if err := a.setupServer(); err != nil {
 		return nil, err
 	}
@@ -178,6 +185,9 @@ func convertServerConfig(agentConfig *Config, logOutput io.Writer) (*nomad.Confi
 	if agentConfig.Sentinel != nil {
 		conf.SentinelConfig = agentConfig.Sentinel
 	}
+	if agentConfig.Server.Admission != nil {
+		conf.AdmissionConfig = agentConfig.Server.Admission
+	}
 	if agentConfig.Server.NonVotingServer {
 		conf.NonVoter = true
 	}
@@ -286,6 +296,12 @@ func convertServerConfig(agentConfig *Config, logOutput io.Writer) (*nomad.Confi
 	if maxHPS := agentConfig.Server.MaxHeartbeatsPerSecond; maxHPS != 0 {
 		conf.MaxHeartbeatsPerSecond = maxHPS
 	}
+	if size := agentConfig.Server.EventBufferSize; size != 0 {
+		if size < 0 {
+			return nil, fmt.Errorf("event_buffer_size must be positive: %d", size)
+		}
+		conf.EventBufferSize = size
+	}
 
 	if *agentConfig.Consul.AutoAdvertise && agentConfig.Consul.ServerServiceName == "" {
 		return nil, fmt.Errorf("server_service_name must be set when auto_advertise is enabled")
@@ -354,6 +370,8 @@ func (a *Agent) clientConfig() (*clientconfig.Config, error) {
 	if a.config.Client.AllocDir != "" {
 		conf.AllocDir = a.config.Client.AllocDir
 	}
+	conf.PluginDir = a.config.PluginDir
+	conf.PluginConfigs = a.config.Plugins
 	if a.config.Client.NetworkInterface != "" {
 		conf.NetworkInterface = a.config.Client.NetworkInterface
 	}
@@ -399,6 +417,15 @@ func (a *Agent) clientConfig() (*clientconfig.Config, error) {
 	conf.Node.Name = a.config.NodeName
 	conf.Node.Meta = a.config.Client.Meta
 	conf.Node.NodeClass = a.config.Client.NodeClass
+	conf.Node.NodePool = a.config.Client.NodePool
+	if pool := conf.Node.NodePool; pool != "" {
+		if pool == structs.NodePoolAll {
+			return nil, fmt.Errorf("node_pool %q is reserved and can't be used by clients", pool)
+		}
+		if err := structs.ValidateNodePool(pool); err != nil {
+			return nil, err
+		}
+	}
 
 	// Set up the HTTP advertise address
 	conf.Node.HTTPAddr = a.config.AdvertiseAddrs.HTTP
@@ -440,6 +467,7 @@ func (a *Agent) clientConfig() (*clientconfig.Config, error) {
 	conf.GCParallelDestroys = a.config.Client.GCParallelDestroys
 	conf.GCDiskUsageThreshold = a.config.Client.GCDiskUsageThreshold
 	conf.GCInodeUsageThreshold = a.config.Client.GCInodeUsageThreshold
+	conf.GCMaxAge = a.config.Client.GCMaxAge
 	conf.GCMaxAllocs = a.config.Client.GCMaxAllocs
 	if a.config.Client.NoHostUUID != nil {
 		conf.NoHostUUID = *a.config.Client.NoHostUUID
@@ -448,6 +476,43 @@ func (a *Agent) clientConfig() (*clientconfig.Config, error) {
 		conf.NoHostUUID = true
 	}
 
+	// Setup the host volumes
+	hvMap := make(map[string]*structs.ClientHostVolumeConfig, len(a.config.Client.HostVolumes))
+	for _, v := range a.config.Client.HostVolumes {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid host_volume %q: %v", v.Name, err)
+		}
+		hvMap[v.Name] = v.Copy()
+	}
+	conf.HostVolumes = hvMap
+
+	// Setup the host networks
+	hnMap := make(map[string]*structs.ClientHostNetworkConfig, len(a.config.Client.HostNetworks))
+	for _, n := range a.config.Client.HostNetworks {
+		if err := n.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid host_network %q: %v", n.Name, err)
+		}
+		hnMap[n.Name] = n.Copy()
+	}
+	conf.HostNetworks = hnMap
+
+	// Setup the CNI networking
+	if a.config.Client.CNIPath != "" {
+		conf.CNIPath = a.config.Client.CNIPath
+	}
+	if a.config.Client.CNIConfigDir != "" {
+		conf.CNIConfigDir = a.config.Client.CNIConfigDir
+	}
+	if a.config.Client.BridgeNetworkName != "" {
+		conf.BridgeNetworkName = a.config.Client.BridgeNetworkName
+	}
+	if a.config.Client.BridgeNetworkSubnet != "" {
+		if _, _, err := net.ParseCIDR(a.config.Client.BridgeNetworkSubnet); err != nil {
+			return nil, fmt.Errorf("invalid bridge_network_subnet %q: %v", a.config.Client.BridgeNetworkSubnet, err)
+		}
+		conf.BridgeNetworkSubnet = a.config.Client.BridgeNetworkSubnet
+	}
+
 	// Setup the ACLs
 	conf.ACLEnabled = a.config.ACL.Enabled
 	conf.ACLTokenTTL = a.config.ACL.TokenTTL
@@ -824,6 +889,12 @@ func (a *Agent) Shutdown() error {
 		a.logger.Printf("[ERR] agent: shutting down Consul client failed: %v", err)
 	}
 
+	if a.auditor != nil {
+		if err := a.auditor.Close(); err != nil {
+			a.logger.Printf("[ERR] agent: closing audit log failed: %v", err)
+		}
+	}
+
 	a.logger.Println("[INFO] agent: shutdown complete")
 	a.shutdown = true
 	close(a.shutdownCh)
@@ -194,6 +194,12 @@ func TestAgent_ServerConfig(t *testing.T) {
 		t.Fatalf("expect 11, got: %v", max)
 	}
 
+	conf.Server.EventBufferSize = 200
+	out, err = a.serverConfig()
+	if size := out.EventBufferSize; size != 200 {
+		t.Fatalf("expect 200, got: %v", size)
+	}
+
 	// Defaults to the global bind addr
 	conf.Addresses.RPC = ""
 	conf.Addresses.Serf = ""
@@ -1,18 +1,33 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/snappy"
+	"github.com/gorilla/websocket"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ugorji/go/codec"
 )
 
 const (
 	allocNotFoundErr    = "allocation not found"
 	resourceNotFoundErr = "resource not found"
+
+	// execIdleTimeout is how long an exec session waits for a message or a
+	// pong from the remote side before failing. Pings are sent at half of it.
+	execIdleTimeout = 60 * time.Second
+
+	// execMaxMessageSize is the largest message read from an exec session
+	execMaxMessageSize = 16 * 1024 * 1024
 )
 
 func (s *HTTPServer) AllocsRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -20,7 +35,9 @@ func (s *HTTPServer) AllocsRequest(resp http.ResponseWriter, req *http.Request)
 		return nil, CodedError(405, ErrInvalidMethod)
 	}
 
-	args := structs.AllocListRequest{}
+	args := structs.AllocListRequest{
+		FilterClientStatus: req.URL.Query().Get("client_status"),
+	}
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
 	}
@@ -41,7 +58,17 @@ func (s *HTTPServer) AllocsRequest(resp http.ResponseWriter, req *http.Request)
 }
 
 func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
-	allocID := strings.TrimPrefix(req.URL.Path, "/v1/allocation/")
+	reqSuffix := strings.TrimPrefix(req.URL.Path, "/v1/allocation/")
+
+	// Stopping an allocation is the only action on a specific allocation
+	tokens := strings.Split(reqSuffix, "/")
+	if len(tokens) == 2 && tokens[1] == "stop" {
+		return s.allocStop(tokens[0], resp, req)
+	} else if len(tokens) != 1 {
+		return nil, CodedError(404, resourceNotFoundErr)
+	}
+
+	allocID := tokens[0]
 	if req.Method != "GET" {
 		return nil, CodedError(405, ErrInvalidMethod)
 	}
@@ -78,6 +105,28 @@ func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Re
 	return alloc, nil
 }
 
+func (s *HTTPServer) allocStop(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if !(req.Method == "POST" || req.Method == "PUT") {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.AllocStopRequest{
+		AllocID: allocID,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.AllocStopResponse
+	if err := s.agent.RPC("Alloc.Stop", &args, &out); err != nil {
+		if structs.IsErrUnknownAllocation(err) {
+			return nil, CodedError(404, err.Error())
+		}
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return &out, nil
+}
+
 func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 
 	reqSuffix := strings.TrimPrefix(req.URL.Path, "/v1/client/allocation/")
@@ -100,6 +149,12 @@ func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Requ
 		return s.allocSnapshot(allocID, resp, req)
 	case "gc":
 		return s.allocGC(allocID, resp, req)
+	case "exec":
+		return s.allocExec(allocID, resp, req)
+	case "signal":
+		return s.allocSignal(allocID, resp, req)
+	case "restart":
+		return s.allocRestart(allocID, resp, req)
 	}
 
 	return nil, CodedError(404, resourceNotFoundErr)
@@ -172,6 +227,272 @@ func (s *HTTPServer) allocGC(allocID string, resp http.ResponseWriter, req *http
 	return nil, rpcErr
 }
 
+func (s *HTTPServer) allocSignal(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if !(req.Method == "POST" || req.Method == "PUT") {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Build the request and parse the ACL token
+	args := structs.AllocSignalRequest{}
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, fmt.Sprintf("Failed to decode body: %v", err))
+	}
+	if args.Signal == "" {
+		return nil, CodedError(400, "must provide a signal")
+	}
+	args.AllocID = allocID
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForAlloc(allocID)
+
+	// Make the RPC
+	var reply structs.GenericResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("Allocations.Signal", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientAllocations.Signal", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientAllocations.Signal", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+	}
+
+	return reply, rpcErr
+}
+
+func (s *HTTPServer) allocRestart(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if !(req.Method == "POST" || req.Method == "PUT") {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	// Build the request and parse the ACL token. The body is optional and
+	// only used to select a single task to restart.
+	args := structs.AllocRestartRequest{}
+	if req.ContentLength != 0 {
+		if err := decodeBody(req, &args); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Failed to decode body: %v", err))
+		}
+	}
+	args.AllocID = allocID
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForAlloc(allocID)
+
+	// Make the RPC
+	var reply structs.GenericResponse
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC("Allocations.Restart", &args, &reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC("ClientAllocations.Restart", &args, &reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC("ClientAllocations.Restart", &args, &reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) || structs.IsErrUnknownAllocation(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+	}
+
+	return reply, rpcErr
+}
+
+func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request and parse the ACL token
+	q := req.URL.Query()
+	task := q.Get("task")
+	if task == "" {
+		return nil, CodedError(400, taskNotPresentErr.Error())
+	}
+
+	var command []string
+	if cmd := q.Get("command"); cmd == "" {
+		return nil, CodedError(400, "must provide a command")
+	} else if err := json.Unmarshal([]byte(cmd), &command); err != nil {
+		return nil, CodedError(400, fmt.Sprintf("failed to parse command: %v", err))
+	}
+
+	tty := false
+	if ttyStr := q.Get("tty"); ttyStr != "" {
+		var err error
+		if tty, err = strconv.ParseBool(ttyStr); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("failed to parse tty value %q: %v", ttyStr, err))
+		}
+	}
+
+	args := cstructs.AllocExecRequest{
+		AllocID: allocID,
+		Task:    task,
+		Tty:     tty,
+		Cmd:     command,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	if !websocket.IsWebSocketUpgrade(req) {
+		return nil, CodedError(400, "exec requires a websocket connection")
+	}
+
+	// Handshake errors are returned rather than written by the upgrader so
+	// that they are reported like the errors of other requests. Upgrades
+	// from another origin are rejected, since browsers don't apply the
+	// same-origin policy to websockets.
+	var upgradeErr error
+	upgrader := websocket.Upgrader{
+		Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+			upgradeErr = CodedError(status, reason.Error())
+		},
+	}
+	conn, err := upgrader.Upgrade(resp, req, nil)
+	if upgradeErr != nil {
+		return nil, upgradeErr
+	} else if err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	defer conn.Close()
+
+	// Fail the session if the remote side stops answering pings
+	conn.SetReadLimit(execMaxMessageSize)
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(execIdleTimeout))
+	})
+	pingStopCh := make(chan struct{})
+	defer close(pingStopCh)
+	go execPing(conn, pingStopCh)
+
+	// The connection has been hijacked so errors are reported by closing the
+	// websocket rather than through the response
+	code, msg := websocket.CloseNormalClosure, ""
+	if err := s.execStreamImpl(conn, &args); err != nil {
+		code, msg = websocket.CloseInternalServerErr, err.Error()
+		if len(msg) > 123 {
+			// Close reasons are limited to 123 bytes
+			msg = msg[:123]
+		}
+	}
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, msg),
+		time.Now().Add(time.Second))
+	return nil, nil
+}
+
+// execPing pings the remote side of the exec session until stopped, so that
+// the session isn't failed while idle.
+func execPing(conn *websocket.Conn, stopCh chan struct{}) {
+	ticker := time.NewTicker(execIdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(time.Second)); err != nil {
+				return
+			}
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// execStreamImpl bridges the websocket to the exec streaming RPC. Input
+// messages are JSON encoded ExecStreamingInput and output messages are JSON
+// encoded ExecStreamingOutput.
+func (s *HTTPServer) execStreamImpl(ws *websocket.Conn, args *cstructs.AllocExecRequest) error {
+	allocID := args.AllocID
+	method := "Allocations.Exec"
+
+	// Get the correct handler
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if localClient {
+		handler, handlerErr = s.agent.Client().StreamingRpcHandler(method)
+	} else if remoteClient {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
+	} else if localServer {
+		handler, handlerErr = s.agent.Server().StreamingRpcHandler(method)
+	}
+
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	// Create a pipe connecting the (possibly remote) handler to the websocket
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe when the session ends.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	go handler(handlerPipe)
+
+	// Create a goroutine that sends the request followed by the input of the
+	// websocket
+	go func() {
+		defer cancel()
+
+		if err := encoder.Encode(args); err != nil {
+			return
+		}
+		encoder.Reset(httpPipe)
+
+		for {
+			ws.SetReadDeadline(time.Now().Add(execIdleTimeout))
+			_, msg, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			var in cstructs.ExecStreamingInput
+			if err := json.Unmarshal(msg, &in); err != nil {
+				s.logger.Printf("[DEBUG] http: failed to decode exec input: %v", err)
+				return
+			}
+
+			if err := encoder.Encode(&in); err != nil {
+				return
+			}
+			encoder.Reset(httpPipe)
+		}
+	}()
+
+	for {
+		var res cstructs.StreamErrWrapper
+		if err := decoder.Decode(&res); err != nil {
+			// The handler closes the stream once the command exits
+			if err == io.EOF || strings.Contains(err.Error(), "closed") {
+				return nil
+			}
+			return err
+		}
+		decoder.Reset(httpPipe)
+
+		if err := res.Error; err != nil {
+			return err
+		}
+
+		if err := ws.WriteMessage(websocket.TextMessage, res.Payload); err != nil {
+			return err
+		}
+	}
+}
+
 func (s *HTTPServer) allocSnapshot(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	var secret string
 	s.parseToken(req, &secret)
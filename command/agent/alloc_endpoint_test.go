@@ -2,17 +2,21 @@ package agent
 
 import (
 	"archive/tar"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/golang/snappy"
+	"github.com/gorilla/websocket"
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/helper"
@@ -85,6 +89,31 @@ func TestHTTP_AllocsList(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocsList_ClientStatus(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		alloc1 := mock.Alloc()
+		alloc2 := mock.Alloc()
+		alloc2.ClientStatus = structs.AllocClientStatusRunning
+		require.NoError(state.UpsertJobSummary(998, mock.JobSummary(alloc1.JobID)))
+		require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc2.JobID)))
+		require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc1, alloc2}))
+
+		req, err := http.NewRequest("GET", "/v1/allocations?client_status=running", nil)
+		require.NoError(err)
+		obj, err := s.Server.AllocsRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+
+		allocs := obj.([]*structs.AllocListStub)
+		require.Len(allocs, 1)
+		require.Equal(alloc2.ID, allocs[0].ID)
+	})
+}
+
 func TestHTTP_AllocsPrefixList(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -541,6 +570,183 @@ func TestHTTP_AllocSnapshot_Atomic(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocSignal(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	path := fmt.Sprintf("/v1/client/allocation/%s/signal", uuid.Generate())
+	httpTest(t, nil, func(s *TestAgent) {
+		// Only writes are allowed
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(err)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, ErrInvalidMethod)
+
+		// A signal is required
+		req, err = http.NewRequest("POST", path, encodeReq(structs.AllocSignalRequest{}))
+		require.NoError(err)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "must provide a signal")
+
+		// Unknown allocations are not found
+		req, err = http.NewRequest("POST", path, encodeReq(structs.AllocSignalRequest{Signal: "SIGHUP"}))
+		require.NoError(err)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		require.True(structs.IsErrUnknownAllocation(err))
+	})
+}
+
+func TestHTTP_AllocRestart(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	path := fmt.Sprintf("/v1/client/allocation/%s/restart", uuid.Generate())
+	httpTest(t, nil, func(s *TestAgent) {
+		// Only writes are allowed
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(err)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, ErrInvalidMethod)
+
+		// Unknown allocations are not found, with or without a task
+		req, err = http.NewRequest("POST", path, nil)
+		require.NoError(err)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		require.True(structs.IsErrUnknownAllocation(err))
+
+		req, err = http.NewRequest("POST", path, encodeReq(structs.AllocRestartRequest{TaskName: "web"}))
+		require.NoError(err)
+		_, err = s.Server.ClientAllocRequest(httptest.NewRecorder(), req)
+		require.True(structs.IsErrUnknownAllocation(err))
+	})
+}
+
+func TestHTTP_AllocStop(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+		require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+		// Only writes are allowed
+		path := fmt.Sprintf("/v1/allocation/%s/stop", alloc.ID)
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(err)
+		_, err = s.Server.AllocSpecificRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, ErrInvalidMethod)
+
+		// Unknown allocations are not found
+		req, err = http.NewRequest("POST", fmt.Sprintf("/v1/allocation/%s/stop", uuid.Generate()), nil)
+		require.NoError(err)
+		_, err = s.Server.AllocSpecificRequest(httptest.NewRecorder(), req)
+		require.True(structs.IsErrUnknownAllocation(err))
+
+		// Stop the allocation
+		req, err = http.NewRequest("POST", path, nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.AllocSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		out := obj.(*structs.AllocStopResponse)
+		require.NotEmpty(out.EvalID)
+	})
+}
+
+func TestHTTP_AllocExec(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	allocID := uuid.Generate()
+	httpTest(t, nil, func(s *TestAgent) {
+		// Missing parameters are rejected before upgrading
+		{
+			path := fmt.Sprintf("/v1/client/allocation/%s/exec?task=web", allocID)
+			req, err := http.NewRequest("GET", path, nil)
+			require.NoError(err)
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.ClientAllocRequest(respW, req)
+			require.Error(err)
+			require.Contains(err.Error(), "must provide a command")
+		}
+
+		// Upgrades from another origin are forbidden
+		{
+			path := fmt.Sprintf("/v1/client/allocation/%s/exec?task=web&command=%s", allocID, url.QueryEscape(`["/bin/sh"]`))
+			req, err := http.NewRequest("GET", path, nil)
+			require.NoError(err)
+			req.Host = "127.0.0.1:4646"
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+			req.Header.Set("Sec-WebSocket-Version", "13")
+			req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+			req.Header.Set("Origin", "http://evil.com")
+
+			respW := httptest.NewRecorder()
+			_, err = s.Server.ClientAllocRequest(respW, req)
+			require.Error(err)
+			codedErr, ok := err.(HTTPCodedError)
+			require.True(ok)
+			require.Equal(403, codedErr.Code())
+		}
+
+		// Errors of the session close the websocket
+		{
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if _, err := s.Server.ClientAllocRequest(w, r); err != nil {
+					w.WriteHeader(500)
+					w.Write([]byte(err.Error()))
+				}
+			}))
+			defer srv.Close()
+
+			u, err := url.Parse(srv.URL)
+			require.NoError(err)
+			u.Scheme = "ws"
+			u.Path = fmt.Sprintf("/v1/client/allocation/%s/exec", allocID)
+			u.RawQuery = url.Values{
+				"task":    []string{"web"},
+				"command": []string{`["/bin/sh"]`},
+			}.Encode()
+
+			conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+			require.NoError(err)
+			defer conn.Close()
+
+			_, _, err = conn.ReadMessage()
+			require.True(websocket.IsCloseError(err, websocket.CloseInternalServerErr))
+			require.True(structs.IsErrUnknownAllocation(err))
+		}
+	})
+}
+
+func TestHTTP_AllocExec_API(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		a := mockFSAlloc(s.client.NodeID(), map[string]interface{}{
+			"run_for": "20s",
+		})
+		addAllocToClient(s, a, runningClientAlloc)
+
+		// Run a command through the API client
+		alloc, _, err := s.Client().Allocations().Info(a.ID, nil)
+		require.NoError(err)
+
+		var stdout, stderr bytes.Buffer
+		code, err := s.Client().Allocations().Exec(context.Background(), alloc,
+			a.Job.TaskGroups[0].Tasks[0].Name, false, []string{"echo", "hello"},
+			nil, &stdout, &stderr, nil, nil)
+		require.NoError(err)
+		require.Equal(0, code)
+		require.Equal(`Exec("echo", ["hello"])`, stdout.String())
+		require.Empty(stderr.String())
+	})
+}
+
 func TestHTTP_AllocGC(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
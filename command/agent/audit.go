@@ -0,0 +1,119 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent/audit"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// setupAudit is used to set up the audit logging of the API requests if it
+// is enabled
+func (a *Agent) setupAudit() error {
+	conf := a.config.Audit
+	if conf == nil || conf.Enabled == nil || !*conf.Enabled {
+		return nil
+	}
+
+	auditor, err := audit.NewAuditor(conf, a.logger)
+	if err != nil {
+		return fmt.Errorf("Failed to set up audit logging: %v", err)
+	}
+	a.auditor = auditor
+	return nil
+}
+
+// auditReceived writes the event of a request being received, and returns
+// it to complete once the request is handled. The event is nil if audit
+// logging is disabled. An error is returned if the request must fail
+// because its event couldn't be written.
+func (s *HTTPServer) auditReceived(req *http.Request) (*audit.Event, error) {
+	auditor := s.agent.auditor
+	if auditor == nil {
+		return nil, nil
+	}
+
+	event := &audit.Event{
+		ID:    uuid.Generate(),
+		Stage: audit.OperationReceived,
+		Auth:  s.auditAuth(req),
+		Request: &audit.Request{
+			ID:        uuid.Generate(),
+			Operation: req.Method,
+			Endpoint:  req.URL.Path,
+			Namespace: req.URL.Query().Get("namespace"),
+			RequestMeta: map[string]string{
+				"remote_address": req.RemoteAddr,
+				"user_agent":     req.UserAgent(),
+			},
+		},
+	}
+	if err := auditor.Event(event); err != nil {
+		s.logger.Printf("[ERR] http: Request %v, audit failed: %v", req.URL.Path, err)
+		return nil, err
+	}
+	return event, nil
+}
+
+// auditComplete writes the event of a request being handled, with the status
+// code and error of its response. An error is returned if the request must
+// fail because its event couldn't be written.
+func (s *HTTPServer) auditComplete(received *audit.Event, code int, errMsg string) error {
+	if received == nil {
+		return nil
+	}
+
+	event := *received
+	event.ID = uuid.Generate()
+	event.Stage = audit.OperationComplete
+	event.Timestamp = time.Time{}
+	event.Response = &audit.Response{
+		StatusCode: code,
+		Error:      errMsg,
+	}
+	if err := s.agent.auditor.Event(&event); err != nil {
+		s.logger.Printf("[ERR] http: Request %v, audit failed: %v", event.Request.Endpoint, err)
+		return err
+	}
+	return nil
+}
+
+// auditAuth returns the actor of the request, described by its ACL token. It
+// is nil if ACLs are disabled, and marked as unresolved if the token can't be
+// resolved. The token is resolved by the local server, or through the
+// client's token cache, so that auditing doesn't add an RPC per request.
+func (s *HTTPServer) auditAuth(req *http.Request) *audit.Auth {
+	if !s.agent.config.ACL.Enabled {
+		return nil
+	}
+
+	var secret string
+	s.parseToken(req, &secret)
+
+	var token *structs.ACLToken
+	var err error
+	if srv := s.agent.Server(); srv != nil {
+		token, err = srv.ResolveSecretToken(secret)
+	} else if client := s.agent.Client(); client != nil {
+		token, err = client.ResolveSecretToken(secret)
+	}
+	if err != nil {
+		s.logger.Printf("[WARN] http: failed to resolve token for audit: %v", err)
+		return &audit.Auth{Unresolved: true}
+	}
+	if token == nil {
+		return nil
+	}
+
+	return &audit.Auth{
+		AccessorID: token.AccessorID,
+		Name:       token.Name,
+		Type:       token.Type,
+		Policies:   token.Policies,
+		Global:     token.Global,
+		CreateTime: token.CreateTime,
+	}
+}
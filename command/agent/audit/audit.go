@@ -0,0 +1,230 @@
+// Package audit implements the audit logging of the HTTP API requests made to
+// a Nomad agent. Every request produces an event when it's received and when
+// it completes, which is written as JSON to the configured sinks unless a
+// filter excludes it.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// eventVersion is the version of the format of the events
+	eventVersion = 1
+
+	// eventType is the type of the entries of the audit log
+	eventType = "audit"
+)
+
+// Stage is the stage of the request an event is about
+type Stage string
+
+const (
+	// OperationReceived is the stage of a request that was received, before
+	// it's handled
+	OperationReceived Stage = "OperationReceived"
+
+	// OperationComplete is the stage of a request that was handled, before
+	// the response is sent
+	OperationComplete Stage = "OperationComplete"
+)
+
+const (
+	// SinkTypeFile writes the events to a file
+	SinkTypeFile = "file"
+
+	// FormatJSON writes every event as a line of JSON
+	FormatJSON = "json"
+
+	// DeliveryEnforced fails the request if its events can't be written
+	DeliveryEnforced = "enforced"
+
+	// DeliveryBestEffort only logs the events that can't be written
+	DeliveryBestEffort = "best-effort"
+
+	// FilterTypeHTTP filters events of HTTP requests
+	FilterTypeHTTP = "HTTPEvent"
+)
+
+// Event is an entry of the audit log
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Stage     Stage     `json:"stage"`
+	Timestamp time.Time `json:"timestamp"`
+	Version   int       `json:"version"`
+	Auth      *Auth     `json:"auth,omitempty"`
+	Request   *Request  `json:"request"`
+	Response  *Response `json:"response,omitempty"`
+}
+
+// Auth is the actor making the request, described by its ACL token
+type Auth struct {
+	AccessorID string    `json:"accessor_id"`
+	Name       string    `json:"name"`
+	Type       string    `json:"type"`
+	Policies   []string  `json:"policies"`
+	Global     bool      `json:"global"`
+	CreateTime time.Time `json:"create_time"`
+
+	// Unresolved is set if the token of the request couldn't be resolved,
+	// in which case the fields describing the token are empty
+	Unresolved bool `json:"unresolved,omitempty"`
+}
+
+// Request describes the request the event is about
+type Request struct {
+	ID          string            `json:"id"`
+	Operation   string            `json:"operation"`
+	Endpoint    string            `json:"endpoint"`
+	Namespace   string            `json:"namespace,omitempty"`
+	RequestMeta map[string]string `json:"request_meta,omitempty"`
+}
+
+// Response describes the outcome of the request
+type Response struct {
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Sink is a destination of the audit events
+type Sink interface {
+	// Write writes an encoded event
+	Write(p []byte) (int, error)
+
+	// Close closes the sink
+	Close() error
+}
+
+// sink is a configured sink
+type sink struct {
+	name     string
+	enforced bool
+	Sink
+}
+
+// Auditor writes the events of the requests to the sinks
+type Auditor struct {
+	sinks   []*sink
+	filters []*filter
+	logger  *log.Logger
+}
+
+// NewAuditor returns an auditor writing to the sinks of the configuration
+func NewAuditor(conf *config.AuditConfig, logger *log.Logger) (*Auditor, error) {
+	if len(conf.Sinks) == 0 {
+		return nil, fmt.Errorf("at least one audit sink is required")
+	}
+
+	a := &Auditor{logger: logger}
+	for _, f := range conf.Filters {
+		filter, err := newFilter(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid audit filter %q: %v", f.Name, err)
+		}
+		a.filters = append(a.filters, filter)
+	}
+
+	for _, s := range conf.Sinks {
+		sink, err := newSink(s)
+		if err != nil {
+			a.Close()
+			return nil, fmt.Errorf("invalid audit sink %q: %v", s.Name, err)
+		}
+		a.sinks = append(a.sinks, sink)
+	}
+	return a, nil
+}
+
+func newSink(conf *config.AuditSink) (*sink, error) {
+	if conf.Type != SinkTypeFile {
+		return nil, fmt.Errorf("unsupported type %q", conf.Type)
+	}
+	if conf.Format != "" && conf.Format != FormatJSON {
+		return nil, fmt.Errorf("unsupported format %q", conf.Format)
+	}
+
+	var enforced bool
+	switch conf.DeliveryGuarantee {
+	case DeliveryEnforced:
+		enforced = true
+	case DeliveryBestEffort:
+	default:
+		return nil, fmt.Errorf("delivery_guarantee must be %q or %q", DeliveryEnforced, DeliveryBestEffort)
+	}
+
+	if conf.Path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	mode := os.FileMode(0600)
+	if conf.Mode != "" {
+		m, err := strconv.ParseUint(conf.Mode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mode %q: %v", conf.Mode, err)
+		}
+		mode = os.FileMode(m)
+	}
+
+	f, err := newFileSink(conf.Path, mode, conf.RotateDuration, int64(conf.RotateBytes), conf.RotateMaxFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &sink{
+		name:     conf.Name,
+		enforced: enforced,
+		Sink:     f,
+	}, nil
+}
+
+// Event writes the event to the sinks, unless it's filtered. An error is
+// returned if it couldn't be written to a sink with an enforced delivery
+// guarantee, in which case the request must fail.
+func (a *Auditor) Event(e *Event) error {
+	for _, f := range a.filters {
+		if f.matches(e) {
+			return nil
+		}
+	}
+
+	e.Type = eventType
+	e.Version = eventVersion
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %v", err)
+	}
+	buf = append(buf, '\n')
+
+	var mErr multierror.Error
+	for _, s := range a.sinks {
+		if _, err := s.Write(buf); err != nil {
+			if s.enforced {
+				multierror.Append(&mErr, fmt.Errorf("failed to write audit event to sink %q: %v", s.name, err))
+			} else {
+				a.logger.Printf("[WARN] audit: failed to write event to sink %q: %v", s.name, err)
+			}
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Close closes the sinks
+func (a *Auditor) Close() error {
+	var mErr multierror.Error
+	for _, s := range a.sinks {
+		if err := s.Close(); err != nil {
+			multierror.Append(&mErr, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
@@ -0,0 +1,183 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvents returns the events written to the file
+func readEvents(t *testing.T, path string) []*Event {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []*Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, &e)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func testEvent(stage Stage, method, endpoint string) *Event {
+	return &Event{
+		ID:    "e",
+		Stage: stage,
+		Request: &Request{
+			ID:        "r",
+			Operation: method,
+			Endpoint:  endpoint,
+		},
+	}
+}
+
+func TestAuditor_Event(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit", "audit.log")
+
+	a, err := NewAuditor(&config.AuditConfig{
+		Sinks: []*config.AuditSink{
+			{
+				Name:              "file",
+				Type:              SinkTypeFile,
+				DeliveryGuarantee: DeliveryEnforced,
+				Path:              path,
+			},
+		},
+		Filters: []*config.AuditFilter{
+			{
+				Name:       "metrics",
+				Type:       FilterTypeHTTP,
+				Endpoints:  []string{"/v1/metrics", "/v1/agent/*"},
+				Operations: []string{"get"},
+			},
+			{
+				Name:   "received",
+				Type:   FilterTypeHTTP,
+				Stages: []string{string(OperationReceived)},
+			},
+		},
+	}, testlog.Logger(t))
+	require.NoError(err)
+	defer a.Close()
+
+	// Filtered events
+	require.NoError(a.Event(testEvent(OperationComplete, "GET", "/v1/metrics")))
+	require.NoError(a.Event(testEvent(OperationComplete, "GET", "/v1/agent/self")))
+	require.NoError(a.Event(testEvent(OperationReceived, "PUT", "/v1/jobs")))
+
+	// Audited events
+	complete := testEvent(OperationComplete, "PUT", "/v1/jobs")
+	complete.Response = &Response{StatusCode: 200}
+	require.NoError(a.Event(complete))
+	require.NoError(a.Event(testEvent(OperationComplete, "PUT", "/v1/agent/join")))
+
+	events := readEvents(t, path)
+	require.Len(events, 2)
+	require.Equal("/v1/jobs", events[0].Request.Endpoint)
+	require.Equal(OperationComplete, events[0].Stage)
+	require.Equal(eventType, events[0].Type)
+	require.Equal(eventVersion, events[0].Version)
+	require.False(events[0].Timestamp.IsZero())
+	require.Equal(200, events[0].Response.StatusCode)
+	require.Equal("/v1/agent/join", events[1].Request.Endpoint)
+
+	info, err := os.Stat(path)
+	require.NoError(err)
+	require.Equal(os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestAuditor_DeliveryGuarantee(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	newAuditor := func(guarantee string) *Auditor {
+		a, err := NewAuditor(&config.AuditConfig{
+			Sinks: []*config.AuditSink{
+				{
+					Name:              guarantee,
+					Type:              SinkTypeFile,
+					DeliveryGuarantee: guarantee,
+					Path:              filepath.Join(dir, guarantee+".log"),
+				},
+			},
+		}, testlog.Logger(t))
+		require.NoError(err)
+		return a
+	}
+
+	// Closed sinks fail to write
+	enforced := newAuditor(DeliveryEnforced)
+	require.NoError(enforced.Close())
+	err = enforced.Event(testEvent(OperationReceived, "GET", "/v1/jobs"))
+	require.Error(err)
+	require.Contains(err.Error(), `sink "enforced"`)
+
+	bestEffort := newAuditor(DeliveryBestEffort)
+	require.NoError(bestEffort.Close())
+	require.NoError(bestEffort.Event(testEvent(OperationReceived, "GET", "/v1/jobs")))
+}
+
+func TestNewAuditor_Invalid(t *testing.T) {
+	t.Parallel()
+
+	sink := func(f func(s *config.AuditSink)) *config.AuditConfig {
+		s := &config.AuditSink{
+			Name:              "file",
+			Type:              SinkTypeFile,
+			DeliveryGuarantee: DeliveryEnforced,
+			Path:              filepath.Join(os.TempDir(), "nomad-audit-invalid.log"),
+		}
+		f(s)
+		return &config.AuditConfig{Sinks: []*config.AuditSink{s}}
+	}
+
+	cases := []struct {
+		name   string
+		config *config.AuditConfig
+		err    string
+	}{
+		{"no sink", &config.AuditConfig{}, "at least one audit sink"},
+		{"type", sink(func(s *config.AuditSink) { s.Type = "syslog" }), `unsupported type "syslog"`},
+		{"format", sink(func(s *config.AuditSink) { s.Format = "xml" }), `unsupported format "xml"`},
+		{"delivery", sink(func(s *config.AuditSink) { s.DeliveryGuarantee = "" }), "delivery_guarantee"},
+		{"path", sink(func(s *config.AuditSink) { s.Path = "" }), "path is required"},
+		{"mode", sink(func(s *config.AuditSink) { s.Mode = "rw" }), "invalid mode"},
+		{
+			"filter",
+			&config.AuditConfig{
+				Sinks:   sink(func(*config.AuditSink) {}).Sinks,
+				Filters: []*config.AuditFilter{{Name: "f", Type: FilterTypeHTTP, Stages: []string{"Nope"}}},
+			},
+			`unknown stage "Nope"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := NewAuditor(c.config, testlog.Logger(t))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.err)
+		})
+	}
+}
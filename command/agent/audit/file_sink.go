@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileSink writes the events to a file, which is rotated on an interval or
+// once it reaches a size. Rotated files are renamed with the time of the
+// rotation, and only the most recent ones are kept.
+type fileSink struct {
+	path           string
+	mode           os.FileMode
+	rotateDuration time.Duration
+	rotateBytes    int64
+	rotateMaxFiles int
+
+	l         sync.Mutex
+	file      *os.File
+	size      int64
+	createdAt time.Time
+}
+
+func newFileSink(path string, mode os.FileMode, rotateDuration time.Duration, rotateBytes int64, rotateMaxFiles int) (*fileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %v", err)
+	}
+
+	f := &fileSink{
+		path:           path,
+		mode:           mode,
+		rotateDuration: rotateDuration,
+		rotateBytes:    rotateBytes,
+		rotateMaxFiles: rotateMaxFiles,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the file for appending
+func (f *fileSink) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.mode)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %v", err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log: %v", err)
+	}
+
+	f.file = file
+	f.size = stat.Size()
+	f.createdAt = time.Now()
+	return nil
+}
+
+func (f *fileSink) Write(p []byte) (int, error) {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	if f.file == nil {
+		return 0, fmt.Errorf("audit log is closed")
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// shouldRotate returns whether the file must be rotated before writing n more
+// bytes to it
+func (f *fileSink) shouldRotate(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.rotateBytes > 0 && f.size+n > f.rotateBytes {
+		return true
+	}
+	return f.rotateDuration > 0 && time.Since(f.createdAt) >= f.rotateDuration
+}
+
+// rotate renames the current file, opens a new one, and deletes the oldest
+// rotated files beyond the number to keep
+func (f *fileSink) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %v", err)
+	}
+	f.file = nil
+
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext)
+	rotated := fmt.Sprintf("%s-%d%s", base, time.Now().UnixNano(), ext)
+	if err := os.Rename(f.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %v", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	if f.rotateMaxFiles <= 0 {
+		return nil
+	}
+	files, err := filepath.Glob(base + "-*" + ext)
+	if err != nil {
+		return fmt.Errorf("failed to list rotated audit logs: %v", err)
+	}
+	if len(files) <= f.rotateMaxFiles {
+		return nil
+	}
+	sort.Strings(files)
+	for _, old := range files[:len(files)-f.rotateMaxFiles] {
+		if err := os.Remove(old); err != nil {
+			return fmt.Errorf("failed to delete rotated audit log: %v", err)
+		}
+	}
+	return nil
+}
+
+func (f *fileSink) Close() error {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
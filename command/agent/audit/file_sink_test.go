@@ -0,0 +1,105 @@
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink_RotateBytes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	f, err := newFileSink(path, 0600, 0, 10, 2)
+	require.NoError(err)
+	defer f.Close()
+
+	// Every write but the first rotates the file, and only two rotated
+	// files are kept
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(err)
+		time.Sleep(time.Millisecond)
+	}
+
+	current, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal("fourth\n", string(current))
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	require.NoError(err)
+	require.Len(rotated, 2)
+	second, err := ioutil.ReadFile(rotated[0])
+	require.NoError(err)
+	require.Equal("second\n", string(second))
+	third, err := ioutil.ReadFile(rotated[1])
+	require.NoError(err)
+	require.Equal("third\n", string(third))
+}
+
+func TestFileSink_RotateDuration(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+
+	f, err := newFileSink(path, 0600, 10*time.Millisecond, 0, 0)
+	require.NoError(err)
+	defer f.Close()
+
+	_, err = f.Write([]byte("first\n"))
+	require.NoError(err)
+	_, err = f.Write([]byte("second\n"))
+	require.NoError(err)
+	time.Sleep(20 * time.Millisecond)
+	_, err = f.Write([]byte("third\n"))
+	require.NoError(err)
+
+	current, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal("third\n", string(current))
+
+	rotated, err := filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	require.NoError(err)
+	require.Len(rotated, 1)
+	old, err := ioutil.ReadFile(rotated[0])
+	require.NoError(err)
+	require.Equal("first\nsecond\n", string(old))
+}
+
+func TestFileSink_Append(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	require.NoError(ioutil.WriteFile(path, []byte("existing\n"), 0600))
+
+	// An existing log is appended to
+	f, err := newFileSink(path, 0600, 0, 0, 0)
+	require.NoError(err)
+	_, err = f.Write([]byte("new\n"))
+	require.NoError(err)
+	require.NoError(f.Close())
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	require.Equal("existing\nnew\n", string(contents))
+
+	_, err = f.Write([]byte("closed\n"))
+	require.Error(err)
+}
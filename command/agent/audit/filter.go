@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	glob "github.com/ryanuber/go-glob"
+)
+
+// filter excludes the events matching all of its endpoints, stages and
+// operations
+type filter struct {
+	endpoints  []string
+	stages     []string
+	operations []string
+}
+
+func newFilter(conf *config.AuditFilter) (*filter, error) {
+	if conf.Type != FilterTypeHTTP {
+		return nil, fmt.Errorf("unsupported type %q", conf.Type)
+	}
+
+	for _, s := range conf.Stages {
+		switch Stage(s) {
+		case OperationReceived, OperationComplete, "*":
+		default:
+			return nil, fmt.Errorf("unknown stage %q", s)
+		}
+	}
+
+	operations := make([]string, 0, len(conf.Operations))
+	for _, o := range conf.Operations {
+		operations = append(operations, strings.ToUpper(o))
+	}
+
+	return &filter{
+		endpoints:  conf.Endpoints,
+		stages:     conf.Stages,
+		operations: operations,
+	}, nil
+}
+
+// matches returns whether the event is excluded by the filter. An empty list
+// matches everything.
+func (f *filter) matches(e *Event) bool {
+	return matchAny(f.endpoints, e.Request.Endpoint) &&
+		matchAny(f.stages, string(e.Stage)) &&
+		matchAny(f.operations, e.Request.Operation)
+}
+
+func matchAny(patterns []string, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if glob.Glob(p, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent/audit"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/stretchr/testify/require"
+)
+
+// auditConfig returns an audit configuration writing to a file in dir
+func auditConfig(dir, guarantee string) *config.AuditConfig {
+	return &config.AuditConfig{
+		Enabled: helper.BoolToPtr(true),
+		Sinks: []*config.AuditSink{
+			{
+				Name:              "file",
+				Type:              audit.SinkTypeFile,
+				DeliveryGuarantee: guarantee,
+				Path:              filepath.Join(dir, "audit.log"),
+			},
+		},
+	}
+}
+
+func readAuditEvents(t *testing.T, path string) []*audit.Event {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var events []*audit.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e audit.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, &e)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestHTTP_Audit(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	httpACLTest(t, func(c *Config) {
+		c.Audit = auditConfig(dir, audit.DeliveryEnforced)
+	}, func(s *TestAgent) {
+		handler := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+			return nil, structs.ErrPermissionDenied
+		}
+
+		req, err := http.NewRequest("GET", "/v1/jobs?namespace=ns", nil)
+		require.NoError(err)
+		setToken(req, s.RootToken)
+		resp := httptest.NewRecorder()
+		s.Server.wrap(handler)(resp, req)
+		require.Equal(403, resp.Code)
+
+		events := readAuditEvents(t, filepath.Join(dir, "audit.log"))
+		require.Len(events, 2)
+
+		received, complete := events[0], events[1]
+		require.Equal(audit.OperationReceived, received.Stage)
+		require.Nil(received.Response)
+		require.Equal(audit.OperationComplete, complete.Stage)
+		require.NotEqual(received.ID, complete.ID)
+		require.Equal(received.Request, complete.Request)
+
+		require.Equal("GET", received.Request.Operation)
+		require.Equal("/v1/jobs", received.Request.Endpoint)
+		require.Equal("ns", received.Request.Namespace)
+		require.Equal(s.RootToken.AccessorID, received.Auth.AccessorID)
+		require.Equal(structs.ACLManagementToken, received.Auth.Type)
+
+		require.Equal(403, complete.Response.StatusCode)
+		require.Equal(structs.ErrPermissionDenied.Error(), complete.Response.Error)
+		require.False(received.Auth.Unresolved)
+
+		// A token that can't be resolved is marked as such
+		req, err = http.NewRequest("GET", "/v1/jobs", nil)
+		require.NoError(err)
+		req.Header.Set("X-Nomad-Token", uuid.Generate())
+		s.Server.wrap(handler)(httptest.NewRecorder(), req)
+
+		events = readAuditEvents(t, filepath.Join(dir, "audit.log"))
+		require.Len(events, 4)
+		require.NotNil(events[2].Auth)
+		require.True(events[2].Auth.Unresolved)
+		require.Empty(events[2].Auth.AccessorID)
+	})
+}
+
+func TestHTTP_Audit_Enforced(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-audit")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	httpTest(t, func(c *Config) {
+		c.Audit = auditConfig(dir, audit.DeliveryEnforced)
+	}, func(s *TestAgent) {
+		called := false
+		handler := func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+			called = true
+			return &structs.Job{Name: "foo"}, nil
+		}
+
+		// Without ACLs there is no actor
+		req, err := http.NewRequest("GET", "/v1/jobs", nil)
+		require.NoError(err)
+		resp := httptest.NewRecorder()
+		s.Server.wrap(handler)(resp, req)
+		require.Equal(200, resp.Code)
+		require.True(called)
+
+		events := readAuditEvents(t, filepath.Join(dir, "audit.log"))
+		require.Len(events, 2)
+		require.Nil(events[0].Auth)
+		require.Equal(200, events[1].Response.StatusCode)
+
+		// Requests whose events can't be written fail without being
+		// handled
+		require.NoError(s.Agent.auditor.Close())
+		called = false
+		resp = httptest.NewRecorder()
+		s.Server.wrap(handler)(resp, req)
+		require.Equal(500, resp.Code)
+		require.Equal(ErrAuditFailed, resp.Body.String())
+		require.False(called)
+	})
+}
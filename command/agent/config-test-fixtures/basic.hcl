@@ -27,6 +27,7 @@ client {
 	alloc_dir = "/tmp/alloc"
 	servers = ["a.b.c:80", "127.0.0.1:1234"]
 	node_class = "linux-medium-64bit"
+	node_pool = "batch"
 	meta {
 		foo = "bar"
 		baz = "zip"
@@ -66,7 +67,26 @@ client {
 	gc_disk_usage_threshold = 82
 	gc_inode_usage_threshold = 91
 	gc_max_allocs = 50
+	gc_max_age = "72h"
 	no_host_uuid = false
+	host_volume "tmp" {
+		path = "/tmp"
+	}
+	host_volume "certs" {
+		path = "/etc/ssl/certs"
+		read_only = true
+	}
+	host_network "public" {
+		cidr = "203.0.113.0/24"
+		reserved_ports = "22,80"
+	}
+	host_network "private" {
+		interface = "eth1"
+	}
+	cni_path = "/opt/cni/bin"
+	cni_config_dir = "/etc/cni/net.d"
+	bridge_network_name = "nomad0"
+	bridge_network_subnet = "10.0.64.0/20"
 }
 server {
 	enabled = true
@@ -84,6 +104,7 @@ server {
 	heartbeat_grace   = "30s"
 	min_heartbeat_ttl = "33s"
 	max_heartbeats_per_second = 11.0
+	event_buffer_size = 200
 	retry_join = [ "1.1.1.1", "2.2.2.2" ]
 	start_join = [ "1.1.1.1", "2.2.2.2" ]
 	retry_max = 3
@@ -98,6 +119,20 @@ server {
 		retry_max = 3
 		retry_interval = "15s"
 	}
+	admission {
+		required_meta = ["owner"]
+		deny_privileged_docker = true
+		default_constraint {
+			attribute = "${attr.kernel.name}"
+			value = "linux"
+		}
+		webhook "policy" {
+			type = "validating"
+			address = "https://127.0.0.1:8443/admit"
+			timeout = "3s"
+			fail_open = true
+		}
+	}
 }
 acl {
 	enabled = true
@@ -133,6 +168,7 @@ consul {
 	client_service_name = "nomad-client"
 	client_http_check_name = "nomad-client-http-health-check"
 	address = "127.0.0.1:9500"
+	grpc_address = "127.0.0.1:9502"
 	token = "token1"
 	auth = "username:pass"
 	ssl = true
@@ -191,6 +227,25 @@ autopilot {
 	server_stabilization_time = "23057s"
 	enable_custom_upgrades = true
 }
+audit {
+	enabled = true
+	sink "file" {
+		type = "file"
+		delivery_guarantee = "enforced"
+		format = "json"
+		path = "/opt/nomad/audit/audit.log"
+		mode = "0640"
+		rotate_duration = "24h"
+		rotate_bytes = 1048576
+		rotate_max_files = 10
+	}
+	filter "metrics" {
+		type = "HTTPEvent"
+		endpoints = ["/v1/metrics", "/v1/agent/*"]
+		stages = ["*"]
+		operations = ["GET"]
+	}
+}
 plugin "docker" {
   args = ["foo", "bar"]
   config {
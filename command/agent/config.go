@@ -19,6 +19,7 @@ import (
 	client "github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/version"
 )
@@ -137,6 +138,10 @@ type Config struct {
 	// Autopilot contains the configuration for Autopilot behavior.
 	Autopilot *config.AutopilotConfig `mapstructure:"autopilot"`
 
+	// Audit contains the configuration for the audit logging of the API
+	// requests
+	Audit *config.AuditConfig `mapstructure:"audit"`
+
 	// Plugins is the set of configured plugins
 	Plugins []*config.PluginConfig `hcl:"plugin,expand"`
 }
@@ -158,6 +163,10 @@ type ClientConfig struct {
 	// NodeClass is used to group the node by class
 	NodeClass string `mapstructure:"node_class"`
 
+	// NodePool is the node pool the node is in. Jobs are only placed on the
+	// nodes of their pool.
+	NodePool string `mapstructure:"node_pool"`
+
 	// Options is used for configuration of nomad internals,
 	// like fingerprinters and drivers. The format is:
 	//
@@ -220,12 +229,41 @@ type ClientConfig struct {
 	// before garbage collection is triggered.
 	GCMaxAllocs int `mapstructure:"gc_max_allocs"`
 
+	// GCMaxAge is the duration after which terminal allocations are garbage
+	// collected regardless of the other thresholds. Zero disables it.
+	GCMaxAge time.Duration `mapstructure:"gc_max_age"`
+
 	// NoHostUUID disables using the host's UUID and will force generation of a
 	// random UUID.
 	NoHostUUID *bool `mapstructure:"no_host_uuid"`
 
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `mapstructure:"server_join"`
+
+	// HostVolumes contains information about the volumes an operator has made
+	// available to jobs running on this node.
+	HostVolumes []*structs.ClientHostVolumeConfig `mapstructure:"host_volume"`
+
+	// HostNetworks contains the named networks of the host ports can be
+	// pinned to, such as the public and private networks of a multi-homed
+	// client.
+	HostNetworks []*structs.ClientHostNetworkConfig `mapstructure:"host_network"`
+
+	// CNIPath is the directory of the CNI plugins used to set up the
+	// networks of the allocations.
+	CNIPath string `mapstructure:"cni_path"`
+
+	// CNIConfigDir is the directory of the CNI network configurations
+	// available to task groups.
+	CNIConfigDir string `mapstructure:"cni_config_dir"`
+
+	// BridgeNetworkName is the name of the bridge used by the allocations
+	// in bridge network mode.
+	BridgeNetworkName string `mapstructure:"bridge_network_name"`
+
+	// BridgeNetworkSubnet is the subnet of the allocations in bridge
+	// network mode.
+	BridgeNetworkSubnet string `mapstructure:"bridge_network_subnet"`
 }
 
 // ACLConfig is configuration specific to the ACL system
@@ -317,6 +355,10 @@ type ServerConfig struct {
 	// to meet the target rate.
 	MaxHeartbeatsPerSecond float64 `mapstructure:"max_heartbeats_per_second"`
 
+	// EventBufferSize is the number of the most recent events the server
+	// keeps for the event stream subscriptions resuming from an index.
+	EventBufferSize int `mapstructure:"event_buffer_size"`
+
 	// StartJoin is a list of addresses to attempt to join when the
 	// agent starts. If Serf is unable to communicate with any of these
 	// addresses, then the agent will error and exit.
@@ -345,11 +387,11 @@ type ServerConfig struct {
 	// true, we ignore the leave, and rejoin the cluster on start.
 	RejoinAfterLeave bool `mapstructure:"rejoin_after_leave"`
 
-	// (Enterprise-only) NonVotingServer is whether this server will act as a
-	// non-voting member of the cluster to help provide read scalability.
+	// NonVotingServer is whether this server will act as a non-voting member
+	// of the cluster to help provide read scalability.
 	NonVotingServer bool `mapstructure:"non_voting_server"`
 
-	// (Enterprise-only) RedundancyZone is the redundancy zone to use for this server.
+	// RedundancyZone is the redundancy zone to use for this server.
 	RedundancyZone string `mapstructure:"redundancy_zone"`
 
 	// (Enterprise-only) UpgradeVersion is the custom upgrade version to use when
@@ -361,6 +403,10 @@ type ServerConfig struct {
 
 	// ServerJoin contains information that is used to attempt to join servers
 	ServerJoin *ServerJoin `mapstructure:"server_join"`
+
+	// Admission configures the admission controllers applied to the jobs on
+	// registration
+	Admission *config.AdmissionConfig `mapstructure:"admission"`
 }
 
 // ServerJoin is used in both clients and servers to bootstrap connections to
@@ -550,57 +596,11 @@ type Resources struct {
 // The supported syntax is comma separated integers or ranges separated by
 // hyphens. For example, "80,120-150,160"
 func (r *Resources) ParseReserved() error {
-	parts := strings.Split(r.ReservedPorts, ",")
-
-	// Hot path the empty case
-	if len(parts) == 1 && parts[0] == "" {
-		return nil
-	}
-
-	ports := make(map[int]struct{})
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		rangeParts := strings.Split(part, "-")
-		l := len(rangeParts)
-		switch l {
-		case 1:
-			if val := rangeParts[0]; val == "" {
-				return fmt.Errorf("can't specify empty port")
-			} else {
-				port, err := strconv.Atoi(val)
-				if err != nil {
-					return err
-				}
-				ports[port] = struct{}{}
-			}
-		case 2:
-			// We are parsing a range
-			start, err := strconv.Atoi(rangeParts[0])
-			if err != nil {
-				return err
-			}
-
-			end, err := strconv.Atoi(rangeParts[1])
-			if err != nil {
-				return err
-			}
-
-			if end < start {
-				return fmt.Errorf("invalid range: starting value (%v) less than ending (%v) value", end, start)
-			}
-
-			for i := start; i <= end; i++ {
-				ports[i] = struct{}{}
-			}
-		default:
-			return fmt.Errorf("can only parse single port numbers or port ranges (ex. 80,100-120,150)")
-		}
-	}
-
-	for port := range ports {
-		r.ParsedReservedPorts = append(r.ParsedReservedPorts, port)
+	ports, err := structs.ParsePortRanges(r.ReservedPorts)
+	if err != nil {
+		return err
 	}
-
+	r.ParsedReservedPorts = append(r.ParsedReservedPorts, ports...)
 	sort.Ints(r.ParsedReservedPorts)
 	return nil
 }
@@ -623,9 +623,7 @@ func DevConfig() *Config {
 	}
 	conf.Client.Options = map[string]string{
 		"driver.raw_exec.enable": "true",
-	}
-	conf.Client.Options = map[string]string{
-		"driver.docker.volumes": "true",
+		"driver.docker.volumes":  "true",
 	}
 	conf.Client.GCInterval = 10 * time.Minute
 	conf.Client.GCDiskUsageThreshold = 99
@@ -695,6 +693,7 @@ func DefaultConfig() *Config {
 		Sentinel:           &config.SentinelConfig{},
 		Version:            version.GetVersion(),
 		Autopilot:          config.DefaultAutopilotConfig(),
+		Audit:              config.DefaultAuditConfig(),
 		DisableUpdateCheck: helper.BoolToPtr(false),
 	}
 }
@@ -864,6 +863,12 @@ func (c *Config) Merge(b *Config) *Config {
 		result.Autopilot = result.Autopilot.Merge(b.Autopilot)
 	}
 
+	if result.Audit == nil && b.Audit != nil {
+		result.Audit = b.Audit.Copy()
+	} else if b.Audit != nil {
+		result.Audit = result.Audit.Merge(b.Audit)
+	}
+
 	if len(result.Plugins) == 0 && len(b.Plugins) != 0 {
 		copy := make([]*config.PluginConfig, len(b.Plugins))
 		for i, v := range b.Plugins {
@@ -1118,6 +1123,9 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.MaxHeartbeatsPerSecond != 0.0 {
 		result.MaxHeartbeatsPerSecond = b.MaxHeartbeatsPerSecond
 	}
+	if b.EventBufferSize != 0 {
+		result.EventBufferSize = b.EventBufferSize
+	}
 	if b.RetryMaxAttempts != 0 {
 		result.RetryMaxAttempts = b.RetryMaxAttempts
 	}
@@ -1143,6 +1151,12 @@ func (a *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		result.ServerJoin = result.ServerJoin.Merge(b.ServerJoin)
 	}
 
+	if result.Admission == nil && b.Admission != nil {
+		result.Admission = b.Admission.Copy()
+	} else if b.Admission != nil {
+		result.Admission = result.Admission.Merge(b.Admission)
+	}
+
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
@@ -1175,6 +1189,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.NodeClass != "" {
 		result.NodeClass = b.NodeClass
 	}
+	if b.NodePool != "" {
+		result.NodePool = b.NodePool
+	}
 	if b.NetworkInterface != "" {
 		result.NetworkInterface = b.NetworkInterface
 	}
@@ -1217,6 +1234,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.GCMaxAllocs != 0 {
 		result.GCMaxAllocs = b.GCMaxAllocs
 	}
+	if b.GCMaxAge != 0 {
+		result.GCMaxAge = b.GCMaxAge
+	}
 	// NoHostUUID defaults to true, merge if false
 	if b.NoHostUUID != nil {
 		result.NoHostUUID = b.NoHostUUID
@@ -1253,6 +1273,31 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.ServerJoin = result.ServerJoin.Merge(b.ServerJoin)
 	}
 
+	if len(a.HostVolumes) == 0 && len(b.HostVolumes) != 0 {
+		result.HostVolumes = structs.CopySliceClientHostVolumeConfig(b.HostVolumes)
+	} else if len(b.HostVolumes) != 0 {
+		result.HostVolumes = structs.HostVolumeSliceMerge(a.HostVolumes, b.HostVolumes)
+	}
+
+	if len(a.HostNetworks) == 0 && len(b.HostNetworks) != 0 {
+		result.HostNetworks = structs.CopySliceClientHostNetworkConfig(b.HostNetworks)
+	} else if len(b.HostNetworks) != 0 {
+		result.HostNetworks = structs.HostNetworkSliceMerge(a.HostNetworks, b.HostNetworks)
+	}
+
+	if b.CNIPath != "" {
+		result.CNIPath = b.CNIPath
+	}
+	if b.CNIConfigDir != "" {
+		result.CNIConfigDir = b.CNIConfigDir
+	}
+	if b.BridgeNetworkName != "" {
+		result.BridgeNetworkName = b.BridgeNetworkName
+	}
+	if b.BridgeNetworkSubnet != "" {
+		result.BridgeNetworkSubnet = b.BridgeNetworkSubnet
+	}
+
 	return &result
 }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/mitchellh/mapstructure"
 )
@@ -102,6 +103,7 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		"acl",
 		"sentinel",
 		"autopilot",
+		"audit",
 		"plugin",
 	}
 	if err := helper.CheckHCLKeys(list, valid); err != nil {
@@ -127,6 +129,7 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 	delete(m, "acl")
 	delete(m, "sentinel")
 	delete(m, "autopilot")
+	delete(m, "audit")
 	delete(m, "plugin")
 
 	// Decode the rest
@@ -218,6 +221,13 @@ func parseConfig(result *Config, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse Audit config
+	if o := list.Filter("audit"); len(o.Items) > 0 {
+		if err := parseAudit(&result.Audit, o); err != nil {
+			return multierror.Prefix(err, "audit->")
+		}
+	}
+
 	// Parse Plugin configs
 	if o := list.Filter("plugin"); len(o.Items) > 0 {
 		if err := parsePlugins(&result.Plugins, o); err != nil {
@@ -362,6 +372,7 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		"alloc_dir",
 		"servers",
 		"node_class",
+		"node_pool",
 		"options",
 		"meta",
 		"chroot_env",
@@ -379,8 +390,15 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		"gc_inode_usage_threshold",
 		"gc_parallel_destroys",
 		"gc_max_allocs",
+		"gc_max_age",
 		"no_host_uuid",
 		"server_join",
+		"host_volume",
+		"host_network",
+		"cni_path",
+		"cni_config_dir",
+		"bridge_network_name",
+		"bridge_network_subnet",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return err
@@ -397,6 +415,8 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 	delete(m, "reserved")
 	delete(m, "stats")
 	delete(m, "server_join")
+	delete(m, "host_volume")
+	delete(m, "host_network")
 
 	var config ClientConfig
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -467,10 +487,119 @@ func parseClient(result **ClientConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse host_volume config
+	if o := listVal.Filter("host_volume"); len(o.Items) > 0 {
+		if err := parseHostVolumes(&config.HostVolumes, o); err != nil {
+			return multierror.Prefix(err, "host_volume ->")
+		}
+	}
+
+	// Parse host_network config
+	if o := listVal.Filter("host_network"); len(o.Items) > 0 {
+		if err := parseHostNetworks(&config.HostNetworks, o); err != nil {
+			return multierror.Prefix(err, "host_network ->")
+		}
+	}
+
 	*result = &config
 	return nil
 }
 
+func parseHostVolumes(result *[]*structs.ClientHostVolumeConfig, list *ast.ObjectList) error {
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("host_volume must be given a name")
+		}
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("host_volume %q defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// Check for invalid keys
+		valid := []string{
+			"path",
+			"read_only",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%q ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		var volume struct {
+			Path     string `mapstructure:"path"`
+			ReadOnly bool   `mapstructure:"read_only"`
+		}
+		if err := mapstructure.WeakDecode(m, &volume); err != nil {
+			return err
+		}
+
+		*result = append(*result, &structs.ClientHostVolumeConfig{
+			Name:     n,
+			Path:     volume.Path,
+			ReadOnly: volume.ReadOnly,
+		})
+	}
+
+	return nil
+}
+
+func parseHostNetworks(result *[]*structs.ClientHostNetworkConfig, list *ast.ObjectList) error {
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("host_network must be given a name")
+		}
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("host_network %q defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// Check for invalid keys
+		valid := []string{
+			"cidr",
+			"interface",
+			"reserved_ports",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%q ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		var network struct {
+			CIDR          string `mapstructure:"cidr"`
+			Interface     string `mapstructure:"interface"`
+			ReservedPorts string `mapstructure:"reserved_ports"`
+		}
+		if err := mapstructure.WeakDecode(m, &network); err != nil {
+			return err
+		}
+
+		*result = append(*result, &structs.ClientHostNetworkConfig{
+			Name:          n,
+			CIDR:          network.CIDR,
+			Interface:     network.Interface,
+			ReservedPorts: network.ReservedPorts,
+		})
+	}
+
+	return nil
+}
+
 func parseReserved(result **Resources, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -550,6 +679,7 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		"heartbeat_grace",
 		"min_heartbeat_ttl",
 		"max_heartbeats_per_second",
+		"event_buffer_size",
 		"rejoin_after_leave",
 		"encrypt",
 		"authoritative_region",
@@ -558,6 +688,7 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		"upgrade_version",
 
 		"server_join",
+		"admission",
 
 		// For backwards compatibility
 		"start_join",
@@ -575,6 +706,7 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 	}
 
 	delete(m, "server_join")
+	delete(m, "admission")
 
 	var config ServerConfig
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -602,10 +734,130 @@ func parseServer(result **ServerConfig, list *ast.ObjectList) error {
 		}
 	}
 
+	// Parse Admission config
+	if o := listVal.Filter("admission"); len(o.Items) > 0 {
+		if err := parseAdmission(&config.Admission, o); err != nil {
+			return multierror.Prefix(err, "admission->")
+		}
+	}
+
 	*result = &config
 	return nil
 }
 
+func parseAdmission(result **config.AdmissionConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'admission' block allowed")
+	}
+
+	// Get our object
+	listVal := list.Items[0].Val
+
+	// Check for invalid keys
+	valid := []string{
+		"default_constraint",
+		"required_meta",
+		"deny_privileged_docker",
+		"webhook",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+	delete(m, "default_constraint")
+	delete(m, "webhook")
+
+	var admission config.AdmissionConfig
+	if err := mapstructure.WeakDecode(m, &admission); err != nil {
+		return err
+	}
+
+	ot, ok := listVal.(*ast.ObjectType)
+	if !ok {
+		return fmt.Errorf("admission value: should be an object")
+	}
+
+	// Parse the default constraints
+	for _, o := range ot.List.Filter("default_constraint").Items {
+		valid := []string{
+			"attribute",
+			"operator",
+			"value",
+		}
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return multierror.Prefix(err, "default_constraint ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+		var constraint config.AdmissionConstraint
+		if err := mapstructure.WeakDecode(m, &constraint); err != nil {
+			return err
+		}
+		if constraint.Attribute == "" {
+			return fmt.Errorf("default_constraint: attribute is required")
+		}
+		admission.DefaultConstraints = append(admission.DefaultConstraints, &constraint)
+	}
+
+	// Parse the webhooks
+	for _, o := range ot.List.Filter("webhook").Items {
+		// Ensure there is a name
+		if len(o.Keys) != 1 {
+			return fmt.Errorf("webhook must have a name")
+		}
+		name := o.Keys[0].Token.Value().(string)
+
+		valid := []string{
+			"type",
+			"address",
+			"timeout",
+			"fail_open",
+		}
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("webhook %q:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+		webhook := config.AdmissionWebhook{Name: name}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &webhook,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("webhook %q:", name))
+		}
+
+		switch webhook.Type {
+		case config.AdmissionWebhookMutating, config.AdmissionWebhookValidating:
+		default:
+			return fmt.Errorf("webhook %q: type must be %q or %q", name,
+				config.AdmissionWebhookMutating, config.AdmissionWebhookValidating)
+		}
+		if webhook.Address == "" {
+			return fmt.Errorf("webhook %q: address is required", name)
+		}
+		admission.Webhooks = append(admission.Webhooks, &webhook)
+	}
+
+	*result = &admission
+	return nil
+}
+
 func parseServerJoin(result **ServerJoin, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -779,6 +1031,7 @@ func parseConsulConfig(result **config.ConsulConfig, list *ast.ObjectList) error
 		"client_auto_join",
 		"client_service_name",
 		"client_http_check_name",
+		"grpc_address",
 		"key_file",
 		"server_auto_join",
 		"server_service_name",
@@ -997,6 +1250,143 @@ func parseAutopilot(result **config.AutopilotConfig, list *ast.ObjectList) error
 	return nil
 }
 
+func parseAudit(result **config.AuditConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'audit' block allowed")
+	}
+
+	// Get our audit object
+	obj := list.Items[0]
+
+	// Value should be an object
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("audit value: should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"enabled",
+		"sink",
+		"filter",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+	delete(m, "sink")
+	delete(m, "filter")
+
+	var auditConfig config.AuditConfig
+	if err := mapstructure.WeakDecode(m, &auditConfig); err != nil {
+		return err
+	}
+
+	// Parse the sinks
+	if o := listVal.Filter("sink"); len(o.Items) > 0 {
+		if err := parseAuditSinks(&auditConfig.Sinks, o); err != nil {
+			return multierror.Prefix(err, "sink ->")
+		}
+	}
+
+	// Parse the filters
+	if o := listVal.Filter("filter"); len(o.Items) > 0 {
+		if err := parseAuditFilters(&auditConfig.Filters, o); err != nil {
+			return multierror.Prefix(err, "filter ->")
+		}
+	}
+
+	*result = &auditConfig
+	return nil
+}
+
+func parseAuditSinks(result *[]*config.AuditSink, list *ast.ObjectList) error {
+	valid := []string{
+		"type",
+		"delivery_guarantee",
+		"format",
+		"path",
+		"mode",
+		"rotate_duration",
+		"rotate_bytes",
+		"rotate_max_files",
+	}
+
+	for _, o := range list.Items {
+		// Ensure there is a name
+		if len(o.Keys) != 1 {
+			return fmt.Errorf("audit sink must have a name")
+		}
+		name := o.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%q:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		sink := config.AuditSink{Name: name}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &sink,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%q:", name))
+		}
+
+		*result = append(*result, &sink)
+	}
+	return nil
+}
+
+func parseAuditFilters(result *[]*config.AuditFilter, list *ast.ObjectList) error {
+	valid := []string{
+		"type",
+		"endpoints",
+		"stages",
+		"operations",
+	}
+
+	for _, o := range list.Items {
+		// Ensure there is a name
+		if len(o.Keys) != 1 {
+			return fmt.Errorf("audit filter must have a name")
+		}
+		name := o.Keys[0].Token.Value().(string)
+
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%q:", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		filter := config.AuditFilter{Name: name}
+		if err := mapstructure.WeakDecode(m, &filter); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%q:", name))
+		}
+
+		*result = append(*result, &filter)
+	}
+	return nil
+}
+
 func parsePlugins(result *[]*config.PluginConfig, list *ast.ObjectList) error {
 	listLen := len(list.Items)
 	plugins := make([]*config.PluginConfig, listLen)
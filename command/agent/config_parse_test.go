@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/stretchr/testify/require"
 )
@@ -48,6 +49,7 @@ func TestConfig_Parse(t *testing.T) {
 					AllocDir:  "/tmp/alloc",
 					Servers:   []string{"a.b.c:80", "127.0.0.1:1234"},
 					NodeClass: "linux-medium-64bit",
+					NodePool:  "batch",
 					ServerJoin: &ServerJoin{
 						RetryJoin:        []string{"1.1.1.1", "2.2.2.2"},
 						RetryInterval:    time.Duration(15) * time.Second,
@@ -85,7 +87,20 @@ func TestConfig_Parse(t *testing.T) {
 					GCDiskUsageThreshold:  82,
 					GCInodeUsageThreshold: 91,
 					GCMaxAllocs:           50,
+					GCMaxAge:              72 * time.Hour,
 					NoHostUUID:            helper.BoolToPtr(false),
+					HostVolumes: []*structs.ClientHostVolumeConfig{
+						{Name: "tmp", Path: "/tmp"},
+						{Name: "certs", Path: "/etc/ssl/certs", ReadOnly: true},
+					},
+					HostNetworks: []*structs.ClientHostNetworkConfig{
+						{Name: "public", CIDR: "203.0.113.0/24", ReservedPorts: "22,80"},
+						{Name: "private", Interface: "eth1"},
+					},
+					CNIPath:             "/opt/cni/bin",
+					CNIConfigDir:        "/etc/cni/net.d",
+					BridgeNetworkName:   "nomad0",
+					BridgeNetworkSubnet: "10.0.64.0/20",
 				},
 				Server: &ServerConfig{
 					Enabled:                true,
@@ -103,6 +118,7 @@ func TestConfig_Parse(t *testing.T) {
 					HeartbeatGrace:         30 * time.Second,
 					MinHeartbeatTTL:        33 * time.Second,
 					MaxHeartbeatsPerSecond: 11.0,
+					EventBufferSize:        200,
 					RetryJoin:              []string{"1.1.1.1", "2.2.2.2"},
 					StartJoin:              []string{"1.1.1.1", "2.2.2.2"},
 					RetryInterval:          15 * time.Second,
@@ -117,6 +133,25 @@ func TestConfig_Parse(t *testing.T) {
 						RetryInterval:    time.Duration(15) * time.Second,
 						RetryMaxAttempts: 3,
 					},
+					Admission: &config.AdmissionConfig{
+						DefaultConstraints: []*config.AdmissionConstraint{
+							{
+								Attribute: "${attr.kernel.name}",
+								Value:     "linux",
+							},
+						},
+						RequiredMeta:         []string{"owner"},
+						DenyPrivilegedDocker: &trueValue,
+						Webhooks: []*config.AdmissionWebhook{
+							{
+								Name:     "policy",
+								Type:     "validating",
+								Address:  "https://127.0.0.1:8443/admit",
+								Timeout:  3 * time.Second,
+								FailOpen: true,
+							},
+						},
+					},
 				},
 				ACL: &ACLConfig{
 					Enabled:          true,
@@ -151,6 +186,7 @@ func TestConfig_Parse(t *testing.T) {
 					ClientServiceName:   "nomad-client",
 					ClientHTTPCheckName: "nomad-client-http-health-check",
 					Addr:                "127.0.0.1:9500",
+					GRPCAddr:            "127.0.0.1:9502",
 					Token:               "token1",
 					Auth:                "username:pass",
 					EnableSSL:           &trueValue,
@@ -216,6 +252,31 @@ func TestConfig_Parse(t *testing.T) {
 					DisableUpgradeMigration: &trueValue,
 					EnableCustomUpgrades:    &trueValue,
 				},
+				Audit: &config.AuditConfig{
+					Enabled: &trueValue,
+					Sinks: []*config.AuditSink{
+						{
+							Name:              "file",
+							Type:              "file",
+							DeliveryGuarantee: "enforced",
+							Format:            "json",
+							Path:              "/opt/nomad/audit/audit.log",
+							Mode:              "0640",
+							RotateDuration:    24 * time.Hour,
+							RotateBytes:       1048576,
+							RotateMaxFiles:    10,
+						},
+					},
+					Filters: []*config.AuditFilter{
+						{
+							Name:       "metrics",
+							Type:       "HTTPEvent",
+							Endpoints:  []string{"/v1/metrics", "/v1/agent/*"},
+							Stages:     []string{"*"},
+							Operations: []string{"GET"},
+						},
+					},
+				},
 				Plugins: []*config.PluginConfig{
 					{
 						Name: "docker",
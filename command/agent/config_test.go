@@ -38,6 +38,7 @@ func TestConfig_Merge(t *testing.T) {
 		Consul:         &config.ConsulConfig{},
 		Sentinel:       &config.SentinelConfig{},
 		Autopilot:      &config.AutopilotConfig{},
+		Audit:          &config.AuditConfig{},
 	}
 
 	c2 := &Config{
@@ -83,6 +84,7 @@ func TestConfig_Merge(t *testing.T) {
 			StateDir:  "/tmp/state1",
 			AllocDir:  "/tmp/alloc1",
 			NodeClass: "class1",
+			NodePool:  "pool1",
 			Options: map[string]string{
 				"foo": "bar",
 			},
@@ -112,6 +114,7 @@ func TestConfig_Merge(t *testing.T) {
 			HeartbeatGrace:         30 * time.Second,
 			MinHeartbeatTTL:        30 * time.Second,
 			MaxHeartbeatsPerSecond: 30.0,
+			EventBufferSize:        50,
 			RedundancyZone:         "foo",
 			UpgradeVersion:         "foo",
 		},
@@ -176,6 +179,25 @@ func TestConfig_Merge(t *testing.T) {
 			DisableUpgradeMigration: &falseValue,
 			EnableCustomUpgrades:    &falseValue,
 		},
+		Audit: &config.AuditConfig{
+			Enabled: &falseValue,
+			Sinks: []*config.AuditSink{
+				{
+					Name:              "file",
+					Type:              "file",
+					DeliveryGuarantee: "best-effort",
+					Path:              "/tmp/audit-1.log",
+					RotateMaxFiles:    1,
+				},
+			},
+			Filters: []*config.AuditFilter{
+				{
+					Name:      "metrics",
+					Type:      "HTTPEvent",
+					Endpoints: []string{"/v1/metrics-1"},
+				},
+			},
+		},
 		Plugins: []*config.PluginConfig{
 			{
 				Name: "docker",
@@ -232,6 +254,7 @@ func TestConfig_Merge(t *testing.T) {
 			StateDir:  "/tmp/state2",
 			AllocDir:  "/tmp/alloc2",
 			NodeClass: "class2",
+			NodePool:  "pool2",
 			Servers:   []string{"server2"},
 			Meta: map[string]string{
 				"baz": "zip",
@@ -259,6 +282,10 @@ func TestConfig_Merge(t *testing.T) {
 			GCParallelDestroys:    6,
 			GCDiskUsageThreshold:  71,
 			GCInodeUsageThreshold: 86,
+			GCMaxAge:              24 * time.Hour,
+			HostVolumes: []*structs.ClientHostVolumeConfig{
+				{Name: "tmp", Path: "/tmp"},
+			},
 		},
 		Server: &ServerConfig{
 			Enabled:                true,
@@ -273,6 +300,7 @@ func TestConfig_Merge(t *testing.T) {
 			HeartbeatGrace:         2 * time.Minute,
 			MinHeartbeatTTL:        2 * time.Minute,
 			MaxHeartbeatsPerSecond: 200.0,
+			EventBufferSize:        500,
 			RejoinAfterLeave:       true,
 			StartJoin:              []string{"1.1.1.1"},
 			RetryJoin:              []string{"1.1.1.1"},
@@ -352,6 +380,25 @@ func TestConfig_Merge(t *testing.T) {
 			DisableUpgradeMigration: &trueValue,
 			EnableCustomUpgrades:    &trueValue,
 		},
+		Audit: &config.AuditConfig{
+			Enabled: &trueValue,
+			Sinks: []*config.AuditSink{
+				{
+					Name:              "file",
+					Type:              "file",
+					DeliveryGuarantee: "best-effort",
+					Path:              "/tmp/audit-2.log",
+					RotateMaxFiles:    2,
+				},
+			},
+			Filters: []*config.AuditFilter{
+				{
+					Name:      "metrics",
+					Type:      "HTTPEvent",
+					Endpoints: []string{"/v1/metrics-2"},
+				},
+			},
+		},
 		Plugins: []*config.PluginConfig{
 			{
 				Name: "docker",
@@ -907,6 +954,21 @@ func TestResources_ParseReserved(t *testing.T) {
 			[]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
 			false,
 		},
+		{
+			"0",
+			nil,
+			true,
+		},
+		{
+			"65535,65536",
+			nil,
+			true,
+		},
+		{
+			"65530-70000",
+			nil,
+			true,
+		},
 	}
 
 	for i, tc := range cases {
@@ -924,6 +986,16 @@ func TestResources_ParseReserved(t *testing.T) {
 	}
 }
 
+func TestConfig_DevConfig_ClientOptions(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Dev mode should opt in to both raw_exec and docker volumes
+	conf := DevConfig()
+	require.Equal("true", conf.Client.Options["driver.raw_exec.enable"])
+	require.Equal("true", conf.Client.Options["driver.docker.volumes"])
+}
+
 func TestIsMissingPort(t *testing.T) {
 	_, _, err := net.SplitHostPort("localhost")
 	if missing := isMissingPort(err); !missing {
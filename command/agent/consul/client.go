@@ -441,6 +441,12 @@ func (c *ServiceClient) sync() error {
 			continue
 		}
 
+		// Sidecar proxies are registered by Consul along with their
+		// Connect service, so they are unknown locally
+		if isSidecarOf(id, c.services) {
+			continue
+		}
+
 		// Ignore if this is not a Nomad managed service. Also ignore
 		// Nomad managed services if this is not a client agent.
 		// This is to prevent server agents from removing services
@@ -483,6 +489,11 @@ func (c *ServiceClient) sync() error {
 			continue
 		}
 
+		// Checks of the sidecar proxies are registered by Consul
+		if isSidecarOf(check.ServiceID, c.services) {
+			continue
+		}
+
 		// Ignore if this is not a Nomad managed check. Also ignore
 		// Nomad managed checks if this is not a client agent.
 		// This is to prevent server agents from removing checks
@@ -636,7 +647,7 @@ func (c *ServiceClient) serviceRegs(ops *operations, service *structs.Service, t
 	}
 
 	// Determine the address to advertise based on the mode
-	ip, port, err := getAddress(addrMode, service.PortLabel, task.Networks, task.DriverNetwork)
+	ip, port, err := GetAddress(addrMode, service.PortLabel, task.Networks, task.DriverNetwork)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get address for service %q: %v", service.Name, err)
 	}
@@ -651,6 +662,12 @@ func (c *ServiceClient) serviceRegs(ops *operations, service *structs.Service, t
 		copy(tags, service.Tags)
 	}
 
+	// Build the Connect sidecar registration, if any
+	connect, err := newConnect(service, task.Networks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Consul Connect configuration for service %q: %v", service.Name, err)
+	}
+
 	// Build the Consul Service registration request
 	serviceReg := &api.AgentServiceRegistration{
 		ID:      id,
@@ -658,6 +675,7 @@ func (c *ServiceClient) serviceRegs(ops *operations, service *structs.Service, t
 		Tags:    tags,
 		Address: ip,
 		Port:    port,
+		Connect: connect,
 	}
 	ops.regServices = append(ops.regServices, serviceReg)
 
@@ -696,7 +714,7 @@ func (c *ServiceClient) checkRegs(ops *operations, serviceID string, service *st
 				c.client, c.logger, c.shutdownCh)
 			ops.scripts = append(ops.scripts, sc)
 
-			// Skip getAddress for script checks
+			// Skip GetAddress for script checks
 			checkReg, err := createCheckReg(serviceID, checkID, check, "", 0)
 			if err != nil {
 				return nil, fmt.Errorf("failed to add script check %q: %v", check.Name, err)
@@ -718,7 +736,7 @@ func (c *ServiceClient) checkRegs(ops *operations, serviceID string, service *st
 			addrMode = structs.AddressModeHost
 		}
 
-		ip, port, err := getAddress(addrMode, portLabel, task.Networks, task.DriverNetwork)
+		ip, port, err := GetAddress(addrMode, portLabel, task.Networks, task.DriverNetwork)
 		if err != nil {
 			return nil, fmt.Errorf("error getting address for check %q: %v", check.Name, err)
 		}
@@ -1067,11 +1085,13 @@ func makeAgentServiceID(role string, service *structs.Service) string {
 
 // makeTaskServiceID creates a unique ID for identifying a task service in
 // Consul. All structs.Service fields are included in the ID's hash except
-// Checks. This allows updates to merely compare IDs.
+// Checks. This allows updates to merely compare IDs. Connect services keep
+// their ID when a canary is promoted, as their sidecar proxy is bootstrapped
+// with it.
 //
 //	Example Service ID: _nomad-task-TNM333JKJPM5AK4FAS3VXQLXFDWOF4VH
 func makeTaskServiceID(allocID, taskName string, service *structs.Service, canary bool) string {
-	return nomadTaskPrefix + service.Hash(allocID, taskName, canary)
+	return nomadTaskPrefix + service.Hash(allocID, taskName, canary && service.Connect == nil)
 }
 
 // makeCheckID creates a unique ID for a check.
@@ -1161,10 +1181,10 @@ func isOldNomadService(id string) bool {
 	return strings.HasPrefix(id, prefix)
 }
 
-// getAddress returns the IP and port to use for a service or check. If no port
+// GetAddress returns the IP and port to use for a service or check. If no port
 // label is specified (an empty value), zero values are returned because no
 // address could be resolved.
-func getAddress(addrMode, portLabel string, networks structs.Networks, driverNet *cstructs.DriverNetwork) (string, int, error) {
+func GetAddress(addrMode, portLabel string, networks structs.Networks, driverNet *cstructs.DriverNetwork) (string, int, error) {
 	switch addrMode {
 	case structs.AddressModeAuto:
 		if driverNet.Advertise() {
@@ -1172,7 +1192,7 @@ func getAddress(addrMode, portLabel string, networks structs.Networks, driverNet
 		} else {
 			addrMode = structs.AddressModeHost
 		}
-		return getAddress(addrMode, portLabel, networks, driverNet)
+		return GetAddress(addrMode, portLabel, networks, driverNet)
 	case structs.AddressModeHost:
 		if portLabel == "" {
 			if len(networks) != 1 {
@@ -0,0 +1,149 @@
+package consul
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// groupServicesPrefix is the prefix of the name under which the services
+	// of a task group are registered, in place of the name of a task.
+	groupServicesPrefix = "group-"
+
+	// sidecarProxySuffix is the suffix Consul appends to the ID of a service
+	// to get the ID of the sidecar proxy service it registers for it.
+	sidecarProxySuffix = "-sidecar-proxy"
+
+	// defaultLocalServiceAddress is the address the sidecar proxies forward
+	// the inbound connections to, as they share the network namespace of
+	// the services.
+	defaultLocalServiceAddress = "127.0.0.1"
+
+	// sidecarBindAddress is the address the sidecar proxies listen on for
+	// the inbound connections, in the network namespace of the allocation.
+	sidecarBindAddress = "0.0.0.0"
+)
+
+// NewGroupServices returns the services of a task group, registered on
+// behalf of the allocation rather than of one of its tasks.
+func NewGroupServices(alloc *structs.Allocation, tg *structs.TaskGroup) *TaskServices {
+	ts := TaskServices{
+		AllocID:   alloc.ID,
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+		Name:      groupServicesPrefix + tg.Name,
+		Services:  tg.Services,
+	}
+
+	if alloc.SharedResources != nil {
+		ts.Networks = alloc.SharedResources.Networks
+	}
+
+	if alloc.DeploymentStatus != nil && alloc.DeploymentStatus.Canary {
+		ts.Canary = true
+	}
+
+	return &ts
+}
+
+// GroupServiceID returns the ID under which a service of a task group is
+// registered in Consul for an allocation. The sidecar proxies of Connect
+// services are bootstrapped with it.
+func GroupServiceID(allocID, group string, service *structs.Service) string {
+	return makeTaskServiceID(allocID, groupServicesPrefix+group, service, false)
+}
+
+// isSidecarOf returns whether the ID is the one of a sidecar proxy service
+// registered by Consul for one of the known services.
+func isSidecarOf(id string, services map[string]*api.AgentServiceRegistration) bool {
+	if !strings.HasSuffix(id, sidecarProxySuffix) {
+		return false
+	}
+	_, ok := services[strings.TrimSuffix(id, sidecarProxySuffix)]
+	return ok
+}
+
+// newConnect returns the Connect registration of a service, or nil if it
+// isn't part of the service mesh. The sidecar proxy listens on the port of
+// its label in the network of the allocation, which is its first network.
+func newConnect(service *structs.Service, networks structs.Networks) (*api.AgentServiceConnect, error) {
+	if !service.Connect.HasSidecar() {
+		return nil, nil
+	}
+
+	if len(networks) != 1 {
+		return nil, fmt.Errorf("Connect services require exactly one network; got %d", len(networks))
+	}
+	net := networks[0]
+
+	sidecar := service.Connect.SidecarService
+	sidecarPort, ok := lookupPort(net, sidecar.Port)
+	if !ok {
+		return nil, fmt.Errorf("invalid sidecar port %q: port label not found", sidecar.Port)
+	}
+
+	proxy := &api.AgentServiceConnectProxyConfig{
+		LocalServiceAddress: defaultLocalServiceAddress,
+		Config:              make(map[string]interface{}),
+	}
+	if port, ok := lookupPort(net, service.PortLabel); ok {
+		proxy.LocalServicePort = mappedPort(port)
+	}
+
+	if p := sidecar.Proxy; p != nil {
+		if p.LocalServiceAddress != "" {
+			proxy.LocalServiceAddress = p.LocalServiceAddress
+		}
+		if p.LocalServicePort != 0 {
+			proxy.LocalServicePort = p.LocalServicePort
+		}
+		for _, u := range p.Upstreams {
+			proxy.Upstreams = append(proxy.Upstreams, api.Upstream{
+				DestinationName: u.DestinationName,
+				LocalBindPort:   u.LocalBindPort,
+			})
+		}
+		for k, v := range p.Config {
+			proxy.Config[k] = v
+		}
+	}
+
+	// The proxy listens inside the network namespace, on the port the host
+	// port is mapped to
+	proxy.Config["bind_address"] = sidecarBindAddress
+	proxy.Config["bind_port"] = mappedPort(sidecarPort)
+
+	return &api.AgentServiceConnect{
+		SidecarService: &api.AgentServiceRegistration{
+			Tags:    helper.CopySliceString(sidecar.Tags),
+			Address: net.PortIP(sidecarPort),
+			Port:    sidecarPort.Value,
+			Proxy:   proxy,
+		},
+	}, nil
+}
+
+// lookupPort returns the port of the network with the label.
+func lookupPort(net *structs.NetworkResource, label string) (structs.Port, bool) {
+	for _, ports := range [][]structs.Port{net.ReservedPorts, net.DynamicPorts} {
+		for _, p := range ports {
+			if p.Label == label {
+				return p, true
+			}
+		}
+	}
+	return structs.Port{}, false
+}
+
+// mappedPort returns the port a host port is mapped to in the network
+// namespace of the allocation.
+func mappedPort(p structs.Port) int {
+	if p.To != 0 {
+		return p.To
+	}
+	return p.Value
+}
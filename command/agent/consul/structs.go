@@ -9,6 +9,10 @@ import (
 type TaskServices struct {
 	AllocID string
 
+	// Namespace and JobID of the allocation
+	Namespace string
+	JobID     string
+
 	// Name of the task
 	Name string
 
@@ -22,7 +26,8 @@ type TaskServices struct {
 	// Services and checks to register for the task.
 	Services []*structs.Service
 
-	// Networks from the task's resources stanza.
+	// Networks from the task's resources stanza and from the task group,
+	// whose ports are mapped from the host.
 	Networks structs.Networks
 
 	// DriverExec is the script executor for the task's driver.
@@ -35,6 +40,8 @@ type TaskServices struct {
 func NewTaskServices(alloc *structs.Allocation, task *structs.Task, restarter TaskRestarter, exec driver.ScriptExecutor, net *cstructs.DriverNetwork) *TaskServices {
 	ts := TaskServices{
 		AllocID:       alloc.ID,
+		Namespace:     alloc.Namespace,
+		JobID:         alloc.JobID,
 		Name:          task.Name,
 		Restarter:     restarter,
 		Services:      task.Services,
@@ -43,7 +50,10 @@ func NewTaskServices(alloc *structs.Allocation, task *structs.Task, restarter Ta
 	}
 
 	if task.Resources != nil {
-		ts.Networks = task.Resources.Networks
+		ts.Networks = append(ts.Networks, task.Resources.Networks...)
+	}
+	if alloc.SharedResources != nil {
+		ts.Networks = append(ts.Networks, alloc.SharedResources.Networks...)
 	}
 
 	if alloc.DeploymentStatus != nil && alloc.DeploymentStatus.Canary {
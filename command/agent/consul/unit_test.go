@@ -1369,6 +1369,72 @@ func TestConsul_CanaryTags(t *testing.T) {
 	require.Len(ctx.FakeConsul.services, 0)
 }
 
+// TestConsul_Connect asserts the sidecar proxies of Connect services are
+// registered along with them and left alone by the sync.
+func TestConsul_Connect(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ctx := setupFake(t)
+
+	ctx.Task.Name = "group-web"
+	ctx.Task.Networks = []*structs.NetworkResource{
+		{
+			Mode: structs.NetworkModeBridge,
+			IP:   "10.0.0.1",
+			DynamicPorts: []structs.Port{
+				{Label: "x", Value: xPort, To: 8080},
+				{Label: "connect-proxy-web", Value: yPort},
+			},
+		},
+	}
+	ctx.Task.Services[0].Connect = &structs.ConsulConnect{
+		SidecarService: &structs.ConsulSidecarService{
+			Port: "connect-proxy-web",
+			Proxy: &structs.ConsulProxy{
+				Upstreams: []*structs.ConsulUpstream{
+					{DestinationName: "db", LocalBindPort: 5432},
+				},
+			},
+		},
+	}
+
+	// The canary flag doesn't change the ID of Connect services
+	ctx.Task.Canary = true
+	id := makeTaskServiceID(ctx.Task.AllocID, ctx.Task.Name, ctx.Task.Services[0], false)
+
+	require.NoError(ctx.ServiceClient.RegisterTask(ctx.Task))
+	require.NoError(ctx.syncOnce())
+	require.Len(ctx.FakeConsul.services, 1)
+	require.Contains(ctx.FakeConsul.services, id)
+
+	sidecar := ctx.FakeConsul.services[id].Connect.SidecarService
+	require.Equal("10.0.0.1", sidecar.Address)
+	require.Equal(yPort, sidecar.Port)
+	require.Equal("127.0.0.1", sidecar.Proxy.LocalServiceAddress)
+	require.Equal(8080, sidecar.Proxy.LocalServicePort)
+	require.Equal([]api.Upstream{{DestinationName: "db", LocalBindPort: 5432}}, sidecar.Proxy.Upstreams)
+	require.Equal("0.0.0.0", sidecar.Proxy.Config["bind_address"])
+	require.Equal(yPort, sidecar.Proxy.Config["bind_port"])
+
+	// Consul registers the sidecar proxy and its check, which the sync
+	// must not deregister
+	sidecarID := id + sidecarProxySuffix
+	ctx.FakeConsul.ServiceRegister(&api.AgentServiceRegistration{ID: sidecarID, Name: "web-sidecar-proxy"})
+	ctx.FakeConsul.CheckRegister(&api.AgentCheckRegistration{ID: "service:" + sidecarID, ServiceID: sidecarID})
+	require.NoError(ctx.ServiceClient.sync())
+	require.Len(ctx.FakeConsul.services, 2)
+	require.Len(ctx.FakeConsul.checks, 1)
+
+	ctx.ServiceClient.RemoveTask(ctx.Task)
+	require.NoError(ctx.syncOnce())
+	require.NotContains(ctx.FakeConsul.services, id)
+
+	// Once the service is gone its sidecar is cleaned up as well
+	require.NoError(ctx.ServiceClient.sync())
+	require.Len(ctx.FakeConsul.services, 0)
+	require.Len(ctx.FakeConsul.checks, 0)
+}
+
 // TestConsul_CanaryTags_NoTags asserts Tags are used when Canary=true and there
 // are no specified canary tags
 func TestConsul_CanaryTags_NoTags(t *testing.T) {
@@ -1703,8 +1769,8 @@ func TestGetAddress(t *testing.T) {
 				i++
 			}
 
-			// Run getAddress
-			ip, port, err := getAddress(tc.Mode, tc.PortLabel, networks, tc.Driver)
+			// Run GetAddress
+			ip, port, err := GetAddress(tc.Mode, tc.PortLabel, networks, tc.Driver)
 
 			// Assert the results
 			assert.Equal(t, tc.ExpectedIP, ip, "IP mismatch")
@@ -1,7 +1,9 @@
 package agent
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -12,7 +14,11 @@ func (s *HTTPServer) EvalsRequest(resp http.ResponseWriter, req *http.Request) (
 		return nil, CodedError(405, ErrInvalidMethod)
 	}
 
-	args := structs.EvalListRequest{}
+	query := req.URL.Query()
+	args := structs.EvalListRequest{
+		FilterJobID:      query.Get("job"),
+		FilterEvalStatus: query.Get("status"),
+	}
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
 	}
@@ -72,6 +78,13 @@ func (s *HTTPServer) evalQuery(resp http.ResponseWriter, req *http.Request, eval
 	args := structs.EvalSpecificRequest{
 		EvalID: evalID,
 	}
+	if relatedStr := req.URL.Query().Get("related"); relatedStr != "" {
+		related, err := strconv.ParseBool(relatedStr)
+		if err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a bool: %v", "related", relatedStr, err))
+		}
+		args.IncludeRelated = related
+	}
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
 	}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_EvalList(t *testing.T) {
@@ -197,3 +198,65 @@ func TestHTTP_EvalQuery(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTP_EvalList_Filter(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		eval1 := mock.Eval()
+		eval2 := mock.Eval()
+		eval2.Status = structs.EvalStatusBlocked
+		require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{eval1, eval2}))
+
+		// Filter on the job
+		req, err := http.NewRequest("GET", "/v1/evaluations?job="+eval1.JobID, nil)
+		require.NoError(err)
+		obj, err := s.Server.EvalsRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		evals := obj.([]*structs.Evaluation)
+		require.Len(evals, 1)
+		require.Equal(eval1.ID, evals[0].ID)
+
+		// Filter on the status
+		req, err = http.NewRequest("GET", "/v1/evaluations?status=blocked", nil)
+		require.NoError(err)
+		obj, err = s.Server.EvalsRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		evals = obj.([]*structs.Evaluation)
+		require.Len(evals, 1)
+		require.Equal(eval2.ID, evals[0].ID)
+	})
+}
+
+func TestHTTP_EvalQuery_Related(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		eval1 := mock.Eval()
+		eval2 := mock.Eval()
+		eval2.PreviousEval = eval1.ID
+		eval1.NextEval = eval2.ID
+		require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{eval1, eval2}))
+
+		req, err := http.NewRequest("GET", "/v1/evaluation/"+eval1.ID+"?related=true", nil)
+		require.NoError(err)
+		obj, err := s.Server.EvalSpecificRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		e := obj.(*structs.Evaluation)
+		require.Len(e.RelatedEvals, 1)
+		require.Equal(eval2.ID, e.RelatedEvals[0].ID)
+
+		// An invalid value is rejected
+		req, err = http.NewRequest("GET", "/v1/evaluation/"+eval1.ID+"?related=foo", nil)
+		require.NoError(err)
+		_, err = s.Server.EvalSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "Failed to parse")
+	})
+}
@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventStream streams the events of the changes of the state of the cluster
+// as newline delimited JSON. The parameters are:
+// * topic: "<topic>:<key>" filter of the events to stream, which may be given
+//          several times. The key may be omitted or "*" to match all the keys
+//          of the topic. Defaults to all the events.
+// * index: The index after which events are streamed, to resume a stream.
+//          Only new events are streamed if omitted.
+func (s *HTTPServer) EventStream(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	query := req.URL.Query()
+	args := &structs.EventStreamRequest{}
+	if indexStr := query.Get("index"); indexStr != "" {
+		index, err := strconv.ParseUint(indexStr, 10, 64)
+		if err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Failed to parse index: %v", err))
+		}
+		args.Index = index
+	}
+
+	topics, err := parseEventTopics(query["topic"])
+	if err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+	args.Topics = topics
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	// Stream from the local server or through the servers of the client
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if srv := s.agent.Server(); srv != nil {
+		handler, handlerErr = srv.StreamingRpcHandler("Event.Stream")
+	} else {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler("Event.Stream")
+	}
+	if handlerErr != nil {
+		return nil, CodedError(500, handlerErr.Error())
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-cache")
+	return s.streamImpl(resp, req, handler, args)
+}
+
+// parseEventTopics parses the "<topic>:<key>" filters of an event stream
+func parseEventTopics(filters []string) (map[structs.Topic][]string, error) {
+	topics := make(map[structs.Topic][]string, len(filters))
+	if len(filters) == 0 {
+		topics[structs.TopicAll] = []string{"*"}
+		return topics, nil
+	}
+
+	for _, filter := range filters {
+		parts := strings.SplitN(filter, ":", 2)
+		topic, key := structs.Topic(parts[0]), "*"
+		if len(parts) == 2 && parts[1] != "" {
+			key = parts[1]
+		}
+
+		switch topic {
+		case structs.TopicJob, structs.TopicAllocation, structs.TopicEvaluation,
+			structs.TopicDeployment, structs.TopicNode, structs.TopicAll:
+		default:
+			return nil, fmt.Errorf("Invalid topic %q", parts[0])
+		}
+		topics[topic] = append(topics[topic], key)
+	}
+	return topics, nil
+}
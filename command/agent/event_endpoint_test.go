@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_EventStream_IllegalMethod(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("POST", "/v1/event/stream", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.EventStream(respW, req)
+		require.Error(t, err)
+		require.Equal(t, 405, err.(HTTPCodedError).Code())
+	})
+}
+
+func TestHTTP_EventStream_InvalidTopic(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/v1/event/stream?topic=Unknown:*", nil)
+		require.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.EventStream(respW, req)
+		require.Error(t, err)
+		require.Equal(t, 400, err.(HTTPCodedError).Code())
+	})
+}
+
+func TestHTTP_EventStream_ParseTopics(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name     string
+		Filters  []string
+		Expected map[structs.Topic][]string
+		Err      string
+	}{
+		{
+			Name:     "all topics",
+			Expected: map[structs.Topic][]string{structs.TopicAll: {"*"}},
+		},
+		{
+			Name:    "keys",
+			Filters: []string{"Job:web", "Job:api", "Node"},
+			Expected: map[structs.Topic][]string{
+				structs.TopicJob:  {"web", "api"},
+				structs.TopicNode: {"*"},
+			},
+		},
+		{
+			Name:     "empty key",
+			Filters:  []string{"Evaluation:"},
+			Expected: map[structs.Topic][]string{structs.TopicEvaluation: {"*"}},
+		},
+		{
+			Name:    "invalid topic",
+			Filters: []string{"Jobs:web"},
+			Err:     `Invalid topic "Jobs"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			topics, err := parseEventTopics(c.Filters)
+			if c.Err != "" {
+				require.EqualError(t, err, c.Err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.Expected, topics)
+		})
+	}
+}
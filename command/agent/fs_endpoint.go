@@ -338,6 +338,15 @@ func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 		return nil, CodedError(500, handlerErr.Error())
 	}
 
+	return s.streamImpl(resp, req, handler, args)
+}
+
+// streamImpl is used to make a streaming RPC call with the given handler that
+// serializes the args and then expects a stream of StreamErrWrapper results
+// where the payload is copied to the response body.
+func (s *HTTPServer) streamImpl(resp http.ResponseWriter, req *http.Request,
+	handler structs.StreamingRpcHandler, args interface{}) (interface{}, error) {
+
 	// Create a pipe connecting the (possibly remote) handler to the http response
 	httpPipe, handlerPipe := net.Pipe()
 	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
@@ -68,6 +68,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/versions"):
 		jobName := strings.TrimSuffix(path, "/versions")
 		return s.jobVersions(resp, req, jobName)
+	case strings.HasSuffix(path, "/submission"):
+		jobName := strings.TrimSuffix(path, "/submission")
+		return s.jobSubmission(resp, req, jobName)
 	case strings.HasSuffix(path, "/revert"):
 		jobName := strings.TrimSuffix(path, "/revert")
 		return s.jobRevert(resp, req, jobName)
@@ -381,6 +384,7 @@ func (s *HTTPServer) jobUpdate(resp http.ResponseWriter, req *http.Request,
 		EnforceIndex:   args.EnforceIndex,
 		JobModifyIndex: args.JobModifyIndex,
 		PolicyOverride: args.PolicyOverride,
+		Submission:     apiJobSubmissionToStructs(args.Submission),
 		WriteRequest: structs.WriteRequest{
 			Region:    args.WriteRequest.Region,
 			AuthToken: args.WriteRequest.SecretID,
@@ -458,6 +462,38 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request,
 	return out, nil
 }
 
+func (s *HTTPServer) jobSubmission(resp http.ResponseWriter, req *http.Request,
+	jobName string) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.JobSubmissionRequest{
+		JobID: jobName,
+	}
+	if versionStr := req.URL.Query().Get("version"); versionStr != "" {
+		version, err := strconv.ParseUint(versionStr, 10, 64)
+		if err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a uint64: %v", "version", versionStr, err))
+		}
+		args.Version = &version
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobSubmissionResponse
+	if err := s.agent.RPC("Job.GetJobSubmission", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Submission == nil {
+		return nil, CodedError(404, "job source not found")
+	}
+	return out.Submission, nil
+}
+
 func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request,
 	jobName string) (interface{}, error) {
 
@@ -588,6 +624,19 @@ func (s *HTTPServer) JobsParseRequest(resp http.ResponseWriter, req *http.Reques
 	return jobStruct, nil
 }
 
+// apiJobSubmissionToStructs converts the source of a submitted job
+func apiJobSubmissionToStructs(sub *api.JobSubmission) *structs.JobSubmission {
+	if sub == nil {
+		return nil
+	}
+	return &structs.JobSubmission{
+		Source:        sub.Source,
+		Format:        sub.Format,
+		VariableFlags: sub.VariableFlags,
+		Variables:     sub.Variables,
+	}
+}
+
 func ApiJobToStructJob(job *api.Job) *structs.Job {
 	job.Canonicalize()
 
@@ -607,6 +656,10 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		VaultToken:  *job.VaultToken,
 	}
 
+	if job.NodePool != nil {
+		j.NodePool = *job.NodePool
+	}
+
 	if l := len(job.Constraints); l != 0 {
 		j.Constraints = make([]*structs.Constraint, l)
 		for i, c := range job.Constraints {
@@ -663,6 +716,10 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		}
 	}
 
+	if job.Multiregion != nil {
+		j.Multiregion = ApiMultiregionToStructs(job.Multiregion)
+	}
+
 	if l := len(job.TaskGroups); l != 0 {
 		j.TaskGroups = make([]*structs.TaskGroup, l)
 		for i, taskGroup := range job.TaskGroups {
@@ -745,10 +802,26 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 			HealthyDeadline:  *taskGroup.Update.HealthyDeadline,
 			ProgressDeadline: *taskGroup.Update.ProgressDeadline,
 			AutoRevert:       *taskGroup.Update.AutoRevert,
+			AutoPromote:      *taskGroup.Update.AutoPromote,
 			Canary:           *taskGroup.Update.Canary,
 		}
 	}
 
+	if l := len(taskGroup.Volumes); l != 0 {
+		tg.Volumes = make(map[string]*structs.VolumeRequest, l)
+		for k, v := range taskGroup.Volumes {
+			tg.Volumes[k] = &structs.VolumeRequest{
+				Name:     v.Name,
+				Type:     v.Type,
+				Source:   v.Source,
+				ReadOnly: v.ReadOnly,
+			}
+		}
+	}
+
+	tg.Networks = ApiNetworkResourceToStructs(taskGroup.Networks)
+	tg.Services = ApiServicesToStructs(taskGroup.Services)
+
 	if l := len(taskGroup.Tasks); l != 0 {
 		tg.Tasks = make([]*structs.Task, l)
 		for l, task := range taskGroup.Tasks {
@@ -759,6 +832,169 @@ func ApiTgToStructsTG(taskGroup *api.TaskGroup, tg *structs.TaskGroup) {
 	}
 }
 
+// ApiNetworkResourceToStructs is a copy and type conversion between the API
+// representation of networks and their struct representation.
+func ApiNetworkResourceToStructs(in []*api.NetworkResource) []*structs.NetworkResource {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]*structs.NetworkResource, len(in))
+	for i, nw := range in {
+		out[i] = &structs.NetworkResource{
+			Mode:  nw.Mode,
+			CIDR:  nw.CIDR,
+			IP:    nw.IP,
+			MBits: *nw.MBits,
+		}
+
+		if l := len(nw.DynamicPorts); l != 0 {
+			out[i].DynamicPorts = make([]structs.Port, l)
+			for j, dp := range nw.DynamicPorts {
+				out[i].DynamicPorts[j] = structs.Port{
+					Label:       dp.Label,
+					Value:       dp.Value,
+					To:          dp.To,
+					HostNetwork: dp.HostNetwork,
+				}
+			}
+		}
+
+		if l := len(nw.ReservedPorts); l != 0 {
+			out[i].ReservedPorts = make([]structs.Port, l)
+			for j, rp := range nw.ReservedPorts {
+				out[i].ReservedPorts[j] = structs.Port{
+					Label:       rp.Label,
+					Value:       rp.Value,
+					To:          rp.To,
+					HostNetwork: rp.HostNetwork,
+				}
+			}
+		}
+	}
+	return out
+}
+
+// ApiServicesToStructs is a copy and type conversion between the API
+// representation of services and their struct representation.
+func ApiServicesToStructs(in []*api.Service) []*structs.Service {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]*structs.Service, len(in))
+	for i, service := range in {
+		out[i] = &structs.Service{
+			Name:        service.Name,
+			PortLabel:   service.PortLabel,
+			Tags:        service.Tags,
+			CanaryTags:  service.CanaryTags,
+			AddressMode: service.AddressMode,
+			Provider:    service.Provider,
+			Connect:     ApiConsulConnectToStructs(service.Connect),
+		}
+
+		if l := len(service.Checks); l != 0 {
+			out[i].Checks = make([]*structs.ServiceCheck, l)
+			for j, check := range service.Checks {
+				out[i].Checks[j] = &structs.ServiceCheck{
+					Name:          check.Name,
+					Type:          check.Type,
+					Command:       check.Command,
+					Args:          check.Args,
+					Path:          check.Path,
+					Protocol:      check.Protocol,
+					PortLabel:     check.PortLabel,
+					AddressMode:   check.AddressMode,
+					Interval:      check.Interval,
+					Timeout:       check.Timeout,
+					InitialStatus: check.InitialStatus,
+					TLSSkipVerify: check.TLSSkipVerify,
+					Header:        check.Header,
+					Method:        check.Method,
+					GRPCService:   check.GRPCService,
+					GRPCUseTLS:    check.GRPCUseTLS,
+				}
+				if check.CheckRestart != nil {
+					out[i].Checks[j].CheckRestart = &structs.CheckRestart{
+						Limit:          check.CheckRestart.Limit,
+						Grace:          *check.CheckRestart.Grace,
+						IgnoreWarnings: check.CheckRestart.IgnoreWarnings,
+					}
+				}
+			}
+		}
+	}
+	return out
+}
+
+// ApiConsulConnectToStructs is a copy and type conversion between the API
+// representation of a Consul Connect configuration and its struct
+// representation.
+func ApiConsulConnectToStructs(in *api.ConsulConnect) *structs.ConsulConnect {
+	if in == nil {
+		return nil
+	}
+
+	out := &structs.ConsulConnect{}
+	if sidecar := in.SidecarService; sidecar != nil {
+		out.SidecarService = &structs.ConsulSidecarService{
+			Tags: sidecar.Tags,
+			Port: sidecar.Port,
+		}
+
+		if proxy := sidecar.Proxy; proxy != nil {
+			out.SidecarService.Proxy = &structs.ConsulProxy{
+				LocalServiceAddress: proxy.LocalServiceAddress,
+				LocalServicePort:    proxy.LocalServicePort,
+				Config:              proxy.Config,
+			}
+
+			if l := len(proxy.Upstreams); l != 0 {
+				out.SidecarService.Proxy.Upstreams = make([]*structs.ConsulUpstream, l)
+				for i, u := range proxy.Upstreams {
+					out.SidecarService.Proxy.Upstreams[i] = &structs.ConsulUpstream{
+						DestinationName: u.DestinationName,
+						LocalBindPort:   u.LocalBindPort,
+					}
+				}
+			}
+		}
+	}
+
+	if task := in.SidecarTask; task != nil {
+		out.SidecarTask = &structs.SidecarTask{
+			Driver:        task.Driver,
+			User:          task.User,
+			Config:        task.Config,
+			Env:           task.Env,
+			Meta:          task.Meta,
+			KillTimeout:   task.KillTimeout,
+			ShutdownDelay: task.ShutdownDelay,
+			KillSignal:    task.KillSignal,
+		}
+
+		if r := task.Resources; r != nil {
+			out.SidecarTask.Resources = &structs.Resources{
+				CPU:      *r.CPU,
+				MemoryMB: *r.MemoryMB,
+				IOPS:     *r.IOPS,
+			}
+			if r.MemoryMaxMB != nil {
+				out.SidecarTask.Resources.MemoryMaxMB = *r.MemoryMaxMB
+			}
+		}
+
+		if lc := task.LogConfig; lc != nil {
+			out.SidecarTask.LogConfig = &structs.LogConfig{
+				MaxFiles:      *lc.MaxFiles,
+				MaxFileSizeMB: *lc.MaxFileSizeMB,
+			}
+		}
+	}
+	return out
+}
+
 // ApiTaskToStructsTask is a copy and type conversion between the API
 // representation of a task from a struct representation of a task.
 func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
@@ -772,6 +1008,7 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 	structsTask.KillTimeout = *apiTask.KillTimeout
 	structsTask.ShutdownDelay = apiTask.ShutdownDelay
 	structsTask.KillSignal = apiTask.KillSignal
+	structsTask.Kind = structs.TaskKind(apiTask.Kind)
 
 	if l := len(apiTask.Constraints); l != 0 {
 		structsTask.Constraints = make([]*structs.Constraint, l)
@@ -789,49 +1026,7 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 		}
 	}
 
-	if l := len(apiTask.Services); l != 0 {
-		structsTask.Services = make([]*structs.Service, l)
-		for i, service := range apiTask.Services {
-			structsTask.Services[i] = &structs.Service{
-				Name:        service.Name,
-				PortLabel:   service.PortLabel,
-				Tags:        service.Tags,
-				CanaryTags:  service.CanaryTags,
-				AddressMode: service.AddressMode,
-			}
-
-			if l := len(service.Checks); l != 0 {
-				structsTask.Services[i].Checks = make([]*structs.ServiceCheck, l)
-				for j, check := range service.Checks {
-					structsTask.Services[i].Checks[j] = &structs.ServiceCheck{
-						Name:          check.Name,
-						Type:          check.Type,
-						Command:       check.Command,
-						Args:          check.Args,
-						Path:          check.Path,
-						Protocol:      check.Protocol,
-						PortLabel:     check.PortLabel,
-						AddressMode:   check.AddressMode,
-						Interval:      check.Interval,
-						Timeout:       check.Timeout,
-						InitialStatus: check.InitialStatus,
-						TLSSkipVerify: check.TLSSkipVerify,
-						Header:        check.Header,
-						Method:        check.Method,
-						GRPCService:   check.GRPCService,
-						GRPCUseTLS:    check.GRPCUseTLS,
-					}
-					if check.CheckRestart != nil {
-						structsTask.Services[i].Checks[j].CheckRestart = &structs.CheckRestart{
-							Limit:          check.CheckRestart.Limit,
-							Grace:          *check.CheckRestart.Grace,
-							IgnoreWarnings: check.CheckRestart.IgnoreWarnings,
-						}
-					}
-				}
-			}
-		}
-	}
+	structsTask.Services = ApiServicesToStructs(apiTask.Services)
 
 	structsTask.Resources = &structs.Resources{
 		CPU:      *apiTask.Resources.CPU,
@@ -839,32 +1034,33 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 		IOPS:     *apiTask.Resources.IOPS,
 	}
 
-	if l := len(apiTask.Resources.Networks); l != 0 {
-		structsTask.Resources.Networks = make([]*structs.NetworkResource, l)
-		for i, nw := range apiTask.Resources.Networks {
-			structsTask.Resources.Networks[i] = &structs.NetworkResource{
-				CIDR:  nw.CIDR,
-				IP:    nw.IP,
-				MBits: *nw.MBits,
+	if apiTask.Resources.MemoryMaxMB != nil {
+		structsTask.Resources.MemoryMaxMB = *apiTask.Resources.MemoryMaxMB
+	}
+
+	structsTask.Resources.Networks = ApiNetworkResourceToStructs(apiTask.Resources.Networks)
+
+	if l := len(apiTask.Resources.Devices); l != 0 {
+		structsTask.Resources.Devices = make([]*structs.RequestedDevice, l)
+		for i, d := range apiTask.Resources.Devices {
+			structsTask.Resources.Devices[i] = &structs.RequestedDevice{
+				Name:  d.Name,
+				Count: *d.Count,
 			}
 
-			if l := len(nw.DynamicPorts); l != 0 {
-				structsTask.Resources.Networks[i].DynamicPorts = make([]structs.Port, l)
-				for j, dp := range nw.DynamicPorts {
-					structsTask.Resources.Networks[i].DynamicPorts[j] = structs.Port{
-						Label: dp.Label,
-						Value: dp.Value,
-					}
+			if l := len(d.Constraints); l != 0 {
+				structsTask.Resources.Devices[i].Constraints = make([]*structs.Constraint, l)
+				for j, constraint := range d.Constraints {
+					c := &structs.Constraint{}
+					ApiConstraintToStructs(constraint, c)
+					structsTask.Resources.Devices[i].Constraints[j] = c
 				}
 			}
 
-			if l := len(nw.ReservedPorts); l != 0 {
-				structsTask.Resources.Networks[i].ReservedPorts = make([]structs.Port, l)
-				for j, rp := range nw.ReservedPorts {
-					structsTask.Resources.Networks[i].ReservedPorts[j] = structs.Port{
-						Label: rp.Label,
-						Value: rp.Value,
-					}
+			if l := len(d.Affinities); l != 0 {
+				structsTask.Resources.Devices[i].Affinities = make([]*structs.Affinity, l)
+				for j, a := range d.Affinities {
+					structsTask.Resources.Devices[i].Affinities[j] = ApiAffinityToStructs(a)
 				}
 			}
 		}
@@ -920,6 +1116,24 @@ func ApiTaskToStructsTask(apiTask *api.Task, structsTask *structs.Task) {
 			File: apiTask.DispatchPayload.File,
 		}
 	}
+
+	if apiTask.Lifecycle != nil {
+		structsTask.Lifecycle = &structs.TaskLifecycleConfig{
+			Hook:    apiTask.Lifecycle.Hook,
+			Sidecar: apiTask.Lifecycle.Sidecar,
+		}
+	}
+
+	if l := len(apiTask.VolumeMounts); l != 0 {
+		structsTask.VolumeMounts = make([]*structs.VolumeMount, l)
+		for i, mount := range apiTask.VolumeMounts {
+			structsTask.VolumeMounts[i] = &structs.VolumeMount{
+				Volume:      mount.Volume,
+				Destination: mount.Destination,
+				ReadOnly:    mount.ReadOnly,
+			}
+		}
+	}
 }
 
 func ApiConstraintToStructs(c1 *api.Constraint, c2 *structs.Constraint) {
@@ -952,3 +1166,33 @@ func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	}
 	return ret
 }
+
+func ApiMultiregionToStructs(m *api.Multiregion) *structs.Multiregion {
+	mr := &structs.Multiregion{}
+	if m.Strategy != nil {
+		mr.Strategy = &structs.MultiregionStrategy{}
+		if m.Strategy.MaxParallel != nil {
+			mr.Strategy.MaxParallel = *m.Strategy.MaxParallel
+		}
+		if m.Strategy.OnFailure != nil {
+			mr.Strategy.OnFailure = *m.Strategy.OnFailure
+		}
+	}
+
+	if l := len(m.Regions); l != 0 {
+		mr.Regions = make([]*structs.MultiregionRegion, l)
+		for i, region := range m.Regions {
+			r := &structs.MultiregionRegion{
+				Name:        region.Name,
+				Datacenters: region.Datacenters,
+				Meta:        region.Meta,
+			}
+			if region.Count != nil {
+				r.Count = *region.Count
+			}
+			mr.Regions[i] = r
+		}
+	}
+
+	return mr
+}
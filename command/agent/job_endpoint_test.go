@@ -15,6 +15,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_JobsList(t *testing.T) {
@@ -860,6 +861,56 @@ func TestHTTP_JobDeployment(t *testing.T) {
 	})
 }
 
+func TestHTTP_JobSubmission(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Register the job with its source through the API
+		job := api.MockJob()
+		args := api.JobRegisterRequest{
+			Job: job,
+			Submission: &api.JobSubmission{
+				Source: `job "example" {}`,
+				Format: "hcl2",
+			},
+			WriteRequest: api.WriteRequest{Region: "global"},
+		}
+		buf := encodeReq(args)
+		req, err := http.NewRequest("PUT", "/v1/jobs", buf)
+		require.NoError(err)
+		_, err = s.Server.JobsRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+
+		// Get the source of the current version
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/submission", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.JobSpecificRequest(respW, req)
+		require.NoError(err)
+
+		sub := obj.(*structs.JobSubmission)
+		require.Equal(`job "example" {}`, sub.Source)
+		require.Equal("hcl2", sub.Format)
+		require.Equal(uint64(0), sub.Version)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// A version without a source is not found
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/submission?version=3", nil)
+		require.NoError(err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "job source not found")
+
+		// An invalid version is rejected
+		req, err = http.NewRequest("GET", "/v1/job/"+*job.ID+"/submission?version=foo", nil)
+		require.NoError(err)
+		_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "Failed to parse")
+	})
+}
+
 func TestHTTP_JobVersions(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -1227,6 +1278,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			HealthyDeadline:  helper.TimeToPtr(3 * time.Minute),
 			ProgressDeadline: helper.TimeToPtr(3 * time.Minute),
 			AutoRevert:       helper.BoolToPtr(false),
+			AutoPromote:      helper.BoolToPtr(false),
 			Canary:           helper.IntToPtr(1),
 		},
 		Spreads: []*api.Spread{
@@ -1313,12 +1365,32 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Sticky:  helper.BoolToPtr(true),
 					Migrate: helper.BoolToPtr(true),
 				},
+				Volumes: map[string]*api.VolumeRequest{
+					"shared": {
+						Name:     "shared",
+						Type:     "host",
+						Source:   "shared_data",
+						ReadOnly: true,
+					},
+				},
+				Networks: []*api.NetworkResource{
+					{
+						Mode:          "bridge",
+						MBits:         helper.IntToPtr(10),
+						ReservedPorts: []api.Port{{Label: "http", Value: 8080, To: 80}},
+						DynamicPorts: []api.Port{
+							{Label: "admin", To: 9090},
+							{Label: "metrics", HostNetwork: "private"},
+						},
+					},
+				},
 				Update: &api.UpdateStrategy{
 					HealthCheck:      helper.StringToPtr(structs.UpdateStrategyHealthCheck_Checks),
 					MinHealthyTime:   helper.TimeToPtr(2 * time.Minute),
 					HealthyDeadline:  helper.TimeToPtr(5 * time.Minute),
 					ProgressDeadline: helper.TimeToPtr(5 * time.Minute),
 					AutoRevert:       helper.BoolToPtr(true),
+					AutoPromote:      helper.BoolToPtr(false),
 				},
 
 				Meta: map[string]string{
@@ -1416,6 +1488,27 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 									},
 								},
 							},
+							Devices: []*api.RequestedDevice{
+								{
+									Name:  "nvidia/gpu",
+									Count: helper.Uint64ToPtr(4),
+									Constraints: []*api.Constraint{
+										{
+											LTarget: "${device.model}",
+											RTarget: "Tesla K80",
+											Operand: "=",
+										},
+									},
+									Affinities: []*api.Affinity{
+										{
+											LTarget: "${device.attr.memory}",
+											RTarget: "8192",
+											Operand: ">=",
+											Weight:  25,
+										},
+									},
+								},
+							},
 						},
 						Meta: map[string]string{
 							"lol": "code",
@@ -1460,6 +1553,17 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						DispatchPayload: &api.DispatchPayloadConfig{
 							File: "fileA",
 						},
+						Lifecycle: &api.TaskLifecycle{
+							Hook:    "prestart",
+							Sidecar: true,
+						},
+						VolumeMounts: []*api.VolumeMount{
+							{
+								Volume:      "shared",
+								Destination: "/srv/shared",
+								ReadOnly:    true,
+							},
+						},
 					},
 				},
 			},
@@ -1587,6 +1691,25 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					Sticky:  true,
 					Migrate: true,
 				},
+				Volumes: map[string]*structs.VolumeRequest{
+					"shared": {
+						Name:     "shared",
+						Type:     "host",
+						Source:   "shared_data",
+						ReadOnly: true,
+					},
+				},
+				Networks: []*structs.NetworkResource{
+					{
+						Mode:          "bridge",
+						MBits:         10,
+						ReservedPorts: []structs.Port{{Label: "http", Value: 8080, To: 80}},
+						DynamicPorts: []structs.Port{
+							{Label: "admin", To: 9090},
+							{Label: "metrics", HostNetwork: "private"},
+						},
+					},
+				},
 				Update: &structs.UpdateStrategy{
 					Stagger:          1 * time.Second,
 					MaxParallel:      5,
@@ -1634,6 +1757,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								CanaryTags:  []string{"3", "4"},
 								PortLabel:   "foo",
 								AddressMode: "auto",
+								Provider:    "consul",
 								Checks: []*structs.ServiceCheck{
 									{
 										Name:          "bar",
@@ -1690,6 +1814,27 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 									},
 								},
 							},
+							Devices: []*structs.RequestedDevice{
+								{
+									Name:  "nvidia/gpu",
+									Count: 4,
+									Constraints: []*structs.Constraint{
+										{
+											LTarget: "${device.model}",
+											RTarget: "Tesla K80",
+											Operand: "=",
+										},
+									},
+									Affinities: []*structs.Affinity{
+										{
+											LTarget: "${device.attr.memory}",
+											RTarget: "8192",
+											Operand: ">=",
+											Weight:  25,
+										},
+									},
+								},
+							},
 						},
 						Meta: map[string]string{
 							"lol": "code",
@@ -1734,6 +1879,17 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 						DispatchPayload: &structs.DispatchPayloadConfig{
 							File: "fileA",
 						},
+						Lifecycle: &structs.TaskLifecycleConfig{
+							Hook:    "prestart",
+							Sidecar: true,
+						},
+						VolumeMounts: []*structs.VolumeMount{
+							{
+								Volume:      "shared",
+								Destination: "/srv/shared",
+								ReadOnly:    true,
+							},
+						},
 					},
 				},
 			},
@@ -1984,3 +2140,66 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 		t.Fatalf("bad:\n%s", strings.Join(diff, "\n"))
 	}
 }
+
+func TestJobs_ApiServicesToStructs_Connect(t *testing.T) {
+	t.Parallel()
+
+	apiService := &api.Service{
+		Name:      "web",
+		PortLabel: "http",
+		Connect: &api.ConsulConnect{
+			SidecarService: &api.ConsulSidecarService{
+				Tags: []string{"proxy"},
+				Proxy: &api.ConsulProxy{
+					LocalServicePort: 8080,
+					Upstreams: []*api.ConsulUpstream{
+						{DestinationName: "db", LocalBindPort: 5432},
+					},
+					Config: map[string]interface{}{"protocol": "http"},
+				},
+			},
+			SidecarTask: &api.SidecarTask{
+				Driver:      "raw_exec",
+				KillTimeout: helper.TimeToPtr(10 * time.Second),
+				Resources:   &api.Resources{CPU: helper.IntToPtr(500)},
+				LogConfig:   &api.LogConfig{MaxFiles: helper.IntToPtr(3)},
+			},
+		},
+	}
+	apiService.Canonicalize(nil, &api.TaskGroup{Name: helper.StringToPtr("group")}, &api.Job{Name: helper.StringToPtr("job")})
+
+	expected := []*structs.Service{
+		{
+			Name:        "web",
+			PortLabel:   "http",
+			AddressMode: "auto",
+			Provider:    "consul",
+			Connect: &structs.ConsulConnect{
+				SidecarService: &structs.ConsulSidecarService{
+					Tags: []string{"proxy"},
+					Proxy: &structs.ConsulProxy{
+						LocalServicePort: 8080,
+						Upstreams: []*structs.ConsulUpstream{
+							{DestinationName: "db", LocalBindPort: 5432},
+						},
+						Config: map[string]interface{}{"protocol": "http"},
+					},
+				},
+				SidecarTask: &structs.SidecarTask{
+					Driver:      "raw_exec",
+					KillTimeout: helper.TimeToPtr(10 * time.Second),
+					Resources: &structs.Resources{
+						CPU:      500,
+						MemoryMB: 300,
+					},
+					LogConfig: &structs.LogConfig{
+						MaxFiles:      3,
+						MaxFileSizeMB: 10,
+					},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, expected, ApiServicesToStructs([]*api.Service{apiService}))
+}
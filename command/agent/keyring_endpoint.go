@@ -0,0 +1,96 @@
+package agent
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// JSONWebKeySet is the response of the JWKS endpoint
+type JSONWebKeySet struct {
+	Keys []*auth.JSONWebKey `json:"keys"`
+}
+
+// JWKSRequest returns the public keys used to verify workload identities as
+// a JSON Web Key Set.
+func (s *HTTPServer) JWKSRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.KeyringListPublicRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListPublicResponse
+	if err := s.agent.RPC("Keyring.ListPublic", &args, &out); err != nil {
+		return nil, err
+	}
+	setMeta(resp, &out.QueryMeta)
+
+	set := &JSONWebKeySet{Keys: make([]*auth.JSONWebKey, 0, len(out.PublicKeys))}
+	for _, pub := range out.PublicKeys {
+		key, err := x509.ParsePKIXPublicKey(pub.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key %q: %v", pub.KeyID, err)
+		}
+		jwk, err := auth.NewJSONWebKey(pub.KeyID, pub.Algorithm, key)
+		if err != nil {
+			return nil, err
+		}
+		jwk.Use = "sig"
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// KeyringListRequest returns the metadata of the root keys
+func (s *HTTPServer) KeyringListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.KeyringListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.KeyringListResponse
+	if err := s.agent.RPC("Keyring.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Keys == nil {
+		out.Keys = make([]*structs.RootKeyStub, 0)
+	}
+	return out.Keys, nil
+}
+
+// KeyringRotateRequest replaces the active root key by a new one
+func (s *HTTPServer) KeyringRotateRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if !(req.Method == "PUT" || req.Method == "POST") {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.KeyringRotateRequest{}
+	s.parseWriteRequest(req, &args.WriteRequest)
+	if full := req.URL.Query().Get("full"); full != "" {
+		var err error
+		if args.Full, err = strconv.ParseBool(full); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("Invalid full value: %v", err))
+		}
+	}
+
+	var out structs.KeyringRotateResponse
+	if err := s.agent.RPC("Keyring.Rotate", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out.Key, nil
+}
@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_JWKS(t *testing.T) {
+	t.Parallel()
+	httpACLTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// No token is needed to fetch the key set
+		req, err := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.JWKSRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		active, err := s.Agent.Server().State().ActiveRootKeyMeta(nil)
+		require.NoError(err)
+
+		set := obj.(*JSONWebKeySet)
+		require.Len(set.Keys, 1)
+		key := set.Keys[0]
+		require.Equal(active.KeyID, key.KeyID)
+		require.Equal("EC", key.KeyType)
+		require.Equal("P-256", key.Curve)
+		require.Equal("ES256", key.Algorithm)
+		require.Equal("sig", key.Use)
+
+		// Only GET is allowed
+		req, err = http.NewRequest("PUT", "/.well-known/jwks.json", nil)
+		require.NoError(err)
+		_, err = s.Server.JWKSRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+	})
+}
+
+func TestHTTP_KeyringRotate(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		req, err := http.NewRequest("PUT", "/v1/operator/keyring/rotate?full=true", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.KeyringRotateRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		rotated := obj.(*structs.RootKeyStub)
+		require.True(rotated.Active)
+
+		req, err = http.NewRequest("GET", "/v1/operator/keyring/keys", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.KeyringListRequest(respW, req)
+		require.NoError(err)
+		keys := obj.([]*structs.RootKeyStub)
+		require.Len(keys, 2)
+		for _, key := range keys {
+			require.Equal(key.KeyID == rotated.KeyID, key.Active)
+		}
+
+		// The full parameter must be a boolean
+		req, err = http.NewRequest("PUT", "/v1/operator/keyring/rotate?full=maybe", nil)
+		require.NoError(err)
+		_, err = s.Server.KeyringRotateRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+	})
+}
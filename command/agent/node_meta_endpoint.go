@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) NodeMetaRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.nodeMetaRead(resp, req)
+	case "PUT", "POST":
+		return s.nodeMetaApply(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) nodeMetaRead(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Get the requested Node ID
+	requestedNode := req.URL.Query().Get("node_id")
+
+	// Build the request and parse the ACL token
+	args := structs.NodeSpecificRequest{
+		NodeID: requestedNode,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.NodeMetaResponse
+	if err := s.nodeMetaRPC(requestedNode, "NodeMeta.Read", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *HTTPServer) nodeMetaApply(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request and parse the ACL token
+	var args cstructs.NodeMetaApplyRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(400, fmt.Sprintf("Failed to decode body: %v", err))
+	}
+	if len(args.Meta) == 0 {
+		return nil, CodedError(400, "must provide metadata to apply")
+	}
+
+	// The node may be given as a query parameter or in the body
+	if requestedNode := req.URL.Query().Get("node_id"); requestedNode != "" {
+		args.NodeID = requestedNode
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply cstructs.NodeMetaResponse
+	if err := s.nodeMetaRPC(args.NodeID, "NodeMeta.Apply", &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+// nodeMetaRPC makes the node metadata RPC against the local client, or through
+// the servers if the node is a remote one.
+func (s *HTTPServer) nodeMetaRPC(nodeID, method string, args, reply interface{}) error {
+	// Determine the handler to use
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForNode(nodeID)
+
+	// Make the RPC
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC(method, args, reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC(method, args, reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC(method, args, reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil {
+		if structs.IsErrNoNodeConn(rpcErr) {
+			rpcErr = CodedError(404, rpcErr.Error())
+		} else if strings.Contains(rpcErr.Error(), "Unknown node") {
+			rpcErr = CodedError(404, rpcErr.Error())
+		}
+	}
+	return rpcErr
+}
@@ -0,0 +1,49 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_NodeMeta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Unsupported methods are rejected
+		req, err := http.NewRequest("DELETE", "/v1/client/metadata", nil)
+		require.NoError(err)
+		_, err = s.Server.NodeMetaRequest(httptest.NewRecorder(), req)
+		require.EqualError(err, ErrInvalidMethod)
+
+		// Metadata is required
+		req, err = http.NewRequest("POST", "/v1/client/metadata", encodeReq(cstructs.NodeMetaApplyRequest{}))
+		require.NoError(err)
+		_, err = s.Server.NodeMetaRequest(httptest.NewRecorder(), req)
+		require.Error(err)
+		require.Contains(err.Error(), "must provide metadata")
+
+		// Apply metadata to the local node
+		args := cstructs.NodeMetaApplyRequest{
+			Meta: map[string]*string{"canary": helper.StringToPtr("true")},
+		}
+		req, err = http.NewRequest("POST", "/v1/client/metadata", encodeReq(args))
+		require.NoError(err)
+		obj, err := s.Server.NodeMetaRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		require.Equal("true", obj.(cstructs.NodeMetaResponse).Meta["canary"])
+
+		// Read it back
+		req, err = http.NewRequest("GET", "/v1/client/metadata", nil)
+		require.NoError(err)
+		obj, err = s.Server.NodeMetaRequest(httptest.NewRecorder(), req)
+		require.NoError(err)
+		resp := obj.(cstructs.NodeMetaResponse)
+		require.Equal("true", resp.Meta["canary"])
+		require.Equal(args.Meta, resp.Dynamic)
+	})
+}
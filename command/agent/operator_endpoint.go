@@ -1,6 +1,9 @@
 package agent
 
 import (
+	"context"
+	"io"
+	"net"
 	"net/http"
 	"strings"
 
@@ -10,8 +13,10 @@ import (
 
 	"github.com/hashicorp/consul/agent/consul/autopilot"
 	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
+	"github.com/ugorji/go/codec"
 )
 
 func (s *HTTPServer) OperatorRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -21,6 +26,8 @@ func (s *HTTPServer) OperatorRequest(resp http.ResponseWriter, req *http.Request
 		return s.OperatorRaftConfiguration(resp, req)
 	case strings.HasPrefix(path, "peer"):
 		return s.OperatorRaftPeer(resp, req)
+	case strings.HasPrefix(path, "transfer-leadership"):
+		return s.OperatorRaftTransferLeadership(resp, req)
 	default:
 		return nil, CodedError(404, ErrInvalidMethod)
 	}
@@ -88,6 +95,23 @@ func (s *HTTPServer) OperatorRaftPeer(resp http.ResponseWriter, req *http.Reques
 	return nil, nil
 }
 
+// OperatorRaftTransferLeadership is used to make the leader step down so that
+// another server takes over the leadership.
+func (s *HTTPServer) OperatorRaftTransferLeadership(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "PUT" && req.Method != "POST" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var args structs.RaftTransferLeadershipRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var reply struct{}
+	if err := s.agent.RPC("Operator.RaftTransferLeadership", &args, &reply); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
 // OperatorAutopilotConfiguration is used to inspect the current Autopilot configuration.
 // This supports the stale query mode in case the cluster doesn't have a leader.
 func (s *HTTPServer) OperatorAutopilotConfiguration(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -208,3 +232,257 @@ func (s *HTTPServer) OperatorServerHealth(resp http.ResponseWriter, req *http.Re
 
 	return out, nil
 }
+
+// OperatorSchedulerConfiguration is used to inspect or update the current
+// scheduler configuration.
+func (s *HTTPServer) OperatorSchedulerConfiguration(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Switch on the method
+	switch req.Method {
+	case "GET":
+		var args structs.GenericRequest
+		if done := s.parse(resp, req, &args.Region, &args.QueryOptions); done {
+			return nil, nil
+		}
+
+		var reply structs.SchedulerConfiguration
+		if err := s.agent.RPC("Operator.SchedulerGetConfiguration", &args, &reply); err != nil {
+			return nil, err
+		}
+
+		out := api.SchedulerConfiguration{
+			SchedulerAlgorithm: api.SchedulerAlgorithm(reply.SchedulerAlgorithm),
+			PreemptionConfig: api.PreemptionConfig{
+				SystemSchedulerEnabled:   reply.PreemptionConfig.SystemSchedulerEnabled,
+				SysBatchSchedulerEnabled: reply.PreemptionConfig.SysBatchSchedulerEnabled,
+				BatchSchedulerEnabled:    reply.PreemptionConfig.BatchSchedulerEnabled,
+				ServiceSchedulerEnabled:  reply.PreemptionConfig.ServiceSchedulerEnabled,
+			},
+			CreateIndex: reply.CreateIndex,
+			ModifyIndex: reply.ModifyIndex,
+		}
+		if len(reply.NodePoolSchedulerAlgorithms) != 0 {
+			out.NodePoolSchedulerAlgorithms = make(map[string]api.SchedulerAlgorithm, len(reply.NodePoolSchedulerAlgorithms))
+			for pool, algorithm := range reply.NodePoolSchedulerAlgorithms {
+				out.NodePoolSchedulerAlgorithms[pool] = api.SchedulerAlgorithm(algorithm)
+			}
+		}
+
+		return out, nil
+
+	case "PUT":
+		var args structs.SchedulerSetConfigRequest
+		s.parseWriteRequest(req, &args.WriteRequest)
+
+		var conf api.SchedulerConfiguration
+		if err := decodeBody(req, &conf); err != nil {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing scheduler config: %v", err))
+		}
+
+		args.Config = structs.SchedulerConfiguration{
+			SchedulerAlgorithm: structs.SchedulerAlgorithm(conf.SchedulerAlgorithm),
+			PreemptionConfig: structs.PreemptionConfig{
+				SystemSchedulerEnabled:   conf.PreemptionConfig.SystemSchedulerEnabled,
+				SysBatchSchedulerEnabled: conf.PreemptionConfig.SysBatchSchedulerEnabled,
+				BatchSchedulerEnabled:    conf.PreemptionConfig.BatchSchedulerEnabled,
+				ServiceSchedulerEnabled:  conf.PreemptionConfig.ServiceSchedulerEnabled,
+			},
+		}
+		if len(conf.NodePoolSchedulerAlgorithms) != 0 {
+			args.Config.NodePoolSchedulerAlgorithms = make(map[string]structs.SchedulerAlgorithm, len(conf.NodePoolSchedulerAlgorithms))
+			for pool, algorithm := range conf.NodePoolSchedulerAlgorithms {
+				args.Config.NodePoolSchedulerAlgorithms[pool] = structs.SchedulerAlgorithm(algorithm)
+			}
+		}
+		if err := args.Config.Validate(); err != nil {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Invalid scheduler config: %v", err))
+		}
+
+		// Check for cas value
+		params := req.URL.Query()
+		if _, ok := params["cas"]; ok {
+			casVal, err := strconv.ParseUint(params.Get("cas"), 10, 64)
+			if err != nil {
+				return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Error parsing cas value: %v", err))
+			}
+			args.Config.ModifyIndex = casVal
+			args.CAS = true
+		}
+
+		var reply bool
+		if err := s.agent.RPC("Operator.SchedulerSetConfiguration", &args, &reply); err != nil {
+			return nil, err
+		}
+
+		// Only use the out value if this was a CAS
+		if !args.CAS {
+			return true, nil
+		}
+		return reply, nil
+
+	default:
+		return nil, CodedError(404, ErrInvalidMethod)
+	}
+}
+
+// SnapshotRequest is used to save a snapshot of the state of the cluster, or
+// to restore the cluster from one.
+func (s *HTTPServer) SnapshotRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	switch req.Method {
+	case "GET":
+		return s.snapshotSaveRequest(resp, req)
+	case "PUT", "POST":
+		return s.snapshotRestoreRequest(resp, req)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+// snapshotHandler returns the handler of a snapshot streaming RPC, from the
+// local server or through the servers of the client
+func (s *HTTPServer) snapshotHandler(method string) (structs.StreamingRpcHandler, error) {
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if srv := s.agent.Server(); srv != nil {
+		handler, handlerErr = srv.StreamingRpcHandler(method)
+	} else {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
+	}
+	if handlerErr != nil {
+		return nil, CodedError(500, handlerErr.Error())
+	}
+	return handler, nil
+}
+
+// snapshotSaveRequest streams the snapshot archive as the response body, with
+// its checksum in the Digest header.
+func (s *HTTPServer) snapshotSaveRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := &structs.SnapshotSaveRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	handler, err := s.snapshotHandler("Operator.SnapshotSave")
+	if err != nil {
+		return nil, err
+	}
+
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	errCh := make(chan HTTPCodedError, 1)
+	go func() {
+		defer cancel()
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		var res structs.SnapshotSaveResponse
+		if err := decoder.Decode(&res); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+		if res.ErrorMsg != "" {
+			errCh <- CodedError(res.ErrorCode, res.ErrorMsg)
+			return
+		}
+
+		setMeta(resp, &res.QueryMeta)
+		resp.Header().Set("Digest", res.SnapshotChecksum)
+		resp.Header().Set("Content-Type", "application/octet-stream")
+
+		if _, err := io.Copy(resp, httpPipe); err != nil &&
+			!strings.Contains(err.Error(), "closed") &&
+			!strings.Contains(err.Error(), "EOF") {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+		errCh <- nil
+	}()
+
+	handler(handlerPipe)
+	cancel()
+	codedErr := <-errCh
+	return nil, codedErr
+}
+
+// snapshotRestoreRequest restores the state of the cluster from the snapshot
+// archive of the request body.
+func (s *HTTPServer) snapshotRestoreRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := &structs.SnapshotRestoreRequest{}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	handler, err := s.snapshotHandler("Operator.SnapshotRestore")
+	if err != nil {
+		return nil, err
+	}
+
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	errCh := make(chan HTTPCodedError, 1)
+	go func() {
+		defer cancel()
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		// Stream the snapshot in chunks, ending with the error of the body,
+		// io.EOF once fully read
+		go func() {
+			buf := make([]byte, 32*1024)
+			for {
+				var wrapper cstructs.StreamErrWrapper
+				n, err := req.Body.Read(buf)
+				wrapper.Payload = buf[:n]
+				if err != nil {
+					wrapper.Error = &cstructs.RpcError{Message: err.Error()}
+				}
+				if encoder.Encode(&wrapper) != nil || err != nil {
+					return
+				}
+			}
+		}()
+
+		var res structs.SnapshotRestoreResponse
+		if err := decoder.Decode(&res); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+		if res.ErrorMsg != "" {
+			errCh <- CodedError(res.ErrorCode, res.ErrorMsg)
+			return
+		}
+
+		setMeta(resp, &res.QueryMeta)
+		errCh <- nil
+	}()
+
+	handler(handlerPipe)
+	cancel()
+	codedErr := <-errCh
+	return nil, codedErr
+}
@@ -11,8 +11,10 @@ import (
 
 	"github.com/hashicorp/consul/testutil/retry"
 	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHTTP_OperatorRaftConfiguration(t *testing.T) {
@@ -78,6 +80,31 @@ func TestHTTP_OperatorRaftPeer(t *testing.T) {
 	})
 }
 
+func TestHTTP_OperatorRaftTransferLeadership(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, func(c *Config) {
+		c.Server.RaftProtocol = 3
+	}, func(s *TestAgent) {
+		// Only PUT and POST are allowed
+		req, err := http.NewRequest("GET", "/v1/operator/raft/transfer-leadership", nil)
+		require.NoError(err)
+		resp := httptest.NewRecorder()
+		_, err = s.Server.OperatorRequest(resp, req)
+		require.Error(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+
+		// If we get this error, it proves the request made it to the
+		// leader, which has no other server to transfer to
+		req, err = http.NewRequest("PUT", "/v1/operator/raft/transfer-leadership", nil)
+		require.NoError(err)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.OperatorRequest(resp, req)
+		require.Error(err)
+		require.Contains(err.Error(), "no other healthy voter")
+	})
+}
+
 func TestOperator_AutopilotGetConfiguration(t *testing.T) {
 	t.Parallel()
 	httpTest(t, nil, func(s *TestAgent) {
@@ -198,6 +225,106 @@ func TestOperator_AutopilotCASConfiguration(t *testing.T) {
 	})
 }
 
+func TestOperator_SchedulerGetConfiguration(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+		body := bytes.NewBuffer(nil)
+		req, _ := http.NewRequest("GET", "/v1/operator/scheduler/configuration", body)
+		resp := httptest.NewRecorder()
+		obj, err := s.Server.OperatorSchedulerConfiguration(resp, req)
+		require.Nil(err)
+		require.Equal(200, resp.Code)
+		out, ok := obj.(api.SchedulerConfiguration)
+		require.True(ok)
+		require.True(out.PreemptionConfig.SystemSchedulerEnabled)
+		require.False(out.PreemptionConfig.ServiceSchedulerEnabled)
+	})
+}
+
+func TestOperator_SchedulerSetConfiguration(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+		body := bytes.NewBuffer([]byte(`{"PreemptionConfig": {"SystemSchedulerEnabled": true, "ServiceSchedulerEnabled": true}}`))
+		req, _ := http.NewRequest("PUT", "/v1/operator/scheduler/configuration", body)
+		resp := httptest.NewRecorder()
+		_, err := s.Server.OperatorSchedulerConfiguration(resp, req)
+		require.Nil(err)
+		require.Equal(200, resp.Code)
+
+		args := structs.GenericRequest{
+			QueryOptions: structs.QueryOptions{
+				Region: s.Config.Region,
+			},
+		}
+
+		var reply structs.SchedulerConfiguration
+		err = s.RPC("Operator.SchedulerGetConfiguration", &args, &reply)
+		require.Nil(err)
+		require.True(reply.PreemptionConfig.SystemSchedulerEnabled)
+		require.True(reply.PreemptionConfig.ServiceSchedulerEnabled)
+
+		// Create a CAS request, bad index
+		{
+			buf := bytes.NewBuffer([]byte(`{"PreemptionConfig": {"SystemSchedulerEnabled": false}}`))
+			req, _ := http.NewRequest("PUT", fmt.Sprintf("/v1/operator/scheduler/configuration?cas=%d", reply.ModifyIndex-1), buf)
+			resp := httptest.NewRecorder()
+			obj, err := s.Server.OperatorSchedulerConfiguration(resp, req)
+			require.Nil(err)
+			require.False(obj.(bool))
+		}
+
+		// Create a CAS request, good index
+		{
+			buf := bytes.NewBuffer([]byte(`{"PreemptionConfig": {"SystemSchedulerEnabled": false}}`))
+			req, _ := http.NewRequest("PUT", fmt.Sprintf("/v1/operator/scheduler/configuration?cas=%d", reply.ModifyIndex), buf)
+			resp := httptest.NewRecorder()
+			obj, err := s.Server.OperatorSchedulerConfiguration(resp, req)
+			require.Nil(err)
+			require.True(obj.(bool))
+		}
+
+		// Verify the update
+		err = s.RPC("Operator.SchedulerGetConfiguration", &args, &reply)
+		require.Nil(err)
+		require.False(reply.PreemptionConfig.SystemSchedulerEnabled)
+		require.False(reply.PreemptionConfig.ServiceSchedulerEnabled)
+	})
+}
+
+func TestOperator_SchedulerSetConfiguration_Algorithm(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+		body := bytes.NewBuffer([]byte(`{"SchedulerAlgorithm": "spread", "NodePoolSchedulerAlgorithms": {"batch": "binpack"}}`))
+		req, _ := http.NewRequest("PUT", "/v1/operator/scheduler/configuration", body)
+		resp := httptest.NewRecorder()
+		_, err := s.Server.OperatorSchedulerConfiguration(resp, req)
+		require.Nil(err)
+
+		req, _ = http.NewRequest("GET", "/v1/operator/scheduler/configuration", nil)
+		resp = httptest.NewRecorder()
+		obj, err := s.Server.OperatorSchedulerConfiguration(resp, req)
+		require.Nil(err)
+		out, ok := obj.(api.SchedulerConfiguration)
+		require.True(ok)
+		require.Equal(api.SchedulerAlgorithmSpread, out.SchedulerAlgorithm)
+		require.Equal(map[string]api.SchedulerAlgorithm{"batch": api.SchedulerAlgorithmBinpack}, out.NodePoolSchedulerAlgorithms)
+
+		// An unknown algorithm is rejected
+		body = bytes.NewBuffer([]byte(`{"SchedulerAlgorithm": "random"}`))
+		req, _ = http.NewRequest("PUT", "/v1/operator/scheduler/configuration", body)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.OperatorSchedulerConfiguration(resp, req)
+		require.Error(err)
+		codedErr, ok := err.(HTTPCodedError)
+		require.True(ok)
+		require.Equal(http.StatusBadRequest, codedErr.Code())
+		require.Contains(err.Error(), "random")
+	})
+}
+
 func TestOperator_ServerHealth(t *testing.T) {
 	httpTest(t, func(c *Config) {
 		c.Server.RaftProtocol = 3
@@ -257,3 +384,49 @@ func TestOperator_ServerHealth_Unhealthy(t *testing.T) {
 		})
 	})
 }
+
+func TestOperator_SnapshotRequests(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Save a snapshot
+		req, err := http.NewRequest("GET", "/v1/operator/snapshot", nil)
+		require.NoError(err)
+		resp := httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.NoError(err)
+		require.Equal(200, resp.Code)
+		require.True(strings.HasPrefix(resp.Header().Get("Digest"), "sha-256="))
+		require.NotEmpty(resp.Header().Get("X-Nomad-Index"))
+		require.Equal("application/octet-stream", resp.Header().Get("Content-Type"))
+
+		snap := resp.Body.Bytes()
+		meta, err := snapshot.Verify(bytes.NewReader(snap))
+		require.NoError(err)
+		require.NotZero(meta.Index)
+
+		// Restore it
+		req, err = http.NewRequest("PUT", "/v1/operator/snapshot", bytes.NewReader(snap))
+		require.NoError(err)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.NoError(err)
+		require.NotEmpty(resp.Header().Get("X-Nomad-Index"))
+
+		// A bad snapshot is rejected
+		req, err = http.NewRequest("PUT", "/v1/operator/snapshot", strings.NewReader("not a snapshot"))
+		require.NoError(err)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.Error(err)
+		require.Equal(500, err.(HTTPCodedError).Code())
+
+		// Only saving and restoring are supported
+		req, err = http.NewRequest("DELETE", "/v1/operator/snapshot", nil)
+		require.NoError(err)
+		resp = httptest.NewRecorder()
+		_, err = s.Server.SnapshotRequest(resp, req)
+		require.Error(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+	})
+}
@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_QuotaList(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		qs1 := mock.QuotaSpec()
+		qs2 := mock.QuotaSpec()
+		args := structs.QuotaSpecUpsertRequest{
+			Quotas:       []*structs.QuotaSpec{qs1, qs2},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		require.NoError(s.Agent.RPC("Quota.UpsertQuotaSpecs", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/quotas", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.QuotasRequest(respW, req)
+		require.NoError(err)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal("true", respW.HeaderMap.Get("X-Nomad-KnownLeader"))
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-LastContact"))
+
+		// Check the output
+		q := obj.([]*structs.QuotaSpec)
+		require.Len(q, 2)
+
+		// The usages are listed as well
+		req, err = http.NewRequest("GET", "/v1/quota-usages", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.QuotaUsagesRequest(respW, req)
+		require.NoError(err)
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Len(obj.([]*structs.QuotaUsage), 2)
+	})
+}
+
+func TestHTTP_QuotaQuery(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		qs1 := mock.QuotaSpec()
+		args := structs.QuotaSpecUpsertRequest{
+			Quotas:       []*structs.QuotaSpec{qs1},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		require.NoError(s.Agent.RPC("Quota.UpsertQuotaSpecs", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("GET", "/v1/quota/"+qs1.Name, nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(err)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal("true", respW.HeaderMap.Get("X-Nomad-KnownLeader"))
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-LastContact"))
+
+		// Check the output
+		q := obj.(*structs.QuotaSpec)
+		require.Equal(qs1.Name, q.Name)
+
+		// Query the usage
+		req, err = http.NewRequest("GET", "/v1/quota/usage/"+qs1.Name, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Equal(qs1.Name, obj.(*structs.QuotaUsage).Name)
+
+		// Missing quotas are not found
+		req, err = http.NewRequest("GET", "/v1/quota/missing", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.EqualError(err, "Quota not found")
+	})
+}
+
+func TestHTTP_QuotaCreate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Make the HTTP request
+		qs1 := mock.QuotaSpec()
+		buf := encodeReq(qs1)
+		req, err := http.NewRequest("PUT", "/v1/quota", buf)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.QuotaCreateRequest(respW, req)
+		require.NoError(err)
+		require.Nil(obj)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check quota was created
+		out, err := s.Agent.server.State().QuotaSpecByName(nil, qs1.Name)
+		require.NoError(err)
+		require.NotNil(out)
+		require.Equal(qs1.Description, out.Description)
+	})
+}
+
+func TestHTTP_QuotaUpdate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		qs1 := mock.QuotaSpec()
+		buf := encodeReq(qs1)
+
+		// The name has to match the path
+		req, err := http.NewRequest("PUT", "/v1/quota/foobar", buf)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.QuotaSpecificRequest(respW, req)
+		require.EqualError(err, "Quota name does not match request path")
+
+		// Make the HTTP request
+		buf = encodeReq(qs1)
+		req, err = http.NewRequest("PUT", "/v1/quota/"+qs1.Name, buf)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Nil(obj)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check quota was created
+		out, err := s.Agent.server.State().QuotaSpecByName(nil, qs1.Name)
+		require.NoError(err)
+		require.NotNil(out)
+	})
+}
+
+func TestHTTP_QuotaDelete(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		qs1 := mock.QuotaSpec()
+		args := structs.QuotaSpecUpsertRequest{
+			Quotas:       []*structs.QuotaSpec{qs1},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		require.NoError(s.Agent.RPC("Quota.UpsertQuotaSpecs", &args, &resp))
+
+		// Make the HTTP request
+		req, err := http.NewRequest("DELETE", "/v1/quota/"+qs1.Name, nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+
+		// Make the request
+		obj, err := s.Server.QuotaSpecificRequest(respW, req)
+		require.NoError(err)
+		require.Nil(obj)
+
+		// Check for the index
+		require.NotZero(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Check quota was deleted
+		out, err := s.Agent.server.State().QuotaSpecByName(nil, qs1.Name)
+		require.NoError(err)
+		require.Nil(out)
+	})
+}
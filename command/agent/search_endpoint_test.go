@@ -333,3 +333,45 @@ func TestHTTP_Search_AllContext(t *testing.T) {
 		assert.Equal("8000", respW.HeaderMap.Get("X-Nomad-Index"))
 	})
 }
+
+func TestHTTP_FuzzySearchWithIllegalMethod(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		req, err := http.NewRequest("GET", "/v1/search/fuzzy", nil)
+		assert.Nil(err)
+		respW := httptest.NewRecorder()
+
+		_, err = s.Server.FuzzySearchRequest(respW, req)
+		assert.NotNil(err, "HTTP GET should not be accepted for this endpoint")
+	})
+}
+
+func TestHTTP_FuzzySearch_POST(t *testing.T) {
+	assert := assert.New(t)
+
+	testJob := "aaaaaaaa-e8f7-fd38-c855-ab94ceb89706"
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		createJobForTest(testJob, s, t)
+
+		data := structs.FuzzySearchRequest{Text: "c855-ab94", Context: structs.All}
+		req, err := http.NewRequest("POST", "/v1/search/fuzzy", encodeReq(data))
+		assert.Nil(err)
+
+		respW := httptest.NewRecorder()
+
+		resp, err := s.Server.FuzzySearchRequest(respW, req)
+		assert.Nil(err)
+
+		res := resp.(structs.FuzzySearchResponse)
+
+		j := res.Matches[structs.Jobs]
+		assert.Equal(1, len(j))
+		assert.Equal([]string{structs.DefaultNamespace, testJob}, j[0].Scope)
+		assert.Empty(res.Matches[structs.Nodes])
+
+		assert.Equal(res.Truncations[structs.Jobs], false)
+		assert.NotEqual("0", respW.HeaderMap.Get("X-Nomad-Index"))
+	})
+}
@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) ServiceRegistrationListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.ServiceRegistrationListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ServiceRegistrationListResponse
+	if err := s.agent.RPC("ServiceRegistration.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Services == nil {
+		out.Services = make([]*structs.ServiceRegistrationListStub, 0)
+	}
+	return out.Services, nil
+}
+
+func (s *HTTPServer) ServiceRegistrationRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/service/")
+	if len(path) == 0 {
+		return nil, CodedError(400, "Missing service name")
+	}
+
+	// The path is either the service name, or the service name followed by
+	// the ID of one of its registrations.
+	parts := strings.SplitN(path, "/", 2)
+	switch {
+	case len(parts) == 1 && req.Method == "GET":
+		return s.serviceRegistrationQuery(resp, req, parts[0])
+	case len(parts) == 2 && parts[1] == "":
+		return nil, CodedError(400, "Missing service registration ID")
+	case len(parts) == 2 && req.Method == "DELETE":
+		return s.serviceRegistrationDelete(resp, req, parts[1])
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) serviceRegistrationQuery(resp http.ResponseWriter, req *http.Request,
+	name string) (interface{}, error) {
+	args := structs.ServiceRegistrationByNameRequest{
+		ServiceName: name,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ServiceRegistrationByNameResponse
+	if err := s.agent.RPC("ServiceRegistration.GetService", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Services == nil {
+		out.Services = make([]*structs.ServiceRegistration, 0)
+	}
+	return out.Services, nil
+}
+
+func (s *HTTPServer) serviceRegistrationDelete(resp http.ResponseWriter, req *http.Request,
+	id string) (interface{}, error) {
+	args := structs.ServiceRegistrationDeleteByIDRequest{
+		ID: id,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("ServiceRegistration.DeleteByID", &args, &out); err != nil {
+		if strings.HasSuffix(err.Error(), "not found") {
+			return nil, CodedError(404, err.Error())
+		}
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
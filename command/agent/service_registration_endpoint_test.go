@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_ServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		services := mock.ServiceRegistrations()
+		state := s.Agent.server.State()
+		require.NoError(state.UpsertServiceRegistrations(1000, services))
+
+		// List the services
+		req, err := http.NewRequest("GET", "/v1/services", nil)
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.ServiceRegistrationListRequest(respW, req)
+		require.NoError(err)
+		require.Equal("1000", respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Len(obj.([]*structs.ServiceRegistrationListStub), 2)
+
+		// Get the instances of a service
+		req, err = http.NewRequest("GET", "/v1/service/"+services[0].ServiceName, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.ServiceRegistrationRequest(respW, req)
+		require.NoError(err)
+		out := obj.([]*structs.ServiceRegistration)
+		require.Len(out, 1)
+		require.Equal(services[0].ID, out[0].ID)
+
+		// Delete it
+		req, err = http.NewRequest("DELETE", "/v1/service/"+services[0].ServiceName+"/"+services[0].ID, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ServiceRegistrationRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+
+		// Unknown services have no instances
+		req, err = http.NewRequest("GET", "/v1/service/"+services[0].ServiceName, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.ServiceRegistrationRequest(respW, req)
+		require.NoError(err)
+		require.Empty(obj.([]*structs.ServiceRegistration))
+
+		// Deleting it again fails
+		req, err = http.NewRequest("DELETE", "/v1/service/"+services[0].ServiceName+"/"+services[0].ID, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ServiceRegistrationRequest(respW, req)
+		require.Error(err)
+		require.Equal(404, err.(HTTPCodedError).Code())
+
+		// Invalid methods are rejected
+		req, err = http.NewRequest("PUT", "/v1/service/"+services[0].ServiceName, nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.ServiceRegistrationRequest(respW, req)
+		require.Error(err)
+		require.Equal(405, err.(HTTPCodedError).Code())
+	})
+}
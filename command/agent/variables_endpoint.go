@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) VariablesListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != "GET" {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.VariablesListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariablesListResponse
+	if err := s.agent.RPC("Variables.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Data == nil {
+		out.Data = make([]*structs.VariableMetadata, 0)
+	}
+	return out.Data, nil
+}
+
+func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/var/")
+	if len(path) == 0 {
+		return nil, CodedError(400, "Missing variable path")
+	}
+	switch req.Method {
+	case "GET":
+		return s.variableQuery(resp, req, path)
+	case "PUT", "POST":
+		return s.variableUpdate(resp, req, path)
+	case "DELETE":
+		return s.variableDelete(resp, req, path)
+	default:
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	args := structs.VariablesReadRequest{
+		Path: path,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariablesReadResponse
+	if err := s.agent.RPC("Variables.Read", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Data == nil {
+		return nil, CodedError(404, "variable not found")
+	}
+	return out.Data, nil
+}
+
+func (s *HTTPServer) variableUpdate(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	// Parse the variable
+	var sv structs.VariableDecrypted
+	if err := decodeBody(req, &sv); err != nil {
+		return nil, CodedError(400, err.Error())
+	}
+
+	// The path of the request takes precedence
+	sv.Path = path
+
+	args := structs.VariablesUpsertRequest{
+		Var: &sv,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.VariablesUpsertResponse
+	if err := s.agent.RPC("Variables.Upsert", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out.Output, nil
+}
+
+func (s *HTTPServer) variableDelete(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+
+	args := structs.VariablesDeleteRequest{
+		Path: path,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Variables.Delete", &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return nil, nil
+}
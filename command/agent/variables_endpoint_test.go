@@ -0,0 +1,62 @@
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_VariablesCRUD(t *testing.T) {
+	t.Parallel()
+	httpTest(t, nil, func(s *TestAgent) {
+		require := require.New(t)
+
+		// Create a variable
+		sv := &structs.VariableDecrypted{
+			Items: map[string]string{"password": "hunter2"},
+		}
+		req, err := http.NewRequest("PUT", "/v1/var/app/web", encodeReq(sv))
+		require.NoError(err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+		require.NotEmpty(respW.HeaderMap.Get("X-Nomad-Index"))
+		require.Equal("app/web", obj.(*structs.VariableMetadata).Path)
+
+		// Read it back
+		req, err = http.NewRequest("GET", "/v1/var/app/web", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+		out := obj.(*structs.VariableDecrypted)
+		require.Equal(structs.DefaultNamespace, out.Namespace)
+		require.Equal(sv.Items, out.Items)
+
+		// List it
+		req, err = http.NewRequest("GET", "/v1/vars?prefix=app", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.VariablesListRequest(respW, req)
+		require.NoError(err)
+		list := obj.([]*structs.VariableMetadata)
+		require.Len(list, 1)
+		require.Equal("app/web", list[0].Path)
+
+		// Delete it
+		req, err = http.NewRequest("DELETE", "/v1/var/app/web", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.NoError(err)
+
+		req, err = http.NewRequest("GET", "/v1/var/app/web", nil)
+		require.NoError(err)
+		respW = httptest.NewRecorder()
+		_, err = s.Server.VariableSpecificRequest(respW, req)
+		require.EqualError(err, "variable not found")
+	})
+}
@@ -15,7 +15,8 @@ func (f *AllocCommand) Help() string {
 Usage: nomad alloc <subcommand> [options] [args]
 
   This command groups subcommands for interacting with allocations. Users can
-  inspect the status, examine the filesystem or logs of an allocation.
+  inspect the status, examine the filesystem or logs of an allocation, run
+  commands inside it, or restart and stop it.
 
   Examine an allocations status:
 
@@ -25,6 +26,22 @@ Usage: nomad alloc <subcommand> [options] [args]
 
       $ nomad alloc logs -f <alloc-id> <task>
 
+  Reload the configuration of a task:
+
+      $ nomad alloc signal -s SIGHUP <alloc-id> <task>
+
+  Restart a single task of an allocation:
+
+      $ nomad alloc restart <alloc-id> <task>
+
+  Stop an allocation and reschedule it:
+
+      $ nomad alloc stop <alloc-id>
+
+  Run a command in a running task:
+
+      $ nomad alloc exec -task <task> <alloc-id> /bin/bash
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -0,0 +1,295 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/docker/docker/pkg/term"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocExecCommand struct {
+	Meta
+
+	// Stdin, Stdout and Stderr override the streams of the command for
+	// testing
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+func (l *AllocExecCommand) Help() string {
+	helpText := `
+Usage: nomad alloc exec [options] <allocation> <command> [<args>...]
+
+  Run a command in a running allocation. The command's input and output are
+  streamed to the terminal and the command's exit code is returned.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Exec Specific Options:
+
+  -task <task-name>
+    Sets the task to exec the command in. May be omitted if the allocation
+    runs a single task.
+
+  -job
+    Use a random allocation from the specified job ID.
+
+  -i
+    Pass stdin to the command. Defaults to true.
+
+  -t
+    Allocate a pseudo-terminal for the command. Defaults to true if stdin and
+    stdout are both terminals.
+  `
+	return strings.TrimSpace(helpText)
+}
+
+func (l *AllocExecCommand) Synopsis() string {
+	return "Execute commands in a task"
+}
+
+func (l *AllocExecCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(l.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-task": complete.PredictAnything,
+			"-job":  complete.PredictNothing,
+			"-i":    complete.PredictNothing,
+			"-t":    complete.PredictNothing,
+		})
+}
+
+func (l *AllocExecCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := l.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Allocs]
+	})
+}
+
+func (l *AllocExecCommand) Name() string { return "alloc exec" }
+
+func (l *AllocExecCommand) Run(args []string) int {
+	var job, stdinOpt, ttyOpt bool
+	var task string
+
+	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
+	flags.Usage = func() { l.Ui.Output(l.Help()) }
+	flags.BoolVar(&job, "job", false, "")
+	flags.BoolVar(&stdinOpt, "i", true, "")
+	flags.BoolVar(&ttyOpt, "t", l.isTTY(), "")
+	flags.StringVar(&task, "task", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if numArgs := len(args); numArgs < 1 {
+		if job {
+			l.Ui.Error("A job ID is required")
+		} else {
+			l.Ui.Error("An allocation ID is required")
+		}
+
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	} else if numArgs < 2 {
+		l.Ui.Error("A command is required")
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	}
+
+	if ttyOpt && !stdinOpt {
+		l.Ui.Error("-i must be enabled if running with tty")
+		return 1
+	}
+
+	client, err := l.Meta.Client()
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	// If -job is specified, use random allocation, otherwise use provided allocation
+	allocID := args[0]
+	if job {
+		allocID, err = getRandomJobAlloc(client, args[0])
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Error fetching allocations: %v", err))
+			return 1
+		}
+	}
+
+	if len(allocID) == 1 {
+		l.Ui.Error(fmt.Sprintf("Alloc ID must contain at least two characters."))
+		return 1
+	}
+
+	allocID = sanitizeUUIDPrefix(allocID)
+	allocs, _, err := client.Allocations().PrefixList(allocID)
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return 1
+	}
+	if len(allocs) == 0 {
+		l.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+		return 1
+	}
+	if len(allocs) > 1 {
+		// Format the allocs
+		out := formatAllocListStubs(allocs, false, shortId)
+		l.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return 1
+	}
+	// Prefix lookup matched a single allocation
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, nil)
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	// Find the tasks of the allocation's task group
+	var tasks []*api.Task
+	for _, tg := range alloc.Job.TaskGroups {
+		if *tg.Name == alloc.TaskGroup {
+			tasks = tg.Tasks
+			break
+		}
+	}
+
+	if task == "" {
+		// Try to determine the tasks name from the allocation
+		if len(tasks) == 1 {
+			task = tasks[0].Name
+		} else {
+			l.Ui.Error(fmt.Sprintf("Allocation %q is running the following tasks:", limit(alloc.ID, shortId)))
+			for _, t := range tasks {
+				l.Ui.Error(fmt.Sprintf("  * %s", t.Name))
+			}
+			l.Ui.Error("\nPlease specify the task.")
+			return 1
+		}
+	} else {
+		found := false
+		for _, t := range tasks {
+			if t.Name == task {
+				found = true
+				break
+			}
+		}
+		if !found {
+			l.Ui.Error(fmt.Sprintf("Could not find task named %q in allocation %q", task, limit(alloc.ID, shortId)))
+			return 1
+		}
+	}
+
+	var stdin io.Reader
+	if stdinOpt {
+		stdin = l.stdin()
+	}
+
+	code, err := l.execImpl(client, alloc, task, ttyOpt, args[1:], stdin, l.stdout(), l.stderr())
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("failed to exec into task: %v", err))
+		return 1
+	}
+
+	return code
+}
+
+// execImpl runs the command in the task, putting the terminal in raw mode
+// and forwarding its size changes if a tty is allocated.
+func (l *AllocExecCommand) execImpl(client *api.Client, alloc *api.Allocation, task string, tty bool,
+	command []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var sizeCh chan api.TerminalSize
+	if tty {
+		inFd, isTerm := term.GetFdInfo(stdin)
+		if isTerm {
+			state, err := term.SetRawTerminal(inFd)
+			if err != nil {
+				return -1, err
+			}
+			defer term.RestoreTerminal(inFd, state)
+		}
+
+		outFd, isTerm := term.GetFdInfo(stdout)
+		if isTerm {
+			state, err := term.SetRawTerminalOutput(outFd)
+			if err != nil {
+				return -1, err
+			}
+			defer term.RestoreTerminal(outFd, state)
+
+			sizeCh = make(chan api.TerminalSize, 1)
+			stop := watchTerminalSize(outFd, sizeCh)
+			defer stop()
+		}
+	} else {
+		// Without a terminal, interrupts stop the command rather than being
+		// passed to it
+		signalCh := make(chan os.Signal, 1)
+		signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signalCh)
+
+		go func() {
+			select {
+			case <-signalCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	return client.Allocations().Exec(ctx, alloc, task, tty, command,
+		stdin, stdout, stderr, sizeCh, nil)
+}
+
+// isTTY returns whether both stdin and stdout are terminals.
+func (l *AllocExecCommand) isTTY() bool {
+	_, isStdinTerminal := term.GetFdInfo(l.stdin())
+	_, isStdoutTerminal := term.GetFdInfo(l.stdout())
+	return isStdinTerminal && isStdoutTerminal
+}
+
+func (l *AllocExecCommand) stdin() io.Reader {
+	if l.Stdin != nil {
+		return l.Stdin
+	}
+	return os.Stdin
+}
+
+func (l *AllocExecCommand) stdout() io.Writer {
+	if l.Stdout != nil {
+		return l.Stdout
+	}
+	return os.Stdout
+}
+
+func (l *AllocExecCommand) stderr() io.Writer {
+	if l.Stderr != nil {
+		return l.Stderr
+	}
+	return os.Stderr
+}
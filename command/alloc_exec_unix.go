@@ -0,0 +1,50 @@
+// +build !windows
+
+package command
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/docker/docker/pkg/term"
+	"github.com/hashicorp/nomad/api"
+)
+
+// watchTerminalSize sends the size of the terminal and then its size each time
+// the terminal is resized. The returned function stops watching.
+func watchTerminalSize(fd uintptr, sizeCh chan<- api.TerminalSize) func() {
+	sendSize := func() {
+		ws, err := term.GetWinsize(fd)
+		if err != nil {
+			return
+		}
+
+		select {
+		case sizeCh <- api.TerminalSize{Height: int(ws.Height), Width: int(ws.Width)}:
+		default:
+		}
+	}
+
+	sendSize()
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, syscall.SIGWINCH)
+	doneCh := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-signalCh:
+				sendSize()
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signalCh)
+		close(doneCh)
+	}
+}
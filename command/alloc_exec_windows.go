@@ -0,0 +1,17 @@
+// +build windows
+
+package command
+
+import (
+	"github.com/docker/docker/pkg/term"
+	"github.com/hashicorp/nomad/api"
+)
+
+// watchTerminalSize sends the size of the terminal. Resizes are not detected
+// on Windows. The returned function is a no-op.
+func watchTerminalSize(fd uintptr, sizeCh chan<- api.TerminalSize) func() {
+	if ws, err := term.GetWinsize(fd); err == nil {
+		sizeCh <- api.TerminalSize{Height: int(ws.Height), Width: int(ws.Width)}
+	}
+	return func() {}
+}
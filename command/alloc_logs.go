@@ -176,6 +176,15 @@ func (l *AllocLogsCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Find the tasks of the allocation's task group
+	var tasks []*api.Task
+	for _, tg := range alloc.Job.TaskGroups {
+		if *tg.Name == alloc.TaskGroup {
+			tasks = tg.Tasks
+			break
+		}
+	}
+
 	var task string
 	if len(args) >= 2 {
 		task = args[1]
@@ -184,20 +193,26 @@ func (l *AllocLogsCommand) Run(args []string) int {
 			return 1
 		}
 
-	} else {
-		// Try to determine the tasks name from the allocation
-		var tasks []*api.Task
-		for _, tg := range alloc.Job.TaskGroups {
-			if *tg.Name == alloc.TaskGroup {
-				if len(tg.Tasks) == 1 {
-					task = tg.Tasks[0].Name
-					break
-				}
-
-				tasks = tg.Tasks
+		// Ensure the task exists so a typo doesn't wait on a missing log
+		found := false
+		for _, t := range tasks {
+			if t.Name == task {
+				found = true
 				break
 			}
 		}
+		if !found {
+			l.Ui.Error(fmt.Sprintf("Could not find task named %q in allocation %q. It is running the following tasks:", task, limit(alloc.ID, length)))
+			for _, t := range tasks {
+				l.Ui.Error(fmt.Sprintf("  * %s", t.Name))
+			}
+			return 1
+		}
+	} else {
+		// Try to determine the tasks name from the allocation
+		if len(tasks) == 1 {
+			task = tasks[0].Name
+		}
 
 		if task == "" {
 			l.Ui.Error(fmt.Sprintf("Allocation %q is running the following tasks:", limit(alloc.ID, length)))
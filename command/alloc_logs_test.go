@@ -92,3 +92,31 @@ func TestLogsCommand_AutocompleteArgs(t *testing.T) {
 	assert.Equal(1, len(res))
 	assert.Equal(a.ID, res[0])
 }
+
+func TestLogsCommand_UnknownTask(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &AllocLogsCommand{Meta: Meta{Ui: ui}}
+
+	// Create a fake alloc
+	state := srv.Agent.Server().State()
+	a := mock.Alloc()
+	if err := state.UpsertAllocs(1000, []*structs.Allocation{a}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Fails on a task that isn't part of the allocation
+	if code := cmd.Run([]string{"-address=" + url, a.ID, "bogus"}); code != 1 {
+		t.Fatalf("expected exit 1, got: %d", code)
+	}
+	out := ui.ErrorWriter.String()
+	if !strings.Contains(out, `Could not find task named "bogus"`) {
+		t.Fatalf("expected unknown task error, got: %s", out)
+	}
+	if !strings.Contains(out, "* web") {
+		t.Fatalf("expected task list, got: %s", out)
+	}
+}
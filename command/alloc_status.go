@@ -279,6 +279,18 @@ func formatAllocBasicInfo(alloc *api.Allocation, client *api.Client, uuidLength
 		basic = append(basic,
 			fmt.Sprintf("Replacement Alloc ID|%s", limit(alloc.NextAllocation, uuidLength)))
 	}
+	if alloc.PreemptedByAllocation != "" {
+		basic = append(basic,
+			fmt.Sprintf("Preempted By Alloc ID|%s", limit(alloc.PreemptedByAllocation, uuidLength)))
+	}
+	if len(alloc.PreemptedAllocations) > 0 {
+		preempted := make([]string, 0, len(alloc.PreemptedAllocations))
+		for _, id := range alloc.PreemptedAllocations {
+			preempted = append(preempted, limit(id, uuidLength))
+		}
+		basic = append(basic,
+			fmt.Sprintf("Preempted Alloc IDs|%s", strings.Join(preempted, ",")))
+	}
 	if alloc.FollowupEvalID != "" {
 		nextEvalTime := futureEvalTimePretty(alloc.FollowupEvalID, client)
 		if nextEvalTime != "" {
@@ -465,6 +477,8 @@ func buildDisplayMessage(event *api.TaskEvent) string {
 		desc = event.DriverMessage
 	case api.TaskLeaderDead:
 		desc = "Leader Task in Group dead"
+	case api.TaskMainDead:
+		desc = "Main tasks in the group died"
 	default:
 		desc = event.Message
 	}
@@ -485,7 +499,11 @@ func (c *AllocStatusCommand) outputTaskResources(alloc *api.Allocation, task str
 	for _, nw := range resource.Networks {
 		ports := append(nw.DynamicPorts, nw.ReservedPorts...)
 		for _, port := range ports {
-			addr = append(addr, fmt.Sprintf("%v: %v:%v\n", port.Label, nw.IP, port.Value))
+			ip := nw.IP
+			if port.HostIP != "" {
+				ip = port.HostIP
+			}
+			addr = append(addr, fmt.Sprintf("%v: %v:%v\n", port.Label, ip, port.Value))
 		}
 	}
 	var resourcesOutput []string
@@ -195,13 +195,16 @@ func formatDeployment(d *api.Deployment, uuidLength int) string {
 
 func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	// Detect if we need to add these columns
-	var canaries, autorevert, progressDeadline bool
+	var canaries, autorevert, autopromote, progressDeadline bool
 	tgNames := make([]string, 0, len(d.TaskGroups))
 	for name, state := range d.TaskGroups {
 		tgNames = append(tgNames, name)
 		if state.AutoRevert {
 			autorevert = true
 		}
+		if state.AutoPromote {
+			autopromote = true
+		}
 		if state.DesiredCanaries > 0 {
 			canaries = true
 		}
@@ -218,6 +221,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 	if autorevert {
 		rowString += "Auto Revert|"
 	}
+	if autopromote {
+		rowString += "Auto Promote|"
+	}
 	if canaries {
 		rowString += "Promoted|"
 	}
@@ -239,6 +245,9 @@ func formatDeploymentGroups(d *api.Deployment, uuidLength int) string {
 		if autorevert {
 			row += fmt.Sprintf("%v|", state.AutoRevert)
 		}
+		if autopromote {
+			row += fmt.Sprintf("%v|", state.AutoPromote)
+		}
 		if canaries {
 			if state.DesiredCanaries > 0 {
 				row += fmt.Sprintf("%v|", state.Promoted)
@@ -19,6 +19,10 @@ Usage: nomad eval <subcommand> [options] [args]
   detail but can be useful for debugging placement failures when the cluster
   does not have the resources to run a given job.
 
+  List the evaluations of a job:
+
+      $ nomad eval list -job <job-id>
+
   Examine an evaluations status:
 
       $ nomad eval status <eval-id>
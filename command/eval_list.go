@@ -0,0 +1,167 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type EvalListCommand struct {
+	Meta
+}
+
+func (c *EvalListCommand) Help() string {
+	helpText := `
+Usage: nomad eval list [options]
+
+  List is used to list the set of evaluations tracked by Nomad, optionally
+  only those of a job or with a status.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+List Options:
+
+  -job <job id>
+    Only show the evaluations of the job.
+
+  -status <status>
+    Only show the evaluations with the status, one of "blocked", "pending",
+    "complete", "failed", or "canceled".
+
+  -json
+    Output the evaluations in a JSON format.
+
+  -t
+    Format and display the evaluations using a Go template.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job": complete.PredictFunc(func(a complete.Args) []string {
+				client, err := c.Meta.Client()
+				if err != nil {
+					return nil
+				}
+				resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+				if err != nil {
+					return []string{}
+				}
+				return resp.Matches[contexts.Jobs]
+			}),
+			"-status":  complete.PredictSet("blocked", "pending", "complete", "failed", "canceled"),
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *EvalListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *EvalListCommand) Synopsis() string {
+	return "List the evaluations"
+}
+
+func (c *EvalListCommand) Name() string { return "eval list" }
+
+func (c *EvalListCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl, jobID, status string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.StringVar(&jobID, "job", "", "")
+	flags.StringVar(&status, "status", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	q := &api.QueryOptions{
+		Params: map[string]string{},
+	}
+	if jobID != "" {
+		q.Params["job"] = jobID
+	}
+	if status != "" {
+		q.Params["status"] = status
+	}
+
+	evals, _, err := client.Evaluations().List(q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving evaluations: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, evals)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatEvalList(evals, length))
+	return 0
+}
+
+func formatEvalList(evals []*api.Evaluation, uuidLength int) string {
+	if len(evals) == 0 {
+		return "No evaluations found"
+	}
+
+	rows := make([]string, len(evals)+1)
+	rows[0] = "ID|Priority|Triggered By|Job ID|Node ID|Status|Placement Failures"
+	for i, eval := range evals {
+		failures, _ := evalFailureStatus(eval)
+		rows[i+1] = fmt.Sprintf("%s|%d|%s|%s|%s|%s|%s",
+			limit(eval.ID, uuidLength),
+			eval.Priority,
+			eval.TriggeredBy,
+			eval.JobID,
+			limit(eval.NodeID, uuidLength),
+			eval.Status,
+			failures,
+		)
+	}
+	return formatList(rows)
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvalListCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &EvalListCommand{}
+}
+
+func TestEvalListCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &EvalListCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error retrieving evaluations") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestEvalListCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &EvalListCommand{Meta: Meta{Ui: ui}}
+
+	// Create fake evals
+	state := srv.Agent.Server().State()
+	eval1 := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.Status = structs.EvalStatusBlocked
+	require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{eval1, eval2}))
+
+	// All the evaluations are listed
+	require.Equal(0, cmd.Run([]string{"-address=" + url}))
+	out := ui.OutputWriter.String()
+	require.Contains(out, limit(eval1.ID, shortId))
+	require.Contains(out, limit(eval2.ID, shortId))
+	ui.OutputWriter.Reset()
+
+	// Filter on the job
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-job", eval1.JobID}))
+	out = ui.OutputWriter.String()
+	require.Contains(out, limit(eval1.ID, shortId))
+	require.NotContains(out, limit(eval2.ID, shortId))
+	ui.OutputWriter.Reset()
+
+	// Filter on the status
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-status", "blocked"}))
+	out = ui.OutputWriter.String()
+	require.NotContains(out, limit(eval1.ID, shortId))
+	require.Contains(out, limit(eval2.ID, shortId))
+	ui.OutputWriter.Reset()
+
+	// No match
+	require.Equal(0, cmd.Run([]string{"-address=" + url, "-status", "failed"}))
+	require.Contains(ui.OutputWriter.String(), "No evaluations found")
+}
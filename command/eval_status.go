@@ -177,8 +177,12 @@ func (c *EvalStatusCommand) Run(args []string) int {
 		return mon.monitor(evals[0].ID, true)
 	}
 
-	// Prefix lookup matched a single evaluation
-	eval, _, err := client.Evaluations().Info(evals[0].ID, nil)
+	// Prefix lookup matched a single evaluation, read it with its related
+	// evaluations
+	q := &api.QueryOptions{
+		Params: map[string]string{"related": "true"},
+	}
+	eval, _, err := client.Evaluations().Info(evals[0].ID, q)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error querying evaluation: %s", err))
 		return 1
@@ -234,6 +238,22 @@ func (c *EvalStatusCommand) Run(args []string) int {
 	}
 	c.Ui.Output(formatKV(basic))
 
+	if len(eval.RelatedEvals) != 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Related Evaluations[reset]"))
+		c.Ui.Output(formatRelatedEvalStubs(eval.RelatedEvals, length))
+	}
+
+	// Show the allocations placed by the evaluation
+	allocs, _, err := client.Evaluations().Allocations(eval.ID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying evaluation allocations: %s", err))
+		return 1
+	}
+	if len(allocs) != 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Placed Allocations[reset]"))
+		c.Ui.Output(formatAllocListStubs(allocs, verbose, length))
+	}
+
 	if failures {
 		c.Ui.Output(c.Colorize().Color("\n[bold]Failed Placements[reset]"))
 		sorted := sortedTaskGroupFromMetrics(eval.FailedTGAllocs)
@@ -258,6 +278,23 @@ func (c *EvalStatusCommand) Run(args []string) int {
 	return 0
 }
 
+// formatRelatedEvalStubs formats the evaluations related to another one
+func formatRelatedEvalStubs(evals []*api.EvaluationStub, uuidLength int) string {
+	rows := make([]string, len(evals)+1)
+	rows[0] = "ID|Priority|Triggered By|Node ID|Status|Description"
+	for i, eval := range evals {
+		rows[i+1] = fmt.Sprintf("%s|%d|%s|%s|%s|%s",
+			limit(eval.ID, uuidLength),
+			eval.Priority,
+			eval.TriggeredBy,
+			limit(eval.NodeID, uuidLength),
+			eval.Status,
+			eval.StatusDescription,
+		)
+	}
+	return formatList(rows)
+}
+
 func sortedTaskGroupFromMetrics(groups map[string]*api.AllocationMetric) []string {
 	tgs := make([]string, 0, len(groups))
 	for tg := range groups {
@@ -269,11 +306,11 @@ func sortedTaskGroupFromMetrics(groups map[string]*api.AllocationMetric) []strin
 
 func getTriggerDetails(eval *api.Evaluation) (noun, subject string) {
 	switch eval.TriggeredBy {
-	case "job-register", "job-deregister", "periodic-job", "rolling-update", "deployment-watcher":
+	case "job-register", "job-deregister", "periodic-job", "rolling-update", "deployment-watcher", "alloc-stop", "preemption":
 		return "Job ID", eval.JobID
-	case "node-update":
+	case "node-update", "node-drain":
 		return "Node ID", eval.NodeID
-	case "max-plan-attempts":
+	case "max-plan-attempts", "alloc-failure", "failed-follow-up":
 		return "Previous Eval", eval.PreviousEval
 	default:
 		return "", ""
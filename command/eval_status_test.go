@@ -9,6 +9,7 @@ import (
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEvalStatusCommand_Implements(t *testing.T) {
@@ -84,3 +85,41 @@ func TestEvalStatusCommand_AutocompleteArgs(t *testing.T) {
 	assert.Equal(1, len(res))
 	assert.Equal(e.ID, res[0])
 }
+
+func TestEvalStatusCommand_Related(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &EvalStatusCommand{Meta: Meta{Ui: ui}}
+
+	// Create an eval with a follow-up eval and an allocation
+	state := srv.Agent.Server().State()
+	eval := mock.Eval()
+	followUp := mock.Eval()
+	followUp.TriggeredBy = structs.EvalTriggerRetryFailedAlloc
+	followUp.PreviousEval = eval.ID
+	eval.NextEval = followUp.ID
+	require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{eval, followUp}))
+
+	alloc := mock.Alloc()
+	alloc.EvalID = eval.ID
+	require.NoError(state.UpsertJobSummary(1001, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc}))
+
+	require.Equal(0, cmd.Run([]string{"-address=" + url, eval.ID}))
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Related Evaluations")
+	require.Contains(out, limit(followUp.ID, shortId))
+	require.Contains(out, "Placed Allocations")
+	require.Contains(out, limit(alloc.ID, shortId))
+	ui.OutputWriter.Reset()
+
+	// The follow-up eval shows the eval that triggered it
+	require.Equal(0, cmd.Run([]string{"-address=" + url, followUp.ID}))
+	out = ui.OutputWriter.String()
+	require.Contains(out, "Previous Eval")
+	require.Contains(out, eval.ID)
+}
@@ -0,0 +1,211 @@
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/jobspec2"
+	"github.com/posener/complete"
+)
+
+// formatExtensions are the extensions of the files formatted in a directory
+var formatExtensions = []string{".nomad", ".hcl"}
+
+type FormatCommand struct {
+	Meta
+
+	// The fields below can be overwritten for tests
+	testStdin io.Reader
+}
+
+func (c *FormatCommand) Help() string {
+	helpText := `
+Usage: nomad fmt [options] [path ...]
+
+  Rewrites the HCL2 job files, and variable files, into the canonical format
+  and style.
+
+  The paths can be files or directories, and default to the current
+  directory. The files in a directory with a .nomad or .hcl extension are
+  formatted. If the path is "-", the file is read from stdin and the
+  formatted file is written to stdout.
+
+  The names of the formatted files are listed.
+
+Format Options:
+
+  -check
+    Check that the files are formatted without rewriting them. The exit code
+    is 1 if any file isn't formatted, and the names of these files are listed.
+
+  -recursive
+    Also format the files in the subdirectories of the directories.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *FormatCommand) Synopsis() string {
+	return "Rewrite job files to the canonical format"
+}
+
+func (c *FormatCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-check":     complete.PredictNothing,
+		"-recursive": complete.PredictNothing,
+	}
+}
+
+func (c *FormatCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(complete.PredictDirs("*"), complete.PredictFiles("*.nomad"), complete.PredictFiles("*.hcl"))
+}
+
+func (c *FormatCommand) Name() string { return "fmt" }
+
+func (c *FormatCommand) Run(args []string) int {
+	var check, recursive bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&check, "check", false, "")
+	flags.BoolVar(&recursive, "recursive", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	if len(paths) == 1 && paths[0] == "-" {
+		return c.formatStdin(check)
+	}
+
+	// Collect the files to format
+	var files []string
+	for _, path := range paths {
+		found, err := formatFiles(path, recursive)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error listing files to format: %s", err))
+			return 1
+		}
+		files = append(files, found...)
+	}
+
+	failed, unformatted := false, false
+	for _, file := range files {
+		changed, err := c.formatFile(file, check)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error formatting %s: %s", file, err))
+			failed = true
+			continue
+		}
+		if changed {
+			c.Ui.Output(file)
+			unformatted = true
+		}
+	}
+
+	if failed || (check && unformatted) {
+		return 1
+	}
+	return 0
+}
+
+// formatStdin formats the file read from stdin and writes it to stdout
+func (c *FormatCommand) formatStdin(check bool) int {
+	var stdin io.Reader = os.Stdin
+	if c.testStdin != nil {
+		stdin = c.testStdin
+	}
+
+	src, err := ioutil.ReadAll(stdin)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading from stdin: %s", err))
+		return 1
+	}
+
+	out, diags := jobspec2.Format(src, "<stdin>")
+	if diags.HasErrors() {
+		c.Ui.Error(fmt.Sprintf("Error formatting <stdin>: %s", diags))
+		return 1
+	}
+
+	if check {
+		if !bytes.Equal(src, out) {
+			return 1
+		}
+		return 0
+	}
+
+	c.Ui.Output(strings.TrimSuffix(string(out), "\n"))
+	return 0
+}
+
+// formatFile formats the file, unless check is set, and returns whether it
+// wasn't formatted
+func (c *FormatCommand) formatFile(path string, check bool) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	out, diags := jobspec2.Format(src, path)
+	if diags.HasErrors() {
+		return false, diags
+	}
+	if bytes.Equal(src, out) {
+		return false, nil
+	}
+
+	if !check {
+		if err := ioutil.WriteFile(path, out, info.Mode()); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// formatFiles returns the files to format for the path. A file is formatted
+// whatever its extension, and the files of a directory are formatted if
+// their extension is one of formatExtensions.
+func formatFiles(path string, recursive bool) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p != path && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, ext := range formatExtensions {
+			if filepath.Ext(p) == ext {
+				files = append(files, p)
+				break
+			}
+		}
+		return nil
+	})
+	return files, err
+}
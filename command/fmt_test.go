@@ -0,0 +1,123 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	unformattedJob = `job "example" {
+datacenters = ["dc1"]
+  type = "service"
+}
+`
+	formattedJob = `job "example" {
+  datacenters = ["dc1"]
+  type        = "service"
+}
+`
+)
+
+func TestFormatCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &FormatCommand{}
+}
+
+func TestFormatCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &FormatCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on a missing file
+	if code := cmd.Run([]string{"/unicorns/leprechauns"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error listing files") {
+		t.Fatalf("expected listing error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Fails on an invalid file
+	dir, err := ioutil.TempDir("", "nomad-fmt")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "invalid.nomad")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`job "example" {`), 0600))
+	if code := cmd.Run([]string{path}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error formatting") {
+		t.Fatalf("expected formatting error, got: %s", out)
+	}
+}
+
+func TestFormatCommand_Directory(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-fmt")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	sub := filepath.Join(dir, "sub")
+	require.NoError(os.Mkdir(sub, 0700))
+
+	top := filepath.Join(dir, "top.nomad")
+	nested := filepath.Join(sub, "nested.hcl")
+	other := filepath.Join(dir, "other.txt")
+	for _, path := range []string{top, nested, other} {
+		require.NoError(ioutil.WriteFile(path, []byte(unformattedJob), 0600))
+	}
+
+	// Checking lists the unformatted files without rewriting them
+	ui := new(cli.MockUi)
+	cmd := &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, cmd.Run([]string{"-check", dir}))
+	require.Equal(top+"\n", ui.OutputWriter.String())
+
+	content, err := ioutil.ReadFile(top)
+	require.NoError(err)
+	require.Equal(unformattedJob, string(content))
+
+	// Formatting recursively rewrites the job files of the subdirectories
+	ui = new(cli.MockUi)
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-recursive", dir}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), top)
+	require.Contains(ui.OutputWriter.String(), nested)
+
+	for _, path := range []string{top, nested} {
+		content, err := ioutil.ReadFile(path)
+		require.NoError(err)
+		require.Equal(formattedJob, string(content))
+	}
+	content, err = ioutil.ReadFile(other)
+	require.NoError(err)
+	require.Equal(unformattedJob, string(content))
+
+	// The formatted files pass the check
+	ui = cli.NewMockUi()
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-check", "-recursive", dir}))
+	require.Empty(ui.OutputWriter.String())
+}
+
+func TestFormatCommand_Stdin(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ui := new(cli.MockUi)
+	cmd := &FormatCommand{Meta: Meta{Ui: ui}, testStdin: strings.NewReader(unformattedJob)}
+	require.Equal(0, cmd.Run([]string{"-"}), ui.ErrorWriter.String())
+	require.Equal(formattedJob, ui.OutputWriter.String())
+
+	cmd = &FormatCommand{Meta: Meta{Ui: ui}, testStdin: strings.NewReader(unformattedJob)}
+	require.Equal(1, cmd.Run([]string{"-check", "-"}))
+}
@@ -2,6 +2,8 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,7 +14,9 @@ import (
 
 	gg "github.com/hashicorp/go-getter"
 	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flag-helpers"
 	"github.com/hashicorp/nomad/jobspec"
+	"github.com/hashicorp/nomad/jobspec2"
 	"github.com/kr/text"
 	"github.com/posener/complete"
 
@@ -376,12 +380,37 @@ READ:
 }
 
 type JobGetter struct {
+	// hcl1 forces the job file to be parsed with the HCL1 parser
+	hcl1 bool
+
+	// json parses the job file as the canonical JSON format of the API
+	json bool
+
+	// vars and varFiles assign the input variables of an HCL2 job file
+	vars     flaghelper.StringFlag
+	varFiles flaghelper.StringFlag
+
 	// The fields below can be overwritten for tests
 	testStdin io.Reader
 }
 
+// registerFlags registers the flags used to parse the job file
+func (j *JobGetter) registerFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&j.hcl1, "hcl1", false, "")
+	flags.BoolVar(&j.json, "json", false, "")
+	flags.Var(&j.vars, "var", "")
+	flags.Var(&j.varFiles, "var-file", "")
+}
+
 // StructJob returns the Job struct from jobfile.
 func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
+	job, _, err := j.ApiJobWithSource(jpath)
+	return job, err
+}
+
+// ApiJobWithSource returns the Job struct from jobfile, along with the source
+// it was parsed from.
+func (j *JobGetter) ApiJobWithSource(jpath string) (*api.Job, *api.JobSubmission, error) {
 	var jobfile io.Reader
 	switch jpath {
 	case "-":
@@ -392,23 +421,23 @@ func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
 		}
 	default:
 		if len(jpath) == 0 {
-			return nil, fmt.Errorf("Error jobfile path has to be specified.")
+			return nil, nil, fmt.Errorf("Error jobfile path has to be specified.")
 		}
 
 		job, err := ioutil.TempFile("", "jobfile")
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		defer os.Remove(job.Name())
 
 		if err := job.Close(); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		// Get the pwd
 		pwd, err := os.Getwd()
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		client := &gg.Client{
@@ -418,24 +447,101 @@ func (j *JobGetter) ApiJob(jpath string) (*api.Job, error) {
 		}
 
 		if err := client.Get(); err != nil {
-			return nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
+			return nil, nil, fmt.Errorf("Error getting jobfile from %q: %v", jpath, err)
 		} else {
 			file, err := os.Open(job.Name())
 			defer file.Close()
 			if err != nil {
-				return nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
+				return nil, nil, fmt.Errorf("Error opening file %q: %v", jpath, err)
 			}
 			jobfile = file
 		}
 	}
 
-	// Parse the JobFile
-	jobStruct, err := jobspec.Parse(jobfile)
+	// Read the JobFile, keeping its source
+	source, err := ioutil.ReadAll(jobfile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error reading job file from %s: %v", jpath, err)
+	}
+
+	if j.hcl1 && j.json {
+		return nil, nil, fmt.Errorf("The -hcl1 and -json flags can't be used together")
+	}
+	isJSON := j.json || bytes.HasPrefix(bytes.TrimSpace(source), []byte("{"))
+	if (j.hcl1 || isJSON) && (len(j.vars) != 0 || len(j.varFiles) != 0) {
+		return nil, nil, fmt.Errorf("Variables can only be set for HCL2 job files")
+	}
+
+	// Parse the JobFile. JSON job files are parsed by the HCL1 parser unless
+	// they are in the canonical JSON format.
+	submission := &api.JobSubmission{
+		Source: string(source),
+	}
+	var jobStruct *api.Job
+	switch {
+	case j.json:
+		submission.Format = "json"
+		jobStruct, err = parseJSONJob(source)
+	case j.hcl1 || isJSON:
+		submission.Format = "hcl1"
+		jobStruct, err = jobspec.Parse(bytes.NewReader(source))
+	default:
+		// Keep the values of the variables along with the source so that
+		// the job can be parsed again from the submission
+		submission.Format = "hcl2"
+		config := &jobspec2.ParseConfig{
+			Path:     jpath,
+			Body:     source,
+			ArgVars:  j.vars,
+			VarFiles: j.varFiles,
+			Envs:     os.Environ(),
+		}
+		jobStruct, err = jobspec2.ParseWithConfig(config)
+		if err == nil {
+			submission.Variables, err = jobspec2.ParseVariables(config)
+		}
+		if err == nil && len(j.vars) != 0 {
+			submission.VariableFlags = make(map[string]string, len(j.vars))
+			for _, v := range j.vars {
+				if idx := strings.Index(v, "="); idx > 0 {
+					submission.VariableFlags[v[:idx]] = v[idx+1:]
+				}
+			}
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing job file from %s: %v", jpath, err)
+		return nil, nil, fmt.Errorf("Error parsing job file from %s: %v", jpath, err)
+	}
+
+	return jobStruct, submission, nil
+}
+
+// parseJSONJob decodes a job in the canonical JSON format of the API. The job
+// is either wrapped in a register request, as output by "job inspect" and
+// "job run -output", or bare, as output by "job inspect -json".
+func parseJSONJob(source []byte) (*api.Job, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(source, &fields); err != nil {
+		return nil, err
+	}
+
+	var job *api.Job
+	dec := json.NewDecoder(bytes.NewReader(source))
+	dec.DisallowUnknownFields()
+	if _, ok := fields["Job"]; ok {
+		var req api.RegisterJobRequest
+		if err := dec.Decode(&req); err != nil {
+			return nil, err
+		}
+		job = req.Job
+	} else if err := dec.Decode(&job); err != nil {
+		return nil, err
 	}
 
-	return jobStruct, nil
+	if job == nil || job.ID == nil || *job.ID == "" {
+		return nil, fmt.Errorf("Job ID must be set")
+	}
+	return job, nil
 }
 
 // COMPAT: Remove in 0.7.0
@@ -490,3 +596,20 @@ func sanitizeUUIDPrefix(prefix string) string {
 func commandErrorText(cmd NamedCommand) string {
 	return fmt.Sprintf("For additional help try 'nomad %s -help'", cmd.Name())
 }
+
+// validateTaskExistsInAllocation returns an error if the allocation's task
+// group does not have a task with the given name.
+func validateTaskExistsInAllocation(taskName string, alloc *api.Allocation) error {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return fmt.Errorf("Could not find allocation task group: %s", alloc.TaskGroup)
+	}
+
+	for _, task := range tg.Tasks {
+		if task.Name == taskName {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Could not find task named: %s", taskName)
+}
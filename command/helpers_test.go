@@ -1,6 +1,7 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,8 +15,10 @@ import (
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/flatmap"
+	"github.com/hashicorp/nomad/jobspec2"
 	"github.com/kr/pretty"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHelpers_FormatKV(t *testing.T) {
@@ -271,6 +274,108 @@ func TestJobGetter_LocalFile(t *testing.T) {
 	}
 }
 
+func TestJobGetter_Source(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// HCL job files are stored as such
+	j := &JobGetter{testStdin: strings.NewReader(job)}
+	_, sub, err := j.ApiJobWithSource("-")
+	require.NoError(err)
+	require.Equal(job, sub.Source)
+	require.Equal("hcl2", sub.Format)
+	require.Empty(sub.VariableFlags)
+	require.Empty(sub.Variables)
+
+	j = &JobGetter{hcl1: true, testStdin: strings.NewReader(job)}
+	_, sub, err = j.ApiJobWithSource("-")
+	require.NoError(err)
+	require.Equal("hcl1", sub.Format)
+
+	// JSON job files are detected and parsed by the HCL1 parser
+	jsonJob := `{"job": {"example": {"datacenters": ["dc1"]}}}`
+	j = &JobGetter{testStdin: strings.NewReader("\n" + jsonJob)}
+	_, sub, err = j.ApiJobWithSource("-")
+	require.NoError(err)
+	require.Equal("hcl1", sub.Format)
+
+	// The values of the input variables are stored along with the source
+	varsJob := `
+variable "image" {}
+variable "count" {
+  default = 1
+}
+variable "dc" {}
+
+job "example" {
+  datacenters = [var.dc]
+  group "cache" {
+    count = var.count
+    task "redis" {
+      driver = "docker"
+      config {
+        image = var.image
+      }
+    }
+  }
+}
+`
+	os.Setenv("NOMAD_VAR_dc", "dc2")
+	defer os.Unsetenv("NOMAD_VAR_dc")
+	j = &JobGetter{testStdin: strings.NewReader(varsJob)}
+	j.vars.Set("image=redis:4.0")
+	expected, sub, err := j.ApiJobWithSource("-")
+	require.NoError(err)
+	require.Equal("hcl2", sub.Format)
+	require.Equal(map[string]string{"image": "redis:4.0"}, sub.VariableFlags)
+	require.Equal("dc = \"dc2\"\nimage = \"redis:4.0\"\n", sub.Variables)
+
+	// The stored variables reproduce the job
+	aj, err := jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
+		Path:       "-",
+		Body:       []byte(sub.Source),
+		VarContent: []byte(sub.Variables),
+	})
+	require.NoError(err)
+	require.Equal(expected, aj)
+}
+
+func TestJobGetter_JSON(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	wrapped, err := json.Marshal(&api.RegisterJobRequest{Job: expectedApiJob})
+	require.NoError(err)
+	bare, err := json.Marshal(expectedApiJob)
+	require.NoError(err)
+
+	// Both the wrapped and the bare jobs are accepted
+	for _, source := range [][]byte{wrapped, bare} {
+		j := &JobGetter{json: true, testStdin: strings.NewReader(string(source))}
+		aj, sub, err := j.ApiJobWithSource("-")
+		require.NoError(err)
+		require.Equal(expectedApiJob, aj)
+		require.Equal("json", sub.Format)
+	}
+
+	// Unknown fields are rejected
+	j := &JobGetter{json: true, testStdin: strings.NewReader(`{"ID": "example", "Datacenter": "dc1"}`)}
+	_, err = j.ApiJob("-")
+	require.Error(err)
+	require.Contains(err.Error(), "unknown field")
+
+	// The job must have an ID
+	j = &JobGetter{json: true, testStdin: strings.NewReader(`{"Job": {"Name": "example"}}`)}
+	_, err = j.ApiJob("-")
+	require.Error(err)
+	require.Contains(err.Error(), "Job ID must be set")
+
+	// HCL1 and JSON are exclusive
+	j = &JobGetter{json: true, hcl1: true, testStdin: strings.NewReader(string(bare))}
+	_, err = j.ApiJob("-")
+	require.Error(err)
+}
+
 // Test StructJob with jobfile from HTTP Server
 func TestJobGetter_HTTPServer(t *testing.T) {
 	t.Parallel()
@@ -32,6 +32,15 @@ Inspect Options:
   -json
     Output the job in its JSON format.
 
+  -hcl
+    Output the source the job was submitted from, if it was stored. Jobs
+    registered with "nomad job run" store their source.
+
+  -vars
+    Used with -hcl, output the values of the input variables the job was
+    submitted with as a variable file, to be passed to -var-file along with
+    the source.
+
   -t
     Format and display job using a Go template.
 `
@@ -47,6 +56,8 @@ func (c *JobInspectCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-version": complete.PredictAnything,
 			"-json":    complete.PredictNothing,
+			"-hcl":     complete.PredictNothing,
+			"-vars":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
 		})
 }
@@ -69,12 +80,14 @@ func (c *JobInspectCommand) AutocompleteArgs() complete.Predictor {
 func (c *JobInspectCommand) Name() string { return "job inspect" }
 
 func (c *JobInspectCommand) Run(args []string) int {
-	var json bool
+	var json, hcl, vars bool
 	var tmpl, versionStr string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&hcl, "hcl", false, "")
+	flags.BoolVar(&vars, "vars", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.StringVar(&versionStr, "version", "", "")
 
@@ -83,6 +96,17 @@ func (c *JobInspectCommand) Run(args []string) int {
 	}
 	args = flags.Args()
 
+	if hcl && (json || len(tmpl) > 0) {
+		c.Ui.Error("The -hcl flag can't be used with the -json or -t flags")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if vars && !hcl {
+		c.Ui.Error("The -vars flag can only be used with the -hcl flag")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -142,6 +166,30 @@ func (c *JobInspectCommand) Run(args []string) int {
 		version = &v
 	}
 
+	// Output the source of the job if requested
+	if hcl {
+		sub, _, err := client.Jobs().Submission(jobs[0].ID, version, nil)
+		if err != nil {
+			if strings.Contains(err.Error(), "job source not found") {
+				c.Ui.Error(fmt.Sprintf("No source was stored for job %q", jobs[0].ID))
+				return 1
+			}
+			c.Ui.Error(fmt.Sprintf("Error inspecting job source: %s", err))
+			return 1
+		}
+
+		if vars {
+			c.Ui.Output(strings.TrimSpace(sub.Variables))
+			return 0
+		}
+
+		c.Ui.Output(strings.TrimSpace(sub.Source))
+		if sub.Variables != "" {
+			c.Ui.Warn("The job was submitted with input variables. Use the -vars flag to output them.")
+		}
+		return 0
+	}
+
 	// Prefix lookup matched a single job
 	job, err := getJob(client, jobs[0].ID, version)
 	if err != nil {
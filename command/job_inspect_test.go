@@ -4,10 +4,12 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInspectCommand_Implements(t *testing.T) {
@@ -59,6 +61,54 @@ func TestInspectCommand_Fails(t *testing.T) {
 	}
 }
 
+func TestInspectCommand_HCL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &JobInspectCommand{Meta: Meta{Ui: ui}}
+
+	// Register a job with its source and variables and one without
+	source := `job "job1" {}`
+	opts := &api.RegisterOptions{
+		Submission: &api.JobSubmission{
+			Source:    source,
+			Format:    "hcl2",
+			Variables: `image = "redis:4.0"` + "\n",
+		},
+	}
+	_, _, err := client.Jobs().RegisterOpts(testJob("job1"), opts, nil)
+	require.NoError(err)
+	_, _, err = client.Jobs().Register(testJob("job2"), nil)
+	require.NoError(err)
+
+	// The source is output
+	code := cmd.Run([]string{"-address=" + url, "-hcl", "job1"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Equal(source+"\n", ui.OutputWriter.String())
+	require.Contains(ui.ErrorWriter.String(), "-vars")
+	ui.OutputWriter.Reset()
+	ui.ErrorWriter.Reset()
+
+	// The variables are output
+	code = cmd.Run([]string{"-address=" + url, "-hcl", "-vars", "job1"})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Equal(`image = "redis:4.0"`+"\n", ui.OutputWriter.String())
+
+	// Fails on the job without a source
+	code = cmd.Run([]string{"-address=" + url, "-hcl", "job2"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), `No source was stored for job "job2"`)
+	ui.ErrorWriter.Reset()
+
+	// Fails with the -json flag
+	code = cmd.Run([]string{"-address=" + url, "-hcl", "-json", "job1"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "can't be used with the -json or -t flags")
+}
+
 func TestInspectCommand_AutocompleteArgs(t *testing.T) {
 	assert := assert.New(t)
 	t.Parallel()
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type JobPeriodicCommand struct {
+	Meta
+}
+
+func (c *JobPeriodicCommand) Help() string {
+	helpText := `
+Usage: nomad job periodic <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with periodic jobs.
+
+  Force a periodic job:
+
+      $ nomad job periodic force <job_id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobPeriodicCommand) Synopsis() string {
+	return "Interact with periodic jobs"
+}
+
+func (c *JobPeriodicCommand) Name() string { return "job periodic" }
+
+func (c *JobPeriodicCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,112 @@
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobPeriodicForceCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &JobPeriodicForceCommand{}
+}
+
+func TestJobPeriodicForceCommand_Fails(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &JobPeriodicForceCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	if code := cmd.Run([]string{"some", "bad", "args"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, commandErrorText(cmd)) {
+		t.Fatalf("expected help output, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	if code := cmd.Run([]string{"-address=nope", "foo"}); code != 1 {
+		t.Fatalf("expected exit code 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Error forcing periodic job") {
+		t.Fatalf("expected failed query error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+}
+
+func TestJobPeriodicForceCommand_NonPeriodicJob(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &JobPeriodicForceCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Create a job that is not periodic
+	state := srv.Agent.Server().State()
+	j := mock.Job()
+	require.Nil(state.UpsertJob(1000, j))
+
+	code := cmd.Run([]string{"-address=" + url, j.ID})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "No periodic job(s)")
+}
+
+func TestJobPeriodicForceCommand_Detach(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &JobPeriodicForceCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Register a periodic job through the server so the dispatcher tracks it
+	j := mock.PeriodicJob()
+	req := &structs.JobRegisterRequest{
+		Job: j,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: j.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.Nil(srv.Agent.RPC("Job.Register", req, &resp))
+
+	code := cmd.Run([]string{"-address=" + url, "-detach", j.ID})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Evaluation ID:")
+}
+
+func TestJobPeriodicForceCommand_AutocompleteArgs(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &JobPeriodicForceCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// Create a fake job
+	state := srv.Agent.Server().State()
+	j := mock.PeriodicJob()
+	assert.Nil(state.UpsertJob(1000, j))
+
+	prefix := j.ID[:len(j.ID)-5]
+	args := complete.Args{Last: prefix}
+	predictor := cmd.AutocompleteArgs()
+
+	res := predictor.Predict(args)
+	assert.Equal(1, len(res))
+	assert.Equal(j.ID, res[0])
+}
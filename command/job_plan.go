@@ -67,9 +67,24 @@ Plan Options:
     Determines whether the diff between the remote job and planned job is shown.
     Defaults to true.
 
+  -hcl1
+    Parses the job file as HCL1. HCL1 doesn't support the variables,
+    expressions and dynamic blocks of HCL2.
+
+  -json
+    Parses the job file as the canonical JSON format of the API, as output by
+    "nomad job inspect".
+
   -policy-override
     Sets the flag to force override any soft mandatory Sentinel policies.
 
+  -var 'key=value'
+    Sets the value of an input variable of the job file. It can be repeated.
+
+  -var-file=path
+    Sets the values of input variables from an HCL file of variable
+    assignments. It can be repeated.
+
   -verbose
     Increase diff verbosity.
 `
@@ -86,6 +101,10 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 			"-diff":            complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
 			"-verbose":         complete.PredictNothing,
+			"-hcl1":            complete.PredictNothing,
+			"-json":            complete.PredictNothing,
+			"-var":             complete.PredictAnything,
+			"-var-file":        complete.PredictFiles("*.hcl"),
 		})
 }
 
@@ -102,6 +121,7 @@ func (c *JobPlanCommand) Run(args []string) int {
 	flags.BoolVar(&diff, "diff", true, "")
 	flags.BoolVar(&policyOverride, "policy-override", false, "")
 	flags.BoolVar(&verbose, "verbose", false, "")
+	c.JobGetter.registerFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 255
@@ -214,7 +234,7 @@ func formatDryRun(resp *api.JobPlanResponse, job *api.Job) string {
 		out = "[bold][green]- All tasks successfully allocated.[reset]\n"
 	} else {
 		// Change the output depending on if we are a system job or not
-		if job.Type != nil && *job.Type == "system" {
+		if job.Type != nil && (*job.Type == "system" || *job.Type == "sysbatch") {
 			out = "[bold][yellow]- WARNING: Failed to place allocations on all nodes.[reset]\n"
 		} else {
 			out = "[bold][yellow]- WARNING: Failed to place all allocations.[reset]\n"
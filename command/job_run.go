@@ -75,6 +75,14 @@ Run Options:
     the evaluation ID will be printed to the screen, which can be used to
     examine the evaluation using the eval-status command.
 
+  -hcl1
+    Parses the job file as HCL1. HCL1 doesn't support the variables,
+    expressions and dynamic blocks of HCL2.
+
+  -json
+    Parses the job file as the canonical JSON format of the API, as output by
+    "nomad job inspect".
+
   -output
     Output the JSON that would be submitted to the HTTP API without submitting
     the job.
@@ -88,6 +96,13 @@ Run Options:
     the job file. This overrides the token found in $VAULT_TOKEN environment
     variable and that found in the job.
 
+  -var 'key=value'
+    Sets the value of an input variable of the job file. It can be repeated.
+
+  -var-file=path
+    Sets the values of input variables from an HCL file of variable
+    assignments. It can be repeated.
+
   -verbose
     Display full information.
 `
@@ -107,6 +122,10 @@ func (c *JobRunCommand) AutocompleteFlags() complete.Flags {
 			"-vault-token":     complete.PredictAnything,
 			"-output":          complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
+			"-hcl1":            complete.PredictNothing,
+			"-json":            complete.PredictNothing,
+			"-var":             complete.PredictAnything,
+			"-var-file":        complete.PredictFiles("*.hcl"),
 		})
 }
 
@@ -128,6 +147,7 @@ func (c *JobRunCommand) Run(args []string) int {
 	flags.BoolVar(&override, "policy-override", false, "")
 	flags.StringVar(&checkIndexStr, "check-index", "", "")
 	flags.StringVar(&vaultToken, "vault-token", "", "")
+	c.JobGetter.registerFlags(flags)
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -148,7 +168,7 @@ func (c *JobRunCommand) Run(args []string) int {
 	}
 
 	// Get Job struct from Jobfile
-	job, err := c.JobGetter.ApiJob(args[0])
+	job, submission, err := c.JobGetter.ApiJobWithSource(args[0])
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 1
@@ -204,8 +224,10 @@ func (c *JobRunCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Set the register options
-	opts := &api.RegisterOptions{}
+	// Set the register options, storing the source of the job with it
+	opts := &api.RegisterOptions{
+		Submission: submission,
+	}
 	if enforce {
 		opts.EnforceIndex = true
 		opts.ModifyIndex = checkIndex
@@ -262,6 +284,13 @@ func (c *JobRunCommand) Run(args []string) int {
 		return 0
 	}
 
+	// A multiregion job that isn't deployed to the region it was submitted to
+	// has no evaluation in the region to monitor
+	if evalID == "" {
+		c.Ui.Output("Job registration successful")
+		return 0
+	}
+
 	// Detach was not specified, so start monitoring
 	mon := newMonitor(c.Ui, client, length)
 	return mon.monitor(evalID, false)
@@ -31,7 +31,8 @@ func (c *JobStatusCommand) Help() string {
 Usage: nomad status [options] <job>
 
   Display status information about a job. If no job ID is given, a list of all
-  known jobs will be displayed.
+  known jobs will be displayed. The jobs of all the namespaces the token may
+  list jobs in are displayed when the namespace is "*".
 
 General Options:
 
@@ -133,6 +134,8 @@ func (c *JobStatusCommand) Run(args []string) int {
 		if len(jobs) == 0 {
 			// No output if we have no jobs
 			c.Ui.Output("No running jobs")
+		} else if c.Meta.namespace == api.AllNamespacesNamespace {
+			c.Ui.Output(createNamespacedStatusListOutput(jobs))
 		} else {
 			c.Ui.Output(createStatusListOutput(jobs))
 		}
@@ -165,6 +168,12 @@ func (c *JobStatusCommand) Run(args []string) int {
 	periodic := job.IsPeriodic()
 	parameterized := job.IsParameterized()
 
+	// Jobs registered before node pools existed are in the default pool
+	nodePool := "default"
+	if job.NodePool != nil && *job.NodePool != "" {
+		nodePool = *job.NodePool
+	}
+
 	// Format the job info
 	basic := []string{
 		fmt.Sprintf("ID|%s", *job.ID),
@@ -173,6 +182,7 @@ func (c *JobStatusCommand) Run(args []string) int {
 		fmt.Sprintf("Type|%s", *job.Type),
 		fmt.Sprintf("Priority|%d", *job.Priority),
 		fmt.Sprintf("Datacenters|%s", strings.Join(job.Datacenters, ",")),
+		fmt.Sprintf("Node Pool|%s", nodePool),
 		fmt.Sprintf("Status|%s", getStatusString(*job.Status, job.Stop)),
 		fmt.Sprintf("Periodic|%v", periodic),
 		fmt.Sprintf("Parameterized|%v", parameterized),
@@ -645,6 +655,22 @@ func createStatusListOutput(jobs []*api.JobListStub) string {
 	return formatList(out)
 }
 
+// createNamespacedStatusListOutput lists the jobs of several namespaces
+func createNamespacedStatusListOutput(jobs []*api.JobListStub) string {
+	out := make([]string, len(jobs)+1)
+	out[0] = "ID|Namespace|Type|Priority|Status|Submit Date"
+	for i, job := range jobs {
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%d|%s|%s",
+			job.ID,
+			job.Namespace,
+			getTypeString(job),
+			job.Priority,
+			getStatusString(job.Status, &job.Stop),
+			formatTime(time.Unix(0, job.SubmitTime)))
+	}
+	return formatList(out)
+}
+
 func getTypeString(job *api.JobListStub) string {
 	t := job.Type
 
@@ -334,6 +334,38 @@ func TestJobStatusCommand_RescheduleEvals(t *testing.T) {
 	require.Contains(out, e.ID[:8])
 }
 
+func TestJobStatusCommand_AllNamespaces(t *testing.T) {
+	t.Parallel()
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &JobStatusCommand{Meta: Meta{Ui: ui}}
+
+	require := require.New(t)
+	state := srv.Agent.Server().State()
+
+	// Create a job in the default namespace and one in another namespace
+	ns := mock.Namespace()
+	require.Nil(state.UpsertNamespaces(900, []*structs.Namespace{ns}))
+
+	j1 := mock.Job()
+	require.Nil(state.UpsertJob(901, j1))
+	j2 := mock.Job()
+	j2.Namespace = ns.Name
+	require.Nil(state.UpsertJob(902, j2))
+
+	// List the jobs of all the namespaces
+	if code := cmd.Run([]string{"-address=" + url, "-namespace=*"}); code != 0 {
+		t.Fatalf("expected exit 0, got: %d", code)
+	}
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Namespace")
+	require.Contains(out, j1.ID)
+	require.Contains(out, j2.ID)
+	require.Contains(out, ns.Name)
+}
+
 func waitForSuccess(ui cli.Ui, client *api.Client, length int, t *testing.T, evalId string) int {
 	mon := newMonitor(ui, client, length)
 	monErr := mon.monitor(evalId, false)
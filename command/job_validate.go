@@ -27,6 +27,23 @@ Alias: nomad validate
   If the supplied path is "-", the jobfile is read from stdin. Otherwise
   it is read from the file at the supplied path or downloaded and
   read from URL specified.
+
+Validate Options:
+
+  -hcl1
+    Parses the job file as HCL1. HCL1 doesn't support the variables,
+    expressions and dynamic blocks of HCL2.
+
+  -json
+    Parses the job file as the canonical JSON format of the API, as output by
+    "nomad job inspect".
+
+  -var 'key=value'
+    Sets the value of an input variable of the job file. It can be repeated.
+
+  -var-file=path
+    Sets the values of input variables from an HCL file of variable
+    assignments. It can be repeated.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -36,7 +53,12 @@ func (c *JobValidateCommand) Synopsis() string {
 }
 
 func (c *JobValidateCommand) AutocompleteFlags() complete.Flags {
-	return nil
+	return complete.Flags{
+		"-hcl1":     complete.PredictNothing,
+		"-json":     complete.PredictNothing,
+		"-var":      complete.PredictAnything,
+		"-var-file": complete.PredictFiles("*.hcl"),
+	}
 }
 
 func (c *JobValidateCommand) AutocompleteArgs() complete.Predictor {
@@ -48,6 +70,7 @@ func (c *JobValidateCommand) Name() string { return "job validate" }
 func (c *JobValidateCommand) Run(args []string) int {
 	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	c.JobGetter.registerFlags(flags)
 	if err := flags.Parse(args); err != nil {
 		return 1
 	}
@@ -134,5 +157,6 @@ func (c *JobValidateCommand) validateLocal(aj *api.Job) (*api.JobValidateRespons
 
 	warnings := job.Warnings()
 	out.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
+	out.ValidationWarnings = structs.FlattenMultierrorWarnings(warnings, canonicalizeWarnings)
 	return &out, nil
 }
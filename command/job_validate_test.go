@@ -57,6 +57,71 @@ job "job1" {
 	}
 }
 
+func TestValidateCommand_Vars(t *testing.T) {
+	t.Parallel()
+	ui := new(cli.MockUi)
+	cmd := &JobValidateCommand{Meta: Meta{Ui: ui}}
+
+	// Create a server
+	s := testutil.NewTestServer(t, nil)
+	defer s.Stop()
+	os.Setenv("NOMAD_ADDR", fmt.Sprintf("http://%s", s.HTTPAddr))
+
+	fh, err := ioutil.TempFile("", "nomad")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(fh.Name())
+	_, err = fh.WriteString(`
+variable "command" {
+	type = string
+}
+
+job "job1" {
+	type = "service"
+	datacenters = [ "dc1" ]
+	group "group1" {
+		count = 1
+		task "task1" {
+			driver = "exec"
+			config {
+				command = var.command
+			}
+			resources {
+				cpu = 1000
+				memory = 512
+			}
+		}
+	}
+}`)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Fails when the variable is not set
+	if code := cmd.Run([]string{fh.Name()}); code != 1 {
+		t.Fatalf("expect exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "Unset variable") {
+		t.Fatalf("expect unset variable error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	// Variables can't be set for HCL1 job files
+	if code := cmd.Run([]string{"-hcl1", "-var", "command=/bin/sleep", fh.Name()}); code != 1 {
+		t.Fatalf("expect exit 1, got: %d", code)
+	}
+	if out := ui.ErrorWriter.String(); !strings.Contains(out, "only be set for HCL2") {
+		t.Fatalf("expect HCL2 error, got: %s", out)
+	}
+	ui.ErrorWriter.Reset()
+
+	cmd = &JobValidateCommand{Meta: Meta{Ui: ui}}
+	if code := cmd.Run([]string{"-var", "command=/bin/sleep", fh.Name()}); code != 0 {
+		t.Fatalf("expect exit 0, got: %d: %s", code, ui.ErrorWriter.String())
+	}
+}
+
 func TestValidateCommand_Fails(t *testing.T) {
 	t.Parallel()
 	ui := new(cli.MockUi)
@@ -0,0 +1,271 @@
+package command
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/posener/complete"
+)
+
+const (
+	// defaultOIDCCallbackAddr is the address the login command listens on for
+	// the OIDC provider to redirect the user back to
+	defaultOIDCCallbackAddr = "localhost:4649"
+
+	// oidcCallbackPath is the path of the OIDC redirect URI
+	oidcCallbackPath = "/oidc/callback"
+)
+
+type LoginCommand struct {
+	Meta
+}
+
+func (c *LoginCommand) Help() string {
+	helpText := `
+Usage: nomad login [options]
+
+  Login is used to exchange an identity from an external OIDC or JWT provider
+  for a Nomad ACL token, using one of the cluster's ACL auth methods.
+
+  With an OIDC auth method, the command prints a URL to visit to log in with
+  the provider and waits for the provider to redirect back to it. With a JWT
+  auth method, the JWT is given with -login-token.
+
+  The token's secret ID is printed and can be set as the NOMAD_TOKEN
+  environment variable for later commands.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Login Options:
+
+  -method=""
+    Specifies the name of the auth method to log in with. The default auth
+    method of the type is used if it is not set.
+
+  -type="OIDC"
+    Specifies the type of the auth method. Must be "OIDC" or "JWT". Defaults
+    to "JWT" if -login-token is set.
+
+  -login-token=""
+    Specifies the JWT to log in with when using a JWT auth method.
+
+  -oidc-callback-addr="localhost:4649"
+    Specifies the address to listen on for the OIDC provider's redirect. The
+    auth method must allow the "http://<addr>/oidc/callback" redirect URI.
+
+  -json
+    Output the ACL token in a JSON format.
+
+  -t
+    Format and display the ACL token using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *LoginCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-method":             complete.PredictAnything,
+			"-type":               complete.PredictSet("OIDC", "JWT"),
+			"-login-token":        complete.PredictAnything,
+			"-oidc-callback-addr": complete.PredictAnything,
+			"-json":               complete.PredictNothing,
+			"-t":                  complete.PredictAnything,
+		})
+}
+
+func (c *LoginCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *LoginCommand) Synopsis() string {
+	return "Log in to Nomad using an auth method"
+}
+
+func (c *LoginCommand) Name() string { return "login" }
+
+func (c *LoginCommand) Run(args []string) int {
+	var method, methodType, loginToken, callbackAddr, tmpl string
+	var json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&method, "method", "", "")
+	flags.StringVar(&methodType, "type", "", "")
+	flags.StringVar(&loginToken, "login-token", "", "")
+	flags.StringVar(&callbackAddr, "oidc-callback-addr", defaultOIDCCallbackAddr, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	args = flags.Args()
+	if l := len(args); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	methodType = strings.ToUpper(methodType)
+	if methodType == "" {
+		methodType = api.ACLAuthMethodTypeOIDC
+		if loginToken != "" {
+			methodType = api.ACLAuthMethodTypeJWT
+		}
+	}
+
+	switch methodType {
+	case api.ACLAuthMethodTypeJWT:
+		if loginToken == "" {
+			c.Ui.Error("A JWT must be specified with -login-token")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+	case api.ACLAuthMethodTypeOIDC:
+		if loginToken != "" {
+			c.Ui.Error("-login-token can only be used with JWT auth methods")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+	default:
+		c.Ui.Error(fmt.Sprintf("Unsupported auth method type %q", methodType))
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	var token *api.ACLToken
+	if methodType == api.ACLAuthMethodTypeJWT {
+		token, _, err = client.ACLAuth().Login(&api.ACLLoginRequest{
+			AuthMethodName: method,
+			LoginToken:     loginToken,
+		}, nil)
+	} else {
+		token, err = c.oidcLogin(client, method, callbackAddr)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error logging in: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, token)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully logged in via %s\n", methodType))
+	c.Ui.Output(formatKVACLToken(token))
+	return 0
+}
+
+// oidcCallback holds the parameters the OIDC provider passed to the redirect
+// URI
+type oidcCallback struct {
+	state string
+	code  string
+	err   error
+}
+
+// oidcLogin runs the OIDC authorization code flow. It listens on the callback
+// address for the provider's redirect, and completes the login with the
+// returned code.
+func (c *LoginCommand) oidcLogin(client *api.Client, method, callbackAddr string) (*api.ACLToken, error) {
+	ln, err := net.Listen("tcp", callbackAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the OIDC callback: %v", err)
+	}
+	defer ln.Close()
+
+	redirectURI := "http://" + callbackAddr + oidcCallbackPath
+	nonce := uuid.Generate()
+
+	resp, _, err := client.ACLAuth().OIDCAuthURL(&api.ACLOIDCAuthURLRequest{
+		AuthMethodName: method,
+		RedirectURI:    redirectURI,
+		ClientNonce:    nonce,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	state := resp.State
+
+	callbackCh := make(chan *oidcCallback, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		cb := &oidcCallback{state: q.Get("state"), code: q.Get("code")}
+		switch {
+		case q.Get("error") != "":
+			cb.err = fmt.Errorf("provider returned an error: %s %s", q.Get("error"), q.Get("error_description"))
+		case cb.state != state:
+			cb.err = fmt.Errorf("state of the callback does not match the login")
+		case cb.code == "":
+			cb.err = fmt.Errorf("callback is missing the authorization code")
+		}
+
+		if cb.err != nil {
+			http.Error(w, cb.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Login successful, you can close this window and return to the terminal.")
+		}
+
+		select {
+		case callbackCh <- cb:
+		default:
+		}
+	})
+
+	srv := &http.Server{Handler: mux}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	c.Ui.Output(fmt.Sprintf("Complete the login via your OIDC provider by visiting:\n\n    %s\n", resp.AuthURL))
+	c.Ui.Output("Waiting for the OIDC provider to redirect back...")
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	var cb *oidcCallback
+	select {
+	case cb = <-callbackCh:
+	case <-signalCh:
+		return nil, fmt.Errorf("login interrupted")
+	}
+	if cb.err != nil {
+		return nil, cb.err
+	}
+
+	token, _, err := client.ACLAuth().OIDCCompleteAuth(&api.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: method,
+		ClientNonce:    nonce,
+		State:          cb.state,
+		Code:           cb.code,
+		RedirectURI:    redirectURI,
+	}, nil)
+	return token, err
+}
@@ -0,0 +1,144 @@
+package command
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginCommand_JWT(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	provider := auth.NewTestProvider(t)
+	defer provider.Stop()
+
+	// Create a JWT auth method that grants management tokens
+	method := mock.ACLAuthMethod()
+	method.Default = true
+	method.Config = &structs.ACLAuthMethodConfig{
+		JWTValidationPubKeys: []string{provider.PublicKeyPEM()},
+		BoundAudiences:       []string{"nomad"},
+	}
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	rule.Selector = ""
+	rule.BindType = structs.ACLBindingRuleBindTypeManagement
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	ui := new(cli.MockUi)
+	cmd := &LoginCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	// A JWT auth method requires a login token
+	code := cmd.Run([]string{"-address=" + url, "-type=jwt"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "-login-token")
+
+	// A JWT for another audience is rejected
+	jwt := provider.SignToken(auth.Claims{"sub": "alice", "aud": "other"})
+	code = cmd.Run([]string{"-address=" + url, "-login-token=" + jwt})
+	require.Equal(1, code)
+
+	// Log in with the default JWT auth method
+	jwt = provider.SignToken(auth.Claims{"sub": "alice", "aud": "nomad"})
+	code = cmd.Run([]string{"-address=" + url, "-login-token=" + jwt})
+	require.Equal(0, code)
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Successfully logged in via JWT")
+	require.Contains(out, "JWT-"+method.Name)
+	require.Contains(out, "management")
+}
+
+func TestLoginCommand_OIDC(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	state := srv.Agent.Server().State()
+	defer srv.Shutdown()
+
+	provider := auth.NewTestProvider(t)
+	defer provider.Stop()
+	provider.SetClaims(auth.Claims{"sub": "alice", "groups": []string{"engineering"}})
+
+	// Pick a free port for the callback listener
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(err)
+	callbackAddr := ln.Addr().String()
+	ln.Close()
+
+	// Create an OIDC auth method that grants the engineering policy
+	method := mock.ACLAuthMethod()
+	method.Type = structs.ACLAuthMethodTypeOIDC
+	method.Config = &structs.ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    provider.Issuer(),
+		OIDCClientID:        provider.ClientID,
+		OIDCClientSecret:    provider.Secret,
+		AllowedRedirectURIs: []string{"http://" + callbackAddr + "/oidc/callback"},
+		ListClaimMappings:   map[string]string{"groups": "groups"},
+	}
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	rule.BindType = structs.ACLBindingRuleBindTypePolicy
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	ui := new(cli.MockUi)
+	cmd := &LoginCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+
+	codeCh := make(chan int, 1)
+	go func() {
+		codeCh <- cmd.Run([]string{"-address=" + url, "-method=" + method.Name,
+			"-oidc-callback-addr=" + callbackAddr})
+	}()
+
+	// Wait for the auth URL to be printed
+	urlRe := regexp.MustCompile(`http://\S+/authorize\?\S+`)
+	var authURL string
+	testutil.WaitForResult(func() (bool, error) {
+		authURL = urlRe.FindString(ui.OutputWriter.String())
+		return authURL != "", fmt.Errorf("auth URL not printed: %s", ui.ErrorWriter.String())
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Log in at the provider and follow its redirect back to the command
+	code, oidcState, err := provider.Authorize(authURL)
+	require.NoError(err)
+	resp, err := http.Get(fmt.Sprintf("http://%s/oidc/callback?code=%s&state=%s", callbackAddr, code, oidcState))
+	require.NoError(err)
+	resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	select {
+	case exit := <-codeCh:
+		require.Equal(0, exit, ui.ErrorWriter.String())
+	case <-time.After(10 * time.Second):
+		t.Fatalf("login did not complete")
+	}
+	out := ui.OutputWriter.String()
+	require.Contains(out, "Successfully logged in via OIDC")
+	require.Contains(out, "[engineering]")
+}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -374,20 +375,37 @@ func formatAllocMetrics(metrics *api.AllocationMetric, scores bool, prefix strin
 	// Print scores
 	if scores {
 		if len(metrics.ScoreMetaData) > 0 {
+			// Collect the scorers of all the nodes so that the columns line
+			// up even when a scorer didn't score every node
+			var scorers []string
+			seen := make(map[string]struct{})
+			for _, scoreMeta := range metrics.ScoreMetaData {
+				for scorerName := range scoreMeta.Scores {
+					if _, ok := seen[scorerName]; !ok {
+						seen[scorerName] = struct{}{}
+						scorers = append(scorers, scorerName)
+					}
+				}
+			}
+			sort.Strings(scorers)
+
 			scoreOutput := make([]string, len(metrics.ScoreMetaData)+1)
 
+			// Add header as first row
+			scoreOutput[0] = "Node|"
+			for _, scorerName := range scorers {
+				scoreOutput[0] += fmt.Sprintf("%v|", scorerName)
+			}
+			scoreOutput[0] += "Final Score"
+
 			for i, scoreMeta := range metrics.ScoreMetaData {
-				// Add header as first row
-				if i == 0 {
-					scoreOutput[0] = "Node|"
-					for scorerName := range scoreMeta.Scores {
-						scoreOutput[0] += fmt.Sprintf("%v|", scorerName)
-					}
-					scoreOutput[0] += "Final Score"
-				}
 				scoreOutput[i+1] = fmt.Sprintf("%v|", scoreMeta.NodeID)
-				for _, scoreVal := range scoreMeta.Scores {
-					scoreOutput[i+1] += fmt.Sprintf("%v|", scoreVal)
+				for _, scorerName := range scorers {
+					if scoreVal, ok := scoreMeta.Scores[scorerName]; ok {
+						scoreOutput[i+1] += fmt.Sprintf("%v|", scoreVal)
+					} else {
+						scoreOutput[i+1] += "-|"
+					}
 				}
 				scoreOutput[i+1] += fmt.Sprintf("%v", scoreMeta.NormScore)
 			}
@@ -5,8 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMonitor_Update_Eval(t *testing.T) {
@@ -285,3 +287,47 @@ func TestMonitor_MonitorWithPrefix(t *testing.T) {
 	}
 
 }
+
+func TestMonitor_formatAllocMetric(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	metrics := &api.AllocationMetric{
+		NodesEvaluated: 3,
+		ConstraintFiltered: map[string]int{
+			"${attr.kernel.name} = linux": 1,
+		},
+		NodesExhausted: 1,
+		DimensionExhausted: map[string]int{
+			"memory": 1,
+		},
+		ScoreMetaData: []*api.NodeScoreMeta{
+			{
+				NodeID: "node-1",
+				Scores: map[string]float64{
+					"node-affinity": 0.5,
+					"binpack":       0.75,
+				},
+				NormScore: 0.625,
+			},
+			{
+				NodeID: "node-2",
+				Scores: map[string]float64{
+					"binpack": 0.25,
+				},
+				NormScore: 0.25,
+			},
+		},
+	}
+
+	out := formatAllocMetrics(metrics, true, "  ")
+	require.Contains(out, `Constraint "${attr.kernel.name} = linux" filtered 1 nodes`)
+	require.Contains(out, `Dimension "memory" exhausted on 1 nodes`)
+
+	// The scorers are sorted and missing scores are marked
+	lines := strings.Split(out, "\n")
+	table := lines[len(lines)-3:]
+	require.Regexp(`^Node\s+binpack\s+node-affinity\s+Final Score$`, table[0])
+	require.Regexp(`^node-1\s+0.75\s+0.5\s+0.625$`, table[1])
+	require.Regexp(`^node-2\s+0.25\s+-\s+0.25$`, table[2])
+}
@@ -27,6 +27,10 @@ Usage: nomad node <subcommand> [options] [args]
 
       $ nomad node eligibility -disable <node-id>
 
+  Tag a node at runtime so jobs can target it with constraints:
+
+      $ nomad node meta apply -node-id <node-id> canary=true
+
   Mark a node to be drained, allowing batch jobs four hours to finished before
   forcing them off the node:
 
@@ -147,7 +147,7 @@ func (c *NodeDrainCommand) Run(args []string) int {
 
 	// Check that we got either enable or disable, but not both.
 	if (enable && disable) || (!monitor && !enable && !disable) {
-		c.Ui.Error("Ethier the '-enable' or '-disable' flag must be set, unless using '-monitor'")
+		c.Ui.Error("Either the '-enable' or '-disable' flag must be set, unless using '-monitor'")
 		c.Ui.Error(commandErrorText(c))
 		return 1
 	}
@@ -160,7 +160,13 @@ func (c *NodeDrainCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Validate a compatible set of flags were set
+	// Validate a compatible set of flags were set. Monitoring only uses
+	// -ignore-system to decide which allocations to wait on.
+	if monitor && (deadline != "" || force || noDeadline) {
+		c.Ui.Error("-monitor can't be combined with flags configuring the drain deadline")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
 	if disable && (deadline != "" || force || noDeadline || ignoreSystem) {
 		c.Ui.Error("-disable can't be combined with flags configuring drain strategy")
 		c.Ui.Error(commandErrorText(c))
@@ -382,6 +382,17 @@ func TestNodeDrainCommand_Fails(t *testing.T) {
 		ui.ErrorWriter.Reset()
 	}
 
+	// Fail on monitor being used with deadline flags
+	for _, flag := range []string{"-force", "-no-deadline", "-deadline=10s"} {
+		if code := cmd.Run([]string{"-address=" + url, "-monitor", flag, "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
+			t.Fatalf("expected exit 1, got: %d", code)
+		}
+		if out := ui.ErrorWriter.String(); !strings.Contains(out, "combined with flags configuring the drain deadline") {
+			t.Fatalf("got: %s", out)
+		}
+		ui.ErrorWriter.Reset()
+	}
+
 	// Fail on setting a deadline plus deadline modifying flags
 	for _, flag := range []string{"-force", "-no-deadline"} {
 		if code := cmd.Run([]string{"-address=" + url, "-enable", "-deadline=10s", flag, "12345678-abcd-efab-cdef-123456789abc"}); code != 1 {
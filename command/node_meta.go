@@ -0,0 +1,86 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+type NodeMetaCommand struct {
+	Meta
+}
+
+func (c *NodeMetaCommand) Help() string {
+	helpText := `
+Usage: nomad node meta <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with the metadata of nodes.
+  Metadata set with these commands is persisted by the client and can be used
+  in constraints and affinities without restarting the agent.
+
+  Read the metadata of the local node:
+
+      $ nomad node meta read
+
+  Set and unset metadata of a node:
+
+      $ nomad node meta apply -node-id <node-id> -unset rack canary=true
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaCommand) Synopsis() string {
+	return "Interact with node metadata"
+}
+
+func (c *NodeMetaCommand) Name() string { return "node meta" }
+
+func (c *NodeMetaCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+// lookupNodeID returns the ID of the node matching the prefix. An empty prefix
+// is returned as is to target the node of the agent.
+func lookupNodeID(client *api.Client, prefix string) (string, error) {
+	if prefix == "" {
+		return "", nil
+	}
+
+	if len(prefix) == 1 {
+		return "", fmt.Errorf("Identifier must contain at least two characters.")
+	}
+
+	prefix = sanitizeUUIDPrefix(prefix)
+	nodes, _, err := client.Nodes().PrefixList(prefix)
+	if err != nil {
+		return "", fmt.Errorf("Error querying node: %s", err)
+	}
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("No node(s) with prefix or id %q found", prefix)
+	}
+	if len(nodes) > 1 {
+		return "", fmt.Errorf("Prefix matched multiple nodes\n\n%s",
+			formatNodeStubList(nodes, true))
+	}
+	return nodes[0].ID, nil
+}
+
+// formatNodeMeta returns the metadata as sorted key value pairs.
+func formatNodeMeta(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, fmt.Sprintf("%s|%s", k, meta[k]))
+	}
+	return formatKV(out)
+}
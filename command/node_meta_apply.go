@@ -0,0 +1,117 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type NodeMetaApplyCommand struct {
+	Meta
+}
+
+func (c *NodeMetaApplyCommand) Help() string {
+	helpText := `
+Usage: nomad node meta apply [options] <key>=<value>...
+
+  Set or unset the metadata of a node at runtime. The metadata is persisted by
+  the client, takes effect without restarting the agent and overrides metadata
+  with the same keys from the client configuration. If no node is given the
+  metadata of the node of the agent the command is run against is changed.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Node Meta Apply Options:
+
+  -node-id
+    Updates the metadata of the node with the given ID or prefix.
+
+  -unset
+    Comma separated list of keys to unset. Unset keys are removed even if they
+    are set in the client configuration.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaApplyCommand) Synopsis() string {
+	return "Set or unset the metadata of a node"
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictAnything,
+			"-unset":   complete.PredictAnything,
+		})
+}
+
+func (c *NodeMetaApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *NodeMetaApplyCommand) Name() string { return "node meta apply" }
+
+func (c *NodeMetaApplyCommand) Run(args []string) int {
+	var nodeID, unset string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.StringVar(&unset, "unset", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Build the metadata to apply
+	meta := make(map[string]*string)
+	for _, kv := range flags.Args() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			c.Ui.Error(fmt.Sprintf("Metadata must be given as <key>=<value>, got %q", kv))
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+		value := parts[1]
+		meta[parts[0]] = &value
+	}
+	if unset != "" {
+		for _, k := range strings.Split(unset, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				meta[k] = nil
+			}
+		}
+	}
+	if len(meta) == 0 {
+		c.Ui.Error("Metadata to set or unset must be given")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if nodeID, err = lookupNodeID(client, nodeID); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	req := &api.NodeMetaApplyRequest{
+		NodeID: nodeID,
+		Meta:   meta,
+	}
+	if _, err := client.Nodes().Meta().Apply(req, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying node metadata: %s", err))
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,104 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type NodeMetaReadCommand struct {
+	Meta
+}
+
+func (c *NodeMetaReadCommand) Help() string {
+	helpText := `
+Usage: nomad node meta read [options]
+
+  Read the metadata of a node. If no node is given the metadata of the node of
+  the agent the command is run against is read.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Node Meta Read Options:
+
+  -node-id
+    Reads the metadata of the node with the given ID or prefix.
+
+  -json
+    Output the metadata in its JSON format, including the metadata from the
+    client configuration and the metadata set at runtime.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *NodeMetaReadCommand) Synopsis() string {
+	return "Read the metadata of a node"
+}
+
+func (c *NodeMetaReadCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node-id": complete.PredictAnything,
+			"-json":    complete.PredictNothing,
+		})
+}
+
+func (c *NodeMetaReadCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *NodeMetaReadCommand) Name() string { return "node meta read" }
+
+func (c *NodeMetaReadCommand) Run(args []string) int {
+	var nodeID string
+	var json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.BoolVar(&json, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if nodeID, err = lookupNodeID(client, nodeID); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	meta, err := client.Nodes().Meta().Read(nodeID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading node metadata: %s", err))
+		return 1
+	}
+
+	if json {
+		out, err := Format(json, "", meta)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatNodeMeta(meta.Meta))
+	return 0
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMetaCommands_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &NodeMetaCommand{}
+	var _ cli.Command = &NodeMetaApplyCommand{}
+	var _ cli.Command = &NodeMetaReadCommand{}
+}
+
+func TestNodeMetaApplyCommand_Fails(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ui := new(cli.MockUi)
+	cmd := &NodeMetaApplyCommand{Meta: Meta{Ui: ui}}
+
+	// Fails without metadata
+	require.Equal(1, cmd.Run([]string{}))
+	require.Contains(ui.ErrorWriter.String(), "Metadata to set or unset must be given")
+	ui.ErrorWriter.Reset()
+
+	// Fails on malformed metadata
+	require.Equal(1, cmd.Run([]string{"canary"}))
+	require.Contains(ui.ErrorWriter.String(), "Metadata must be given as <key>=<value>")
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	require.Equal(1, cmd.Run([]string{"-address=nope", "canary=true"}))
+	require.Contains(ui.ErrorWriter.String(), "Error applying node metadata")
+	ui.ErrorWriter.Reset()
+
+	// Fails on unknown node
+	require.Equal(1, cmd.Run([]string{"-address=nope", "-node-id=12345678-abcd-efab-cdef-123456789abc", "canary=true"}))
+	require.Contains(ui.ErrorWriter.String(), "Error querying node")
+}
+
+func TestNodeMetaCommands_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Wait for the node to register
+	var nodeID string
+	testutil.WaitForResult(func() (bool, error) {
+		nodes, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return false, err
+		}
+		if len(nodes) == 0 {
+			return false, nil
+		}
+		nodeID = nodes[0].ID
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+
+	ui := new(cli.MockUi)
+	apply := &NodeMetaApplyCommand{Meta: Meta{Ui: ui}}
+	read := &NodeMetaReadCommand{Meta: Meta{Ui: ui}}
+
+	code := apply.Run([]string{"-address=" + url, "-node-id=" + nodeID[:8], "canary=true", "rack=r1"})
+	require.Zero(code)
+
+	code = apply.Run([]string{"-address=" + url, "-unset=rack"})
+	require.Zero(code)
+
+	code = read.Run([]string{"-address=" + url, "-node-id=" + nodeID})
+	require.Zero(code)
+	out := ui.OutputWriter.String()
+	require.Contains(out, "canary")
+	require.NotContains(out, "rack")
+}
@@ -181,7 +181,7 @@ func (c *NodeStatusCommand) Run(args []string) int {
 		// Format the nodes list
 		out := make([]string, len(nodes)+1)
 
-		out[0] = "ID|DC|Name|Class|"
+		out[0] = "ID|DC|Node Pool|Name|Class|"
 
 		if c.verbose {
 			out[0] += "Address|Version|"
@@ -194,9 +194,10 @@ func (c *NodeStatusCommand) Run(args []string) int {
 		}
 
 		for i, node := range nodes {
-			out[i+1] = fmt.Sprintf("%s|%s|%s|%s",
+			out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s",
 				limit(node.ID, c.length),
 				node.Datacenter,
+				node.NodePool,
 				node.Name,
 				node.NodeClass)
 			if c.verbose {
@@ -327,6 +328,7 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		fmt.Sprintf("ID|%s", limit(node.ID, c.length)),
 		fmt.Sprintf("Name|%s", node.Name),
 		fmt.Sprintf("Class|%s", node.NodeClass),
+		fmt.Sprintf("Node Pool|%s", node.NodePool),
 		fmt.Sprintf("DC|%s", node.Datacenter),
 		fmt.Sprintf("Drain|%v", formatDrain(node)),
 		fmt.Sprintf("Eligibility|%s", node.SchedulingEligibility),
@@ -605,10 +607,13 @@ func getAllocatedResources(client *api.Client, runningAllocs []*api.Allocation,
 	// Get Resources
 	var cpu, mem, disk, iops int
 	for _, alloc := range runningAllocs {
-		cpu += *alloc.Resources.CPU
-		mem += *alloc.Resources.MemoryMB
-		disk += *alloc.Resources.DiskMB
-		iops += *alloc.Resources.IOPS
+		if alloc.Resources == nil {
+			continue
+		}
+		cpu += intValue(alloc.Resources.CPU)
+		mem += intValue(alloc.Resources.MemoryMB)
+		disk += intValue(alloc.Resources.DiskMB)
+		iops += intValue(alloc.Resources.IOPS)
 	}
 
 	resources := make([]string, 2)
@@ -632,17 +637,28 @@ func computeNodeTotalResources(node *api.Node) api.Resources {
 	total := api.Resources{}
 
 	r := node.Resources
+	if r == nil {
+		r = &api.Resources{}
+	}
 	res := node.Reserved
 	if res == nil {
 		res = &api.Resources{}
 	}
-	total.CPU = helper.IntToPtr(*r.CPU - *res.CPU)
-	total.MemoryMB = helper.IntToPtr(*r.MemoryMB - *res.MemoryMB)
-	total.DiskMB = helper.IntToPtr(*r.DiskMB - *res.DiskMB)
-	total.IOPS = helper.IntToPtr(*r.IOPS - *res.IOPS)
+	total.CPU = helper.IntToPtr(intValue(r.CPU) - intValue(res.CPU))
+	total.MemoryMB = helper.IntToPtr(intValue(r.MemoryMB) - intValue(res.MemoryMB))
+	total.DiskMB = helper.IntToPtr(intValue(r.DiskMB) - intValue(res.DiskMB))
+	total.IOPS = helper.IntToPtr(intValue(r.IOPS) - intValue(res.IOPS))
 	return total
 }
 
+// intValue returns the value of the optional resource, or zero if unset.
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
 // getActualResources returns the actual resource usage of the allocations.
 func getActualResources(client *api.Client, runningAllocs []*api.Allocation, node *api.Node) ([]string, error) {
 	// Compute the total
@@ -658,8 +674,18 @@ func getActualResources(client *api.Client, runningAllocs []*api.Allocation, nod
 			return nil, err
 		}
 
-		cpu += stats.ResourceUsage.CpuStats.TotalTicks
-		mem += stats.ResourceUsage.MemoryStats.RSS
+		// Drivers may not report every statistic so skip missing ones
+		// rather than failing the whole status output
+		ru := stats.ResourceUsage
+		if ru == nil {
+			continue
+		}
+		if ru.CpuStats != nil {
+			cpu += ru.CpuStats.TotalTicks
+		}
+		if ru.MemoryStats != nil {
+			mem += ru.MemoryStats.RSS
+		}
 	}
 
 	resources := make([]string, 2)
@@ -732,11 +758,12 @@ func formatNodeStubList(nodes []*api.NodeListStub, verbose bool) string {
 	// Format the nodes list that matches the prefix so that the user
 	// can create a more specific request
 	out := make([]string, len(nodes)+1)
-	out[0] = "ID|DC|Name|Class|Drain|Eligibility|Status"
+	out[0] = "ID|DC|Node Pool|Name|Class|Drain|Eligibility|Status"
 	for i, node := range nodes {
-		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%v|%s|%s",
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%v|%s|%s",
 			limit(node.ID, length),
 			node.Datacenter,
+			node.NodePool,
 			node.Name,
 			node.NodeClass,
 			node.Drain,
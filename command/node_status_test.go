@@ -1,17 +1,22 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNodeStatusCommand_Implements(t *testing.T) {
@@ -276,3 +281,94 @@ func TestNodeStatusCommand_FormatDrain(t *testing.T) {
 	node.DrainStrategy.IgnoreSystemJobs = true
 	assert.Equal("true; 1970-01-01T00:00:01Z deadline; ignoring system jobs", formatDrain(node))
 }
+
+// testNodeStatusAPI serves the API endpoints used by the node status command
+// for a node whose client reports no resource usage.
+func testNodeStatusAPI(t *testing.T, node *api.Node, allocs []*api.Allocation) *httptest.Server {
+	stub := &api.NodeListStub{ID: node.ID, Name: node.Name, Status: node.Status}
+	encode := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("X-Nomad-Index", "1")
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			t.Errorf("failed to encode response: %v", err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/nodes", func(w http.ResponseWriter, r *http.Request) {
+		encode(w, []*api.NodeListStub{stub})
+	})
+	mux.HandleFunc("/v1/node/"+node.ID, func(w http.ResponseWriter, r *http.Request) {
+		encode(w, node)
+	})
+	mux.HandleFunc("/v1/node/"+node.ID+"/allocations", func(w http.ResponseWriter, r *http.Request) {
+		encode(w, allocs)
+	})
+	mux.HandleFunc("/v1/client/stats", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no host stats", http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/v1/client/allocation/", func(w http.ResponseWriter, r *http.Request) {
+		// The allocation's tasks report no resource usage
+		encode(w, &api.AllocResourceUsage{})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestNodeStatusCommand_NoResourceStats(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	node := &api.Node{
+		ID:     "6f0a4b2c-1111-2222-3333-444444444444",
+		Name:   "statless",
+		Status: "ready",
+		Resources: &api.Resources{
+			CPU:      helper.IntToPtr(1000),
+			MemoryMB: helper.IntToPtr(1024),
+			DiskMB:   helper.IntToPtr(1024),
+			IOPS:     helper.IntToPtr(0),
+		},
+	}
+
+	// A node without allocations
+	srv := testNodeStatusAPI(t, node, nil)
+	defer srv.Close()
+
+	ui := cli.NewMockUi()
+	cmd := &NodeStatusCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-address=" + srv.URL, node.ID}))
+	out := ui.OutputWriter.String()
+	require.Contains(out, "statless")
+	require.Contains(out, "Allocation Resource Utilization")
+	require.Contains(out, "0/1000 MHz")
+	require.Contains(out, "No allocations placed")
+
+	// A node whose running allocation reports no CPU or memory stats
+	allocs := []*api.Allocation{
+		{
+			ID:        "7a1b2c3d-1111-2222-3333-444444444444",
+			NodeID:    node.ID,
+			JobID:     "example",
+			TaskGroup: "cache",
+			Job: &api.Job{
+				ID:      helper.StringToPtr("example"),
+				Version: helper.Uint64ToPtr(0),
+			},
+			ClientStatus: "running",
+			Resources: &api.Resources{
+				CPU:      helper.IntToPtr(100),
+				MemoryMB: helper.IntToPtr(128),
+				DiskMB:   helper.IntToPtr(10),
+				IOPS:     helper.IntToPtr(0),
+			},
+		},
+	}
+	srv2 := testNodeStatusAPI(t, node, allocs)
+	defer srv2.Close()
+
+	ui = cli.NewMockUi()
+	cmd = &NodeStatusCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, cmd.Run([]string{"-address=" + srv2.URL, node.ID}))
+	out = ui.OutputWriter.String()
+	require.Contains(out, "Allocation Resource Utilization")
+	require.Contains(out, "0/1000 MHz  0 B/1.0 GiB")
+}
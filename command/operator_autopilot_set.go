@@ -75,7 +75,7 @@ func (c *OperatorAutopilotSetCommand) Run(args []string) int {
 	cleanupDeadServers.Merge(&conf.CleanupDeadServers)
 	enableRedundancyZones.Merge(&conf.EnableRedundancyZones)
 	disableUpgradeMigration.Merge(&conf.DisableUpgradeMigration)
-	enableRedundancyZones.Merge(&conf.EnableCustomUpgrades)
+	enableCustomUpgrades.Merge(&conf.EnableCustomUpgrades)
 
 	trailing := uint(conf.MaxTrailingLogs)
 	maxTrailingLogs.Merge(&trailing)
@@ -131,9 +131,10 @@ Set Config Options:
      Controls the maximum number of log entries that a server can trail
      the leader by before being considered unhealthy.
 
-  -redundancy-zone-tag=<value>
-     (Enterprise-only) Controls the node_meta tag name used for
-     separating servers into different redundancy zones.
+  -enable-redundancy-zones=[true|false]
+     Controls whether Nomad will separate servers into redundancy zones,
+     keeping only one voter in each of the zones and the other servers
+     as standbys. Must be one of [true|false].
 
   -server-stabilization-time=<10s>
      Controls the minimum amount of time a server must be stable in
@@ -141,10 +142,10 @@ Set Config Options:
      effect if all servers are running Raft protocol version 3 or
      higher. Must be a duration value such as "10s".
 
-  -upgrade-version-tag=<value>
-     (Enterprise-only) The node_meta tag to use for version info when
-     performing upgrade migrations. If left blank, the Nomad version
-     will be used.
+  -enable-custom-upgrades=[true|false]
+     (Enterprise-only) Controls whether Nomad will use the upgrade_version
+     of the servers for version info when performing upgrade migrations,
+     rather than the Nomad version. Must be one of [true|false].
 `
 	return strings.TrimSpace(helpText)
 }
@@ -26,6 +26,8 @@ func TestOperatorAutopilotSetConfigCommand(t *testing.T) {
 		"-max-trailing-logs=99",
 		"-last-contact-threshold=123ms",
 		"-server-stabilization-time=123ms",
+		"-enable-redundancy-zones=true",
+		"-enable-custom-upgrades=false",
 	}
 
 	code := c.Run(args)
@@ -59,4 +61,7 @@ func TestOperatorAutopilotSetConfigCommand(t *testing.T) {
 	if conf.ServerStabilizationTime != 123*time.Millisecond {
 		t.Fatalf("bad: %#v", conf)
 	}
+	if !conf.EnableRedundancyZones || conf.EnableCustomUpgrades {
+		t.Fatalf("bad: %#v", conf)
+	}
 }
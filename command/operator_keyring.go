@@ -17,7 +17,7 @@ type OperatorKeyringCommand struct {
 
 func (c *OperatorKeyringCommand) Help() string {
 	helpText := `
-Usage: nomad operator keyring [options]
+Usage: nomad operator keyring <subcommand> [options] [args]
 
   Manages encryption keys used for gossip messages between Nomad servers. Gossip
   encryption is optional. When enabled, this command may be used to examine
@@ -27,6 +27,18 @@ Usage: nomad operator keyring [options]
 
   All operations performed by this command can only be run against server nodes.
 
+  Rotating the gossip encryption key consists of installing a new key, making
+  it the primary key and removing the old key once all servers use the new
+  one:
+
+      $ nomad operator keyring install <new-key>
+      $ nomad operator keyring use <new-key>
+      $ nomad operator keyring remove <old-key>
+
+  List the installed keys:
+
+      $ nomad operator keyring list
+
   All variations of the keyring command return 0 if all nodes reply and there
   are no errors. If any node fails to reply or reports failure, the exit code
   will be 1.
@@ -37,6 +49,9 @@ General Options:
 
 Keyring Options:
 
+  The actions are also available as flags for backwards compatibility. Only a
+  single action may be given.
+
   -install=<key>            Install a new encryption key. This will broadcast
                             the new key to all members in the cluster.
   -list                     List all keys currently in use within the cluster.
@@ -85,6 +100,11 @@ func (c *OperatorKeyringCommand) Run(args []string) int {
 		return 1
 	}
 
+	// Without any of the legacy flags list the subcommands
+	if len(args) == 0 {
+		return cli.RunResultHelp
+	}
+
 	c.Ui = &cli.PrefixedUi{
 		OutputPrefix: "",
 		InfoPrefix:   "==> ",
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+// OperatorKeyringInstallCommand is a Command implementation that installs a new gossip
+// encryption key in the keyrings of all servers.
+type OperatorKeyringInstallCommand struct {
+	Meta
+}
+
+func (c *OperatorKeyringInstallCommand) Help() string {
+	helpText := `
+Usage: nomad operator keyring install [options] <key>
+
+  Install a new encryption key used for gossip messages between Nomad servers.
+  The key is broadcast to all members of the cluster but is not used to
+  encrypt messages until it is made the primary key with the "use" command.
+
+  This command can only be run against server nodes. It returns 0 if all nodes
+  reply and there are no errors. If any node fails to reply or reports failure,
+  the exit code will be 1.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorKeyringInstallCommand) Synopsis() string {
+	return "Install a gossip encryption key"
+}
+
+func (c *OperatorKeyringInstallCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorKeyringInstallCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *OperatorKeyringInstallCommand) Name() string { return "operator keyring install" }
+
+func (c *OperatorKeyringInstallCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one key
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <key>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating nomad cli client: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("==> Installing new gossip encryption key...")
+	if _, err := client.Agent().InstallKey(args[0]); err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+	return 0
+}
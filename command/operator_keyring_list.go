@@ -0,0 +1,84 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+// OperatorKeyringListCommand is a Command implementation that lists the gossip
+// encryption keys installed on the servers.
+type OperatorKeyringListCommand struct {
+	Meta
+}
+
+func (c *OperatorKeyringListCommand) Help() string {
+	helpText := `
+Usage: nomad operator keyring list [options]
+
+  List the encryption keys used for gossip messages that are currently
+  installed on the Nomad servers of the cluster.
+
+  This command can only be run against server nodes. It returns 0 if all nodes
+  reply and there are no errors. If any node fails to reply or reports failure,
+  the exit code will be 1.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorKeyringListCommand) Synopsis() string {
+	return "List the installed gossip encryption keys"
+}
+
+func (c *OperatorKeyringListCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorKeyringListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorKeyringListCommand) Name() string { return "operator keyring list" }
+
+func (c *OperatorKeyringListCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating nomad cli client: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("==> Gathering installed encryption keys...")
+	r, err := client.Agent().ListKeys()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+
+	keys := make([]string, 0, len(r.Keys))
+	for k := range r.Keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := append([]string{"Key"}, keys...)
+	c.Ui.Output(formatList(out))
+	return 0
+}
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+// OperatorKeyringRemoveCommand is a Command implementation that removes a gossip encryption
+// key from the keyrings of all servers.
+type OperatorKeyringRemoveCommand struct {
+	Meta
+}
+
+func (c *OperatorKeyringRemoveCommand) Help() string {
+	helpText := `
+Usage: nomad operator keyring remove [options] <key>
+
+  Remove the given encryption key from the keyrings of all Nomad servers. This
+  operation may only be performed on keys which are not currently the primary
+  key.
+
+  This command can only be run against server nodes. It returns 0 if all nodes
+  reply and there are no errors. If any node fails to reply or reports failure,
+  the exit code will be 1.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorKeyringRemoveCommand) Synopsis() string {
+	return "Remove a gossip encryption key"
+}
+
+func (c *OperatorKeyringRemoveCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorKeyringRemoveCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *OperatorKeyringRemoveCommand) Name() string { return "operator keyring remove" }
+
+func (c *OperatorKeyringRemoveCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one key
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <key>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating nomad cli client: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("==> Removing gossip encryption key...")
+	if _, err := client.Agent().RemoveKey(args[0]); err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+	return 0
+}
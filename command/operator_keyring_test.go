@@ -0,0 +1,86 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorKeyringCommands_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorKeyringCommand{}
+	var _ cli.Command = &OperatorKeyringInstallCommand{}
+	var _ cli.Command = &OperatorKeyringListCommand{}
+	var _ cli.Command = &OperatorKeyringRemoveCommand{}
+	var _ cli.Command = &OperatorKeyringUseCommand{}
+}
+
+func TestOperatorKeyringCommands_Fails(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	ui := new(cli.MockUi)
+
+	// Fails on misuse
+	install := &OperatorKeyringInstallCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, install.Run([]string{}))
+	require.Contains(ui.ErrorWriter.String(), "This command takes one argument")
+	ui.ErrorWriter.Reset()
+
+	list := &OperatorKeyringListCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, list.Run([]string{"some"}))
+	require.Contains(ui.ErrorWriter.String(), "This command takes no arguments")
+	ui.ErrorWriter.Reset()
+
+	// Fails on connection failure
+	remove := &OperatorKeyringRemoveCommand{Meta: Meta{Ui: ui}}
+	require.Equal(1, remove.Run([]string{"-address=nope", "key"}))
+	require.Contains(ui.ErrorWriter.String(), "error:")
+	ui.ErrorWriter.Reset()
+
+	// The parent command without flags lists the subcommands
+	keyring := &OperatorKeyringCommand{Meta: Meta{Ui: ui}}
+	require.Equal(cli.RunResultHelp, keyring.Run([]string{}))
+}
+
+func TestOperatorKeyringCommands_Rotate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	const (
+		oldKey = "HS5lJ+XuTlYKWaeGYyG+/A=="
+		newKey = "UXYc+Di7Pm9+2JGswc6rmQ=="
+	)
+	srv, _, url := testServer(t, false, func(c *agent.Config) {
+		c.Server.EncryptKey = oldKey
+	})
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	list := &OperatorKeyringListCommand{Meta: Meta{Ui: ui}}
+	install := &OperatorKeyringInstallCommand{Meta: Meta{Ui: ui}}
+	use := &OperatorKeyringUseCommand{Meta: Meta{Ui: ui}}
+	remove := &OperatorKeyringRemoveCommand{Meta: Meta{Ui: ui}}
+
+	require.Equal(0, list.Run([]string{"-address=" + url}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), oldKey)
+	require.NotContains(ui.OutputWriter.String(), newKey)
+	ui.OutputWriter.Reset()
+
+	// Install the new key and make it the primary key
+	require.Equal(0, install.Run([]string{"-address=" + url, newKey}), ui.ErrorWriter.String())
+	require.Equal(0, use.Run([]string{"-address=" + url, newKey}), ui.ErrorWriter.String())
+
+	// The primary key can't be removed
+	require.Equal(1, remove.Run([]string{"-address=" + url, newKey}))
+	ui.ErrorWriter.Reset()
+
+	// Remove the old key
+	require.Equal(0, remove.Run([]string{"-address=" + url, oldKey}), ui.ErrorWriter.String())
+	ui.OutputWriter.Reset()
+
+	require.Equal(0, list.Run([]string{"-address=" + url}), ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), newKey)
+	require.NotContains(ui.OutputWriter.String(), oldKey)
+}
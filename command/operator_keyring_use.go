@@ -0,0 +1,75 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+// OperatorKeyringUseCommand is a Command implementation that changes the primary gossip
+// encryption key of all servers.
+type OperatorKeyringUseCommand struct {
+	Meta
+}
+
+func (c *OperatorKeyringUseCommand) Help() string {
+	helpText := `
+Usage: nomad operator keyring use [options] <key>
+
+  Change the primary encryption key used to encrypt gossip messages between
+  Nomad servers. The key must already be installed with the "install" command
+  before this operation can succeed.
+
+  This command can only be run against server nodes. It returns 0 if all nodes
+  reply and there are no errors. If any node fails to reply or reports failure,
+  the exit code will be 1.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorKeyringUseCommand) Synopsis() string {
+	return "Change the primary gossip encryption key"
+}
+
+func (c *OperatorKeyringUseCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorKeyringUseCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictAnything
+}
+
+func (c *OperatorKeyringUseCommand) Name() string { return "operator keyring use" }
+
+func (c *OperatorKeyringUseCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one key
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <key>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating nomad cli client: %s", err))
+		return 1
+	}
+
+	c.Ui.Output("==> Changing primary gossip encryption key...")
+	if _, err := client.Agent().UseKey(args[0]); err != nil {
+		c.Ui.Error(fmt.Sprintf("error: %s", err))
+		return 1
+	}
+	return 0
+}
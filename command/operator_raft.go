@@ -26,6 +26,10 @@ Usage: nomad operator raft <subcommand> [options]
 
       $ nomad operator raft remove-peer -peer-address "IP:Port"
 
+  Make the leader step down so another server is elected:
+
+      $ nomad operator raft transfer-leadership
+
   Please see the individual subcommand help for detailed usage information.
 `
 	return strings.TrimSpace(helpText)
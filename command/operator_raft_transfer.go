@@ -0,0 +1,112 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/posener/complete"
+)
+
+type OperatorRaftTransferCommand struct {
+	Meta
+}
+
+func (c *OperatorRaftTransferCommand) Help() string {
+	helpText := `
+Usage: nomad operator raft transfer-leadership [options]
+
+  Make the current leader step down so that another Nomad server is elected
+  leader. This can be used to move the leadership away from a server before
+  maintenance, or from a server in a degraded state.
+
+  The leadership isn't transferred to a chosen server: the leader steps down
+  by demoting itself to a non-voter and any other voter may be elected. There
+  must be another healthy voter in the cluster, and all the servers must use
+  Raft protocol version 3 or higher. Autopilot promotes the former leader back
+  to a voter once it has been stable for the server stabilization time.
+
+  Until then the cluster has one less voter, so it tolerates one less server
+  failure. For example a cluster of three servers loses its quorum if another
+  server fails before the former leader is promoted back.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Transfer Leadership Options:
+
+  -wait=<duration>
+    How long to wait for a new leader to be elected. Defaults to 10s.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRaftTransferCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-wait": complete.PredictAnything,
+		})
+}
+
+func (c *OperatorRaftTransferCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRaftTransferCommand) Synopsis() string {
+	return "Make the leader step down so another server is elected"
+}
+
+func (c *OperatorRaftTransferCommand) Name() string { return "operator raft transfer-leadership" }
+
+func (c *OperatorRaftTransferCommand) Run(args []string) int {
+	var wait time.Duration
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.DurationVar(&wait, "wait", 10*time.Second, "")
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Check for misuse
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	leader, err := client.Status().Leader()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying leader: %s", err))
+		return 1
+	}
+
+	if err := client.Operator().RaftTransferLeadership(nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error transferring leadership: %s", err))
+		return 1
+	}
+
+	// Wait for another server to be elected
+	deadline := time.Now().Add(wait)
+	for {
+		newLeader, err := client.Status().Leader()
+		if err == nil && newLeader != "" && newLeader != leader {
+			c.Ui.Output(fmt.Sprintf("Transferred leadership from %s to %s", leader, newLeader))
+			return 0
+		}
+		if time.Now().After(deadline) {
+			c.Ui.Error(fmt.Sprintf("Leader %s stepped down, but no new leader was elected within %s", leader, wait))
+			return 1
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
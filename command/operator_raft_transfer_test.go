@@ -0,0 +1,34 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperator_Raft_TransferLeadership_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorRaftTransferCommand{}
+}
+
+func TestOperator_Raft_TransferLeadership(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s, _, addr := testServer(t, false, nil)
+	defer s.Shutdown()
+
+	ui := new(cli.MockUi)
+	c := &OperatorRaftTransferCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := c.Run([]string{"-address=" + addr, "extra"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(c))
+	ui.ErrorWriter.Reset()
+
+	// A single server has no other voter to transfer to
+	code = c.Run([]string{"-address=" + addr})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Error transferring leadership")
+}
@@ -0,0 +1,41 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorRootCommand struct {
+	Meta
+}
+
+func (c *OperatorRootCommand) Help() string {
+	helpText := `
+Usage: nomad operator root <subcommand> [options]
+
+  This command groups subcommands for interacting with the root keys the
+  servers use to sign workload identities and encrypt variables.
+
+  List the root keys:
+
+      $ nomad operator root keyring list
+
+  Rotate the root key:
+
+      $ nomad operator root keyring rotate
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootCommand) Synopsis() string {
+	return "Provides access to the root keyring"
+}
+
+func (c *OperatorRootCommand) Name() string { return "operator root" }
+
+func (c *OperatorRootCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorRootKeyringCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring <subcommand> [options]
+
+  Manages the root keys used by the servers to sign workload identities and
+  encrypt variables. Rotating the root key makes a new key active. The previous
+  keys are kept to verify the identities and decrypt the variables they signed
+  and encrypted.
+
+  List the root keys:
+
+      $ nomad operator root keyring list
+
+  Rotate the root key and re-encrypt all variables with the new key:
+
+      $ nomad operator root keyring rotate -full
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringCommand) Synopsis() string {
+	return "Manages root encryption keys"
+}
+
+func (c *OperatorRootKeyringCommand) Name() string { return "operator root keyring" }
+
+func (c *OperatorRootKeyringCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,113 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type OperatorRootKeyringListCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringListCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring list [options]
+
+  List the root keys known to the servers. Key material is never displayed.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+List Options:
+
+  -json
+    Output the root keys in a JSON format.
+
+  -t
+    Format and display the root keys using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *OperatorRootKeyringListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRootKeyringListCommand) Synopsis() string {
+	return "List the root keys"
+}
+
+func (c *OperatorRootKeyringListCommand) Name() string { return "operator root keyring list" }
+
+func (c *OperatorRootKeyringListCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if l := len(flags.Args()); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	keys, _, err := client.Operator().KeyringList(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing root keys: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, keys)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatRootKeys(keys))
+	return 0
+}
+
+func formatRootKeys(keys []*api.RootKeyMeta) string {
+	if len(keys) == 0 {
+		return "No root keys found"
+	}
+
+	output := make([]string, 0, len(keys)+1)
+	output = append(output, "Key|Algorithm|Active|Create Time")
+	for _, k := range keys {
+		output = append(output, fmt.Sprintf("%s|%s|%v|%s",
+			k.KeyID, k.Algorithm, k.Active, formatUnixNanoTime(k.CreateTime)))
+	}
+	return formatList(output)
+}
@@ -0,0 +1,82 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type OperatorRootKeyringRotateCommand struct {
+	Meta
+}
+
+func (c *OperatorRootKeyringRotateCommand) Help() string {
+	helpText := `
+Usage: nomad operator root keyring rotate [options]
+
+  Generate a new root key and make it the active key. New workload identities
+  are signed and new variables are encrypted with the new key.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Rotate Options:
+
+  -full
+    Re-encrypt all existing variables with the new key.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRootKeyringRotateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-full": complete.PredictNothing,
+		})
+}
+
+func (c *OperatorRootKeyringRotateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorRootKeyringRotateCommand) Synopsis() string {
+	return "Rotate the root key"
+}
+
+func (c *OperatorRootKeyringRotateCommand) Name() string { return "operator root keyring rotate" }
+
+func (c *OperatorRootKeyringRotateCommand) Run(args []string) int {
+	var full bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&full, "full", false, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if l := len(flags.Args()); l != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	key, _, err := client.Operator().KeyringRotate(full, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error rotating root key: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Rotated root key to %s", key.KeyID))
+	return 0
+}
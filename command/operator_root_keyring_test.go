@@ -0,0 +1,34 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorRootKeyringCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorRootKeyringListCommand{}
+	var _ cli.Command = &OperatorRootKeyringRotateCommand{}
+}
+
+func TestOperatorRootKeyringCommand_Rotate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	rotate := &OperatorRootKeyringRotateCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, rotate.Run([]string{"-address=" + url, "-full"}))
+	require.Contains(ui.OutputWriter.String(), "Rotated root key")
+	ui.OutputWriter.Reset()
+
+	list := &OperatorRootKeyringListCommand{Meta: Meta{Ui: ui}}
+	require.Equal(0, list.Run([]string{"-address=" + url}))
+	out := ui.OutputWriter.String()
+	require.Contains(out, "true")
+	require.Contains(out, "false")
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/command/snapshotagent"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type OperatorSnapshotAgentCommand struct {
+	Meta
+}
+
+func (c *OperatorSnapshotAgentCommand) Help() string {
+	helpText := `
+Usage: nomad operator snapshot agent [options] <config-file>
+
+  Starts a process that takes snapshots of the state of the Nomad servers on
+  an interval, and saves them to a local directory or to object storage (Amazon
+  S3, Azure Blob Storage or Google Cloud Storage) for disaster recovery. Only
+  the configured number of most recent snapshots is retained.
+
+  Every snapshot is downloaded to a local scratch file and verified before
+  being stored. Snapshots stored in Amazon S3 can be encrypted with server side
+  encryption, using an AWS KMS key if given.
+
+  If ACLs are enabled, a management token must be supplied in order to perform
+  snapshot operations.
+
+  The agent is configured with an HCL file:
+
+    snapshot {
+      interval = "1h"
+      retain   = 30
+      stale    = false
+      prefix   = "nomad"
+    }
+
+    aws_storage {
+      s3_region                 = "us-east-1"
+      s3_bucket                 = "nomad-backups"
+      s3_key_prefix             = "snapshots"
+      s3_server_side_encryption = true
+    }
+
+  The storage is one of the local_storage, aws_storage, azure_blob_storage or
+  google_storage blocks. Please see the documentation for the full list of
+  options.
+
+General Options:
+
+  ` + generalOptionsUsage()
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSnapshotAgentCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorSnapshotAgentCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorSnapshotAgentCommand) Synopsis() string {
+	return "Periodically saves snapshots of Nomad server state"
+}
+
+func (c *OperatorSnapshotAgentCommand) Name() string { return "operator snapshot agent" }
+
+func (c *OperatorSnapshotAgentCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Check for misuse
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <config-file>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	config, err := snapshotagent.ParseConfigFile(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading configuration: %s", err))
+		return 1
+	}
+
+	// Set up a client.
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	logger := log.New(&cli.UiWriter{Ui: c.Ui}, "", log.LstdFlags)
+	agent, err := snapshotagent.NewAgent(config, client, logger)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error starting snapshot agent: %s", err))
+		return 1
+	}
+
+	// Run until interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+	go func() {
+		<-signalCh
+		cancel()
+	}()
+
+	agent.Run(ctx)
+	return 0
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotAgent_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorSnapshotAgentCommand{}
+}
+
+func TestOperatorSnapshotAgent_Fails(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "nomad-tempdir")
+	require.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSnapshotAgentCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing configuration file
+	code = cmd.Run([]string{filepath.Join(tmpDir, "missing.hcl")})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Error loading configuration")
+	ui.ErrorWriter.Reset()
+
+	// Fails on a configuration without storage
+	configPath := filepath.Join(tmpDir, "agent.hcl")
+	require.NoError(ioutil.WriteFile(configPath, []byte(`snapshot { retain = 2 }`), 0600))
+	code = cmd.Run([]string{configPath})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "exactly one storage must be configured")
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotInspect_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorSnapshotInspectCommand{}
+}
+
+func TestOperatorSnapshotInspect_Works(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	snapPath := generateSnapshotFile(t)
+	defer os.RemoveAll(filepath.Dir(snapPath))
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSnapshotInspectCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{snapPath})
+	require.Equal(0, code, ui.ErrorWriter.String())
+
+	output := ui.OutputWriter.String()
+	for _, key := range []string{"ID", "Size", "Index", "Term", "Version"} {
+		require.Contains(output, key)
+	}
+}
+
+func TestOperatorSnapshotInspect_HandlesFailure(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "nomad-tempdir")
+	require.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSnapshotInspectCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing file
+	code = cmd.Run([]string{filepath.Join(tmpDir, "missing.snap")})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Error opening snapshot file")
+	ui.ErrorWriter.Reset()
+
+	// Fails on a file that isn't a snapshot
+	badPath := filepath.Join(tmpDir, "bad.snap")
+	require.NoError(ioutil.WriteFile(badPath, []byte("not a snapshot"), 0600))
+	code = cmd.Run([]string{badPath})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Error verifying snapshot")
+}
+
+// generateSnapshotFile saves a snapshot of a test server into a temporary
+// directory and returns its path
+func generateSnapshotFile(t *testing.T) string {
+	tmpDir, err := ioutil.TempDir("", "nomad-tempdir")
+	require.NoError(t, err)
+
+	srv, api, _ := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	snap, err := api.Operator().Snapshot(nil)
+	require.NoError(t, err)
+	defer snap.Close()
+
+	data, err := ioutil.ReadAll(snap)
+	require.NoError(t, err)
+
+	dest := filepath.Join(tmpDir, "backup.snap")
+	require.NoError(t, ioutil.WriteFile(dest, data, 0600))
+	return dest
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotRestore_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorSnapshotRestoreCommand{}
+}
+
+func TestOperatorSnapshotRestore_Works(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	snapPath := generateSnapshotFile(t)
+	defer os.RemoveAll(filepath.Dir(snapPath))
+
+	srv, client, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	// Register a job which isn't part of the snapshot
+	_, _, err := client.Jobs().Register(testJob("snapshot-test-job"), nil)
+	require.NoError(err)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSnapshotRestoreCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"--address=" + url, snapPath})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Snapshot Restored")
+
+	// The job is gone after the restore
+	_, _, err = client.Jobs().Info("snapshot-test-job", nil)
+	require.Error(err)
+	require.Contains(err.Error(), "not found")
+}
+
+func TestOperatorSnapshotRestore_Fails(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSnapshotRestoreCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails on a missing file
+	code = cmd.Run([]string{"/nope/backup.snap"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Error opening snapshot file")
+}
@@ -0,0 +1,147 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/posener/complete"
+)
+
+type OperatorSnapshotSaveCommand struct {
+	Meta
+}
+
+func (c *OperatorSnapshotSaveCommand) Help() string {
+	helpText := `
+Usage: nomad operator snapshot save [options] <file>
+
+  Retrieves an atomic, point-in-time snapshot of the state of the Nomad servers
+  which includes jobs, nodes, allocations, periodic jobs, and ACLs.
+
+  If ACLs are enabled, a management token must be supplied in order to perform
+  snapshot operations.
+
+  To create a snapshot from the leader server and save it to "backup.snap":
+
+    $ nomad operator snapshot save backup.snap
+
+  To create a potentially stale snapshot from any available server (useful if no
+  leader is available):
+
+    $ nomad operator snapshot save -stale backup.snap
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Snapshot Save Options:
+
+  -stale=[true|false]
+    The -stale argument defaults to "false" which means the leader provides the
+    result. If the cluster is in an outage state without a leader, you may need
+    to set -stale to "true" to get the snapshot from a non-leader server.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSnapshotSaveCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-stale": complete.PredictNothing,
+		})
+}
+
+func (c *OperatorSnapshotSaveCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorSnapshotSaveCommand) Synopsis() string {
+	return "Saves snapshot of Nomad server state"
+}
+
+func (c *OperatorSnapshotSaveCommand) Name() string { return "operator snapshot save" }
+
+func (c *OperatorSnapshotSaveCommand) Run(args []string) int {
+	var stale bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&stale, "stale", false, "")
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+
+	// Check for misuse
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <file>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	path := args[0]
+
+	// Set up a client.
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Take the snapshot.
+	q := &api.QueryOptions{
+		AllowStale: stale,
+	}
+	snap, err := client.Operator().Snapshot(q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying snapshot: %s", err))
+		return 1
+	}
+	defer snap.Close()
+
+	// Save the file to a temporary location first, so a failed save doesn't
+	// leave a partial snapshot behind.
+	tmpFile := path + ".tmp"
+	f, err := os.Create(tmpFile)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing snapshot file: %s", err))
+		return 1
+	}
+	defer os.Remove(tmpFile)
+
+	// The checksum of the snapshot is validated while it is copied.
+	if _, err := io.Copy(f, snap); err != nil {
+		f.Close()
+		c.Ui.Error(fmt.Sprintf("Error writing snapshot file: %s", err))
+		return 1
+	}
+	if err := f.Close(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error closing snapshot file: %s", err))
+		return 1
+	}
+
+	// Read it back to verify.
+	f, err = os.Open(tmpFile)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot file for verify: %s", err))
+		return 1
+	}
+	meta, err := snapshot.Verify(f)
+	f.Close()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error verifying snapshot file: %s", err))
+		return 1
+	}
+
+	if err := os.Rename(tmpFile, path); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error writing snapshot file: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Saved snapshot to %q at index %d", path, meta.Index))
+	return 0
+}
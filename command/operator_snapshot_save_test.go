@@ -0,0 +1,68 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperatorSnapshotSave_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &OperatorSnapshotSaveCommand{}
+}
+
+func TestOperatorSnapshotSave_Works(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	tmpDir, err := ioutil.TempDir("", "nomad-tempdir")
+	require.NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSnapshotSaveCommand{Meta: Meta{Ui: ui}}
+
+	dest := filepath.Join(tmpDir, "backup.snap")
+	code := cmd.Run([]string{"--address=" + url, dest})
+	require.Equal(0, code, ui.ErrorWriter.String())
+	require.Contains(ui.OutputWriter.String(), "Saved snapshot to")
+
+	f, err := os.Open(dest)
+	require.NoError(err)
+	defer f.Close()
+
+	meta, err := snapshot.Verify(f)
+	require.NoError(err)
+	require.NotZero(meta.Index)
+
+	// No partial file is left behind
+	_, err = os.Stat(dest + ".tmp")
+	require.True(os.IsNotExist(err))
+}
+
+func TestOperatorSnapshotSave_Fails(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ui := new(cli.MockUi)
+	cmd := &OperatorSnapshotSaveCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	// Fails when specified address can't be reached
+	code = cmd.Run([]string{"-address=http://nope", "backup.snap"})
+	require.Equal(1, code)
+	require.Contains(ui.ErrorWriter.String(), "Error querying snapshot")
+}
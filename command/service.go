@@ -0,0 +1,46 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type ServiceCommand struct {
+	Meta
+}
+
+func (c *ServiceCommand) Help() string {
+	helpText := `
+Usage: nomad service <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with the services
+  registered in Nomad. Only the services using the nomad provider are
+  registered in Nomad, the other services are registered in Consul.
+
+  List the registered services:
+
+      $ nomad service list
+
+  Display the registered instances of a service:
+
+      $ nomad service info <service_name>
+
+  Delete a registration of a service:
+
+      $ nomad service delete <service_name> <service_id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ServiceCommand) Synopsis() string {
+	return "Interact with registered services"
+}
+
+func (c *ServiceCommand) Name() string { return "service" }
+
+func (c *ServiceCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ServiceDeleteCommand struct {
+	Meta
+}
+
+func (c *ServiceDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad service delete [options] <service_name> <service_id>
+
+  Delete is used to remove a registration of a service. The registration is
+  recreated by the client running the allocation if it is still running.
+
+General Options:
+
+  ` + generalOptionsUsage()
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ServiceDeleteCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{})
+}
+
+func (c *ServiceDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServiceDeleteCommand) Synopsis() string {
+	return "Delete a registration of a service"
+}
+
+func (c *ServiceDeleteCommand) Name() string { return "service delete" }
+
+func (c *ServiceDeleteCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly two arguments
+	args = flags.Args()
+	if l := len(args); l != 2 {
+		c.Ui.Error("This command takes two arguments: <service_name> <service_id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name, id := args[0], args[1]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.Services().Delete(name, id, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting service registration: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted service registration %q!", id))
+	return 0
+}
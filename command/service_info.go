@@ -0,0 +1,143 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ServiceInfoCommand struct {
+	Meta
+}
+
+func (c *ServiceInfoCommand) Help() string {
+	helpText := `
+Usage: nomad service info [options] <service_name>
+
+  Info is used to display the registered instances of a service, with the
+  status of their checks.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+Info Options:
+
+  -verbose
+    Display full information.
+
+  -json
+    Output the service instances in a JSON format.
+
+  -t
+    Format and display the service instances using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ServiceInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-verbose": complete.PredictNothing,
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+		})
+}
+
+func (c *ServiceInfoCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServiceInfoCommand) Synopsis() string {
+	return "Display the instances of a registered service"
+}
+
+func (c *ServiceInfoCommand) Name() string { return "service info" }
+
+func (c *ServiceInfoCommand) Run(args []string) int {
+	var verbose, json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&verbose, "verbose", false, "")
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <service_name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	// Truncate the id unless full length is requested
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	instances, _, err := client.Services().Get(name, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving service: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, instances)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(instances) == 0 {
+		c.Ui.Error(fmt.Sprintf("No instances of service %q found", name))
+		return 1
+	}
+
+	c.Ui.Output(formatServiceInstances(instances, length))
+	return 0
+}
+
+func formatServiceInstances(instances []*api.ServiceRegistration, length int) string {
+	output := make([]string, 0, len(instances)+1)
+	output = append(output, "ID|Node ID|Alloc ID|Address|Tags|Checks")
+	for _, s := range instances {
+		output = append(output, fmt.Sprintf("%s|%s|%s|%s:%d|[%s]|%s",
+			s.ID, limit(s.NodeID, length), limit(s.AllocID, length),
+			s.Address, s.Port, strings.Join(s.Tags, ","), formatServiceChecks(s.Checks)))
+	}
+
+	return formatList(output)
+}
+
+func formatServiceChecks(checks []*api.ServiceRegistrationCheck) string {
+	if len(checks) == 0 {
+		return "<none>"
+	}
+
+	statuses := make([]string, 0, len(checks))
+	for _, check := range checks {
+		statuses = append(statuses, fmt.Sprintf("%s=%s", check.Name, check.Status))
+	}
+	return strings.Join(statuses, ",")
+}
@@ -0,0 +1,116 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type ServiceListCommand struct {
+	Meta
+}
+
+func (c *ServiceListCommand) Help() string {
+	helpText := `
+Usage: nomad service list [options]
+
+  List is used to list the services registered in Nomad with the tags of
+  their instances.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+List Options:
+
+  -json
+    Output the services in a JSON format.
+
+  -t
+    Format and display the services using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ServiceListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *ServiceListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ServiceListCommand) Synopsis() string {
+	return "List registered services"
+}
+
+func (c *ServiceListCommand) Name() string { return "service list" }
+
+func (c *ServiceListCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	services, _, err := client.Services().List(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing services: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, services)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatServices(services))
+	return 0
+}
+
+func formatServices(services []*api.ServiceRegistrationListStub) string {
+	if len(services) == 0 {
+		return "No services found"
+	}
+
+	output := make([]string, 0, len(services)+1)
+	output = append(output, "Service Name|Tags")
+	for _, s := range services {
+		output = append(output, fmt.Sprintf("%s|[%s]",
+			s.ServiceName, strings.Join(s.Tags, ",")))
+	}
+
+	return formatList(output)
+}
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &ServiceListCommand{}
+	var _ cli.Command = &ServiceInfoCommand{}
+	var _ cli.Command = &ServiceDeleteCommand{}
+}
+
+func TestServiceCommand_Run(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	meta := Meta{Ui: ui, flagAddress: url}
+
+	list := &ServiceListCommand{Meta: meta}
+	require.Equal(0, list.Run([]string{"-address=" + url}))
+	require.Contains(ui.OutputWriter.String(), "No services found")
+	ui.OutputWriter.Reset()
+
+	services := mock.ServiceRegistrations()
+	state := srv.Agent.Server().State()
+	require.NoError(state.UpsertServiceRegistrations(1000, services))
+
+	// List the services
+	require.Equal(0, list.Run([]string{"-address=" + url}))
+	out := ui.OutputWriter.String()
+	require.Contains(out, services[0].ServiceName)
+	require.Contains(out, services[1].ServiceName)
+	ui.OutputWriter.Reset()
+
+	// Display the instances of a service
+	info := &ServiceInfoCommand{Meta: meta}
+	require.Equal(0, info.Run([]string{"-address=" + url, services[1].ServiceName}))
+	out = ui.OutputWriter.String()
+	require.Contains(out, services[1].ID)
+	require.Contains(out, "alive=passing")
+	ui.OutputWriter.Reset()
+
+	// Delete it
+	del := &ServiceDeleteCommand{Meta: meta}
+	require.Equal(0, del.Run([]string{"-address=" + url, services[1].ServiceName, services[1].ID}))
+	require.Contains(ui.OutputWriter.String(), "Successfully deleted")
+	ui.OutputWriter.Reset()
+
+	require.Equal(1, info.Run([]string{"-address=" + url, services[1].ServiceName}))
+	require.Contains(ui.ErrorWriter.String(), "No instances")
+	ui.ErrorWriter.Reset()
+
+	// Arguments are checked
+	require.Equal(1, del.Run([]string{"-address=" + url, services[0].ServiceName}))
+	require.Contains(ui.ErrorWriter.String(), "two arguments")
+}
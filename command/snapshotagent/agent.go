@@ -0,0 +1,145 @@
+// Package snapshotagent implements an agent taking periodic snapshots of the
+// state of the Nomad servers, and keeping a number of them in a local
+// directory or in object storage for disaster recovery.
+package snapshotagent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper/snapshot"
+)
+
+const (
+	// snapshotSuffix is the suffix of the names of the snapshots
+	snapshotSuffix = ".snap"
+)
+
+// Agent takes the snapshots of the cluster on an interval and stores them
+type Agent struct {
+	config  *Config
+	client  *api.Client
+	storage Storage
+	logger  *log.Logger
+}
+
+// NewAgent returns an agent taking the snapshots through the client and
+// storing them in the storage of the configuration
+func NewAgent(config *Config, client *api.Client, logger *log.Logger) (*Agent, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	storage, err := NewStorage(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up snapshot storage: %v", err)
+	}
+
+	return &Agent{
+		config:  config,
+		client:  client,
+		storage: storage,
+		logger:  logger,
+	}, nil
+}
+
+// Run takes a snapshot right away and then on every interval until the
+// context is done. Failures are logged and retried on the next interval.
+func (a *Agent) Run(ctx context.Context) {
+	a.logger.Printf("[INFO] snapshot: saving snapshots every %s to %s", a.config.Snapshot.Interval, a.storage)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		if name, err := a.Snapshot(); err != nil {
+			a.logger.Printf("[ERR] snapshot: %v", err)
+		} else {
+			a.logger.Printf("[INFO] snapshot: saved snapshot %q", name)
+		}
+		timer.Reset(a.config.Snapshot.Interval)
+	}
+}
+
+// Snapshot takes a snapshot, stores it and deletes the snapshots beyond the
+// number to retain. It returns the name of the new snapshot.
+func (a *Agent) Snapshot() (string, error) {
+	// Download the snapshot to a scratch file, verifying its checksum, so
+	// that it's never stored partially or corrupted
+	scratch, err := ioutil.TempFile(a.config.Snapshot.LocalScratchPath, "nomad-snapshot")
+	if err != nil {
+		return "", fmt.Errorf("failed to create scratch file: %v", err)
+	}
+	defer func() {
+		scratch.Close()
+		os.Remove(scratch.Name())
+	}()
+
+	q := &api.QueryOptions{AllowStale: a.config.Snapshot.Stale}
+	snap, err := a.client.Operator().Snapshot(q)
+	if err != nil {
+		return "", fmt.Errorf("failed to take snapshot: %v", err)
+	}
+	size, err := io.Copy(scratch, snap)
+	snap.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to download snapshot: %v", err)
+	}
+
+	if _, err := scratch.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind snapshot: %v", err)
+	}
+	if _, err := snapshot.Verify(scratch); err != nil {
+		return "", fmt.Errorf("failed to verify snapshot: %v", err)
+	}
+	if _, err := scratch.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind snapshot: %v", err)
+	}
+
+	// The names sort in the order of the snapshots, which the retention
+	// relies on
+	name := fmt.Sprintf("%s-%d%s", a.config.Snapshot.Prefix, time.Now().UnixNano()/int64(time.Millisecond), snapshotSuffix)
+	if err := a.storage.Store(name, scratch, size); err != nil {
+		return "", fmt.Errorf("failed to store snapshot in %s: %v", a.storage, err)
+	}
+
+	if err := a.prune(); err != nil {
+		return name, fmt.Errorf("failed to delete old snapshots: %v", err)
+	}
+	return name, nil
+}
+
+// prune deletes the oldest snapshots beyond the number to retain
+func (a *Agent) prune() error {
+	retain := a.config.Snapshot.Retain
+	if retain == 0 {
+		return nil
+	}
+
+	names, err := a.storage.List(a.config.Snapshot.Prefix + "-")
+	if err != nil {
+		return err
+	}
+	if len(names) <= retain {
+		return nil
+	}
+
+	for _, name := range names[:len(names)-retain] {
+		if err := a.storage.Delete(name); err != nil {
+			return err
+		}
+		a.logger.Printf("[DEBUG] snapshot: deleted old snapshot %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package snapshotagent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func testAgent(t *testing.T, retain int) (*Agent, string, func()) {
+	srv := agent.NewTestAgent(t, t.Name(), nil)
+
+	dir, err := ioutil.TempDir("", "nomad-snapshots")
+	require.NoError(t, err)
+
+	config := DefaultConfig()
+	config.Snapshot.Interval = 50 * time.Millisecond
+	config.Snapshot.Retain = retain
+	config.Local = &LocalStorageConfig{Path: dir}
+
+	a, err := NewAgent(config, srv.Client(), testlog.Logger(t))
+	require.NoError(t, err)
+
+	return a, dir, func() {
+		srv.Shutdown()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestAgent_Snapshot(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	a, dir, cleanup := testAgent(t, 2)
+	defer cleanup()
+
+	var names []string
+	for i := 0; i < 3; i++ {
+		name, err := a.Snapshot()
+		require.NoError(err)
+		names = append(names, name)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	// Only the most recent snapshots are retained
+	stored, err := a.storage.List("nomad-")
+	require.NoError(err)
+	require.Equal(names[1:], stored)
+
+	f, err := os.Open(filepath.Join(dir, names[2]))
+	require.NoError(err)
+	defer f.Close()
+	meta, err := snapshot.Verify(f)
+	require.NoError(err)
+	require.NotZero(meta.Index)
+}
+
+func TestAgent_Run(t *testing.T) {
+	t.Parallel()
+
+	a, _, cleanup := testAgent(t, 0)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	doneCh := make(chan struct{})
+	go func() {
+		a.Run(ctx)
+		close(doneCh)
+	}()
+
+	// Snapshots are taken on the interval
+	testutil.WaitForResult(func() (bool, error) {
+		names, err := a.storage.List("nomad-")
+		if err != nil {
+			return false, err
+		}
+		return len(names) >= 3, nil
+	}, func(err error) {
+		t.Fatalf("snapshots not taken: %v", err)
+	})
+
+	cancel()
+	select {
+	case <-doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent did not stop")
+	}
+}
+
+func TestAgent_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewAgent(DefaultConfig(), nil, testlog.Logger(t))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "exactly one storage must be configured")
+}
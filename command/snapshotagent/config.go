@@ -0,0 +1,269 @@
+package snapshotagent
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Config is the configuration of the snapshot agent
+type Config struct {
+	// Snapshot configures when and how the snapshots are taken
+	Snapshot *SnapshotConfig
+
+	// Exactly one of the storages must be configured
+	Local  *LocalStorageConfig
+	AWS    *AWSStorageConfig
+	Azure  *AzureStorageConfig
+	Google *GoogleStorageConfig
+}
+
+// SnapshotConfig configures when and how the snapshots are taken
+type SnapshotConfig struct {
+	// Interval is the time between two snapshots
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Retain is the number of snapshots to keep in the storage, the oldest
+	// ones being deleted. Zero keeps all of them.
+	Retain int `mapstructure:"retain"`
+
+	// Stale allows any server to take the snapshot, and not only the leader
+	Stale bool `mapstructure:"stale"`
+
+	// Prefix is the prefix of the names of the snapshot files
+	Prefix string `mapstructure:"prefix"`
+
+	// LocalScratchPath is the directory where the snapshots are downloaded
+	// and verified before being stored. Defaults to the temporary directory.
+	LocalScratchPath string `mapstructure:"local_scratch_path"`
+}
+
+// LocalStorageConfig stores the snapshots in a local directory
+type LocalStorageConfig struct {
+	Path string `mapstructure:"path"`
+}
+
+// AWSStorageConfig stores the snapshots in an S3 bucket
+type AWSStorageConfig struct {
+	// AccessKeyID and SecretAccessKey are the static credentials to use. The
+	// environment, shared credentials file and instance role are used
+	// otherwise.
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+
+	Region    string `mapstructure:"s3_region"`
+	Bucket    string `mapstructure:"s3_bucket"`
+	KeyPrefix string `mapstructure:"s3_key_prefix"`
+
+	// Endpoint is the endpoint of an S3 compatible storage to use instead
+	// of AWS
+	Endpoint       string `mapstructure:"s3_endpoint"`
+	ForcePathStyle bool   `mapstructure:"s3_force_path_style"`
+
+	// ServerSideEncryption encrypts the snapshots with AES-256, or with
+	// the given KMS key
+	ServerSideEncryption bool   `mapstructure:"s3_server_side_encryption"`
+	KMSKeyID             string `mapstructure:"s3_kms_key_id"`
+}
+
+// AzureStorageConfig stores the snapshots in an Azure Blob Storage container
+type AzureStorageConfig struct {
+	AccountName   string `mapstructure:"account_name"`
+	AccountKey    string `mapstructure:"account_key"`
+	ContainerName string `mapstructure:"container_name"`
+
+	// Endpoint is the endpoint of the blob service, defaults to the one of
+	// the account in the Azure public cloud
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// GoogleStorageConfig stores the snapshots in a Google Cloud Storage bucket,
+// through its S3 compatible API authenticated with HMAC keys
+type GoogleStorageConfig struct {
+	HMACAccessID string `mapstructure:"hmac_access_id"`
+	HMACSecret   string `mapstructure:"hmac_secret"`
+	Bucket       string `mapstructure:"bucket"`
+	KeyPrefix    string `mapstructure:"key_prefix"`
+
+	// Endpoint defaults to https://storage.googleapis.com
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+// DefaultConfig returns the default configuration, without any storage
+func DefaultConfig() *Config {
+	return &Config{
+		Snapshot: &SnapshotConfig{
+			Interval: time.Hour,
+			Retain:   30,
+			Prefix:   "nomad",
+		},
+	}
+}
+
+// Validate returns an error if the configuration isn't usable
+func (c *Config) Validate() error {
+	var mErr multierror.Error
+	if c.Snapshot.Interval <= 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("snapshot interval must be positive"))
+	}
+	if c.Snapshot.Retain < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("snapshot retain must not be negative"))
+	}
+	if c.Snapshot.Prefix == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("snapshot prefix must not be empty"))
+	}
+
+	storages := 0
+	if c.Local != nil {
+		storages++
+		if c.Local.Path == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("local_storage path is required"))
+		}
+	}
+	if c.AWS != nil {
+		storages++
+		if c.AWS.Bucket == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("aws_storage s3_bucket is required"))
+		}
+		if c.AWS.Region == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("aws_storage s3_region is required"))
+		}
+		if c.AWS.KMSKeyID != "" && !c.AWS.ServerSideEncryption {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("aws_storage s3_kms_key_id requires s3_server_side_encryption"))
+		}
+	}
+	if c.Azure != nil {
+		storages++
+		if c.Azure.AccountName == "" || c.Azure.AccountKey == "" || c.Azure.ContainerName == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("azure_blob_storage account_name, account_key and container_name are required"))
+		}
+	}
+	if c.Google != nil {
+		storages++
+		if c.Google.Bucket == "" || c.Google.HMACAccessID == "" || c.Google.HMACSecret == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("google_storage bucket, hmac_access_id and hmac_secret are required"))
+		}
+	}
+	if storages != 1 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("exactly one storage must be configured, got %d", storages))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// ParseConfigFile parses the given path as a config file.
+func ParseConfigFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseConfig(f)
+}
+
+// ParseConfig parses the config from the given io.Reader, on top of the
+// default configuration.
+func ParseConfig(r io.Reader) (*Config, error) {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		return nil, err
+	}
+
+	root, err := hcl.Parse(buf.String())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing: %s", err)
+	}
+
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("error parsing: root should be an object")
+	}
+
+	valid := []string{
+		"snapshot",
+		"local_storage",
+		"aws_storage",
+		"azure_blob_storage",
+		"google_storage",
+	}
+	if err := helper.CheckHCLKeys(list, valid); err != nil {
+		return nil, multierror.Prefix(err, "config:")
+	}
+
+	config := DefaultConfig()
+	if o := list.Filter("snapshot"); len(o.Items) > 0 {
+		if err := parseBlock("snapshot", o, config.Snapshot); err != nil {
+			return nil, err
+		}
+	}
+	if o := list.Filter("local_storage"); len(o.Items) > 0 {
+		config.Local = &LocalStorageConfig{}
+		if err := parseBlock("local_storage", o, config.Local); err != nil {
+			return nil, err
+		}
+	}
+	if o := list.Filter("aws_storage"); len(o.Items) > 0 {
+		config.AWS = &AWSStorageConfig{}
+		if err := parseBlock("aws_storage", o, config.AWS); err != nil {
+			return nil, err
+		}
+	}
+	if o := list.Filter("azure_blob_storage"); len(o.Items) > 0 {
+		config.Azure = &AzureStorageConfig{}
+		if err := parseBlock("azure_blob_storage", o, config.Azure); err != nil {
+			return nil, err
+		}
+	}
+	if o := list.Filter("google_storage"); len(o.Items) > 0 {
+		config.Google = &GoogleStorageConfig{}
+		if err := parseBlock("google_storage", o, config.Google); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// parseBlock decodes the single block of the list into result, rejecting the
+// keys result has no field for
+func parseBlock(name string, list *ast.ObjectList, result interface{}) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one '%s' block allowed", name)
+	}
+	listVal := list.Items[0].Val
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, listVal); err != nil {
+		return err
+	}
+
+	var md mapstructure.Metadata
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Metadata:         &md,
+		Result:           result,
+	})
+	if err != nil {
+		return err
+	}
+	if err := dec.Decode(m); err != nil {
+		return multierror.Prefix(err, fmt.Sprintf("%s:", name))
+	}
+	if len(md.Unused) > 0 {
+		sort.Strings(md.Unused)
+		return fmt.Errorf("%s: invalid keys: %v", name, md.Unused)
+	}
+	return nil
+}
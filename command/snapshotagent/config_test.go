@@ -0,0 +1,165 @@
+package snapshotagent
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Parse(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	config, err := ParseConfig(strings.NewReader(`
+snapshot {
+  interval           = "30m"
+  retain             = 5
+  stale              = true
+  local_scratch_path = "/tmp/scratch"
+}
+
+aws_storage {
+  s3_region                 = "us-east-1"
+  s3_bucket                 = "backups"
+  s3_key_prefix             = "nomad"
+  s3_server_side_encryption = true
+  s3_kms_key_id             = "alias/nomad"
+}
+`))
+	require.NoError(err)
+	require.NoError(config.Validate())
+
+	require.Equal(&SnapshotConfig{
+		Interval:         30 * time.Minute,
+		Retain:           5,
+		Stale:            true,
+		Prefix:           "nomad",
+		LocalScratchPath: "/tmp/scratch",
+	}, config.Snapshot)
+	require.Equal(&AWSStorageConfig{
+		Region:               "us-east-1",
+		Bucket:               "backups",
+		KeyPrefix:            "nomad",
+		ServerSideEncryption: true,
+		KMSKeyID:             "alias/nomad",
+	}, config.AWS)
+	require.Nil(config.Local)
+	require.Nil(config.Azure)
+	require.Nil(config.Google)
+}
+
+func TestConfig_Parse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name   string
+		Config string
+		Err    string
+	}{
+		{
+			Name:   "unknown block",
+			Config: `nope {}`,
+			Err:    `invalid key: nope`,
+		},
+		{
+			Name:   "unknown key",
+			Config: `local_storage { path = "/backups" nope = 1 }`,
+			Err:    `local_storage: invalid keys: [nope]`,
+		},
+		{
+			Name:   "bad duration",
+			Config: `snapshot { interval = "often" }`,
+			Err:    `snapshot:`,
+		},
+		{
+			Name:   "duplicate block",
+			Config: `snapshot {} snapshot {}`,
+			Err:    `only one 'snapshot' block allowed`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			_, err := ParseConfig(strings.NewReader(c.Config))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.Err)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name   string
+		Modify func(*Config)
+		Err    string
+	}{
+		{
+			Name:   "valid",
+			Modify: func(c *Config) {},
+		},
+		{
+			Name: "no storage",
+			Modify: func(c *Config) {
+				c.Local = nil
+			},
+			Err: "exactly one storage must be configured, got 0",
+		},
+		{
+			Name: "two storages",
+			Modify: func(c *Config) {
+				c.Google = &GoogleStorageConfig{Bucket: "b", HMACAccessID: "id", HMACSecret: "secret"}
+			},
+			Err: "exactly one storage must be configured, got 2",
+		},
+		{
+			Name: "bad interval",
+			Modify: func(c *Config) {
+				c.Snapshot.Interval = 0
+			},
+			Err: "interval must be positive",
+		},
+		{
+			Name: "negative retain",
+			Modify: func(c *Config) {
+				c.Snapshot.Retain = -1
+			},
+			Err: "retain must not be negative",
+		},
+		{
+			Name: "kms key without encryption",
+			Modify: func(c *Config) {
+				c.Local = nil
+				c.AWS = &AWSStorageConfig{Region: "us-east-1", Bucket: "b", KMSKeyID: "alias/nomad"}
+			},
+			Err: "s3_kms_key_id requires s3_server_side_encryption",
+		},
+		{
+			Name: "incomplete azure",
+			Modify: func(c *Config) {
+				c.Local = nil
+				c.Azure = &AzureStorageConfig{AccountName: "nomad"}
+			},
+			Err: "account_name, account_key and container_name are required",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Local = &LocalStorageConfig{Path: "/backups"}
+			c.Modify(config)
+
+			err := config.Validate()
+			if c.Err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.Err)
+		})
+	}
+}
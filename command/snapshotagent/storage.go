@@ -0,0 +1,104 @@
+package snapshotagent
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Storage is where the snapshot agent keeps the snapshots
+type Storage interface {
+	// Store saves the snapshot of the given size under name
+	Store(name string, snap io.ReadSeeker, size int64) error
+
+	// List returns the names of the stored snapshots with the given prefix,
+	// sorted
+	List(prefix string) ([]string, error)
+
+	// Delete removes the named snapshot
+	Delete(name string) error
+
+	// String describes the storage for the logs
+	String() string
+}
+
+// NewStorage returns the storage of the configuration
+func NewStorage(config *Config) (Storage, error) {
+	switch {
+	case config.Local != nil:
+		return newLocalStorage(config.Local)
+	case config.AWS != nil:
+		return newS3Storage(config.AWS)
+	case config.Azure != nil:
+		return newAzureStorage(config.Azure)
+	case config.Google != nil:
+		return newGoogleStorage(config.Google)
+	default:
+		return nil, fmt.Errorf("no storage configured")
+	}
+}
+
+// localStorage stores the snapshots in a local directory
+type localStorage struct {
+	path string
+}
+
+func newLocalStorage(config *LocalStorageConfig) (*localStorage, error) {
+	if err := os.MkdirAll(config.Path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %v", err)
+	}
+	return &localStorage{path: config.Path}, nil
+}
+
+func (l *localStorage) Store(name string, snap io.ReadSeeker, size int64) error {
+	// Write to a temporary file first so that a failed store never leaves a
+	// partial snapshot behind
+	f, err := ioutil.TempFile(l.path, name+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := io.Copy(f, snap); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filepath.Join(l.path, name))
+}
+
+func (l *localStorage) List(prefix string) ([]string, error) {
+	files, err := ioutil.ReadDir(l.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, snapshotSuffix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (l *localStorage) Delete(name string) error {
+	return os.Remove(filepath.Join(l.path, name))
+}
+
+func (l *localStorage) String() string {
+	return fmt.Sprintf("local path %q", l.path)
+}
@@ -0,0 +1,228 @@
+package snapshotagent
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+const (
+	// azureAPIVersion is the version of the Blob service REST API in use,
+	// the first one accepting blobs of up to 5000 MiB in a single request
+	azureAPIVersion = "2019-12-12"
+)
+
+// azureStorage stores the snapshots in an Azure Blob Storage container,
+// through the REST API of the Blob service authorized with the account key
+type azureStorage struct {
+	client    *http.Client
+	endpoint  string
+	account   string
+	key       []byte
+	container string
+}
+
+func newAzureStorage(config *AzureStorageConfig) (*azureStorage, error) {
+	key, err := base64.StdEncoding.DecodeString(config.AccountKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure account key: %v", err)
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", config.AccountName)
+	}
+
+	return &azureStorage{
+		client:    cleanhttp.DefaultClient(),
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		account:   config.AccountName,
+		key:       key,
+		container: config.ContainerName,
+	}, nil
+}
+
+func (a *azureStorage) Store(name string, snap io.ReadSeeker, size int64) error {
+	req, err := a.newRequest("PUT", name, nil, ioutil.NopCloser(snap))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+
+	_, err = a.do(req, http.StatusCreated)
+	return err
+}
+
+// azureBlobList is the response of the List Blobs operation
+type azureBlobList struct {
+	Blobs struct {
+		Blob []struct {
+			Name string
+		}
+	}
+	NextMarker string
+}
+
+func (a *azureStorage) List(prefix string) ([]string, error) {
+	var names []string
+	marker := ""
+	for {
+		query := url.Values{
+			"restype": {"container"},
+			"comp":    {"list"},
+			"prefix":  {prefix},
+		}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+
+		req, err := a.newRequest("GET", "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := a.do(req, http.StatusOK)
+		if err != nil {
+			return nil, err
+		}
+
+		var list azureBlobList
+		if err := xml.Unmarshal(body, &list); err != nil {
+			return nil, fmt.Errorf("failed to decode blob list: %v", err)
+		}
+		for _, b := range list.Blobs.Blob {
+			if strings.HasSuffix(b.Name, snapshotSuffix) {
+				names = append(names, b.Name)
+			}
+		}
+
+		if list.NextMarker == "" {
+			break
+		}
+		marker = list.NextMarker
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (a *azureStorage) Delete(name string) error {
+	req, err := a.newRequest("DELETE", name, nil, nil)
+	if err != nil {
+		return err
+	}
+	_, err = a.do(req, http.StatusAccepted)
+	return err
+}
+
+func (a *azureStorage) String() string {
+	return fmt.Sprintf("Azure Blob Storage container %q", a.container)
+}
+
+// newRequest returns a request on the named blob of the container, or on the
+// container itself if name is empty
+func (a *azureStorage) newRequest(method, name string, query url.Values, body io.ReadCloser) (*http.Request, error) {
+	u, err := url.Parse(a.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure endpoint: %v", err)
+	}
+	u.Path += "/" + a.container
+	if name != "" {
+		u.Path += "/" + name
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	return req, nil
+}
+
+// do signs and sends the request, returning the body of the response if it
+// has the expected status
+func (a *azureStorage) do(req *http.Request, status int) ([]byte, error) {
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", a.account, a.sign(req)))
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != status {
+		return nil, fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// sign returns the Shared Key signature of the request
+func (a *azureStorage) sign(req *http.Request) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+
+	// The x-ms- headers, sorted
+	var headers []string
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if strings.HasPrefix(k, "x-ms-") {
+			headers = append(headers, k+":"+strings.Join(v, ","))
+		}
+	}
+	sort.Strings(headers)
+
+	// The path of the resource followed by the sorted query parameters
+	resource := "/" + a.account + req.URL.EscapedPath()
+	query := req.URL.Query()
+	var params []string
+	for k, v := range query {
+		sort.Strings(v)
+		params = append(params, strings.ToLower(k)+":"+strings.Join(v, ","))
+	}
+	sort.Strings(params)
+	for _, p := range params {
+		resource += "\n" + p
+	}
+
+	toSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, superseded by x-ms-date
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		strings.Join(headers, "\n"),
+		resource,
+	}, "\n")
+
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(toSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,152 @@
+package snapshotagent
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	// googleEndpoint is the endpoint of the S3 compatible API of Google
+	// Cloud Storage
+	googleEndpoint = "https://storage.googleapis.com"
+
+	// googleRegion is the region Google Cloud Storage expects in the
+	// signatures of its S3 compatible API
+	googleRegion = "auto"
+)
+
+// s3Storage stores the snapshots in an S3 bucket, or in the bucket of an S3
+// compatible storage
+type s3Storage struct {
+	client    *s3.S3
+	bucket    string
+	keyPrefix string
+
+	serverSideEncryption bool
+	kmsKeyID             string
+
+	// name is the name of the kind of storage, for the logs
+	name string
+}
+
+func newS3Storage(config *AWSStorageConfig) (*s3Storage, error) {
+	conf := &aws.Config{
+		Region: aws.String(config.Region),
+	}
+	if config.AccessKeyID != "" || config.SecretAccessKey != "" {
+		conf.Credentials = credentials.NewStaticCredentials(config.AccessKeyID, config.SecretAccessKey, "")
+	}
+	if config.Endpoint != "" {
+		conf.Endpoint = aws.String(config.Endpoint)
+	}
+	if config.ForcePathStyle {
+		conf.S3ForcePathStyle = aws.Bool(true)
+	}
+
+	return &s3Storage{
+		client:               s3.New(session.New(conf)),
+		bucket:               config.Bucket,
+		keyPrefix:            config.KeyPrefix,
+		serverSideEncryption: config.ServerSideEncryption,
+		kmsKeyID:             config.KMSKeyID,
+		name:                 "S3",
+	}, nil
+}
+
+// newGoogleStorage returns a storage in a Google Cloud Storage bucket, using
+// its S3 compatible API
+func newGoogleStorage(config *GoogleStorageConfig) (*s3Storage, error) {
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = googleEndpoint
+	}
+
+	s, err := newS3Storage(&AWSStorageConfig{
+		AccessKeyID:     config.HMACAccessID,
+		SecretAccessKey: config.HMACSecret,
+		Region:          googleRegion,
+		Bucket:          config.Bucket,
+		KeyPrefix:       config.KeyPrefix,
+		Endpoint:        endpoint,
+		ForcePathStyle:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.name = "Google Cloud Storage"
+	return s, nil
+}
+
+// key returns the key of the named snapshot, in the key prefix directory
+func (s *s3Storage) key(name string) string {
+	if s.keyPrefix == "" {
+		return name
+	}
+	return strings.TrimSuffix(s.keyPrefix, "/") + "/" + name
+}
+
+func (s *s3Storage) Store(name string, snap io.ReadSeeker, size int64) error {
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(s.key(name)),
+		Body:          snap,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String("application/octet-stream"),
+	}
+	if s.serverSideEncryption {
+		if s.kmsKeyID != "" {
+			input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+			input.SSEKMSKeyId = aws.String(s.kmsKeyID)
+		} else {
+			input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+		}
+	}
+
+	_, err := s.client.PutObject(input)
+	return err
+}
+
+func (s *s3Storage) List(prefix string) ([]string, error) {
+	input := &s3.ListObjectsInput{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.key(prefix)),
+	}
+
+	var names []string
+	keyPrefix := s.key("")
+	err := s.client.ListObjectsPages(input, func(out *s3.ListObjectsOutput, lastPage bool) bool {
+		for _, o := range out.Contents {
+			name := strings.TrimPrefix(aws.StringValue(o.Key), keyPrefix)
+			if strings.Contains(name, "/") || !strings.HasSuffix(name, snapshotSuffix) {
+				continue
+			}
+			names = append(names, name)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s *s3Storage) Delete(name string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(name)),
+	})
+	return err
+}
+
+func (s *s3Storage) String() string {
+	return fmt.Sprintf("%s bucket %q", s.name, s.bucket)
+}
@@ -0,0 +1,270 @@
+package snapshotagent
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeObjectStore keeps the objects of a fake object storage server
+type fakeObjectStore struct {
+	sync.Mutex
+	objects map[string][]byte
+	headers map[string]http.Header
+}
+
+func newFakeObjectStore() *fakeObjectStore {
+	return &fakeObjectStore{
+		objects: make(map[string][]byte),
+		headers: make(map[string]http.Header),
+	}
+}
+
+func (f *fakeObjectStore) put(key string, r *http.Request) error {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	f.Lock()
+	defer f.Unlock()
+	f.objects[key] = data
+	f.headers[key] = r.Header
+	return nil
+}
+
+func (f *fakeObjectStore) list(prefix string) []string {
+	f.Lock()
+	defer f.Unlock()
+	var keys []string
+	for k := range f.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *fakeObjectStore) delete(key string) {
+	f.Lock()
+	defer f.Unlock()
+	delete(f.objects, key)
+}
+
+// fakeS3 serves the S3 operations used by the storage, in the bucket
+// "backups" with path style addressing
+func fakeS3(t *testing.T, store *fakeObjectStore) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=id/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/backups/")
+		switch {
+		case r.Method == "PUT":
+			if err := store.put(key, r); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		case r.Method == "DELETE":
+			store.delete(key)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET" && strings.TrimSuffix(r.URL.Path, "/") == "/backups":
+			type contents struct {
+				Key string
+			}
+			var result struct {
+				XMLName  xml.Name `xml:"ListBucketResult"`
+				Contents []contents
+			}
+			for _, k := range store.list(r.URL.Query().Get("prefix")) {
+				result.Contents = append(result.Contents, contents{k})
+			}
+			require.NoError(t, xml.NewEncoder(w).Encode(result))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// fakeAzure serves the Blob service operations used by the storage, in the
+// container "backups"
+func fakeAzure(t *testing.T, store *fakeObjectStore) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "SharedKey nomad:") ||
+			r.Header.Get("x-ms-version") != azureAPIVersion ||
+			r.Header.Get("x-ms-date") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/backups/")
+		switch {
+		case r.Method == "PUT" && r.Header.Get("x-ms-blob-type") == "BlockBlob":
+			if err := store.put(key, r); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "DELETE":
+			store.delete(key)
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == "GET" && r.URL.Query().Get("comp") == "list":
+			type blob struct {
+				Name string
+			}
+			var result struct {
+				XMLName xml.Name `xml:"EnumerationResults"`
+				Blobs   struct {
+					Blob []blob
+				}
+			}
+			for _, k := range store.list(r.URL.Query().Get("prefix")) {
+				result.Blobs.Blob = append(result.Blobs.Blob, blob{k})
+			}
+			require.NoError(t, xml.NewEncoder(w).Encode(result))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// testStorage stores, lists and deletes snapshots in the storage
+func testStorage(t *testing.T, s Storage) {
+	require := require.New(t)
+
+	for _, name := range []string{"nomad-2.snap", "nomad-1.snap", "other-1.snap"} {
+		require.NoError(s.Store(name, strings.NewReader("data of "+name), int64(len("data of "+name))))
+	}
+
+	names, err := s.List("nomad-")
+	require.NoError(err)
+	require.Equal([]string{"nomad-1.snap", "nomad-2.snap"}, names)
+
+	require.NoError(s.Delete("nomad-1.snap"))
+	names, err = s.List("nomad-")
+	require.NoError(err)
+	require.Equal([]string{"nomad-2.snap"}, names)
+}
+
+func TestStorage_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-snapshots")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s, err := NewStorage(&Config{Local: &LocalStorageConfig{Path: dir}})
+	require.NoError(err)
+	testStorage(t, s)
+
+	// No temporary files are left behind
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(err)
+	require.Len(files, 2)
+}
+
+func TestStorage_S3(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	store := newFakeObjectStore()
+	srv := fakeS3(t, store)
+	defer srv.Close()
+
+	s, err := NewStorage(&Config{AWS: &AWSStorageConfig{
+		AccessKeyID:          "id",
+		SecretAccessKey:      "secret",
+		Region:               "us-east-1",
+		Bucket:               "backups",
+		KeyPrefix:            "cluster/",
+		Endpoint:             srv.URL,
+		ForcePathStyle:       true,
+		ServerSideEncryption: true,
+		KMSKeyID:             "alias/nomad",
+	}})
+	require.NoError(err)
+	testStorage(t, s)
+
+	require.Equal([]string{"cluster/nomad-2.snap", "cluster/other-1.snap"}, store.list(""))
+	require.Equal("data of nomad-2.snap", string(store.objects["cluster/nomad-2.snap"]))
+	headers := store.headers["cluster/nomad-2.snap"]
+	require.Equal("aws:kms", headers.Get("X-Amz-Server-Side-Encryption"))
+	require.Equal("alias/nomad", headers.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+}
+
+func TestStorage_Google(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	store := newFakeObjectStore()
+	srv := fakeS3(t, store)
+	defer srv.Close()
+
+	s, err := NewStorage(&Config{Google: &GoogleStorageConfig{
+		HMACAccessID: "id",
+		HMACSecret:   "secret",
+		Bucket:       "backups",
+		Endpoint:     srv.URL,
+	}})
+	require.NoError(err)
+	testStorage(t, s)
+	require.Equal([]string{"nomad-2.snap", "other-1.snap"}, store.list(""))
+}
+
+func TestStorage_Azure(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	store := newFakeObjectStore()
+	srv := fakeAzure(t, store)
+	defer srv.Close()
+
+	s, err := NewStorage(&Config{Azure: &AzureStorageConfig{
+		AccountName:   "nomad",
+		AccountKey:    base64.StdEncoding.EncodeToString([]byte("key")),
+		ContainerName: "backups",
+		Endpoint:      srv.URL,
+	}})
+	require.NoError(err)
+	testStorage(t, s)
+	require.Equal([]string{"nomad-2.snap", "other-1.snap"}, store.list(""))
+	require.Equal("data of nomad-2.snap", string(store.objects["nomad-2.snap"]))
+}
+
+func TestStorage_Azure_Sign(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, err := newAzureStorage(&AzureStorageConfig{
+		AccountName:   "myaccount",
+		AccountKey:    base64.StdEncoding.EncodeToString([]byte("key")),
+		ContainerName: "mycontainer",
+	})
+	require.NoError(err)
+
+	req, err := s.newRequest("GET", "", map[string][]string{
+		"restype": {"container"},
+		"comp":    {"list"},
+	}, nil)
+	require.NoError(err)
+	req.Header.Set("x-ms-date", "Fri, 26 Jun 2015 23:39:12 GMT")
+
+	// The signature is stable and only depends on the signed parts
+	sig := s.sign(req)
+	require.Equal(sig, s.sign(req))
+	req.Header.Set("User-Agent", "nomad")
+	require.Equal(sig, s.sign(req))
+	req.Header.Set("x-ms-date", "Sat, 27 Jun 2015 23:39:12 GMT")
+	require.NotEqual(sig, s.sign(req))
+}
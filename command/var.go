@@ -0,0 +1,51 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type VarCommand struct {
+	Meta
+}
+
+func (f *VarCommand) Help() string {
+	helpText := `
+Usage: nomad var <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with variables. Variables
+  are key-value pairs stored encrypted by the Nomad servers at a path. Tasks
+  can read the variables stored under the nomad/jobs/<job> path from their
+  templates.
+
+  Create or update a variable:
+
+      $ nomad var put app/web username=admin password=hunter2
+
+  Read a variable:
+
+      $ nomad var get app/web
+
+  List variables:
+
+      $ nomad var list app/
+
+  Delete a variable:
+
+      $ nomad var delete app/web
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (f *VarCommand) Synopsis() string {
+	return "Interact with variables"
+}
+
+func (f *VarCommand) Name() string { return "var" }
+
+func (f *VarCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
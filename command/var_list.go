@@ -0,0 +1,122 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarListCommand struct {
+	Meta
+}
+
+func (c *VarListCommand) Help() string {
+	helpText := `
+Usage: nomad var list [options] [<prefix>]
+
+  List is used to list the variables the token can list, optionally filtered
+  by a path prefix. The items of the variables are not displayed.
+
+General Options:
+
+  ` + generalOptionsUsage() + `
+
+List Options:
+
+  -json
+    Output the variables in a JSON format.
+
+  -t
+    Format and display the variables using a Go template.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *VarListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *VarListCommand) Synopsis() string {
+	return "List variables"
+}
+
+func (c *VarListCommand) Name() string { return "var list" }
+
+func (c *VarListCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got at most one argument
+	args = flags.Args()
+	if l := len(args); l > 1 {
+		c.Ui.Error("This command takes at most one argument: <prefix>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	prefix := ""
+	if len(args) == 1 {
+		prefix = args[0]
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	vars, _, err := client.Variables().List(&api.QueryOptions{Prefix: prefix})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing variables: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, vars)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatVariables(vars))
+	return 0
+}
+
+func formatVariables(vars []*api.VariableMetadata) string {
+	if len(vars) == 0 {
+		return "No variables found"
+	}
+
+	output := make([]string, 0, len(vars)+1)
+	output = append(output, "Namespace|Path|Modify Time")
+	for _, v := range vars {
+		output = append(output, fmt.Sprintf("%s|%s|%s",
+			v.Namespace, v.Path, formatUnixNanoTime(v.ModifyTime)))
+	}
+
+	return formatList(output)
+}
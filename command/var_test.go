@@ -0,0 +1,65 @@
+package command
+
+import (
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVarCommand_Implements(t *testing.T) {
+	t.Parallel()
+	var _ cli.Command = &VarPutCommand{}
+	var _ cli.Command = &VarGetCommand{}
+	var _ cli.Command = &VarListCommand{}
+	var _ cli.Command = &VarDeleteCommand{}
+}
+
+func TestVarCommand_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := new(cli.MockUi)
+	meta := Meta{Ui: ui, flagAddress: url}
+
+	// Items must be key value pairs
+	put := &VarPutCommand{Meta: meta}
+	require.Equal(1, put.Run([]string{"-address=" + url, "app/web", "password"}))
+	require.Contains(ui.ErrorWriter.String(), "Invalid item")
+	ui.ErrorWriter.Reset()
+
+	// Create a variable
+	require.Equal(0, put.Run([]string{"-address=" + url, "app/web", "username=admin", "password=hunter2"}))
+	require.Contains(ui.OutputWriter.String(), "Successfully wrote")
+	ui.OutputWriter.Reset()
+
+	// Read it back
+	get := &VarGetCommand{Meta: meta}
+	require.Equal(0, get.Run([]string{"-address=" + url, "app/web"}))
+	out := ui.OutputWriter.String()
+	require.Contains(out, "app/web")
+	require.Contains(out, "hunter2")
+	ui.OutputWriter.Reset()
+
+	// List it without its items
+	list := &VarListCommand{Meta: meta}
+	require.Equal(0, list.Run([]string{"-address=" + url, "app/"}))
+	out = ui.OutputWriter.String()
+	require.Contains(out, "app/web")
+	require.NotContains(out, "hunter2")
+	ui.OutputWriter.Reset()
+
+	// Delete it
+	del := &VarDeleteCommand{Meta: meta}
+	require.Equal(0, del.Run([]string{"-address=" + url, "app/web"}))
+	ui.OutputWriter.Reset()
+
+	require.Equal(0, list.Run([]string{"-address=" + url}))
+	require.Contains(ui.OutputWriter.String(), "No variables found")
+
+	require.Equal(1, get.Run([]string{"-address=" + url, "app/web"}))
+	require.Contains(ui.ErrorWriter.String(), "not found")
+}
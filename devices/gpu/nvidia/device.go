@@ -0,0 +1,397 @@
+package nvidia
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "nvidia-gpu"
+
+	// vendor is the vendor providing the devices
+	vendor = "nvidia"
+
+	// NvidiaVisibleDevices is the environment variable used by the NVIDIA
+	// container runtime to select the GPUs exposed to a container
+	NvidiaVisibleDevices = "NVIDIA_VISIBLE_DEVICES"
+
+	// defaultFingerprintPeriod is how often devices are detected by default
+	defaultFingerprintPeriod = "1m"
+
+	// defaultStatsPeriod is how often statistics are collected by default
+	defaultStatsPeriod = "5s"
+)
+
+var (
+	// pluginInfo describes the plugin
+	pluginInfo = &base.PluginInfoResponse{
+		Type:             base.PluginTypeDevice,
+		PluginApiVersion: "0.0.1",
+		PluginVersion:    "0.1.0",
+		Name:             pluginName,
+	}
+
+	// configSpec is the specification of the plugin's configuration
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"ignored_gpu_ids": hclspec.NewDefault(
+			hclspec.NewAttr("ignored_gpu_ids", "list(string)", false),
+			hclspec.NewLiteral("[]"),
+		),
+		"fingerprint_period": hclspec.NewDefault(
+			hclspec.NewAttr("fingerprint_period", "string", false),
+			hclspec.NewLiteral("\""+defaultFingerprintPeriod+"\""),
+		),
+		"stats_period": hclspec.NewDefault(
+			hclspec.NewAttr("stats_period", "string", false),
+			hclspec.NewLiteral("\""+defaultStatsPeriod+"\""),
+		),
+	})
+)
+
+// Config contains configuration information for the plugin.
+type Config struct {
+	IgnoredGPUIDs     []string `codec:"ignored_gpu_ids"`
+	FingerprintPeriod string   `codec:"fingerprint_period"`
+	StatsPeriod       string   `codec:"stats_period"`
+}
+
+// NvidiaDevice is a device plugin that exposes the NVIDIA GPUs of a host. GPUs
+// are detected and monitored using the nvidia-smi tool that ships with the
+// NVIDIA driver.
+type NvidiaDevice struct {
+	logger log.Logger
+
+	// smi is used to query the GPUs of the host
+	smi smiClient
+
+	// ignoredGPUIDs is the set of GPU UUIDs that should not be exposed
+	ignoredGPUIDs map[string]struct{}
+
+	// fingerprintPeriod is how often we should detect devices
+	fingerprintPeriod time.Duration
+
+	// statsPeriod is how often we should collect statistics for fingerprinted
+	// devices
+	statsPeriod time.Duration
+
+	// devices is the set of detected GPUs keyed by their UUID
+	devices    map[string]*gpuInfo
+	deviceLock sync.RWMutex
+}
+
+// NewNvidiaDevice returns a new NVIDIA device plugin.
+func NewNvidiaDevice(log log.Logger) *NvidiaDevice {
+	return &NvidiaDevice{
+		logger:        log.Named(pluginName),
+		smi:           &nvidiaSmi{},
+		ignoredGPUIDs: make(map[string]struct{}),
+		devices:       make(map[string]*gpuInfo),
+	}
+}
+
+// PluginInfo returns information describing the plugin.
+func (d *NvidiaDevice) PluginInfo() (*base.PluginInfoResponse, error) {
+	return pluginInfo, nil
+}
+
+// ConfigSchema returns the plugins configuration schema.
+func (d *NvidiaDevice) ConfigSchema() (*hclspec.Spec, error) {
+	return configSpec, nil
+}
+
+// SetConfig is used to set the configuration of the plugin. Unset durations
+// fall back to their defaults.
+func (d *NvidiaDevice) SetConfig(data []byte) error {
+	var config Config
+	if len(data) != 0 {
+		if err := base.MsgPackDecode(data, &config); err != nil {
+			return err
+		}
+	}
+
+	d.ignoredGPUIDs = make(map[string]struct{}, len(config.IgnoredGPUIDs))
+	for _, id := range config.IgnoredGPUIDs {
+		d.ignoredGPUIDs[id] = struct{}{}
+	}
+
+	if config.FingerprintPeriod == "" {
+		config.FingerprintPeriod = defaultFingerprintPeriod
+	}
+	period, err := time.ParseDuration(config.FingerprintPeriod)
+	if err != nil {
+		return fmt.Errorf("failed to parse fingerprint period %q: %v", config.FingerprintPeriod, err)
+	}
+	d.fingerprintPeriod = period
+
+	if config.StatsPeriod == "" {
+		config.StatsPeriod = defaultStatsPeriod
+	}
+	speriod, err := time.ParseDuration(config.StatsPeriod)
+	if err != nil {
+		return fmt.Errorf("failed to parse stats period %q: %v", config.StatsPeriod, err)
+	}
+	d.statsPeriod = speriod
+
+	return nil
+}
+
+// Fingerprint streams detected GPUs. A message is emitted when GPUs are added
+// or removed.
+func (d *NvidiaDevice) Fingerprint(ctx context.Context) (<-chan *device.FingerprintResponse, error) {
+	if d.fingerprintPeriod == 0 {
+		return nil, status.New(codes.Internal, "plugin not configured").Err()
+	}
+
+	outCh := make(chan *device.FingerprintResponse)
+	go d.fingerprint(ctx, outCh)
+	return outCh, nil
+}
+
+// fingerprint is the long running goroutine that detects GPUs
+func (d *NvidiaDevice) fingerprint(ctx context.Context, devices chan *device.FingerprintResponse) {
+	defer close(devices)
+
+	// Create a timer that will fire immediately for the first detection
+	ticker := time.NewTimer(0)
+	first := true
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(d.fingerprintPeriod)
+		}
+
+		gpus, err := d.smi.GPUs()
+		if err != nil {
+			d.logger.Error("failed to detect GPUs", "error", err)
+			if first {
+				// The driver is most likely not installed so stop detecting
+				devices <- device.NewFingerprintError(err)
+				return
+			}
+			continue
+		}
+
+		changed := d.updateDevices(gpus)
+		if !changed && !first {
+			continue
+		}
+		first = false
+
+		select {
+		case <-ctx.Done():
+			return
+		case devices <- device.NewFingerprint(d.deviceGroups()...):
+		}
+	}
+}
+
+// updateDevices stores the detected GPUs that are not ignored and returns
+// whether the set of GPUs has changed.
+func (d *NvidiaDevice) updateDevices(gpus []*gpuInfo) bool {
+	d.deviceLock.Lock()
+	defer d.deviceLock.Unlock()
+
+	detected := make(map[string]*gpuInfo, len(gpus))
+	for _, gpu := range gpus {
+		if _, ok := d.ignoredGPUIDs[gpu.UUID]; ok {
+			continue
+		}
+		detected[gpu.UUID] = gpu
+	}
+
+	changed := len(detected) != len(d.devices)
+	for id, gpu := range detected {
+		old, ok := d.devices[id]
+		if !ok || *old != *gpu {
+			changed = true
+			break
+		}
+	}
+
+	d.devices = detected
+	return changed
+}
+
+// deviceGroups groups the detected GPUs by model.
+func (d *NvidiaDevice) deviceGroups() []*device.DeviceGroup {
+	d.deviceLock.RLock()
+	defer d.deviceLock.RUnlock()
+
+	groups := make(map[string]*device.DeviceGroup)
+	var order []string
+	for _, gpu := range d.devices {
+		group, ok := groups[gpu.Name]
+		if !ok {
+			group = &device.DeviceGroup{
+				Vendor: vendor,
+				Type:   device.DeviceTypeGPU,
+				Name:   gpu.Name,
+				Attributes: map[string]string{
+					"memory":         fmt.Sprintf("%d", gpu.MemoryMiB),
+					"driver_version": gpu.DriverVersion,
+				},
+			}
+			groups[gpu.Name] = group
+			order = append(order, gpu.Name)
+		}
+
+		group.Devices = append(group.Devices, &device.Device{
+			ID:      gpu.UUID,
+			Healthy: true,
+			HwLocality: &device.DeviceLocality{
+				PciBusID: gpu.PciBusID,
+			},
+		})
+	}
+
+	out := make([]*device.DeviceGroup, 0, len(order))
+	for _, name := range order {
+		group := groups[name]
+		sortDevices(group.Devices)
+		out = append(out, group)
+	}
+	sortGroups(out)
+	return out
+}
+
+// Reserve returns the environment that exposes the given GPUs to a task run
+// with the NVIDIA container runtime.
+func (d *NvidiaDevice) Reserve(deviceIDs []string) (*device.ContainerReservation, error) {
+	if len(deviceIDs) == 0 {
+		return nil, status.New(codes.InvalidArgument, "no device ids given").Err()
+	}
+
+	d.deviceLock.RLock()
+	defer d.deviceLock.RUnlock()
+
+	for _, id := range deviceIDs {
+		if _, ok := d.devices[id]; !ok {
+			return nil, status.Newf(codes.InvalidArgument, "unknown device %q", id).Err()
+		}
+	}
+
+	return &device.ContainerReservation{
+		Envs: map[string]string{
+			NvidiaVisibleDevices: strings.Join(deviceIDs, ","),
+		},
+	}, nil
+}
+
+// Stats streams statistics for the detected GPUs.
+func (d *NvidiaDevice) Stats(ctx context.Context) (<-chan *device.StatsResponse, error) {
+	if d.statsPeriod == 0 {
+		return nil, status.New(codes.Internal, "plugin not configured").Err()
+	}
+
+	outCh := make(chan *device.StatsResponse)
+	go d.stats(ctx, outCh)
+	return outCh, nil
+}
+
+// stats is the long running goroutine that streams GPU statistics
+func (d *NvidiaDevice) stats(ctx context.Context, stats chan *device.StatsResponse) {
+	defer close(stats)
+
+	// Create a timer that will fire immediately for the first collection
+	ticker := time.NewTimer(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ticker.Reset(d.statsPeriod)
+		}
+
+		gpuStats, err := d.smi.Stats()
+		if err != nil {
+			d.logger.Error("failed to collect GPU statistics", "error", err)
+			continue
+		}
+
+		groups := d.statsGroups(gpuStats, time.Now())
+		if len(groups) == 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case stats <- &device.StatsResponse{Groups: groups}:
+		}
+	}
+}
+
+// statsGroups groups the statistics of the detected GPUs by model.
+func (d *NvidiaDevice) statsGroups(gpuStats []*gpuStats, now time.Time) []*device.DeviceGroupStats {
+	d.deviceLock.RLock()
+	defer d.deviceLock.RUnlock()
+
+	groups := make(map[string]*device.DeviceGroupStats)
+	var out []*device.DeviceGroupStats
+	for _, s := range gpuStats {
+		gpu, ok := d.devices[s.UUID]
+		if !ok {
+			continue
+		}
+
+		group, ok := groups[gpu.Name]
+		if !ok {
+			group = &device.DeviceGroupStats{
+				Vendor:        vendor,
+				Type:          device.DeviceTypeGPU,
+				Name:          gpu.Name,
+				InstanceStats: make(map[string]*device.DeviceStats),
+			}
+			groups[gpu.Name] = group
+			out = append(out, group)
+		}
+
+		group.InstanceStats[s.UUID] = &device.DeviceStats{
+			Summary: &device.StatValue{
+				IntNumeratorVal:   s.MemoryUsedMiB,
+				IntDenominatorVal: gpu.MemoryMiB,
+				Unit:              "MiB",
+				Desc:              "Memory in use",
+			},
+			Stats: &device.StatObject{
+				Attributes: map[string]*device.StatValue{
+					"memory": {
+						IntNumeratorVal:   s.MemoryUsedMiB,
+						IntDenominatorVal: gpu.MemoryMiB,
+						Unit:              "MiB",
+						Desc:              "Memory in use",
+					},
+					"gpu_utilization": {
+						IntNumeratorVal: s.UtilizationGPU,
+						Unit:            "%",
+						Desc:            "GPU utilization",
+					},
+					"temperature": {
+						IntNumeratorVal: s.TemperatureC,
+						Unit:            "C",
+						Desc:            "GPU temperature",
+					},
+				},
+			},
+			Timestamp: now,
+		}
+	}
+
+	return out
+}
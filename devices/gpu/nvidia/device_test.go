@@ -0,0 +1,190 @@
+package nvidia
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/device"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSmi is a smiClient that returns canned results
+type mockSmi struct {
+	gpus  []*gpuInfo
+	stats []*gpuStats
+	err   error
+}
+
+func (m *mockSmi) GPUs() ([]*gpuInfo, error)   { return m.gpus, m.err }
+func (m *mockSmi) Stats() ([]*gpuStats, error) { return m.stats, m.err }
+
+func testDevice(t *testing.T, smi smiClient, config *Config) *NvidiaDevice {
+	d := NewNvidiaDevice(testlog.HCLogger(t))
+	d.smi = smi
+
+	var data []byte
+	if config != nil {
+		require.NoError(t, base.MsgPackEncode(&data, config))
+	}
+	require.NoError(t, d.SetConfig(data))
+	return d
+}
+
+func TestParseGPUs(t *testing.T) {
+	require := require.New(t)
+
+	out := `GPU-6f2a, Tesla K80, 11441, 00000000:00:04.0, 396.26
+GPU-8c1b, Tesla K80, 11441, 00000000:00:05.0, 396.26
+GPU-93dd, Tesla V100-SXM2-16GB, 16160, 00000000:00:06.0, 396.26
+`
+	gpus, err := parseGPUs(strings.NewReader(out))
+	require.NoError(err)
+	require.Len(gpus, 3)
+	require.Equal(&gpuInfo{
+		UUID:          "GPU-6f2a",
+		Name:          "Tesla K80",
+		MemoryMiB:     11441,
+		PciBusID:      "00000000:00:04.0",
+		DriverVersion: "396.26",
+	}, gpus[0])
+	require.Equal("Tesla V100-SXM2-16GB", gpus[2].Name)
+
+	_, err = parseGPUs(strings.NewReader("GPU-6f2a, Tesla K80\n"))
+	require.Error(err)
+
+	_, err = parseGPUs(strings.NewReader("GPU-6f2a, Tesla K80, lots, 00000000:00:04.0, 396.26\n"))
+	require.Error(err)
+}
+
+func TestParseStats(t *testing.T) {
+	require := require.New(t)
+
+	out := `GPU-6f2a, 12, 2048, 41
+GPU-8c1b, [Not Supported], 0, 38
+`
+	stats, err := parseStats(strings.NewReader(out))
+	require.NoError(err)
+	require.Len(stats, 2)
+	require.Equal(&gpuStats{UUID: "GPU-6f2a", UtilizationGPU: 12, MemoryUsedMiB: 2048, TemperatureC: 41}, stats[0])
+	require.Equal(&gpuStats{UUID: "GPU-8c1b", TemperatureC: 38}, stats[1])
+}
+
+func TestNvidiaDevice_SetConfig(t *testing.T) {
+	require := require.New(t)
+
+	d := testDevice(t, &mockSmi{}, nil)
+	require.Equal(time.Minute, d.fingerprintPeriod)
+	require.Equal(5*time.Second, d.statsPeriod)
+
+	d = testDevice(t, &mockSmi{}, &Config{
+		IgnoredGPUIDs:     []string{"GPU-1"},
+		FingerprintPeriod: "10s",
+	})
+	require.Equal(10*time.Second, d.fingerprintPeriod)
+	require.Contains(d.ignoredGPUIDs, "GPU-1")
+
+	var data []byte
+	require.NoError(base.MsgPackEncode(&data, &Config{StatsPeriod: "often"}))
+	require.Error(d.SetConfig(data))
+}
+
+func TestNvidiaDevice_Fingerprint(t *testing.T) {
+	require := require.New(t)
+
+	smi := &mockSmi{
+		gpus: []*gpuInfo{
+			{UUID: "GPU-2", Name: "Tesla K80", MemoryMiB: 11441, PciBusID: "0000:05", DriverVersion: "396.26"},
+			{UUID: "GPU-1", Name: "Tesla K80", MemoryMiB: 11441, PciBusID: "0000:04", DriverVersion: "396.26"},
+			{UUID: "GPU-3", Name: "Tesla V100", MemoryMiB: 16160, PciBusID: "0000:06", DriverVersion: "396.26"},
+		},
+	}
+	d := testDevice(t, smi, &Config{IgnoredGPUIDs: []string{"GPU-3"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	outCh, err := d.Fingerprint(ctx)
+	require.NoError(err)
+
+	var resp *device.FingerprintResponse
+	select {
+	case resp = <-outCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for fingerprint")
+	}
+
+	require.NoError(resp.Error)
+	require.Len(resp.Devices, 1)
+	group := resp.Devices[0]
+	require.Equal("nvidia", group.Vendor)
+	require.Equal(device.DeviceTypeGPU, group.Type)
+	require.Equal("Tesla K80", group.Name)
+	require.Equal("11441", group.Attributes["memory"])
+	require.Len(group.Devices, 2)
+	require.Equal("GPU-1", group.Devices[0].ID)
+	require.Equal("GPU-2", group.Devices[1].ID)
+	require.True(group.Devices[0].Healthy)
+	require.Equal("0000:04", group.Devices[0].HwLocality.PciBusID)
+}
+
+func TestNvidiaDevice_Fingerprint_Error(t *testing.T) {
+	require := require.New(t)
+
+	d := testDevice(t, &mockSmi{err: context.DeadlineExceeded}, nil)
+
+	outCh, err := d.Fingerprint(context.Background())
+	require.NoError(err)
+
+	resp := <-outCh
+	require.Error(resp.Error)
+
+	_, ok := <-outCh
+	require.False(ok)
+}
+
+func TestNvidiaDevice_Reserve(t *testing.T) {
+	require := require.New(t)
+
+	d := testDevice(t, &mockSmi{}, nil)
+	d.updateDevices([]*gpuInfo{
+		{UUID: "GPU-1", Name: "Tesla K80"},
+		{UUID: "GPU-2", Name: "Tesla K80"},
+	})
+
+	res, err := d.Reserve([]string{"GPU-1", "GPU-2"})
+	require.NoError(err)
+	require.Equal("GPU-1,GPU-2", res.Envs[NvidiaVisibleDevices])
+
+	_, err = d.Reserve(nil)
+	require.Error(err)
+
+	_, err = d.Reserve([]string{"GPU-3"})
+	require.Error(err)
+}
+
+func TestNvidiaDevice_Stats(t *testing.T) {
+	require := require.New(t)
+
+	d := testDevice(t, &mockSmi{}, nil)
+	d.updateDevices([]*gpuInfo{
+		{UUID: "GPU-1", Name: "Tesla K80", MemoryMiB: 11441},
+	})
+
+	groups := d.statsGroups([]*gpuStats{
+		{UUID: "GPU-1", UtilizationGPU: 50, MemoryUsedMiB: 1024, TemperatureC: 40},
+		{UUID: "GPU-9", UtilizationGPU: 10},
+	}, time.Now())
+	require.Len(groups, 1)
+	require.Equal("Tesla K80", groups[0].Name)
+	require.Len(groups[0].InstanceStats, 1)
+
+	stats := groups[0].InstanceStats["GPU-1"]
+	require.NotNil(stats)
+	require.EqualValues(1024, stats.Summary.IntNumeratorVal)
+	require.EqualValues(11441, stats.Summary.IntDenominatorVal)
+	require.EqualValues(50, stats.Stats.Attributes["gpu_utilization"].IntNumeratorVal)
+}
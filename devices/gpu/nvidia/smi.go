@@ -0,0 +1,173 @@
+package nvidia
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/plugins/device"
+)
+
+const (
+	// smiBinary is the name of the tool used to query the GPUs
+	smiBinary = "nvidia-smi"
+
+	// gpuQuery is the set of fields queried when detecting GPUs
+	gpuQuery = "uuid,name,memory.total,pci.bus_id,driver_version"
+
+	// statsQuery is the set of fields queried when collecting statistics
+	statsQuery = "uuid,utilization.gpu,memory.used,temperature.gpu"
+)
+
+// gpuInfo describes a detected GPU.
+type gpuInfo struct {
+	UUID          string
+	Name          string
+	MemoryMiB     int64
+	PciBusID      string
+	DriverVersion string
+}
+
+// gpuStats is a sample of the statistics of a GPU.
+type gpuStats struct {
+	UUID           string
+	UtilizationGPU int64
+	MemoryUsedMiB  int64
+	TemperatureC   int64
+}
+
+// smiClient is used to query the GPUs of the host.
+type smiClient interface {
+	// GPUs returns the detected GPUs
+	GPUs() ([]*gpuInfo, error)
+
+	// Stats returns a sample of the statistics of the detected GPUs
+	Stats() ([]*gpuStats, error)
+}
+
+// nvidiaSmi queries the GPUs by running nvidia-smi.
+type nvidiaSmi struct{}
+
+func (n *nvidiaSmi) GPUs() ([]*gpuInfo, error) {
+	out, err := n.query(gpuQuery)
+	if err != nil {
+		return nil, err
+	}
+	return parseGPUs(bytes.NewReader(out))
+}
+
+func (n *nvidiaSmi) Stats() ([]*gpuStats, error) {
+	out, err := n.query(statsQuery)
+	if err != nil {
+		return nil, err
+	}
+	return parseStats(bytes.NewReader(out))
+}
+
+// query runs nvidia-smi for the given fields and returns the CSV output.
+func (n *nvidiaSmi) query(fields string) ([]byte, error) {
+	path, err := exec.LookPath(smiBinary)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found: %v", smiBinary, err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(path, "--query-gpu="+fields, "--format=csv,noheader,nounits")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %v: %s", smiBinary, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// parseGPUs parses the output of a GPU query.
+func parseGPUs(r io.Reader) ([]*gpuInfo, error) {
+	records, err := readRecords(r, 5)
+	if err != nil {
+		return nil, err
+	}
+
+	gpus := make([]*gpuInfo, 0, len(records))
+	for _, rec := range records {
+		memory, err := parseInt(rec[2])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse memory of GPU %q: %v", rec[0], err)
+		}
+
+		gpus = append(gpus, &gpuInfo{
+			UUID:          rec[0],
+			Name:          rec[1],
+			MemoryMiB:     memory,
+			PciBusID:      rec[3],
+			DriverVersion: rec[4],
+		})
+	}
+	return gpus, nil
+}
+
+// parseStats parses the output of a statistics query. Values the GPU does not
+// support are reported as zero.
+func parseStats(r io.Reader) ([]*gpuStats, error) {
+	records, err := readRecords(r, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*gpuStats, 0, len(records))
+	for _, rec := range records {
+		s := &gpuStats{UUID: rec[0]}
+		s.UtilizationGPU, _ = parseInt(rec[1])
+		s.MemoryUsedMiB, _ = parseInt(rec[2])
+		s.TemperatureC, _ = parseInt(rec[3])
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
+
+// readRecords reads the CSV records of a query that has the given number of
+// fields, trimming the whitespace nvidia-smi pads values with.
+func readRecords(r io.Reader, fields int) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = fields
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s output: %v", smiBinary, err)
+	}
+
+	for _, rec := range records {
+		for i, v := range rec {
+			rec[i] = strings.TrimSpace(v)
+		}
+	}
+	return records, nil
+}
+
+// parseInt parses an integer value, ignoring a fractional part.
+func parseInt(v string) (int64, error) {
+	if i := strings.Index(v, "."); i != -1 {
+		v = v[:i]
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// sortDevices sorts the devices by PCI bus ID so the order is stable.
+func sortDevices(devices []*device.Device) {
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].HwLocality.PciBusID < devices[j].HwLocality.PciBusID
+	})
+}
+
+// sortGroups sorts the device groups by name so the order is stable.
+func sortGroups(groups []*device.DeviceGroup) {
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+}
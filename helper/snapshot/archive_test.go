@@ -0,0 +1,99 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	require := require.New(t)
+
+	// Create some fake snapshot data
+	metadata := raft.SnapshotMeta{
+		Index: 2005,
+		Term:  2011,
+		Configuration: raft.Configuration{
+			Servers: []raft.Server{
+				{Suffrage: raft.Voter, ID: "hello", Address: "127.0.0.1:8300"},
+			},
+		},
+		Size: 1024,
+	}
+	var snap bytes.Buffer
+	var expected bytes.Buffer
+	both := io.MultiWriter(&snap, &expected)
+	_, err := io.Copy(both, io.LimitReader(rand.Reader, 1024))
+	require.NoError(err)
+
+	// Write out the snapshot and read it back
+	var archive bytes.Buffer
+	require.NoError(write(&archive, &metadata, &snap))
+
+	var out bytes.Buffer
+	var outMetadata raft.SnapshotMeta
+	require.NoError(read(&archive, &outMetadata, &out))
+
+	require.Equal(metadata, outMetadata)
+	require.Equal(expected.Bytes(), out.Bytes())
+}
+
+func TestArchive_BadData(t *testing.T) {
+	cases := []struct {
+		Name  string
+		Files map[string]string
+		Err   string
+	}{
+		{
+			Name:  "unexpected file",
+			Files: map[string]string{"nope.txt": "hello"},
+			Err:   `unexpected file "nope.txt"`,
+		},
+		{
+			Name: "bad hash",
+			Files: map[string]string{
+				"meta.json":  "{}",
+				"state.bin":  "data",
+				"SHA256SUMS": fmt.Sprintf("%x  meta.json\n%x  state.bin\n", make([]byte, 32), make([]byte, 32)),
+			},
+			Err: `hash check failed for`,
+		},
+		{
+			Name: "missing hash",
+			Files: map[string]string{
+				"meta.json": "{}",
+				"state.bin": "data",
+			},
+			Err: `file missing for`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			archive := tar.NewWriter(&buf)
+			for name, content := range c.Files {
+				require.NoError(t, archive.WriteHeader(&tar.Header{
+					Name: name,
+					Mode: 0600,
+					Size: int64(len(content)),
+				}))
+				_, err := archive.Write([]byte(content))
+				require.NoError(t, err)
+			}
+			require.NoError(t, archive.Close())
+
+			var metadata raft.SnapshotMeta
+			err := read(&buf, &metadata, ioutil.Discard)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.Err)
+		})
+	}
+}
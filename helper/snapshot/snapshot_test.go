@@ -0,0 +1,181 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/raft"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFSM is a simple FSM keeping the data of the applied logs
+type mockFSM struct {
+	sync.Mutex
+	logs [][]byte
+}
+
+func (m *mockFSM) Apply(log *raft.Log) interface{} {
+	m.Lock()
+	defer m.Unlock()
+	m.logs = append(m.logs, log.Data)
+	return nil
+}
+
+func (m *mockFSM) Snapshot() (raft.FSMSnapshot, error) {
+	m.Lock()
+	defer m.Unlock()
+	logs := make([][]byte, len(m.logs))
+	copy(logs, m.logs)
+	return &mockSnapshot{logs}, nil
+}
+
+func (m *mockFSM) Restore(in io.ReadCloser) error {
+	m.Lock()
+	defer m.Unlock()
+	defer in.Close()
+	m.logs = nil
+	return json.NewDecoder(in).Decode(&m.logs)
+}
+
+func (m *mockFSM) Logs() [][]byte {
+	m.Lock()
+	defer m.Unlock()
+	return m.logs
+}
+
+type mockSnapshot struct {
+	logs [][]byte
+}
+
+func (s *mockSnapshot) Persist(sink raft.SnapshotSink) error {
+	if err := json.NewEncoder(sink).Encode(s.logs); err != nil {
+		sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+func (s *mockSnapshot) Release() {}
+
+// makeRaft returns a single server Raft cluster with the given FSM
+func makeRaft(t *testing.T, fsm raft.FSM) (*raft.Raft, func()) {
+	conf := raft.DefaultConfig()
+	conf.LocalID = raft.ServerID("server1")
+	conf.HeartbeatTimeout = 50 * time.Millisecond
+	conf.ElectionTimeout = 50 * time.Millisecond
+	conf.LeaderLeaseTimeout = 50 * time.Millisecond
+	conf.CommitTimeout = 5 * time.Millisecond
+	conf.Logger = testlog.Logger(t)
+
+	logs := raft.NewInmemStore()
+	snaps := raft.NewInmemSnapshotStore()
+	addr, trans := raft.NewInmemTransport("")
+
+	configuration := raft.Configuration{
+		Servers: []raft.Server{{ID: conf.LocalID, Address: addr}},
+	}
+	require.NoError(t, raft.BootstrapCluster(conf, logs, logs, snaps, trans, configuration))
+
+	r, err := raft.NewRaft(conf, fsm, logs, logs, snaps, trans)
+	require.NoError(t, err)
+
+	select {
+	case <-r.LeaderCh():
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for leadership")
+	}
+	return r, func() { r.Shutdown() }
+}
+
+func applyLogs(t *testing.T, r *raft.Raft, start, count int) {
+	for i := start; i < start+count; i++ {
+		data := []byte(fmt.Sprintf("log-%d", i))
+		require.NoError(t, r.Apply(data, time.Second).Error())
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	require := require.New(t)
+	logger := testlog.Logger(t)
+
+	before := &mockFSM{}
+	r, shutdown := makeRaft(t, before)
+	defer shutdown()
+	applyLogs(t, r, 0, 10)
+
+	// Take a snapshot and read it back
+	snap, err := New(logger, r)
+	require.NoError(err)
+	defer snap.Close()
+	require.NotZero(snap.Index())
+	require.True(strings.HasPrefix(snap.Checksum(), "sha-256="))
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, snap)
+	require.NoError(err)
+
+	meta, err := Verify(bytes.NewReader(buf.Bytes()))
+	require.NoError(err)
+	require.Equal(snap.Index(), meta.Index)
+
+	// Restore the snapshot on another cluster with diverging logs
+	after := &mockFSM{}
+	r2, shutdown2 := makeRaft(t, after)
+	defer shutdown2()
+	applyLogs(t, r2, 100, 3)
+
+	require.NoError(Restore(logger, bytes.NewReader(buf.Bytes()), r2))
+	require.Equal(before.Logs(), after.Logs())
+}
+
+func TestSnapshot_Nil(t *testing.T) {
+	require := require.New(t)
+	var snap *Snapshot
+
+	require.Zero(snap.Index())
+	require.Empty(snap.Checksum())
+	_, err := ioutil.ReadAll(snap)
+	require.NoError(err)
+	require.NoError(snap.Close())
+}
+
+func TestSnapshot_BadRestore(t *testing.T) {
+	require := require.New(t)
+	logger := testlog.Logger(t)
+
+	before := &mockFSM{}
+	r, shutdown := makeRaft(t, before)
+	defer shutdown()
+	applyLogs(t, r, 0, 10)
+
+	snap, err := New(logger, r)
+	require.NoError(err)
+	defer snap.Close()
+	data, err := ioutil.ReadAll(snap)
+	require.NoError(err)
+
+	after := &mockFSM{}
+	r2, shutdown2 := makeRaft(t, after)
+	defer shutdown2()
+	applyLogs(t, r2, 100, 3)
+	expected := after.Logs()
+
+	// A truncated snapshot is rejected before anything is restored
+	err = Restore(logger, bytes.NewReader(data[:len(data)/2]), r2)
+	require.Error(err)
+	require.Equal(expected, after.Logs())
+
+	// So is garbage
+	err = Restore(logger, strings.NewReader("not a snapshot"), r2)
+	require.Error(err)
+	require.Contains(err.Error(), "failed to decompress")
+	require.Equal(expected, after.Logs())
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"log"
 	"os"
+
+	hclog "github.com/hashicorp/go-hclog"
 )
 
 // UseStdout returns true if NOMAD_TEST_STDOUT=1 and sends logs to stdout.
@@ -56,3 +58,11 @@ func WithPrefix(t LogPrinter, prefix string) *log.Logger {
 func Logger(t LogPrinter) *log.Logger {
 	return WithPrefix(t, "")
 }
+
+// HCLogger returns a new test hc-logger at the trace level.
+func HCLogger(t LogPrinter) hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Level:  hclog.Trace,
+		Output: NewWriter(t),
+	})
+}
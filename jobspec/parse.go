@@ -106,6 +106,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	delete(m, "affinity")
 	delete(m, "meta")
 	delete(m, "migrate")
+	delete(m, "multiregion")
 	delete(m, "parameterized")
 	delete(m, "periodic")
 	delete(m, "reschedule")
@@ -141,8 +142,10 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"id",
 		"meta",
 		"migrate",
+		"multiregion",
 		"name",
 		"namespace",
+		"node_pool",
 		"parameterized",
 		"periodic",
 		"priority",
@@ -179,6 +182,13 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// If we have a multiregion definition, then parse that
+	if o := listVal.Filter("multiregion"); len(o.Items) > 0 {
+		if err := parseMultiregion(&result.Multiregion, o); err != nil {
+			return multierror.Prefix(err, "multiregion ->")
+		}
+	}
+
 	// If we have a periodic definition, then parse that
 	if o := listVal.Filter("periodic"); len(o.Items) > 0 {
 		if err := parsePeriodic(&result.Periodic, o); err != nil {
@@ -315,6 +325,9 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"vault",
 			"migrate",
 			"spread",
+			"volume",
+			"network",
+			"service",
 		}
 		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -334,6 +347,9 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "vault")
 		delete(m, "migrate")
 		delete(m, "spread")
+		delete(m, "volume")
+		delete(m, "network")
+		delete(m, "service")
 
 		// Build the group with the basic decode
 		var g api.TaskGroup
@@ -412,6 +428,27 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// Parse any volume declarations
+		if o := listVal.Filter("volume"); len(o.Items) > 0 {
+			if err := parseVolumes(&g.Volumes, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', volume ->", n))
+			}
+		}
+
+		// Parse the network shared by the tasks
+		if o := listVal.Filter("network"); len(o.Items) > 0 {
+			if err := parseGroupNetwork(&g.Networks, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', network ->", n))
+			}
+		}
+
+		// Parse the services of the group
+		if o := listVal.Filter("service"); len(o.Items) > 0 {
+			if err := parseServices(&g.Services, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s',", n))
+			}
+		}
+
 		// Parse tasks
 		if o := listVal.Filter("task"); len(o.Items) > 0 {
 			if err := parseTasks(*result.Name, *g.Name, &g.Tasks, o); err != nil {
@@ -810,6 +847,44 @@ func parseSpreadTarget(result *[]*api.SpreadTarget, list *ast.ObjectList) error
 	return nil
 }
 
+func parseVolumes(out *map[string]*api.VolumeRequest, list *ast.ObjectList) error {
+	volumes := make(map[string]*api.VolumeRequest, len(list.Items))
+
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := volumes[n]; ok {
+			return fmt.Errorf("volume '%s' defined more than once", n)
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"type",
+			"source",
+			"read_only",
+		}
+		if err := helper.CheckHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		var v api.VolumeRequest
+		if err := mapstructure.WeakDecode(m, &v); err != nil {
+			return err
+		}
+		v.Name = n
+		volumes[n] = &v
+	}
+
+	*out = volumes
+	return nil
+}
+
 // parseBool takes an interface value and tries to convert it to a boolean and
 // returns an error if the type can't be converted.
 func parseBool(value interface{}) (bool, error) {
@@ -863,6 +938,7 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			"env",
 			"kill_timeout",
 			"leader",
+			"lifecycle",
 			"logs",
 			"meta",
 			"resources",
@@ -872,6 +948,7 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			"user",
 			"vault",
 			"kill_signal",
+			"volume_mount",
 		}
 		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
@@ -887,12 +964,14 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 		delete(m, "affinity")
 		delete(m, "dispatch_payload")
 		delete(m, "env")
+		delete(m, "lifecycle")
 		delete(m, "logs")
 		delete(m, "meta")
 		delete(m, "resources")
 		delete(m, "service")
 		delete(m, "template")
 		delete(m, "vault")
+		delete(m, "volume_mount")
 
 		// Build the task
 		var t api.Task
@@ -927,7 +1006,7 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 		}
 
 		if o := listVal.Filter("service"); len(o.Items) > 0 {
-			if err := parseServices(jobName, taskGroupName, &t, o); err != nil {
+			if err := parseServices(&t.Services, o); err != nil {
 				return multierror.Prefix(err, fmt.Sprintf("'%s',", n))
 			}
 		}
@@ -1068,12 +1147,74 @@ func parseTasks(jobName string, taskGroupName string, result *[]*api.Task, list
 			}
 		}
 
+		// If we have a lifecycle block parse that
+		if o := listVal.Filter("lifecycle"); len(o.Items) > 0 {
+			if len(o.Items) > 1 {
+				return fmt.Errorf("only one lifecycle block is allowed in a task. Number of lifecycle blocks found: %d", len(o.Items))
+			}
+			var m map[string]interface{}
+			lifecycleBlock := o.Items[0]
+
+			// Check for invalid keys
+			valid := []string{
+				"hook",
+				"sidecar",
+			}
+			if err := helper.CheckHCLKeys(lifecycleBlock.Val, valid); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', lifecycle ->", n))
+			}
+
+			if err := hcl.DecodeObject(&m, lifecycleBlock.Val); err != nil {
+				return err
+			}
+
+			t.Lifecycle = &api.TaskLifecycle{}
+			if err := mapstructure.WeakDecode(m, t.Lifecycle); err != nil {
+				return err
+			}
+		}
+
+		// Parse volume mounts
+		if o := listVal.Filter("volume_mount"); len(o.Items) > 0 {
+			if err := parseVolumeMounts(&t.VolumeMounts, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', volume_mount ->", n))
+			}
+		}
+
 		*result = append(*result, &t)
 	}
 
 	return nil
 }
 
+func parseVolumeMounts(result *[]*api.VolumeMount, list *ast.ObjectList) error {
+	for _, o := range list.Elem().Items {
+		// Check for invalid keys
+		valid := []string{
+			"volume",
+			"destination",
+			"read_only",
+		}
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return err
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+
+		var vm api.VolumeMount
+		if err := mapstructure.WeakDecode(m, &vm); err != nil {
+			return err
+		}
+
+		*result = append(*result, &vm)
+	}
+
+	return nil
+}
+
 func parseArtifacts(result *[]*api.TaskArtifact, list *ast.ObjectList) error {
 	for _, o := range list.Elem().Items {
 		// Check for invalid keys
@@ -1190,8 +1331,8 @@ func parseTemplates(result *[]*api.Template, list *ast.ObjectList) error {
 	return nil
 }
 
-func parseServices(jobName string, taskGroupName string, task *api.Task, serviceObjs *ast.ObjectList) error {
-	task.Services = make([]*api.Service, len(serviceObjs.Items))
+func parseServices(result *[]*api.Service, serviceObjs *ast.ObjectList) error {
+	*result = make([]*api.Service, len(serviceObjs.Items))
 	for idx, o := range serviceObjs.Items {
 		// Check for invalid keys
 		valid := []string{
@@ -1202,6 +1343,8 @@ func parseServices(jobName string, taskGroupName string, task *api.Task, service
 			"check",
 			"address_mode",
 			"check_restart",
+			"provider",
+			"connect",
 		}
 		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
 			return multierror.Prefix(err, fmt.Sprintf("service (%d) ->", idx))
@@ -1215,6 +1358,7 @@ func parseServices(jobName string, taskGroupName string, task *api.Task, service
 
 		delete(m, "check")
 		delete(m, "check_restart")
+		delete(m, "connect")
 
 		if err := mapstructure.WeakDecode(m, &service); err != nil {
 			return err
@@ -1246,12 +1390,276 @@ func parseServices(jobName string, taskGroupName string, task *api.Task, service
 			}
 		}
 
-		task.Services[idx] = &service
+		// Filter connect
+		if co := checkList.Filter("connect"); len(co.Items) > 0 {
+			if len(co.Items) > 1 {
+				return fmt.Errorf("connect '%s': cannot have more than 1 connect", service.Name)
+			}
+			c, err := parseConnect(co.Items[0])
+			if err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("service: '%s', connect ->", service.Name))
+			}
+			service.Connect = c
+		}
+
+		(*result)[idx] = &service
 	}
 
 	return nil
 }
 
+func parseConnect(co *ast.ObjectItem) (*api.ConsulConnect, error) {
+	valid := []string{
+		"sidecar_service",
+		"sidecar_task",
+	}
+	if err := helper.CheckHCLKeys(co.Val, valid); err != nil {
+		return nil, err
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := co.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return nil, fmt.Errorf("connect should be an object")
+	}
+
+	var connect api.ConsulConnect
+
+	if o := listVal.Filter("sidecar_service"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return nil, fmt.Errorf("only one sidecar_service block is allowed")
+		}
+		s, err := parseSidecarService(o.Items[0])
+		if err != nil {
+			return nil, multierror.Prefix(err, "sidecar_service ->")
+		}
+		connect.SidecarService = s
+	}
+
+	if o := listVal.Filter("sidecar_task"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return nil, fmt.Errorf("only one sidecar_task block is allowed")
+		}
+		t, err := parseSidecarTask(o.Items[0])
+		if err != nil {
+			return nil, multierror.Prefix(err, "sidecar_task ->")
+		}
+		connect.SidecarTask = t
+	}
+
+	return &connect, nil
+}
+
+func parseSidecarService(o *ast.ObjectItem) (*api.ConsulSidecarService, error) {
+	valid := []string{
+		"tags",
+		"port",
+		"proxy",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return nil, err
+	}
+	delete(m, "proxy")
+
+	var sidecar api.ConsulSidecarService
+	if err := mapstructure.WeakDecode(m, &sidecar); err != nil {
+		return nil, err
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return nil, fmt.Errorf("sidecar_service should be an object")
+	}
+
+	if po := listVal.Filter("proxy"); len(po.Items) > 0 {
+		if len(po.Items) > 1 {
+			return nil, fmt.Errorf("only one proxy block is allowed")
+		}
+		p, err := parseProxy(po.Items[0])
+		if err != nil {
+			return nil, multierror.Prefix(err, "proxy ->")
+		}
+		sidecar.Proxy = p
+	}
+
+	return &sidecar, nil
+}
+
+func parseProxy(o *ast.ObjectItem) (*api.ConsulProxy, error) {
+	valid := []string{
+		"local_service_address",
+		"local_service_port",
+		"upstreams",
+		"config",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return nil, err
+	}
+	delete(m, "upstreams")
+	delete(m, "config")
+
+	var proxy api.ConsulProxy
+	if err := mapstructure.WeakDecode(m, &proxy); err != nil {
+		return nil, err
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return nil, fmt.Errorf("proxy should be an object")
+	}
+
+	for idx, uo := range listVal.Filter("upstreams").Items {
+		valid := []string{
+			"destination_name",
+			"local_bind_port",
+		}
+		if err := helper.CheckHCLKeys(uo.Val, valid); err != nil {
+			return nil, multierror.Prefix(err, fmt.Sprintf("upstreams (%d) ->", idx))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, uo.Val); err != nil {
+			return nil, err
+		}
+
+		var upstream api.ConsulUpstream
+		if err := mapstructure.WeakDecode(m, &upstream); err != nil {
+			return nil, err
+		}
+		proxy.Upstreams = append(proxy.Upstreams, &upstream)
+	}
+
+	if co := listVal.Filter("config"); len(co.Items) > 0 {
+		for _, o := range co.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return nil, err
+			}
+			if err := mapstructure.WeakDecode(m, &proxy.Config); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &proxy, nil
+}
+
+func parseSidecarTask(o *ast.ObjectItem) (*api.SidecarTask, error) {
+	valid := []string{
+		"driver",
+		"user",
+		"config",
+		"env",
+		"resources",
+		"meta",
+		"kill_timeout",
+		"logs",
+		"shutdown_delay",
+		"kill_signal",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return nil, err
+	}
+	delete(m, "config")
+	delete(m, "env")
+	delete(m, "resources")
+	delete(m, "meta")
+	delete(m, "logs")
+
+	var t api.SidecarTask
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &t,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := dec.Decode(m); err != nil {
+		return nil, err
+	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return nil, fmt.Errorf("sidecar_task should be an object")
+	}
+
+	// The config, env and meta blocks are lists in HCL that are merged
+	for key, result := range map[string]interface{}{
+		"config": &t.Config,
+		"env":    &t.Env,
+		"meta":   &t.Meta,
+	} {
+		if bo := listVal.Filter(key); len(bo.Items) > 0 {
+			for _, o := range bo.Elem().Items {
+				var m map[string]interface{}
+				if err := hcl.DecodeObject(&m, o.Val); err != nil {
+					return nil, err
+				}
+				if err := mapstructure.WeakDecode(m, result); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if ro := listVal.Filter("resources"); len(ro.Items) > 0 {
+		var r api.Resources
+		if err := parseResources(&r, ro); err != nil {
+			return nil, err
+		}
+		t.Resources = &r
+	}
+
+	if lo := listVal.Filter("logs"); len(lo.Items) > 0 {
+		if len(lo.Items) > 1 {
+			return nil, fmt.Errorf("only one logs block is allowed")
+		}
+		valid := []string{
+			"max_files",
+			"max_file_size",
+		}
+		if err := helper.CheckHCLKeys(lo.Items[0].Val, valid); err != nil {
+			return nil, multierror.Prefix(err, "logs ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, lo.Items[0].Val); err != nil {
+			return nil, err
+		}
+
+		var log api.LogConfig
+		if err := mapstructure.WeakDecode(m, &log); err != nil {
+			return nil, err
+		}
+		t.LogConfig = &log
+	}
+
+	return &t, nil
+}
+
 func parseChecks(service *api.Service, checkObjs *ast.ObjectList) error {
 	service.Checks = make([]api.ServiceCheck, len(checkObjs.Items))
 	for idx, co := range checkObjs.Items {
@@ -1412,7 +1820,9 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		"iops",
 		"disk",
 		"memory",
+		"memory_max",
 		"network",
+		"device",
 	}
 	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "resources ->")
@@ -1423,6 +1833,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		return err
 	}
 	delete(m, "network")
+	delete(m, "device")
 
 	if err := mapstructure.WeakDecode(m, result); err != nil {
 		return err
@@ -1465,12 +1876,116 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		result.Networks = []*api.NetworkResource{&r}
 	}
 
+	// Parse the device resources
+	if o := listVal.Filter("device"); len(o.Items) > 0 {
+		if err := parseDevices(&result.Devices, o); err != nil {
+			return multierror.Prefix(err, "resources, device ->")
+		}
+	}
+
+	return nil
+}
+
+func parseDevices(result *[]*api.RequestedDevice, list *ast.ObjectList) error {
+	for _, o := range list.Items {
+		if len(o.Keys) == 0 {
+			return fmt.Errorf("missing device name")
+		}
+		name := o.Keys[0].Token.Value().(string)
+
+		// Check for invalid keys
+		valid := []string{
+			"count",
+			"constraint",
+			"affinity",
+		}
+		if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return err
+		}
+		delete(m, "constraint")
+		delete(m, "affinity")
+
+		var r api.RequestedDevice
+		if err := mapstructure.WeakDecode(m, &r); err != nil {
+			return err
+		}
+		r.Name = name
+
+		var listVal *ast.ObjectList
+		if ot, ok := o.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("device '%s': should be an object", name)
+		}
+
+		// Parse constraints
+		if o := listVal.Filter("constraint"); len(o.Items) > 0 {
+			if err := parseConstraints(&r.Constraints, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', constraint ->", name))
+			}
+		}
+
+		// Parse affinities
+		if o := listVal.Filter("affinity"); len(o.Items) > 0 {
+			if err := parseAffinities(&r.Affinities, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', affinity ->", name))
+			}
+		}
+
+		*result = append(*result, &r)
+	}
+
+	return nil
+}
+
+func parseGroupNetwork(result *[]*api.NetworkResource, list *ast.ObjectList) error {
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'network' block allowed")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"mode",
+		"mbits",
+		"port",
+	}
+	if err := helper.CheckHCLKeys(list.Items[0].Val, valid); err != nil {
+		return err
+	}
+
+	var r api.NetworkResource
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, list.Items[0].Val); err != nil {
+		return err
+	}
+	if err := mapstructure.WeakDecode(m, &r); err != nil {
+		return err
+	}
+
+	var networkObj *ast.ObjectList
+	if ot, ok := list.Items[0].Val.(*ast.ObjectType); ok {
+		networkObj = ot.List
+	} else {
+		return fmt.Errorf("network: should be an object")
+	}
+
+	if err := parsePorts(networkObj, &r); err != nil {
+		return multierror.Prefix(err, "ports ->")
+	}
+
+	*result = []*api.NetworkResource{&r}
 	return nil
 }
 
 func parsePorts(networkObj *ast.ObjectList, nw *api.NetworkResource) error {
 	// Check for invalid keys
 	valid := []string{
+		"mode",
 		"mbits",
 		"port",
 	}
@@ -1535,6 +2050,7 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 		"healthy_deadline",
 		"progress_deadline",
 		"auto_revert",
+		"auto_promote",
 		"canary",
 	}
 	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
@@ -1636,6 +2152,143 @@ func parsePeriodic(result **api.PeriodicConfig, list *ast.ObjectList) error {
 	return nil
 }
 
+func parseMultiregion(result **api.Multiregion, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'multiregion' block allowed per job")
+	}
+
+	// Get our multiregion object
+	o := list.Items[0]
+
+	// We need this later
+	var listVal *ast.ObjectList
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("multiregion should be an object")
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"strategy",
+		"region",
+	}
+	if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+		return err
+	}
+
+	var mr api.Multiregion
+
+	// Parse the strategy
+	if o := listVal.Filter("strategy"); len(o.Items) > 0 {
+		if err := parseMultiregionStrategy(&mr.Strategy, o); err != nil {
+			return multierror.Prefix(err, "strategy ->")
+		}
+	}
+
+	// Parse the regions
+	if o := listVal.Filter("region"); len(o.Items) > 0 {
+		if err := parseMultiregionRegions(&mr.Regions, o); err != nil {
+			return multierror.Prefix(err, "region ->")
+		}
+	}
+
+	*result = &mr
+	return nil
+}
+
+func parseMultiregionStrategy(result **api.MultiregionStrategy, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'strategy' block allowed per multiregion")
+	}
+
+	// Get our strategy object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"max_parallel",
+		"on_failure",
+	}
+	if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	var s api.MultiregionStrategy
+	if err := mapstructure.WeakDecode(m, &s); err != nil {
+		return err
+	}
+	*result = &s
+	return nil
+}
+
+func parseMultiregionRegions(result *[]*api.MultiregionRegion, list *ast.ObjectList) error {
+	seen := make(map[string]struct{})
+	for _, item := range list.Items {
+		n := item.Keys[0].Token.Value().(string)
+
+		// Make sure we haven't already found this
+		if _, ok := seen[n]; ok {
+			return fmt.Errorf("region '%s' defined more than once", n)
+		}
+		seen[n] = struct{}{}
+
+		// We need this later
+		var listVal *ast.ObjectList
+		if ot, ok := item.Val.(*ast.ObjectType); ok {
+			listVal = ot.List
+		} else {
+			return fmt.Errorf("region should be an object")
+		}
+
+		// Check for invalid keys
+		valid := []string{
+			"count",
+			"datacenters",
+			"meta",
+		}
+		if err := helper.CheckHCLKeys(listVal, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", n))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+		delete(m, "meta")
+
+		// Decode the region
+		r := api.MultiregionRegion{Name: n}
+		if err := mapstructure.WeakDecode(m, &r); err != nil {
+			return err
+		}
+
+		// Parse out meta fields. These are in HCL as a list so we need
+		// to iterate over them and merge them.
+		if metaO := listVal.Filter("meta"); len(metaO.Items) > 0 {
+			for _, o := range metaO.Elem().Items {
+				var m map[string]interface{}
+				if err := hcl.DecodeObject(&m, o.Val); err != nil {
+					return err
+				}
+				if err := mapstructure.WeakDecode(m, &r.Meta); err != nil {
+					return err
+				}
+			}
+		}
+
+		*result = append(*result, &r)
+	}
+	return nil
+}
+
 func parseVault(result *api.Vault, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) == 0 {
@@ -30,6 +30,7 @@ func TestParse(t *testing.T) {
 				Priority:    helper.IntToPtr(52),
 				AllAtOnce:   helper.BoolToPtr(true),
 				Datacenters: []string{"us2", "eu1"},
+				NodePool:    helper.StringToPtr("batch"),
 				Region:      helper.StringToPtr("fooregion"),
 				Namespace:   helper.StringToPtr("foonamespace"),
 				VaultToken:  helper.StringToPtr("foo"),
@@ -80,6 +81,7 @@ func TestParse(t *testing.T) {
 					HealthyDeadline:  helper.TimeToPtr(10 * time.Minute),
 					ProgressDeadline: helper.TimeToPtr(10 * time.Minute),
 					AutoRevert:       helper.BoolToPtr(true),
+					AutoPromote:      helper.BoolToPtr(true),
 					Canary:           helper.IntToPtr(1),
 				},
 
@@ -164,6 +166,7 @@ func TestParse(t *testing.T) {
 							HealthyDeadline:  helper.TimeToPtr(1 * time.Minute),
 							ProgressDeadline: helper.TimeToPtr(1 * time.Minute),
 							AutoRevert:       helper.BoolToPtr(false),
+							AutoPromote:      helper.BoolToPtr(false),
 							Canary:           helper.IntToPtr(2),
 						},
 						Migrate: &api.MigrateStrategy{
@@ -221,8 +224,9 @@ func TestParse(t *testing.T) {
 									"LOREM": "ipsum",
 								},
 								Resources: &api.Resources{
-									CPU:      helper.IntToPtr(500),
-									MemoryMB: helper.IntToPtr(128),
+									CPU:         helper.IntToPtr(500),
+									MemoryMB:    helper.IntToPtr(128),
+									MemoryMaxMB: helper.IntToPtr(256),
 									Networks: []*api.NetworkResource{
 										{
 											MBits:         helper.IntToPtr(100),
@@ -660,6 +664,227 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"task-lifecycle.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "init",
+								Driver: "docker",
+								Lifecycle: &api.TaskLifecycle{
+									Hook: "prestart",
+								},
+							},
+							{
+								Name:   "proxy",
+								Driver: "docker",
+								Lifecycle: &api.TaskLifecycle{
+									Hook:    "prestart",
+									Sidecar: true,
+								},
+							},
+							{
+								Name:   "main",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"devices.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Tasks: []*api.Task{
+							{
+								Name:   "train",
+								Driver: "docker",
+								Resources: &api.Resources{
+									Devices: []*api.RequestedDevice{
+										{
+											Name:  "nvidia/gpu",
+											Count: helper.Uint64ToPtr(2),
+											Constraints: []*api.Constraint{
+												{
+													LTarget: "${device.attr.memory}",
+													RTarget: "8192",
+													Operand: ">=",
+												},
+											},
+											Affinities: []*api.Affinity{
+												{
+													LTarget: "${device.model}",
+													RTarget: "Tesla K80",
+													Operand: "=",
+													Weight:  50,
+												},
+											},
+										},
+										{
+											Name: "fpga",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"volumes.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Volumes: map[string]*api.VolumeRequest{
+							"shared": {
+								Name:   "shared",
+								Type:   "host",
+								Source: "shared_data",
+							},
+							"certs": {
+								Name:     "certs",
+								Type:     "host",
+								Source:   "tls_certs",
+								ReadOnly: true,
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "web",
+								Driver: "docker",
+								VolumeMounts: []*api.VolumeMount{
+									{
+										Volume:      "shared",
+										Destination: "/srv/shared",
+									},
+									{
+										Volume:      "certs",
+										Destination: "/etc/ssl/certs",
+										ReadOnly:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"group-network.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Networks: []*api.NetworkResource{
+							{
+								Mode:          "bridge",
+								MBits:         helper.IntToPtr(20),
+								ReservedPorts: []api.Port{{Label: "http", Value: 8080, To: 80}},
+								DynamicPorts: []api.Port{
+									{Label: "admin", To: 9090},
+									{Label: "metrics", HostNetwork: "private"},
+								},
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "web",
+								Driver: "exec",
+								Services: []*api.Service{
+									{
+										Name:      "web",
+										PortLabel: "http",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"group-service-connect.hcl",
+			&api.Job{
+				ID:   helper.StringToPtr("foo"),
+				Name: helper.StringToPtr("foo"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: helper.StringToPtr("bar"),
+						Networks: []*api.NetworkResource{
+							{
+								Mode:         "bridge",
+								DynamicPorts: []api.Port{{Label: "http", To: 8080}},
+							},
+						},
+						Services: []*api.Service{
+							{
+								Name:      "web",
+								PortLabel: "http",
+								Connect: &api.ConsulConnect{
+									SidecarService: &api.ConsulSidecarService{
+										Tags: []string{"proxy"},
+										Proxy: &api.ConsulProxy{
+											LocalServicePort: 8080,
+											Upstreams: []*api.ConsulUpstream{
+												{
+													DestinationName: "db",
+													LocalBindPort:   5432,
+												},
+											},
+											Config: map[string]interface{}{
+												"protocol": "http",
+											},
+										},
+									},
+									SidecarTask: &api.SidecarTask{
+										Driver:        "raw_exec",
+										KillTimeout:   helper.TimeToPtr(10 * time.Second),
+										ShutdownDelay: helper.TimeToPtr(2 * time.Second),
+										Resources: &api.Resources{
+											CPU:      helper.IntToPtr(500),
+											MemoryMB: helper.IntToPtr(256),
+										},
+										LogConfig: &api.LogConfig{
+											MaxFiles: helper.IntToPtr(3),
+										},
+										Env: map[string]string{
+											"FOO": "bar",
+										},
+									},
+								},
+							},
+						},
+						Tasks: []*api.Task{
+							{
+								Name:   "web",
+								Driver: "exec",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"service-check-driver-address.hcl",
 			&api.Job{
@@ -691,6 +916,7 @@ func TestParse(t *testing.T) {
 										Name:        "random-service",
 										PortLabel:   "9000",
 										AddressMode: "driver",
+										Provider:    "nomad",
 										Checks: []api.ServiceCheck{
 											{
 												Name:        "random-check",
@@ -846,6 +1072,34 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"multiregion.hcl",
+			&api.Job{
+				ID:          helper.StringToPtr("foo"),
+				Name:        helper.StringToPtr("foo"),
+				Datacenters: []string{"dc1"},
+				Multiregion: &api.Multiregion{
+					Strategy: &api.MultiregionStrategy{
+						MaxParallel: helper.IntToPtr(1),
+						OnFailure:   helper.StringToPtr("fail_all"),
+					},
+					Regions: []*api.MultiregionRegion{
+						{
+							Name:        "west",
+							Count:       helper.IntToPtr(2),
+							Datacenters: []string{"west-1"},
+							Meta:        map[string]string{"region_code": "W"},
+						},
+						{
+							Name:        "east",
+							Count:       helper.IntToPtr(1),
+							Datacenters: []string{"east-1", "east-2"},
+						},
+					},
+				},
+			},
+			false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -5,6 +5,7 @@ job "binstore-storagelocker" {
   priority    = 52
   all_at_once = true
   datacenters = ["us2", "eu1"]
+  node_pool   = "batch"
   vault_token = "foo"
 
   meta {
@@ -42,6 +43,7 @@ job "binstore-storagelocker" {
     healthy_deadline = "10m"
     progress_deadline = "10m"
     auto_revert = true
+    auto_promote = true
     canary = 1
   }
 
@@ -84,6 +86,7 @@ job "binstore-storagelocker" {
         healthy_deadline = "1m"
         progress_deadline = "1m"
         auto_revert = false
+        auto_promote = false
         canary = 2
     }
 
@@ -169,8 +172,9 @@ job "binstore-storagelocker" {
       }
 
       resources {
-        cpu    = 500
-        memory = 128
+        cpu        = 500
+        memory     = 128
+        memory_max = 256
 
         network {
           mbits = "100"
@@ -0,0 +1,27 @@
+job "foo" {
+  group "bar" {
+    task "train" {
+      driver = "docker"
+
+      resources {
+        device "nvidia/gpu" {
+          count = 2
+
+          constraint {
+            attribute = "${device.attr.memory}"
+            operator  = ">="
+            value     = "8192"
+          }
+
+          affinity {
+            attribute = "${device.model}"
+            value     = "Tesla K80"
+            weight    = 50
+          }
+        }
+
+        device "fpga" {}
+      }
+    }
+  }
+}
@@ -0,0 +1,30 @@
+job "foo" {
+  group "bar" {
+    network {
+      mode  = "bridge"
+      mbits = 20
+
+      port "http" {
+        static = 8080
+        to     = 80
+      }
+
+      port "admin" {
+        to = 9090
+      }
+
+      port "metrics" {
+        host_network = "private"
+      }
+    }
+
+    task "web" {
+      driver = "exec"
+
+      service {
+        name = "web"
+        port = "http"
+      }
+    }
+  }
+}
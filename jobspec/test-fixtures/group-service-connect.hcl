@@ -0,0 +1,58 @@
+job "foo" {
+  group "bar" {
+    network {
+      mode = "bridge"
+
+      port "http" {
+        to = 8080
+      }
+    }
+
+    service {
+      name = "web"
+      port = "http"
+
+      connect {
+        sidecar_service {
+          tags = ["proxy"]
+
+          proxy {
+            local_service_port = 8080
+
+            upstreams {
+              destination_name = "db"
+              local_bind_port  = 5432
+            }
+
+            config {
+              protocol = "http"
+            }
+          }
+        }
+
+        sidecar_task {
+          driver         = "raw_exec"
+          kill_timeout   = "10s"
+          shutdown_delay = "2s"
+
+          resources {
+            cpu    = 500
+            memory = 256
+          }
+
+          logs {
+            max_files = 3
+          }
+
+          env {
+            FOO = "bar"
+          }
+        }
+      }
+    }
+
+    task "web" {
+      driver = "exec"
+    }
+  }
+}
@@ -23,6 +23,7 @@ job "address_mode_driver" {
             port = "9000"
 
             address_mode = "driver"
+            provider     = "nomad"
 
             check {
               name     = "random-check"
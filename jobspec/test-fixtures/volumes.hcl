@@ -0,0 +1,29 @@
+job "foo" {
+  group "bar" {
+    volume "shared" {
+      type   = "host"
+      source = "shared_data"
+    }
+
+    volume "certs" {
+      type      = "host"
+      source    = "tls_certs"
+      read_only = true
+    }
+
+    task "web" {
+      driver = "docker"
+
+      volume_mount {
+        volume      = "shared"
+        destination = "/srv/shared"
+      }
+
+      volume_mount {
+        volume      = "certs"
+        destination = "/etc/ssl/certs"
+        read_only   = true
+      }
+    }
+  }
+}
@@ -0,0 +1,225 @@
+package jobspec2
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+)
+
+// formatIndent is the indentation of each level of nesting
+const formatIndent = "  "
+
+// formatItem is a token, or a sequence of tokens kept as is such as a quoted
+// string or a heredoc, of a formatted line
+type formatItem struct {
+	Type  hclsyntax.TokenType
+	Bytes []byte
+}
+
+// Format rewrites the HCL2 file into the canonical style: the lines are
+// indented by two spaces per level of nesting, the tokens are separated by
+// single spaces where needed, and the equal signs of consecutive attributes
+// are aligned. Strings, heredocs and comments are kept as is. Files which
+// are not valid HCL2 are not formatted.
+func Format(src []byte, path string) ([]byte, hcl.Diagnostics) {
+	if _, diags := hclsyntax.ParseConfig(src, path, hcl.Pos{Line: 1, Column: 1}); diags.HasErrors() {
+		return nil, diags
+	}
+	tokens, diags := hclsyntax.LexConfig(src, path, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	lines := formatLines(src, tokens)
+
+	// Indent the lines by their depth of nesting
+	indents := make([]int, len(lines))
+	depth := 0
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		indents[i] = depth
+		if isCloser(line[0].Type) && depth > 0 {
+			indents[i]--
+		}
+		for _, item := range line {
+			switch {
+			case isOpener(item.Type):
+				depth++
+			case isCloser(item.Type) && depth > 0:
+				depth--
+			}
+		}
+	}
+
+	// Align the equal signs of consecutive attributes with the same indent
+	widths := make([]int, len(lines))
+	for start := 0; start < len(lines); {
+		if !isAttribute(lines[start]) {
+			start++
+			continue
+		}
+		end, width := start, 0
+		for ; end < len(lines) && isAttribute(lines[end]) && indents[end] == indents[start]; end++ {
+			if w := len(lines[end][0].Bytes); w > width {
+				width = w
+			}
+		}
+		for i := start; i < end; i++ {
+			widths[i] = width
+		}
+		start = end
+	}
+
+	var buf bytes.Buffer
+	for i, line := range lines {
+		if len(line) == 0 {
+			buf.WriteString("\n")
+			continue
+		}
+		buf.WriteString(strings.Repeat(formatIndent, indents[i]))
+		for j, item := range line {
+			if j > 0 && spaceBetween(line, j) {
+				buf.WriteString(" ")
+			}
+			buf.Write(item.Bytes)
+			if j == 0 && widths[i] > 0 {
+				buf.WriteString(strings.Repeat(" ", widths[i]-len(item.Bytes)))
+			}
+		}
+		buf.WriteString("\n")
+	}
+
+	out := bytes.TrimRight(buf.Bytes(), "\n")
+	out = bytes.TrimLeft(out, "\n")
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return append(out, '\n'), nil
+}
+
+// formatLines splits the tokens in lines of items. Quoted strings and
+// heredocs are kept as is from the source, including the interpolations.
+func formatLines(src []byte, tokens hclsyntax.Tokens) [][]formatItem {
+	var lines [][]formatItem
+	var line []formatItem
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.Type {
+		case hclsyntax.TokenEOF:
+			if len(line) != 0 {
+				lines = append(lines, line)
+			}
+			return lines
+		case hclsyntax.TokenNewline:
+			lines = append(lines, line)
+			line = nil
+		case hclsyntax.TokenComment:
+			// Line comments include their newline
+			comment := bytes.TrimRight(tok.Bytes, "\r\n")
+			line = append(line, formatItem{Type: tok.Type, Bytes: comment})
+			if len(comment) != len(tok.Bytes) {
+				lines = append(lines, line)
+				line = nil
+			}
+		case hclsyntax.TokenOQuote, hclsyntax.TokenOHeredoc:
+			end := matchingToken(tokens, i)
+			line = append(line, formatItem{
+				Type:  tok.Type,
+				Bytes: src[tok.Range.Start.Byte:tokens[end].Range.End.Byte],
+			})
+			i = end
+		default:
+			line = append(line, formatItem{Type: tok.Type, Bytes: tok.Bytes})
+		}
+	}
+
+	if len(line) != 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// matchingToken returns the index of the token closing the quote or heredoc
+// opened at start
+func matchingToken(tokens hclsyntax.Tokens, start int) int {
+	open := tokens[start].Type
+	closing := hclsyntax.TokenCQuote
+	if open == hclsyntax.TokenOHeredoc {
+		closing = hclsyntax.TokenCHeredoc
+	}
+
+	nesting := 0
+	for i := start; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case open:
+			nesting++
+		case closing:
+			nesting--
+			if nesting == 0 {
+				return i
+			}
+		}
+	}
+	return len(tokens) - 1
+}
+
+// spaceBetween returns whether a space separates the item at index i of the
+// line from the previous one
+func spaceBetween(line []formatItem, i int) bool {
+	prev, next := line[i-1].Type, line[i].Type
+
+	switch prev {
+	case hclsyntax.TokenOParen, hclsyntax.TokenOBrack, hclsyntax.TokenDot, hclsyntax.TokenBang:
+		return false
+	case hclsyntax.TokenMinus:
+		// Unary minus
+		if i == 1 || !isOperand(line[i-2].Type) {
+			return false
+		}
+	}
+
+	switch next {
+	case hclsyntax.TokenCParen, hclsyntax.TokenCBrack, hclsyntax.TokenComma,
+		hclsyntax.TokenDot, hclsyntax.TokenEllipsis:
+		return false
+	case hclsyntax.TokenOParen, hclsyntax.TokenOBrack:
+		// Function calls and indexes
+		return !isOperand(prev)
+	case hclsyntax.TokenCBrace:
+		return prev != hclsyntax.TokenOBrace
+	}
+
+	return true
+}
+
+// isOperand returns whether the token ends an operand, as opposed to an
+// operator or an opening bracket
+func isOperand(t hclsyntax.TokenType) bool {
+	switch t {
+	case hclsyntax.TokenIdent, hclsyntax.TokenNumberLit, hclsyntax.TokenOQuote,
+		hclsyntax.TokenOHeredoc, hclsyntax.TokenCParen, hclsyntax.TokenCBrack,
+		hclsyntax.TokenCBrace:
+		return true
+	}
+	return false
+}
+
+func isOpener(t hclsyntax.TokenType) bool {
+	return t == hclsyntax.TokenOBrace || t == hclsyntax.TokenOBrack || t == hclsyntax.TokenOParen
+}
+
+func isCloser(t hclsyntax.TokenType) bool {
+	return t == hclsyntax.TokenCBrace || t == hclsyntax.TokenCBrack || t == hclsyntax.TokenCParen
+}
+
+// isAttribute returns whether the line starts an attribute definition
+func isAttribute(line []formatItem) bool {
+	return len(line) >= 2 &&
+		line[0].Type == hclsyntax.TokenIdent &&
+		line[1].Type == hclsyntax.TokenEqual
+}
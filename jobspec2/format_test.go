@@ -0,0 +1,163 @@
+package jobspec2
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{
+			Name: "indentation",
+			Input: `
+job "example" {
+group "cache" {
+        count = 1
+   }
+}`,
+			Expected: `
+job "example" {
+  group "cache" {
+    count = 1
+  }
+}
+`,
+		},
+		{
+			Name: "spacing",
+			Input: `
+job   "example"{
+  datacenters=["dc1","dc2"]
+  priority = 50-1
+  meta {
+    a = upper ( "b" )
+    b = -1
+    c = var.list [0]
+  }
+  update {}
+}`,
+			Expected: `
+job "example" {
+  datacenters = ["dc1", "dc2"]
+  priority    = 50 - 1
+  meta {
+    a = upper("b")
+    b = -1
+    c = var.list[0]
+  }
+  update {}
+}
+`,
+		},
+		{
+			Name: "alignment",
+			Input: `
+job "example" {
+  type = "service"
+  datacenters = ["dc1"]
+
+  priority = 50 # comment
+  all_at_once = false
+}`,
+			Expected: `
+job "example" {
+  type        = "service"
+  datacenters = ["dc1"]
+
+  priority    = 50 # comment
+  all_at_once = false
+}
+`,
+		},
+		{
+			Name: "multi-line",
+			Input: `
+job "example" {
+  datacenters = [
+  "dc1",
+  "dc2",
+  ]
+  meta = {
+  a = "b"
+  long = "c"
+  }
+}`,
+			Expected: `
+job "example" {
+  datacenters = [
+    "dc1",
+    "dc2",
+  ]
+  meta = {
+    a    = "b"
+    long = "c"
+  }
+}
+`,
+		},
+		{
+			Name: "verbatim",
+			Input: `
+job "example" {
+    # A   comment
+  meta {
+    a = "${ attr.kernel.name }  b"
+  }
+  group "cache" {
+    task "redis" {
+      template {
+        data = <<EOH
+  {{ key   "a" }}
+EOH
+      }
+    }
+  }
+}`,
+			Expected: `
+job "example" {
+  # A   comment
+  meta {
+    a = "${ attr.kernel.name }  b"
+  }
+  group "cache" {
+    task "redis" {
+      template {
+        data = <<EOH
+  {{ key   "a" }}
+EOH
+      }
+    }
+  }
+}
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			out, diags := Format([]byte(strings.TrimPrefix(c.Input, "\n")), "test.hcl")
+			require.False(t, diags.HasErrors(), diags.Error())
+			require.Equal(t, strings.TrimPrefix(c.Expected, "\n"), string(out))
+
+			// Formatting is idempotent
+			again, diags := Format(out, "test.hcl")
+			require.False(t, diags.HasErrors(), diags.Error())
+			require.Equal(t, string(out), string(again))
+		})
+	}
+}
+
+func TestFormat_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, diags := Format([]byte(`job "example" {`), "test.hcl")
+	require.True(t, diags.HasErrors())
+}
@@ -0,0 +1,119 @@
+package jobspec2
+
+import (
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// functions returns the functions available to the expressions of a job file
+func functions() map[string]function.Function {
+	return map[string]function.Function{
+		"abs":        stdlib.AbsoluteFunc,
+		"coalesce":   stdlib.CoalesceFunc,
+		"concat":     stdlib.ConcatFunc,
+		"csvdecode":  stdlib.CSVDecodeFunc,
+		"format":     stdlib.FormatFunc,
+		"formatlist": stdlib.FormatListFunc,
+		"int":        stdlib.IntFunc,
+		"join":       joinFunc,
+		"jsondecode": stdlib.JSONDecodeFunc,
+		"jsonencode": stdlib.JSONEncodeFunc,
+		"length":     stdlib.LengthFunc,
+		"lower":      stdlib.LowerFunc,
+		"max":        stdlib.MaxFunc,
+		"min":        stdlib.MinFunc,
+		"replace":    replaceFunc,
+		"reverse":    stdlib.ReverseFunc,
+		"split":      splitFunc,
+		"strlen":     stdlib.StrlenFunc,
+		"substr":     stdlib.SubstrFunc,
+		"trimspace":  trimSpaceFunc,
+		"upper":      stdlib.UpperFunc,
+	}
+}
+
+// joinFunc concatenates the elements of a list of strings with a separator
+var joinFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "separator",
+			Type: cty.String,
+		},
+		{
+			Name: "list",
+			Type: cty.List(cty.String),
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		var elems []string
+		for it := args[1].ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			elems = append(elems, v.AsString())
+		}
+		return cty.StringVal(strings.Join(elems, args[0].AsString())), nil
+	},
+})
+
+// splitFunc splits a string into a list of strings around a separator
+var splitFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "separator",
+			Type: cty.String,
+		},
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.List(cty.String)),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		parts := strings.Split(args[1].AsString(), args[0].AsString())
+		elems := make([]cty.Value, len(parts))
+		for i, part := range parts {
+			elems[i] = cty.StringVal(part)
+		}
+		return cty.ListVal(elems), nil
+	},
+})
+
+// replaceFunc replaces all the occurrences of a substring
+var replaceFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+		{
+			Name: "substr",
+			Type: cty.String,
+		},
+		{
+			Name: "replace",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		out := strings.Replace(args[0].AsString(), args[1].AsString(), args[2].AsString(), -1)
+		return cty.StringVal(out), nil
+	},
+})
+
+// trimSpaceFunc removes the leading and trailing whitespace of a string
+var trimSpaceFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+	},
+	Type: function.StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		return cty.StringVal(strings.TrimSpace(args[0].AsString())), nil
+	},
+})
@@ -0,0 +1,127 @@
+package jobspec2
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/jobspec"
+)
+
+// ParseConfig is the set of inputs used to parse an HCL2 job specification.
+type ParseConfig struct {
+	// Path is the path of the job file, used in diagnostics.
+	Path string
+
+	// Body is the content of the job file.
+	Body []byte
+
+	// ArgVars are the variable values given as "name=value", as passed with
+	// the -var flag.
+	ArgVars []string
+
+	// VarFiles are the paths of the HCL files assigning variable values, as
+	// passed with the -var-file flag.
+	VarFiles []string
+
+	// Envs is the environment in the "key=value" form. Variables are read
+	// from the entries prefixed with NOMAD_VAR_.
+	Envs []string
+
+	// VarContent is the content of an HCL variable file, such as the one
+	// returned by ParseVariables. It is applied after the VarFiles.
+	VarContent []byte
+}
+
+// Parse parses the HCL2 job specification from the given reader, without any
+// variable values beyond the declared defaults.
+func Parse(path string, r io.Reader) (*api.Job, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseWithConfig(&ParseConfig{
+		Path: path,
+		Body: body,
+	})
+}
+
+// ParseFile parses the HCL2 job specification at the given path.
+func ParseFile(path string) (*api.Job, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(path, f)
+}
+
+// ParseWithConfig parses an HCL2 job specification. The variables are
+// resolved and the expressions, functions and dynamic blocks of the job are
+// evaluated, after which the job is decoded by the jobspec package.
+//
+// Interpolations that reference anything other than a variable, such as
+// "${attr.kernel.name}" or "${NOMAD_PORT_http}", are evaluated by Nomad at
+// runtime and so are kept as is.
+func ParseWithConfig(args *ParseConfig) (*api.Job, error) {
+	body, err := parseBody(args)
+	if err != nil {
+		return nil, err
+	}
+
+	vars, diags := decodeVariables(body, args)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	r := &renderer{
+		src: args.Body,
+		buf: new(bytes.Buffer),
+	}
+	ctx := &hcl.EvalContext{
+		Variables: vars,
+		Functions: functions(),
+	}
+	if diags := r.renderBody(body, ctx, variableBlock); diags.HasErrors() {
+		return nil, diags
+	}
+
+	return jobspec.Parse(r.buf)
+}
+
+// ParseVariables resolves the input variables of an HCL2 job specification
+// and returns the values assigned by the environment, the variable files and
+// content and the arguments, as the content of an HCL variable file. Parsing
+// the job with the returned content as its only input yields the same job.
+func ParseVariables(args *ParseConfig) (string, error) {
+	body, err := parseBody(args)
+	if err != nil {
+		return "", err
+	}
+
+	vars, diags := resolveVariables(body, args)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	return formatVariables(vars), nil
+}
+
+// parseBody parses the HCL2 syntax of the job specification
+func parseBody(args *ParseConfig) (*hclsyntax.Body, error) {
+	file, diags := hclsyntax.ParseConfig(args.Body, args.Path, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil, fmt.Errorf("unexpected body type %T", file.Body)
+	}
+	return body, nil
+}
@@ -0,0 +1,195 @@
+package jobspec2
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/jobspec"
+	"github.com/stretchr/testify/require"
+)
+
+func parseFixture(t *testing.T, file string, args *ParseConfig) (*api.Job, error) {
+	path := filepath.Join("./test-fixtures", file)
+	src, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	if args == nil {
+		args = &ParseConfig{}
+	}
+	args.Path = path
+	args.Body = src
+	return ParseWithConfig(args)
+}
+
+func TestParse_HCL1Compatible(t *testing.T) {
+	t.Parallel()
+
+	// Job files without HCL1 only syntax parse to the same job
+	files := []string{
+		"basic.hcl",
+		"migrate-job.hcl",
+		"periodic-cron.hcl",
+		"parameterized_job.hcl",
+		"vault_inheritance.hcl",
+	}
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			path := filepath.Join("../jobspec/test-fixtures", file)
+			expected, err := jobspec.ParseFile(path)
+			require.NoError(t, err)
+
+			actual, err := ParseFile(path)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestParse_Variables(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	job, err := parseFixture(t, "variables.hcl", &ParseConfig{
+		ArgVars: []string{"env=prod"},
+	})
+	require.NoError(err)
+
+	require.Equal([]string{"dc1"}, job.Datacenters)
+	require.Equal("PROD", job.Meta["env"])
+	require.Equal(1, *job.TaskGroups[0].Count)
+
+	task := job.TaskGroups[0].Tasks[0]
+	require.Equal("redis:3.2", task.Config["image"])
+	require.Equal([]interface{}{"--port", "${NOMAD_PORT_db}", "dc1"}, task.Config["args"])
+
+	// Runtime interpolations are kept
+	require.Equal("prod-${NOMAD_ALLOC_INDEX}", task.Env["ENV"])
+	require.Equal("${attr.unique.network.ip-address}", task.Env["HOST"])
+	require.Equal("${NOMAD_TASK_DIR}", task.Env["LIT"])
+}
+
+func TestParse_Variables_Precedence(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	job, err := parseFixture(t, "variables.hcl", &ParseConfig{
+		ArgVars: []string{
+			"image=redis:4.0",
+			`datacenters=["dc1", "dc2"]`,
+		},
+		VarFiles: []string{"./test-fixtures/variables.vars.hcl"},
+		Envs: []string{
+			"NOMAD_VAR_env=dev",
+			"NOMAD_VAR_image=redis:3.0",
+			"NOMAD_VAR_unknown=ignored",
+		},
+	})
+	require.NoError(err)
+
+	// The variable file overrides the environment, and the arguments
+	// override both
+	require.Equal("STAGING", job.Meta["env"])
+	require.Equal(3, *job.TaskGroups[0].Count)
+	require.Equal("redis:4.0", job.TaskGroups[0].Tasks[0].Config["image"])
+	require.Equal([]string{"dc1", "dc2"}, job.Datacenters)
+}
+
+func TestParseVariables(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	args := &ParseConfig{
+		ArgVars: []string{
+			"image=redis:${4.0}",
+			`datacenters=["dc1", "dc\"2"]`,
+		},
+		VarFiles: []string{"./test-fixtures/variables.vars.hcl"},
+		Envs:     []string{"NOMAD_VAR_env=dev"},
+	}
+	expected, err := parseFixture(t, "variables.hcl", args)
+	require.NoError(err)
+
+	path := filepath.Join("./test-fixtures", "variables.hcl")
+	src, err := ioutil.ReadFile(path)
+	require.NoError(err)
+	args.Path = path
+	args.Body = src
+	content, err := ParseVariables(args)
+	require.NoError(err)
+	require.Equal(`count = 3
+datacenters = ["dc1", "dc\"2"]
+env = "staging"
+image = "redis:$${4.0}"
+`, content)
+
+	// The variables reproduce the job without the other inputs
+	job, err := parseFixture(t, "variables.hcl", &ParseConfig{
+		VarContent: []byte(content),
+	})
+	require.NoError(err)
+	require.Equal(expected, job)
+}
+
+func TestParse_Variables_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		Name    string
+		ArgVars []string
+		Err     string
+	}{
+		{
+			Name: "unset",
+			Err:  `variable "env" has no default`,
+		},
+		{
+			Name:    "undeclared",
+			ArgVars: []string{"env=prod", "region=global"},
+			Err:     `variable "region" which is not declared`,
+		},
+		{
+			Name:    "malformed",
+			ArgVars: []string{"env"},
+			Err:     "not of the form name=value",
+		},
+		{
+			Name:    "wrong type",
+			ArgVars: []string{"env=prod", "count=many"},
+			Err:     `value of the variable "count" is not valid`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			_, err := parseFixture(t, "variables.hcl", &ParseConfig{
+				ArgVars: c.ArgVars,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), c.Err)
+		})
+	}
+}
+
+func TestParse_Dynamic(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	job, err := parseFixture(t, "dynamic.hcl", nil)
+	require.NoError(err)
+
+	task := job.TaskGroups[0].Tasks[0]
+	require.Len(task.Constraints, 2)
+	require.Equal("${attr.kernel.name}", task.Constraints[0].LTarget)
+	require.Equal("!=", task.Constraints[0].Operand)
+	require.Equal("linux", task.Constraints[0].RTarget)
+	require.Equal("darwin", task.Constraints[1].RTarget)
+
+	ports := task.Resources.Networks[0].ReservedPorts
+	require.Len(ports, 2)
+	require.Equal("admin", ports[0].Label)
+	require.Equal(8081, ports[0].Value)
+	require.Equal("http", ports[1].Label)
+	require.Equal(8080, ports[1].Value)
+}
@@ -0,0 +1,456 @@
+package jobspec2
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const (
+	// dynamicBlock is the block type generating a block per element of a
+	// collection
+	dynamicBlock = "dynamic"
+)
+
+// dynamicSchema is the schema of a dynamic block
+var dynamicSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "for_each", Required: true},
+		{Name: "iterator"},
+		{Name: "labels"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "content"},
+	},
+}
+
+// renderer evaluates an HCL2 body and writes the result in the HCL1 syntax
+// understood by the jobspec package.
+type renderer struct {
+	// src is the source of the job file
+	src []byte
+
+	buf    *bytes.Buffer
+	indent int
+}
+
+// renderBody writes the attributes and blocks of the body, skipping the
+// blocks of type skipBlock.
+func (r *renderer) renderBody(body *hclsyntax.Body, ctx *hcl.EvalContext, skipBlock string) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	// Keep the attributes in the order of the source
+	attrs := make([]*hclsyntax.Attribute, 0, len(body.Attributes))
+	for _, attr := range body.Attributes {
+		attrs = append(attrs, attr)
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+	})
+
+	for _, attr := range attrs {
+		text, ok, attrDiags := r.exprText(attr.Expr, ctx)
+		diags = append(diags, attrDiags...)
+		if !ok {
+			// Null values leave the attribute unset
+			continue
+		}
+		r.writeLine(fmt.Sprintf("%s = %s", attr.Name, text))
+	}
+
+	for _, block := range body.Blocks {
+		switch block.Type {
+		case skipBlock:
+			continue
+		case dynamicBlock:
+			diags = append(diags, r.renderDynamic(block, ctx)...)
+		default:
+			diags = append(diags, r.renderBlock(block.Type, block.Labels, block.Body, ctx)...)
+		}
+	}
+
+	return diags
+}
+
+// renderBlock writes a block with the given type and labels
+func (r *renderer) renderBlock(blockType string, labels []string, body *hclsyntax.Body, ctx *hcl.EvalContext) hcl.Diagnostics {
+	header := []string{blockType}
+	for _, label := range labels {
+		header = append(header, quote(label))
+	}
+	r.writeLine(strings.Join(header, " ") + " {")
+
+	r.indent++
+	diags := r.renderBody(body, ctx, "")
+	r.indent--
+
+	r.writeLine("}")
+	return diags
+}
+
+// renderDynamic writes a block for each element of the for_each collection
+// of the dynamic block. The content of the blocks can reference the element
+// as iterator.key and iterator.value, where the iterator defaults to the type
+// of the generated blocks.
+func (r *renderer) renderDynamic(block *hclsyntax.Block, ctx *hcl.EvalContext) hcl.Diagnostics {
+	if len(block.Labels) != 1 {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid dynamic block",
+			Detail:   "A dynamic block must have exactly one label, the type of the blocks to generate.",
+			Subject:  block.TypeRange.Ptr(),
+		}}
+	}
+	blockType := block.Labels[0]
+
+	content, diags := block.Body.Content(dynamicSchema)
+	if diags.HasErrors() {
+		return diags
+	}
+	if len(content.Blocks) != 1 {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid dynamic block",
+			Detail:   "A dynamic block must have exactly one content block.",
+			Subject:  block.TypeRange.Ptr(),
+		})
+	}
+	contentBody := content.Blocks[0].Body.(*hclsyntax.Body)
+
+	iterator := blockType
+	if attr, ok := content.Attributes["iterator"]; ok {
+		iterator = hcl.ExprAsKeyword(attr.Expr)
+		if iterator == "" {
+			return append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid dynamic iterator",
+				Detail:   "The iterator must be a single identifier.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	forEachAttr := content.Attributes["for_each"]
+	forEach, valDiags := forEachAttr.Expr.Value(ctx)
+	diags = append(diags, valDiags...)
+	if valDiags.HasErrors() {
+		return diags
+	}
+	if forEach.IsNull() || !forEach.IsKnown() || !forEach.CanIterateElements() {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid dynamic for_each",
+			Detail:   "The for_each value must be a list, set or map.",
+			Subject:  forEachAttr.Expr.Range().Ptr(),
+		})
+	}
+
+	for it := forEach.ElementIterator(); it.Next(); {
+		key, value := it.Element()
+		child := ctx.NewChild()
+		child.Variables = map[string]cty.Value{
+			iterator: cty.ObjectVal(map[string]cty.Value{
+				"key":   key,
+				"value": value,
+			}),
+		}
+
+		var labels []string
+		if attr, ok := content.Attributes["labels"]; ok {
+			val, valDiags := attr.Expr.Value(child)
+			diags = append(diags, valDiags...)
+			if valDiags.HasErrors() {
+				return diags
+			}
+			val, err := convert.Convert(val, cty.List(cty.String))
+			if err != nil || val.IsNull() || !val.IsWhollyKnown() {
+				return append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid dynamic labels",
+					Detail:   "The labels must be a list of strings.",
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+			for _, label := range val.AsValueSlice() {
+				labels = append(labels, label.AsString())
+			}
+		}
+
+		diags = append(diags, r.renderBlock(blockType, labels, contentBody, child)...)
+	}
+
+	return diags
+}
+
+// exprText returns the HCL1 text of the expression. It returns false if the
+// expression evaluated to null.
+func (r *renderer) exprText(expr hclsyntax.Expression, ctx *hcl.EvalContext) (string, bool, hcl.Diagnostics) {
+	switch e := expr.(type) {
+	case *hclsyntax.TemplateWrapExpr:
+		if r.isRuntime(e.Wrapped, ctx) {
+			return quote("${" + r.source(e.Wrapped) + "}"), true, nil
+		}
+	case *hclsyntax.TemplateExpr:
+		if r.hasRuntime(e.Parts, ctx) {
+			return r.runtimeTemplate(e, ctx)
+		}
+	case *hclsyntax.TupleConsExpr:
+		var diags hcl.Diagnostics
+		elems := make([]string, 0, len(e.Exprs))
+		for _, elem := range e.Exprs {
+			text, ok, elemDiags := r.exprText(elem, ctx)
+			diags = append(diags, elemDiags...)
+			if !ok && !elemDiags.HasErrors() {
+				diags = append(diags, nullElement(elem.Range()))
+			}
+			elems = append(elems, text)
+		}
+		return "[" + strings.Join(elems, ", ") + "]", true, diags
+	case *hclsyntax.ObjectConsExpr:
+		var diags hcl.Diagnostics
+		items := make([]string, 0, len(e.Items))
+		for _, item := range e.Items {
+			key, keyDiags := item.KeyExpr.Value(ctx)
+			diags = append(diags, keyDiags...)
+			if keyDiags.HasErrors() {
+				continue
+			}
+			key, err := convert.Convert(key, cty.String)
+			if err != nil || key.IsNull() || !key.IsKnown() {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid object key",
+					Detail:   "The object keys must be strings.",
+					Subject:  item.KeyExpr.Range().Ptr(),
+				})
+				continue
+			}
+
+			text, ok, valDiags := r.exprText(item.ValueExpr, ctx)
+			diags = append(diags, valDiags...)
+			if !ok {
+				continue
+			}
+			items = append(items, fmt.Sprintf("%s = %s", quote(key.AsString()), text))
+		}
+		return "{" + strings.Join(items, ", ") + "}", true, diags
+	}
+
+	val, diags := expr.Value(ctx)
+	if diags.HasErrors() {
+		return "", false, diags
+	}
+	text, ok, err := valueText(val)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid value",
+			Detail:   err.Error(),
+			Subject:  expr.Range().Ptr(),
+		})
+	}
+	return text, ok, diags
+}
+
+// runtimeTemplate returns the HCL1 text of a template that interpolates
+// runtime values. The runtime interpolations are kept and the other parts
+// are evaluated.
+func (r *renderer) runtimeTemplate(e *hclsyntax.TemplateExpr, ctx *hcl.EvalContext) (string, bool, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	var buf strings.Builder
+	for _, part := range e.Parts {
+		if r.isRuntime(part, ctx) {
+			buf.WriteString("${" + r.source(part) + "}")
+			continue
+		}
+
+		val, partDiags := part.Value(ctx)
+		diags = append(diags, partDiags...)
+		if partDiags.HasErrors() {
+			continue
+		}
+		val, err := convert.Convert(val, cty.String)
+		if err != nil || val.IsNull() || !val.IsKnown() {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid template interpolation",
+				Detail:   "The interpolated value must be convertible to a string.",
+				Subject:  part.Range().Ptr(),
+			})
+			continue
+		}
+		buf.WriteString(val.AsString())
+	}
+	return quote(buf.String()), true, diags
+}
+
+// hasRuntime returns whether any of the template parts is a runtime
+// interpolation
+func (r *renderer) hasRuntime(parts []hclsyntax.Expression, ctx *hcl.EvalContext) bool {
+	for _, part := range parts {
+		if r.isRuntime(part, ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRuntime returns whether the expression references a value that isn't
+// known to the evaluation context, such as attr.kernel.name or
+// NOMAD_PORT_http, and so is interpolated by Nomad at runtime.
+func (r *renderer) isRuntime(expr hclsyntax.Expression, ctx *hcl.EvalContext) bool {
+	traversal, ok := expr.(*hclsyntax.ScopeTraversalExpr)
+	if !ok {
+		return false
+	}
+
+	root := traversal.Traversal.RootName()
+	for c := ctx; c != nil; c = c.Parent() {
+		if _, ok := c.Variables[root]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// source returns the source of the expression
+func (r *renderer) source(expr hclsyntax.Expression) string {
+	rng := expr.Range()
+	return string(r.src[rng.Start.Byte:rng.End.Byte])
+}
+
+func (r *renderer) writeLine(line string) {
+	r.buf.WriteString(strings.Repeat("  ", r.indent))
+	r.buf.WriteString(line)
+	r.buf.WriteString("\n")
+}
+
+// valueText returns the HCL1 text of a value. It returns false if the value
+// is null.
+func valueText(val cty.Value) (string, bool, error) {
+	if val.IsNull() {
+		return "", false, nil
+	}
+	if !val.IsKnown() {
+		return "", false, fmt.Errorf("The value is unknown.")
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return quote(val.AsString()), true, nil
+	case ty == cty.Number:
+		bf := val.AsBigFloat()
+		if bf.IsInt() {
+			return bf.Text('f', 0), true, nil
+		}
+		f, _ := bf.Float64()
+		return strconv.FormatFloat(f, 'f', -1, 64), true, nil
+	case ty == cty.Bool:
+		return strconv.FormatBool(val.True()), true, nil
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		var elems []string
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			text, ok, err := valueText(elem)
+			if err != nil {
+				return "", false, err
+			}
+			if !ok {
+				return "", false, fmt.Errorf("The collection contains a null element.")
+			}
+			elems = append(elems, text)
+		}
+		return "[" + strings.Join(elems, ", ") + "]", true, nil
+	case ty.IsMapType() || ty.IsObjectType():
+		var items []string
+		for it := val.ElementIterator(); it.Next(); {
+			key, elem := it.Element()
+			text, ok, err := valueText(elem)
+			if err != nil {
+				return "", false, err
+			}
+			if !ok {
+				continue
+			}
+			items = append(items, fmt.Sprintf("%s = %s", quote(key.AsString()), text))
+		}
+		return "{" + strings.Join(items, ", ") + "}", true, nil
+	default:
+		return "", false, fmt.Errorf("The value of type %s is not supported.", ty.FriendlyName())
+	}
+}
+
+// quote returns the string as an HCL1 quoted string. The content of "${...}"
+// sequences is copied as is, as HCL1 doesn't unescape it.
+func quote(s string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '$' && i+1 < len(s) && s[i+1] == '{' {
+			if end := interpolationEnd(s, i+2); end > 0 {
+				buf.WriteString(s[i:end])
+				i = end - 1
+				continue
+			}
+		}
+
+		switch c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if c < 0x20 {
+				fmt.Fprintf(&buf, `\u%04x`, c)
+			} else {
+				buf.WriteByte(c)
+			}
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// interpolationEnd returns the index following the brace closing the
+// interpolation whose content starts at start, or -1 if it isn't closed.
+func interpolationEnd(s string, start int) int {
+	braces := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			braces++
+		case '}':
+			braces--
+			if braces == 0 {
+				return i + 1
+			}
+		case '\n':
+			return -1
+		}
+	}
+	return -1
+}
+
+func nullElement(rng hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid value",
+		Detail:   "The collection contains a null element.",
+		Subject:  &rng,
+	}
+}
@@ -0,0 +1,44 @@
+variable "ports" {
+  type = map(number)
+
+  default = {
+    http  = 8080
+    admin = 8081
+  }
+}
+
+variable "kernels" {
+  default = ["linux", "darwin"]
+}
+
+job "example" {
+  group "web" {
+    task "server" {
+      driver = "docker"
+
+      dynamic "constraint" {
+        for_each = var.kernels
+        iterator = kernel
+
+        content {
+          attribute = "${attr.kernel.name}"
+          operator  = "!="
+          value     = kernel.value
+        }
+      }
+
+      resources {
+        network {
+          dynamic "port" {
+            for_each = var.ports
+            labels   = [port.key]
+
+            content {
+              static = port.value
+            }
+          }
+        }
+      }
+    }
+  }
+}
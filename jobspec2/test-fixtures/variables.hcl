@@ -0,0 +1,45 @@
+variable "datacenters" {
+  type    = list(string)
+  default = ["dc1"]
+}
+
+variable "env" {
+  type        = string
+  description = "The environment the job runs in"
+}
+
+variable "count" {
+  type    = number
+  default = 1
+}
+
+variable "image" {
+  default = "redis:3.2"
+}
+
+job "example" {
+  datacenters = var.datacenters
+
+  meta {
+    env = upper(var.env)
+  }
+
+  group "cache" {
+    count = var.count
+
+    task "redis" {
+      driver = "docker"
+
+      config {
+        image = var.image
+        args  = ["--port", "${NOMAD_PORT_db}", join(",", var.datacenters)]
+      }
+
+      env {
+        ENV  = "${var.env}-${NOMAD_ALLOC_INDEX}"
+        HOST = "${attr.unique.network.ip-address}"
+        LIT  = "$${NOMAD_TASK_DIR}"
+      }
+    }
+  }
+}
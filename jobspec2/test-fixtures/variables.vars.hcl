@@ -0,0 +1,2 @@
+env   = "staging"
+count = 3
@@ -0,0 +1,444 @@
+package jobspec2
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+const (
+	// variableBlock is the block type declaring an input variable
+	variableBlock = "variable"
+
+	// variablesRoot is the name under which the variables are referenced,
+	// as in "var.name"
+	variablesRoot = "var"
+
+	// envVarPrefix is the prefix of the environment variables assigning
+	// input variables
+	envVarPrefix = "NOMAD_VAR_"
+
+	// varContentFilename is the name of the variable content in diagnostics
+	varContentFilename = "<variables>"
+)
+
+// variableSchema is the schema of a variable block
+var variableSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "type"},
+		{Name: "default"},
+		{Name: "description"},
+	},
+}
+
+// variable is an input variable declared by the job file
+type variable struct {
+	Name  string
+	Type  cty.Type
+	Value cty.Value
+	Range hcl.Range
+
+	// Set is whether a value was assigned
+	Set bool
+
+	// Input is whether the value was assigned by an input rather than by
+	// the default
+	Input bool
+}
+
+// decodeVariables decodes the variable blocks of the body and assigns them
+// their value. The returned map is the set of variables of the evaluation
+// context.
+func decodeVariables(body *hclsyntax.Body, args *ParseConfig) (map[string]cty.Value, hcl.Diagnostics) {
+	vars, diags := resolveVariables(body, args)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	values := make(map[string]cty.Value, len(vars))
+	for name, v := range vars {
+		values[name] = v.Value
+	}
+	return map[string]cty.Value{
+		variablesRoot: cty.ObjectVal(values),
+	}, nil
+}
+
+// resolveVariables decodes the variable blocks of the body and assigns them
+// their value. In order of increasing precedence the value is the default,
+// the environment, the variable files, the variable content and the command
+// line arguments.
+func resolveVariables(body *hclsyntax.Body, args *ParseConfig) (map[string]*variable, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+	vars := make(map[string]*variable)
+
+	for _, block := range body.Blocks {
+		if block.Type != variableBlock {
+			continue
+		}
+		v, vDiags := decodeVariableBlock(block)
+		diags = append(diags, vDiags...)
+		if v == nil {
+			continue
+		}
+		if _, ok := vars[v.Name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate variable",
+				Detail:   fmt.Sprintf("The variable %q is declared more than once.", v.Name),
+				Subject:  block.TypeRange.Ptr(),
+			})
+			continue
+		}
+		vars[v.Name] = v
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	// Environment variables that don't match a declared variable are ignored
+	for _, env := range args.Envs {
+		if !strings.HasPrefix(env, envVarPrefix) {
+			continue
+		}
+		name, raw, ok := splitAssignment(strings.TrimPrefix(env, envVarPrefix))
+		if !ok {
+			continue
+		}
+		v, ok := vars[name]
+		if !ok {
+			continue
+		}
+		diags = append(diags, v.setRaw(raw, hcl.Range{Filename: "<env>"})...)
+	}
+
+	for _, path := range args.VarFiles {
+		diags = append(diags, decodeVarFile(path, vars)...)
+	}
+
+	if len(args.VarContent) != 0 {
+		diags = append(diags, decodeVarContent(args.VarContent, varContentFilename, vars)...)
+	}
+
+	for _, arg := range args.ArgVars {
+		rng := hcl.Range{Filename: "<arg>"}
+		name, raw, ok := splitAssignment(arg)
+		if !ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid variable argument",
+				Detail:   fmt.Sprintf("The argument %q is not of the form name=value.", arg),
+				Subject:  &rng,
+			})
+			continue
+		}
+		v, ok := vars[name]
+		if !ok {
+			diags = append(diags, undeclaredVariable(name, rng))
+			continue
+		}
+		diags = append(diags, v.setRaw(raw, rng)...)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v := vars[name]
+		if !v.Set {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Unset variable",
+				Detail:   fmt.Sprintf("The variable %q has no default and no value was given.", name),
+				Subject:  v.Range.Ptr(),
+			})
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	return vars, nil
+}
+
+// decodeVariableBlock decodes the declaration of a variable
+func decodeVariableBlock(block *hclsyntax.Block) (*variable, hcl.Diagnostics) {
+	if len(block.Labels) != 1 {
+		return nil, hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid variable block",
+			Detail:   "A variable block must have exactly one label, the name of the variable.",
+			Subject:  block.TypeRange.Ptr(),
+		}}
+	}
+
+	content, diags := block.Body.Content(variableSchema)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	v := &variable{
+		Name:  block.Labels[0],
+		Type:  cty.DynamicPseudoType,
+		Range: block.TypeRange,
+	}
+
+	if attr, ok := content.Attributes["type"]; ok {
+		ty, tyDiags := typeFromExpr(attr.Expr)
+		diags = append(diags, tyDiags...)
+		if tyDiags.HasErrors() {
+			return nil, diags
+		}
+		v.Type = ty
+	}
+
+	if attr, ok := content.Attributes["default"]; ok {
+		val, valDiags := attr.Expr.Value(&hcl.EvalContext{Functions: functions()})
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			return nil, diags
+		}
+		diags = append(diags, v.set(val, attr.Expr.Range())...)
+	}
+
+	return v, diags
+}
+
+// decodeVarFile assigns the variables from the attributes of an HCL file
+func decodeVarFile(path string, vars map[string]*variable) hcl.Diagnostics {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Failed to read variable file",
+			Detail:   fmt.Sprintf("The file %q could not be read: %v", path, err),
+		}}
+	}
+	return decodeVarContent(src, path, vars)
+}
+
+// decodeVarContent assigns the variables from the attributes of the content
+// of an HCL variable file
+func decodeVarContent(src []byte, filename string, vars map[string]*variable) hcl.Diagnostics {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return diags
+	}
+	attrs, diags := file.Body.JustAttributes()
+	if diags.HasErrors() {
+		return diags
+	}
+
+	ctx := &hcl.EvalContext{Functions: functions()}
+	for name, attr := range attrs {
+		v, ok := vars[name]
+		if !ok {
+			diags = append(diags, undeclaredVariable(name, attr.NameRange))
+			continue
+		}
+		val, valDiags := attr.Expr.Value(ctx)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			continue
+		}
+		diags = append(diags, v.set(val, attr.Expr.Range())...)
+		v.Input = true
+	}
+	return diags
+}
+
+// set assigns the value to the variable, converting it to its type
+func (v *variable) set(val cty.Value, rng hcl.Range) hcl.Diagnostics {
+	converted, err := convert.Convert(val, v.Type)
+	if err != nil {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid variable value",
+			Detail:   fmt.Sprintf("The value of the variable %q is not valid: %v.", v.Name, err),
+			Subject:  &rng,
+		}}
+	}
+	v.Value = converted
+	v.Set = true
+	return nil
+}
+
+// setRaw assigns the value given as a string by an input. The string is used
+// as is for primitive types, and parsed as an HCL expression otherwise.
+func (v *variable) setRaw(raw string, rng hcl.Range) hcl.Diagnostics {
+	v.Input = true
+	if v.Type.IsPrimitiveType() || v.Type == cty.DynamicPseudoType {
+		return v.set(cty.StringVal(raw), rng)
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(raw), rng.Filename, hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return diags
+	}
+	val, diags := expr.Value(&hcl.EvalContext{Functions: functions()})
+	if diags.HasErrors() {
+		return diags
+	}
+	return v.set(val, rng)
+}
+
+// formatVariables returns the values of the variables assigned by an input as
+// the content of an HCL variable file
+func formatVariables(vars map[string]*variable) string {
+	names := make([]string, 0, len(vars))
+	for name, v := range vars {
+		if v.Input {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s = %s\n", name, formatValue(vars[name].Value))
+	}
+	return buf.String()
+}
+
+// formatValue returns the HCL expression of a variable value
+func formatValue(val cty.Value) string {
+	if val.IsNull() {
+		return "null"
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		return quoteString(val.AsString())
+	case ty == cty.Number:
+		return val.AsBigFloat().Text('f', -1)
+	case ty == cty.Bool:
+		if val.True() {
+			return "true"
+		}
+		return "false"
+	case ty.IsListType() || ty.IsSetType() || ty.IsTupleType():
+		var elems []string
+		for it := val.ElementIterator(); it.Next(); {
+			_, ev := it.Element()
+			elems = append(elems, formatValue(ev))
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	case ty.IsMapType() || ty.IsObjectType():
+		var elems []string
+		for it := val.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			elems = append(elems, quoteString(k.AsString())+" = "+formatValue(ev))
+		}
+		return "{" + strings.Join(elems, ", ") + "}"
+	default:
+		return "null"
+	}
+}
+
+// quoteString returns the HCL string literal of s, escaping the template
+// sequences so that s isn't interpolated
+func quoteString(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for i, r := range s {
+		switch r {
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '$', '%':
+			buf.WriteRune(r)
+			if i+1 < len(s) && s[i+1] == '{' {
+				buf.WriteRune(r)
+			}
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// typeFromExpr returns the type described by a type expression such as
+// string or list(number)
+func typeFromExpr(expr hcl.Expression) (cty.Type, hcl.Diagnostics) {
+	switch hcl.ExprAsKeyword(expr) {
+	case "string":
+		return cty.String, nil
+	case "number":
+		return cty.Number, nil
+	case "bool":
+		return cty.Bool, nil
+	case "any":
+		return cty.DynamicPseudoType, nil
+	}
+
+	invalid := hcl.Diagnostics{{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid type",
+		Detail:   "The type must be one of string, number, bool, any, list(type), set(type) or map(type).",
+		Subject:  expr.Range().Ptr(),
+	}}
+
+	call, diags := hcl.ExprCall(expr)
+	if diags.HasErrors() || len(call.Arguments) != 1 {
+		return cty.DynamicPseudoType, invalid
+	}
+	elem, diags := typeFromExpr(call.Arguments[0])
+	if diags.HasErrors() {
+		return cty.DynamicPseudoType, diags
+	}
+
+	switch call.Name {
+	case "list":
+		return cty.List(elem), nil
+	case "set":
+		return cty.Set(elem), nil
+	case "map":
+		return cty.Map(elem), nil
+	default:
+		return cty.DynamicPseudoType, invalid
+	}
+}
+
+// splitAssignment splits a "name=value" string
+func splitAssignment(s string) (string, string, bool) {
+	idx := strings.Index(s, "=")
+	if idx < 1 {
+		return "", "", false
+	}
+	return s[:idx], s[idx+1:], true
+}
+
+func undeclaredVariable(name string, rng hcl.Range) *hcl.Diagnostic {
+	return &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Undeclared variable",
+		Detail:   fmt.Sprintf("A value was given for the variable %q which is not declared in the job file.", name),
+		Subject:  &rng,
+	}
+}
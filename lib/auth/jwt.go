@@ -0,0 +1,296 @@
+// Package auth implements the pieces needed to log in to Nomad with an
+// external identity: verifying JSON Web Tokens, discovering OpenID Connect
+// providers and evaluating the selectors of ACL binding rules against the
+// claims of a verified token.
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	// Register the hash functions used by the supported algorithms
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+var (
+	// ErrMalformedToken is returned when a token isn't a compact JWS
+	ErrMalformedToken = errors.New("malformed JWT")
+
+	// ErrInvalidSignature is returned when no key verifies the signature of
+	// a token
+	ErrInvalidSignature = errors.New("failed to verify JWT signature")
+)
+
+// Claims are the decoded claims of a token.
+type Claims map[string]interface{}
+
+// Expected are the values the registered claims of a token are checked
+// against.
+type Expected struct {
+	// Issuer is the required "iss" claim, if set
+	Issuer string
+
+	// Audiences are the accepted "aud" claims. The token must have at least
+	// one of them, if set.
+	Audiences []string
+
+	// Nonce is the required "nonce" claim, if set
+	Nonce string
+
+	// Leeway is the clock skew allowed when checking the "exp", "nbf" and
+	// "iat" claims
+	Leeway time.Duration
+
+	// Now is the time to check the token at. It defaults to the current
+	// time.
+	Now time.Time
+
+	// ExpirationOptional accepts tokens without an "exp" claim, such as
+	// workload identities
+	ExpirationOptional bool
+}
+
+// jwtHeader is the JOSE header of a token.
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// parsedToken is a token split into its parts.
+type parsedToken struct {
+	header    jwtHeader
+	claims    Claims
+	signed    []byte
+	signature []byte
+}
+
+// parseToken splits and decodes a compact JWS without verifying it.
+func parseToken(token string) (*parsedToken, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	headerRaw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%v: failed to decode header: %v", ErrMalformedToken, err)
+	}
+	claimsRaw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%v: failed to decode claims: %v", ErrMalformedToken, err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%v: failed to decode signature: %v", ErrMalformedToken, err)
+	}
+
+	p := &parsedToken{
+		signed:    []byte(parts[0] + "." + parts[1]),
+		signature: signature,
+	}
+	if err := json.Unmarshal(headerRaw, &p.header); err != nil {
+		return nil, fmt.Errorf("%v: failed to decode header: %v", ErrMalformedToken, err)
+	}
+
+	// Decode numbers as json.Number so large integer claims keep their
+	// precision
+	dec := json.NewDecoder(strings.NewReader(string(claimsRaw)))
+	dec.UseNumber()
+	if err := dec.Decode(&p.claims); err != nil {
+		return nil, fmt.Errorf("%v: failed to decode claims: %v", ErrMalformedToken, err)
+	}
+	return p, nil
+}
+
+// Verify checks the signature of the token with the key set and validates
+// its registered claims. It returns the claims of a valid token.
+func Verify(token string, keys KeySet, expected *Expected) (Claims, error) {
+	p, err := parseToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := keys.Keys(p.header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	verified := false
+	for _, key := range candidates {
+		if err := verifySignature(p.header.Algorithm, key, p.signed, p.signature); err == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrInvalidSignature
+	}
+
+	if err := validateClaims(p.claims, expected); err != nil {
+		return nil, err
+	}
+	return p.claims, nil
+}
+
+// verifySignature checks the signature of the signed content with the key
+// for the algorithm.
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "PS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "PS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[0] {
+	case 'R':
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %q requires an RSA key", alg)
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, digest, signature)
+	case 'P':
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %q requires an RSA key", alg)
+		}
+		return rsa.VerifyPSS(pub, hash, digest, signature, nil)
+	default:
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %q requires an ECDSA key", alg)
+		}
+
+		// The signature is the concatenation of R and S, each the size of
+		// the curve
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+}
+
+// validateClaims checks the registered claims of a token.
+func validateClaims(claims Claims, expected *Expected) error {
+	if expected == nil {
+		expected = &Expected{}
+	}
+	now := expected.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	exp, ok, err := claims.numericDate("exp")
+	if err != nil {
+		return err
+	}
+	if !ok && !expected.ExpirationOptional {
+		return errors.New("JWT is missing the exp claim")
+	}
+	if ok && now.After(exp.Add(expected.Leeway)) {
+		return errors.New("JWT is expired")
+	}
+
+	if nbf, ok, err := claims.numericDate("nbf"); err != nil {
+		return err
+	} else if ok && now.Add(expected.Leeway).Before(nbf) {
+		return errors.New("JWT is not valid yet")
+	}
+
+	if iat, ok, err := claims.numericDate("iat"); err != nil {
+		return err
+	} else if ok && now.Add(expected.Leeway).Before(iat) {
+		return errors.New("JWT is issued in the future")
+	}
+
+	if expected.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != expected.Issuer {
+			return fmt.Errorf("JWT issuer %q is not %q", iss, expected.Issuer)
+		}
+	}
+
+	if len(expected.Audiences) != 0 {
+		if !audienceMatches(claims["aud"], expected.Audiences) {
+			return errors.New("JWT audience doesn't match any of the bound audiences")
+		}
+	}
+
+	if expected.Nonce != "" {
+		if nonce, _ := claims["nonce"].(string); nonce != expected.Nonce {
+			return errors.New("JWT nonce doesn't match")
+		}
+	}
+	return nil
+}
+
+// numericDate returns the time of a NumericDate claim and whether it is set.
+func (c Claims) numericDate(name string) (time.Time, bool, error) {
+	raw, ok := c[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	var secs float64
+	switch v := raw.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s claim: %v", name, err)
+		}
+		secs = f
+	case float64:
+		secs = v
+	default:
+		return time.Time{}, false, fmt.Errorf("invalid %s claim", name)
+	}
+	return time.Unix(0, int64(secs*float64(time.Second))), true, nil
+}
+
+// audienceMatches returns whether the "aud" claim, a string or a list of
+// strings, contains one of the audiences.
+func audienceMatches(aud interface{}, audiences []string) bool {
+	var values []string
+	switch v := aud.(type) {
+	case string:
+		values = []string{v}
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	for _, v := range values {
+		for _, a := range audiences {
+			if v == a {
+				return true
+			}
+		}
+	}
+	return false
+}
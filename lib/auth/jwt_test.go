@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify_StaticKeys(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	p := NewTestProvider(t)
+	defer p.Stop()
+
+	keys, err := NewStaticKeySet([]string{p.PublicKeyPEM()})
+	require.NoError(err)
+
+	token := p.SignToken(Claims{"sub": "alice", "aud": []string{"a", "b"}})
+	claims, err := Verify(token, keys, &Expected{
+		Issuer:    p.Issuer(),
+		Audiences: []string{"b"},
+	})
+	require.NoError(err)
+	require.Equal("alice", claims["sub"])
+
+	// Wrong issuer
+	_, err = Verify(token, keys, &Expected{Issuer: "other"})
+	require.Error(err)
+
+	// Wrong audience
+	_, err = Verify(token, keys, &Expected{Audiences: []string{"c"}})
+	require.Error(err)
+
+	// Tampered claims
+	tampered := p.SignToken(Claims{"sub": "bob"})
+	parts := []byte(token)
+	copy(parts, tampered[:len(tampered)/2])
+	_, err = Verify(string(parts), keys, nil)
+	require.Error(err)
+
+	// Signed by another key
+	other := NewTestProvider(t)
+	defer other.Stop()
+	_, err = Verify(other.SignToken(nil), keys, nil)
+	require.Equal(ErrInvalidSignature, err)
+}
+
+func TestVerify_TimeClaims(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	p := NewTestProvider(t)
+	defer p.Stop()
+	keys, err := NewStaticKeySet([]string{p.PublicKeyPEM()})
+	require.NoError(err)
+
+	now := time.Now()
+
+	// Expired, but within the leeway
+	token := p.SignToken(Claims{"exp": now.Add(-time.Minute).Unix()})
+	_, err = Verify(token, keys, nil)
+	require.Error(err)
+	_, err = Verify(token, keys, &Expected{Leeway: 2 * time.Minute})
+	require.NoError(err)
+
+	// Not valid yet
+	token = p.SignToken(Claims{"nbf": now.Add(time.Hour).Unix()})
+	_, err = Verify(token, keys, nil)
+	require.Error(err)
+	_, err = Verify(token, keys, &Expected{Now: now.Add(2 * time.Hour), Leeway: 2 * time.Hour})
+	require.NoError(err)
+
+	// Missing expiration
+	token = p.SignToken(Claims{"exp": nil})
+	_, err = Verify(token, keys, nil)
+	require.Error(err)
+}
+
+func TestVerify_ECDSA(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(err)
+	keys, err := NewStaticKeySet([]string{string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))})
+	require.NoError(err)
+
+	header, _ := json.Marshal(jwtHeader{Algorithm: "ES256"})
+	body, _ := json.Marshal(Claims{"exp": time.Now().Add(time.Hour).Unix()})
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(err)
+	sig := append(padBytes(r.Bytes(), 32), padBytes(s.Bytes(), 32)...)
+
+	_, err = Verify(signed+"."+base64.RawURLEncoding.EncodeToString(sig), keys, nil)
+	require.NoError(err)
+
+	// The algorithm must match the key type
+	require.Error(verifySignature("RS256", &key.PublicKey, []byte(signed), sig))
+	require.Error(verifySignature("none", &key.PublicKey, []byte(signed), nil))
+	require.Error(verifySignature("HS256", crypto.PublicKey(nil), []byte(signed), sig))
+}
+
+func TestVerify_Malformed(t *testing.T) {
+	t.Parallel()
+	keys := &staticKeySet{}
+	for _, token := range []string{"", "a.b", "a.b.c", "!!.e30.sig"} {
+		if _, err := Verify(token, keys, nil); err == nil {
+			t.Fatalf("expected error for %q", token)
+		}
+	}
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	p := NewTestProvider(t)
+	defer p.Stop()
+
+	keys := NewRemoteKeySet(p.Issuer()+"/keys", http.DefaultClient)
+	claims, err := Verify(p.SignToken(Claims{"sub": "alice"}), keys, nil)
+	require.NoError(err)
+	require.Equal("alice", claims["sub"])
+
+	_, err = keys.Keys("unknown")
+	require.Error(err)
+}
+
+func TestPadBytes(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	require.Equal([]byte{0, 0, 1, 2}, padBytes([]byte{1, 2}, 4))
+	require.Equal([]byte{1, 2, 3, 4}, padBytes([]byte{1, 2, 3, 4}, 4))
+	require.Equal([]byte{0, 0}, padBytes(nil, 2))
+}
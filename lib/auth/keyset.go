@@ -0,0 +1,290 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+)
+
+// maxResponseSize is the largest discovery document or key set that is read
+const maxResponseSize = 1024 * 1024
+
+// KeySet provides the keys a token may be signed with.
+type KeySet interface {
+	// Keys returns the candidate keys for the key ID of a token, which may
+	// be empty.
+	Keys(kid string) ([]crypto.PublicKey, error)
+}
+
+// staticKeySet is a fixed set of keys.
+type staticKeySet struct {
+	keys []crypto.PublicKey
+}
+
+// NewStaticKeySet returns a key set of PEM encoded public keys or
+// certificates.
+func NewStaticKeySet(pems []string) (KeySet, error) {
+	if len(pems) == 0 {
+		return nil, errors.New("no public keys given")
+	}
+
+	ks := &staticKeySet{}
+	for i, p := range pems {
+		key, err := ParsePublicKeyPEM(p)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %v", i, err)
+		}
+		ks.keys = append(ks.keys, key)
+	}
+	return ks, nil
+}
+
+func (s *staticKeySet) Keys(string) ([]crypto.PublicKey, error) {
+	return s.keys, nil
+}
+
+// ParsePublicKeyPEM parses a PEM encoded RSA or ECDSA public key or
+// certificate.
+func ParsePublicKeyPEM(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var key crypto.PublicKey
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = cert.PublicKey
+	case "RSA PUBLIC KEY":
+		k, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = k
+	default:
+		k, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = k
+	}
+
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+}
+
+// remoteKeySet is a JSON Web Key Set fetched from a URL. The keys are
+// fetched on first use and fetched again when a token uses an unknown key
+// ID, to pick up rotated keys.
+type remoteKeySet struct {
+	url    string
+	client *http.Client
+
+	l    sync.Mutex
+	keys map[string]crypto.PublicKey
+}
+
+// NewRemoteKeySet returns a key set fetched from the JWKS URL with the
+// client.
+func NewRemoteKeySet(url string, client *http.Client) KeySet {
+	return &remoteKeySet{
+		url:    url,
+		client: client,
+	}
+}
+
+func (r *remoteKeySet) Keys(kid string) ([]crypto.PublicKey, error) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	if r.keys == nil || (kid != "" && r.keys[kid] == nil) {
+		keys, err := r.fetch()
+		if err != nil {
+			return nil, err
+		}
+		r.keys = keys
+	}
+
+	if kid != "" {
+		key, ok := r.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key ID %q", kid)
+		}
+		return []crypto.PublicKey{key}, nil
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// JSONWebKey is a single key of a JWKS document.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+
+	// RSA parameters
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC parameters
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// NewJSONWebKey returns the JWK of an RSA or ECDSA public key used to sign
+// tokens with the algorithm.
+func NewJSONWebKey(kid, alg string, key crypto.PublicKey) (*JSONWebKey, error) {
+	jwk := &JSONWebKey{
+		KeyID:     kid,
+		Use:       "sig",
+		Algorithm: alg,
+	}
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = k.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(padBytes(k.X.Bytes(), size))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(padBytes(k.Y.Bytes(), size))
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+	return jwk, nil
+}
+
+// padBytes left-pads the big-endian bytes of an integer with zeros to size
+// bytes.
+func padBytes(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	return append(make([]byte, size-len(b)), b...)
+}
+
+// fetch downloads and decodes the key set. Keys of unsupported types or of
+// a use other than signing are skipped.
+func (r *remoteKeySet) fetch() (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []JSONWebKey `json:"keys"`
+	}
+	if err := getJSON(r.client, r.url, &doc); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %v", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for i, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		kid := jwk.KeyID
+		if kid == "" {
+			kid = fmt.Sprintf("#%d", i)
+		}
+		keys[kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS has no usable keys")
+	}
+	return keys, nil
+}
+
+// publicKey decodes the RSA or EC public key of the JWK.
+func (k *JSONWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+	}
+}
+
+// decodeBigInt decodes a base64url encoded big endian integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// getJSON fetches the URL and decodes its JSON body into out.
+func getJSON(client *http.Client, url string, out interface{}) error {
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
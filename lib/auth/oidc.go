@@ -0,0 +1,146 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// discoveryPath is appended to the issuer URL to find the OpenID Connect
+// discovery document
+const discoveryPath = "/.well-known/openid-configuration"
+
+// Provider is an OpenID Connect provider found through discovery.
+type Provider struct {
+	Issuer   string
+	AuthURL  string
+	TokenURL string
+	JWKSURL  string
+
+	client *http.Client
+	keys   KeySet
+}
+
+// Discover fetches the discovery document of the OpenID Connect provider at
+// the issuer URL.
+func Discover(issuer string, client *http.Client) (*Provider, error) {
+	issuer = strings.TrimSuffix(issuer, "/")
+
+	var doc struct {
+		Issuer   string `json:"issuer"`
+		AuthURL  string `json:"authorization_endpoint"`
+		TokenURL string `json:"token_endpoint"`
+		JWKSURL  string `json:"jwks_uri"`
+	}
+	if err := getJSON(client, issuer+discoveryPath, &doc); err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %v", err)
+	}
+
+	// The issuer must match the URL the document was fetched from so a
+	// provider can't impersonate another one
+	if strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("OIDC provider issuer %q doesn't match discovery URL %q", doc.Issuer, issuer)
+	}
+	if doc.JWKSURL == "" {
+		return nil, errors.New("OIDC provider has no jwks_uri")
+	}
+
+	return &Provider{
+		Issuer:   doc.Issuer,
+		AuthURL:  doc.AuthURL,
+		TokenURL: doc.TokenURL,
+		JWKSURL:  doc.JWKSURL,
+		client:   client,
+		keys:     NewRemoteKeySet(doc.JWKSURL, client),
+	}, nil
+}
+
+// KeySet returns the signing keys of the provider.
+func (p *Provider) KeySet() KeySet {
+	return p.keys
+}
+
+// AuthCodeURL returns the URL to send the user to in order to start the
+// authorization code flow. The "openid" scope is always requested.
+func (p *Provider) AuthCodeURL(clientID, redirectURI, state, nonce string, scopes []string) (string, error) {
+	if p.AuthURL == "" {
+		return "", errors.New("OIDC provider has no authorization_endpoint")
+	}
+
+	allScopes := []string{"openid"}
+	for _, s := range scopes {
+		if s != "openid" {
+			allScopes = append(allScopes, s)
+		}
+	}
+
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", clientID)
+	v.Set("redirect_uri", redirectURI)
+	v.Set("scope", strings.Join(allScopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+
+	sep := "?"
+	if strings.Contains(p.AuthURL, "?") {
+		sep = "&"
+	}
+	return p.AuthURL + sep + v.Encode(), nil
+}
+
+// Exchange redeems an authorization code at the token endpoint of the
+// provider and returns the ID token.
+func (p *Provider) Exchange(clientID, clientSecret, redirectURI, code string) (string, error) {
+	if p.TokenURL == "" {
+		return "", errors.New("OIDC provider has no token_endpoint")
+	}
+
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", redirectURI)
+
+	req, err := http.NewRequest("POST", p.TokenURL, strings.NewReader(v.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange authorization code: %v", err)
+	}
+
+	var out struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("failed to decode token response: %v", err)
+	}
+	if out.Error != "" {
+		return "", fmt.Errorf("failed to exchange authorization code: %s %s", out.Error, out.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to exchange authorization code: unexpected response code %d", resp.StatusCode)
+	}
+	if out.IDToken == "" {
+		return "", errors.New("token response has no id_token")
+	}
+	return out.IDToken, nil
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvider_AuthCodeFlow(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	p := NewTestProvider(t)
+	defer p.Stop()
+	p.SetClaims(Claims{"sub": "alice", "groups": []string{"dev"}})
+
+	provider, err := Discover(p.Issuer()+"/", http.DefaultClient)
+	require.NoError(err)
+	require.Equal(p.Issuer()+"/token", provider.TokenURL)
+
+	authURL, err := provider.AuthCodeURL(p.ClientID, "http://localhost:4649/oidc/callback", "state1", "nonce1", []string{"email"})
+	require.NoError(err)
+	u, err := url.Parse(authURL)
+	require.NoError(err)
+	require.Equal("openid email", u.Query().Get("scope"))
+	require.Equal("code", u.Query().Get("response_type"))
+
+	code, state, err := p.Authorize(authURL)
+	require.NoError(err)
+	require.Equal("state1", state)
+
+	// A bad secret is rejected
+	_, err = provider.Exchange(p.ClientID, "wrong", "http://localhost:4649/oidc/callback", code)
+	require.Error(err)
+
+	code, _, err = p.Authorize(authURL)
+	require.NoError(err)
+	idToken, err := provider.Exchange(p.ClientID, p.Secret, "http://localhost:4649/oidc/callback", code)
+	require.NoError(err)
+
+	claims, err := Verify(idToken, provider.KeySet(), &Expected{
+		Issuer:    provider.Issuer,
+		Audiences: []string{p.ClientID},
+		Nonce:     "nonce1",
+	})
+	require.NoError(err)
+	require.Equal("alice", claims["sub"])
+
+	// The nonce must match
+	_, err = Verify(idToken, provider.KeySet(), &Expected{Nonce: "other"})
+	require.Error(err)
+
+	// Codes can only be used once
+	_, err = provider.Exchange(p.ClientID, p.Secret, "http://localhost:4649/oidc/callback", code)
+	require.Error(err)
+}
+
+func TestDiscover_IssuerMismatch(t *testing.T) {
+	t.Parallel()
+
+	p := NewTestProvider(t)
+	defer p.Stop()
+
+	if _, err := Discover(p.Issuer()+"/other", http.DefaultClient); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,473 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ClaimValues are the claims of a token mapped to the variables that
+// binding rule selectors and bind names refer to. Values are referenced as
+// value.<name> and lists as list.<name>.
+type ClaimValues struct {
+	Value map[string]string
+	List  map[string][]string
+}
+
+// MapClaims maps the claims to variables. The keys of the mappings are claim
+// names, or JSON pointers such as "/groups/0" to reach nested claims, and the
+// values are variable names. Claims missing from the token are skipped.
+func MapClaims(claims Claims, valueMappings, listMappings map[string]string) (*ClaimValues, error) {
+	v := &ClaimValues{
+		Value: make(map[string]string, len(valueMappings)),
+		List:  make(map[string][]string, len(listMappings)),
+	}
+
+	for claim, name := range valueMappings {
+		raw, ok := lookupClaim(claims, claim)
+		if !ok {
+			continue
+		}
+		s, ok := scalarString(raw)
+		if !ok {
+			return nil, fmt.Errorf("claim %q is not a string, number or boolean", claim)
+		}
+		v.Value[name] = s
+	}
+
+	for claim, name := range listMappings {
+		raw, ok := lookupClaim(claims, claim)
+		if !ok {
+			continue
+		}
+
+		// A single value is treated as a list of one
+		items, ok := raw.([]interface{})
+		if !ok {
+			items = []interface{}{raw}
+		}
+
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			s, ok := scalarString(item)
+			if !ok {
+				return nil, fmt.Errorf("claim %q is not a list of strings, numbers or booleans", claim)
+			}
+			list = append(list, s)
+		}
+		v.List[name] = list
+	}
+	return v, nil
+}
+
+// lookupClaim returns the claim with the name or at the JSON pointer.
+func lookupClaim(claims Claims, name string) (interface{}, bool) {
+	if !strings.HasPrefix(name, "/") {
+		raw, ok := claims[name]
+		return raw, ok
+	}
+
+	var cur interface{} = map[string]interface{}(claims)
+	for _, part := range strings.Split(name[1:], "/") {
+		part = strings.Replace(strings.Replace(part, "~1", "/", -1), "~0", "~", -1)
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			next, ok := c[part]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			cur = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// scalarString returns the string form of a string, number or boolean.
+func scalarString(raw interface{}) (string, bool) {
+	switch v := raw.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
+
+// InterpolateBindName replaces the ${value.<name>} references in the bind
+// name of a binding rule. It returns false if a referenced variable isn't
+// set, in which case the rule doesn't apply.
+func InterpolateBindName(bindName string, v *ClaimValues) (string, bool) {
+	var out strings.Builder
+	rest := bindName
+	for {
+		start := strings.Index(rest, "${")
+		if start == -1 {
+			out.WriteString(rest)
+			return out.String(), true
+		}
+		end := strings.Index(rest[start:], "}")
+		if end == -1 {
+			return "", false
+		}
+		end += start
+
+		ref := strings.TrimSpace(rest[start+2 : end])
+		if !strings.HasPrefix(ref, "value.") {
+			return "", false
+		}
+		val, ok := v.Value[strings.TrimPrefix(ref, "value.")]
+		if !ok || val == "" {
+			return "", false
+		}
+
+		out.WriteString(rest[:start])
+		out.WriteString(val)
+		rest = rest[end+1:]
+	}
+}
+
+// Selector is a parsed binding rule selector. The grammar is:
+//
+//	expr       = and { "or" and }
+//	and        = term { "and" term }
+//	term       = [ "not" ] ( "(" expr ")" | comparison )
+//	comparison = value ( "==" | "!=" ) string
+//	           | string [ "not" ] "in" list
+//	           | ( value | list ) "is" [ "not" ] "empty"
+//
+// where value is value.<name>, list is list.<name> and string is a double
+// quoted string. An empty selector matches every token.
+type Selector struct {
+	root selectorNode
+}
+
+// ParseSelector parses the selector of a binding rule.
+func ParseSelector(s string) (*Selector, error) {
+	if strings.TrimSpace(s) == "" {
+		return &Selector{}, nil
+	}
+
+	tokens, err := lexSelector(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &selectorParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("invalid selector: unexpected %q", p.peek().text)
+	}
+	return &Selector{root: root}, nil
+}
+
+// Match returns whether the claim values satisfy the selector.
+func (s *Selector) Match(v *ClaimValues) bool {
+	if s.root == nil {
+		return true
+	}
+	return s.root.eval(v)
+}
+
+// selectorNode is a node of the parsed selector.
+type selectorNode interface {
+	eval(v *ClaimValues) bool
+}
+
+type orNode struct{ left, right selectorNode }
+
+func (n *orNode) eval(v *ClaimValues) bool { return n.left.eval(v) || n.right.eval(v) }
+
+type andNode struct{ left, right selectorNode }
+
+func (n *andNode) eval(v *ClaimValues) bool { return n.left.eval(v) && n.right.eval(v) }
+
+type notNode struct{ node selectorNode }
+
+func (n *notNode) eval(v *ClaimValues) bool { return !n.node.eval(v) }
+
+// equalNode compares a value variable to a string.
+type equalNode struct {
+	name  string
+	value string
+}
+
+func (n *equalNode) eval(v *ClaimValues) bool {
+	val, ok := v.Value[n.name]
+	return ok && val == n.value
+}
+
+// inNode checks that a list variable contains a string.
+type inNode struct {
+	name  string
+	value string
+}
+
+func (n *inNode) eval(v *ClaimValues) bool {
+	for _, item := range v.List[n.name] {
+		if item == n.value {
+			return true
+		}
+	}
+	return false
+}
+
+// emptyNode checks that a variable is unset or empty.
+type emptyNode struct {
+	list bool
+	name string
+}
+
+func (n *emptyNode) eval(v *ClaimValues) bool {
+	if n.list {
+		return len(v.List[n.name]) == 0
+	}
+	return v.Value[n.name] == ""
+}
+
+// selectorToken is a lexed token of a selector.
+type selectorToken struct {
+	text   string
+	quoted bool
+}
+
+// lexSelector splits a selector into tokens.
+func lexSelector(s string) ([]selectorToken, error) {
+	var tokens []selectorToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, selectorToken{text: string(c)})
+			i++
+		case c == '=' || c == '!':
+			if i+1 >= len(s) || s[i+1] != '=' {
+				return nil, fmt.Errorf("invalid selector: unexpected %q", string(c))
+			}
+			tokens = append(tokens, selectorToken{text: s[i : i+2]})
+			i += 2
+		case c == '"':
+			end := i + 1
+			for ; end < len(s); end++ {
+				if s[end] == '\\' {
+					end++
+					continue
+				}
+				if s[end] == '"' {
+					break
+				}
+			}
+			if end >= len(s) {
+				return nil, errors.New("invalid selector: unterminated string")
+			}
+			str, err := strconv.Unquote(s[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector: %v", err)
+			}
+			tokens = append(tokens, selectorToken{text: str, quoted: true})
+			i = end + 1
+		default:
+			end := i
+			for end < len(s) && isIdentChar(rune(s[end])) {
+				end++
+			}
+			if end == i {
+				return nil, fmt.Errorf("invalid selector: unexpected %q", string(c))
+			}
+			tokens = append(tokens, selectorToken{text: s[i:end]})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+func isIdentChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+// selectorParser is a recursive descent parser of selector tokens.
+type selectorParser struct {
+	tokens []selectorToken
+	pos    int
+}
+
+func (p *selectorParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *selectorParser) peek() selectorToken {
+	if p.done() {
+		return selectorToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+// keyword returns whether the next token is the unquoted keyword and
+// consumes it if so.
+func (p *selectorParser) keyword(k string) bool {
+	if t := p.peek(); !p.done() && !t.quoted && t.text == k {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *selectorParser) next() (selectorToken, error) {
+	if p.done() {
+		return selectorToken{}, errors.New("invalid selector: unexpected end")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *selectorParser) parseOr() (selectorNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *selectorParser) parseAnd() (selectorNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *selectorParser) parseTerm() (selectorNode, error) {
+	if p.keyword("not") {
+		node, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: node}, nil
+	}
+
+	if p.keyword("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, errors.New("invalid selector: missing )")
+		}
+		return node, nil
+	}
+
+	first, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+
+	// "<string> [not] in list.<name>"
+	if first.quoted {
+		negate := p.keyword("not")
+		if !p.keyword("in") {
+			return nil, fmt.Errorf("invalid selector: expected in after %q", first.text)
+		}
+		t, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		name, ok := variableName(t, "list.")
+		if !ok {
+			return nil, fmt.Errorf("invalid selector: %q is not a list variable", t.text)
+		}
+		var node selectorNode = &inNode{name: name, value: first.text}
+		if negate {
+			node = &notNode{node: node}
+		}
+		return node, nil
+	}
+
+	// "<variable> is [not] empty"
+	if p.keyword("is") {
+		negate := p.keyword("not")
+		if !p.keyword("empty") {
+			return nil, errors.New("invalid selector: expected empty after is")
+		}
+		var node selectorNode
+		if name, ok := variableName(first, "list."); ok {
+			node = &emptyNode{list: true, name: name}
+		} else if name, ok := variableName(first, "value."); ok {
+			node = &emptyNode{name: name}
+		} else {
+			return nil, fmt.Errorf("invalid selector: %q is not a variable", first.text)
+		}
+		if negate {
+			node = &notNode{node: node}
+		}
+		return node, nil
+	}
+
+	// "value.<name> (==|!=) <string>"
+	name, ok := variableName(first, "value.")
+	if !ok {
+		return nil, fmt.Errorf("invalid selector: %q is not a value variable", first.text)
+	}
+	op, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if op.quoted || (op.text != "==" && op.text != "!=") {
+		return nil, fmt.Errorf("invalid selector: expected == or != after %q", first.text)
+	}
+	val, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if !val.quoted {
+		return nil, fmt.Errorf("invalid selector: expected a quoted string after %s", op.text)
+	}
+
+	var node selectorNode = &equalNode{name: name, value: val.text}
+	if op.text == "!=" {
+		node = &notNode{node: node}
+	}
+	return node, nil
+}
+
+// variableName returns the name of an unquoted variable token with the
+// prefix.
+func variableName(t selectorToken, prefix string) (string, bool) {
+	if t.quoted || !strings.HasPrefix(t.text, prefix) || len(t.text) == len(prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(t.text, prefix), true
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapClaims(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	claims := Claims{
+		"email":  "alice@example.com",
+		"admin":  true,
+		"uid":    json.Number("1001"),
+		"groups": []interface{}{"dev", "ops"},
+		"org":    map[string]interface{}{"team": "infra"},
+	}
+
+	v, err := MapClaims(claims,
+		map[string]string{"email": "email", "admin": "admin", "uid": "uid", "/org/team": "team", "missing": "missing"},
+		map[string]string{"groups": "groups", "email": "emails"})
+	require.NoError(err)
+	require.Equal(map[string]string{
+		"email": "alice@example.com",
+		"admin": "true",
+		"uid":   "1001",
+		"team":  "infra",
+	}, v.Value)
+	require.Equal([]string{"dev", "ops"}, v.List["groups"])
+	require.Equal([]string{"alice@example.com"}, v.List["emails"])
+
+	// Objects can't be mapped to values
+	_, err = MapClaims(claims, map[string]string{"org": "org"}, nil)
+	require.Error(err)
+}
+
+func TestSelector(t *testing.T) {
+	t.Parallel()
+
+	v := &ClaimValues{
+		Value: map[string]string{"email": "alice@example.com", "team": "infra"},
+		List:  map[string][]string{"groups": {"dev", "ops"}},
+	}
+
+	cases := []struct {
+		selector string
+		match    bool
+	}{
+		{``, true},
+		{`value.team == "infra"`, true},
+		{`value.team != "infra"`, false},
+		{`value.missing == ""`, false},
+		{`"ops" in list.groups`, true},
+		{`"admin" in list.groups`, false},
+		{`"admin" not in list.groups`, true},
+		{`"dev" in list.groups and value.team == "infra"`, true},
+		{`"admin" in list.groups or value.team == "infra"`, true},
+		{`not (value.team == "infra" or "admin" in list.groups)`, false},
+		{`value.missing is empty and list.groups is not empty`, true},
+		{`value.email == "alice@example.com" and ("admin" in list.groups or "ops" in list.groups)`, true},
+	}
+
+	for _, c := range cases {
+		s, err := ParseSelector(c.selector)
+		if err != nil {
+			t.Fatalf("%q: %v", c.selector, err)
+		}
+		if got := s.Match(v); got != c.match {
+			t.Fatalf("%q: got %v; want %v", c.selector, got, c.match)
+		}
+	}
+}
+
+func TestSelector_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{
+		`value.team`,
+		`value.team = "infra"`,
+		`value.team == infra`,
+		`list.groups == "dev"`,
+		`"dev" in value.groups`,
+		`"dev" in`,
+		`(value.team == "infra"`,
+		`value.team == "infra" and`,
+		`value.team == "infra" "extra"`,
+		`"unterminated`,
+		`team is empty`,
+	} {
+		if _, err := ParseSelector(s); err == nil {
+			t.Fatalf("expected error for %q", s)
+		}
+	}
+}
+
+func TestInterpolateBindName(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	v := &ClaimValues{Value: map[string]string{"team": "infra", "empty": ""}}
+
+	name, ok := InterpolateBindName("team-${value.team}", v)
+	require.True(ok)
+	require.Equal("team-infra", name)
+
+	name, ok = InterpolateBindName("static", v)
+	require.True(ok)
+	require.Equal("static", name)
+
+	for _, n := range []string{"${value.missing}", "${value.empty}", "${list.groups}", "${value.team"} {
+		_, ok = InterpolateBindName(n, v)
+		require.False(ok, n)
+	}
+}
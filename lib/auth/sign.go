@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// Sign returns a compact JWS of the claims signed with the RSA or ECDSA
+// private key. RSA keys sign with RS256 and ECDSA keys with the ES algorithm
+// matching their curve. The key ID is set in the header if not empty.
+func Sign(claims Claims, key crypto.Signer, kid string) (string, error) {
+	var alg string
+	var hash crypto.Hash
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		alg, hash = "RS256", crypto.SHA256
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			alg, hash = "ES256", crypto.SHA256
+		case elliptic.P384():
+			alg, hash = "ES384", crypto.SHA384
+		case elliptic.P521():
+			alg, hash = "ES512", crypto.SHA512
+		default:
+			return "", fmt.Errorf("unsupported curve %s", k.Curve.Params().Name)
+		}
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	header, err := json.Marshal(jwtHeader{Algorithm: alg, KeyID: kid})
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+
+	h := hash.New()
+	h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, hash, digest)
+		if err != nil {
+			return "", err
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		if err != nil {
+			return "", err
+		}
+
+		// The signature is the concatenation of R and S, each padded to the
+		// size of the curve
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig = append(padBytes(r.Bytes(), size), padBytes(s.Bytes(), size)...)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign_RoundTrip(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(err)
+
+	cases := []struct {
+		alg string
+		key crypto.Signer
+	}{
+		{"ES384", ecKey},
+		{"RS256", rsaKey},
+	}
+
+	for _, c := range cases {
+		token, err := Sign(Claims{"sub": "alice"}, c.key, "kid")
+		require.NoError(err, c.alg)
+
+		p, err := parseToken(token)
+		require.NoError(err, c.alg)
+		require.Equal(c.alg, p.header.Algorithm)
+		require.Equal("kid", p.header.KeyID)
+
+		// The key is published as a JWK, which must decode to the same key
+		jwk, err := NewJSONWebKey("kid", c.alg, c.key.Public())
+		require.NoError(err, c.alg)
+		pub, err := jwk.publicKey()
+		require.NoError(err, c.alg)
+		keys := &staticKeySet{keys: []crypto.PublicKey{pub}}
+
+		// Tokens without an expiration are only accepted when allowed
+		_, err = Verify(token, keys, nil)
+		require.Error(err, c.alg)
+		claims, err := Verify(token, keys, &Expected{ExpirationOptional: true})
+		require.NoError(err, c.alg)
+		require.Equal("alice", claims["sub"])
+	}
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/go-testing-interface"
+)
+
+// TestProvider is an OpenID Connect provider for tests. It signs tokens with
+// an RSA key that is published both as a JWKS document and a PEM public key.
+type TestProvider struct {
+	Server   *httptest.Server
+	ClientID string
+	Secret   string
+
+	key *rsa.PrivateKey
+	kid string
+
+	l      sync.Mutex
+	claims Claims
+	codes  map[string]string // code -> nonce
+}
+
+// NewTestProvider starts a test provider. Callers must call Stop when done.
+func NewTestProvider(t testing.T) *TestProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	p := &TestProvider{
+		ClientID: "nomad",
+		Secret:   "secret",
+		key:      key,
+		kid:      "test-key",
+		claims:   Claims{"sub": "alice"},
+		codes:    make(map[string]string),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, p.handleDiscovery)
+	mux.HandleFunc("/keys", p.handleKeys)
+	mux.HandleFunc("/token", p.handleToken)
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+// Stop shuts down the provider.
+func (p *TestProvider) Stop() {
+	p.Server.Close()
+}
+
+// Issuer returns the issuer and discovery URL of the provider.
+func (p *TestProvider) Issuer() string {
+	return p.Server.URL
+}
+
+// SetClaims sets the custom claims of the ID tokens the provider issues.
+func (p *TestProvider) SetClaims(claims Claims) {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.claims = claims
+}
+
+// PublicKeyPEM returns the PEM encoded public key of the provider.
+func (p *TestProvider) PublicKeyPEM() string {
+	der, err := x509.MarshalPKIXPublicKey(&p.key.PublicKey)
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// SignToken returns a JWT with the claims signed by the provider's key.
+// Unless set by the claims, the token is issued by the provider and expires
+// in an hour.
+func (p *TestProvider) SignToken(claims Claims) string {
+	all := Claims{
+		"iss": p.Issuer(),
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range claims {
+		all[k] = v
+	}
+
+	token, err := Sign(all, p.key, p.kid)
+	if err != nil {
+		panic(err)
+	}
+	return token
+}
+
+// Authorize stands in for the user logging in at the authorization URL. It
+// returns the code and state the provider would send to the redirect URI.
+func (p *TestProvider) Authorize(authURL string) (code, state string, err error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	q := u.Query()
+	if q.Get("client_id") != p.ClientID {
+		return "", "", fmt.Errorf("unexpected client ID %q", q.Get("client_id"))
+	}
+
+	p.l.Lock()
+	defer p.l.Unlock()
+	code = fmt.Sprintf("code-%d", len(p.codes))
+	p.codes[code] = q.Get("nonce")
+	return code, q.Get("state"), nil
+}
+
+func (p *TestProvider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(map[string]string{
+		"issuer":                 p.Issuer(),
+		"authorization_endpoint": p.Issuer() + "/authorize",
+		"token_endpoint":         p.Issuer() + "/token",
+		"jwks_uri":               p.Issuer() + "/keys",
+	})
+}
+
+func (p *TestProvider) handleKeys(w http.ResponseWriter, r *http.Request) {
+	jwk, err := NewJSONWebKey(p.kid, "RS256", &p.key.PublicKey)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []*JSONWebKey{jwk},
+	})
+}
+
+func (p *TestProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	id, secret, _ := r.BasicAuth()
+	if id != p.ClientID || secret != p.Secret {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	p.l.Lock()
+	nonce, ok := p.codes[r.FormValue("code")]
+	delete(p.codes, r.FormValue("code"))
+	claims := Claims{"aud": p.ClientID, "nonce": nonce}
+	for k, v := range p.claims {
+		claims[k] = v
+	}
+	p.l.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     p.SignToken(claims),
+	})
+}
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -35,6 +36,72 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 	return resolveTokenFromSnapshotCache(snap, s.aclCache, secretID)
 }
 
+// ResolveSecretToken is used to translate an ACL Token Secret ID into the
+// ACL token, nil if ACLs are disabled, or an error.
+func (s *Server) ResolveSecretToken(secretID string) (*structs.ACLToken, error) {
+	// Fast-path if ACLs are disabled
+	if !s.config.ACLEnabled {
+		return nil, nil
+	}
+
+	// Handle anonymous requests
+	if secretID == "" {
+		return structs.AnonymousACLToken, nil
+	}
+
+	token, err := s.fsm.State().ACLTokenBySecretID(nil, secretID)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, structs.ErrTokenNotFound
+	}
+	if token.IsExpired(time.Now().UTC()) {
+		return nil, structs.ErrTokenExpired
+	}
+	return token, nil
+}
+
+// resolveTokenOrIdentity is used to translate either an ACL Token Secret ID
+// or a workload identity into an ACL object, nil if ACLs are disabled, or an
+// error. A workload identity grants access to the variables of its job.
+func (s *Server) resolveTokenOrIdentity(secretID string) (*acl.ACL, error) {
+	// Secret IDs are UUIDs, while workload identities are signed JWTs
+	if !s.config.ACLEnabled || strings.Count(secretID, ".") != 2 {
+		return s.ResolveToken(secretID)
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "resolveIdentity"}, time.Now())
+
+	claims, err := s.encrypter.VerifyClaims(secretID)
+	if err != nil {
+		return nil, structs.ErrTokenNotFound
+	}
+	allocID, _ := claims["nomad_allocation_id"].(string)
+	task, _ := claims["nomad_task"].(string)
+
+	// The identity is only valid while its allocation is running
+	alloc, err := s.fsm.State().AllocByID(nil, allocID)
+	if err != nil {
+		return nil, err
+	}
+	if alloc == nil || alloc.ClientTerminalStatus() {
+		return nil, structs.ErrTokenNotFound
+	}
+
+	caps := []string{acl.VariablesCapabilityRead, acl.VariablesCapabilityList}
+	var paths []*acl.VariablesPathPolicy
+	for _, path := range structs.VariablesTaskPaths(alloc.JobID, alloc.TaskGroup, task) {
+		paths = append(paths, &acl.VariablesPathPolicy{PathSpec: path, Capabilities: caps})
+	}
+	policy := &acl.Policy{
+		Namespaces: []*acl.NamespacePolicy{{
+			Name:      alloc.Namespace,
+			Variables: &acl.VariablesPolicy{Paths: paths},
+		}},
+	}
+	return acl.NewACL(false, []*acl.Policy{policy})
+}
+
 // resolveTokenFromSnapshotCache is used to resolve an ACL object from a snapshot of state,
 // using a cache to avoid parsing and ACL construction when possible. It is split from resolveToken
 // to simplify testing.
@@ -54,6 +121,9 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		if token == nil {
 			return nil, structs.ErrTokenNotFound
 		}
+		if token.IsExpired(time.Now().UTC()) {
+			return nil, structs.ErrTokenExpired
+		}
 	}
 
 	// Check if this is a management token
@@ -61,9 +131,13 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 		return acl.ManagementACL, nil
 	}
 
-	// Get all associated policies
-	policies := make([]*structs.ACLPolicy, 0, len(token.Policies))
-	for _, policyName := range token.Policies {
+	// Get all associated policies, including those granted by roles
+	policyNames, err := tokenPolicyNames(snap, token)
+	if err != nil {
+		return nil, err
+	}
+	policies := make([]*structs.ACLPolicy, 0, len(policyNames))
+	for _, policyName := range policyNames {
 		policy, err := snap.ACLPolicyByName(nil, policyName)
 		if err != nil {
 			return nil, err
@@ -84,3 +158,46 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *lru.TwoQueu
 	}
 	return aclObj, nil
 }
+
+// allowNsOpFunc returns a function checking whether the ACL allows an
+// operation in a namespace. A nil ACL, used when ACLs are disabled, allows
+// every operation.
+func allowNsOpFunc(aclObj *acl.ACL, op string) func(string) bool {
+	return func(ns string) bool {
+		return aclObj == nil || aclObj.AllowNsOp(ns, op)
+	}
+}
+
+// tokenPolicyNames returns the names of the policies a token is associated
+// with, either directly or through its roles. Roles that don't exist are
+// ignored, since they don't grant any more privilege.
+func tokenPolicyNames(snap *state.StateSnapshot, token *structs.ACLToken) ([]string, error) {
+	if len(token.Roles) == 0 {
+		return token.Policies, nil
+	}
+
+	seen := make(map[string]struct{}, len(token.Policies))
+	names := make([]string, 0, len(token.Policies))
+	add := func(policies []string) {
+		for _, name := range policies {
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	add(token.Policies)
+	for _, roleName := range token.Roles {
+		role, err := snap.ACLRoleByName(nil, roleName)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			continue
+		}
+		add(role.Policies)
+	}
+	return names, nil
+}
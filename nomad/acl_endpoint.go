@@ -3,14 +3,19 @@ package nomad
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -24,6 +29,17 @@ const (
 	// aclBootstrapReset is the file name to create in the data dir. It's only contents
 	// should be the reset index
 	aclBootstrapReset = "acl-bootstrap-reset"
+
+	// aclAuthProviderTimeout bounds the requests made to identity providers
+	// during a login
+	aclAuthProviderTimeout = 10 * time.Second
+
+	// oidcStateAudience is the audience of the state of OIDC logins, which
+	// keeps it from being accepted as a workload identity or the reverse
+	oidcStateAudience = "nomadproject.io/oidc-state"
+
+	// oidcStateTTL is how long an OIDC login may take to complete
+	oidcStateTTL = 10 * time.Minute
 )
 
 // ACL endpoint is used for manipulating ACL tokens and policies
@@ -146,8 +162,12 @@ func (a *ACL) ListPolicies(args *structs.ACLPolicyListRequest, reply *structs.AC
 			return structs.ErrTokenNotFound
 		}
 
-		policies = make(map[string]struct{}, len(token.Policies))
-		for _, p := range token.Policies {
+		names, err := tokenPolicyNames(snap, token)
+		if err != nil {
+			return err
+		}
+		policies = make(map[string]struct{}, len(names))
+		for _, p := range names {
 			policies[p] = struct{}{}
 		}
 	}
@@ -233,8 +253,13 @@ func (a *ACL) GetPolicy(args *structs.ACLPolicySpecificRequest, reply *structs.S
 			return structs.ErrTokenNotFound
 		}
 
+		names, err := tokenPolicyNames(snap, token)
+		if err != nil {
+			return err
+		}
+
 		found := false
-		for _, p := range token.Policies {
+		for _, p := range names {
 			if p == args.Name {
 				found = true
 				break
@@ -302,8 +327,22 @@ func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLP
 	if token == nil {
 		return structs.ErrTokenNotFound
 	}
-	if token.Type != structs.ACLManagementToken && !token.PolicySubset(args.Names) {
-		return structs.ErrPermissionDenied
+	if token.IsExpired(time.Now().UTC()) {
+		return structs.ErrTokenExpired
+	}
+	if token.Type != structs.ACLManagementToken {
+		// Policies granted through the roles of the token may also be queried
+		snap, err := a.srv.State().Snapshot()
+		if err != nil {
+			return err
+		}
+		names, err := tokenPolicyNames(snap, token)
+		if err != nil {
+			return err
+		}
+		if subset, _ := helper.SliceStringIsSubset(names, args.Names); !subset {
+			return structs.ErrPermissionDenied
+		}
 	}
 
 	// Setup the blocking query
@@ -336,6 +375,291 @@ func (a *ACL) GetPolicies(args *structs.ACLPolicySetRequest, reply *structs.ACLP
 	return a.srv.blockingRPC(&opts)
 }
 
+// UpsertRoles is used to create or update a set of roles
+func (a *ACL) UpsertRoles(args *structs.ACLRoleUpsertRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.UpsertRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Roles) == 0 {
+		return fmt.Errorf("must specify as least one role")
+	}
+
+	// Validate each role, compute hash
+	for idx, role := range args.Roles {
+		if err := role.Validate(); err != nil {
+			return fmt.Errorf("role %d invalid: %v", idx, err)
+		}
+		role.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRoleUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteRoles is used to delete roles
+func (a *ACL) DeleteRoles(args *structs.ACLRoleDeleteRequest, reply *structs.GenericResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	args.Region = a.srv.config.AuthoritativeRegion
+
+	if done, err := a.srv.forward("ACL.DeleteRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_roles"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of roles
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify as least one role")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLRoleDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListRoles is used to list the roles
+func (a *ACL) ListRoles(args *structs.ACLRoleListRequest, reply *structs.ACLRoleListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_roles"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine the roles that may be listed
+	mgt := acl.IsManagement()
+	var roles map[string]struct{}
+	if !mgt {
+		token, err := a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+		roles = helper.SliceStringToSet(token.Roles)
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the roles
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.ACLRoleByNamePrefix(ws, prefix)
+			} else {
+				iter, err = state.ACLRoles(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the roles to a list stub
+			reply.Roles = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				role := raw.(*structs.ACLRole)
+				if _, ok := roles[role.Name]; ok || mgt {
+					reply.Roles = append(reply.Roles, role.Stub())
+				}
+			}
+
+			// Use the last index that affected the role table
+			index, err := state.Index("acl_role")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRole is used to get a specific role
+func (a *ACL) GetRole(args *structs.ACLRoleSpecificRequest, reply *structs.SingleACLRoleResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetRole", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_role"}, time.Now())
+
+	// Check management level permissions
+	acl, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	} else if acl == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// If it is not a management token determine if it can get this role
+	if !acl.IsManagement() {
+		token, err := a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return structs.ErrTokenNotFound
+		}
+		if _, ok := helper.SliceStringToSet(token.Roles)[args.Name]; !ok {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the role
+			out, err := state.ACLRoleByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Role = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the role table
+				index, err := state.Index("acl_role")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetRoles is used to get a set of roles
+func (a *ACL) GetRoles(args *structs.ACLRoleSetRequest, reply *structs.ACLRoleSetResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetRoles", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_roles"}, time.Now())
+
+	var token *structs.ACLToken
+	var err error
+	if args.AuthToken == "" {
+		// No need to look up the anonymous token
+		token = structs.AnonymousACLToken
+	} else {
+		// For client typed tokens, allow them to query any roles associated with that token.
+		// This is used by clients which are resolving the policies granted by the roles.
+		token, err = a.srv.State().ACLTokenBySecretID(nil, args.AuthToken)
+		if err != nil {
+			return err
+		}
+	}
+
+	if token == nil {
+		return structs.ErrTokenNotFound
+	}
+	if token.IsExpired(time.Now().UTC()) {
+		return structs.ErrTokenExpired
+	}
+	if token.Type != structs.ACLManagementToken {
+		if subset, _ := helper.SliceStringIsSubset(token.Roles, args.Names); !subset {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Setup the output
+			reply.Roles = make(map[string]*structs.ACLRole, len(args.Names))
+
+			// Look for the role
+			for _, roleName := range args.Names {
+				out, err := state.ACLRoleByName(ws, roleName)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Roles[roleName] = out
+				}
+			}
+
+			// Use the last index that affected the role table
+			index, err := state.Index("acl_role")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
 // Bootstrap is used to bootstrap the initial token
 func (a *ACL) Bootstrap(args *structs.ACLTokenBootstrapRequest, reply *structs.ACLTokenUpsertResponse) error {
 	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
@@ -495,12 +819,32 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			return fmt.Errorf("token %d invalid: %v", idx, err)
 		}
 
+		// Verify the roles of the token exist
+		for _, roleName := range token.Roles {
+			role, err := state.ACLRoleByName(nil, roleName)
+			if err != nil {
+				return fmt.Errorf("role lookup failed: %v", err)
+			}
+			if role == nil {
+				return fmt.Errorf("token %d invalid: cannot find role %s", idx, roleName)
+			}
+		}
+
 		// Generate an accessor and secret ID if new
 		if token.AccessorID == "" {
 			token.AccessorID = uuid.Generate()
 			token.SecretID = uuid.Generate()
 			token.CreateTime = time.Now().UTC()
 
+			// Set the expiration time relative to the creation time
+			if token.ExpirationTTL != 0 {
+				expiration := token.CreateTime.Add(token.ExpirationTTL)
+				token.ExpirationTime = &expiration
+				token.ExpirationTTL = 0
+			} else if token.ExpirationTime != nil && !token.ExpirationTime.After(token.CreateTime) {
+				return fmt.Errorf("token %d invalid: expiration time must be in the future", idx)
+			}
+
 		} else {
 			// Verify the token exists
 			out, err := state.ACLTokenByAccessorID(nil, token.AccessorID)
@@ -515,6 +859,12 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 			if token.Global != out.Global {
 				return fmt.Errorf("cannot toggle global mode of %s", token.AccessorID)
 			}
+
+			// The expiration time is fixed when the token is created
+			if token.ExpirationTTL != 0 {
+				return fmt.Errorf("cannot set expiration TTL of existing token %s", token.AccessorID)
+			}
+			token.ExpirationTime = out.ExpirationTime
 		}
 
 		// Compute the token hash
@@ -831,3 +1181,779 @@ func (a *ACL) ResolveToken(args *structs.ResolveACLTokenRequest, reply *structs.
 	}
 	return nil
 }
+
+// UpsertAuthMethods is used to create or update a set of auth methods
+func (a *ACL) UpsertAuthMethods(args *structs.ACLAuthMethodUpsertRequest, reply *structs.GenericResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.UpsertAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.AuthMethods) == 0 {
+		return fmt.Errorf("must specify as least one auth method")
+	}
+
+	// Snapshot the state
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Find the existing default method of each type
+	defaults := make(map[string]string)
+	iter, err := state.ACLAuthMethods(nil)
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		method := raw.(*structs.ACLAuthMethod)
+		if method.Default {
+			defaults[method.Type] = method.Name
+		}
+	}
+
+	// Validate each auth method, compute hash
+	for idx, method := range args.AuthMethods {
+		if err := method.Validate(); err != nil {
+			return fmt.Errorf("auth method %d invalid: %v", idx, err)
+		}
+
+		// Only one method of each type may be the default
+		if method.Default {
+			if name, ok := defaults[method.Type]; ok && name != method.Name {
+				return fmt.Errorf("auth method %d invalid: default %s auth method already exists: %s", idx, method.Type, name)
+			}
+			defaults[method.Type] = method.Name
+		}
+		method.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteAuthMethods is used to delete auth methods along with their binding
+// rules
+func (a *ACL) DeleteAuthMethods(args *structs.ACLAuthMethodDeleteRequest, reply *structs.GenericResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.DeleteAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_auth_methods"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of auth methods
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify as least one auth method")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLAuthMethodDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListAuthMethods is used to list the auth methods. Any caller may list
+// them so that they can choose a method to log in with.
+func (a *ACL) ListAuthMethods(args *structs.ACLAuthMethodListRequest, reply *structs.ACLAuthMethodListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListAuthMethods", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_auth_methods"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the auth methods
+			iter, err := state.ACLAuthMethods(ws)
+			if err != nil {
+				return err
+			}
+
+			// Convert all the auth methods to a list stub
+			reply.AuthMethods = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				method := raw.(*structs.ACLAuthMethod)
+				if prefix := args.QueryOptions.Prefix; prefix != "" && !strings.HasPrefix(method.Name, prefix) {
+					continue
+				}
+				reply.AuthMethods = append(reply.AuthMethods, method.Stub())
+			}
+
+			// Use the last index that affected the auth method table
+			index, err := state.Index("acl_auth_method")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetAuthMethod is used to get a specific auth method
+func (a *ACL) GetAuthMethod(args *structs.ACLAuthMethodSpecificRequest, reply *structs.SingleACLAuthMethodResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetAuthMethod", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_auth_method"}, time.Now())
+
+	// Check management level permissions, the config may hold secrets
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the auth method
+			out, err := state.ACLAuthMethodByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.AuthMethod = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the auth method table
+				index, err := state.Index("acl_auth_method")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// UpsertBindingRules is used to create or update a set of binding rules
+func (a *ACL) UpsertBindingRules(args *structs.ACLBindingRuleUpsertRequest, reply *structs.ACLBindingRuleUpsertResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.UpsertBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "upsert_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.BindingRules) == 0 {
+		return fmt.Errorf("must specify as least one binding rule")
+	}
+
+	// Snapshot the state
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	// Validate each binding rule, compute hash
+	for idx, rule := range args.BindingRules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("binding rule %d invalid: %v", idx, err)
+		}
+		if _, err := auth.ParseSelector(rule.Selector); err != nil {
+			return fmt.Errorf("binding rule %d invalid: %v", idx, err)
+		}
+
+		// Verify the auth method exists
+		method, err := state.ACLAuthMethodByName(nil, rule.AuthMethod)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
+		if method == nil {
+			return fmt.Errorf("binding rule %d invalid: cannot find auth method %s", idx, rule.AuthMethod)
+		}
+
+		// Generate an ID if new, otherwise verify the rule exists
+		if rule.ID == "" {
+			rule.ID = uuid.Generate()
+		} else {
+			out, err := state.ACLBindingRuleByID(nil, rule.ID)
+			if err != nil {
+				return fmt.Errorf("binding rule lookup failed: %v", err)
+			}
+			if out == nil {
+				return fmt.Errorf("cannot find binding rule %s", rule.ID)
+			}
+		}
+		rule.SetHash()
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLBindingRuleUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to
+	// pickup the proper create / modify indexes.
+	state, err = a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, rule := range args.BindingRules {
+		out, err := state.ACLBindingRuleByID(nil, rule.ID)
+		if err != nil {
+			return fmt.Errorf("binding rule lookup failed: %v", err)
+		}
+		reply.BindingRules = append(reply.BindingRules, out)
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteBindingRules is used to delete binding rules
+func (a *ACL) DeleteBindingRules(args *structs.ACLBindingRuleDeleteRequest, reply *structs.GenericResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.DeleteBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "delete_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate non-zero set of binding rules
+	if len(args.IDs) == 0 {
+		return fmt.Errorf("must specify as least one binding rule")
+	}
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLBindingRuleDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListBindingRules is used to list the binding rules
+func (a *ACL) ListBindingRules(args *structs.ACLBindingRuleListRequest, reply *structs.ACLBindingRuleListResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.ListBindingRules", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "list_binding_rules"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Iterate over all the binding rules
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = state.ACLBindingRuleByIDPrefix(ws, prefix)
+			} else if args.AuthMethod != "" {
+				iter, err = state.ACLBindingRulesByAuthMethod(ws, args.AuthMethod)
+			} else {
+				iter, err = state.ACLBindingRules(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Convert all the binding rules to a list stub
+			reply.BindingRules = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				rule := raw.(*structs.ACLBindingRule)
+				if args.AuthMethod != "" && rule.AuthMethod != args.AuthMethod {
+					continue
+				}
+				reply.BindingRules = append(reply.BindingRules, rule.Stub())
+			}
+
+			// Use the last index that affected the binding rule table
+			index, err := state.Index("acl_binding_rule")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// GetBindingRule is used to get a specific binding rule
+func (a *ACL) GetBindingRule(args *structs.ACLBindingRuleSpecificRequest, reply *structs.SingleACLBindingRuleResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.GetBindingRule", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "get_binding_rule"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Look for the binding rule
+			out, err := state.ACLBindingRuleByID(ws, args.ID)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.BindingRule = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the binding rule table
+				index, err := state.Index("acl_binding_rule")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return a.srv.blockingRPC(&opts)
+}
+
+// Login is used to exchange a JWT signed by the identity provider of a JWT
+// auth method for an ACL token
+func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.Login", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "login"}, time.Now())
+
+	if args.LoginToken == "" {
+		return fmt.Errorf("missing login token")
+	}
+
+	method, err := a.authMethod(args.AuthMethodName, structs.ACLAuthMethodTypeJWT)
+	if err != nil {
+		return err
+	}
+
+	// Build the key set the token must be signed with
+	c := method.Config
+	client := aclAuthHTTPClient()
+	var keys auth.KeySet
+	switch {
+	case len(c.JWTValidationPubKeys) != 0:
+		keys, err = auth.NewStaticKeySet(c.JWTValidationPubKeys)
+		if err != nil {
+			return fmt.Errorf("auth method %s has invalid public keys: %v", method.Name, err)
+		}
+	case c.JWKSURL != "":
+		keys = auth.NewRemoteKeySet(c.JWKSURL, client)
+	default:
+		provider, err := auth.Discover(c.OIDCDiscoveryURL, client)
+		if err != nil {
+			return err
+		}
+		keys = provider.KeySet()
+	}
+
+	claims, err := auth.Verify(args.LoginToken, keys, &auth.Expected{
+		Issuer:    c.BoundIssuer,
+		Audiences: c.BoundAudiences,
+		Leeway:    c.ClockSkewLeeway,
+	})
+	if err != nil {
+		return fmt.Errorf("login token invalid: %v", err)
+	}
+
+	return a.loginToken(method, claims, reply)
+}
+
+// OIDCAuthURL is used to start an OIDC login. It returns the URL of the
+// provider the user must visit to log in. The provider then sends the user
+// to the redirect URI, which completes the login with OIDCCompleteAuth.
+func (a *ACL) OIDCAuthURL(args *structs.ACLOIDCAuthURLRequest, reply *structs.ACLOIDCAuthURLResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.OIDCAuthURL", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_auth_url"}, time.Now())
+
+	if args.ClientNonce == "" {
+		return fmt.Errorf("missing client nonce")
+	}
+
+	method, err := a.authMethod(args.AuthMethodName, structs.ACLAuthMethodTypeOIDC)
+	if err != nil {
+		return err
+	}
+	c := method.Config
+	if _, ok := helper.SliceStringToSet(c.AllowedRedirectURIs)[args.RedirectURI]; !ok {
+		return fmt.Errorf("redirect URI %q is not allowed by auth method %s", args.RedirectURI, method.Name)
+	}
+
+	provider, err := auth.Discover(c.OIDCDiscoveryURL, aclAuthHTTPClient())
+	if err != nil {
+		return err
+	}
+
+	// The state is returned to the caller by the provider with the code, so
+	// the caller can match the callback to the request it started. It is
+	// signed by the servers, which only complete logins they started. The
+	// client nonce is sent as the nonce of the ID token, which ties the
+	// token to the caller.
+	state, err := a.srv.encrypter.SignClaims(oidcStateClaims(method.Name, args.RedirectURI, args.ClientNonce, time.Now().UTC()))
+	if err != nil {
+		return fmt.Errorf("failed to sign OIDC state: %v", err)
+	}
+	reply.AuthURL, err = provider.AuthCodeURL(c.OIDCClientID, args.RedirectURI, state, args.ClientNonce, c.OIDCScopes)
+	if err != nil {
+		return err
+	}
+	reply.State = state
+	return nil
+}
+
+// oidcStateClaims returns the claims of the state of an OIDC login, binding
+// it to the auth method, the redirect URI and the nonce of the caller.
+func oidcStateClaims(method, redirectURI, nonce string, now time.Time) auth.Claims {
+	return auth.Claims{
+		"aud":   oidcStateAudience,
+		"iat":   now.Unix(),
+		"nbf":   now.Unix(),
+		"exp":   now.Add(oidcStateTTL).Unix(),
+		"jti":   uuid.Generate(),
+		"nonce": nonce,
+
+		"nomad_auth_method":  method,
+		"nomad_redirect_uri": redirectURI,
+	}
+}
+
+// verifyOIDCState verifies that the state was signed by the servers for a
+// login with the auth method, redirect URI and nonce that hasn't expired.
+func (a *ACL) verifyOIDCState(state, method, redirectURI, nonce string) error {
+	claims, err := auth.Verify(state, a.srv.encrypter, &auth.Expected{
+		Audiences: []string{oidcStateAudience},
+		Nonce:     nonce,
+		Leeway:    identityLeeway,
+	})
+	if err != nil {
+		return fmt.Errorf("state invalid: %v", err)
+	}
+	if m, _ := claims["nomad_auth_method"].(string); m != method {
+		return fmt.Errorf("state invalid: login was started with another auth method")
+	}
+	if uri, _ := claims["nomad_redirect_uri"].(string); uri != redirectURI {
+		return fmt.Errorf("state invalid: login was started with another redirect URI")
+	}
+	return nil
+}
+
+// OIDCCompleteAuth is used to complete an OIDC login with the authorization
+// code the provider sent to the redirect URI. It exchanges the code for an
+// ID token and creates an ACL token from its claims.
+func (a *ACL) OIDCCompleteAuth(args *structs.ACLOIDCCompleteAuthRequest, reply *structs.ACLLoginResponse) error {
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	if done, err := a.srv.forward("ACL.OIDCCompleteAuth", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_complete_auth"}, time.Now())
+
+	if args.ClientNonce == "" {
+		return fmt.Errorf("missing client nonce")
+	}
+	if args.State == "" || args.Code == "" {
+		return fmt.Errorf("missing state or code")
+	}
+
+	method, err := a.authMethod(args.AuthMethodName, structs.ACLAuthMethodTypeOIDC)
+	if err != nil {
+		return err
+	}
+	c := method.Config
+	if _, ok := helper.SliceStringToSet(c.AllowedRedirectURIs)[args.RedirectURI]; !ok {
+		return fmt.Errorf("redirect URI %q is not allowed by auth method %s", args.RedirectURI, method.Name)
+	}
+
+	// The state must be the one the login was started with, before the code
+	// is redeemed
+	if err := a.verifyOIDCState(args.State, method.Name, args.RedirectURI, args.ClientNonce); err != nil {
+		return err
+	}
+
+	provider, err := auth.Discover(c.OIDCDiscoveryURL, aclAuthHTTPClient())
+	if err != nil {
+		return err
+	}
+	idToken, err := provider.Exchange(c.OIDCClientID, c.OIDCClientSecret, args.RedirectURI, args.Code)
+	if err != nil {
+		return err
+	}
+
+	// The ID token must be issued to Nomad unless other audiences are bound
+	audiences := c.BoundAudiences
+	if len(audiences) == 0 {
+		audiences = []string{c.OIDCClientID}
+	}
+	claims, err := auth.Verify(idToken, provider.KeySet(), &auth.Expected{
+		Issuer:    provider.Issuer,
+		Audiences: audiences,
+		Nonce:     args.ClientNonce,
+		Leeway:    c.ClockSkewLeeway,
+	})
+	if err != nil {
+		return fmt.Errorf("ID token invalid: %v", err)
+	}
+
+	return a.loginToken(method, claims, reply)
+}
+
+// authMethod returns the auth method with the name, or the default method of
+// the type if the name is empty.
+func (a *ACL) authMethod(name, methodType string) (*structs.ACLAuthMethod, error) {
+	state := a.srv.State()
+	if name != "" {
+		method, err := state.ACLAuthMethodByName(nil, name)
+		if err != nil {
+			return nil, err
+		}
+		if method == nil {
+			return nil, fmt.Errorf("auth method %s not found", name)
+		}
+		if method.Type != methodType {
+			return nil, fmt.Errorf("auth method %s is not of type %s", name, methodType)
+		}
+		return method, nil
+	}
+
+	iter, err := state.ACLAuthMethods(nil)
+	if err != nil {
+		return nil, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		method := raw.(*structs.ACLAuthMethod)
+		if method.Default && method.Type == methodType {
+			return method, nil
+		}
+	}
+	return nil, fmt.Errorf("no default %s auth method", methodType)
+}
+
+// loginToken creates a local token for a login with the auth method. The
+// binding rules of the method whose selectors match the claims decide the
+// roles and policies of the token. The token expires after the max token
+// TTL of the method.
+func (a *ACL) loginToken(method *structs.ACLAuthMethod, claims auth.Claims, reply *structs.ACLLoginResponse) error {
+	values, err := auth.MapClaims(claims, method.Config.ClaimMappings, method.Config.ListClaimMappings)
+	if err != nil {
+		return err
+	}
+
+	// Snapshot the state
+	state, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	iter, err := state.ACLBindingRulesByAuthMethod(nil, method.Name)
+	if err != nil {
+		return err
+	}
+
+	management := false
+	roles := make(map[string]struct{})
+	policies := make(map[string]struct{})
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		rule := raw.(*structs.ACLBindingRule)
+		selector, err := auth.ParseSelector(rule.Selector)
+		if err != nil {
+			a.srv.logger.Printf("[WARN] nomad.acl: skipping invalid binding rule %s: %v", rule.ID, err)
+			continue
+		}
+		if !selector.Match(values) {
+			continue
+		}
+
+		if rule.BindType == structs.ACLBindingRuleBindTypeManagement {
+			management = true
+			continue
+		}
+
+		name, ok := auth.InterpolateBindName(rule.BindName, values)
+		if !ok {
+			continue
+		}
+		switch rule.BindType {
+		case structs.ACLBindingRuleBindTypeRole:
+			// Skip roles that don't exist, a token can't be linked to them
+			role, err := state.ACLRoleByName(nil, name)
+			if err != nil {
+				return fmt.Errorf("role lookup failed: %v", err)
+			}
+			if role != nil {
+				roles[name] = struct{}{}
+			}
+		case structs.ACLBindingRuleBindTypePolicy:
+			policies[name] = struct{}{}
+		}
+	}
+
+	token := &structs.ACLToken{
+		AccessorID: uuid.Generate(),
+		SecretID:   uuid.Generate(),
+		Name:       fmt.Sprintf("%s-%s", method.Type, method.Name),
+		Type:       structs.ACLClientToken,
+		CreateTime: time.Now().UTC(),
+	}
+	if management {
+		token.Type = structs.ACLManagementToken
+	} else {
+		token.Roles = sortedSet(roles)
+		token.Policies = sortedSet(policies)
+		if len(token.Roles) == 0 && len(token.Policies) == 0 {
+			return fmt.Errorf("no binding rules of auth method %s matched the login", method.Name)
+		}
+	}
+	expiration := token.CreateTime.Add(method.MaxTokenTTL)
+	token.ExpirationTime = &expiration
+	token.SetHash()
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{token},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Populate the response. We do a lookup against the state to
+	// pickup the proper create / modify indexes.
+	out, err := a.srv.State().ACLTokenByAccessorID(nil, token.AccessorID)
+	if err != nil {
+		return fmt.Errorf("token lookup failed: %v", err)
+	}
+	reply.Token = out
+	reply.Index = index
+	return nil
+}
+
+// aclAuthHTTPClient returns the client used to talk to identity providers.
+func aclAuthHTTPClient() *http.Client {
+	client := cleanhttp.DefaultClient()
+	client.Timeout = aclAuthProviderTimeout
+	return client
+}
+
+// sortedSet returns the members of the set in order.
+func sortedSet(set map[string]struct{}) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
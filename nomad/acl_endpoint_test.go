@@ -11,10 +11,12 @@ import (
 
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestACLEndpoint_GetPolicy(t *testing.T) {
@@ -519,6 +521,146 @@ func TestACLEndpoint_UpsertPolicies_Invalid(t *testing.T) {
 	}
 }
 
+func TestACLEndpoint_UpsertDeleteRoles(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the roles
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	req := &structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{r1, r2},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp))
+	require.NotZero(resp.Index)
+
+	out, err := s1.fsm.State().ACLRoleByName(nil, r1.Name)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// Roles must have policies
+	invalid := mock.ACLRole()
+	invalid.Policies = nil
+	req.Roles = []*structs.ACLRole{invalid}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertRoles", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing policies")
+
+	// List the roles
+	list := &structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var listResp structs.ACLRoleListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.ListRoles", list, &listResp))
+	require.Len(listResp.Roles, 2)
+
+	// Delete a role
+	del := &structs.ACLRoleDeleteRequest{
+		Names: []string{r1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.DeleteRoles", del, &resp))
+
+	out, err = s1.fsm.State().ACLRoleByName(nil, r1.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestACLEndpoint_GetRoles_TokenSubset(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a token granted a policy only through a role
+	policy := mock.ACLPolicy()
+	policy2 := mock.ACLPolicy()
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	role2 := mock.ACLRole()
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name}
+
+	state := s1.fsm.State()
+	require.NoError(state.UpsertACLPolicies(1000, []*structs.ACLPolicy{policy, policy2}))
+	require.NoError(state.UpsertACLRoles(1001, []*structs.ACLRole{role, role2}))
+	require.NoError(state.UpsertACLTokens(1002, []*structs.ACLToken{token}))
+
+	// The token can get its own roles
+	get := &structs.ACLRoleSetRequest{
+		Names: []string{role.Name},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.ACLRoleSetResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.GetRoles", get, &resp))
+	require.Equal(role, resp.Roles[role.Name])
+
+	// But not the other roles
+	get.Names = []string{role.Name, role2.Name}
+	err := msgpackrpc.CallWithCodec(codec, "ACL.GetRoles", get, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// The token can get the policies granted by its roles
+	getPolicies := &structs.ACLPolicySetRequest{
+		Names: []string{policy.Name},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var policyResp structs.ACLPolicySetResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.GetPolicies", getPolicies, &policyResp))
+	require.Equal(policy, policyResp.Policies[policy.Name])
+
+	getPolicies.Names = []string{policy2.Name}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.GetPolicies", getPolicies, &policyResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// The token can get its role and the policy with the single lookups
+	getRole := &structs.ACLRoleSpecificRequest{
+		Name: role.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var roleResp structs.SingleACLRoleResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.GetRole", getRole, &roleResp))
+	require.Equal(role, roleResp.Role)
+
+	getPolicy := &structs.ACLPolicySpecificRequest{
+		Name: policy.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var singlePolicyResp structs.SingleACLPolicyResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.GetPolicy", getPolicy, &singlePolicyResp))
+	require.Equal(policy, singlePolicyResp.Policy)
+}
+
 func TestACLEndpoint_GetToken(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -1089,6 +1231,71 @@ func TestACLEndpoint_UpsertTokens(t *testing.T) {
 	assert.Equal(t, created, out)
 }
 
+func TestACLEndpoint_UpsertTokens_RolesAndTTL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	role := mock.ACLRole()
+	require.NoError(s1.fsm.State().UpsertACLRoles(1000, []*structs.ACLRole{role}))
+
+	// Create a token with a role and a TTL
+	tk := mock.ACLToken()
+	tk.AccessorID = ""
+	tk.Policies = nil
+	tk.Roles = []string{role.Name}
+	tk.ExpirationTTL = time.Hour
+
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{tk},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLTokenUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp))
+
+	created := resp.Tokens[0]
+	require.Equal([]string{role.Name}, created.Roles)
+	require.NotNil(created.ExpirationTime)
+	require.Equal(created.CreateTime.Add(time.Hour), *created.ExpirationTime)
+	require.Zero(created.ExpirationTTL)
+
+	// Updating the token keeps the expiration time
+	update := *created
+	update.Name = "updated"
+	update.ExpirationTime = nil
+	req.Tokens = []*structs.ACLToken{&update}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp))
+	out, err := s1.fsm.State().ACLTokenByAccessorID(nil, created.AccessorID)
+	require.NoError(err)
+	require.Equal("updated", out.Name)
+	require.Equal(created.ExpirationTime, out.ExpirationTime)
+
+	// Unknown roles are rejected
+	tk2 := mock.ACLToken()
+	tk2.AccessorID = ""
+	tk2.Roles = []string{"missing"}
+	req.Tokens = []*structs.ACLToken{tk2}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "cannot find role missing")
+
+	// Expiration times in the past are rejected
+	tk3 := mock.ACLToken()
+	tk3.AccessorID = ""
+	past := time.Now().UTC().Add(-time.Minute)
+	tk3.ExpirationTime = &past
+	req.Tokens = []*structs.ACLToken{tk3}
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertTokens", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "must be in the future")
+}
+
 func TestACLEndpoint_UpsertTokens_Invalid(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -1147,3 +1354,340 @@ func TestACLEndpoint_ResolveToken(t *testing.T) {
 	assert.Equal(t, uint64(1000), resp.Index)
 	assert.Nil(t, resp.Token)
 }
+
+func TestACLEndpoint_UpsertDeleteAuthMethods(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the auth methods
+	m1 := mock.ACLAuthMethod()
+	m1.Default = true
+	m2 := mock.ACLAuthMethod()
+	req := &structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{m1, m2},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp))
+	require.NotZero(resp.Index)
+
+	// Only one method of a type may be the default
+	m3 := mock.ACLAuthMethod()
+	m3.Default = true
+	req.AuthMethods = []*structs.ACLAuthMethod{m3}
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertAuthMethods", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "default JWT auth method already exists")
+
+	// Anyone may list the methods
+	list := &structs.ACLAuthMethodListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.ACLAuthMethodListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.ListAuthMethods", list, &listResp))
+	require.Len(listResp.AuthMethods, 2)
+
+	// Getting a method needs a management token
+	get := &structs.ACLAuthMethodSpecificRequest{
+		Name:         m1.Name,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleACLAuthMethodResponse
+	err = msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethod", get, &getResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	get.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.GetAuthMethod", get, &getResp))
+	require.Equal(m1.Config, getResp.AuthMethod.Config)
+
+	// Delete a method
+	del := &structs.ACLAuthMethodDeleteRequest{
+		Names: []string{m1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.DeleteAuthMethods", del, &resp))
+
+	out, err := s1.fsm.State().ACLAuthMethodByName(nil, m1.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestACLEndpoint_UpsertDeleteBindingRules(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	method := mock.ACLAuthMethod()
+	require.NoError(s1.fsm.State().UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	// Binding rules need an existing auth method
+	rule := mock.ACLBindingRule()
+	rule.ID = ""
+	req := &structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{rule},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.ACLBindingRuleUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "cannot find auth method")
+
+	// The selector must parse
+	rule.AuthMethod = method.Name
+	rule.Selector = `groups contains "engineering"`
+	err = msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "invalid selector")
+
+	rule.Selector = `"engineering" in list.groups`
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.UpsertBindingRules", req, &resp))
+	require.Len(resp.BindingRules, 1)
+	created := resp.BindingRules[0]
+	require.NotEmpty(created.ID)
+	require.NotZero(created.CreateIndex)
+
+	// List the rules of the method
+	list := &structs.ACLBindingRuleListRequest{
+		AuthMethod: method.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var listResp structs.ACLBindingRuleListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.ListBindingRules", list, &listResp))
+	require.Len(listResp.BindingRules, 1)
+
+	list.AuthMethod = "other"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.ListBindingRules", list, &listResp))
+	require.Len(listResp.BindingRules, 0)
+
+	// Get the rule
+	get := &structs.ACLBindingRuleSpecificRequest{
+		ID: created.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var getResp structs.SingleACLBindingRuleResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.GetBindingRule", get, &getResp))
+	require.Equal(created, getResp.BindingRule)
+
+	// Delete the rule
+	del := &structs.ACLBindingRuleDeleteRequest{
+		IDs: []string{created.ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.DeleteBindingRules", del, &delResp))
+
+	out, err := s1.fsm.State().ACLBindingRuleByID(nil, created.ID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestACLEndpoint_Login(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	provider := auth.NewTestProvider(t)
+	defer provider.Stop()
+
+	// Create a JWT method with rules granting a role by group, a policy
+	// named after the team claim and management to admins
+	state := s1.fsm.State()
+	role := mock.ACLRole()
+	role.Name = "engineering"
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role}))
+
+	method := mock.ACLAuthMethod()
+	method.Default = true
+	method.Config = &structs.ACLAuthMethodConfig{
+		JWTValidationPubKeys: []string{provider.PublicKeyPEM()},
+		BoundIssuer:          provider.Issuer(),
+		BoundAudiences:       []string{"nomad"},
+		ClaimMappings:        map[string]string{"team": "team"},
+		ListClaimMappings:    map[string]string{"groups": "groups"},
+	}
+	require.NoError(state.UpsertACLAuthMethods(1001, []*structs.ACLAuthMethod{method}))
+
+	r1 := mock.ACLBindingRule()
+	r1.AuthMethod = method.Name
+	r2 := mock.ACLBindingRule()
+	r2.AuthMethod = method.Name
+	r2.Selector = `value.team is not empty`
+	r2.BindType = structs.ACLBindingRuleBindTypePolicy
+	r2.BindName = "team-${value.team}"
+	r3 := mock.ACLBindingRule()
+	r3.AuthMethod = method.Name
+	r3.Selector = `"admins" in list.groups`
+	r3.BindType = structs.ACLBindingRuleBindTypeManagement
+	r3.BindName = ""
+	require.NoError(state.UpsertACLBindingRules(1002, []*structs.ACLBindingRule{r1, r2, r3}))
+
+	req := &structs.ACLLoginRequest{
+		LoginToken: provider.SignToken(auth.Claims{
+			"aud":    "nomad",
+			"team":   "infra",
+			"groups": []string{"engineering"},
+		}),
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.ACLLoginResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp))
+
+	token := resp.Token
+	require.NotNil(token)
+	require.Equal(structs.ACLClientToken, token.Type)
+	require.Equal([]string{"engineering"}, token.Roles)
+	require.Equal([]string{"team-infra"}, token.Policies)
+	require.False(token.Global)
+	require.NotNil(token.ExpirationTime)
+	require.WithinDuration(token.CreateTime.Add(method.MaxTokenTTL), *token.ExpirationTime, time.Second)
+
+	out, err := state.ACLTokenBySecretID(nil, token.SecretID)
+	require.NoError(err)
+	require.Equal(token, out)
+
+	// Admins get a management token
+	req.AuthMethodName = method.Name
+	req.LoginToken = provider.SignToken(auth.Claims{"aud": "nomad", "groups": []string{"admins"}})
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp))
+	require.Equal(structs.ACLManagementToken, resp.Token.Type)
+
+	// No rule matches
+	req.LoginToken = provider.SignToken(auth.Claims{"aud": "nomad"})
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "no binding rules")
+
+	// Wrong audience
+	req.LoginToken = provider.SignToken(auth.Claims{"aud": "other", "groups": []string{"engineering"}})
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "audience")
+
+	// Signed by another provider
+	other := auth.NewTestProvider(t)
+	defer other.Stop()
+	req.LoginToken = other.SignToken(auth.Claims{"aud": "nomad", "iss": provider.Issuer()})
+	err = msgpackrpc.CallWithCodec(codec, "ACL.Login", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "signature")
+}
+
+func TestACLEndpoint_OIDCLogin(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	provider := auth.NewTestProvider(t)
+	defer provider.Stop()
+	provider.SetClaims(auth.Claims{"sub": "alice", "groups": []string{"engineering"}})
+
+	state := s1.fsm.State()
+	method := mock.ACLAuthMethod()
+	method.Type = structs.ACLAuthMethodTypeOIDC
+	method.Config = &structs.ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    provider.Issuer(),
+		OIDCClientID:        provider.ClientID,
+		OIDCClientSecret:    provider.Secret,
+		AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+		ListClaimMappings:   map[string]string{"groups": "groups"},
+	}
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method}))
+
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	rule.BindType = structs.ACLBindingRuleBindTypePolicy
+	rule.BindName = "engineering"
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule}))
+
+	// The redirect URI must be allowed
+	urlReq := &structs.ACLOIDCAuthURLRequest{
+		AuthMethodName: method.Name,
+		RedirectURI:    "http://evil.example.com/callback",
+		ClientNonce:    "nonce",
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var urlResp structs.ACLOIDCAuthURLResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.OIDCAuthURL", urlReq, &urlResp)
+	require.Error(err)
+	require.Contains(err.Error(), "not allowed")
+
+	urlReq.RedirectURI = "http://localhost:4649/oidc/callback"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.OIDCAuthURL", urlReq, &urlResp))
+	require.True(strings.HasPrefix(urlResp.AuthURL, provider.Issuer()+"/authorize?"))
+
+	// Log in at the provider and complete the login with the code
+	code, state2, err := provider.Authorize(urlResp.AuthURL)
+	require.NoError(err)
+	require.Equal(urlResp.State, state2)
+	complete := &structs.ACLOIDCCompleteAuthRequest{
+		AuthMethodName: method.Name,
+		ClientNonce:    "other",
+		State:          state2,
+		Code:           code,
+		RedirectURI:    urlReq.RedirectURI,
+		WriteRequest:   structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.ACLLoginResponse
+
+	// The nonce must match the one the login was started with
+	err = msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", complete, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "nonce")
+
+	// The state must be the one the servers signed for the login
+	code, _, err = provider.Authorize(urlResp.AuthURL)
+	require.NoError(err)
+	complete.Code = code
+	complete.ClientNonce = "nonce"
+	for _, forged := range []string{"", uuid.Generate(), provider.SignToken(auth.Claims{"nonce": "nonce"})} {
+		complete.State = forged
+		err = msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", complete, &resp)
+		require.Error(err)
+		require.Contains(err.Error(), "state")
+	}
+
+	// A state signed for another redirect URI is rejected
+	otherState, err := s1.encrypter.SignClaims(oidcStateClaims(method.Name, "http://localhost:4650/oidc/callback", "nonce", time.Now()))
+	require.NoError(err)
+	complete.State = otherState
+	err = msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", complete, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "another redirect URI")
+
+	complete.State = state2
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ACL.OIDCCompleteAuth", complete, &resp))
+	require.NotNil(resp.Token)
+	require.Equal([]string{"engineering"}, resp.Token.Policies)
+	require.Equal("OIDC-"+method.Name, resp.Token.Name)
+}
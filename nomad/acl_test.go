@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"testing"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/acl"
@@ -11,6 +12,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResolveACLToken(t *testing.T) {
@@ -92,6 +94,45 @@ func TestResolveACLToken(t *testing.T) {
 	}
 }
 
+func TestResolveACLToken_RolesAndExpiration(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	state := state.TestStateStore(t)
+	cache, err := lru.New2Q(16)
+	require.NoError(err)
+
+	// Create a token that is only granted its policy through a role
+	policy := mock.ACLPolicy()
+	role := mock.ACLRole()
+	role.Policies = []string{policy.Name}
+	token := mock.ACLToken()
+	token.Policies = nil
+	token.Roles = []string{role.Name, "missing"}
+
+	// Create an expired token
+	expired := mock.ACLToken()
+	past := time.Now().UTC().Add(-time.Minute)
+	expired.ExpirationTime = &past
+
+	require.NoError(state.UpsertACLPolicies(100, []*structs.ACLPolicy{policy}))
+	require.NoError(state.UpsertACLRoles(105, []*structs.ACLRole{role}))
+	require.NoError(state.UpsertACLTokens(110, []*structs.ACLToken{token, expired}))
+
+	snap, err := state.Snapshot()
+	require.NoError(err)
+
+	aclObj, err := resolveTokenFromSnapshotCache(snap, cache, token.SecretID)
+	require.NoError(err)
+	require.NotNil(aclObj)
+	require.False(aclObj.IsManagement())
+	require.True(aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+
+	aclObj, err = resolveTokenFromSnapshotCache(snap, cache, expired.SecretID)
+	require.Equal(structs.ErrTokenExpired, err)
+	require.Nil(aclObj)
+}
+
 func TestResolveACLToken_LeaderToken(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
@@ -107,3 +148,31 @@ func TestResolveACLToken_LeaderToken(t *testing.T) {
 		assert.True(token.IsManagement())
 	}
 }
+
+func TestResolveSecretToken(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	token := mock.ACLToken()
+	expired := mock.ACLToken()
+	past := time.Now().UTC().Add(-time.Minute)
+	expired.ExpirationTime = &past
+	require.NoError(s1.State().UpsertACLTokens(110, []*structs.ACLToken{token, expired}))
+
+	out, err := s1.ResolveSecretToken(token.SecretID)
+	require.NoError(err)
+	require.Equal(token.AccessorID, out.AccessorID)
+
+	out, err = s1.ResolveSecretToken("")
+	require.NoError(err)
+	require.Equal(structs.AnonymousACLToken, out)
+
+	_, err = s1.ResolveSecretToken(expired.SecretID)
+	require.Equal(structs.ErrTokenExpired, err)
+
+	_, err = s1.ResolveSecretToken(uuid.Generate())
+	require.Equal(structs.ErrTokenNotFound, err)
+}
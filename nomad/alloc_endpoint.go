@@ -8,6 +8,8 @@ import (
 	"github.com/hashicorp/go-memdb"
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -24,10 +26,14 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 	}
 	defer metrics.MeasureSince([]string{"nomad", "alloc", "list"}, time.Now())
 
-	// Check namespace read-job permissions
-	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check namespace read-job permissions. The allocations of all the
+	// namespaces are filtered down to the namespaces the token may read.
+	aclObj, err := a.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	}
+	allow := allowNsOpFunc(aclObj, acl.NamespaceCapabilityReadJob)
+	if namespace := args.RequestNamespace(); namespace != structs.AllNamespacesSentinel && !allow(namespace) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -41,6 +47,8 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 			var iter memdb.ResultIterator
 			if prefix := args.QueryOptions.Prefix; prefix != "" {
 				iter, err = state.AllocsByIDPrefix(ws, args.RequestNamespace(), prefix)
+			} else if status := args.FilterClientStatus; status != "" {
+				iter, err = state.AllocsByClientStatus(ws, args.RequestNamespace(), status)
 			} else {
 				iter, err = state.AllocsByNamespace(ws, args.RequestNamespace())
 			}
@@ -55,6 +63,12 @@ func (a *Alloc) List(args *structs.AllocListRequest, reply *structs.AllocListRes
 					break
 				}
 				alloc := raw.(*structs.Allocation)
+				if !allow(alloc.Namespace) {
+					continue
+				}
+				if args.FilterClientStatus != "" && alloc.ClientStatus != args.FilterClientStatus {
+					continue
+				}
 				allocs = append(allocs, alloc.Stub())
 			}
 			reply.Allocations = allocs
@@ -117,8 +131,9 @@ func (a *Alloc) GetAlloc(args *structs.AllocSpecificRequest,
 				return err
 			}
 
-			// Setup the output
-			reply.Alloc = out
+			// Setup the output. The workload identities are only sent to
+			// the node running the allocation, through GetClientAllocs.
+			reply.Alloc = out.Sanitize()
 			if out != nil {
 				reply.Index = out.ModifyIndex
 			} else {
@@ -168,8 +183,8 @@ func (a *Alloc) GetAllocs(args *structs.AllocsGetRequest,
 					break
 				}
 
-				// Store the pointer
-				allocs[i] = out
+				// Store the pointer, without the workload identities
+				allocs[i] = out.Sanitize()
 
 				// Check if we have passed the minimum index
 				if out.ModifyIndex > args.QueryOptions.MinQueryIndex {
@@ -233,3 +248,66 @@ func (a *Alloc) UpdateDesiredTransition(args *structs.AllocUpdateDesiredTransiti
 	reply.Index = index
 	return nil
 }
+
+// Stop is used to stop an allocation and migrate it to another node.
+func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopResponse) error {
+	if done, err := a.srv.forward("Alloc.Stop", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "alloc", "stop"}, time.Now())
+
+	if args.AllocID == "" {
+		return fmt.Errorf("must provide an alloc id")
+	}
+
+	snap, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return structs.NewErrUnknownAllocation(args.AllocID)
+	}
+
+	// Check namespace alloc-lifecycle permissions
+	if aclObj, err := a.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocLifecycle) {
+		return structs.ErrPermissionDenied
+	}
+
+	if alloc.TerminalStatus() {
+		return fmt.Errorf("allocation %q is already terminal", args.AllocID)
+	}
+
+	// Mark the allocation for migration and create an eval so the scheduler
+	// replaces it
+	eval := &structs.Evaluation{
+		ID:          uuid.Generate(),
+		Namespace:   alloc.Namespace,
+		Priority:    alloc.Job.Priority,
+		Type:        alloc.Job.Type,
+		TriggeredBy: structs.EvalTriggerAllocStop,
+		JobID:       alloc.JobID,
+		Status:      structs.EvalStatusPending,
+	}
+	transitionReq := &structs.AllocUpdateDesiredTransitionRequest{
+		Allocs: map[string]*structs.DesiredTransition{
+			alloc.ID: {Migrate: helper.BoolToPtr(true)},
+		},
+		Evals: []*structs.Evaluation{eval},
+	}
+
+	_, index, err := a.srv.raftApply(structs.AllocUpdateDesiredTransitionRequestType, transitionReq)
+	if err != nil {
+		a.srv.logger.Printf("[ERR] nomad.allocs: AllocUpdateDesiredTransitionRequest failed: %v", err)
+		return err
+	}
+
+	reply.EvalID = eval.ID
+	reply.Index = index
+	return nil
+}
@@ -82,6 +82,49 @@ func TestAllocEndpoint_List(t *testing.T) {
 	}
 }
 
+func TestAllocEndpoint_List_ClientStatus(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	alloc1 := mock.Alloc()
+	alloc2 := mock.Alloc()
+	alloc2.ClientStatus = structs.AllocClientStatusRunning
+	state := s1.fsm.State()
+	require.NoError(state.UpsertJobSummary(998, mock.JobSummary(alloc1.JobID)))
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc2.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc1, alloc2}))
+
+	// Lookup the running allocations
+	get := &structs.AllocListRequest{
+		FilterClientStatus: structs.AllocClientStatusRunning,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var resp structs.AllocListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp))
+	require.Equal(uint64(1000), resp.Index)
+	require.Len(resp.Allocations, 1)
+	require.Equal(alloc2.ID, resp.Allocations[0].ID)
+
+	// The filter applies to the prefix lookups
+	get.Prefix = alloc2.ID[:4]
+	var resp2 structs.AllocListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp2))
+	require.Len(resp2.Allocations, 1)
+	require.Equal(alloc2.ID, resp2.Allocations[0].ID)
+
+	get.FilterClientStatus = structs.AllocClientStatusFailed
+	var resp3 structs.AllocListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.List", get, &resp3))
+	require.Empty(resp3.Allocations)
+}
+
 func TestAllocEndpoint_List_ACL(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -429,6 +472,55 @@ func TestAllocEndpoint_GetAllocs(t *testing.T) {
 	}
 }
 
+func TestAllocEndpoint_GetAllocs_SignedIdentities(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create an alloc with a workload identity
+	alloc := mock.Alloc()
+	alloc.SignedIdentities = map[string]string{"web": "identity"}
+	state := s1.fsm.State()
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	token := mock.CreatePolicyAndToken(t, state, 1001, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+
+	// The identities aren't returned to the readers of the job
+	get := &structs.AllocSpecificRequest{
+		AllocID: alloc.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.SingleAllocResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.GetAlloc", get, &resp))
+	require.NotNil(resp.Alloc)
+	require.Nil(resp.Alloc.SignedIdentities)
+
+	getAllocs := &structs.AllocsGetRequest{
+		AllocIDs: []string{alloc.ID},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var allocsResp structs.AllocsGetResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Alloc.GetAllocs", getAllocs, &allocsResp))
+	require.Len(allocsResp.Allocs, 1)
+	require.Nil(allocsResp.Allocs[0].SignedIdentities)
+
+	// The stored alloc keeps its identities
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.NoError(err)
+	require.Equal("identity", out.SignedIdentities["web"])
+}
+
 func TestAllocEndpoint_GetAllocs_Blocking(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -567,3 +659,62 @@ func TestAllocEndpoint_UpdateDesiredTransition(t *testing.T) {
 	require.True(*out1.DesiredTransition.Migrate)
 	require.True(*out2.DesiredTransition.Migrate)
 }
+
+func TestAllocEndpoint_Stop_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	alloc := mock.Alloc()
+	state := s1.fsm.State()
+	require.Nil(state.UpsertJobSummary(998, mock.JobSummary(alloc.JobID)))
+	require.Nil(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	req := &structs.AllocStopRequest{
+		AllocID: alloc.ID,
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+
+	// Try without permissions
+	var resp structs.AllocStopResponse
+	err := msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp)
+	require.True(structs.IsErrPermissionDenied(err))
+
+	// Try with a token that can only read jobs
+	invalid := mock.CreatePolicyAndToken(t, state, 1005, "invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	req.AuthToken = invalid.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp)
+	require.True(structs.IsErrPermissionDenied(err))
+
+	// Try with an unknown alloc
+	valid := mock.CreatePolicyAndToken(t, state, 1007, "valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityAllocLifecycle}))
+	req.AuthToken = valid.SecretID
+	req.AllocID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp)
+	require.True(structs.IsErrUnknownAllocation(err))
+
+	// Try with a valid token
+	req.AllocID = alloc.ID
+	require.Nil(msgpackrpc.CallWithCodec(codec, "Alloc.Stop", req, &resp))
+	require.NotZero(resp.Index)
+	require.NotEmpty(resp.EvalID)
+
+	// The alloc should be marked for migration and the eval created
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.Nil(err)
+	require.True(out.DesiredTransition.ShouldMigrate())
+
+	eval, err := state.EvalByID(nil, resp.EvalID)
+	require.Nil(err)
+	require.NotNil(eval)
+	require.Equal(structs.EvalTriggerAllocStop, eval.TriggeredBy)
+	require.Equal(alloc.JobID, eval.JobID)
+}
@@ -3,6 +3,8 @@ package nomad
 import (
 	"context"
 	"fmt"
+	"sort"
+	"time"
 
 	"github.com/armon/go-metrics"
 	"github.com/hashicorp/consul/agent/consul/autopilot"
@@ -89,7 +91,109 @@ func (d *AutopilotDelegate) PromoteNonVoters(conf *autopilot.Config, health auto
 		return nil, fmt.Errorf("failed to get raft configuration: %v", err)
 	}
 
-	return autopilot.PromoteStableServers(conf, health, future.Configuration().Servers), nil
+	// Index the metadata of the servers by their Raft ID
+	parts := make(map[raft.ServerID]*serverParts)
+	for _, m := range d.server.serf.Members() {
+		if ok, p := isNomadServer(m); ok && p.Region == d.server.Region() {
+			parts[raft.ServerID(p.ID)] = p
+		}
+	}
+
+	promotions, demotions := autopilotPromotions(conf, health, future.Configuration().Servers, parts, time.Now())
+
+	// Demote the failed voters replaced by a standby of their redundancy zone
+	for _, server := range demotions {
+		d.server.logger.Printf("[INFO] autopilot: Demoting failed voter %q of redundancy zone %q",
+			server.ID, parts[server.ID].RedundancyZone)
+		if err := d.server.raft.DemoteVoter(server.ID, 0, 0).Error(); err != nil {
+			return nil, fmt.Errorf("failed to demote raft peer: %v", err)
+		}
+	}
+
+	return promotions, nil
+}
+
+// autopilotPromotions returns the non-voters to promote to voters, and the
+// voters to demote. Stable servers are promoted unless they are explicitly
+// non-voting. With redundancy zones, only one voter is kept by zone: a
+// standby of the zone is promoted if it has no healthy voter, and the failed
+// voters of the zone are demoted once a healthy voter is back.
+func autopilotPromotions(conf *autopilot.Config, health autopilot.OperatorHealthReply,
+	servers []raft.Server, parts map[raft.ServerID]*serverParts, now time.Time) ([]raft.Server, []raft.Server) {
+
+	zone := func(server raft.Server) string {
+		if conf.RedundancyZoneTag == "" {
+			return ""
+		}
+		if p, ok := parts[server.ID]; ok {
+			return p.RedundancyZone
+		}
+		return ""
+	}
+	healthy := func(server raft.Server) bool {
+		h := health.ServerHealth(string(server.ID))
+		return h != nil && h.Healthy
+	}
+
+	// Sort the servers for stable choices of the standbys to promote
+	sorted := make([]raft.Server, len(servers))
+	copy(sorted, servers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	// Find the voters of each zone
+	zoneVoters := make(map[string][]raft.Server)
+	zoneHealthy := make(map[string]bool)
+	for _, server := range sorted {
+		if z := zone(server); z != "" && autopilot.IsPotentialVoter(server.Suffrage) {
+			zoneVoters[z] = append(zoneVoters[z], server)
+			if healthy(server) {
+				zoneHealthy[z] = true
+			}
+		}
+	}
+
+	var promotions []raft.Server
+	for _, server := range sorted {
+		if autopilot.IsPotentialVoter(server.Suffrage) {
+			continue
+		}
+		if p, ok := parts[server.ID]; ok && p.NonVoter {
+			continue
+		}
+		if !health.ServerHealth(string(server.ID)).IsStable(now, conf) {
+			continue
+		}
+
+		// Servers of a zone with a healthy voter are kept as standbys
+		if z := zone(server); z != "" {
+			if zoneHealthy[z] {
+				continue
+			}
+			zoneHealthy[z] = true
+		}
+		promotions = append(promotions, server)
+	}
+
+	var demotions []raft.Server
+	for _, voters := range zoneVoters {
+		if len(voters) < 2 {
+			continue
+		}
+
+		// Only demote when a healthy voter remains in the zone
+		var failed []raft.Server
+		for _, server := range voters {
+			if !healthy(server) {
+				failed = append(failed, server)
+			}
+		}
+		if len(failed) < len(voters) {
+			demotions = append(demotions, failed...)
+		}
+	}
+	sort.Slice(demotions, func(i, j int) bool { return demotions[i].ID < demotions[j].ID })
+
+	return promotions, demotions
 }
 
 func (d *AutopilotDelegate) Raft() *raft.Raft {
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
+	"github.com/stretchr/testify/require"
 )
 
 // wantPeers determines whether the server has the given
@@ -351,3 +352,152 @@ func TestAutopilot_PromoteNonVoter(t *testing.T) {
 		}
 	})
 }
+
+func TestAutopilot_NonVotingServer(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.RaftConfig.ProtocolVersion = 3
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	s2 := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+		c.RaftConfig.ProtocolVersion = 3
+		c.NonVoter = true
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+
+	// Wait for the server to be stable long enough to have been promoted
+	retry.Run(t, func(r *retry.R) {
+		health := s1.autopilot.GetServerHealth(s2.config.NodeID)
+		if health == nil || !health.Healthy {
+			r.Fatalf("bad: %v", health)
+		}
+		if time.Since(health.StableSince) < 2*s1.config.AutopilotConfig.ServerStabilizationTime {
+			r.Fatal("stable period not elapsed")
+		}
+	})
+
+	// It is still a non-voter
+	future := s1.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		t.Fatal(err)
+	}
+	servers := future.Configuration().Servers
+	if len(servers) != 2 || servers[1].Suffrage != raft.Nonvoter {
+		t.Fatalf("bad: %v", servers)
+	}
+}
+
+func TestAutopilot_Promotions(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	stable := now.Add(-time.Minute)
+
+	// Servers are named after their zone ("-" for none), whether they are
+	// voters ("v"), non-voting servers ("x") or non-voters, and whether
+	// they are failed ("f"), stable ("s") or healthy
+	server := func(id string) raft.Server {
+		suffrage := raft.Nonvoter
+		if id[1] == 'v' {
+			suffrage = raft.Voter
+		}
+		return raft.Server{ID: raft.ServerID(id), Suffrage: suffrage}
+	}
+	health := func(servers ...string) autopilot.OperatorHealthReply {
+		var reply autopilot.OperatorHealthReply
+		for _, id := range servers {
+			h := autopilot.ServerHealth{ID: id, Healthy: id[2] != 'f', StableSince: now}
+			if id[2] == 's' {
+				h.StableSince = stable
+			}
+			reply.Servers = append(reply.Servers, h)
+		}
+		return reply
+	}
+	parts := func(servers ...string) map[raft.ServerID]*serverParts {
+		parts := make(map[raft.ServerID]*serverParts)
+		for _, id := range servers {
+			p := &serverParts{ID: id, NonVoter: id[1] == 'x'}
+			if id[0] != '-' {
+				p.RedundancyZone = id[:1]
+			}
+			parts[raft.ServerID(id)] = p
+		}
+		return parts
+	}
+
+	cases := []struct {
+		Name       string
+		Zones      bool
+		Servers    []string
+		Promotions []string
+		Demotions  []string
+	}{
+		{
+			Name:       "stable servers are promoted",
+			Servers:    []string{"-vs", "-ns", "-nh", "-nf"},
+			Promotions: []string{"-ns"},
+		},
+		{
+			Name:    "non-voting servers are never promoted",
+			Servers: []string{"-vs", "-xs"},
+		},
+		{
+			Name:       "zones are ignored unless enabled",
+			Servers:    []string{"avs", "ans", "bns"},
+			Promotions: []string{"ans", "bns"},
+		},
+		{
+			Name:       "one voter by zone",
+			Zones:      true,
+			Servers:    []string{"avs", "ans", "bns", "bos", "cns", "-ns"},
+			Promotions: []string{"-ns", "bns", "cns"},
+		},
+		{
+			Name:       "standby replaces failed voter",
+			Zones:      true,
+			Servers:    []string{"avf", "ans", "aos", "bvs"},
+			Promotions: []string{"ans"},
+		},
+		{
+			Name:      "failed voter is demoted once replaced",
+			Zones:     true,
+			Servers:   []string{"avf1", "avf2", "avs", "ans", "bvf"},
+			Demotions: []string{"avf1", "avf2"},
+		},
+		{
+			Name:    "failed voter is kept without replacement",
+			Zones:   true,
+			Servers: []string{"avf1", "avf2", "anh"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			conf := &autopilot.Config{ServerStabilizationTime: 10 * time.Second}
+			if c.Zones {
+				conf.RedundancyZoneTag = AutopilotRZTag
+			}
+
+			var servers []raft.Server
+			for _, id := range c.Servers {
+				servers = append(servers, server(id))
+			}
+
+			promotions, demotions := autopilotPromotions(conf, health(c.Servers...), servers, parts(c.Servers...), now)
+
+			var promoted, demoted []string
+			for _, s := range promotions {
+				promoted = append(promoted, string(s.ID))
+			}
+			for _, s := range demotions {
+				demoted = append(demoted, string(s.ID))
+			}
+			require.Equal(t, c.Promotions, promoted)
+			require.Equal(t, c.Demotions, demoted)
+		})
+	}
+}
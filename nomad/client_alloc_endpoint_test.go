@@ -2,7 +2,9 @@ package nomad
 
 import (
 	"fmt"
+	"net"
 	"testing"
+	"time"
 
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/acl"
@@ -14,6 +16,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/require"
+	codec "github.com/ugorji/go/codec"
 )
 
 func TestClientAllocations_GarbageCollectAll_Local(t *testing.T) {
@@ -760,3 +763,190 @@ func TestClientAllocations_Stats_Remote(t *testing.T) {
 	require.Nil(err)
 	require.NotNil(resp.Stats)
 }
+
+func TestClientAllocations_Signal_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer c.Shutdown()
+
+	// Force an allocation onto the node
+	a := mock.Alloc()
+	a.NodeID = c.NodeID()
+	a.Job.TaskGroups[0].Count = 1
+	a.Job.TaskGroups[0].Tasks[0] = &structs.Task{
+		Name:   "web",
+		Driver: "mock_driver",
+		Config: map[string]interface{}{
+			"run_for": "20s",
+		},
+		LogConfig: structs.DefaultLogConfig(),
+		Resources: &structs.Resources{
+			CPU:      500,
+			MemoryMB: 256,
+		},
+	}
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Upsert the allocation
+	state := s.State()
+	require.Nil(state.UpsertJob(999, a.Job))
+	require.Nil(state.UpsertAllocs(1003, []*structs.Allocation{a}))
+
+	// Wait for the client to run the allocation
+	testutil.WaitForResult(func() (bool, error) {
+		alloc, err := state.AllocByID(nil, a.ID)
+		if err != nil {
+			return false, err
+		}
+		if alloc == nil {
+			return false, fmt.Errorf("unknown alloc")
+		}
+		if alloc.ClientStatus != structs.AllocClientStatusRunning {
+			return false, fmt.Errorf("alloc client status: %v", alloc.ClientStatus)
+		}
+
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("Alloc on node %q not running: %v", c.NodeID(), err)
+	})
+
+	// Make the request without having an alloc id
+	req := &structs.AllocSignalRequest{
+		Signal:       "SIGHUP",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+
+	// Fetch the response
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "ClientAllocations.Signal", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "missing")
+
+	// Fetch the response setting the alloc id
+	req.AllocID = a.ID
+	var resp2 structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "ClientAllocations.Signal", req, &resp2)
+	require.Nil(err)
+}
+
+func TestClientAllocations_Exec_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create an allocation in a namespace the tokens below can't exec into
+	nsA, nsB := mock.Namespace(), mock.Namespace()
+	state := s.State()
+	require.Nil(state.UpsertNamespaces(1000, []*structs.Namespace{nsA, nsB}))
+
+	a := mock.Alloc()
+	a.Namespace = nsB.Name
+	a.Job.Namespace = nsB.Name
+	require.Nil(state.UpsertAllocs(1003, []*structs.Allocation{a}))
+
+	policyA := mock.NamespacePolicy(nsA.Name, "", []string{acl.NamespaceCapabilityAllocExec})
+	tokenA := mock.CreatePolicyAndToken(t, state, 1005, "exec-a", policyA)
+
+	policyB := mock.NamespacePolicy(nsB.Name, "", []string{acl.NamespaceCapabilityAllocExec})
+	tokenB := mock.CreatePolicyAndToken(t, state, 1007, "exec-b", policyB)
+
+	cases := []struct {
+		Name          string
+		Token         string
+		Namespace     string
+		ExpectedError string
+	}{
+		{
+			Name:          "token for other namespace",
+			Token:         tokenA.SecretID,
+			Namespace:     nsA.Name,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "token for alloc namespace",
+			Token:         tokenB.SecretID,
+			Namespace:     nsB.Name,
+			ExpectedError: "Unknown node",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			Namespace:     nsA.Name,
+			ExpectedError: "Unknown node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.AllocExecRequest{
+				AllocID: a.ID,
+				Task:    "web",
+				Cmd:     []string{"/bin/sh"},
+				QueryOptions: structs.QueryOptions{
+					Namespace: c.Namespace,
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			// Get the handler
+			handler, err := s.StreamingRpcHandler("Allocations.Exec")
+			require.Nil(err)
+
+			// Create a pipe
+			p1, p2 := net.Pipe()
+			defer p1.Close()
+			defer p2.Close()
+
+			// Start the handler
+			go handler(p2)
+
+			// Send the request
+			encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+			require.Nil(encoder.Encode(req))
+
+			// The request is rejected before reaching a client
+			msgCh := make(chan *cstructs.StreamErrWrapper, 1)
+			errCh := make(chan error, 1)
+			go func() {
+				var msg cstructs.StreamErrWrapper
+				decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+				if err := decoder.Decode(&msg); err != nil {
+					errCh <- err
+					return
+				}
+				msgCh <- &msg
+			}()
+
+			select {
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			case err := <-errCh:
+				t.Fatalf("error decoding: %v", err)
+			case msg := <-msgCh:
+				require.NotNil(msg.Error)
+				require.Contains(msg.Error.Error(), c.ExpectedError)
+			}
+		})
+	}
+}
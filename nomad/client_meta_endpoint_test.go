@@ -0,0 +1,135 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client"
+	"github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeMeta_Local(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server and client
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	c := client.TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.config.RPCAddr.String()}
+	})
+	defer c.Shutdown()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := s.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a clients")
+	})
+
+	// Make the request without having a node-id
+	req := &cstructs.NodeMetaApplyRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+		Meta:         map[string]*string{"canary": helper.StringToPtr("true")},
+	}
+	var resp cstructs.NodeMetaResponse
+	err := msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "missing")
+
+	// Unknown nodes are rejected
+	req.NodeID = uuid.Generate()
+	err = msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "Unknown node")
+
+	// Apply the metadata to the client
+	req.NodeID = c.NodeID()
+	require.Nil(msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp))
+	require.Equal("true", resp.Meta["canary"])
+
+	// Read it back
+	readReq := &structs.NodeSpecificRequest{
+		NodeID:       c.NodeID(),
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var readResp cstructs.NodeMetaResponse
+	require.Nil(msgpackrpc.CallWithCodec(codec, "NodeMeta.Read", readReq, &readResp))
+	require.Equal("true", readResp.Meta["canary"])
+
+	// The node is re-registered with the new metadata
+	testutil.WaitForResult(func() (bool, error) {
+		node, err := s.State().NodeByID(nil, c.NodeID())
+		if err != nil {
+			return false, err
+		}
+		return node != nil && node.Meta["canary"] == "true", nil
+	}, func(err error) {
+		t.Fatalf("node metadata not updated: %v", err)
+	})
+}
+
+func TestNodeMeta_Local_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Start a server
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	tokenRead := mock.CreatePolicyAndToken(t, s.State(), 1005, "read", mock.NodePolicy(acl.PolicyRead))
+	tokenWrite := mock.CreatePolicyAndToken(t, s.State(), 1009, "write", mock.NodePolicy(acl.PolicyWrite))
+
+	cases := []struct {
+		Name          string
+		Token         string
+		ExpectedError string
+	}{
+		{
+			Name:          "bad token",
+			Token:         tokenRead.SecretID,
+			ExpectedError: structs.ErrPermissionDenied.Error(),
+		},
+		{
+			Name:          "good token",
+			Token:         tokenWrite.SecretID,
+			ExpectedError: "Unknown node",
+		},
+		{
+			Name:          "root token",
+			Token:         root.SecretID,
+			ExpectedError: "Unknown node",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &cstructs.NodeMetaApplyRequest{
+				NodeID: uuid.Generate(),
+				Meta:   map[string]*string{"canary": helper.StringToPtr("true")},
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					AuthToken: c.Token,
+				},
+			}
+
+			var resp cstructs.NodeMetaResponse
+			err := msgpackrpc.CallWithCodec(codec, "NodeMeta.Apply", req, &resp)
+			require.NotNil(err)
+			require.Contains(err.Error(), c.ExpectedError)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"fmt"
 	"net"
 	"testing"
 
@@ -248,8 +249,25 @@ func TestServerWithNodeConn_NoPathAndErr(t *testing.T) {
 	testutil.WaitForLeader(t, s2.RPC)
 	testutil.WaitForLeader(t, s3.RPC)
 
-	// Shutdown the RPC layer for server 3
+	// Wait for the servers to copy the root key to each other, since the
+	// leader connects to the other servers to send it
+	for _, s := range []*Server{s2, s3} {
+		s := s
+		testutil.WaitForResult(func() (bool, error) {
+			active, err := s.fsm.State().ActiveRootKeyMeta(nil)
+			if err != nil || active == nil {
+				return false, fmt.Errorf("no active root key: %v", err)
+			}
+			return s.encrypter.HasKey(active.KeyID), fmt.Errorf("missing root key")
+		}, func(err error) {
+			t.Fatalf("err: %v", err)
+		})
+	}
+
+	// Shutdown the RPC layer for server 3, and close the connections made
+	// to it
 	s3.rpcListener.Close()
+	s1.connPool.ReloadTLS(nil)
 
 	srv, err := s1.serverWithNodeConn(uuid.Generate(), s1.Region())
 	require.Nil(srv)
@@ -98,11 +98,11 @@ type Config struct {
 	// RaftTimeout is applied to any network traffic for raft. Defaults to 10s.
 	RaftTimeout time.Duration
 
-	// (Enterprise-only) NonVoter is used to prevent this server from being added
-	// as a voting member of the Raft cluster.
+	// NonVoter is used to prevent this server from being added as a voting
+	// member of the Raft cluster.
 	NonVoter bool
 
-	// (Enterprise-only) RedundancyZone is the redundancy zone to use for this server.
+	// RedundancyZone is the redundancy zone to use for this server.
 	RedundancyZone string
 
 	// (Enterprise-only) UpgradeVersion is the custom upgrade version to use when
@@ -178,6 +178,10 @@ type Config struct {
 	// for GC. This gives users some time to view terminal deployments.
 	DeploymentGCThreshold time.Duration
 
+	// ACLTokenExpirationGCInterval is how often we dispatch a job to GC
+	// expired ACL tokens.
+	ACLTokenExpirationGCInterval time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -266,6 +270,16 @@ type Config struct {
 	// SentinelConfig is this Agent's Sentinel configuration
 	SentinelConfig *config.SentinelConfig
 
+	// AdmissionConfig configures the built-in and webhook admission
+	// controllers applied to the jobs on registration
+	AdmissionConfig *config.AdmissionConfig
+
+	// JobMutators and JobValidators are additional admission controllers
+	// applied to the jobs on registration, for programs embedding the
+	// server. Mutators run before validators, in order.
+	JobMutators   []JobMutator
+	JobValidators []JobValidator
+
 	// StatsCollectionInterval is the interval at which the Nomad server
 	// publishes metrics which are periodic in nature like updating gauges
 	StatsCollectionInterval time.Duration
@@ -290,6 +304,14 @@ type Config struct {
 	// autopilot tasks, such as promoting eligible non-voters and removing
 	// dead servers.
 	AutopilotInterval time.Duration
+
+	// DefaultSchedulerConfig is used to apply the initial scheduler config
+	// when bootstrapping.
+	DefaultSchedulerConfig structs.SchedulerConfiguration
+
+	// EventBufferSize is the number of the most recent events kept for the
+	// event stream subscriptions resuming from an index.
+	EventBufferSize int
 }
 
 // CheckVersion is used to check if the ProtocolVersion is valid
@@ -333,6 +355,7 @@ func DefaultConfig() *Config {
 		NodeGCThreshold:                  24 * time.Hour,
 		DeploymentGCInterval:             5 * time.Minute,
 		DeploymentGCThreshold:            1 * time.Hour,
+		ACLTokenExpirationGCInterval:     5 * time.Minute,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
@@ -358,6 +381,13 @@ func DefaultConfig() *Config {
 		},
 		ServerHealthInterval: 2 * time.Second,
 		AutopilotInterval:    10 * time.Second,
+		EventBufferSize:      100,
+		DefaultSchedulerConfig: structs.SchedulerConfiguration{
+			SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+			PreemptionConfig: structs.PreemptionConfig{
+				SystemSchedulerEnabled: true,
+			},
+		},
 	}
 
 	// Enable all known schedulers by default
@@ -46,6 +46,8 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.jobGC(eval)
 	case structs.CoreJobDeploymentGC:
 		return c.deploymentGC(eval)
+	case structs.CoreJobACLTokenExpiredGC:
+		return c.expiredACLTokenGC(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -64,6 +66,9 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.deploymentGC(eval); err != nil {
 		return err
 	}
+	if err := c.expiredACLTokenGC(eval); err != nil {
+		return err
+	}
 
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
@@ -282,11 +287,11 @@ func (c *CoreScheduler) gcEval(eval *structs.Evaluation, thresholdIndex uint64,
 		return false, nil, err
 	}
 
-	// If the eval is from a running "batch" job we don't want to garbage
-	// collect its allocations. If there is a long running batch job and its
-	// terminal allocations get GC'd the scheduler would re-run the
+	// If the eval is from a running "batch" or "sysbatch" job we don't want
+	// to garbage collect its allocations. If there is a long running batch job
+	// and its terminal allocations get GC'd the scheduler would re-run the
 	// allocations.
-	if eval.Type == structs.JobTypeBatch {
+	if eval.Type == structs.JobTypeBatch || eval.Type == structs.JobTypeSysBatch {
 		// Check if the job is running
 
 		// Can collect if:
@@ -597,6 +602,83 @@ func (c *CoreScheduler) partitionDeploymentReap(deployments []string) []*structs
 	return requests
 }
 
+// expiredACLTokenGC is used to garbage collect expired ACL tokens
+func (c *CoreScheduler) expiredACLTokenGC(eval *structs.Evaluation) error {
+	if !c.srv.config.ACLEnabled {
+		return nil
+	}
+
+	// Global tokens are only collected by the authoritative region, which
+	// replicates the deletion to the other regions.
+	authoritative := c.srv.config.Region == c.srv.config.AuthoritativeRegion
+
+	// Iterate over the tokens
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.ACLTokens(ws)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var local, global []string
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		token := raw.(*structs.ACLToken)
+
+		// Ignore tokens that haven't expired
+		if !token.IsExpired(now) {
+			continue
+		}
+
+		if !token.Global {
+			local = append(local, token.AccessorID)
+		} else if authoritative {
+			global = append(global, token.AccessorID)
+		}
+	}
+
+	// Fast-path the nothing case
+	if len(local)+len(global) == 0 {
+		return nil
+	}
+	c.srv.logger.Printf("[DEBUG] sched.core: expired ACL token GC: %d local and %d global tokens eligible",
+		len(local), len(global))
+
+	// Local and global tokens can't be deleted in the same request
+	if err := c.aclTokenReap(local, eval.LeaderACL); err != nil {
+		return err
+	}
+	return c.aclTokenReap(global, eval.LeaderACL)
+}
+
+// aclTokenReap contacts the leader and issues a delete of the passed tokens.
+func (c *CoreScheduler) aclTokenReap(accessorIDs []string, leaderACL string) error {
+	for len(accessorIDs) != 0 {
+		n := len(accessorIDs)
+		if n > maxIdsPerReap {
+			n = maxIdsPerReap
+		}
+		req := &structs.ACLTokenDeleteRequest{
+			AccessorIDs: accessorIDs[:n],
+			WriteRequest: structs.WriteRequest{
+				Region:    c.srv.config.Region,
+				AuthToken: leaderACL,
+			},
+		}
+		accessorIDs = accessorIDs[n:]
+
+		var resp structs.GenericResponse
+		if err := c.srv.RPC("ACL.DeleteTokens", req, &resp); err != nil {
+			c.srv.logger.Printf("[ERR] sched.core: expired ACL token reap failed: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
 // allocGCEligible returns if the allocation is eligible to be garbage collected
 // according to its terminal status and its reschedule trackers
 func allocGCEligible(a *structs.Allocation, job *structs.Job, gcTime time.Time, thresholdIndex uint64) bool {
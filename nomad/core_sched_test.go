@@ -1645,6 +1645,45 @@ func TestCoreScheduler_DeploymentGC(t *testing.T) {
 	assert.NotNil(out3, "Terminal Deployment With Allocs")
 }
 
+func TestCoreScheduler_ExpiredACLTokenGC(t *testing.T) {
+	t.Parallel()
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+	require := require.New(t)
+
+	// Insert an expired local, an expired global and an unexpired token
+	state := s1.fsm.State()
+	past := time.Now().UTC().Add(-time.Minute)
+	future := time.Now().UTC().Add(time.Hour)
+	tk1, tk2, tk3 := mock.ACLToken(), mock.ACLToken(), mock.ACLToken()
+	tk1.ExpirationTime = &past
+	tk2.ExpirationTime = &past
+	tk2.Global = true
+	tk3.ExpirationTime = &future
+	require.NoError(state.UpsertACLTokens(1000, []*structs.ACLToken{tk1, tk2, tk3}))
+
+	// Create a core scheduler
+	snap, err := state.Snapshot()
+	require.NoError(err)
+	core := NewCoreScheduler(s1, snap)
+
+	// Attempt the GC
+	gc := s1.coreJobEval(structs.CoreJobACLTokenExpiredGC, 2000)
+	require.NoError(core.Process(gc))
+
+	// Only the expired tokens should be gone
+	out, err := state.ACLTokenByAccessorID(nil, tk1.AccessorID)
+	require.NoError(err)
+	require.Nil(out)
+	out, err = state.ACLTokenByAccessorID(nil, tk2.AccessorID)
+	require.NoError(err)
+	require.Nil(out)
+	out, err = state.ACLTokenByAccessorID(nil, tk3.AccessorID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
 func TestCoreScheduler_DeploymentGC_Force(t *testing.T) {
 	t.Parallel()
 	for _, withAcl := range []bool{false, true} {
@@ -151,6 +151,16 @@ func (d *Deployment) Pause(args *structs.DeploymentPauseRequest, reply *structs.
 		return fmt.Errorf("can't resume terminal deployment")
 	}
 
+	// Pending deployments are started by the deployment watcher once their
+	// peer regions are ready
+	if deploy.Status == structs.DeploymentStatusPending {
+		if args.Pause {
+			return fmt.Errorf("can't pause pending deployment")
+		}
+
+		return fmt.Errorf("can't resume pending deployment")
+	}
+
 	// Call into the deployment watcher
 	return d.srv.deploymentWatcher.PauseDeployment(args, reply)
 }
@@ -192,6 +202,9 @@ func (d *Deployment) Promote(args *structs.DeploymentPromoteRequest, reply *stru
 	if !deploy.Active() {
 		return fmt.Errorf("can't promote terminal deployment")
 	}
+	if deploy.Status == structs.DeploymentStatusPending {
+		return fmt.Errorf("can't promote pending deployment")
+	}
 
 	// Call into the deployment watcher
 	return d.srv.deploymentWatcher.PromoteDeployment(args, reply)
@@ -251,10 +264,14 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 	}
 	defer metrics.MeasureSince([]string{"nomad", "deployment", "list"}, time.Now())
 
-	// Check namespace read-job permissions
-	if aclObj, err := d.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check namespace read-job permissions. The deployments of all the
+	// namespaces are filtered down to the namespaces the token may read.
+	aclObj, err := d.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	}
+	allow := allowNsOpFunc(aclObj, acl.NamespaceCapabilityReadJob)
+	if namespace := args.RequestNamespace(); namespace != structs.AllNamespacesSentinel && !allow(namespace) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -282,6 +299,9 @@ func (d *Deployment) List(args *structs.DeploymentListRequest, reply *structs.De
 					break
 				}
 				deploy := raw.(*structs.Deployment)
+				if !allow(deploy.Namespace) {
+					continue
+				}
 				deploys = append(deploys, deploy)
 			}
 			reply.Deployments = deploys
@@ -390,6 +390,61 @@ func TestDeploymentEndpoint_Pause(t *testing.T) {
 	assert.Equal(dout.ModifyIndex, resp.DeploymentModifyIndex, "wrong modify index")
 }
 
+func TestDeploymentEndpoint_Pending(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	assert := assert.New(t)
+
+	// Create a pending deployment
+	j := mock.Job()
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.Status = structs.DeploymentStatusPending
+	d.StatusDescription = structs.DeploymentStatusDescriptionPendingForPeer
+	state := s1.fsm.State()
+
+	assert.Nil(state.UpsertJob(999, j), "UpsertJob")
+	assert.Nil(state.UpsertDeployment(1000, d), "UpsertDeployment")
+
+	// Pending deployments can't be paused, resumed or promoted
+	pauseReq := &structs.DeploymentPauseRequest{
+		DeploymentID: d.ID,
+		Pause:        true,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.DeploymentUpdateResponse
+	err := msgpackrpc.CallWithCodec(codec, "Deployment.Pause", pauseReq, &resp)
+	assert.EqualError(err, "can't pause pending deployment")
+
+	pauseReq.Pause = false
+	err = msgpackrpc.CallWithCodec(codec, "Deployment.Pause", pauseReq, &resp)
+	assert.EqualError(err, "can't resume pending deployment")
+
+	promoteReq := &structs.DeploymentPromoteRequest{
+		DeploymentID: d.ID,
+		All:          true,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Deployment.Promote", promoteReq, &resp)
+	assert.EqualError(err, "can't promote pending deployment")
+
+	// They can be failed
+	failReq := &structs.DeploymentFailRequest{
+		DeploymentID: d.ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	assert.Nil(msgpackrpc.CallWithCodec(codec, "Deployment.Fail", failReq, &resp), "RPC")
+
+	dout, err := state.DeploymentByID(nil, d.ID)
+	assert.Nil(err, "DeploymentByID failed")
+	assert.Equal(structs.DeploymentStatusFailed, dout.Status)
+}
+
 func TestDeploymentEndpoint_Pause_ACL(t *testing.T) {
 	t.Parallel()
 	s1, _ := TestACLServer(t, func(c *Config) {
@@ -53,3 +53,57 @@ func (d *deploymentWatcherRaftShim) UpdateAllocDesiredTransition(req *structs.Al
 	fsmErrIntf, index, raftErr := d.apply(structs.AllocUpdateDesiredTransitionRequestType, req)
 	return d.convertApplyErrors(fsmErrIntf, index, raftErr)
 }
+
+// deploymentWatcherPeerShim is the shim that provides the methods to update
+// the deployments of multiregion jobs in peer regions. The requests are
+// forwarded to the peer regions using the replication token.
+type deploymentWatcherPeerShim struct {
+	// rpc is used to make an RPC that is forwarded to the peer region
+	rpc func(method string, args interface{}, reply interface{}) error
+
+	// token returns the token used to authenticate with the peer region
+	token func() string
+}
+
+func (d *deploymentWatcherPeerShim) LatestPeerDeployment(region, namespace, jobID string) (*structs.Deployment, error) {
+	args := &structs.JobSpecificRequest{
+		JobID: jobID,
+		QueryOptions: structs.QueryOptions{
+			Region:    region,
+			Namespace: namespace,
+			AuthToken: d.token(),
+		},
+	}
+	var resp structs.SingleDeploymentResponse
+	if err := d.rpc("Job.LatestDeployment", args, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Deployment, nil
+}
+
+func (d *deploymentWatcherPeerShim) ResumePeerDeployment(region string, deploy *structs.Deployment) error {
+	args := &structs.DeploymentPauseRequest{
+		DeploymentID: deploy.ID,
+		Pause:        false,
+		WriteRequest: structs.WriteRequest{
+			Region:    region,
+			Namespace: deploy.Namespace,
+			AuthToken: d.token(),
+		},
+	}
+	var resp structs.DeploymentUpdateResponse
+	return d.rpc("Deployment.Pause", args, &resp)
+}
+
+func (d *deploymentWatcherPeerShim) FailPeerDeployment(region string, deploy *structs.Deployment) error {
+	args := &structs.DeploymentFailRequest{
+		DeploymentID: deploy.ID,
+		WriteRequest: structs.WriteRequest{
+			Region:    region,
+			Namespace: deploy.Namespace,
+			AuthToken: d.token(),
+		},
+	}
+	var resp structs.DeploymentUpdateResponse
+	return d.rpc("Deployment.Fail", args, &resp)
+}
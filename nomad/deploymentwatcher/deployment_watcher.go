@@ -455,6 +455,12 @@ FAIL:
 				break FAIL
 			}
 
+			// Promote the deployment if its canaries are healthy and it is
+			// configured to be promoted automatically
+			if err := w.autoPromoteDeployment(updates.allocs); err != nil {
+				w.logger.Printf("[ERR] nomad.deployment_watcher: failed to auto promote deployment %q: %v", w.deploymentID, err)
+			}
+
 			// Create an eval to push the deployment along
 			if res.createEval || len(res.allowReplacements) != 0 {
 				w.createBatchedUpdate(res.allowReplacements, allocIndex)
@@ -567,6 +573,65 @@ func (w *deploymentWatcher) handleAllocUpdate(allocs []*structs.AllocListStub) (
 	return res, nil
 }
 
+// autoPromoteDeployment promotes the deployment once all of its canaries are
+// placed and healthy, if every task group with canaries has auto_promote set.
+// The task groups are promoted together since they are part of the same job
+// version.
+func (w *deploymentWatcher) autoPromoteDeployment(allocs []*structs.AllocListStub) error {
+	// Read the deployment from the state store since the promotion updates
+	// the allocations before the tracked deployment is updated
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		return err
+	}
+
+	d, err := snap.DeploymentByID(nil, w.deploymentID)
+	if err != nil {
+		return err
+	}
+	if !d.HasPlacedCanaries() || !d.RequiresPromotion() {
+		return nil
+	}
+
+	healthy := make(map[string]struct{}, len(allocs))
+	for _, alloc := range allocs {
+		if alloc.DeploymentStatus.IsHealthy() {
+			healthy[alloc.ID] = struct{}{}
+		}
+	}
+
+	for _, dstate := range d.TaskGroups {
+		if dstate.DesiredCanaries == 0 {
+			continue
+		}
+		if !dstate.AutoPromote || len(dstate.PlacedCanaries) < dstate.DesiredCanaries {
+			return nil
+		}
+
+		for _, id := range dstate.PlacedCanaries {
+			if _, ok := healthy[id]; !ok {
+				return nil
+			}
+		}
+	}
+
+	w.logger.Printf("[DEBUG] nomad.deployment_watcher: auto promoting deployment %q", w.deploymentID)
+	req := &structs.ApplyDeploymentPromoteRequest{
+		DeploymentPromoteRequest: structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			All:          true,
+		},
+		Eval: w.getEval(),
+	}
+	index, err := w.upsertDeploymentPromotion(req)
+	if err != nil {
+		return err
+	}
+
+	w.setLatestEval(index)
+	return nil
+}
+
 // shouldFail returns whether the job should be failed and whether it should
 // rolled back to an earlier stable version by examining the allocations in the
 // deployment.
@@ -53,6 +53,20 @@ type DeploymentRaftEndpoints interface {
 	UpdateAllocDesiredTransition(req *structs.AllocUpdateDesiredTransitionRequest) (uint64, error)
 }
 
+// DeploymentPeerEndpoints exposes the deployment watcher to the deployments
+// of a multiregion job in the job's other regions.
+type DeploymentPeerEndpoints interface {
+	// LatestPeerDeployment returns the latest deployment of the job in the
+	// region
+	LatestPeerDeployment(region, namespace, jobID string) (*structs.Deployment, error)
+
+	// ResumePeerDeployment is used to start a pending deployment in the region
+	ResumePeerDeployment(region string, d *structs.Deployment) error
+
+	// FailPeerDeployment is used to fail a deployment in the region
+	FailPeerDeployment(region string, d *structs.Deployment) error
+}
+
 // Watcher is used to watch deployments and their allocations created
 // by the scheduler and trigger the scheduler when allocation health
 // transitions.
@@ -71,6 +85,10 @@ type Watcher struct {
 	// deployments watcher
 	raft DeploymentRaftEndpoints
 
+	// peers contains the set of endpoints used to continue the rollout of
+	// multiregion jobs in their other regions
+	peers DeploymentPeerEndpoints
+
 	// state is the state that is watched for state changes.
 	state *state.StateStore
 
@@ -91,11 +109,12 @@ type Watcher struct {
 // NewDeploymentsWatcher returns a deployments watcher that is used to watch
 // deployments and trigger the scheduler as needed.
 func NewDeploymentsWatcher(logger *log.Logger,
-	raft DeploymentRaftEndpoints, stateQueriesPerSecond float64,
-	updateBatchDuration time.Duration) *Watcher {
+	raft DeploymentRaftEndpoints, peers DeploymentPeerEndpoints,
+	stateQueriesPerSecond float64, updateBatchDuration time.Duration) *Watcher {
 
 	return &Watcher{
 		raft:                raft,
+		peers:               peers,
 		queryLimiter:        rate.NewLimiter(rate.Limit(stateQueriesPerSecond), 100),
 		updateBatchDuration: updateBatchDuration,
 		logger:              logger,
@@ -146,6 +165,7 @@ func (w *Watcher) flush() {
 // add and remove watchers on.
 func (w *Watcher) watchDeployments(ctx context.Context) {
 	dindex := uint64(1)
+	resumed := false
 	for {
 		// Block getting all deployments using the last deployment index.
 		deployments, idx, err := w.getDeploys(ctx, dindex)
@@ -160,6 +180,13 @@ func (w *Watcher) watchDeployments(ctx context.Context) {
 		// Update the latest index
 		dindex = idx
 
+		// The rollouts of multiregion jobs are only tracked in memory, so
+		// pick up the ones a previous leader may not have handed off
+		if err == nil && !resumed {
+			resumed = true
+			w.resumeRollouts(ctx, deployments)
+		}
+
 		// Ensure we are tracking the things we should and not tracking what we
 		// shouldn't be
 		for _, d := range deployments {
@@ -270,6 +297,11 @@ func (w *Watcher) remove(d *structs.Deployment) {
 	if watcher, ok := w.watchers[d.ID]; ok {
 		watcher.StopWatch()
 		delete(w.watchers, d.ID)
+
+		// Continue the rollout of a multiregion job in its other regions
+		if watcher.j.IsMultiregion() {
+			go w.handlePeers(w.ctx, d, watcher.j)
+		}
 	}
 }
 
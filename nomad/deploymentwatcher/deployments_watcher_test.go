@@ -19,7 +19,7 @@ import (
 
 func testDeploymentWatcher(t *testing.T, qps float64, batchDur time.Duration) (*Watcher, *mockBackend) {
 	m := newMockBackend(t)
-	w := NewDeploymentsWatcher(testlog.Logger(t), m, qps, batchDur)
+	w := NewDeploymentsWatcher(testlog.Logger(t), m, m, qps, batchDur)
 	return w, m
 }
 
@@ -965,6 +965,71 @@ func TestDeploymentWatcher_Watch_ProgressDeadline_Canaries(t *testing.T) {
 	})
 }
 
+// Test that a deployment with auto_promote set is promoted once its canaries
+// are healthy
+func TestDeploymentWatcher_AutoPromote(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := testDeploymentWatcher(t, 1000.0, 1*time.Millisecond)
+
+	// Create a job, canary alloc, and a deployment
+	j := mock.Job()
+	j.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j.TaskGroups[0].Update.Canary = 1
+	j.TaskGroups[0].Update.AutoPromote = true
+	d := mock.Deployment()
+	d.StatusDescription = structs.DeploymentStatusDescriptionRunningNeedsPromotion
+	d.JobID = j.ID
+	d.TaskGroups["web"].DesiredCanaries = 1
+	d.TaskGroups["web"].AutoPromote = true
+	a := mock.Alloc()
+	a.DeploymentID = d.ID
+	a.DeploymentStatus = &structs.AllocDeploymentStatus{Canary: true}
+	d.TaskGroups["web"].PlacedCanaries = []string{a.ID}
+	require.Nil(m.state.UpsertJob(m.nextIndex(), j), "UpsertJob")
+	require.Nil(m.state.UpsertDeployment(m.nextIndex(), d), "UpsertDeployment")
+	require.Nil(m.state.UpsertAllocs(m.nextIndex(), []*structs.Allocation{a}), "UpsertAllocs")
+
+	// Assert that the deployment is promoted
+	matchConfig := &matchDeploymentPromoteRequestConfig{
+		Promotion: &structs.DeploymentPromoteRequest{
+			DeploymentID: d.ID,
+			All:          true,
+		},
+		Eval: true,
+	}
+	matcher := matchDeploymentPromoteRequest(matchConfig)
+	m.On("UpdateDeploymentPromotion", mocker.MatchedBy(matcher)).Return(nil).Once()
+	m.On("UpdateAllocDesiredTransition", mocker.Anything).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == len(w.watchers), nil },
+		func(err error) { require.Equal(1, len(w.watchers), "Should have 1 deployment") })
+
+	// Mark the canary as healthy
+	a2 := a.Copy()
+	a2.DeploymentStatus = &structs.AllocDeploymentStatus{
+		Healthy:   helper.BoolToPtr(true),
+		Canary:    true,
+		Timestamp: time.Now(),
+	}
+	require.Nil(m.state.UpdateAllocsFromClient(m.nextIndex(), []*structs.Allocation{a2}))
+
+	testutil.WaitForResult(func() (bool, error) {
+		dout, err := m.state.DeploymentByID(nil, d.ID)
+		if err != nil {
+			return false, err
+		}
+		if !dout.TaskGroups["web"].Promoted {
+			return false, fmt.Errorf("deployment not promoted")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	m.AssertCalled(t, "UpdateDeploymentPromotion", mocker.MatchedBy(matcher))
+}
+
 // Test that a promoted deployment with alloc healthy updates create
 // evals to move the deployment forward
 func TestDeploymentWatcher_PromotedCanary_UpdatedAllocs(t *testing.T) {
@@ -0,0 +1,174 @@
+package deploymentwatcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// peerUpdateAttempts is the number of times the deployment of a
+	// multiregion job in a peer region is looked up before giving up. The
+	// deployment may not have been created yet by the peer's scheduler.
+	peerUpdateAttempts = 5
+
+	// peerUpdateRetryInterval is the time to wait between the attempts to
+	// update the deployment in a peer region
+	peerUpdateRetryInterval = 10 * time.Second
+)
+
+// multiregionPeers returns the regions whose pending deployments should be
+// started and the regions whose deployments should be failed once the
+// deployment of the multiregion job in its region completes with the status.
+func multiregionPeers(job *structs.Job, status string) (run, fail []string) {
+	mr := job.Multiregion
+	idx := mr.RegionIndex(job.Region)
+	if idx < 0 {
+		return nil, nil
+	}
+
+	var maxParallel int
+	var onFailure string
+	if mr.Strategy != nil {
+		maxParallel = mr.Strategy.MaxParallel
+		onFailure = mr.Strategy.OnFailure
+	}
+
+	// next is the region that was waiting for this region to complete. If
+	// max parallel isn't set, all the regions were started at once.
+	var next []string
+	if n := idx + maxParallel; maxParallel != 0 && n < len(mr.Regions) {
+		next = []string{mr.Regions[n].Name}
+	}
+
+	switch status {
+	case structs.DeploymentStatusSuccessful:
+		return next, nil
+	case structs.DeploymentStatusFailed:
+		switch onFailure {
+		case structs.MultiregionOnFailureFailLocal:
+			return next, nil
+		case structs.MultiregionOnFailureFailAll:
+			for i, r := range mr.Regions {
+				if i != idx {
+					fail = append(fail, r.Name)
+				}
+			}
+		default:
+			for _, r := range mr.Regions[idx+1:] {
+				fail = append(fail, r.Name)
+			}
+		}
+		return nil, fail
+	default:
+		return nil, nil
+	}
+}
+
+// handlePeers continues the rollout of a multiregion job in the job's other
+// regions once its deployment in this region is complete.
+func (w *Watcher) handlePeers(ctx context.Context, d *structs.Deployment, job *structs.Job) {
+	if w.peers == nil {
+		return
+	}
+
+	run, fail := multiregionPeers(job, d.Status)
+	for _, region := range run {
+		w.updatePeer(ctx, region, job, true)
+	}
+	for _, region := range fail {
+		w.updatePeer(ctx, region, job, false)
+	}
+}
+
+// resumeRollouts continues the rollouts of the multiregion jobs whose latest
+// deployment in this region is complete. It is called when the watcher is
+// enabled since a previous leader may have lost leadership before handing the
+// rollout off to the peer regions. Peer deployments that were already started
+// or failed are left alone.
+func (w *Watcher) resumeRollouts(ctx context.Context, deployments []*structs.Deployment) {
+	if w.peers == nil {
+		return
+	}
+
+	snap, err := w.state.Snapshot()
+	if err != nil {
+		w.logger.Printf("[ERR] nomad.deployments_watcher: failed to resume multiregion rollouts: %v", err)
+		return
+	}
+
+	for _, d := range deployments {
+		if d.Active() {
+			continue
+		}
+
+		job, err := snap.JobByID(nil, d.Namespace, d.JobID)
+		if err != nil || job == nil || !job.IsMultiregion() || job.Stop || d.JobVersion != job.Version {
+			continue
+		}
+		latest, err := snap.LatestDeploymentByJobID(nil, d.Namespace, d.JobID)
+		if err != nil || latest == nil || latest.ID != d.ID {
+			continue
+		}
+
+		if run, fail := multiregionPeers(job, d.Status); len(run) == 0 && len(fail) == 0 {
+			continue
+		}
+		go w.handlePeers(ctx, d, job)
+	}
+}
+
+// updatePeer starts or fails the deployment of the job in the peer region,
+// retrying while the deployment can't be found.
+func (w *Watcher) updatePeer(ctx context.Context, region string, job *structs.Job, run bool) {
+	for attempt := 1; ; attempt++ {
+		err := w.updatePeerOnce(region, job, run)
+		if err == nil {
+			return
+		}
+
+		if attempt == peerUpdateAttempts {
+			w.logger.Printf("[ERR] nomad.deployments_watcher: failed to update deployment of job %q in region %q: %v", job.ID, region, err)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(peerUpdateRetryInterval):
+		}
+	}
+}
+
+// updatePeerOnce starts or fails the latest deployment of the job in the peer
+// region.
+func (w *Watcher) updatePeerOnce(region string, job *structs.Job, run bool) error {
+	d, err := w.peers.LatestPeerDeployment(region, job.Namespace, job.ID)
+	if err != nil {
+		return err
+	}
+
+	// The peer's scheduler may not have created the deployment yet
+	if d == nil || d.JobVersion < job.Version {
+		return fmt.Errorf("deployment for job version %d not found", job.Version)
+	}
+
+	if run {
+		// The deployment was already started, possibly by an operator
+		if d.Status != structs.DeploymentStatusPending {
+			return nil
+		}
+
+		w.logger.Printf("[DEBUG] nomad.deployments_watcher: starting deployment %q in region %q", d.ID, region)
+		return w.peers.ResumePeerDeployment(region, d)
+	}
+
+	if !d.Active() {
+		return nil
+	}
+
+	w.logger.Printf("[DEBUG] nomad.deployments_watcher: failing deployment %q in region %q", d.ID, region)
+	return w.peers.FailPeerDeployment(region, d)
+}
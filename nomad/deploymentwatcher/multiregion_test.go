@@ -0,0 +1,178 @@
+package deploymentwatcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	mocker "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func multiregionTestJob(region string, maxParallel int, onFailure string) *structs.Job {
+	job := mock.Job()
+	job.Region = region
+	job.Multiregion = &structs.Multiregion{
+		Strategy: &structs.MultiregionStrategy{
+			MaxParallel: maxParallel,
+			OnFailure:   onFailure,
+		},
+		Regions: []*structs.MultiregionRegion{
+			{Name: "west"},
+			{Name: "east"},
+			{Name: "north"},
+		},
+	}
+	return job
+}
+
+func TestMultiregionPeers(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		job       *structs.Job
+		status    string
+		expRun    []string
+		expFailed []string
+	}{
+		{
+			name:   "successful starts next region",
+			job:    multiregionTestJob("west", 1, ""),
+			status: structs.DeploymentStatusSuccessful,
+			expRun: []string{"east"},
+		},
+		{
+			name:   "successful with max parallel starts region after window",
+			job:    multiregionTestJob("west", 2, ""),
+			status: structs.DeploymentStatusSuccessful,
+			expRun: []string{"north"},
+		},
+		{
+			name:   "successful last region",
+			job:    multiregionTestJob("north", 1, ""),
+			status: structs.DeploymentStatusSuccessful,
+		},
+		{
+			name:   "successful without max parallel",
+			job:    multiregionTestJob("west", 0, ""),
+			status: structs.DeploymentStatusSuccessful,
+		},
+		{
+			name:      "failed fails following regions",
+			job:       multiregionTestJob("east", 1, ""),
+			status:    structs.DeploymentStatusFailed,
+			expFailed: []string{"north"},
+		},
+		{
+			name:      "failed with fail_all",
+			job:       multiregionTestJob("east", 1, structs.MultiregionOnFailureFailAll),
+			status:    structs.DeploymentStatusFailed,
+			expFailed: []string{"west", "north"},
+		},
+		{
+			name:   "failed with fail_local",
+			job:    multiregionTestJob("east", 1, structs.MultiregionOnFailureFailLocal),
+			status: structs.DeploymentStatusFailed,
+			expRun: []string{"north"},
+		},
+		{
+			name:   "cancelled",
+			job:    multiregionTestJob("west", 1, ""),
+			status: structs.DeploymentStatusCancelled,
+		},
+		{
+			name:   "unknown region",
+			job:    multiregionTestJob("south", 1, ""),
+			status: structs.DeploymentStatusSuccessful,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			run, failed := multiregionPeers(c.job, c.status)
+			require.Equal(t, c.expRun, run)
+			require.Equal(t, c.expFailed, failed)
+		})
+	}
+}
+
+func TestWatcher_UpdatePeerOnce(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	job := multiregionTestJob("west", 1, "")
+	job.Version = 2
+
+	// A deployment of an older version of the job is not updated
+	old := structs.NewDeployment(job)
+	old.JobVersion = 1
+	old.Status = structs.DeploymentStatusPending
+	m.On("LatestPeerDeployment", "east", job.Namespace, job.ID).Return(old, nil).Once()
+	require.Error(w.updatePeerOnce("east", job, true))
+
+	// A pending deployment of the job is resumed
+	d := structs.NewDeployment(job)
+	d.Status = structs.DeploymentStatusPending
+	m.On("LatestPeerDeployment", "east", job.Namespace, job.ID).Return(d, nil).Once()
+	m.On("ResumePeerDeployment", "east", d).Return(nil).Once()
+	require.NoError(w.updatePeerOnce("east", job, true))
+
+	// A deployment that was already started is left alone
+	running := d.Copy()
+	running.Status = structs.DeploymentStatusRunning
+	m.On("LatestPeerDeployment", "east", job.Namespace, job.ID).Return(running, nil).Once()
+	require.NoError(w.updatePeerOnce("east", job, true))
+
+	// An active deployment is failed
+	m.On("LatestPeerDeployment", "north", job.Namespace, job.ID).Return(d, nil).Once()
+	m.On("FailPeerDeployment", "north", mocker.Anything).Return(nil).Once()
+	require.NoError(w.updatePeerOnce("north", job, false))
+
+	m.AssertNumberOfCalls(t, "ResumePeerDeployment", 1)
+	m.AssertNumberOfCalls(t, "FailPeerDeployment", 1)
+}
+
+func TestWatcher_ResumeRollouts(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// A multiregion job whose deployment completed before the leader handed
+	// the rollout off to the next region
+	job := multiregionTestJob("west", 1, "")
+	require.NoError(m.state.UpsertJob(100, job))
+	d := structs.NewDeployment(job)
+	d.Status = structs.DeploymentStatusSuccessful
+	require.NoError(m.state.UpsertDeployment(101, d))
+
+	// A multiregion job whose completed deployment was superseded
+	job2 := multiregionTestJob("west", 1, "")
+	require.NoError(m.state.UpsertJob(102, job2))
+	old := structs.NewDeployment(job2)
+	old.Status = structs.DeploymentStatusSuccessful
+	require.NoError(m.state.UpsertDeployment(103, old))
+	d2 := structs.NewDeployment(job2)
+	d2.Status = structs.DeploymentStatusCancelled
+	require.NoError(m.state.UpsertDeployment(104, d2))
+
+	next := structs.NewDeployment(job)
+	next.Status = structs.DeploymentStatusPending
+	m.On("LatestPeerDeployment", "east", job.Namespace, job.ID).Return(next, nil).Once()
+	resumed := make(chan struct{})
+	m.On("ResumePeerDeployment", "east", next).Return(nil).Once().Run(func(mocker.Arguments) {
+		close(resumed)
+	})
+
+	w.SetEnabled(true, m.state)
+	select {
+	case <-resumed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("rollout wasn't resumed")
+	}
+
+	m.AssertNumberOfCalls(t, "LatestPeerDeployment", 1)
+	m.AssertNumberOfCalls(t, "ResumePeerDeployment", 1)
+}
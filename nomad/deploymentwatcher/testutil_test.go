@@ -265,3 +265,17 @@ func matchDeploymentAllocHealthRequest(c *matchDeploymentAllocHealthRequestConfi
 		return true
 	}
 }
+
+func (m *mockBackend) LatestPeerDeployment(region, namespace, jobID string) (*structs.Deployment, error) {
+	args := m.Called(region, namespace, jobID)
+	d, _ := args.Get(0).(*structs.Deployment)
+	return d, args.Error(1)
+}
+
+func (m *mockBackend) ResumePeerDeployment(region string, d *structs.Deployment) error {
+	return m.Called(region, d).Error(0)
+}
+
+func (m *mockBackend) FailPeerDeployment(region string, d *structs.Deployment) error {
+	return m.Called(region, d).Error(0)
+}
@@ -68,7 +68,7 @@ func (n *drainingNode) IsDone() (bool, error) {
 	for _, alloc := range allocs {
 		// System jobs are only stopped after a node is done draining
 		// everything else, so ignore them here.
-		if alloc.Job.Type == structs.JobTypeSystem {
+		if structs.IsSystemJobType(alloc.Job.Type) {
 			continue
 		}
 
@@ -109,7 +109,7 @@ func (n *drainingNode) RemainingAllocs() ([]*structs.Allocation, error) {
 		}
 
 		// Skip system if configured to
-		if alloc.Job.Type == structs.JobTypeSystem && ignoreSystem {
+		if structs.IsSystemJobType(alloc.Job.Type) && ignoreSystem {
 			continue
 		}
 
@@ -139,7 +139,7 @@ func (n *drainingNode) DrainingJobs() ([]structs.NamespacedID, error) {
 	jobIDs := make(map[structs.NamespacedID]struct{})
 	var jobs []structs.NamespacedID
 	for _, alloc := range allocs {
-		if alloc.TerminalStatus() || alloc.Job.Type == structs.JobTypeSystem {
+		if alloc.TerminalStatus() || structs.IsSystemJobType(alloc.Job.Type) {
 			continue
 		}
 
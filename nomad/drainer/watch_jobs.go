@@ -206,7 +206,7 @@ func (w *drainingJobWatcher) watch() {
 			}
 
 			// Ignore any system jobs
-			if job.Type == structs.JobTypeSystem {
+			if structs.IsSystemJobType(job.Type) {
 				w.deregisterJob(job.ID, job.Namespace)
 				continue
 			}
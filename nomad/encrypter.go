@@ -0,0 +1,343 @@
+package nomad
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// rootKeyEncryptionKeySize is the size of the AES-256 keys used to
+	// encrypt variables
+	rootKeyEncryptionKeySize = 32
+
+	// identityLeeway is the clock skew allowed between the server that
+	// signed a workload identity and the one verifying it
+	identityLeeway = time.Minute
+)
+
+// Encrypter signs workload identities and encrypts variables with the
+// active root key. The metadata and public keys of the root keys are stored
+// in the state store, while their key material is held in memory and in the
+// server's keystore. The parsed keys are cached by key ID.
+type Encrypter struct {
+	srv *Server
+
+	// keystore persists the key material, unless the server runs in
+	// development mode
+	keystore *keystore
+
+	l          sync.Mutex
+	keys       map[string]*structs.RootKey
+	signers    map[string]crypto.Signer
+	ciphers    map[string]cipher.AEAD
+	publicKeys map[string]crypto.PublicKey
+}
+
+// NewEncrypter returns an encrypter using the root keys of the server's
+// state store, loading their key material from the keystore in keystoreDir.
+// If keystoreDir is empty the key material is only kept in memory.
+func NewEncrypter(srv *Server, keystoreDir string) (*Encrypter, error) {
+	e := &Encrypter{
+		srv:        srv,
+		keys:       make(map[string]*structs.RootKey),
+		signers:    make(map[string]crypto.Signer),
+		ciphers:    make(map[string]cipher.AEAD),
+		publicKeys: make(map[string]crypto.PublicKey),
+	}
+	if keystoreDir == "" {
+		return e, nil
+	}
+
+	ks, err := newKeystore(keystoreDir)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := ks.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		e.keys[key.Meta.KeyID] = key
+	}
+	e.keystore = ks
+	return e, nil
+}
+
+// AddKey stores the key material of a root key in the keystore so the
+// server can use the key.
+func (e *Encrypter) AddKey(key *structs.RootKey) error {
+	if key == nil || key.Meta == nil || key.Meta.KeyID == "" {
+		return fmt.Errorf("missing root key")
+	}
+	if _, err := e.signer(key); err != nil {
+		return err
+	}
+	if _, err := e.cipher(key); err != nil {
+		return err
+	}
+
+	e.l.Lock()
+	defer e.l.Unlock()
+	if e.keystore != nil {
+		if err := e.keystore.Save(key); err != nil {
+			return fmt.Errorf("failed to save root key %s: %v", key.Meta.KeyID, err)
+		}
+	}
+	e.keys[key.Meta.KeyID] = key.Copy()
+	return nil
+}
+
+// GetKey returns the root key with its key material, or an error if the key
+// material isn't available on this server.
+func (e *Encrypter) GetKey(keyID string) (*structs.RootKey, error) {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	key, ok := e.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("root key %s is not available on this server", keyID)
+	}
+	return key.Copy(), nil
+}
+
+// HasKey returns whether the key material of the root key is available on
+// this server.
+func (e *Encrypter) HasKey(keyID string) bool {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	_, ok := e.keys[keyID]
+	return ok
+}
+
+// activeKey returns the active root key.
+func (e *Encrypter) activeKey() (*structs.RootKey, error) {
+	meta, err := e.srv.fsm.State().ActiveRootKeyMeta(nil)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("no active root key")
+	}
+	return e.GetKey(meta.KeyID)
+}
+
+// SignClaims returns the claims signed by the active root key.
+func (e *Encrypter) SignClaims(claims auth.Claims) (string, error) {
+	key, err := e.activeKey()
+	if err != nil {
+		return "", err
+	}
+
+	signer, err := e.signer(key)
+	if err != nil {
+		return "", err
+	}
+	return auth.Sign(claims, signer, key.Meta.KeyID)
+}
+
+// VerifyClaims verifies that the token is a workload identity signed by one
+// of the root keys and returns its claims.
+func (e *Encrypter) VerifyClaims(token string) (auth.Claims, error) {
+	return auth.Verify(token, e, &auth.Expected{
+		Audiences:          []string{structs.WorkloadIdentityAudience},
+		Leeway:             identityLeeway,
+		ExpirationOptional: true,
+	})
+}
+
+// Keys implements auth.KeySet with the public keys of the root keys. They
+// are replicated with the metadata of the keys, so any server can verify
+// workload identities.
+func (e *Encrypter) Keys(kid string) ([]crypto.PublicKey, error) {
+	meta, err := e.srv.fsm.State().RootKeyMetaByID(nil, kid)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("unknown root key %q", kid)
+	}
+
+	e.l.Lock()
+	defer e.l.Unlock()
+	if pub, ok := e.publicKeys[kid]; ok {
+		return []crypto.PublicKey{pub}, nil
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(meta.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key of root key %s: %v", kid, err)
+	}
+	e.publicKeys[kid] = pub
+	return []crypto.PublicKey{pub}, nil
+}
+
+// Encrypt encrypts the plaintext with the active root key, and returns the
+// ciphertext and the ID of the key.
+func (e *Encrypter) Encrypt(plaintext []byte) ([]byte, string, error) {
+	key, err := e.activeKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	aead, err := e.cipher(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The random nonce is prepended to the ciphertext
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(key.Meta.KeyID)), key.Meta.KeyID, nil
+}
+
+// Decrypt decrypts the ciphertext encrypted by the given root key.
+func (e *Encrypter) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
+	key, err := e.GetKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := e.cipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	size := aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, fmt.Errorf("malformed ciphertext")
+	}
+	return aead.Open(nil, ciphertext[:size], ciphertext[size:], []byte(keyID))
+}
+
+// signer returns the parsed private key of the root key.
+func (e *Encrypter) signer(key *structs.RootKey) (crypto.Signer, error) {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	if signer, ok := e.signers[key.Meta.KeyID]; ok {
+		return signer, nil
+	}
+
+	priv, err := x509.ParsePKCS8PrivateKey(key.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse root key %s: %v", key.Meta.KeyID, err)
+	}
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("root key %s is not a signing key", key.Meta.KeyID)
+	}
+	e.signers[key.Meta.KeyID] = signer
+	return signer, nil
+}
+
+// cipher returns the AES-GCM cipher of the root key.
+func (e *Encrypter) cipher(key *structs.RootKey) (cipher.AEAD, error) {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	if aead, ok := e.ciphers[key.Meta.KeyID]; ok {
+		return aead, nil
+	}
+
+	if len(key.EncryptionKey) != rootKeyEncryptionKeySize {
+		return nil, fmt.Errorf("root key %s has no encryption key", key.Meta.KeyID)
+	}
+	block, err := aes.NewCipher(key.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	e.ciphers[key.Meta.KeyID] = aead
+	return aead, nil
+}
+
+// checkRootKey returns an error unless the key material of the root key
+// matches the public key in its metadata. The encryption key can't be checked
+// on its own, but only the holders of the signing key have it.
+func checkRootKey(key *structs.RootKey, meta *structs.RootKeyMeta) error {
+	priv, err := x509.ParsePKCS8PrivateKey(key.Key)
+	if err != nil {
+		return fmt.Errorf("failed to parse root key %s: %v", meta.KeyID, err)
+	}
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("root key %s is not a signing key", meta.KeyID)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(pub, meta.PublicKey) {
+		return fmt.Errorf("root key %s doesn't match its public key", meta.KeyID)
+	}
+	return nil
+}
+
+// generateRootKey returns a new active root key.
+func generateRootKey() (*structs.RootKey, error) {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	encKey := make([]byte, rootKeyEncryptionKeySize)
+	if _, err := rand.Read(encKey); err != nil {
+		return nil, err
+	}
+
+	return &structs.RootKey{
+		Meta: &structs.RootKeyMeta{
+			KeyID:      uuid.Generate(),
+			Algorithm:  structs.RootKeyAlgorithmES256,
+			PublicKey:  pub,
+			Active:     true,
+			CreateTime: time.Now().UTC().UnixNano(),
+		},
+		Key:           der,
+		EncryptionKey: encKey,
+	}, nil
+}
+
+// identityClaims returns the claims of the workload identity of a task of
+// the allocation.
+func identityClaims(alloc *structs.Allocation, task string, now time.Time) auth.Claims {
+	return auth.Claims{
+		"sub": fmt.Sprintf("%s:%s:%s:%s", alloc.Namespace, alloc.JobID, alloc.TaskGroup, task),
+		"aud": structs.WorkloadIdentityAudience,
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+
+		"nomad_namespace":     alloc.Namespace,
+		"nomad_job_id":        alloc.JobID,
+		"nomad_task_group":    alloc.TaskGroup,
+		"nomad_task":          task,
+		"nomad_allocation_id": alloc.ID,
+	}
+}
@@ -49,6 +49,16 @@ func (e *Eval) GetEval(args *structs.EvalSpecificRequest,
 				return err
 			}
 
+			// Add the related evaluations to a copy of the evaluation
+			if out != nil && args.IncludeRelated {
+				related, err := state.EvalsRelatedToID(ws, out.ID)
+				if err != nil {
+					return err
+				}
+				out = out.Copy()
+				out.RelatedEvals = related
+			}
+
 			// Setup the output
 			reply.Eval = out
 			if out != nil {
@@ -325,10 +335,14 @@ func (e *Eval) List(args *structs.EvalListRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "eval", "list"}, time.Now())
 
-	// Check for read-job permissions
-	if aclObj, err := e.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check for read-job permissions. The evaluations of all the namespaces
+	// are filtered down to the namespaces the token may read.
+	aclObj, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+	}
+	allow := allowNsOpFunc(aclObj, acl.NamespaceCapabilityReadJob)
+	if namespace := args.RequestNamespace(); namespace != structs.AllNamespacesSentinel && !allow(namespace) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -356,6 +370,15 @@ func (e *Eval) List(args *structs.EvalListRequest,
 					break
 				}
 				eval := raw.(*structs.Evaluation)
+				if !allow(eval.Namespace) {
+					continue
+				}
+				if args.FilterJobID != "" && eval.JobID != args.FilterJobID {
+					continue
+				}
+				if args.FilterEvalStatus != "" && eval.Status != args.FilterEvalStatus {
+					continue
+				}
 				evals = append(evals, eval)
 			}
 			reply.Evaluations = evals
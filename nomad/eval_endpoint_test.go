@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEvalEndpoint_GetEval(t *testing.T) {
@@ -59,6 +60,50 @@ func TestEvalEndpoint_GetEval(t *testing.T) {
 	}
 }
 
+func TestEvalEndpoint_GetEval_Related(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Chain a blocked and a follow-up evaluation to an evaluation
+	eval1 := mock.Eval()
+	blocked := mock.Eval()
+	blocked.Status = structs.EvalStatusBlocked
+	blocked.PreviousEval = eval1.ID
+	followUp := mock.Eval()
+	followUp.TriggeredBy = structs.EvalTriggerRetryFailedAlloc
+	followUp.PreviousEval = blocked.ID
+	blocked.NextEval = followUp.ID
+	eval1.BlockedEval = blocked.ID
+	unrelated := mock.Eval()
+	require.NoError(s1.fsm.State().UpsertEvals(1000, []*structs.Evaluation{eval1, blocked, followUp, unrelated}))
+
+	// The related evaluations are not returned by default
+	get := &structs.EvalSpecificRequest{
+		EvalID:       eval1.ID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.SingleEvalResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.GetEval", get, &resp))
+	require.Nil(resp.Eval.RelatedEvals)
+
+	// All the chained evaluations are returned
+	get.IncludeRelated = true
+	var resp2 structs.SingleEvalResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.GetEval", get, &resp2))
+	require.Len(resp2.Eval.RelatedEvals, 2)
+	ids := []string{resp2.Eval.RelatedEvals[0].ID, resp2.Eval.RelatedEvals[1].ID}
+	require.ElementsMatch([]string{blocked.ID, followUp.ID}, ids)
+
+	// The stored evaluation is not modified
+	stored, err := s1.fsm.State().EvalByID(nil, eval1.ID)
+	require.NoError(err)
+	require.Nil(stored.RelatedEvals)
+}
+
 func TestEvalEndpoint_GetEval_ACL(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, nil)
@@ -606,6 +651,41 @@ func TestEvalEndpoint_Reap(t *testing.T) {
 	}
 }
 
+func TestEvalEndpoint_List_Filter(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	eval1 := mock.Eval()
+	eval2 := mock.Eval()
+	eval2.JobID = eval1.JobID
+	eval2.Status = structs.EvalStatusBlocked
+	eval3 := mock.Eval()
+	require.NoError(s1.fsm.State().UpsertEvals(1000, []*structs.Evaluation{eval1, eval2, eval3}))
+
+	// Filter on the job
+	get := &structs.EvalListRequest{
+		FilterJobID: eval1.JobID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	var resp structs.EvalListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp))
+	require.Len(resp.Evaluations, 2)
+
+	// Filter on the job and status
+	get.FilterEvalStatus = structs.EvalStatusBlocked
+	var resp2 structs.EvalListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Eval.List", get, &resp2))
+	require.Len(resp2.Evaluations, 1)
+	require.Equal(eval2.ID, resp2.Evaluations[0].ID)
+}
+
 func TestEvalEndpoint_List(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
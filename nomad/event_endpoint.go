@@ -0,0 +1,211 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/stream"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	// eventStreamHeartbeatRate is how often an empty set of events is sent
+	// on an idle event stream to keep the connection alive
+	eventStreamHeartbeatRate = 10 * time.Second
+)
+
+// Event endpoint is used to stream the events of the changes of the state of
+// the cluster.
+type Event struct {
+	srv *Server
+}
+
+func (e *Event) register() {
+	e.srv.streamingRpcs.Register("Event.Stream", e.stream)
+}
+
+// stream streams the events matching the topics of the request as newline
+// delimited JSON.
+func (e *Event) stream(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	defer metrics.MeasureSince([]string{"nomad", "event", "stream"}, time.Now())
+
+	// Decode the arguments
+	var args structs.EventStreamRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != e.srv.Region() {
+		e.forwardRegion(conn, encoder, &args)
+		return
+	}
+
+	// Check the permissions of the topics
+	if code, err := e.authorize(&args); err != nil {
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	sub := e.srv.eventBroker.Subscribe(&stream.SubscribeRequest{
+		Topics:    args.Topics,
+		Namespace: args.RequestNamespace(),
+		Index:     args.Index,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Create a goroutine to detect the remote side closing
+	go func() {
+		buf := make([]byte, 1)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				cancel()
+				return
+			}
+		}
+	}()
+
+	// Create a goroutine returning the events of the subscription
+	eventsCh := make(chan *structs.Events)
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			events, err := sub.Next(ctx)
+			if err != nil {
+				errCh <- err
+				return
+			}
+			select {
+			case eventsCh <- events:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var buf bytes.Buffer
+	jsonEncoder := codec.NewEncoder(&buf, structs.JsonHandle)
+	heartbeat := time.NewTicker(eventStreamHeartbeatRate)
+	defer heartbeat.Stop()
+
+	// Start with a heartbeat so the stream is established before any event
+	var events *structs.Events
+	for {
+		if events == nil {
+			buf.WriteString("{}")
+		} else if err := jsonEncoder.Encode(events); err != nil {
+			handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+			return
+		}
+		buf.WriteByte('\n')
+
+		resp := cstructs.StreamErrWrapper{Payload: buf.Bytes()}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+		encoder.Reset(conn)
+		buf.Reset()
+
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-errCh:
+			handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+			return
+		case <-heartbeat.C:
+			events = nil
+		case events = <-eventsCh:
+		}
+
+		// The token may have been deleted or its policies changed since the
+		// stream was opened, so check the permissions again before sending
+		// anything
+		if code, err := e.authorize(&args); err != nil {
+			handleStreamResultError(err, code, encoder)
+			return
+		}
+	}
+}
+
+// authorize returns an error, along with its code, if the token of the
+// request doesn't allow to stream the events of its topics.
+func (e *Event) authorize(args *structs.EventStreamRequest) (*int64, error) {
+	aclObj, err := e.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		if structs.IsErrTokenNotFound(err) {
+			return helper.Int64ToPtr(403), err
+		}
+		return helper.Int64ToPtr(500), err
+	}
+	if aclObj != nil && !allowEventTopics(aclObj, args.RequestNamespace(), args.Topics) {
+		return helper.Int64ToPtr(403), structs.ErrPermissionDenied
+	}
+	return nil, nil
+}
+
+// forwardRegion forwards an event stream to a server of another region
+func (e *Event) forwardRegion(conn io.ReadWriteCloser, encoder *codec.Encoder, args *structs.EventStreamRequest) {
+	region := args.RequestRegion()
+	e.srv.peerLock.RLock()
+	servers := e.srv.peers[region]
+	if len(servers) == 0 {
+		e.srv.peerLock.RUnlock()
+		handleStreamResultError(structs.ErrNoRegionPath, helper.Int64ToPtr(400), encoder)
+		return
+	}
+	server := servers[rand.Intn(len(servers))]
+	e.srv.peerLock.RUnlock()
+
+	srvConn, err := e.srv.streamingRpc(server, "Event.Stream")
+	if err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+	defer srvConn.Close()
+
+	// Send the request
+	outEncoder := codec.NewEncoder(srvConn, structs.MsgpackHandle)
+	if err := outEncoder.Encode(args); err != nil {
+		handleStreamResultError(err, helper.Int64ToPtr(500), encoder)
+		return
+	}
+
+	structs.Bridge(conn, srvConn)
+}
+
+// allowEventTopics returns whether an ACL allows to stream the events of the
+// given topics: the Node topic requires node read permissions, and the other
+// topics read-job permissions on the namespace.
+func allowEventTopics(aclObj *acl.ACL, namespace string, topics map[structs.Topic][]string) bool {
+	if len(topics) == 0 {
+		topics = map[structs.Topic][]string{structs.TopicAll: nil}
+	}
+	for topic := range topics {
+		if topic == structs.TopicNode || topic == structs.TopicAll {
+			if !aclObj.AllowNodeRead() {
+				return false
+			}
+		}
+		if topic != structs.TopicNode {
+			if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+				return false
+			}
+		}
+	}
+	return true
+}
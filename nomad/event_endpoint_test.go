@@ -0,0 +1,232 @@
+package nomad
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+// startEventStream starts an event stream on the server and returns the
+// channel of its messages
+func startEventStream(t *testing.T, s *Server, req *structs.EventStreamRequest) (<-chan *cstructs.StreamErrWrapper, func()) {
+	handler, err := s.StreamingRpcHandler("Event.Stream")
+	require.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	go handler(p2)
+
+	msgCh := make(chan *cstructs.StreamErrWrapper, 10)
+	go func() {
+		decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+		for {
+			var msg cstructs.StreamErrWrapper
+			if err := decoder.Decode(&msg); err != nil {
+				if err != io.EOF && !strings.Contains(err.Error(), "closed") {
+					t.Errorf("error decoding: %v", err)
+				}
+				return
+			}
+			msgCh <- &msg
+		}
+	}()
+
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.NoError(t, encoder.Encode(req))
+	return msgCh, func() {
+		p1.Close()
+		p2.Close()
+	}
+}
+
+func TestEvent_Stream(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s := TestServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	req := &structs.EventStreamRequest{
+		Topics: map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+	msgCh, stop := startEventStream(t, s, req)
+	defer stop()
+
+	// The stream starts with a heartbeat once subscribed
+	select {
+	case msg := <-msgCh:
+		require.Nil(msg.Error)
+		require.Equal("{}\n", string(msg.Payload))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for heartbeat")
+	}
+
+	job := mock.Job()
+	regReq := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", regReq, &regResp))
+
+	select {
+	case msg := <-msgCh:
+		require.Nil(msg.Error)
+		require.True(strings.HasSuffix(string(msg.Payload), "\n"))
+
+		var events structs.Events
+		require.NoError(json.Unmarshal(msg.Payload, &events))
+		require.Equal(regResp.JobModifyIndex, events.Index)
+		require.Len(events.Events, 1)
+		require.Equal(structs.TypeJobRegistered, events.Events[0].Type)
+		require.Equal(job.ID, events.Events[0].Key)
+		require.Equal(job.ID, events.Events[0].Payload.Job.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for events")
+	}
+}
+
+func TestEvent_Stream_ACL(t *testing.T) {
+	t.Parallel()
+
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	testutil.WaitForLeader(t, s.RPC)
+
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	token := mock.CreatePolicyAndToken(t, s.State(), 1005, "job-read", policy)
+
+	cases := []struct {
+		Name   string
+		Token  string
+		Topics map[structs.Topic][]string
+		Denied bool
+	}{
+		{
+			Name:   "job topic",
+			Token:  token.SecretID,
+			Topics: map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		},
+		{
+			Name:   "node topic",
+			Token:  token.SecretID,
+			Topics: map[structs.Topic][]string{structs.TopicNode: {"*"}},
+			Denied: true,
+		},
+		{
+			Name:   "all topics",
+			Token:  token.SecretID,
+			Denied: true,
+		},
+		{
+			Name:  "root token",
+			Token: root.SecretID,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			req := &structs.EventStreamRequest{
+				Topics: c.Topics,
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					Namespace: structs.DefaultNamespace,
+					AuthToken: c.Token,
+				},
+			}
+			msgCh, stop := startEventStream(t, s, req)
+			defer stop()
+
+			select {
+			case msg := <-msgCh:
+				if !c.Denied {
+					require.Nil(t, msg.Error)
+					require.Equal(t, "{}\n", string(msg.Payload))
+					return
+				}
+				require.NotNil(t, msg.Error)
+				require.Equal(t, int64(403), *msg.Error.Code)
+				require.Contains(t, msg.Error.Error(), structs.ErrPermissionDenied.Error())
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout waiting for the stream")
+			}
+		})
+	}
+}
+
+func TestEvent_Stream_ACL_Revoked(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s, root := TestACLServer(t, nil)
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	policy := mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob})
+	token := mock.CreatePolicyAndToken(t, s.State(), 1005, "job-read", policy)
+
+	req := &structs.EventStreamRequest{
+		Topics: map[structs.Topic][]string{structs.TopicJob: {"*"}},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	msgCh, stop := startEventStream(t, s, req)
+	defer stop()
+
+	select {
+	case msg := <-msgCh:
+		require.Nil(msg.Error)
+		require.Equal("{}\n", string(msg.Payload))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for heartbeat")
+	}
+
+	// Delete the token of the stream
+	require.NoError(s.State().DeleteACLTokens(1010, []string{token.AccessorID}))
+
+	// The events of the next job are not sent and the stream is closed
+	job := mock.Job()
+	regReq := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+			AuthToken: root.SecretID,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", regReq, &regResp))
+
+	select {
+	case msg := <-msgCh:
+		require.NotNil(msg.Error)
+		require.Equal(int64(403), *msg.Error.Code)
+		require.Empty(msg.Payload)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the stream to close")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/hashicorp/raft"
@@ -44,6 +45,17 @@ const (
 	DeploymentSnapshot
 	ACLPolicySnapshot
 	ACLTokenSnapshot
+	SchedulerConfigSnapshot
+	NamespaceSnapshot
+	QuotaSpecSnapshot
+	QuotaUsageSnapshot
+	ACLRoleSnapshot
+	ACLAuthMethodSnapshot
+	ACLBindingRuleSnapshot
+	RootKeySnapshot
+	VariableSnapshot
+	ServiceRegistrationSnapshot
+	JobSubmissionSnapshot
 )
 
 // LogApplier is the definition of a function that can apply a Raft log
@@ -67,10 +79,15 @@ type nomadFSM struct {
 	evalBroker         *EvalBroker
 	blockedEvals       *BlockedEvals
 	periodicDispatcher *PeriodicDispatch
+	eventBroker        *stream.EventBroker
 	logger             *log.Logger
 	state              *state.StateStore
 	timetable          *TimeTable
 
+	// events are the events of the log being applied, published to the
+	// event broker once it is applied
+	events []structs.Event
+
 	// config is the FSM config
 	config *FSMConfig
 
@@ -112,6 +129,10 @@ type FSMConfig struct {
 	// be added to.
 	Blocked *BlockedEvals
 
+	// EventBroker is the broker the events of the changes of the state are
+	// published to. Events aren't published if it is nil.
+	EventBroker *stream.EventBroker
+
 	// LogOutput is the writer logs should be written to
 	LogOutput io.Writer
 
@@ -135,6 +156,7 @@ func NewFSM(config *FSMConfig) (*nomadFSM, error) {
 		evalBroker:          config.EvalBroker,
 		periodicDispatcher:  config.Periodic,
 		blockedEvals:        config.Blocked,
+		eventBroker:         config.EventBroker,
 		logger:              log.New(config.LogOutput, "", log.LstdFlags|log.Lmicroseconds),
 		config:              config,
 		state:               state,
@@ -176,6 +198,9 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 	// Witness this write
 	n.timetable.Witness(log.Index, time.Now().UTC())
 
+	// Publish the events of the changes once applied
+	defer n.publishEvents(log.Index)
+
 	// Check if this message type should be ignored when unknown. This is
 	// used so that new commands can be added with developer control if older
 	// versions can safely ignore the command, or if they should crash.
@@ -246,6 +271,38 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyNodeEligibilityUpdate(buf[1:], log.Index)
 	case structs.BatchNodeUpdateDrainRequestType:
 		return n.applyBatchDrainUpdate(buf[1:], log.Index)
+	case structs.SchedulerConfigRequestType:
+		return n.applySchedulerConfigUpdate(buf[1:], log.Index)
+	case structs.NamespaceUpsertRequestType:
+		return n.applyNamespaceUpsert(buf[1:], log.Index)
+	case structs.NamespaceDeleteRequestType:
+		return n.applyNamespaceDelete(buf[1:], log.Index)
+	case structs.QuotaSpecUpsertRequestType:
+		return n.applyQuotaSpecUpsert(buf[1:], log.Index)
+	case structs.QuotaSpecDeleteRequestType:
+		return n.applyQuotaSpecDelete(buf[1:], log.Index)
+	case structs.ACLRoleUpsertRequestType:
+		return n.applyACLRoleUpsert(buf[1:], log.Index)
+	case structs.ACLRoleDeleteRequestType:
+		return n.applyACLRoleDelete(buf[1:], log.Index)
+	case structs.ACLAuthMethodUpsertRequestType:
+		return n.applyACLAuthMethodUpsert(buf[1:], log.Index)
+	case structs.ACLAuthMethodDeleteRequestType:
+		return n.applyACLAuthMethodDelete(buf[1:], log.Index)
+	case structs.ACLBindingRuleUpsertRequestType:
+		return n.applyACLBindingRuleUpsert(buf[1:], log.Index)
+	case structs.ACLBindingRuleDeleteRequestType:
+		return n.applyACLBindingRuleDelete(buf[1:], log.Index)
+	case structs.RootKeyUpsertRequestType:
+		return n.applyRootKeyUpsert(buf[1:], log.Index)
+	case structs.VariableUpsertRequestType:
+		return n.applyVariableUpsert(buf[1:], log.Index)
+	case structs.VariableDeleteRequestType:
+		return n.applyVariableDelete(buf[1:], log.Index)
+	case structs.ServiceRegistrationUpsertRequestType:
+		return n.applyServiceRegistrationUpsert(buf[1:], log.Index)
+	case structs.ServiceRegistrationDeleteByIDRequestType:
+		return n.applyServiceRegistrationDeleteByID(buf[1:], log.Index)
 	}
 
 	// Check enterprise only message types.
@@ -262,6 +319,52 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 	panic(fmt.Errorf("failed to apply request: %#v", buf))
 }
 
+// addEvent adds an event to the events of the log being applied
+func (n *nomadFSM) addEvent(event structs.Event) {
+	if n.eventBroker != nil {
+		n.events = append(n.events, event)
+	}
+}
+
+// addAllocEvents adds the events of updated allocations, looking up their
+// current state as the updates may be partial.
+func (n *nomadFSM) addAllocEvents(allocs []*structs.Allocation) {
+	if n.eventBroker == nil {
+		return
+	}
+	for _, update := range allocs {
+		alloc, err := n.state.AllocByID(nil, update.ID)
+		if err != nil || alloc == nil {
+			continue
+		}
+		n.addEvent(stream.AllocEvent(structs.TypeAllocUpdated, alloc))
+	}
+}
+
+// addDeploymentEvent adds the event of an updated deployment
+func (n *nomadFSM) addDeploymentEvent(eventType, deploymentID string) {
+	if n.eventBroker == nil {
+		return
+	}
+	deployment, err := n.state.DeploymentByID(nil, deploymentID)
+	if err != nil || deployment == nil {
+		return
+	}
+	n.addEvent(stream.DeploymentEvent(eventType, deployment))
+}
+
+// publishEvents publishes the events of an applied log to the event broker
+func (n *nomadFSM) publishEvents(index uint64) {
+	if len(n.events) == 0 {
+		return
+	}
+	for i := range n.events {
+		n.events[i].Index = index
+	}
+	n.eventBroker.Publish(&structs.Events{Index: index, Events: n.events})
+	n.events = nil
+}
+
 func (n *nomadFSM) applyUpsertNode(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_node"}, time.Now())
 	var req structs.NodeRegisterRequest
@@ -276,6 +379,7 @@ func (n *nomadFSM) applyUpsertNode(buf []byte, index uint64) interface{} {
 		n.logger.Printf("[ERR] nomad.fsm: UpsertNode failed: %v", err)
 		return err
 	}
+	n.addEvent(stream.NodeEvent(structs.TypeNodeRegistration, req.Node.ID, req.Node))
 
 	// Unblock evals for the nodes computed node class if it is in a ready
 	// state.
@@ -297,6 +401,7 @@ func (n *nomadFSM) applyDeregisterNode(buf []byte, index uint64) interface{} {
 		n.logger.Printf("[ERR] nomad.fsm: DeleteNode failed: %v", err)
 		return err
 	}
+	n.addEvent(stream.NodeEvent(structs.TypeNodeDeregistration, req.NodeID, nil))
 	return nil
 }
 
@@ -419,6 +524,18 @@ func (n *nomadFSM) applyUpsertJob(buf []byte, index uint64) interface{} {
 		n.logger.Printf("[ERR] nomad.fsm: UpsertJob failed: %v", err)
 		return err
 	}
+	n.addEvent(stream.JobEvent(structs.TypeJobRegistered, req.Job.Namespace, req.Job.ID, req.Job))
+
+	// Store the source the job was submitted from with its new version
+	if req.Submission != nil {
+		sub := req.Submission.Copy()
+		sub.Namespace = req.Job.Namespace
+		sub.JobID = req.Job.ID
+		if err := n.state.UpsertJobSubmission(index, sub); err != nil {
+			n.logger.Printf("[ERR] nomad.fsm: UpsertJobSubmission failed: %v", err)
+			return err
+		}
+	}
 
 	// We always add the job to the periodic dispatcher because there is the
 	// possibility that the periodic spec was removed and then we should stop
@@ -542,6 +659,7 @@ func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, pu
 		// the job was updated to be non-periodic, thus checking if it is periodic
 		// doesn't ensure we clean it up properly.
 		n.state.DeletePeriodicLaunch(index, namespace, jobID)
+		n.addEvent(stream.JobEvent(structs.TypeJobDeregistered, namespace, jobID, nil))
 	} else {
 		// Get the current job and mark it as stopped and re-insert it.
 		ws := memdb.NewWatchSet()
@@ -562,6 +680,7 @@ func (n *nomadFSM) handleJobDeregister(index uint64, jobID, namespace string, pu
 			n.logger.Printf("[ERR] nomad.fsm: UpsertJob failed: %v", err)
 			return err
 		}
+		n.addEvent(stream.JobEvent(structs.TypeJobDeregistered, namespace, jobID, stopped))
 	}
 
 	return nil
@@ -599,6 +718,7 @@ func (n *nomadFSM) handleUpsertedEval(eval *structs.Evaluation) {
 	if eval == nil {
 		return
 	}
+	n.addEvent(stream.EvalEvent(structs.TypeEvalUpdated, eval))
 
 	if eval.ShouldEnqueue() {
 		n.evalBroker.Enqueue(eval)
@@ -696,6 +816,7 @@ func (n *nomadFSM) applyAllocClientUpdate(buf []byte, index uint64) interface{}
 		n.logger.Printf("[ERR] nomad.fsm: UpdateAllocFromClient failed: %v", err)
 		return err
 	}
+	n.addAllocEvents(req.Alloc)
 
 	// Update any evals
 	if len(req.Evals) > 0 {
@@ -732,6 +853,27 @@ func (n *nomadFSM) applyAllocClientUpdate(buf []byte, index uint64) interface{}
 	return nil
 }
 
+// allocQuota returns the quota attached to the namespace of the allocation,
+// or an empty string if there is none.
+func (n *nomadFSM) allocQuota(allocID string) (string, error) {
+	alloc, err := n.state.AllocByID(nil, allocID)
+	if err != nil {
+		return "", err
+	}
+	if alloc == nil {
+		return "", nil
+	}
+
+	ns, err := n.state.NamespaceByName(nil, alloc.Namespace)
+	if err != nil {
+		return "", err
+	}
+	if ns == nil {
+		return "", nil
+	}
+	return ns.Quota, nil
+}
+
 // applyAllocUpdateDesiredTransition is used to update the desired transitions
 // of a set of allocations.
 func (n *nomadFSM) applyAllocUpdateDesiredTransition(buf []byte, index uint64) interface{} {
@@ -820,7 +962,16 @@ func (n *nomadFSM) applyPlanResults(buf []byte, index uint64) interface{} {
 		n.logger.Printf("[ERR] nomad.fsm: ApplyPlan failed: %v", err)
 		return err
 	}
+	n.addAllocEvents(req.Alloc)
+	if req.Deployment != nil {
+		n.addDeploymentEvent(structs.TypeDeploymentUpdate, req.Deployment.ID)
+	}
+	for _, u := range req.DeploymentUpdates {
+		n.addDeploymentEvent(structs.TypeDeploymentUpdate, u.DeploymentID)
+	}
 
+	// Enqueue the evals of the jobs with preempted allocations
+	n.handleUpsertedEvals(req.PreemptionEvals)
 	return nil
 }
 
@@ -837,6 +988,7 @@ func (n *nomadFSM) applyDeploymentStatusUpdate(buf []byte, index uint64) interfa
 		n.logger.Printf("[ERR] nomad.fsm: UpsertDeploymentStatusUpdate failed: %v", err)
 		return err
 	}
+	n.addDeploymentEvent(structs.TypeDeploymentUpdate, req.DeploymentUpdate.DeploymentID)
 
 	n.handleUpsertedEval(req.Eval)
 	return nil
@@ -854,6 +1006,7 @@ func (n *nomadFSM) applyDeploymentPromotion(buf []byte, index uint64) interface{
 		n.logger.Printf("[ERR] nomad.fsm: UpsertDeploymentPromotion failed: %v", err)
 		return err
 	}
+	n.addDeploymentEvent(structs.TypeDeploymentPromotion, req.DeploymentID)
 
 	n.handleUpsertedEval(req.Eval)
 	return nil
@@ -909,6 +1062,72 @@ func (n *nomadFSM) applyJobStability(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyNamespaceUpsert is used to upsert a set of namespaces
+func (n *nomadFSM) applyNamespaceUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_upsert"}, time.Now())
+	var req structs.NamespaceUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertNamespaces(index, req.Namespaces); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertNamespaces failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyNamespaceDelete is used to delete a set of namespaces
+func (n *nomadFSM) applyNamespaceDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_namespace_delete"}, time.Now())
+	var req structs.NamespaceDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteNamespaces(index, req.Namespaces); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteNamespaces failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyQuotaSpecUpsert is used to upsert a set of quota specifications
+func (n *nomadFSM) applyQuotaSpecUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_quota_spec_upsert"}, time.Now())
+	var req structs.QuotaSpecUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertQuotaSpecs(index, req.Quotas); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertQuotaSpecs failed: %v", err)
+		return err
+	}
+
+	// Unblock the evaluations blocked on the quotas since their limits may
+	// have been raised
+	for _, q := range req.Quotas {
+		n.blockedEvals.UnblockQuota(q.Name, index)
+	}
+	return nil
+}
+
+// applyQuotaSpecDelete is used to delete a set of quota specifications
+func (n *nomadFSM) applyQuotaSpecDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_quota_spec_delete"}, time.Now())
+	var req structs.QuotaSpecDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteQuotaSpecs(index, req.Names); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteQuotaSpecs failed: %v", err)
+		return err
+	}
+	return nil
+}
+
 // applyACLPolicyUpsert is used to upsert a set of policies
 func (n *nomadFSM) applyACLPolicyUpsert(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_policy_upsert"}, time.Now())
@@ -939,6 +1158,173 @@ func (n *nomadFSM) applyACLPolicyDelete(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyACLRoleUpsert is used to upsert a set of roles
+func (n *nomadFSM) applyACLRoleUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_upsert"}, time.Now())
+	var req structs.ACLRoleUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLRoles(index, req.Roles); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertACLRoles failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLRoleDelete is used to delete a set of roles
+func (n *nomadFSM) applyACLRoleDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_role_delete"}, time.Now())
+	var req structs.ACLRoleDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLRoles(index, req.Names); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteACLRoles failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLAuthMethodUpsert is used to upsert a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_upsert"}, time.Now())
+	var req structs.ACLAuthMethodUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLAuthMethods(index, req.AuthMethods); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertACLAuthMethods failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLAuthMethodDelete is used to delete a set of auth methods
+func (n *nomadFSM) applyACLAuthMethodDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_auth_method_delete"}, time.Now())
+	var req structs.ACLAuthMethodDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLAuthMethods(index, req.Names); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteACLAuthMethods failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLBindingRuleUpsert is used to upsert a set of binding rules
+func (n *nomadFSM) applyACLBindingRuleUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_upsert"}, time.Now())
+	var req structs.ACLBindingRuleUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertACLBindingRules(index, req.BindingRules); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertACLBindingRules failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyACLBindingRuleDelete is used to delete a set of binding rules
+func (n *nomadFSM) applyACLBindingRuleDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_binding_rule_delete"}, time.Now())
+	var req structs.ACLBindingRuleDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteACLBindingRules(index, req.IDs); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteACLBindingRules failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyRootKeyUpsert is used to upsert the metadata of a root key
+func (n *nomadFSM) applyRootKeyUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_upsert"}, time.Now())
+	var req structs.RootKeyUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertRootKeyMeta(index, req.RootKeyMeta); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertRootKeyMeta failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyVariableUpsert is used to upsert an encrypted variable
+func (n *nomadFSM) applyVariableUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_upsert"}, time.Now())
+	var req structs.VariableUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertVariable(index, req.Var, req.CheckIndex); err != nil {
+		if err != structs.ErrVariableConflict {
+			n.logger.Printf("[ERR] nomad.fsm: UpsertVariable failed: %v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// applyVariableDelete is used to delete a variable
+func (n *nomadFSM) applyVariableDelete(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_delete"}, time.Now())
+	var req structs.VariablesDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteVariable(index, req.RequestNamespace(), req.Path); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteVariable failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyServiceRegistrationUpsert is used to upsert service instances
+func (n *nomadFSM) applyServiceRegistrationUpsert(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_upsert"}, time.Now())
+	var req structs.ServiceRegistrationUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertServiceRegistrations(index, req.Services); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: UpsertServiceRegistrations failed: %v", err)
+		return err
+	}
+	return nil
+}
+
+// applyServiceRegistrationDeleteByID is used to delete a service instance
+func (n *nomadFSM) applyServiceRegistrationDeleteByID(buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_service_registration_delete_id"}, time.Now())
+	var req structs.ServiceRegistrationDeleteByIDRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteServiceRegistrationByID(index, req.RequestNamespace(), req.ID); err != nil {
+		n.logger.Printf("[ERR] nomad.fsm: DeleteServiceRegistrationByID failed: %v", err)
+		return err
+	}
+	return nil
+}
+
 // applyACLTokenUpsert is used to upsert a set of policies
 func (n *nomadFSM) applyACLTokenUpsert(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_upsert"}, time.Now())
@@ -1001,6 +1387,23 @@ func (n *nomadFSM) applyAutopilotUpdate(buf []byte, index uint64) interface{} {
 	return n.state.AutopilotSetConfig(index, &req.Config)
 }
 
+func (n *nomadFSM) applySchedulerConfigUpdate(buf []byte, index uint64) interface{} {
+	var req structs.SchedulerSetConfigRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_scheduler_config"}, time.Now())
+
+	if req.CAS {
+		act, err := n.state.SchedulerCASConfig(index, req.Config.ModifyIndex, &req.Config)
+		if err != nil {
+			return err
+		}
+		return act
+	}
+	return n.state.SchedulerSetConfig(index, &req.Config)
+}
+
 func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
 	// Create a new snapshot
 	snap, err := n.state.Snapshot()
@@ -1203,6 +1606,33 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case NamespaceSnapshot:
+			namespace := new(structs.Namespace)
+			if err := dec.Decode(namespace); err != nil {
+				return err
+			}
+			if err := restore.NamespaceRestore(namespace); err != nil {
+				return err
+			}
+
+		case QuotaSpecSnapshot:
+			spec := new(structs.QuotaSpec)
+			if err := dec.Decode(spec); err != nil {
+				return err
+			}
+			if err := restore.QuotaSpecRestore(spec); err != nil {
+				return err
+			}
+
+		case QuotaUsageSnapshot:
+			usage := new(structs.QuotaUsage)
+			if err := dec.Decode(usage); err != nil {
+				return err
+			}
+			if err := restore.QuotaUsageRestore(usage); err != nil {
+				return err
+			}
+
 		case ACLPolicySnapshot:
 			policy := new(structs.ACLPolicy)
 			if err := dec.Decode(policy); err != nil {
@@ -1221,6 +1651,78 @@ func (n *nomadFSM) Restore(old io.ReadCloser) error {
 				return err
 			}
 
+		case SchedulerConfigSnapshot:
+			config := new(structs.SchedulerConfiguration)
+			if err := dec.Decode(config); err != nil {
+				return err
+			}
+			if err := restore.SchedulerConfigRestore(config); err != nil {
+				return err
+			}
+
+		case ACLRoleSnapshot:
+			role := new(structs.ACLRole)
+			if err := dec.Decode(role); err != nil {
+				return err
+			}
+			if err := restore.ACLRoleRestore(role); err != nil {
+				return err
+			}
+
+		case ACLAuthMethodSnapshot:
+			method := new(structs.ACLAuthMethod)
+			if err := dec.Decode(method); err != nil {
+				return err
+			}
+			if err := restore.ACLAuthMethodRestore(method); err != nil {
+				return err
+			}
+
+		case ACLBindingRuleSnapshot:
+			rule := new(structs.ACLBindingRule)
+			if err := dec.Decode(rule); err != nil {
+				return err
+			}
+			if err := restore.ACLBindingRuleRestore(rule); err != nil {
+				return err
+			}
+
+		case RootKeySnapshot:
+			key := new(structs.RootKeyMeta)
+			if err := dec.Decode(key); err != nil {
+				return err
+			}
+			if err := restore.RootKeyMetaRestore(key); err != nil {
+				return err
+			}
+
+		case VariableSnapshot:
+			v := new(structs.VariableEncrypted)
+			if err := dec.Decode(v); err != nil {
+				return err
+			}
+			if err := restore.VariableRestore(v); err != nil {
+				return err
+			}
+
+		case ServiceRegistrationSnapshot:
+			service := new(structs.ServiceRegistration)
+			if err := dec.Decode(service); err != nil {
+				return err
+			}
+			if err := restore.ServiceRegistrationRestore(service); err != nil {
+				return err
+			}
+
+		case JobSubmissionSnapshot:
+			sub := new(structs.JobSubmission)
+			if err := dec.Decode(sub); err != nil {
+				return err
+			}
+			if err := restore.JobSubmissionRestore(sub); err != nil {
+				return err
+			}
+
 		default:
 			// Check if this is an enterprise only object being restored
 			restorer, ok := n.enterpriseRestorers[snapType]
@@ -1496,14 +1998,58 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistNamespaces(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistQuotaSpecs(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistQuotaUsages(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistACLPolicies(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistACLRoles(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLAuthMethods(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistACLBindingRules(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistRootKeys(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistVariables(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistServiceRegistrations(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
+	if err := s.persistJobSubmissions(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistACLTokens(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
 	}
+	if err := s.persistSchedulerConfig(sink, encoder); err != nil {
+		sink.Cancel()
+		return err
+	}
 	if err := s.persistEnterpriseTables(sink, encoder); err != nil {
 		sink.Cancel()
 		return err
@@ -1784,6 +2330,90 @@ func (s *nomadSnapshot) persistDeployments(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistNamespaces(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the namespaces
+	ws := memdb.NewWatchSet()
+	namespaces, err := s.snap.Namespaces(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := namespaces.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		namespace := raw.(*structs.Namespace)
+
+		// Write out a namespace registration
+		sink.Write([]byte{byte(NamespaceSnapshot)})
+		if err := encoder.Encode(namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistQuotaSpecs(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the quota specifications
+	ws := memdb.NewWatchSet()
+	specs, err := s.snap.QuotaSpecs(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := specs.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		spec := raw.(*structs.QuotaSpec)
+
+		// Write out a quota specification registration
+		sink.Write([]byte{byte(QuotaSpecSnapshot)})
+		if err := encoder.Encode(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistQuotaUsages(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the quota usages
+	ws := memdb.NewWatchSet()
+	usages, err := s.snap.QuotaUsages(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := usages.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		usage := raw.(*structs.QuotaUsage)
+
+		// Write out a quota usage registration
+		sink.Write([]byte{byte(QuotaUsageSnapshot)})
+		if err := encoder.Encode(usage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistACLPolicies(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the policies
@@ -1812,6 +2442,203 @@ func (s *nomadSnapshot) persistACLPolicies(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistACLRoles(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the roles
+	ws := memdb.NewWatchSet()
+	roles, err := s.snap.ACLRoles(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := roles.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		role := raw.(*structs.ACLRole)
+
+		// Write out a role registration
+		sink.Write([]byte{byte(ACLRoleSnapshot)})
+		if err := encoder.Encode(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLAuthMethods(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the auth methods
+	ws := memdb.NewWatchSet()
+	methods, err := s.snap.ACLAuthMethods(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := methods.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		method := raw.(*structs.ACLAuthMethod)
+
+		// Write out an auth method registration
+		sink.Write([]byte{byte(ACLAuthMethodSnapshot)})
+		if err := encoder.Encode(method); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistACLBindingRules(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the binding rules
+	ws := memdb.NewWatchSet()
+	rules, err := s.snap.ACLBindingRules(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := rules.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		rule := raw.(*structs.ACLBindingRule)
+
+		// Write out a binding rule registration
+		sink.Write([]byte{byte(ACLBindingRuleSnapshot)})
+		if err := encoder.Encode(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistRootKeys(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get the metadata of all the root keys. Their key material is kept in
+	// the keystore of each server rather than in snapshots.
+	ws := memdb.NewWatchSet()
+	keys, err := s.snap.RootKeyMetas(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := keys.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		key := raw.(*structs.RootKeyMeta)
+
+		// Write out a root key registration
+		sink.Write([]byte{byte(RootKeySnapshot)})
+		if err := encoder.Encode(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the variables
+	ws := memdb.NewWatchSet()
+	vars, err := s.snap.Variables(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := vars.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		v := raw.(*structs.VariableEncrypted)
+
+		// Write out a variable registration
+		sink.Write([]byte{byte(VariableSnapshot)})
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistServiceRegistrations(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the service instances
+	ws := memdb.NewWatchSet()
+	services, err := s.snap.GetServiceRegistrations(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := services.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		service := raw.(*structs.ServiceRegistration)
+
+		// Write out a service registration
+		sink.Write([]byte{byte(ServiceRegistrationSnapshot)})
+		if err := encoder.Encode(service); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistJobSubmissions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all the job sources
+	ws := memdb.NewWatchSet()
+	subs, err := s.snap.JobSubmissions(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Get the next item
+		raw := subs.Next()
+		if raw == nil {
+			break
+		}
+
+		// Prepare the request struct
+		sub := raw.(*structs.JobSubmission)
+
+		// Write out a job submission
+		sink.Write([]byte{byte(JobSubmissionSnapshot)})
+		if err := encoder.Encode(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistACLTokens(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the policies
@@ -1840,6 +2667,25 @@ func (s *nomadSnapshot) persistACLTokens(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistSchedulerConfig(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get the scheduler config
+	_, config, err := s.snap.SchedulerConfig()
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return nil
+	}
+
+	// Write out the scheduler config
+	sink.Write([]byte{byte(SchedulerConfigSnapshot)})
+	if err := encoder.Encode(config); err != nil {
+		return err
+	}
+	return nil
+}
+
 // Release is a no-op, as we just need to GC the pointer
 // to the state store snapshot. There is nothing to explicitly
 // cleanup.
@@ -2,6 +2,7 @@ package nomad
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"reflect"
@@ -15,6 +16,7 @@ import (
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
@@ -557,6 +559,36 @@ func TestFSM_UpdateNodeEligibility_Unblock(t *testing.T) {
 	})
 }
 
+func TestFSM_RegisterJob_Submission(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+
+	job := mock.Job()
+	req := structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source: `job "example" {}`,
+			Format: structs.JobSubmissionFormatHCL2,
+		},
+		WriteRequest: structs.WriteRequest{
+			Namespace: job.Namespace,
+		},
+	}
+	buf, err := structs.Encode(structs.JobRegisterRequestType, req)
+	require.NoError(t, err)
+
+	resp := fsm.Apply(makeLog(buf))
+	require.Nil(t, resp)
+
+	// Verify the source is stored with the job version
+	out, err := fsm.State().JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	require.Equal(t, req.Submission.Source, out.Source)
+	require.Equal(t, job.ID, out.JobID)
+	require.Equal(t, uint64(1), out.CreateIndex)
+}
+
 func TestFSM_RegisterJob(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2137,6 +2169,240 @@ func TestFSM_DeleteACLPolicies(t *testing.T) {
 	assert.Nil(t, out)
 }
 
+func TestFSM_UpsertNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	req := structs.NamespaceUpsertRequest{
+		Namespaces: []*structs.Namespace{ns1, ns2},
+	}
+	buf, err := structs.Encode(structs.NamespaceUpsertRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().NamespaceByName(ws, ns1.Name)
+	require.NoError(err)
+	require.NotNil(out)
+
+	out, err = fsm.State().NamespaceByName(ws, ns2.Name)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
+func TestFSM_DeleteNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	require.NoError(fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+
+	req := structs.NamespaceDeleteRequest{
+		Namespaces: []string{ns1.Name, ns2.Name},
+	}
+	buf, err := structs.Encode(structs.NamespaceDeleteRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are NOT registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().NamespaceByName(ws, ns1.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	out, err = fsm.State().NamespaceByName(ws, ns2.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestFSM_UpsertQuotaSpecs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+	fsm.blockedEvals.SetEnabled(true)
+
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+
+	// Mark an eval as blocked on the quota
+	eval := mock.Eval()
+	eval.QuotaLimitReached = qs1.Name
+	fsm.blockedEvals.Block(eval)
+	require.Equal(1, fsm.blockedEvals.Stats().TotalBlocked)
+
+	req := structs.QuotaSpecUpsertRequest{
+		Quotas: []*structs.QuotaSpec{qs1, qs2},
+	}
+	buf, err := structs.Encode(structs.QuotaSpecUpsertRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().QuotaSpecByName(ws, qs1.Name)
+	require.NoError(err)
+	require.NotNil(out)
+
+	out, err = fsm.State().QuotaSpecByName(ws, qs2.Name)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// Verify the eval was unblocked
+	testutil.WaitForResult(func() (bool, error) {
+		bStats := fsm.blockedEvals.Stats()
+		if bStats.TotalBlocked != 0 {
+			return false, fmt.Errorf("bad: %#v", bStats)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %s", err)
+	})
+}
+
+func TestFSM_DeleteQuotaSpecs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	require.NoError(fsm.State().UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs1, qs2}))
+
+	req := structs.QuotaSpecDeleteRequest{
+		Names: []string{qs1.Name, qs2.Name},
+	}
+	buf, err := structs.Encode(structs.QuotaSpecDeleteRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are NOT registered
+	ws := memdb.NewWatchSet()
+	out, err := fsm.State().QuotaSpecByName(ws, qs1.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	out, err = fsm.State().QuotaSpecByName(ws, qs2.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestFSM_UpsertDeleteACLRoles(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	role := mock.ACLRole()
+	req := structs.ACLRoleUpsertRequest{
+		Roles: []*structs.ACLRole{role},
+	}
+	buf, err := structs.Encode(structs.ACLRoleUpsertRequestType, req)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are registered
+	out, err := fsm.State().ACLRoleByName(nil, role.Name)
+	require.NoError(err)
+	require.NotNil(out)
+
+	del := structs.ACLRoleDeleteRequest{
+		Names: []string{role.Name},
+	}
+	buf, err = structs.Encode(structs.ACLRoleDeleteRequestType, del)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are NOT registered
+	out, err = fsm.State().ACLRoleByName(nil, role.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestFSM_UpsertDeleteServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	services := mock.ServiceRegistrations()
+	buf, err := structs.Encode(structs.ServiceRegistrationUpsertRequestType, structs.ServiceRegistrationUpsertRequest{
+		Services: services,
+	})
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are registered
+	out, err := fsm.State().GetServiceRegistrationByID(nil, services[0].Namespace, services[0].ID)
+	require.NoError(err)
+	require.NotNil(out)
+
+	del := structs.ServiceRegistrationDeleteByIDRequest{
+		ID:           services[0].ID,
+		WriteRequest: structs.WriteRequest{Namespace: services[0].Namespace},
+	}
+	buf, err = structs.Encode(structs.ServiceRegistrationDeleteByIDRequestType, del)
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are NOT registered
+	out, err = fsm.State().GetServiceRegistrationByID(nil, services[0].Namespace, services[0].ID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestFSM_UpsertDeleteACLAuthMethodsAndBindingRules(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+
+	method := mock.ACLAuthMethod()
+	buf, err := structs.Encode(structs.ACLAuthMethodUpsertRequestType, structs.ACLAuthMethodUpsertRequest{
+		AuthMethods: []*structs.ACLAuthMethod{method},
+	})
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	buf, err = structs.Encode(structs.ACLBindingRuleUpsertRequestType, structs.ACLBindingRuleUpsertRequest{
+		BindingRules: []*structs.ACLBindingRule{rule},
+	})
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are registered
+	outMethod, err := fsm.State().ACLAuthMethodByName(nil, method.Name)
+	require.NoError(err)
+	require.NotNil(outMethod)
+	outRule, err := fsm.State().ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.NotNil(outRule)
+
+	buf, err = structs.Encode(structs.ACLBindingRuleDeleteRequestType, structs.ACLBindingRuleDeleteRequest{
+		IDs: []string{rule.ID},
+	})
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	buf, err = structs.Encode(structs.ACLAuthMethodDeleteRequestType, structs.ACLAuthMethodDeleteRequest{
+		Names: []string{method.Name},
+	})
+	require.NoError(err)
+	require.Nil(fsm.Apply(makeLog(buf)))
+
+	// Verify we are NOT registered
+	outMethod, err = fsm.State().ACLAuthMethodByName(nil, method.Name)
+	require.NoError(err)
+	require.Nil(outMethod)
+	outRule, err = fsm.State().ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Nil(outRule)
+}
+
 func TestFSM_BootstrapACLTokens(t *testing.T) {
 	t.Parallel()
 	fsm := testFSM(t)
@@ -2593,6 +2859,52 @@ func TestFSM_SnapshotRestore_Deployments(t *testing.T) {
 	}
 }
 
+func TestFSM_SnapshotRestore_Namespaces(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.NamespaceByName(ws, ns1.Name)
+	out2, _ := state2.NamespaceByName(ws, ns2.Name)
+	assert.Equal(t, ns1, out1)
+	assert.Equal(t, ns2, out2)
+
+	// The default namespace is still present
+	out3, _ := state2.NamespaceByName(ws, structs.DefaultNamespace)
+	assert.NotNil(t, out3)
+}
+
+func TestFSM_SnapshotRestore_Quotas(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs1, qs2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	ws := memdb.NewWatchSet()
+	out1, _ := state2.QuotaSpecByName(ws, qs1.Name)
+	out2, _ := state2.QuotaSpecByName(ws, qs2.Name)
+	assert.Equal(t, qs1, out1)
+	assert.Equal(t, qs2, out2)
+
+	usage1, _ := state.QuotaUsageByName(ws, qs1.Name)
+	usage2, _ := state2.QuotaUsageByName(ws, qs1.Name)
+	assert.Equal(t, usage1, usage2)
+}
+
 func TestFSM_SnapshotRestore_ACLPolicy(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -2612,6 +2924,44 @@ func TestFSM_SnapshotRestore_ACLPolicy(t *testing.T) {
 	assert.Equal(t, p2, out2)
 }
 
+func TestFSM_SnapshotRestore_ACLRole(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	state.UpsertACLRoles(1000, []*structs.ACLRole{r1, r2})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out1, _ := state2.ACLRoleByName(nil, r1.Name)
+	out2, _ := state2.ACLRoleByName(nil, r2.Name)
+	assert.Equal(t, r1, out1)
+	assert.Equal(t, r2, out2)
+}
+
+func TestFSM_SnapshotRestore_ACLAuthMethodsAndBindingRules(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	m1 := mock.ACLAuthMethod()
+	r1 := mock.ACLBindingRule()
+	r1.AuthMethod = m1.Name
+	state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{m1})
+	state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{r1})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	outMethod, _ := state2.ACLAuthMethodByName(nil, m1.Name)
+	outRule, _ := state2.ACLBindingRuleByID(nil, r1.ID)
+	assert.Equal(t, m1, outMethod)
+	assert.Equal(t, r1, outRule)
+}
+
 func TestFSM_SnapshotRestore_ACLTokens(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -2631,6 +2981,110 @@ func TestFSM_SnapshotRestore_ACLTokens(t *testing.T) {
 	assert.Equal(t, tk2, out2)
 }
 
+func TestFSM_SnapshotRestore_RootKeys(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	key1 := mock.RootKey().Meta
+	key2 := mock.RootKey().Meta
+	state.UpsertRootKeyMeta(1000, key1)
+	state.UpsertRootKeyMeta(1001, key2)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out1, _ := state2.RootKeyMetaByID(nil, key1.KeyID)
+	out2, _ := state2.ActiveRootKeyMeta(nil)
+	require.False(t, out1.Active)
+	require.Equal(t, key1.PublicKey, out1.PublicKey)
+	require.Equal(t, key2, out2)
+}
+
+func TestFSM_SnapshotRestore_Variables(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	sv := &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      "app/web",
+		},
+		Data:  []byte("encrypted"),
+		KeyID: "key",
+	}
+	state.UpsertVariable(1000, sv, 0)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, _ := state2.GetVariable(nil, sv.Namespace, sv.Path)
+	require.Equal(t, sv, out)
+}
+
+func TestFSM_SnapshotRestore_ServiceRegistrations(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	services := mock.ServiceRegistrations()
+	state.UpsertServiceRegistrations(1000, services)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	for _, service := range services {
+		out, _ := state2.GetServiceRegistrationByID(nil, service.Namespace, service.ID)
+		require.Equal(t, service, out)
+	}
+}
+
+func TestFSM_SnapshotRestore_JobSubmissions(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	job := mock.Job()
+	require.NoError(t, state.UpsertJob(1000, job))
+	sub := &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+	}
+	require.NoError(t, state.UpsertJobSubmission(1001, sub))
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, err := state2.JobSubmission(nil, job.Namespace, job.ID, job.Version)
+	require.NoError(t, err)
+	require.Equal(t, sub, out)
+}
+
+func TestFSM_SnapshotRestore_SchedulerConfiguration(t *testing.T) {
+	t.Parallel()
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	schedConfig := &structs.SchedulerConfiguration{
+		PreemptionConfig: structs.PreemptionConfig{
+			SystemSchedulerEnabled: false,
+			BatchSchedulerEnabled:  true,
+		},
+	}
+	state.SchedulerSetConfig(1000, schedConfig)
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	index, out, err := state2.SchedulerConfig()
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, index)
+	require.Equal(t, schedConfig, out)
+}
+
 func TestFSM_SnapshotRestore_AddMissingSummary(t *testing.T) {
 	t.Parallel()
 	// Add some state
@@ -2822,3 +3276,118 @@ func TestFSM_Autopilot(t *testing.T) {
 		t.Fatalf("bad: %v", config.CleanupDeadServers)
 	}
 }
+
+func TestFSM_SchedulerConfig(t *testing.T) {
+	t.Parallel()
+	fsm := testFSM(t)
+	require := require.New(t)
+
+	// Set the scheduler config using a request.
+	req := structs.SchedulerSetConfigRequest{
+		Config: structs.SchedulerConfiguration{
+			PreemptionConfig: structs.PreemptionConfig{
+				SystemSchedulerEnabled: true,
+				BatchSchedulerEnabled:  true,
+			},
+		},
+	}
+	buf, err := structs.Encode(structs.SchedulerConfigRequestType, req)
+	require.Nil(err)
+
+	resp := fsm.Apply(makeLog(buf))
+	if _, ok := resp.(error); ok {
+		t.Fatalf("bad: %v", resp)
+	}
+
+	// Verify key is set directly in the state store.
+	_, config, err := fsm.state.SchedulerConfig()
+	require.Nil(err)
+	require.Equal(config.PreemptionConfig.SystemSchedulerEnabled, req.Config.PreemptionConfig.SystemSchedulerEnabled)
+	require.Equal(config.PreemptionConfig.BatchSchedulerEnabled, req.Config.PreemptionConfig.BatchSchedulerEnabled)
+
+	// Now use CAS and provide an old index
+	req.CAS = true
+	req.Config.PreemptionConfig = structs.PreemptionConfig{SystemSchedulerEnabled: false}
+	req.Config.ModifyIndex = config.ModifyIndex - 1
+	buf, err = structs.Encode(structs.SchedulerConfigRequestType, req)
+	require.Nil(err)
+
+	resp = fsm.Apply(makeLog(buf))
+	if _, ok := resp.(error); ok {
+		t.Fatalf("bad: %v", resp)
+	}
+
+	_, config, err = fsm.state.SchedulerConfig()
+	require.Nil(err)
+	// Verify that preemption is still enabled
+	require.True(config.PreemptionConfig.SystemSchedulerEnabled)
+}
+
+func TestFSM_Events(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	fsm := testFSM(t)
+	fsm.eventBroker = stream.NewEventBroker(100)
+	sub := fsm.eventBroker.Subscribe(&stream.SubscribeRequest{Namespace: structs.DefaultNamespace})
+
+	apply := func(index uint64, msgType structs.MessageType, req interface{}) {
+		buf, err := structs.Encode(msgType, req)
+		require.NoError(err)
+		require.Nil(fsm.Apply(&raft.Log{Index: index, Term: 1, Type: raft.LogCommand, Data: buf}))
+	}
+	next := func() *structs.Events {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		events, err := sub.Next(ctx)
+		require.NoError(err)
+		return events
+	}
+
+	node := mock.Node()
+	apply(10, structs.NodeRegisterRequestType, structs.NodeRegisterRequest{Node: node})
+	events := next()
+	require.Equal(uint64(10), events.Index)
+	require.Len(events.Events, 1)
+	require.Equal(structs.TopicNode, events.Events[0].Topic)
+	require.Equal(structs.TypeNodeRegistration, events.Events[0].Type)
+	require.Equal(node.ID, events.Events[0].Key)
+	require.Equal(uint64(10), events.Events[0].Index)
+	require.Empty(events.Events[0].Payload.Node.SecretID)
+
+	job := mock.Job()
+	apply(11, structs.JobRegisterRequestType, structs.JobRegisterRequest{
+		Job:          job,
+		WriteRequest: structs.WriteRequest{Namespace: job.Namespace},
+	})
+	events = next()
+	require.Equal(structs.TypeJobRegistered, events.Events[0].Type)
+	require.Equal(job.ID, events.Events[0].Key)
+	require.Equal(job.Namespace, events.Events[0].Namespace)
+	require.Equal(uint64(11), events.Events[0].Payload.Job.ModifyIndex)
+
+	// The events of a log are published together
+	eval := mock.Eval()
+	eval.JobID = job.ID
+	alloc := mock.Alloc()
+	alloc.JobID = job.ID
+	alloc.Job = job
+	require.NoError(fsm.State().UpsertAllocs(12, []*structs.Allocation{alloc}))
+	update := &structs.Allocation{
+		ID:           alloc.ID,
+		NodeID:       alloc.NodeID,
+		ClientStatus: structs.AllocClientStatusRunning,
+	}
+	apply(13, structs.AllocClientUpdateRequestType, structs.AllocUpdateRequest{
+		Alloc: []*structs.Allocation{update},
+		Evals: []*structs.Evaluation{eval},
+	})
+	events = next()
+	require.Equal(uint64(13), events.Index)
+	require.Len(events.Events, 2)
+	require.Equal(structs.TypeAllocUpdated, events.Events[0].Type)
+	require.Equal(alloc.ID, events.Events[0].Key)
+	require.Equal(structs.AllocClientStatusRunning, events.Events[0].Payload.Allocation.ClientStatus)
+	require.Nil(events.Events[0].Payload.Allocation.Job)
+	require.Equal(structs.TypeEvalUpdated, events.Events[1].Type)
+	require.Equal(eval.ID, events.Events[1].Key)
+}
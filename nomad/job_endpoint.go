@@ -51,6 +51,22 @@ var (
 // Job endpoint is used for job interactions
 type Job struct {
 	srv *Server
+
+	// mutators and validators are the admission controllers applied to the
+	// jobs on registration
+	mutators   []JobMutator
+	validators []JobValidator
+}
+
+// NewJobEndpoints returns the Job endpoint with the admission controllers of
+// the server configuration
+func NewJobEndpoints(s *Server) *Job {
+	mutators, validators := admissionControllers(s)
+	return &Job{
+		srv:        s,
+		mutators:   mutators,
+		validators: validators,
+	}
 }
 
 // Register is used to upsert a job for scheduling
@@ -65,22 +81,8 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return fmt.Errorf("missing job for registration")
 	}
 
-	// Initialize the job fields (sets defaults and any necessary init work).
-	canonicalizeWarnings := args.Job.Canonicalize()
-
-	// Add implicit constraints
-	setImplicitConstraints(args.Job)
-
-	// Validate the job and capture any warnings
-	err, warnings := validateJob(args.Job)
-	if err != nil {
-		return err
-	}
-
-	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
-
-	// Check job submission permissions
+	// Check job submission permissions before the job is passed to the
+	// admission controllers
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
 	} else if aclObj != nil {
@@ -97,6 +99,34 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		}
 	}
 
+	// Initialize the job fields (sets defaults and any necessary init work).
+	canonicalizeWarnings := args.Job.Canonicalize()
+
+	// Run the admission controllers, which add the implicit constraints and
+	// validate the job
+	job, warnings, err := j.admissionControllers(args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
+
+	// Register the job without its source if the source can't be stored
+	if args.Submission != nil {
+		if err := args.Submission.Validate(); err != nil {
+			warnings = append(warnings, fmt.Errorf("Job source not stored: %v", err))
+			args.Submission = nil
+		}
+	}
+
+	// Set the warning message
+	reply.Warnings = structs.MergeMultierrorWarnings(append(warnings, canonicalizeWarnings)...)
+
+	// A multiregion job is registered in each of its regions by the region
+	// it is submitted to
+	if args.Job.IsMultiregion() && !args.MultiregionFanout {
+		return j.multiregionRegister(args, reply)
+	}
+
 	// Lookup the job
 	snap, err := j.srv.State().Snapshot()
 	if err != nil {
@@ -108,6 +138,13 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Jobs can only be registered in existing namespaces
+	if ns, err := snap.NamespaceByName(ws, args.Job.Namespace); err != nil {
+		return err
+	} else if ns == nil {
+		return fmt.Errorf("job %q is in nonexistent namespace %q", args.Job.ID, args.Job.Namespace)
+	}
+
 	// If EnforceIndex set, check it before trying to apply
 	if args.EnforceIndex {
 		jmi := args.JobModifyIndex
@@ -171,13 +208,19 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 	if policyWarnings != nil {
-		reply.Warnings = structs.MergeMultierrorWarnings(warnings,
-			canonicalizeWarnings, policyWarnings)
+		reply.Warnings = structs.MergeMultierrorWarnings(append(warnings,
+			canonicalizeWarnings, policyWarnings)...)
 	}
 
 	// Clear the Vault token
 	args.Job.VaultToken = ""
 
+	// The region registering a multiregion job only checks that the job can
+	// be registered before registering it in any of its regions
+	if args.MultiregionCheck {
+		return nil
+	}
+
 	// Check if the job has changed at all
 	if existingJob == nil || existingJob.SpecChanged(args.Job) {
 		// Set the submit time
@@ -237,6 +280,84 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	return nil
 }
 
+// multiregionRegister registers a multiregion job in each of its regions. The
+// job is checked in all the regions before being registered in any of them.
+// If the job is deployed to this region, the reply is the one of the
+// registration in this region. The results of each region are returned in the
+// reply's region results.
+func (j *Job) multiregionRegister(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error {
+	// Check that all the regions are known before registering the job in any
+	// of them
+	regions := j.srv.Regions()
+	for _, region := range args.Job.Multiregion.Regions {
+		if !lib.StrContains(regions, region.Name) {
+			return fmt.Errorf("Multiregion job region %q is unknown", region.Name)
+		}
+	}
+
+	// Check that the job can be registered in all the regions, so that a
+	// region rejecting the job doesn't leave it registered in the others
+	var mErr multierror.Error
+	for _, region := range args.Job.Multiregion.Regions {
+		req := j.multiregionRegisterRequest(args, region)
+		req.MultiregionCheck = true
+
+		var resp structs.JobRegisterResponse
+		if err := j.srv.RPC("Job.Register", req, &resp); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("job can't be registered in region %q: %v", region.Name, err))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	reply.RegionResults = make(map[string]*structs.JobRegisterResponse, len(args.Job.Multiregion.Regions))
+	for _, region := range args.Job.Multiregion.Regions {
+		req := j.multiregionRegisterRequest(args, region)
+
+		var resp structs.JobRegisterResponse
+		if err := j.srv.RPC("Job.Register", req, &resp); err != nil {
+			j.srv.logger.Printf("[ERR] nomad.job: failed to register job %q in region %q: %v", args.Job.ID, region.Name, err)
+			multierror.Append(&mErr, fmt.Errorf("failed to register job in region %q: %v", region.Name, err))
+			continue
+		}
+
+		reply.RegionResults[region.Name] = &resp
+		if region.Name == j.srv.Region() {
+			reply.EvalID = resp.EvalID
+			reply.EvalCreateIndex = resp.EvalCreateIndex
+			reply.JobModifyIndex = resp.JobModifyIndex
+			reply.Warnings = resp.Warnings
+			reply.QueryMeta = resp.QueryMeta
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// multiregionRegisterRequest returns the request registering the multiregion
+// job in the region.
+func (j *Job) multiregionRegisterRequest(args *structs.JobRegisterRequest, region *structs.MultiregionRegion) *structs.JobRegisterRequest {
+	req := &structs.JobRegisterRequest{
+		Job:               args.Job.RegionalJob(region),
+		PolicyOverride:    args.PolicyOverride,
+		MultiregionFanout: true,
+		Submission:        args.Submission,
+		WriteRequest: structs.WriteRequest{
+			Region:    region.Name,
+			Namespace: args.RequestNamespace(),
+			AuthToken: args.AuthToken,
+		},
+	}
+
+	// The modify index of the job can only be enforced in this region
+	if region.Name == j.srv.Region() {
+		req.EnforceIndex = args.EnforceIndex
+		req.JobModifyIndex = args.JobModifyIndex
+	}
+	return req
+}
+
 // setImplicitConstraints adds implicit constraints to the job based on the
 // features it is requesting.
 func setImplicitConstraints(j *structs.Job) {
@@ -370,12 +491,12 @@ func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValid
 	// Initialize the job fields (sets defaults and any necessary init work).
 	canonicalizeWarnings := args.Job.Canonicalize()
 
-	// Add implicit constraints
-	setImplicitConstraints(args.Job)
-
-	// Validate the job and capture any warnings
-	err, warnings := validateJob(args.Job)
-	if err != nil {
+	// Run the admission controllers, which add the implicit constraints and
+	// validate the job
+	job, warnings, err := j.admissionControllers(args.Job)
+	if err == nil {
+		args.Job = job
+	} else {
 		if merr, ok := err.(*multierror.Error); ok {
 			for _, err := range merr.Errors {
 				reply.ValidationErrors = append(reply.ValidationErrors, err.Error())
@@ -388,7 +509,9 @@ func (j *Job) Validate(args *structs.JobValidateRequest, reply *structs.JobValid
 	}
 
 	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
+	warnings = append(warnings, canonicalizeWarnings)
+	reply.Warnings = structs.MergeMultierrorWarnings(warnings...)
+	reply.ValidationWarnings = structs.FlattenMultierrorWarnings(warnings...)
 	reply.DriverConfigValidated = true
 	return nil
 }
@@ -860,6 +983,67 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// GetJobSubmission is used to retrieve the source a job version was
+// submitted from
+func (j *Job) GetJobSubmission(args *structs.JobSubmissionRequest,
+	reply *structs.JobSubmissionResponse) error {
+	if done, err := j.srv.forward("Job.GetJobSubmission", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "get_job_submission"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Default to the current version of the job
+			var version uint64
+			if args.Version != nil {
+				version = *args.Version
+			} else {
+				job, err := state.JobByID(ws, args.RequestNamespace(), args.JobID)
+				if err != nil {
+					return err
+				}
+				if job != nil {
+					version = job.Version
+				}
+			}
+
+			// Look for the job source
+			out, err := state.JobSubmission(ws, args.RequestNamespace(), args.JobID, version)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Submission = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the job submission table
+				index, err := state.Index("job_submission")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // List is used to list the jobs registered in the system
 func (j *Job) List(args *structs.JobListRequest,
 	reply *structs.JobListResponse) error {
@@ -868,10 +1052,14 @@ func (j *Job) List(args *structs.JobListRequest,
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "list"}, time.Now())
 
-	// Check for list-job permissions
-	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
+	// Check for list-job permissions. The jobs of all the namespaces are
+	// filtered down to the namespaces the token may list the jobs of.
+	aclObj, err := j.srv.ResolveToken(args.AuthToken)
+	if err != nil {
 		return err
-	} else if aclObj != nil && !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityListJobs) {
+	}
+	allow := allowNsOpFunc(aclObj, acl.NamespaceCapabilityListJobs)
+	if namespace := args.RequestNamespace(); namespace != structs.AllNamespacesSentinel && !allow(namespace) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -899,7 +1087,10 @@ func (j *Job) List(args *structs.JobListRequest,
 					break
 				}
 				job := raw.(*structs.Job)
-				summary, err := state.JobSummaryByID(ws, args.RequestNamespace(), job.ID)
+				if !allow(job.Namespace) {
+					continue
+				}
+				summary, err := state.JobSummaryByID(ws, job.Namespace, job.ID)
 				if err != nil {
 					return fmt.Errorf("unable to look up summary for job: %v", job.ID)
 				}
@@ -1118,21 +1309,6 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		return fmt.Errorf("Job required for plan")
 	}
 
-	// Initialize the job fields (sets defaults and any necessary init work).
-	canonicalizeWarnings := args.Job.Canonicalize()
-
-	// Add implicit constraints
-	setImplicitConstraints(args.Job)
-
-	// Validate the job and capture any warnings
-	err, warnings := validateJob(args.Job)
-	if err != nil {
-		return err
-	}
-
-	// Set the warning message
-	reply.Warnings = structs.MergeMultierrorWarnings(warnings, canonicalizeWarnings)
-
 	// Check job submission permissions, which we assume is the same for plan
 	if aclObj, err := j.srv.ResolveToken(args.AuthToken); err != nil {
 		return err
@@ -1148,14 +1324,28 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		}
 	}
 
+	// Initialize the job fields (sets defaults and any necessary init work).
+	canonicalizeWarnings := args.Job.Canonicalize()
+
+	// Run the admission controllers, which add the implicit constraints and
+	// validate the job
+	job, warnings, err := j.admissionControllers(args.Job)
+	if err != nil {
+		return err
+	}
+	args.Job = job
+
+	// Set the warning message
+	reply.Warnings = structs.MergeMultierrorWarnings(append(warnings, canonicalizeWarnings)...)
+
 	// Enforce Sentinel policies
 	policyWarnings, err := j.enforceSubmitJob(args.PolicyOverride, args.Job)
 	if err != nil {
 		return err
 	}
 	if policyWarnings != nil {
-		reply.Warnings = structs.MergeMultierrorWarnings(warnings,
-			canonicalizeWarnings, policyWarnings)
+		reply.Warnings = structs.MergeMultierrorWarnings(append(warnings,
+			canonicalizeWarnings, policyWarnings)...)
 	}
 
 	// Acquire a snapshot of the state
@@ -1261,8 +1451,9 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 
 // validateJob validates a Job and task drivers and returns an error if there is
 // a validation problem or if the Job is of a type a user is not allowed to
-// submit.
-func validateJob(job *structs.Job) (invalid, warnings error) {
+// submit. pluginDrivers are the drivers which aren't built in but that some
+// node fingerprints from its plugin directory.
+func validateJob(job *structs.Job, pluginDrivers map[string]struct{}) (invalid, warnings error) {
 	validationErrors := new(multierror.Error)
 	if err := job.Validate(); err != nil {
 		multierror.Append(validationErrors, err)
@@ -1280,6 +1471,23 @@ func validateJob(job *structs.Job) (invalid, warnings error) {
 		tgSignals, tgOk := signals[tg.Name]
 
 		for _, task := range tg.Tasks {
+			// The drivers launched from the plugin directory of the clients
+			// are unknown to the servers, so their configuration is left
+			// for the plugin to validate when the task starts. Any other
+			// driver doesn't exist.
+			if _, ok := driver.BuiltinDrivers[task.Driver]; !ok {
+				if _, ok := pluginDrivers[task.Driver]; !ok {
+					formatted := fmt.Errorf("group %q -> task %q: unknown driver %q", tg.Name, task.Name, task.Driver)
+					multierror.Append(validationErrors, formatted)
+					continue
+				}
+
+				formatted := fmt.Errorf("group %q -> task %q: driver %q isn't built in. Its config, script checks and signals were not validated",
+					tg.Name, task.Name, task.Driver)
+				warnings = multierror.Append(warnings, formatted)
+				continue
+			}
+
 			d, err := driver.NewDriver(
 				task.Driver,
 				driver.NewEmptyDriverContext(),
@@ -1295,6 +1503,14 @@ func validateJob(job *structs.Job) (invalid, warnings error) {
 				multierror.Append(validationErrors, formatted)
 			}
 
+			// Script checks are executed through the driver. Ensure the
+			// driver is capable of running commands inside the task
+			if checks := scriptChecks(task); len(checks) != 0 && !d.Abilities().Exec {
+				formatted := fmt.Errorf("group %q -> task %q: driver %q doesn't support executing commands. Script checks are %v",
+					tg.Name, task.Name, task.Driver, strings.Join(checks, ", "))
+				multierror.Append(validationErrors, formatted)
+			}
+
 			// The task group didn't have any task that required signals
 			if !tgOk {
 				continue
@@ -1323,6 +1539,55 @@ func validateJob(job *structs.Job) (invalid, warnings error) {
 	return validationErrors.ErrorOrNil(), warnings
 }
 
+// pluginDrivers returns the drivers used by the job which aren't built in but
+// are fingerprinted by at least one node, as launched from its plugin
+// directory.
+func pluginDrivers(store *state.StateStore, job *structs.Job) (map[string]struct{}, error) {
+	unknown := make(map[string]struct{})
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			if _, ok := driver.BuiltinDrivers[task.Driver]; !ok {
+				unknown[task.Driver] = struct{}{}
+			}
+		}
+	}
+	if len(unknown) == 0 {
+		return nil, nil
+	}
+
+	iter, err := store.Nodes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]struct{})
+	for raw := iter.Next(); raw != nil && len(found) != len(unknown); raw = iter.Next() {
+		node := raw.(*structs.Node)
+		for name := range unknown {
+			if _, ok := node.Attributes["driver."+name]; ok {
+				found[name] = struct{}{}
+			} else if info, ok := node.Drivers[name]; ok && info.Detected {
+				found[name] = struct{}{}
+			}
+		}
+	}
+	return found, nil
+}
+
+// scriptChecks returns the names of the script checks defined on the task's
+// services.
+func scriptChecks(task *structs.Task) []string {
+	var checks []string
+	for _, service := range task.Services {
+		for _, check := range service.Checks {
+			if check.Type == structs.ServiceCheckScript {
+				checks = append(checks, check.Name)
+			}
+		}
+	}
+	return checks
+}
+
 // validateJobUpdate ensures updates to a job are valid.
 func validateJobUpdate(old, new *structs.Job) error {
 	// Validate Dispatch not set on new Jobs
@@ -0,0 +1,215 @@
+package nomad
+
+import (
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// JobMutator is an admission controller modifying the jobs on registration,
+// before they are validated
+type JobMutator interface {
+	// Name returns the name of the mutator, for the errors
+	Name() string
+
+	// Mutate returns the modified job and any warnings, or an error if the
+	// job must be rejected
+	Mutate(*structs.Job) (out *structs.Job, warnings []error, err error)
+}
+
+// JobValidator is an admission controller accepting or rejecting the jobs on
+// registration, once they are mutated
+type JobValidator interface {
+	// Name returns the name of the validator, for the errors
+	Name() string
+
+	// Validate returns any warnings, or an error if the job must be rejected
+	Validate(*structs.Job) (warnings []error, err error)
+}
+
+// admissionControllers returns the mutators and validators the server applies
+// to the jobs on registration. The sidecar tasks of the Connect services are
+// always injected first, then come the built-in and webhook controllers of the
+// configuration, and the ones set programmatically. Implicit constraints are
+// always added last, and the job is always validated first.
+func admissionControllers(s *Server) ([]JobMutator, []JobValidator) {
+	conf := s.config
+	mutators := []JobMutator{jobConnectHook{}}
+	validators := []JobValidator{jobValidate{srv: s}}
+
+	if admission := conf.AdmissionConfig; admission != nil {
+		if len(admission.DefaultConstraints) != 0 {
+			mutators = append(mutators, newJobDefaultConstraints(admission.DefaultConstraints))
+		}
+		if len(admission.RequiredMeta) != 0 {
+			validators = append(validators, jobRequiredMeta{keys: admission.RequiredMeta})
+		}
+		if admission.DenyPrivilegedDocker != nil && *admission.DenyPrivilegedDocker {
+			validators = append(validators, jobDenyPrivilegedDocker{})
+		}
+		for _, w := range admission.Webhooks {
+			hook := newJobWebhook(w)
+			if w.Type == config.AdmissionWebhookMutating {
+				mutators = append(mutators, hook)
+			} else {
+				validators = append(validators, hook)
+			}
+		}
+	}
+
+	mutators = append(mutators, conf.JobMutators...)
+	mutators = append(mutators, jobImplicitConstraints{})
+	validators = append(validators, conf.JobValidators...)
+	return mutators, validators
+}
+
+// admissionControllers runs the mutators and then the validators on the job,
+// returning the job to register and the warnings
+func (j *Job) admissionControllers(job *structs.Job) (*structs.Job, []error, error) {
+	var warnings []error
+
+	for _, m := range j.mutators {
+		out, w, err := m.Mutate(job)
+		warnings = append(warnings, w...)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("job mutator %q failed: %v", m.Name(), err)
+		}
+		job = out
+	}
+
+	var validationErrors multierror.Error
+	for _, v := range j.validators {
+		w, err := v.Validate(job)
+		warnings = append(warnings, w...)
+		if err != nil {
+			// The built-in validation errors are returned as is
+			if _, ok := v.(jobValidate); !ok {
+				err = fmt.Errorf("job validator %q rejected the job: %v", v.Name(), err)
+			}
+			multierror.Append(&validationErrors, err)
+		}
+	}
+	return job, warnings, validationErrors.ErrorOrNil()
+}
+
+// jobImplicitConstraints adds the constraints required by the features the
+// job requests
+type jobImplicitConstraints struct{}
+
+func (jobImplicitConstraints) Name() string {
+	return "implicit-constraints"
+}
+
+func (jobImplicitConstraints) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	setImplicitConstraints(job)
+	return job, nil, nil
+}
+
+// jobValidate is the built-in validation of the job
+type jobValidate struct {
+	srv *Server
+}
+
+func (jobValidate) Name() string {
+	return "validate"
+}
+
+func (v jobValidate) Validate(job *structs.Job) ([]error, error) {
+	plugins, err := pluginDrivers(v.srv.State(), job)
+	if err != nil {
+		return nil, err
+	}
+
+	err, warnings := validateJob(job, plugins)
+	if warnings != nil {
+		return []error{warnings}, err
+	}
+	return nil, err
+}
+
+// jobDefaultConstraints adds the configured constraints to the jobs lacking
+// them
+type jobDefaultConstraints struct {
+	constraints []*structs.Constraint
+}
+
+func newJobDefaultConstraints(conf []*config.AdmissionConstraint) jobDefaultConstraints {
+	var constraints []*structs.Constraint
+	for _, c := range conf {
+		operand := c.Operator
+		if operand == "" {
+			operand = "="
+		}
+		constraints = append(constraints, &structs.Constraint{
+			LTarget: c.Attribute,
+			RTarget: c.Value,
+			Operand: operand,
+		})
+	}
+	return jobDefaultConstraints{constraints: constraints}
+}
+
+func (jobDefaultConstraints) Name() string {
+	return "default-constraints"
+}
+
+func (m jobDefaultConstraints) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+OUTER:
+	for _, c := range m.constraints {
+		// Constraints on the same attribute are left to the job
+		for _, existing := range job.Constraints {
+			if existing.LTarget == c.LTarget {
+				continue OUTER
+			}
+		}
+		job.Constraints = append(job.Constraints, c.Copy())
+	}
+	return job, nil, nil
+}
+
+// jobRequiredMeta rejects the jobs missing any of the required meta keys
+type jobRequiredMeta struct {
+	keys []string
+}
+
+func (jobRequiredMeta) Name() string {
+	return "required-meta"
+}
+
+func (v jobRequiredMeta) Validate(job *structs.Job) ([]error, error) {
+	var missing []string
+	for _, k := range v.keys {
+		if job.Meta[k] == "" {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) != 0 {
+		return nil, fmt.Errorf("missing required meta keys %q", missing)
+	}
+	return nil, nil
+}
+
+// jobDenyPrivilegedDocker rejects the jobs with Docker tasks running in
+// privileged mode
+type jobDenyPrivilegedDocker struct{}
+
+func (jobDenyPrivilegedDocker) Name() string {
+	return "deny-privileged-docker"
+}
+
+func (jobDenyPrivilegedDocker) Validate(job *structs.Job) ([]error, error) {
+	var mErr multierror.Error
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			if task.Driver != "docker" {
+				continue
+			}
+			if privileged, ok := task.Config["privileged"].(bool); ok && privileged {
+				multierror.Append(&mErr, fmt.Errorf("group %q -> task %q: privileged Docker containers are not allowed", tg.Name, task.Name))
+			}
+		}
+	}
+	return nil, mErr.ErrorOrNil()
+}
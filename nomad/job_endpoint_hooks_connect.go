@@ -0,0 +1,125 @@
+package nomad
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// connectSidecarDriver is the driver of the injected sidecar proxies. It
+	// has to run the tasks in the network namespace of the allocation.
+	connectSidecarDriver = "exec"
+
+	// connectSidecarCommand is the Envoy binary run by the injected sidecar
+	// proxies, looked up in the $PATH of the clients.
+	connectSidecarCommand = "envoy"
+
+	// connectMinConsulVersion is the minimum version of the Consul agent of
+	// the clients running Connect services. The sidecar proxies reach its
+	// gRPC endpoint through a unix socket, which needs Consul 1.6.
+	connectMinConsulVersion = ">= 1.6.0"
+)
+
+var (
+	// connectConsulConstraint is the implicit constraint of the task groups
+	// with Connect services
+	connectConsulConstraint = &structs.Constraint{
+		LTarget: "${attr.consul.version}",
+		RTarget: connectMinConsulVersion,
+		Operand: structs.ConstraintVersion,
+	}
+)
+
+// jobConnectHook injects the sidecar proxy tasks of the Consul Connect
+// services, along with the ports they listen on
+type jobConnectHook struct{}
+
+func (jobConnectHook) Name() string {
+	return "connect"
+}
+
+func (jobConnectHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	for _, tg := range job.TaskGroups {
+		connect := false
+		for _, service := range tg.Services {
+			if !service.Connect.HasSidecar() {
+				continue
+			}
+			connect = true
+
+			// Validation reports the services without a bridge network
+			if len(tg.Networks) != 0 && service.Connect.SidecarService.Port == "" {
+				label := structs.ConnectProxyName(service.Name)
+				if _, ok := tg.Networks[0].PortLabels()[label]; !ok {
+					tg.Networks[0].DynamicPorts = append(tg.Networks[0].DynamicPorts, structs.Port{Label: label})
+				}
+				service.Connect.SidecarService.Port = label
+			}
+
+			kind := structs.NewTaskKind(structs.ConnectProxyPrefix, service.Name)
+			if task := tg.LookupTaskKind(kind); task != nil {
+				// The task was derived from a previous version of the service
+				// or submitted along with the job. Reset it to run the stock
+				// proxy and apply the overrides again.
+				stock := newConnectTask(service)
+				task.Driver = stock.Driver
+				task.Config = stock.Config
+				service.Connect.SidecarTask.MergeIntoTask(task)
+				continue
+			}
+
+			task := newConnectTask(service)
+			service.Connect.SidecarTask.MergeIntoTask(task)
+			task.Canonicalize(job, tg)
+			tg.Tasks = append(tg.Tasks, task)
+		}
+
+		if connect && !hasConstraint(tg.Constraints, connectConsulConstraint) {
+			tg.Constraints = append(tg.Constraints, connectConsulConstraint.Copy())
+		}
+	}
+	return job, nil, nil
+}
+
+// newConnectTask returns the task running the Envoy sidecar proxy of a
+// Connect service. Its bootstrap configuration is written to its secrets
+// directory by the client before it is started.
+func newConnectTask(service *structs.Service) *structs.Task {
+	return &structs.Task{
+		Name:   structs.ConnectProxyName(service.Name),
+		Kind:   structs.NewTaskKind(structs.ConnectProxyPrefix, service.Name),
+		Driver: connectSidecarDriver,
+		Config: map[string]interface{}{
+			"command": connectSidecarCommand,
+			"args": []interface{}{
+				"-c", "${NOMAD_SECRETS_DIR}/envoy_bootstrap.json",
+				"-l", "info",
+				"--disable-hot-restart",
+			},
+		},
+		Resources: &structs.Resources{
+			CPU:      250,
+			MemoryMB: 128,
+		},
+		LogConfig: &structs.LogConfig{
+			MaxFiles:      2,
+			MaxFileSizeMB: 2,
+		},
+		ShutdownDelay: 5 * time.Second,
+		Lifecycle: &structs.TaskLifecycleConfig{
+			Hook:    structs.TaskLifecycleHookPrestart,
+			Sidecar: true,
+		},
+	}
+}
+
+// hasConstraint returns whether the constraint is in the list
+func hasConstraint(constraints []*structs.Constraint, c *structs.Constraint) bool {
+	for _, existing := range constraints {
+		if existing.Equal(c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,391 @@
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+)
+
+// testMutator sets a meta key on the jobs
+type testMutator struct{}
+
+func (testMutator) Name() string { return "test-mutator" }
+
+func (testMutator) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	if job.Meta == nil {
+		job.Meta = map[string]string{}
+	}
+	job.Meta["mutated"] = "true"
+	return job, []error{fmt.Errorf("job was mutated")}, nil
+}
+
+// testValidator rejects the jobs with a given name
+type testValidator struct{}
+
+func (testValidator) Name() string { return "test-validator" }
+
+func (testValidator) Validate(job *structs.Job) ([]error, error) {
+	if job.Name == "rejected" {
+		return nil, fmt.Errorf("job name is rejected")
+	}
+	return nil, nil
+}
+
+func TestJobEndpoint_Register_AdmissionControllers(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionConfig = &config.AdmissionConfig{
+			DefaultConstraints: []*config.AdmissionConstraint{
+				{Attribute: "${attr.kernel.name}", Value: "linux"},
+				{Attribute: "${meta.rack}", Operator: "!=", Value: "r1"},
+			},
+			RequiredMeta: []string{"cost-center"},
+		}
+		c.JobMutators = []JobMutator{testMutator{}}
+		c.JobValidators = []JobValidator{testValidator{}}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	register := func(job *structs.Job) (*structs.JobRegisterResponse, error) {
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+		return &resp, err
+	}
+
+	// The required meta is enforced
+	job := mock.Job()
+	_, err := register(job)
+	require.Error(err)
+	require.Contains(err.Error(), `job validator "required-meta" rejected the job`)
+	require.Contains(err.Error(), `"cost-center"`)
+
+	// The validators set programmatically run
+	job = mock.Job()
+	job.Name = "rejected"
+	job.Meta["cost-center"] = "ops"
+	_, err = register(job)
+	require.Error(err)
+	require.Contains(err.Error(), "job name is rejected")
+
+	// The job is mutated, keeping its own constraint on the kernel
+	job = mock.Job()
+	job.Meta["cost-center"] = "ops"
+	resp, err := register(job)
+	require.NoError(err)
+	require.Contains(resp.Warnings, "job was mutated")
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("true", out.Meta["mutated"])
+	require.Len(out.Constraints, 2)
+	require.Equal(job.Constraints[0], out.Constraints[0])
+	require.Equal(&structs.Constraint{
+		LTarget: "${meta.rack}",
+		RTarget: "r1",
+		Operand: "!=",
+	}, out.Constraints[1])
+}
+
+func TestJobEndpoint_Validate_AdmissionControllers(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.AdmissionConfig = &config.AdmissionConfig{
+			DenyPrivilegedDocker: helper.BoolToPtr(true),
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Driver = "docker"
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"image":      "redis",
+		"privileged": true,
+	}
+	req := &structs.JobValidateRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	var resp structs.JobValidateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Validate", req, &resp))
+	require.Len(resp.ValidationErrors, 1)
+	require.Contains(resp.Error, "privileged Docker containers are not allowed")
+}
+
+func TestJobEndpoint_Register_AdmissionControllers_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	called := false
+	srv := testWebhook(t, func(job *structs.Job) *AdmissionWebhookResponse {
+		called = true
+		return &AdmissionWebhookResponse{Allowed: true}
+	})
+	defer srv.Close()
+
+	s1, _ := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionConfig = &config.AdmissionConfig{
+			Webhooks: []*config.AdmissionWebhook{{
+				Name:    "validate",
+				Type:    config.AdmissionWebhookValidating,
+				Address: srv.URL,
+			}},
+		}
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The webhook isn't called for requests without permissions
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+	require.False(called)
+}
+
+func TestJobRequiredMeta(t *testing.T) {
+	t.Parallel()
+
+	v := jobRequiredMeta{keys: []string{"owner", "team"}}
+	job := mock.Job()
+	job.Meta = map[string]string{"owner": "ops", "team": ""}
+
+	_, err := v.Validate(job)
+	require.EqualError(t, err, `missing required meta keys ["team"]`)
+
+	job.Meta["team"] = "platform"
+	_, err = v.Validate(job)
+	require.NoError(t, err)
+}
+
+func TestJobDenyPrivilegedDocker(t *testing.T) {
+	t.Parallel()
+
+	job := mock.Job()
+	task := job.TaskGroups[0].Tasks[0]
+	task.Driver = "docker"
+	task.Config = map[string]interface{}{"image": "redis", "privileged": false}
+
+	_, err := jobDenyPrivilegedDocker{}.Validate(job)
+	require.NoError(t, err)
+
+	task.Config["privileged"] = true
+	_, err = jobDenyPrivilegedDocker{}.Validate(job)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), fmt.Sprintf("group %q -> task %q", job.TaskGroups[0].Name, task.Name))
+
+	// Other drivers are left alone
+	task.Driver = "exec"
+	_, err = jobDenyPrivilegedDocker{}.Validate(job)
+	require.NoError(t, err)
+}
+
+func TestJobConnectHook(t *testing.T) {
+	t.Parallel()
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Tasks[0].Resources.Networks = nil
+	tg.Tasks[0].Services = nil
+	tg.Networks = []*structs.NetworkResource{
+		{
+			Mode:         structs.NetworkModeBridge,
+			DynamicPorts: []structs.Port{{Label: "http", To: 8080}},
+		},
+	}
+	tg.Services = []*structs.Service{
+		{
+			Name:      "web",
+			PortLabel: "http",
+			Connect: &structs.ConsulConnect{
+				SidecarService: &structs.ConsulSidecarService{},
+				SidecarTask: &structs.SidecarTask{
+					User: "envoy",
+					Env:  map[string]string{"FOO": "bar"},
+				},
+			},
+		},
+	}
+
+	out, warnings, err := jobConnectHook{}.Mutate(job)
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+
+	// The sidecar task and its port are injected
+	tg = out.TaskGroups[0]
+	task := tg.LookupTask("connect-proxy-web")
+	require.NotNil(t, task)
+	require.True(t, task.Kind.IsConnectProxy())
+	require.Equal(t, "web", task.Kind.Value())
+	require.Equal(t, "exec", task.Driver)
+	require.Equal(t, "envoy", task.User)
+	require.Equal(t, "bar", task.Env["FOO"])
+	require.True(t, task.IsSidecar())
+	require.Equal(t, "connect-proxy-web", tg.Services[0].Connect.SidecarService.Port)
+	require.Contains(t, tg.Networks[0].PortLabels(), "connect-proxy-web")
+	require.Len(t, tg.Constraints, 1)
+	require.Equal(t, "${attr.consul.version}", tg.Constraints[0].LTarget)
+	require.NoError(t, out.Validate())
+
+	// Mutating again doesn't inject the task, port or constraint twice
+	numTasks, numPorts := len(tg.Tasks), len(tg.Networks[0].DynamicPorts)
+	out, _, err = jobConnectHook{}.Mutate(out)
+	require.NoError(t, err)
+	tg = out.TaskGroups[0]
+	require.Len(t, tg.Tasks, numTasks)
+	require.Len(t, tg.Networks[0].DynamicPorts, numPorts)
+	require.Len(t, tg.Constraints, 1)
+
+	// A sidecar task submitted with the job is reset to the stock proxy
+	task = tg.LookupTask("connect-proxy-web")
+	task.Driver = "raw_exec"
+	task.Config = map[string]interface{}{"command": "cat"}
+	out, _, err = jobConnectHook{}.Mutate(out)
+	require.NoError(t, err)
+	task = out.TaskGroups[0].LookupTask("connect-proxy-web")
+	require.Equal(t, "exec", task.Driver)
+	require.Equal(t, "envoy", task.Config["command"])
+	require.Equal(t, "envoy", task.User)
+
+	// The driver and its config can't be overridden
+	tg.Services[0].Connect.SidecarTask.Driver = "raw_exec"
+	tg.Services[0].Connect.SidecarTask.Config = map[string]interface{}{"command": "cat"}
+	err = out.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Sidecar task driver can't be overridden")
+	require.Contains(t, err.Error(), "Sidecar task config can't be overridden")
+}
+
+// testWebhook returns a webhook server responding with the result of f
+func testWebhook(t *testing.T, f func(job *structs.Job) *AdmissionWebhookResponse) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AdmissionWebhookRequest
+		if err := codec.NewDecoder(r.Body, structs.JsonHandle).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			w.WriteHeader(400)
+			return
+		}
+		resp := f(req.Job)
+		if resp == nil {
+			w.WriteHeader(500)
+			return
+		}
+		codec.NewEncoder(w, structs.JsonHandle).Encode(resp)
+	}))
+}
+
+func TestJobWebhook_Mutating(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv := testWebhook(t, func(job *structs.Job) *AdmissionWebhookResponse {
+		if job.VaultToken != "" {
+			t.Errorf("webhook received the Vault token")
+		}
+		job.Priority = 70
+		return &AdmissionWebhookResponse{
+			Allowed:  true,
+			Warnings: []string{"priority raised"},
+			Job:      job,
+		}
+	})
+	defer srv.Close()
+
+	hook := newJobWebhook(&config.AdmissionWebhook{
+		Name:    "mutate",
+		Type:    config.AdmissionWebhookMutating,
+		Address: srv.URL,
+	})
+
+	job := mock.Job()
+	job.VaultToken = "secret"
+	out, warnings, err := hook.Mutate(job)
+	require.NoError(err)
+	require.Equal(70, out.Priority)
+	require.Equal(job.ID, out.ID)
+	require.Equal("secret", out.VaultToken)
+	require.Equal("secret", job.VaultToken)
+	require.Len(warnings, 1)
+	require.EqualError(warnings[0], "priority raised")
+}
+
+func TestJobWebhook_Validating(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	srv := testWebhook(t, func(job *structs.Job) *AdmissionWebhookResponse {
+		switch job.Name {
+		case "denied":
+			return &AdmissionWebhookResponse{Reason: "not on my watch"}
+		case "broken":
+			return nil
+		}
+		return &AdmissionWebhookResponse{Allowed: true}
+	})
+	defer srv.Close()
+
+	hook := newJobWebhook(&config.AdmissionWebhook{
+		Name:    "validate",
+		Type:    config.AdmissionWebhookValidating,
+		Address: srv.URL,
+	})
+
+	job := mock.Job()
+	_, err := hook.Validate(job)
+	require.NoError(err)
+
+	job.Name = "denied"
+	_, err = hook.Validate(job)
+	require.EqualError(err, "not on my watch")
+
+	// A failing webhook rejects the job unless it fails open
+	job.Name = "broken"
+	_, err = hook.Validate(job)
+	require.Error(err)
+	require.Contains(err.Error(), "unexpected response code 500")
+
+	hook.failOpen = true
+	warnings, err := hook.Validate(job)
+	require.NoError(err)
+	require.Len(warnings, 1)
+	require.Contains(warnings[0].Error(), `admission webhook "validate" failed, admitting the job`)
+}
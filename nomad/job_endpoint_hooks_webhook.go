@@ -0,0 +1,151 @@
+package nomad
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	// defaultWebhookTimeout is how long to wait for an admission webhook
+	// when no timeout is configured
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// AdmissionWebhookRequest is the body posted to the admission webhooks
+type AdmissionWebhookRequest struct {
+	Job *structs.Job
+}
+
+// AdmissionWebhookResponse is the body the admission webhooks respond with
+type AdmissionWebhookResponse struct {
+	// Allowed is whether the job is admitted
+	Allowed bool
+
+	// Reason explains why the job is rejected
+	Reason string
+
+	// Warnings are returned to the submitter of the job
+	Warnings []string
+
+	// Job is the modified job, for mutating webhooks. The job is left as is
+	// if it is nil.
+	Job *structs.Job
+}
+
+// jobWebhook is an admission controller posting the job to an external
+// service, which may reject it or, if mutating, modify it
+type jobWebhook struct {
+	name     string
+	address  string
+	mutating bool
+	failOpen bool
+	client   *http.Client
+}
+
+func newJobWebhook(conf *config.AdmissionWebhook) *jobWebhook {
+	timeout := conf.Timeout
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+	client := cleanhttp.DefaultClient()
+	client.Timeout = timeout
+
+	return &jobWebhook{
+		name:     conf.Name,
+		address:  conf.Address,
+		mutating: conf.Type == config.AdmissionWebhookMutating,
+		failOpen: conf.FailOpen,
+		client:   client,
+	}
+}
+
+func (w *jobWebhook) Name() string {
+	return w.name
+}
+
+func (w *jobWebhook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	resp, warnings, err := w.call(job)
+	if err != nil || resp == nil || resp.Job == nil {
+		return job, warnings, err
+	}
+
+	// The identity of the job can't change
+	if resp.Job.ID != job.ID || resp.Job.Namespace != job.Namespace {
+		return nil, warnings, fmt.Errorf("webhook changed the job ID or namespace")
+	}
+
+	// The Vault token isn't sent to the webhook, so restore it
+	resp.Job.VaultToken = job.VaultToken
+	return resp.Job, warnings, nil
+}
+
+func (w *jobWebhook) Validate(job *structs.Job) ([]error, error) {
+	_, warnings, err := w.call(job)
+	return warnings, err
+}
+
+// call posts the job to the webhook and returns its response if the job is
+// admitted. If the webhook fails and is configured to fail open, the job is
+// admitted with a nil response and a warning.
+func (w *jobWebhook) call(job *structs.Job) (*AdmissionWebhookResponse, []error, error) {
+	resp, err := w.post(job)
+	if err != nil {
+		if w.failOpen {
+			return nil, []error{fmt.Errorf("admission webhook %q failed, admitting the job: %v", w.name, err)}, nil
+		}
+		return nil, nil, err
+	}
+
+	var warnings []error
+	for _, warning := range resp.Warnings {
+		warnings = append(warnings, fmt.Errorf("%s", warning))
+	}
+	if !resp.Allowed {
+		reason := resp.Reason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		return nil, warnings, fmt.Errorf("%s", reason)
+	}
+	return resp, warnings, nil
+}
+
+// post sends the job to the webhook. The Vault token of the job is a
+// credential of the submitter, so it is never sent.
+func (w *jobWebhook) post(job *structs.Job) (*AdmissionWebhookResponse, error) {
+	if job.VaultToken != "" {
+		job = job.Copy()
+		job.VaultToken = ""
+	}
+
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, structs.JsonHandle).Encode(&AdmissionWebhookRequest{Job: job}); err != nil {
+		return nil, fmt.Errorf("failed to encode job: %v", err)
+	}
+
+	httpResp, err := w.client.Post(w.address, "application/json", &buf)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(httpResp.Body, 1024))
+		return nil, fmt.Errorf("unexpected response code %d: %s", httpResp.StatusCode, body)
+	}
+
+	var resp AdmissionWebhookResponse
+	if err := codec.NewDecoder(httpResp.Body, structs.JsonHandle).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return &resp, nil
+}
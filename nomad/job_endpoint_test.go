@@ -411,6 +411,127 @@ func TestJobEndpoint_Register_Periodic(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Register_Multiregion(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+
+	s2 := TestServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Wait for the regions to know about each other
+	testutil.WaitForResult(func() (bool, error) {
+		regions := s1.Regions()
+		return len(regions) == 2, fmt.Errorf("unexpected regions: %v", regions)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Create the register request for a job in both regions
+	job := mock.Job()
+	job.Region = "region1"
+	job.Multiregion = &structs.Multiregion{
+		Strategy: &structs.MultiregionStrategy{MaxParallel: 1},
+		Regions: []*structs.MultiregionRegion{
+			{
+				Name:        "region1",
+				Datacenters: []string{"dc1"},
+			},
+			{
+				Name:        "region2",
+				Count:       3,
+				Datacenters: []string{"dc2"},
+				Meta:        map[string]string{"region": "two"},
+			},
+		},
+	}
+	job.TaskGroups[0].Count = 0
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "region1",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Registering fails while a region is unknown
+	job.Multiregion.Regions = append(job.Multiregion.Regions, &structs.MultiregionRegion{Name: "region3"})
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "region3")
+	job.Multiregion.Regions = job.Multiregion.Regions[:2]
+
+	// Registering fails in all the regions if a region rejects the job
+	rejected := job.Copy()
+	rejected.ID = "rejected"
+	batch := rejected.RegionalJob(rejected.Multiregion.Regions[1])
+	batch.Type = structs.JobTypeBatch
+	batch.Multiregion = nil
+	require.NoError(s2.fsm.State().UpsertJob(1000, batch))
+	rejectedReq := &structs.JobRegisterRequest{
+		Job:          rejected,
+		WriteRequest: req.WriteRequest,
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", rejectedReq, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "region2")
+	out, err := s1.fsm.State().JobByID(nil, rejected.Namespace, rejected.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.NotZero(resp.JobModifyIndex)
+	require.NotEmpty(resp.EvalID)
+	require.Len(resp.RegionResults, 2)
+	require.Equal(resp.EvalID, resp.RegionResults["region1"].EvalID)
+	require.NotEmpty(resp.RegionResults["region2"].EvalID)
+
+	// Check for the job in both regions
+	ws := memdb.NewWatchSet()
+	out, err = s1.fsm.State().JobByID(ws, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("region1", out.Region)
+	require.Equal([]string{"dc1"}, out.Datacenters)
+	require.Equal(resp.JobModifyIndex, out.CreateIndex)
+
+	out, err = s2.fsm.State().JobByID(ws, job.Namespace, job.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal("region2", out.Region)
+	require.Equal([]string{"dc2"}, out.Datacenters)
+	require.Equal("two", out.Meta["region"])
+	require.Equal(3, out.TaskGroups[0].Count)
+
+	// The region a job that isn't deployed to it is submitted to returns the
+	// results of the job's regions
+	other := job.Copy()
+	other.ID = "other"
+	other.Multiregion.Regions = other.Multiregion.Regions[1:]
+	otherReq := &structs.JobRegisterRequest{
+		Job:          other,
+		WriteRequest: req.WriteRequest,
+	}
+	var otherResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", otherReq, &otherResp))
+	require.Empty(otherResp.EvalID)
+	require.Len(otherResp.RegionResults, 1)
+	require.NotEmpty(otherResp.RegionResults["region2"].EvalID)
+}
+
 func TestJobEndpoint_Register_ParameterizedJob(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, func(c *Config) {
@@ -2342,6 +2463,151 @@ func TestJobEndpoint_GetJobVersions(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_GetJobSubmission(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register the job with its source
+	job := mock.Job()
+	job.Priority = 88
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source: "priority = 88",
+			Format: structs.JobSubmissionFormatHCL2,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	// Register a new version with another source
+	job.Priority = 100
+	reg.Submission.Source = "priority = 100"
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	// The source of the current version is returned by default
+	get := &structs.JobSubmissionRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var subResp structs.JobSubmissionResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.NotNil(subResp.Submission)
+	require.Equal("priority = 100", subResp.Submission.Source)
+	require.Equal(uint64(1), subResp.Submission.Version)
+	require.Equal(resp.JobModifyIndex, subResp.Index)
+
+	// Lookup a previous version
+	get.Version = helper.Uint64ToPtr(0)
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &subResp))
+	require.NotNil(subResp.Submission)
+	require.Equal("priority = 88", subResp.Submission.Source)
+
+	// Lookup a non-existing job
+	get.JobID = "foobarbaz"
+	get.Version = nil
+	var missingResp structs.JobSubmissionResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &missingResp))
+	require.Nil(missingResp.Submission)
+}
+
+func TestJobEndpoint_Register_SubmissionTooLarge(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The job is registered without its source, with a warning
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		Submission: &structs.JobSubmission{
+			Source: strings.Repeat("#", structs.JobSubmissionMaxSize+1),
+			Format: structs.JobSubmissionFormatHCL2,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+	require.Contains(resp.Warnings, "Job source not stored")
+
+	out, err := s1.fsm.State().JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestJobEndpoint_GetJobSubmission_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(10, job))
+	require.NoError(state.UpsertJobSubmission(11, &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+	}))
+
+	get := &structs.JobSubmissionRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Attempt to fetch without a token should fail
+	var resp structs.JobSubmissionResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "Permission denied")
+
+	// Expect failure for request with an invalid token
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityListJobs}))
+	get.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp)
+	require.NotNil(err)
+	require.Contains(err.Error(), "Permission denied")
+
+	// Expect success for request with a read-job token
+	validToken := mock.CreatePolicyAndToken(t, state, 1005, "test-valid",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	get.AuthToken = validToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.NotNil(resp.Submission)
+
+	// Expect success for request with a management token
+	get.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.GetJobSubmission", get, &resp))
+	require.NotNil(resp.Submission)
+}
+
 func TestJobEndpoint_GetJobVersions_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -2952,6 +3218,59 @@ func TestJobEndpoint_ListJobs_WithACL(t *testing.T) {
 	require.Equal(job.ID, validResp.Jobs[0].ID)
 }
 
+func TestJobEndpoint_ListJobs_AllNamespaces_WithACL(t *testing.T) {
+	require := require.New(t)
+	t.Parallel()
+
+	srv, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer srv.Shutdown()
+	codec := rpcClient(t, srv)
+	testutil.WaitForLeader(t, srv.RPC)
+	state := srv.fsm.State()
+
+	// Create a job in the default namespace and one in another namespace
+	ns := mock.Namespace()
+	require.NoError(state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	job1 := mock.Job()
+	require.NoError(state.UpsertJob(1001, job1))
+	job2 := mock.Job()
+	job2.Namespace = ns.Name
+	require.NoError(state.UpsertJob(1002, job2))
+
+	req := &structs.JobListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.AllNamespacesSentinel,
+			AuthToken: root.SecretID,
+		},
+	}
+
+	// The management token lists the jobs of all the namespaces
+	var mgmtResp structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", req, &mgmtResp))
+	require.Len(mgmtResp.Jobs, 2)
+
+	// Other tokens only list the jobs of the namespaces they may list
+	token := mock.CreatePolicyAndToken(t, state, 1003, "test-valid",
+		mock.NamespacePolicy(ns.Name, "", []string{acl.NamespaceCapabilityListJobs}))
+	req.AuthToken = token.SecretID
+
+	var validResp structs.JobListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.List", req, &validResp))
+	require.Len(validResp.Jobs, 1)
+	require.Equal(job2.ID, validResp.Jobs[0].ID)
+	require.Equal(ns.Name, validResp.Jobs[0].Namespace)
+
+	// Listing the default namespace directly is still denied
+	req.Namespace = structs.DefaultNamespace
+	var invalidResp structs.JobListResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.List", req, &invalidResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+}
+
 func TestJobEndpoint_ListJobs_Blocking(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -3924,7 +4243,7 @@ func TestJobEndpoint_ValidateJob_InvalidDriverConf(t *testing.T) {
 		"foo": "bar",
 	}
 
-	err, warnings := validateJob(job)
+	err, warnings := validateJob(job, nil)
 	if err == nil || !strings.Contains(err.Error(), "-> config") {
 		t.Fatalf("Expected config error; got %v", err)
 	}
@@ -3934,6 +4253,79 @@ func TestJobEndpoint_ValidateJob_InvalidDriverConf(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_ValidateJob_ExternalDriver(t *testing.T) {
+	t.Parallel()
+	// Create a mock job using a driver plugin the server doesn't load
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Driver = "my-plugin"
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"foo": "bar",
+	}
+
+	plugins := map[string]struct{}{"my-plugin": {}}
+	err, warnings := validateJob(job, plugins)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The server warns that the task wasn't validated
+	if warnings == nil || !strings.Contains(warnings.Error(), `driver "my-plugin" isn't built in`) ||
+		!strings.Contains(warnings.Error(), "config, script checks and signals were not validated") {
+		t.Fatalf("expected unvalidated driver warning; got %v", warnings)
+	}
+}
+
+func TestJobEndpoint_ValidateJob_UnknownDriver(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Create a mock job with a typo in its driver
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Driver = "dokcer"
+
+	plugins := map[string]struct{}{"my-plugin": {}}
+	err, warnings := validateJob(job, plugins)
+	require.Error(err)
+	require.Contains(err.Error(), `unknown driver "dokcer"`)
+	require.Nil(warnings)
+}
+
+func TestJobEndpoint_Register_PluginDriver(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a job using a driver plugin
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Driver = "my-plugin"
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// The job is rejected until a node fingerprints the plugin
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), `unknown driver "my-plugin"`)
+
+	node := mock.Node()
+	node.Attributes["driver.my-plugin"] = "1"
+	require.NoError(s1.fsm.State().UpsertNode(1000, node))
+
+	// The job is registered with a warning that its driver wasn't validated
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	require.NotZero(resp.Index)
+	require.Contains(resp.Warnings, `driver "my-plugin" isn't built in`)
+}
+
 func TestJobEndpoint_ValidateJob_InvalidSignals(t *testing.T) {
 	t.Parallel()
 	// Create a mock job that wants to send a signal to a driver that can't
@@ -3945,7 +4337,7 @@ func TestJobEndpoint_ValidateJob_InvalidSignals(t *testing.T) {
 		ChangeSignal: "SIGUSR1",
 	}
 
-	err, warnings := validateJob(job)
+	err, warnings := validateJob(job, nil)
 	if err == nil || !strings.Contains(err.Error(), "support sending signals") {
 		t.Fatalf("Expected signal feasibility error; got %v", err)
 	}
@@ -3955,6 +4347,41 @@ func TestJobEndpoint_ValidateJob_InvalidSignals(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_ValidateJob_ScriptChecks(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	// Create a mock job with a script check on a driver that can't exec
+	job := mock.Job()
+	task := job.TaskGroups[0].Tasks[0]
+	task.Driver = "qemu"
+	task.Config = map[string]interface{}{
+		"image_path": "linux.img",
+	}
+	task.Services[0].Checks = append(task.Services[0].Checks, &structs.ServiceCheck{
+		Name:     "check-script",
+		Type:     structs.ServiceCheckScript,
+		Command:  "/bin/true",
+		Interval: 10 * time.Second,
+		Timeout:  2 * time.Second,
+	})
+
+	err, warnings := validateJob(job, nil)
+	require.Error(err)
+	require.Contains(err.Error(), "support executing commands")
+	require.Contains(err.Error(), "check-script")
+	require.Nil(warnings)
+
+	// The exec driver supports script checks
+	task.Driver = "exec"
+	task.Config = map[string]interface{}{
+		"command": "/bin/date",
+	}
+	err, warnings = validateJob(job, nil)
+	require.NoError(err)
+	require.Nil(warnings)
+}
+
 func TestJobEndpoint_ValidateJob_KillSignal(t *testing.T) {
 	require := require.New(t)
 	t.Parallel()
@@ -3966,7 +4393,7 @@ func TestJobEndpoint_ValidateJob_KillSignal(t *testing.T) {
 		job.TaskGroups[0].Tasks[0].Driver = "qemu" // qemu does not support sending signals
 		job.TaskGroups[0].Tasks[0].KillSignal = "SIGINT"
 
-		err, warnings := validateJob(job)
+		err, warnings := validateJob(job, nil)
 		require.NotNil(err)
 		require.True(strings.Contains(err.Error(), "support sending signals"))
 		require.Nil(warnings)
@@ -3978,7 +4405,7 @@ func TestJobEndpoint_ValidateJob_KillSignal(t *testing.T) {
 		job := mock.Job()
 		job.TaskGroups[0].Tasks[0].KillSignal = "SIGINT"
 
-		err, warnings := validateJob(job)
+		err, warnings := validateJob(job, nil)
 		require.Nil(err)
 		require.Nil(warnings)
 	}
@@ -4064,6 +4491,50 @@ func TestJobEndpoint_ValidateJobUpdate_ACL(t *testing.T) {
 	require.Equal("", validResp.Warnings)
 }
 
+func TestJobEndpoint_ValidateJob_Structured(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// A job with warnings reports each of them
+	job := mock.Job()
+	job.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	job.TaskGroups[0].Update.MaxParallel = 20
+	req := &structs.JobValidateRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobValidateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Validate", req, &resp))
+	require.Empty(resp.ValidationErrors)
+	require.Len(resp.ValidationWarnings, 1)
+	require.Contains(resp.ValidationWarnings[0], "max parallel count is greater")
+	require.Contains(resp.Warnings, resp.ValidationWarnings[0])
+
+	// An invalid job reports each error and isn't registered
+	job = mock.Job()
+	job.Priority = 0
+	job.TaskGroups[0].Count = -1
+	req.Job = job
+	var invalidResp structs.JobValidateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Validate", req, &invalidResp))
+	require.Len(invalidResp.ValidationErrors, 2)
+	require.NotEmpty(invalidResp.Error)
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
 func TestJobEndpoint_Dispatch_ACL(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -0,0 +1,277 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Keyring endpoint is used to manage the root keys used to sign workload
+// identities and encrypt variables
+type Keyring struct {
+	srv *Server
+
+	// ctx provides context regarding the underlying connection
+	ctx *RPCContext
+}
+
+// Rotate is used to replace the active root key by a new one. The previous
+// keys are kept to verify the identities and decrypt the variables they
+// signed and encrypted, unless a full rotation re-encrypts the variables.
+func (k *Keyring) Rotate(args *structs.KeyringRotateRequest, reply *structs.KeyringRotateResponse) error {
+	if done, err := k.srv.forward("Keyring.Rotate", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "rotate"}, time.Now())
+
+	// Check operator write permissions
+	if aclObj, err := k.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	key, err := generateRootKey()
+	if err != nil {
+		return err
+	}
+
+	// Store the key material locally before the other servers learn about
+	// the key, so they can fetch it from the leader
+	if err := k.srv.encrypter.AddKey(key); err != nil {
+		return err
+	}
+
+	// Update via Raft
+	meta := key.Meta
+	req := structs.RootKeyUpsertRequest{RootKeyMeta: meta, WriteRequest: args.WriteRequest}
+	_, index, err := k.srv.raftApply(structs.RootKeyUpsertRequestType, req)
+	if err != nil {
+		return err
+	}
+	k.srv.logger.Printf("[INFO] nomad: rotated root key to %s", meta.KeyID)
+	meta.CreateIndex = index
+	meta.ModifyIndex = index
+	reply.Key = meta.Stub()
+	reply.Index = index
+
+	if args.Full {
+		index, err := k.reencryptVariables(meta.KeyID)
+		if err != nil {
+			return err
+		}
+		if index != 0 {
+			reply.Index = index
+		}
+	}
+	return nil
+}
+
+// reencryptVariables re-encrypts the variables that weren't encrypted by the
+// active root key, and returns the index of the last update.
+func (k *Keyring) reencryptVariables(keyID string) (uint64, error) {
+	iter, err := k.srv.State().Variables(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var index uint64
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		sv := raw.(*structs.VariableEncrypted)
+		if sv.KeyID == keyID {
+			continue
+		}
+
+		idx, err := k.reencryptVariable(sv)
+		if err != nil {
+			return 0, err
+		}
+		if idx != 0 {
+			index = idx
+		}
+	}
+	return index, nil
+}
+
+// reencryptVariable re-encrypts the variable with the active root key, unless
+// it was updated or deleted since it was read. It returns the index of the
+// update, or zero if the variable was left as is.
+func (k *Keyring) reencryptVariable(sv *structs.VariableEncrypted) (uint64, error) {
+	plaintext, err := k.srv.encrypter.Decrypt(sv.Data, sv.KeyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt variable %q: %v", sv.Path, err)
+	}
+	updated := sv.Copy()
+	if updated.Data, updated.KeyID, err = k.srv.encrypter.Encrypt(plaintext); err != nil {
+		return 0, fmt.Errorf("failed to encrypt variable %q: %v", sv.Path, err)
+	}
+
+	req := structs.VariableUpsertRequest{Var: updated, CheckIndex: sv.ModifyIndex}
+	resp, index, err := k.srv.raftApply(structs.VariableUpsertRequestType, req)
+	if err != nil {
+		return 0, err
+	}
+	if err, ok := resp.(error); ok && err != nil {
+		// The variable was deleted, or updated with a key that is still in
+		// the keyring, since it was read
+		if err == structs.ErrVariableConflict {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return index, nil
+}
+
+// List is used to list the metadata of the root keys
+func (k *Keyring) List(args *structs.KeyringListRequest, reply *structs.KeyringListResponse) error {
+	if done, err := k.srv.forward("Keyring.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list"}, time.Now())
+
+	// Check operator read permissions
+	if aclObj, err := k.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.RootKeyMetas(ws)
+			if err != nil {
+				return err
+			}
+
+			reply.Keys = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.Keys = append(reply.Keys, raw.(*structs.RootKeyMeta).Stub())
+			}
+
+			// Use the last index that affected the root key table
+			index, err := state.Index("root_keys")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return k.srv.blockingRPC(&opts)
+}
+
+// ListPublic is used to list the public half of all root keys. It requires no
+// ACL token since the keys are used by third parties to verify workload
+// identities.
+func (k *Keyring) ListPublic(args *structs.KeyringListPublicRequest, reply *structs.KeyringListPublicResponse) error {
+	if done, err := k.srv.forward("Keyring.ListPublic", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "list_public"}, time.Now())
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.RootKeyMetas(ws)
+			if err != nil {
+				return err
+			}
+
+			reply.PublicKeys = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.PublicKeys = append(reply.PublicKeys, raw.(*structs.RootKeyMeta).Public())
+			}
+
+			// Use the last index that affected the root key table
+			index, err := state.Index("root_keys")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return k.srv.blockingRPC(&opts)
+}
+
+// Get is used by servers to fetch the key material of a root key that they
+// are missing from another server. The key material isn't replicated through
+// Raft, so it is only returned to the other servers of the region over mutual
+// TLS. Without TLS for RPC the connection can't prove that it comes from a
+// server, so the key material is sent to the RPC address of the requesting
+// server instead.
+func (k *Keyring) Get(args *structs.KeyringGetRootKeyRequest, reply *structs.KeyringGetRootKeyResponse) error {
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "get"}, time.Now())
+
+	if !k.srv.config.TLSConfig.EnableRPC {
+		if err := k.srv.sendRootKey(args.ServerID, args.KeyID); err != nil {
+			return err
+		}
+	} else {
+		if err := k.srv.validateServerConn(k.ctx); err != nil {
+			return err
+		}
+
+		key, err := k.srv.encrypter.GetKey(args.KeyID)
+		if err != nil {
+			return err
+		}
+		reply.Key = key
+	}
+
+	index, err := k.srv.State().Index("root_keys")
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// Put is used by servers to send the key material of a root key to a server
+// that is missing it. The key material is only stored if it matches the
+// public key of a root key in the state store, so it can't be made up or
+// replaced by the caller.
+func (k *Keyring) Put(args *structs.KeyringPutRootKeyRequest, reply *structs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "put"}, time.Now())
+
+	key := args.Key
+	if key == nil || key.Meta == nil || key.Meta.KeyID == "" {
+		return fmt.Errorf("missing root key")
+	}
+	if k.srv.encrypter.HasKey(key.Meta.KeyID) {
+		return nil
+	}
+
+	meta, err := k.srv.State().RootKeyMetaByID(nil, key.Meta.KeyID)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		return fmt.Errorf("unknown root key %q", key.Meta.KeyID)
+	}
+	if err := checkRootKey(key, meta); err != nil {
+		return err
+	}
+
+	key.Meta = meta
+	return k.srv.encrypter.AddKey(key)
+}
@@ -0,0 +1,257 @@
+package nomad
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringEndpoint_ListPublic(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// The leader creates the first root key
+	active, err := s1.fsm.State().ActiveRootKeyMeta(nil)
+	require.NoError(err)
+	require.NotNil(active)
+
+	// Add another key
+	key := mock.RootKey().Meta
+	require.NoError(s1.fsm.State().UpsertRootKeyMeta(1000, key))
+
+	// No token is needed to list the public keys
+	req := &structs.KeyringListPublicRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.KeyringListPublicResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.ListPublic", req, &resp))
+	require.EqualValues(1000, resp.Index)
+	require.Len(resp.PublicKeys, 2)
+
+	ids := []string{resp.PublicKeys[0].KeyID, resp.PublicKeys[1].KeyID}
+	require.Contains(ids, active.KeyID)
+	require.Contains(ids, key.KeyID)
+	for _, pub := range resp.PublicKeys {
+		require.Equal(structs.RootKeyAlgorithmES256, pub.Algorithm)
+		require.NotEmpty(pub.PublicKey)
+	}
+}
+
+func TestKeyringEndpoint_Get_NoTLS(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	active, err := s1.fsm.State().ActiveRootKeyMeta(nil)
+	require.NoError(err)
+	require.NotNil(active)
+
+	// Without TLS the key material is never returned, even with a
+	// management token, and it is only sent to known servers
+	for _, serverID := range []string{"", s1.config.NodeID, uuid.Generate()} {
+		req := &structs.KeyringGetRootKeyRequest{
+			KeyID:    active.KeyID,
+			ServerID: serverID,
+			QueryOptions: structs.QueryOptions{
+				Region:    "global",
+				AuthToken: root.SecretID,
+			},
+		}
+		var resp structs.KeyringGetRootKeyResponse
+		err = msgpackrpc.CallWithCodec(codec, "Keyring.Get", req, &resp)
+		require.Error(err)
+		require.Contains(err.Error(), structs.ErrPermissionDenied.Error())
+		require.Nil(resp.Key)
+	}
+}
+
+func TestKeyringEndpoint_Put(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Add the metadata of a key whose key material the server doesn't hold
+	key := mock.RootKey()
+	require.NoError(s1.fsm.State().UpsertRootKeyMeta(1000, key.Meta))
+
+	put := func(key *structs.RootKey) error {
+		req := &structs.KeyringPutRootKeyRequest{
+			Key:          key,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		return msgpackrpc.CallWithCodec(codec, "Keyring.Put", req, &resp)
+	}
+
+	// Unknown keys are refused
+	err := put(mock.RootKey())
+	require.Error(err)
+	require.Contains(err.Error(), "unknown root key")
+
+	// Key material that doesn't match the public key is refused
+	forged := mock.RootKey()
+	forged.Meta = key.Meta.Copy()
+	err = put(forged)
+	require.Error(err)
+	require.Contains(err.Error(), "doesn't match its public key")
+	require.False(s1.encrypter.HasKey(key.Meta.KeyID))
+
+	// The key material is stored with the metadata of the state store
+	sent := key.Copy()
+	sent.Meta.Active = false
+	require.NoError(put(sent))
+	stored, err := s1.encrypter.GetKey(key.Meta.KeyID)
+	require.NoError(err)
+	require.Equal(key.Key, stored.Key)
+	require.Equal(key.EncryptionKey, stored.EncryptionKey)
+	require.True(stored.Meta.Active)
+
+	// Keys the server holds are never replaced
+	forged.Meta.Active = false
+	require.NoError(put(forged))
+	stored, err = s1.encrypter.GetKey(key.Meta.KeyID)
+	require.NoError(err)
+	require.Equal(key.Key, stored.Key)
+}
+
+func TestKeyring_ReplicateRootKeys_NoTLS(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+
+	// The follower persists the replicated key in its keystore
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+	s2 := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+		c.DevMode = false
+		c.DataDir = dir
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	var active *structs.RootKeyMeta
+	testutil.WaitForResult(func() (bool, error) {
+		var err error
+		active, err = s1.fsm.State().ActiveRootKeyMeta(nil)
+		return active != nil, err
+	}, func(err error) {
+		t.Fatalf("no active root key: %v", err)
+	})
+
+	// Both servers end up holding the key material
+	for _, s := range []*Server{s1, s2} {
+		s := s
+		testutil.WaitForResult(func() (bool, error) {
+			return s.encrypter.HasKey(active.KeyID), nil
+		}, func(err error) {
+			t.Fatalf("server %s is missing root key %s", s.config.NodeName, active.KeyID)
+		})
+	}
+	k1, err := s1.encrypter.GetKey(active.KeyID)
+	require.NoError(err)
+	k2, err := s2.encrypter.GetKey(active.KeyID)
+	require.NoError(err)
+	require.Equal(k1.Key, k2.Key)
+	require.Equal(k1.EncryptionKey, k2.EncryptionKey)
+
+	ks, err := newKeystore(filepath.Join(dir, keystoreDir))
+	require.NoError(err)
+	keys, err := ks.LoadAll()
+	require.NoError(err)
+	require.Len(keys, 1)
+	require.Equal(k1.Key, keys[0].Key)
+}
+
+func TestKeyring_ReplicateRootKeys(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	const (
+		cafile  = "../helper/tlsutil/testdata/ca.pem"
+		foocert = "../helper/tlsutil/testdata/nomad-foo.pem"
+		fookey  = "../helper/tlsutil/testdata/nomad-foo-key.pem"
+	)
+	tlsConfig := &config.TLSConfig{
+		EnableRPC:            true,
+		VerifyServerHostname: true,
+		CAFile:               cafile,
+		CertFile:             foocert,
+		KeyFile:              fookey,
+	}
+
+	// The key material is only copied between servers over mutual TLS
+	s1, _ := TestACLServer(t, func(c *Config) {
+		c.Region = "regionFoo"
+		c.AuthoritativeRegion = "regionFoo"
+		c.TLSConfig = tlsConfig
+	})
+	defer s1.Shutdown()
+
+	// The follower persists the replicated key in its keystore
+	dir := tmpDir(t)
+	defer os.RemoveAll(dir)
+	s2, _ := TestACLServer(t, func(c *Config) {
+		c.Region = "regionFoo"
+		c.AuthoritativeRegion = "regionFoo"
+		c.TLSConfig = tlsConfig
+		c.DevDisableBootstrap = true
+		c.DevMode = false
+		c.DataDir = dir
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	var active *structs.RootKeyMeta
+	testutil.WaitForResult(func() (bool, error) {
+		var err error
+		active, err = s1.fsm.State().ActiveRootKeyMeta(nil)
+		return active != nil, err
+	}, func(err error) {
+		t.Fatalf("no active root key: %v", err)
+	})
+
+	// Both servers end up holding the key material, which isn't in the state
+	for _, s := range []*Server{s1, s2} {
+		s := s
+		testutil.WaitForResult(func() (bool, error) {
+			return s.encrypter.HasKey(active.KeyID), nil
+		}, func(err error) {
+			t.Fatalf("server %s is missing root key %s", s.config.NodeName, active.KeyID)
+		})
+	}
+	k1, err := s1.encrypter.GetKey(active.KeyID)
+	require.NoError(err)
+	k2, err := s2.encrypter.GetKey(active.KeyID)
+	require.NoError(err)
+	require.Equal(k1.Key, k2.Key)
+
+	ks, err := newKeystore(filepath.Join(dir, keystoreDir))
+	require.NoError(err)
+	keys, err := ks.LoadAll()
+	require.NoError(err)
+	require.Len(keys, 1)
+	require.Equal(k1.Key, keys[0].Key)
+}
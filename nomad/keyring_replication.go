@@ -0,0 +1,170 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// rootKeyReplicationRetry is how long to wait before fetching the key
+	// material of root keys again after a failure
+	rootKeyReplicationRetry = 5 * time.Second
+)
+
+// replicateRootKeys runs on every server and fetches the key material of the
+// root keys in the state store that the server is missing from the other
+// servers. The key material isn't replicated through Raft so that it never
+// appears in the Raft log or in snapshots.
+func (s *Server) replicateRootKeys() {
+	for {
+		state := s.fsm.State()
+		ws := memdb.NewWatchSet()
+		ws.Add(state.AbandonCh())
+
+		iter, err := state.RootKeyMetas(ws)
+		if err != nil {
+			s.logger.Printf("[ERR] nomad.keyring: failed to list root keys: %v", err)
+		}
+
+		missing := err != nil
+		for iter != nil {
+			raw := iter.Next()
+			if raw == nil {
+				break
+			}
+			meta := raw.(*structs.RootKeyMeta)
+			if s.encrypter.HasKey(meta.KeyID) {
+				continue
+			}
+			if err := s.fetchRootKey(meta.KeyID); err != nil {
+				s.logger.Printf("[WARN] nomad.keyring: failed to fetch root key %s: %v", meta.KeyID, err)
+				missing = true
+			}
+		}
+
+		if missing {
+			select {
+			case <-time.After(rootKeyReplicationRetry):
+				continue
+			case <-s.shutdownCh:
+				return
+			}
+		}
+
+		// Wait for new root keys
+		ws.Add(s.shutdownCh)
+		ws.Watch(nil)
+
+		select {
+		case <-s.shutdownCh:
+			return
+		default:
+		}
+	}
+}
+
+// fetchRootKey fetches the key material of the root key from the other
+// servers of the region, starting with the leader, and stores it in the
+// keystore. The servers only return the key material over mutual TLS, and
+// otherwise send it to this server with Keyring.Put.
+func (s *Server) fetchRootKey(keyID string) error {
+	s.peerLock.RLock()
+	var servers []*serverParts
+	if leader := s.localPeers[s.raft.Leader()]; leader != nil {
+		servers = append(servers, leader)
+	}
+	for _, server := range s.peers[s.config.Region] {
+		if len(servers) != 0 && server.ID == servers[0].ID {
+			continue
+		}
+		servers = append(servers, server)
+	}
+	s.peerLock.RUnlock()
+
+	meta, err := s.fsm.State().RootKeyMetaByID(nil, keyID)
+	if err != nil {
+		return err
+	}
+	if meta == nil {
+		return fmt.Errorf("unknown root key %q", keyID)
+	}
+
+	args := structs.KeyringGetRootKeyRequest{
+		KeyID:    keyID,
+		ServerID: s.config.NodeID,
+		QueryOptions: structs.QueryOptions{
+			Region: s.config.Region,
+		},
+	}
+
+	err = fmt.Errorf("no other server is known")
+	for _, server := range servers {
+		if server.ID == s.config.NodeID {
+			continue
+		}
+
+		var reply structs.KeyringGetRootKeyResponse
+		if err = s.forwardServer(server, "Keyring.Get", &args, &reply); err != nil {
+			continue
+		}
+
+		// Without TLS the key material was sent with Keyring.Put
+		if reply.Key == nil {
+			if s.encrypter.HasKey(keyID) {
+				return nil
+			}
+			err = fmt.Errorf("server %s didn't send root key %s", server.Name, keyID)
+			continue
+		}
+		if reply.Key.Meta == nil || reply.Key.Meta.KeyID != keyID {
+			err = fmt.Errorf("server %s returned the wrong root key", server.Name)
+			continue
+		}
+		if err = checkRootKey(reply.Key, meta); err != nil {
+			continue
+		}
+		return s.encrypter.AddKey(reply.Key)
+	}
+	return err
+}
+
+// sendRootKey sends the key material of the root key to the server of the
+// region with the given node ID. It is used to copy the key material between
+// servers when RPC doesn't use TLS: the server is reached at the address it
+// advertises, so the key material only goes to that server, whichever
+// connection asked for it.
+func (s *Server) sendRootKey(serverID, keyID string) error {
+	if serverID == "" || serverID == s.config.NodeID {
+		return structs.ErrPermissionDenied
+	}
+
+	var server *serverParts
+	s.peerLock.RLock()
+	for _, p := range s.peers[s.config.Region] {
+		if p.ID == serverID {
+			server = p
+			break
+		}
+	}
+	s.peerLock.RUnlock()
+	if server == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	key, err := s.encrypter.GetKey(keyID)
+	if err != nil {
+		return err
+	}
+
+	args := structs.KeyringPutRootKeyRequest{
+		Key: key,
+		WriteRequest: structs.WriteRequest{
+			Region: s.config.Region,
+		},
+	}
+	var reply structs.GenericResponse
+	return s.forwardServer(server, "Keyring.Put", &args, &reply)
+}
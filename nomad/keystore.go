@@ -0,0 +1,142 @@
+package nomad
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// keystoreDir is the directory in the server's data directory that
+	// holds the key material of the root keys
+	keystoreDir = "keystore"
+
+	// keystoreKEKFile is the file holding the key-encryption key of the
+	// keystore. It is generated by each server and never leaves it.
+	keystoreKEKFile = "root.kek"
+
+	// keystoreKeyExt is the extension of the files holding root keys
+	keystoreKeyExt = ".nks.json"
+
+	// keystoreKEKSize is the size of the AES-256 key-encryption key
+	keystoreKEKSize = 32
+)
+
+// keystore persists the key material of the root keys on the server's disk.
+// Each key is encrypted with a key-encryption key that is generated by the
+// server and is neither replicated nor included in snapshots.
+type keystore struct {
+	dir string
+	kek cipher.AEAD
+}
+
+// newKeystore opens the keystore in dir, creating it and its key-encryption
+// key if they don't exist.
+func newKeystore(dir string) (*keystore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create keystore: %v", err)
+	}
+
+	kekPath := filepath.Join(dir, keystoreKEKFile)
+	kek, err := ioutil.ReadFile(kekPath)
+	if os.IsNotExist(err) {
+		kek = make([]byte, keystoreKEKSize)
+		if _, err := rand.Read(kek); err != nil {
+			return nil, err
+		}
+		if err := writeFileAtomic(kekPath, kek); err != nil {
+			return nil, fmt.Errorf("failed to write keystore key: %v", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read keystore key: %v", err)
+	}
+	if len(kek) != keystoreKEKSize {
+		return nil, fmt.Errorf("keystore key %s is malformed", kekPath)
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &keystore{dir: dir, kek: aead}, nil
+}
+
+// Save encrypts the root key and writes it to the keystore.
+func (k *keystore) Save(key *structs.RootKey) error {
+	plaintext, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, k.kek.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	ciphertext := k.kek.Seal(nonce, nonce, plaintext, []byte(key.Meta.KeyID))
+	return writeFileAtomic(filepath.Join(k.dir, key.Meta.KeyID+keystoreKeyExt), ciphertext)
+}
+
+// LoadAll returns the root keys in the keystore.
+func (k *keystore) LoadAll() ([]*structs.RootKey, error) {
+	files, err := ioutil.ReadDir(k.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*structs.RootKey
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), keystoreKeyExt) {
+			continue
+		}
+		keyID := strings.TrimSuffix(f.Name(), keystoreKeyExt)
+
+		ciphertext, err := ioutil.ReadFile(filepath.Join(k.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		size := k.kek.NonceSize()
+		if len(ciphertext) < size {
+			return nil, fmt.Errorf("root key %s in keystore is malformed", keyID)
+		}
+		plaintext, err := k.kek.Open(nil, ciphertext[:size], ciphertext[size:], []byte(keyID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt root key %s: %v", keyID, err)
+		}
+
+		key := new(structs.RootKey)
+		if err := json.Unmarshal(plaintext, key); err != nil {
+			return nil, fmt.Errorf("failed to decode root key %s: %v", keyID, err)
+		}
+		if key.Meta == nil || key.Meta.KeyID != keyID {
+			return nil, fmt.Errorf("root key %s in keystore is malformed", keyID)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// writeFileAtomic writes the data to a temporary file readable only by the
+// owner and renames it to path.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package nomad
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystore_SaveLoad(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	dir, err := ioutil.TempDir("", "nomad-keystore")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	ks, err := newKeystore(dir)
+	require.NoError(err)
+
+	key := mock.RootKey()
+	require.NoError(ks.Save(key))
+
+	// The key material isn't written in plaintext
+	raw, err := ioutil.ReadFile(filepath.Join(dir, key.Meta.KeyID+keystoreKeyExt))
+	require.NoError(err)
+	require.NotContains(string(raw), key.Meta.KeyID)
+
+	// Reopening the keystore reuses the key-encryption key
+	ks, err = newKeystore(dir)
+	require.NoError(err)
+	keys, err := ks.LoadAll()
+	require.NoError(err)
+	require.Len(keys, 1)
+	require.Equal(key.Key, keys[0].Key)
+	require.Equal(key.Meta.KeyID, keys[0].Meta.KeyID)
+
+	// A keystore with another key-encryption key can't read the keys
+	require.NoError(os.Remove(filepath.Join(dir, keystoreKEKFile)))
+	ks, err = newKeystore(dir)
+	require.NoError(err)
+	_, err = ks.LoadAll()
+	require.Error(err)
+}
@@ -42,6 +42,8 @@ const (
 
 var minAutopilotVersion = version.Must(version.NewVersion("0.8.0"))
 
+var minSchedulerConfigVersion = version.Must(version.NewVersion("0.9.0"))
+
 // monitorLeadership is used to monitor if we acquire or lose our role
 // as the leader in the Raft cluster. There is some work the leader is
 // expected to do, so we must react to changes
@@ -91,6 +93,21 @@ func (s *Server) leaderLoop(stopCh chan struct{}) {
 	var reconcileCh chan serf.Member
 	establishedLeader := false
 
+	// The routines started when establishing leadership are stopped along
+	// with the leader loop, or when leadership is reasserted
+	leaderStopCh, stopLeader := newLeaderStopCh(stopCh)
+	defer stopLeader()
+
+	// Revoke the leadership when the loop ends if it was established
+	defer func() {
+		if !establishedLeader {
+			return
+		}
+		if err := s.revokeLeadership(); err != nil {
+			s.logger.Printf("[ERR] nomad: failed to revoke leadership: %v", err)
+		}
+	}()
+
 RECONCILE:
 	// Setup a reconciliation timer
 	reconcileCh = nil
@@ -107,11 +124,13 @@ RECONCILE:
 
 	// Check if we need to handle initial leadership actions
 	if !establishedLeader {
-		if err := s.establishLeadership(stopCh); err != nil {
+		if err := s.establishLeadership(leaderStopCh); err != nil {
 			s.logger.Printf("[ERR] nomad: failed to establish leadership: %v", err)
 
 			// Immediately revoke leadership since we didn't successfully
 			// establish leadership.
+			stopLeader()
+			leaderStopCh, stopLeader = newLeaderStopCh(stopCh)
 			if err := s.revokeLeadership(); err != nil {
 				s.logger.Printf("[ERR] nomad: failed to revoke leadership: %v", err)
 			}
@@ -120,11 +139,6 @@ RECONCILE:
 		}
 
 		establishedLeader = true
-		defer func() {
-			if err := s.revokeLeadership(); err != nil {
-				s.logger.Printf("[ERR] nomad: failed to revoke leadership: %v", err)
-			}
-		}()
 	}
 
 	// Reconcile any missing data
@@ -158,10 +172,59 @@ WAIT:
 			goto RECONCILE
 		case member := <-reconcileCh:
 			s.reconcileMember(member)
+		case errCh := <-s.reassertLeaderCh:
+			// Leadership was never established if the initial attempt
+			// failed, so there is nothing to reassert
+			if !establishedLeader {
+				errCh <- fmt.Errorf("leadership has not been established")
+				continue
+			}
+
+			// Refresh the leader state from the content of the state
+			// store, such as after a snapshot restore
+			stopLeader()
+			leaderStopCh, stopLeader = newLeaderStopCh(stopCh)
+			if err := s.revokeLeadership(); err != nil {
+				s.logger.Printf("[ERR] nomad: failed to revoke leadership: %v", err)
+			}
+			err := s.establishLeadership(leaderStopCh)
+			errCh <- err
+			if err != nil {
+				s.logger.Printf("[ERR] nomad: failed to re-establish leadership: %v", err)
+				stopLeader()
+				leaderStopCh, stopLeader = newLeaderStopCh(stopCh)
+				if err := s.revokeLeadership(); err != nil {
+					s.logger.Printf("[ERR] nomad: failed to revoke leadership: %v", err)
+				}
+
+				// Retry establishing the leadership on the next reconcile
+				establishedLeader = false
+			}
+			goto RECONCILE
 		}
 	}
 }
 
+// newLeaderStopCh returns a channel closed once the stop channel of the
+// leader loop is closed or the returned function is called, whichever comes
+// first, to stop the routines started when establishing leadership.
+func newLeaderStopCh(stopCh chan struct{}) (chan struct{}, func()) {
+	ch := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() { close(ch) })
+	}
+
+	go func() {
+		select {
+		case <-stopCh:
+			stop()
+		case <-ch:
+		}
+	}()
+	return ch, stop
+}
+
 // establishLeadership is invoked once we become leader and are able
 // to invoke an initial barrier. The barrier is used to ensure any
 // previously inflight transactions have been committed and that our
@@ -187,6 +250,15 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	s.getOrCreateAutopilotConfig()
 	s.autopilot.Start()
 
+	// Initialize scheduler configuration
+	s.getOrCreateSchedulerConfig()
+
+	// Create the root key used to sign workload identities. Plans can't be
+	// applied without it.
+	if _, err := s.getOrCreateRootKey(); err != nil {
+		return err
+	}
+
 	// Enable the plan queue, since we are now the leader
 	s.planQueue.SetEnabled(true)
 
@@ -263,10 +335,18 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 		return fmt.Errorf("unable to reconcile job summaries: %v", err)
 	}
 
+	// Start replication of the namespaces and quota specifications if we are
+	// not the authoritative region.
+	if s.config.Region != s.config.AuthoritativeRegion {
+		go s.replicateNamespaces(stopCh)
+		go s.replicateQuotaSpecs(stopCh)
+	}
+
 	// Start replication of ACLs and Policies if they are enabled,
 	// and we are not the authoritative region.
 	if s.config.ACLEnabled && s.config.Region != s.config.AuthoritativeRegion {
 		go s.replicateACLPolicies(stopCh)
+		go s.replicateACLRoles(stopCh)
 		go s.replicateACLTokens(stopCh)
 	}
 
@@ -439,6 +519,14 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	deploymentGC := time.NewTicker(s.config.DeploymentGCInterval)
 	defer deploymentGC.Stop()
 
+	// Expired ACL tokens only need to be collected when ACLs are enabled
+	var aclTokenGCCh <-chan time.Time
+	if s.config.ACLEnabled {
+		aclTokenGC := time.NewTicker(s.config.ACLTokenExpirationGCInterval)
+		defer aclTokenGC.Stop()
+		aclTokenGCCh = aclTokenGC.C
+	}
+
 	// getLatest grabs the latest index from the state store. It returns true if
 	// the index was retrieved successfully.
 	getLatest := func() (uint64, bool) {
@@ -470,6 +558,10 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := getLatest(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobDeploymentGC, index))
 			}
+		case <-aclTokenGCCh:
+			if index, ok := getLatest(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobACLTokenExpiredGC, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -860,6 +952,12 @@ func (s *Server) addRaftPeer(m serf.Member, parts *serverParts) error {
 			s.logger.Printf("[ERR] nomad: failed to add raft peer: %v", err)
 			return err
 		}
+	case minRaftProtocol == 2 && parts.RaftVersion >= 3 && parts.NonVoter:
+		addFuture := s.raft.AddNonvoter(raft.ServerID(parts.ID), raft.ServerAddress(addr), 0, 0)
+		if err := addFuture.Error(); err != nil {
+			s.logger.Printf("[ERR] nomad: failed to add raft peer: %v", err)
+			return err
+		}
 	case minRaftProtocol == 2 && parts.RaftVersion >= 3:
 		addFuture := s.raft.AddVoter(raft.ServerID(parts.ID), raft.ServerAddress(addr), 0, 0)
 		if err := addFuture.Error(); err != nil {
@@ -924,6 +1022,291 @@ func (s *Server) removeRaftPeer(m serf.Member, parts *serverParts) error {
 	return nil
 }
 
+// replicateNamespaces is used to replicate namespaces from the authoritative
+// region to this region.
+func (s *Server) replicateNamespaces(stopCh chan struct{}) {
+	req := structs.NamespaceListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Printf("[DEBUG] nomad: starting namespace replication from authoritative region %q", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of namespaces
+			var resp structs.NamespaceListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"Namespace.ListNamespaces", &req, &resp)
+			if err != nil {
+				s.logger.Printf("[ERR] nomad: failed to fetch namespaces from authoritative region: %v", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffNamespaces(s.State(), req.MinQueryIndex, resp.Namespaces)
+
+			// Delete namespaces that should not exist
+			if len(delete) > 0 {
+				args := &structs.NamespaceDeleteRequest{
+					Namespaces: delete,
+				}
+				_, _, err := s.raftApply(structs.NamespaceDeleteRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to delete namespaces: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated namespaces
+			var fetched []*structs.Namespace
+			if len(update) > 0 {
+				req := structs.NamespaceSetRequest{
+					Namespaces: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.NamespaceSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"Namespace.GetNamespaces", &req, &reply); err != nil {
+					s.logger.Printf("[ERR] nomad: failed to fetch namespaces from authoritative region: %v", err)
+					goto ERR_WAIT
+				}
+				for _, namespace := range reply.Namespaces {
+					fetched = append(fetched, namespace)
+				}
+			}
+
+			// Update local namespaces
+			if len(fetched) > 0 {
+				args := &structs.NamespaceUpsertRequest{
+					Namespaces: fetched,
+				}
+				_, _, err := s.raftApply(structs.NamespaceUpsertRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to update namespaces: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffNamespaces is used to perform a two-way diff between the local
+// namespaces and the remote namespaces to determine which namespaces need to
+// be deleted or updated. The default namespace exists in every region and is
+// never deleted.
+func diffNamespaces(state *state.StateStore, minIndex uint64, remoteList []*structs.Namespace) (delete []string, update []string) {
+	// Construct a set of the local and remote namespaces
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local namespaces
+	iter, err := state.Namespaces(nil)
+	if err != nil {
+		panic("failed to iterate local namespaces")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		namespace := raw.(*structs.Namespace)
+		local[namespace.Name] = namespace.Hash
+	}
+
+	// Iterate over the remote namespaces
+	for _, rns := range remoteList {
+		remote[rns.Name] = struct{}{}
+
+		// Check if the namespace is missing locally
+		if localHash, ok := local[rns.Name]; !ok {
+			update = append(update, rns.Name)
+
+			// Check if the namespace is newer remotely and there is a hash
+			// mis-match.
+		} else if rns.ModifyIndex > minIndex && !bytes.Equal(localHash, rns.Hash) {
+			update = append(update, rns.Name)
+		}
+	}
+
+	// Check if namespace should be deleted
+	for lns := range local {
+		if _, ok := remote[lns]; !ok && lns != structs.DefaultNamespace {
+			delete = append(delete, lns)
+		}
+	}
+	return
+}
+
+// replicateQuotaSpecs is used to replicate quota specifications from the
+// authoritative region to this region.
+func (s *Server) replicateQuotaSpecs(stopCh chan struct{}) {
+	req := structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Printf("[DEBUG] nomad: starting quota replication from authoritative region %q", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of quota specifications
+			var resp structs.QuotaSpecListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"Quota.ListQuotaSpecs", &req, &resp)
+			if err != nil {
+				s.logger.Printf("[ERR] nomad: failed to fetch quotas from authoritative region: %v", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffQuotaSpecs(s.State(), req.MinQueryIndex, resp.Quotas)
+
+			// Delete quota specifications that should not exist
+			if len(delete) > 0 {
+				args := &structs.QuotaSpecDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.QuotaSpecDeleteRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to delete quotas: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated quota specifications
+			var fetched []*structs.QuotaSpec
+			if len(update) > 0 {
+				req := structs.QuotaSpecSetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.QuotaSpecSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"Quota.GetQuotaSpecs", &req, &reply); err != nil {
+					s.logger.Printf("[ERR] nomad: failed to fetch quotas from authoritative region: %v", err)
+					goto ERR_WAIT
+				}
+				for _, spec := range reply.Quotas {
+					fetched = append(fetched, spec)
+				}
+			}
+
+			// Update local quota specifications
+			if len(fetched) > 0 {
+				args := &structs.QuotaSpecUpsertRequest{
+					Quotas: fetched,
+				}
+				_, _, err := s.raftApply(structs.QuotaSpecUpsertRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to update quotas: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffQuotaSpecs is used to perform a two-way diff between the local quota
+// specifications and the remote ones to determine which need to be deleted or
+// updated.
+func diffQuotaSpecs(state *state.StateStore, minIndex uint64, remoteList []*structs.QuotaSpec) (delete []string, update []string) {
+	// Construct a set of the local and remote quota specifications
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local quota specifications
+	iter, err := state.QuotaSpecs(nil)
+	if err != nil {
+		panic("failed to iterate local quotas")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		spec := raw.(*structs.QuotaSpec)
+		local[spec.Name] = spec.Hash
+	}
+
+	// Iterate over the remote quota specifications
+	for _, rspec := range remoteList {
+		remote[rspec.Name] = struct{}{}
+
+		// Check if the quota specification is missing locally
+		if localHash, ok := local[rspec.Name]; !ok {
+			update = append(update, rspec.Name)
+
+			// Check if the quota specification is newer remotely and there
+			// is a hash mis-match.
+		} else if rspec.ModifyIndex > minIndex && !bytes.Equal(localHash, rspec.Hash) {
+			update = append(update, rspec.Name)
+		}
+	}
+
+	// Check if quota specification should be deleted
+	for lspec := range local {
+		if _, ok := remote[lspec]; !ok {
+			delete = append(delete, lspec)
+		}
+	}
+	return
+}
+
 // replicateACLPolicies is used to replicate ACL policies from
 // the authoritative region to this region.
 func (s *Server) replicateACLPolicies(stopCh chan struct{}) {
@@ -1065,6 +1448,147 @@ func diffACLPolicies(state *state.StateStore, minIndex uint64, remoteList []*str
 	return
 }
 
+// replicateACLRoles is used to replicate ACL roles from
+// the authoritative region to this region.
+func (s *Server) replicateACLRoles(stopCh chan struct{}) {
+	req := structs.ACLRoleListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:     s.config.AuthoritativeRegion,
+			AllowStale: true,
+		},
+	}
+	limiter := rate.NewLimiter(replicationRateLimit, int(replicationRateLimit))
+	s.logger.Printf("[DEBUG] nomad: starting ACL role replication from authoritative region %q", req.Region)
+
+START:
+	for {
+		select {
+		case <-stopCh:
+			return
+		default:
+			// Rate limit how often we attempt replication
+			limiter.Wait(context.Background())
+
+			// Fetch the list of roles
+			var resp structs.ACLRoleListResponse
+			req.AuthToken = s.ReplicationToken()
+			err := s.forwardRegion(s.config.AuthoritativeRegion,
+				"ACL.ListRoles", &req, &resp)
+			if err != nil {
+				s.logger.Printf("[ERR] nomad: failed to fetch roles from authoritative region: %v", err)
+				goto ERR_WAIT
+			}
+
+			// Perform a two-way diff
+			delete, update := diffACLRoles(s.State(), req.MinQueryIndex, resp.Roles)
+
+			// Delete roles that should not exist
+			if len(delete) > 0 {
+				args := &structs.ACLRoleDeleteRequest{
+					Names: delete,
+				}
+				_, _, err := s.raftApply(structs.ACLRoleDeleteRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to delete roles: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Fetch any outdated roles
+			var fetched []*structs.ACLRole
+			if len(update) > 0 {
+				req := structs.ACLRoleSetRequest{
+					Names: update,
+					QueryOptions: structs.QueryOptions{
+						Region:        s.config.AuthoritativeRegion,
+						AuthToken:     s.ReplicationToken(),
+						AllowStale:    true,
+						MinQueryIndex: resp.Index - 1,
+					},
+				}
+				var reply structs.ACLRoleSetResponse
+				if err := s.forwardRegion(s.config.AuthoritativeRegion,
+					"ACL.GetRoles", &req, &reply); err != nil {
+					s.logger.Printf("[ERR] nomad: failed to fetch roles from authoritative region: %v", err)
+					goto ERR_WAIT
+				}
+				for _, role := range reply.Roles {
+					fetched = append(fetched, role)
+				}
+			}
+
+			// Update local roles
+			if len(fetched) > 0 {
+				args := &structs.ACLRoleUpsertRequest{
+					Roles: fetched,
+				}
+				_, _, err := s.raftApply(structs.ACLRoleUpsertRequestType, args)
+				if err != nil {
+					s.logger.Printf("[ERR] nomad: failed to update roles: %v", err)
+					goto ERR_WAIT
+				}
+			}
+
+			// Update the minimum query index, blocks until there
+			// is a change.
+			req.MinQueryIndex = resp.Index
+		}
+	}
+
+ERR_WAIT:
+	select {
+	case <-time.After(s.config.ReplicationBackoff):
+		goto START
+	case <-stopCh:
+		return
+	}
+}
+
+// diffACLRoles is used to perform a two-way diff between the local
+// roles and the remote roles to determine which roles need to
+// be deleted or updated.
+func diffACLRoles(state *state.StateStore, minIndex uint64, remoteList []*structs.ACLRoleListStub) (delete []string, update []string) {
+	// Construct a set of the local and remote roles
+	local := make(map[string][]byte)
+	remote := make(map[string]struct{})
+
+	// Add all the local roles
+	iter, err := state.ACLRoles(nil)
+	if err != nil {
+		panic("failed to iterate local roles")
+	}
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		role := raw.(*structs.ACLRole)
+		local[role.Name] = role.Hash
+	}
+
+	// Iterate over the remote roles
+	for _, rr := range remoteList {
+		remote[rr.Name] = struct{}{}
+
+		// Check if the role is missing locally
+		if localHash, ok := local[rr.Name]; !ok {
+			update = append(update, rr.Name)
+
+			// Check if role is newer remotely and there is a hash mis-match.
+		} else if rr.ModifyIndex > minIndex && !bytes.Equal(localHash, rr.Hash) {
+			update = append(update, rr.Name)
+		}
+	}
+
+	// Check if role should be deleted
+	for lr := range local {
+		if _, ok := remote[lr]; !ok {
+			delete = append(delete, lr)
+		}
+	}
+	return
+}
+
 // replicateACLTokens is used to replicate global ACL tokens from
 // the authoritative region to this region.
 func (s *Server) replicateACLTokens(stopCh chan struct{}) {
@@ -1233,3 +1757,72 @@ func (s *Server) getOrCreateAutopilotConfig() *structs.AutopilotConfig {
 
 	return config
 }
+
+// getOrCreateSchedulerConfig is used to get the scheduler config, initializing it if necessary
+func (s *Server) getOrCreateSchedulerConfig() *structs.SchedulerConfiguration {
+	state := s.fsm.State()
+	_, config, err := state.SchedulerConfig()
+	if err != nil {
+		s.logger.Printf("[ERR] nomad: failed to get scheduler config: %v", err)
+		return nil
+	}
+	if config != nil {
+		return config
+	}
+
+	if !ServersMeetMinimumVersion(s.Members(), minSchedulerConfigVersion) {
+		s.logger.Printf("[WARN] nomad: can't initialize scheduler config until all servers are >= %s", minSchedulerConfigVersion.String())
+		return nil
+	}
+
+	config = &s.config.DefaultSchedulerConfig
+	req := structs.SchedulerSetConfigRequest{Config: *config}
+	if _, _, err = s.raftApply(structs.SchedulerConfigRequestType, req); err != nil {
+		s.logger.Printf("[ERR] nomad: failed to initialize scheduler config: %v", err)
+		return nil
+	}
+
+	return config
+}
+
+// getOrCreateRootKey returns the metadata of the active root key, creating
+// the key if the cluster doesn't have one yet
+func (s *Server) getOrCreateRootKey() (*structs.RootKeyMeta, error) {
+	meta, err := s.fsm.State().ActiveRootKeyMeta(nil)
+	if err != nil {
+		s.logger.Printf("[ERR] nomad: failed to get root key: %v", err)
+		return nil, err
+	}
+
+	if meta != nil {
+		// The key may not have been replicated to this server yet. A new key
+		// isn't created if it can't be fetched, since the variables it
+		// encrypted would become unreadable: the key material is fetched
+		// again by replicateRootKeys until it is found.
+		if !s.encrypter.HasKey(meta.KeyID) {
+			if err := s.fetchRootKey(meta.KeyID); err != nil {
+				s.logger.Printf("[WARN] nomad: active root key %s is not available yet: %v", meta.KeyID, err)
+			}
+		}
+		return meta, nil
+	}
+
+	key, err := generateRootKey()
+	if err != nil {
+		s.logger.Printf("[ERR] nomad: failed to generate root key: %v", err)
+		return nil, err
+	}
+	if err := s.encrypter.AddKey(key); err != nil {
+		s.logger.Printf("[ERR] nomad: failed to store root key: %v", err)
+		return nil, err
+	}
+
+	req := structs.RootKeyUpsertRequest{RootKeyMeta: key.Meta}
+	if _, _, err = s.raftApply(structs.RootKeyUpsertRequestType, req); err != nil {
+		s.logger.Printf("[ERR] nomad: failed to initialize root key: %v", err)
+		return nil, err
+	}
+
+	s.logger.Printf("[INFO] nomad: created root key %s", key.Meta.KeyID)
+	return key.Meta, nil
+}
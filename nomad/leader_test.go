@@ -1,9 +1,11 @@
 package nomad
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -555,6 +557,13 @@ func TestLeader_ReapFailedEval(t *testing.T) {
 	defer s1.Shutdown()
 	testutil.WaitForLeader(t, s1.RPC)
 
+	// The broker is enabled once the root key is created
+	testutil.WaitForResult(func() (bool, error) {
+		return s1.evalBroker.Enabled(), nil
+	}, func(err error) {
+		t.Fatalf("should have finished establish leader loop")
+	})
+
 	// Wait for a periodic dispatch
 	eval := mock.Eval()
 	s1.evalBroker.Enqueue(eval)
@@ -674,6 +683,52 @@ func TestLeader_RestoreVaultAccessors(t *testing.T) {
 	}
 }
 
+// failingVaultClient fails to revoke tokens while fail is set
+type failingVaultClient struct {
+	TestVaultClient
+	fail int32
+}
+
+func (v *failingVaultClient) RevokeTokens(ctx context.Context, accessors []*structs.VaultAccessor, committed bool) error {
+	if atomic.LoadInt32(&v.fail) == 1 {
+		return errors.New("failed to revoke tokens")
+	}
+	return v.TestVaultClient.RevokeTokens(ctx, accessors, committed)
+}
+
+func TestLeader_ReassertLeadership_Failure(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+		c.ReconcileInterval = 100 * time.Millisecond
+	})
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Insert a vault accessor that should be revoked and fail revoking it,
+	// so that establishing the leadership fails
+	va := mock.VaultAccessor()
+	require.NoError(s1.fsm.State().UpsertVaultAccessor(100, []*structs.VaultAccessor{va}))
+	tvc := &failingVaultClient{fail: 1}
+	s1.vault = tvc
+
+	errCh := make(chan error, 1)
+	s1.reassertLeaderCh <- errCh
+	require.Error(<-errCh)
+
+	// The leadership is established again once it can be
+	atomic.StoreInt32(&tvc.fail, 0)
+	testutil.WaitForResult(func() (bool, error) {
+		if !s1.evalBroker.Enabled() || !s1.planQueue.Enabled() {
+			return false, fmt.Errorf("leadership not established")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+}
+
 func TestLeader_ReplicateACLPolicies(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, func(c *Config) {
@@ -742,6 +797,218 @@ func TestLeader_DiffACLPolicies(t *testing.T) {
 	assert.Equal(t, []string{p3.Name, p4.Name}, update)
 }
 
+func TestLeader_ReplicateNamespaces(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+	})
+	defer s1.Shutdown()
+	s2, _ := TestACLServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+		c.ReplicationBackoff = 20 * time.Millisecond
+		c.ReplicationToken = root.SecretID
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Write a namespace to the authoritative region
+	ns1 := mock.Namespace()
+	if err := s1.State().UpsertNamespaces(100, []*structs.Namespace{ns1}); err != nil {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Wait for the namespace to replicate
+	testutil.WaitForResult(func() (bool, error) {
+		state := s2.State()
+		out, err := state.NamespaceByName(nil, ns1.Name)
+		return out != nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate namespace")
+	})
+}
+
+func TestLeader_DiffNamespaces(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	ns3 := mock.Namespace()
+	assert.Nil(t, state.UpsertNamespaces(100, []*structs.Namespace{ns1, ns2, ns3}))
+
+	// Simulate a remote list
+	rns2 := ns2.Copy()
+	rns2.ModifyIndex = 50 // Ignored, same index
+	rns3 := ns3.Copy()
+	rns3.ModifyIndex = 100 // Updated, higher index
+	rns3.Hash = []byte{0, 1, 2, 3}
+	ns4 := mock.Namespace()
+	remoteList := []*structs.Namespace{
+		rns2,
+		rns3,
+		ns4,
+	}
+	delete, update := diffNamespaces(state, 50, remoteList)
+
+	// ns1 does not exist on the remote side, should delete. The default
+	// namespace is never deleted.
+	assert.Equal(t, []string{ns1.Name}, delete)
+
+	// ns2 is un-modified - ignore. ns3 modified, ns4 new.
+	assert.Equal(t, []string{ns3.Name, ns4.Name}, update)
+}
+
+func TestLeader_ReplicateQuotaSpecs(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+	})
+	defer s1.Shutdown()
+	s2, _ := TestACLServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+		c.ReplicationBackoff = 20 * time.Millisecond
+		c.ReplicationToken = root.SecretID
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Write a quota specification to the authoritative region
+	qs1 := mock.QuotaSpec()
+	qs1.Limits[0].Region = "region2"
+	qs1.SetHash()
+	if err := s1.State().UpsertQuotaSpecs(100, []*structs.QuotaSpec{qs1}); err != nil {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Wait for the quota specification to replicate
+	testutil.WaitForResult(func() (bool, error) {
+		state := s2.State()
+		out, err := state.QuotaSpecByName(nil, qs1.Name)
+		return out != nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate quota")
+	})
+
+	// The usage is tracked against the limit of the region
+	usage, err := s2.State().QuotaUsageByName(nil, qs1.Name)
+	assert.Nil(t, err)
+	assert.Len(t, usage.Used, 1)
+}
+
+func TestLeader_DiffQuotaSpecs(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	qs3 := mock.QuotaSpec()
+	assert.Nil(t, state.UpsertQuotaSpecs(100, []*structs.QuotaSpec{qs1, qs2, qs3}))
+
+	// Simulate a remote list
+	rqs2 := qs2.Copy()
+	rqs2.ModifyIndex = 50 // Ignored, same index
+	rqs3 := qs3.Copy()
+	rqs3.ModifyIndex = 100 // Updated, higher index
+	rqs3.Hash = []byte{0, 1, 2, 3}
+	qs4 := mock.QuotaSpec()
+	remoteList := []*structs.QuotaSpec{
+		rqs2,
+		rqs3,
+		qs4,
+	}
+	delete, update := diffQuotaSpecs(state, 50, remoteList)
+
+	// qs1 does not exist on the remote side, should delete
+	assert.Equal(t, []string{qs1.Name}, delete)
+
+	// qs2 is un-modified - ignore. qs3 modified, qs4 new.
+	assert.Equal(t, []string{qs3.Name, qs4.Name}, update)
+}
+
+func TestLeader_ReplicateACLRoles(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.Region = "region1"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+	})
+	defer s1.Shutdown()
+	s2, _ := TestACLServer(t, func(c *Config) {
+		c.Region = "region2"
+		c.AuthoritativeRegion = "region1"
+		c.ACLEnabled = true
+		c.ReplicationBackoff = 20 * time.Millisecond
+		c.ReplicationToken = root.SecretID
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	// Write a role to the authoritative region
+	r1 := mock.ACLRole()
+	if err := s1.State().UpsertACLRoles(100, []*structs.ACLRole{r1}); err != nil {
+		t.Fatalf("bad: %v", err)
+	}
+
+	// Wait for the role to replicate
+	testutil.WaitForResult(func() (bool, error) {
+		state := s2.State()
+		out, err := state.ACLRoleByName(nil, r1.Name)
+		return out != nil, err
+	}, func(err error) {
+		t.Fatalf("should replicate role")
+	})
+}
+
+func TestLeader_DiffACLRoles(t *testing.T) {
+	t.Parallel()
+
+	state := state.TestStateStore(t)
+
+	// Populate the local state
+	r1 := mock.ACLRole()
+	r2 := mock.ACLRole()
+	r3 := mock.ACLRole()
+	assert.Nil(t, state.UpsertACLRoles(100, []*structs.ACLRole{r1, r2, r3}))
+
+	// Simulate a remote list
+	r2Stub := r2.Stub()
+	r2Stub.ModifyIndex = 50 // Ignored, same index
+	r3Stub := r3.Stub()
+	r3Stub.ModifyIndex = 100 // Updated, higher index
+	r3Stub.Hash = []byte{0, 1, 2, 3}
+	r4 := mock.ACLRole()
+	remoteList := []*structs.ACLRoleListStub{
+		r2Stub,
+		r3Stub,
+		r4.Stub(),
+	}
+	delete, update := diffACLRoles(state, 50, remoteList)
+
+	// R1 does not exist on the remote side, should delete
+	assert.Equal(t, []string{r1.Name}, delete)
+
+	// R2 is un-modified - ignore. R3 modified, R4 new.
+	assert.Equal(t, []string{r3.Name, r4.Name}, update)
+}
+
 func TestLeader_ReplicateACLTokens(t *testing.T) {
 	t.Parallel()
 	s1, root := TestACLServer(t, func(c *Config) {
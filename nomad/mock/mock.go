@@ -1,6 +1,10 @@
 package mock
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"fmt"
 	"time"
 
@@ -56,6 +60,7 @@ func Node() *structs.Node {
 			"version":  "5.6",
 		},
 		NodeClass:             "linux-medium-pci",
+		NodePool:              structs.NodePoolDefault,
 		Status:                structs.NodeStatusReady,
 		SchedulingEligibility: structs.NodeSchedulingEligible,
 	}
@@ -330,6 +335,15 @@ func SystemJob() *structs.Job {
 	return job
 }
 
+func SysBatchJob() *structs.Job {
+	job := SystemJob()
+	job.ID = fmt.Sprintf("mock-sysbatch-%s", uuid.Generate())
+	job.Type = structs.JobTypeSysBatch
+	job.TaskGroups[0].RestartPolicy.Mode = structs.RestartPolicyModeFail
+	job.Canonicalize()
+	return job
+}
+
 func PeriodicJob() *structs.Job {
 	job := Job()
 	job.Type = structs.JobTypeBatch
@@ -570,6 +584,114 @@ func ACLPolicy() *structs.ACLPolicy {
 	return ap
 }
 
+func Namespace() *structs.Namespace {
+	ns := &structs.Namespace{
+		Name:        fmt.Sprintf("team-%s", uuid.Generate()),
+		Description: "test namespace",
+		CreateIndex: 100,
+		ModifyIndex: 200,
+	}
+	ns.SetHash()
+	return ns
+}
+
+func QuotaSpec() *structs.QuotaSpec {
+	qs := &structs.QuotaSpec{
+		Name:        fmt.Sprintf("quota-%s", uuid.Generate()),
+		Description: "test quota",
+		Limits: []*structs.QuotaLimit{
+			{
+				Region: "global",
+				RegionLimit: &structs.Resources{
+					CPU:      2000,
+					MemoryMB: 2000,
+				},
+			},
+		},
+		CreateIndex: 100,
+		ModifyIndex: 200,
+	}
+	qs.SetHash()
+	return qs
+}
+
+func ACLRole() *structs.ACLRole {
+	role := &structs.ACLRole{
+		Name:        fmt.Sprintf("role-%s", uuid.Generate()),
+		Description: "Super cool role!",
+		Policies:    []string{"foo", "bar"},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	role.SetHash()
+	return role
+}
+
+func ACLAuthMethod() *structs.ACLAuthMethod {
+	method := &structs.ACLAuthMethod{
+		Name:        fmt.Sprintf("method-%s", uuid.Generate()),
+		Type:        structs.ACLAuthMethodTypeJWT,
+		MaxTokenTTL: time.Hour,
+		Config: &structs.ACLAuthMethodConfig{
+			JWKSURL:        "https://example.com/keys",
+			BoundAudiences: []string{"nomad"},
+			ClaimMappings:  map[string]string{"sub": "user"},
+			ListClaimMappings: map[string]string{
+				"groups": "groups",
+			},
+		},
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	method.SetHash()
+	return method
+}
+
+func ACLBindingRule() *structs.ACLBindingRule {
+	rule := &structs.ACLBindingRule{
+		ID:          uuid.Generate(),
+		Description: "Super cool rule!",
+		AuthMethod:  "method",
+		Selector:    `"engineering" in list.groups`,
+		BindType:    structs.ACLBindingRuleBindTypeRole,
+		BindName:    "engineering",
+		CreateIndex: 10,
+		ModifyIndex: 20,
+	}
+	rule.SetHash()
+	return rule
+}
+
+func RootKey() *structs.RootKey {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		panic(err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		panic(err)
+	}
+	encKey := make([]byte, 32)
+	if _, err := rand.Read(encKey); err != nil {
+		panic(err)
+	}
+	return &structs.RootKey{
+		Meta: &structs.RootKeyMeta{
+			KeyID:      uuid.Generate(),
+			Algorithm:  structs.RootKeyAlgorithmES256,
+			PublicKey:  pub,
+			Active:     true,
+			CreateTime: time.Now().UTC().UnixNano(),
+		},
+		Key:           der,
+		EncryptionKey: encKey,
+	}
+}
+
 func ACLToken() *structs.ACLToken {
 	tk := &structs.ACLToken{
 		AccessorID:  uuid.Generate(),
@@ -598,3 +720,48 @@ func ACLManagementToken() *structs.ACLToken {
 		ModifyIndex: 20,
 	}
 }
+
+func Variable() *structs.VariableDecrypted {
+	return &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace: structs.DefaultNamespace,
+			Path:      fmt.Sprintf("app/%s", uuid.Generate()[:8]),
+		},
+		Items: map[string]string{
+			"username": "admin",
+			"password": "hunter2",
+		},
+	}
+}
+
+func ServiceRegistrations() []*structs.ServiceRegistration {
+	return []*structs.ServiceRegistration{
+		{
+			ID:          "_nomad-task-" + uuid.Generate(),
+			ServiceName: "example-cache",
+			Namespace:   structs.DefaultNamespace,
+			NodeID:      uuid.Generate(),
+			Datacenter:  "dc1",
+			JobID:       "example",
+			AllocID:     uuid.Generate(),
+			Tags:        []string{"foo"},
+			Address:     "192.168.10.1",
+			Port:        23000,
+		},
+		{
+			ID:          "_nomad-task-" + uuid.Generate(),
+			ServiceName: "countdash-api",
+			Namespace:   structs.DefaultNamespace,
+			NodeID:      uuid.Generate(),
+			Datacenter:  "dc1",
+			JobID:       "countdash",
+			AllocID:     uuid.Generate(),
+			Tags:        []string{"bar"},
+			Address:     "192.168.200.200",
+			Port:        29000,
+			Checks: []*structs.ServiceRegistrationCheck{
+				{Name: "alive", Type: structs.ServiceCheckTCP, Status: "passing"},
+			},
+		},
+	}
+}
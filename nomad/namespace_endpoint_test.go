@@ -0,0 +1,218 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceEndpoint_UpsertDeleteNamespaces(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the namespaces
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	req := &structs.NamespaceUpsertRequest{
+		Namespaces: []*structs.Namespace{ns1, ns2},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp))
+	require.NotZero(resp.Index)
+
+	out, err := s1.fsm.State().NamespaceByName(nil, ns1.Name)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// Namespaces must have a valid name
+	invalid := mock.Namespace()
+	invalid.Name = "*"
+	req.Namespaces = []*structs.Namespace{invalid}
+	err = msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "invalid name")
+
+	// Writes require a management token
+	token := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1001, "ns-write",
+		mock.NamespacePolicy(structs.DefaultNamespace, "write", nil))
+	req.Namespaces = []*structs.Namespace{mock.Namespace()}
+	req.AuthToken = token.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// The default namespace can't be deleted
+	del := &structs.NamespaceDeleteRequest{
+		Namespaces: []string{structs.DefaultNamespace},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Namespace.DeleteNamespaces", del, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "can not delete default namespace")
+
+	// Delete a namespace
+	del.Namespaces = []string{ns1.Name}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.DeleteNamespaces", del, &resp))
+
+	out, err = s1.fsm.State().NamespaceByName(nil, ns1.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestNamespaceEndpoint_DeleteNamespaces_NonTerminal(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	ns := mock.Namespace()
+	require.NoError(s1.fsm.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	// Register a job in the namespace
+	job := mock.Job()
+	job.Namespace = ns.Name
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: ns.Name,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &regResp))
+
+	// The namespace can't be deleted while the job isn't terminal
+	del := &structs.NamespaceDeleteRequest{
+		Namespaces: []string{ns.Name},
+		WriteRequest: structs.WriteRequest{
+			Region: "global",
+		},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Namespace.DeleteNamespaces", del, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "non-terminal")
+
+	out, err := s1.fsm.State().NamespaceByName(nil, ns.Name)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
+func TestNamespaceEndpoint_ListNamespaces_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	require.NoError(state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+
+	// The management token lists all the namespaces
+	req := &structs.NamespaceListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.NamespaceListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", req, &resp))
+	require.Len(resp.Namespaces, 3)
+	require.EqualValues(1000, resp.Index)
+
+	// Other tokens only list the namespaces they have a capability in
+	token := mock.CreatePolicyAndToken(t, state, 1001, "ns1-read",
+		mock.NamespacePolicy(ns1.Name, "", []string{acl.NamespaceCapabilityReadJob}))
+	req.AuthToken = token.SecretID
+	var resp2 structs.NamespaceListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", req, &resp2))
+	require.Len(resp2.Namespaces, 1)
+	require.Equal(ns1.Name, resp2.Namespaces[0].Name)
+
+	// Lookup by prefix
+	req.AuthToken = root.SecretID
+	req.Prefix = "team-"
+	var resp3 structs.NamespaceListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.ListNamespaces", req, &resp3))
+	require.Len(resp3.Namespaces, 2)
+}
+
+func TestNamespaceEndpoint_GetNamespace_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+	require.NoError(state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+
+	token := mock.CreatePolicyAndToken(t, state, 1001, "ns1-read",
+		mock.NamespacePolicy(ns1.Name, "", []string{acl.NamespaceCapabilityReadJob}))
+
+	req := &structs.NamespaceSpecificRequest{
+		Name: ns1.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.SingleNamespaceResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespace", req, &resp))
+	require.Equal(ns1, resp.Namespace)
+	require.EqualValues(1000, resp.Index)
+
+	// The token has no capability in the other namespace
+	req.Name = ns2.Name
+	err := msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespace", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Missing namespaces aren't found
+	req.Name = "missing"
+	req.AuthToken = root.SecretID
+	var resp2 structs.SingleNamespaceResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespace", req, &resp2))
+	require.Nil(resp2.Namespace)
+
+	// Sets of namespaces require a management token
+	setReq := &structs.NamespaceSetRequest{
+		Namespaces: []string{ns1.Name, ns2.Name},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var setResp structs.NamespaceSetResponse
+	err = msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespaces", setReq, &setResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	setReq.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Namespace.GetNamespaces", setReq, &setResp))
+	require.Len(setResp.Namespaces, 2)
+	require.Equal(ns2, setResp.Namespaces[ns2.Name])
+}
@@ -115,6 +115,17 @@ func (n *Node) Register(args *structs.NodeRegisterRequest, reply *structs.NodeUp
 		return fmt.Errorf("invalid status for node")
 	}
 
+	// Default the node pool for clients that don't set one
+	if args.Node.NodePool == "" {
+		args.Node.NodePool = structs.NodePoolDefault
+	}
+	if args.Node.NodePool == structs.NodePoolAll {
+		return fmt.Errorf("node pool %q is reserved and can't be used by nodes", structs.NodePoolAll)
+	}
+	if err := structs.ValidateNodePool(args.Node.NodePool); err != nil {
+		return err
+	}
+
 	// Default to eligible for scheduling if unset
 	if args.Node.SchedulingEligibility == "" {
 		args.Node.SchedulingEligibility = structs.NodeSchedulingEligible
@@ -811,7 +822,7 @@ func (n *Node) GetAllocs(args *structs.NodeSpecificRequest,
 				reply.Allocs = make([]*structs.Allocation, 0, n)
 				for _, alloc := range allocs {
 					if readNS(alloc.Namespace) {
-						reply.Allocs = append(reply.Allocs, alloc)
+						reply.Allocs = append(reply.Allocs, alloc.Sanitize())
 					}
 
 					// Get the max of all allocs since
@@ -905,6 +916,7 @@ func (n *Node) GetClientAllocs(args *structs.NodeSpecificRequest,
 
 			reply.Allocs = make(map[string]uint64)
 			reply.MigrateTokens = make(map[string]string)
+			reply.SignedIdentities = make(map[string]map[string]string)
 
 			// preferTableIndex is used to determine whether we should build the
 			// response index based on the full table indexes versus the modify
@@ -919,6 +931,9 @@ func (n *Node) GetClientAllocs(args *structs.NodeSpecificRequest,
 
 				for _, alloc := range allocs {
 					reply.Allocs[alloc.ID] = alloc.AllocModifyIndex
+					if len(alloc.SignedIdentities) != 0 {
+						reply.SignedIdentities[alloc.ID] = alloc.SignedIdentities
+					}
 
 					// If the allocation is going to do a migration, create a
 					// migration token so that the client can authenticate with
@@ -1224,6 +1239,20 @@ func (n *Node) createNodeEvals(nodeID string, nodeIndex uint64) ([]string, uint6
 		sysJobs = append(sysJobs, job.(*structs.Job))
 	}
 
+	sysBatchJobsIter, err := snap.JobsByScheduler(ws, structs.JobTypeSysBatch)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find sysbatch jobs for '%s': %v", nodeID, err)
+	}
+
+	for raw := sysBatchJobsIter.Next(); raw != nil; raw = sysBatchJobsIter.Next() {
+		// Periodic and parameterized jobs only run through their children
+		job := raw.(*structs.Job)
+		if job.IsPeriodic() || job.IsParameterized() {
+			continue
+		}
+		sysJobs = append(sysJobs, job)
+	}
+
 	// Fast-path if nothing to do
 	if len(allocs) == 0 && len(sysJobs) == 0 {
 		return nil, 0, nil
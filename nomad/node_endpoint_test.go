@@ -173,6 +173,36 @@ func TestClientEndpoint_Register_NodeConn_Forwarded(t *testing.T) {
 	})
 }
 
+func TestClientEndpoint_Register_NodePool(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Nodes can't be in the "all" pool
+	node := mock.Node()
+	node.NodePool = structs.NodePoolAll
+	req := &structs.NodeRegisterRequest{
+		Node:         node,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "reserved")
+
+	// Nodes without a pool are placed in the default pool
+	node.NodePool = ""
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.Register", req, &resp))
+
+	out, err := s1.fsm.State().NodeByID(nil, node.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(structs.NodePoolDefault, out.NodePool)
+}
+
 func TestClientEndpoint_Register_SecretMismatch(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -1685,6 +1715,42 @@ func TestClientEndpoint_GetClientAllocs(t *testing.T) {
 	})
 }
 
+func TestClientEndpoint_GetClientAllocs_SignedIdentities(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create an alloc with a workload identity on the node
+	node := mock.Node()
+	state := s1.fsm.State()
+	require.NoError(state.UpsertNode(98, node))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.SignedIdentities = map[string]string{"web": "identity"}
+	require.NoError(state.UpsertJobSummary(99, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(100, []*structs.Allocation{alloc}))
+
+	// The node running the alloc receives its identities
+	get := &structs.NodeSpecificRequest{
+		NodeID:       node.ID,
+		SecretID:     node.SecretID,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.NodeClientAllocsResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.GetClientAllocs", get, &resp))
+	require.Equal(map[string]string{"web": "identity"}, resp.SignedIdentities[alloc.ID])
+
+	// The allocations of the node are listed without them
+	var allocsResp structs.NodeAllocsResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Node.GetAllocs", get, &allocsResp))
+	require.Len(allocsResp.Allocs, 1)
+	require.Nil(allocsResp.Allocs[0].SignedIdentities)
+}
+
 func TestClientEndpoint_GetClientAllocs_Blocking(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, nil)
@@ -2354,6 +2420,42 @@ func TestClientEndpoint_CreateNodeEvals(t *testing.T) {
 	}
 }
 
+func TestClientEndpoint_CreateNodeEvals_SysBatch(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Inject a sysbatch job and a periodic sysbatch job
+	state := s1.fsm.State()
+	job := mock.SysBatchJob()
+	require.NoError(state.UpsertJob(1, job))
+
+	periodic := mock.SysBatchJob()
+	periodic.Periodic = &structs.PeriodicConfig{
+		Enabled:  true,
+		SpecType: structs.PeriodicSpecCron,
+		Spec:     "*/30 * * * *",
+	}
+	require.NoError(state.UpsertJob(2, periodic))
+
+	// Only the sysbatch job that isn't periodic is evaluated
+	node := mock.Node()
+	ids, index, err := s1.staticEndpoints.Node.createNodeEvals(node.ID, 1)
+	require.NoError(err)
+	require.NotZero(index)
+	require.Len(ids, 1)
+
+	eval, err := state.EvalByID(nil, ids[0])
+	require.NoError(err)
+	require.NotNil(eval)
+	require.Equal(structs.JobTypeSysBatch, eval.Type)
+	require.Equal(job.ID, eval.JobID)
+	require.Equal(node.ID, eval.NodeID)
+	require.Equal(structs.EvalTriggerNodeUpdate, eval.TriggeredBy)
+}
+
 func TestClientEndpoint_Evaluate(t *testing.T) {
 	t.Parallel()
 	s1 := TestServer(t, func(c *Config) {
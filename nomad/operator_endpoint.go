@@ -1,13 +1,20 @@
 package nomad
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"math/rand"
 	"net"
+	"time"
 
 	"github.com/hashicorp/consul/agent/consul/autopilot"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
 	"github.com/hashicorp/serf/serf"
+	"github.com/ugorji/go/codec"
 )
 
 // Operator endpoint is used to perform low-level operator tasks for Nomad.
@@ -15,6 +22,11 @@ type Operator struct {
 	srv *Server
 }
 
+func (op *Operator) register() {
+	op.srv.streamingRpcs.Register("Operator.SnapshotSave", op.snapshotSave)
+	op.srv.streamingRpcs.Register("Operator.SnapshotRestore", op.snapshotRestore)
+}
+
 // RaftGetConfiguration is used to retrieve the current Raft configuration.
 func (op *Operator) RaftGetConfiguration(args *structs.GenericRequest, reply *structs.RaftConfigurationResponse) error {
 	if done, err := op.srv.forward("Operator.RaftGetConfiguration", args, args, reply); done {
@@ -191,6 +203,63 @@ REMOVE:
 	return nil
 }
 
+// RaftTransferLeadership is used to make the leader step down, so that
+// another healthy voter is elected leader. The Raft library in use can't
+// transfer the leadership directly, so the leader demotes itself to a
+// non-voter, and is promoted back by autopilot on the new leader once it has
+// been stable for the server stabilization time. Until then the cluster has
+// one less voter, so it tolerates one less server failure, and the new leader
+// can't be chosen.
+func (op *Operator) RaftTransferLeadership(args *structs.RaftTransferLeadershipRequest, reply *struct{}) error {
+	if done, err := op.srv.forward("Operator.RaftTransferLeadership", args, args, reply); done {
+		return err
+	}
+
+	// Check management permissions
+	if aclObj, err := op.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Autopilot only promotes non-voters back with Raft protocol 3
+	minRaftProtocol, err := op.srv.autopilot.MinRaftProtocol()
+	if err != nil {
+		return err
+	}
+	if minRaftProtocol < 3 {
+		return fmt.Errorf("transferring leadership requires all servers to use Raft protocol version 3 or higher")
+	}
+
+	// Make sure another healthy voter can take over
+	future := op.srv.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return err
+	}
+	localID := op.srv.config.RaftConfig.LocalID
+	found := false
+	for _, s := range future.Configuration().Servers {
+		if s.ID == localID || s.Suffrage != raft.Voter {
+			continue
+		}
+		if health := op.srv.autopilot.GetServerHealth(string(s.ID)); health != nil && health.Healthy {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("no other healthy voter to transfer leadership to")
+	}
+
+	if err := op.srv.raft.DemoteVoter(localID, 0, 0).Error(); err != nil {
+		op.srv.logger.Printf("[WARN] nomad.operator: Failed to transfer leadership: %v", err)
+		return err
+	}
+
+	op.srv.logger.Printf("[WARN] nomad.operator: Stepped down as leader to transfer leadership")
+	return nil
+}
+
 // AutopilotGetConfiguration is used to retrieve the current Autopilot configuration.
 func (op *Operator) AutopilotGetConfiguration(args *structs.GenericRequest, reply *structs.AutopilotConfig) error {
 	if done, err := op.srv.forward("Operator.AutopilotGetConfiguration", args, args, reply); done {
@@ -252,6 +321,71 @@ func (op *Operator) AutopilotSetConfiguration(args *structs.AutopilotSetConfigRe
 	return nil
 }
 
+// SchedulerGetConfiguration is used to retrieve the current scheduler configuration.
+func (op *Operator) SchedulerGetConfiguration(args *structs.GenericRequest, reply *structs.SchedulerConfiguration) error {
+	if done, err := op.srv.forward("Operator.SchedulerGetConfiguration", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	state := op.srv.fsm.State()
+	_, config, err := state.SchedulerConfig()
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return fmt.Errorf("scheduler config not initialized yet")
+	}
+
+	*reply = *config
+
+	return nil
+}
+
+// SchedulerSetConfiguration is used to set the current scheduler configuration.
+func (op *Operator) SchedulerSetConfiguration(args *structs.SchedulerSetConfigRequest, reply *bool) error {
+	if done, err := op.srv.forward("Operator.SchedulerSetConfiguration", args, args, reply); done {
+		return err
+	}
+
+	// This action requires operator write access.
+	rule, err := op.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if rule != nil && !rule.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if err := args.Config.Validate(); err != nil {
+		return err
+	}
+
+	// Apply the update
+	resp, _, err := op.srv.raftApply(structs.SchedulerConfigRequestType, args)
+	if err != nil {
+		op.srv.logger.Printf("[ERR] nomad.operator: Apply failed: %v", err)
+		return err
+	}
+	if respErr, ok := resp.(error); ok {
+		return respErr
+	}
+
+	// Check if the return type is a bool.
+	if respBool, ok := resp.(bool); ok {
+		*reply = respBool
+	}
+	return nil
+}
+
 // ServerHealth is used to get the current health of the servers.
 func (op *Operator) ServerHealth(args *structs.GenericRequest, reply *autopilot.OperatorHealthReply) error {
 	// This must be sent to the leader, so we fix the args since we are
@@ -283,3 +417,242 @@ func (op *Operator) ServerHealth(args *structs.GenericRequest, reply *autopilot.
 
 	return nil
 }
+
+// snapshotSave streams a snapshot of the state of the cluster, taken by the
+// leader unless stale reads are allowed. The SnapshotSaveResponse is sent
+// first, followed by the snapshot archive.
+func (op *Operator) snapshotSave(conn io.ReadWriteCloser) {
+	defer conn.Close()
+
+	var args structs.SnapshotSaveRequest
+	var reply structs.SnapshotSaveResponse
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	handleFailure := func(code int, err error) {
+		encoder.Encode(&structs.SnapshotSaveResponse{
+			ErrorCode: code,
+			ErrorMsg:  err.Error(),
+		})
+	}
+
+	if err := decoder.Decode(&args); err != nil {
+		handleFailure(500, err)
+		return
+	}
+
+	// Forward to the region or the leader
+	if forwarded, err := op.forwardStreamingRPC(&args, "Operator.SnapshotSave", args, conn); forwarded {
+		if err != nil {
+			handleFailure(500, err)
+		}
+		return
+	}
+
+	// Check management permissions
+	if aclObj, err := op.srv.ResolveToken(args.AuthToken); err != nil {
+		code := 500
+		if err == structs.ErrTokenNotFound {
+			code = 400
+		}
+		handleFailure(code, err)
+		return
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		handleFailure(403, structs.ErrPermissionDenied)
+		return
+	}
+
+	// Take the snapshot and capture its index
+	snap, err := snapshot.New(op.srv.logger, op.srv.raft)
+	if err != nil {
+		handleFailure(500, err)
+		return
+	}
+	defer snap.Close()
+
+	reply.SnapshotChecksum = snap.Checksum()
+	reply.Index = snap.Index()
+	op.srv.setQueryMeta(&reply.QueryMeta)
+
+	if err := encoder.Encode(&reply); err != nil {
+		op.srv.logger.Printf("[ERR] nomad.operator: failed to encode snapshot response: %v", err)
+		return
+	}
+	if _, err := io.Copy(conn, snap); err != nil {
+		op.srv.logger.Printf("[ERR] nomad.operator: failed to stream snapshot: %v", err)
+	}
+}
+
+// snapshotRestore restores the state of the cluster from a snapshot streamed
+// after the SnapshotRestoreRequest, as chunks of StreamErrWrapper ending with
+// an EOF error. The snapshot is verified before being restored by the leader,
+// which then re-establishes leadership from the restored state.
+func (op *Operator) snapshotRestore(conn io.ReadWriteCloser) {
+	defer conn.Close()
+
+	var args structs.SnapshotRestoreRequest
+	var reply structs.SnapshotRestoreResponse
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	handleFailure := func(code int, err error) {
+		encoder.Encode(&structs.SnapshotRestoreResponse{
+			ErrorCode: code,
+			ErrorMsg:  err.Error(),
+		})
+	}
+
+	if err := decoder.Decode(&args); err != nil {
+		handleFailure(500, err)
+		return
+	}
+
+	// Forward to the region or the leader
+	if forwarded, err := op.forwardStreamingRPC(&args, "Operator.SnapshotRestore", args, conn); forwarded {
+		if err != nil {
+			handleFailure(500, err)
+		}
+		return
+	}
+
+	// Check management permissions
+	if aclObj, err := op.srv.ResolveToken(args.AuthToken); err != nil {
+		code := 500
+		if err == structs.ErrTokenNotFound {
+			code = 400
+		}
+		handleFailure(code, err)
+		return
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		handleFailure(403, structs.ErrPermissionDenied)
+		return
+	}
+
+	reader, errCh := decodeStreamOutput(decoder)
+	defer reader.Close()
+	if err := snapshot.Restore(op.srv.logger, reader, op.srv.raft); err != nil {
+		handleFailure(500, fmt.Errorf("failed to restore from snapshot: %v", err))
+		return
+	}
+	if err := <-errCh; err != nil {
+		handleFailure(400, fmt.Errorf("failed to read stream: %v", err))
+		return
+	}
+
+	// Reassert leadership to reload the leader state, such as the eval
+	// broker, from the restored state store
+	timeoutCh := time.After(time.Minute)
+	lerrCh := make(chan error, 1)
+	select {
+	case op.srv.reassertLeaderCh <- lerrCh:
+	case <-timeoutCh:
+		handleFailure(500, fmt.Errorf("timed out waiting to re-run leader actions"))
+		return
+	case <-op.srv.shutdownCh:
+		return
+	}
+
+	select {
+	case err := <-lerrCh:
+		if err != nil {
+			handleFailure(500, err)
+			return
+		}
+	case <-timeoutCh:
+		handleFailure(500, fmt.Errorf("timed out waiting for re-run of leader actions"))
+		return
+	case <-op.srv.shutdownCh:
+		return
+	}
+
+	reply.Index, _ = op.srv.State().LatestIndex()
+	op.srv.setQueryMeta(&reply.QueryMeta)
+	encoder.Encode(&reply)
+}
+
+// forwardStreamingRPC forwards a streaming RPC to a server of the region of
+// the request, or to the leader unless the request allows stale reads. It
+// returns whether the RPC was forwarded, bridging the connections until
+// either side closes.
+func (op *Operator) forwardStreamingRPC(info structs.RPCInfo, method string, args interface{}, conn io.ReadWriteCloser) (bool, error) {
+	var server *serverParts
+	if region := info.RequestRegion(); region != op.srv.Region() {
+		op.srv.peerLock.RLock()
+		servers := op.srv.peers[region]
+		if len(servers) == 0 {
+			op.srv.peerLock.RUnlock()
+			return true, structs.ErrNoRegionPath
+		}
+		server = servers[rand.Intn(len(servers))]
+		op.srv.peerLock.RUnlock()
+	} else if info.IsRead() && info.AllowStaleRead() {
+		return false, nil
+	} else {
+		isLeader, leader := op.srv.getLeader()
+		if isLeader {
+			return false, nil
+		}
+		if leader == nil {
+			return true, structs.ErrNoLeader
+		}
+		server = leader
+	}
+
+	srvConn, err := op.srv.streamingRpc(server, method)
+	if err != nil {
+		return true, err
+	}
+	defer srvConn.Close()
+
+	// Send the request
+	outEncoder := codec.NewEncoder(srvConn, structs.MsgpackHandle)
+	if err := outEncoder.Encode(args); err != nil {
+		return true, err
+	}
+
+	structs.Bridge(conn, srvConn)
+	return true, nil
+}
+
+// decodeStreamOutput returns a reader of the payloads of the StreamErrWrapper
+// chunks read from the decoder, until an EOF error chunk. The returned channel
+// receives the error ending the stream, if any, once done. Closing the reader
+// stops the decoding.
+func decodeStreamOutput(decoder *codec.Decoder) (io.ReadCloser, <-chan error) {
+	pr, pw := io.Pipe()
+	errCh := make(chan error, 1)
+
+	go func() {
+		defer close(errCh)
+
+		for {
+			var wrapper cstructs.StreamErrWrapper
+			if err := decoder.Decode(&wrapper); err != nil {
+				pw.CloseWithError(fmt.Errorf("failed to decode input: %v", err))
+				errCh <- err
+				return
+			}
+
+			if len(wrapper.Payload) != 0 {
+				if _, err := pw.Write(wrapper.Payload); err != nil {
+					pw.CloseWithError(err)
+					errCh <- err
+					return
+				}
+			}
+
+			if errW := wrapper.Error; errW != nil {
+				if errW.Message == io.EOF.Error() {
+					pw.CloseWithError(io.EOF)
+				} else {
+					err := errors.New(errW.Message)
+					pw.CloseWithError(err)
+					errCh <- err
+				}
+				return
+			}
+		}
+	}()
+
+	return pr, errCh
+}
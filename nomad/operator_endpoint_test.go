@@ -1,19 +1,29 @@
 package nomad
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/consul/lib/freeport"
+	"github.com/hashicorp/consul/testutil/retry"
 	"github.com/hashicorp/net-rpc-msgpackrpc"
 	"github.com/hashicorp/nomad/acl"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/snapshot"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
 )
 
 func TestOperator_RaftGetConfiguration(t *testing.T) {
@@ -333,3 +343,350 @@ func TestOperator_RaftRemovePeerByID_ACL(t *testing.T) {
 		assert.Nil(err)
 	}
 }
+
+func TestOperator_RaftTransferLeadership(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	conf := func(c *Config) {
+		c.DevDisableBootstrap = true
+		c.BootstrapExpect = 3
+		c.RaftConfig.ProtocolVersion = 3
+	}
+	s1 := TestServer(t, conf)
+	defer s1.Shutdown()
+	s2 := TestServer(t, conf)
+	defer s2.Shutdown()
+	s3 := TestServer(t, conf)
+	defer s3.Shutdown()
+	servers := []*Server{s1, s2, s3}
+	TestJoin(t, s1, s2, s3)
+
+	for _, s := range servers {
+		retry.Run(t, func(r *retry.R) { r.Check(wantPeers(s, 3)) })
+	}
+
+	// Wait for autopilot to see all the servers healthy
+	var leader *Server
+	retry.Run(t, func(r *retry.R) {
+		leader = nil
+		for _, s := range servers {
+			if s.IsLeader() {
+				leader = s
+			}
+		}
+		if leader == nil {
+			r.Fatal("no leader")
+		}
+		if !leader.autopilot.GetClusterHealth().Healthy {
+			r.Fatal("cluster not healthy")
+		}
+	})
+
+	// Transfer through a follower, which forwards to the leader
+	var follower *Server
+	for _, s := range servers {
+		if s != leader {
+			follower = s
+			break
+		}
+	}
+	codec := rpcClient(t, follower)
+	arg := structs.RaftTransferLeadershipRequest{
+		WriteRequest: structs.WriteRequest{Region: follower.config.Region},
+	}
+	var reply struct{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeadership", &arg, &reply))
+
+	// Another server is elected
+	retry.Run(t, func(r *retry.R) {
+		if leader.IsLeader() {
+			r.Fatal("leader did not step down")
+		}
+		for _, s := range servers {
+			if s != leader && s.IsLeader() {
+				return
+			}
+		}
+		r.Fatal("no new leader")
+	})
+
+	// The former leader is promoted back to a voter
+	for _, s := range servers {
+		retry.Run(t, func(r *retry.R) { r.Check(wantPeers(s, 3)) })
+	}
+}
+
+func TestOperator_RaftTransferLeadership_NoVoter(t *testing.T) {
+	t.Parallel()
+	s1 := TestServer(t, func(c *Config) {
+		c.RaftConfig.ProtocolVersion = 3
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// A single server can't transfer its leadership
+	arg := structs.RaftTransferLeadershipRequest{
+		WriteRequest: structs.WriteRequest{Region: s1.config.Region},
+	}
+	var reply struct{}
+	err := msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeadership", &arg, &reply)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no other healthy voter")
+	require.True(t, s1.IsLeader())
+}
+
+func TestOperator_RaftTransferLeadership_ACL(t *testing.T) {
+	t.Parallel()
+	s1, root := TestACLServer(t, func(c *Config) {
+		c.RaftConfig.ProtocolVersion = 3
+	})
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1001, "test-invalid", mock.NodePolicy(acl.PolicyWrite))
+
+	arg := structs.RaftTransferLeadershipRequest{}
+	arg.Region = s1.config.Region
+	var reply struct{}
+
+	// Try with no token and expect permission denied
+	err := msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeadership", &arg, &reply)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Try with an invalid token and expect permission denied
+	arg.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeadership", &arg, &reply)
+	require.EqualError(t, err, structs.ErrPermissionDenied.Error())
+
+	// A management token gets past the ACLs
+	arg.AuthToken = root.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Operator.RaftTransferLeadership", &arg, &reply)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no other healthy voter")
+}
+
+// snapshotSave saves a snapshot through the given server, returning the
+// response and the snapshot archive
+func snapshotSave(t *testing.T, s *Server, token string) (*structs.SnapshotSaveResponse, []byte) {
+	handler, err := s.StreamingRpcHandler("Operator.SnapshotSave")
+	require.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+	go handler(p2)
+
+	req := &structs.SnapshotSaveRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    s.config.Region,
+			AuthToken: token,
+		},
+	}
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.NoError(t, encoder.Encode(req))
+
+	var resp structs.SnapshotSaveResponse
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	require.NoError(t, decoder.Decode(&resp))
+	if resp.ErrorMsg != "" {
+		return &resp, nil
+	}
+
+	data, err := ioutil.ReadAll(p1)
+	require.NoError(t, err)
+	return &resp, data
+}
+
+// snapshotRestore restores a snapshot through the given server
+func snapshotRestore(t *testing.T, s *Server, token string, data []byte) *structs.SnapshotRestoreResponse {
+	handler, err := s.StreamingRpcHandler("Operator.SnapshotRestore")
+	require.NoError(t, err)
+
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+	go handler(p2)
+
+	req := &structs.SnapshotRestoreRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    s.config.Region,
+			AuthToken: token,
+		},
+	}
+	encoder := codec.NewEncoder(p1, structs.MsgpackHandle)
+	require.NoError(t, encoder.Encode(req))
+
+	// Stream the snapshot in chunks, ending with an EOF
+	go func() {
+		in := bytes.NewReader(data)
+		buf := make([]byte, 1024)
+		for {
+			n, err := in.Read(buf)
+			var wrapper cstructs.StreamErrWrapper
+			wrapper.Payload = buf[:n]
+			if err != nil {
+				wrapper.Error = &cstructs.RpcError{Message: err.Error()}
+			}
+			if encoder.Encode(&wrapper) != nil || err != nil {
+				return
+			}
+		}
+	}()
+
+	var resp structs.SnapshotRestoreResponse
+	decoder := codec.NewDecoder(p1, structs.MsgpackHandle)
+	require.NoError(t, decoder.Decode(&resp))
+	return &resp
+}
+
+func TestOperator_SnapshotSave(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	s2 := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+	})
+	defer s2.Shutdown()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	testutil.WaitForResult(func() (bool, error) {
+		return true, wantPeers(s1, 2)
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// Snapshots can only be taken once a log is applied after the join
+	codec := rpcClient(t, s1)
+	job := mock.Job()
+	regReq := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", regReq, &regResp))
+
+	// Save through the follower, which forwards to the leader
+	follower := s2
+	if s2.IsLeader() {
+		follower = s1
+	}
+	resp, data := snapshotSave(t, follower, "")
+	require.Empty(resp.ErrorMsg)
+	require.NotZero(resp.Index)
+	require.True(resp.KnownLeader)
+
+	sum := sha256.Sum256(data)
+	require.Equal("sha-256="+base64.StdEncoding.EncodeToString(sum[:]), resp.SnapshotChecksum)
+
+	meta, err := snapshot.Verify(bytes.NewReader(data))
+	require.NoError(err)
+	require.Equal(resp.Index, meta.Index)
+}
+
+func TestOperator_SnapshotSave_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Try without a token and expect failure
+	resp, _ := snapshotSave(t, s1, "")
+	require.Equal(403, resp.ErrorCode)
+	require.Equal(structs.ErrPermissionDenied.Error(), resp.ErrorMsg)
+
+	// Try with an unknown token
+	resp, _ = snapshotSave(t, s1, "7df5a0bc-4a4f-2d8e-f3d8-d9f5a5b6ba0c")
+	require.Equal(400, resp.ErrorCode)
+
+	// Try with a management token
+	resp, data := snapshotSave(t, s1, root.SecretID)
+	require.Empty(resp.ErrorMsg)
+	_, err := snapshot.Verify(bytes.NewReader(data))
+	require.NoError(err)
+}
+
+func TestOperator_SnapshotRestore(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	register := func(job *structs.Job) {
+		req := &structs.JobRegisterRequest{
+			Job: job,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+	}
+
+	// Snapshot the state with a first job, then register another one
+	job1 := mock.Job()
+	register(job1)
+	resp, data := snapshotSave(t, s1, "")
+	require.Empty(resp.ErrorMsg)
+
+	job2 := mock.Job()
+	register(job2)
+
+	// Restoring brings the state back to the snapshot
+	restoreResp := snapshotRestore(t, s1, "", data)
+	require.Empty(restoreResp.ErrorMsg)
+	require.NotZero(restoreResp.Index)
+
+	state := s1.fsm.State()
+	out, err := state.JobByID(nil, job1.Namespace, job1.ID)
+	require.NoError(err)
+	require.NotNil(out)
+	out, err = state.JobByID(nil, job2.Namespace, job2.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	// The server is still the leader and accepts writes
+	require.True(s1.IsLeader())
+	require.True(s1.evalBroker.Enabled())
+	register(mock.Job())
+
+	// A corrupted snapshot is rejected, leaving the state untouched
+	restoreResp = snapshotRestore(t, s1, "", data[:len(data)/2])
+	require.Equal(500, restoreResp.ErrorCode)
+	require.Contains(restoreResp.ErrorMsg, "failed to restore from snapshot")
+	out, err = state.JobByID(nil, job1.Namespace, job1.ID)
+	require.NoError(err)
+	require.NotNil(out)
+}
+
+func TestOperator_SnapshotRestore_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	s1, _ := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	token := mock.CreatePolicyAndToken(t, s1.State(), 1001, "operator-write",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+
+	resp := snapshotRestore(t, s1, token.SecretID, []byte("not a snapshot"))
+	require.Equal(403, resp.ErrorCode)
+	require.Equal(structs.ErrPermissionDenied.Error(), resp.ErrorMsg)
+}
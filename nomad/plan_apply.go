@@ -9,6 +9,7 @@ import (
 	"github.com/armon/go-metrics"
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/raft"
@@ -126,6 +127,7 @@ func (s *Server) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 	// are multiple updates per node
 	minUpdates := len(result.NodeUpdate)
 	minUpdates += len(result.NodeAllocation)
+	minUpdates += len(result.NodePreemptions)
 
 	// Setup the update request
 	req := structs.ApplyPlanResultsRequest{
@@ -144,6 +146,30 @@ func (s *Server) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 		req.Alloc = append(req.Alloc, allocList...)
 	}
 
+	// Sign the workload identities of the placed allocations
+	if err := s.signAllocIdentities(plan.Job, result.NodeAllocation); err != nil {
+		return nil, err
+	}
+
+	// Evict the preempted allocations and create evals for their jobs so
+	// that they may be placed elsewhere
+	if len(result.NodePreemptions) > 0 {
+		preemptedJobs := make(map[structs.NamespacedID]struct{})
+		for _, preemptions := range result.NodePreemptions {
+			req.Alloc = append(req.Alloc, preemptions...)
+
+			for _, alloc := range preemptions {
+				preemptedJobs[structs.NamespacedID{ID: alloc.JobID, Namespace: alloc.Namespace}] = struct{}{}
+			}
+		}
+
+		evals, err := preemptionEvals(snap, preemptedJobs)
+		if err != nil {
+			return nil, err
+		}
+		req.PreemptionEvals = evals
+	}
+
 	// Set the time the alloc was applied for the first time. This can be used
 	// to approximate the scheduling time.
 	now := time.Now().UTC().UnixNano()
@@ -170,6 +196,80 @@ func (s *Server) applyPlan(plan *structs.Plan, result *structs.PlanResult, snap
 	return future, nil
 }
 
+// signAllocIdentities signs a workload identity for each task of the
+// allocations that don't have one yet. Allocations updated in place keep
+// their existing identities.
+func (s *Server) signAllocIdentities(job *structs.Job, nodeAllocs map[string][]*structs.Allocation) error {
+	if job == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, allocs := range nodeAllocs {
+		for _, alloc := range allocs {
+			tg := job.LookupTaskGroup(alloc.TaskGroup)
+			if tg == nil {
+				continue
+			}
+
+			// The identities may be shared with the allocation in the
+			// state store, so they are copied before being modified
+			var identities map[string]string
+			for _, task := range tg.Tasks {
+				if _, ok := alloc.SignedIdentities[task.Name]; ok {
+					continue
+				}
+
+				token, err := s.encrypter.SignClaims(identityClaims(alloc, task.Name, now))
+				if err != nil {
+					return fmt.Errorf("failed to sign identity of task %q in alloc %q: %v", task.Name, alloc.ID, err)
+				}
+				if identities == nil {
+					identities = make(map[string]string, len(tg.Tasks))
+					for name, identity := range alloc.SignedIdentities {
+						identities[name] = identity
+					}
+				}
+				identities[task.Name] = token
+			}
+			if identities != nil {
+				alloc.SignedIdentities = identities
+			}
+		}
+	}
+	return nil
+}
+
+// preemptionEvals creates an evaluation for each of the jobs whose
+// allocations are preempted by a plan.
+func preemptionEvals(snap *state.StateSnapshot, jobs map[structs.NamespacedID]struct{}) ([]*structs.Evaluation, error) {
+	ws := memdb.NewWatchSet()
+	evals := make([]*structs.Evaluation, 0, len(jobs))
+	for tuple := range jobs {
+		job, err := snap.JobByID(ws, tuple.Namespace, tuple.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to lookup preempted job %q: %v", tuple.ID, err)
+		}
+
+		// The job may have been deregistered in the meantime
+		if job == nil {
+			continue
+		}
+
+		evals = append(evals, &structs.Evaluation{
+			ID:             uuid.Generate(),
+			Namespace:      job.Namespace,
+			Priority:       job.Priority,
+			Type:           job.Type,
+			TriggeredBy:    structs.EvalTriggerPreemption,
+			JobID:          job.ID,
+			JobModifyIndex: job.ModifyIndex,
+			Status:         structs.EvalStatusPending,
+		})
+	}
+	return evals, nil
+}
+
 // asyncPlanWait is used to apply and respond to a plan async
 func (s *Server) asyncPlanWait(waitCh chan struct{}, future raft.ApplyFuture,
 	result *structs.PlanResult, pending *pendingPlan) {
@@ -222,6 +322,75 @@ func evaluatePlan(pool *EvaluatePool, snap *state.StateSnapshot, plan *structs.P
 	return evaluatePlanPlacements(pool, snap, plan, logger)
 }
 
+// refreshIndex returns the index the scheduler should refresh to as the maximum
+// of the allocation, node and quota tables.
+func refreshIndex(snap *state.StateSnapshot) (uint64, error) {
+	allocIndex, err := snap.Index("allocs")
+	if err != nil {
+		return 0, err
+	}
+	nodeIndex, err := snap.Index("nodes")
+	if err != nil {
+		return 0, err
+	}
+	quotaIndex, err := snap.Index("quota_spec")
+	if err != nil {
+		return 0, err
+	}
+	usageIndex, err := snap.Index("quota_usage")
+	if err != nil {
+		return 0, err
+	}
+	return maxUint64(nodeIndex, allocIndex, quotaIndex, usageIndex), nil
+}
+
+// evaluatePlanQuota returns whether the plan would be over quota. The plan is
+// over quota if it increases the usage of a resource beyond the limit of the
+// quota attached to the namespace of the job in the region.
+func evaluatePlanQuota(snap *state.StateSnapshot, plan *structs.Plan) (bool, error) {
+	if plan.Job == nil {
+		return false, nil
+	}
+
+	// Lookup the quota attached to the namespace of the job
+	ns, err := snap.NamespaceByName(nil, plan.Job.Namespace)
+	if err != nil {
+		return false, err
+	}
+	if ns == nil || ns.Quota == "" {
+		return false, nil
+	}
+	spec, err := snap.QuotaSpecByName(nil, ns.Quota)
+	if err != nil {
+		return false, err
+	}
+	if spec == nil {
+		return false, nil
+	}
+	limit := spec.RegionLimit(snap.Config().Region)
+	if limit == nil {
+		return false, nil
+	}
+
+	usage, err := snap.QuotaUsageByName(nil, spec.Name)
+	if err != nil {
+		return false, err
+	}
+	used := &structs.Resources{}
+	if usage != nil {
+		if u, ok := usage.Used[limit.Key()]; ok {
+			used = u.RegionLimit
+		}
+	}
+
+	delta, err := snap.PlanQuotaDelta(nil, spec.Name, plan)
+	if err != nil {
+		return false, err
+	}
+
+	return len(limit.ExceededBy(used, delta)) != 0, nil
+}
+
 // evaluatePlanPlacements is used to determine what portions of a plan can be
 // applied if any, looking for node over commitment. Returns if there should be
 // a plan application which may be partial or if there was an error
@@ -230,6 +399,7 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 	result := &structs.PlanResult{
 		NodeUpdate:        make(map[string][]*structs.Allocation),
 		NodeAllocation:    make(map[string][]*structs.Allocation),
+		NodePreemptions:   make(map[string][]*structs.Allocation),
 		Deployment:        plan.Deployment.Copy(),
 		DeploymentUpdates: plan.DeploymentUpdates,
 	}
@@ -275,6 +445,7 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 			if plan.AllAtOnce {
 				result.NodeUpdate = nil
 				result.NodeAllocation = nil
+				result.NodePreemptions = nil
 				result.DeploymentUpdates = nil
 				result.Deployment = nil
 				return true
@@ -291,6 +462,9 @@ func evaluatePlanPlacements(pool *EvaluatePool, snap *state.StateSnapshot, plan
 		if nodeAlloc := plan.NodeAllocation[nodeID]; len(nodeAlloc) > 0 {
 			result.NodeAllocation[nodeID] = nodeAlloc
 		}
+		if nodePreemptions := plan.NodePreemptions[nodeID]; len(nodePreemptions) > 0 {
+			result.NodePreemptions[nodeID] = nodePreemptions
+		}
 		return
 	}
 
@@ -429,11 +603,15 @@ func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID stri
 	}
 
 	// Determine the proposed allocation by first removing allocations
-	// that are planned evictions and adding the new allocations.
+	// that are planned evictions or preemptions and adding the new
+	// allocations.
 	var remove []*structs.Allocation
 	if update := plan.NodeUpdate[nodeID]; len(update) > 0 {
 		remove = append(remove, update...)
 	}
+	if preempted := plan.NodePreemptions[nodeID]; len(preempted) > 0 {
+		remove = append(remove, preempted...)
+	}
 	if updated := plan.NodeAllocation[nodeID]; len(updated) > 0 {
 		for _, alloc := range updated {
 			remove = append(remove, alloc)
@@ -442,6 +620,24 @@ func evaluateNodePlan(snap *state.StateSnapshot, plan *structs.Plan, nodeID stri
 	proposed := structs.RemoveAllocs(existingAlloc, remove)
 	proposed = append(proposed, plan.NodeAllocation[nodeID]...)
 
+	// New placements must be in the node pool of their job
+	existing := make(map[string]struct{}, len(existingAlloc))
+	for _, alloc := range existingAlloc {
+		existing[alloc.ID] = struct{}{}
+	}
+	for _, alloc := range plan.NodeAllocation[nodeID] {
+		if _, ok := existing[alloc.ID]; ok {
+			continue
+		}
+		job := alloc.Job
+		if job == nil {
+			job = plan.Job
+		}
+		if job != nil && !node.InNodePool(job.NodePool) {
+			return false, "node is not in the node pool of the job", nil
+		}
+	}
+
 	// Check if these allocations fit
 	fit, reason, _, err := structs.AllocsFit(node, proposed, nil)
 	return fit, reason, err
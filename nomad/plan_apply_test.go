@@ -1,17 +1,20 @@
 package nomad
 
 import (
+	"encoding/pem"
 	"reflect"
 	"testing"
 
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/lib/auth"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/hashicorp/raft"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -227,6 +230,158 @@ func TestPlanApply_applyPlan(t *testing.T) {
 	assert.Equal(index, evalOut.ModifyIndex)
 }
 
+func TestPlanApply_applyPlan_Preemption(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register node
+	node := mock.Node()
+	testRegisterNode(t, s1, node)
+
+	// Register a low priority job with an allocation
+	lowJob := mock.Job()
+	lowJob.Priority = 20
+	require.NoError(s1.State().UpsertJob(1000, lowJob))
+
+	lowAlloc := mock.Alloc()
+	lowAlloc.Job = lowJob
+	lowAlloc.JobID = lowJob.ID
+	lowAlloc.NodeID = node.ID
+	s1.State().UpsertJobSummary(1001, mock.JobSummary(lowAlloc.JobID))
+	require.NoError(s1.State().UpsertAllocs(1002, []*structs.Allocation{lowAlloc}))
+
+	// Create an eval for the preempting job
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.PreemptedAllocations = []string{lowAlloc.ID}
+	s1.State().UpsertJobSummary(1003, mock.JobSummary(alloc.JobID))
+	eval := mock.Eval()
+	eval.JobID = alloc.JobID
+	require.NoError(s1.State().UpsertEvals(1004, []*structs.Evaluation{eval}))
+
+	// Place the allocation and preempt the low priority one
+	plan := &structs.Plan{
+		Job:    alloc.Job,
+		EvalID: eval.ID,
+	}
+	plan.AppendPreemptedAlloc(lowAlloc, alloc.ID)
+	planRes := &structs.PlanResult{
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+		NodePreemptions: plan.NodePreemptions,
+	}
+
+	// Snapshot the state
+	snap, err := s1.State().Snapshot()
+	require.NoError(err)
+
+	// Apply the plan
+	future, err := s1.applyPlan(plan, planRes, snap)
+	require.NoError(err)
+
+	// Verify plan applies cleanly
+	index, err := planWaitFuture(future)
+	require.NoError(err)
+	require.NotEqual(0, index)
+
+	// Lookup the preempted allocation
+	ws := memdb.NewWatchSet()
+	allocOut, err := s1.fsm.State().AllocByID(ws, lowAlloc.ID)
+	require.NoError(err)
+	require.Equal(structs.AllocDesiredStatusEvict, allocOut.DesiredStatus)
+	require.Equal(alloc.ID, allocOut.PreemptedByAllocation)
+	require.NotNil(allocOut.Job)
+
+	// Lookup the follow up eval of the preempted job
+	evals, err := s1.fsm.State().EvalsByJob(ws, lowJob.Namespace, lowJob.ID)
+	require.NoError(err)
+	require.Len(evals, 1)
+	require.Equal(structs.EvalTriggerPreemption, evals[0].TriggeredBy)
+	require.Equal(lowJob.Priority, evals[0].Priority)
+}
+
+func TestPlanApply_applyPlan_SignIdentities(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register node
+	node := mock.Node()
+	testRegisterNode(t, s1, node)
+
+	// Place an allocation
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	s1.State().UpsertJobSummary(1000, mock.JobSummary(alloc.JobID))
+	eval := mock.Eval()
+	eval.JobID = alloc.JobID
+	require.NoError(s1.State().UpsertEvals(1001, []*structs.Evaluation{eval}))
+
+	plan := &structs.Plan{
+		Job:    alloc.Job,
+		EvalID: eval.ID,
+	}
+	planRes := &structs.PlanResult{
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+
+	snap, err := s1.State().Snapshot()
+	require.NoError(err)
+	future, err := s1.applyPlan(plan, planRes, snap)
+	require.NoError(err)
+	_, err = planWaitFuture(future)
+	require.NoError(err)
+
+	allocOut, err := s1.fsm.State().AllocByID(nil, alloc.ID)
+	require.NoError(err)
+	require.Len(allocOut.SignedIdentities, 1)
+	token := allocOut.SignedIdentities["web"]
+	require.NotEmpty(token)
+
+	// The identity is verified by the public half of the active root key
+	key, err := s1.fsm.State().ActiveRootKeyMeta(nil)
+	require.NoError(err)
+	keys, err := auth.NewStaticKeySet([]string{
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: key.PublicKey})),
+	})
+	require.NoError(err)
+
+	claims, err := auth.Verify(token, keys, &auth.Expected{
+		Audiences:          []string{structs.WorkloadIdentityAudience},
+		ExpirationOptional: true,
+	})
+	require.NoError(err)
+	require.Equal(alloc.ID, claims["nomad_allocation_id"])
+	require.Equal(alloc.JobID, claims["nomad_job_id"])
+	require.Equal("web", claims["nomad_task"])
+
+	// Allocations updated in place keep their identity
+	update := allocOut.Copy()
+	planRes = &structs.PlanResult{
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {update},
+		},
+	}
+	snap, err = s1.State().Snapshot()
+	require.NoError(err)
+	future, err = s1.applyPlan(plan, planRes, snap)
+	require.NoError(err)
+	_, err = planWaitFuture(future)
+	require.NoError(err)
+
+	allocOut, err = s1.fsm.State().AllocByID(nil, alloc.ID)
+	require.NoError(err)
+	require.Equal(token, allocOut.SignedIdentities["web"])
+}
+
 func TestPlanApply_EvalPlan_Simple(t *testing.T) {
 	t.Parallel()
 	state := testStateStore(t)
@@ -271,6 +426,66 @@ func TestPlanApply_EvalPlan_Simple(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalPlan_Quota(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+	node := mock.Node()
+	state.UpsertNode(1000, node)
+	node2 := mock.Node()
+	state.UpsertNode(1000, node2)
+
+	// Attach a quota to a namespace and use most of its CPU
+	qs := mock.QuotaSpec()
+	require.NoError(state.UpsertQuotaSpecs(1001, []*structs.QuotaSpec{qs}))
+	ns := mock.Namespace()
+	ns.Quota = qs.Name
+	require.NoError(state.UpsertNamespaces(1002, []*structs.Namespace{ns}))
+
+	existing := mock.Alloc()
+	existing.Namespace = ns.Name
+	existing.Job.Namespace = ns.Name
+	existing.NodeID = node2.ID
+	existing.Resources.CPU = 1500
+	require.NoError(state.UpsertAllocs(1003, []*structs.Allocation{existing}))
+
+	pool := NewEvaluatePool(workerPoolSize, workerPoolBufferSize)
+	defer pool.Shutdown()
+
+	// A placement within the quota is applied
+	alloc := mock.Alloc()
+	alloc.Namespace = ns.Name
+	alloc.Job.Namespace = ns.Name
+	alloc.NodeID = node.ID
+	alloc.Resources = nil
+	plan := &structs.Plan{
+		Job: alloc.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+	snap, _ := state.Snapshot()
+	result, err := evaluatePlan(pool, snap, plan, testlog.Logger(t))
+	require.NoError(err)
+	require.Equal(plan.NodeAllocation, result.NodeAllocation)
+	require.Zero(result.RefreshIndex)
+
+	// A placement exceeding the quota is rejected
+	alloc.TaskResources["web"].CPU = 600
+	result, err = evaluatePlan(pool, snap, plan, testlog.Logger(t))
+	require.NoError(err)
+	require.Empty(result.NodeAllocation)
+	require.EqualValues(1003, result.RefreshIndex)
+
+	// Unless it also stops enough allocations
+	plan.NodeUpdate = map[string][]*structs.Allocation{
+		node2.ID: {existing},
+	}
+	result, err = evaluatePlan(pool, snap, plan, testlog.Logger(t))
+	require.NoError(err)
+	require.Equal(plan.NodeAllocation, result.NodeAllocation)
+}
+
 func TestPlanApply_EvalPlan_Partial(t *testing.T) {
 	t.Parallel()
 	state := testStateStore(t)
@@ -435,6 +650,45 @@ func TestPlanApply_EvalNodePlan_NodeNotReady(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalNodePlan_NodePool(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+	node := mock.Node()
+	node.NodePool = "gpu"
+	state.UpsertNode(1000, node)
+	snap, _ := state.Snapshot()
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	plan := &structs.Plan{
+		Job: alloc.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc},
+		},
+	}
+
+	fit, reason, err := evaluateNodePlan(snap, plan, node.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if fit {
+		t.Fatalf("bad")
+	}
+	if reason != "node is not in the node pool of the job" {
+		t.Fatalf("bad: %q", reason)
+	}
+
+	// Jobs in the "all" pool can be placed on any node
+	alloc.Job.NodePool = structs.NodePoolAll
+	fit, reason, err = evaluateNodePlan(snap, plan, node.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !fit {
+		t.Fatalf("bad: %q", reason)
+	}
+}
+
 func TestPlanApply_EvalNodePlan_NodeDrain(t *testing.T) {
 	t.Parallel()
 	state := testStateStore(t)
@@ -594,6 +848,45 @@ func TestPlanApply_EvalNodePlan_NodeFull_Evict(t *testing.T) {
 	}
 }
 
+func TestPlanApply_EvalNodePlan_NodeFull_Preemption(t *testing.T) {
+	t.Parallel()
+	alloc := mock.Alloc()
+	state := testStateStore(t)
+	node := mock.Node()
+	alloc.NodeID = node.ID
+	node.Resources = alloc.Resources
+	node.Reserved = nil
+	state.UpsertNode(1000, node)
+	state.UpsertAllocs(1001, []*structs.Allocation{alloc})
+	snap, _ := state.Snapshot()
+
+	alloc2 := mock.Alloc()
+	plan := &structs.Plan{
+		Job: alloc2.Job,
+		NodeAllocation: map[string][]*structs.Allocation{
+			node.ID: {alloc2},
+		},
+	}
+
+	// The node is full without the preemption
+	fit, _, err := evaluateNodePlan(snap, plan, node.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if fit {
+		t.Fatalf("bad")
+	}
+
+	plan.AppendPreemptedAlloc(alloc, alloc2.ID)
+	fit, reason, err := evaluateNodePlan(snap, plan, node.ID)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !fit {
+		t.Fatalf("bad: %v", reason)
+	}
+}
+
 func TestPlanApply_EvalNodePlan_NodeFull_AllocEvict(t *testing.T) {
 	t.Parallel()
 	alloc := mock.Alloc()
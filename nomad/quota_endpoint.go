@@ -0,0 +1,408 @@
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Quota endpoint is used for manipulating quota specifications and querying
+// their usage
+type Quota struct {
+	srv *Server
+}
+
+// UpsertQuotaSpecs is used to upsert a set of quota specifications
+func (q *Quota) UpsertQuotaSpecs(args *structs.QuotaSpecUpsertRequest,
+	reply *structs.GenericResponse) error {
+	// Quota specifications are global, so modification requests always flow
+	// to the authoritative region
+	args.Region = q.srv.config.AuthoritativeRegion
+	if done, err := q.srv.forward("Quota.UpsertQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "upsert_quota_specs"}, time.Now())
+
+	// Check quota write permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate there is at least one quota specification
+	if len(args.Quotas) == 0 {
+		return fmt.Errorf("must specify at least one quota specification")
+	}
+
+	// Validate the quota specifications and set the hash
+	for _, spec := range args.Quotas {
+		if err := spec.Validate(); err != nil {
+			return fmt.Errorf("Invalid quota specification %q: %v", spec.Name, err)
+		}
+
+		spec.SetHash()
+	}
+
+	// Update via Raft
+	out, index, err := q.srv.raftApply(structs.QuotaSpecUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteQuotaSpecs is used to delete a set of quota specifications
+func (q *Quota) DeleteQuotaSpecs(args *structs.QuotaSpecDeleteRequest, reply *structs.GenericResponse) error {
+	// Quota specifications are global, so modification requests always flow
+	// to the authoritative region
+	args.Region = q.srv.config.AuthoritativeRegion
+	if done, err := q.srv.forward("Quota.DeleteQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "delete_quota_specs"}, time.Now())
+
+	// Check quota write permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowQuotaWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Validate at least one quota specification
+	if len(args.Names) == 0 {
+		return fmt.Errorf("must specify at least one quota specification to delete")
+	}
+
+	// Update via Raft
+	out, index, err := q.srv.raftApply(structs.QuotaSpecDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Check if there was an error when applying.
+	if err, ok := out.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// ListQuotaSpecs is used to list the quota specifications
+func (q *Quota) ListQuotaSpecs(args *structs.QuotaSpecListRequest, reply *structs.QuotaSpecListResponse) error {
+	if done, err := q.srv.forward("Quota.ListQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "list_quota_specs"}, time.Now())
+
+	// Resolve token to ACL to filter the quota list
+	aclObj, err := q.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Iterate over all the quota specifications
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = s.QuotaSpecsByNamePrefix(ws, prefix)
+			} else {
+				iter, err = s.QuotaSpecs(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			reply.Quotas = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				spec := raw.(*structs.QuotaSpec)
+
+				// Only return quota specifications allowed by acl
+				if ok, err := allowQuotaRead(aclObj, ws, s, spec.Name); err != nil {
+					return err
+				} else if ok {
+					reply.Quotas = append(reply.Quotas, spec)
+				}
+			}
+
+			// Use the last index that affected the quota table
+			index, err := s.Index("quota_spec")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// GetQuotaSpec is used to get a specific quota specification
+func (q *Quota) GetQuotaSpec(args *structs.QuotaSpecificRequest, reply *structs.SingleQuotaSpecResponse) error {
+	if done, err := q.srv.forward("Quota.GetQuotaSpec", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "get_quota_spec"}, time.Now())
+
+	aclObj, err := q.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Check capabilities for the given quota
+			if ok, err := allowQuotaRead(aclObj, ws, s, args.Name); err != nil {
+				return err
+			} else if !ok {
+				return structs.ErrPermissionDenied
+			}
+
+			// Look for the quota specification
+			out, err := s.QuotaSpecByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Quota = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the quota table
+				index, err := s.Index("quota_spec")
+				if err != nil {
+					return err
+				}
+
+				// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+				// We floor the index at one, since realistically the first write must have a higher index.
+				if index == 0 {
+					index = 1
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// GetQuotaSpecs is used to get a set of quota specifications
+func (q *Quota) GetQuotaSpecs(args *structs.QuotaSpecSetRequest, reply *structs.QuotaSpecSetResponse) error {
+	if done, err := q.srv.forward("Quota.GetQuotaSpecs", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "get_quota_specs"}, time.Now())
+
+	// Check management level permissions
+	if aclObj, err := q.srv.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Setup the output
+			reply.Quotas = make(map[string]*structs.QuotaSpec, len(args.Names))
+
+			// Look for the quota specifications
+			for _, name := range args.Names {
+				out, err := s.QuotaSpecByName(ws, name)
+				if err != nil {
+					return err
+				}
+				if out != nil {
+					reply.Quotas[name] = out
+				}
+			}
+
+			// Use the last index that affected the quota table
+			index, err := s.Index("quota_spec")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// ListQuotaUsages is used to list the usages of the quota specifications in
+// the region
+func (q *Quota) ListQuotaUsages(args *structs.QuotaUsageListRequest, reply *structs.QuotaUsageListResponse) error {
+	if done, err := q.srv.forward("Quota.ListQuotaUsages", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "list_quota_usages"}, time.Now())
+
+	// Resolve token to ACL to filter the usage list
+	aclObj, err := q.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Iterate over all the quota usages
+			var err error
+			var iter memdb.ResultIterator
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = s.QuotaUsagesByNamePrefix(ws, prefix)
+			} else {
+				iter, err = s.QuotaUsages(ws)
+			}
+			if err != nil {
+				return err
+			}
+
+			reply.Usages = nil
+			for {
+				raw := iter.Next()
+				if raw == nil {
+					break
+				}
+				usage := raw.(*structs.QuotaUsage)
+
+				// Only return quota usages allowed by acl
+				if ok, err := allowQuotaRead(aclObj, ws, s, usage.Name); err != nil {
+					return err
+				} else if ok {
+					reply.Usages = append(reply.Usages, usage)
+				}
+			}
+
+			// Use the last index that affected the quota usage table
+			index, err := s.Index("quota_usage")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// GetQuotaUsage is used to get the usage of a specific quota specification in
+// the region
+func (q *Quota) GetQuotaUsage(args *structs.QuotaSpecificRequest, reply *structs.SingleQuotaUsageResponse) error {
+	if done, err := q.srv.forward("Quota.GetQuotaUsage", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "quota", "get_quota_usage"}, time.Now())
+
+	aclObj, err := q.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			// Check capabilities for the given quota
+			if ok, err := allowQuotaRead(aclObj, ws, s, args.Name); err != nil {
+				return err
+			} else if !ok {
+				return structs.ErrPermissionDenied
+			}
+
+			// Look for the quota usage
+			out, err := s.QuotaUsageByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			// Setup the output
+			reply.Usage = out
+			if out != nil {
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the quota usage table
+				index, err := s.Index("quota_usage")
+				if err != nil {
+					return err
+				}
+
+				// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+				// We floor the index at one, since realistically the first write must have a higher index.
+				if index == 0 {
+					index = 1
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return q.srv.blockingRPC(&opts)
+}
+
+// allowQuotaRead returns whether the ACL allows reading the quota
+// specification. Besides the quota read capability, access to any namespace
+// attached to the quota allows reading it.
+func allowQuotaRead(aclObj *acl.ACL, ws memdb.WatchSet, s *state.StateStore, quota string) (bool, error) {
+	if aclObj == nil || aclObj.AllowQuotaRead() {
+		return true, nil
+	}
+
+	iter, err := s.NamespacesByQuota(ws, quota)
+	if err != nil {
+		return false, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if aclObj.AllowNamespace(raw.(*structs.Namespace).Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,207 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaEndpoint_UpsertDeleteQuotaSpecs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create the quota specifications
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	req := &structs.QuotaSpecUpsertRequest{
+		Quotas: []*structs.QuotaSpec{qs1, qs2},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+	require.NotZero(resp.Index)
+
+	out, err := s1.fsm.State().QuotaSpecByName(nil, qs1.Name)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// Quota specifications must be valid
+	invalid := mock.QuotaSpec()
+	invalid.Limits[0].Region = ""
+	req.Quotas = []*structs.QuotaSpec{invalid}
+	err = msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "missing region")
+
+	// Writes require the quota write capability
+	token := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1001, "quota-read",
+		mock.QuotaPolicy(acl.PolicyRead))
+	req.Quotas = []*structs.QuotaSpec{mock.QuotaSpec()}
+	req.AuthToken = token.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	token = mock.CreatePolicyAndToken(t, s1.fsm.State(), 1002, "quota-write",
+		mock.QuotaPolicy(acl.PolicyWrite))
+	req.AuthToken = token.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.UpsertQuotaSpecs", req, &resp))
+
+	// Quota specifications used by a namespace can't be deleted
+	ns := mock.Namespace()
+	ns.Quota = qs1.Name
+	require.NoError(s1.fsm.State().UpsertNamespaces(1003, []*structs.Namespace{ns}))
+
+	del := &structs.QuotaSpecDeleteRequest{
+		Names: []string{qs1.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, "Quota.DeleteQuotaSpecs", del, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "is used by namespace")
+
+	// Delete a quota specification
+	del.Names = []string{qs2.Name}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.DeleteQuotaSpecs", del, &resp))
+
+	out, err = s1.fsm.State().QuotaSpecByName(nil, qs2.Name)
+	require.NoError(err)
+	require.Nil(out)
+}
+
+func TestQuotaEndpoint_ListQuotaSpecs_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs1, qs2}))
+	ns := mock.Namespace()
+	ns.Quota = qs1.Name
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{ns}))
+
+	// The management token lists all the quota specifications
+	req := &structs.QuotaSpecListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.QuotaSpecListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", req, &resp))
+	require.Len(resp.Quotas, 2)
+	require.EqualValues(1000, resp.Index)
+
+	// Tokens with a capability in a namespace list the quota attached to it
+	token := mock.CreatePolicyAndToken(t, state, 1002, "ns-read",
+		mock.NamespacePolicy(ns.Name, "", []string{acl.NamespaceCapabilityReadJob}))
+	req.AuthToken = token.SecretID
+	var resp2 structs.QuotaSpecListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", req, &resp2))
+	require.Len(resp2.Quotas, 1)
+	require.Equal(qs1.Name, resp2.Quotas[0].Name)
+
+	// The usages are filtered the same way
+	usageReq := &structs.QuotaUsageListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var usageResp structs.QuotaUsageListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaUsages", usageReq, &usageResp))
+	require.Len(usageResp.Usages, 1)
+	require.Equal(qs1.Name, usageResp.Usages[0].Name)
+
+	// Lookup by prefix
+	req.AuthToken = root.SecretID
+	req.Prefix = qs2.Name[:len(qs2.Name)-2]
+	var resp3 structs.QuotaSpecListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.ListQuotaSpecs", req, &resp3))
+	require.Len(resp3.Quotas, 1)
+	require.Equal(qs2.Name, resp3.Quotas[0].Name)
+}
+
+func TestQuotaEndpoint_GetQuotaSpec_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs1, qs2}))
+
+	token := mock.CreatePolicyAndToken(t, state, 1001, "quota-read",
+		mock.QuotaPolicy(acl.PolicyRead))
+
+	req := &structs.QuotaSpecificRequest{
+		Name: qs1.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var resp structs.SingleQuotaSpecResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpec", req, &resp))
+	require.Equal(qs1, resp.Quota)
+	require.EqualValues(1000, resp.Index)
+
+	var usageResp structs.SingleQuotaUsageResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaUsage", req, &usageResp))
+	require.NotNil(usageResp.Usage)
+	require.Equal(qs1.Name, usageResp.Usage.Name)
+
+	// Tokens without the quota read capability are denied
+	token = mock.CreatePolicyAndToken(t, state, 1002, "ns-read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "read", nil))
+	req.AuthToken = token.SecretID
+	err := msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpec", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Missing quota specifications aren't found
+	req.Name = "missing"
+	req.AuthToken = root.SecretID
+	var resp2 structs.SingleQuotaSpecResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpec", req, &resp2))
+	require.Nil(resp2.Quota)
+
+	// Sets of quota specifications require a management token
+	setReq := &structs.QuotaSpecSetRequest{
+		Names: []string{qs1.Name, qs2.Name},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var setResp structs.QuotaSpecSetResponse
+	err = msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpecs", setReq, &setResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	setReq.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Quota.GetQuotaSpecs", setReq, &setResp))
+	require.Len(setResp.Quotas, 2)
+	require.Equal(qs2, setResp.Quotas[qs2.Name])
+}
@@ -453,6 +453,30 @@ func (s *Server) forwardServer(server *serverParts, method string, args interfac
 	return s.connPool.RPC(s.config.Region, server.Addr, server.MajorVersion, method, args, reply)
 }
 
+// validateServerConn returns an error unless the RPC connection comes from
+// another server of the region. The connection must use mutual TLS with a
+// verified server certificate for the region, so connections are refused
+// when TLS isn't enabled for RPC.
+func (s *Server) validateServerConn(ctx *RPCContext) error {
+	// RPCs made in-process on behalf of the local agent are not from a
+	// server
+	if ctx == nil || ctx.Conn == nil {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ctx.TLS || len(ctx.VerifiedChains) == 0 {
+		return structs.ErrNoServerTLS
+	}
+
+	name := "server." + s.config.Region + ".nomad"
+	for _, chain := range ctx.VerifiedChains {
+		if len(chain) != 0 && chain[0].VerifyHostname(name) == nil {
+			return nil
+		}
+	}
+	return structs.ErrPermissionDenied
+}
+
 // forwardRegion is used to forward an RPC call to a remote region, or fail if no servers
 func (s *Server) forwardRegion(region, method string, args interface{}, reply interface{}) error {
 	// Bail if we can't find any servers
@@ -2,6 +2,8 @@ package nomad
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"net"
 	"net/rpc"
 	"os"
@@ -267,6 +269,44 @@ func TestRPC_streamingRpcConn_badMethod_TLS(t *testing.T) {
 	require.True(structs.IsErrUnknownMethod(err))
 }
 
+func TestRPC_validateServerConn(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, func(c *Config) {
+		c.Region = "regionFoo"
+	})
+	defer s1.Shutdown()
+
+	loadCert := func(certFile, keyFile string) *x509.Certificate {
+		pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+		require.NoError(err)
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		require.NoError(err)
+		return cert
+	}
+	foo := loadCert("../helper/tlsutil/testdata/nomad-foo.pem", "../helper/tlsutil/testdata/nomad-foo-key.pem")
+	bad := loadCert("../helper/tlsutil/testdata/nomad-bad.pem", "../helper/tlsutil/testdata/nomad-bad-key.pem")
+
+	conn, other := net.Pipe()
+	defer conn.Close()
+	defer other.Close()
+
+	// In-process RPCs are refused
+	require.Equal(structs.ErrPermissionDenied, s1.validateServerConn(nil))
+	require.Equal(structs.ErrPermissionDenied, s1.validateServerConn(&RPCContext{}))
+
+	// Connections without mutual TLS are refused, whatever their address
+	require.Equal(structs.ErrNoServerTLS, s1.validateServerConn(&RPCContext{Conn: conn}))
+	require.Equal(structs.ErrNoServerTLS, s1.validateServerConn(&RPCContext{Conn: conn, TLS: true}))
+
+	// Only a server certificate for the region is accepted
+	ctx := &RPCContext{Conn: conn, TLS: true, VerifiedChains: [][]*x509.Certificate{{bad}}}
+	require.Equal(structs.ErrPermissionDenied, s1.validateServerConn(ctx))
+
+	ctx.VerifiedChains = append(ctx.VerifiedChains, []*x509.Certificate{foo})
+	require.NoError(s1.validateServerConn(ctx))
+}
+
 // COMPAT: Remove in 0.10
 // This is a very low level test to assert that the V2 handling works. It is
 // making manual RPC calls since no helpers exist at this point since we are
@@ -1,6 +1,8 @@
 package nomad
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -15,6 +17,10 @@ const (
 	// truncateLimit is the maximum number of matches that will be returned for a
 	// prefix for a specific context
 	truncateLimit = 20
+
+	// fuzzyMinTextLength is the minimum length of the text of a fuzzy search,
+	// below which it would match most objects
+	fuzzyMinTextLength = 2
 )
 
 var (
@@ -26,6 +32,8 @@ var (
 		structs.Nodes,
 		structs.Evals,
 		structs.Deployments,
+		structs.Namespaces,
+		structs.Quotas,
 	}
 )
 
@@ -57,6 +65,10 @@ func (s *Search) getMatches(iter memdb.ResultIterator, prefix string) ([]string,
 			id = raw.(*structs.Node).ID
 		case *structs.Deployment:
 			id = raw.(*structs.Deployment).ID
+		case *structs.Namespace:
+			id = raw.(*structs.Namespace).Name
+		case *structs.QuotaSpec:
+			id = raw.(*structs.QuotaSpec).Name
 		default:
 			matchID, ok := getEnterpriseMatch(raw)
 			if !ok {
@@ -91,15 +103,39 @@ func getResourceIter(context structs.Context, aclObj *acl.ACL, namespace, prefix
 		return state.NodesByIDPrefix(ws, prefix)
 	case structs.Deployments:
 		return state.DeploymentsByIDPrefix(ws, namespace, prefix)
+	case structs.Namespaces:
+		iter, err := state.NamespacesByNamePrefix(ws, prefix)
+		if err != nil {
+			return nil, err
+		}
+		if aclObj == nil {
+			return iter, nil
+		}
+		return memdb.NewFilterIterator(iter, namespaceFilter(aclObj)), nil
+	case structs.Quotas:
+		return state.QuotaSpecsByNamePrefix(ws, prefix)
 	default:
 		return getEnterpriseResourceIter(context, aclObj, namespace, prefix, ws, state)
 	}
 }
 
+// namespaceFilter wraps a namespace iterator to filter out the namespaces
+// the ACL has no capability in.
+func namespaceFilter(aclObj *acl.ACL) func(interface{}) bool {
+	return func(raw interface{}) bool {
+		ns, ok := raw.(*structs.Namespace)
+		if !ok {
+			return true
+		}
+
+		return !aclObj.AllowNamespace(ns.Name)
+	}
+}
+
 // If the length of a prefix is odd, return a subset to the last even character
-// This only applies to UUIDs, jobs are excluded
+// This only applies to UUIDs, jobs, namespaces and quotas are excluded
 func roundUUIDDownIfOdd(prefix string, context structs.Context) string {
-	if context == structs.Jobs {
+	if context == structs.Jobs || context == structs.Namespaces || context == structs.Quotas {
 		return prefix
 	}
 
@@ -190,3 +226,143 @@ func (s *Search) PrefixSearch(args *structs.SearchRequest, reply *structs.Search
 		}}
 	return s.srv.blockingRPC(&opts)
 }
+
+// fuzzyMatch returns the fuzzy match of an object whose name or ID contains
+// the lowercased text, along with the position of the text in the best
+// matching field.
+func fuzzyMatch(raw interface{}, text string) (structs.FuzzyMatch, int, bool) {
+	var match structs.FuzzyMatch
+	var fields []string
+	switch obj := raw.(type) {
+	case *structs.Job:
+		match = structs.FuzzyMatch{ID: obj.Name, Scope: []string{obj.Namespace, obj.ID}}
+		fields = []string{obj.Name, obj.ID}
+	case *structs.Allocation:
+		match = structs.FuzzyMatch{ID: obj.Name, Scope: []string{obj.Namespace, obj.ID}}
+		fields = []string{obj.Name, obj.ID}
+	case *structs.Node:
+		match = structs.FuzzyMatch{ID: obj.Name, Scope: []string{obj.ID}}
+		fields = []string{obj.Name, obj.ID}
+	case *structs.Evaluation:
+		match = structs.FuzzyMatch{ID: obj.ID, Scope: []string{obj.Namespace, obj.ID}}
+		fields = []string{obj.ID}
+	case *structs.Deployment:
+		match = structs.FuzzyMatch{ID: obj.ID, Scope: []string{obj.Namespace, obj.ID}}
+		fields = []string{obj.ID}
+	case *structs.Namespace:
+		match = structs.FuzzyMatch{ID: obj.Name}
+		fields = []string{obj.Name}
+	default:
+		id, ok := getEnterpriseMatch(raw)
+		if !ok {
+			return match, 0, false
+		}
+		match = structs.FuzzyMatch{ID: id, Scope: []string{id}}
+		fields = []string{id}
+	}
+
+	pos := -1
+	for _, field := range fields {
+		i := strings.Index(strings.ToLower(field), text)
+		if i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+	return match, pos, pos >= 0
+}
+
+// getFuzzyMatches returns the fuzzy matches of an iterator, best matches
+// first: those matching at the start of their name or ID, then by name.
+func (s *Search) getFuzzyMatches(iter memdb.ResultIterator, text string) ([]structs.FuzzyMatch, bool) {
+	type scoredMatch struct {
+		match structs.FuzzyMatch
+		pos   int
+	}
+
+	text = strings.ToLower(text)
+	var scored []scoredMatch
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		match, pos, ok := fuzzyMatch(raw, text)
+		if !ok {
+			continue
+		}
+		scored = append(scored, scoredMatch{match, pos})
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].pos != scored[j].pos {
+			return scored[i].pos < scored[j].pos
+		}
+		return scored[i].match.ID < scored[j].match.ID
+	})
+
+	truncated := len(scored) > truncateLimit
+	if truncated {
+		scored = scored[:truncateLimit]
+	}
+
+	matches := make([]structs.FuzzyMatch, 0, len(scored))
+	for _, m := range scored {
+		matches = append(matches, m.match)
+	}
+	return matches, truncated
+}
+
+// FuzzySearch is used to list the jobs, allocations, nodes, evaluations and/or
+// deployments whose name or ID contains the given text.
+func (s *Search) FuzzySearch(args *structs.FuzzySearchRequest, reply *structs.FuzzySearchResponse) error {
+	if done, err := s.srv.forward("Search.FuzzySearch", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "search", "fuzzy_search"}, time.Now())
+
+	if len(args.Text) < fuzzyMinTextLength {
+		return fmt.Errorf("fuzzy search text must be at least %d characters", fuzzyMinTextLength)
+	}
+
+	aclObj, err := s.srv.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	namespace := args.RequestNamespace()
+
+	// Require either node:read or namespace:read-job
+	if !anySearchPerms(aclObj, namespace, args.Context) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryMeta: &reply.QueryMeta,
+		queryOpts: &args.QueryOptions,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			reply.Matches = make(map[structs.Context][]structs.FuzzyMatch)
+			reply.Truncations = make(map[structs.Context]bool)
+			reply.Index = 0
+
+			contexts := searchContexts(aclObj, namespace, args.Context)
+			for _, ctx := range contexts {
+				iter, err := getResourceIter(ctx, aclObj, namespace, "", ws, state)
+				if err != nil {
+					return err
+				}
+
+				matches, isTrunc := s.getFuzzyMatches(iter, args.Text)
+				reply.Matches[ctx] = matches
+				reply.Truncations[ctx] = isTrunc
+
+				index, err := state.Index(contextToIndex(ctx))
+				if err != nil {
+					return err
+				}
+				if index > reply.Index {
+					reply.Index = index
+				}
+			}
+
+			s.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return s.srv.blockingRPC(&opts)
+}
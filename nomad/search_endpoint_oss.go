@@ -19,6 +19,9 @@ var (
 
 // contextToIndex returns the index name to lookup in the state store.
 func contextToIndex(ctx structs.Context) string {
+	if ctx == structs.Quotas {
+		return "quota_spec"
+	}
 	return string(ctx)
 }
 
@@ -42,6 +45,14 @@ func anySearchPerms(aclObj *acl.ACL, namespace string, context structs.Context)
 		return true
 	}
 
+	// Namespaces are filtered down to the ones the token has a capability in
+	if context == structs.Namespaces {
+		return true
+	}
+	if context == structs.Quotas {
+		return aclObj.AllowQuotaRead()
+	}
+
 	nodeRead := aclObj.AllowNodeRead()
 	jobRead := aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob)
 	if !nodeRead && !jobRead {
@@ -96,6 +107,12 @@ func searchContexts(aclObj *acl.ACL, namespace string, context structs.Context)
 			if aclObj.AllowNodeRead() {
 				available = append(available, c)
 			}
+		case structs.Namespaces:
+			available = append(available, c)
+		case structs.Quotas:
+			if aclObj.AllowQuotaRead() {
+				available = append(available, c)
+			}
 		}
 	}
 	return available
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const jobIndex = 1000
@@ -556,6 +557,78 @@ func TestSearch_PrefixSearch_Deployment(t *testing.T) {
 	assert.Equal(uint64(2000), resp.Index)
 }
 
+func TestSearch_PrefixSearch_Namespace(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+	s := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	ns := mock.Namespace()
+	assert.Nil(s.fsm.State().UpsertNamespaces(2000, []*structs.Namespace{ns}))
+
+	prefix := ns.Name[:len(ns.Name)-2]
+
+	req := &structs.SearchRequest{
+		Prefix:  prefix,
+		Context: structs.Namespaces,
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+
+	var resp structs.SearchResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Search.PrefixSearch", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	assert.Equal(1, len(resp.Matches[structs.Namespaces]))
+	assert.Equal(ns.Name, resp.Matches[structs.Namespaces][0])
+	assert.Equal(resp.Truncations[structs.Namespaces], false)
+
+	assert.Equal(uint64(2000), resp.Index)
+}
+
+func TestSearch_PrefixSearch_Quota(t *testing.T) {
+	assert := assert.New(t)
+	t.Parallel()
+	s := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	qs := mock.QuotaSpec()
+	assert.Nil(s.fsm.State().UpsertQuotaSpecs(2000, []*structs.QuotaSpec{qs}))
+
+	prefix := qs.Name[:len(qs.Name)-2]
+
+	req := &structs.SearchRequest{
+		Prefix:  prefix,
+		Context: structs.Quotas,
+		QueryOptions: structs.QueryOptions{
+			Region: "global",
+		},
+	}
+
+	var resp structs.SearchResponse
+	if err := msgpackrpc.CallWithCodec(codec, "Search.PrefixSearch", req, &resp); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	assert.Equal(1, len(resp.Matches[structs.Quotas]))
+	assert.Equal(qs.Name, resp.Matches[structs.Quotas][0])
+	assert.Equal(resp.Truncations[structs.Quotas], false)
+
+	assert.Equal(uint64(2000), resp.Index)
+}
+
 func TestSearch_PrefixSearch_AllContext(t *testing.T) {
 	assert := assert.New(t)
 	t.Parallel()
@@ -756,3 +829,120 @@ func TestSearch_PrefixSearch_MultiRegion(t *testing.T) {
 	assert.Equal(job.ID, resp.Matches[structs.Jobs][0])
 	assert.Equal(uint64(jobIndex), resp.Index)
 }
+
+func TestSearch_FuzzySearch(t *testing.T) {
+	require := require.New(t)
+
+	t.Parallel()
+	s := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	state := s.fsm.State()
+
+	job1 := mock.Job()
+	job1.ID = "my-api"
+	job1.Name = "my-api"
+	job2 := mock.Job()
+	job2.ID = "api"
+	job2.Name = "api"
+	job3 := mock.Job()
+	job3.ID = "web"
+	job3.Name = "web"
+	for i, job := range []*structs.Job{job1, job2, job3} {
+		require.NoError(state.UpsertJob(uint64(1000+i), job))
+	}
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "API",
+		Context: structs.Jobs,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+		},
+	}
+
+	// Matches are case insensitive, best matches first
+	var resp structs.FuzzySearchResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+	require.Equal([]structs.FuzzyMatch{
+		{ID: "api", Scope: []string{structs.DefaultNamespace, "api"}},
+		{ID: "my-api", Scope: []string{structs.DefaultNamespace, "my-api"}},
+	}, resp.Matches[structs.Jobs])
+	require.False(resp.Truncations[structs.Jobs])
+	require.Equal(uint64(1002), resp.Index)
+
+	alloc := mock.Alloc()
+	alloc.JobID = job2.ID
+	alloc.Name = "api.web[0]"
+	require.NoError(state.UpsertJobSummary(1009, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1010, []*structs.Allocation{alloc}))
+
+	// Allocations match by their ID too
+	req.Text = alloc.ID[2:10]
+	req.Context = structs.All
+	resp = structs.FuzzySearchResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+	require.Equal([]structs.FuzzyMatch{
+		{ID: alloc.Name, Scope: []string{alloc.Namespace, alloc.ID}},
+	}, resp.Matches[structs.Allocs])
+	require.Equal(uint64(1010), resp.Index)
+
+	// The text must not be too short
+	req.Text = "a"
+	err := msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp)
+	require.Error(err)
+	require.Contains(err.Error(), "at least 2 characters")
+}
+
+func TestSearch_FuzzySearch_ACL(t *testing.T) {
+	require := require.New(t)
+
+	t.Parallel()
+	s, root := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+
+	defer s.Shutdown()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	state := s.fsm.State()
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1001, node))
+
+	req := &structs.FuzzySearchRequest{
+		Text:    "oo",
+		Context: structs.All,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// Try without a token and expect failure
+	var resp structs.FuzzySearchResponse
+	err := msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Try with a node:read token and expect only nodes
+	nodeToken := mock.CreatePolicyAndToken(t, state, 1003, "test-node", mock.NodePolicy(acl.PolicyRead))
+	req.AuthToken = nodeToken.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+	require.Len(resp.Matches[structs.Nodes], 1)
+	require.Equal(node.Name, resp.Matches[structs.Nodes][0].ID)
+	require.Empty(resp.Matches[structs.Jobs])
+
+	// Try with a management token
+	req.AuthToken = root.SecretID
+	req.Text = "mock-service"
+	resp = structs.FuzzySearchResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Search.FuzzySearch", req, &resp))
+	require.Len(resp.Matches[structs.Jobs], 1)
+	require.Equal(job.ID, resp.Matches[structs.Jobs][0].Scope[1])
+}
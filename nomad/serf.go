@@ -132,6 +132,11 @@ func (s *Server) maybeBootstrap() {
 			s.logger.Printf("[ERR] nomad: peer %v has bootstrap mode. Expect disabled.", member)
 			return
 		}
+
+		// Non-voting servers join the cluster once it is bootstrapped
+		if p.NonVoter {
+			continue
+		}
 		servers = append(servers, *p)
 	}
 
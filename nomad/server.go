@@ -29,6 +29,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
 	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/scheduler"
@@ -162,6 +163,11 @@ type Server struct {
 	// join/leave from the region.
 	reconcileCh chan serf.Member
 
+	// reassertLeaderCh is used to ask the leader loop to re-establish
+	// leadership, such as after restoring a snapshot, replying on the
+	// given channel once done.
+	reassertLeaderCh chan chan error
+
 	// eventCh is used to receive events from the serf cluster
 	eventCh chan serf.Event
 
@@ -169,6 +175,10 @@ type Server struct {
 	// capacity changes.
 	blockedEvals *BlockedEvals
 
+	// eventBroker holds the events of the changes of the state published by
+	// the FSM for the event stream.
+	eventBroker *stream.EventBroker
+
 	// deploymentWatcher is used to watch deployments and their allocations and
 	// make the required calls to continue to transition the deployment.
 	deploymentWatcher *deploymentwatcher.Watcher
@@ -198,6 +208,9 @@ type Server struct {
 	// vault is the client for communicating with Vault.
 	vault VaultClient
 
+	// encrypter signs workload identities with the root keys
+	encrypter *Encrypter
+
 	// Worker used for processing
 	workers []*Worker
 
@@ -227,6 +240,7 @@ type endpoints struct {
 	Status     *Status
 	Node       *Node
 	Job        *Job
+	Namespace  *Namespace
 	Eval       *Eval
 	Plan       *Plan
 	Alloc      *Alloc
@@ -234,15 +248,23 @@ type endpoints struct {
 	Region     *Region
 	Search     *Search
 	Periodic   *Periodic
+	Quota      *Quota
 	System     *System
 	Operator   *Operator
 	ACL        *ACL
+	Variables  *Variables
 	Enterprise *EnterpriseEndpoints
 
+	ServiceRegistration *ServiceRegistration
+
+	// Streaming endpoints
+	Event *Event
+
 	// Client endpoints
 	ClientStats       *ClientStats
 	FileSystem        *FileSystem
 	ClientAllocations *ClientAllocations
+	NodeMeta          *NodeMeta
 }
 
 // NewServer is used to construct a new Nomad server from the
@@ -290,29 +312,44 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, logger *log.Logg
 
 	// Create the server
 	s := &Server{
-		config:        config,
-		consulCatalog: consulCatalog,
-		connPool:      pool.NewPool(config.LogOutput, serverRPCCache, serverMaxStreams, tlsWrap),
-		logger:        logger,
-		tlsWrap:       tlsWrap,
-		rpcServer:     rpc.NewServer(),
-		streamingRpcs: structs.NewStreamingRpcRegistry(),
-		nodeConns:     make(map[string][]*nodeConnState),
-		peers:         make(map[string][]*serverParts),
-		localPeers:    make(map[raft.ServerAddress]*serverParts),
-		reconcileCh:   make(chan serf.Member, 32),
-		eventCh:       make(chan serf.Event, 256),
-		evalBroker:    evalBroker,
-		blockedEvals:  blockedEvals,
-		planQueue:     planQueue,
-		rpcTLS:        incomingTLS,
-		aclCache:      aclCache,
-		shutdownCh:    make(chan struct{}),
+		config:           config,
+		consulCatalog:    consulCatalog,
+		connPool:         pool.NewPool(config.LogOutput, serverRPCCache, serverMaxStreams, tlsWrap),
+		logger:           logger,
+		tlsWrap:          tlsWrap,
+		rpcServer:        rpc.NewServer(),
+		streamingRpcs:    structs.NewStreamingRpcRegistry(),
+		nodeConns:        make(map[string][]*nodeConnState),
+		peers:            make(map[string][]*serverParts),
+		localPeers:       make(map[raft.ServerAddress]*serverParts),
+		reconcileCh:      make(chan serf.Member, 32),
+		reassertLeaderCh: make(chan chan error),
+		eventCh:          make(chan serf.Event, 256),
+		evalBroker:       evalBroker,
+		blockedEvals:     blockedEvals,
+		eventBroker:      stream.NewEventBroker(config.EventBufferSize),
+		planQueue:        planQueue,
+		rpcTLS:           incomingTLS,
+		aclCache:         aclCache,
+		shutdownCh:       make(chan struct{}),
 	}
 
 	// Create the periodic dispatcher for launching periodic jobs.
 	s.periodicDispatcher = NewPeriodicDispatch(s.logger, s)
 
+	// Create the encrypter used to sign workload identities, with the
+	// keystore holding the key material of the root keys
+	keystorePath := ""
+	if !config.DevMode {
+		keystorePath = filepath.Join(config.DataDir, keystoreDir)
+	}
+	s.encrypter, err = NewEncrypter(s, keystorePath)
+	if err != nil {
+		s.Shutdown()
+		s.logger.Printf("[ERR] nomad: failed to setup keystore: %v", err)
+		return nil, fmt.Errorf("Failed to setup keystore: %v", err)
+	}
+
 	// Initialize the stats fetcher that autopilot will use.
 	s.statsFetcher = NewStatsFetcher(logger, s.connPool, s.config.Region)
 
@@ -376,6 +413,9 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, logger *log.Logg
 	// Start ingesting events for Serf
 	go s.serfEventHandler()
 
+	// Fetch the key material of the root keys from the other servers
+	go s.replicateRootKeys()
+
 	// start the RPC listener for the server
 	s.startRPCListener()
 
@@ -896,9 +936,16 @@ func (s *Server) setupDeploymentWatcher() error {
 		apply: s.raftApply,
 	}
 
+	// Create the shim used to continue the rollout of multiregion jobs in
+	// their other regions
+	peerShim := &deploymentWatcherPeerShim{
+		rpc:   s.RPC,
+		token: s.ReplicationToken,
+	}
+
 	// Create the deployment watcher
 	s.deploymentWatcher = deploymentwatcher.NewDeploymentsWatcher(
-		s.logger, raftShim,
+		s.logger, raftShim, peerShim,
 		deploymentwatcher.LimitStateQueriesPerSecond,
 		deploymentwatcher.CrossDeploymentUpdateBatchDuration)
 
@@ -1001,25 +1048,34 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 		s.staticEndpoints.ACL = &ACL{s}
 		s.staticEndpoints.Alloc = &Alloc{s}
 		s.staticEndpoints.Eval = &Eval{s}
-		s.staticEndpoints.Job = &Job{s}
+		s.staticEndpoints.Job = NewJobEndpoints(s)
+		s.staticEndpoints.Namespace = &Namespace{s}
+		s.staticEndpoints.Variables = &Variables{s}
 		s.staticEndpoints.Node = &Node{srv: s} // Add but don't register
 		s.staticEndpoints.Deployment = &Deployment{srv: s}
 		s.staticEndpoints.Operator = &Operator{s}
 		s.staticEndpoints.Periodic = &Periodic{s}
 		s.staticEndpoints.Plan = &Plan{s}
+		s.staticEndpoints.Quota = &Quota{s}
 		s.staticEndpoints.Region = &Region{s}
 		s.staticEndpoints.Status = &Status{s}
 		s.staticEndpoints.System = &System{s}
 		s.staticEndpoints.Search = &Search{s}
+		s.staticEndpoints.ServiceRegistration = &ServiceRegistration{s}
 		s.staticEndpoints.Enterprise = NewEnterpriseEndpoints(s)
 
 		// Client endpoints
 		s.staticEndpoints.ClientStats = &ClientStats{s}
 		s.staticEndpoints.ClientAllocations = &ClientAllocations{s}
+		s.staticEndpoints.ClientAllocations.register()
+		s.staticEndpoints.NodeMeta = &NodeMeta{s}
 
 		// Streaming endpoints
 		s.staticEndpoints.FileSystem = &FileSystem{s}
 		s.staticEndpoints.FileSystem.register()
+		s.staticEndpoints.Event = &Event{s}
+		s.staticEndpoints.Event.register()
+		s.staticEndpoints.Operator.register()
 	}
 
 	// Register the static handlers
@@ -1027,10 +1083,14 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	server.Register(s.staticEndpoints.Alloc)
 	server.Register(s.staticEndpoints.Eval)
 	server.Register(s.staticEndpoints.Job)
+	server.Register(s.staticEndpoints.Namespace)
+	server.Register(s.staticEndpoints.Variables)
+	server.Register(s.staticEndpoints.ServiceRegistration)
 	server.Register(s.staticEndpoints.Deployment)
 	server.Register(s.staticEndpoints.Operator)
 	server.Register(s.staticEndpoints.Periodic)
 	server.Register(s.staticEndpoints.Plan)
+	server.Register(s.staticEndpoints.Quota)
 	server.Register(s.staticEndpoints.Region)
 	server.Register(s.staticEndpoints.Status)
 	server.Register(s.staticEndpoints.System)
@@ -1038,13 +1098,16 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	s.staticEndpoints.Enterprise.Register(server)
 	server.Register(s.staticEndpoints.ClientStats)
 	server.Register(s.staticEndpoints.ClientAllocations)
+	server.Register(s.staticEndpoints.NodeMeta)
 	server.Register(s.staticEndpoints.FileSystem)
 
 	// Create new dynamic endpoints and add them to the RPC server.
 	node := &Node{srv: s, ctx: ctx}
+	keyring := &Keyring{srv: s, ctx: ctx}
 
 	// Register the dynamic endpoints
 	server.Register(node)
+	server.Register(keyring)
 }
 
 // setupRaft is used to setup and initialize Raft
@@ -1060,11 +1123,12 @@ func (s *Server) setupRaft() error {
 
 	// Create the FSM
 	fsmConfig := &FSMConfig{
-		EvalBroker: s.evalBroker,
-		Periodic:   s.periodicDispatcher,
-		Blocked:    s.blockedEvals,
-		LogOutput:  s.config.LogOutput,
-		Region:     s.Region(),
+		EvalBroker:  s.evalBroker,
+		Periodic:    s.periodicDispatcher,
+		Blocked:     s.blockedEvals,
+		EventBroker: s.eventBroker,
+		LogOutput:   s.config.LogOutput,
+		Region:      s.Region(),
 	}
 	var err error
 	s.fsm, err = NewFSM(fsmConfig)
@@ -1097,7 +1161,8 @@ func (s *Server) setupRaft() error {
 		s.raftInmem = store
 		stable = store
 		log = store
-		snap = raft.NewDiscardSnapshotStore()
+		// Keep the snapshots in memory so they can be saved by operators
+		snap = raft.NewInmemSnapshotStore()
 
 	} else {
 		// Create the base raft path
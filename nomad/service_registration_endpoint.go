@@ -0,0 +1,242 @@
+package nomad
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ServiceRegistration endpoint is used to register and discover the instances
+// of the services with the Nomad provider
+type ServiceRegistration struct {
+	srv *Server
+}
+
+// Upsert is used by clients to register or update the service instances of
+// their allocations
+func (s *ServiceRegistration) Upsert(args *structs.ServiceRegistrationUpsertRequest, reply *structs.GenericResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "upsert"}, time.Now())
+
+	// Only clients can register services
+	if aclObj, isNode, err := s.resolveTokenOrNode(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !isNode {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.Services) == 0 {
+		return fmt.Errorf("must specify as least one service")
+	}
+	var mErr multierror.Error
+	for _, service := range args.Services {
+		if err := service.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("service %q invalid: %v", service.ID, err))
+		}
+	}
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	// Update via Raft
+	resp, index, err := s.srv.raftApply(structs.ServiceRegistrationUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := resp.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// DeleteByID is used to remove a service instance
+func (s *ServiceRegistration) DeleteByID(args *structs.ServiceRegistrationDeleteByIDRequest, reply *structs.GenericResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.DeleteByID", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "delete_id"}, time.Now())
+
+	// Check submit-job permissions on the namespace, unless the request comes
+	// from a client
+	ns := args.RequestNamespace()
+	if aclObj, isNode, err := s.resolveTokenOrNode(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !isNode && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.ID == "" {
+		return fmt.Errorf("missing service ID")
+	}
+	existing, err := s.srv.State().GetServiceRegistrationByID(nil, ns, args.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("service registration %q not found", args.ID)
+	}
+
+	// Update via Raft
+	resp, index, err := s.srv.raftApply(structs.ServiceRegistrationDeleteByIDRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := resp.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// List is used to list the services of a namespace
+func (s *ServiceRegistration) List(args *structs.ServiceRegistrationListRequest, reply *structs.ServiceRegistrationListResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "list"}, time.Now())
+
+	// Check read-job permissions on the namespace
+	ns := args.RequestNamespace()
+	if err := s.allowRead(args.AuthToken, ns); err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.GetServiceRegistrationsByNamespace(ws, ns)
+			if err != nil {
+				return err
+			}
+
+			// Group the instances by service, merging their tags
+			tags := make(map[string]map[string]struct{})
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				service := raw.(*structs.ServiceRegistration)
+				if _, ok := tags[service.ServiceName]; !ok {
+					tags[service.ServiceName] = make(map[string]struct{})
+				}
+				for _, tag := range service.Tags {
+					tags[service.ServiceName][tag] = struct{}{}
+				}
+			}
+
+			reply.Services = make([]*structs.ServiceRegistrationListStub, 0, len(tags))
+			for name, set := range tags {
+				stub := &structs.ServiceRegistrationListStub{ServiceName: name}
+				for tag := range set {
+					stub.Tags = append(stub.Tags, tag)
+				}
+				sort.Strings(stub.Tags)
+				reply.Services = append(reply.Services, stub)
+			}
+			sort.Slice(reply.Services, func(i, j int) bool {
+				return reply.Services[i].ServiceName < reply.Services[j].ServiceName
+			})
+
+			return s.setIndex(state, &reply.QueryMeta)
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// GetService is used to list the instances of a service
+func (s *ServiceRegistration) GetService(args *structs.ServiceRegistrationByNameRequest, reply *structs.ServiceRegistrationByNameResponse) error {
+	if done, err := s.srv.forward("ServiceRegistration.GetService", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "service_registration", "get_service"}, time.Now())
+
+	// Check read-job permissions on the namespace
+	ns := args.RequestNamespace()
+	if err := s.allowRead(args.AuthToken, ns); err != nil {
+		return err
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.GetServiceRegistrationsByName(ws, ns, args.ServiceName)
+			if err != nil {
+				return err
+			}
+
+			reply.Services = make([]*structs.ServiceRegistration, 0)
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				reply.Services = append(reply.Services, raw.(*structs.ServiceRegistration))
+			}
+
+			return s.setIndex(state, &reply.QueryMeta)
+		}}
+	return s.srv.blockingRPC(&opts)
+}
+
+// allowRead returns an error unless the secret ID is a token allowed to read
+// the jobs of the namespace or the secret ID of a node
+func (s *ServiceRegistration) allowRead(secretID, ns string) error {
+	aclObj, isNode, err := s.resolveTokenOrNode(secretID)
+	if err != nil {
+		return err
+	}
+	if aclObj != nil && !isNode && !aclObj.AllowNsOp(ns, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+	return nil
+}
+
+// resolveTokenOrNode resolves an ACL token, falling back to looking the
+// secret ID up as a node's since clients register and read services without
+// an ACL token.
+func (s *ServiceRegistration) resolveTokenOrNode(secretID string) (*acl.ACL, bool, error) {
+	aclObj, err := s.srv.ResolveToken(secretID)
+	if err == nil {
+		return aclObj, false, nil
+	}
+	if err != structs.ErrTokenNotFound {
+		return nil, false, err
+	}
+
+	node, stateErr := s.srv.fsm.State().NodeBySecretID(nil, secretID)
+	if stateErr != nil {
+		// Return the original ResolveToken error with this err
+		var merr multierror.Error
+		merr.Errors = append(merr.Errors, err, stateErr)
+		return nil, false, merr.ErrorOrNil()
+	}
+	if node == nil {
+		return nil, false, structs.ErrTokenNotFound
+	}
+	return nil, true, nil
+}
+
+// setIndex sets the query's index to the last index that affected the
+// service registrations table
+func (s *ServiceRegistration) setIndex(state *state.StateStore, meta *structs.QueryMeta) error {
+	index, err := state.Index("service_registrations")
+	if err != nil {
+		return err
+	}
+
+	// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+	// We floor the index at one, since realistically the first write must have a higher index.
+	meta.Index = helper.Uint64Max(1, index)
+	return nil
+}
@@ -0,0 +1,131 @@
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceRegistrationEndpoint_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Register some services
+	services := mock.ServiceRegistrations()
+	services[1].ServiceName = services[0].ServiceName
+	req := &structs.ServiceRegistrationUpsertRequest{
+		Services:     services,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp))
+	require.NotEqual(uint64(0), resp.Index)
+
+	// Invalid registrations are rejected
+	invalid := services[0].Copy()
+	invalid.AllocID = ""
+	req.Services = []*structs.ServiceRegistration{invalid}
+	require.Error(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp))
+
+	// List the services with their merged tags
+	list := &structs.ServiceRegistrationListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.ServiceRegistrationListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", list, &listResp))
+	require.Len(listResp.Services, 1)
+	require.Equal(services[0].ServiceName, listResp.Services[0].ServiceName)
+	require.Equal([]string{"bar", "foo"}, listResp.Services[0].Tags)
+
+	// Get the instances of the service
+	get := &structs.ServiceRegistrationByNameRequest{
+		ServiceName:  services[0].ServiceName,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.ServiceRegistrationByNameResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp))
+	require.Len(getResp.Services, 2)
+
+	// Delete an instance
+	del := &structs.ServiceRegistrationDeleteByIDRequest{
+		ID:           services[0].ID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", del, &delResp))
+	require.Error(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", del, &delResp))
+
+	getResp = structs.ServiceRegistrationByNameResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp))
+	require.Len(getResp.Services, 1)
+	require.Equal(services[1].ID, getResp.Services[0].ID)
+}
+
+func TestServiceRegistrationEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	node := mock.Node()
+	require.NoError(s1.fsm.State().UpsertNode(1000, node))
+
+	// Only clients can register services
+	services := mock.ServiceRegistrations()
+	req := &structs.ServiceRegistrationUpsertRequest{
+		Services:     services,
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: root.SecretID},
+	}
+	var resp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	req.AuthToken = node.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.Upsert", req, &resp))
+
+	// Reads require the read-job capability
+	get := &structs.ServiceRegistrationByNameRequest{
+		ServiceName:  services[0].ServiceName,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.ServiceRegistrationByNameResponse
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	read := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1001, "read",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilityReadJob}))
+	get.AuthToken = read.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.GetService", get, &getResp))
+	require.Len(getResp.Services, 1)
+
+	// Clients can read services with their secret
+	list := &structs.ServiceRegistrationListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: node.SecretID},
+	}
+	var listResp structs.ServiceRegistrationListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.List", list, &listResp))
+	require.Len(listResp.Services, 2)
+
+	// Deletes require the submit-job capability
+	del := &structs.ServiceRegistrationDeleteByIDRequest{
+		ID:           services[0].ID,
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: read.SecretID},
+	}
+	var delResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", del, &delResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	del.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "ServiceRegistration.DeleteByID", del, &delResp))
+}
@@ -0,0 +1,183 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobSubmissionSchema returns the memdb schema for the job submission table
+// which keeps the sources of the tracked job versions.
+func jobSubmissionSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "job_submission",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, JobID, Version)
+				// is uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field:     "JobID",
+							Lowercase: true,
+						},
+
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// UpsertJobSubmission stores the source of the current version of the job.
+// The sources of the versions that are no longer tracked are deleted.
+func (s *StateStore) UpsertJobSubmission(index uint64, sub *structs.JobSubmission) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// COMPAT 0.7: Upgrade old objects that do not have namespaces
+	if sub.Namespace == "" {
+		sub.Namespace = structs.DefaultNamespace
+	}
+
+	existing, err := txn.First("jobs", "id", sub.Namespace, sub.JobID)
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("job %q not found", sub.JobID)
+	}
+	sub.Version = existing.(*structs.Job).Version
+
+	previous, err := txn.First("job_submission", "id", sub.Namespace, sub.JobID, sub.Version)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	if previous != nil {
+		sub.CreateIndex = previous.(*structs.JobSubmission).CreateIndex
+	} else {
+		sub.CreateIndex = index
+	}
+	sub.ModifyIndex = index
+
+	if err := txn.Insert("job_submission", sub); err != nil {
+		return fmt.Errorf("job submission insert failed: %v", err)
+	}
+
+	// Delete the sources of the versions that were pruned from job_version
+	iter, err := txn.Get("job_submission", "id_prefix", sub.Namespace, sub.JobID)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	var stale []*structs.JobSubmission
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		other := raw.(*structs.JobSubmission)
+		if other.JobID != sub.JobID {
+			continue
+		}
+		version, err := txn.First("job_version", "id", other.Namespace, other.JobID, other.Version)
+		if err != nil {
+			return fmt.Errorf("job version lookup failed: %v", err)
+		}
+		if version == nil {
+			stale = append(stale, other)
+		}
+	}
+	for _, other := range stale {
+		if err := txn.Delete("job_submission", other); err != nil {
+			return fmt.Errorf("job submission delete failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"job_submission", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// deleteJobSubmissions deletes the sources of all the versions of the job
+func (s *StateStore) deleteJobSubmissions(index uint64, namespace, jobID string, txn *memdb.Txn) error {
+	iter, err := txn.Get("job_submission", "id_prefix", namespace, jobID)
+	if err != nil {
+		return fmt.Errorf("job submission lookup failed: %v", err)
+	}
+
+	var subs []*structs.JobSubmission
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		// Ensure the ID is an exact match
+		sub := raw.(*structs.JobSubmission)
+		if sub.JobID == jobID {
+			subs = append(subs, sub)
+		}
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	for _, sub := range subs {
+		if err := txn.Delete("job_submission", sub); err != nil {
+			return fmt.Errorf("deleting job submission failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"job_submission", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// JobSubmission returns the source of a version of the job, or nil if it
+// wasn't stored
+func (s *StateStore) JobSubmission(ws memdb.WatchSet, namespace, jobID string, version uint64) (*structs.JobSubmission, error) {
+	txn := s.db.Txn(false)
+
+	// COMPAT 0.7: Upgrade old objects that do not have namespaces
+	if namespace == "" {
+		namespace = structs.DefaultNamespace
+	}
+
+	watchCh, existing, err := txn.FirstWatch("job_submission", "id", namespace, jobID, version)
+	if err != nil {
+		return nil, fmt.Errorf("job submission lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.JobSubmission), nil
+	}
+	return nil, nil
+}
+
+// JobSubmissions returns an iterator over all the job sources
+func (s *StateStore) JobSubmissions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("job_submission", "id")
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// JobSubmissionRestore is used to restore a job source
+func (r *StateRestore) JobSubmissionRestore(sub *structs.JobSubmission) error {
+	if err := r.txn.Insert("job_submission", sub); err != nil {
+		return fmt.Errorf("inserting job submission failed: %v", err)
+	}
+	return nil
+}
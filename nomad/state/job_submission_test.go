@@ -0,0 +1,101 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateStore_UpsertJobSubmission(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+
+	// The source is stored with the current version of the job
+	sub := &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+	}
+	require.NoError(state.UpsertJobSubmission(1001, sub))
+
+	ws := memdb.NewWatchSet()
+	out, err := state.JobSubmission(ws, job.Namespace, job.ID, 0)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(sub.Source, out.Source)
+	require.Equal(uint64(1001), out.CreateIndex)
+	require.Equal(uint64(1001), out.ModifyIndex)
+
+	index, err := state.Index("job_submission")
+	require.NoError(err)
+	require.Equal(uint64(1001), index)
+
+	// Registering new versions prunes the sources of the untracked ones
+	for i := 1; i <= structs.JobTrackedVersions; i++ {
+		next := job.Copy()
+		next.Priority = job.Priority + i
+		require.NoError(state.UpsertJob(uint64(1001+2*i), next))
+		require.NoError(state.UpsertJobSubmission(uint64(1002+2*i), &structs.JobSubmission{
+			Source:    sub.Source,
+			Format:    sub.Format,
+			Namespace: job.Namespace,
+			JobID:     job.ID,
+		}))
+	}
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(err)
+	require.Nil(out)
+
+	out, err = state.JobSubmission(nil, job.Namespace, job.ID, uint64(structs.JobTrackedVersions))
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(uint64(structs.JobTrackedVersions), out.Version)
+
+	require.True(watchFired(ws))
+}
+
+func TestStateStore_UpsertJobSubmission_MissingJob(t *testing.T) {
+	t.Parallel()
+	state := testStateStore(t)
+
+	sub := &structs.JobSubmission{
+		Source: `job "example" {}`,
+		Format: structs.JobSubmissionFormatHCL2,
+		JobID:  "missing",
+	}
+	require.Error(t, state.UpsertJobSubmission(1000, sub))
+}
+
+func TestStateStore_DeleteJob_JobSubmissions(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	job := mock.Job()
+	require.NoError(state.UpsertJob(1000, job))
+	require.NoError(state.UpsertJobSubmission(1001, &structs.JobSubmission{
+		Source:    `job "example" {}`,
+		Format:    structs.JobSubmissionFormatHCL2,
+		Namespace: job.Namespace,
+		JobID:     job.ID,
+	}))
+
+	require.NoError(state.DeleteJob(1002, job.Namespace, job.ID))
+
+	out, err := state.JobSubmission(nil, job.Namespace, job.ID, 0)
+	require.NoError(err)
+	require.Nil(out)
+
+	index, err := state.Index("job_submission")
+	require.NoError(err)
+	require.Equal(uint64(1002), index)
+}
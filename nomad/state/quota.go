@@ -0,0 +1,395 @@
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UpsertQuotaSpecs is used to create or update a set of quota specifications.
+// The usage of each specification is recomputed from the allocations of the
+// namespaces attached to it.
+func (s *StateStore) UpsertQuotaSpecs(index uint64, specs []*structs.QuotaSpec) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, spec := range specs {
+		// Ensure the quota hash is non-nil. This should be done outside the
+		// state store for performance reasons, but we check here for defense
+		// in depth.
+		if len(spec.Hash) == 0 {
+			spec.SetHash()
+		}
+
+		// Check if the quota already exists
+		existing, err := txn.First("quota_spec", "id", spec.Name)
+		if err != nil {
+			return fmt.Errorf("quota lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			spec.CreateIndex = existing.(*structs.QuotaSpec).CreateIndex
+			spec.ModifyIndex = index
+		} else {
+			spec.CreateIndex = index
+			spec.ModifyIndex = index
+		}
+
+		// Update the quota
+		if err := txn.Insert("quota_spec", spec); err != nil {
+			return fmt.Errorf("upserting quota failed: %v", err)
+		}
+
+		// The limits of the region may have changed
+		if err := s.refreshQuotaUsageTxn(index, txn, spec); err != nil {
+			return err
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"quota_spec", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteQuotaSpecs is used to remove a set of quota specifications. A quota
+// specification can't be deleted while namespaces are attached to it.
+func (s *StateStore) DeleteQuotaSpecs(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, name := range names {
+		// Ensure that no namespace is attached to the quota
+		ns, err := txn.First("namespaces", "quota", name)
+		if err != nil {
+			return fmt.Errorf("namespace lookup failed: %v", err)
+		}
+		if ns != nil {
+			return fmt.Errorf("quota %q is used by namespace %q", name, ns.(*structs.Namespace).Name)
+		}
+
+		// Delete the quota and its usage
+		if _, err := txn.DeleteAll("quota_spec", "id", name); err != nil {
+			return fmt.Errorf("deleting quota failed: %v", err)
+		}
+		if _, err := txn.DeleteAll("quota_usage", "id", name); err != nil {
+			return fmt.Errorf("deleting quota usage failed: %v", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"quota_spec", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"quota_usage", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// QuotaSpecByName is used to lookup a quota specification by name
+func (s *StateStore) QuotaSpecByName(ws memdb.WatchSet, name string) (*structs.QuotaSpec, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("quota_spec", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("quota lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.QuotaSpec), nil
+	}
+	return nil, nil
+}
+
+// QuotaSpecsByNamePrefix is used to lookup quota specifications by prefix
+func (s *StateStore) QuotaSpecsByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("quota_spec", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("quota lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// QuotaSpecs returns an iterator over all the quota specifications
+func (s *StateStore) QuotaSpecs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire quota table
+	iter, err := txn.Get("quota_spec", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// QuotaUsageByName is used to lookup the usage of a quota specification by
+// name
+func (s *StateStore) QuotaUsageByName(ws memdb.WatchSet, name string) (*structs.QuotaUsage, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("quota_usage", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("quota usage lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.QuotaUsage), nil
+	}
+	return nil, nil
+}
+
+// QuotaUsagesByNamePrefix is used to lookup quota usages by prefix
+func (s *StateStore) QuotaUsagesByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("quota_usage", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("quota usage lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// QuotaUsages returns an iterator over all the quota usages
+func (s *StateStore) QuotaUsages(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire quota usage table
+	iter, err := txn.Get("quota_usage", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// NamespacesByQuota returns an iterator over the namespaces attached to the
+// quota specification
+func (s *StateStore) NamespacesByQuota(ws memdb.WatchSet, quota string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("namespaces", "quota", quota)
+	if err != nil {
+		return nil, fmt.Errorf("namespace lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// PlanQuotaDelta returns how much the usage of the quota changes if the plan
+// is applied: the stopped and preempted allocations release their resources
+// and the placed ones consume theirs.
+func (s *StateStore) PlanQuotaDelta(ws memdb.WatchSet, quota string, plan *structs.Plan) (*structs.Resources, error) {
+	txn := s.db.Txn(false)
+	delta := new(structs.Resources)
+
+	// inQuota returns whether the allocation counts against the quota
+	inQuota := func(alloc *structs.Allocation) (bool, error) {
+		ns, err := s.namespaceByNameImpl(ws, txn, alloc.Namespace)
+		if err != nil {
+			return false, err
+		}
+		return ns != nil && ns.Quota == quota, nil
+	}
+
+	// release subtracts the resources of the existing allocation if it
+	// counts against the quota
+	release := func(allocID string) error {
+		watchCh, raw, err := txn.FirstWatch("allocs", "id", allocID)
+		if err != nil {
+			return fmt.Errorf("alloc lookup failed: %v", err)
+		}
+		ws.Add(watchCh)
+		if raw == nil {
+			return nil
+		}
+		existing := raw.(*structs.Allocation)
+		if existing.TerminalStatus() {
+			return nil
+		}
+		if ok, err := inQuota(existing); err != nil {
+			return err
+		} else if ok {
+			delta.AddQuotaResources(existing.QuotaResources(), -1)
+		}
+		return nil
+	}
+
+	for _, allocs := range plan.NodeUpdate {
+		for _, alloc := range allocs {
+			if err := release(alloc.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, allocs := range plan.NodePreemptions {
+		for _, alloc := range allocs {
+			if err := release(alloc.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			// In-place updates replace the resources of the existing
+			// allocation
+			if err := release(alloc.ID); err != nil {
+				return nil, err
+			}
+			if alloc.TerminalStatus() {
+				continue
+			}
+			if ok, err := inQuota(alloc); err != nil {
+				return nil, err
+			} else if ok {
+				delta.AddQuotaResources(alloc.QuotaResources(), 1)
+			}
+		}
+	}
+
+	return delta, nil
+}
+
+// refreshQuotaUsageTxn recomputes the usage of the quota specification from
+// the non-terminal allocations of the namespaces attached to it.
+func (s *StateStore) refreshQuotaUsageTxn(index uint64, txn *memdb.Txn, spec *structs.QuotaSpec) error {
+	usage := structs.NewQuotaUsage(spec, s.config.Region)
+
+	existing, err := txn.First("quota_usage", "id", spec.Name)
+	if err != nil {
+		return fmt.Errorf("quota usage lookup failed: %v", err)
+	}
+	if existing != nil {
+		usage.CreateIndex = existing.(*structs.QuotaUsage).CreateIndex
+	} else {
+		usage.CreateIndex = index
+	}
+	usage.ModifyIndex = index
+
+	// Sum up the resources of the allocations if the region is limited
+	if len(usage.Used) != 0 {
+		namespaces, err := txn.Get("namespaces", "quota", spec.Name)
+		if err != nil {
+			return fmt.Errorf("namespace lookup failed: %v", err)
+		}
+		for raw := namespaces.Next(); raw != nil; raw = namespaces.Next() {
+			ns := raw.(*structs.Namespace)
+			allocs, err := txn.Get("allocs", "namespace", ns.Name)
+			if err != nil {
+				return fmt.Errorf("alloc lookup failed: %v", err)
+			}
+			for raw := allocs.Next(); raw != nil; raw = allocs.Next() {
+				alloc := raw.(*structs.Allocation)
+				if alloc.TerminalStatus() {
+					continue
+				}
+				for _, used := range usage.Used {
+					used.RegionLimit.AddQuotaResources(alloc.QuotaResources(), 1)
+				}
+			}
+		}
+	}
+
+	if err := txn.Insert("quota_usage", usage); err != nil {
+		return fmt.Errorf("upserting quota usage failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"quota_usage", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// refreshNamespaceQuotaUsageTxn recomputes the usage of the quota
+// specification, if it exists, after a namespace was attached to or detached
+// from it.
+func (s *StateStore) refreshNamespaceQuotaUsageTxn(index uint64, txn *memdb.Txn, quota string) error {
+	if quota == "" {
+		return nil
+	}
+	raw, err := txn.First("quota_spec", "id", quota)
+	if err != nil {
+		return fmt.Errorf("quota lookup failed: %v", err)
+	}
+	if raw == nil {
+		return nil
+	}
+	return s.refreshQuotaUsageTxn(index, txn, raw.(*structs.QuotaSpec))
+}
+
+// updateQuotaWithAlloc updates the usage of the quota specification the
+// namespace of the allocation is attached to, when an allocation is
+// added or modified.
+func (s *StateStore) updateQuotaWithAlloc(index uint64, new, existing *structs.Allocation, txn *memdb.Txn) error {
+	// Compute the change of the resources the allocation consumes
+	delta := &structs.Resources{}
+	if existing != nil && !existing.TerminalStatus() {
+		delta.AddQuotaResources(existing.QuotaResources(), -1)
+	}
+	if !new.TerminalStatus() {
+		delta.AddQuotaResources(new.QuotaResources(), 1)
+	}
+	if delta.CPU == 0 && delta.MemoryMB == 0 && delta.DiskMB == 0 {
+		return nil
+	}
+
+	ns, err := s.namespaceByNameImpl(nil, txn, new.Namespace)
+	if err != nil {
+		return err
+	}
+	if ns == nil || ns.Quota == "" {
+		return nil
+	}
+
+	raw, err := txn.First("quota_usage", "id", ns.Quota)
+	if err != nil {
+		return fmt.Errorf("quota usage lookup failed: %v", err)
+	}
+	if raw == nil {
+		return nil
+	}
+
+	usage := raw.(*structs.QuotaUsage).Copy()
+	for _, used := range usage.Used {
+		used.RegionLimit.AddQuotaResources(delta, 1)
+	}
+	usage.ModifyIndex = index
+
+	if err := txn.Insert("quota_usage", usage); err != nil {
+		return fmt.Errorf("upserting quota usage failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"quota_usage", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// QuotaSpecRestore is used to restore a quota specification
+func (r *StateRestore) QuotaSpecRestore(spec *structs.QuotaSpec) error {
+	if err := r.txn.Insert("quota_spec", spec); err != nil {
+		return fmt.Errorf("quota insert failed: %v", err)
+	}
+	return nil
+}
+
+// QuotaUsageRestore is used to restore a quota usage
+func (r *StateRestore) QuotaUsageRestore(usage *structs.QuotaUsage) error {
+	if err := r.txn.Insert("quota_usage", usage); err != nil {
+		return fmt.Errorf("quota usage insert failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package state
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+// quotaAlloc returns an allocation of a job in the namespace
+func quotaAlloc(ns *structs.Namespace) *structs.Allocation {
+	alloc := mock.Alloc()
+	alloc.Namespace = ns.Name
+	alloc.Job.Namespace = ns.Name
+	return alloc
+}
+
+// quotaUsed returns the usage of the quota in the region of the test state
+// store
+func quotaUsed(t *testing.T, s *StateStore, spec *structs.QuotaSpec) *structs.Resources {
+	usage, err := s.QuotaUsageByName(nil, spec.Name)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+	used, ok := usage.Used[spec.RegionLimit("global").Key()]
+	require.True(t, ok)
+	return used.RegionLimit
+}
+
+func TestStateStore_UpsertQuotaSpecs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+
+	// Create a watchset so we can test that upsert fires the watch
+	ws := memdb.NewWatchSet()
+	_, err := state.QuotaSpecByName(ws, qs1.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs1, qs2}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.QuotaSpecByName(ws, qs1.Name)
+	require.NoError(err)
+	require.Equal(qs1, out)
+
+	out, err = state.QuotaSpecByName(ws, qs2.Name)
+	require.NoError(err)
+	require.Equal(qs2, out)
+
+	// The usages are created along with the specifications
+	usage, err := state.QuotaUsageByName(ws, qs1.Name)
+	require.NoError(err)
+	require.NotNil(usage)
+	require.Len(usage.Used, 1)
+
+	iter, err := state.QuotaSpecs(ws)
+	require.NoError(err)
+
+	// Ensure we see both quotas
+	count := 0
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		count++
+	}
+	require.Equal(2, count)
+
+	index, err := state.Index("quota_spec")
+	require.NoError(err)
+	require.EqualValues(1000, index)
+
+	index, err = state.Index("quota_usage")
+	require.NoError(err)
+	require.EqualValues(1000, index)
+
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_DeleteQuotaSpecs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+	qs1 := mock.QuotaSpec()
+	qs2 := mock.QuotaSpec()
+
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs1, qs2}))
+
+	// Quotas used by a namespace can't be deleted
+	ns := mock.Namespace()
+	ns.Quota = qs1.Name
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{ns}))
+	err := state.DeleteQuotaSpecs(1002, []string{qs1.Name})
+	require.Error(err)
+	require.Contains(err.Error(), "is used by namespace")
+
+	ns = ns.Copy()
+	ns.Quota = ""
+	require.NoError(state.UpsertNamespaces(1003, []*structs.Namespace{ns}))
+
+	// Create a watcher
+	ws := memdb.NewWatchSet()
+	_, err = state.QuotaSpecByName(ws, qs1.Name)
+	require.NoError(err)
+
+	require.NoError(state.DeleteQuotaSpecs(1004, []string{qs1.Name, qs2.Name}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.QuotaSpecByName(ws, qs1.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	usage, err := state.QuotaUsageByName(ws, qs1.Name)
+	require.NoError(err)
+	require.Nil(usage)
+
+	index, err := state.Index("quota_spec")
+	require.NoError(err)
+	require.EqualValues(1004, index)
+
+	require.False(watchFired(ws))
+}
+
+func TestStateStore_QuotaSpecsByNamePrefix(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+	qs1 := mock.QuotaSpec()
+	qs1.Name = "foo"
+	qs2 := mock.QuotaSpec()
+	qs2.Name = "foobar"
+	qs3 := mock.QuotaSpec()
+	qs3.Name = "bar"
+
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs1, qs2, qs3}))
+
+	gatherNames := func(iter memdb.ResultIterator) []string {
+		var names []string
+		for {
+			raw := iter.Next()
+			if raw == nil {
+				break
+			}
+			names = append(names, raw.(*structs.QuotaSpec).Name)
+		}
+		return names
+	}
+
+	iter, err := state.QuotaSpecsByNamePrefix(nil, "foo")
+	require.NoError(err)
+	require.ElementsMatch([]string{"foo", "foobar"}, gatherNames(iter))
+
+	iter, err = state.QuotaSpecsByNamePrefix(nil, "bar")
+	require.NoError(err)
+	require.ElementsMatch([]string{"bar"}, gatherNames(iter))
+}
+
+func TestStateStore_UpsertNamespaces_UnknownQuota(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	ns := mock.Namespace()
+	ns.Quota = "missing"
+	err := state.UpsertNamespaces(1000, []*structs.Namespace{ns})
+	require.Error(err)
+	require.Contains(err.Error(), "unknown quota")
+}
+
+func TestStateStore_QuotaUsage_Allocs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	qs := mock.QuotaSpec()
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs}))
+	ns := mock.Namespace()
+	ns.Quota = qs.Name
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{ns}))
+
+	// Placing allocations consumes the quota
+	a1 := quotaAlloc(ns)
+	a2 := quotaAlloc(ns)
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{a1, a2}))
+	used := quotaUsed(t, state, qs)
+	require.Equal(1000, used.CPU)
+	require.Equal(512, used.MemoryMB)
+	require.Equal(300, used.DiskMB)
+
+	// Allocations of other namespaces don't
+	require.NoError(state.UpsertAllocs(1003, []*structs.Allocation{mock.Alloc()}))
+	require.Equal(1000, quotaUsed(t, state, qs).CPU)
+
+	// Terminal allocations release the quota
+	a1 = a1.Copy()
+	a1.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(state.UpdateAllocsFromClient(1004, []*structs.Allocation{a1}))
+	used = quotaUsed(t, state, qs)
+	require.Equal(500, used.CPU)
+	require.Equal(256, used.MemoryMB)
+
+	usage, err := state.QuotaUsageByName(nil, qs.Name)
+	require.NoError(err)
+	require.EqualValues(1004, usage.ModifyIndex)
+
+	// Detaching the namespace releases its usage
+	ns = ns.Copy()
+	ns.Quota = ""
+	require.NoError(state.UpsertNamespaces(1005, []*structs.Namespace{ns}))
+	require.Zero(quotaUsed(t, state, qs).CPU)
+
+	// Attaching it again recomputes the usage from the allocations
+	ns = ns.Copy()
+	ns.Quota = qs.Name
+	require.NoError(state.UpsertNamespaces(1006, []*structs.Namespace{ns}))
+	require.Equal(500, quotaUsed(t, state, qs).CPU)
+}
+
+func TestStateStore_PlanQuotaDelta(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	qs := mock.QuotaSpec()
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs}))
+	ns := mock.Namespace()
+	ns.Quota = qs.Name
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{ns}))
+
+	existing := quotaAlloc(ns)
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{existing}))
+
+	// Stop the existing allocation and place two new ones, with the
+	// combined resources stripped as the scheduler does
+	placed1 := quotaAlloc(ns)
+	placed1.Resources = nil
+	placed2 := quotaAlloc(ns)
+	placed2.Resources = nil
+	other := mock.Alloc()
+	plan := &structs.Plan{
+		NodeUpdate: map[string][]*structs.Allocation{
+			existing.NodeID: {existing},
+		},
+		NodeAllocation: map[string][]*structs.Allocation{
+			placed1.NodeID: {placed1, placed2, other},
+		},
+	}
+
+	delta, err := state.PlanQuotaDelta(nil, qs.Name, plan)
+	require.NoError(err)
+	require.Equal(500, delta.CPU)
+	require.Equal(256, delta.MemoryMB)
+	require.Equal(150, delta.DiskMB)
+}
@@ -0,0 +1,88 @@
+package state
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func TestStateStore_SchedulerConfig(t *testing.T) {
+	s := testStateStore(t)
+
+	expected := &structs.SchedulerConfiguration{
+		PreemptionConfig: structs.PreemptionConfig{
+			SystemSchedulerEnabled:  true,
+			ServiceSchedulerEnabled: true,
+		},
+	}
+
+	if err := s.SchedulerSetConfig(0, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	idx, config, err := s.SchedulerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx != 0 {
+		t.Fatalf("bad: %d", idx)
+	}
+	if !reflect.DeepEqual(expected, config) {
+		t.Fatalf("bad: %#v, %#v", expected, config)
+	}
+}
+
+func TestStateStore_SchedulerCASConfig(t *testing.T) {
+	s := testStateStore(t)
+
+	expected := &structs.SchedulerConfiguration{
+		PreemptionConfig: structs.PreemptionConfig{
+			SystemSchedulerEnabled: true,
+		},
+	}
+
+	if err := s.SchedulerSetConfig(0, expected); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SchedulerSetConfig(1, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	// Do a CAS with an index lower than the entry
+	ok, err := s.SchedulerCASConfig(2, 0, &structs.SchedulerConfiguration{})
+	if ok || err != nil {
+		t.Fatalf("expected (false, nil), got: (%v, %#v)", ok, err)
+	}
+
+	// Check that the index is untouched and the entry
+	// has not been updated.
+	idx, config, err := s.SchedulerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx != 1 {
+		t.Fatalf("bad: %d", idx)
+	}
+	if !config.PreemptionConfig.SystemSchedulerEnabled {
+		t.Fatalf("bad: %#v", config)
+	}
+
+	// Do another CAS, this time with the correct index
+	ok, err = s.SchedulerCASConfig(2, 1, &structs.SchedulerConfiguration{})
+	if !ok || err != nil {
+		t.Fatalf("expected (true, nil), got: (%v, %#v)", ok, err)
+	}
+
+	// Make sure the config was updated
+	idx, config, err = s.SchedulerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx != 2 {
+		t.Fatalf("bad: %d", idx)
+	}
+	if config.PreemptionConfig.SystemSchedulerEnabled {
+		t.Fatalf("bad: %#v", config)
+	}
+}
@@ -36,14 +36,25 @@ func init() {
 		jobTableSchema,
 		jobSummarySchema,
 		jobVersionSchema,
+		jobSubmissionSchema,
 		deploymentSchema,
 		periodicLaunchTableSchema,
 		evalTableSchema,
 		allocTableSchema,
 		vaultAccessorTableSchema,
+		namespaceTableSchema,
+		quotaSpecTableSchema,
+		quotaUsageTableSchema,
 		aclPolicyTableSchema,
+		aclRoleTableSchema,
+		aclAuthMethodTableSchema,
+		aclBindingRuleTableSchema,
+		rootKeyTableSchema,
+		variablesTableSchema,
+		serviceRegistrationsTableSchema,
 		aclTokenTableSchema,
 		autopilotConfigTableSchema,
+		schedulerConfigTableSchema,
 	}...)
 }
 
@@ -257,9 +268,9 @@ func jobIsGCable(obj interface{}) (bool, error) {
 		return true, nil
 	}
 
-	// Otherwise, only batch jobs are eligible because they complete on their
-	// own without a user stopping them.
-	if j.Type != structs.JobTypeBatch {
+	// Otherwise, only batch and sysbatch jobs are eligible because they
+	// complete on their own without a user stopping them.
+	if j.Type != structs.JobTypeBatch && j.Type != structs.JobTypeSysBatch {
 		return false, nil
 	}
 
@@ -506,6 +517,25 @@ func allocTableSchema() *memdb.TableSchema {
 					Field: "DeploymentID",
 				},
 			},
+
+			// Client status index is used to lookup allocations of a
+			// namespace by client status
+			"client_status": {
+				Name:         "client_status",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "ClientStatus",
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -548,6 +578,71 @@ func vaultAccessorTableSchema() *memdb.TableSchema {
 	}
 }
 
+// namespaceTableSchema returns the MemDB schema for the namespace table.
+// This table is used to store the namespaces jobs and their associated
+// objects are registered in.
+func namespaceTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "namespaces",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+			"quota": {
+				Name:         "quota",
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Quota",
+				},
+			},
+		},
+	}
+}
+
+// quotaSpecTableSchema returns the MemDB schema for the quota specification
+// table. This table is used to store the quota specifications namespaces are
+// attached to.
+func quotaSpecTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "quota_spec",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// quotaUsageTableSchema returns the MemDB schema for the quota usage table.
+// This table is used to track the resource usage of each quota
+// specification in the region.
+func quotaUsageTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "quota_usage",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
 // aclPolicyTableSchema returns the MemDB schema for the policy table.
 // This table is used to store the policies which are referenced by tokens
 func aclPolicyTableSchema() *memdb.TableSchema {
@@ -566,6 +661,90 @@ func aclPolicyTableSchema() *memdb.TableSchema {
 	}
 }
 
+// aclRoleTableSchema returns the MemDB schema for the role table.
+// This table is used to store the roles which are referenced by tokens
+func aclRoleTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "acl_role",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// aclAuthMethodTableSchema returns the MemDB schema for the auth method
+// table. This table is used to store the methods tokens can be created by
+// logging in with
+func aclAuthMethodTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "acl_auth_method",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// aclBindingRuleTableSchema returns the MemDB schema for the binding rule
+// table. This table is used to store the rules that decide what the tokens
+// created by a login are granted
+func aclBindingRuleTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "acl_binding_rule",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "ID",
+				},
+			},
+			"auth_method": {
+				Name:         "auth_method",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "AuthMethod",
+				},
+			},
+		},
+	}
+}
+
+// rootKeyTableSchema returns the MemDB schema for the root key table. This
+// table is used to store the metadata of the keys that sign workload
+// identities and encrypt variables. Their key material is kept out of the
+// state store.
+func rootKeyTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "root_keys",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "KeyID",
+				},
+			},
+		},
+	}
+}
+
 // aclTokenTableSchema returns the MemDB schema for the tokens table.
 // This table is used to store the bearer tokens which are used to authenticate
 func aclTokenTableSchema() *memdb.TableSchema {
@@ -599,3 +778,102 @@ func aclTokenTableSchema() *memdb.TableSchema {
 		},
 	}
 }
+
+// variablesTableSchema returns the MemDB schema for the variables table.
+// This table is used to store the encrypted variables
+func variablesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "variables",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, Path) is
+				// uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+					},
+				},
+			},
+			"keyid": {
+				Name:         "keyid",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "KeyID",
+				},
+			},
+		},
+	}
+}
+
+// serviceRegistrationsTableSchema returns the MemDB schema for the service
+// registrations table. This table is used to store the instances of the
+// services with the Nomad provider.
+func serviceRegistrationsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: "service_registrations",
+		Indexes: map[string]*memdb.IndexSchema{
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+
+				// Use a compound index so the tuple of (Namespace, ID) is
+				// uniquely identifying
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "ID",
+						},
+					},
+				},
+			},
+			"service_name": {
+				Name:         "service_name",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+
+						&memdb.StringFieldIndex{
+							Field: "ServiceName",
+						},
+					},
+				},
+			},
+			"alloc_id": {
+				Name:         "alloc_id",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "AllocID",
+				},
+			},
+			"node_id": {
+				Name:         "node_id",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "NodeID",
+				},
+			},
+		},
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/go-memdb"
@@ -74,9 +75,35 @@ func NewStateStore(config *StateStoreConfig) (*StateStore, error) {
 		config:    config,
 		abandonCh: make(chan struct{}),
 	}
+
+	// Create the default namespace. A snapshot restored into the state store
+	// replaces it with the copy it holds.
+	if err := s.initDefaultNamespace(); err != nil {
+		return nil, fmt.Errorf("state store setup failed: %v", err)
+	}
 	return s, nil
 }
 
+// initDefaultNamespace inserts the default namespace, which always exists
+func (s *StateStore) initDefaultNamespace() error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	ns := &structs.Namespace{
+		Name:        structs.DefaultNamespace,
+		Description: structs.DefaultNamespaceDescription,
+		CreateIndex: 1,
+		ModifyIndex: 1,
+	}
+	ns.SetHash()
+	if err := txn.Insert("namespaces", ns); err != nil {
+		return fmt.Errorf("inserting default namespace failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
 // Config returns the state store configuration.
 func (s *StateStore) Config() *StateStoreConfig {
 	return s.config
@@ -217,6 +244,13 @@ func (s *StateStore) UpsertPlanResults(index uint64, results *structs.ApplyPlanR
 		}
 	}
 
+	// Upsert the follow up evals of the jobs with preempted allocations
+	for _, eval := range results.PreemptionEvals {
+		if err := s.nestedUpsertEval(txn, index, eval); err != nil {
+			return err
+		}
+	}
+
 	txn.Commit()
 	return nil
 }
@@ -362,6 +396,9 @@ func (s *StateStore) Deployments(ws memdb.WatchSet) (memdb.ResultIterator, error
 
 func (s *StateStore) DeploymentsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
+	if namespace == structs.AllNamespacesSentinel {
+		return s.allObjectsImpl(ws, txn, "deployment")
+	}
 
 	// Walk the entire deployments table
 	iter, err := txn.Get("deployment", "namespace", namespace)
@@ -398,7 +435,7 @@ func deploymentNamespaceFilter(namespace string) func(interface{}) bool {
 			return true
 		}
 
-		return d.Namespace != namespace
+		return namespace != structs.AllNamespacesSentinel && d.Namespace != namespace
 	}
 }
 
@@ -596,6 +633,11 @@ func (s *StateStore) DeleteNode(index uint64, nodeID string) error {
 		return fmt.Errorf("index update failed: %v", err)
 	}
 
+	// The services of the node's allocations are no longer reachable
+	if err := s.deleteServiceRegistrations(txn, index, "node_id", nodeID); err != nil {
+		return err
+	}
+
 	txn.Commit()
 	return nil
 }
@@ -635,6 +677,13 @@ func (s *StateStore) UpdateNodeStatus(index uint64, nodeID, status string, event
 		return fmt.Errorf("index update failed: %v", err)
 	}
 
+	// The services of a down node's allocations are no longer reachable
+	if status == structs.NodeStatusDown {
+		if err := s.deleteServiceRegistrations(txn, index, "node_id", nodeID); err != nil {
+			return err
+		}
+	}
+
 	txn.Commit()
 	return nil
 }
@@ -1048,6 +1097,11 @@ func (s *StateStore) DeleteJob(index uint64, namespace, jobID string) error {
 		return err
 	}
 
+	// Delete the job sources
+	if err := s.deleteJobSubmissions(index, namespace, jobID, txn); err != nil {
+		return err
+	}
+
 	// Delete the job summary
 	if _, err = txn.DeleteAll("job_summary", "id", namespace, jobID); err != nil {
 		return fmt.Errorf("deleing job summary failed: %v", err)
@@ -1181,6 +1235,16 @@ func (s *StateStore) JobsByIDPrefix(ws memdb.WatchSet, namespace, id string) (me
 		namespace = structs.DefaultNamespace
 	}
 
+	// The jobs of all the namespaces are filtered by their ID
+	if namespace == structs.AllNamespacesSentinel {
+		iter, err := txn.Get("jobs", "id")
+		if err != nil {
+			return nil, fmt.Errorf("job lookup failed: %v", err)
+		}
+		ws.Add(iter.WatchCh())
+		return memdb.NewFilterIterator(iter, jobIDPrefixFilter(id)), nil
+	}
+
 	iter, err := txn.Get("jobs", "id_prefix", namespace, id)
 	if err != nil {
 		return nil, fmt.Errorf("job lookup failed: %v", err)
@@ -1191,6 +1255,19 @@ func (s *StateStore) JobsByIDPrefix(ws memdb.WatchSet, namespace, id string) (me
 	return iter, nil
 }
 
+// jobIDPrefixFilter returns a filter function that filters out the jobs
+// whose ID doesn't start with the prefix.
+func jobIDPrefixFilter(prefix string) func(interface{}) bool {
+	return func(raw interface{}) bool {
+		job, ok := raw.(*structs.Job)
+		if !ok {
+			return true
+		}
+
+		return !strings.HasPrefix(job.ID, prefix)
+	}
+}
+
 // JobVersionsByID returns all the tracked versions of a job.
 func (s *StateStore) JobVersionsByID(ws memdb.WatchSet, namespace, id string) ([]*structs.Job, error) {
 	txn := s.db.Txn(false)
@@ -1314,9 +1391,26 @@ func (s *StateStore) Jobs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 // JobsByNamespace returns an iterator over all the jobs for the given namespace
 func (s *StateStore) JobsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
+	if namespace == structs.AllNamespacesSentinel {
+		return s.allObjectsImpl(ws, txn, "jobs")
+	}
 	return s.jobsByNamespaceImpl(ws, namespace, txn)
 }
 
+// allObjectsImpl returns an iterator over all the objects of a table whose
+// "id" index is usable without arguments, used to list the objects of all
+// the namespaces.
+func (s *StateStore) allObjectsImpl(ws memdb.WatchSet, txn *memdb.Txn, table string) (memdb.ResultIterator, error) {
+	iter, err := txn.Get(table, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
 // jobsByNamespaceImpl returns an iterator over all the jobs for the given namespace
 func (s *StateStore) jobsByNamespaceImpl(ws memdb.WatchSet, namespace string, txn *memdb.Txn) (memdb.ResultIterator, error) {
 	// Walk the entire jobs table
@@ -1772,6 +1866,49 @@ func (s *StateStore) EvalByID(ws memdb.WatchSet, id string) (*structs.Evaluation
 	return nil, nil
 }
 
+// EvalsRelatedToID returns the evaluations chained to the given one through
+// their previous, next and blocked evaluations, ordered by creation
+func (s *StateStore) EvalsRelatedToID(ws memdb.WatchSet, id string) ([]*structs.EvaluationStub, error) {
+	txn := s.db.Txn(false)
+
+	related := []*structs.EvaluationStub{}
+	seen := map[string]struct{}{id: {}}
+	queue := []string{id}
+	for len(queue) != 0 {
+		next := queue[0]
+		queue = queue[1:]
+
+		watchCh, raw, err := txn.FirstWatch("evals", "id", next)
+		if err != nil {
+			return nil, fmt.Errorf("eval lookup failed: %v", err)
+		}
+		ws.Add(watchCh)
+		if raw == nil {
+			continue
+		}
+
+		eval := raw.(*structs.Evaluation)
+		if eval.ID != id {
+			related = append(related, eval.Stub())
+		}
+		for _, linked := range []string{eval.PreviousEval, eval.NextEval, eval.BlockedEval} {
+			if linked == "" {
+				continue
+			}
+			if _, ok := seen[linked]; ok {
+				continue
+			}
+			seen[linked] = struct{}{}
+			queue = append(queue, linked)
+		}
+	}
+
+	sort.Slice(related, func(i, j int) bool {
+		return related[i].CreateIndex < related[j].CreateIndex
+	})
+	return related, nil
+}
+
 // EvalsByIDPrefix is used to lookup evaluations by prefix in a particular
 // namespace
 func (s *StateStore) EvalsByIDPrefix(ws memdb.WatchSet, namespace, id string) (memdb.ResultIterator, error) {
@@ -1804,7 +1941,7 @@ func evalNamespaceFilter(namespace string) func(interface{}) bool {
 			return true
 		}
 
-		return eval.Namespace != namespace
+		return namespace != structs.AllNamespacesSentinel && eval.Namespace != namespace
 	}
 }
 
@@ -1863,6 +2000,9 @@ func (s *StateStore) Evals(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 // namespace
 func (s *StateStore) EvalsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
+	if namespace == structs.AllNamespacesSentinel {
+		return s.allObjectsImpl(ws, txn, "evals")
+	}
 
 	// Walk the entire table
 	iter, err := txn.Get("evals", "namespace", namespace)
@@ -1948,7 +2088,7 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *memdb.Txn, index uint64, a
 		return fmt.Errorf("error updating job summary: %v", err)
 	}
 
-	if err := s.updateEntWithAlloc(index, copyAlloc, exist, txn); err != nil {
+	if err := s.updateQuotaWithAlloc(index, copyAlloc, exist, txn); err != nil {
 		return err
 	}
 
@@ -1957,6 +2097,14 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *memdb.Txn, index uint64, a
 		return fmt.Errorf("alloc insert failed: %v", err)
 	}
 
+	// Remove the services of stopped allocations in case their client
+	// couldn't deregister them
+	if copyAlloc.ClientTerminalStatus() {
+		if err := s.deleteServiceRegistrations(txn, index, "alloc_id", copyAlloc.ID); err != nil {
+			return err
+		}
+	}
+
 	// Set the job's status
 	forceStatus := ""
 	if !copyAlloc.TerminalStatus() {
@@ -2059,7 +2207,7 @@ func (s *StateStore) upsertAllocsImpl(index uint64, allocs []*structs.Allocation
 			return fmt.Errorf("error updating job summary: %v", err)
 		}
 
-		if err := s.updateEntWithAlloc(index, alloc, exist, txn); err != nil {
+		if err := s.updateQuotaWithAlloc(index, alloc, exist, txn); err != nil {
 			return err
 		}
 
@@ -2222,7 +2370,7 @@ func allocNamespaceFilter(namespace string) func(interface{}) bool {
 			return true
 		}
 
-		return alloc.Namespace != namespace
+		return namespace != structs.AllNamespacesSentinel && alloc.Namespace != namespace
 	}
 }
 
@@ -2384,6 +2532,9 @@ func (s *StateStore) Allocs(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 // namespace
 func (s *StateStore) AllocsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
+	if namespace == structs.AllNamespacesSentinel {
+		return s.allObjectsImpl(ws, txn, "allocs")
+	}
 	return s.allocsByNamespaceImpl(ws, txn, namespace)
 }
 
@@ -2401,6 +2552,33 @@ func (s *StateStore) allocsByNamespaceImpl(ws memdb.WatchSet, txn *memdb.Txn, na
 	return iter, nil
 }
 
+// AllocsByClientStatus returns an iterator over all the allocations in the
+// namespace with the client status
+func (s *StateStore) AllocsByClientStatus(ws memdb.WatchSet, namespace, status string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// The allocations of all the namespaces are filtered by their status
+	if namespace == structs.AllNamespacesSentinel {
+		iter, err := s.allObjectsImpl(ws, txn, "allocs")
+		if err != nil {
+			return nil, err
+		}
+		return memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+			alloc, ok := raw.(*structs.Allocation)
+			return !ok || alloc.ClientStatus != status
+		}), nil
+	}
+
+	iter, err := txn.Get("allocs", "client_status", namespace, status)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
 // UpsertVaultAccessors is used to register a set of Vault Accessors
 func (s *StateStore) UpsertVaultAccessor(index uint64, accessors []*structs.VaultAccessor) error {
 	txn := s.db.Txn(true)
@@ -3535,6 +3713,177 @@ func (s *StateStore) addEphemeralDiskToTaskGroups(job *structs.Job) {
 	}
 }
 
+// UpsertNamespaces is used to create or update a set of namespaces
+func (s *StateStore) UpsertNamespaces(index uint64, namespaces []*structs.Namespace) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, ns := range namespaces {
+		// Ensure the namespace hash is non-nil. This should be done outside
+		// the state store for performance reasons, but we check here for
+		// defense in depth.
+		if len(ns.Hash) == 0 {
+			ns.SetHash()
+		}
+
+		// Ensure the quota the namespace is attached to exists
+		if ns.Quota != "" {
+			quota, err := txn.First("quota_spec", "id", ns.Quota)
+			if err != nil {
+				return fmt.Errorf("quota lookup failed: %v", err)
+			}
+			if quota == nil {
+				return fmt.Errorf("namespace %q references unknown quota %q", ns.Name, ns.Quota)
+			}
+		}
+
+		// Check if the namespace already exists
+		existing, err := txn.First("namespaces", "id", ns.Name)
+		if err != nil {
+			return fmt.Errorf("namespace lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		oldQuota := ""
+		if existing != nil {
+			ns.CreateIndex = existing.(*structs.Namespace).CreateIndex
+			ns.ModifyIndex = index
+			oldQuota = existing.(*structs.Namespace).Quota
+		} else {
+			ns.CreateIndex = index
+			ns.ModifyIndex = index
+		}
+
+		// Update the namespace
+		if err := txn.Insert("namespaces", ns); err != nil {
+			return fmt.Errorf("upserting namespace failed: %v", err)
+		}
+
+		// Move the usage of the namespace between quotas
+		if oldQuota != ns.Quota {
+			if err := s.refreshNamespaceQuotaUsageTxn(index, txn, oldQuota); err != nil {
+				return err
+			}
+			if err := s.refreshNamespaceQuotaUsageTxn(index, txn, ns.Quota); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"namespaces", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteNamespaces is used to remove a set of namespaces. The default
+// namespace can't be deleted, nor can a namespace with non-terminal jobs.
+func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, name := range names {
+		if name == structs.DefaultNamespace {
+			return fmt.Errorf("default namespace can not be deleted")
+		}
+
+		// Ensure that the namespace doesn't have any non-terminal jobs
+		iter, err := s.jobsByNamespaceImpl(nil, name, txn)
+		if err != nil {
+			return err
+		}
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			job := raw.(*structs.Job)
+			if job.Status != structs.JobStatusDead {
+				return fmt.Errorf("namespace %q contains at least one non-terminal job %q. "+
+					"All jobs must be terminal in namespace before it can be deleted", name, job.ID)
+			}
+		}
+
+		existing, err := s.namespaceByNameImpl(nil, txn, name)
+		if err != nil {
+			return err
+		}
+
+		// Delete the namespace
+		if _, err := txn.DeleteAll("namespaces", "id", name); err != nil {
+			return fmt.Errorf("deleting namespace failed: %v", err)
+		}
+
+		// Release the usage of the namespace from its quota
+		if existing != nil {
+			if err := s.refreshNamespaceQuotaUsageTxn(index, txn, existing.Quota); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"namespaces", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// NamespaceByName is used to lookup a namespace by name
+func (s *StateStore) NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error) {
+	txn := s.db.Txn(false)
+	return s.namespaceByNameImpl(ws, txn, name)
+}
+
+// namespaceByNameImpl is used to lookup a namespace by name within a
+// transaction
+func (s *StateStore) namespaceByNameImpl(ws memdb.WatchSet, txn *memdb.Txn, name string) (*structs.Namespace, error) {
+	watchCh, existing, err := txn.FirstWatch("namespaces", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("namespace lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.Namespace), nil
+	}
+	return nil, nil
+}
+
+// namespaceExists returns whether a namespace exists
+func (s *StateStore) namespaceExists(txn *memdb.Txn, namespace string) (bool, error) {
+	ns, err := s.namespaceByNameImpl(nil, txn, namespace)
+	if err != nil {
+		return false, err
+	}
+	return ns != nil, nil
+}
+
+// NamespacesByNamePrefix is used to lookup namespaces by prefix
+func (s *StateStore) NamespacesByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("namespaces", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("namespaces lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// Namespaces returns an iterator over all the namespaces
+func (s *StateStore) Namespaces(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire namespace table
+	iter, err := txn.Get("namespaces", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
 // UpsertACLPolicies is used to create or update a set of ACL policies
 func (s *StateStore) UpsertACLPolicies(index uint64, policies []*structs.ACLPolicy) error {
 	txn := s.db.Txn(true)
@@ -3637,121 +3986,101 @@ func (s *StateStore) ACLPolicies(ws memdb.WatchSet) (memdb.ResultIterator, error
 	return iter, nil
 }
 
-// UpsertACLTokens is used to create or update a set of ACL tokens
-func (s *StateStore) UpsertACLTokens(index uint64, tokens []*structs.ACLToken) error {
+// UpsertACLRoles is used to create or update a set of ACL roles
+func (s *StateStore) UpsertACLRoles(index uint64, roles []*structs.ACLRole) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
-	for _, token := range tokens {
-		// Ensure the policy hash is non-nil. This should be done outside the state store
+	for _, role := range roles {
+		// Ensure the role hash is non-nil. This should be done outside the state store
 		// for performance reasons, but we check here for defense in depth.
-		if len(token.Hash) == 0 {
-			token.SetHash()
+		if len(role.Hash) == 0 {
+			role.SetHash()
 		}
 
-		// Check if the token already exists
-		existing, err := txn.First("acl_token", "id", token.AccessorID)
+		// Check if the role already exists
+		existing, err := txn.First("acl_role", "id", role.Name)
 		if err != nil {
-			return fmt.Errorf("token lookup failed: %v", err)
+			return fmt.Errorf("role lookup failed: %v", err)
 		}
 
 		// Update all the indexes
 		if existing != nil {
-			existTK := existing.(*structs.ACLToken)
-			token.CreateIndex = existTK.CreateIndex
-			token.ModifyIndex = index
-
-			// Do not allow SecretID or create time to change
-			token.SecretID = existTK.SecretID
-			token.CreateTime = existTK.CreateTime
-
+			role.CreateIndex = existing.(*structs.ACLRole).CreateIndex
+			role.ModifyIndex = index
 		} else {
-			token.CreateIndex = index
-			token.ModifyIndex = index
+			role.CreateIndex = index
+			role.ModifyIndex = index
 		}
 
-		// Update the token
-		if err := txn.Insert("acl_token", token); err != nil {
-			return fmt.Errorf("upserting token failed: %v", err)
+		// Update the role
+		if err := txn.Insert("acl_role", role); err != nil {
+			return fmt.Errorf("upserting role failed: %v", err)
 		}
 	}
 
 	// Update the indexes table
-	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+	if err := txn.Insert("index", &IndexEntry{"acl_role", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
+
 	txn.Commit()
 	return nil
 }
 
-// DeleteACLTokens deletes the tokens with the given accessor ids
-func (s *StateStore) DeleteACLTokens(index uint64, ids []string) error {
+// DeleteACLRoles deletes the roles with the given names
+func (s *StateStore) DeleteACLRoles(index uint64, names []string) error {
 	txn := s.db.Txn(true)
 	defer txn.Abort()
 
-	// Delete the tokens
-	for _, id := range ids {
-		if _, err := txn.DeleteAll("acl_token", "id", id); err != nil {
-			return fmt.Errorf("deleting acl token failed: %v", err)
+	// Delete the role
+	for _, name := range names {
+		if _, err := txn.DeleteAll("acl_role", "id", name); err != nil {
+			return fmt.Errorf("deleting acl role failed: %v", err)
 		}
 	}
-	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+	if err := txn.Insert("index", &IndexEntry{"acl_role", index}); err != nil {
 		return fmt.Errorf("index update failed: %v", err)
 	}
 	txn.Commit()
 	return nil
 }
 
-// ACLTokenByAccessorID is used to lookup a token by accessor ID
-func (s *StateStore) ACLTokenByAccessorID(ws memdb.WatchSet, id string) (*structs.ACLToken, error) {
-	txn := s.db.Txn(false)
-
-	watchCh, existing, err := txn.FirstWatch("acl_token", "id", id)
-	if err != nil {
-		return nil, fmt.Errorf("acl token lookup failed: %v", err)
-	}
-	ws.Add(watchCh)
-
-	if existing != nil {
-		return existing.(*structs.ACLToken), nil
-	}
-	return nil, nil
-}
-
-// ACLTokenBySecretID is used to lookup a token by secret ID
-func (s *StateStore) ACLTokenBySecretID(ws memdb.WatchSet, secretID string) (*structs.ACLToken, error) {
+// ACLRoleByName is used to lookup a role by name
+func (s *StateStore) ACLRoleByName(ws memdb.WatchSet, name string) (*structs.ACLRole, error) {
 	txn := s.db.Txn(false)
 
-	watchCh, existing, err := txn.FirstWatch("acl_token", "secret", secretID)
+	watchCh, existing, err := txn.FirstWatch("acl_role", "id", name)
 	if err != nil {
-		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+		return nil, fmt.Errorf("acl role lookup failed: %v", err)
 	}
 	ws.Add(watchCh)
 
 	if existing != nil {
-		return existing.(*structs.ACLToken), nil
+		return existing.(*structs.ACLRole), nil
 	}
 	return nil, nil
 }
 
-// ACLTokenByAccessorIDPrefix is used to lookup tokens by prefix
-func (s *StateStore) ACLTokenByAccessorIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+// ACLRoleByNamePrefix is used to lookup roles by prefix
+func (s *StateStore) ACLRoleByNamePrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
 
-	iter, err := txn.Get("acl_token", "id_prefix", prefix)
+	iter, err := txn.Get("acl_role", "id_prefix", prefix)
 	if err != nil {
-		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+		return nil, fmt.Errorf("acl role lookup failed: %v", err)
 	}
 	ws.Add(iter.WatchCh())
+
 	return iter, nil
 }
 
-// ACLTokens returns an iterator over all the tokens
-func (s *StateStore) ACLTokens(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+// ACLRoles returns an iterator over all the acl roles
+func (s *StateStore) ACLRoles(ws memdb.WatchSet) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
 
 	// Walk the entire table
-	iter, err := txn.Get("acl_token", "id")
+	iter, err := txn.Get("acl_role", "id")
 	if err != nil {
 		return nil, err
 	}
@@ -3759,8 +4088,682 @@ func (s *StateStore) ACLTokens(ws memdb.WatchSet) (memdb.ResultIterator, error)
 	return iter, nil
 }
 
-// ACLTokensByGlobal returns an iterator over all the tokens filtered by global value
-func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool) (memdb.ResultIterator, error) {
+// UpsertACLAuthMethods is used to create or update a set of auth methods
+func (s *StateStore) UpsertACLAuthMethods(index uint64, methods []*structs.ACLAuthMethod) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, method := range methods {
+		// Ensure the method hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(method.Hash) == 0 {
+			method.SetHash()
+		}
+
+		// Check if the method already exists
+		existing, err := txn.First("acl_auth_method", "id", method.Name)
+		if err != nil {
+			return fmt.Errorf("auth method lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			method.CreateIndex = existing.(*structs.ACLAuthMethod).CreateIndex
+			method.ModifyIndex = index
+		} else {
+			method.CreateIndex = index
+			method.ModifyIndex = index
+		}
+
+		// Update the method
+		if err := txn.Insert("acl_auth_method", method); err != nil {
+			return fmt.Errorf("upserting auth method failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_auth_method", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLAuthMethods deletes the auth methods with the given names along
+// with their binding rules
+func (s *StateStore) DeleteACLAuthMethods(index uint64, names []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, name := range names {
+		if _, err := txn.DeleteAll("acl_auth_method", "id", name); err != nil {
+			return fmt.Errorf("deleting auth method failed: %v", err)
+		}
+		if _, err := txn.DeleteAll("acl_binding_rule", "auth_method", name); err != nil {
+			return fmt.Errorf("deleting binding rules failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_auth_method", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rule", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLAuthMethodByName is used to lookup an auth method by name
+func (s *StateStore) ACLAuthMethodByName(ws memdb.WatchSet, name string) (*structs.ACLAuthMethod, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_auth_method", "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("auth method lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLAuthMethod), nil
+	}
+	return nil, nil
+}
+
+// ACLAuthMethods returns an iterator over all the auth methods
+func (s *StateStore) ACLAuthMethods(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_auth_method", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertACLBindingRules is used to create or update a set of binding rules
+func (s *StateStore) UpsertACLBindingRules(index uint64, rules []*structs.ACLBindingRule) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, rule := range rules {
+		// Ensure the rule hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(rule.Hash) == 0 {
+			rule.SetHash()
+		}
+
+		// Check if the rule already exists
+		existing, err := txn.First("acl_binding_rule", "id", rule.ID)
+		if err != nil {
+			return fmt.Errorf("binding rule lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			rule.CreateIndex = existing.(*structs.ACLBindingRule).CreateIndex
+			rule.ModifyIndex = index
+		} else {
+			rule.CreateIndex = index
+			rule.ModifyIndex = index
+		}
+
+		// Update the rule
+		if err := txn.Insert("acl_binding_rule", rule); err != nil {
+			return fmt.Errorf("upserting binding rule failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rule", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLBindingRules deletes the binding rules with the given IDs
+func (s *StateStore) DeleteACLBindingRules(index uint64, ids []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		if _, err := txn.DeleteAll("acl_binding_rule", "id", id); err != nil {
+			return fmt.Errorf("deleting binding rule failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_binding_rule", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLBindingRuleByID is used to lookup a binding rule by ID
+func (s *StateStore) ACLBindingRuleByID(ws memdb.WatchSet, id string) (*structs.ACLBindingRule, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_binding_rule", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("binding rule lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLBindingRule), nil
+	}
+	return nil, nil
+}
+
+// ACLBindingRuleByIDPrefix is used to lookup binding rules by ID prefix
+func (s *StateStore) ACLBindingRuleByIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("acl_binding_rule", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("binding rule lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLBindingRulesByAuthMethod returns an iterator over the binding rules of
+// an auth method
+func (s *StateStore) ACLBindingRulesByAuthMethod(ws memdb.WatchSet, method string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("acl_binding_rule", "auth_method", method)
+	if err != nil {
+		return nil, fmt.Errorf("binding rule lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLBindingRules returns an iterator over all the binding rules
+func (s *StateStore) ACLBindingRules(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_binding_rule", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertRootKeyMeta is used to create or update the metadata of a root key.
+// If the key is active, any other active key is marked inactive.
+func (s *StateStore) UpsertRootKeyMeta(index uint64, key *structs.RootKeyMeta) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("root_keys", "id", key.KeyID)
+	if err != nil {
+		return fmt.Errorf("root key lookup failed: %v", err)
+	}
+	if existing != nil {
+		key.CreateIndex = existing.(*structs.RootKeyMeta).CreateIndex
+	} else {
+		key.CreateIndex = index
+	}
+	key.ModifyIndex = index
+
+	if key.Active {
+		iter, err := txn.Get("root_keys", "id")
+		if err != nil {
+			return fmt.Errorf("root key lookup failed: %v", err)
+		}
+
+		var deactivate []*structs.RootKeyMeta
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			other := raw.(*structs.RootKeyMeta)
+			if other.Active && other.KeyID != key.KeyID {
+				deactivate = append(deactivate, other)
+			}
+		}
+
+		for _, other := range deactivate {
+			other = other.Copy()
+			other.Active = false
+			other.ModifyIndex = index
+			if err := txn.Insert("root_keys", other); err != nil {
+				return fmt.Errorf("updating root key failed: %v", err)
+			}
+		}
+	}
+
+	if err := txn.Insert("root_keys", key); err != nil {
+		return fmt.Errorf("upserting root key failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"root_keys", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// RootKeyMetaByID is used to lookup the metadata of a root key by its ID
+func (s *StateStore) RootKeyMetaByID(ws memdb.WatchSet, id string) (*structs.RootKeyMeta, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("root_keys", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("root key lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.RootKeyMeta), nil
+	}
+	return nil, nil
+}
+
+// ActiveRootKeyMeta returns the metadata of the root key used to sign new
+// workload identities, or nil if no key has been created yet
+func (s *StateStore) ActiveRootKeyMeta(ws memdb.WatchSet) (*structs.RootKeyMeta, error) {
+	iter, err := s.RootKeyMetas(ws)
+	if err != nil {
+		return nil, err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if key := raw.(*structs.RootKeyMeta); key.Active {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+// RootKeyMetas returns an iterator over the metadata of all the root keys
+func (s *StateStore) RootKeyMetas(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("root_keys", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertVariable is used to create or update an encrypted variable. If
+// checkIndex is non-zero, the variable is only updated if it still exists with
+// that modify index.
+func (s *StateStore) UpsertVariable(index uint64, v *structs.VariableEncrypted, checkIndex uint64) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("variables", "id", v.Namespace, v.Path)
+	if err != nil {
+		return fmt.Errorf("variable lookup failed: %v", err)
+	}
+	if checkIndex != 0 && (existing == nil || existing.(*structs.VariableEncrypted).ModifyIndex != checkIndex) {
+		return structs.ErrVariableConflict
+	}
+	if existing != nil {
+		exist := existing.(*structs.VariableEncrypted)
+		v.CreateIndex = exist.CreateIndex
+		v.CreateTime = exist.CreateTime
+	} else {
+		v.CreateIndex = index
+	}
+	v.ModifyIndex = index
+
+	if err := txn.Insert("variables", v); err != nil {
+		return fmt.Errorf("upserting variable failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteVariable is used to delete a variable
+func (s *StateStore) DeleteVariable(index uint64, namespace, path string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("variables", "id", namespace, path)
+	if err != nil {
+		return fmt.Errorf("variable lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("variable not found")
+	}
+	if err := txn.Delete("variables", existing); err != nil {
+		return fmt.Errorf("deleting variable failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"variables", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// GetVariable is used to lookup the variable at a path of a namespace
+func (s *StateStore) GetVariable(ws memdb.WatchSet, namespace, path string) (*structs.VariableEncrypted, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("variables", "id", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.VariableEncrypted), nil
+	}
+	return nil, nil
+}
+
+// VariablesByPathPrefix returns an iterator over the variables of a
+// namespace whose path starts with the given prefix
+func (s *StateStore) VariablesByPathPrefix(ws memdb.WatchSet, namespace, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("variables", "id_prefix", namespace, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// Variables returns an iterator over all the variables
+func (s *StateStore) Variables(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("variables", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertServiceRegistrations is used to register or update service instances
+func (s *StateStore) UpsertServiceRegistrations(index uint64, services []*structs.ServiceRegistration) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, service := range services {
+		// Don't resurrect the services of stopped allocations when a client
+		// update races with the allocation's status update
+		alloc, err := txn.First("allocs", "id", service.AllocID)
+		if err != nil {
+			return fmt.Errorf("alloc lookup failed: %v", err)
+		}
+		if alloc != nil && alloc.(*structs.Allocation).ClientTerminalStatus() {
+			continue
+		}
+
+		existing, err := txn.First("service_registrations", "id", service.Namespace, service.ID)
+		if err != nil {
+			return fmt.Errorf("service registration lookup failed: %v", err)
+		}
+		if existing != nil {
+			service.CreateIndex = existing.(*structs.ServiceRegistration).CreateIndex
+		} else {
+			service.CreateIndex = index
+		}
+		service.ModifyIndex = index
+
+		if err := txn.Insert("service_registrations", service); err != nil {
+			return fmt.Errorf("upserting service registration failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// DeleteServiceRegistrationByID is used to remove a service instance
+func (s *StateStore) DeleteServiceRegistrationByID(index uint64, namespace, id string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	existing, err := txn.First("service_registrations", "id", namespace, id)
+	if err != nil {
+		return fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	if existing == nil {
+		return fmt.Errorf("service registration not found")
+	}
+	if err := txn.Delete("service_registrations", existing); err != nil {
+		return fmt.Errorf("deleting service registration failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	txn.Commit()
+	return nil
+}
+
+// deleteServiceRegistrations removes the service instances matching the
+// given index and value, such as the instances of an allocation or node.
+func (s *StateStore) deleteServiceRegistrations(txn *memdb.Txn, index uint64, indexName, value string) error {
+	num, err := txn.DeleteAll("service_registrations", indexName, value)
+	if err != nil {
+		return fmt.Errorf("deleting service registrations failed: %v", err)
+	}
+	if num == 0 {
+		return nil
+	}
+	if err := txn.Insert("index", &IndexEntry{"service_registrations", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return nil
+}
+
+// GetServiceRegistrations returns an iterator over all the service instances
+func (s *StateStore) GetServiceRegistrations(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetServiceRegistrationsByNamespace returns an iterator over the service
+// instances of a namespace, ordered by ID
+func (s *StateStore) GetServiceRegistrationsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "id_prefix", namespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetServiceRegistrationsByName returns an iterator over the instances of a
+// service of a namespace
+func (s *StateStore) GetServiceRegistrationsByName(ws memdb.WatchSet, namespace, name string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "service_name", namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetServiceRegistrationByID is used to lookup a service instance
+func (s *StateStore) GetServiceRegistrationByID(ws memdb.WatchSet, namespace, id string) (*structs.ServiceRegistration, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("service_registrations", "id", namespace, id)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ServiceRegistration), nil
+	}
+	return nil, nil
+}
+
+// GetServiceRegistrationsByAllocID returns the service instances of an
+// allocation
+func (s *StateStore) GetServiceRegistrationsByAllocID(ws memdb.WatchSet, allocID string) ([]*structs.ServiceRegistration, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("service_registrations", "alloc_id", allocID)
+	if err != nil {
+		return nil, fmt.Errorf("service registration lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var out []*structs.ServiceRegistration
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		out = append(out, raw.(*structs.ServiceRegistration))
+	}
+	return out, nil
+}
+
+// UpsertACLTokens is used to create or update a set of ACL tokens
+func (s *StateStore) UpsertACLTokens(index uint64, tokens []*structs.ACLToken) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	for _, token := range tokens {
+		// Ensure the policy hash is non-nil. This should be done outside the state store
+		// for performance reasons, but we check here for defense in depth.
+		if len(token.Hash) == 0 {
+			token.SetHash()
+		}
+
+		// Check if the token already exists
+		existing, err := txn.First("acl_token", "id", token.AccessorID)
+		if err != nil {
+			return fmt.Errorf("token lookup failed: %v", err)
+		}
+
+		// Update all the indexes
+		if existing != nil {
+			existTK := existing.(*structs.ACLToken)
+			token.CreateIndex = existTK.CreateIndex
+			token.ModifyIndex = index
+
+			// Do not allow SecretID or create time to change
+			token.SecretID = existTK.SecretID
+			token.CreateTime = existTK.CreateTime
+
+		} else {
+			token.CreateIndex = index
+			token.ModifyIndex = index
+		}
+
+		// Update the token
+		if err := txn.Insert("acl_token", token); err != nil {
+			return fmt.Errorf("upserting token failed: %v", err)
+		}
+	}
+
+	// Update the indexes table
+	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// DeleteACLTokens deletes the tokens with the given accessor ids
+func (s *StateStore) DeleteACLTokens(index uint64, ids []string) error {
+	txn := s.db.Txn(true)
+	defer txn.Abort()
+
+	// Delete the tokens
+	for _, id := range ids {
+		if _, err := txn.DeleteAll("acl_token", "id", id); err != nil {
+			return fmt.Errorf("deleting acl token failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	txn.Commit()
+	return nil
+}
+
+// ACLTokenByAccessorID is used to lookup a token by accessor ID
+func (s *StateStore) ACLTokenByAccessorID(ws memdb.WatchSet, id string) (*structs.ACLToken, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_token", "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLToken), nil
+	}
+	return nil, nil
+}
+
+// ACLTokenBySecretID is used to lookup a token by secret ID
+func (s *StateStore) ACLTokenBySecretID(ws memdb.WatchSet, secretID string) (*structs.ACLToken, error) {
+	txn := s.db.Txn(false)
+
+	watchCh, existing, err := txn.FirstWatch("acl_token", "secret", secretID)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+
+	if existing != nil {
+		return existing.(*structs.ACLToken), nil
+	}
+	return nil, nil
+}
+
+// ACLTokenByAccessorIDPrefix is used to lookup tokens by prefix
+func (s *StateStore) ACLTokenByAccessorIDPrefix(ws memdb.WatchSet, prefix string) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	iter, err := txn.Get("acl_token", "id_prefix", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("acl token lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLTokens returns an iterator over all the tokens
+func (s *StateStore) ACLTokens(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.Txn(false)
+
+	// Walk the entire table
+	iter, err := txn.Get("acl_token", "id")
+	if err != nil {
+		return nil, err
+	}
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ACLTokensByGlobal returns an iterator over all the tokens filtered by global value
+func (s *StateStore) ACLTokensByGlobal(ws memdb.WatchSet, globalVal bool) (memdb.ResultIterator, error) {
 	txn := s.db.Txn(false)
 
 	// Walk the entire table
@@ -3948,6 +4951,14 @@ func (r *StateRestore) VaultAccessorRestore(accessor *structs.VaultAccessor) err
 	return nil
 }
 
+// NamespaceRestore is used to restore a namespace
+func (r *StateRestore) NamespaceRestore(ns *structs.Namespace) error {
+	if err := r.txn.Insert("namespaces", ns); err != nil {
+		return fmt.Errorf("namespace insert failed: %v", err)
+	}
+	return nil
+}
+
 // ACLPolicyRestore is used to restore an ACL policy
 func (r *StateRestore) ACLPolicyRestore(policy *structs.ACLPolicy) error {
 	if err := r.txn.Insert("acl_policy", policy); err != nil {
@@ -3956,6 +4967,54 @@ func (r *StateRestore) ACLPolicyRestore(policy *structs.ACLPolicy) error {
 	return nil
 }
 
+// ACLRoleRestore is used to restore an ACL role
+func (r *StateRestore) ACLRoleRestore(role *structs.ACLRole) error {
+	if err := r.txn.Insert("acl_role", role); err != nil {
+		return fmt.Errorf("inserting acl role failed: %v", err)
+	}
+	return nil
+}
+
+// ACLAuthMethodRestore is used to restore an auth method
+func (r *StateRestore) ACLAuthMethodRestore(method *structs.ACLAuthMethod) error {
+	if err := r.txn.Insert("acl_auth_method", method); err != nil {
+		return fmt.Errorf("inserting auth method failed: %v", err)
+	}
+	return nil
+}
+
+// ACLBindingRuleRestore is used to restore a binding rule
+func (r *StateRestore) ACLBindingRuleRestore(rule *structs.ACLBindingRule) error {
+	if err := r.txn.Insert("acl_binding_rule", rule); err != nil {
+		return fmt.Errorf("inserting binding rule failed: %v", err)
+	}
+	return nil
+}
+
+// RootKeyMetaRestore is used to restore the metadata of a root key
+func (r *StateRestore) RootKeyMetaRestore(key *structs.RootKeyMeta) error {
+	if err := r.txn.Insert("root_keys", key); err != nil {
+		return fmt.Errorf("inserting root key failed: %v", err)
+	}
+	return nil
+}
+
+// VariableRestore is used to restore an encrypted variable
+func (r *StateRestore) VariableRestore(v *structs.VariableEncrypted) error {
+	if err := r.txn.Insert("variables", v); err != nil {
+		return fmt.Errorf("inserting variable failed: %v", err)
+	}
+	return nil
+}
+
+// ServiceRegistrationRestore is used to restore a service instance
+func (r *StateRestore) ServiceRegistrationRestore(service *structs.ServiceRegistration) error {
+	if err := r.txn.Insert("service_registrations", service); err != nil {
+		return fmt.Errorf("inserting service registration failed: %v", err)
+	}
+	return nil
+}
+
 // ACLTokenRestore is used to restore an ACL token
 func (r *StateRestore) ACLTokenRestore(token *structs.ACLToken) error {
 	if err := r.txn.Insert("acl_token", token); err != nil {
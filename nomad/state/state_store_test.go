@@ -1402,6 +1402,27 @@ func TestStateStore_UpsertJob_BadNamespace(t *testing.T) {
 	assert.Nil(out)
 }
 
+func TestStateStore_UpsertJob_Namespace(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	ns := mock.Namespace()
+	require.NoError(state.UpsertNamespaces(999, []*structs.Namespace{ns}))
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	require.NoError(state.UpsertJob(1000, job))
+
+	out, err := state.JobByID(nil, ns.Name, job.ID)
+	require.NoError(err)
+	require.Equal(job, out)
+
+	// The job isn't listed in the default namespace
+	iter, err := state.JobsByNamespace(nil, structs.DefaultNamespace)
+	require.NoError(err)
+	require.Nil(iter.Next())
+}
+
 // Upsert a job that is the child of a parent job and ensures its summary gets
 // updated.
 func TestStateStore_UpsertJob_ChildJob(t *testing.T) {
@@ -3159,6 +3180,35 @@ func TestStateStore_Evals(t *testing.T) {
 	}
 }
 
+func TestStateStore_EvalsRelatedToID(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	// Chain evals through their links, including a cycle and a missing eval
+	e1 := mock.Eval()
+	e2 := mock.Eval()
+	e3 := mock.Eval()
+	e4 := mock.Eval()
+	e1.NextEval = e2.ID
+	e2.PreviousEval = e1.ID
+	e2.BlockedEval = e3.ID
+	e3.PreviousEval = e2.ID
+	e3.NextEval = uuid.Generate()
+	require.NoError(state.UpsertEvals(1000, []*structs.Evaluation{e1, e2, e3}))
+	require.NoError(state.UpsertEvals(1001, []*structs.Evaluation{e4}))
+
+	related, err := state.EvalsRelatedToID(nil, e2.ID)
+	require.NoError(err)
+	require.Len(related, 2)
+	ids := []string{related[0].ID, related[1].ID}
+	require.ElementsMatch([]string{e1.ID, e3.ID}, ids)
+
+	related, err = state.EvalsRelatedToID(nil, e4.ID)
+	require.NoError(err)
+	require.Empty(related)
+}
+
 func TestStateStore_EvalsByIDPrefix(t *testing.T) {
 	state := testStateStore(t)
 	var evals []*structs.Evaluation
@@ -4659,6 +4709,72 @@ func TestStateStore_AllocsByIDPrefix(t *testing.T) {
 	}
 }
 
+func TestStateStore_AllocsByClientStatus(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	alloc1 := mock.Alloc()
+	alloc2 := mock.Alloc()
+	alloc2.JobID = alloc1.JobID
+	alloc2.ClientStatus = structs.AllocClientStatusFailed
+	require.NoError(state.UpsertJobSummary(998, mock.JobSummary(alloc1.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc1, alloc2}))
+
+	ws := memdb.NewWatchSet()
+	iter, err := state.AllocsByClientStatus(ws, structs.DefaultNamespace, structs.AllocClientStatusFailed)
+	require.NoError(err)
+
+	var out []*structs.Allocation
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		out = append(out, raw.(*structs.Allocation))
+	}
+	require.Len(out, 1)
+	require.Equal(alloc2.ID, out[0].ID)
+
+	// Updating the client status of an allocation fires the watch
+	update := alloc1.Copy()
+	update.ClientStatus = structs.AllocClientStatusFailed
+	require.NoError(state.UpdateAllocsFromClient(1001, []*structs.Allocation{update}))
+	require.True(watchFired(ws))
+
+	iter, err = state.AllocsByClientStatus(nil, structs.DefaultNamespace, structs.AllocClientStatusFailed)
+	require.NoError(err)
+	out = nil
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		out = append(out, raw.(*structs.Allocation))
+	}
+	require.Len(out, 2)
+}
+
+func TestStateStore_AllocsByClientStatus_Missing(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	state := testStateStore(t)
+
+	// Allocations created by the schedulers have no client status yet
+	alloc := mock.Alloc()
+	alloc.ClientStatus = ""
+	require.NoError(state.UpsertJobSummary(998, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	out, err := state.AllocByID(nil, alloc.ID)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// The allocation is indexed once the client reports its status
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusRunning
+	require.NoError(state.UpdateAllocsFromClient(1001, []*structs.Allocation{update}))
+
+	iter, err := state.AllocsByClientStatus(nil, structs.DefaultNamespace, structs.AllocClientStatusRunning)
+	require.NoError(err)
+	raw := iter.Next()
+	require.NotNil(raw)
+	require.Equal(alloc.ID, raw.(*structs.Allocation).ID)
+	require.Nil(iter.Next())
+}
+
 func TestStateStore_Allocs(t *testing.T) {
 	state := testStateStore(t)
 	var allocs []*structs.Allocation
@@ -6748,6 +6864,658 @@ func TestStateStore_ACLTokenByAccessorIDPrefix(t *testing.T) {
 	assert.Equal(t, expect, out)
 }
 
+func TestStateStore_DefaultNamespace(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	out, err := state.NamespaceByName(nil, structs.DefaultNamespace)
+	require.NoError(err)
+	require.NotNil(out)
+	require.Equal(structs.DefaultNamespaceDescription, out.Description)
+
+	// The default namespace can't be deleted
+	err = state.DeleteNamespaces(1000, []string{structs.DefaultNamespace})
+	require.Error(err)
+	require.Contains(err.Error(), "can not be deleted")
+}
+
+func TestStateStore_UpsertNamespaces(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+
+	ws := memdb.NewWatchSet()
+	_, err := state.NamespaceByName(ws, ns1.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.NamespaceByName(ws, ns1.Name)
+	require.NoError(err)
+	require.Equal(ns1, out)
+
+	out, err = state.NamespaceByName(ws, ns2.Name)
+	require.NoError(err)
+	require.Equal(ns2, out)
+
+	index, err := state.Index("namespaces")
+	require.NoError(err)
+	require.EqualValues(1000, index)
+
+	// Update a namespace and ensure the create index is preserved
+	ns3 := ns1.Copy()
+	ns3.Description = "updated"
+	ns3.SetHash()
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{ns3}))
+	require.True(watchFired(ws))
+
+	out, err = state.NamespaceByName(nil, ns1.Name)
+	require.NoError(err)
+	require.Equal("updated", out.Description)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+}
+
+func TestStateStore_DeleteNamespaces(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	ns1 := mock.Namespace()
+	ns2 := mock.Namespace()
+
+	require.NoError(state.UpsertNamespaces(1000, []*structs.Namespace{ns1, ns2}))
+
+	ws := memdb.NewWatchSet()
+	_, err := state.NamespaceByName(ws, ns1.Name)
+	require.NoError(err)
+
+	require.NoError(state.DeleteNamespaces(1001, []string{ns1.Name, ns2.Name}))
+	require.True(watchFired(ws))
+
+	out, err := state.NamespaceByName(nil, ns1.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	// Only the default namespace is left
+	iter, err := state.Namespaces(nil)
+	require.NoError(err)
+	raw := iter.Next()
+	require.NotNil(raw)
+	require.Equal(structs.DefaultNamespace, raw.(*structs.Namespace).Name)
+	require.Nil(iter.Next())
+
+	index, err := state.Index("namespaces")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+}
+
+func TestStateStore_DeleteNamespaces_NonTerminalJobs(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	ns := mock.Namespace()
+	require.NoError(state.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	require.NoError(state.UpsertJob(1001, job))
+
+	err := state.DeleteNamespaces(1002, []string{ns.Name})
+	require.Error(err)
+	require.Contains(err.Error(), "one non-terminal")
+
+	out, err := state.NamespaceByName(nil, ns.Name)
+	require.NoError(err)
+	require.NotNil(out)
+
+	// The namespace can be deleted once the job is dead
+	eval := mock.Eval()
+	eval.Namespace = ns.Name
+	eval.JobID = job.ID
+	eval.Status = structs.EvalStatusComplete
+	require.NoError(state.UpsertEvals(1003, []*structs.Evaluation{eval}))
+	require.NoError(state.DeleteNamespaces(1004, []string{ns.Name}))
+}
+
+func TestStateStore_NamespacesByNamePrefix(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	names := []string{
+		"foo",
+		"bar",
+		"foobar",
+		"foozip",
+		"zip",
+	}
+
+	// Create the namespaces
+	var baseIndex uint64 = 1000
+	for _, name := range names {
+		ns := mock.Namespace()
+		ns.Name = name
+		require.NoError(state.UpsertNamespaces(baseIndex, []*structs.Namespace{ns}))
+		baseIndex++
+	}
+
+	// Scan by prefix
+	iter, err := state.NamespacesByNamePrefix(nil, "foo")
+	require.NoError(err)
+
+	out := []string{}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		out = append(out, raw.(*structs.Namespace).Name)
+	}
+	sort.Strings(out)
+	require.Equal([]string{"foo", "foobar", "foozip"}, out)
+}
+
+func TestStateStore_RestoreNamespace(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	ns := mock.Namespace()
+
+	restore, err := state.Restore()
+	require.NoError(err)
+
+	require.NoError(restore.NamespaceRestore(ns))
+	restore.Commit()
+
+	out, err := state.NamespaceByName(nil, ns.Name)
+	require.NoError(err)
+	require.Equal(ns, out)
+}
+
+func TestStateStore_AllNamespaces(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	ns := mock.Namespace()
+	require.NoError(state.UpsertNamespaces(999, []*structs.Namespace{ns}))
+
+	job1 := mock.Job()
+	job2 := mock.Job()
+	job2.Namespace = ns.Name
+	require.NoError(state.UpsertJob(1000, job1))
+	require.NoError(state.UpsertJob(1001, job2))
+
+	alloc1 := mock.Alloc()
+	alloc1.Job = job1
+	alloc1.JobID = job1.ID
+	alloc2 := mock.Alloc()
+	alloc2.Job = job2
+	alloc2.JobID = job2.ID
+	alloc2.Namespace = ns.Name
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{alloc1, alloc2}))
+
+	count := func(iter memdb.ResultIterator, err error) int {
+		require.NoError(err)
+		n := 0
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			n++
+		}
+		return n
+	}
+
+	require.Equal(2, count(state.JobsByNamespace(nil, structs.AllNamespacesSentinel)))
+	require.Equal(1, count(state.JobsByIDPrefix(nil, structs.AllNamespacesSentinel, job2.ID)))
+	require.Equal(2, count(state.AllocsByNamespace(nil, structs.AllNamespacesSentinel)))
+	require.Equal(1, count(state.AllocsByIDPrefix(nil, structs.AllNamespacesSentinel, alloc2.ID[:8])))
+	require.Equal(2, count(state.AllocsByClientStatus(nil, structs.AllNamespacesSentinel, alloc1.ClientStatus)))
+}
+
+func TestStateStore_UpsertACLRole(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	role := mock.ACLRole()
+	role2 := mock.ACLRole()
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLRoleByName(ws, role.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role, role2}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLRoleByName(ws, role.Name)
+	require.NoError(err)
+	require.Equal(role, out)
+
+	out, err = state.ACLRoleByName(ws, role2.Name)
+	require.NoError(err)
+	require.Equal(role2, out)
+
+	iter, err := state.ACLRoles(ws)
+	require.NoError(err)
+
+	// Ensure we see both roles
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(2, count)
+
+	index, err := state.Index("acl_role")
+	require.NoError(err)
+	require.EqualValues(1000, index)
+
+	// Update a role and ensure the create index is preserved
+	role3 := new(structs.ACLRole)
+	*role3 = *role
+	role3.Description = "updated"
+	role3.SetHash()
+	require.NoError(state.UpsertACLRoles(1001, []*structs.ACLRole{role3}))
+	require.True(watchFired(ws))
+
+	out, err = state.ACLRoleByName(nil, role.Name)
+	require.NoError(err)
+	require.Equal("updated", out.Description)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+}
+
+func TestStateStore_DeleteACLRole(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	role := mock.ACLRole()
+	role2 := mock.ACLRole()
+
+	require.NoError(state.UpsertACLRoles(1000, []*structs.ACLRole{role, role2}))
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLRoleByName(ws, role.Name)
+	require.NoError(err)
+
+	require.NoError(state.DeleteACLRoles(1001, []string{role.Name, role2.Name}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLRoleByName(ws, role.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	iter, err := state.ACLRoles(ws)
+	require.NoError(err)
+	require.Nil(iter.Next())
+
+	index, err := state.Index("acl_role")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+}
+
+func TestStateStore_ACLRoleByNamePrefix(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	names := []string{
+		"foo",
+		"bar",
+		"foobar",
+		"foozip",
+		"zip",
+	}
+
+	// Create the roles
+	var baseIndex uint64 = 1000
+	for _, name := range names {
+		r := mock.ACLRole()
+		r.Name = name
+		require.NoError(state.UpsertACLRoles(baseIndex, []*structs.ACLRole{r}))
+		baseIndex++
+	}
+
+	// Scan by prefix
+	iter, err := state.ACLRoleByNamePrefix(nil, "foo")
+	require.NoError(err)
+
+	out := []string{}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		out = append(out, raw.(*structs.ACLRole).Name)
+	}
+	sort.Strings(out)
+	require.Equal([]string{"foo", "foobar", "foozip"}, out)
+}
+
+func TestStateStore_RestoreACLRole(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	role := mock.ACLRole()
+
+	restore, err := state.Restore()
+	require.NoError(err)
+
+	require.NoError(restore.ACLRoleRestore(role))
+	restore.Commit()
+
+	out, err := state.ACLRoleByName(nil, role.Name)
+	require.NoError(err)
+	require.Equal(role, out)
+}
+
+func TestStateStore_UpsertDeleteACLAuthMethod(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	method := mock.ACLAuthMethod()
+	method2 := mock.ACLAuthMethod()
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLAuthMethodByName(ws, method.Name)
+	require.NoError(err)
+
+	require.NoError(state.UpsertACLAuthMethods(1000, []*structs.ACLAuthMethod{method, method2}))
+	require.True(watchFired(ws))
+
+	ws = memdb.NewWatchSet()
+	out, err := state.ACLAuthMethodByName(ws, method.Name)
+	require.NoError(err)
+	require.Equal(method, out)
+
+	iter, err := state.ACLAuthMethods(ws)
+	require.NoError(err)
+	count := 0
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		count++
+	}
+	require.Equal(2, count)
+
+	// Add a binding rule to each method
+	rule := mock.ACLBindingRule()
+	rule.AuthMethod = method.Name
+	rule2 := mock.ACLBindingRule()
+	rule2.AuthMethod = method2.Name
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule, rule2}))
+
+	// Deleting a method deletes its binding rules
+	require.NoError(state.DeleteACLAuthMethods(1002, []string{method.Name}))
+	require.True(watchFired(ws))
+
+	out, err = state.ACLAuthMethodByName(nil, method.Name)
+	require.NoError(err)
+	require.Nil(out)
+
+	outRule, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Nil(outRule)
+
+	outRule, err = state.ACLBindingRuleByID(nil, rule2.ID)
+	require.NoError(err)
+	require.Equal(rule2, outRule)
+
+	for _, table := range []string{"acl_auth_method", "acl_binding_rule"} {
+		index, err := state.Index(table)
+		require.NoError(err)
+		require.EqualValues(1002, index)
+	}
+}
+
+func TestStateStore_UpsertDeleteACLBindingRule(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	rule := mock.ACLBindingRule()
+	rule2 := mock.ACLBindingRule()
+	rule2.AuthMethod = "other"
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLBindingRuleByID(ws, rule.ID)
+	require.NoError(err)
+
+	require.NoError(state.UpsertACLBindingRules(1000, []*structs.ACLBindingRule{rule, rule2}))
+	require.True(watchFired(ws))
+
+	out, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Equal(rule, out)
+
+	iter, err := state.ACLBindingRulesByAuthMethod(nil, "other")
+	require.NoError(err)
+	raw := iter.Next()
+	require.NotNil(raw)
+	require.Equal(rule2.ID, raw.(*structs.ACLBindingRule).ID)
+	require.Nil(iter.Next())
+
+	iter, err = state.ACLBindingRuleByIDPrefix(nil, rule.ID[:8])
+	require.NoError(err)
+	require.Equal(rule.ID, iter.Next().(*structs.ACLBindingRule).ID)
+
+	// Update a rule and ensure the create index is preserved
+	rule3 := new(structs.ACLBindingRule)
+	*rule3 = *rule
+	rule3.BindName = "updated"
+	rule3.SetHash()
+	require.NoError(state.UpsertACLBindingRules(1001, []*structs.ACLBindingRule{rule3}))
+
+	out, err = state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Equal("updated", out.BindName)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+
+	ws = memdb.NewWatchSet()
+	_, err = state.ACLBindingRuleByID(ws, rule.ID)
+	require.NoError(err)
+	require.NoError(state.DeleteACLBindingRules(1002, []string{rule.ID, rule2.ID}))
+	require.True(watchFired(ws))
+
+	iter, err = state.ACLBindingRules(nil)
+	require.NoError(err)
+	require.Nil(iter.Next())
+
+	index, err := state.Index("acl_binding_rule")
+	require.NoError(err)
+	require.EqualValues(1002, index)
+}
+
+func TestStateStore_RestoreACLAuthMethodAndBindingRule(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	method := mock.ACLAuthMethod()
+	rule := mock.ACLBindingRule()
+
+	restore, err := state.Restore()
+	require.NoError(err)
+
+	require.NoError(restore.ACLAuthMethodRestore(method))
+	require.NoError(restore.ACLBindingRuleRestore(rule))
+	restore.Commit()
+
+	out, err := state.ACLAuthMethodByName(nil, method.Name)
+	require.NoError(err)
+	require.Equal(method, out)
+
+	outRule, err := state.ACLBindingRuleByID(nil, rule.ID)
+	require.NoError(err)
+	require.Equal(rule, outRule)
+}
+
+func TestStateStore_UpsertRootKeyMeta(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	key1 := mock.RootKey().Meta
+	key2 := mock.RootKey().Meta
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ActiveRootKeyMeta(ws)
+	require.NoError(err)
+
+	require.NoError(state.UpsertRootKeyMeta(1000, key1))
+	require.True(watchFired(ws))
+
+	out, err := state.ActiveRootKeyMeta(nil)
+	require.NoError(err)
+	require.Equal(key1.KeyID, out.KeyID)
+	require.EqualValues(1000, out.CreateIndex)
+
+	// Activating another key deactivates the first one
+	require.NoError(state.UpsertRootKeyMeta(1001, key2))
+
+	out, err = state.ActiveRootKeyMeta(nil)
+	require.NoError(err)
+	require.Equal(key2.KeyID, out.KeyID)
+
+	out, err = state.RootKeyMetaByID(nil, key1.KeyID)
+	require.NoError(err)
+	require.False(out.Active)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+
+	// The stored key isn't modified
+	require.True(key1.Active)
+
+	index, err := state.Index("root_keys")
+	require.NoError(err)
+	require.EqualValues(1001, index)
+}
+
+func TestStateStore_UpsertDeleteVariable(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	sv := &structs.VariableEncrypted{
+		VariableMetadata: structs.VariableMetadata{
+			Namespace:  structs.DefaultNamespace,
+			Path:       "app/web",
+			CreateTime: 1,
+			ModifyTime: 1,
+		},
+		Data:  []byte("encrypted"),
+		KeyID: "key",
+	}
+	other := sv.Copy()
+	other.Path = "other"
+
+	ws := memdb.NewWatchSet()
+	_, err := state.GetVariable(ws, sv.Namespace, sv.Path)
+	require.NoError(err)
+
+	require.NoError(state.UpsertVariable(1000, sv, 0))
+	require.NoError(state.UpsertVariable(1001, other, 0))
+	require.True(watchFired(ws))
+
+	// Updating keeps the create index and time
+	update := sv.Copy()
+	update.CreateTime = 2
+	update.ModifyTime = 2
+	require.NoError(state.UpsertVariable(1002, update, 0))
+
+	out, err := state.GetVariable(nil, sv.Namespace, sv.Path)
+	require.NoError(err)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1002, out.ModifyIndex)
+	require.EqualValues(1, out.CreateTime)
+	require.EqualValues(2, out.ModifyTime)
+
+	iter, err := state.VariablesByPathPrefix(nil, sv.Namespace, "app/")
+	require.NoError(err)
+	require.Equal(sv.Path, iter.Next().(*structs.VariableEncrypted).Path)
+	require.Nil(iter.Next())
+
+	ws = memdb.NewWatchSet()
+	_, err = state.GetVariable(ws, sv.Namespace, sv.Path)
+	require.NoError(err)
+	require.NoError(state.DeleteVariable(1003, sv.Namespace, sv.Path))
+	require.True(watchFired(ws))
+
+	out, err = state.GetVariable(nil, sv.Namespace, sv.Path)
+	require.NoError(err)
+	require.Nil(out)
+	require.Error(state.DeleteVariable(1004, sv.Namespace, sv.Path))
+
+	// Check-and-set updates are rejected once the variable was modified or
+	// deleted
+	require.Equal(structs.ErrVariableConflict, state.UpsertVariable(1004, sv.Copy(), 1002))
+	require.Equal(structs.ErrVariableConflict, state.UpsertVariable(1004, other.Copy(), 1000))
+	require.NoError(state.UpsertVariable(1004, other.Copy(), 1001))
+
+	index, err := state.Index("variables")
+	require.NoError(err)
+	require.EqualValues(1004, index)
+}
+
+func TestStateStore_ServiceRegistrations(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+	services := mock.ServiceRegistrations()
+
+	ws := memdb.NewWatchSet()
+	_, err := state.GetServiceRegistrationsByName(ws, structs.DefaultNamespace, "example-cache")
+	require.NoError(err)
+
+	require.NoError(state.UpsertServiceRegistrations(1000, services))
+	require.True(watchFired(ws))
+
+	// Updating keeps the create index
+	update := services[0].Copy()
+	update.Port = 24000
+	require.NoError(state.UpsertServiceRegistrations(1001, []*structs.ServiceRegistration{update}))
+
+	out, err := state.GetServiceRegistrationByID(nil, update.Namespace, update.ID)
+	require.NoError(err)
+	require.EqualValues(1000, out.CreateIndex)
+	require.EqualValues(1001, out.ModifyIndex)
+	require.Equal(24000, out.Port)
+
+	iter, err := state.GetServiceRegistrationsByName(nil, structs.DefaultNamespace, "countdash-api")
+	require.NoError(err)
+	require.Equal(services[1].ID, iter.Next().(*structs.ServiceRegistration).ID)
+	require.Nil(iter.Next())
+
+	// Deleting by ID
+	require.NoError(state.DeleteServiceRegistrationByID(1002, update.Namespace, update.ID))
+	require.Error(state.DeleteServiceRegistrationByID(1003, update.Namespace, update.ID))
+	out, err = state.GetServiceRegistrationByID(nil, update.Namespace, update.ID)
+	require.NoError(err)
+	require.Nil(out)
+
+	index, err := state.Index("service_registrations")
+	require.NoError(err)
+	require.EqualValues(1002, index)
+}
+
+func TestStateStore_ServiceRegistrations_TerminalAlloc(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	alloc := mock.Alloc()
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	service := mock.ServiceRegistrations()[0]
+	service.AllocID = alloc.ID
+	service.NodeID = alloc.NodeID
+	require.NoError(state.UpsertServiceRegistrations(1001, []*structs.ServiceRegistration{service}))
+
+	// Stopping the allocation removes its services
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(state.UpdateAllocsFromClient(1002, []*structs.Allocation{update}))
+
+	out, err := state.GetServiceRegistrationsByAllocID(nil, alloc.ID)
+	require.NoError(err)
+	require.Empty(out)
+
+	// And late registrations are ignored
+	require.NoError(state.UpsertServiceRegistrations(1003, []*structs.ServiceRegistration{service}))
+	out, err = state.GetServiceRegistrationsByAllocID(nil, alloc.ID)
+	require.NoError(err)
+	require.Empty(out)
+}
+
+func TestStateStore_ServiceRegistrations_NodeDown(t *testing.T) {
+	require := require.New(t)
+	state := testStateStore(t)
+
+	node := mock.Node()
+	require.NoError(state.UpsertNode(1000, node))
+
+	service := mock.ServiceRegistrations()[0]
+	service.NodeID = node.ID
+	require.NoError(state.UpsertServiceRegistrations(1001, []*structs.ServiceRegistration{service}))
+
+	require.NoError(state.UpdateNodeStatus(1002, node.ID, structs.NodeStatusDown, nil))
+
+	out, err := state.GetServiceRegistrationByID(nil, service.Namespace, service.ID)
+	require.NoError(err)
+	require.Nil(out)
+}
+
 func TestStateStore_RestoreACLPolicy(t *testing.T) {
 	state := testStateStore(t)
 	policy := mock.ACLPolicy()
@@ -0,0 +1,169 @@
+// Package stream publishes the changes of the state of the cluster as events
+// that subscribers can filter by topic and key, and resume from an index.
+package stream
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventBroker holds the most recent events published by the FSM and notifies
+// the subscriptions of new events.
+type EventBroker struct {
+	l sync.Mutex
+
+	// buffer holds the most recent events, ordered by index. Events are only
+	// ever appended or dropped from the front so subscriptions can read a
+	// copy of the slice without holding the lock.
+	buffer []*structs.Events
+
+	// buffered is the number of events in the buffer and size the maximum
+	buffered int
+	size     int
+
+	// publishCh is closed and replaced when events are published
+	publishCh chan struct{}
+}
+
+// NewEventBroker returns an event broker keeping up to size events for the
+// subscriptions resuming from an index.
+func NewEventBroker(size int) *EventBroker {
+	if size < 1 {
+		size = 1
+	}
+	return &EventBroker{
+		size:      size,
+		publishCh: make(chan struct{}),
+	}
+}
+
+// Publish adds the events of a raft index to the buffer, dropping the oldest
+// events if it is full, and notifies the subscriptions.
+func (b *EventBroker) Publish(events *structs.Events) {
+	if len(events.Events) == 0 {
+		return
+	}
+
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	b.buffer = append(b.buffer, events)
+	b.buffered += len(events.Events)
+
+	// Always keep the latest events
+	drop := 0
+	for b.buffered > b.size && drop < len(b.buffer)-1 {
+		b.buffered -= len(b.buffer[drop].Events)
+		drop++
+	}
+	b.buffer = b.buffer[drop:]
+
+	close(b.publishCh)
+	b.publishCh = make(chan struct{})
+}
+
+// snapshot returns the buffered events and the channel closed when new events
+// are published
+func (b *EventBroker) snapshot() ([]*structs.Events, <-chan struct{}) {
+	b.l.Lock()
+	defer b.l.Unlock()
+	return b.buffer, b.publishCh
+}
+
+// SubscribeRequest is the filter of a subscription
+type SubscribeRequest struct {
+	// Topics maps the topics to the keys of the events to stream, "*"
+	// matching all of them. All the events match if it is empty.
+	Topics map[structs.Topic][]string
+
+	// Namespace is the namespace of the events of namespaced objects
+	Namespace string
+
+	// Index is the index after which events are streamed. Only new events
+	// are streamed if it is zero. If the events following the index have
+	// already been dropped from the buffer, the subscription starts at the
+	// oldest buffered event.
+	Index uint64
+}
+
+// Subscription returns the events matching its filter in index order
+type Subscription struct {
+	broker *EventBroker
+	req    *SubscribeRequest
+
+	// index is the index of the last events returned
+	index uint64
+}
+
+// Subscribe returns a subscription to the events matching the request
+func (b *EventBroker) Subscribe(req *SubscribeRequest) *Subscription {
+	sub := &Subscription{
+		broker: b,
+		req:    req,
+		index:  req.Index,
+	}
+	if sub.index == 0 {
+		if buffer, _ := b.snapshot(); len(buffer) != 0 {
+			sub.index = buffer[len(buffer)-1].Index
+		}
+	}
+	return sub
+}
+
+// Next blocks until events matching the subscription are published after the
+// last ones it returned, or the context is done.
+func (s *Subscription) Next(ctx context.Context) (*structs.Events, error) {
+	for {
+		buffer, publishCh := s.broker.snapshot()
+		i := sort.Search(len(buffer), func(i int) bool {
+			return buffer[i].Index > s.index
+		})
+		for ; i < len(buffer); i++ {
+			s.index = buffer[i].Index
+			if events := s.filter(buffer[i]); events != nil {
+				return events, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-publishCh:
+		}
+	}
+}
+
+// filter returns the events matching the subscription or nil if none does
+func (s *Subscription) filter(events *structs.Events) *structs.Events {
+	var matching []structs.Event
+	for _, e := range events.Events {
+		if s.req.matches(&e) {
+			matching = append(matching, e)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+	return &structs.Events{Index: events.Index, Events: matching}
+}
+
+// matches returns whether an event matches the filter of a subscription
+func (r *SubscribeRequest) matches(e *structs.Event) bool {
+	if e.Namespace != "" && e.Namespace != r.Namespace {
+		return false
+	}
+	if len(r.Topics) == 0 {
+		return true
+	}
+	for _, topic := range []structs.Topic{e.Topic, structs.TopicAll} {
+		for _, key := range r.Topics[topic] {
+			if key == "*" || key == e.Key {
+				return true
+			}
+		}
+	}
+	return false
+}
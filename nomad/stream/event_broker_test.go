@@ -0,0 +1,127 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvents(index uint64, events ...structs.Event) *structs.Events {
+	for i := range events {
+		events[i].Index = index
+	}
+	return &structs.Events{Index: index, Events: events}
+}
+
+func jobEvent(namespace, id string) structs.Event {
+	return JobEvent(structs.TypeJobRegistered, namespace, id, nil)
+}
+
+func nextEvents(t *testing.T, sub *Subscription) *structs.Events {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	events, err := sub.Next(ctx)
+	require.NoError(t, err)
+	return events
+}
+
+func TestEventBroker_Subscribe(t *testing.T) {
+	require := require.New(t)
+	b := NewEventBroker(100)
+	b.Publish(testEvents(10, jobEvent("default", "old")))
+
+	// Only new events are streamed without index
+	sub := b.Subscribe(&SubscribeRequest{Namespace: "default"})
+
+	doneCh := make(chan *structs.Events)
+	go func() {
+		doneCh <- nextEvents(t, sub)
+	}()
+	b.Publish(testEvents(11, jobEvent("default", "example")))
+
+	select {
+	case events := <-doneCh:
+		require.Equal(uint64(11), events.Index)
+		require.Len(events.Events, 1)
+		require.Equal("example", events.Events[0].Key)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for events")
+	}
+
+	// The subscription ends with its context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := sub.Next(ctx)
+	require.Equal(context.Canceled, err)
+}
+
+func TestEventBroker_Filter(t *testing.T) {
+	require := require.New(t)
+	b := NewEventBroker(100)
+	b.Publish(testEvents(10,
+		jobEvent("default", "web"),
+		jobEvent("default", "api"),
+		jobEvent("other", "web"),
+		NodeEvent(structs.TypeNodeRegistration, "node1", nil),
+	))
+	b.Publish(testEvents(11, jobEvent("other", "api")))
+	b.Publish(testEvents(12, NodeEvent(structs.TypeNodeDeregistration, "node1", nil)))
+
+	// Keys of a topic, and only the events of the namespace
+	sub := b.Subscribe(&SubscribeRequest{
+		Topics:    map[structs.Topic][]string{structs.TopicJob: {"web"}},
+		Namespace: "default",
+		Index:     1,
+	})
+	events := nextEvents(t, sub)
+	require.Equal(uint64(10), events.Index)
+	require.Len(events.Events, 1)
+	require.Equal("web", events.Events[0].Key)
+	require.Equal("default", events.Events[0].Namespace)
+
+	// All the keys of a topic, skipping the indexes without matching events
+	sub = b.Subscribe(&SubscribeRequest{
+		Topics:    map[structs.Topic][]string{structs.TopicNode: {"*"}},
+		Namespace: "default",
+		Index:     10,
+	})
+	events = nextEvents(t, sub)
+	require.Equal(uint64(12), events.Index)
+	require.Equal(structs.TypeNodeDeregistration, events.Events[0].Type)
+
+	// All the topics
+	sub = b.Subscribe(&SubscribeRequest{
+		Topics:    map[structs.Topic][]string{structs.TopicAll: {"*"}},
+		Namespace: "other",
+		Index:     9,
+	})
+	events = nextEvents(t, sub)
+	require.Len(events.Events, 2)
+	require.Equal(uint64(11), nextEvents(t, sub).Index)
+	require.Equal(uint64(12), nextEvents(t, sub).Index)
+}
+
+func TestEventBroker_BufferSize(t *testing.T) {
+	require := require.New(t)
+	b := NewEventBroker(2)
+	for i := uint64(1); i <= 4; i++ {
+		b.Publish(testEvents(i, jobEvent("default", "example")))
+	}
+
+	// Resuming from a dropped index starts at the oldest buffered event
+	sub := b.Subscribe(&SubscribeRequest{Namespace: "default", Index: 1})
+	require.Equal(uint64(3), nextEvents(t, sub).Index)
+	require.Equal(uint64(4), nextEvents(t, sub).Index)
+
+	// The latest events are kept even if they exceed the size
+	b.Publish(testEvents(5,
+		jobEvent("default", "a"),
+		jobEvent("default", "b"),
+		jobEvent("default", "c"),
+	))
+	require.Len(b.buffer, 1)
+	require.Len(nextEvents(t, sub).Events, 3)
+}
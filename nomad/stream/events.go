@@ -0,0 +1,68 @@
+package stream
+
+import "github.com/hashicorp/nomad/nomad/structs"
+
+// JobEvent returns an event of the Job topic
+func JobEvent(eventType string, namespace, jobID string, job *structs.Job) structs.Event {
+	return structs.Event{
+		Topic:     structs.TopicJob,
+		Type:      eventType,
+		Key:       jobID,
+		Namespace: namespace,
+		Payload:   &structs.EventPayload{Job: job},
+	}
+}
+
+// AllocEvent returns an event of the Allocation topic. The job of the
+// allocation is removed from the payload to keep the events small, and the
+// workload identities are removed as they are only for the tasks they were
+// issued to.
+func AllocEvent(eventType string, alloc *structs.Allocation) structs.Event {
+	alloc = alloc.CopySkipJob()
+	alloc.Job = nil
+	alloc.SignedIdentities = nil
+	return structs.Event{
+		Topic:     structs.TopicAllocation,
+		Type:      eventType,
+		Key:       alloc.ID,
+		Namespace: alloc.Namespace,
+		Payload:   &structs.EventPayload{Allocation: alloc},
+	}
+}
+
+// EvalEvent returns an event of the Evaluation topic
+func EvalEvent(eventType string, eval *structs.Evaluation) structs.Event {
+	return structs.Event{
+		Topic:     structs.TopicEvaluation,
+		Type:      eventType,
+		Key:       eval.ID,
+		Namespace: eval.Namespace,
+		Payload:   &structs.EventPayload{Evaluation: eval},
+	}
+}
+
+// DeploymentEvent returns an event of the Deployment topic
+func DeploymentEvent(eventType string, deployment *structs.Deployment) structs.Event {
+	return structs.Event{
+		Topic:     structs.TopicDeployment,
+		Type:      eventType,
+		Key:       deployment.ID,
+		Namespace: deployment.Namespace,
+		Payload:   &structs.EventPayload{Deployment: deployment},
+	}
+}
+
+// NodeEvent returns an event of the Node topic. The secret ID of the node is
+// removed from the payload.
+func NodeEvent(eventType string, nodeID string, node *structs.Node) structs.Event {
+	if node != nil {
+		node = node.Copy()
+		node.SecretID = ""
+	}
+	return structs.Event{
+		Topic:   structs.TopicNode,
+		Type:    eventType,
+		Key:     nodeID,
+		Payload: &structs.EventPayload{Node: node},
+	}
+}
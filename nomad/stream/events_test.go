@@ -0,0 +1,25 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocEvent_RemovesIdentities(t *testing.T) {
+	require := require.New(t)
+	alloc := mock.Alloc()
+	alloc.SignedIdentities = map[string]string{"web": "token"}
+
+	event := AllocEvent(structs.TypeAllocUpdated, alloc)
+	require.Equal(alloc.ID, event.Key)
+	require.NotNil(event.Payload.Allocation)
+	require.Nil(event.Payload.Allocation.SignedIdentities)
+	require.Nil(event.Payload.Allocation.Job)
+
+	// The allocation in the state store is left untouched
+	require.Equal("token", alloc.SignedIdentities["web"])
+	require.NotNil(alloc.Job)
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// AdmissionConfig is the configuration of the admission controllers the
+// servers apply to the jobs on registration
+type AdmissionConfig struct {
+	// DefaultConstraints are added to the jobs lacking them
+	DefaultConstraints []*AdmissionConstraint `mapstructure:"default_constraint"`
+
+	// RequiredMeta are the meta keys every job must set
+	RequiredMeta []string `mapstructure:"required_meta"`
+
+	// DenyPrivilegedDocker rejects the jobs with privileged Docker tasks
+	DenyPrivilegedDocker *bool `mapstructure:"deny_privileged_docker"`
+
+	// Webhooks are external admission controllers called over HTTP
+	Webhooks []*AdmissionWebhook `mapstructure:"webhook"`
+}
+
+// AdmissionConstraint is a constraint added to the jobs
+type AdmissionConstraint struct {
+	Attribute string `mapstructure:"attribute"`
+	Operator  string `mapstructure:"operator"`
+	Value     string `mapstructure:"value"`
+}
+
+const (
+	// AdmissionWebhookMutating is the type of the webhooks that may modify
+	// the job
+	AdmissionWebhookMutating = "mutating"
+
+	// AdmissionWebhookValidating is the type of the webhooks that may only
+	// accept or reject the job
+	AdmissionWebhookValidating = "validating"
+)
+
+// AdmissionWebhook is an external admission controller the job is sent to
+type AdmissionWebhook struct {
+	// Name is the name of the webhook
+	Name string `mapstructure:"-"`
+
+	// Type is "mutating" or "validating"
+	Type string `mapstructure:"type"`
+
+	// Address is the URL the job is posted to
+	Address string `mapstructure:"address"`
+
+	// Timeout is how long to wait for a response
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// FailOpen admits the job if the webhook can't be reached or errors,
+	// instead of rejecting it
+	FailOpen bool `mapstructure:"fail_open"`
+}
+
+// Copy returns a copy of the admission configuration
+func (a *AdmissionConfig) Copy() *AdmissionConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := *a
+	nc.DefaultConstraints = nil
+	for _, c := range a.DefaultConstraints {
+		ncc := *c
+		nc.DefaultConstraints = append(nc.DefaultConstraints, &ncc)
+	}
+	nc.RequiredMeta = helper.CopySliceString(a.RequiredMeta)
+	if a.DenyPrivilegedDocker != nil {
+		nc.DenyPrivilegedDocker = helper.BoolToPtr(*a.DenyPrivilegedDocker)
+	}
+	nc.Webhooks = nil
+	for _, w := range a.Webhooks {
+		nw := *w
+		nc.Webhooks = append(nc.Webhooks, &nw)
+	}
+	return &nc
+}
+
+// Merge is used to merge two admission configs together. The settings from
+// the input always take precedence, its constraints and required meta are
+// added, and its webhooks replace the ones with the same name.
+func (a *AdmissionConfig) Merge(b *AdmissionConfig) *AdmissionConfig {
+	result := a.Copy()
+	for _, c := range b.DefaultConstraints {
+		nc := *c
+		result.DefaultConstraints = append(result.DefaultConstraints, &nc)
+	}
+	existing := helper.SliceStringToSet(result.RequiredMeta)
+	for _, k := range b.RequiredMeta {
+		if _, ok := existing[k]; !ok {
+			result.RequiredMeta = append(result.RequiredMeta, k)
+		}
+	}
+	if b.DenyPrivilegedDocker != nil {
+		result.DenyPrivilegedDocker = helper.BoolToPtr(*b.DenyPrivilegedDocker)
+	}
+
+WEBHOOKS:
+	for _, w := range b.Webhooks {
+		nw := *w
+		for i, rw := range result.Webhooks {
+			if rw.Name == w.Name {
+				result.Webhooks[i] = &nw
+				continue WEBHOOKS
+			}
+		}
+		result.Webhooks = append(result.Webhooks, &nw)
+	}
+	return result
+}
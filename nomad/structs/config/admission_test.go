@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmissionConfig_Merge(t *testing.T) {
+	require := require.New(t)
+
+	c1 := &AdmissionConfig{
+		DefaultConstraints: []*AdmissionConstraint{
+			{Attribute: "${attr.kernel.name}", Value: "linux"},
+		},
+		RequiredMeta:         []string{"owner"},
+		DenyPrivilegedDocker: helper.BoolToPtr(true),
+		Webhooks: []*AdmissionWebhook{
+			{Name: "a", Type: AdmissionWebhookValidating, Address: "http://a"},
+		},
+	}
+
+	c2 := &AdmissionConfig{
+		DefaultConstraints: []*AdmissionConstraint{
+			{Attribute: "${meta.rack}", Operator: "!=", Value: "r1"},
+		},
+		RequiredMeta: []string{"owner", "team"},
+		Webhooks: []*AdmissionWebhook{
+			{Name: "a", Type: AdmissionWebhookMutating, Address: "http://other", Timeout: time.Second},
+			{Name: "b", Type: AdmissionWebhookValidating, Address: "http://b"},
+		},
+	}
+
+	merged := c1.Merge(c2)
+	require.Len(merged.DefaultConstraints, 2)
+	require.Equal([]string{"owner", "team"}, merged.RequiredMeta)
+	require.True(*merged.DenyPrivilegedDocker)
+	require.Equal([]*AdmissionWebhook{
+		{Name: "a", Type: AdmissionWebhookMutating, Address: "http://other", Timeout: time.Second},
+		{Name: "b", Type: AdmissionWebhookValidating, Address: "http://b"},
+	}, merged.Webhooks)
+
+	// The inputs are left untouched
+	require.Len(c1.DefaultConstraints, 1)
+	require.Equal("http://a", c1.Webhooks[0].Address)
+
+	merged = merged.Merge(&AdmissionConfig{DenyPrivilegedDocker: helper.BoolToPtr(false)})
+	require.False(*merged.DenyPrivilegedDocker)
+}
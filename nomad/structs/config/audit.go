@@ -0,0 +1,141 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/helper"
+)
+
+// AuditConfig is the configuration of the audit logging of the API requests
+type AuditConfig struct {
+	// Enabled enables the audit logging
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Sinks are where the audit events are written
+	Sinks []*AuditSink `mapstructure:"sink"`
+
+	// Filters exclude events from the audit log
+	Filters []*AuditFilter `mapstructure:"filter"`
+}
+
+// AuditSink is the configuration of a destination of the audit events
+type AuditSink struct {
+	// Name is the name of the sink
+	Name string `mapstructure:"-"`
+
+	// Type is the type of sink, only "file" is supported
+	Type string `mapstructure:"type"`
+
+	// DeliveryGuarantee is "enforced" to fail the requests whose events
+	// can't be written, or "best-effort" to only log the failures
+	DeliveryGuarantee string `mapstructure:"delivery_guarantee"`
+
+	// Format is the format of the events, only "json" is supported
+	Format string `mapstructure:"format"`
+
+	// Path is the path of the file the events are written to
+	Path string `mapstructure:"path"`
+
+	// Mode is the file mode of the audit log, in octal
+	Mode string `mapstructure:"mode"`
+
+	// RotateDuration is how long a file is written to before it's rotated.
+	// Zero disables the rotation on time.
+	RotateDuration time.Duration `mapstructure:"rotate_duration"`
+
+	// RotateBytes is the size of a file after which it's rotated. Zero
+	// disables the rotation on size.
+	RotateBytes int `mapstructure:"rotate_bytes"`
+
+	// RotateMaxFiles is the number of rotated files to keep. Zero keeps all
+	// of them.
+	RotateMaxFiles int `mapstructure:"rotate_max_files"`
+}
+
+// AuditFilter excludes the matching events from the audit log
+type AuditFilter struct {
+	// Name is the name of the filter
+	Name string `mapstructure:"-"`
+
+	// Type is the type of events filtered, only "HTTPEvent" is supported
+	Type string `mapstructure:"type"`
+
+	// Endpoints are the request paths filtered, which may end in a "*"
+	// wildcard
+	Endpoints []string `mapstructure:"endpoints"`
+
+	// Stages are the stages filtered, or "*" for all
+	Stages []string `mapstructure:"stages"`
+
+	// Operations are the HTTP methods filtered, or "*" for all
+	Operations []string `mapstructure:"operations"`
+}
+
+// DefaultAuditConfig returns the default audit configuration, with the audit
+// logging disabled
+func DefaultAuditConfig() *AuditConfig {
+	return &AuditConfig{
+		Enabled: helper.BoolToPtr(false),
+	}
+}
+
+// Copy returns a copy of the audit configuration
+func (a *AuditConfig) Copy() *AuditConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := *a
+	if a.Enabled != nil {
+		nc.Enabled = helper.BoolToPtr(*a.Enabled)
+	}
+	nc.Sinks = nil
+	for _, s := range a.Sinks {
+		ns := *s
+		nc.Sinks = append(nc.Sinks, &ns)
+	}
+	nc.Filters = nil
+	for _, f := range a.Filters {
+		nf := *f
+		nf.Endpoints = helper.CopySliceString(f.Endpoints)
+		nf.Stages = helper.CopySliceString(f.Stages)
+		nf.Operations = helper.CopySliceString(f.Operations)
+		nc.Filters = append(nc.Filters, &nf)
+	}
+	return &nc
+}
+
+// Merge is used to merge two audit configs together. The settings from the
+// input always take precedence, and its sinks and filters replace the ones
+// with the same name.
+func (a *AuditConfig) Merge(b *AuditConfig) *AuditConfig {
+	result := a.Copy()
+	if b.Enabled != nil {
+		result.Enabled = helper.BoolToPtr(*b.Enabled)
+	}
+
+SINKS:
+	for _, s := range b.Sinks {
+		ns := *s
+		for i, rs := range result.Sinks {
+			if rs.Name == s.Name {
+				result.Sinks[i] = &ns
+				continue SINKS
+			}
+		}
+		result.Sinks = append(result.Sinks, &ns)
+	}
+
+FILTERS:
+	for _, f := range b.Filters {
+		nf := *f
+		for i, rf := range result.Filters {
+			if rf.Name == f.Name {
+				result.Filters[i] = &nf
+				continue FILTERS
+			}
+		}
+		result.Filters = append(result.Filters, &nf)
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditConfig_Merge(t *testing.T) {
+	require := require.New(t)
+
+	c1 := &AuditConfig{
+		Enabled: helper.BoolToPtr(true),
+		Sinks: []*AuditSink{
+			{Name: "a", Type: "file", Path: "/tmp/a.log"},
+			{Name: "b", Type: "file", Path: "/tmp/b.log"},
+		},
+		Filters: []*AuditFilter{
+			{Name: "f", Type: "HTTPEvent", Endpoints: []string{"/v1/metrics"}},
+		},
+	}
+
+	c2 := &AuditConfig{
+		Sinks: []*AuditSink{
+			{Name: "b", Type: "file", Path: "/tmp/other.log"},
+			{Name: "c", Type: "file", Path: "/tmp/c.log"},
+		},
+	}
+
+	// Sinks with the same name are replaced, the others are appended
+	merged := c1.Merge(c2)
+	require.True(*merged.Enabled)
+	require.Equal([]*AuditSink{
+		{Name: "a", Type: "file", Path: "/tmp/a.log"},
+		{Name: "b", Type: "file", Path: "/tmp/other.log"},
+		{Name: "c", Type: "file", Path: "/tmp/c.log"},
+	}, merged.Sinks)
+	require.Equal(c1.Filters, merged.Filters)
+
+	// The inputs are left untouched
+	require.Equal("/tmp/b.log", c1.Sinks[1].Path)
+	require.Len(c1.Sinks, 2)
+
+	merged = merged.Merge(&AuditConfig{Enabled: helper.BoolToPtr(false)})
+	require.False(*merged.Enabled)
+	require.Len(merged.Sinks, 3)
+}
@@ -24,7 +24,7 @@ type AutopilotConfig struct {
 	// be behind before being considered unhealthy.
 	MaxTrailingLogs int `mapstructure:"max_trailing_logs"`
 
-	// (Enterprise-only) EnableRedundancyZones specifies whether to enable redundancy zones.
+	// EnableRedundancyZones specifies whether to enable redundancy zones.
 	EnableRedundancyZones *bool `mapstructure:"enable_redundancy_zones"`
 
 	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
@@ -55,6 +55,10 @@ type ConsulConfig struct {
 	// Addr is the address of the local Consul agent
 	Addr string `mapstructure:"address"`
 
+	// GRPCAddr is the address of the gRPC endpoint of the local Consul
+	// agent, which configures the sidecar proxies of Connect services
+	GRPCAddr string `mapstructure:"grpc_address"`
+
 	// Timeout is used by Consul HTTP Client
 	Timeout time.Duration `mapstructure:"timeout"`
 
@@ -107,6 +111,7 @@ func DefaultConsulConfig() *ConsulConfig {
 		ServerAutoJoin:      helper.BoolToPtr(true),
 		ClientAutoJoin:      helper.BoolToPtr(true),
 		Timeout:             5 * time.Second,
+		GRPCAddr:            "127.0.0.1:8502",
 	}
 }
 
@@ -138,6 +143,9 @@ func (a *ConsulConfig) Merge(b *ConsulConfig) *ConsulConfig {
 	if b.Addr != "" {
 		result.Addr = b.Addr
 	}
+	if b.GRPCAddr != "" {
+		result.GRPCAddr = b.GRPCAddr
+	}
 	if b.Timeout != 0 {
 		result.Timeout = b.Timeout
 	}
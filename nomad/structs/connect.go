@@ -0,0 +1,367 @@
+package structs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/copystructure"
+)
+
+const (
+	// ConnectProxyPrefix is the prefix of the kind of the tasks running the
+	// sidecar proxy of a Connect service, and of the names of the tasks and
+	// ports injected for them.
+	ConnectProxyPrefix = "connect-proxy"
+)
+
+// TaskKind identifies the tasks Nomad manages on behalf of the user, in the
+// form "<kind>:<value>". It is empty for the tasks of the user.
+type TaskKind string
+
+// NewTaskKind returns the kind of a task from its name and value.
+func NewTaskKind(name, value string) TaskKind {
+	return TaskKind(fmt.Sprintf("%s:%s", name, value))
+}
+
+// Name returns the name of the kind.
+func (k TaskKind) Name() string {
+	return strings.SplitN(string(k), ":", 2)[0]
+}
+
+// Value returns the value of the kind, or an empty string if there is none.
+func (k TaskKind) Value() string {
+	if s := strings.SplitN(string(k), ":", 2); len(s) > 1 {
+		return s[1]
+	}
+	return ""
+}
+
+// IsConnectProxy returns whether the task runs the sidecar proxy of a Connect
+// service, whose name is the value of the kind.
+func (k TaskKind) IsConnectProxy() bool {
+	return k.Name() == ConnectProxyPrefix && k.Value() != ""
+}
+
+// ConnectProxyName returns the name of the task and port injected for the
+// sidecar proxy of a Connect service.
+func ConnectProxyName(service string) string {
+	return fmt.Sprintf("%s-%s", ConnectProxyPrefix, service)
+}
+
+// ConsulConnect configures a service to join the Consul Connect service mesh.
+type ConsulConnect struct {
+	// SidecarService is the sidecar proxy registered for the service.
+	SidecarService *ConsulSidecarService
+
+	// SidecarTask overrides the task injected to run the sidecar proxy.
+	SidecarTask *SidecarTask
+}
+
+func (c *ConsulConnect) Copy() *ConsulConnect {
+	if c == nil {
+		return nil
+	}
+	return &ConsulConnect{
+		SidecarService: c.SidecarService.Copy(),
+		SidecarTask:    c.SidecarTask.Copy(),
+	}
+}
+
+// HasSidecar returns whether a sidecar proxy must be run for the service.
+func (c *ConsulConnect) HasSidecar() bool {
+	return c != nil && c.SidecarService != nil
+}
+
+// Validate returns an error if the Connect configuration is invalid.
+func (c *ConsulConnect) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.SidecarService == nil {
+		return fmt.Errorf("Consul Connect must have a sidecar_service")
+	}
+
+	var mErr multierror.Error
+	if err := c.SidecarService.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if err := c.SidecarTask.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ConsulSidecarService is the sidecar proxy service of a Connect service.
+type ConsulSidecarService struct {
+	// Tags are the tags of the sidecar service.
+	Tags []string
+
+	// Port is the label of the group port the proxy listens on. If empty a
+	// dynamic port is added to the group network for it.
+	Port string
+
+	// Proxy configures the sidecar proxy.
+	Proxy *ConsulProxy
+}
+
+func (s *ConsulSidecarService) Copy() *ConsulSidecarService {
+	if s == nil {
+		return nil
+	}
+	return &ConsulSidecarService{
+		Tags:  helper.CopySliceString(s.Tags),
+		Port:  s.Port,
+		Proxy: s.Proxy.Copy(),
+	}
+}
+
+// Validate returns an error if the sidecar service is invalid.
+func (s *ConsulSidecarService) Validate() error {
+	if s == nil {
+		return nil
+	}
+	return s.Proxy.Validate()
+}
+
+// ConsulProxy configures the sidecar proxy of a Connect service.
+type ConsulProxy struct {
+	// LocalServiceAddress is the address the proxy forwards the inbound
+	// connections to. It defaults to 127.0.0.1, the service being in the
+	// network namespace of the proxy.
+	LocalServiceAddress string
+
+	// LocalServicePort is the port the proxy forwards the inbound
+	// connections to. It defaults to the port of the service in the
+	// network namespace of the allocation.
+	LocalServicePort int
+
+	// Upstreams are the services the proxy exposes locally to the tasks.
+	Upstreams []*ConsulUpstream
+
+	// Config is the opaque configuration of the proxy, passed to Consul.
+	Config map[string]interface{}
+}
+
+func (p *ConsulProxy) Copy() *ConsulProxy {
+	if p == nil {
+		return nil
+	}
+
+	np := new(ConsulProxy)
+	*np = *p
+
+	if p.Upstreams != nil {
+		np.Upstreams = make([]*ConsulUpstream, len(p.Upstreams))
+		for i, u := range p.Upstreams {
+			np.Upstreams[i] = u.Copy()
+		}
+	}
+
+	if i, err := copystructure.Copy(p.Config); err != nil {
+		panic(err.Error())
+	} else if i != nil {
+		np.Config = i.(map[string]interface{})
+	}
+	return np
+}
+
+// Validate returns an error if the proxy configuration is invalid.
+func (p *ConsulProxy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if p.LocalServicePort < 0 || p.LocalServicePort >= maxValidPort {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Proxy local_service_port must be between 1 and %d; got %d", maxValidPort-1, p.LocalServicePort))
+	}
+
+	destinations := make(map[string]struct{}, len(p.Upstreams))
+	binds := make(map[int]string, len(p.Upstreams))
+	for i, u := range p.Upstreams {
+		if err := u.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Upstream %d validation failed: %v", i+1, err))
+			continue
+		}
+		if _, ok := destinations[u.DestinationName]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Upstream %q is duplicate", u.DestinationName))
+		}
+		destinations[u.DestinationName] = struct{}{}
+		if other, ok := binds[u.LocalBindPort]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Upstreams %q and %q bind the same local port %d", other, u.DestinationName, u.LocalBindPort))
+		}
+		binds[u.LocalBindPort] = u.DestinationName
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ConsulUpstream is a service the sidecar proxy exposes locally.
+type ConsulUpstream struct {
+	// DestinationName is the name of the upstream service.
+	DestinationName string
+
+	// LocalBindPort is the port the proxy listens on for the upstream, in
+	// the network namespace of the allocation.
+	LocalBindPort int
+}
+
+func (u *ConsulUpstream) Copy() *ConsulUpstream {
+	if u == nil {
+		return nil
+	}
+	nu := new(ConsulUpstream)
+	*nu = *u
+	return nu
+}
+
+// Validate returns an error if the upstream is invalid.
+func (u *ConsulUpstream) Validate() error {
+	var mErr multierror.Error
+	if u.DestinationName == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Missing destination_name"))
+	}
+	if u.LocalBindPort <= 0 || u.LocalBindPort >= maxValidPort {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("local_bind_port must be between 1 and %d; got %d", maxValidPort-1, u.LocalBindPort))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// SidecarTask overrides the fields of the task injected to run the sidecar
+// proxy of a Connect service.
+type SidecarTask struct {
+	// Driver is the driver running the proxy. It can't be overridden, as the
+	// client only bootstraps the stock Envoy task.
+	Driver string
+
+	// User is the user running the proxy.
+	User string
+
+	// Config is the configuration of the driver. It can't be overridden.
+	Config map[string]interface{}
+
+	// Env are the environment variables of the proxy.
+	Env map[string]string
+
+	// Resources are the resources of the proxy.
+	Resources *Resources
+
+	// Meta is the metadata of the task.
+	Meta map[string]string
+
+	// KillTimeout is the time between signaling the proxy that it will be
+	// killed and killing it.
+	KillTimeout *time.Duration
+
+	// LogConfig configures the rotation of the logs of the proxy.
+	LogConfig *LogConfig
+
+	// ShutdownDelay is the delay between deregistering the services and
+	// signaling the proxy to shut down.
+	ShutdownDelay *time.Duration
+
+	// KillSignal is the signal sent to the proxy to kill it.
+	KillSignal string
+}
+
+func (t *SidecarTask) Copy() *SidecarTask {
+	if t == nil {
+		return nil
+	}
+
+	nt := new(SidecarTask)
+	*nt = *t
+	nt.Env = helper.CopyMapStringString(nt.Env)
+	nt.Meta = helper.CopyMapStringString(nt.Meta)
+	nt.Resources = nt.Resources.Copy()
+
+	if t.LogConfig != nil {
+		lc := *t.LogConfig
+		nt.LogConfig = &lc
+	}
+	if t.KillTimeout != nil {
+		nt.KillTimeout = helper.TimeToPtr(*t.KillTimeout)
+	}
+	if t.ShutdownDelay != nil {
+		nt.ShutdownDelay = helper.TimeToPtr(*t.ShutdownDelay)
+	}
+
+	if i, err := copystructure.Copy(nt.Config); err != nil {
+		panic(err.Error())
+	} else if i != nil {
+		nt.Config = i.(map[string]interface{})
+	}
+	return nt
+}
+
+// Validate returns an error if the overrides of the sidecar task are invalid.
+func (t *SidecarTask) Validate() error {
+	if t == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if t.Driver != "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Sidecar task driver can't be overridden"))
+	}
+	if len(t.Config) != 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Sidecar task config can't be overridden"))
+	}
+	if t.KillTimeout != nil && *t.KillTimeout < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Sidecar task kill_timeout must be a positive value"))
+	}
+	if t.ShutdownDelay != nil && *t.ShutdownDelay < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Sidecar task shutdown_delay must be a positive value"))
+	}
+	if t.LogConfig != nil {
+		if err := t.LogConfig.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Sidecar task logs validation failed: %v", err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// MergeIntoTask overrides the fields of the task with the ones set on the
+// sidecar task. The driver and its config are never overridden.
+func (t *SidecarTask) MergeIntoTask(task *Task) {
+	if t == nil {
+		return
+	}
+
+	if t.User != "" {
+		task.User = t.User
+	}
+	if t.KillSignal != "" {
+		task.KillSignal = t.KillSignal
+	}
+
+	if task.Env == nil {
+		task.Env = make(map[string]string, len(t.Env))
+	}
+	for k, v := range t.Env {
+		task.Env[k] = v
+	}
+	if task.Meta == nil && len(t.Meta) != 0 {
+		task.Meta = make(map[string]string, len(t.Meta))
+	}
+	for k, v := range t.Meta {
+		task.Meta[k] = v
+	}
+
+	if t.Resources != nil {
+		task.Resources = t.Resources.Copy()
+	}
+	if t.LogConfig != nil {
+		lc := *t.LogConfig
+		task.LogConfig = &lc
+	}
+	if t.KillTimeout != nil {
+		task.KillTimeout = *t.KillTimeout
+	}
+	if t.ShutdownDelay != nil {
+		task.ShutdownDelay = *t.ShutdownDelay
+	}
+}
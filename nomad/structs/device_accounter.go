@@ -0,0 +1,94 @@
+package structs
+
+// DeviceAccounter is used to account for the device instances in use on a
+// node. It detects when a device instance is assigned more than once and is
+// used to find the free instances of a device group.
+type DeviceAccounter struct {
+	// Devices maps a device group to its accounting
+	Devices map[DeviceIdTuple]*DeviceAccounterInstance
+}
+
+// DeviceAccounterInstance tracks the usage of the instances of a device group.
+type DeviceAccounterInstance struct {
+	// Device is the device group being accounted for
+	Device *NodeDeviceResource
+
+	// Instances maps the ID of each device instance to the number of times it
+	// is in use
+	Instances map[string]int
+}
+
+// NewDeviceAccounter returns a device accounter for the devices of the node.
+// Unhealthy device instances are not tracked and so never considered free.
+func NewDeviceAccounter(n *Node) *DeviceAccounter {
+	d := &DeviceAccounter{
+		Devices: make(map[DeviceIdTuple]*DeviceAccounterInstance, len(n.Devices)),
+	}
+
+	for _, dev := range n.Devices {
+		id := *dev.ID()
+		instances := make(map[string]int, len(dev.Instances))
+		for _, instance := range dev.Instances {
+			if !instance.Healthy {
+				continue
+			}
+			instances[instance.ID] = 0
+		}
+
+		d.Devices[id] = &DeviceAccounterInstance{
+			Device:    dev,
+			Instances: instances,
+		}
+	}
+
+	return d
+}
+
+// AddAllocs is used to add the device instances assigned to the allocations.
+// Returns true if an instance is assigned more than once.
+func (d *DeviceAccounter) AddAllocs(allocs []*Allocation) (collision bool) {
+	for _, alloc := range allocs {
+		for _, devices := range alloc.TaskDevices {
+			for _, res := range devices {
+				if d.AddReserved(res) {
+					collision = true
+				}
+			}
+		}
+	}
+	return
+}
+
+// AddReserved is used to add a set of assigned device instances. Returns true
+// if an instance is already in use. Instances that are no longer part of the
+// node are ignored.
+func (d *DeviceAccounter) AddReserved(res *AllocatedDeviceResource) (collision bool) {
+	devInst, ok := d.Devices[*res.ID()]
+	if !ok {
+		return false
+	}
+
+	for _, id := range res.DeviceIDs {
+		cur, ok := devInst.Instances[id]
+		if !ok {
+			continue
+		}
+
+		if cur != 0 {
+			collision = true
+		}
+		devInst.Instances[id]++
+	}
+	return
+}
+
+// FreeCount returns the number of free device instances.
+func (i *DeviceAccounterInstance) FreeCount() int {
+	count := 0
+	for _, c := range i.Instances {
+		if c == 0 {
+			count++
+		}
+	}
+	return count
+}
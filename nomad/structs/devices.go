@@ -0,0 +1,313 @@
+package structs
+
+import (
+	"fmt"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+// DeviceIdTuple is the tuple that identifies a group of devices: the vendor,
+// the device type and the model name.
+type DeviceIdTuple struct {
+	Vendor string
+	Type   string
+	Name   string
+}
+
+func (id *DeviceIdTuple) String() string {
+	if id == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/%s/%s", id.Vendor, id.Type, id.Name)
+}
+
+// Matches returns whether the device group identified by other satisfies the
+// possibly partial ID of a device request. Empty fields match anything.
+func (id *DeviceIdTuple) Matches(other *DeviceIdTuple) bool {
+	if other == nil {
+		return false
+	}
+
+	if id.Name != "" && id.Name != other.Name {
+		return false
+	}
+	if id.Vendor != "" && id.Vendor != other.Vendor {
+		return false
+	}
+	if id.Type != "" && id.Type != other.Type {
+		return false
+	}
+	return true
+}
+
+// NodeDeviceResource is a group of devices of the same vendor, type and model
+// that has been fingerprinted on a node.
+type NodeDeviceResource struct {
+	Vendor     string
+	Type       string
+	Name       string
+	Instances  []*NodeDevice
+	Attributes map[string]string
+}
+
+// ID returns the ID tuple of the device group.
+func (n *NodeDeviceResource) ID() *DeviceIdTuple {
+	if n == nil {
+		return nil
+	}
+
+	return &DeviceIdTuple{
+		Vendor: n.Vendor,
+		Type:   n.Type,
+		Name:   n.Name,
+	}
+}
+
+func (n *NodeDeviceResource) Copy() *NodeDeviceResource {
+	if n == nil {
+		return nil
+	}
+
+	nn := new(NodeDeviceResource)
+	*nn = *n
+	if n.Instances != nil {
+		nn.Instances = make([]*NodeDevice, len(n.Instances))
+		for i, d := range n.Instances {
+			nn.Instances[i] = d.Copy()
+		}
+	}
+	if n.Attributes != nil {
+		nn.Attributes = make(map[string]string, len(n.Attributes))
+		for k, v := range n.Attributes {
+			nn.Attributes[k] = v
+		}
+	}
+	return nn
+}
+
+// HealthyInstances returns the number of healthy device instances.
+func (n *NodeDeviceResource) HealthyInstances() int {
+	healthy := 0
+	for _, d := range n.Instances {
+		if d.Healthy {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// NodeDevice is a single device instance.
+type NodeDevice struct {
+	ID                string
+	Healthy           bool
+	HealthDescription string
+	Locality          *NodeDeviceLocality
+}
+
+func (n *NodeDevice) Copy() *NodeDevice {
+	if n == nil {
+		return nil
+	}
+
+	nn := new(NodeDevice)
+	*nn = *n
+	if n.Locality != nil {
+		nn.Locality = new(NodeDeviceLocality)
+		*nn.Locality = *n.Locality
+	}
+	return nn
+}
+
+// HashInclude is used to keep the IDs and locality of device instances out of
+// the computed node class, as they uniquely identify the node.
+func (n NodeDevice) HashInclude(field string, v interface{}) (bool, error) {
+	switch field {
+	case "Healthy":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// NodeDeviceLocality stores information about the hardware locality of a
+// device instance.
+type NodeDeviceLocality struct {
+	PciBusID string
+}
+
+// CopySliceNodeDeviceResource is a helper to copy a list of device groups.
+func CopySliceNodeDeviceResource(s []*NodeDeviceResource) []*NodeDeviceResource {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	ns := make([]*NodeDeviceResource, l)
+	for i, d := range s {
+		ns[i] = d.Copy()
+	}
+	return ns
+}
+
+// RequestedDevice is used to request a device for a task.
+type RequestedDevice struct {
+	// Name is the request name. The possible values are as follows:
+	// * <type>: A single value only specifies the type of request.
+	// * <vendor>/<type>: A single slash delimiter assumes the vendor and type of device is specified.
+	// * <vendor>/<type>/<name>: Two slash delimiters assume vendor, type and specific model are specified.
+	//
+	// Examples are as follows:
+	// * "gpu"
+	// * "nvidia/gpu"
+	// * "nvidia/gpu/GTX2080Ti"
+	Name string
+
+	// Count is the number of requested devices
+	Count uint64
+
+	// Constraints are a set of constraints to apply when selecting the device
+	// to use.
+	Constraints []*Constraint
+
+	// Affinities are a set of affinities to apply when selecting the device
+	// to use.
+	Affinities []*Affinity
+}
+
+// ID returns the possibly partial ID tuple parsed from the request name.
+func (r *RequestedDevice) ID() *DeviceIdTuple {
+	if r == nil || r.Name == "" {
+		return nil
+	}
+
+	parts := strings.SplitN(r.Name, "/", 3)
+	switch len(parts) {
+	case 1:
+		return &DeviceIdTuple{
+			Type: parts[0],
+		}
+	case 2:
+		return &DeviceIdTuple{
+			Vendor: parts[0],
+			Type:   parts[1],
+		}
+	default:
+		return &DeviceIdTuple{
+			Vendor: parts[0],
+			Type:   parts[1],
+			Name:   parts[2],
+		}
+	}
+}
+
+func (r *RequestedDevice) Copy() *RequestedDevice {
+	if r == nil {
+		return nil
+	}
+
+	nr := new(RequestedDevice)
+	*nr = *r
+	nr.Constraints = CopySliceConstraints(r.Constraints)
+	nr.Affinities = CopySliceAffinities(r.Affinities)
+	return nr
+}
+
+// Validate is used to check that a device request is well formed.
+func (r *RequestedDevice) Validate() error {
+	if r == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if r.Name == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("device name must be given as one of the following: type, vendor/type, or vendor/type/name"))
+	} else {
+		for _, part := range strings.Split(r.Name, "/") {
+			if part == "" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("device name %q contains an empty part", r.Name))
+				break
+			}
+		}
+	}
+	if r.Count == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("device %q must request at least one instance", r.Name))
+	}
+
+	for idx, constr := range r.Constraints {
+		// Ensure that the constraint doesn't use an operand we do not allow
+		switch constr.Operand {
+		case ConstraintDistinctHosts, ConstraintDistinctProperty:
+			outer := fmt.Errorf("Constraint %d validation failed: using unsupported operand %q", idx+1, constr.Operand)
+			mErr.Errors = append(mErr.Errors, outer)
+		default:
+			if err := constr.Validate(); err != nil {
+				outer := fmt.Errorf("Constraint %d validation failed: %s", idx+1, err)
+				mErr.Errors = append(mErr.Errors, outer)
+			}
+		}
+	}
+	for idx, affinity := range r.Affinities {
+		if err := affinity.Validate(); err != nil {
+			outer := fmt.Errorf("Affinity %d validation failed: %s", idx+1, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// AllocatedDeviceResource is the set of device instances of a device group
+// that the scheduler has assigned to a task.
+type AllocatedDeviceResource struct {
+	Vendor    string
+	Type      string
+	Name      string
+	DeviceIDs []string
+}
+
+// ID returns the ID tuple of the device group the instances belong to.
+func (a *AllocatedDeviceResource) ID() *DeviceIdTuple {
+	if a == nil {
+		return nil
+	}
+
+	return &DeviceIdTuple{
+		Vendor: a.Vendor,
+		Type:   a.Type,
+		Name:   a.Name,
+	}
+}
+
+func (a *AllocatedDeviceResource) Copy() *AllocatedDeviceResource {
+	if a == nil {
+		return nil
+	}
+
+	na := new(AllocatedDeviceResource)
+	*na = *a
+	if a.DeviceIDs != nil {
+		na.DeviceIDs = make([]string, len(a.DeviceIDs))
+		copy(na.DeviceIDs, a.DeviceIDs)
+	}
+	return na
+}
+
+// CopyMapAllocatedDevices is a helper to copy the devices assigned to each
+// task of an allocation.
+func CopyMapAllocatedDevices(m map[string][]*AllocatedDeviceResource) map[string][]*AllocatedDeviceResource {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string][]*AllocatedDeviceResource, len(m))
+	for task, devices := range m {
+		nd := make([]*AllocatedDeviceResource, len(devices))
+		for i, d := range devices {
+			nd[i] = d.Copy()
+		}
+		nm[task] = nd
+	}
+	return nm
+}
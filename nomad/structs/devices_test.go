@@ -0,0 +1,167 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testDeviceNode() *Node {
+	n := testNode()
+	n.Devices = []*NodeDeviceResource{
+		{
+			Vendor: "nvidia",
+			Type:   "gpu",
+			Name:   "Tesla K80",
+			Instances: []*NodeDevice{
+				{ID: "GPU-1", Healthy: true},
+				{ID: "GPU-2", Healthy: true},
+				{ID: "GPU-3", Healthy: false},
+			},
+		},
+	}
+	return n
+}
+
+func TestRequestedDevice_ID(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Expected *DeviceIdTuple
+	}{
+		{
+			Name:     "gpu",
+			Expected: &DeviceIdTuple{Type: "gpu"},
+		},
+		{
+			Name:     "nvidia/gpu",
+			Expected: &DeviceIdTuple{Vendor: "nvidia", Type: "gpu"},
+		},
+		{
+			Name:     "nvidia/gpu/Tesla K80",
+			Expected: &DeviceIdTuple{Vendor: "nvidia", Type: "gpu", Name: "Tesla K80"},
+		},
+		{
+			Name:     "",
+			Expected: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			r := &RequestedDevice{Name: c.Name}
+			require.Equal(t, c.Expected, r.ID())
+		})
+	}
+}
+
+func TestDeviceIdTuple_Matches(t *testing.T) {
+	require := require.New(t)
+
+	id := &DeviceIdTuple{Vendor: "nvidia", Type: "gpu", Name: "Tesla K80"}
+	require.True((&DeviceIdTuple{Type: "gpu"}).Matches(id))
+	require.True((&DeviceIdTuple{Vendor: "nvidia", Type: "gpu"}).Matches(id))
+	require.True((&DeviceIdTuple{Vendor: "nvidia", Type: "gpu", Name: "Tesla K80"}).Matches(id))
+	require.False((&DeviceIdTuple{Type: "fpga"}).Matches(id))
+	require.False((&DeviceIdTuple{Vendor: "amd", Type: "gpu"}).Matches(id))
+	require.False((&DeviceIdTuple{Vendor: "nvidia", Type: "gpu", Name: "Tesla V100"}).Matches(id))
+	require.False((&DeviceIdTuple{Type: "gpu"}).Matches(nil))
+}
+
+func TestRequestedDevice_Validate(t *testing.T) {
+	require := require.New(t)
+
+	r := &RequestedDevice{
+		Name:  "nvidia/gpu",
+		Count: 2,
+		Constraints: []*Constraint{
+			{
+				LTarget: "${device.attr.memory}",
+				RTarget: "8192",
+				Operand: ">=",
+			},
+		},
+		Affinities: []*Affinity{
+			{
+				LTarget: "${device.model}",
+				RTarget: "Tesla K80",
+				Operand: "=",
+				Weight:  50,
+			},
+		},
+	}
+	require.NoError(r.Validate())
+
+	r = &RequestedDevice{
+		Name: "nvidia//k80",
+		Constraints: []*Constraint{
+			{Operand: ConstraintDistinctHosts},
+		},
+	}
+	err := r.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "empty part")
+	require.Contains(err.Error(), "at least one instance")
+	require.Contains(err.Error(), "unsupported operand")
+
+	r = &RequestedDevice{Count: 1}
+	err = r.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "device name must be given")
+}
+
+func TestDeviceAccounter_AddAllocs(t *testing.T) {
+	require := require.New(t)
+
+	n := testDeviceNode()
+	d := NewDeviceAccounter(n)
+	require.Len(d.Devices, 1)
+
+	// Unhealthy instances are never free
+	devInst := d.Devices[*n.Devices[0].ID()]
+	require.NotNil(devInst)
+	require.Equal(2, devInst.FreeCount())
+
+	alloc := &Allocation{
+		TaskDevices: map[string][]*AllocatedDeviceResource{
+			"web": {
+				{
+					Vendor:    "nvidia",
+					Type:      "gpu",
+					Name:      "Tesla K80",
+					DeviceIDs: []string{"GPU-1"},
+				},
+			},
+			// Devices that are no longer part of the node are ignored
+			"db": {
+				{
+					Vendor:    "intel",
+					Type:      "fpga",
+					Name:      "arria",
+					DeviceIDs: []string{"FPGA-1"},
+				},
+			},
+		},
+	}
+	require.False(d.AddAllocs([]*Allocation{alloc}))
+	require.Equal(1, devInst.FreeCount())
+
+	// Adding the same allocation again collides
+	require.True(d.AddAllocs([]*Allocation{alloc}))
+}
+
+func TestDeviceAccounter_AddReserved(t *testing.T) {
+	require := require.New(t)
+
+	n := testDeviceNode()
+	d := NewDeviceAccounter(n)
+
+	res := &AllocatedDeviceResource{
+		Vendor:    "nvidia",
+		Type:      "gpu",
+		Name:      "Tesla K80",
+		DeviceIDs: []string{"GPU-1", "GPU-2"},
+	}
+	require.False(d.AddReserved(res))
+	require.Equal(0, d.Devices[*res.ID()].FreeCount())
+	require.True(d.AddReserved(res))
+}
@@ -133,6 +133,11 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, cDiff)
 	}
 
+	// Multiregion diff
+	if mrDiff := multiregionDiff(j.Multiregion, other.Multiregion, contextual); mrDiff != nil {
+		diff.Objects = append(diff.Objects, mrDiff)
+	}
+
 	// Check to see if there is a diff. We don't use reflect because we are
 	// filtering quite a few fields that will change on each diff.
 	if diff.Type == DiffTypeNone {
@@ -268,6 +273,27 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, diskDiff)
 	}
 
+	// Volumes diff
+	volumesDiff := primitiveObjectSetDiff(
+		volumeRequestSlice(tg.Volumes),
+		volumeRequestSlice(other.Volumes),
+		nil,
+		"Volume",
+		contextual)
+	if volumesDiff != nil {
+		diff.Objects = append(diff.Objects, volumesDiff...)
+	}
+
+	// Networks diff
+	if nDiffs := networkResourceDiffs(tg.Networks, other.Networks, contextual); nDiffs != nil {
+		diff.Objects = append(diff.Objects, nDiffs...)
+	}
+
+	// Services diff
+	if sDiffs := serviceDiffs(tg.Services, other.Services, contextual); sDiffs != nil {
+		diff.Objects = append(diff.Objects, sDiffs...)
+	}
+
 	// Update diff
 	// COMPAT: Remove "Stagger" in 0.7.0.
 	if uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual); uDiff != nil {
@@ -442,6 +468,23 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, dDiff)
 	}
 
+	// VolumeMounts diff
+	vmDiff := primitiveObjectSetDiff(
+		interfaceSlice(t.VolumeMounts),
+		interfaceSlice(other.VolumeMounts),
+		nil,
+		"VolumeMount",
+		contextual)
+	if vmDiff != nil {
+		diff.Objects = append(diff.Objects, vmDiff...)
+	}
+
+	// Lifecycle diff
+	lcDiff := primitiveObjectDiff(t.Lifecycle, other.Lifecycle, nil, "Lifecycle", contextual)
+	if lcDiff != nil {
+		diff.Objects = append(diff.Objects, lcDiff)
+	}
+
 	// Artifacts diff
 	diffs := primitiveObjectSetDiff(
 		interfaceSlice(t.Artifacts),
@@ -580,6 +623,142 @@ func serviceDiff(old, new *Service, contextual bool) *ObjectDiff {
 		diff.Objects = append(diff.Objects, cDiffs...)
 	}
 
+	// Consul Connect diffs
+	if conDiff := connectDiff(old.Connect, new.Connect, contextual); conDiff != nil {
+		diff.Objects = append(diff.Objects, conDiff)
+	}
+
+	return diff
+}
+
+// connectDiff returns the diff of two Consul Connect objects. If contextual
+// diff is enabled, all fields will be returned, even if no diff occurred.
+func connectDiff(old, new *ConsulConnect, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "ConsulConnect"}
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &ConsulConnect{}
+		diff.Type = DiffTypeAdded
+	} else if new == nil {
+		new = &ConsulConnect{}
+		diff.Type = DiffTypeDeleted
+	} else {
+		diff.Type = DiffTypeEdited
+	}
+
+	if sDiff := sidecarServiceDiff(old.SidecarService, new.SidecarService, contextual); sDiff != nil {
+		diff.Objects = append(diff.Objects, sDiff)
+	}
+	if tDiff := sidecarTaskDiff(old.SidecarTask, new.SidecarTask, contextual); tDiff != nil {
+		diff.Objects = append(diff.Objects, tDiff)
+	}
+	return diff
+}
+
+// sidecarServiceDiff returns the diff of two Consul Connect sidecar services.
+// If contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func sidecarServiceDiff(old, new *ConsulSidecarService, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "SidecarService"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &ConsulSidecarService{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &ConsulSidecarService{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	if setDiff := stringSetDiff(old.Tags, new.Tags, "Tags", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+	if pDiff := consulProxyDiff(old.Proxy, new.Proxy, contextual); pDiff != nil {
+		diff.Objects = append(diff.Objects, pDiff)
+	}
+	return diff
+}
+
+// consulProxyDiff returns the diff of two Consul Connect proxies. If
+// contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func consulProxyDiff(old, new *ConsulProxy, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "ConsulProxy"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &ConsulProxy{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &ConsulProxy{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	upstreamsDiff := primitiveObjectSetDiff(
+		interfaceSlice(old.Upstreams),
+		interfaceSlice(new.Upstreams),
+		nil,
+		"ConsulUpstreams",
+		contextual)
+	if upstreamsDiff != nil {
+		diff.Objects = append(diff.Objects, upstreamsDiff...)
+	}
+
+	if cDiff := configDiff(old.Config, new.Config, contextual); cDiff != nil {
+		diff.Objects = append(diff.Objects, cDiff)
+	}
+	return diff
+}
+
+// sidecarTaskDiff returns the diff of two Consul Connect sidecar tasks. If
+// contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "SidecarTask"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &SidecarTask{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, false)
+	} else if new == nil {
+		new = &SidecarTask{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, false)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, false)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, false)
+	}
+
+	// Diff the fields, flattening the nested configuration as the task
+	// overrides are read as a whole.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
 	return diff
 }
 
@@ -810,6 +989,42 @@ func parameterizedJobDiff(old, new *ParameterizedJobConfig, contextual bool) *Ob
 	return diff
 }
 
+// multiregionDiff returns the diff of two multiregion objects. If contextual
+// diff is enabled, all fields will be returned, even if no diff occurred.
+func multiregionDiff(old, new *Multiregion, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Multiregion"}
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &Multiregion{}
+		diff.Type = DiffTypeAdded
+	} else if new == nil {
+		new = &Multiregion{}
+		diff.Type = DiffTypeDeleted
+	} else {
+		diff.Type = DiffTypeEdited
+	}
+
+	// Strategy diff
+	if sDiff := primitiveObjectDiff(old.Strategy, new.Strategy, nil, "Strategy", contextual); sDiff != nil {
+		diff.Objects = append(diff.Objects, sDiff)
+	}
+
+	// Regions diff
+	rDiffs := primitiveObjectSetDiff(
+		interfaceSlice(old.Regions),
+		interfaceSlice(new.Regions),
+		nil,
+		"Region",
+		contextual)
+	if rDiffs != nil {
+		diff.Objects = append(diff.Objects, rDiffs...)
+	}
+
+	return diff
+}
+
 // Diff returns a diff of two resource objects. If contextual diff is enabled,
 // non-changed fields will still be returned.
 func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
@@ -840,6 +1055,16 @@ func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
 		diff.Objects = append(diff.Objects, nDiffs...)
 	}
 
+	// Requested devices diff
+	if dDiffs := primitiveObjectSetDiff(
+		interfaceSlice(r.Devices),
+		interfaceSlice(other.Devices),
+		nil,
+		"Device",
+		contextual); dDiffs != nil {
+		diff.Objects = append(diff.Objects, dDiffs...)
+	}
+
 	return diff
 }
 
@@ -848,7 +1073,7 @@ func (r *Resources) Diff(other *Resources, contextual bool) *ObjectDiff {
 func (r *NetworkResource) Diff(other *NetworkResource, contextual bool) *ObjectDiff {
 	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Network"}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
-	filter := []string{"Device", "CIDR", "IP"}
+	filter := []string{"Device", "CIDR", "IP", "HostNetwork"}
 
 	if reflect.DeepEqual(r, other) {
 		return nil
@@ -938,10 +1163,11 @@ func portDiffs(old, new []Port, dynamic bool, contextual bool) []*ObjectDiff {
 	oldPorts := makeSet(old)
 	newPorts := makeSet(new)
 
-	var filter []string
+	// The address of the ports is set by the scheduler
+	filter := []string{"HostIP"}
 	name := "Static Port"
 	if dynamic {
-		filter = []string{"Value"}
+		filter = append(filter, "Value")
 		name = "Dynamic Port"
 	}
 
@@ -1313,3 +1539,13 @@ func interfaceSlice(slice interface{}) []interface{} {
 
 	return ret
 }
+
+// volumeRequestSlice returns the volume requests of a task group as a slice
+// of interface so they can be diffed as a set.
+func volumeRequestSlice(volumes map[string]*VolumeRequest) []interface{} {
+	ret := make([]interface{}, 0, len(volumes))
+	for _, v := range volumes {
+		ret = append(ret, v)
+	}
+	return ret
+}
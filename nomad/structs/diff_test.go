@@ -937,6 +937,55 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Multiregion strategy edited
+			Old: &Job{
+				Multiregion: &Multiregion{
+					Strategy: &MultiregionStrategy{
+						MaxParallel: 1,
+					},
+					Regions: []*MultiregionRegion{{Name: "west"}},
+				},
+			},
+			New: &Job{
+				Multiregion: &Multiregion{
+					Strategy: &MultiregionStrategy{
+						MaxParallel: 2,
+						OnFailure:   MultiregionOnFailureFailAll,
+					},
+					Regions: []*MultiregionRegion{{Name: "west"}},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Multiregion",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Strategy",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeEdited,
+										Name: "MaxParallel",
+										Old:  "1",
+										New:  "2",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "OnFailure",
+										Old:  "",
+										New:  MultiregionOnFailureFailAll,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Parameterized Job added
 			Old: &Job{},
@@ -1977,6 +2026,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "Update",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "AutoPromote",
+								Old:  "false",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "AutoRevert",
@@ -2033,6 +2088,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "Update",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "AutoPromote",
+								Old:  "",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "AutoRevert",
@@ -2084,6 +2145,7 @@ func TestTaskGroupDiff(t *testing.T) {
 					HealthyDeadline:  30 * time.Second,
 					ProgressDeadline: 29 * time.Second,
 					AutoRevert:       true,
+					AutoPromote:      true,
 					Canary:           2,
 				},
 			},
@@ -2095,6 +2157,7 @@ func TestTaskGroupDiff(t *testing.T) {
 					HealthyDeadline:  31 * time.Second,
 					ProgressDeadline: 32 * time.Second,
 					AutoRevert:       false,
+					AutoPromote:      false,
 					Canary:           1,
 				},
 			},
@@ -2105,6 +2168,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "Update",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "AutoPromote",
+								Old:  "true",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "AutoRevert",
@@ -2184,6 +2253,12 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "Update",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "AutoPromote",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "AutoRevert",
@@ -2521,6 +2596,141 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Volumes added
+			Old: &TaskGroup{},
+			New: &TaskGroup{
+				Volumes: map[string]*VolumeRequest{
+					"foo": {
+						Name:   "foo",
+						Type:   VolumeTypeHost,
+						Source: "shared",
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "Volume",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Name",
+								Old:  "",
+								New:  "foo",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "ReadOnly",
+								Old:  "",
+								New:  "false",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Source",
+								Old:  "",
+								New:  "shared",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Type",
+								Old:  "",
+								New:  "host",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			// Connect added to a group service
+			Old: &TaskGroup{
+				Services: []*Service{{Name: "web", PortLabel: "http"}},
+			},
+			New: &TaskGroup{
+				Services: []*Service{
+					{
+						Name:      "web",
+						PortLabel: "http",
+						Connect: &ConsulConnect{
+							SidecarService: &ConsulSidecarService{
+								Port: "proxy",
+								Proxy: &ConsulProxy{
+									Upstreams: []*ConsulUpstream{
+										{DestinationName: "db", LocalBindPort: 5432},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Service",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "ConsulConnect",
+								Objects: []*ObjectDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "SidecarService",
+										Fields: []*FieldDiff{
+											{
+												Type: DiffTypeAdded,
+												Name: "Port",
+												Old:  "",
+												New:  "proxy",
+											},
+										},
+										Objects: []*ObjectDiff{
+											{
+												Type: DiffTypeAdded,
+												Name: "ConsulProxy",
+												Fields: []*FieldDiff{
+													{
+														Type: DiffTypeAdded,
+														Name: "LocalServicePort",
+														Old:  "",
+														New:  "0",
+													},
+												},
+												Objects: []*ObjectDiff{
+													{
+														Type: DiffTypeAdded,
+														Name: "ConsulUpstreams",
+														Fields: []*FieldDiff{
+															{
+																Type: DiffTypeAdded,
+																Name: "DestinationName",
+																Old:  "",
+																New:  "db",
+															},
+															{
+																Type: DiffTypeAdded,
+																Name: "LocalBindPort",
+																Old:  "",
+																New:  "5432",
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for i, c := range cases {
@@ -3275,6 +3485,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "MemoryMaxMB",
+								Old:  "0",
+								New:  "0",
+							},
 						},
 					},
 				},
@@ -3357,6 +3573,12 @@ func TestTaskDiff(t *testing.T) {
 												Old:  "",
 												New:  "foo",
 											},
+											{
+												Type: DiffTypeAdded,
+												Name: "To",
+												Old:  "",
+												New:  "0",
+											},
 											{
 												Type: DiffTypeAdded,
 												Name: "Value",
@@ -3375,6 +3597,12 @@ func TestTaskDiff(t *testing.T) {
 												Old:  "",
 												New:  "baz",
 											},
+											{
+												Type: DiffTypeAdded,
+												Name: "To",
+												Old:  "",
+												New:  "0",
+											},
 										},
 									},
 								},
@@ -3401,6 +3629,12 @@ func TestTaskDiff(t *testing.T) {
 												Old:  "foo",
 												New:  "",
 											},
+											{
+												Type: DiffTypeDeleted,
+												Name: "To",
+												Old:  "0",
+												New:  "",
+											},
 											{
 												Type: DiffTypeDeleted,
 												Name: "Value",
@@ -3419,6 +3653,12 @@ func TestTaskDiff(t *testing.T) {
 												Old:  "bar",
 												New:  "",
 											},
+											{
+												Type: DiffTypeDeleted,
+												Name: "To",
+												Old:  "0",
+												New:  "",
+											},
 										},
 									},
 								},
@@ -3620,12 +3860,30 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "2",
 								New:  "2",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "boom.HostIP",
+								Old:  "",
+								New:  "",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "boom.HostNetwork",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "boom.Label",
 								Old:  "boom_port",
 								New:  "boom_port",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "boom.To",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "boom.Value",
@@ -3775,6 +4033,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "foo",
 								New:  "bar",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -3868,6 +4132,10 @@ func TestTaskDiff(t *testing.T) {
 								Type: DiffTypeNone,
 								Name: "PortLabel",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+							},
 						},
 					},
 				},
@@ -4206,6 +4474,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Provider",
+								Old:  "",
+								New:  "",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -5071,6 +5345,144 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "Lifecycle added",
+			Old:  &Task{},
+			New: &Task{
+				Lifecycle: &TaskLifecycleConfig{
+					Hook:    TaskLifecycleHookPrestart,
+					Sidecar: true,
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "Lifecycle",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Hook",
+								Old:  "",
+								New:  "prestart",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Sidecar",
+								Old:  "",
+								New:  "true",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "VolumeMounts edited",
+			Old: &Task{
+				VolumeMounts: []*VolumeMount{
+					{
+						Volume:      "foo",
+						Destination: "/srv",
+					},
+				},
+			},
+			New: &Task{
+				VolumeMounts: []*VolumeMount{
+					{
+						Volume:      "foo",
+						Destination: "/srv",
+						ReadOnly:    true,
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "VolumeMount",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Destination",
+								Old:  "",
+								New:  "/srv",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "ReadOnly",
+								Old:  "",
+								New:  "true",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Volume",
+								Old:  "",
+								New:  "foo",
+							},
+						},
+					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "VolumeMount",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Destination",
+								Old:  "/srv",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "ReadOnly",
+								Old:  "false",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Volume",
+								Old:  "foo",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "Lifecycle edited",
+			Old: &Task{
+				Lifecycle: &TaskLifecycleConfig{
+					Hook:    TaskLifecycleHookPrestart,
+					Sidecar: false,
+				},
+			},
+			New: &Task{
+				Lifecycle: &TaskLifecycleConfig{
+					Hook:    TaskLifecycleHookPrestart,
+					Sidecar: true,
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Lifecycle",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Sidecar",
+								Old:  "false",
+								New:  "true",
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for i, c := range cases {
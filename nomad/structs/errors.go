@@ -10,11 +10,14 @@ const (
 	errNoLeader            = "No cluster leader"
 	errNoRegionPath        = "No path to region"
 	errTokenNotFound       = "ACL token not found"
+	errTokenExpired        = "ACL token expired"
 	errPermissionDenied    = "Permission denied"
 	errNoNodeConn          = "No path to node"
 	errUnknownMethod       = "Unknown rpc method"
 	errUnknownNomadVersion = "Unable to determine Nomad version"
 	errNodeLacksRpc        = "Node does not support RPC; requires 0.8 or later"
+	errVariableConflict    = "Variable was modified or deleted"
+	errNoServerTLS         = "RPC requires mutual TLS with a verified server certificate"
 
 	// Prefix based errors that are used to check if the error is of a given
 	// type. These errors should be created with the associated constructor.
@@ -29,11 +32,14 @@ var (
 	ErrNoLeader            = errors.New(errNoLeader)
 	ErrNoRegionPath        = errors.New(errNoRegionPath)
 	ErrTokenNotFound       = errors.New(errTokenNotFound)
+	ErrTokenExpired        = errors.New(errTokenExpired)
 	ErrPermissionDenied    = errors.New(errPermissionDenied)
 	ErrNoNodeConn          = errors.New(errNoNodeConn)
 	ErrUnknownMethod       = errors.New(errUnknownMethod)
 	ErrUnknownNomadVersion = errors.New(errUnknownNomadVersion)
 	ErrNodeLacksRpc        = errors.New(errNodeLacksRpc)
+	ErrVariableConflict    = errors.New(errVariableConflict)
+	ErrNoServerTLS         = errors.New(errNoServerTLS)
 )
 
 // IsErrNoLeader returns whether the error is due to there being no leader.
@@ -35,6 +35,23 @@ func MergeMultierrorWarnings(warnings ...error) string {
 	return warningMsg.Error()
 }
 
+// FlattenMultierrorWarnings returns the messages of the warnings, with the
+// errors of multierrors listed individually
+func FlattenMultierrorWarnings(warnings ...error) []string {
+	var out []string
+	for _, warn := range warnings {
+		if warn == nil {
+			continue
+		}
+		if merr, ok := warn.(*multierror.Error); ok {
+			out = append(out, FlattenMultierrorWarnings(merr.Errors...)...)
+			continue
+		}
+		out = append(out, warn.Error())
+	}
+	return out
+}
+
 // warningsFormatter is used to format job warnings
 func warningsFormatter(es []error) string {
 	points := make([]string, len(es))
@@ -154,6 +171,11 @@ func AllocsFit(node *Node, allocs []*Allocation, netIdx *NetworkIndex) (bool, st
 		return false, "bandwidth exceeded", used, nil
 	}
 
+	// Check that no device instance is assigned twice
+	if NewDeviceAccounter(node).AddAllocs(allocs) {
+		return false, "device oversubscribed", used, nil
+	}
+
 	// Allocations fit!
 	return true, "", used, nil
 }
@@ -162,6 +184,34 @@ func AllocsFit(node *Node, allocs []*Allocation, netIdx *NetworkIndex) (bool, st
 // http://www.columbia.edu/~cs2035/courses/ieor4405.S13/datacenter_scheduling.ppt
 // This is equivalent to their BestFit v3
 func ScoreFit(node *Node, util *Resources) float64 {
+	// Total will be "maximized" the smaller the value is.
+	// At 100% utilization, the total is 2, while at 0% util it is 20.
+	total := freeResourceTotal(node, util)
+
+	// Invert so that the "maximized" total represents a high-value
+	// score. Because the floor is 20, we simply use that as an anchor.
+	// This means at a perfect fit, we return 18 as the score.
+	score := 20.0 - total
+
+	// Bound the score, just in case
+	// If the score is over 18, that means we've overfit the node.
+	return boundFitScore(score)
+}
+
+// ScoreFitSpread is the inverse of ScoreFit. It scores the nodes with the
+// most free resources the highest, so that allocations are spread across
+// the nodes instead of packed onto as few of them as possible.
+func ScoreFitSpread(node *Node, util *Resources) float64 {
+	// At 0% utilization the total is 20, while at 100% util it is 2. Anchor
+	// the score at 2 so that an empty node scores 18.
+	score := freeResourceTotal(node, util) - 2.0
+	return boundFitScore(score)
+}
+
+// freeResourceTotal returns the sum of the exponentially weighted free CPU
+// and memory percentages of the node given its utilization. It ranges from 2
+// at 100% utilization to 20 at 0% utilization.
+func freeResourceTotal(node *Node, util *Resources) float64 {
 	// Determine the node availability
 	nodeCpu := float64(node.Resources.CPU)
 	if node.Reserved != nil {
@@ -176,21 +226,15 @@ func ScoreFit(node *Node, util *Resources) float64 {
 	freePctCpu := 1 - (float64(util.CPU) / nodeCpu)
 	freePctRam := 1 - (float64(util.MemoryMB) / nodeMem)
 
-	// Total will be "maximized" the smaller the value is.
-	// At 100% utilization, the total is 2, while at 0% util it is 20.
-	total := math.Pow(10, freePctCpu) + math.Pow(10, freePctRam)
-
-	// Invert so that the "maximized" total represents a high-value
-	// score. Because the floor is 20, we simply use that as an anchor.
-	// This means at a perfect fit, we return 18 as the score.
-	score := 20.0 - total
+	return math.Pow(10, freePctCpu) + math.Pow(10, freePctRam)
+}
 
-	// Bound the score, just in case
-	// If the score is over 18, that means we've overfit the node.
+// boundFitScore bounds a fit score between 0 and 18.
+func boundFitScore(score float64) float64 {
 	if score > 18.0 {
-		score = 18.0
+		return 18.0
 	} else if score < 0 {
-		score = 0
+		return 0
 	}
 	return score
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	multierror "github.com/hashicorp/go-multierror"
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/stretchr/testify/assert"
@@ -106,7 +107,7 @@ func TestAllocsFit_PortsOvercommitted(t *testing.T) {
 						Device:        "eth0",
 						IP:            "10.0.0.1",
 						MBits:         50,
-						ReservedPorts: []Port{{"main", 8000}},
+						ReservedPorts: []Port{{Label: "main", Value: 8000}},
 					},
 				},
 			},
@@ -157,7 +158,7 @@ func TestAllocsFit(t *testing.T) {
 					Device:        "eth0",
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"main", 80}},
+					ReservedPorts: []Port{{Label: "main", Value: 80}},
 				},
 			},
 		},
@@ -174,7 +175,7 @@ func TestAllocsFit(t *testing.T) {
 					Device:        "eth0",
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"main", 8000}},
+					ReservedPorts: []Port{{Label: "main", Value: 8000}},
 				},
 			},
 		},
@@ -216,6 +217,63 @@ func TestAllocsFit(t *testing.T) {
 
 }
 
+func TestAllocsFit_Devices(t *testing.T) {
+	n := &Node{
+		Resources: &Resources{
+			CPU:      2000,
+			MemoryMB: 2048,
+		},
+		Devices: []*NodeDeviceResource{
+			{
+				Vendor: "nvidia",
+				Type:   "gpu",
+				Name:   "Tesla K80",
+				Instances: []*NodeDevice{
+					{ID: "GPU-1", Healthy: true},
+					{ID: "GPU-2", Healthy: true},
+				},
+			},
+		},
+	}
+
+	a1 := &Allocation{
+		Resources: &Resources{
+			CPU:      500,
+			MemoryMB: 512,
+		},
+		TaskDevices: map[string][]*AllocatedDeviceResource{
+			"web": {
+				{
+					Vendor:    "nvidia",
+					Type:      "gpu",
+					Name:      "Tesla K80",
+					DeviceIDs: []string{"GPU-1"},
+				},
+			},
+		},
+	}
+	a2 := a1.Copy()
+	a2.TaskDevices["web"][0].DeviceIDs = []string{"GPU-2"}
+
+	// Should fit allocations using different devices
+	fit, dim, _, err := AllocsFit(n, []*Allocation{a1, a2}, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !fit {
+		t.Fatalf("Bad: %s", dim)
+	}
+
+	// Should not fit allocations sharing a device
+	fit, dim, _, err = AllocsFit(n, []*Allocation{a1, a1}, nil)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if fit || dim != "device oversubscribed" {
+		t.Fatalf("Bad: %v %s", fit, dim)
+	}
+}
+
 func TestScoreFit(t *testing.T) {
 	node := &Node{}
 	node.Resources = &Resources{
@@ -258,6 +316,42 @@ func TestScoreFit(t *testing.T) {
 	}
 }
 
+func TestScoreFitSpread(t *testing.T) {
+	node := &Node{}
+	node.Resources = &Resources{
+		CPU:      4096,
+		MemoryMB: 8192,
+	}
+	node.Reserved = &Resources{
+		CPU:      2048,
+		MemoryMB: 4096,
+	}
+
+	// A full node scores the lowest
+	util := &Resources{
+		CPU:      2048,
+		MemoryMB: 4096,
+	}
+	assert.Equal(t, 0.0, ScoreFitSpread(node, util))
+
+	// An empty node scores the highest
+	util = &Resources{
+		CPU:      0,
+		MemoryMB: 0,
+	}
+	assert.Equal(t, 18.0, ScoreFitSpread(node, util))
+
+	// A half used node scores between the two and is the inverse of the
+	// bin packing score
+	util = &Resources{
+		CPU:      1024,
+		MemoryMB: 2048,
+	}
+	score := ScoreFitSpread(node, util)
+	assert.True(t, score > 2.0 && score < 8.0, "bad: %v", score)
+	assert.InDelta(t, 18.0, score+ScoreFit(node, util), 0.0001)
+}
+
 func TestACLPolicyListHash(t *testing.T) {
 	h1 := ACLPolicyListHash(nil)
 	assert.NotEqual(t, "", h1)
@@ -382,3 +476,12 @@ func TestGenerateMigrateToken(t *testing.T) {
 	assert.False(CompareMigrateToken(allocID, nodeSecret, token2))
 	assert.True(CompareMigrateToken("x", nodeSecret, token2))
 }
+
+func TestFlattenMultierrorWarnings(t *testing.T) {
+	var merr multierror.Error
+	multierror.Append(&merr, fmt.Errorf("first"), fmt.Errorf("second"))
+
+	out := FlattenMultierrorWarnings(nil, &merr, fmt.Errorf("third"))
+	assert.Equal(t, []string{"first", "second", "third"}, out)
+	assert.Empty(t, FlattenMultierrorWarnings(nil))
+}
@@ -4,7 +4,12 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+
+	multierror "github.com/hashicorp/go-multierror"
 )
 
 const (
@@ -20,6 +25,11 @@ const (
 
 	// maxValidPort is the max valid port number
 	maxValidPort = 65536
+
+	// DefaultHostNetwork is the name of the network of the interface
+	// fingerprinted by the client, on which the ports that aren't pinned to
+	// a host network are allocated.
+	DefaultHostNetwork = "default"
 )
 
 var (
@@ -34,6 +44,7 @@ var (
 // and the used network resources on a machine given allocations
 type NetworkIndex struct {
 	AvailNetworks  []*NetworkResource // List of available networks
+	HostNetworks   []*NetworkResource // List of addresses of the named host networks
 	AvailBandwidth map[string]int     // Bandwidth by device
 	UsedPorts      map[string]Bitmap  // Ports by IP
 	UsedBandwidth  map[string]int     // Bandwidth by device
@@ -72,6 +83,11 @@ func (idx *NetworkIndex) Overcommitted() bool {
 func (idx *NetworkIndex) SetNode(node *Node) (collide bool) {
 	// Add the available CIDR blocks
 	for _, n := range node.Resources.Networks {
+		if n.HostNetwork != "" {
+			// The bandwidth is only accounted on the default network
+			idx.HostNetworks = append(idx.HostNetworks, n)
+			continue
+		}
 		if n.Device != "" {
 			idx.AvailNetworks = append(idx.AvailNetworks, n)
 			idx.AvailBandwidth[n.Device] = n.MBits
@@ -86,6 +102,17 @@ func (idx *NetworkIndex) SetNode(node *Node) (collide bool) {
 			}
 		}
 	}
+
+	// Reserve the reserved ports of the host networks on their addresses.
+	// They may overlap with the reserved ports of the node.
+	for _, n := range idx.HostNetworks {
+		used := idx.usedPorts(n.IP)
+		for _, port := range n.ReservedPorts {
+			if port.Value > 0 && port.Value < maxValidPort {
+				used.Set(uint(port.Value))
+			}
+		}
+	}
 	return
 }
 
@@ -102,6 +129,13 @@ func (idx *NetworkIndex) AddAllocs(allocs []*Allocation) (collide bool) {
 				collide = true
 			}
 		}
+
+		// Add the ports of the group network shared by the tasks
+		if alloc.SharedResources != nil && len(alloc.SharedResources.Networks) != 0 {
+			if idx.AddReserved(alloc.SharedResources.Networks[0]) {
+				collide = true
+			}
+		}
 	}
 	return
 }
@@ -110,25 +144,14 @@ func (idx *NetworkIndex) AddAllocs(allocs []*Allocation) (collide bool) {
 // if there is a port collision
 func (idx *NetworkIndex) AddReserved(n *NetworkResource) (collide bool) {
 	// Add the port usage
-	used := idx.UsedPorts[n.IP]
-	if used == nil {
-		// Try to get a bitmap from the pool, else create
-		raw := bitmapPool.Get()
-		if raw != nil {
-			used = raw.(Bitmap)
-			used.Clear()
-		} else {
-			used, _ = NewBitmap(maxValidPort)
-		}
-		idx.UsedPorts[n.IP] = used
-	}
-
+	idx.usedPorts(n.IP)
 	for _, ports := range [][]Port{n.ReservedPorts, n.DynamicPorts} {
 		for _, port := range ports {
 			// Guard against invalid port
 			if port.Value < 0 || port.Value >= maxValidPort {
 				return true
 			}
+			used := idx.usedPorts(n.PortIP(port))
 			if used.Check(uint(port.Value)) {
 				collide = true
 			} else {
@@ -142,6 +165,23 @@ func (idx *NetworkIndex) AddReserved(n *NetworkResource) (collide bool) {
 	return
 }
 
+// usedPorts returns the bitmap of the used ports of an address
+func (idx *NetworkIndex) usedPorts(ip string) Bitmap {
+	used := idx.UsedPorts[ip]
+	if used == nil {
+		// Try to get a bitmap from the pool, else create
+		raw := bitmapPool.Get()
+		if raw != nil {
+			used = raw.(Bitmap)
+			used.Clear()
+		} else {
+			used, _ = NewBitmap(maxValidPort)
+		}
+		idx.UsedPorts[ip] = used
+	}
+	return used
+}
+
 // yieldIP is used to iteratively invoke the callback with
 // an available IP
 func (idx *NetworkIndex) yieldIP(cb func(net *NetworkResource, ip net.IP) bool) {
@@ -170,6 +210,149 @@ func (idx *NetworkIndex) yieldIP(cb func(net *NetworkResource, ip net.IP) bool)
 // AssignNetwork is used to assign network resources given an ask.
 // If the ask cannot be satisfied, returns nil
 func (idx *NetworkIndex) AssignNetwork(ask *NetworkResource) (out *NetworkResource, err error) {
+	// Split the ports pinned to host networks from the ones allocated on the
+	// default network
+	defaultAsk := ask.Copy()
+	defaultAsk.ReservedPorts, defaultAsk.DynamicPorts = nil, nil
+	hostAsks := make(map[string]*NetworkResource)
+	hostAsk := func(name string) *NetworkResource {
+		if hostAsks[name] == nil {
+			hostAsks[name] = &NetworkResource{}
+		}
+		return hostAsks[name]
+	}
+	for _, port := range ask.ReservedPorts {
+		if isDefaultHostNetwork(port.HostNetwork) {
+			defaultAsk.ReservedPorts = append(defaultAsk.ReservedPorts, port)
+		} else {
+			h := hostAsk(port.HostNetwork)
+			h.ReservedPorts = append(h.ReservedPorts, port)
+		}
+	}
+	for _, port := range ask.DynamicPorts {
+		if isDefaultHostNetwork(port.HostNetwork) {
+			defaultAsk.DynamicPorts = append(defaultAsk.DynamicPorts, port)
+		} else {
+			h := hostAsk(port.HostNetwork)
+			h.DynamicPorts = append(h.DynamicPorts, port)
+		}
+	}
+	if len(hostAsks) == 0 {
+		return idx.assignDefaultNetwork(ask)
+	}
+
+	out, err = idx.assignDefaultNetwork(defaultAsk)
+	if err != nil {
+		return nil, err
+	}
+	// Track the ports assigned by address, since host networks may share
+	// addresses with the default network or with each other
+	assigned := make(map[string]Port, len(ask.ReservedPorts)+len(ask.DynamicPorts))
+	taken := make(map[string][]Port)
+	for _, ports := range [][]Port{out.ReservedPorts, out.DynamicPorts} {
+		for _, port := range ports {
+			assigned[port.Label] = port
+			taken[out.IP] = append(taken[out.IP], port)
+		}
+	}
+
+	names := make([]string, 0, len(hostAsks))
+	for name := range hostAsks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ports, err := idx.assignHostNetwork(name, hostAsks[name], taken)
+		if err != nil {
+			return nil, err
+		}
+		for _, port := range ports {
+			assigned[port.Label] = port
+			taken[port.HostIP] = append(taken[port.HostIP], port)
+		}
+	}
+
+	// Keep the ports in the order of the ask
+	out.ReservedPorts, out.DynamicPorts = nil, nil
+	for _, port := range ask.ReservedPorts {
+		out.ReservedPorts = append(out.ReservedPorts, assigned[port.Label])
+	}
+	for _, port := range ask.DynamicPorts {
+		out.DynamicPorts = append(out.DynamicPorts, assigned[port.Label])
+	}
+	return out, nil
+}
+
+// assignHostNetwork assigns the ports pinned to a host network on one of its
+// addresses, avoiding the ports already taken by the ask on each address. The
+// assigned ports record the address they were allocated on.
+func (idx *NetworkIndex) assignHostNetwork(name string, ask *NetworkResource, taken map[string][]Port) ([]Port, error) {
+	err := fmt.Errorf("host network %q not available", name)
+	for _, n := range idx.HostNetworks {
+		if n.HostNetwork != name {
+			continue
+		}
+		used := idx.UsedPorts[n.IP]
+
+		// Check if any of the reserved ports are in use
+		collision := false
+		for _, port := range ask.ReservedPorts {
+			if port.Value < 0 || port.Value >= maxValidPort {
+				return nil, fmt.Errorf("invalid port %d (out of range)", port.Value)
+			}
+			if used != nil && used.Check(uint(port.Value)) {
+				collision = true
+				break
+			}
+			for _, t := range taken[n.IP] {
+				if t.Value == port.Value {
+					collision = true
+				}
+			}
+		}
+		if collision {
+			err = fmt.Errorf("reserved port collision on host network %q", name)
+			continue
+		}
+
+		// The dynamic ports must not collide with the reserved ports of the
+		// ask nor with the ports it already took on the address
+		pick := &NetworkResource{
+			ReservedPorts: append(append([]Port{}, ask.ReservedPorts...), taken[n.IP]...),
+			DynamicPorts:  ask.DynamicPorts,
+		}
+		dynPorts, dynErr := getDynamicPortsStochastic(used, pick)
+		if dynErr != nil {
+			dynPorts, dynErr = getDynamicPortsPrecise(used, pick)
+			if dynErr != nil {
+				err = dynErr
+				continue
+			}
+		}
+
+		ports := make([]Port, 0, len(ask.ReservedPorts)+len(ask.DynamicPorts))
+		for _, port := range ask.ReservedPorts {
+			port.HostIP = n.IP
+			ports = append(ports, port)
+		}
+		for i, port := range ask.DynamicPorts {
+			port.Value = dynPorts[i]
+			port.HostIP = n.IP
+			ports = append(ports, port)
+		}
+		return ports, nil
+	}
+	return nil, err
+}
+
+// isDefaultHostNetwork returns whether the name of the host network of a port
+// refers to the default network.
+func isDefaultHostNetwork(name string) bool {
+	return name == "" || name == DefaultHostNetwork
+}
+
+// assignDefaultNetwork assigns the ask on the addresses of the default network
+func (idx *NetworkIndex) assignDefaultNetwork(ask *NetworkResource) (out *NetworkResource, err error) {
 	err = fmt.Errorf("no networks available")
 	idx.yieldIP(func(n *NetworkResource, ip net.IP) (stop bool) {
 		// Convert the IP to a string
@@ -202,6 +385,7 @@ func (idx *NetworkIndex) AssignNetwork(ask *NetworkResource) (out *NetworkResour
 
 		// Create the offer
 		offer := &NetworkResource{
+			Mode:          ask.Mode,
 			Device:        n.Device,
 			IP:            ipStr,
 			MBits:         ask.MBits,
@@ -324,3 +508,176 @@ func isPortReserved(haystack []int, needle int) bool {
 	}
 	return false
 }
+
+// ClientHostNetworkConfig is used to configure a named host network of a
+// Nomad client, such as the public or private network of a multi-homed node.
+// The addresses of the host network are the addresses of its interface, or of
+// any interface, in its CIDR block. Ports pinned to the host network are
+// allocated on these addresses.
+type ClientHostNetworkConfig struct {
+	Name          string
+	CIDR          string
+	Interface     string
+	ReservedPorts string
+}
+
+func (p *ClientHostNetworkConfig) Copy() *ClientHostNetworkConfig {
+	if p == nil {
+		return nil
+	}
+
+	c := new(ClientHostNetworkConfig)
+	*c = *p
+	return c
+}
+
+// Validate is used to check that a host network is well formed.
+func (p *ClientHostNetworkConfig) Validate() error {
+	var mErr multierror.Error
+	if p.Name == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Missing host network name"))
+	} else if p.Name == DefaultHostNetwork {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Host network name %q is reserved for the default network", p.Name))
+	}
+	if p.CIDR == "" && p.Interface == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Host network %q must have a cidr or an interface", p.Name))
+	}
+	if p.CIDR != "" {
+		if _, _, err := net.ParseCIDR(p.CIDR); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid cidr for host network %q: %v", p.Name, err))
+		}
+	}
+	if _, err := ParsePortRanges(p.ReservedPorts); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid reserved ports for host network %q: %v", p.Name, err))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CopyMapStringClientHostNetworkConfig is a helper to copy a map of host
+// networks.
+func CopyMapStringClientHostNetworkConfig(m map[string]*ClientHostNetworkConfig) map[string]*ClientHostNetworkConfig {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]*ClientHostNetworkConfig, len(m))
+	for k, v := range m {
+		nm[k] = v.Copy()
+	}
+	return nm
+}
+
+// CopySliceClientHostNetworkConfig is a helper to copy a list of host
+// networks.
+func CopySliceClientHostNetworkConfig(s []*ClientHostNetworkConfig) []*ClientHostNetworkConfig {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	ns := make([]*ClientHostNetworkConfig, l)
+	for i, v := range s {
+		ns[i] = v.Copy()
+	}
+	return ns
+}
+
+// HostNetworkSliceMerge merges two lists of host networks. Networks in b
+// replace networks of the same name in a.
+func HostNetworkSliceMerge(a, b []*ClientHostNetworkConfig) []*ClientHostNetworkConfig {
+	n := make([]*ClientHostNetworkConfig, len(a))
+	seenKeys := make(map[string]int, len(a))
+
+	for i, config := range a {
+		n[i] = config.Copy()
+		seenKeys[config.Name] = i
+	}
+
+	for _, config := range b {
+		if fIndex, ok := seenKeys[config.Name]; ok {
+			n[fIndex] = config.Copy()
+			continue
+		}
+
+		n = append(n, config.Copy())
+	}
+
+	return n
+}
+
+// ParsePortRanges expands a list of ports into a sorted slice of port numbers.
+// The supported syntax is comma separated integers or ranges separated by
+// hyphens. For example, "80,120-150,160"
+func ParsePortRanges(spec string) ([]int, error) {
+	parts := strings.Split(spec, ",")
+
+	// Hot path the empty case
+	if len(parts) == 1 && parts[0] == "" {
+		return nil, nil
+	}
+
+	ports := make(map[int]struct{})
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		rangeParts := strings.Split(part, "-")
+		l := len(rangeParts)
+		switch l {
+		case 1:
+			if val := rangeParts[0]; val == "" {
+				return nil, fmt.Errorf("can't specify empty port")
+			} else {
+				port, err := strconv.Atoi(val)
+				if err != nil {
+					return nil, err
+				}
+				if err := validPortNumber(port); err != nil {
+					return nil, err
+				}
+				ports[port] = struct{}{}
+			}
+		case 2:
+			// We are parsing a range
+			start, err := strconv.Atoi(rangeParts[0])
+			if err != nil {
+				return nil, err
+			}
+
+			end, err := strconv.Atoi(rangeParts[1])
+			if err != nil {
+				return nil, err
+			}
+
+			if end < start {
+				return nil, fmt.Errorf("invalid range: ending value (%v) less than starting (%v) value", end, start)
+			}
+			if err := validPortNumber(start); err != nil {
+				return nil, err
+			}
+			if err := validPortNumber(end); err != nil {
+				return nil, err
+			}
+
+			for i := start; i <= end; i++ {
+				ports[i] = struct{}{}
+			}
+		default:
+			return nil, fmt.Errorf("can only parse single port numbers or port ranges (ex. 80,100-120,150)")
+		}
+	}
+
+	parsed := make([]int, 0, len(ports))
+	for port := range ports {
+		parsed = append(parsed, port)
+	}
+	sort.Ints(parsed)
+	return parsed, nil
+}
+
+// validPortNumber returns an error if the port is outside of the valid range
+// of port numbers.
+func validPortNumber(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %d: must be between 1 and 65535", port)
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"net"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestNetworkIndex_Overcommitted(t *testing.T) {
@@ -14,7 +16,7 @@ func TestNetworkIndex_Overcommitted(t *testing.T) {
 		Device:        "eth0",
 		IP:            "192.168.0.100",
 		MBits:         505,
-		ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+		ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 	}
 	collide := idx.AddReserved(reserved)
 	if collide {
@@ -65,7 +67,7 @@ func TestNetworkIndex_SetNode(t *testing.T) {
 				{
 					Device:        "eth0",
 					IP:            "192.168.0.100",
-					ReservedPorts: []Port{{"ssh", 22}},
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 					MBits:         1,
 				},
 			},
@@ -101,7 +103,7 @@ func TestNetworkIndex_AddAllocs(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         20,
-							ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+							ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 						},
 					},
 				},
@@ -115,7 +117,7 @@ func TestNetworkIndex_AddAllocs(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         50,
-							ReservedPorts: []Port{{"one", 10000}},
+							ReservedPorts: []Port{{Label: "one", Value: 10000}},
 						},
 					},
 				},
@@ -148,7 +150,7 @@ func TestNetworkIndex_AddReserved(t *testing.T) {
 		Device:        "eth0",
 		IP:            "192.168.0.100",
 		MBits:         20,
-		ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+		ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 	}
 	collide := idx.AddReserved(reserved)
 	if collide {
@@ -189,7 +191,7 @@ func TestNetworkIndex_yieldIP(t *testing.T) {
 				{
 					Device:        "eth0",
 					IP:            "192.168.0.100",
-					ReservedPorts: []Port{{"ssh", 22}},
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 					MBits:         1,
 				},
 			},
@@ -227,7 +229,7 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 				{
 					Device:        "eth0",
 					IP:            "192.168.0.100",
-					ReservedPorts: []Port{{"ssh", 22}},
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 					MBits:         1,
 				},
 			},
@@ -244,7 +246,7 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         20,
-							ReservedPorts: []Port{{"one", 8000}, {"two", 9000}},
+							ReservedPorts: []Port{{Label: "one", Value: 8000}, {Label: "two", Value: 9000}},
 						},
 					},
 				},
@@ -258,7 +260,7 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 							Device:        "eth0",
 							IP:            "192.168.0.100",
 							MBits:         50,
-							ReservedPorts: []Port{{"main", 10000}},
+							ReservedPorts: []Port{{Label: "main", Value: 10000}},
 						},
 					},
 				},
@@ -269,7 +271,7 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 
 	// Ask for a reserved port
 	ask := &NetworkResource{
-		ReservedPorts: []Port{{"main", 8000}},
+		ReservedPorts: []Port{{Label: "main", Value: 8000}},
 	}
 	offer, err := idx.AssignNetwork(ask)
 	if err != nil {
@@ -281,14 +283,14 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 	if offer.IP != "192.168.0.101" {
 		t.Fatalf("bad: %#v", offer)
 	}
-	rp := Port{"main", 8000}
+	rp := Port{Label: "main", Value: 8000}
 	if len(offer.ReservedPorts) != 1 || offer.ReservedPorts[0] != rp {
 		t.Fatalf("bad: %#v", offer)
 	}
 
 	// Ask for dynamic ports
 	ask = &NetworkResource{
-		DynamicPorts: []Port{{"http", 0}, {"https", 0}, {"admin", 0}},
+		DynamicPorts: []Port{{Label: "http", Value: 0}, {Label: "https", Value: 0}, {Label: "admin", Value: 0}},
 	}
 	offer, err = idx.AssignNetwork(ask)
 	if err != nil {
@@ -311,8 +313,8 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 
 	// Ask for reserved + dynamic ports
 	ask = &NetworkResource{
-		ReservedPorts: []Port{{"main", 2345}},
-		DynamicPorts:  []Port{{"http", 0}, {"https", 0}, {"admin", 0}},
+		ReservedPorts: []Port{{Label: "main", Value: 2345}},
+		DynamicPorts:  []Port{{Label: "http", Value: 0}, {Label: "https", Value: 0}, {Label: "admin", Value: 0}},
 	}
 	offer, err = idx.AssignNetwork(ask)
 	if err != nil {
@@ -325,7 +327,7 @@ func TestNetworkIndex_AssignNetwork(t *testing.T) {
 		t.Fatalf("bad: %#v", offer)
 	}
 
-	rp = Port{"main", 2345}
+	rp = Port{Label: "main", Value: 2345}
 	if len(offer.ReservedPorts) != 1 || offer.ReservedPorts[0] != rp {
 		t.Fatalf("bad: %#v", offer)
 	}
@@ -377,7 +379,7 @@ func TestNetworkIndex_AssignNetwork_Dynamic_Contention(t *testing.T) {
 
 	// Ask for dynamic ports
 	ask := &NetworkResource{
-		DynamicPorts: []Port{{"http", 0}},
+		DynamicPorts: []Port{{Label: "http", Value: 0}},
 	}
 	offer, err := idx.AssignNetwork(ask)
 	if err != nil {
@@ -397,6 +399,168 @@ func TestNetworkIndex_AssignNetwork_Dynamic_Contention(t *testing.T) {
 	}
 }
 
+func TestNetworkIndex_AssignNetwork_HostNetwork(t *testing.T) {
+	require := require.New(t)
+	idx := NewNetworkIndex()
+	n := &Node{
+		Resources: &Resources{
+			Networks: []*NetworkResource{
+				{
+					Device: "eth0",
+					CIDR:   "192.168.0.100/32",
+					IP:     "192.168.0.100",
+					MBits:  1000,
+				},
+				{
+					HostNetwork:   "public",
+					Device:        "eth1",
+					CIDR:          "10.0.0.1/32",
+					IP:            "10.0.0.1",
+					ReservedPorts: []Port{{Label: "ssh", Value: 22}},
+				},
+			},
+		},
+	}
+	require.False(idx.SetNode(n))
+	require.Len(idx.AvailNetworks, 1)
+	require.Len(idx.HostNetworks, 1)
+	require.True(idx.UsedPorts["10.0.0.1"].Check(22))
+
+	// The port 80 is used on the default network only
+	require.False(idx.AddAllocs([]*Allocation{
+		{
+			TaskResources: map[string]*Resources{
+				"web": {
+					Networks: []*NetworkResource{
+						{
+							Device:        "eth0",
+							IP:            "192.168.0.100",
+							MBits:         20,
+							ReservedPorts: []Port{{Label: "http", Value: 80}},
+						},
+					},
+				},
+			},
+		},
+	}))
+
+	ask := &NetworkResource{
+		MBits: 10,
+		ReservedPorts: []Port{
+			{Label: "admin", Value: 8080},
+			{Label: "http", Value: 80, HostNetwork: "public"},
+		},
+		DynamicPorts: []Port{
+			{Label: "metrics", HostNetwork: "public"},
+			{Label: "rpc", HostNetwork: DefaultHostNetwork},
+		},
+	}
+	offer, err := idx.AssignNetwork(ask)
+	require.NoError(err)
+	require.Equal("192.168.0.100", offer.IP)
+	require.Equal("eth0", offer.Device)
+	require.Equal(10, offer.MBits)
+	require.Equal([]Port{
+		{Label: "admin", Value: 8080},
+		{Label: "http", Value: 80, HostNetwork: "public", HostIP: "10.0.0.1"},
+	}, offer.ReservedPorts)
+	require.Len(offer.DynamicPorts, 2)
+	require.Equal("metrics", offer.DynamicPorts[0].Label)
+	require.Equal("10.0.0.1", offer.DynamicPorts[0].HostIP)
+	require.Equal("10.0.0.1", offer.PortIP(offer.DynamicPorts[0]))
+	require.Equal("rpc", offer.DynamicPorts[1].Label)
+	require.Empty(offer.DynamicPorts[1].HostIP)
+	require.Equal("192.168.0.100", offer.PortIP(offer.DynamicPorts[1]))
+
+	// The ports are reserved on the address they were allocated on
+	require.False(idx.AddReserved(offer))
+	require.True(idx.UsedPorts["10.0.0.1"].Check(80))
+	require.True(idx.UsedPorts["10.0.0.1"].Check(uint(offer.DynamicPorts[0].Value)))
+	require.True(idx.UsedPorts["192.168.0.100"].Check(8080))
+
+	// Reserved port collisions are detected on the host network
+	_, err = idx.AssignNetwork(&NetworkResource{
+		ReservedPorts: []Port{{Label: "ssh", Value: 22, HostNetwork: "public"}},
+	})
+	require.Error(err)
+	require.Contains(err.Error(), "reserved port collision")
+
+	// Unknown host networks can't be assigned
+	_, err = idx.AssignNetwork(&NetworkResource{
+		DynamicPorts: []Port{{Label: "http", HostNetwork: "private"}},
+	})
+	require.Error(err)
+	require.Contains(err.Error(), `host network "private" not available`)
+}
+
+func TestClientHostNetworkConfig_Validate(t *testing.T) {
+	cases := []struct {
+		Name   string
+		Config *ClientHostNetworkConfig
+		Err    string
+	}{
+		{
+			Name:   "cidr",
+			Config: &ClientHostNetworkConfig{Name: "public", CIDR: "10.0.0.0/8", ReservedPorts: "22,80-90"},
+		},
+		{
+			Name:   "interface",
+			Config: &ClientHostNetworkConfig{Name: "public", Interface: "eth1"},
+		},
+		{
+			Name:   "missing name",
+			Config: &ClientHostNetworkConfig{Interface: "eth1"},
+			Err:    "Missing host network name",
+		},
+		{
+			Name:   "default name",
+			Config: &ClientHostNetworkConfig{Name: DefaultHostNetwork, Interface: "eth1"},
+			Err:    "reserved for the default network",
+		},
+		{
+			Name:   "missing cidr and interface",
+			Config: &ClientHostNetworkConfig{Name: "public"},
+			Err:    "must have a cidr or an interface",
+		},
+		{
+			Name:   "invalid cidr",
+			Config: &ClientHostNetworkConfig{Name: "public", CIDR: "10.0.0.0"},
+			Err:    "Invalid cidr",
+		},
+		{
+			Name:   "invalid reserved ports",
+			Config: &ClientHostNetworkConfig{Name: "public", CIDR: "10.0.0.0/8", ReservedPorts: "90-80"},
+			Err:    "Invalid reserved ports",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			err := c.Config.Validate()
+			if c.Err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.Err)
+			}
+		})
+	}
+}
+
+func TestParsePortRanges(t *testing.T) {
+	ports, err := ParsePortRanges("80, 22,1000-1002,80")
+	require.NoError(t, err)
+	require.Equal(t, []int{22, 80, 1000, 1001, 1002}, ports)
+
+	ports, err = ParsePortRanges("")
+	require.NoError(t, err)
+	require.Empty(t, ports)
+
+	for _, spec := range []string{"0", "65536", "a", "10-", "10-5", "1-2-3", "1,,2"} {
+		_, err := ParsePortRanges(spec)
+		require.Error(t, err, spec)
+	}
+}
+
 func TestIntContains(t *testing.T) {
 	l := []int{1, 2, 10, 20}
 	if isPortReserved(l, 50) {
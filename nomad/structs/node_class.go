@@ -44,6 +44,14 @@ func (n Node) HashInclude(field string, v interface{}) (bool, error) {
 	switch field {
 	case "Datacenter", "Attributes", "Meta", "NodeClass":
 		return true, nil
+	case "HostVolumes":
+		// Only include host volumes when set so the computed class of nodes
+		// without any is unchanged.
+		return len(n.HostVolumes) != 0, nil
+	case "Devices":
+		// Device instance IDs are excluded by NodeDevice so nodes with the
+		// same devices share a class.
+		return len(n.Devices) != 0, nil
 	default:
 		return false, nil
 	}
@@ -60,6 +68,8 @@ func (n Node) HashIncludeMap(field string, k, v interface{}) (bool, error) {
 	switch field {
 	case "Meta", "Attributes":
 		return !IsUniqueNamespace(key), nil
+	case "HostVolumes":
+		return true, nil
 	default:
 		return false, fmt.Errorf("unexpected map field: %v", field)
 	}
@@ -183,6 +183,71 @@ func TestNode_ComputedClass_Meta(t *testing.T) {
 	}
 }
 
+func TestNode_ComputedClass_HostVolumes(t *testing.T) {
+	// Create a node and gets it computed class
+	n := testNode()
+	if err := n.ComputeClass(); err != nil {
+		t.Fatalf("ComputeClass() failed: %v", err)
+	}
+	old := n.ComputedClass
+
+	// An empty set of host volumes doesn't change the class
+	n.HostVolumes = map[string]*ClientHostVolumeConfig{}
+	if err := n.ComputeClass(); err != nil {
+		t.Fatalf("ComputeClass() failed: %v", err)
+	}
+	if old != n.ComputedClass {
+		t.Fatal("ComputeClass() didn't ignore empty host volumes")
+	}
+
+	// Add a host volume and compute the class again.
+	n.HostVolumes["shared"] = &ClientHostVolumeConfig{Name: "shared", Path: "/srv/shared"}
+	if err := n.ComputeClass(); err != nil {
+		t.Fatalf("ComputeClass() failed: %v", err)
+	}
+	if old == n.ComputedClass {
+		t.Fatal("ComputeClass() ignored host volume change")
+	}
+}
+
+func TestNode_ComputedClass_Devices(t *testing.T) {
+	// Create a node and gets it computed class
+	n := testNode()
+	n.Devices = []*NodeDeviceResource{
+		{
+			Vendor: "nvidia",
+			Type:   "gpu",
+			Name:   "Tesla K80",
+			Instances: []*NodeDevice{
+				{ID: "GPU-1", Healthy: true},
+			},
+		},
+	}
+	if err := n.ComputeClass(); err != nil {
+		t.Fatalf("ComputeClass() failed: %v", err)
+	}
+	old := n.ComputedClass
+
+	// Changing the ID of a device instance doesn't change the class
+	n.Devices[0].Instances[0].ID = "GPU-2"
+	n.Devices[0].Instances[0].Locality = &NodeDeviceLocality{PciBusID: "0000:04"}
+	if err := n.ComputeClass(); err != nil {
+		t.Fatalf("ComputeClass() failed: %v", err)
+	}
+	if old != n.ComputedClass {
+		t.Fatal("ComputeClass() didn't ignore device instance ID")
+	}
+
+	// Changing the health of a device instance changes the class
+	n.Devices[0].Instances[0].Healthy = false
+	if err := n.ComputeClass(); err != nil {
+		t.Fatalf("ComputeClass() failed: %v", err)
+	}
+	if old == n.ComputedClass {
+		t.Fatal("ComputeClass() ignored device health change")
+	}
+}
+
 func TestNode_EscapedConstraints(t *testing.T) {
 	// Non-escaped constraints
 	ne1 := &Constraint{
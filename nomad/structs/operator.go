@@ -1,8 +1,10 @@
 package structs
 
 import (
+	"fmt"
 	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/raft"
 )
 
@@ -63,6 +65,13 @@ type RaftPeerByIDRequest struct {
 	WriteRequest
 }
 
+// RaftTransferLeadershipRequest is used by the Operator endpoint to make the
+// leader step down so that another voter takes over the leadership.
+type RaftTransferLeadershipRequest struct {
+	// WriteRequest holds the Region for this request.
+	WriteRequest
+}
+
 // AutopilotSetConfigRequest is used by the Operator endpoint to update the
 // current Autopilot configuration of the cluster.
 type AutopilotSetConfigRequest struct {
@@ -103,7 +112,7 @@ type AutopilotConfig struct {
 	// be behind before being considered unhealthy.
 	MaxTrailingLogs uint64
 
-	// (Enterprise-only) EnableRedundancyZones specifies whether to enable redundancy zones.
+	// EnableRedundancyZones specifies whether to enable redundancy zones.
 	EnableRedundancyZones bool
 
 	// (Enterprise-only) DisableUpgradeMigration will disable Autopilot's upgrade migration
@@ -119,3 +128,178 @@ type AutopilotConfig struct {
 	CreateIndex uint64
 	ModifyIndex uint64
 }
+
+// SchedulerAlgorithm is the algorithm the scheduler uses to score the nodes
+// a task group fits on.
+type SchedulerAlgorithm string
+
+const (
+	// SchedulerAlgorithmBinpack prefers the nodes with the least free
+	// resources, packing allocations tightly to maximize utilization.
+	SchedulerAlgorithmBinpack SchedulerAlgorithm = "binpack"
+
+	// SchedulerAlgorithmSpread prefers the nodes with the most free
+	// resources, spreading allocations to reduce the impact of node failures.
+	SchedulerAlgorithmSpread SchedulerAlgorithm = "spread"
+)
+
+// SchedulerConfiguration is the config for the scheduler.
+type SchedulerConfiguration struct {
+	// SchedulerAlgorithm is the default algorithm used to score nodes. It
+	// defaults to bin packing when unset.
+	SchedulerAlgorithm SchedulerAlgorithm
+
+	// NodePoolSchedulerAlgorithms overrides the scheduler algorithm for the
+	// jobs of the given node pools.
+	NodePoolSchedulerAlgorithms map[string]SchedulerAlgorithm
+
+	// PreemptionConfig specifies whether to enable eviction of lower
+	// priority jobs to place higher priority jobs.
+	PreemptionConfig PreemptionConfig
+
+	// CreateIndex/ModifyIndex store the create/modify indexes of this configuration.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// EffectiveSchedulerAlgorithm returns the scheduler algorithm used to place
+// the jobs of the given node pool.
+func (s *SchedulerConfiguration) EffectiveSchedulerAlgorithm(pool string) SchedulerAlgorithm {
+	if s == nil {
+		return SchedulerAlgorithmBinpack
+	}
+
+	if pool == "" {
+		pool = NodePoolDefault
+	}
+	if algorithm, ok := s.NodePoolSchedulerAlgorithms[pool]; ok && algorithm != "" {
+		return algorithm
+	}
+
+	if s.SchedulerAlgorithm == "" {
+		return SchedulerAlgorithmBinpack
+	}
+	return s.SchedulerAlgorithm
+}
+
+// Validate returns an error if the scheduler configuration is invalid.
+func (s *SchedulerConfiguration) Validate() error {
+	var mErr multierror.Error
+	if err := validateSchedulerAlgorithm(s.SchedulerAlgorithm, true); err != nil {
+		multierror.Append(&mErr, err)
+	}
+
+	for pool, algorithm := range s.NodePoolSchedulerAlgorithms {
+		if err := ValidateNodePool(pool); err != nil {
+			multierror.Append(&mErr, err)
+		}
+		if err := validateSchedulerAlgorithm(algorithm, false); err != nil {
+			multierror.Append(&mErr, fmt.Errorf("node pool %q: %v", pool, err))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// validateSchedulerAlgorithm returns an error if the algorithm is unknown.
+func validateSchedulerAlgorithm(algorithm SchedulerAlgorithm, allowEmpty bool) error {
+	switch algorithm {
+	case SchedulerAlgorithmBinpack, SchedulerAlgorithmSpread:
+		return nil
+	case "":
+		if allowEmpty {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid scheduler algorithm %q", algorithm)
+}
+
+// PreemptionEnabled returns whether preemption is enabled for the scheduler
+// of the given job type.
+func (s *SchedulerConfiguration) PreemptionEnabled(jobType string) bool {
+	if s == nil {
+		return false
+	}
+
+	switch jobType {
+	case JobTypeSystem:
+		return s.PreemptionConfig.SystemSchedulerEnabled
+	case JobTypeSysBatch:
+		return s.PreemptionConfig.SysBatchSchedulerEnabled
+	case JobTypeBatch:
+		return s.PreemptionConfig.BatchSchedulerEnabled
+	case JobTypeService:
+		return s.PreemptionConfig.ServiceSchedulerEnabled
+	default:
+		return false
+	}
+}
+
+// PreemptionConfig specifies whether preemption is enabled for each
+// scheduler type.
+type PreemptionConfig struct {
+	// SystemSchedulerEnabled specifies if preemption is enabled for system jobs
+	SystemSchedulerEnabled bool
+
+	// SysBatchSchedulerEnabled specifies if preemption is enabled for sysbatch jobs
+	SysBatchSchedulerEnabled bool
+
+	// BatchSchedulerEnabled specifies if preemption is enabled for batch jobs
+	BatchSchedulerEnabled bool
+
+	// ServiceSchedulerEnabled specifies if preemption is enabled for service jobs
+	ServiceSchedulerEnabled bool
+}
+
+// SchedulerSetConfigRequest is used by the Operator endpoint to update the
+// current Scheduler configuration of the cluster.
+type SchedulerSetConfigRequest struct {
+	// Config is the new Scheduler configuration to use.
+	Config SchedulerConfiguration
+
+	// CAS controls whether to use check-and-set semantics for this request.
+	CAS bool
+
+	// WriteRequest holds the ACL token to go along with this request.
+	WriteRequest
+}
+
+// SnapshotSaveRequest is used by the Operator endpoint to stream a snapshot
+// of the state of the cluster.
+type SnapshotSaveRequest struct {
+	QueryOptions
+}
+
+// SnapshotSaveResponse is sent by the Operator endpoint before streaming the
+// snapshot, or to report the error preventing it.
+type SnapshotSaveResponse struct {
+	// SnapshotChecksum is the checksum of the snapshot archive, in the format
+	// of an HTTP Digest header.
+	SnapshotChecksum string
+
+	// ErrorCode is the HTTP status code of the error, if any.
+	ErrorCode int `codec:",omitempty"`
+
+	// ErrorMsg is the message of the error, if any.
+	ErrorMsg string `codec:",omitempty"`
+
+	QueryMeta
+}
+
+// SnapshotRestoreRequest is used by the Operator endpoint to restore the
+// state of the cluster from a snapshot streamed after the request.
+type SnapshotRestoreRequest struct {
+	WriteRequest
+}
+
+// SnapshotRestoreResponse is sent by the Operator endpoint once the snapshot
+// is restored, or to report the error preventing it.
+type SnapshotRestoreResponse struct {
+	// ErrorCode is the HTTP status code of the error, if any.
+	ErrorCode int `codec:",omitempty"`
+
+	// ErrorMsg is the message of the error, if any.
+	ErrorMsg string `codec:",omitempty"`
+
+	QueryMeta
+}
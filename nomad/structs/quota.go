@@ -0,0 +1,369 @@
+package structs
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// maxQuotaDescriptionLength is the maximum length of the description of
+	// a quota specification.
+	maxQuotaDescriptionLength = 256
+)
+
+// QuotaSpec specifies the allowed resource usage of the namespaces attached
+// to it, for each region.
+type QuotaSpec struct {
+	// Name is the name for the quota object
+	Name string
+
+	// Description is an optional description for the quota object
+	Description string
+
+	// Limits is the set of quota limits encapsulated by this quota object.
+	// Each limit applies quota in a particular region.
+	Limits []*QuotaLimit
+
+	// Hash is the hash of the object and is used to make replication
+	// efficient.
+	Hash []byte
+
+	// Raft indexes to track creation and modification
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Validate returns an error if the quota specification is invalid.
+func (q *QuotaSpec) Validate() error {
+	var mErr multierror.Error
+
+	if !validNamespaceName.MatchString(q.Name) {
+		err := fmt.Errorf("invalid name %q. Must match regex %s", q.Name, validNamespaceName)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(q.Description) > maxQuotaDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxQuotaDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	regions := make(map[string]struct{}, len(q.Limits))
+	for i, l := range q.Limits {
+		if l == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("limit %d is nil", i+1))
+			continue
+		}
+		if err := l.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("limit %d is invalid: %v", i+1, err))
+		}
+		if _, ok := regions[l.Region]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("duplicate limit for region %q", l.Region))
+		}
+		regions[l.Region] = struct{}{}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// SetHash is used to compute and set the hash of the quota specification and
+// its limits.
+func (q *QuotaSpec) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(q.Name))
+	hash.Write([]byte(q.Description))
+	for _, l := range q.Limits {
+		hash.Write(l.SetHash())
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	q.Hash = hashVal
+	return hashVal
+}
+
+// RegionLimit returns the limit of the quota specification in the region, or
+// nil if the region is not limited.
+func (q *QuotaSpec) RegionLimit(region string) *QuotaLimit {
+	for _, l := range q.Limits {
+		if l.Region == region {
+			return l
+		}
+	}
+	return nil
+}
+
+func (q *QuotaSpec) Copy() *QuotaSpec {
+	if q == nil {
+		return nil
+	}
+	nq := new(QuotaSpec)
+	*nq = *q
+	nq.Hash = make([]byte, len(q.Hash))
+	copy(nq.Hash, q.Hash)
+	if q.Limits != nil {
+		nq.Limits = make([]*QuotaLimit, len(q.Limits))
+		for i, l := range q.Limits {
+			nq.Limits[i] = l.Copy()
+		}
+	}
+	return nq
+}
+
+// QuotaLimit describes the resource limit in a particular region.
+type QuotaLimit struct {
+	// Region is the region in which this limit has affect
+	Region string
+
+	// RegionLimit is the quota limit that applies to any allocation within a
+	// referencing namespace in the region. Only the CPU, MemoryMB and DiskMB
+	// fields are limited. A value of zero is treated as unlimited and a
+	// negative value is treated as fully disallowed.
+	RegionLimit *Resources
+
+	// Hash is the hash of the object and is used to make replication
+	// efficient.
+	Hash []byte
+}
+
+// Validate returns an error if the quota limit is invalid.
+func (l *QuotaLimit) Validate() error {
+	var mErr multierror.Error
+
+	if l.Region == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing region"))
+	}
+	if l.RegionLimit == nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("missing region limit"))
+	} else {
+		if len(l.RegionLimit.Networks) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("network limits are not supported"))
+		}
+		if len(l.RegionLimit.Devices) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("device limits are not supported"))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// SetHash is used to compute and set the hash of the quota limit.
+func (l *QuotaLimit) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(l.Region))
+	if l.RegionLimit != nil {
+		binary.Write(hash, binary.LittleEndian, int64(l.RegionLimit.CPU))
+		binary.Write(hash, binary.LittleEndian, int64(l.RegionLimit.MemoryMB))
+		binary.Write(hash, binary.LittleEndian, int64(l.RegionLimit.DiskMB))
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	l.Hash = hashVal
+	return hashVal
+}
+
+// Key returns the key of the usage of the limit in a QuotaUsage.
+func (l *QuotaLimit) Key() string {
+	return base64.StdEncoding.EncodeToString(l.Hash)
+}
+
+// ExceededBy returns the dimensions of the limit the usage exceeds once the
+// delta is added to it. Only the dimensions the delta increases are checked,
+// so that the usage of a quota whose limit was lowered can still go down.
+func (l *QuotaLimit) ExceededBy(used, delta *Resources) []string {
+	if l.RegionLimit == nil {
+		return nil
+	}
+
+	var exceeded []string
+	check := func(dimension string, limit, used, delta int) {
+		if delta <= 0 || limit == 0 {
+			return
+		}
+		if limit < 0 {
+			limit = 0
+		}
+		if used+delta > limit {
+			exceeded = append(exceeded, fmt.Sprintf("%s exhausted (%d > %d)", dimension, used+delta, limit))
+		}
+	}
+	check("cpu", l.RegionLimit.CPU, used.CPU, delta.CPU)
+	check("memory", l.RegionLimit.MemoryMB, used.MemoryMB, delta.MemoryMB)
+	check("disk", l.RegionLimit.DiskMB, used.DiskMB, delta.DiskMB)
+	return exceeded
+}
+
+func (l *QuotaLimit) Copy() *QuotaLimit {
+	if l == nil {
+		return nil
+	}
+	nl := new(QuotaLimit)
+	*nl = *l
+	nl.RegionLimit = l.RegionLimit.Copy()
+	nl.Hash = make([]byte, len(l.Hash))
+	copy(nl.Hash, l.Hash)
+	return nl
+}
+
+// QuotaUsage is the resource usage of a quota specification in a region.
+type QuotaUsage struct {
+	// Name is the name of the quota specification
+	Name string
+
+	// Used is the resource usage of the allocations of the namespaces
+	// attached to the quota, for each limit of the quota in the region. It
+	// is keyed by the Key of the limit.
+	Used map[string]*QuotaLimit
+
+	// Raft indexes to track creation and modification
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// NewQuotaUsage returns an empty usage of the limits of the quota
+// specification in the region.
+func NewQuotaUsage(spec *QuotaSpec, region string) *QuotaUsage {
+	u := &QuotaUsage{
+		Name: spec.Name,
+		Used: make(map[string]*QuotaLimit),
+	}
+	if l := spec.RegionLimit(region); l != nil {
+		u.Used[l.Key()] = &QuotaLimit{
+			Region:      l.Region,
+			RegionLimit: new(Resources),
+			Hash:        l.Hash,
+		}
+	}
+	return u
+}
+
+func (u *QuotaUsage) Copy() *QuotaUsage {
+	if u == nil {
+		return nil
+	}
+	nu := new(QuotaUsage)
+	*nu = *u
+	if u.Used != nil {
+		nu.Used = make(map[string]*QuotaLimit, len(u.Used))
+		for k, l := range u.Used {
+			nu.Used[k] = l.Copy()
+		}
+	}
+	return nu
+}
+
+// QuotaResources returns the resources of the allocation that count against
+// a quota.
+func (a *Allocation) QuotaResources() *Resources {
+	r := new(Resources)
+	if a.Resources != nil {
+		r.CPU = a.Resources.CPU
+		r.MemoryMB = a.Resources.MemoryMB
+		r.DiskMB = a.Resources.DiskMB
+		return r
+	}
+
+	// Allocations within a plan have the combined resources stripped, so
+	// sum up the shared and individual task resources.
+	if a.SharedResources != nil {
+		r.DiskMB = a.SharedResources.DiskMB
+	}
+	for _, tr := range a.TaskResources {
+		r.CPU += tr.CPU
+		r.MemoryMB += tr.MemoryMB
+		r.DiskMB += tr.DiskMB
+	}
+	return r
+}
+
+// AddQuotaResources adds the quota resources of the delta to the resources,
+// or subtracts them if sign is negative.
+func (r *Resources) AddQuotaResources(delta *Resources, sign int) {
+	r.CPU += sign * delta.CPU
+	r.MemoryMB += sign * delta.MemoryMB
+	r.DiskMB += sign * delta.DiskMB
+}
+
+// QuotaSpecListRequest is used to request a list of quota specifications
+type QuotaSpecListRequest struct {
+	QueryOptions
+}
+
+// QuotaSpecListResponse is used for a list request
+type QuotaSpecListResponse struct {
+	Quotas []*QuotaSpec
+	QueryMeta
+}
+
+// QuotaSpecificRequest is used to query a specific quota specification or
+// usage
+type QuotaSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleQuotaSpecResponse is used to return a single quota specification
+type SingleQuotaSpecResponse struct {
+	Quota *QuotaSpec
+	QueryMeta
+}
+
+// QuotaSpecSetRequest is used to query a set of quota specifications
+type QuotaSpecSetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// QuotaSpecSetResponse is used to return a set of quota specifications
+type QuotaSpecSetResponse struct {
+	Quotas map[string]*QuotaSpec // Keyed by quota Name
+	QueryMeta
+}
+
+// QuotaSpecUpsertRequest is used to upsert a set of quota specifications
+type QuotaSpecUpsertRequest struct {
+	Quotas []*QuotaSpec
+	WriteRequest
+}
+
+// QuotaSpecDeleteRequest is used to delete a set of quota specifications
+type QuotaSpecDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// QuotaUsageListRequest is used to request a list of quota usages
+type QuotaUsageListRequest struct {
+	QueryOptions
+}
+
+// QuotaUsageListResponse is used for a list request
+type QuotaUsageListResponse struct {
+	Usages []*QuotaUsage
+	QueryMeta
+}
+
+// SingleQuotaUsageResponse is used to return a single quota usage
+type SingleQuotaUsageResponse struct {
+	Usage *QuotaUsage
+	QueryMeta
+}
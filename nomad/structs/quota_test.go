@@ -0,0 +1,98 @@
+package structs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaSpec_Validate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	spec := &QuotaSpec{
+		Name: "default-quota",
+		Limits: []*QuotaLimit{
+			{
+				Region:      "global",
+				RegionLimit: &Resources{CPU: 1000},
+			},
+		},
+	}
+	require.NoError(spec.Validate())
+
+	spec.Name = "bad name"
+	spec.Limits = append(spec.Limits,
+		&QuotaLimit{Region: "global", RegionLimit: &Resources{}},
+		&QuotaLimit{RegionLimit: &Resources{Networks: []*NetworkResource{{MBits: 10}}}},
+	)
+	err := spec.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "invalid name")
+	require.Contains(err.Error(), `duplicate limit for region "global"`)
+	require.Contains(err.Error(), "missing region")
+	require.Contains(err.Error(), "network limits are not supported")
+}
+
+func TestQuotaSpec_SetHash(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	spec := &QuotaSpec{
+		Name: "default-quota",
+		Limits: []*QuotaLimit{
+			{
+				Region:      "global",
+				RegionLimit: &Resources{CPU: 1000},
+			},
+		},
+	}
+	out1 := spec.SetHash()
+	require.NotNil(out1)
+	require.Equal(out1, spec.Hash)
+	require.Equal(spec.Limits[0].Key(), spec.RegionLimit("global").Key())
+
+	spec.Limits[0].RegionLimit.CPU = 2000
+	out2 := spec.SetHash()
+	require.NotEqual(out1, out2)
+}
+
+func TestQuotaLimit_ExceededBy(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	limit := &QuotaLimit{
+		Region: "global",
+		RegionLimit: &Resources{
+			CPU:      1000,
+			MemoryMB: -1,
+		},
+	}
+
+	// Unlimited dimensions and ones the delta doesn't increase are ignored
+	used := &Resources{CPU: 500, MemoryMB: 512, DiskMB: 1000}
+	require.Empty(limit.ExceededBy(used, &Resources{CPU: 500, DiskMB: 100}))
+	require.Empty(limit.ExceededBy(used, &Resources{CPU: -100, MemoryMB: -10}))
+
+	// Negative limits disallow any usage
+	require.Equal([]string{"cpu exhausted (1100 > 1000)", "memory exhausted (768 > 0)"},
+		limit.ExceededBy(used, &Resources{CPU: 600, MemoryMB: 256}))
+}
+
+func TestAllocation_QuotaResources(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	alloc := &Allocation{
+		TaskResources: map[string]*Resources{
+			"web":     {CPU: 500, MemoryMB: 256},
+			"sidecar": {CPU: 100, MemoryMB: 64},
+		},
+		SharedResources: &Resources{DiskMB: 150},
+	}
+	require.Equal(&Resources{CPU: 600, MemoryMB: 320, DiskMB: 150}, alloc.QuotaResources())
+
+	// The combined resources are used when present
+	alloc.Resources = &Resources{CPU: 700, MemoryMB: 400, DiskMB: 200}
+	require.Equal(&Resources{CPU: 700, MemoryMB: 400, DiskMB: 200}, alloc.QuotaResources())
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"net"
 	"net/url"
@@ -46,6 +47,12 @@ var (
 	// validPolicyName is used to validate a policy name
 	validPolicyName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
 
+	// validNamespaceName is used to validate a namespace name
+	validNamespaceName = regexp.MustCompile("^[a-zA-Z0-9-]{1,128}$")
+
+	// validNodePoolName is used to validate a node pool name
+	validNodePoolName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+
 	// b32 is a lowercase base32 encoding for use in URL friendly service hashes
 	b32 = base32.NewEncoding(strings.ToLower("abcdefghijklmnopqrstuvwxyz234567"))
 )
@@ -83,6 +90,22 @@ const (
 	AllocUpdateDesiredTransitionRequestType
 	NodeUpdateEligibilityRequestType
 	BatchNodeUpdateDrainRequestType
+	SchedulerConfigRequestType
+	NamespaceUpsertRequestType
+	NamespaceDeleteRequestType
+	QuotaSpecUpsertRequestType
+	QuotaSpecDeleteRequestType
+	ACLRoleUpsertRequestType
+	ACLRoleDeleteRequestType
+	ACLAuthMethodUpsertRequestType
+	ACLAuthMethodDeleteRequestType
+	ACLBindingRuleUpsertRequestType
+	ACLBindingRuleDeleteRequestType
+	RootKeyUpsertRequestType
+	VariableUpsertRequestType
+	VariableDeleteRequestType
+	ServiceRegistrationUpsertRequestType
+	ServiceRegistrationDeleteByIDRequestType
 )
 
 const (
@@ -122,10 +145,30 @@ const (
 	ACLClientToken     = "client"
 	ACLManagementToken = "management"
 
+	// ACLAuthMethodTypeJWT and ACLAuthMethodTypeOIDC are the types of auth
+	// methods. JWT methods validate a token presented by the caller, OIDC
+	// methods run the authorization code flow with a provider.
+	ACLAuthMethodTypeJWT  = "JWT"
+	ACLAuthMethodTypeOIDC = "OIDC"
+
+	// ACLBindingRuleBindTypeRole, ACLBindingRuleBindTypePolicy and
+	// ACLBindingRuleBindTypeManagement are what a binding rule grants the
+	// tokens created by a login
+	ACLBindingRuleBindTypeRole       = "role"
+	ACLBindingRuleBindTypePolicy     = "policy"
+	ACLBindingRuleBindTypeManagement = "management"
+
 	// DefaultNamespace is the default namespace.
 	DefaultNamespace            = "default"
 	DefaultNamespaceDescription = "Default shared namespace"
 
+	// AllNamespacesSentinel is the namespace of the list requests returning
+	// the objects of all the namespaces the token may read.
+	AllNamespacesSentinel = "*"
+
+	// maxNamespaceDescriptionLength limits a namespace description length
+	maxNamespaceDescriptionLength = 256
+
 	// JitterFraction is a the limit to the amount of jitter we apply
 	// to a user specified MaxQueryTime. We divide the specified time by
 	// the fraction. So 16 == 6.25% limit of jitter. This jitter is also
@@ -436,6 +479,42 @@ type SearchRequest struct {
 	QueryOptions
 }
 
+// FuzzySearchRequest is used to parameterize a fuzzy search, returning the
+// objects of a context whose name or ID contains the given text.
+type FuzzySearchRequest struct {
+	// Text is the case insensitive substring matched against the names and
+	// IDs of the objects
+	Text string
+
+	// Context is the type that can be matched against, or all of them
+	Context Context
+
+	QueryOptions
+}
+
+// FuzzyMatch is an object matching a fuzzy search
+type FuzzyMatch struct {
+	// ID is the name of the object, or its ID if it has no name
+	ID string
+
+	// Scope is the namespace, if any, and the ID of the object, identifying
+	// it when its name isn't unique
+	Scope []string
+}
+
+// FuzzySearchResponse is used to return the fuzzy matches of each context
+// and whether they have been truncated.
+type FuzzySearchResponse struct {
+	// Matches maps the contexts to their matches, best matches first
+	Matches map[Context][]FuzzyMatch
+
+	// Truncations indicates whether the matches for a particular context have
+	// been truncated
+	Truncations map[Context]bool
+
+	QueryMeta
+}
+
 // JobRegisterRequest is used for Job.Register endpoint
 // to register a job as being a schedulable entity.
 type JobRegisterRequest struct {
@@ -450,6 +529,18 @@ type JobRegisterRequest struct {
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
 
+	// MultiregionFanout is set when the region a multiregion job was
+	// submitted to registers the job in one of the job's regions.
+	MultiregionFanout bool
+
+	// MultiregionCheck is set along with MultiregionFanout to only check
+	// that the job can be registered in the region, without registering it.
+	MultiregionCheck bool
+
+	// Submission is the source the job was parsed from, stored alongside the
+	// registered job version if set
+	Submission *JobSubmission
+
 	WriteRequest
 }
 
@@ -593,6 +684,9 @@ type EvalDeleteRequest struct {
 // EvalSpecificRequest is used when we just need to specify a target evaluation
 type EvalSpecificRequest struct {
 	EvalID string
+
+	// IncludeRelated returns the evaluations related to the evaluation
+	IncludeRelated bool
 	QueryOptions
 }
 
@@ -613,6 +707,10 @@ type EvalDequeueRequest struct {
 
 // EvalListRequest is used to list the evaluations
 type EvalListRequest struct {
+	// FilterJobID and FilterEvalStatus only return the evaluations of the
+	// job and with the status, if set
+	FilterJobID      string
+	FilterEvalStatus string
 	QueryOptions
 }
 
@@ -645,6 +743,10 @@ type ApplyPlanResultsRequest struct {
 	// processed many times, potentially making state updates, without the state of
 	// the evaluation itself being updated.
 	EvalID string
+
+	// PreemptionEvals is a slice of follow up evals for jobs whose allocations
+	// have been preempted to place allocs in this plan
+	PreemptionEvals []*Evaluation
 }
 
 // AllocUpdateRequest is used to submit changes to allocations, either
@@ -680,6 +782,9 @@ type AllocUpdateDesiredTransitionRequest struct {
 
 // AllocListRequest is used to request a list of allocations
 type AllocListRequest struct {
+	// FilterClientStatus only returns the allocations with the client
+	// status, if set
+	FilterClientStatus string
 	QueryOptions
 }
 
@@ -689,6 +794,47 @@ type AllocSpecificRequest struct {
 	QueryOptions
 }
 
+// AllocSignalRequest is used to signal the tasks of an allocation
+type AllocSignalRequest struct {
+	AllocID string
+
+	// Task is the task to signal. All tasks of the allocation are signalled
+	// if it is empty.
+	Task string
+
+	// Signal is the name of the signal, such as SIGHUP
+	Signal string
+
+	QueryOptions
+}
+
+// AllocRestartRequest is used to restart the tasks of an allocation
+type AllocRestartRequest struct {
+	AllocID string
+
+	// TaskName is the task to restart. All tasks of the allocation are
+	// restarted if it is empty.
+	TaskName string
+
+	QueryOptions
+}
+
+// AllocStopRequest is used to stop and reschedule a running allocation
+type AllocStopRequest struct {
+	AllocID string
+
+	WriteRequest
+}
+
+// AllocStopResponse is the response to an AllocStopRequest
+type AllocStopResponse struct {
+	// EvalID is the id of the follow up evaluation that reschedules the
+	// allocation
+	EvalID string
+
+	WriteMeta
+}
+
 // AllocsGetRequest is used to query a set of allocations
 type AllocsGetRequest struct {
 	AllocIDs []string
@@ -905,6 +1051,10 @@ type JobRegisterResponse struct {
 	// deprecation warnings.
 	Warnings string
 
+	// RegionResults are the results of the registration of a multiregion job
+	// in each of the job's regions, keyed by region.
+	RegionResults map[string]*JobRegisterResponse
+
 	QueryMeta
 }
 
@@ -932,6 +1082,10 @@ type JobValidateResponse struct {
 	// ValidationErrors is a list of validation errors
 	ValidationErrors []string
 
+	// ValidationWarnings is a list of the warnings about the job, such as
+	// deprecations
+	ValidationWarnings []string
+
 	// Error is a string version of any error that may have occurred
 	Error string
 
@@ -993,6 +1147,11 @@ type NodeClientAllocsResponse struct {
 	// authenticated access to sticky volumes
 	MigrateTokens map[string]string
 
+	// SignedIdentities are the workload identities of the tasks of each
+	// allocation, keyed by allocation ID and task name. The other endpoints
+	// returning allocations omit them.
+	SignedIdentities map[string]map[string]string
+
 	QueryMeta
 }
 
@@ -1034,6 +1193,22 @@ type JobListResponse struct {
 	QueryMeta
 }
 
+// JobSubmissionRequest is used to get the source of a job version
+type JobSubmissionRequest struct {
+	JobID string
+
+	// Version is the job version whose source is returned. The current
+	// version is used if it's nil.
+	Version *uint64
+	QueryOptions
+}
+
+// JobSubmissionResponse is used to return the source of a job version
+type JobSubmissionResponse struct {
+	Submission *JobSubmission
+	QueryMeta
+}
+
 // JobVersionsRequest is used to get a jobs versions
 type JobVersionsRequest struct {
 	JobID string
@@ -1302,6 +1477,23 @@ func ShouldDrainNode(status string) bool {
 	}
 }
 
+const (
+	// NodePoolDefault is the node pool of nodes and jobs that don't set one.
+	NodePoolDefault = "default"
+
+	// NodePoolAll is a pseudo node pool that jobs can use to be placed on
+	// nodes of any pool. Nodes can't be in it.
+	NodePoolAll = "all"
+)
+
+// ValidateNodePool returns an error if the name is not a valid node pool name.
+func ValidateNodePool(pool string) error {
+	if !validNodePoolName.MatchString(pool) {
+		return fmt.Errorf("invalid node pool %q: must be 1-128 alphanumeric, dash or underscore characters", pool)
+	}
+	return nil
+}
+
 // ValidNodeStatus is used to check if a node status is valid
 func ValidNodeStatus(status string) bool {
 	switch status {
@@ -1449,6 +1641,10 @@ type Node struct {
 	// together for the purpose of determining scheduling pressure.
 	NodeClass string
 
+	// NodePool is the node pool of the node. Jobs are only placed on the
+	// nodes of their pool.
+	NodePool string
+
 	// ComputedClass is a unique id that identifies nodes with a common set of
 	// attributes and capabilities.
 	ComputedClass string
@@ -1485,11 +1681,35 @@ type Node struct {
 	// Drivers is a map of driver names to current driver information
 	Drivers map[string]*DriverInfo
 
+	// HostVolumes is a map of host volume names to their configuration
+	HostVolumes map[string]*ClientHostVolumeConfig
+
+	// Devices are the device groups fingerprinted by the device plugins of
+	// the node
+	Devices []*NodeDeviceResource
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// InNodePool returns whether jobs of the given node pool can be placed on the
+// node. Nodes and jobs without a pool are in the default pool.
+func (n *Node) InNodePool(pool string) bool {
+	if pool == "" {
+		pool = NodePoolDefault
+	}
+	if pool == NodePoolAll {
+		return true
+	}
+
+	nodePool := n.NodePool
+	if nodePool == "" {
+		nodePool = NodePoolDefault
+	}
+	return nodePool == pool
+}
+
 // Ready returns true if the node is ready for running allocations
 func (n *Node) Ready() bool {
 	// Drain is checked directly to support pre-0.8 Node data
@@ -1527,6 +1747,8 @@ func (n *Node) Copy() *Node {
 	nn.Events = copyNodeEvents(n.Events)
 	nn.DrainStrategy = nn.DrainStrategy.Copy()
 	nn.Drivers = copyNodeDrivers(n.Drivers)
+	nn.HostVolumes = CopyMapStringClientHostVolumeConfig(n.HostVolumes)
+	nn.Devices = CopySliceNodeDeviceResource(n.Devices)
 	return nn
 }
 
@@ -1580,6 +1802,7 @@ func (n *Node) Stub() *NodeListStub {
 		Datacenter:            n.Datacenter,
 		Name:                  n.Name,
 		NodeClass:             n.NodeClass,
+		NodePool:              n.NodePool,
 		Version:               n.Attributes["nomad.version"],
 		Drain:                 n.Drain,
 		SchedulingEligibility: n.SchedulingEligibility,
@@ -1599,6 +1822,7 @@ type NodeListStub struct {
 	Datacenter            string
 	Name                  string
 	NodeClass             string
+	NodePool              string
 	Version               string
 	Drain                 bool
 	SchedulingEligibility string
@@ -1609,20 +1833,33 @@ type NodeListStub struct {
 	ModifyIndex           uint64
 }
 
-// Networks defined for a task on the Resources struct.
+// Networks defined for a task on the Resources struct, or shared by the tasks
+// of a task group.
 type Networks []*NetworkResource
 
+// Copy returns a deep copy of the networks
+func (ns Networks) Copy() Networks {
+	if ns == nil {
+		return nil
+	}
+	out := make(Networks, len(ns))
+	for i, n := range ns {
+		out[i] = n.Copy()
+	}
+	return out
+}
+
 // Port assignment and IP for the given label or empty values.
 func (ns Networks) Port(label string) (string, int) {
 	for _, n := range ns {
 		for _, p := range n.ReservedPorts {
 			if p.Label == label {
-				return n.IP, p.Value
+				return n.PortIP(p), p.Value
 			}
 		}
 		for _, p := range n.DynamicPorts {
 			if p.Label == label {
-				return n.IP, p.Value
+				return n.PortIP(p), p.Value
 			}
 		}
 	}
@@ -1634,9 +1871,16 @@ func (ns Networks) Port(label string) (string, int) {
 type Resources struct {
 	CPU      int
 	MemoryMB int
+
+	// MemoryMaxMB is the hard memory limit of a task. The task may use memory
+	// above its MemoryMB reservation up to this limit. Placements are only
+	// made against MemoryMB. Zero means the reservation is the hard limit.
+	MemoryMaxMB int
+
 	DiskMB   int
 	IOPS     int
 	Networks Networks
+	Devices  []*RequestedDevice
 }
 
 const (
@@ -1681,6 +1925,9 @@ func (r *Resources) Merge(other *Resources) {
 	if other.MemoryMB != 0 {
 		r.MemoryMB = other.MemoryMB
 	}
+	if other.MemoryMaxMB != 0 {
+		r.MemoryMaxMB = other.MemoryMaxMB
+	}
 	if other.DiskMB != 0 {
 		r.DiskMB = other.DiskMB
 	}
@@ -1690,6 +1937,9 @@ func (r *Resources) Merge(other *Resources) {
 	if len(other.Networks) != 0 {
 		r.Networks = other.Networks
 	}
+	if len(other.Devices) != 0 {
+		r.Devices = other.Devices
+	}
 }
 
 func (r *Resources) Canonicalize() {
@@ -1698,6 +1948,9 @@ func (r *Resources) Canonicalize() {
 	if len(r.Networks) == 0 {
 		r.Networks = nil
 	}
+	if len(r.Devices) == 0 {
+		r.Devices = nil
+	}
 
 	for _, n := range r.Networks {
 		n.Canonicalize()
@@ -1716,6 +1969,9 @@ func (r *Resources) MeetsMinResources() error {
 	if r.MemoryMB < minResources.MemoryMB {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum MemoryMB value is %d; got %d", minResources.MemoryMB, r.MemoryMB))
 	}
+	if r.MemoryMaxMB != 0 && r.MemoryMaxMB < r.MemoryMB {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("MemoryMaxMB value (%d) must be greater than or equal to MemoryMB value (%d)", r.MemoryMaxMB, r.MemoryMB))
+	}
 	if r.IOPS < minResources.IOPS {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum IOPS value is %d; got %d", minResources.IOPS, r.IOPS))
 	}
@@ -1742,6 +1998,12 @@ func (r *Resources) Copy() *Resources {
 			newR.Networks[i] = r.Networks[i].Copy()
 		}
 	}
+	if r.Devices != nil {
+		newR.Devices = make([]*RequestedDevice, len(r.Devices))
+		for i, d := range r.Devices {
+			newR.Devices[i] = d.Copy()
+		}
+	}
 	return newR
 }
 
@@ -1804,11 +2066,41 @@ func (r *Resources) GoString() string {
 type Port struct {
 	Label string
 	Value int
+
+	// To is the port inside the network namespace of the allocation that
+	// the host port is mapped to. It is only used by group networks in
+	// bridge or CNI mode, and defaults to the host port.
+	To int
+
+	// HostNetwork is the name of the host network of the client the port
+	// is allocated on. Ports are allocated on the default network if it is
+	// empty.
+	HostNetwork string
+
+	// HostIP is the address of the host network the port was allocated
+	// on. It is empty for the ports allocated on the IP of their network.
+	HostIP string
 }
 
+const (
+	// NetworkModeHost is the default network mode, where the tasks share
+	// the network namespace of the host.
+	NetworkModeHost = "host"
+
+	// NetworkModeBridge creates a network namespace for the allocation,
+	// connected to the host through a bridge set up by the CNI plugins.
+	NetworkModeBridge = "bridge"
+
+	// NetworkModeCNIPrefix prefixes the name of a CNI network configuration
+	// provided by the client the allocation's network namespace joins.
+	NetworkModeCNIPrefix = "cni/"
+)
+
 // NetworkResource is used to represent available network
 // resources
 type NetworkResource struct {
+	Mode          string // Mode of the group network
+	HostNetwork   string // Host network of the node address, empty for the default network
 	Device        string // Name of the device
 	CIDR          string // CIDR block of addresses
 	IP            string // Host IP address
@@ -1818,6 +2110,14 @@ type NetworkResource struct {
 }
 
 func (nr *NetworkResource) Equals(other *NetworkResource) bool {
+	if nr.Mode != other.Mode {
+		return false
+	}
+
+	if nr.HostNetwork != other.HostNetwork {
+		return false
+	}
+
 	if nr.Device != other.Device {
 		return false
 	}
@@ -1910,10 +2210,50 @@ func (n *NetworkResource) Add(delta *NetworkResource) {
 	n.DynamicPorts = append(n.DynamicPorts, delta.DynamicPorts...)
 }
 
+// PortIP returns the address a port of the network is allocated on
+func (n *NetworkResource) PortIP(p Port) string {
+	if p.HostIP != "" {
+		return p.HostIP
+	}
+	return n.IP
+}
+
 func (n *NetworkResource) GoString() string {
 	return fmt.Sprintf("*%#v", *n)
 }
 
+// validateNetworkPorts ensures the ports requested by a task are labeled,
+// their labels are unique and reserved ports are valid port numbers.
+func validateNetworkPorts(networks []*NetworkResource) error {
+	var mErr multierror.Error
+	labels := make(map[string]struct{})
+	checkLabel := func(label string) {
+		if label == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("port label must not be empty"))
+			return
+		}
+		if _, ok := labels[label]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("found a port label collision: %s", label))
+		}
+		labels[label] = struct{}{}
+	}
+
+	for _, n := range networks {
+		for _, port := range n.ReservedPorts {
+			checkLabel(port.Label)
+			if port.Value <= 0 || port.Value >= maxValidPort {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("reserved port %q must be between 1 and %d; got %d",
+					port.Label, maxValidPort-1, port.Value))
+			}
+		}
+		for _, port := range n.DynamicPorts {
+			checkLabel(port.Label)
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 // PortLabels returns a map of port labels to their assigned host ports.
 func (n *NetworkResource) PortLabels() map[string]int {
 	num := len(n.ReservedPorts) + len(n.DynamicPorts)
@@ -1934,8 +2274,17 @@ const (
 	JobTypeService = "service"
 	JobTypeBatch   = "batch"
 	JobTypeSystem  = "system"
+
+	// JobTypeSysBatch runs a batch task group once on every eligible node
+	JobTypeSysBatch = "sysbatch"
 )
 
+// IsSystemJobType returns whether jobs of the type are placed on every
+// eligible node by the system scheduler.
+func IsSystemJobType(jobType string) bool {
+	return jobType == JobTypeSystem || jobType == JobTypeSysBatch
+}
+
 const (
 	JobStatusPending = "pending" // Pending means the job is waiting on scheduling
 	JobStatusRunning = "running" // Running means the job has non-terminal allocations
@@ -1961,8 +2310,88 @@ const (
 	// JobTrackedVersions is the number of historic job versions that are
 	// kept.
 	JobTrackedVersions = 6
+
+	// JobSubmissionMaxSize is the largest job source, in bytes, that is
+	// stored with a job version
+	JobSubmissionMaxSize = 1024 * 1024
+)
+
+const (
+	// JobSubmissionFormatHCL1 is the format of the sources parsed by the
+	// HCL1 parser, including the JSON sources that aren't in the canonical
+	// JSON format
+	JobSubmissionFormatHCL1 = "hcl1"
+
+	// JobSubmissionFormatHCL2 is the format of the sources parsed by the
+	// HCL2 parser
+	JobSubmissionFormatHCL2 = "hcl2"
+
+	// JobSubmissionFormatJSON is the format of the sources written in the
+	// canonical JSON format of the API
+	JobSubmissionFormatJSON = "json"
 )
 
+// JobSubmission is the source a job version was parsed from, as submitted by
+// the user. It is kept for as long as the job version is tracked.
+type JobSubmission struct {
+	// Source is the job specification as submitted
+	Source string
+
+	// Format is the format of the source, "hcl1", "hcl2" or "json"
+	Format string
+
+	// VariableFlags are the values of the input variables of an HCL2 source
+	// given with the -var flag
+	VariableFlags map[string]string
+
+	// Variables are the values of the input variables of an HCL2 source
+	// assigned by the environment, the variable files and the -var flag, as
+	// the content of an HCL variable file. Parsing the source with them
+	// yields the submitted job.
+	Variables string
+
+	// Namespace, JobID and Version identify the job version the source
+	// belongs to. They are set by the server on registration.
+	Namespace string
+	JobID     string
+	Version   uint64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy returns a copy of the job submission
+func (s *JobSubmission) Copy() *JobSubmission {
+	if s == nil {
+		return nil
+	}
+	ns := *s
+	ns.VariableFlags = helper.CopyMapStringString(s.VariableFlags)
+	return &ns
+}
+
+// Validate returns an error if the submission can't be stored
+func (s *JobSubmission) Validate() error {
+	switch s.Format {
+	case JobSubmissionFormatHCL2:
+	case JobSubmissionFormatHCL1, JobSubmissionFormatJSON:
+		if len(s.VariableFlags) != 0 || s.Variables != "" {
+			return fmt.Errorf("job source in the %q format can't have variables", s.Format)
+		}
+	default:
+		return fmt.Errorf("unknown job source format %q", s.Format)
+	}
+
+	size := len(s.Source) + len(s.Variables)
+	for k, v := range s.VariableFlags {
+		size += len(k) + len(v)
+	}
+	if size > JobSubmissionMaxSize {
+		return fmt.Errorf("job source is larger than the %d bytes limit", JobSubmissionMaxSize)
+	}
+	return nil
+}
+
 // Job is the scope of a scheduling request to Nomad. It is the largest
 // scoped object, and is a named collection of task groups. Each task group
 // is further composed of tasks. A task group (TG) is the unit of scheduling
@@ -2009,6 +2438,10 @@ type Job struct {
 	// Datacenters contains all the datacenters this job is allowed to span
 	Datacenters []string
 
+	// NodePool is the node pool the job is placed in. The "all" pool allows
+	// the job to be placed on nodes of any pool.
+	NodePool string
+
 	// Constraints can be specified at a job level and apply to
 	// all the task groups and tasks.
 	Constraints []*Constraint
@@ -2028,6 +2461,9 @@ type Job struct {
 	// COMPAT: Remove in 0.7.0. Stagger is deprecated in 0.6.0.
 	Update UpdateStrategy
 
+	// Multiregion is used to deploy the job to multiple federated regions.
+	Multiregion *Multiregion
+
 	// Periodic is used to define the interval the job is run at.
 	Periodic *PeriodicConfig
 
@@ -2104,6 +2540,11 @@ func (j *Job) Canonicalize() (warnings error) {
 		j.Namespace = DefaultNamespace
 	}
 
+	// Ensure the job is in a node pool.
+	if j.NodePool == "" {
+		j.NodePool = NodePoolDefault
+	}
+
 	for _, tg := range j.TaskGroups {
 		tg.Canonicalize(j)
 	}
@@ -2116,6 +2557,10 @@ func (j *Job) Canonicalize() (warnings error) {
 		j.Periodic.Canonicalize()
 	}
 
+	if j.Multiregion != nil {
+		j.Multiregion.Canonicalize()
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -2142,6 +2587,7 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = nj.Periodic.Copy()
 	nj.Meta = helper.CopyMapStringString(nj.Meta)
 	nj.ParameterizedJob = nj.ParameterizedJob.Copy()
+	nj.Multiregion = nj.Multiregion.Copy()
 	return nj
 }
 
@@ -2164,7 +2610,7 @@ func (j *Job) Validate() error {
 		mErr.Errors = append(mErr.Errors, errors.New("Job must be in a namespace"))
 	}
 	switch j.Type {
-	case JobTypeCore, JobTypeService, JobTypeBatch, JobTypeSystem:
+	case JobTypeCore, JobTypeService, JobTypeBatch, JobTypeSystem, JobTypeSysBatch:
 	case "":
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job type"))
 	default:
@@ -2176,6 +2622,11 @@ func (j *Job) Validate() error {
 	if len(j.Datacenters) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job datacenters"))
 	}
+	if j.NodePool != "" {
+		if err := ValidateNodePool(j.NodePool); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
 	if len(j.TaskGroups) == 0 {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing job task groups"))
 	}
@@ -2185,7 +2636,7 @@ func (j *Job) Validate() error {
 			mErr.Errors = append(mErr.Errors, outer)
 		}
 	}
-	if j.Type == JobTypeSystem {
+	if IsSystemJobType(j.Type) {
 		if j.Affinities != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have an affinity stanza"))
 		}
@@ -2198,7 +2649,7 @@ func (j *Job) Validate() error {
 		}
 	}
 
-	if j.Type == JobTypeSystem {
+	if IsSystemJobType(j.Type) {
 		if j.Spreads != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a spread stanza"))
 		}
@@ -2222,10 +2673,10 @@ func (j *Job) Validate() error {
 			taskGroups[tg.Name] = idx
 		}
 
-		if j.Type == "system" && tg.Count > 1 {
+		if IsSystemJobType(j.Type) && tg.Count > 1 {
 			mErr.Errors = append(mErr.Errors,
-				fmt.Errorf("Job task group %s has count %d. Count cannot exceed 1 with system scheduler",
-					tg.Name, tg.Count))
+				fmt.Errorf("Job task group %s has count %d. Count cannot exceed 1 with %s scheduler",
+					tg.Name, tg.Count, j.Type))
 		}
 	}
 
@@ -2239,9 +2690,9 @@ func (j *Job) Validate() error {
 
 	// Validate periodic is only used with batch jobs.
 	if j.IsPeriodic() && j.Periodic.Enabled {
-		if j.Type != JobTypeBatch {
+		if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
 			mErr.Errors = append(mErr.Errors,
-				fmt.Errorf("Periodic can only be used with %q scheduler", JobTypeBatch))
+				fmt.Errorf("Periodic can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch))
 		}
 
 		if err := j.Periodic.Validate(); err != nil {
@@ -2250,9 +2701,9 @@ func (j *Job) Validate() error {
 	}
 
 	if j.IsParameterized() {
-		if j.Type != JobTypeBatch {
+		if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
 			mErr.Errors = append(mErr.Errors,
-				fmt.Errorf("Parameterized job can only be used with %q scheduler", JobTypeBatch))
+				fmt.Errorf("Parameterized job can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch))
 		}
 
 		if err := j.ParameterizedJob.Validate(); err != nil {
@@ -2260,6 +2711,13 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.Multiregion != nil {
+		if err := j.Multiregion.Validate(); err != nil {
+			outer := fmt.Errorf("Multiregion validation failed: %v", err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -2345,6 +2803,7 @@ func (j *Job) HasUpdateStrategy() bool {
 func (j *Job) Stub(summary *JobSummary) *JobListStub {
 	return &JobListStub{
 		ID:                j.ID,
+		Namespace:         j.Namespace,
 		ParentID:          j.ParentID,
 		Name:              j.Name,
 		Type:              j.Type,
@@ -2378,6 +2837,55 @@ func (j *Job) IsParameterized() bool {
 	return j.ParameterizedJob != nil && !j.Dispatched
 }
 
+// IsMultiregion returns whether a job is deployed to multiple regions.
+func (j *Job) IsMultiregion() bool {
+	return j.Multiregion != nil && len(j.Multiregion.Regions) != 0
+}
+
+// MultiregionPending returns whether the deployments of a multiregion job in
+// the job's region wait for the deployments of the regions before it in the
+// rollout to complete before they start.
+func (j *Job) MultiregionPending() bool {
+	if !j.IsMultiregion() || j.Multiregion.Strategy == nil {
+		return false
+	}
+
+	maxParallel := j.Multiregion.Strategy.MaxParallel
+	return maxParallel != 0 && j.Multiregion.RegionIndex(j.Region) >= maxParallel
+}
+
+// RegionalJob returns the copy of a multiregion job that is registered in the
+// region. The region's datacenters replace the datacenters of the job, its
+// meta is merged into the meta of the job and its count replaces the count of
+// the task groups with a count of zero.
+func (j *Job) RegionalJob(region *MultiregionRegion) *Job {
+	nj := j.Copy()
+	nj.Region = region.Name
+
+	if len(region.Datacenters) != 0 {
+		nj.Datacenters = helper.CopySliceString(region.Datacenters)
+	}
+
+	if len(region.Meta) != 0 {
+		if nj.Meta == nil {
+			nj.Meta = make(map[string]string, len(region.Meta))
+		}
+		for k, v := range region.Meta {
+			nj.Meta[k] = v
+		}
+	}
+
+	if region.Count != 0 {
+		for _, tg := range nj.TaskGroups {
+			if tg.Count == 0 {
+				tg.Count = region.Count
+			}
+		}
+	}
+
+	return nj
+}
+
 // VaultPolicies returns the set of Vault policies per task group, per task
 func (j *Job) VaultPolicies() map[string]map[string]*Vault {
 	policies := make(map[string]map[string]*Vault, len(j.TaskGroups))
@@ -2487,6 +2995,7 @@ func (j *Job) SetSubmitTime() {
 // for the job list
 type JobListStub struct {
 	ID                string
+	Namespace         string
 	ParentID          string
 	Name              string
 	Type              string
@@ -2590,6 +3099,7 @@ var (
 		HealthyDeadline:  5 * time.Minute,
 		ProgressDeadline: 10 * time.Minute,
 		AutoRevert:       false,
+		AutoPromote:      false,
 		Canary:           0,
 	}
 )
@@ -2628,6 +3138,10 @@ type UpdateStrategy struct {
 	// stable version.
 	AutoRevert bool
 
+	// AutoPromote declares that the deployment should be promoted when all
+	// canaries are healthy.
+	AutoPromote bool
+
 	// Canary is the number of canaries to deploy when a change to the task
 	// group is detected.
 	Canary int
@@ -2944,75 +3458,280 @@ func (d *DispatchPayloadConfig) Validate() error {
 	return nil
 }
 
-var (
-	DefaultServiceJobRestartPolicy = RestartPolicy{
-		Delay:    15 * time.Second,
-		Attempts: 2,
-		Interval: 30 * time.Minute,
-		Mode:     RestartPolicyModeFail,
-	}
-	DefaultBatchJobRestartPolicy = RestartPolicy{
-		Delay:    15 * time.Second,
-		Attempts: 3,
-		Interval: 24 * time.Hour,
-		Mode:     RestartPolicyModeFail,
-	}
-)
+const (
+	// MultiregionOnFailureFailAll fails the deployments in all the regions
+	// when the deployment in one of the regions fails.
+	MultiregionOnFailureFailAll = "fail_all"
 
-var (
-	DefaultServiceJobReschedulePolicy = ReschedulePolicy{
-		Delay:         30 * time.Second,
-		DelayFunction: "exponential",
-		MaxDelay:      1 * time.Hour,
-		Unlimited:     true,
-	}
-	DefaultBatchJobReschedulePolicy = ReschedulePolicy{
-		Attempts:      1,
-		Interval:      24 * time.Hour,
-		Delay:         5 * time.Second,
-		DelayFunction: "constant",
-	}
+	// MultiregionOnFailureFailLocal only fails the deployment in the region
+	// that failed and continues the rollout in the remaining regions.
+	MultiregionOnFailureFailLocal = "fail_local"
 )
 
-const (
-	// RestartPolicyModeDelay causes an artificial delay till the next interval is
-	// reached when the specified attempts have been reached in the interval.
-	RestartPolicyModeDelay = "delay"
+// Multiregion is used to deploy a job to multiple federated regions. The
+// region the job is submitted to registers the job in each of the regions.
+type Multiregion struct {
+	// Strategy configures the order the regions are deployed in
+	Strategy *MultiregionStrategy
 
-	// RestartPolicyModeFail causes a job to fail if the specified number of
-	// attempts are reached within an interval.
-	RestartPolicyModeFail = "fail"
+	// Regions are the regions the job is deployed to, in rollout order
+	Regions []*MultiregionRegion
+}
 
-	// RestartPolicyMinInterval is the minimum interval that is accepted for a
-	// restart policy.
-	RestartPolicyMinInterval = 5 * time.Second
+// MultiregionStrategy configures the rollout of a multiregion job.
+type MultiregionStrategy struct {
+	// MaxParallel is the number of regions that are deployed at the same
+	// time. If zero, all the regions are deployed at once.
+	MaxParallel int
 
-	// ReasonWithinPolicy describes restart events that are within policy
-	ReasonWithinPolicy = "Restart within policy"
-)
+	// OnFailure is the behavior of the rollout when the deployment in a
+	// region fails. By default the deployments in the failed region and in
+	// the regions after it are failed.
+	OnFailure string
+}
 
-// RestartPolicy configures how Tasks are restarted when they crash or fail.
-type RestartPolicy struct {
-	// Attempts is the number of restart that will occur in an interval.
-	Attempts int
+// MultiregionRegion is a region a multiregion job is deployed to.
+type MultiregionRegion struct {
+	// Name is the name of the region
+	Name string
 
-	// Interval is a duration in which we can limit the number of restarts
-	// within.
-	Interval time.Duration
+	// Count replaces the count of the task groups with a count of zero
+	Count int
 
-	// Delay is the time between a failure and a restart.
-	Delay time.Duration
+	// Datacenters replaces the datacenters of the job in the region
+	Datacenters []string
 
-	// Mode controls what happens when the task restarts more than attempt times
-	// in an interval.
-	Mode string
+	// Meta is merged into the meta of the job in the region
+	Meta map[string]string
 }
 
-func (r *RestartPolicy) Copy() *RestartPolicy {
-	if r == nil {
-		return nil
+func (m *Multiregion) Canonicalize() {
+	if m.Strategy == nil {
+		m.Strategy = &MultiregionStrategy{}
 	}
-	nrp := new(RestartPolicy)
+	for _, r := range m.Regions {
+		if len(r.Meta) == 0 {
+			r.Meta = nil
+		}
+	}
+}
+
+func (m *Multiregion) Validate() error {
+	var mErr multierror.Error
+	if len(m.Regions) == 0 {
+		multierror.Append(&mErr, errors.New("Missing regions"))
+	}
+
+	if s := m.Strategy; s != nil {
+		if s.MaxParallel < 0 {
+			multierror.Append(&mErr, fmt.Errorf("Max parallel must be >= 0: %d", s.MaxParallel))
+		}
+		switch s.OnFailure {
+		case "", MultiregionOnFailureFailAll, MultiregionOnFailureFailLocal:
+		default:
+			multierror.Append(&mErr, fmt.Errorf("Unknown on_failure value %q", s.OnFailure))
+		}
+	}
+
+	seen := make(map[string]struct{}, len(m.Regions))
+	for idx, r := range m.Regions {
+		if r.Name == "" {
+			multierror.Append(&mErr, fmt.Errorf("Region %d missing name", idx+1))
+			continue
+		}
+		if _, ok := seen[r.Name]; ok {
+			multierror.Append(&mErr, fmt.Errorf("Region %q defined more than once", r.Name))
+		}
+		seen[r.Name] = struct{}{}
+
+		if r.Count < 0 {
+			multierror.Append(&mErr, fmt.Errorf("Region %q count must be >= 0: %d", r.Name, r.Count))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+func (m *Multiregion) Copy() *Multiregion {
+	if m == nil {
+		return nil
+	}
+	nm := new(Multiregion)
+	if m.Strategy != nil {
+		ns := *m.Strategy
+		nm.Strategy = &ns
+	}
+	if m.Regions != nil {
+		nm.Regions = make([]*MultiregionRegion, len(m.Regions))
+		for i, r := range m.Regions {
+			nr := *r
+			nr.Datacenters = helper.CopySliceString(r.Datacenters)
+			nr.Meta = helper.CopyMapStringString(r.Meta)
+			nm.Regions[i] = &nr
+		}
+	}
+	return nm
+}
+
+// RegionIndex returns the position of the region in the rollout or -1 if
+// the job isn't deployed to the region.
+func (m *Multiregion) RegionIndex(region string) int {
+	for i, r := range m.Regions {
+		if r.Name == region {
+			return i
+		}
+	}
+	return -1
+}
+
+const (
+	// TaskLifecycleHookPrestart runs the task before the main tasks of the
+	// group are started.
+	TaskLifecycleHookPrestart = "prestart"
+
+	// TaskLifecycleHookPoststart runs the task once the main tasks of the
+	// group are running.
+	TaskLifecycleHookPoststart = "poststart"
+
+	// TaskLifecycleHookPoststop runs the task once the main tasks of the
+	// group are dead.
+	TaskLifecycleHookPoststop = "poststop"
+)
+
+// TaskLifecycleConfig configures when a task is run relative to the main
+// tasks of its group.
+type TaskLifecycleConfig struct {
+	// Hook is the point in the group's lifecycle the task is started at.
+	Hook string
+
+	// Sidecar marks the task as running alongside the main tasks rather than
+	// having to complete before they are started.
+	Sidecar bool
+}
+
+func (l *TaskLifecycleConfig) Copy() *TaskLifecycleConfig {
+	if l == nil {
+		return nil
+	}
+	nl := new(TaskLifecycleConfig)
+	*nl = *l
+	return nl
+}
+
+func (l *TaskLifecycleConfig) Validate() error {
+	if l == nil {
+		return nil
+	}
+
+	switch l.Hook {
+	case TaskLifecycleHookPrestart, TaskLifecycleHookPoststart:
+	case TaskLifecycleHookPoststop:
+		if l.Sidecar {
+			return fmt.Errorf("%q tasks can't be sidecars", TaskLifecycleHookPoststop)
+		}
+	case "":
+		return fmt.Errorf("no lifecycle hook provided")
+	default:
+		return fmt.Errorf("invalid hook %q, must be %q, %q or %q", l.Hook,
+			TaskLifecycleHookPrestart, TaskLifecycleHookPoststart, TaskLifecycleHookPoststop)
+	}
+
+	return nil
+}
+
+// IsInit returns whether the task must run to completion before the main
+// tasks of the group are started.
+func (t *Task) IsInit() bool {
+	return t.Lifecycle != nil && t.Lifecycle.Hook == TaskLifecycleHookPrestart && !t.Lifecycle.Sidecar
+}
+
+// IsPoststart returns whether the task is started once the main tasks of the
+// group are running.
+func (t *Task) IsPoststart() bool {
+	return t.Lifecycle != nil && t.Lifecycle.Hook == TaskLifecycleHookPoststart
+}
+
+// IsPoststop returns whether the task is run once the main tasks of the group
+// are dead.
+func (t *Task) IsPoststop() bool {
+	return t.Lifecycle != nil && t.Lifecycle.Hook == TaskLifecycleHookPoststop
+}
+
+// IsSidecar returns whether the task runs alongside the main tasks of the group
+// until they are dead.
+func (t *Task) IsSidecar() bool {
+	return t.Lifecycle != nil && t.Lifecycle.Sidecar
+}
+
+var (
+	DefaultServiceJobRestartPolicy = RestartPolicy{
+		Delay:    15 * time.Second,
+		Attempts: 2,
+		Interval: 30 * time.Minute,
+		Mode:     RestartPolicyModeFail,
+	}
+	DefaultBatchJobRestartPolicy = RestartPolicy{
+		Delay:    15 * time.Second,
+		Attempts: 3,
+		Interval: 24 * time.Hour,
+		Mode:     RestartPolicyModeFail,
+	}
+)
+
+var (
+	DefaultServiceJobReschedulePolicy = ReschedulePolicy{
+		Delay:         30 * time.Second,
+		DelayFunction: "exponential",
+		MaxDelay:      1 * time.Hour,
+		Unlimited:     true,
+	}
+	DefaultBatchJobReschedulePolicy = ReschedulePolicy{
+		Attempts:      1,
+		Interval:      24 * time.Hour,
+		Delay:         5 * time.Second,
+		DelayFunction: "constant",
+	}
+)
+
+const (
+	// RestartPolicyModeDelay causes an artificial delay till the next interval is
+	// reached when the specified attempts have been reached in the interval.
+	RestartPolicyModeDelay = "delay"
+
+	// RestartPolicyModeFail causes a job to fail if the specified number of
+	// attempts are reached within an interval.
+	RestartPolicyModeFail = "fail"
+
+	// RestartPolicyMinInterval is the minimum interval that is accepted for a
+	// restart policy.
+	RestartPolicyMinInterval = 5 * time.Second
+
+	// ReasonWithinPolicy describes restart events that are within policy
+	ReasonWithinPolicy = "Restart within policy"
+)
+
+// RestartPolicy configures how Tasks are restarted when they crash or fail.
+type RestartPolicy struct {
+	// Attempts is the number of restart that will occur in an interval.
+	Attempts int
+
+	// Interval is a duration in which we can limit the number of restarts
+	// within.
+	Interval time.Duration
+
+	// Delay is the time between a failure and a restart.
+	Delay time.Duration
+
+	// Mode controls what happens when the task restarts more than attempt times
+	// in an interval.
+	Mode string
+}
+
+func (r *RestartPolicy) Copy() *RestartPolicy {
+	if r == nil {
+		return nil
+	}
+	nrp := new(RestartPolicy)
 	*nrp = *r
 	return nrp
 }
@@ -3045,7 +3764,7 @@ func NewRestartPolicy(jobType string) *RestartPolicy {
 	case JobTypeService, JobTypeSystem:
 		rp := DefaultServiceJobRestartPolicy
 		return &rp
-	case JobTypeBatch:
+	case JobTypeBatch, JobTypeSysBatch:
 		rp := DefaultBatchJobRestartPolicy
 		return &rp
 	}
@@ -3132,7 +3851,7 @@ func (r *ReschedulePolicy) Validate() error {
 	// Validate MaxDelay if not using linear delay progression
 	if r.DelayFunction != "constant" {
 		if r.MaxDelay.Nanoseconds() < ReschedulePolicyMinDelay.Nanoseconds() {
-			multierror.Append(&mErr, fmt.Errorf("Max Delay cannot be less than %v (got %v)", ReschedulePolicyMinDelay, r.Delay))
+			multierror.Append(&mErr, fmt.Errorf("Max Delay cannot be less than %v (got %v)", ReschedulePolicyMinDelay, r.MaxDelay))
 			delayPreCheck = false
 		}
 		if r.MaxDelay < r.Delay {
@@ -3366,6 +4085,18 @@ type TaskGroup struct {
 	// Spread can be specified at the task group level to express spreading
 	// allocations across a desired attribute, such as datacenter
 	Spreads []*Spread
+
+	// Volumes is a map of volumes that have been requested by the task group.
+	Volumes map[string]*VolumeRequest
+
+	// Networks are the networks shared by the tasks of the task group. In
+	// bridge or CNI mode, the tasks join a network namespace created for
+	// the allocation.
+	Networks Networks
+
+	// Services are the services of the task group, registered on behalf of
+	// all its tasks. Their ports are the ports of the group network.
+	Services []*Service
 }
 
 func (tg *TaskGroup) Copy() *TaskGroup {
@@ -3380,6 +4111,16 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg.ReschedulePolicy = ntg.ReschedulePolicy.Copy()
 	ntg.Affinities = CopySliceAffinities(ntg.Affinities)
 	ntg.Spreads = CopySliceSpreads(ntg.Spreads)
+	ntg.Volumes = CopyMapVolumeRequest(ntg.Volumes)
+	ntg.Networks = ntg.Networks.Copy()
+
+	if tg.Services != nil {
+		services := make([]*Service, len(ntg.Services))
+		for i, s := range ntg.Services {
+			services[i] = s.Copy()
+		}
+		ntg.Services = services
+	}
 
 	if tg.Tasks != nil {
 		tasks := make([]*Task, len(ntg.Tasks))
@@ -3424,6 +4165,23 @@ func (tg *TaskGroup) Canonicalize(job *Job) {
 		tg.EphemeralDisk = DefaultEphemeralDisk()
 	}
 
+	if len(tg.Networks) == 0 {
+		tg.Networks = nil
+	}
+	for _, n := range tg.Networks {
+		if n.Mode == "" {
+			n.Mode = NetworkModeHost
+		}
+		n.Canonicalize()
+	}
+
+	if len(tg.Services) == 0 {
+		tg.Services = nil
+	}
+	for _, service := range tg.Services {
+		service.Canonicalize(job.Name, tg.Name, "group")
+	}
+
 	for _, task := range tg.Tasks {
 		task.Canonicalize(job, tg)
 	}
@@ -3460,7 +4218,7 @@ func (tg *TaskGroup) Validate(j *Job) error {
 			mErr.Errors = append(mErr.Errors, outer)
 		}
 	}
-	if j.Type == JobTypeSystem {
+	if IsSystemJobType(j.Type) {
 		if tg.Affinities != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have an affinity stanza"))
 		}
@@ -3481,7 +4239,7 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Task Group %v should have a restart policy", tg.Name))
 	}
 
-	if j.Type == JobTypeSystem {
+	if IsSystemJobType(j.Type) {
 		if tg.Spreads != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have a spread stanza"))
 		}
@@ -3494,7 +4252,7 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
-	if j.Type == JobTypeSystem {
+	if IsSystemJobType(j.Type) {
 		if tg.ReschedulePolicy != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs should not have a reschedule policy"))
 		}
@@ -3542,11 +4300,40 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		}
 	}
 
+	// Validate the volume requests
+	for name, v := range tg.Volumes {
+		if err := v.Validate(); err != nil {
+			outer := fmt.Errorf("Volume %q validation failed: %v", name, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+	}
+
+	// Validate the group network
+	if err := tg.validateNetworks(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	// Validate the group services
+	if err := tg.validateServices(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
 	// Check for duplicate tasks, that there is only leader task if any,
 	// and no duplicated static ports
 	tasks := make(map[string]int)
 	staticPorts := make(map[int]string)
+	for _, net := range tg.Networks {
+		for _, port := range net.ReservedPorts {
+			if other, ok := staticPorts[port.Value]; ok {
+				err := fmt.Errorf("Static port %d already reserved by %s", port.Value, other)
+				mErr.Errors = append(mErr.Errors, err)
+			} else {
+				staticPorts[port.Value] = fmt.Sprintf("taskgroup network:%s", port.Label)
+			}
+		}
+	}
 	leaderTasks := 0
+	mainTasks := 0
 	for idx, task := range tg.Tasks {
 		if task.Name == "" {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Task %d missing name", idx+1))
@@ -3560,10 +4347,28 @@ func (tg *TaskGroup) Validate(j *Job) error {
 			leaderTasks++
 		}
 
+		for _, vm := range task.VolumeMounts {
+			if _, ok := tg.Volumes[vm.Volume]; !ok {
+				err := fmt.Errorf("Task %s mounts volume %q which is not requested by the task group", task.Name, vm.Volume)
+				mErr.Errors = append(mErr.Errors, err)
+			}
+		}
+
+		if task.Lifecycle == nil {
+			mainTasks++
+		} else if task.Leader {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Task %s has a lifecycle and can't be marked as leader", task.Name))
+		}
+
 		if task.Resources == nil {
 			continue
 		}
 
+		if len(tg.Networks) != 0 && tg.Networks[0].Mode != NetworkModeHost && len(task.Resources.Networks) != 0 {
+			err := fmt.Errorf("Task %s can't request a network when the task group network is in %q mode", task.Name, tg.Networks[0].Mode)
+			mErr.Errors = append(mErr.Errors, err)
+		}
+
 		for _, net := range task.Resources.Networks {
 			for _, port := range net.ReservedPorts {
 				if other, ok := staticPorts[port.Value]; ok {
@@ -3580,9 +4385,13 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("Only one task may be marked as leader"))
 	}
 
+	if mainTasks == 0 && len(tg.Tasks) > 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Must have at least one task without a lifecycle"))
+	}
+
 	// Validate the tasks
 	for _, task := range tg.Tasks {
-		if err := task.Validate(tg.EphemeralDisk, j.Type); err != nil {
+		if err := task.Validate(tg.EphemeralDisk, j.Type, tg.Networks); err != nil {
 			outer := fmt.Errorf("Task %s validation failed: %v", task.Name, err)
 			mErr.Errors = append(mErr.Errors, outer)
 		}
@@ -3590,6 +4399,132 @@ func (tg *TaskGroup) Validate(j *Job) error {
 	return mErr.ErrorOrNil()
 }
 
+// validateNetworks validates the network shared by the tasks of the group.
+func (tg *TaskGroup) validateNetworks() error {
+	if len(tg.Networks) == 0 {
+		return nil
+	}
+
+	var mErr multierror.Error
+	if len(tg.Networks) > 1 {
+		mErr.Errors = append(mErr.Errors, errors.New("Only one network may be defined by a task group"))
+	}
+
+	for _, net := range tg.Networks {
+		switch {
+		case net.Mode == NetworkModeHost, net.Mode == NetworkModeBridge:
+		case strings.HasPrefix(net.Mode, NetworkModeCNIPrefix) && len(net.Mode) > len(NetworkModeCNIPrefix):
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Invalid network mode %q", net.Mode))
+		}
+
+		if err := validateNetworkPorts([]*NetworkResource{net}); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+
+		for _, ports := range [][]Port{net.ReservedPorts, net.DynamicPorts} {
+			for _, port := range ports {
+				if port.To == 0 {
+					continue
+				}
+				if net.Mode == NetworkModeHost {
+					mErr.Errors = append(mErr.Errors, fmt.Errorf("Port %q can't be mapped in host network mode", port.Label))
+				} else if port.To < 0 || port.To >= maxValidPort {
+					mErr.Errors = append(mErr.Errors, fmt.Errorf("Port %q must be mapped to a port between 1 and %d; got %d",
+						port.Label, maxValidPort-1, port.To))
+				}
+			}
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// validateServices validates the services of the group, which can only use
+// the ports of the group network. Connect services additionally require the
+// group network to be in bridge mode, as their sidecar proxy reaches them in
+// the network namespace of the allocation.
+func (tg *TaskGroup) validateServices() error {
+	if len(tg.Services) == 0 {
+		return nil
+	}
+
+	var mErr multierror.Error
+	portLabels := make(map[string]struct{})
+	for _, network := range tg.Networks {
+		for portLabel := range network.PortLabels() {
+			portLabels[portLabel] = struct{}{}
+		}
+	}
+
+	knownServices := make(map[string]struct{})
+	connectServices := make(map[string]struct{})
+	for i, service := range tg.Services {
+		if err := service.Validate(); err != nil {
+			outer := fmt.Errorf("Service[%d] %q validation failed: %s", i, service.Name, err)
+			mErr.Errors = append(mErr.Errors, outer)
+		}
+
+		if _, ok := knownServices[service.Name+service.PortLabel]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %q is duplicate", service.Name))
+		}
+		knownServices[service.Name+service.PortLabel] = struct{}{}
+
+		if service.PortLabel != "" {
+			if _, ok := portLabels[service.PortLabel]; !ok {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %q uses port %q which is not defined by the group network", service.Name, service.PortLabel))
+			}
+		}
+
+		for _, check := range service.Checks {
+			if check.Type == ServiceCheckScript {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %q check %q: script checks are not supported by group services", service.Name, check.Name))
+			}
+			if check.CheckRestart != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %q check %q: check_restart is not supported by group services", service.Name, check.Name))
+			}
+			if check.AddressMode == AddressModeDriver {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %q check %q: address_mode %q is not supported by group services", service.Name, check.Name, AddressModeDriver))
+			}
+			if check.PortLabel != "" {
+				if _, ok := portLabels[check.PortLabel]; !ok {
+					mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %q check %q uses port %q which is not defined by the group network", service.Name, check.Name, check.PortLabel))
+				}
+			}
+		}
+
+		if service.AddressMode == AddressModeDriver {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Service %q: address_mode %q is not supported by group services", service.Name, AddressModeDriver))
+		}
+
+		if !service.Connect.HasSidecar() {
+			continue
+		}
+
+		if _, ok := connectServices[service.Name]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Consul Connect service %q is duplicate", service.Name))
+		}
+		connectServices[service.Name] = struct{}{}
+
+		if len(tg.Networks) == 0 || tg.Networks[0].Mode != NetworkModeBridge {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Consul Connect service %q requires the group network to be in %q mode", service.Name, NetworkModeBridge))
+		}
+		if service.PortLabel == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Consul Connect service %q must have a port", service.Name))
+		}
+		if port := service.Connect.SidecarService.Port; port != "" {
+			if _, ok := portLabels[port]; !ok {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Consul Connect service %q sidecar uses port %q which is not defined by the group network", service.Name, port))
+			}
+		}
+
+		if tg.LookupTaskKind(NewTaskKind(ConnectProxyPrefix, service.Name)) == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Consul Connect service %q is missing its sidecar task", service.Name))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 // Warnings returns a list of warnings that may be from dubious settings or
 // deprecation warnings.
 func (tg *TaskGroup) Warnings(j *Job) error {
@@ -3608,6 +4543,16 @@ func (tg *TaskGroup) Warnings(j *Job) error {
 	return mErr.ErrorOrNil()
 }
 
+// LookupTaskKind finds a task by kind
+func (tg *TaskGroup) LookupTaskKind(kind TaskKind) *Task {
+	for _, t := range tg.Tasks {
+		if t.Kind == kind {
+			return t
+		}
+	}
+	return nil
+}
+
 // LookupTask finds a task by name
 func (tg *TaskGroup) LookupTask(name string) *Task {
 	for _, t := range tg.Tasks {
@@ -3662,7 +4607,7 @@ func (c *CheckRestart) Validate() error {
 	}
 
 	if c.Grace < 0 {
-		mErr.Errors = append(mErr.Errors, fmt.Errorf("grace period must be greater than or equal to 0 but found %d", c.Grace))
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("grace period must be greater than or equal to 0 but found %v", c.Grace))
 	}
 
 	return mErr.ErrorOrNil()
@@ -3738,6 +4683,10 @@ func (sc *ServiceCheck) Canonicalize(serviceName string) {
 	if sc.Name == "" {
 		sc.Name = fmt.Sprintf("service: %q check", serviceName)
 	}
+
+	// Check types are matched case sensitively when registering the check
+	// with Consul, so normalize them.
+	sc.Type = strings.ToLower(sc.Type)
 }
 
 // validate a Service's ServiceCheck
@@ -3764,7 +4713,7 @@ func (sc *ServiceCheck) validate() error {
 		}
 
 	default:
-		return fmt.Errorf(`invalid type (%+q), must be one of "http", "tcp", or "script" type`, sc.Type)
+		return fmt.Errorf(`invalid type (%+q), must be one of "http", "tcp", "grpc", or "script" type`, sc.Type)
 	}
 
 	// Validate interval and timeout
@@ -3775,9 +4724,9 @@ func (sc *ServiceCheck) validate() error {
 	}
 
 	if sc.Timeout == 0 {
-		return fmt.Errorf("missing required value timeout. Timeout cannot be less than %v", minCheckInterval)
+		return fmt.Errorf("missing required value timeout. Timeout cannot be less than %v", minCheckTimeout)
 	} else if sc.Timeout < minCheckTimeout {
-		return fmt.Errorf("timeout (%v) is lower than required minimum timeout %v", sc.Timeout, minCheckInterval)
+		return fmt.Errorf("timeout (%v) is lower than required minimum timeout %v", sc.Timeout, minCheckTimeout)
 	}
 
 	// Validate InitialStatus
@@ -3875,8 +4824,16 @@ const (
 	AddressModeDriver = "driver"
 )
 
-// Service represents a Consul service definition in Nomad
-type Service struct {
+const (
+	// ServiceProviderConsul registers the service in Consul
+	ServiceProviderConsul = "consul"
+
+	// ServiceProviderNomad registers the service in Nomad's state store
+	ServiceProviderNomad = "nomad"
+)
+
+// Service represents a Consul service definition in Nomad
+type Service struct {
 	// Name of the service registered with Consul. Consul defaults the
 	// Name to ServiceID if not specified.  The Name if specified is used
 	// as one of the seed values when generating a Consul ServiceID.
@@ -3894,6 +4851,14 @@ type Service struct {
 	Tags       []string        // List of tags for the service
 	CanaryTags []string        // List of tags for the service when it is a canary
 	Checks     []*ServiceCheck // List of checks associated with the service
+
+	// Provider is where the service is registered, either Consul or Nomad
+	// itself
+	Provider string
+
+	// Connect registers the service in the Consul Connect service mesh.
+	// Only the services of a task group can use it.
+	Connect *ConsulConnect
 }
 
 func (s *Service) Copy() *Service {
@@ -3904,6 +4869,7 @@ func (s *Service) Copy() *Service {
 	*ns = *s
 	ns.Tags = helper.CopySliceString(ns.Tags)
 	ns.CanaryTags = helper.CopySliceString(ns.CanaryTags)
+	ns.Connect = s.Connect.Copy()
 
 	if s.Checks != nil {
 		checks := make([]*ServiceCheck, len(ns.Checks))
@@ -3930,6 +4896,9 @@ func (s *Service) Canonicalize(job string, taskGroup string, task string) {
 	if len(s.Checks) == 0 {
 		s.Checks = nil
 	}
+	if s.Provider == "" {
+		s.Provider = ServiceProviderConsul
+	}
 
 	s.Name = args.ReplaceEnv(s.Name, map[string]string{
 		"JOB":       job,
@@ -3964,6 +4933,39 @@ func (s *Service) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("service address_mode must be %q, %q, or %q; not %q", AddressModeAuto, AddressModeHost, AddressModeDriver, s.AddressMode))
 	}
 
+	switch s.Provider {
+	case "", ServiceProviderConsul:
+		// OK
+	case ServiceProviderNomad:
+		// Nomad runs the checks of its services itself, so only the simple
+		// check types are supported
+		for _, c := range s.Checks {
+			if c.Type != ServiceCheckHTTP && c.Type != ServiceCheckTCP {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("check %s invalid: services with provider %q only support %q and %q checks", c.Name, ServiceProviderNomad, ServiceCheckHTTP, ServiceCheckTCP))
+			}
+			if c.CheckRestart != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("check %s invalid: check_restart is not supported by services with provider %q", c.Name, ServiceProviderNomad))
+			}
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("service provider must be %q or %q; not %q", ServiceProviderConsul, ServiceProviderNomad, s.Provider))
+	}
+
+	if s.Connect != nil {
+		if err := s.Connect.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+		if s.Provider == ServiceProviderNomad {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Consul Connect is not supported by services with provider %q", ServiceProviderNomad))
+		}
+
+		// The ID of the service must not change when a canary is promoted,
+		// as the sidecar proxy is bootstrapped for it
+		if len(s.CanaryTags) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("canary_tags are not supported by Consul Connect services"))
+		}
+	}
+
 	for _, c := range s.Checks {
 		if s.PortLabel == "" && c.PortLabel == "" && c.RequiresPort() {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("check %s invalid: check requires a port but neither check nor service %+q have a port", c.Name, s.Name))
@@ -4007,6 +5009,9 @@ func (s *Service) Hash(allocID, taskName string, canary bool) string {
 	for _, tag := range s.CanaryTags {
 		io.WriteString(h, tag)
 	}
+	if s.Connect.HasSidecar() {
+		hashConnectSidecar(h, s.Connect.SidecarService)
+	}
 
 	// Vary ID on whether or not CanaryTags will be used
 	if canary {
@@ -4020,6 +5025,28 @@ func (s *Service) Hash(allocID, taskName string, canary bool) string {
 	return b32.EncodeToString(h.Sum(nil))
 }
 
+// hashConnectSidecar writes the registration of the sidecar service of a
+// Connect service to the hash of the service. The overrides of the sidecar
+// task are left out as they don't change the registration.
+func hashConnectSidecar(h hash.Hash, sidecar *ConsulSidecarService) {
+	io.WriteString(h, "Connect")
+	io.WriteString(h, sidecar.Port)
+	for _, tag := range sidecar.Tags {
+		io.WriteString(h, tag)
+	}
+	if p := sidecar.Proxy; p != nil {
+		io.WriteString(h, p.LocalServiceAddress)
+		io.WriteString(h, strconv.Itoa(p.LocalServicePort))
+		for _, u := range p.Upstreams {
+			io.WriteString(h, u.DestinationName)
+			io.WriteString(h, strconv.Itoa(u.LocalBindPort))
+		}
+
+		// fmt prints maps sorted by key
+		fmt.Fprintf(h, "%v", p.Config)
+	}
+}
+
 const (
 	// DefaultKillTimeout is the default timeout between signaling a task it
 	// will be killed and killing it.
@@ -4123,6 +5150,18 @@ type Task struct {
 	// KillSignal is the kill signal to use for the task. This is an optional
 	// specification and defaults to SIGINT
 	KillSignal string
+
+	// Lifecycle configures when the task is run relative to the other tasks
+	// in the group. If nil the task is a main task.
+	Lifecycle *TaskLifecycleConfig
+
+	// VolumeMounts is a list of Volume name <-> mount configurations that
+	// will be attached to this task.
+	VolumeMounts []*VolumeMount
+
+	// Kind is set on the tasks Nomad injects on behalf of the user, such as
+	// the sidecar proxies of the Consul Connect services.
+	Kind TaskKind
 }
 
 func (t *Task) Copy() *Task {
@@ -4148,6 +5187,8 @@ func (t *Task) Copy() *Task {
 	nt.Resources = nt.Resources.Copy()
 	nt.Meta = helper.CopyMapStringString(nt.Meta)
 	nt.DispatchPayload = nt.DispatchPayload.Copy()
+	nt.Lifecycle = nt.Lifecycle.Copy()
+	nt.VolumeMounts = CopySliceVolumeMount(nt.VolumeMounts)
 
 	if t.Artifacts != nil {
 		artifacts := make([]*TaskArtifact, 0, len(t.Artifacts))
@@ -4218,7 +5259,7 @@ func (t *Task) GoString() string {
 }
 
 // Validate is used to sanity check a task
-func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
+func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string, tgNetworks Networks) error {
 	var mErr multierror.Error
 	if t.Name == "" {
 		mErr.Errors = append(mErr.Errors, errors.New("Missing task name"))
@@ -4246,6 +5287,18 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
 			mErr.Errors = append(mErr.Errors, err)
 		}
 
+		// Validate the requested ports
+		if err := validateNetworkPorts(t.Resources.Networks); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+
+		// Validate the requested devices
+		for idx, d := range t.Resources.Devices {
+			if err := d.Validate(); err != nil {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Device %d validation failed: %v", idx+1, err))
+			}
+		}
+
 		// Ensure the task isn't asking for disk resources
 		if t.Resources.DiskMB > 0 {
 			mErr.Errors = append(mErr.Errors, errors.New("Task can't ask for disk resources, they have to be specified at the task group level."))
@@ -4259,6 +5312,11 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
+	// Validate the lifecycle
+	if err := t.Lifecycle.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Lifecycle validation failed: %v", err))
+	}
+
 	for idx, constr := range t.Constraints {
 		if err := constr.Validate(); err != nil {
 			outer := fmt.Errorf("Constraint %d validation failed: %s", idx+1, err)
@@ -4272,7 +5330,7 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
 		}
 	}
 
-	if jobType == JobTypeSystem {
+	if IsSystemJobType(jobType) {
 		if t.Affinities != nil {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("System jobs may not have an affinity stanza"))
 		}
@@ -4286,7 +5344,7 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
 	}
 
 	// Validate Services
-	if err := validateServices(t); err != nil {
+	if err := validateServices(t, tgNetworks); err != nil {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
@@ -4338,8 +5396,8 @@ func (t *Task) Validate(ephemeralDisk *EphemeralDisk, jobType string) error {
 }
 
 // validateServices takes a task and validates the services within it are valid
-// and reference ports that exist.
-func validateServices(t *Task) error {
+// and reference ports that exist in the task or task group networks.
+func validateServices(t *Task, tgNetworks Networks) error {
 	var mErr multierror.Error
 
 	// Ensure that services don't ask for nonexistent ports and their names are
@@ -4358,6 +5416,10 @@ func validateServices(t *Task) error {
 			mErr.Errors = append(mErr.Errors, outer)
 		}
 
+		if service.Connect != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("service %q: Consul Connect is only supported by the services of a task group", service.Name))
+		}
+
 		// Ensure that services with the same name are not being registered for
 		// the same port
 		if _, ok := knownServices[service.Name+service.PortLabel]; ok {
@@ -4436,6 +5498,11 @@ func validateServices(t *Task) error {
 			}
 		}
 	}
+	for _, network := range tgNetworks {
+		for portLabel := range network.PortLabels() {
+			portLabels[portLabel] = struct{}{}
+		}
+	}
 
 	// Iterate over a sorted list of keys to make error listings stable
 	keys := make([]string, 0, len(servicePorts))
@@ -4756,6 +5823,10 @@ const (
 
 	// TaskLeaderDead indicates that the leader task within the has finished.
 	TaskLeaderDead = "Leader Task Dead"
+
+	// TaskMainDead indicates that the main tasks of the group have finished
+	// and the sidecar tasks are being stopped.
+	TaskMainDead = "Main Tasks Dead"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -4919,6 +5990,10 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 			parts = append(parts, fmt.Sprintf("Signal: %d", event.Signal))
 		}
 
+		if event.Details["oom_killed"] == "true" {
+			parts = append(parts, "OOM Killed")
+		}
+
 		if event.Message != "" {
 			parts = append(parts, fmt.Sprintf("Exit Message: %q", event.Message))
 		}
@@ -4965,6 +6040,8 @@ func (event *TaskEvent) PopulateEventDisplayMessage() {
 		desc = event.DriverMessage
 	case TaskLeaderDead:
 		desc = "Leader Task in Group dead"
+	case TaskMainDead:
+		desc = "Main tasks in the group died"
 	default:
 		desc = event.Message
 	}
@@ -5036,6 +6113,14 @@ func (e *TaskEvent) SetSignal(s int) *TaskEvent {
 	return e
 }
 
+// SetOOMKilled marks the task as having been killed by the OOM killer.
+func (e *TaskEvent) SetOOMKilled(oom bool) *TaskEvent {
+	if oom {
+		e.Details["oom_killed"] = "true"
+	}
+	return e
+}
+
 func (e *TaskEvent) SetExitMessage(err error) *TaskEvent {
 	if err != nil {
 		e.Message = err.Error()
@@ -5650,6 +6735,7 @@ func (v *Vault) Validate() error {
 const (
 	// DeploymentStatuses are the various states a deployment can be be in
 	DeploymentStatusRunning    = "running"
+	DeploymentStatusPending    = "pending"
 	DeploymentStatusPaused     = "paused"
 	DeploymentStatusFailed     = "failed"
 	DeploymentStatusSuccessful = "successful"
@@ -5660,6 +6746,7 @@ const (
 	DeploymentStatusDescriptionRunning               = "Deployment is running"
 	DeploymentStatusDescriptionRunningNeedsPromotion = "Deployment is running but requires promotion"
 	DeploymentStatusDescriptionPaused                = "Deployment is paused"
+	DeploymentStatusDescriptionPendingForPeer        = "Deployment is pending, waiting for peer region"
 	DeploymentStatusDescriptionSuccessful            = "Deployment completed successfully"
 	DeploymentStatusDescriptionStoppedJob            = "Cancelled because job is stopped"
 	DeploymentStatusDescriptionNewerJob              = "Cancelled due to newer version of job"
@@ -5767,7 +6854,7 @@ func (d *Deployment) Copy() *Deployment {
 // Active returns whether the deployment is active or terminal.
 func (d *Deployment) Active() bool {
 	switch d.Status {
-	case DeploymentStatusRunning, DeploymentStatusPaused:
+	case DeploymentStatusRunning, DeploymentStatusPending, DeploymentStatusPaused:
 		return true
 	default:
 		return false
@@ -5823,6 +6910,10 @@ type DeploymentState struct {
 	// reverted on failure
 	AutoRevert bool
 
+	// AutoPromote marks promotion triggered automatically by healthy canaries
+	// copied from TaskGroup UpdateStrategy in scheduler.reconcile
+	AutoPromote bool
+
 	// ProgressDeadline is the deadline by which an allocation must transition
 	// to healthy before the deployment is considered failed.
 	ProgressDeadline time.Duration
@@ -5863,6 +6954,7 @@ func (d *DeploymentState) GoString() string {
 	base += fmt.Sprintf("\n\tHealthy: %d", d.HealthyAllocs)
 	base += fmt.Sprintf("\n\tUnhealthy: %d", d.UnhealthyAllocs)
 	base += fmt.Sprintf("\n\tAutoRevert: %v", d.AutoRevert)
+	base += fmt.Sprintf("\n\tAutoPromote: %v", d.AutoPromote)
 	return base
 }
 
@@ -6044,6 +7136,10 @@ type Allocation struct {
 	// task. These should sum to the total Resources.
 	TaskResources map[string]*Resources
 
+	// TaskDevices is the set of device instances assigned to each task
+	// that requests devices.
+	TaskDevices map[string][]*AllocatedDeviceResource
+
 	// Metrics associated with this allocation
 	Metrics *AllocMetric
 
@@ -6087,6 +7183,19 @@ type Allocation struct {
 	// that can be rescheduled in the future
 	FollowupEvalID string
 
+	// PreemptedAllocations captures IDs of any allocations that were preempted
+	// in order to place this allocation
+	PreemptedAllocations []string
+
+	// PreemptedByAllocation tracks the alloc ID of the allocation that caused this allocation
+	// to stop running because it got preempted
+	PreemptedByAllocation string
+
+	// SignedIdentities is the workload identity of each task, a JWT signed
+	// by the servers identifying the task's namespace, job, group and
+	// allocation. It is keyed by task name.
+	SignedIdentities map[string]string
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -6147,6 +7256,7 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 		}
 		na.TaskResources = tr
 	}
+	na.TaskDevices = CopyMapAllocatedDevices(a.TaskDevices)
 
 	na.Metrics = na.Metrics.Copy()
 	na.DeploymentStatus = na.DeploymentStatus.Copy()
@@ -6160,6 +7270,23 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 	}
 
 	na.RescheduleTracker = a.RescheduleTracker.Copy()
+	na.PreemptedAllocations = helper.CopySliceString(a.PreemptedAllocations)
+	na.SignedIdentities = helper.CopyMapStringString(a.SignedIdentities)
+	return na
+}
+
+// Sanitize returns the allocation without the workload identities of its
+// tasks, as they are only sent to the node running them. The allocation is
+// only copied if it has identities, and the copy shares everything else with
+// the original.
+func (a *Allocation) Sanitize() *Allocation {
+	if a == nil || len(a.SignedIdentities) == 0 {
+		return a
+	}
+
+	na := new(Allocation)
+	*na = *a
+	na.SignedIdentities = nil
 	return na
 }
 
@@ -6401,6 +7528,7 @@ func (a *Allocation) SetEventDisplayMessages() {
 func (a *Allocation) Stub() *AllocListStub {
 	return &AllocListStub{
 		ID:                 a.ID,
+		Namespace:          a.Namespace,
 		EvalID:             a.EvalID,
 		Name:               a.Name,
 		NodeID:             a.NodeID,
@@ -6426,6 +7554,7 @@ func (a *Allocation) Stub() *AllocListStub {
 // AllocListStub is used to return a subset of alloc information
 type AllocListStub struct {
 	ID                 string
+	Namespace          string
 	EvalID             string
 	Name               string
 	NodeID             string
@@ -6747,6 +7876,8 @@ const (
 	EvalTriggerFailedFollowUp    = "failed-follow-up"
 	EvalTriggerMaxPlans          = "max-plan-attempts"
 	EvalTriggerRetryFailedAlloc  = "alloc-failure"
+	EvalTriggerAllocStop         = "alloc-stop"
+	EvalTriggerPreemption        = "preemption"
 )
 
 const (
@@ -6772,6 +7903,11 @@ const (
 	// check if they are terminal. If so, we delete these out of the system.
 	CoreJobDeploymentGC = "deployment-gc"
 
+	// CoreJobACLTokenExpiredGC is used for the garbage collection of expired
+	// ACL tokens. Local tokens are collected in every region, while global
+	// tokens are only collected in the authoritative region.
+	CoreJobACLTokenExpiredGC = "acl-token-expired-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -6882,11 +8018,62 @@ type Evaluation struct {
 	// scheduler.
 	SnapshotIndex uint64
 
+	// RelatedEvals are the evaluations chained to this one through their
+	// previous, next and blocked evaluations. It is only set when reading
+	// the evaluation with its related evaluations, and is not stored.
+	RelatedEvals []*EvaluationStub
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
 }
 
+// EvaluationStub is a summary of an evaluation used when listing the
+// evaluations related to another one
+type EvaluationStub struct {
+	ID                string
+	Namespace         string
+	Priority          int
+	Type              string
+	TriggeredBy       string
+	JobID             string
+	NodeID            string
+	DeploymentID      string
+	Status            string
+	StatusDescription string
+	WaitUntil         time.Time
+	NextEval          string
+	PreviousEval      string
+	BlockedEval       string
+	CreateIndex       uint64
+	ModifyIndex       uint64
+}
+
+// Stub returns a summary of the evaluation
+func (e *Evaluation) Stub() *EvaluationStub {
+	if e == nil {
+		return nil
+	}
+	return &EvaluationStub{
+		ID:                e.ID,
+		Namespace:         e.Namespace,
+		Priority:          e.Priority,
+		Type:              e.Type,
+		TriggeredBy:       e.TriggeredBy,
+		JobID:             e.JobID,
+		NodeID:            e.NodeID,
+		DeploymentID:      e.DeploymentID,
+		Status:            e.Status,
+		StatusDescription: e.StatusDescription,
+		WaitUntil:         e.WaitUntil,
+		NextEval:          e.NextEval,
+		PreviousEval:      e.PreviousEval,
+		BlockedEval:       e.BlockedEval,
+		CreateIndex:       e.CreateIndex,
+		ModifyIndex:       e.ModifyIndex,
+	}
+}
+
 // TerminalStatus returns if the current status is terminal and
 // will no longer transition.
 func (e *Evaluation) TerminalStatus() bool {
@@ -6969,11 +8156,12 @@ func (e *Evaluation) ShouldBlock() bool {
 // for a given Job
 func (e *Evaluation) MakePlan(j *Job) *Plan {
 	p := &Plan{
-		EvalID:         e.ID,
-		Priority:       e.Priority,
-		Job:            j,
-		NodeUpdate:     make(map[string][]*Allocation),
-		NodeAllocation: make(map[string][]*Allocation),
+		EvalID:          e.ID,
+		Priority:        e.Priority,
+		Job:             j,
+		NodeUpdate:      make(map[string][]*Allocation),
+		NodeAllocation:  make(map[string][]*Allocation),
+		NodePreemptions: make(map[string][]*Allocation),
 	}
 	if j != nil {
 		p.AllAtOnce = j.AllAtOnce
@@ -7074,6 +8262,11 @@ type Plan struct {
 	// The evicts must be considered prior to the allocations.
 	NodeAllocation map[string][]*Allocation
 
+	// NodePreemptions is a map from node id to a set of allocations from other
+	// lower priority jobs that are preempted. Preempted allocations are marked
+	// as evicted.
+	NodePreemptions map[string][]*Allocation
+
 	// Annotations contains annotations by the scheduler to be used by operators
 	// to understand the decisions made by the scheduler.
 	Annotations *PlanAnnotations
@@ -7118,6 +8311,29 @@ func (p *Plan) AppendUpdate(alloc *Allocation, desiredStatus, desiredDesc, clien
 	p.NodeUpdate[node] = append(existing, newAlloc)
 }
 
+// AppendPreemptedAlloc is used to append an allocation that's being preempted
+// to the plan. The preempting allocation's ID is recorded on the preempted
+// allocation.
+func (p *Plan) AppendPreemptedAlloc(alloc *Allocation, preemptingAllocID string) {
+	newAlloc := new(Allocation)
+	*newAlloc = *alloc
+
+	// Normalize the job and strip the resources as they can be rebuilt
+	newAlloc.Job = nil
+	newAlloc.Resources = nil
+
+	newAlloc.DesiredStatus = AllocDesiredStatusEvict
+	newAlloc.DesiredDescription = fmt.Sprintf("Preempted by alloc ID %v", preemptingAllocID)
+	newAlloc.PreemptedByAllocation = preemptingAllocID
+
+	node := alloc.NodeID
+	if p.NodePreemptions == nil {
+		p.NodePreemptions = make(map[string][]*Allocation)
+	}
+	existing := p.NodePreemptions[node]
+	p.NodePreemptions[node] = append(existing, newAlloc)
+}
+
 func (p *Plan) PopUpdate(alloc *Allocation) {
 	existing := p.NodeUpdate[alloc.NodeID]
 	n := len(existing)
@@ -7141,6 +8357,7 @@ func (p *Plan) AppendAlloc(alloc *Allocation) {
 func (p *Plan) IsNoOp() bool {
 	return len(p.NodeUpdate) == 0 &&
 		len(p.NodeAllocation) == 0 &&
+		len(p.NodePreemptions) == 0 &&
 		p.Deployment == nil &&
 		len(p.DeploymentUpdates) == 0
 }
@@ -7153,6 +8370,10 @@ type PlanResult struct {
 	// NodeAllocation contains all the allocations that were committed.
 	NodeAllocation map[string][]*Allocation
 
+	// NodePreemptions is a map from node id to a set of allocations from other
+	// lower priority jobs that are preempted.
+	NodePreemptions map[string][]*Allocation
+
 	// Deployment is the deployment that was committed.
 	Deployment *Deployment
 
@@ -7173,7 +8394,8 @@ type PlanResult struct {
 // IsNoOp checks if this plan result would do nothing
 func (p *PlanResult) IsNoOp() bool {
 	return len(p.NodeUpdate) == 0 && len(p.NodeAllocation) == 0 &&
-		len(p.DeploymentUpdates) == 0 && p.Deployment == nil
+		len(p.NodePreemptions) == 0 && len(p.DeploymentUpdates) == 0 &&
+		p.Deployment == nil
 }
 
 // FullCommit is used to check if all the allocations in a plan
@@ -7368,6 +8590,119 @@ func IsServerSide(e error) bool {
 	return false
 }
 
+// Namespace allows logically grouping jobs and their associated objects.
+type Namespace struct {
+	// Name is the name of the namespace
+	Name string
+
+	// Description is a human readable description of the namespace
+	Description string
+
+	// Quota is the quota specification that the namespace should account
+	// against.
+	Quota string
+
+	// Hash is the hash of the namespace which is used to efficiently replicate
+	// cross-regions.
+	Hash []byte
+
+	// Raft Indexes
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+func (n *Namespace) Validate() error {
+	var mErr multierror.Error
+
+	// Validate the name and description
+	if !validNamespaceName.MatchString(n.Name) {
+		err := fmt.Errorf("invalid name %q. Must match regex %s", n.Name, validNamespaceName)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(n.Description) > maxNamespaceDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxNamespaceDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// SetHash is used to compute and set the hash of the namespace
+func (n *Namespace) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(n.Name))
+	hash.Write([]byte(n.Description))
+	hash.Write([]byte(n.Quota))
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	n.Hash = hashVal
+	return hashVal
+}
+
+func (n *Namespace) Copy() *Namespace {
+	nc := new(Namespace)
+	*nc = *n
+	nc.Hash = make([]byte, len(n.Hash))
+	copy(nc.Hash, n.Hash)
+	return nc
+}
+
+// NamespaceListRequest is used to request a list of namespaces
+type NamespaceListRequest struct {
+	QueryOptions
+}
+
+// NamespaceListResponse is used for a list request
+type NamespaceListResponse struct {
+	Namespaces []*Namespace
+	QueryMeta
+}
+
+// NamespaceSpecificRequest is used to query a specific namespace
+type NamespaceSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleNamespaceResponse is used to return a single namespace
+type SingleNamespaceResponse struct {
+	Namespace *Namespace
+	QueryMeta
+}
+
+// NamespaceSetRequest is used to query a set of namespaces
+type NamespaceSetRequest struct {
+	Namespaces []string
+	QueryOptions
+}
+
+// NamespaceSetResponse is used to return a set of namespaces
+type NamespaceSetResponse struct {
+	Namespaces map[string]*Namespace // Keyed by namespace Name
+	QueryMeta
+}
+
+// NamespaceDeleteRequest is used to delete a set of namespaces
+type NamespaceDeleteRequest struct {
+	Namespaces []string
+	WriteRequest
+}
+
+// NamespaceUpsertRequest is used to upsert a set of namespaces
+type NamespaceUpsertRequest struct {
+	Namespaces []*Namespace
+	WriteRequest
+}
+
 // ACLPolicy is used to represent an ACL policy
 type ACLPolicy struct {
 	Name        string // Unique name
@@ -7482,16 +8817,144 @@ type ACLPolicyUpsertRequest struct {
 	WriteRequest
 }
 
+// ACLRole is a named set of policies that tokens can be linked to instead of
+// listing the policies themselves
+type ACLRole struct {
+	Name        string   // Unique name
+	Description string   // Human readable
+	Policies    []string // Policies this role grants
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SetHash is used to compute and set the hash of the ACL role
+func (r *ACLRole) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(r.Name))
+	hash.Write([]byte(r.Description))
+	for _, policyName := range r.Policies {
+		hash.Write([]byte(policyName))
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	r.Hash = hashVal
+	return hashVal
+}
+
+func (r *ACLRole) Stub() *ACLRoleListStub {
+	return &ACLRoleListStub{
+		Name:        r.Name,
+		Description: r.Description,
+		Policies:    r.Policies,
+		Hash:        r.Hash,
+		CreateIndex: r.CreateIndex,
+		ModifyIndex: r.ModifyIndex,
+	}
+}
+
+func (r *ACLRole) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(r.Name) {
+		err := fmt.Errorf("invalid name '%s'", r.Name)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(r.Policies) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("role missing policies"))
+	}
+	if len(r.Description) > maxPolicyDescriptionLength {
+		err := fmt.Errorf("description longer than %d", maxPolicyDescriptionLength)
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLRoleListStub is used to for listing ACL roles
+type ACLRoleListStub struct {
+	Name        string
+	Description string
+	Policies    []string
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLRoleListRequest is used to request a list of roles
+type ACLRoleListRequest struct {
+	QueryOptions
+}
+
+// ACLRoleSpecificRequest is used to query a specific role
+type ACLRoleSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// ACLRoleSetRequest is used to query a set of roles
+type ACLRoleSetRequest struct {
+	Names []string
+	QueryOptions
+}
+
+// ACLRoleListResponse is used for a list request
+type ACLRoleListResponse struct {
+	Roles []*ACLRoleListStub
+	QueryMeta
+}
+
+// SingleACLRoleResponse is used to return a single role
+type SingleACLRoleResponse struct {
+	Role *ACLRole
+	QueryMeta
+}
+
+// ACLRoleSetResponse is used to return a set of roles
+type ACLRoleSetResponse struct {
+	Roles map[string]*ACLRole
+	QueryMeta
+}
+
+// ACLRoleDeleteRequest is used to delete a set of roles
+type ACLRoleDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLRoleUpsertRequest is used to upsert a set of roles
+type ACLRoleUpsertRequest struct {
+	Roles []*ACLRole
+	WriteRequest
+}
+
 // ACLToken represents a client token which is used to Authenticate
 type ACLToken struct {
-	AccessorID  string   // Public Accessor ID (UUID)
-	SecretID    string   // Secret ID, private (UUID)
-	Name        string   // Human friendly name
-	Type        string   // Client or Management
-	Policies    []string // Policies this token ties to
-	Global      bool     // Global or Region local
-	Hash        []byte
-	CreateTime  time.Time // Time of creation
+	AccessorID string   // Public Accessor ID (UUID)
+	SecretID   string   // Secret ID, private (UUID)
+	Name       string   // Human friendly name
+	Type       string   // Client or Management
+	Policies   []string // Policies this token ties to
+	Roles      []string // Roles this token ties to
+	Global     bool     // Global or Region local
+	Hash       []byte
+	CreateTime time.Time // Time of creation
+
+	// ExpirationTime is the time after which the token can't be used and is
+	// garbage collected. A nil time never expires.
+	ExpirationTime *time.Time
+
+	// ExpirationTTL is used when creating the token to set its expiration
+	// time relative to its creation time. It is not stored.
+	ExpirationTTL time.Duration
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -7509,15 +8972,17 @@ var (
 )
 
 type ACLTokenListStub struct {
-	AccessorID  string
-	Name        string
-	Type        string
-	Policies    []string
-	Global      bool
-	Hash        []byte
-	CreateTime  time.Time
-	CreateIndex uint64
-	ModifyIndex uint64
+	AccessorID     string
+	Name           string
+	Type           string
+	Policies       []string
+	Roles          []string
+	Global         bool
+	Hash           []byte
+	CreateTime     time.Time
+	ExpirationTime *time.Time
+	CreateIndex    uint64
+	ModifyIndex    uint64
 }
 
 // SetHash is used to compute and set the hash of the ACL token
@@ -7534,11 +8999,17 @@ func (a *ACLToken) SetHash() []byte {
 	for _, policyName := range a.Policies {
 		hash.Write([]byte(policyName))
 	}
+	for _, roleName := range a.Roles {
+		hash.Write([]byte(roleName))
+	}
 	if a.Global {
 		hash.Write([]byte("global"))
 	} else {
 		hash.Write([]byte("local"))
 	}
+	if a.ExpirationTime != nil {
+		hash.Write([]byte(a.ExpirationTime.UTC().Format(time.RFC3339Nano)))
+	}
 
 	// Finalize the hash
 	hashVal := hash.Sum(nil)
@@ -7550,15 +9021,17 @@ func (a *ACLToken) SetHash() []byte {
 
 func (a *ACLToken) Stub() *ACLTokenListStub {
 	return &ACLTokenListStub{
-		AccessorID:  a.AccessorID,
-		Name:        a.Name,
-		Type:        a.Type,
-		Policies:    a.Policies,
-		Global:      a.Global,
-		Hash:        a.Hash,
-		CreateTime:  a.CreateTime,
-		CreateIndex: a.CreateIndex,
-		ModifyIndex: a.ModifyIndex,
+		AccessorID:     a.AccessorID,
+		Name:           a.Name,
+		Type:           a.Type,
+		Policies:       a.Policies,
+		Roles:          a.Roles,
+		Global:         a.Global,
+		Hash:           a.Hash,
+		CreateTime:     a.CreateTime,
+		ExpirationTime: a.ExpirationTime,
+		CreateIndex:    a.CreateIndex,
+		ModifyIndex:    a.ModifyIndex,
 	}
 }
 
@@ -7570,19 +9043,33 @@ func (a *ACLToken) Validate() error {
 	}
 	switch a.Type {
 	case ACLClientToken:
-		if len(a.Policies) == 0 {
-			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies"))
+		if len(a.Policies) == 0 && len(a.Roles) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("client token missing policies or roles"))
 		}
 	case ACLManagementToken:
 		if len(a.Policies) != 0 {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with policies"))
 		}
+		if len(a.Roles) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("management token cannot be associated with roles"))
+		}
 	default:
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("token type must be client or management"))
 	}
+	if a.ExpirationTTL < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("token expiration TTL can't be negative"))
+	}
+	if a.ExpirationTTL != 0 && a.ExpirationTime != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("token can't set both an expiration time and TTL"))
+	}
 	return mErr.ErrorOrNil()
 }
 
+// IsExpired returns whether the token has expired at the given time.
+func (a *ACLToken) IsExpired(t time.Time) bool {
+	return a.ExpirationTime != nil && !a.ExpirationTime.After(t)
+}
+
 // PolicySubset checks if a given set of policies is a subset of the token
 func (a *ACLToken) PolicySubset(policies []string) bool {
 	// Hot-path the management tokens, superset of all policies.
@@ -7673,3 +9160,1029 @@ type ACLTokenUpsertResponse struct {
 	Tokens []*ACLToken
 	WriteMeta
 }
+
+// ACLAuthMethod is a way of logging in with an external identity provider
+// instead of presenting an ACL token. A successful login creates a local
+// token whose policies and roles are decided by the binding rules of the
+// method.
+type ACLAuthMethod struct {
+	Name string // Unique name
+	Type string // JWT or OIDC
+
+	// MaxTokenTTL is the lifetime of the tokens created by a login
+	MaxTokenTTL time.Duration
+
+	// Default marks the method used by a login that doesn't name one
+	Default bool
+
+	Config *ACLAuthMethodConfig
+	Hash   []byte
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodConfig is the provider specific configuration of an auth
+// method.
+type ACLAuthMethodConfig struct {
+	// OIDCDiscoveryURL is the issuer URL of the OIDC provider. It is
+	// required by OIDC methods and may be used by JWT methods to fetch the
+	// signing keys.
+	OIDCDiscoveryURL string
+
+	// OIDCClientID and OIDCClientSecret are the credentials Nomad uses with
+	// the OIDC provider
+	OIDCClientID     string
+	OIDCClientSecret string
+
+	// OIDCScopes are requested in addition to the "openid" scope
+	OIDCScopes []string
+
+	// AllowedRedirectURIs are the callback URIs an OIDC login may use
+	AllowedRedirectURIs []string
+
+	// JWKSURL is the JSON Web Key Set of a JWT method
+	JWKSURL string
+
+	// JWTValidationPubKeys are the PEM encoded keys of a JWT method
+	JWTValidationPubKeys []string
+
+	// BoundIssuer is the required "iss" claim of a JWT method
+	BoundIssuer string
+
+	// BoundAudiences are the accepted "aud" claims
+	BoundAudiences []string
+
+	// ClockSkewLeeway is the skew allowed when checking the time claims
+	ClockSkewLeeway time.Duration
+
+	// ClaimMappings and ListClaimMappings map claims to the variables that
+	// binding rules refer to as value.<name> and list.<name>
+	ClaimMappings     map[string]string
+	ListClaimMappings map[string]string
+}
+
+// Copy returns a deep copy of the config.
+func (c *ACLAuthMethodConfig) Copy() *ACLAuthMethodConfig {
+	if c == nil {
+		return nil
+	}
+	nc := new(ACLAuthMethodConfig)
+	*nc = *c
+	nc.OIDCScopes = helper.CopySliceString(c.OIDCScopes)
+	nc.AllowedRedirectURIs = helper.CopySliceString(c.AllowedRedirectURIs)
+	nc.JWTValidationPubKeys = helper.CopySliceString(c.JWTValidationPubKeys)
+	nc.BoundAudiences = helper.CopySliceString(c.BoundAudiences)
+	nc.ClaimMappings = helper.CopyMapStringString(c.ClaimMappings)
+	nc.ListClaimMappings = helper.CopyMapStringString(c.ListClaimMappings)
+	return nc
+}
+
+// SetHash is used to compute and set the hash of the auth method
+func (m *ACLAuthMethod) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(m.Name))
+	hash.Write([]byte(m.Type))
+	hash.Write([]byte(m.MaxTokenTTL.String()))
+	if m.Default {
+		hash.Write([]byte("default"))
+	}
+	if c := m.Config; c != nil {
+		hash.Write([]byte(c.OIDCDiscoveryURL))
+		hash.Write([]byte(c.OIDCClientID))
+		hash.Write([]byte(c.OIDCClientSecret))
+		for _, v := range c.OIDCScopes {
+			hash.Write([]byte(v))
+		}
+		for _, v := range c.AllowedRedirectURIs {
+			hash.Write([]byte(v))
+		}
+		hash.Write([]byte(c.JWKSURL))
+		for _, v := range c.JWTValidationPubKeys {
+			hash.Write([]byte(v))
+		}
+		hash.Write([]byte(c.BoundIssuer))
+		for _, v := range c.BoundAudiences {
+			hash.Write([]byte(v))
+		}
+		hash.Write([]byte(c.ClockSkewLeeway.String()))
+		for _, mapping := range []map[string]string{c.ClaimMappings, c.ListClaimMappings} {
+			keys := make([]string, 0, len(mapping))
+			for k := range mapping {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				hash.Write([]byte(k))
+				hash.Write([]byte(mapping[k]))
+			}
+		}
+	}
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	m.Hash = hashVal
+	return hashVal
+}
+
+// Copy returns a deep copy of the auth method.
+func (m *ACLAuthMethod) Copy() *ACLAuthMethod {
+	if m == nil {
+		return nil
+	}
+	nm := new(ACLAuthMethod)
+	*nm = *m
+	nm.Config = m.Config.Copy()
+	nm.Hash = append([]byte(nil), m.Hash...)
+	return nm
+}
+
+func (m *ACLAuthMethod) Stub() *ACLAuthMethodListStub {
+	return &ACLAuthMethodListStub{
+		Name:        m.Name,
+		Type:        m.Type,
+		Default:     m.Default,
+		Hash:        m.Hash,
+		CreateIndex: m.CreateIndex,
+		ModifyIndex: m.ModifyIndex,
+	}
+}
+
+// Validate is used to sanity check an auth method
+func (m *ACLAuthMethod) Validate() error {
+	var mErr multierror.Error
+	if !validPolicyName.MatchString(m.Name) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid name '%s'", m.Name))
+	}
+	if m.MaxTokenTTL <= 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("max token TTL must be positive"))
+	}
+
+	c := m.Config
+	if c == nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("auth method missing config"))
+		return mErr.ErrorOrNil()
+	}
+	if c.ClockSkewLeeway < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("clock skew leeway can't be negative"))
+	}
+
+	switch m.Type {
+	case ACLAuthMethodTypeOIDC:
+		if c.OIDCDiscoveryURL == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("OIDC auth method missing discovery URL"))
+		}
+		if c.OIDCClientID == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("OIDC auth method missing client ID"))
+		}
+		if len(c.AllowedRedirectURIs) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("OIDC auth method missing allowed redirect URIs"))
+		}
+		if c.JWKSURL != "" || len(c.JWTValidationPubKeys) != 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("OIDC auth method can't set a JWKS URL or public keys"))
+		}
+	case ACLAuthMethodTypeJWT:
+		sources := 0
+		for _, set := range []bool{c.OIDCDiscoveryURL != "", c.JWKSURL != "", len(c.JWTValidationPubKeys) != 0} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("JWT auth method must set exactly one of a discovery URL, a JWKS URL or public keys"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("auth method type must be %s or %s", ACLAuthMethodTypeJWT, ACLAuthMethodTypeOIDC))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLAuthMethodListStub is used to for listing auth methods. It leaves out
+// the config, which may hold the client secret.
+type ACLAuthMethodListStub struct {
+	Name        string
+	Type        string
+	Default     bool
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLAuthMethodListRequest is used to request a list of auth methods
+type ACLAuthMethodListRequest struct {
+	QueryOptions
+}
+
+// ACLAuthMethodSpecificRequest is used to query a specific auth method
+type ACLAuthMethodSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// ACLAuthMethodListResponse is used for a list request
+type ACLAuthMethodListResponse struct {
+	AuthMethods []*ACLAuthMethodListStub
+	QueryMeta
+}
+
+// SingleACLAuthMethodResponse is used to return a single auth method
+type SingleACLAuthMethodResponse struct {
+	AuthMethod *ACLAuthMethod
+	QueryMeta
+}
+
+// ACLAuthMethodDeleteRequest is used to delete a set of auth methods
+type ACLAuthMethodDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
+
+// ACLAuthMethodUpsertRequest is used to upsert a set of auth methods
+type ACLAuthMethodUpsertRequest struct {
+	AuthMethods []*ACLAuthMethod
+	WriteRequest
+}
+
+// ACLBindingRule decides what the tokens created by a login with an auth
+// method are granted. If the selector matches the claims of the login, the
+// token is linked to the role or policy named by BindName, or made a
+// management token.
+type ACLBindingRule struct {
+	ID          string // Unique ID (UUID)
+	Description string // Human readable
+	AuthMethod  string // Name of the auth method
+
+	// Selector is matched against the mapped claims of a login. An empty
+	// selector matches every login.
+	Selector string
+
+	// BindType is role, policy or management
+	BindType string
+
+	// BindName is the role or policy to link. It may refer to the mapped
+	// claims as ${value.<name>}.
+	BindName string
+
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// SetHash is used to compute and set the hash of the binding rule
+func (r *ACLBindingRule) SetHash() []byte {
+	// Initialize a 256bit Blake2 hash (32 bytes)
+	hash, err := blake2b.New256(nil)
+	if err != nil {
+		panic(err)
+	}
+
+	// Write all the user set fields
+	hash.Write([]byte(r.ID))
+	hash.Write([]byte(r.Description))
+	hash.Write([]byte(r.AuthMethod))
+	hash.Write([]byte(r.Selector))
+	hash.Write([]byte(r.BindType))
+	hash.Write([]byte(r.BindName))
+
+	// Finalize the hash
+	hashVal := hash.Sum(nil)
+
+	// Set and return the hash
+	r.Hash = hashVal
+	return hashVal
+}
+
+func (r *ACLBindingRule) Stub() *ACLBindingRuleListStub {
+	return &ACLBindingRuleListStub{
+		ID:          r.ID,
+		Description: r.Description,
+		AuthMethod:  r.AuthMethod,
+		BindType:    r.BindType,
+		BindName:    r.BindName,
+		Hash:        r.Hash,
+		CreateIndex: r.CreateIndex,
+		ModifyIndex: r.ModifyIndex,
+	}
+}
+
+// Validate is used to sanity check a binding rule. The selector is parsed by
+// the ACL endpoint.
+func (r *ACLBindingRule) Validate() error {
+	var mErr multierror.Error
+	if r.AuthMethod == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("binding rule missing auth method"))
+	}
+	if len(r.Description) > maxPolicyDescriptionLength {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("description longer than %d", maxPolicyDescriptionLength))
+	}
+	switch r.BindType {
+	case ACLBindingRuleBindTypeRole, ACLBindingRuleBindTypePolicy:
+		if r.BindName == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("binding rule missing bind name"))
+		}
+	case ACLBindingRuleBindTypeManagement:
+		if r.BindName != "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("management binding rule can't set a bind name"))
+		}
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("bind type must be %s, %s or %s",
+			ACLBindingRuleBindTypeRole, ACLBindingRuleBindTypePolicy, ACLBindingRuleBindTypeManagement))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLBindingRuleListStub is used to for listing binding rules
+type ACLBindingRuleListStub struct {
+	ID          string
+	Description string
+	AuthMethod  string
+	BindType    string
+	BindName    string
+	Hash        []byte
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ACLBindingRuleListRequest is used to request a list of binding rules,
+// optionally of a single auth method
+type ACLBindingRuleListRequest struct {
+	AuthMethod string
+	QueryOptions
+}
+
+// ACLBindingRuleSpecificRequest is used to query a specific binding rule
+type ACLBindingRuleSpecificRequest struct {
+	ID string
+	QueryOptions
+}
+
+// ACLBindingRuleListResponse is used for a list request
+type ACLBindingRuleListResponse struct {
+	BindingRules []*ACLBindingRuleListStub
+	QueryMeta
+}
+
+// SingleACLBindingRuleResponse is used to return a single binding rule
+type SingleACLBindingRuleResponse struct {
+	BindingRule *ACLBindingRule
+	QueryMeta
+}
+
+// ACLBindingRuleDeleteRequest is used to delete a set of binding rules
+type ACLBindingRuleDeleteRequest struct {
+	IDs []string
+	WriteRequest
+}
+
+// ACLBindingRuleUpsertRequest is used to upsert a set of binding rules
+type ACLBindingRuleUpsertRequest struct {
+	BindingRules []*ACLBindingRule
+	WriteRequest
+}
+
+// ACLBindingRuleUpsertResponse is used to return from an
+// ACLBindingRuleUpsertRequest
+type ACLBindingRuleUpsertResponse struct {
+	BindingRules []*ACLBindingRule
+	WriteMeta
+}
+
+// ACLLoginRequest is used to log in with a JWT auth method
+type ACLLoginRequest struct {
+	// AuthMethodName is the method to log in with. The default method is
+	// used if it is empty.
+	AuthMethodName string
+
+	// LoginToken is the JWT presented by the caller
+	LoginToken string
+
+	WriteRequest
+}
+
+// ACLOIDCAuthURLRequest is used to start an OIDC login
+type ACLOIDCAuthURLRequest struct {
+	// AuthMethodName is the method to log in with. The default method is
+	// used if it is empty.
+	AuthMethodName string
+
+	// RedirectURI is where the provider sends the user after the login. It
+	// must be one of the allowed redirect URIs of the method.
+	RedirectURI string
+
+	// ClientNonce is a random value kept by the caller. The ID token of the
+	// login must carry it, which ties the login to the caller.
+	ClientNonce string
+
+	WriteRequest
+}
+
+// ACLOIDCAuthURLResponse is used to return the URL the user must visit to
+// log in with the OIDC provider
+type ACLOIDCAuthURLResponse struct {
+	AuthURL string
+
+	// State is the state of the login, which the provider passes back to the
+	// redirect URI. It must be given to complete the login.
+	State string
+
+	WriteMeta
+}
+
+// ACLOIDCCompleteAuthRequest is used to complete an OIDC login with the
+// parameters the provider passed to the redirect URI
+type ACLOIDCCompleteAuthRequest struct {
+	AuthMethodName string
+	ClientNonce    string
+	State          string
+	Code           string
+	RedirectURI    string
+	WriteRequest
+}
+
+// ACLLoginResponse is used to return the token created by a login
+type ACLLoginResponse struct {
+	Token *ACLToken
+	WriteMeta
+}
+
+const (
+	// RootKeyAlgorithmES256 signs workload identities with ECDSA using the
+	// P-256 curve and SHA-256
+	RootKeyAlgorithmES256 = "ES256"
+
+	// WorkloadIdentityAudience is the audience of workload identities
+	WorkloadIdentityAudience = "nomadproject.io"
+)
+
+// RootKeyMeta is the metadata of a root key the servers use to sign workload
+// identities and encrypt variables. Only the metadata and the public key are
+// replicated through Raft; the key material is kept in the keystore of each
+// server so it never appears in the Raft log or snapshots.
+type RootKeyMeta struct {
+	// KeyID identifies the key in the "kid" header of the tokens it signs
+	// and in the variables it encrypts
+	KeyID string
+
+	// Algorithm is the signing algorithm of the key
+	Algorithm string
+
+	// PublicKey is the PKIX encoded public key, used to verify the workload
+	// identities the key signed
+	PublicKey []byte
+
+	// Active marks the key currently used to sign new identities
+	Active bool
+
+	// CreateTime is the time the key was created
+	CreateTime int64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Copy returns a copy of the root key metadata
+func (m *RootKeyMeta) Copy() *RootKeyMeta {
+	if m == nil {
+		return nil
+	}
+	nm := new(RootKeyMeta)
+	*nm = *m
+	nm.PublicKey = append([]byte(nil), m.PublicKey...)
+	return nm
+}
+
+// Stub returns the metadata of the root key, without its public key.
+func (m *RootKeyMeta) Stub() *RootKeyStub {
+	return &RootKeyStub{
+		KeyID:       m.KeyID,
+		Algorithm:   m.Algorithm,
+		Active:      m.Active,
+		CreateTime:  m.CreateTime,
+		CreateIndex: m.CreateIndex,
+		ModifyIndex: m.ModifyIndex,
+	}
+}
+
+// Public returns the public key of the root key.
+func (m *RootKeyMeta) Public() *RootKeyPublic {
+	return &RootKeyPublic{
+		KeyID:      m.KeyID,
+		Algorithm:  m.Algorithm,
+		PublicKey:  m.PublicKey,
+		CreateTime: m.CreateTime,
+	}
+}
+
+// RootKey is a root key along with its key material. It is only stored in
+// the keystore of each server and sent between servers that are missing it,
+// never through Raft.
+type RootKey struct {
+	Meta *RootKeyMeta
+
+	// Key is the PKCS #8 encoded private key
+	Key []byte
+
+	// EncryptionKey is the AES-256 key used to encrypt variables
+	EncryptionKey []byte
+}
+
+// Copy returns a copy of the root key
+func (k *RootKey) Copy() *RootKey {
+	if k == nil {
+		return nil
+	}
+	nk := new(RootKey)
+	nk.Meta = k.Meta.Copy()
+	nk.Key = append([]byte(nil), k.Key...)
+	nk.EncryptionKey = append([]byte(nil), k.EncryptionKey...)
+	return nk
+}
+
+// RootKeyPublic is the public key of a root key, which is used to verify the
+// workload identities the key signed.
+type RootKeyPublic struct {
+	KeyID     string
+	Algorithm string
+
+	// PublicKey is the PKIX encoded public key
+	PublicKey []byte
+
+	CreateTime int64
+}
+
+// RootKeyStub is the metadata of a root key
+type RootKeyStub struct {
+	KeyID       string
+	Algorithm   string
+	Active      bool
+	CreateTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// RootKeyUpsertRequest is used to store the metadata of a root key
+type RootKeyUpsertRequest struct {
+	RootKeyMeta *RootKeyMeta
+	WriteRequest
+}
+
+// KeyringGetRootKeyRequest is used by servers to fetch the key material of a
+// root key from another server
+type KeyringGetRootKeyRequest struct {
+	KeyID string
+
+	// ServerID is the node ID of the requesting server. Without TLS for
+	// RPC, the key material is sent to this server instead of being
+	// returned.
+	ServerID string
+	QueryOptions
+}
+
+// KeyringGetRootKeyResponse is used to return a root key and its key
+// material
+type KeyringGetRootKeyResponse struct {
+	Key *RootKey
+	QueryMeta
+}
+
+// KeyringPutRootKeyRequest is used by servers to send the key material of a
+// root key to another server
+type KeyringPutRootKeyRequest struct {
+	Key *RootKey
+	WriteRequest
+}
+
+// KeyringListPublicRequest is used to list the public keys of the root keys
+type KeyringListPublicRequest struct {
+	QueryOptions
+}
+
+// KeyringListPublicResponse is used to return the public keys of the root
+// keys
+type KeyringListPublicResponse struct {
+	PublicKeys []*RootKeyPublic
+	QueryMeta
+}
+
+// KeyringRotateRequest is used to replace the active root key by a new one
+type KeyringRotateRequest struct {
+	// Full re-encrypts all the variables with the new key
+	Full bool
+
+	WriteRequest
+}
+
+// KeyringRotateResponse is used to return the new active root key
+type KeyringRotateResponse struct {
+	Key *RootKeyStub
+	WriteMeta
+}
+
+// KeyringListRequest is used to list the root keys
+type KeyringListRequest struct {
+	QueryOptions
+}
+
+// KeyringListResponse is used to return the root keys
+type KeyringListResponse struct {
+	Keys []*RootKeyStub
+	QueryMeta
+}
+
+const (
+	// maxVariableSize is the maximum size of the items of a variable
+	maxVariableSize = 16 * 1024
+
+	// VariablesJobPathPrefix is the path prefix of the variables tasks have
+	// access to with their workload identity
+	VariablesJobPathPrefix = "nomad/jobs"
+)
+
+var (
+	// validVariablePath is used to validate the path of a variable
+	validVariablePath = regexp.MustCompile("^[a-zA-Z0-9-_~./]{1,128}$")
+)
+
+// VariableMetadata is the metadata of a variable
+type VariableMetadata struct {
+	Namespace string
+	Path      string
+
+	CreateTime  int64
+	ModifyTime  int64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// VariableDecrypted is a variable with its items in plaintext. It is never
+// stored, and only exists in the requests and responses of the Variables
+// endpoint.
+type VariableDecrypted struct {
+	VariableMetadata
+	Items map[string]string
+}
+
+// Copy returns a copy of the variable
+func (v *VariableDecrypted) Copy() *VariableDecrypted {
+	if v == nil {
+		return nil
+	}
+	nv := new(VariableDecrypted)
+	*nv = *v
+	nv.Items = helper.CopyMapStringString(v.Items)
+	return nv
+}
+
+// Validate returns an error if the variable is invalid
+func (v *VariableDecrypted) Validate() error {
+	var mErr multierror.Error
+	if err := ValidateVariablePath(v.Path); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(v.Items) == 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("variable must have at least one item"))
+	}
+
+	size := 0
+	for k, item := range v.Items {
+		if k == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("item keys can't be empty"))
+		}
+		size += len(k) + len(item)
+	}
+	if size > maxVariableSize {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("variable items exceed maximum size of %d bytes", maxVariableSize))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ValidateVariablePath returns an error if the path is not a valid variable
+// path. Paths under "nomad/" are reserved, except for the paths of jobs.
+func ValidateVariablePath(path string) error {
+	if !validVariablePath.MatchString(path) {
+		return fmt.Errorf("invalid variable path %q: must be 1-128 characters of letters, digits and \"-_~./\"", path)
+	}
+	if strings.HasPrefix(path, "/") || strings.HasSuffix(path, "/") || strings.Contains(path, "//") {
+		return fmt.Errorf("invalid variable path %q: path segments can't be empty", path)
+	}
+	if path == "nomad" || strings.HasPrefix(path, "nomad/") {
+		if path != VariablesJobPathPrefix && !strings.HasPrefix(path, VariablesJobPathPrefix+"/") {
+			return fmt.Errorf("invalid variable path %q: only %q is allowed under \"nomad/\"", path, VariablesJobPathPrefix)
+		}
+	}
+	return nil
+}
+
+// VariablesTaskPaths returns the paths of the variables a task can read with
+// its workload identity, from the least to the most specific.
+func VariablesTaskPaths(jobID, group, task string) []string {
+	jobPath := VariablesJobPathPrefix + "/" + jobID
+	return []string{
+		jobPath,
+		jobPath + "/" + group,
+		jobPath + "/" + group + "/" + task,
+	}
+}
+
+// VariableEncrypted is a variable with its items encrypted by a root key,
+// as it is stored in the state store.
+type VariableEncrypted struct {
+	VariableMetadata
+
+	// Data is the encrypted JSON encoding of the items
+	Data []byte
+
+	// KeyID is the ID of the root key that encrypted the items
+	KeyID string
+}
+
+// Copy returns a copy of the variable
+func (v *VariableEncrypted) Copy() *VariableEncrypted {
+	if v == nil {
+		return nil
+	}
+	nv := new(VariableEncrypted)
+	*nv = *v
+	nv.Data = append([]byte(nil), v.Data...)
+	return nv
+}
+
+// VariablesUpsertRequest is used to create or update a variable
+type VariablesUpsertRequest struct {
+	Var *VariableDecrypted
+	WriteRequest
+}
+
+// VariablesUpsertResponse is used to return the stored variable
+type VariablesUpsertResponse struct {
+	Output *VariableMetadata
+	WriteMeta
+}
+
+// VariableUpsertRequest is used to store an encrypted variable through Raft
+type VariableUpsertRequest struct {
+	Var *VariableEncrypted
+
+	// CheckIndex, if non-zero, only updates the variable if it still exists
+	// with this modify index. ErrVariableConflict is returned otherwise.
+	CheckIndex uint64
+
+	WriteRequest
+}
+
+// VariablesDeleteRequest is used to delete the variable at a path of the
+// request's namespace
+type VariablesDeleteRequest struct {
+	Path string
+	WriteRequest
+}
+
+// VariablesReadRequest is used to read the variable at a path of the
+// request's namespace
+type VariablesReadRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesReadResponse is used to return a variable
+type VariablesReadResponse struct {
+	Data *VariableDecrypted
+	QueryMeta
+}
+
+// VariablesListRequest is used to list the variables of the request's
+// namespace whose path starts with the query prefix
+type VariablesListRequest struct {
+	QueryOptions
+}
+
+// VariablesListResponse is used to return the metadata of variables
+type VariablesListResponse struct {
+	Data []*VariableMetadata
+	QueryMeta
+}
+
+// ServiceRegistration is an instance of a service with the Nomad provider,
+// registered by the client running its allocation.
+type ServiceRegistration struct {
+	// ID is the ID of the service instance, unique within its namespace
+	ID string
+
+	// ServiceName is the name of the service
+	ServiceName string
+
+	Namespace  string
+	NodeID     string
+	Datacenter string
+	JobID      string
+	AllocID    string
+
+	Tags    []string
+	Address string
+	Port    int
+
+	// Checks are the health checks of the instance, run by its client
+	Checks []*ServiceRegistrationCheck
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ServiceRegistrationCheck is the status of a health check of a service
+// instance.
+type ServiceRegistrationCheck struct {
+	Name   string
+	Type   string
+	Status string
+	Output string
+}
+
+// Copy returns a copy of the service registration
+func (s *ServiceRegistration) Copy() *ServiceRegistration {
+	if s == nil {
+		return nil
+	}
+	ns := new(ServiceRegistration)
+	*ns = *s
+	ns.Tags = helper.CopySliceString(s.Tags)
+	if s.Checks != nil {
+		ns.Checks = make([]*ServiceRegistrationCheck, len(s.Checks))
+		for i, c := range s.Checks {
+			nc := *c
+			ns.Checks[i] = &nc
+		}
+	}
+	return ns
+}
+
+// Healthy returns whether all the checks of the instance are passing
+func (s *ServiceRegistration) Healthy() bool {
+	for _, c := range s.Checks {
+		if c.Status != api.HealthPassing {
+			return false
+		}
+	}
+	return true
+}
+
+// Equals returns whether two registrations describe the same instance,
+// ignoring their raft indexes.
+func (s *ServiceRegistration) Equals(o *ServiceRegistration) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	a, b := *s, *o
+	a.CreateIndex, a.ModifyIndex = 0, 0
+	b.CreateIndex, b.ModifyIndex = 0, 0
+	return reflect.DeepEqual(a, b)
+}
+
+// Validate returns an error if the registration is missing required fields
+func (s *ServiceRegistration) Validate() error {
+	var mErr multierror.Error
+	if s.ID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing service ID"))
+	}
+	if s.ServiceName == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing service name"))
+	}
+	if s.Namespace == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing namespace"))
+	}
+	if s.NodeID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing node ID"))
+	}
+	if s.AllocID == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing allocation ID"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ServiceRegistrationListStub is a service and the tags of its instances
+type ServiceRegistrationListStub struct {
+	ServiceName string
+	Tags        []string
+}
+
+// ServiceRegistrationUpsertRequest is used by clients to register or update
+// service instances
+type ServiceRegistrationUpsertRequest struct {
+	Services []*ServiceRegistration
+	WriteRequest
+}
+
+// ServiceRegistrationDeleteByIDRequest is used to remove a service instance
+// of the request's namespace
+type ServiceRegistrationDeleteByIDRequest struct {
+	ID string
+	WriteRequest
+}
+
+// ServiceRegistrationListRequest is used to list the services of the
+// request's namespace
+type ServiceRegistrationListRequest struct {
+	QueryOptions
+}
+
+// ServiceRegistrationListResponse is used to return the services of a
+// namespace
+type ServiceRegistrationListResponse struct {
+	Services []*ServiceRegistrationListStub
+	QueryMeta
+}
+
+// ServiceRegistrationByNameRequest is used to list the instances of a
+// service of the request's namespace
+type ServiceRegistrationByNameRequest struct {
+	ServiceName string
+	QueryOptions
+}
+
+// ServiceRegistrationByNameResponse is used to return the instances of a
+// service
+type ServiceRegistrationByNameResponse struct {
+	Services []*ServiceRegistration
+	QueryMeta
+}
+
+// Topic is the kind of object the events of the event stream are about
+type Topic string
+
+const (
+	TopicJob        Topic = "Job"
+	TopicAllocation Topic = "Allocation"
+	TopicEvaluation Topic = "Evaluation"
+	TopicDeployment Topic = "Deployment"
+	TopicNode       Topic = "Node"
+
+	// TopicAll matches the events of all the topics
+	TopicAll Topic = "*"
+)
+
+// The types of the events of the event stream
+const (
+	TypeNodeRegistration    = "NodeRegistration"
+	TypeNodeDeregistration  = "NodeDeregistration"
+	TypeJobRegistered       = "JobRegistered"
+	TypeJobDeregistered     = "JobDeregistered"
+	TypeEvalUpdated         = "EvaluationUpdated"
+	TypeAllocUpdated        = "AllocationUpdated"
+	TypeDeploymentUpdate    = "DeploymentStatusUpdate"
+	TypeDeploymentPromotion = "DeploymentPromotion"
+)
+
+// Event is a change of the state of the cluster published on the event stream
+type Event struct {
+	// Topic is the kind of object that changed
+	Topic Topic
+
+	// Type is the kind of change
+	Type string
+
+	// Key is the ID of the object that changed
+	Key string
+
+	// Namespace is the namespace of the object, empty for the objects
+	// that aren't namespaced
+	Namespace string
+
+	// Index is the raft index of the change
+	Index uint64
+
+	// Payload is the object after the change
+	Payload *EventPayload
+}
+
+// EventPayload holds the object of an event, depending on its topic
+type EventPayload struct {
+	Job        *Job        `json:",omitempty"`
+	Allocation *Allocation `json:",omitempty"`
+	Evaluation *Evaluation `json:",omitempty"`
+	Deployment *Deployment `json:",omitempty"`
+	Node       *Node       `json:",omitempty"`
+}
+
+// Events are the events published for a raft index. An empty set of events
+// is sent as a heartbeat on the event stream.
+type Events struct {
+	Index  uint64
+	Events []Event
+}
+
+// EventStreamRequest is used to stream the events of the given topics. The
+// topics map to the keys of the events to stream, "*" matching all the keys.
+// Only the events after Index are streamed, and only new events if it is
+// zero.
+type EventStreamRequest struct {
+	Topics map[Topic][]string
+	Index  uint64
+	QueryOptions
+}
@@ -15,6 +15,32 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestJobSubmission_Validate(t *testing.T) {
+	sub := &JobSubmission{
+		Source: `job "example" {}`,
+		Format: JobSubmissionFormatHCL2,
+		VariableFlags: map[string]string{
+			"image": "redis:4.0",
+		},
+		Variables: `image = "redis:4.0"`,
+	}
+	require.NoError(t, sub.Validate())
+
+	sub.Format = "yaml"
+	require.Error(t, sub.Validate())
+
+	// Only HCL2 sources have variables
+	sub.Format = JobSubmissionFormatHCL1
+	require.Error(t, sub.Validate())
+	sub.VariableFlags = nil
+	sub.Variables = ""
+	require.NoError(t, sub.Validate())
+
+	sub.Format = JobSubmissionFormatJSON
+	sub.Source = strings.Repeat("#", JobSubmissionMaxSize+1)
+	require.Error(t, sub.Validate())
+}
+
 func TestJob_Validate(t *testing.T) {
 	j := &Job{}
 	err := j.Validate()
@@ -422,6 +448,30 @@ func TestJob_SystemJob_Validate(t *testing.T) {
 
 }
 
+func TestJob_SysBatchJob_Validate(t *testing.T) {
+	require := require.New(t)
+
+	j := testJob()
+	j.Type = JobTypeSysBatch
+	j.TaskGroups[0].ReschedulePolicy = nil
+	j.Canonicalize()
+
+	err := j.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "Count cannot exceed 1 with sysbatch scheduler")
+
+	j.TaskGroups[0].Count = 1
+	require.NoError(j.Validate())
+
+	// Sysbatch jobs may be periodic
+	j.Periodic = &PeriodicConfig{
+		Enabled:  true,
+		SpecType: PeriodicSpecCron,
+		Spec:     "*/5 * * * *",
+	}
+	require.NoError(j.Validate())
+}
+
 func TestJob_VaultPolicies(t *testing.T) {
 	j0 := &Job{}
 	e0 := make(map[string]map[string]*Vault, 0)
@@ -772,10 +822,280 @@ func TestTaskGroup_Validate(t *testing.T) {
 	}
 }
 
+func TestTaskGroup_Validate_Lifecycle(t *testing.T) {
+	j := testJob()
+	prestart := &TaskLifecycleConfig{Hook: TaskLifecycleHookPrestart}
+
+	tg := &TaskGroup{
+		Name: "web",
+		Tasks: []*Task{
+			{Name: "init", Leader: true, Lifecycle: prestart},
+		},
+	}
+	err := tg.Validate(j)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Task init has a lifecycle and can't be marked as leader")
+	require.Contains(t, err.Error(), "Must have at least one task without a lifecycle")
+
+	tg.Tasks[0].Leader = false
+	tg.Tasks = append(tg.Tasks, &Task{Name: "main"})
+	err = tg.Validate(j)
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "lifecycle")
+}
+
+func TestTaskGroup_Validate_Volumes(t *testing.T) {
+	j := testJob()
+
+	tg := &TaskGroup{
+		Name: "web",
+		Volumes: map[string]*VolumeRequest{
+			"foo": {Name: "foo", Type: "nfs"},
+		},
+		Tasks: []*Task{
+			{
+				Name: "web",
+				VolumeMounts: []*VolumeMount{
+					{Volume: "bar", Destination: "/srv"},
+				},
+			},
+		},
+	}
+	err := tg.Validate(j)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Unsupported volume type "nfs"`)
+	require.Contains(t, err.Error(), "Missing volume source")
+	require.Contains(t, err.Error(), `Task web mounts volume "bar" which is not requested by the task group`)
+
+	tg.Volumes["foo"] = &VolumeRequest{Name: "foo", Type: VolumeTypeHost, Source: "shared"}
+	tg.Tasks[0].VolumeMounts[0].Volume = "foo"
+	err = tg.Validate(j)
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "volume")
+}
+
+func TestTaskGroup_Validate_Networks(t *testing.T) {
+	j := testJob()
+
+	tg := &TaskGroup{
+		Name: "web",
+		Networks: []*NetworkResource{
+			{
+				Mode:          "overlay",
+				ReservedPorts: []Port{{Label: "http", Value: 80, To: 70000}},
+			},
+			{Mode: NetworkModeHost},
+		},
+		Tasks: []*Task{
+			{
+				Name: "web",
+				Resources: &Resources{
+					Networks: []*NetworkResource{
+						{ReservedPorts: []Port{{Label: "admin", Value: 80}}},
+					},
+				},
+				Services: []*Service{{Name: "web", PortLabel: "http"}},
+			},
+		},
+	}
+	err := tg.Validate(j)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Only one network may be defined by a task group")
+	require.Contains(t, err.Error(), `Invalid network mode "overlay"`)
+	require.Contains(t, err.Error(), `Port "http" must be mapped to a port between 1 and 65535; got 70000`)
+	require.Contains(t, err.Error(), "Static port 80 already reserved by taskgroup network:http")
+
+	tg.Networks = []*NetworkResource{
+		{
+			Mode:          NetworkModeHost,
+			ReservedPorts: []Port{{Label: "http", Value: 8080, To: 80}},
+		},
+	}
+	err = tg.Validate(j)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Port "http" can't be mapped in host network mode`)
+
+	tg.Networks[0].Mode = NetworkModeBridge
+	err = tg.Validate(j)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `Task web can't request a network when the task group network is in "bridge" mode`)
+	require.NotContains(t, err.Error(), "mapped")
+	require.NotContains(t, err.Error(), "http")
+
+	tg.Networks[0].Mode = "cni/mynet"
+	tg.Tasks[0].Resources.Networks = nil
+	err = tg.Validate(j)
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "network")
+	require.NotContains(t, err.Error(), "http")
+}
+
+func TestTaskGroup_Validate_Services(t *testing.T) {
+	j := testJob()
+
+	tg := &TaskGroup{
+		Name:             "web",
+		EphemeralDisk:    DefaultEphemeralDisk(),
+		RestartPolicy:    NewRestartPolicy(JobTypeService),
+		ReschedulePolicy: NewReschedulePolicy(JobTypeService),
+		Networks: []*NetworkResource{
+			{
+				Mode:         NetworkModeHost,
+				DynamicPorts: []Port{{Label: "http"}},
+			},
+		},
+		Services: []*Service{
+			{
+				Name:      "web",
+				PortLabel: "http",
+				Checks: []*ServiceCheck{
+					{
+						Name:         "script",
+						Type:         ServiceCheckScript,
+						Command:      "/bin/true",
+						Interval:     time.Second,
+						Timeout:      time.Second,
+						CheckRestart: &CheckRestart{Limit: 1},
+					},
+				},
+			},
+			{
+				Name:      "admin",
+				PortLabel: "admin",
+				Connect: &ConsulConnect{
+					SidecarService: &ConsulSidecarService{Port: "missing"},
+				},
+			},
+		},
+		Tasks: []*Task{
+			{
+				Name:      "web",
+				Driver:    "exec",
+				Resources: DefaultResources(),
+				LogConfig: DefaultLogConfig(),
+			},
+		},
+	}
+	err := tg.Validate(j)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `check "script": script checks are not supported by group services`)
+	require.Contains(t, err.Error(), `check "script": check_restart is not supported by group services`)
+	require.Contains(t, err.Error(), `Service "admin" uses port "admin" which is not defined by the group network`)
+	require.Contains(t, err.Error(), `Consul Connect service "admin" requires the group network to be in "bridge" mode`)
+	require.Contains(t, err.Error(), `Consul Connect service "admin" sidecar uses port "missing" which is not defined by the group network`)
+	require.Contains(t, err.Error(), `Consul Connect service "admin" is missing its sidecar task`)
+
+	// A valid Connect service with its sidecar task
+	tg.Networks[0].Mode = NetworkModeBridge
+	tg.Networks[0].DynamicPorts = append(tg.Networks[0].DynamicPorts, Port{Label: "sidecar"})
+	tg.Services = tg.Services[1:]
+	tg.Services[0].PortLabel = "http"
+	tg.Services[0].Connect.SidecarService.Port = "sidecar"
+	tg.Tasks = append(tg.Tasks, &Task{
+		Name:      ConnectProxyName("admin"),
+		Kind:      NewTaskKind(ConnectProxyPrefix, "admin"),
+		Driver:    "exec",
+		Resources: DefaultResources(),
+		LogConfig: DefaultLogConfig(),
+	})
+	require.NoError(t, tg.Validate(j))
+
+	// Connect can't be used by the services of the tasks
+	tg.Tasks[0].Services = []*Service{
+		{
+			Name:      "task",
+			PortLabel: "http",
+			Connect:   &ConsulConnect{SidecarService: &ConsulSidecarService{}},
+		},
+	}
+	err = tg.Validate(j)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Consul Connect")
+}
+
+func TestConsulProxy_Validate(t *testing.T) {
+	proxy := &ConsulProxy{
+		LocalServicePort: 70000,
+		Upstreams: []*ConsulUpstream{
+			{DestinationName: "db", LocalBindPort: 5432},
+			{DestinationName: "db", LocalBindPort: 5433},
+			{DestinationName: "cache", LocalBindPort: 5432},
+			{LocalBindPort: 6379},
+		},
+	}
+	err := proxy.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "local_service_port must be between 1 and 65535; got 70000")
+	require.Contains(t, err.Error(), `Upstream "db" is duplicate`)
+	require.Contains(t, err.Error(), `Upstreams "db" and "cache" bind the same local port 5432`)
+	require.Contains(t, err.Error(), "Upstream 4 validation failed: 1 error(s) occurred:\n\n* Missing destination_name")
+
+	proxy.LocalServicePort = 8080
+	proxy.Upstreams = proxy.Upstreams[:1]
+	require.NoError(t, proxy.Validate())
+}
+
+func TestTask_Validate_Lifecycle(t *testing.T) {
+	task := &Task{
+		Name:   "init",
+		Driver: "docker",
+		Resources: &Resources{
+			CPU:      100,
+			MemoryMB: 100,
+			IOPS:     10,
+		},
+		LogConfig: DefaultLogConfig(),
+		Lifecycle: &TaskLifecycleConfig{
+			Hook: TaskLifecycleHookPrestart,
+		},
+	}
+	ephemeralDisk := DefaultEphemeralDisk()
+	require.NoError(t, task.Validate(ephemeralDisk, JobTypeService, nil))
+	require.True(t, task.IsInit())
+	require.False(t, task.IsSidecar())
+
+	task.Lifecycle.Sidecar = true
+	require.NoError(t, task.Validate(ephemeralDisk, JobTypeService, nil))
+	require.False(t, task.IsInit())
+	require.True(t, task.IsSidecar())
+
+	task.Lifecycle.Hook = TaskLifecycleHookPoststop
+	err := task.Validate(ephemeralDisk, JobTypeService, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"poststop" tasks can't be sidecars`)
+
+	task.Lifecycle.Sidecar = false
+	require.NoError(t, task.Validate(ephemeralDisk, JobTypeService, nil))
+	require.False(t, task.IsInit())
+	require.False(t, task.IsSidecar())
+	require.True(t, task.IsPoststop())
+
+	task.Lifecycle.Hook = TaskLifecycleHookPoststart
+	require.NoError(t, task.Validate(ephemeralDisk, JobTypeService, nil))
+	require.False(t, task.IsInit())
+	require.False(t, task.IsSidecar())
+	require.True(t, task.IsPoststart())
+
+	task.Lifecycle.Sidecar = true
+	require.NoError(t, task.Validate(ephemeralDisk, JobTypeService, nil))
+	require.True(t, task.IsSidecar())
+	require.True(t, task.IsPoststart())
+
+	task.Lifecycle.Hook = "bogus"
+	err = task.Validate(ephemeralDisk, JobTypeService, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid hook "bogus"`)
+
+	task.Lifecycle.Hook = ""
+	err = task.Validate(ephemeralDisk, JobTypeService, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no lifecycle hook provided")
+}
+
 func TestTask_Validate(t *testing.T) {
 	task := &Task{}
 	ephemeralDisk := DefaultEphemeralDisk()
-	err := task.Validate(ephemeralDisk, JobTypeBatch)
+	err := task.Validate(ephemeralDisk, JobTypeBatch, nil)
 	mErr := err.(*multierror.Error)
 	if !strings.Contains(mErr.Errors[0].Error(), "task name") {
 		t.Fatalf("err: %s", err)
@@ -788,7 +1108,7 @@ func TestTask_Validate(t *testing.T) {
 	}
 
 	task = &Task{Name: "web/foo"}
-	err = task.Validate(ephemeralDisk, JobTypeBatch)
+	err = task.Validate(ephemeralDisk, JobTypeBatch, nil)
 	mErr = err.(*multierror.Error)
 	if !strings.Contains(mErr.Errors[0].Error(), "slashes") {
 		t.Fatalf("err: %s", err)
@@ -805,7 +1125,7 @@ func TestTask_Validate(t *testing.T) {
 		LogConfig: DefaultLogConfig(),
 	}
 	ephemeralDisk.SizeMB = 200
-	err = task.Validate(ephemeralDisk, JobTypeBatch)
+	err = task.Validate(ephemeralDisk, JobTypeBatch, nil)
 	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
@@ -819,7 +1139,7 @@ func TestTask_Validate(t *testing.T) {
 			LTarget: "${meta.rack}",
 		})
 
-	err = task.Validate(ephemeralDisk, JobTypeBatch)
+	err = task.Validate(ephemeralDisk, JobTypeBatch, nil)
 	mErr = err.(*multierror.Error)
 	if !strings.Contains(mErr.Errors[0].Error(), "task level: distinct_hosts") {
 		t.Fatalf("err: %s", err)
@@ -829,6 +1149,38 @@ func TestTask_Validate(t *testing.T) {
 	}
 }
 
+func TestTask_Validate_NetworkPorts(t *testing.T) {
+	task := &Task{
+		Name:   "web",
+		Driver: "docker",
+		Resources: &Resources{
+			CPU:      100,
+			MemoryMB: 100,
+			IOPS:     10,
+			Networks: []*NetworkResource{
+				{
+					MBits:         10,
+					ReservedPorts: []Port{{Label: "http", Value: 80}, {Label: "", Value: 443}, {Label: "admin", Value: 70000}},
+					DynamicPorts:  []Port{{Label: "http", Value: 0}, {Label: "rpc", Value: 0}},
+				},
+			},
+		},
+		LogConfig: DefaultLogConfig(),
+	}
+	ephemeralDisk := DefaultEphemeralDisk()
+
+	err := task.Validate(ephemeralDisk, JobTypeService, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "port label must not be empty")
+	require.Contains(t, err.Error(), `reserved port "admin" must be between 1 and 65535; got 70000`)
+	require.Contains(t, err.Error(), "found a port label collision: http")
+
+	// Labels are compared exactly
+	task.Resources.Networks[0].ReservedPorts = []Port{{Label: "http", Value: 80}}
+	task.Resources.Networks[0].DynamicPorts = []Port{{Label: "HTTP", Value: 0}, {Label: "rpc", Value: 0}}
+	require.NoError(t, task.Validate(ephemeralDisk, JobTypeService, nil))
+}
+
 func TestTask_Validate_Services(t *testing.T) {
 	s1 := &Service{
 		Name:      "service-name",
@@ -902,7 +1254,7 @@ func TestTask_Validate_Services(t *testing.T) {
 		},
 	}
 
-	err := task.Validate(ephemeralDisk, JobTypeService)
+	err := task.Validate(ephemeralDisk, JobTypeService, nil)
 	if err == nil {
 		t.Fatal("expected an error")
 	}
@@ -923,7 +1275,7 @@ func TestTask_Validate_Services(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
-	if err = task1.Validate(ephemeralDisk, JobTypeService); err != nil {
+	if err = task1.Validate(ephemeralDisk, JobTypeService, nil); err != nil {
 		t.Fatalf("err : %v", err)
 	}
 }
@@ -982,7 +1334,7 @@ func TestTask_Validate_Service_AddressMode_Ok(t *testing.T) {
 	for _, service := range cases {
 		task := getTask(service)
 		t.Run(service.Name, func(t *testing.T) {
-			if err := task.Validate(ephemeralDisk, JobTypeService); err != nil {
+			if err := task.Validate(ephemeralDisk, JobTypeService, nil); err != nil {
 				t.Fatalf("unexpected err: %v", err)
 			}
 		})
@@ -1035,7 +1387,7 @@ func TestTask_Validate_Service_AddressMode_Bad(t *testing.T) {
 	for _, service := range cases {
 		task := getTask(service)
 		t.Run(service.Name, func(t *testing.T) {
-			err := task.Validate(ephemeralDisk, JobTypeService)
+			err := task.Validate(ephemeralDisk, JobTypeService, nil)
 			if err == nil {
 				t.Fatalf("expected an error")
 			}
@@ -1129,6 +1481,25 @@ func TestTask_Validate_Service_Check(t *testing.T) {
 	}
 }
 
+func TestServiceCheck_Canonicalize_Type(t *testing.T) {
+	check := &ServiceCheck{
+		Type:     "HTTP",
+		Path:     "/health",
+		Interval: 10 * time.Second,
+		Timeout:  2 * time.Second,
+	}
+
+	check.Canonicalize("web")
+	require.Equal(t, ServiceCheckHTTP, check.Type)
+	require.True(t, check.RequiresPort())
+	require.NoError(t, check.validate())
+
+	check.Type = "udp"
+	err := check.validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `"grpc"`)
+}
+
 // TestTask_Validate_Service_Check_AddressMode asserts that checks do not
 // inherit address mode but do inherit ports.
 func TestTask_Validate_Service_Check_AddressMode(t *testing.T) {
@@ -1288,7 +1659,7 @@ func TestTask_Validate_Service_Check_AddressMode(t *testing.T) {
 		tc := tc
 		task := getTask(tc.Service)
 		t.Run(tc.Service.Name, func(t *testing.T) {
-			err := validateServices(task)
+			err := validateServices(task, nil)
 			if err == nil && tc.ErrContains == "" {
 				// Ok!
 				return
@@ -1333,12 +1704,13 @@ func TestTask_Validate_Service_Check_CheckRestart(t *testing.T) {
 	t.Parallel()
 	invalidCheckRestart := &CheckRestart{
 		Limit: -1,
-		Grace: -1,
+		Grace: -1 * time.Second,
 	}
 
 	err := invalidCheckRestart.Validate()
 	assert.NotNil(t, err, "invalidateCheckRestart.Validate()")
 	assert.Len(t, err.(*multierror.Error).Errors, 2)
+	assert.Contains(t, err.Error(), "grace period must be greater than or equal to 0 but found -1s")
 
 	validCheckRestart := &CheckRestart{}
 	assert.Nil(t, validCheckRestart.Validate())
@@ -1356,7 +1728,7 @@ func TestTask_Validate_LogConfig(t *testing.T) {
 		SizeMB: 1,
 	}
 
-	err := task.Validate(ephemeralDisk, JobTypeService)
+	err := task.Validate(ephemeralDisk, JobTypeService, nil)
 	mErr := err.(*multierror.Error)
 	if !strings.Contains(mErr.Errors[3].Error(), "log storage") {
 		t.Fatalf("err: %s", err)
@@ -1373,7 +1745,7 @@ func TestTask_Validate_Template(t *testing.T) {
 		SizeMB: 1,
 	}
 
-	err := task.Validate(ephemeralDisk, JobTypeService)
+	err := task.Validate(ephemeralDisk, JobTypeService, nil)
 	if !strings.Contains(err.Error(), "Template 1 validation failed") {
 		t.Fatalf("err: %s", err)
 	}
@@ -1386,7 +1758,7 @@ func TestTask_Validate_Template(t *testing.T) {
 	}
 
 	task.Templates = []*Template{good, good}
-	err = task.Validate(ephemeralDisk, JobTypeService)
+	err = task.Validate(ephemeralDisk, JobTypeService, nil)
 	if !strings.Contains(err.Error(), "same destination as") {
 		t.Fatalf("err: %s", err)
 	}
@@ -1399,7 +1771,7 @@ func TestTask_Validate_Template(t *testing.T) {
 		},
 	}
 
-	err = task.Validate(ephemeralDisk, JobTypeService)
+	err = task.Validate(ephemeralDisk, JobTypeService, nil)
 	if err == nil {
 		t.Fatalf("expected error from Template.Validate")
 	}
@@ -1773,6 +2145,18 @@ func TestResource_Superset(t *testing.T) {
 	}
 }
 
+func TestResource_MeetsMinResources_MemoryMax(t *testing.T) {
+	require := require.New(t)
+
+	r := &Resources{CPU: 100, MemoryMB: 256, MemoryMaxMB: 1024}
+	require.NoError(r.MeetsMinResources())
+
+	r.MemoryMaxMB = 128
+	err := r.MeetsMinResources()
+	require.Error(err)
+	require.Contains(err.Error(), "MemoryMaxMB value (128) must be greater than or equal to MemoryMB value (256)")
+}
+
 func TestResource_Add(t *testing.T) {
 	r1 := &Resources{
 		CPU:      2000,
@@ -1783,7 +2167,7 @@ func TestResource_Add(t *testing.T) {
 			{
 				CIDR:          "10.0.0.0/8",
 				MBits:         100,
-				ReservedPorts: []Port{{"ssh", 22}},
+				ReservedPorts: []Port{{Label: "ssh", Value: 22}},
 			},
 		},
 	}
@@ -1796,7 +2180,7 @@ func TestResource_Add(t *testing.T) {
 			{
 				IP:            "10.0.0.1",
 				MBits:         50,
-				ReservedPorts: []Port{{"web", 80}},
+				ReservedPorts: []Port{{Label: "web", Value: 80}},
 			},
 		},
 	}
@@ -1815,7 +2199,7 @@ func TestResource_Add(t *testing.T) {
 			{
 				CIDR:          "10.0.0.0/8",
 				MBits:         150,
-				ReservedPorts: []Port{{"ssh", 22}, {"web", 80}},
+				ReservedPorts: []Port{{Label: "ssh", Value: 22}, {Label: "web", Value: 80}},
 			},
 		},
 	}
@@ -1831,7 +2215,7 @@ func TestResource_Add_Network(t *testing.T) {
 		Networks: []*NetworkResource{
 			{
 				MBits:        50,
-				DynamicPorts: []Port{{"http", 0}, {"https", 0}},
+				DynamicPorts: []Port{{Label: "http", Value: 0}, {Label: "https", Value: 0}},
 			},
 		},
 	}
@@ -1839,7 +2223,7 @@ func TestResource_Add_Network(t *testing.T) {
 		Networks: []*NetworkResource{
 			{
 				MBits:        25,
-				DynamicPorts: []Port{{"admin", 0}},
+				DynamicPorts: []Port{{Label: "admin", Value: 0}},
 			},
 		},
 	}
@@ -1857,7 +2241,7 @@ func TestResource_Add_Network(t *testing.T) {
 		Networks: []*NetworkResource{
 			{
 				MBits:        75,
-				DynamicPorts: []Port{{"http", 0}, {"https", 0}, {"admin", 0}},
+				DynamicPorts: []Port{{Label: "http", Value: 0}, {Label: "https", Value: 0}, {Label: "admin", Value: 0}},
 			},
 		},
 	}
@@ -2468,6 +2852,19 @@ func TestReschedulePolicy_Validate(t *testing.T) {
 				fmt.Errorf("Max Delay cannot be less than %v (got %v)", ReschedulePolicyMinDelay, 0*time.Second),
 			},
 		},
+		{
+			desc: "Invalid Max Delay",
+			ReschedulePolicy: &ReschedulePolicy{
+				Unlimited:     true,
+				DelayFunction: "exponential",
+				Delay:         10 * time.Second,
+				MaxDelay:      2 * time.Second,
+			},
+			errors: []error{
+				fmt.Errorf("Max Delay cannot be less than %v (got %v)", ReschedulePolicyMinDelay, 2*time.Second),
+				fmt.Errorf("Max Delay cannot be less than Delay %v (got %v)", 10*time.Second, 2*time.Second),
+			},
+		},
 		{
 			desc: "Valid Unlimited config",
 			ReschedulePolicy: &ReschedulePolicy{
@@ -3488,6 +3885,95 @@ func TestParameterizedJobConfig_Canonicalize(t *testing.T) {
 	}
 }
 
+func TestMultiregion_Validate(t *testing.T) {
+	require := require.New(t)
+
+	m := &Multiregion{
+		Strategy: &MultiregionStrategy{
+			MaxParallel: -1,
+			OnFailure:   "foo",
+		},
+		Regions: []*MultiregionRegion{
+			{Name: "west", Count: -1},
+			{Name: "west"},
+			{},
+		},
+	}
+	err := m.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "Max parallel must be >= 0")
+	require.Contains(err.Error(), "Unknown on_failure")
+	require.Contains(err.Error(), "count must be >= 0")
+	require.Contains(err.Error(), "defined more than once")
+	require.Contains(err.Error(), "missing name")
+
+	require.Contains((&Multiregion{}).Validate().Error(), "Missing regions")
+
+	m = &Multiregion{
+		Strategy: &MultiregionStrategy{
+			MaxParallel: 1,
+			OnFailure:   MultiregionOnFailureFailLocal,
+		},
+		Regions: []*MultiregionRegion{{Name: "west"}, {Name: "east"}},
+	}
+	require.NoError(m.Validate())
+}
+
+func TestJob_RegionalJob(t *testing.T) {
+	require := require.New(t)
+
+	job := testJob()
+	job.Meta = map[string]string{"owner": "armon"}
+	job.TaskGroups[0].Count = 0
+	job.Multiregion = &Multiregion{
+		Regions: []*MultiregionRegion{
+			{
+				Name:        "west",
+				Count:       3,
+				Datacenters: []string{"west-1"},
+				Meta:        map[string]string{"code": "w"},
+			},
+		},
+	}
+
+	regional := job.RegionalJob(job.Multiregion.Regions[0])
+	require.Equal("west", regional.Region)
+	require.Equal([]string{"west-1"}, regional.Datacenters)
+	require.Equal(map[string]string{"owner": "armon", "code": "w"}, regional.Meta)
+	require.Equal(3, regional.TaskGroups[0].Count)
+	require.NotNil(regional.Multiregion)
+
+	// The original job isn't modified
+	require.Equal("global", job.Region)
+	require.Equal(map[string]string{"owner": "armon"}, job.Meta)
+	require.Equal(0, job.TaskGroups[0].Count)
+
+	// The count of groups that set one isn't replaced
+	job.TaskGroups[0].Count = 2
+	regional = job.RegionalJob(job.Multiregion.Regions[0])
+	require.Equal(2, regional.TaskGroups[0].Count)
+}
+
+func TestJob_MultiregionPending(t *testing.T) {
+	job := testJob()
+	require.False(t, job.MultiregionPending())
+
+	job.Multiregion = &Multiregion{
+		Strategy: &MultiregionStrategy{MaxParallel: 1},
+		Regions:  []*MultiregionRegion{{Name: "west"}, {Name: "east"}},
+	}
+
+	job.Region = "west"
+	require.False(t, job.MultiregionPending())
+
+	job.Region = "east"
+	require.True(t, job.MultiregionPending())
+
+	// All the regions are deployed at once without max parallel
+	job.Multiregion.Strategy.MaxParallel = 0
+	require.False(t, job.MultiregionPending())
+}
+
 func TestDispatchPayloadConfig_Validate(t *testing.T) {
 	d := &DispatchPayloadConfig{
 		File: "foo",
@@ -3574,6 +4060,130 @@ func TestACLTokenValidate(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestACLTokenValidate_RolesAndExpiration(t *testing.T) {
+	require := require.New(t)
+
+	// A client token may only be associated with roles
+	tk := &ACLToken{Type: ACLClientToken, Roles: []string{"foo"}}
+	require.NoError(tk.Validate())
+
+	// Management tokens can't have roles
+	tk.Type = ACLManagementToken
+	err := tk.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "associated with roles")
+
+	// Negative TTLs are invalid
+	tk.Type = ACLClientToken
+	tk.ExpirationTTL = -time.Second
+	err = tk.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "can't be negative")
+
+	// The TTL and expiration time are exclusive
+	now := time.Now().UTC()
+	tk.ExpirationTTL = time.Hour
+	tk.ExpirationTime = &now
+	err = tk.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "both an expiration time and TTL")
+}
+
+func TestACLTokenIsExpired(t *testing.T) {
+	now := time.Now().UTC()
+	past := now.Add(-time.Minute)
+
+	tk := &ACLToken{}
+	assert.False(t, tk.IsExpired(now))
+
+	tk.ExpirationTime = &past
+	assert.True(t, tk.IsExpired(now))
+	assert.False(t, tk.IsExpired(past.Add(-time.Second)))
+}
+
+func TestACLRoleValidate(t *testing.T) {
+	require := require.New(t)
+
+	role := &ACLRole{Name: "bad name"}
+	err := role.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "invalid name")
+	require.Contains(err.Error(), "missing policies")
+
+	role.Name = "ci"
+	role.Policies = []string{"foo"}
+	require.NoError(role.Validate())
+}
+
+func TestACLAuthMethodValidate(t *testing.T) {
+	require := require.New(t)
+
+	method := &ACLAuthMethod{Name: "bad name", Type: "ldap"}
+	err := method.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "invalid name")
+	require.Contains(err.Error(), "max token TTL")
+	require.Contains(err.Error(), "missing config")
+
+	method = &ACLAuthMethod{
+		Name:        "ci",
+		Type:        ACLAuthMethodTypeJWT,
+		MaxTokenTTL: time.Hour,
+		Config: &ACLAuthMethodConfig{
+			JWKSURL:              "https://example.com/keys",
+			JWTValidationPubKeys: []string{"key"},
+		},
+	}
+	err = method.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "exactly one")
+
+	method.Config.JWTValidationPubKeys = nil
+	require.NoError(method.Validate())
+
+	method.Type = ACLAuthMethodTypeOIDC
+	err = method.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "missing discovery URL")
+	require.Contains(err.Error(), "missing client ID")
+	require.Contains(err.Error(), "missing allowed redirect URIs")
+
+	method.Config = &ACLAuthMethodConfig{
+		OIDCDiscoveryURL:    "https://example.com",
+		OIDCClientID:        "nomad",
+		AllowedRedirectURIs: []string{"http://localhost:4649/oidc/callback"},
+	}
+	require.NoError(method.Validate())
+
+	// The hash covers the config
+	hash := method.SetHash()
+	method.Config.OIDCScopes = []string{"groups"}
+	require.NotEqual(hash, method.SetHash())
+}
+
+func TestACLBindingRuleValidate(t *testing.T) {
+	require := require.New(t)
+
+	rule := &ACLBindingRule{BindType: "bad"}
+	err := rule.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "missing auth method")
+	require.Contains(err.Error(), "bind type")
+
+	rule = &ACLBindingRule{AuthMethod: "ci", BindType: ACLBindingRuleBindTypeRole}
+	err = rule.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "missing bind name")
+
+	rule.BindName = "deployer"
+	require.NoError(rule.Validate())
+
+	rule.BindType = ACLBindingRuleBindTypeManagement
+	require.Error(rule.Validate())
+	rule.BindName = ""
+	require.NoError(rule.Validate())
+}
+
 func TestACLTokenPolicySubset(t *testing.T) {
 	tk := &ACLToken{
 		Type:     ACLClientToken,
@@ -3619,6 +4229,40 @@ func TestACLTokenSetHash(t *testing.T) {
 	assert.NotEqual(t, out1, out2)
 }
 
+func TestNamespace_Validate(t *testing.T) {
+	require := require.New(t)
+
+	ns := &Namespace{
+		Name:        "*",
+		Description: strings.Repeat("a", maxNamespaceDescriptionLength+1),
+	}
+	err := ns.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "invalid name")
+	require.Contains(err.Error(), "description longer")
+
+	ns.Name = "team-a"
+	ns.Description = "team a"
+	require.NoError(ns.Validate())
+}
+
+func TestNamespace_SetHash(t *testing.T) {
+	require := require.New(t)
+
+	ns := &Namespace{
+		Name:        "foo",
+		Description: "bar",
+	}
+	out1 := ns.SetHash()
+	require.NotEmpty(out1)
+	require.Equal(out1, ns.Hash)
+
+	ns.Quota = "quota"
+	out2 := ns.SetHash()
+	require.Equal(out2, ns.Hash)
+	require.NotEqual(out1, out2)
+}
+
 func TestACLPolicySetHash(t *testing.T) {
 	ap := &ACLPolicy{
 		Name:        "foo",
@@ -3665,6 +4309,7 @@ func TestTaskEventPopulate(t *testing.T) {
 		{NewTaskEvent(TaskKilling).SetKillTimeout(1 * time.Second), "Sent interrupt. Waiting 1s before force killing"},
 		{NewTaskEvent(TaskTerminated).SetExitCode(-1).SetSignal(3), "Exit Code: -1, Signal: 3"},
 		{NewTaskEvent(TaskTerminated).SetMessage("Goodbye"), "Exit Code: 0, Exit Message: \"Goodbye\""},
+		{NewTaskEvent(TaskTerminated).SetExitCode(137).SetOOMKilled(true), "Exit Code: 137, OOM Killed"},
 		{NewTaskEvent(TaskKilled), "Task successfully killed"},
 		{NewTaskEvent(TaskKilled).SetKillError(fmt.Errorf("undead creatures can't be killed")), "undead creatures can't be killed"},
 		{NewTaskEvent(TaskNotRestarting).SetRestartReason("Chaos Monkey did it"), "Chaos Monkey did it"},
@@ -3702,12 +4347,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 			},
 			true,
@@ -3718,12 +4363,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.0",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 			},
 			false,
@@ -3734,12 +4379,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         40,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 			},
 			false,
@@ -3750,12 +4395,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}, {"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}, {Label: "web", Value: 80}},
 				},
 			},
 			false,
@@ -3766,7 +4411,7 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
@@ -3782,12 +4427,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"web", 80}},
+					ReservedPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:            "10.0.0.1",
 					MBits:         50,
-					ReservedPorts: []Port{{"notweb", 80}},
+					ReservedPorts: []Port{{Label: "notweb", Value: 80}},
 				},
 			},
 			false,
@@ -3798,12 +4443,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"web", 80}},
+					DynamicPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"web", 80}, {"web", 80}},
+					DynamicPorts: []Port{{Label: "web", Value: 80}, {Label: "web", Value: 80}},
 				},
 			},
 			false,
@@ -3814,7 +4459,7 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"web", 80}},
+					DynamicPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:           "10.0.0.1",
@@ -3830,12 +4475,12 @@ func TestNetworkResourcesEquals(t *testing.T) {
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"web", 80}},
+					DynamicPorts: []Port{{Label: "web", Value: 80}},
 				},
 				{
 					IP:           "10.0.0.1",
 					MBits:        50,
-					DynamicPorts: []Port{{"notweb", 80}},
+					DynamicPorts: []Port{{Label: "notweb", Value: 80}},
 				},
 			},
 			false,
@@ -3865,6 +4510,35 @@ func TestNode_Canonicalize(t *testing.T) {
 	require.Equal(NodeSchedulingIneligible, node.SchedulingEligibility)
 }
 
+func TestNode_InNodePool(t *testing.T) {
+	require := require.New(t)
+
+	n := &Node{}
+	require.True(n.InNodePool(""))
+	require.True(n.InNodePool(NodePoolDefault))
+	require.True(n.InNodePool(NodePoolAll))
+	require.False(n.InNodePool("gpu"))
+
+	n.NodePool = "gpu"
+	require.False(n.InNodePool(""))
+	require.False(n.InNodePool(NodePoolDefault))
+	require.True(n.InNodePool(NodePoolAll))
+	require.True(n.InNodePool("gpu"))
+}
+
+func TestJob_Validate_NodePool(t *testing.T) {
+	require := require.New(t)
+
+	j := testJob()
+	j.NodePool = "gpu"
+	require.NoError(j.Validate())
+
+	j.NodePool = "not a pool"
+	err := j.Validate()
+	require.Error(err)
+	require.Contains(err.Error(), "invalid node pool")
+}
+
 func TestNode_Copy(t *testing.T) {
 	t.Parallel()
 	require := require.New(t)
@@ -0,0 +1,178 @@
+package structs
+
+import (
+	"fmt"
+	"path/filepath"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// VolumeTypeHost is the type of a volume backed by a host volume that
+	// is declared in the client configuration.
+	VolumeTypeHost = "host"
+)
+
+// ClientHostVolumeConfig is used to configure access to host paths on a
+// Nomad client. Host volumes are fingerprinted as part of the node so the
+// scheduler can place task groups that require them.
+type ClientHostVolumeConfig struct {
+	Name     string
+	Path     string
+	ReadOnly bool
+}
+
+func (p *ClientHostVolumeConfig) Copy() *ClientHostVolumeConfig {
+	if p == nil {
+		return nil
+	}
+
+	c := new(ClientHostVolumeConfig)
+	*c = *p
+	return c
+}
+
+// Validate is used to check that a host volume is well formed.
+func (p *ClientHostVolumeConfig) Validate() error {
+	var mErr multierror.Error
+	if p.Name == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Missing host volume name"))
+	}
+	if p.Path == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Missing path for host volume %q", p.Name))
+	} else if !filepath.IsAbs(p.Path) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Path for host volume %q must be absolute: %q", p.Name, p.Path))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CopyMapStringClientHostVolumeConfig is a helper to copy a map of host
+// volumes.
+func CopyMapStringClientHostVolumeConfig(m map[string]*ClientHostVolumeConfig) map[string]*ClientHostVolumeConfig {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]*ClientHostVolumeConfig, len(m))
+	for k, v := range m {
+		nm[k] = v.Copy()
+	}
+	return nm
+}
+
+// CopySliceClientHostVolumeConfig is a helper to copy a list of host volumes.
+func CopySliceClientHostVolumeConfig(s []*ClientHostVolumeConfig) []*ClientHostVolumeConfig {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	ns := make([]*ClientHostVolumeConfig, l)
+	for i, v := range s {
+		ns[i] = v.Copy()
+	}
+	return ns
+}
+
+// HostVolumeSliceMerge merges two lists of host volumes. Volumes in b
+// replace volumes of the same name in a.
+func HostVolumeSliceMerge(a, b []*ClientHostVolumeConfig) []*ClientHostVolumeConfig {
+	n := make([]*ClientHostVolumeConfig, len(a))
+	seenKeys := make(map[string]int, len(a))
+
+	for i, config := range a {
+		n[i] = config.Copy()
+		seenKeys[config.Name] = i
+	}
+
+	for _, config := range b {
+		if fIndex, ok := seenKeys[config.Name]; ok {
+			n[fIndex] = config.Copy()
+			continue
+		}
+
+		n = append(n, config.Copy())
+	}
+
+	return n
+}
+
+// VolumeRequest is a representation of a storage volume that a TaskGroup
+// wishes to use.
+type VolumeRequest struct {
+	Name     string
+	Type     string
+	Source   string
+	ReadOnly bool
+}
+
+func (v *VolumeRequest) Copy() *VolumeRequest {
+	if v == nil {
+		return nil
+	}
+
+	nv := new(VolumeRequest)
+	*nv = *v
+	return nv
+}
+
+// Validate is used to check that a volume request is well formed.
+func (v *VolumeRequest) Validate() error {
+	var mErr multierror.Error
+	switch v.Type {
+	case VolumeTypeHost:
+	case "":
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Missing volume type"))
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Unsupported volume type %q, must be %q", v.Type, VolumeTypeHost))
+	}
+	if v.Source == "" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Missing volume source"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CopyMapVolumeRequest is a helper to copy a map of volume requests.
+func CopyMapVolumeRequest(m map[string]*VolumeRequest) map[string]*VolumeRequest {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(map[string]*VolumeRequest, len(m))
+	for k, v := range m {
+		nm[k] = v.Copy()
+	}
+	return nm
+}
+
+// VolumeMount is a representation of the mounting of a volume, requested by
+// the task group, into a task.
+type VolumeMount struct {
+	Volume      string
+	Destination string
+	ReadOnly    bool
+}
+
+func (v *VolumeMount) Copy() *VolumeMount {
+	if v == nil {
+		return nil
+	}
+
+	nv := new(VolumeMount)
+	*nv = *v
+	return nv
+}
+
+// CopySliceVolumeMount is a helper to copy a list of volume mounts.
+func CopySliceVolumeMount(s []*VolumeMount) []*VolumeMount {
+	l := len(s)
+	if l == 0 {
+		return nil
+	}
+
+	ns := make([]*VolumeMount, l)
+	for i, v := range s {
+		ns[i] = v.Copy()
+	}
+	return ns
+}
@@ -38,6 +38,11 @@ type serverParts struct {
 	Addr         net.Addr
 	RPCAddr      net.Addr
 	Status       serf.MemberStatus
+
+	// NonVoter is set for the servers which must never be promoted to
+	// voters, and RedundancyZone is the redundancy zone of the server.
+	NonVoter       bool
+	RedundancyZone string
 }
 
 func (s *serverParts) String() string {
@@ -117,23 +122,27 @@ func isNomadServer(m serf.Member) (bool, *serverParts) {
 		}
 	}
 
+	_, nonVoter := m.Tags["nonvoter"]
+
 	addr := &net.TCPAddr{IP: m.Addr, Port: port}
 	rpcAddr := &net.TCPAddr{IP: rpcIP, Port: port}
 	parts := &serverParts{
-		Name:         m.Name,
-		ID:           id,
-		Region:       region,
-		Datacenter:   datacenter,
-		Port:         port,
-		Bootstrap:    bootstrap,
-		Expect:       expect,
-		Addr:         addr,
-		RPCAddr:      rpcAddr,
-		MajorVersion: majorVersion,
-		MinorVersion: minorVersion,
-		Build:        *buildVersion,
-		RaftVersion:  raftVsn,
-		Status:       m.Status,
+		Name:           m.Name,
+		ID:             id,
+		Region:         region,
+		Datacenter:     datacenter,
+		Port:           port,
+		Bootstrap:      bootstrap,
+		Expect:         expect,
+		Addr:           addr,
+		RPCAddr:        rpcAddr,
+		MajorVersion:   majorVersion,
+		MinorVersion:   minorVersion,
+		Build:          *buildVersion,
+		RaftVersion:    raftVsn,
+		Status:         m.Status,
+		NonVoter:       nonVoter,
+		RedundancyZone: m.Tags[AutopilotRZTag],
 	}
 	return true, parts
 }
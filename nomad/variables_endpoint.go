@@ -0,0 +1,244 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Variables endpoint is used to manipulate the encrypted variables of a
+// namespace
+type Variables struct {
+	srv *Server
+}
+
+// Upsert is used to create or update a variable
+func (v *Variables) Upsert(args *structs.VariablesUpsertRequest, reply *structs.VariablesUpsertResponse) error {
+	if done, err := v.srv.forward("Variables.Upsert", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "upsert"}, time.Now())
+
+	if args.Var == nil {
+		return fmt.Errorf("missing variable")
+	}
+	sv := args.Var.Copy()
+	sv.Namespace = args.RequestNamespace()
+	if err := sv.Validate(); err != nil {
+		return err
+	}
+
+	// Check write permissions on the path
+	if aclObj, err := v.srv.resolveTokenOrIdentity(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(sv.Namespace, sv.Path, acl.VariablesCapabilityWrite) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Encrypt the items with the active root key
+	plaintext, err := json.Marshal(sv.Items)
+	if err != nil {
+		return err
+	}
+	ciphertext, keyID, err := v.srv.encrypter.Encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt variable: %v", err)
+	}
+
+	now := time.Now().UTC().UnixNano()
+	req := &structs.VariableUpsertRequest{
+		Var: &structs.VariableEncrypted{
+			VariableMetadata: structs.VariableMetadata{
+				Namespace:  sv.Namespace,
+				Path:       sv.Path,
+				CreateTime: now,
+				ModifyTime: now,
+			},
+			Data:  ciphertext,
+			KeyID: keyID,
+		},
+		WriteRequest: args.WriteRequest,
+	}
+
+	// Update via Raft
+	resp, index, err := v.srv.raftApply(structs.VariableUpsertRequestType, req)
+	if err != nil {
+		return err
+	}
+	if err, ok := resp.(error); ok && err != nil {
+		return err
+	}
+
+	// The state store sets the indexes and keeps the create time of an
+	// existing variable
+	out, err := v.srv.State().GetVariable(nil, sv.Namespace, sv.Path)
+	if err != nil {
+		return err
+	}
+	if out != nil {
+		meta := out.VariableMetadata
+		reply.Output = &meta
+	}
+	reply.Index = index
+	return nil
+}
+
+// Delete is used to delete a variable
+func (v *Variables) Delete(args *structs.VariablesDeleteRequest, reply *structs.GenericResponse) error {
+	if done, err := v.srv.forward("Variables.Delete", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "delete"}, time.Now())
+
+	// Check destroy permissions on the path
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.resolveTokenOrIdentity(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(ns, args.Path, acl.VariablesCapabilityDestroy) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.Path == "" {
+		return fmt.Errorf("missing variable path")
+	}
+	existing, err := v.srv.State().GetVariable(nil, ns, args.Path)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("variable %q not found", args.Path)
+	}
+
+	// Update via Raft
+	resp, index, err := v.srv.raftApply(structs.VariableDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	if err, ok := resp.(error); ok && err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
+// Read is used to read and decrypt a variable
+func (v *Variables) Read(args *structs.VariablesReadRequest, reply *structs.VariablesReadResponse) error {
+	if done, err := v.srv.forward("Variables.Read", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "read"}, time.Now())
+
+	// Check read permissions on the path
+	ns := args.RequestNamespace()
+	if aclObj, err := v.srv.resolveTokenOrIdentity(args.AuthToken); err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableOperation(ns, args.Path, acl.VariablesCapabilityRead) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			out, err := state.GetVariable(ws, ns, args.Path)
+			if err != nil {
+				return err
+			}
+
+			reply.Data = nil
+			if out != nil {
+				decrypted, err := v.decrypt(out)
+				if err != nil {
+					return err
+				}
+				reply.Data = decrypted
+				reply.Index = out.ModifyIndex
+			} else {
+				// Use the last index that affected the variables table
+				index, err := state.Index("variables")
+				if err != nil {
+					return err
+				}
+				reply.Index = index
+			}
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// List is used to list the metadata of the variables whose path starts with
+// the query prefix. Only the variables the token may list are returned.
+func (v *Variables) List(args *structs.VariablesListRequest, reply *structs.VariablesListResponse) error {
+	if done, err := v.srv.forward("Variables.List", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "list"}, time.Now())
+
+	// Check list permissions on the namespace
+	ns := args.RequestNamespace()
+	aclObj, err := v.srv.resolveTokenOrIdentity(args.AuthToken)
+	if err != nil {
+		return err
+	} else if aclObj != nil && !aclObj.AllowVariableSearch(ns) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			iter, err := state.VariablesByPathPrefix(ws, ns, args.Prefix)
+			if err != nil {
+				return err
+			}
+
+			reply.Data = nil
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				sv := raw.(*structs.VariableEncrypted)
+				if aclObj != nil && !aclObj.AllowVariableOperation(ns, sv.Path, acl.VariablesCapabilityList) {
+					continue
+				}
+				meta := sv.VariableMetadata
+				reply.Data = append(reply.Data, &meta)
+			}
+
+			// Use the last index that affected the variables table
+			index, err := state.Index("variables")
+			if err != nil {
+				return err
+			}
+
+			// Ensure we never set the index to zero, otherwise a blocking query cannot be used.
+			// We floor the index at one, since realistically the first write must have a higher index.
+			if index == 0 {
+				index = 1
+			}
+			reply.Index = index
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// decrypt returns the decrypted variable.
+func (v *Variables) decrypt(sv *structs.VariableEncrypted) (*structs.VariableDecrypted, error) {
+	plaintext, err := v.srv.encrypter.Decrypt(sv.Data, sv.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt variable %q: %v", sv.Path, err)
+	}
+
+	out := &structs.VariableDecrypted{VariableMetadata: sv.VariableMetadata}
+	if err := json.Unmarshal(plaintext, &out.Items); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,468 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariablesEndpoint_CRUD(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a variable
+	sv := mock.Variable()
+	req := &structs.VariablesUpsertRequest{
+		Var:          sv,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	require.NotEqual(uint64(0), resp.Index)
+	require.Equal(sv.Path, resp.Output.Path)
+	require.Equal(resp.Index, resp.Output.CreateIndex)
+
+	// The items are encrypted in the state store
+	stored, err := s1.fsm.State().GetVariable(nil, structs.DefaultNamespace, sv.Path)
+	require.NoError(err)
+	require.NotContains(string(stored.Data), "hunter2")
+
+	// Read it back
+	get := &structs.VariablesReadRequest{
+		Path:         sv.Path,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp))
+	require.Equal(sv.Items, getResp.Data.Items)
+	require.Equal(resp.Index, getResp.Index)
+
+	// Update it
+	sv.Items = map[string]string{"password": "correct horse"}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	getResp = structs.VariablesReadResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp))
+	require.Equal(sv.Items, getResp.Data.Items)
+	require.True(getResp.Data.CreateIndex < getResp.Data.ModifyIndex)
+
+	// List by prefix
+	other := mock.Variable()
+	other.Path = "other/" + other.Path
+	req.Var = other
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+
+	list := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", Prefix: "app/"},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", list, &listResp))
+	require.Len(listResp.Data, 1)
+	require.Equal(sv.Path, listResp.Data[0].Path)
+	require.Equal(resp.Index, listResp.Index)
+
+	// Delete it
+	del := &structs.VariablesDeleteRequest{
+		Path:         sv.Path,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Delete", del, &delResp))
+	getResp = structs.VariablesReadResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp))
+	require.Nil(getResp.Data)
+
+	// Deleting a missing variable fails
+	require.Error(msgpackrpc.CallWithCodec(codec, "Variables.Delete", del, &delResp))
+
+	// Invalid variables are rejected
+	req.Var = &structs.VariableDecrypted{
+		VariableMetadata: structs.VariableMetadata{Path: "nomad/other"},
+		Items:            map[string]string{"a": "b"},
+	}
+	require.Error(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+}
+
+func TestVariablesEndpoint_LeaderChange(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	s2 := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+	})
+	defer s2.Shutdown()
+	s3 := TestServer(t, func(c *Config) {
+		c.DevDisableBootstrap = true
+	})
+	defer s3.Shutdown()
+	servers := []*Server{s1, s2, s3}
+	TestJoin(t, s1, s2, s3)
+
+	for _, s := range servers {
+		testutil.WaitForResult(func() (bool, error) {
+			peers, _ := s.numPeers()
+			return peers == 3, fmt.Errorf("%d peers", peers)
+		}, func(err error) {
+			t.Fatalf("should have 3 peers: %v", err)
+		})
+	}
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Write a variable
+	sv := mock.Variable()
+	req := &structs.VariablesUpsertRequest{
+		Var:          sv,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(rpcClient(t, s1), "Variables.Upsert", req, &resp))
+
+	stored, err := s1.fsm.State().GetVariable(nil, structs.DefaultNamespace, sv.Path)
+	require.NoError(err)
+	require.NotNil(stored)
+
+	// The key material is copied to all the servers without TLS
+	for _, s := range servers {
+		s := s
+		testutil.WaitForResult(func() (bool, error) {
+			return s.encrypter.HasKey(stored.KeyID), nil
+		}, func(err error) {
+			t.Fatalf("server %s is missing root key %s", s.config.NodeName, stored.KeyID)
+		})
+	}
+
+	// Kill the leader
+	var leader *Server
+	for _, s := range servers {
+		if s.IsLeader() {
+			leader = s
+			break
+		}
+	}
+	require.NotNil(leader)
+	leader.Leave()
+	leader.Shutdown()
+
+	var remaining []*Server
+	for _, s := range servers {
+		if s != leader {
+			remaining = append(remaining, s)
+		}
+	}
+	testutil.WaitForResult(func() (bool, error) {
+		for _, s := range remaining {
+			if s.IsLeader() {
+				return true, nil
+			}
+		}
+		return false, fmt.Errorf("no new leader")
+	}, func(err error) {
+		t.Fatalf("err: %v", err)
+	})
+
+	// The new leader keeps the active key and can read the variable
+	for _, s := range remaining {
+		active, err := s.fsm.State().ActiveRootKeyMeta(nil)
+		require.NoError(err)
+		require.Equal(stored.KeyID, active.KeyID)
+
+		get := &structs.VariablesReadRequest{
+			Path:         sv.Path,
+			QueryOptions: structs.QueryOptions{Region: "global"},
+		}
+		var getResp structs.VariablesReadResponse
+		require.NoError(msgpackrpc.CallWithCodec(rpcClient(t, s), "Variables.Read", get, &getResp))
+		require.NotNil(getResp.Data)
+		require.Equal(sv.Items, getResp.Data.Items)
+	}
+}
+
+func TestVariablesEndpoint_ACL(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	sv := mock.Variable()
+	sv.Path = "app/web"
+	secret := mock.Variable()
+	secret.Path = "app/secret"
+	for _, v := range []*structs.VariableDecrypted{sv, secret} {
+		req := &structs.VariablesUpsertRequest{
+			Var:          v,
+			WriteRequest: structs.WriteRequest{Region: "global", AuthToken: root.SecretID},
+		}
+		var resp structs.VariablesUpsertResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	}
+
+	token := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1000, "vars", `
+namespace "default" {
+	variables {
+		path "app/*" {
+			capabilities = ["list", "read"]
+		}
+		path "app/secret" {
+			capabilities = ["deny"]
+		}
+	}
+}`)
+
+	// Reads are allowed on the granted paths
+	get := &structs.VariablesReadRequest{
+		Path:         sv.Path,
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var getResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp))
+	require.Equal(sv.Items, getResp.Data.Items)
+
+	get.Path = secret.Path
+	getResp = structs.VariablesReadResponse{}
+	err := msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Only the listable variables are listed
+	list := &structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var listResp structs.VariablesListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.List", list, &listResp))
+	require.Len(listResp.Data, 1)
+	require.Equal(sv.Path, listResp.Data[0].Path)
+
+	// Writes are denied
+	req := &structs.VariablesUpsertRequest{
+		Var:          sv,
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: token.SecretID},
+	}
+	var resp structs.VariablesUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+}
+
+func TestVariablesEndpoint_WorkloadIdentity(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	alloc := mock.Alloc()
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	state := s1.fsm.State()
+	require.NoError(state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+	require.NoError(state.UpsertAllocs(1000, []*structs.Allocation{alloc}))
+
+	identity, err := s1.encrypter.SignClaims(identityClaims(alloc, "web", time.Now()))
+	require.NoError(err)
+
+	// Create variables for the task, another job and another task
+	paths := []string{
+		fmt.Sprintf("nomad/jobs/%s/web/web", alloc.JobID),
+		"nomad/jobs/other",
+		fmt.Sprintf("nomad/jobs/%s/web/other", alloc.JobID),
+	}
+	for _, path := range paths {
+		sv := mock.Variable()
+		sv.Path = path
+		req := &structs.VariablesUpsertRequest{
+			Var:          sv,
+			WriteRequest: structs.WriteRequest{Region: "global", AuthToken: root.SecretID},
+		}
+		var resp structs.VariablesUpsertResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	}
+
+	// The task can only read its own variables
+	get := &structs.VariablesReadRequest{
+		Path:         paths[0],
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: identity},
+	}
+	var getResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp))
+	require.NotNil(getResp.Data)
+
+	for _, path := range paths[1:] {
+		get.Path = path
+		getResp = structs.VariablesReadResponse{}
+		err := msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp)
+		require.EqualError(err, structs.ErrPermissionDenied.Error(), path)
+	}
+
+	// Tampered identities are rejected
+	get.Path = paths[0]
+	get.AuthToken = identity[:len(identity)-4] + "AAAA"
+	getResp = structs.VariablesReadResponse{}
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp)
+	require.EqualError(err, structs.ErrTokenNotFound.Error())
+
+	// The identity is rejected once the allocation is terminal
+	stopped := alloc.Copy()
+	stopped.ClientStatus = structs.AllocClientStatusComplete
+	require.NoError(state.UpdateAllocsFromClient(1001, []*structs.Allocation{stopped}))
+	get.AuthToken = identity
+	getResp = structs.VariablesReadResponse{}
+	err = msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp)
+	require.EqualError(err, structs.ErrTokenNotFound.Error())
+}
+
+func TestKeyringEndpoint_Rotate(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1, root := TestACLServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Store a variable with the initial key
+	sv := mock.Variable()
+	req := &structs.VariablesUpsertRequest{
+		Var:          sv,
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: root.SecretID},
+	}
+	var resp structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	initial, err := s1.fsm.State().ActiveRootKeyMeta(nil)
+	require.NoError(err)
+
+	// Operator write is required
+	token := mock.CreatePolicyAndToken(t, s1.fsm.State(), 1000, "operator", `operator { policy = "read" }`)
+	rotate := &structs.KeyringRotateRequest{
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: token.SecretID},
+	}
+	var rotateResp structs.KeyringRotateResponse
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotate, &rotateResp)
+	require.EqualError(err, structs.ErrPermissionDenied.Error())
+
+	// Rotating keeps the variable readable with the previous key
+	rotate.AuthToken = root.SecretID
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotate, &rotateResp))
+	require.NotEqual(initial.KeyID, rotateResp.Key.KeyID)
+	require.True(rotateResp.Key.Active)
+
+	get := &structs.VariablesReadRequest{
+		Path:         sv.Path,
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: root.SecretID},
+	}
+	var getResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp))
+	require.Equal(sv.Items, getResp.Data.Items)
+
+	stored, err := s1.fsm.State().GetVariable(nil, structs.DefaultNamespace, sv.Path)
+	require.NoError(err)
+	require.Equal(initial.KeyID, stored.KeyID)
+
+	// A full rotation re-encrypts the variable with the new key
+	rotate.Full = true
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotate, &rotateResp))
+	stored, err = s1.fsm.State().GetVariable(nil, structs.DefaultNamespace, sv.Path)
+	require.NoError(err)
+	require.Equal(rotateResp.Key.KeyID, stored.KeyID)
+	getResp = structs.VariablesReadResponse{}
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp))
+	require.Equal(sv.Items, getResp.Data.Items)
+
+	// All the keys are listed
+	list := &structs.KeyringListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var listResp structs.KeyringListResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.List", list, &listResp))
+	require.Len(listResp.Keys, 3)
+	active := 0
+	for _, key := range listResp.Keys {
+		if key.Active {
+			active++
+			require.Equal(rotateResp.Key.KeyID, key.KeyID)
+		}
+	}
+	require.Equal(1, active)
+}
+
+func TestKeyringEndpoint_Rotate_ConcurrentWrites(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+	s1 := TestServer(t, nil)
+	defer s1.Shutdown()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Store two variables with the initial key
+	deleted, updated := mock.Variable(), mock.Variable()
+	for _, sv := range []*structs.VariableDecrypted{deleted, updated} {
+		req := &structs.VariablesUpsertRequest{
+			Var:          sv,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.VariablesUpsertResponse
+		require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+	}
+
+	// A full rotation reads the variables before re-encrypting them
+	var stale []*structs.VariableEncrypted
+	iter, err := s1.fsm.State().Variables(nil)
+	require.NoError(err)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		stale = append(stale, raw.(*structs.VariableEncrypted))
+	}
+	require.Len(stale, 2)
+
+	rotate := &structs.KeyringRotateRequest{
+		Full:         true,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var rotateResp structs.KeyringRotateResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Keyring.Rotate", rotate, &rotateResp))
+
+	// Delete and update the variables in the meantime
+	del := &structs.VariablesDeleteRequest{
+		Path:         deleted.Path,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var delResp structs.GenericResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Delete", del, &delResp))
+
+	updated.Items = map[string]string{"password": "correct horse"}
+	req := &structs.VariablesUpsertRequest{
+		Var:          updated,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.VariablesUpsertResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Upsert", req, &resp))
+
+	// Re-encrypting the variables as read neither restores the deleted one
+	// nor overwrites the update
+	keyring := &Keyring{srv: s1}
+	for _, sv := range stale {
+		index, err := keyring.reencryptVariable(sv)
+		require.NoError(err)
+		require.Zero(index)
+	}
+
+	out, err := s1.fsm.State().GetVariable(nil, structs.DefaultNamespace, deleted.Path)
+	require.NoError(err)
+	require.Nil(out)
+
+	get := &structs.VariablesReadRequest{
+		Path:         updated.Path,
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.VariablesReadResponse
+	require.NoError(msgpackrpc.CallWithCodec(codec, "Variables.Read", get, &getResp))
+	require.Equal(updated.Items, getResp.Data.Items)
+	require.Equal(resp.Output.ModifyIndex, getResp.Data.ModifyIndex)
+}
@@ -0,0 +1,336 @@
+package base
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	baseplugin "github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/drivers/base/proto"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+	netctx "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ DriverPlugin = &driverPluginClient{}
+
+// driverPluginClient implements the client side of a remote driver plugin,
+// using gRPC to communicate to the remote plugin.
+type driverPluginClient struct {
+	// BasePluginClient is embedded to give access to the base plugin methods.
+	*baseplugin.BasePluginClient
+
+	client proto.DriverClient
+}
+
+func (d *driverPluginClient) TaskConfigSchema() (*hclspec.Spec, error) {
+	resp, err := d.client.TaskConfigSchema(context.Background(), &proto.TaskConfigSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetSpec(), nil
+}
+
+func (d *driverPluginClient) Capabilities() (*Capabilities, error) {
+	resp, err := d.client.Capabilities(context.Background(), &proto.CapabilitiesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	caps := &Capabilities{}
+	if c := resp.GetCapabilities(); c != nil {
+		caps.SendSignals = c.SendSignals
+		caps.Exec = c.Exec
+		caps.FSIsolation = fsIsolationFromProtoMap[c.FsIsolation]
+	}
+
+	return caps, nil
+}
+
+// Fingerprint is used to retrieve the health and attributes of the driver.
+// An error may be immediately returned if the fingerprint call could not be
+// made or as part of the streaming response. If the context is cancelled, the
+// error will be propogated.
+func (d *driverPluginClient) Fingerprint(ctx context.Context) (<-chan *Fingerprint, error) {
+	stream, err := d.client.Fingerprint(ctx, &proto.FingerprintRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *Fingerprint, 1)
+	go d.handleFingerprint(ctx, stream, out)
+	return out, nil
+}
+
+// handleFingerprint should be launched in a goroutine and handles converting
+// the gRPC stream to a channel. Exits either when context is cancelled or the
+// stream has an error.
+func (d *driverPluginClient) handleFingerprint(
+	ctx netctx.Context,
+	stream proto.Driver_FingerprintClient,
+	out chan *Fingerprint) {
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			// Handle a non-graceful stream error
+			if err != io.EOF {
+				if errStatus := status.FromContextError(ctx.Err()); errStatus.Code() == codes.Canceled {
+					err = context.Canceled
+				}
+
+				out <- &Fingerprint{
+					Err: err,
+				}
+			}
+
+			// End the stream
+			close(out)
+			return
+		}
+
+		// Send the response
+		out <- &Fingerprint{
+			Attributes:        resp.GetAttributes(),
+			Health:            healthStateFromProtoMap[resp.GetHealth()],
+			HealthDescription: resp.GetHealthDescription(),
+		}
+	}
+}
+
+func (d *driverPluginClient) RecoverTask(h *TaskHandle) error {
+	req := &proto.RecoverTaskRequest{
+		Handle: taskHandleToProto(h),
+	}
+	if h != nil && h.Config != nil {
+		req.TaskId = h.Config.ID
+	}
+
+	_, err := d.client.RecoverTask(context.Background(), req)
+	return err
+}
+
+func (d *driverPluginClient) StartTask(c *TaskConfig) (*TaskHandle, error) {
+	req := &proto.StartTaskRequest{
+		Task: taskConfigToProto(c),
+	}
+
+	resp, err := d.client.StartTask(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.GetResult() {
+	case proto.StartTaskResponse_SUCCESS:
+		return taskHandleFromProto(resp.GetHandle()), nil
+	case proto.StartTaskResponse_RETRY:
+		return nil, structs.NewRecoverableError(errors.New(resp.GetDriverErrorMsg()), true)
+	default:
+		return nil, errors.New(resp.GetDriverErrorMsg())
+	}
+}
+
+// WaitTask returns a channel that will have an ExitResult pushed to it once
+// the task exits. The channel is closed after the result is sent.
+func (d *driverPluginClient) WaitTask(ctx context.Context, taskID string) (<-chan *ExitResult, error) {
+	ch := make(chan *ExitResult, 1)
+	go d.handleWaitTask(ctx, taskID, ch)
+	return ch, nil
+}
+
+func (d *driverPluginClient) handleWaitTask(ctx context.Context, taskID string, ch chan *ExitResult) {
+	defer close(ch)
+
+	resp, err := d.client.WaitTask(ctx, &proto.WaitTaskRequest{TaskId: taskID})
+	if err != nil {
+		ch <- &ExitResult{Err: err}
+		return
+	}
+
+	result := exitResultFromProto(resp.GetResult())
+	if result == nil {
+		result = &ExitResult{}
+	}
+	result.Err = errFromString(resp.GetErr())
+	ch <- result
+}
+
+func (d *driverPluginClient) StopTask(taskID string, timeout time.Duration, signal string) error {
+	req := &proto.StopTaskRequest{
+		TaskId:  taskID,
+		Timeout: ptypes.DurationProto(timeout),
+		Signal:  signal,
+	}
+
+	_, err := d.client.StopTask(context.Background(), req)
+	return err
+}
+
+func (d *driverPluginClient) DestroyTask(taskID string) error {
+	req := &proto.DestroyTaskRequest{
+		TaskId: taskID,
+	}
+
+	_, err := d.client.DestroyTask(context.Background(), req)
+	return err
+}
+
+func (d *driverPluginClient) InspectTask(taskID string) (*TaskStatus, error) {
+	resp, err := d.client.InspectTask(context.Background(), &proto.InspectTaskRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := taskStatusFromProto(resp.GetTask())
+	if err != nil {
+		return nil, err
+	}
+
+	if out != nil && resp.GetDriver() != nil {
+		out.DriverAttributes = resp.GetDriver().GetAttributes()
+	}
+
+	return out, nil
+}
+
+func (d *driverPluginClient) TaskStats(taskID string) (*cstructs.TaskResourceUsage, error) {
+	resp, err := d.client.TaskStats(context.Background(), &proto.TaskStatsRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+
+	return taskStatsFromProto(resp.GetStats())
+}
+
+// TaskEvents returns a channel that will receive events from the driver about
+// all the tasks it manages. If the context is cancelled, the error will be
+// propogated.
+func (d *driverPluginClient) TaskEvents(ctx context.Context) (<-chan *TaskEvent, error) {
+	stream, err := d.client.TaskEvents(ctx, &proto.TaskEventsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *TaskEvent, 1)
+	go d.handleTaskEvents(ctx, stream, out)
+	return out, nil
+}
+
+func (d *driverPluginClient) handleTaskEvents(
+	ctx netctx.Context,
+	stream proto.Driver_TaskEventsClient,
+	out chan *TaskEvent) {
+
+	for {
+		ev, err := stream.Recv()
+		if err != nil {
+			// Handle a non-graceful stream error
+			if err != io.EOF {
+				if errStatus := status.FromContextError(ctx.Err()); errStatus.Code() == codes.Canceled {
+					err = context.Canceled
+				}
+
+				out <- &TaskEvent{
+					Err: err,
+				}
+			}
+
+			// End the stream
+			close(out)
+			return
+		}
+
+		event := &TaskEvent{
+			TaskID:      ev.GetTaskId(),
+			Message:     ev.GetMessage(),
+			Annotations: ev.GetAnnotations(),
+		}
+		if ev.GetTimestamp() != nil {
+			if ts, err := ptypes.Timestamp(ev.GetTimestamp()); err == nil {
+				event.Timestamp = ts
+			}
+		}
+
+		out <- event
+	}
+}
+
+// TaskLogs returns a channel that will receive the output of the task. The
+// channel is closed when the task exits. If the context is cancelled, the
+// error will be propogated.
+func (d *driverPluginClient) TaskLogs(ctx context.Context, taskID string) (<-chan *TaskLog, error) {
+	stream, err := d.client.TaskLogs(ctx, &proto.TaskLogsRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *TaskLog, 1)
+	go d.handleTaskLogs(ctx, stream, out)
+	return out, nil
+}
+
+func (d *driverPluginClient) handleTaskLogs(
+	ctx netctx.Context,
+	stream proto.Driver_TaskLogsClient,
+	out chan *TaskLog) {
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			// Handle a non-graceful stream error
+			if err != io.EOF {
+				if errStatus := status.FromContextError(ctx.Err()); errStatus.Code() == codes.Canceled {
+					err = context.Canceled
+				}
+
+				out <- &TaskLog{
+					Err: err,
+				}
+			}
+
+			// End the stream
+			close(out)
+			return
+		}
+
+		out <- &TaskLog{
+			Stream: logStreamFromProtoMap[resp.GetStream()],
+			Data:   resp.GetData(),
+		}
+	}
+}
+
+func (d *driverPluginClient) SignalTask(taskID string, signal string) error {
+	req := &proto.SignalTaskRequest{
+		TaskId: taskID,
+		Signal: signal,
+	}
+
+	_, err := d.client.SignalTask(context.Background(), req)
+	return err
+}
+
+func (d *driverPluginClient) ExecTask(taskID string, cmd []string, timeout time.Duration) (*ExecTaskResult, error) {
+	req := &proto.ExecTaskRequest{
+		TaskId:  taskID,
+		Command: cmd,
+		Timeout: ptypes.DurationProto(timeout),
+	}
+
+	resp, err := d.client.ExecTask(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExecTaskResult{
+		Stdout:     resp.GetStdout(),
+		Stderr:     resp.GetStderr(),
+		ExitResult: exitResultFromProto(resp.GetResult()),
+	}, nil
+}
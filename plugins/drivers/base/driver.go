@@ -0,0 +1,285 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	baseplugin "github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+// DriverPlugin is the interface with drivers will implement. It is also
+// implemented by a plugin client which proxies the calls to go-plugin. See
+// the proto/driver.proto file for detailed information about each RPC and
+// message structure.
+type DriverPlugin interface {
+	baseplugin.BasePlugin
+
+	// TaskConfigSchema returns the schema for parsing the driver configuration
+	// of a task.
+	TaskConfigSchema() (*hclspec.Spec, error)
+
+	// Capabilities returns the set of features the driver implements.
+	Capabilities() (*Capabilities, error)
+
+	// Fingerprint returns a stream of driver health and attributes. The first
+	// response is sent immediately and further responses are only sent when
+	// the state of the driver changes.
+	Fingerprint(context.Context) (<-chan *Fingerprint, error)
+
+	// RecoverTask re-attaches to a task that was started prior to the client
+	// or driver restarting.
+	RecoverTask(*TaskHandle) error
+
+	// StartTask starts the task and returns a handle that must be stored by
+	// the client in order to recover the task.
+	StartTask(*TaskConfig) (*TaskHandle, error)
+
+	// WaitTask returns a channel that receives the exit result of the task.
+	WaitTask(ctx context.Context, taskID string) (<-chan *ExitResult, error)
+
+	// StopTask stops the task, sending the given signal and forcefully killing
+	// it if it has not exited within the timeout.
+	StopTask(taskID string, timeout time.Duration, signal string) error
+
+	// DestroyTask removes the task from the driver's state and cleans up any
+	// resources created for it. It can not be called on a running task.
+	DestroyTask(taskID string) error
+
+	// InspectTask returns detailed status information about the task.
+	InspectTask(taskID string) (*TaskStatus, error)
+
+	// TaskStats returns the resource usage of the task.
+	TaskStats(taskID string) (*cstructs.TaskResourceUsage, error)
+
+	// TaskEvents returns a stream of events emitted by the driver.
+	TaskEvents(context.Context) (<-chan *TaskEvent, error)
+
+	// TaskLogs returns a stream of the output of the task. The stream ends
+	// when the task exits.
+	TaskLogs(ctx context.Context, taskID string) (<-chan *TaskLog, error)
+
+	// SignalTask sends a signal to the task. Only implemented if the driver
+	// advertises the SendSignals capability.
+	SignalTask(taskID string, signal string) error
+
+	// ExecTask runs a command inside the task's execution environment. Only
+	// implemented if the driver advertises the Exec capability.
+	ExecTask(taskID string, cmd []string, timeout time.Duration) (*ExecTaskResult, error)
+}
+
+// DriverSignalTaskNotSupported can be embedded by drivers which don't support
+// the SignalTask RPC.
+type DriverSignalTaskNotSupported struct{}
+
+func (DriverSignalTaskNotSupported) SignalTask(taskID, signal string) error {
+	return fmt.Errorf("SignalTask is not supported by this driver")
+}
+
+// DriverExecTaskNotSupported can be embedded by drivers which don't support
+// the ExecTask RPC.
+type DriverExecTaskNotSupported struct{}
+
+func (DriverExecTaskNotSupported) ExecTask(taskID string, cmd []string, timeout time.Duration) (*ExecTaskResult, error) {
+	return nil, fmt.Errorf("ExecTask is not supported by this driver")
+}
+
+// DriverTaskLogsNotSupported can be embedded by drivers which don't support
+// the TaskLogs RPC, such as drivers writing the output of their tasks to the
+// log directory of the allocation themselves.
+type DriverTaskLogsNotSupported struct{}
+
+func (DriverTaskLogsNotSupported) TaskLogs(ctx context.Context, taskID string) (<-chan *TaskLog, error) {
+	return nil, fmt.Errorf("TaskLogs is not supported by this driver")
+}
+
+// HealthState is the health of a driver as reported by fingerprinting.
+type HealthState string
+
+const (
+	// HealthStateUndetected is used when the driver's dependencies are not
+	// met and the driver can not be used.
+	HealthStateUndetected = HealthState("undetected")
+
+	// HealthStateUnhealthy is used when the driver is detected but is unable
+	// to perform operations.
+	HealthStateUnhealthy = HealthState("unhealthy")
+
+	// HealthStateHealthy is used when the driver is fully functional.
+	HealthStateHealthy = HealthState("healthy")
+)
+
+// Fingerprint is a single fingerprint result emitted by a driver.
+type Fingerprint struct {
+	// Attributes are added to the node and may be used in constraints.
+	Attributes map[string]string
+
+	// Health is the current health of the driver.
+	Health HealthState
+
+	// HealthDescription is a human readable description of Health.
+	HealthDescription string
+
+	// Err is set if the fingerprint stream encountered an error.
+	Err error
+}
+
+// FSIsolation is the type of filesystem isolation a driver provides.
+type FSIsolation string
+
+const (
+	FSIsolationNone   = FSIsolation("none")
+	FSIsolationChroot = FSIsolation("chroot")
+	FSIsolationImage  = FSIsolation("image")
+)
+
+// Capabilities is the set of optional features a driver implements.
+type Capabilities struct {
+	// SendSignals marks the driver as being able to send signals
+	SendSignals bool
+
+	// Exec marks the driver as being able to execute arbitrary commands
+	// such as health checks. Used by the ScriptExecutor interface.
+	Exec bool
+
+	// FSIsolation indicates what kind of filesystem isolation the driver
+	// supports.
+	FSIsolation FSIsolation
+}
+
+// TaskConfig is the configuration used to start a task.
+type TaskConfig struct {
+	ID        string
+	Name      string
+	Env       map[string]string
+	Resources *Resources
+	Mounts    []*MountConfig
+	Devices   []*DeviceConfig
+	User      string
+	AllocDir  string
+
+	// rawDriverConfig is the MessagePack encoding of the task's driver
+	// config stanza.
+	rawDriverConfig []byte
+}
+
+// EncodeDriverConfig encodes the given value as the driver config of the task.
+func (tc *TaskConfig) EncodeDriverConfig(val interface{}) error {
+	return baseplugin.MsgPackEncode(&tc.rawDriverConfig, val)
+}
+
+// DecodeDriverConfig decodes the driver config of the task into t.
+func (tc *TaskConfig) DecodeDriverConfig(t interface{}) error {
+	return baseplugin.MsgPackDecode(tc.rawDriverConfig, t)
+}
+
+// Resources are the resources allocated to a task.
+type Resources struct {
+	CPU      int64
+	MemoryMB int64
+	DiskMB   int64
+	IOPS     int64
+
+	// Linux holds computed values for Linux specific isolation features.
+	Linux *LinuxResources
+}
+
+// LinuxResources are computed values used for cgroup based isolation.
+type LinuxResources struct {
+	CPUPeriod        int64
+	CPUQuota         int64
+	CPUShares        int64
+	MemoryLimitBytes int64
+	OOMScoreAdj      int64
+	CpusetCPUs       string
+	CpusetMems       string
+}
+
+// MountConfig describes a host path to mount into the task.
+type MountConfig struct {
+	TaskPath string
+	HostPath string
+	Readonly bool
+}
+
+// DeviceConfig describes a host device to expose to the task.
+type DeviceConfig struct {
+	TaskPath    string
+	HostPath    string
+	Permissions string
+}
+
+// TaskState is the execution state of a task.
+type TaskState string
+
+const (
+	TaskStateUnknown = TaskState("unknown")
+	TaskStateRunning = TaskState("running")
+	TaskStateExited  = TaskState("exited")
+)
+
+// TaskHandle is the opaque handle returned by StartTask that is used to
+// recover the task.
+type TaskHandle struct {
+	Config      *TaskConfig
+	State       TaskState
+	DriverState []byte
+}
+
+// ExitResult is the result of a task exiting.
+type ExitResult struct {
+	ExitCode  int
+	Signal    int
+	OOMKilled bool
+	Err       error
+}
+
+// Successful returns whether the task exited cleanly.
+func (r *ExitResult) Successful() bool {
+	return r.ExitCode == 0 && r.Signal == 0 && r.Err == nil
+}
+
+// TaskStatus is detailed information about a task.
+type TaskStatus struct {
+	ID               string
+	Name             string
+	State            TaskState
+	SizeOnDiskMB     int64
+	StartedAt        time.Time
+	CompletedAt      time.Time
+	ExitResult       *ExitResult
+	DriverAttributes map[string]string
+}
+
+// TaskEvent is an event emitted by the driver for a task.
+type TaskEvent struct {
+	TaskID      string
+	Timestamp   time.Time
+	Message     string
+	Annotations map[string]string
+	Err         error
+}
+
+// LogStream is the output stream of a task.
+type LogStream string
+
+const (
+	LogStreamStdout = LogStream("stdout")
+	LogStreamStderr = LogStream("stderr")
+)
+
+// TaskLog is output written by a task.
+type TaskLog struct {
+	Stream LogStream
+	Data   []byte
+	Err    error
+}
+
+// ExecTaskResult is the result of running a command in a task.
+type ExecTaskResult struct {
+	Stdout     []byte
+	Stderr     []byte
+	ExitResult *ExitResult
+}
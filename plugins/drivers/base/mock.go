@@ -0,0 +1,66 @@
+package base
+
+import (
+	"context"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	baseplugin "github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+// MockDriverPlugin is used for testing.
+// Each function can be set as a closure to make assertions about how data
+// is passed through the base plugin layer.
+type MockDriverPlugin struct {
+	*baseplugin.MockPlugin
+	TaskConfigSchemaF func() (*hclspec.Spec, error)
+	CapabilitiesF     func() (*Capabilities, error)
+	FingerprintF      func(context.Context) (<-chan *Fingerprint, error)
+	RecoverTaskF      func(*TaskHandle) error
+	StartTaskF        func(*TaskConfig) (*TaskHandle, error)
+	WaitTaskF         func(context.Context, string) (<-chan *ExitResult, error)
+	StopTaskF         func(string, time.Duration, string) error
+	DestroyTaskF      func(string) error
+	InspectTaskF      func(string) (*TaskStatus, error)
+	TaskStatsF        func(string) (*cstructs.TaskResourceUsage, error)
+	TaskEventsF       func(context.Context) (<-chan *TaskEvent, error)
+	TaskLogsF         func(context.Context, string) (<-chan *TaskLog, error)
+	SignalTaskF       func(string, string) error
+	ExecTaskF         func(string, []string, time.Duration) (*ExecTaskResult, error)
+}
+
+func (p *MockDriverPlugin) TaskConfigSchema() (*hclspec.Spec, error) { return p.TaskConfigSchemaF() }
+func (p *MockDriverPlugin) Capabilities() (*Capabilities, error)     { return p.CapabilitiesF() }
+func (p *MockDriverPlugin) Fingerprint(ctx context.Context) (<-chan *Fingerprint, error) {
+	return p.FingerprintF(ctx)
+}
+func (p *MockDriverPlugin) RecoverTask(h *TaskHandle) error { return p.RecoverTaskF(h) }
+func (p *MockDriverPlugin) StartTask(c *TaskConfig) (*TaskHandle, error) {
+	return p.StartTaskF(c)
+}
+func (p *MockDriverPlugin) WaitTask(ctx context.Context, id string) (<-chan *ExitResult, error) {
+	return p.WaitTaskF(ctx, id)
+}
+func (p *MockDriverPlugin) StopTask(id string, timeout time.Duration, signal string) error {
+	return p.StopTaskF(id, timeout, signal)
+}
+func (p *MockDriverPlugin) DestroyTask(id string) error { return p.DestroyTaskF(id) }
+func (p *MockDriverPlugin) InspectTask(id string) (*TaskStatus, error) {
+	return p.InspectTaskF(id)
+}
+func (p *MockDriverPlugin) TaskStats(id string) (*cstructs.TaskResourceUsage, error) {
+	return p.TaskStatsF(id)
+}
+func (p *MockDriverPlugin) TaskEvents(ctx context.Context) (<-chan *TaskEvent, error) {
+	return p.TaskEventsF(ctx)
+}
+func (p *MockDriverPlugin) TaskLogs(ctx context.Context, id string) (<-chan *TaskLog, error) {
+	return p.TaskLogsF(ctx, id)
+}
+func (p *MockDriverPlugin) SignalTask(id string, signal string) error {
+	return p.SignalTaskF(id, signal)
+}
+func (p *MockDriverPlugin) ExecTask(id string, cmd []string, timeout time.Duration) (*ExecTaskResult, error) {
+	return p.ExecTaskF(id, cmd, timeout)
+}
@@ -0,0 +1,49 @@
+package base
+
+import (
+	"context"
+
+	log "github.com/hashicorp/go-hclog"
+	plugin "github.com/hashicorp/go-plugin"
+	baseplugin "github.com/hashicorp/nomad/plugins/base"
+	bproto "github.com/hashicorp/nomad/plugins/base/proto"
+	"github.com/hashicorp/nomad/plugins/drivers/base/proto"
+	"google.golang.org/grpc"
+)
+
+// PluginDriver wraps a DriverPlugin and implements go-plugins GRPCPlugin
+// interface to expose the interface over gRPC.
+type PluginDriver struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl DriverPlugin
+}
+
+func (p *PluginDriver) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterDriverServer(s, &driverPluginServer{
+		impl:   p.Impl,
+		broker: broker,
+	})
+	return nil
+}
+
+func (p *PluginDriver) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &driverPluginClient{
+		client: proto.NewDriverClient(c),
+		BasePluginClient: &baseplugin.BasePluginClient{
+			Client: bproto.NewBasePluginClient(c),
+		},
+	}, nil
+}
+
+// Serve is used to serve a driver plugin
+func Serve(d DriverPlugin, logger log.Logger) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: baseplugin.Handshake,
+		Plugins: map[string]plugin.Plugin{
+			baseplugin.PluginTypeBase:   &baseplugin.PluginBase{Impl: d},
+			baseplugin.PluginTypeDriver: &PluginDriver{Impl: d},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+		Logger:     logger,
+	})
+}
@@ -0,0 +1,210 @@
+package base
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/nomad/structs"
+	baseplugin "github.com/hashicorp/nomad/plugins/base"
+	"github.com/stretchr/testify/require"
+)
+
+// testDriverClient returns a client dispensed from an in-memory gRPC
+// connection to the given mock driver.
+func testDriverClient(t *testing.T, mock *MockDriverPlugin) (DriverPlugin, func()) {
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		baseplugin.PluginTypeBase:   &baseplugin.PluginBase{Impl: mock},
+		baseplugin.PluginTypeDriver: &PluginDriver{Impl: mock},
+	})
+
+	raw, err := client.Dispense(baseplugin.PluginTypeDriver)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	impl, ok := raw.(DriverPlugin)
+	if !ok {
+		t.Fatalf("bad: %#v", raw)
+	}
+
+	return impl, func() {
+		client.Close()
+		server.Stop()
+	}
+}
+
+func TestDriverPlugin_Capabilities(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	mock := &MockDriverPlugin{
+		CapabilitiesF: func() (*Capabilities, error) {
+			return &Capabilities{
+				SendSignals: true,
+				FSIsolation: FSIsolationChroot,
+			}, nil
+		},
+	}
+
+	impl, cleanup := testDriverClient(t, mock)
+	defer cleanup()
+
+	caps, err := impl.Capabilities()
+	require.NoError(err)
+	require.True(caps.SendSignals)
+	require.False(caps.Exec)
+	require.Equal(FSIsolationChroot, caps.FSIsolation)
+}
+
+func TestDriverPlugin_StartTask(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	type driverConfig struct {
+		Command string `codec:"command"`
+	}
+
+	mock := &MockDriverPlugin{
+		StartTaskF: func(c *TaskConfig) (*TaskHandle, error) {
+			var dc driverConfig
+			if err := c.DecodeDriverConfig(&dc); err != nil {
+				return nil, err
+			}
+
+			switch dc.Command {
+			case "retry":
+				return nil, structs.NewRecoverableError(fmt.Errorf("try again"), true)
+			case "fail":
+				return nil, fmt.Errorf("failed")
+			}
+
+			return &TaskHandle{
+				Config:      c,
+				State:       TaskStateRunning,
+				DriverState: []byte(dc.Command),
+			}, nil
+		},
+	}
+
+	impl, cleanup := testDriverClient(t, mock)
+	defer cleanup()
+
+	cfg := &TaskConfig{
+		ID:   "foo",
+		Name: "web",
+		Resources: &Resources{
+			CPU:      100,
+			MemoryMB: 256,
+		},
+	}
+	require.NoError(cfg.EncodeDriverConfig(&driverConfig{Command: "/bin/date"}))
+
+	handle, err := impl.StartTask(cfg)
+	require.NoError(err)
+	require.Equal(TaskStateRunning, handle.State)
+	require.Equal("foo", handle.Config.ID)
+	require.Equal(int64(256), handle.Config.Resources.MemoryMB)
+	require.Equal([]byte("/bin/date"), handle.DriverState)
+
+	// Recoverable errors are preserved over the wire
+	require.NoError(cfg.EncodeDriverConfig(&driverConfig{Command: "retry"}))
+	_, err = impl.StartTask(cfg)
+	require.Error(err)
+	require.True(structs.IsRecoverable(err))
+
+	require.NoError(cfg.EncodeDriverConfig(&driverConfig{Command: "fail"}))
+	_, err = impl.StartTask(cfg)
+	require.Error(err)
+	require.False(structs.IsRecoverable(err))
+}
+
+func TestDriverPlugin_WaitTask(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	mock := &MockDriverPlugin{
+		WaitTaskF: func(ctx context.Context, id string) (<-chan *ExitResult, error) {
+			ch := make(chan *ExitResult, 1)
+			ch <- &ExitResult{
+				ExitCode:  137,
+				OOMKilled: true,
+			}
+			return ch, nil
+		},
+	}
+
+	impl, cleanup := testDriverClient(t, mock)
+	defer cleanup()
+
+	ch, err := impl.WaitTask(context.Background(), "foo")
+	require.NoError(err)
+
+	select {
+	case result := <-ch:
+		require.NoError(result.Err)
+		require.Equal(137, result.ExitCode)
+		require.True(result.OOMKilled)
+		require.False(result.Successful())
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for exit result")
+	}
+}
+
+func TestDriverPlugin_TaskLogs(t *testing.T) {
+	t.Parallel()
+	require := require.New(t)
+
+	mock := &MockDriverPlugin{
+		TaskLogsF: func(ctx context.Context, id string) (<-chan *TaskLog, error) {
+			if id != "foo" {
+				return nil, fmt.Errorf("unknown task %q", id)
+			}
+			ch := make(chan *TaskLog, 2)
+			ch <- &TaskLog{Stream: LogStreamStdout, Data: []byte("hello")}
+			ch <- &TaskLog{Stream: LogStreamStderr, Data: []byte("world")}
+			close(ch)
+			return ch, nil
+		},
+	}
+
+	impl, cleanup := testDriverClient(t, mock)
+	defer cleanup()
+
+	ch, err := impl.TaskLogs(context.Background(), "foo")
+	require.NoError(err)
+
+	var logs []*TaskLog
+	timeout := time.After(5 * time.Second)
+	for done := false; !done; {
+		select {
+		case log, ok := <-ch:
+			if !ok {
+				done = true
+				break
+			}
+			require.NoError(log.Err)
+			logs = append(logs, log)
+		case <-timeout:
+			t.Fatalf("timeout waiting for task logs")
+		}
+	}
+
+	require.Equal([]*TaskLog{
+		{Stream: LogStreamStdout, Data: []byte("hello")},
+		{Stream: LogStreamStderr, Data: []byte("world")},
+	}, logs)
+
+	// Errors of unknown tasks are returned on the stream
+	ch, err = impl.TaskLogs(context.Background(), "bar")
+	require.NoError(err)
+	select {
+	case log := <-ch:
+		require.Error(log.Err)
+		require.Contains(log.Err.Error(), "unknown task")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout waiting for task logs error")
+	}
+}
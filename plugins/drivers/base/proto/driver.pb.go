@@ -49,7 +49,7 @@ func (x TaskState) String() string {
 	return proto.EnumName(TaskState_name, int32(x))
 }
 func (TaskState) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{0}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{0}
 }
 
 type FingerprintResponse_HealthState int32
@@ -75,7 +75,7 @@ func (x FingerprintResponse_HealthState) String() string {
 	return proto.EnumName(FingerprintResponse_HealthState_name, int32(x))
 }
 func (FingerprintResponse_HealthState) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{5, 0}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{5, 0}
 }
 
 type StartTaskResponse_Result int32
@@ -101,7 +101,7 @@ func (x StartTaskResponse_Result) String() string {
 	return proto.EnumName(StartTaskResponse_Result_name, int32(x))
 }
 func (StartTaskResponse_Result) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{9, 0}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{9, 0}
 }
 
 type DriverCapabilities_FSIsolation int32
@@ -127,7 +127,7 @@ func (x DriverCapabilities_FSIsolation) String() string {
 	return proto.EnumName(DriverCapabilities_FSIsolation_name, int32(x))
 }
 func (DriverCapabilities_FSIsolation) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{27, 0}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{27, 0}
 }
 
 type CPUUsage_Fields int32
@@ -162,7 +162,7 @@ func (x CPUUsage_Fields) String() string {
 	return proto.EnumName(CPUUsage_Fields_name, int32(x))
 }
 func (CPUUsage_Fields) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{43, 0}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{43, 0}
 }
 
 type MemoryUsage_Fields int32
@@ -194,7 +194,30 @@ func (x MemoryUsage_Fields) String() string {
 	return proto.EnumName(MemoryUsage_Fields_name, int32(x))
 }
 func (MemoryUsage_Fields) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{44, 0}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{44, 0}
+}
+
+type TaskLogsResponse_Stream int32
+
+const (
+	TaskLogsResponse_STDOUT TaskLogsResponse_Stream = 0
+	TaskLogsResponse_STDERR TaskLogsResponse_Stream = 1
+)
+
+var TaskLogsResponse_Stream_name = map[int32]string{
+	0: "STDOUT",
+	1: "STDERR",
+}
+var TaskLogsResponse_Stream_value = map[string]int32{
+	"STDOUT": 0,
+	"STDERR": 1,
+}
+
+func (x TaskLogsResponse_Stream) String() string {
+	return proto.EnumName(TaskLogsResponse_Stream_name, int32(x))
+}
+func (TaskLogsResponse_Stream) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_driver_c76ff134863d81c0, []int{47, 0}
 }
 
 type TaskConfigSchemaRequest struct {
@@ -207,7 +230,7 @@ func (m *TaskConfigSchemaRequest) Reset()         { *m = TaskConfigSchemaRequest
 func (m *TaskConfigSchemaRequest) String() string { return proto.CompactTextString(m) }
 func (*TaskConfigSchemaRequest) ProtoMessage()    {}
 func (*TaskConfigSchemaRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{0}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{0}
 }
 func (m *TaskConfigSchemaRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskConfigSchemaRequest.Unmarshal(m, b)
@@ -239,7 +262,7 @@ func (m *TaskConfigSchemaResponse) Reset()         { *m = TaskConfigSchemaRespon
 func (m *TaskConfigSchemaResponse) String() string { return proto.CompactTextString(m) }
 func (*TaskConfigSchemaResponse) ProtoMessage()    {}
 func (*TaskConfigSchemaResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{1}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{1}
 }
 func (m *TaskConfigSchemaResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskConfigSchemaResponse.Unmarshal(m, b)
@@ -276,7 +299,7 @@ func (m *CapabilitiesRequest) Reset()         { *m = CapabilitiesRequest{} }
 func (m *CapabilitiesRequest) String() string { return proto.CompactTextString(m) }
 func (*CapabilitiesRequest) ProtoMessage()    {}
 func (*CapabilitiesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{2}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{2}
 }
 func (m *CapabilitiesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CapabilitiesRequest.Unmarshal(m, b)
@@ -311,7 +334,7 @@ func (m *CapabilitiesResponse) Reset()         { *m = CapabilitiesResponse{} }
 func (m *CapabilitiesResponse) String() string { return proto.CompactTextString(m) }
 func (*CapabilitiesResponse) ProtoMessage()    {}
 func (*CapabilitiesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{3}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{3}
 }
 func (m *CapabilitiesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CapabilitiesResponse.Unmarshal(m, b)
@@ -348,7 +371,7 @@ func (m *FingerprintRequest) Reset()         { *m = FingerprintRequest{} }
 func (m *FingerprintRequest) String() string { return proto.CompactTextString(m) }
 func (*FingerprintRequest) ProtoMessage()    {}
 func (*FingerprintRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{4}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{4}
 }
 func (m *FingerprintRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FingerprintRequest.Unmarshal(m, b)
@@ -391,7 +414,7 @@ func (m *FingerprintResponse) Reset()         { *m = FingerprintResponse{} }
 func (m *FingerprintResponse) String() string { return proto.CompactTextString(m) }
 func (*FingerprintResponse) ProtoMessage()    {}
 func (*FingerprintResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{5}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{5}
 }
 func (m *FingerprintResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FingerprintResponse.Unmarshal(m, b)
@@ -446,7 +469,7 @@ func (m *RecoverTaskRequest) Reset()         { *m = RecoverTaskRequest{} }
 func (m *RecoverTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*RecoverTaskRequest) ProtoMessage()    {}
 func (*RecoverTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{6}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{6}
 }
 func (m *RecoverTaskRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RecoverTaskRequest.Unmarshal(m, b)
@@ -490,7 +513,7 @@ func (m *RecoverTaskResponse) Reset()         { *m = RecoverTaskResponse{} }
 func (m *RecoverTaskResponse) String() string { return proto.CompactTextString(m) }
 func (*RecoverTaskResponse) ProtoMessage()    {}
 func (*RecoverTaskResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{7}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{7}
 }
 func (m *RecoverTaskResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RecoverTaskResponse.Unmarshal(m, b)
@@ -522,7 +545,7 @@ func (m *StartTaskRequest) Reset()         { *m = StartTaskRequest{} }
 func (m *StartTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*StartTaskRequest) ProtoMessage()    {}
 func (*StartTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{8}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{8}
 }
 func (m *StartTaskRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StartTaskRequest.Unmarshal(m, b)
@@ -576,7 +599,7 @@ func (m *StartTaskResponse) Reset()         { *m = StartTaskResponse{} }
 func (m *StartTaskResponse) String() string { return proto.CompactTextString(m) }
 func (*StartTaskResponse) ProtoMessage()    {}
 func (*StartTaskResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{9}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{9}
 }
 func (m *StartTaskResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StartTaskResponse.Unmarshal(m, b)
@@ -636,7 +659,7 @@ func (m *WaitTaskRequest) Reset()         { *m = WaitTaskRequest{} }
 func (m *WaitTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*WaitTaskRequest) ProtoMessage()    {}
 func (*WaitTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{10}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{10}
 }
 func (m *WaitTaskRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_WaitTaskRequest.Unmarshal(m, b)
@@ -677,7 +700,7 @@ func (m *WaitTaskResponse) Reset()         { *m = WaitTaskResponse{} }
 func (m *WaitTaskResponse) String() string { return proto.CompactTextString(m) }
 func (*WaitTaskResponse) ProtoMessage()    {}
 func (*WaitTaskResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{11}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{11}
 }
 func (m *WaitTaskResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_WaitTaskResponse.Unmarshal(m, b)
@@ -729,7 +752,7 @@ func (m *StopTaskRequest) Reset()         { *m = StopTaskRequest{} }
 func (m *StopTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*StopTaskRequest) ProtoMessage()    {}
 func (*StopTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{12}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{12}
 }
 func (m *StopTaskRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StopTaskRequest.Unmarshal(m, b)
@@ -780,7 +803,7 @@ func (m *StopTaskResponse) Reset()         { *m = StopTaskResponse{} }
 func (m *StopTaskResponse) String() string { return proto.CompactTextString(m) }
 func (*StopTaskResponse) ProtoMessage()    {}
 func (*StopTaskResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{13}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{13}
 }
 func (m *StopTaskResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StopTaskResponse.Unmarshal(m, b)
@@ -812,7 +835,7 @@ func (m *DestroyTaskRequest) Reset()         { *m = DestroyTaskRequest{} }
 func (m *DestroyTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*DestroyTaskRequest) ProtoMessage()    {}
 func (*DestroyTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{14}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{14}
 }
 func (m *DestroyTaskRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DestroyTaskRequest.Unmarshal(m, b)
@@ -849,7 +872,7 @@ func (m *DestroyTaskResponse) Reset()         { *m = DestroyTaskResponse{} }
 func (m *DestroyTaskResponse) String() string { return proto.CompactTextString(m) }
 func (*DestroyTaskResponse) ProtoMessage()    {}
 func (*DestroyTaskResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{15}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{15}
 }
 func (m *DestroyTaskResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DestroyTaskResponse.Unmarshal(m, b)
@@ -879,7 +902,7 @@ func (m *ListTasksRequest) Reset()         { *m = ListTasksRequest{} }
 func (m *ListTasksRequest) String() string { return proto.CompactTextString(m) }
 func (*ListTasksRequest) ProtoMessage()    {}
 func (*ListTasksRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{16}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{16}
 }
 func (m *ListTasksRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListTasksRequest.Unmarshal(m, b)
@@ -911,7 +934,7 @@ func (m *ListTasksResponse) Reset()         { *m = ListTasksResponse{} }
 func (m *ListTasksResponse) String() string { return proto.CompactTextString(m) }
 func (*ListTasksResponse) ProtoMessage()    {}
 func (*ListTasksResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{17}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{17}
 }
 func (m *ListTasksResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListTasksResponse.Unmarshal(m, b)
@@ -950,7 +973,7 @@ func (m *InspectTaskRequest) Reset()         { *m = InspectTaskRequest{} }
 func (m *InspectTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*InspectTaskRequest) ProtoMessage()    {}
 func (*InspectTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{18}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{18}
 }
 func (m *InspectTaskRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InspectTaskRequest.Unmarshal(m, b)
@@ -993,7 +1016,7 @@ func (m *InspectTaskResponse) Reset()         { *m = InspectTaskResponse{} }
 func (m *InspectTaskResponse) String() string { return proto.CompactTextString(m) }
 func (*InspectTaskResponse) ProtoMessage()    {}
 func (*InspectTaskResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{19}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{19}
 }
 func (m *InspectTaskResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InspectTaskResponse.Unmarshal(m, b)
@@ -1046,7 +1069,7 @@ func (m *TaskStatsRequest) Reset()         { *m = TaskStatsRequest{} }
 func (m *TaskStatsRequest) String() string { return proto.CompactTextString(m) }
 func (*TaskStatsRequest) ProtoMessage()    {}
 func (*TaskStatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{20}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{20}
 }
 func (m *TaskStatsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskStatsRequest.Unmarshal(m, b)
@@ -1085,7 +1108,7 @@ func (m *TaskStatsResponse) Reset()         { *m = TaskStatsResponse{} }
 func (m *TaskStatsResponse) String() string { return proto.CompactTextString(m) }
 func (*TaskStatsResponse) ProtoMessage()    {}
 func (*TaskStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{21}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{21}
 }
 func (m *TaskStatsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskStatsResponse.Unmarshal(m, b)
@@ -1122,7 +1145,7 @@ func (m *TaskEventsRequest) Reset()         { *m = TaskEventsRequest{} }
 func (m *TaskEventsRequest) String() string { return proto.CompactTextString(m) }
 func (*TaskEventsRequest) ProtoMessage()    {}
 func (*TaskEventsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{22}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{22}
 }
 func (m *TaskEventsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskEventsRequest.Unmarshal(m, b)
@@ -1156,7 +1179,7 @@ func (m *SignalTaskRequest) Reset()         { *m = SignalTaskRequest{} }
 func (m *SignalTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*SignalTaskRequest) ProtoMessage()    {}
 func (*SignalTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{23}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{23}
 }
 func (m *SignalTaskRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignalTaskRequest.Unmarshal(m, b)
@@ -1200,7 +1223,7 @@ func (m *SignalTaskResponse) Reset()         { *m = SignalTaskResponse{} }
 func (m *SignalTaskResponse) String() string { return proto.CompactTextString(m) }
 func (*SignalTaskResponse) ProtoMessage()    {}
 func (*SignalTaskResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{24}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{24}
 }
 func (m *SignalTaskResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignalTaskResponse.Unmarshal(m, b)
@@ -1237,7 +1260,7 @@ func (m *ExecTaskRequest) Reset()         { *m = ExecTaskRequest{} }
 func (m *ExecTaskRequest) String() string { return proto.CompactTextString(m) }
 func (*ExecTaskRequest) ProtoMessage()    {}
 func (*ExecTaskRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{25}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{25}
 }
 func (m *ExecTaskRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecTaskRequest.Unmarshal(m, b)
@@ -1294,7 +1317,7 @@ func (m *ExecTaskResponse) Reset()         { *m = ExecTaskResponse{} }
 func (m *ExecTaskResponse) String() string { return proto.CompactTextString(m) }
 func (*ExecTaskResponse) ProtoMessage()    {}
 func (*ExecTaskResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{26}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{26}
 }
 func (m *ExecTaskResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecTaskResponse.Unmarshal(m, b)
@@ -1353,7 +1376,7 @@ func (m *DriverCapabilities) Reset()         { *m = DriverCapabilities{} }
 func (m *DriverCapabilities) String() string { return proto.CompactTextString(m) }
 func (*DriverCapabilities) ProtoMessage()    {}
 func (*DriverCapabilities) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{27}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{27}
 }
 func (m *DriverCapabilities) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DriverCapabilities.Unmarshal(m, b)
@@ -1424,7 +1447,7 @@ func (m *TaskConfig) Reset()         { *m = TaskConfig{} }
 func (m *TaskConfig) String() string { return proto.CompactTextString(m) }
 func (*TaskConfig) ProtoMessage()    {}
 func (*TaskConfig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{28}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{28}
 }
 func (m *TaskConfig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskConfig.Unmarshal(m, b)
@@ -1521,7 +1544,7 @@ func (m *Resources) Reset()         { *m = Resources{} }
 func (m *Resources) String() string { return proto.CompactTextString(m) }
 func (*Resources) ProtoMessage()    {}
 func (*Resources) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{29}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{29}
 }
 func (m *Resources) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Resources.Unmarshal(m, b)
@@ -1570,7 +1593,7 @@ func (m *RawResources) Reset()         { *m = RawResources{} }
 func (m *RawResources) String() string { return proto.CompactTextString(m) }
 func (*RawResources) ProtoMessage()    {}
 func (*RawResources) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{30}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{30}
 }
 func (m *RawResources) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RawResources.Unmarshal(m, b)
@@ -1641,7 +1664,7 @@ func (m *NetworkResource) Reset()         { *m = NetworkResource{} }
 func (m *NetworkResource) String() string { return proto.CompactTextString(m) }
 func (*NetworkResource) ProtoMessage()    {}
 func (*NetworkResource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{31}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{31}
 }
 func (m *NetworkResource) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NetworkResource.Unmarshal(m, b)
@@ -1715,7 +1738,7 @@ func (m *NetworkPort) Reset()         { *m = NetworkPort{} }
 func (m *NetworkPort) String() string { return proto.CompactTextString(m) }
 func (*NetworkPort) ProtoMessage()    {}
 func (*NetworkPort) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{32}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{32}
 }
 func (m *NetworkPort) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NetworkPort.Unmarshal(m, b)
@@ -1773,7 +1796,7 @@ func (m *LinuxResources) Reset()         { *m = LinuxResources{} }
 func (m *LinuxResources) String() string { return proto.CompactTextString(m) }
 func (*LinuxResources) ProtoMessage()    {}
 func (*LinuxResources) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{33}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{33}
 }
 func (m *LinuxResources) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LinuxResources.Unmarshal(m, b)
@@ -1858,7 +1881,7 @@ func (m *Mount) Reset()         { *m = Mount{} }
 func (m *Mount) String() string { return proto.CompactTextString(m) }
 func (*Mount) ProtoMessage()    {}
 func (*Mount) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{34}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{34}
 }
 func (m *Mount) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Mount.Unmarshal(m, b)
@@ -1921,7 +1944,7 @@ func (m *Device) Reset()         { *m = Device{} }
 func (m *Device) String() string { return proto.CompactTextString(m) }
 func (*Device) ProtoMessage()    {}
 func (*Device) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{35}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{35}
 }
 func (m *Device) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Device.Unmarshal(m, b)
@@ -1979,7 +2002,7 @@ func (m *TaskHandle) Reset()         { *m = TaskHandle{} }
 func (m *TaskHandle) String() string { return proto.CompactTextString(m) }
 func (*TaskHandle) ProtoMessage()    {}
 func (*TaskHandle) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{36}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{36}
 }
 func (m *TaskHandle) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskHandle.Unmarshal(m, b)
@@ -2039,7 +2062,7 @@ func (m *NetworkOverride) Reset()         { *m = NetworkOverride{} }
 func (m *NetworkOverride) String() string { return proto.CompactTextString(m) }
 func (*NetworkOverride) ProtoMessage()    {}
 func (*NetworkOverride) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{37}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{37}
 }
 func (m *NetworkOverride) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NetworkOverride.Unmarshal(m, b)
@@ -2097,7 +2120,7 @@ func (m *ExitResult) Reset()         { *m = ExitResult{} }
 func (m *ExitResult) String() string { return proto.CompactTextString(m) }
 func (*ExitResult) ProtoMessage()    {}
 func (*ExitResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{38}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{38}
 }
 func (m *ExitResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExitResult.Unmarshal(m, b)
@@ -2163,7 +2186,7 @@ func (m *TaskStatus) Reset()         { *m = TaskStatus{} }
 func (m *TaskStatus) String() string { return proto.CompactTextString(m) }
 func (*TaskStatus) ProtoMessage()    {}
 func (*TaskStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{39}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{39}
 }
 func (m *TaskStatus) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskStatus.Unmarshal(m, b)
@@ -2245,7 +2268,7 @@ func (m *TaskDriverStatus) Reset()         { *m = TaskDriverStatus{} }
 func (m *TaskDriverStatus) String() string { return proto.CompactTextString(m) }
 func (*TaskDriverStatus) ProtoMessage()    {}
 func (*TaskDriverStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{40}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{40}
 }
 func (m *TaskDriverStatus) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskDriverStatus.Unmarshal(m, b)
@@ -2290,7 +2313,7 @@ func (m *TaskStats) Reset()         { *m = TaskStats{} }
 func (m *TaskStats) String() string { return proto.CompactTextString(m) }
 func (*TaskStats) ProtoMessage()    {}
 func (*TaskStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{41}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{41}
 }
 func (m *TaskStats) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskStats.Unmarshal(m, b)
@@ -2352,7 +2375,7 @@ func (m *TaskResourceUsage) Reset()         { *m = TaskResourceUsage{} }
 func (m *TaskResourceUsage) String() string { return proto.CompactTextString(m) }
 func (*TaskResourceUsage) ProtoMessage()    {}
 func (*TaskResourceUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{42}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{42}
 }
 func (m *TaskResourceUsage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TaskResourceUsage.Unmarshal(m, b)
@@ -2404,7 +2427,7 @@ func (m *CPUUsage) Reset()         { *m = CPUUsage{} }
 func (m *CPUUsage) String() string { return proto.CompactTextString(m) }
 func (*CPUUsage) ProtoMessage()    {}
 func (*CPUUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{43}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{43}
 }
 func (m *CPUUsage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CPUUsage.Unmarshal(m, b)
@@ -2490,7 +2513,7 @@ func (m *MemoryUsage) Reset()         { *m = MemoryUsage{} }
 func (m *MemoryUsage) String() string { return proto.CompactTextString(m) }
 func (*MemoryUsage) ProtoMessage()    {}
 func (*MemoryUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{44}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{44}
 }
 func (m *MemoryUsage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MemoryUsage.Unmarshal(m, b)
@@ -2570,7 +2593,7 @@ func (m *DriverTaskEvent) Reset()         { *m = DriverTaskEvent{} }
 func (m *DriverTaskEvent) String() string { return proto.CompactTextString(m) }
 func (*DriverTaskEvent) ProtoMessage()    {}
 func (*DriverTaskEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_driver_4db9b3af49fa7db9, []int{45}
+	return fileDescriptor_driver_c76ff134863d81c0, []int{45}
 }
 func (m *DriverTaskEvent) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DriverTaskEvent.Unmarshal(m, b)
@@ -2618,6 +2641,93 @@ func (m *DriverTaskEvent) GetAnnotations() map[string]string {
 	return nil
 }
 
+type TaskLogsRequest struct {
+	// TaskId is the ID of the target task
+	TaskId               string   `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskLogsRequest) Reset()         { *m = TaskLogsRequest{} }
+func (m *TaskLogsRequest) String() string { return proto.CompactTextString(m) }
+func (*TaskLogsRequest) ProtoMessage()    {}
+func (*TaskLogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_c76ff134863d81c0, []int{46}
+}
+func (m *TaskLogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskLogsRequest.Unmarshal(m, b)
+}
+func (m *TaskLogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskLogsRequest.Marshal(b, m, deterministic)
+}
+func (dst *TaskLogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskLogsRequest.Merge(dst, src)
+}
+func (m *TaskLogsRequest) XXX_Size() int {
+	return xxx_messageInfo_TaskLogsRequest.Size(m)
+}
+func (m *TaskLogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskLogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskLogsRequest proto.InternalMessageInfo
+
+func (m *TaskLogsRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+type TaskLogsResponse struct {
+	// Stream is the output stream the data was written to by the task
+	Stream TaskLogsResponse_Stream `protobuf:"varint,1,opt,name=stream,proto3,enum=hashicorp.nomad.plugins.drivers.base.proto.TaskLogsResponse_Stream" json:"stream,omitempty"`
+	// Data is the output written by the task
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskLogsResponse) Reset()         { *m = TaskLogsResponse{} }
+func (m *TaskLogsResponse) String() string { return proto.CompactTextString(m) }
+func (*TaskLogsResponse) ProtoMessage()    {}
+func (*TaskLogsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_driver_c76ff134863d81c0, []int{47}
+}
+func (m *TaskLogsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskLogsResponse.Unmarshal(m, b)
+}
+func (m *TaskLogsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskLogsResponse.Marshal(b, m, deterministic)
+}
+func (dst *TaskLogsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskLogsResponse.Merge(dst, src)
+}
+func (m *TaskLogsResponse) XXX_Size() int {
+	return xxx_messageInfo_TaskLogsResponse.Size(m)
+}
+func (m *TaskLogsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskLogsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskLogsResponse proto.InternalMessageInfo
+
+func (m *TaskLogsResponse) GetStream() TaskLogsResponse_Stream {
+	if m != nil {
+		return m.Stream
+	}
+	return TaskLogsResponse_STDOUT
+}
+
+func (m *TaskLogsResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*TaskConfigSchemaRequest)(nil), "hashicorp.nomad.plugins.drivers.base.proto.TaskConfigSchemaRequest")
 	proto.RegisterType((*TaskConfigSchemaResponse)(nil), "hashicorp.nomad.plugins.drivers.base.proto.TaskConfigSchemaResponse")
@@ -2671,12 +2781,15 @@ func init() {
 	proto.RegisterType((*MemoryUsage)(nil), "hashicorp.nomad.plugins.drivers.base.proto.MemoryUsage")
 	proto.RegisterType((*DriverTaskEvent)(nil), "hashicorp.nomad.plugins.drivers.base.proto.DriverTaskEvent")
 	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.plugins.drivers.base.proto.DriverTaskEvent.AnnotationsEntry")
+	proto.RegisterType((*TaskLogsRequest)(nil), "hashicorp.nomad.plugins.drivers.base.proto.TaskLogsRequest")
+	proto.RegisterType((*TaskLogsResponse)(nil), "hashicorp.nomad.plugins.drivers.base.proto.TaskLogsResponse")
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.base.proto.TaskState", TaskState_name, TaskState_value)
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.base.proto.FingerprintResponse_HealthState", FingerprintResponse_HealthState_name, FingerprintResponse_HealthState_value)
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.base.proto.StartTaskResponse_Result", StartTaskResponse_Result_name, StartTaskResponse_Result_value)
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.base.proto.DriverCapabilities_FSIsolation", DriverCapabilities_FSIsolation_name, DriverCapabilities_FSIsolation_value)
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.base.proto.CPUUsage_Fields", CPUUsage_Fields_name, CPUUsage_Fields_value)
 	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.base.proto.MemoryUsage_Fields", MemoryUsage_Fields_name, MemoryUsage_Fields_value)
+	proto.RegisterEnum("hashicorp.nomad.plugins.drivers.base.proto.TaskLogsResponse_Stream", TaskLogsResponse_Stream_name, TaskLogsResponse_Stream_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -2733,6 +2846,9 @@ type DriverClient interface {
 	// TaskEvents starts a streaming RPC where all task events emitted by the
 	// driver are streamed to the caller.
 	TaskEvents(ctx context.Context, in *TaskEventsRequest, opts ...grpc.CallOption) (Driver_TaskEventsClient, error)
+	// TaskLogs starts a streaming RPC where the output of the given task is
+	// streamed to the caller. The stream ends when the task exits.
+	TaskLogs(ctx context.Context, in *TaskLogsRequest, opts ...grpc.CallOption) (Driver_TaskLogsClient, error)
 	// SignalTask sends a signal to the task
 	SignalTask(ctx context.Context, in *SignalTaskRequest, opts ...grpc.CallOption) (*SignalTaskResponse, error)
 	// ExecTask executes a command inside the tasks execution context
@@ -2901,6 +3017,38 @@ func (x *driverTaskEventsClient) Recv() (*DriverTaskEvent, error) {
 	return m, nil
 }
 
+func (c *driverClient) TaskLogs(ctx context.Context, in *TaskLogsRequest, opts ...grpc.CallOption) (Driver_TaskLogsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Driver_serviceDesc.Streams[2], "/hashicorp.nomad.plugins.drivers.base.proto.Driver/TaskLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &driverTaskLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Driver_TaskLogsClient interface {
+	Recv() (*TaskLogsResponse, error)
+	grpc.ClientStream
+}
+
+type driverTaskLogsClient struct {
+	grpc.ClientStream
+}
+
+func (x *driverTaskLogsClient) Recv() (*TaskLogsResponse, error) {
+	m := new(TaskLogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *driverClient) SignalTask(ctx context.Context, in *SignalTaskRequest, opts ...grpc.CallOption) (*SignalTaskResponse, error) {
 	out := new(SignalTaskResponse)
 	err := c.cc.Invoke(ctx, "/hashicorp.nomad.plugins.drivers.base.proto.Driver/SignalTask", in, out, opts...)
@@ -2963,6 +3111,9 @@ type DriverServer interface {
 	// TaskEvents starts a streaming RPC where all task events emitted by the
 	// driver are streamed to the caller.
 	TaskEvents(*TaskEventsRequest, Driver_TaskEventsServer) error
+	// TaskLogs starts a streaming RPC where the output of the given task is
+	// streamed to the caller. The stream ends when the task exits.
+	TaskLogs(*TaskLogsRequest, Driver_TaskLogsServer) error
 	// SignalTask sends a signal to the task
 	SignalTask(context.Context, *SignalTaskRequest) (*SignalTaskResponse, error)
 	// ExecTask executes a command inside the tasks execution context
@@ -3195,6 +3346,27 @@ func (x *driverTaskEventsServer) Send(m *DriverTaskEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Driver_TaskLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TaskLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DriverServer).TaskLogs(m, &driverTaskLogsServer{stream})
+}
+
+type Driver_TaskLogsServer interface {
+	Send(*TaskLogsResponse) error
+	grpc.ServerStream
+}
+
+type driverTaskLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *driverTaskLogsServer) Send(m *TaskLogsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Driver_SignalTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SignalTaskRequest)
 	if err := dec(in); err != nil {
@@ -3295,188 +3467,198 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Driver_TaskEvents_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "TaskLogs",
+			Handler:       _Driver_TaskLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "driver.proto",
 }
 
-func init() { proto.RegisterFile("driver.proto", fileDescriptor_driver_4db9b3af49fa7db9) }
-
-var fileDescriptor_driver_4db9b3af49fa7db9 = []byte{
-	// 2810 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x5f, 0x6f, 0xe3, 0xc6,
-	0x11, 0x37, 0xf5, 0xcf, 0xd2, 0x48, 0x96, 0x79, 0x7b, 0x77, 0x8d, 0xa2, 0xa0, 0xcd, 0x85, 0x40,
-	0x00, 0x23, 0x69, 0xe4, 0xc6, 0x69, 0xd3, 0xfc, 0xb9, 0xbb, 0x44, 0x91, 0x78, 0x67, 0xe7, 0x2c,
-	0xd9, 0x59, 0xc9, 0x48, 0x52, 0x24, 0x61, 0x68, 0x72, 0x4f, 0x62, 0x2c, 0xfe, 0x09, 0x97, 0xf2,
-	0xd9, 0x01, 0x0a, 0xe4, 0x21, 0x40, 0x81, 0xa2, 0x2d, 0x8a, 0xb4, 0xef, 0x41, 0xdf, 0x8b, 0xa2,
-	0xaf, 0x7d, 0xea, 0x17, 0x08, 0xd0, 0x4f, 0xd0, 0x2f, 0x50, 0xa0, 0x7d, 0xe8, 0x27, 0x28, 0xf6,
-	0x0f, 0x29, 0xca, 0xf2, 0x25, 0xa6, 0x74, 0x7d, 0x22, 0x77, 0x76, 0xf9, 0x9b, 0xe1, 0xcc, 0xec,
-	0xcc, 0xec, 0x0e, 0xd4, 0xec, 0xd0, 0x39, 0x25, 0x61, 0x2b, 0x08, 0xfd, 0xc8, 0x47, 0x2f, 0x8c,
-	0x4d, 0x3a, 0x76, 0x2c, 0x3f, 0x0c, 0x5a, 0x9e, 0xef, 0x9a, 0x76, 0x2b, 0x98, 0x4c, 0x47, 0x8e,
-	0x47, 0x5b, 0x62, 0x15, 0x6d, 0x1d, 0x9b, 0x94, 0x88, 0xb5, 0xcd, 0x1f, 0x8d, 0x7c, 0x7f, 0x34,
-	0x21, 0xdb, 0x7c, 0x74, 0x3c, 0x7d, 0xb8, 0x6d, 0x4f, 0x43, 0x33, 0x72, 0x7c, 0x4f, 0xce, 0x3f,
-	0x7b, 0x71, 0x3e, 0x72, 0x5c, 0x42, 0x23, 0xd3, 0x0d, 0xe4, 0x82, 0xb7, 0x47, 0x4e, 0x34, 0x9e,
-	0x1e, 0xb7, 0x2c, 0xdf, 0xdd, 0x4e, 0xf8, 0x6e, 0x73, 0xbe, 0xdb, 0x92, 0xef, 0x36, 0x1d, 0x9b,
-	0x21, 0xb1, 0xb7, 0xc7, 0xd6, 0x84, 0x06, 0xc4, 0x62, 0x4f, 0x83, 0xbd, 0x08, 0x04, 0xed, 0x69,
-	0x78, 0x6a, 0x68, 0xd2, 0x93, 0x8e, 0xef, 0x3d, 0x74, 0x46, 0x03, 0x6b, 0x4c, 0x5c, 0x13, 0x93,
-	0xcf, 0xa7, 0x84, 0x46, 0xda, 0x47, 0xd0, 0x58, 0x9c, 0xa2, 0x81, 0xef, 0x51, 0x82, 0xde, 0x86,
-	0x02, 0x03, 0x69, 0x28, 0xb7, 0x94, 0xad, 0xea, 0xce, 0x8f, 0x5b, 0x8f, 0xfb, 0x69, 0xc1, 0xbc,
-	0x25, 0x99, 0xb7, 0x06, 0x01, 0xb1, 0x30, 0xff, 0x52, 0xbb, 0x09, 0xd7, 0x3b, 0x66, 0x60, 0x1e,
-	0x3b, 0x13, 0x27, 0x72, 0x08, 0x8d, 0x99, 0x7e, 0x01, 0x37, 0xe6, 0xc9, 0x92, 0xe1, 0x31, 0xd4,
-	0xac, 0x14, 0x5d, 0x32, 0xbe, 0xdb, 0xba, 0xba, 0xb6, 0x5b, 0x5d, 0x4e, 0x9a, 0x43, 0x9f, 0xc3,
-	0xd4, 0x6e, 0x00, 0xba, 0xe7, 0x78, 0x23, 0x12, 0x06, 0xa1, 0xe3, 0x45, 0xb1, 0x44, 0x7f, 0xcc,
-	0xc3, 0xf5, 0x39, 0xb2, 0x94, 0xc8, 0x07, 0x30, 0xa3, 0x28, 0x74, 0x8e, 0xa7, 0x11, 0x97, 0x27,
-	0xbf, 0x55, 0xdd, 0x39, 0xc8, 0x22, 0xcf, 0x25, 0xa0, 0xad, 0x76, 0x82, 0xa8, 0x7b, 0x51, 0x78,
-	0x8e, 0x53, 0x2c, 0x90, 0x05, 0xa5, 0x31, 0x31, 0x27, 0xd1, 0xb8, 0x91, 0xbb, 0xa5, 0x6c, 0xd5,
-	0x77, 0x1e, 0xac, 0xca, 0x6c, 0x97, 0xa3, 0x0d, 0x22, 0x33, 0x22, 0x58, 0x42, 0xa3, 0x97, 0x00,
-	0x89, 0x37, 0xc3, 0x26, 0xd4, 0x0a, 0x9d, 0x80, 0xb9, 0x63, 0x23, 0x7f, 0x4b, 0xd9, 0xaa, 0xe0,
-	0x6b, 0x62, 0xa6, 0x3b, 0x9b, 0x68, 0xde, 0x81, 0xcd, 0x0b, 0x22, 0x23, 0x15, 0xf2, 0x27, 0xe4,
-	0x9c, 0x1b, 0xa8, 0x82, 0xd9, 0x2b, 0xba, 0x01, 0xc5, 0x53, 0x73, 0x32, 0x25, 0x5c, 0xee, 0x0a,
-	0x16, 0x83, 0x37, 0x72, 0xaf, 0x29, 0xda, 0xeb, 0x50, 0x4d, 0x09, 0x81, 0xea, 0x00, 0x47, 0xfd,
-	0xae, 0x3e, 0xd4, 0x3b, 0x43, 0xbd, 0xab, 0xae, 0xa1, 0x0d, 0xa8, 0x1c, 0xf5, 0x77, 0xf5, 0xf6,
-	0xfe, 0x70, 0xf7, 0x43, 0x55, 0x41, 0x55, 0x58, 0x8f, 0x07, 0x39, 0xed, 0x97, 0x80, 0x30, 0xb1,
-	0xfc, 0x53, 0x12, 0x32, 0x27, 0x95, 0xc6, 0x42, 0x4f, 0xc1, 0x7a, 0x64, 0xd2, 0x13, 0xc3, 0xb1,
-	0xa5, 0x00, 0x25, 0x36, 0xdc, 0xb3, 0x51, 0x1f, 0x4a, 0x63, 0xd3, 0xb3, 0x27, 0x42, 0x88, 0xea,
-	0xce, 0xab, 0x59, 0x94, 0xc7, 0x38, 0xec, 0xf2, 0xaf, 0xb1, 0x44, 0x61, 0xee, 0x3b, 0xc7, 0x5e,
-	0xa8, 0x54, 0xfb, 0x04, 0xd4, 0x41, 0x64, 0x86, 0x51, 0x5a, 0xa6, 0x77, 0xa1, 0xc0, 0x84, 0x90,
-	0x2e, 0x9b, 0x99, 0xb1, 0xd8, 0x7f, 0x98, 0x63, 0x68, 0x5f, 0xe6, 0xe1, 0x5a, 0x8a, 0x81, 0x74,
-	0xc5, 0x8f, 0xa0, 0x14, 0x12, 0x3a, 0x9d, 0x44, 0x9c, 0x47, 0x7d, 0xa7, 0x9b, 0x85, 0xc7, 0x02,
-	0x5c, 0x0b, 0x73, 0x2c, 0x2c, 0x31, 0xd1, 0x16, 0xa8, 0xe2, 0x33, 0x83, 0x84, 0xa1, 0x1f, 0x1a,
-	0x2e, 0x1d, 0x49, 0x4b, 0xd6, 0x05, 0x5d, 0x67, 0xe4, 0x1e, 0x1d, 0xa5, 0x94, 0x9c, 0x7f, 0x12,
-	0x4a, 0x46, 0x0f, 0x41, 0xf5, 0x48, 0xf4, 0xc8, 0x0f, 0x4f, 0x0c, 0xa6, 0xe9, 0xd0, 0xb1, 0x49,
-	0xa3, 0xc0, 0x91, 0xdf, 0xcc, 0x82, 0xdc, 0x17, 0x18, 0x07, 0x12, 0x02, 0x6f, 0x7a, 0xf3, 0x04,
-	0xed, 0x45, 0x28, 0x89, 0x7f, 0x66, 0x2e, 0x36, 0x38, 0xea, 0x74, 0xf4, 0xc1, 0x40, 0x5d, 0x43,
-	0x15, 0x28, 0x62, 0x7d, 0x88, 0x99, 0xeb, 0x55, 0xa0, 0x78, 0xaf, 0x3d, 0x6c, 0xef, 0xab, 0x39,
-	0xed, 0x05, 0xd8, 0x7c, 0xdf, 0x74, 0xa2, 0xab, 0x78, 0x9d, 0x16, 0x81, 0x3a, 0x5b, 0x2b, 0x8d,
-	0xd5, 0x9f, 0x33, 0x56, 0x46, 0x25, 0xe9, 0x67, 0x4e, 0x74, 0xc1, 0x3c, 0x2a, 0xe4, 0x49, 0x18,
-	0x4a, 0x8b, 0xb0, 0x57, 0xed, 0x11, 0x6c, 0x0e, 0x22, 0x3f, 0xb8, 0xd2, 0xbe, 0x78, 0x05, 0xd6,
-	0x59, 0x52, 0xf1, 0xa7, 0x91, 0xdc, 0x18, 0x4f, 0xb7, 0x44, 0xd2, 0x69, 0xc5, 0x49, 0xa7, 0xd5,
-	0x95, 0x49, 0x09, 0xc7, 0x2b, 0xd1, 0x0f, 0xa0, 0x44, 0x9d, 0x91, 0x67, 0x4e, 0x64, 0x60, 0x90,
-	0x23, 0x0d, 0x31, 0xef, 0x8f, 0x19, 0xcb, 0x1d, 0xf1, 0x12, 0xa0, 0x2e, 0xa1, 0x51, 0xe8, 0x9f,
-	0x5f, 0x49, 0x63, 0x37, 0xe1, 0xfa, 0xdc, 0x72, 0x89, 0x82, 0x40, 0xdd, 0x77, 0x28, 0x57, 0x64,
-	0x92, 0x2a, 0x4c, 0xb8, 0x96, 0xa2, 0x49, 0xed, 0xee, 0x43, 0x91, 0x21, 0xc5, 0x01, 0x39, 0xb3,
-	0x07, 0xb2, 0x40, 0x34, 0xa5, 0x58, 0x80, 0x30, 0xe1, 0xf7, 0x3c, 0x96, 0xae, 0xae, 0x66, 0xee,
-	0x6f, 0x72, 0x70, 0x7d, 0x6e, 0xbd, 0x14, 0x6a, 0xc5, 0x08, 0x20, 0x65, 0xe2, 0x18, 0x68, 0x08,
-	0x25, 0xb1, 0x4c, 0xda, 0xeb, 0x76, 0x56, 0x34, 0x91, 0x06, 0x25, 0xa6, 0xc4, 0xba, 0x74, 0xa7,
-	0xe5, 0xff, 0x2f, 0x3b, 0x4d, 0x8d, 0xff, 0x88, 0x7e, 0xaf, 0x3a, 0x3f, 0x85, 0x6b, 0xa9, 0xc5,
-	0x52, 0x97, 0x0f, 0xa0, 0x48, 0x19, 0x41, 0x2a, 0xf3, 0x67, 0xcb, 0x28, 0x93, 0x62, 0x81, 0xa1,
-	0x5d, 0x17, 0x1c, 0xf4, 0x53, 0xe2, 0x25, 0xf2, 0x68, 0x5d, 0xb8, 0x36, 0xe0, 0xfe, 0x7c, 0xa5,
-	0x0d, 0x34, 0xdb, 0x0b, 0xb9, 0xb9, 0xbd, 0x70, 0x03, 0x50, 0x1a, 0x45, 0xfa, 0xf1, 0x39, 0x6c,
-	0xea, 0x67, 0xc4, 0xba, 0x12, 0x72, 0x03, 0xd6, 0x2d, 0xdf, 0x75, 0x4d, 0xcf, 0x6e, 0xe4, 0x6e,
-	0xe5, 0xb7, 0x2a, 0x38, 0x1e, 0xa6, 0x37, 0x6d, 0xfe, 0xaa, 0x9b, 0x56, 0xfb, 0x5a, 0x01, 0x75,
-	0xc6, 0x5b, 0x6a, 0x93, 0x49, 0x1f, 0xd9, 0x0c, 0x88, 0xf1, 0xae, 0x61, 0x39, 0x92, 0xf4, 0x38,
-	0xae, 0x08, 0x3a, 0x09, 0xc3, 0x54, 0xf0, 0xca, 0x3f, 0x89, 0xe0, 0xa5, 0xfd, 0x4b, 0x01, 0xb4,
-	0x58, 0x97, 0xa1, 0xe7, 0xa0, 0x46, 0x89, 0x67, 0x1b, 0x42, 0x97, 0xc2, 0xd6, 0x65, 0x5c, 0x65,
-	0x34, 0xa1, 0x54, 0x8a, 0x10, 0x14, 0xc8, 0x19, 0xb1, 0xb8, 0x7c, 0x65, 0xcc, 0xdf, 0x91, 0x0b,
-	0xb5, 0x87, 0xd4, 0x70, 0xa8, 0x3f, 0x31, 0x93, 0xb2, 0xa5, 0xbe, 0xf3, 0xee, 0x6a, 0x45, 0x62,
-	0xeb, 0xde, 0x60, 0x2f, 0x46, 0xc4, 0xd5, 0x87, 0x34, 0x19, 0x68, 0x2d, 0xa8, 0xa6, 0xe6, 0x50,
-	0x19, 0x0a, 0xfd, 0x83, 0xbe, 0xae, 0xae, 0x21, 0x80, 0x52, 0x67, 0x17, 0x1f, 0x1c, 0x0c, 0x45,
-	0xe6, 0xd8, 0xeb, 0xb5, 0xef, 0xeb, 0x6a, 0x4e, 0xfb, 0x5d, 0x01, 0x60, 0x96, 0xd1, 0x51, 0x1d,
-	0x72, 0x89, 0xcd, 0x73, 0x8e, 0xcd, 0xfe, 0xc8, 0x33, 0xdd, 0xb8, 0x4a, 0xe2, 0xef, 0x68, 0x07,
-	0x6e, 0xba, 0x74, 0x14, 0x98, 0xd6, 0x89, 0x21, 0x73, 0xb0, 0xc5, 0x3f, 0xe6, 0xbf, 0x56, 0xc3,
-	0xd7, 0xe5, 0xa4, 0x94, 0x5a, 0xe0, 0xbe, 0x07, 0x79, 0xe2, 0x9d, 0x36, 0x0a, 0x3c, 0x00, 0xbe,
-	0xb5, 0x5c, 0xb9, 0xd1, 0xd2, 0xbd, 0x53, 0x51, 0x81, 0x32, 0x2c, 0x34, 0x80, 0x4a, 0x48, 0xa8,
-	0x3f, 0x0d, 0x2d, 0x42, 0x1b, 0xc5, 0xec, 0x1b, 0x0f, 0xc7, 0x1f, 0xe3, 0x19, 0x0e, 0xda, 0x83,
-	0x92, 0xeb, 0x4f, 0xbd, 0x88, 0x36, 0x4a, 0x5c, 0xd4, 0x97, 0xb3, 0x20, 0xf6, 0xd8, 0x97, 0x58,
-	0x02, 0xa0, 0x7d, 0x58, 0xb7, 0xc9, 0xa9, 0xc3, 0xa4, 0x5b, 0xe7, 0x58, 0x3b, 0x99, 0x6c, 0xce,
-	0x3f, 0xc5, 0x31, 0x04, 0x33, 0xc4, 0x94, 0x92, 0xb0, 0x51, 0x16, 0x86, 0x60, 0xef, 0xe8, 0x19,
-	0xa8, 0x98, 0x93, 0x89, 0x6f, 0x19, 0xb6, 0x13, 0x36, 0x2a, 0x7c, 0xa2, 0xcc, 0x09, 0x5d, 0x27,
-	0x6c, 0xbe, 0x0a, 0xe5, 0x58, 0x5f, 0x99, 0xca, 0xdf, 0x7f, 0x28, 0x50, 0x49, 0x54, 0x83, 0x3e,
-	0x86, 0x8d, 0xd0, 0x7c, 0x64, 0xcc, 0x14, 0x2d, 0x22, 0xdc, 0x6b, 0x99, 0x14, 0x6d, 0x3e, 0x9a,
-	0xe9, 0xba, 0x16, 0xa6, 0x46, 0xc8, 0x82, 0xcd, 0x89, 0xe3, 0x4d, 0xcf, 0x52, 0x0c, 0x44, 0x06,
-	0x79, 0x23, 0x0b, 0x83, 0x7d, 0x06, 0x31, 0x63, 0x51, 0x9f, 0xcc, 0x8d, 0xb5, 0xbf, 0x29, 0x50,
-	0x4b, 0xcb, 0xc0, 0xd4, 0x61, 0x05, 0x53, 0xfe, 0x2b, 0x79, 0xcc, 0x5e, 0x59, 0x68, 0x71, 0x89,
-	0xeb, 0x87, 0xe7, 0x9c, 0x7d, 0x1e, 0xcb, 0x11, 0xd3, 0xba, 0xed, 0xd0, 0x13, 0xee, 0xd9, 0x79,
-	0xcc, 0xdf, 0x19, 0xcd, 0xf1, 0x03, 0xca, 0x8b, 0xbe, 0x3c, 0xe6, 0xef, 0xe8, 0x7d, 0x28, 0xcb,
-	0xac, 0xc2, 0x5c, 0x31, 0xbf, 0x64, 0x8a, 0x8a, 0x25, 0xc4, 0x09, 0x98, 0xf6, 0xa7, 0x1c, 0x6c,
-	0x5e, 0x98, 0x65, 0xc2, 0x0a, 0xaf, 0x88, 0x63, 0xb3, 0x18, 0x31, 0xc1, 0x2c, 0xc7, 0x8e, 0xab,
-	0x2e, 0xfe, 0xce, 0xf7, 0x73, 0x20, 0x2b, 0xa2, 0x9c, 0x13, 0x30, 0xbb, 0xbb, 0xc7, 0x4e, 0x24,
-	0xa4, 0x2f, 0x62, 0x31, 0x40, 0x9f, 0x40, 0x3d, 0x24, 0x94, 0x84, 0xa7, 0xc4, 0x36, 0x02, 0x3f,
-	0x8c, 0xe2, 0x9f, 0xf8, 0xf9, 0x12, 0x3f, 0x71, 0xe8, 0x87, 0x11, 0xde, 0x88, 0xe1, 0xd8, 0x88,
-	0xa2, 0x8f, 0x60, 0xc3, 0x3e, 0xf7, 0x4c, 0xd7, 0xb1, 0x24, 0x7c, 0x69, 0x35, 0xf8, 0x9a, 0x44,
-	0xe3, 0xe8, 0xec, 0xc0, 0x96, 0x9a, 0x64, 0xbf, 0x38, 0x31, 0x8f, 0xc9, 0x44, 0x6a, 0x47, 0x0c,
-	0xe6, 0x1d, 0xbe, 0x28, 0x1d, 0x5e, 0xfb, 0x2a, 0x07, 0xf5, 0x79, 0xef, 0x41, 0x3f, 0x04, 0xb0,
-	0x82, 0xa9, 0x11, 0x90, 0xd0, 0xf1, 0x6d, 0xe9, 0x23, 0x15, 0x2b, 0x98, 0x1e, 0x72, 0x02, 0xdb,
-	0x73, 0x6c, 0xfa, 0xf3, 0xa9, 0x1f, 0x99, 0xd2, 0x59, 0xca, 0x56, 0x30, 0x7d, 0x8f, 0x8d, 0xe3,
-	0x6f, 0xf9, 0x05, 0x03, 0x95, 0x4e, 0xc3, 0x96, 0x0f, 0x38, 0x01, 0xbd, 0x0c, 0x37, 0x85, 0x5f,
-	0x19, 0x13, 0xc7, 0x75, 0x22, 0xc3, 0xf1, 0x8c, 0xe3, 0x73, 0x76, 0x50, 0x17, 0xae, 0x84, 0xc4,
-	0xe4, 0x3e, 0x9b, 0xdb, 0xf3, 0xde, 0x61, 0x33, 0x48, 0x83, 0x0d, 0xdf, 0x77, 0x0d, 0x6a, 0xf9,
-	0x21, 0x31, 0x4c, 0xfb, 0x33, 0x1e, 0xe8, 0xf2, 0xb8, 0xea, 0xfb, 0xee, 0x80, 0xd1, 0xda, 0xf6,
-	0x67, 0xe8, 0x59, 0xa8, 0x5a, 0xc1, 0x94, 0x92, 0xc8, 0x60, 0x8f, 0x46, 0x89, 0xff, 0x36, 0x08,
-	0x52, 0x27, 0x98, 0xd2, 0xd4, 0x02, 0x97, 0xb8, 0x2c, 0x1a, 0xa5, 0x16, 0xf4, 0x88, 0x4b, 0xb5,
-	0x8f, 0xa1, 0xc8, 0x63, 0x17, 0xfb, 0x3b, 0x9e, 0xf7, 0x03, 0x33, 0x1a, 0x4b, 0xfd, 0x95, 0x19,
-	0xe1, 0xd0, 0x8c, 0xc6, 0x6c, 0x72, 0xec, 0xd3, 0x48, 0x4c, 0x0a, 0x27, 0x2b, 0x33, 0x02, 0x9f,
-	0x6c, 0x42, 0x39, 0x24, 0xa6, 0xed, 0x7b, 0x93, 0x73, 0xfe, 0xe3, 0x65, 0x9c, 0x8c, 0xb5, 0xcf,
-	0xa1, 0x24, 0xc2, 0xd9, 0x0a, 0xf8, 0x2f, 0x01, 0xb2, 0x46, 0xa1, 0x3f, 0x0d, 0x98, 0x65, 0x5c,
-	0x87, 0x52, 0xc7, 0xf7, 0x68, 0x7c, 0x07, 0x20, 0x66, 0x0e, 0x67, 0x13, 0xda, 0xb7, 0x8a, 0x48,
-	0x6b, 0xe2, 0xf0, 0xc6, 0x4a, 0x04, 0x99, 0xa3, 0x56, 0x3b, 0xf0, 0x4a, 0x94, 0xb8, 0xe0, 0x23,
-	0xf2, 0xd6, 0x63, 0xa9, 0x82, 0x8f, 0x88, 0x82, 0x8f, 0xb0, 0xc2, 0x42, 0xe6, 0x51, 0x81, 0x29,
-	0xd2, 0x68, 0xd5, 0x4e, 0x6a, 0x62, 0xa2, 0xfd, 0x5b, 0x49, 0xc2, 0x40, 0x5c, 0xb6, 0x22, 0x0b,
-	0xca, 0x6c, 0x33, 0x19, 0xae, 0x19, 0xc8, 0x83, 0xc5, 0xee, 0x0a, 0x65, 0x71, 0x8b, 0xed, 0x9d,
-	0x9e, 0x19, 0x88, 0x04, 0xbb, 0x1e, 0x88, 0x11, 0x8b, 0x29, 0xa6, 0x3d, 0x8b, 0x29, 0xec, 0x1d,
-	0x3d, 0x0f, 0x75, 0x73, 0x1a, 0xf9, 0x86, 0x69, 0x9f, 0x92, 0x30, 0x72, 0x28, 0x91, 0x06, 0xdf,
-	0x60, 0xd4, 0x76, 0x4c, 0x6c, 0xbe, 0x01, 0xb5, 0x34, 0xe6, 0xf7, 0x25, 0xa1, 0x62, 0x3a, 0x09,
-	0x7d, 0x0a, 0x30, 0x2b, 0xcc, 0x98, 0x63, 0x90, 0x33, 0x27, 0x32, 0x2c, 0xdf, 0x16, 0x31, 0xaf,
-	0x88, 0xcb, 0x8c, 0xd0, 0xf1, 0x6d, 0x72, 0xa1, 0xd6, 0x2d, 0xc6, 0xb5, 0x2e, 0xdb, 0x8b, 0x6c,
-	0xe7, 0x9c, 0x38, 0x93, 0x09, 0xb1, 0xa5, 0x84, 0x15, 0xdf, 0x77, 0x1f, 0x70, 0x82, 0xf6, 0x9f,
-	0x9c, 0x70, 0x10, 0x71, 0xe6, 0xb8, 0x52, 0xdd, 0x93, 0x18, 0x3d, 0xff, 0x04, 0x8c, 0xfe, 0x3c,
-	0x6c, 0x52, 0xe7, 0x0b, 0x62, 0xf8, 0x9e, 0xc1, 0xb2, 0x8a, 0xe1, 0x1e, 0xcb, 0x28, 0x50, 0x63,
-	0xe4, 0x03, 0xaf, 0xeb, 0xd0, 0x93, 0xde, 0x31, 0x7a, 0x1d, 0x80, 0x46, 0x66, 0x18, 0x11, 0xdb,
-	0x30, 0x23, 0x59, 0xe5, 0x34, 0x17, 0x0a, 0xeb, 0x61, 0x7c, 0x05, 0x8b, 0x2b, 0x72, 0x75, 0x3b,
-	0x42, 0x77, 0xa0, 0x66, 0xf9, 0x6e, 0x30, 0x21, 0xf2, 0xe3, 0xd2, 0xf7, 0x7e, 0x5c, 0x4d, 0xd6,
-	0xb7, 0xa3, 0x54, 0x55, 0xbd, 0xfe, 0x44, 0xaa, 0xea, 0xbf, 0x2b, 0xe2, 0x98, 0x95, 0x3e, 0xea,
-	0xa1, 0xc9, 0x25, 0xf7, 0x95, 0xfb, 0xab, 0x1c, 0x1e, 0xbf, 0xeb, 0xb2, 0x72, 0xd5, 0x8b, 0xc1,
-	0x6f, 0xf3, 0x50, 0x49, 0x4e, 0x6b, 0x0b, 0x1e, 0xf3, 0x1a, 0x54, 0x92, 0x9b, 0x70, 0x59, 0xc4,
-	0x7c, 0xa7, 0xa1, 0x92, 0xc5, 0xe8, 0x04, 0x90, 0x39, 0x1a, 0x25, 0x25, 0x90, 0x31, 0xa5, 0xe6,
-	0x28, 0x3e, 0xe9, 0xde, 0xc9, 0xaa, 0x8c, 0x38, 0x91, 0x1d, 0x31, 0x10, 0xac, 0x9a, 0xa3, 0xd1,
-	0x1c, 0x05, 0x7d, 0xa9, 0xc0, 0xcd, 0x79, 0x4e, 0xc6, 0xf1, 0xb9, 0x11, 0x38, 0xb6, 0xac, 0xcd,
-	0x7b, 0x4b, 0x9d, 0x5d, 0x5b, 0x73, 0x4c, 0xde, 0x39, 0x3f, 0x74, 0x6c, 0xa1, 0x7e, 0x14, 0x2e,
-	0x4c, 0x34, 0xbf, 0x52, 0xe0, 0xa9, 0xc7, 0xac, 0xbf, 0xc4, 0x1e, 0x83, 0xb4, 0x3d, 0x56, 0x56,
-	0x48, 0xca, 0x9c, 0x7f, 0x56, 0xc4, 0x41, 0x7b, 0x5e, 0x3f, 0xf7, 0x66, 0xb5, 0x61, 0x75, 0xe7,
-	0xa7, 0x59, 0x98, 0x75, 0x0e, 0x8f, 0x04, 0x0f, 0x5e, 0x51, 0x1e, 0xcc, 0x55, 0x94, 0x19, 0x6b,
-	0x9d, 0x1e, 0xff, 0x52, 0xa0, 0x49, 0x18, 0xed, 0xaf, 0x79, 0x28, 0xc7, 0x2c, 0x58, 0x46, 0xa7,
-	0xe7, 0x34, 0x22, 0xae, 0xe1, 0xc6, 0x31, 0x51, 0xc1, 0x20, 0x48, 0x3d, 0x16, 0x15, 0x9f, 0x81,
-	0x0a, 0x3b, 0x22, 0x88, 0xe9, 0x1c, 0x9f, 0x2e, 0x33, 0x02, 0x9f, 0x7c, 0x16, 0xaa, 0x91, 0x1f,
-	0x99, 0x13, 0x23, 0x72, 0xac, 0x13, 0x91, 0x44, 0x15, 0x0c, 0x9c, 0x34, 0x64, 0x14, 0xf4, 0x22,
-	0x5c, 0x8b, 0xc6, 0xa1, 0x1f, 0x45, 0x13, 0x56, 0x10, 0xf2, 0xc2, 0x47, 0x14, 0x29, 0x05, 0xac,
-	0x26, 0x13, 0xa2, 0x20, 0xa2, 0x2c, 0x1d, 0xcc, 0x16, 0x33, 0xaf, 0xe6, 0x61, 0xaa, 0x80, 0x37,
-	0x12, 0x2a, 0xf3, 0x7a, 0xd4, 0x80, 0xf5, 0x80, 0x84, 0x16, 0xf1, 0x44, 0x24, 0x52, 0x70, 0x3c,
-	0x44, 0x36, 0x6c, 0xba, 0xc4, 0xa4, 0xd3, 0x90, 0xd8, 0xc6, 0x43, 0x87, 0x4c, 0x6c, 0x71, 0x60,
-	0xaa, 0x67, 0xab, 0xa1, 0x63, 0xdd, 0xb4, 0xee, 0x71, 0x08, 0x5c, 0x8f, 0x31, 0xc5, 0x98, 0x15,
-	0x21, 0xe2, 0x0d, 0x6d, 0x42, 0x75, 0xf0, 0xe1, 0x60, 0xa8, 0xf7, 0x8c, 0xde, 0x41, 0x57, 0x97,
-	0x57, 0xfa, 0x03, 0x1d, 0x8b, 0xa1, 0xc2, 0xe6, 0x87, 0x07, 0xc3, 0xf6, 0xbe, 0x31, 0xdc, 0xeb,
-	0x3c, 0x18, 0xa8, 0x39, 0x74, 0x13, 0xae, 0x0d, 0x77, 0xf1, 0xc1, 0x70, 0xb8, 0xaf, 0x77, 0x8d,
-	0x43, 0x1d, 0xef, 0x1d, 0x74, 0x07, 0x6a, 0x1e, 0x21, 0xa8, 0xcf, 0xc8, 0xc3, 0xbd, 0x9e, 0xae,
-	0x16, 0x50, 0x15, 0xd6, 0x0f, 0x75, 0xdc, 0xd1, 0xfb, 0x43, 0xb5, 0xa8, 0xfd, 0x33, 0x07, 0xd5,
-	0x94, 0x29, 0x99, 0x73, 0x87, 0x54, 0x1c, 0xa1, 0x0a, 0x98, 0xbd, 0xb2, 0x60, 0x63, 0x99, 0xd6,
-	0x58, 0x98, 0xa8, 0x80, 0xc5, 0x80, 0x19, 0xcf, 0x35, 0xcf, 0x52, 0x71, 0xa0, 0x80, 0xcb, 0xae,
-	0x79, 0x26, 0x40, 0x9e, 0x83, 0xda, 0x09, 0x09, 0x3d, 0x32, 0x91, 0xf3, 0xc2, 0x2c, 0x55, 0x41,
-	0x13, 0x4b, 0xb6, 0x40, 0x95, 0x4b, 0x66, 0x30, 0xc2, 0x26, 0x75, 0x41, 0xef, 0xc5, 0x60, 0xa3,
-	0x45, 0xd5, 0x97, 0xb8, 0xea, 0xef, 0x2e, 0xe9, 0xae, 0x8f, 0xd3, 0xfe, 0x20, 0xd1, 0xfe, 0x3a,
-	0xe4, 0x71, 0x7c, 0x93, 0xdd, 0x69, 0x77, 0x76, 0x99, 0xc6, 0x37, 0xa0, 0xd2, 0x6b, 0x7f, 0x60,
-	0x1c, 0xb5, 0x07, 0xf7, 0x75, 0x35, 0x87, 0x54, 0xa8, 0x3d, 0xd0, 0x71, 0x5f, 0xdf, 0x37, 0x8e,
-	0x06, 0xed, 0xfb, 0xba, 0x9a, 0x47, 0x37, 0x40, 0x95, 0x14, 0xbe, 0x8e, 0x53, 0x0b, 0xda, 0x5f,
-	0x72, 0xb0, 0x29, 0x82, 0x7f, 0x72, 0x61, 0xf6, 0xf8, 0x9b, 0xab, 0xe5, 0xe3, 0x73, 0x03, 0xd6,
-	0x5d, 0x42, 0x13, 0x63, 0x54, 0x70, 0x3c, 0x44, 0x1e, 0x54, 0x4d, 0xcf, 0xf3, 0x23, 0x7e, 0xd7,
-	0x42, 0x65, 0x04, 0xdd, 0xcf, 0x7e, 0xb5, 0x93, 0x88, 0xdf, 0x6a, 0xcf, 0xe0, 0x44, 0x00, 0x4d,
-	0x33, 0x68, 0xde, 0x05, 0xf5, 0xe2, 0x82, 0x2c, 0x19, 0xec, 0x85, 0x97, 0x67, 0x09, 0x8c, 0x30,
-	0x57, 0x3d, 0xea, 0x3f, 0xe8, 0x1f, 0xbc, 0xdf, 0x57, 0xd7, 0xd8, 0x00, 0x1f, 0xf5, 0xfb, 0x7b,
-	0xfd, 0xfb, 0xaa, 0x82, 0x00, 0x4a, 0xfa, 0x07, 0x7b, 0x43, 0xbd, 0xab, 0xe6, 0x76, 0xfe, 0xbb,
-	0x09, 0x25, 0x21, 0x24, 0xfa, 0x46, 0x66, 0xf0, 0x74, 0xf3, 0x15, 0x75, 0x96, 0xab, 0xa4, 0xe7,
-	0xba, 0xba, 0xcd, 0xee, 0x6a, 0x20, 0xf2, 0x1e, 0x73, 0x0d, 0xfd, 0x41, 0x81, 0xda, 0xdc, 0x9d,
-	0x5d, 0xa6, 0x9b, 0xa6, 0x4b, 0x5a, 0xbf, 0xcd, 0xb7, 0x97, 0x07, 0x48, 0xa4, 0xfa, 0x5a, 0x81,
-	0x6a, 0xaa, 0xd5, 0x89, 0xee, 0x2e, 0xdd, 0x23, 0x15, 0x32, 0xbd, 0xb5, 0x62, 0x8f, 0x55, 0x5b,
-	0xfb, 0x89, 0x82, 0x7e, 0xaf, 0x40, 0x35, 0xd5, 0x2c, 0xcc, 0x26, 0xd4, 0x62, 0x93, 0x33, 0x9b,
-	0x50, 0x97, 0x75, 0x29, 0xd7, 0xd0, 0xaf, 0x15, 0xa8, 0x24, 0x8d, 0x3f, 0x74, 0x7b, 0xc9, 0x7e,
-	0xa1, 0x10, 0xe7, 0xce, 0x4a, 0xdd, 0x46, 0x6d, 0x0d, 0xfd, 0x4a, 0x81, 0x72, 0xdc, 0x26, 0x43,
-	0x99, 0x12, 0xd1, 0x85, 0x46, 0x5c, 0xf3, 0xf6, 0x72, 0x1f, 0xcf, 0x49, 0x12, 0x77, 0xb0, 0xb2,
-	0x49, 0x72, 0xa1, 0xe1, 0xd6, 0xbc, 0xbd, 0xdc, 0xc7, 0x89, 0x24, 0xcc, 0x67, 0x52, 0x8d, 0xb0,
-	0x6c, 0x3e, 0xb3, 0xd8, 0x70, 0xcb, 0xe6, 0x33, 0x97, 0x75, 0xe0, 0x84, 0xcf, 0x24, 0x0d, 0xb7,
-	0x6c, 0x3e, 0x73, 0xb1, 0x77, 0x97, 0xcd, 0x67, 0x16, 0xba, 0x7c, 0x52, 0x3f, 0xa9, 0x56, 0x5b,
-	0x36, 0xfd, 0x2c, 0xf6, 0xf4, 0xb2, 0xe9, 0xe7, 0x92, 0x1e, 0x9f, 0xd4, 0xcf, 0xec, 0xcc, 0x72,
-	0x7b, 0xb9, 0xc6, 0xd4, 0x32, 0xfa, 0x59, 0x68, 0x92, 0x69, 0x6b, 0xe8, 0x37, 0xf2, 0x52, 0x46,
-	0xb4, 0xb6, 0x50, 0x66, 0xbc, 0xb9, 0x96, 0x58, 0xf3, 0xcd, 0x15, 0xf2, 0x2c, 0x0f, 0x81, 0xbf,
-	0x55, 0x00, 0x66, 0xed, 0xb0, 0x6c, 0xe2, 0x2c, 0x34, 0xe3, 0x9a, 0x77, 0x97, 0xfd, 0x7c, 0x6e,
-	0xa3, 0xc7, 0xcd, 0xb0, 0x6c, 0x1b, 0xfd, 0x42, 0xfb, 0x2e, 0xdb, 0x46, 0xbf, 0xd8, 0x7f, 0xd3,
-	0xd6, 0xde, 0x59, 0xff, 0x45, 0x51, 0x94, 0x44, 0x25, 0xfe, 0x78, 0xe5, 0x7f, 0x01, 0x00, 0x00,
-	0xff, 0xff, 0xb0, 0x0e, 0x99, 0xe1, 0x4f, 0x26, 0x00, 0x00,
+func init() { proto.RegisterFile("driver.proto", fileDescriptor_driver_c76ff134863d81c0) }
+
+var fileDescriptor_driver_c76ff134863d81c0 = []byte{
+	// 2887 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0xdb, 0x6e, 0x23, 0xc7,
+	0xd1, 0xd6, 0xf0, 0x24, 0xb2, 0x48, 0x51, 0xa3, 0xde, 0xdd, 0xdf, 0x34, 0x8d, 0xff, 0xf7, 0x7a,
+	0x00, 0x03, 0x8b, 0xf5, 0x6f, 0x6e, 0x2c, 0x27, 0x8e, 0x0f, 0x7b, 0x30, 0x4d, 0x72, 0x2d, 0x79,
+	0x45, 0x4a, 0x6e, 0x52, 0xb0, 0x9d, 0xd8, 0x1e, 0x8f, 0x66, 0x7a, 0xc9, 0xb1, 0x38, 0x07, 0x4f,
+	0x37, 0xb5, 0x2b, 0x03, 0x01, 0x7c, 0x61, 0x20, 0x48, 0x90, 0x04, 0x81, 0x93, 0x7b, 0x23, 0x17,
+	0xb9, 0x0b, 0x82, 0xdc, 0xe6, 0x2a, 0x2f, 0x60, 0x20, 0x4f, 0x90, 0x17, 0x08, 0x90, 0x3c, 0x42,
+	0x10, 0xf4, 0x61, 0x86, 0x43, 0x69, 0x37, 0xab, 0x21, 0x37, 0x57, 0xd3, 0x5d, 0xdd, 0xfd, 0x55,
+	0x4d, 0x75, 0x75, 0x55, 0x75, 0x17, 0xd4, 0x9c, 0xc8, 0x3d, 0x21, 0x51, 0x2b, 0x8c, 0x02, 0x16,
+	0xa0, 0xeb, 0x13, 0x8b, 0x4e, 0x5c, 0x3b, 0x88, 0xc2, 0x96, 0x1f, 0x78, 0x96, 0xd3, 0x0a, 0xa7,
+	0xb3, 0xb1, 0xeb, 0xd3, 0x96, 0x9c, 0x45, 0x5b, 0x47, 0x16, 0x25, 0x72, 0x6e, 0xf3, 0xff, 0xc6,
+	0x41, 0x30, 0x9e, 0x92, 0x1b, 0xa2, 0x77, 0x34, 0xbb, 0x7f, 0xc3, 0x99, 0x45, 0x16, 0x73, 0x03,
+	0x5f, 0x8d, 0x3f, 0x7f, 0x76, 0x9c, 0xb9, 0x1e, 0xa1, 0xcc, 0xf2, 0x42, 0x35, 0xe1, 0xed, 0xb1,
+	0xcb, 0x26, 0xb3, 0xa3, 0x96, 0x1d, 0x78, 0x37, 0x12, 0xbe, 0x37, 0x04, 0xdf, 0x1b, 0x8a, 0xef,
+	0x0d, 0x3a, 0xb1, 0x22, 0xe2, 0xdc, 0x98, 0xd8, 0x53, 0x1a, 0x12, 0x9b, 0x7f, 0x4d, 0xde, 0x90,
+	0x08, 0xc6, 0xb3, 0xf0, 0xcc, 0xc8, 0xa2, 0xc7, 0x9d, 0xc0, 0xbf, 0xef, 0x8e, 0x87, 0xf6, 0x84,
+	0x78, 0x16, 0x26, 0x5f, 0xcc, 0x08, 0x65, 0xc6, 0xc7, 0xd0, 0x38, 0x3f, 0x44, 0xc3, 0xc0, 0xa7,
+	0x04, 0xbd, 0x0d, 0x05, 0x0e, 0xd2, 0xd0, 0xae, 0x6a, 0xd7, 0xaa, 0xdb, 0xff, 0xdf, 0x7a, 0xdc,
+	0x4f, 0x4b, 0xe6, 0x2d, 0xc5, 0xbc, 0x35, 0x0c, 0x89, 0x8d, 0xc5, 0x4a, 0xe3, 0x0a, 0x5c, 0xea,
+	0x58, 0xa1, 0x75, 0xe4, 0x4e, 0x5d, 0xe6, 0x12, 0x1a, 0x33, 0xfd, 0x12, 0x2e, 0x2f, 0x92, 0x15,
+	0xc3, 0x23, 0xa8, 0xd9, 0x29, 0xba, 0x62, 0x7c, 0xbb, 0x75, 0x71, 0x6d, 0xb7, 0xba, 0x82, 0xb4,
+	0x80, 0xbe, 0x80, 0x69, 0x5c, 0x06, 0x74, 0xd7, 0xf5, 0xc7, 0x24, 0x0a, 0x23, 0xd7, 0x67, 0xb1,
+	0x44, 0xbf, 0xcd, 0xc3, 0xa5, 0x05, 0xb2, 0x92, 0x28, 0x00, 0xb0, 0x18, 0x8b, 0xdc, 0xa3, 0x19,
+	0x13, 0xf2, 0xe4, 0xaf, 0x55, 0xb7, 0xf7, 0xb3, 0xc8, 0xf3, 0x08, 0xd0, 0x56, 0x3b, 0x41, 0xec,
+	0xf9, 0x2c, 0x3a, 0xc5, 0x29, 0x16, 0xc8, 0x86, 0xd2, 0x84, 0x58, 0x53, 0x36, 0x69, 0xe4, 0xae,
+	0x6a, 0xd7, 0xea, 0xdb, 0xf7, 0x56, 0x65, 0xb6, 0x23, 0xd0, 0x86, 0xcc, 0x62, 0x04, 0x2b, 0x68,
+	0xf4, 0x32, 0x20, 0xd9, 0x32, 0x1d, 0x42, 0xed, 0xc8, 0x0d, 0xb9, 0x39, 0x36, 0xf2, 0x57, 0xb5,
+	0x6b, 0x15, 0xbc, 0x25, 0x47, 0xba, 0xf3, 0x81, 0xe6, 0x2d, 0xd8, 0x3c, 0x23, 0x32, 0xd2, 0x21,
+	0x7f, 0x4c, 0x4e, 0xc5, 0x06, 0x55, 0x30, 0x6f, 0xa2, 0xcb, 0x50, 0x3c, 0xb1, 0xa6, 0x33, 0x22,
+	0xe4, 0xae, 0x60, 0xd9, 0x79, 0x33, 0xf7, 0xba, 0x66, 0xbc, 0x01, 0xd5, 0x94, 0x10, 0xa8, 0x0e,
+	0x70, 0x38, 0xe8, 0xf6, 0x46, 0xbd, 0xce, 0xa8, 0xd7, 0xd5, 0xd7, 0xd0, 0x06, 0x54, 0x0e, 0x07,
+	0x3b, 0xbd, 0xf6, 0xde, 0x68, 0xe7, 0x23, 0x5d, 0x43, 0x55, 0x58, 0x8f, 0x3b, 0x39, 0xe3, 0x27,
+	0x80, 0x30, 0xb1, 0x83, 0x13, 0x12, 0x71, 0x23, 0x55, 0x9b, 0x85, 0x9e, 0x81, 0x75, 0x66, 0xd1,
+	0x63, 0xd3, 0x75, 0x94, 0x00, 0x25, 0xde, 0xdd, 0x75, 0xd0, 0x00, 0x4a, 0x13, 0xcb, 0x77, 0xa6,
+	0x52, 0x88, 0xea, 0xf6, 0x6b, 0x59, 0x94, 0xc7, 0x39, 0xec, 0x88, 0xd5, 0x58, 0xa1, 0x70, 0xf3,
+	0x5d, 0x60, 0x2f, 0x55, 0x6a, 0x7c, 0x0a, 0xfa, 0x90, 0x59, 0x11, 0x4b, 0xcb, 0xf4, 0x1e, 0x14,
+	0xb8, 0x10, 0x0d, 0x6d, 0x39, 0xc6, 0xf2, 0xfc, 0x61, 0x81, 0x61, 0x7c, 0x95, 0x87, 0xad, 0x14,
+	0x03, 0x65, 0x8a, 0x1f, 0x43, 0x29, 0x22, 0x74, 0x36, 0x65, 0x82, 0x47, 0x7d, 0xbb, 0x9b, 0x85,
+	0xc7, 0x39, 0xb8, 0x16, 0x16, 0x58, 0x58, 0x61, 0xa2, 0x6b, 0xa0, 0xcb, 0x65, 0x26, 0x89, 0xa2,
+	0x20, 0x32, 0x3d, 0x3a, 0x56, 0x3b, 0x59, 0x97, 0xf4, 0x1e, 0x27, 0xf7, 0xe9, 0x38, 0xa5, 0xe4,
+	0xfc, 0xd3, 0x50, 0x32, 0xba, 0x0f, 0xba, 0x4f, 0xd8, 0x83, 0x20, 0x3a, 0x36, 0xb9, 0xa6, 0x23,
+	0xd7, 0x21, 0x8d, 0x82, 0x40, 0x7e, 0x2b, 0x0b, 0xf2, 0x40, 0x62, 0xec, 0x2b, 0x08, 0xbc, 0xe9,
+	0x2f, 0x12, 0x8c, 0x97, 0xa0, 0x24, 0xff, 0x99, 0x9b, 0xd8, 0xf0, 0xb0, 0xd3, 0xe9, 0x0d, 0x87,
+	0xfa, 0x1a, 0xaa, 0x40, 0x11, 0xf7, 0x46, 0x98, 0x9b, 0x5e, 0x05, 0x8a, 0x77, 0xdb, 0xa3, 0xf6,
+	0x9e, 0x9e, 0x33, 0xae, 0xc3, 0xe6, 0x07, 0x96, 0xcb, 0x2e, 0x62, 0x75, 0x06, 0x03, 0x7d, 0x3e,
+	0x57, 0x6d, 0xd6, 0x60, 0x61, 0xb3, 0x32, 0x2a, 0xa9, 0xf7, 0xd0, 0x65, 0x67, 0xb6, 0x47, 0x87,
+	0x3c, 0x89, 0x22, 0xb5, 0x23, 0xbc, 0x69, 0x3c, 0x80, 0xcd, 0x21, 0x0b, 0xc2, 0x0b, 0x9d, 0x8b,
+	0x57, 0x61, 0x9d, 0x07, 0x95, 0x60, 0xc6, 0xd4, 0xc1, 0x78, 0xb6, 0x25, 0x83, 0x4e, 0x2b, 0x0e,
+	0x3a, 0xad, 0xae, 0x0a, 0x4a, 0x38, 0x9e, 0x89, 0xfe, 0x07, 0x4a, 0xd4, 0x1d, 0xfb, 0xd6, 0x54,
+	0x39, 0x06, 0xd5, 0x33, 0x10, 0xe8, 0x73, 0xc6, 0xea, 0x44, 0xbc, 0x0c, 0xa8, 0x4b, 0x28, 0x8b,
+	0x82, 0xd3, 0x0b, 0x69, 0xec, 0x0a, 0x5c, 0x5a, 0x98, 0xae, 0x50, 0x10, 0xe8, 0x7b, 0x2e, 0x15,
+	0x8a, 0x4c, 0x42, 0x85, 0x05, 0x5b, 0x29, 0x9a, 0xd2, 0xee, 0x1e, 0x14, 0x39, 0x52, 0xec, 0x90,
+	0x33, 0x5b, 0x20, 0x77, 0x44, 0x33, 0x8a, 0x25, 0x08, 0x17, 0x7e, 0xd7, 0xe7, 0xe1, 0xea, 0x62,
+	0xdb, 0xfd, 0x6d, 0x0e, 0x2e, 0x2d, 0xcc, 0x57, 0x42, 0xad, 0xe8, 0x01, 0x94, 0x4c, 0x02, 0x03,
+	0x8d, 0xa0, 0x24, 0xa7, 0xa9, 0xfd, 0xba, 0x99, 0x15, 0x4d, 0x86, 0x41, 0x85, 0xa9, 0xb0, 0x1e,
+	0x79, 0xd2, 0xf2, 0xff, 0x95, 0x93, 0xa6, 0xc7, 0x7f, 0x44, 0x9f, 0xa8, 0xce, 0xcf, 0x60, 0x2b,
+	0x35, 0x59, 0xe9, 0xf2, 0x1e, 0x14, 0x29, 0x27, 0x28, 0x65, 0xfe, 0x60, 0x19, 0x65, 0x52, 0x2c,
+	0x31, 0x8c, 0x4b, 0x92, 0x43, 0xef, 0x84, 0xf8, 0x89, 0x3c, 0x46, 0x17, 0xb6, 0x86, 0xc2, 0x9e,
+	0x2f, 0x74, 0x80, 0xe6, 0x67, 0x21, 0xb7, 0x70, 0x16, 0x2e, 0x03, 0x4a, 0xa3, 0x28, 0x3b, 0x3e,
+	0x85, 0xcd, 0xde, 0x43, 0x62, 0x5f, 0x08, 0xb9, 0x01, 0xeb, 0x76, 0xe0, 0x79, 0x96, 0xef, 0x34,
+	0x72, 0x57, 0xf3, 0xd7, 0x2a, 0x38, 0xee, 0xa6, 0x0f, 0x6d, 0xfe, 0xa2, 0x87, 0xd6, 0xf8, 0x46,
+	0x03, 0x7d, 0xce, 0x5b, 0x69, 0x93, 0x4b, 0xcf, 0x1c, 0x0e, 0xc4, 0x79, 0xd7, 0xb0, 0xea, 0x29,
+	0x7a, 0xec, 0x57, 0x24, 0x9d, 0x44, 0x51, 0xca, 0x79, 0xe5, 0x9f, 0x86, 0xf3, 0x32, 0xfe, 0xae,
+	0x01, 0x3a, 0x9f, 0x97, 0xa1, 0x17, 0xa0, 0x46, 0x89, 0xef, 0x98, 0x52, 0x97, 0x72, 0xaf, 0xcb,
+	0xb8, 0xca, 0x69, 0x52, 0xa9, 0x14, 0x21, 0x28, 0x90, 0x87, 0xc4, 0x16, 0xf2, 0x95, 0xb1, 0x68,
+	0x23, 0x0f, 0x6a, 0xf7, 0xa9, 0xe9, 0xd2, 0x60, 0x6a, 0x25, 0x69, 0x4b, 0x7d, 0xfb, 0xbd, 0xd5,
+	0x92, 0xc4, 0xd6, 0xdd, 0xe1, 0x6e, 0x8c, 0x88, 0xab, 0xf7, 0x69, 0xd2, 0x31, 0x5a, 0x50, 0x4d,
+	0x8d, 0xa1, 0x32, 0x14, 0x06, 0xfb, 0x83, 0x9e, 0xbe, 0x86, 0x00, 0x4a, 0x9d, 0x1d, 0xbc, 0xbf,
+	0x3f, 0x92, 0x91, 0x63, 0xb7, 0xdf, 0x7e, 0xb7, 0xa7, 0xe7, 0x8c, 0x5f, 0x15, 0x00, 0xe6, 0x11,
+	0x1d, 0xd5, 0x21, 0x97, 0xec, 0x79, 0xce, 0x75, 0xf8, 0x1f, 0xf9, 0x96, 0x17, 0x67, 0x49, 0xa2,
+	0x8d, 0xb6, 0xe1, 0x8a, 0x47, 0xc7, 0xa1, 0x65, 0x1f, 0x9b, 0x2a, 0x06, 0xdb, 0x62, 0xb1, 0xf8,
+	0xb5, 0x1a, 0xbe, 0xa4, 0x06, 0x95, 0xd4, 0x12, 0xf7, 0x7d, 0xc8, 0x13, 0xff, 0xa4, 0x51, 0x10,
+	0x0e, 0xf0, 0xce, 0x72, 0xe9, 0x46, 0xab, 0xe7, 0x9f, 0xc8, 0x0c, 0x94, 0x63, 0xa1, 0x21, 0x54,
+	0x22, 0x42, 0x83, 0x59, 0x64, 0x13, 0xda, 0x28, 0x66, 0x3f, 0x78, 0x38, 0x5e, 0x8c, 0xe7, 0x38,
+	0x68, 0x17, 0x4a, 0x5e, 0x30, 0xf3, 0x19, 0x6d, 0x94, 0x84, 0xa8, 0xaf, 0x64, 0x41, 0xec, 0xf3,
+	0x95, 0x58, 0x01, 0xa0, 0x3d, 0x58, 0x77, 0xc8, 0x89, 0xcb, 0xa5, 0x5b, 0x17, 0x58, 0xdb, 0x99,
+	0xf6, 0x5c, 0x2c, 0xc5, 0x31, 0x04, 0xdf, 0x88, 0x19, 0x25, 0x51, 0xa3, 0x2c, 0x37, 0x82, 0xb7,
+	0xd1, 0x73, 0x50, 0xb1, 0xa6, 0xd3, 0xc0, 0x36, 0x1d, 0x37, 0x6a, 0x54, 0xc4, 0x40, 0x59, 0x10,
+	0xba, 0x6e, 0xd4, 0x7c, 0x0d, 0xca, 0xb1, 0xbe, 0x32, 0xa5, 0xbf, 0x7f, 0xd5, 0xa0, 0x92, 0xa8,
+	0x06, 0x7d, 0x02, 0x1b, 0x91, 0xf5, 0xc0, 0x9c, 0x2b, 0x5a, 0x7a, 0xb8, 0xd7, 0x33, 0x29, 0xda,
+	0x7a, 0x30, 0xd7, 0x75, 0x2d, 0x4a, 0xf5, 0x90, 0x0d, 0x9b, 0x53, 0xd7, 0x9f, 0x3d, 0x4c, 0x31,
+	0x90, 0x11, 0xe4, 0xcd, 0x2c, 0x0c, 0xf6, 0x38, 0xc4, 0x9c, 0x45, 0x7d, 0xba, 0xd0, 0x37, 0xfe,
+	0xac, 0x41, 0x2d, 0x2d, 0x03, 0x57, 0x87, 0x1d, 0xce, 0xc4, 0xaf, 0xe4, 0x31, 0x6f, 0x72, 0xd7,
+	0xe2, 0x11, 0x2f, 0x88, 0x4e, 0x05, 0xfb, 0x3c, 0x56, 0x3d, 0xae, 0x75, 0xc7, 0xa5, 0xc7, 0xc2,
+	0xb2, 0xf3, 0x58, 0xb4, 0x39, 0xcd, 0x0d, 0x42, 0x2a, 0x92, 0xbe, 0x3c, 0x16, 0x6d, 0xf4, 0x01,
+	0x94, 0x55, 0x54, 0xe1, 0xa6, 0x98, 0x5f, 0x32, 0x44, 0xc5, 0x12, 0xe2, 0x04, 0xcc, 0xf8, 0x5d,
+	0x0e, 0x36, 0xcf, 0x8c, 0x72, 0x61, 0xa5, 0x55, 0xc4, 0xbe, 0x59, 0xf6, 0xb8, 0x60, 0xb6, 0xeb,
+	0xc4, 0x59, 0x97, 0x68, 0x8b, 0xf3, 0x1c, 0xaa, 0x8c, 0x28, 0xe7, 0x86, 0x7c, 0xdf, 0xbd, 0x23,
+	0x97, 0x49, 0xe9, 0x8b, 0x58, 0x76, 0xd0, 0xa7, 0x50, 0x8f, 0x08, 0x25, 0xd1, 0x09, 0x71, 0xcc,
+	0x30, 0x88, 0x58, 0xfc, 0x13, 0x3f, 0x5c, 0xe2, 0x27, 0x0e, 0x82, 0x88, 0xe1, 0x8d, 0x18, 0x8e,
+	0xf7, 0x28, 0xfa, 0x18, 0x36, 0x9c, 0x53, 0xdf, 0xf2, 0x5c, 0x5b, 0xc1, 0x97, 0x56, 0x83, 0xaf,
+	0x29, 0x34, 0x81, 0xce, 0x2f, 0x6c, 0xa9, 0x41, 0xfe, 0x8b, 0x53, 0xeb, 0x88, 0x4c, 0x95, 0x76,
+	0x64, 0x67, 0xd1, 0xe0, 0x8b, 0xca, 0xe0, 0x8d, 0xaf, 0x73, 0x50, 0x5f, 0xb4, 0x1e, 0xf4, 0xbf,
+	0x00, 0x76, 0x38, 0x33, 0x43, 0x12, 0xb9, 0x81, 0xa3, 0x6c, 0xa4, 0x62, 0x87, 0xb3, 0x03, 0x41,
+	0xe0, 0x67, 0x8e, 0x0f, 0x7f, 0x31, 0x0b, 0x98, 0xa5, 0x8c, 0xa5, 0x6c, 0x87, 0xb3, 0xf7, 0x79,
+	0x3f, 0x5e, 0x2b, 0x1e, 0x18, 0xa8, 0x32, 0x1a, 0x3e, 0x7d, 0x28, 0x08, 0xe8, 0x15, 0xb8, 0x22,
+	0xed, 0xca, 0x9c, 0xba, 0x9e, 0xcb, 0x4c, 0xd7, 0x37, 0x8f, 0x4e, 0x19, 0x91, 0x9b, 0x91, 0xc7,
+	0x48, 0x0e, 0xee, 0xf1, 0xb1, 0x5d, 0xff, 0x1d, 0x3e, 0x82, 0x0c, 0xd8, 0x08, 0x02, 0xcf, 0xa4,
+	0x76, 0x10, 0x11, 0xd3, 0x72, 0x3e, 0x17, 0x8e, 0x2e, 0x8f, 0xab, 0x41, 0xe0, 0x0d, 0x39, 0xad,
+	0xed, 0x7c, 0x8e, 0x9e, 0x87, 0xaa, 0x1d, 0xce, 0x28, 0x61, 0x26, 0xff, 0x34, 0x4a, 0xe2, 0xb7,
+	0x41, 0x92, 0x3a, 0xe1, 0x8c, 0xa6, 0x26, 0x78, 0xc4, 0xe3, 0xde, 0x28, 0x35, 0xa1, 0x4f, 0x3c,
+	0x6a, 0x7c, 0x02, 0x45, 0xe1, 0xbb, 0xf8, 0xdf, 0x89, 0xb8, 0x1f, 0x5a, 0x6c, 0xa2, 0xf4, 0x57,
+	0xe6, 0x84, 0x03, 0x8b, 0x4d, 0xf8, 0xe0, 0x24, 0xa0, 0x4c, 0x0e, 0x4a, 0x23, 0x2b, 0x73, 0x82,
+	0x18, 0x6c, 0x42, 0x39, 0x22, 0x96, 0x13, 0xf8, 0xd3, 0x53, 0xf1, 0xe3, 0x65, 0x9c, 0xf4, 0x8d,
+	0x2f, 0xa0, 0x24, 0xdd, 0xd9, 0x0a, 0xf8, 0x2f, 0x03, 0xb2, 0xc7, 0x51, 0x30, 0x0b, 0xf9, 0xce,
+	0x78, 0x2e, 0xa5, 0x6e, 0xe0, 0xd3, 0xf8, 0x0d, 0x40, 0x8e, 0x1c, 0xcc, 0x07, 0x8c, 0xef, 0x34,
+	0x80, 0xf9, 0xe5, 0x8d, 0xa7, 0x08, 0x2a, 0x46, 0xad, 0x76, 0xe1, 0x55, 0x28, 0x71, 0xc2, 0x47,
+	0xd4, 0xab, 0xc7, 0x52, 0x09, 0x1f, 0x91, 0x09, 0x1f, 0xe1, 0x89, 0x85, 0x8a, 0xa3, 0x12, 0x53,
+	0x86, 0xd1, 0xaa, 0x93, 0xe4, 0xc4, 0xc4, 0xf8, 0x87, 0x96, 0xb8, 0x81, 0x38, 0x6d, 0x45, 0x36,
+	0x94, 0xf9, 0x61, 0x32, 0x3d, 0x2b, 0x54, 0x17, 0x8b, 0x9d, 0x15, 0xd2, 0xe2, 0x16, 0x3f, 0x3b,
+	0x7d, 0x2b, 0x94, 0x01, 0x76, 0x3d, 0x94, 0x3d, 0xee, 0x53, 0x2c, 0x67, 0xee, 0x53, 0x78, 0x1b,
+	0xbd, 0x08, 0x75, 0x6b, 0xc6, 0x02, 0xd3, 0x72, 0x4e, 0x48, 0xc4, 0x5c, 0x4a, 0xd4, 0x86, 0x6f,
+	0x70, 0x6a, 0x3b, 0x26, 0x36, 0xdf, 0x84, 0x5a, 0x1a, 0xf3, 0x49, 0x41, 0xa8, 0x98, 0x0e, 0x42,
+	0x9f, 0x01, 0xcc, 0x13, 0x33, 0x6e, 0x18, 0xe4, 0xa1, 0xcb, 0x4c, 0x3b, 0x70, 0xa4, 0xcf, 0x2b,
+	0xe2, 0x32, 0x27, 0x74, 0x02, 0x87, 0x9c, 0xc9, 0x75, 0x8b, 0x71, 0xae, 0xcb, 0xcf, 0x22, 0x3f,
+	0x39, 0xc7, 0xee, 0x74, 0x4a, 0x1c, 0x25, 0x61, 0x25, 0x08, 0xbc, 0x7b, 0x82, 0x60, 0xfc, 0x33,
+	0x27, 0x0d, 0x44, 0xde, 0x39, 0x2e, 0x94, 0xf7, 0x24, 0x9b, 0x9e, 0x7f, 0x0a, 0x9b, 0xfe, 0x22,
+	0x6c, 0x52, 0xf7, 0x4b, 0x62, 0x06, 0xbe, 0xc9, 0xa3, 0x8a, 0xe9, 0x1d, 0x29, 0x2f, 0x50, 0xe3,
+	0xe4, 0x7d, 0xbf, 0xeb, 0xd2, 0xe3, 0xfe, 0x11, 0x7a, 0x03, 0x80, 0x32, 0x2b, 0x62, 0xc4, 0x31,
+	0x2d, 0xa6, 0xb2, 0x9c, 0xe6, 0xb9, 0xc4, 0x7a, 0x14, 0x3f, 0xc1, 0xe2, 0x8a, 0x9a, 0xdd, 0x66,
+	0xe8, 0x16, 0xd4, 0xec, 0xc0, 0x0b, 0xa7, 0x44, 0x2d, 0x2e, 0x3d, 0x71, 0x71, 0x35, 0x99, 0xdf,
+	0x66, 0xa9, 0xac, 0x7a, 0xfd, 0xa9, 0x64, 0xd5, 0x7f, 0xd1, 0xe4, 0x35, 0x2b, 0x7d, 0xd5, 0x43,
+	0xd3, 0x47, 0xbc, 0x57, 0xee, 0xad, 0x72, 0x79, 0xfc, 0x4f, 0x8f, 0x95, 0xab, 0x3e, 0x0c, 0x7e,
+	0x97, 0x87, 0x4a, 0x72, 0x5b, 0x3b, 0x67, 0x31, 0xaf, 0x43, 0x25, 0x79, 0x09, 0x6f, 0xe4, 0x9e,
+	0xa8, 0xeb, 0xf9, 0x64, 0x74, 0x0c, 0xc8, 0x1a, 0x8f, 0x93, 0x14, 0xc8, 0x9c, 0x51, 0x6b, 0x1c,
+	0xdf, 0x74, 0x6f, 0x65, 0x55, 0x46, 0x1c, 0xc8, 0x0e, 0x39, 0x08, 0xd6, 0xad, 0xf1, 0x78, 0x81,
+	0x82, 0xbe, 0xd2, 0xe0, 0xca, 0x22, 0x27, 0xf3, 0xe8, 0xd4, 0x0c, 0x5d, 0x47, 0xe5, 0xe6, 0xfd,
+	0xa5, 0xee, 0xae, 0xad, 0x05, 0x26, 0xef, 0x9c, 0x1e, 0xb8, 0x8e, 0x54, 0x3f, 0x8a, 0xce, 0x0d,
+	0x34, 0xbf, 0xd6, 0xe0, 0x99, 0xc7, 0xcc, 0x7f, 0xc4, 0x7e, 0x0c, 0xd3, 0xfb, 0xb1, 0xb2, 0x42,
+	0x52, 0xdb, 0xf9, 0x07, 0x0d, 0xb6, 0xce, 0x4d, 0x40, 0x77, 0xe7, 0xb9, 0x61, 0x75, 0xfb, 0xfb,
+	0x59, 0x98, 0x75, 0x0e, 0x0e, 0x25, 0x0f, 0x91, 0x51, 0xee, 0x2f, 0x64, 0x94, 0x19, 0x73, 0x9d,
+	0xbe, 0x58, 0x29, 0xd1, 0x14, 0x8c, 0xf1, 0xa7, 0x3c, 0x94, 0x63, 0x16, 0x3c, 0xa2, 0xd3, 0x53,
+	0xca, 0x88, 0x67, 0x7a, 0xb1, 0x4f, 0xd4, 0x30, 0x48, 0x52, 0x9f, 0x7b, 0xc5, 0xe7, 0xa0, 0xc2,
+	0xaf, 0x08, 0x72, 0x38, 0x27, 0x86, 0xcb, 0x9c, 0x20, 0x06, 0x9f, 0x87, 0x2a, 0x0b, 0x98, 0x35,
+	0x35, 0x99, 0x6b, 0x1f, 0xcb, 0x20, 0xaa, 0x61, 0x10, 0xa4, 0x11, 0xa7, 0xa0, 0x97, 0x60, 0x8b,
+	0x4d, 0xa2, 0x80, 0xb1, 0x29, 0x4f, 0x08, 0x45, 0xe2, 0x23, 0x93, 0x94, 0x02, 0xd6, 0x93, 0x01,
+	0x99, 0x10, 0x51, 0x1e, 0x0e, 0xe6, 0x93, 0xb9, 0x55, 0x0b, 0x37, 0x55, 0xc0, 0x1b, 0x09, 0x95,
+	0x5b, 0x3d, 0x7f, 0x39, 0x08, 0x49, 0x64, 0x13, 0x5f, 0x7a, 0x22, 0x0d, 0xc7, 0x5d, 0xe4, 0xc0,
+	0xa6, 0x47, 0x2c, 0x3a, 0x8b, 0x88, 0x63, 0xde, 0x77, 0xc9, 0xd4, 0x91, 0x17, 0xa6, 0x7a, 0xb6,
+	0x1c, 0x3a, 0xd6, 0x4d, 0xeb, 0xae, 0x80, 0xc0, 0xf5, 0x18, 0x53, 0xf6, 0x79, 0x12, 0x22, 0x5b,
+	0x68, 0x13, 0xaa, 0xc3, 0x8f, 0x86, 0xa3, 0x5e, 0xdf, 0xec, 0xef, 0x77, 0x7b, 0xea, 0x49, 0x7f,
+	0xd8, 0xc3, 0xb2, 0xab, 0xf1, 0xf1, 0xd1, 0xfe, 0xa8, 0xbd, 0x67, 0x8e, 0x76, 0x3b, 0xf7, 0x86,
+	0x7a, 0x0e, 0x5d, 0x81, 0xad, 0xd1, 0x0e, 0xde, 0x1f, 0x8d, 0xf6, 0x7a, 0x5d, 0xf3, 0xa0, 0x87,
+	0x77, 0xf7, 0xbb, 0x43, 0x3d, 0x8f, 0x10, 0xd4, 0xe7, 0xe4, 0xd1, 0x6e, 0xbf, 0xa7, 0x17, 0xf8,
+	0x5b, 0xed, 0x41, 0x0f, 0x77, 0x7a, 0x83, 0x91, 0x5e, 0x34, 0xfe, 0x96, 0x83, 0x6a, 0x6a, 0x2b,
+	0xb9, 0x71, 0x47, 0x54, 0x5e, 0xa1, 0x0a, 0x98, 0x37, 0xb9, 0xb3, 0xb1, 0x2d, 0x7b, 0x22, 0xb7,
+	0xa8, 0x80, 0x65, 0x87, 0x6f, 0x9e, 0x67, 0x3d, 0x4c, 0xf9, 0x81, 0x02, 0x2e, 0x7b, 0xd6, 0x43,
+	0x09, 0xf2, 0x02, 0xd4, 0x8e, 0x49, 0xe4, 0x93, 0xa9, 0x1a, 0x97, 0xdb, 0x52, 0x95, 0x34, 0x39,
+	0xe5, 0x1a, 0xe8, 0x6a, 0xca, 0x1c, 0x46, 0xee, 0x49, 0x5d, 0xd2, 0xfb, 0x31, 0xd8, 0xf8, 0xbc,
+	0xea, 0x4b, 0x42, 0xf5, 0xb7, 0x97, 0x34, 0xd7, 0xc7, 0x69, 0x7f, 0x98, 0x68, 0x7f, 0x1d, 0xf2,
+	0x38, 0x7e, 0xc9, 0xee, 0xb4, 0x3b, 0x3b, 0x5c, 0xe3, 0x1b, 0x50, 0xe9, 0xb7, 0x3f, 0x34, 0x0f,
+	0xdb, 0x43, 0xfe, 0x26, 0x81, 0x74, 0xa8, 0xdd, 0xeb, 0xe1, 0x41, 0x6f, 0xcf, 0x3c, 0x1c, 0xf2,
+	0x57, 0x8a, 0x3c, 0xba, 0x0c, 0xba, 0xa2, 0x88, 0x79, 0x82, 0x5a, 0x30, 0xfe, 0x98, 0x83, 0x4d,
+	0xe9, 0xfc, 0x93, 0x07, 0xb3, 0xc7, 0xbf, 0x5c, 0x2d, 0xef, 0x9f, 0x1b, 0xb0, 0xee, 0x11, 0x9a,
+	0x6c, 0x46, 0x05, 0xc7, 0x5d, 0xe4, 0x43, 0xd5, 0xf2, 0xfd, 0x80, 0x89, 0xb7, 0x16, 0xaa, 0x3c,
+	0xe8, 0x5e, 0xf6, 0xa7, 0x9d, 0x44, 0xfc, 0x56, 0x7b, 0x0e, 0x27, 0x1d, 0x68, 0x9a, 0x41, 0xf3,
+	0x36, 0xe8, 0x67, 0x27, 0x64, 0x8a, 0x60, 0xd7, 0x61, 0x93, 0xb3, 0xda, 0x0b, 0xc6, 0x4f, 0x7e,
+	0xe8, 0xfc, 0xbd, 0x8a, 0xd7, 0x72, 0xb2, 0x7a, 0x9a, 0xfb, 0x31, 0x7f, 0x82, 0x8b, 0x88, 0xe5,
+	0xa9, 0xa2, 0x4e, 0x27, 0xab, 0x37, 0x4e, 0xa3, 0xb5, 0x86, 0x02, 0x0a, 0x2b, 0x48, 0x71, 0xd9,
+	0xb6, 0xd4, 0xad, 0xaa, 0x86, 0x45, 0xdb, 0xb8, 0x0a, 0x25, 0x39, 0x8b, 0xbf, 0x5f, 0x0d, 0x47,
+	0xdd, 0xfd, 0xc3, 0x91, 0x7c, 0xcb, 0x1a, 0x8e, 0xba, 0x3d, 0x8c, 0x75, 0xed, 0xfa, 0x2b, 0xf3,
+	0xa0, 0x4c, 0xf8, 0xf1, 0x3b, 0x1c, 0xdc, 0x1b, 0xec, 0x7f, 0x30, 0xd0, 0xd7, 0x78, 0x07, 0x1f,
+	0x0e, 0x06, 0xbb, 0x83, 0x77, 0x75, 0x8d, 0x2f, 0xe9, 0x7d, 0xb8, 0xcb, 0x4b, 0x78, 0xb9, 0xed,
+	0x7f, 0xe9, 0x50, 0x92, 0x8a, 0x47, 0xdf, 0xaa, 0xbf, 0x4c, 0x17, 0x94, 0x51, 0x67, 0xb9, 0xdb,
+	0xc1, 0x42, 0xa5, 0xba, 0xd9, 0x5d, 0x0d, 0x44, 0xbd, 0xcd, 0xae, 0xa1, 0xdf, 0x68, 0x50, 0x5b,
+	0x78, 0x87, 0xcc, 0xf4, 0x7a, 0xf6, 0x88, 0x72, 0x76, 0xf3, 0xed, 0xe5, 0x01, 0x12, 0xa9, 0xbe,
+	0xd1, 0xa0, 0x9a, 0x2a, 0xdf, 0xa2, 0xdb, 0x4b, 0xd7, 0x7d, 0xa5, 0x4c, 0x77, 0x56, 0xac, 0x1b,
+	0x1b, 0x6b, 0xdf, 0xd3, 0xd0, 0xaf, 0x35, 0xa8, 0xa6, 0x0a, 0xa0, 0xd9, 0x84, 0x3a, 0x5f, 0xb8,
+	0x6d, 0xde, 0x59, 0x7a, 0x7d, 0xa2, 0xa7, 0x9f, 0x6b, 0x50, 0x49, 0x8a, 0x99, 0xe8, 0xe6, 0x92,
+	0x35, 0x50, 0x29, 0xce, 0xad, 0x95, 0x2a, 0xa8, 0xc6, 0x1a, 0xfa, 0xa9, 0x06, 0xe5, 0xb8, 0xf4,
+	0x87, 0x32, 0x05, 0xd7, 0x33, 0xc5, 0xc5, 0xe6, 0xcd, 0xe5, 0x16, 0x2f, 0x48, 0x12, 0x57, 0xe5,
+	0xb2, 0x49, 0x72, 0xa6, 0x88, 0xd8, 0xbc, 0xb9, 0xdc, 0xe2, 0x44, 0x12, 0x6e, 0x33, 0xa9, 0xe2,
+	0x5e, 0x36, 0x9b, 0x39, 0x5f, 0x44, 0x6c, 0xde, 0x59, 0x7a, 0xfd, 0x82, 0xcd, 0x24, 0x45, 0xc4,
+	0x6c, 0x36, 0x73, 0xb6, 0x1e, 0xd9, 0xbc, 0xb5, 0xe4, 0xea, 0x05, 0xfd, 0xa4, 0xca, 0x87, 0xd9,
+	0xf4, 0x73, 0xbe, 0x4e, 0xd9, 0xbc, 0xb3, 0xf4, 0xfa, 0x05, 0xfd, 0xcc, 0xef, 0x61, 0x37, 0x97,
+	0x2b, 0xb6, 0x2d, 0xa3, 0x9f, 0x73, 0x85, 0x3f, 0x63, 0x0d, 0xfd, 0x42, 0x3d, 0x34, 0xc9, 0x72,
+	0x1d, 0xca, 0x8c, 0xb7, 0x50, 0xe6, 0x6b, 0xbe, 0xb5, 0x42, 0xee, 0x20, 0x5c, 0xe0, 0xcf, 0x34,
+	0x28, 0xc7, 0x71, 0x36, 0xdb, 0xc1, 0x3a, 0x93, 0x18, 0x34, 0x6f, 0xae, 0x12, 0xda, 0x85, 0x2c,
+	0xbf, 0xd4, 0x00, 0xe6, 0xe5, 0xc6, 0x6c, 0xaa, 0x39, 0x57, 0xec, 0x6c, 0xde, 0x5e, 0x76, 0xf9,
+	0x82, 0xd3, 0x89, 0x8b, 0x8d, 0xd9, 0x74, 0x73, 0xa6, 0x3c, 0xda, 0xbc, 0xb9, 0xdc, 0xe2, 0x58,
+	0x92, 0x77, 0xd6, 0x7f, 0x54, 0x14, 0x63, 0x47, 0x25, 0xf1, 0x79, 0xf5, 0xdf, 0x03, 0x00, 0x92,
+	0x1d, 0x27, 0x7f, 0xaf, 0x27, 0x00, 0x00,
 }
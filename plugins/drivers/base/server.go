@@ -0,0 +1,304 @@
+package base
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers/base/proto"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// driverPluginServer wraps a driver plugin and exposes it via gRPC.
+type driverPluginServer struct {
+	broker *plugin.GRPCBroker
+	impl   DriverPlugin
+}
+
+func (d *driverPluginServer) TaskConfigSchema(ctx context.Context, req *proto.TaskConfigSchemaRequest) (*proto.TaskConfigSchemaResponse, error) {
+	spec, err := d.impl.TaskConfigSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.TaskConfigSchemaResponse{Spec: spec}, nil
+}
+
+func (d *driverPluginServer) Capabilities(ctx context.Context, req *proto.CapabilitiesRequest) (*proto.CapabilitiesResponse, error) {
+	caps, err := d.impl.Capabilities()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.CapabilitiesResponse{
+		Capabilities: &proto.DriverCapabilities{
+			SendSignals: caps.SendSignals,
+			Exec:        caps.Exec,
+			FsIsolation: fsIsolationToProtoMap[caps.FSIsolation],
+		},
+	}
+
+	return resp, nil
+}
+
+func (d *driverPluginServer) Fingerprint(req *proto.FingerprintRequest, stream proto.Driver_FingerprintServer) error {
+	ctx := stream.Context()
+	outCh, err := d.impl.Fingerprint(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case f, ok := <-outCh:
+			// The output channel has been closed, end the stream
+			if !ok {
+				return nil
+			}
+
+			// Handle any error
+			if f.Err != nil {
+				return f.Err
+			}
+
+			resp := &proto.FingerprintResponse{
+				Attributes:        f.Attributes,
+				Health:            healthStateToProtoMap[f.Health],
+				HealthDescription: f.HealthDescription,
+			}
+
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *driverPluginServer) RecoverTask(ctx context.Context, req *proto.RecoverTaskRequest) (*proto.RecoverTaskResponse, error) {
+	if err := d.impl.RecoverTask(taskHandleFromProto(req.Handle)); err != nil {
+		return nil, err
+	}
+
+	return &proto.RecoverTaskResponse{}, nil
+}
+
+func (d *driverPluginServer) StartTask(ctx context.Context, req *proto.StartTaskRequest) (*proto.StartTaskResponse, error) {
+	handle, err := d.impl.StartTask(taskConfigFromProto(req.Task))
+	if err != nil {
+		// Errors starting the task are reported in the response so the
+		// client can decide whether to retry.
+		result := proto.StartTaskResponse_FATAL
+		if structs.IsRecoverable(err) {
+			result = proto.StartTaskResponse_RETRY
+		}
+
+		return &proto.StartTaskResponse{
+			Result:         result,
+			DriverErrorMsg: err.Error(),
+		}, nil
+	}
+
+	resp := &proto.StartTaskResponse{
+		Result: proto.StartTaskResponse_SUCCESS,
+		Handle: taskHandleToProto(handle),
+	}
+
+	return resp, nil
+}
+
+func (d *driverPluginServer) WaitTask(ctx context.Context, req *proto.WaitTaskRequest) (*proto.WaitTaskResponse, error) {
+	ch, err := d.impl.WaitTask(ctx, req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *ExitResult
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-ch:
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("task %q exited without a result", req.TaskId)
+	}
+
+	resp := &proto.WaitTaskResponse{
+		Result: exitResultToProto(result),
+	}
+	if result.Err != nil {
+		resp.Err = result.Err.Error()
+	}
+
+	return resp, nil
+}
+
+func (d *driverPluginServer) StopTask(ctx context.Context, req *proto.StopTaskRequest) (*proto.StopTaskResponse, error) {
+	timeout, err := ptypes.Duration(req.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.impl.StopTask(req.TaskId, timeout, req.Signal); err != nil {
+		return nil, err
+	}
+
+	return &proto.StopTaskResponse{}, nil
+}
+
+func (d *driverPluginServer) DestroyTask(ctx context.Context, req *proto.DestroyTaskRequest) (*proto.DestroyTaskResponse, error) {
+	if err := d.impl.DestroyTask(req.TaskId); err != nil {
+		return nil, err
+	}
+
+	return &proto.DestroyTaskResponse{}, nil
+}
+
+// ListTasks is not part of the DriverPlugin interface as the client tracks the
+// tasks it has started itself.
+func (d *driverPluginServer) ListTasks(ctx context.Context, req *proto.ListTasksRequest) (*proto.ListTasksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "ListTasks is not implemented")
+}
+
+func (d *driverPluginServer) InspectTask(ctx context.Context, req *proto.InspectTaskRequest) (*proto.InspectTaskResponse, error) {
+	ts, err := d.impl.InspectTask(req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+
+	pstatus, err := taskStatusToProto(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.InspectTaskResponse{
+		Task: pstatus,
+		Driver: &proto.TaskDriverStatus{
+			Attributes: ts.DriverAttributes,
+		},
+	}
+
+	return resp, nil
+}
+
+func (d *driverPluginServer) TaskStats(ctx context.Context, req *proto.TaskStatsRequest) (*proto.TaskStatsResponse, error) {
+	stats, err := d.impl.TaskStats(req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+
+	pstats, err := taskStatsToProto(req.TaskId, stats)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proto.TaskStatsResponse{Stats: pstats}, nil
+}
+
+func (d *driverPluginServer) TaskEvents(req *proto.TaskEventsRequest, stream proto.Driver_TaskEventsServer) error {
+	ctx := stream.Context()
+	ch, err := d.impl.TaskEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-ch:
+			// The output channel has been closed, end the stream
+			if !ok {
+				return nil
+			}
+
+			// Handle any error
+			if event.Err != nil {
+				return event.Err
+			}
+
+			ts, err := ptypes.TimestampProto(event.Timestamp)
+			if err != nil {
+				return err
+			}
+
+			pevent := &proto.DriverTaskEvent{
+				TaskId:      event.TaskID,
+				Timestamp:   ts,
+				Message:     event.Message,
+				Annotations: event.Annotations,
+			}
+
+			if err := stream.Send(pevent); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *driverPluginServer) TaskLogs(req *proto.TaskLogsRequest, stream proto.Driver_TaskLogsServer) error {
+	ctx := stream.Context()
+	ch, err := d.impl.TaskLogs(ctx, req.GetTaskId())
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case log, ok := <-ch:
+			// The output channel has been closed, end the stream
+			if !ok {
+				return nil
+			}
+
+			// Handle any error
+			if log.Err != nil {
+				return log.Err
+			}
+
+			resp := &proto.TaskLogsResponse{
+				Stream: logStreamToProtoMap[log.Stream],
+				Data:   log.Data,
+			}
+
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (d *driverPluginServer) SignalTask(ctx context.Context, req *proto.SignalTaskRequest) (*proto.SignalTaskResponse, error) {
+	if err := d.impl.SignalTask(req.TaskId, req.Signal); err != nil {
+		return nil, err
+	}
+
+	return &proto.SignalTaskResponse{}, nil
+}
+
+func (d *driverPluginServer) ExecTask(ctx context.Context, req *proto.ExecTaskRequest) (*proto.ExecTaskResponse, error) {
+	timeout, err := ptypes.Duration(req.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := d.impl.ExecTask(req.TaskId, req.Command, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &proto.ExecTaskResponse{
+		Stdout: result.Stdout,
+		Stderr: result.Stderr,
+		Result: exitResultToProto(result.ExitResult),
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,536 @@
+package base
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/plugins/drivers/base/proto"
+)
+
+var healthStateToProtoMap = map[HealthState]proto.FingerprintResponse_HealthState{
+	HealthStateUndetected: proto.FingerprintResponse_UNDETECTED,
+	HealthStateUnhealthy:  proto.FingerprintResponse_UNHEALTHY,
+	HealthStateHealthy:    proto.FingerprintResponse_HEALTHY,
+}
+
+var healthStateFromProtoMap = map[proto.FingerprintResponse_HealthState]HealthState{
+	proto.FingerprintResponse_UNDETECTED: HealthStateUndetected,
+	proto.FingerprintResponse_UNHEALTHY:  HealthStateUnhealthy,
+	proto.FingerprintResponse_HEALTHY:    HealthStateHealthy,
+}
+
+var fsIsolationToProtoMap = map[FSIsolation]proto.DriverCapabilities_FSIsolation{
+	FSIsolationNone:   proto.DriverCapabilities_NONE,
+	FSIsolationChroot: proto.DriverCapabilities_CHROOT,
+	FSIsolationImage:  proto.DriverCapabilities_IMAGE,
+}
+
+var fsIsolationFromProtoMap = map[proto.DriverCapabilities_FSIsolation]FSIsolation{
+	proto.DriverCapabilities_NONE:   FSIsolationNone,
+	proto.DriverCapabilities_CHROOT: FSIsolationChroot,
+	proto.DriverCapabilities_IMAGE:  FSIsolationImage,
+}
+
+var logStreamToProtoMap = map[LogStream]proto.TaskLogsResponse_Stream{
+	LogStreamStdout: proto.TaskLogsResponse_STDOUT,
+	LogStreamStderr: proto.TaskLogsResponse_STDERR,
+}
+
+var logStreamFromProtoMap = map[proto.TaskLogsResponse_Stream]LogStream{
+	proto.TaskLogsResponse_STDOUT: LogStreamStdout,
+	proto.TaskLogsResponse_STDERR: LogStreamStderr,
+}
+
+var taskStateToProtoMap = map[TaskState]proto.TaskState{
+	TaskStateUnknown: proto.TaskState_UNKNOWN,
+	TaskStateRunning: proto.TaskState_RUNNING,
+	TaskStateExited:  proto.TaskState_EXITED,
+}
+
+var taskStateFromProtoMap = map[proto.TaskState]TaskState{
+	proto.TaskState_UNKNOWN: TaskStateUnknown,
+	proto.TaskState_RUNNING: TaskStateRunning,
+	proto.TaskState_EXITED:  TaskStateExited,
+}
+
+// taskConfigFromProto converts between a proto and struct TaskConfig
+func taskConfigFromProto(in *proto.TaskConfig) *TaskConfig {
+	if in == nil {
+		return nil
+	}
+
+	return &TaskConfig{
+		ID:              in.Id,
+		Name:            in.Name,
+		Env:             in.Env,
+		Resources:       resourcesFromProto(in.Resources),
+		Mounts:          mountsFromProto(in.Mounts),
+		Devices:         devicesFromProto(in.Devices),
+		User:            in.User,
+		AllocDir:        in.AllocDir,
+		rawDriverConfig: in.MsgpackDriverConfig,
+	}
+}
+
+// taskConfigToProto converts between a struct and proto TaskConfig
+func taskConfigToProto(in *TaskConfig) *proto.TaskConfig {
+	if in == nil {
+		return nil
+	}
+
+	return &proto.TaskConfig{
+		Id:                  in.ID,
+		Name:                in.Name,
+		Env:                 in.Env,
+		Resources:           resourcesToProto(in.Resources),
+		Mounts:              mountsToProto(in.Mounts),
+		Devices:             devicesToProto(in.Devices),
+		User:                in.User,
+		AllocDir:            in.AllocDir,
+		MsgpackDriverConfig: in.rawDriverConfig,
+	}
+}
+
+// resourcesFromProto converts between a proto and struct Resources
+func resourcesFromProto(in *proto.Resources) *Resources {
+	if in == nil {
+		return nil
+	}
+
+	out := &Resources{}
+	if raw := in.RawResources; raw != nil {
+		out.CPU = raw.Cpu
+		out.MemoryMB = raw.Memory
+		out.DiskMB = raw.Disk
+		out.IOPS = raw.Iops
+	}
+
+	if linux := in.LinuxResources; linux != nil {
+		out.Linux = &LinuxResources{
+			CPUPeriod:        linux.CpuPeriod,
+			CPUQuota:         linux.CpuQuota,
+			CPUShares:        linux.CpuShares,
+			MemoryLimitBytes: linux.MemoryLimitInBytes,
+			OOMScoreAdj:      linux.OomScoreAdj,
+			CpusetCPUs:       linux.CpusetCpus,
+			CpusetMems:       linux.CpusetMems,
+		}
+	}
+
+	return out
+}
+
+// resourcesToProto converts between a struct and proto Resources
+func resourcesToProto(in *Resources) *proto.Resources {
+	if in == nil {
+		return nil
+	}
+
+	out := &proto.Resources{
+		RawResources: &proto.RawResources{
+			Cpu:    in.CPU,
+			Memory: in.MemoryMB,
+			Disk:   in.DiskMB,
+			Iops:   in.IOPS,
+		},
+	}
+
+	if linux := in.Linux; linux != nil {
+		out.LinuxResources = &proto.LinuxResources{
+			CpuPeriod:          linux.CPUPeriod,
+			CpuQuota:           linux.CPUQuota,
+			CpuShares:          linux.CPUShares,
+			MemoryLimitInBytes: linux.MemoryLimitBytes,
+			OomScoreAdj:        linux.OOMScoreAdj,
+			CpusetCpus:         linux.CpusetCPUs,
+			CpusetMems:         linux.CpusetMems,
+		}
+	}
+
+	return out
+}
+
+// mountsFromProto converts between a list of proto and struct Mounts
+func mountsFromProto(in []*proto.Mount) []*MountConfig {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]*MountConfig, len(in))
+	for i, m := range in {
+		out[i] = &MountConfig{
+			TaskPath: m.TaskPath,
+			HostPath: m.HostPath,
+			Readonly: m.Readonly,
+		}
+	}
+
+	return out
+}
+
+// mountsToProto converts between a list of struct and proto Mounts
+func mountsToProto(in []*MountConfig) []*proto.Mount {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]*proto.Mount, len(in))
+	for i, m := range in {
+		out[i] = &proto.Mount{
+			TaskPath: m.TaskPath,
+			HostPath: m.HostPath,
+			Readonly: m.Readonly,
+		}
+	}
+
+	return out
+}
+
+// devicesFromProto converts between a list of proto and struct Devices
+func devicesFromProto(in []*proto.Device) []*DeviceConfig {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]*DeviceConfig, len(in))
+	for i, d := range in {
+		out[i] = &DeviceConfig{
+			TaskPath:    d.TaskPath,
+			HostPath:    d.HostPath,
+			Permissions: d.CgroupPermissions,
+		}
+	}
+
+	return out
+}
+
+// devicesToProto converts between a list of struct and proto Devices
+func devicesToProto(in []*DeviceConfig) []*proto.Device {
+	if in == nil {
+		return nil
+	}
+
+	out := make([]*proto.Device, len(in))
+	for i, d := range in {
+		out[i] = &proto.Device{
+			TaskPath:          d.TaskPath,
+			HostPath:          d.HostPath,
+			CgroupPermissions: d.Permissions,
+		}
+	}
+
+	return out
+}
+
+// taskHandleFromProto converts between a proto and struct TaskHandle
+func taskHandleFromProto(in *proto.TaskHandle) *TaskHandle {
+	if in == nil {
+		return nil
+	}
+
+	return &TaskHandle{
+		Config:      taskConfigFromProto(in.Config),
+		State:       taskStateFromProtoMap[in.State],
+		DriverState: in.DriverState,
+	}
+}
+
+// taskHandleToProto converts between a struct and proto TaskHandle
+func taskHandleToProto(in *TaskHandle) *proto.TaskHandle {
+	if in == nil {
+		return nil
+	}
+
+	return &proto.TaskHandle{
+		Config:      taskConfigToProto(in.Config),
+		State:       taskStateToProtoMap[in.State],
+		DriverState: in.DriverState,
+	}
+}
+
+// exitResultFromProto converts between a proto and struct ExitResult
+func exitResultFromProto(in *proto.ExitResult) *ExitResult {
+	if in == nil {
+		return nil
+	}
+
+	return &ExitResult{
+		ExitCode:  int(in.ExitCode),
+		Signal:    int(in.Signal),
+		OOMKilled: in.OomKilled,
+	}
+}
+
+// exitResultToProto converts between a struct and proto ExitResult
+func exitResultToProto(in *ExitResult) *proto.ExitResult {
+	if in == nil {
+		return nil
+	}
+
+	return &proto.ExitResult{
+		ExitCode:  int32(in.ExitCode),
+		Signal:    int32(in.Signal),
+		OomKilled: in.OOMKilled,
+	}
+}
+
+// taskStatusFromProto converts between a proto and struct TaskStatus
+func taskStatusFromProto(in *proto.TaskStatus) (*TaskStatus, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &TaskStatus{
+		ID:           in.Id,
+		Name:         in.Name,
+		State:        taskStateFromProtoMap[in.State],
+		SizeOnDiskMB: in.SizeOnDiskMb,
+		ExitResult:   exitResultFromProto(in.Result),
+	}
+
+	if in.StartedAt != nil {
+		started, err := ptypes.Timestamp(in.StartedAt)
+		if err != nil {
+			return nil, err
+		}
+		out.StartedAt = started
+	}
+
+	if in.CompletedAt != nil {
+		completed, err := ptypes.Timestamp(in.CompletedAt)
+		if err != nil {
+			return nil, err
+		}
+		out.CompletedAt = completed
+	}
+
+	return out, nil
+}
+
+// taskStatusToProto converts between a struct and proto TaskStatus
+func taskStatusToProto(in *TaskStatus) (*proto.TaskStatus, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &proto.TaskStatus{
+		Id:           in.ID,
+		Name:         in.Name,
+		State:        taskStateToProtoMap[in.State],
+		SizeOnDiskMb: in.SizeOnDiskMB,
+		Result:       exitResultToProto(in.ExitResult),
+	}
+
+	if !in.StartedAt.IsZero() {
+		started, err := ptypes.TimestampProto(in.StartedAt)
+		if err != nil {
+			return nil, err
+		}
+		out.StartedAt = started
+	}
+
+	if !in.CompletedAt.IsZero() {
+		completed, err := ptypes.TimestampProto(in.CompletedAt)
+		if err != nil {
+			return nil, err
+		}
+		out.CompletedAt = completed
+	}
+
+	return out, nil
+}
+
+// taskStatsFromProto converts between a proto TaskStats and a
+// TaskResourceUsage
+func taskStatsFromProto(in *proto.TaskStats) (*cstructs.TaskResourceUsage, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	out := &cstructs.TaskResourceUsage{
+		ResourceUsage: resourceUsageFromProto(in.AggResourceUsage),
+	}
+
+	if in.Timestamp != nil {
+		ts, err := ptypes.Timestamp(in.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		out.Timestamp = ts.UnixNano()
+	}
+
+	if len(in.ResourceUsageByPid) != 0 {
+		out.Pids = make(map[string]*cstructs.ResourceUsage, len(in.ResourceUsageByPid))
+		for pid, ru := range in.ResourceUsageByPid {
+			out.Pids[pid] = resourceUsageFromProto(ru)
+		}
+	}
+
+	return out, nil
+}
+
+// taskStatsToProto converts between a TaskResourceUsage and a proto TaskStats
+func taskStatsToProto(id string, in *cstructs.TaskResourceUsage) (*proto.TaskStats, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	ts, err := ptypes.TimestampProto(time.Unix(0, in.Timestamp))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &proto.TaskStats{
+		Id:               id,
+		Timestamp:        ts,
+		AggResourceUsage: resourceUsageToProto(in.ResourceUsage),
+	}
+
+	if len(in.Pids) != 0 {
+		out.ResourceUsageByPid = make(map[string]*proto.TaskResourceUsage, len(in.Pids))
+		for pid, ru := range in.Pids {
+			out.ResourceUsageByPid[pid] = resourceUsageToProto(ru)
+		}
+	}
+
+	return out, nil
+}
+
+// resourceUsageFromProto converts between a proto and struct ResourceUsage
+func resourceUsageFromProto(in *proto.TaskResourceUsage) *cstructs.ResourceUsage {
+	if in == nil {
+		return nil
+	}
+
+	out := &cstructs.ResourceUsage{}
+	if cpu := in.Cpu; cpu != nil {
+		out.CpuStats = &cstructs.CpuStats{
+			SystemMode:       cpu.SystemMode,
+			UserMode:         cpu.UserMode,
+			TotalTicks:       cpu.TotalTicks,
+			ThrottledPeriods: cpu.ThrottledPeriods,
+			ThrottledTime:    cpu.ThrottledTime,
+			Percent:          cpu.Percent,
+		}
+		for _, f := range cpu.MeasuredFields {
+			out.CpuStats.Measured = append(out.CpuStats.Measured, cpuUsageMeasuredFieldToStats(f))
+		}
+	}
+
+	if mem := in.Memory; mem != nil {
+		out.MemoryStats = &cstructs.MemoryStats{
+			RSS:            mem.Rss,
+			Cache:          mem.Cache,
+			MaxUsage:       mem.MaxUsage,
+			KernelUsage:    mem.KernelUsage,
+			KernelMaxUsage: mem.KernelMaxUsage,
+		}
+		for _, f := range mem.MeasuredFields {
+			out.MemoryStats.Measured = append(out.MemoryStats.Measured, memoryUsageMeasuredFieldToStats(f))
+		}
+	}
+
+	return out
+}
+
+// resourceUsageToProto converts between a struct and proto ResourceUsage
+func resourceUsageToProto(in *cstructs.ResourceUsage) *proto.TaskResourceUsage {
+	if in == nil {
+		return nil
+	}
+
+	out := &proto.TaskResourceUsage{}
+	if cpu := in.CpuStats; cpu != nil {
+		out.Cpu = &proto.CPUUsage{
+			SystemMode:       cpu.SystemMode,
+			UserMode:         cpu.UserMode,
+			TotalTicks:       cpu.TotalTicks,
+			ThrottledPeriods: cpu.ThrottledPeriods,
+			ThrottledTime:    cpu.ThrottledTime,
+			Percent:          cpu.Percent,
+		}
+		for _, m := range cpu.Measured {
+			if f, ok := cpuUsageMeasuredFieldFromStats(m); ok {
+				out.Cpu.MeasuredFields = append(out.Cpu.MeasuredFields, f)
+			}
+		}
+	}
+
+	if mem := in.MemoryStats; mem != nil {
+		out.Memory = &proto.MemoryUsage{
+			Rss:            mem.RSS,
+			Cache:          mem.Cache,
+			MaxUsage:       mem.MaxUsage,
+			KernelUsage:    mem.KernelUsage,
+			KernelMaxUsage: mem.KernelMaxUsage,
+		}
+		for _, m := range mem.Measured {
+			if f, ok := memoryUsageMeasuredFieldFromStats(m); ok {
+				out.Memory.MeasuredFields = append(out.Memory.MeasuredFields, f)
+			}
+		}
+	}
+
+	return out
+}
+
+var cpuUsageMeasuredFields = map[proto.CPUUsage_Fields]string{
+	proto.CPUUsage_SYSTEM_MODE:       "System Mode",
+	proto.CPUUsage_USER_MODE:         "User Mode",
+	proto.CPUUsage_TOTAL_TICKS:       "Total Ticks",
+	proto.CPUUsage_THROTTLED_PERIODS: "Throttled Periods",
+	proto.CPUUsage_THROTTLED_TIME:    "Throttled Time",
+	proto.CPUUsage_PERCENT:           "Percent",
+}
+
+func cpuUsageMeasuredFieldToStats(f proto.CPUUsage_Fields) string {
+	if s, ok := cpuUsageMeasuredFields[f]; ok {
+		return s
+	}
+	return strconv.Itoa(int(f))
+}
+
+func cpuUsageMeasuredFieldFromStats(s string) (proto.CPUUsage_Fields, bool) {
+	for f, name := range cpuUsageMeasuredFields {
+		if name == s {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+var memoryUsageMeasuredFields = map[proto.MemoryUsage_Fields]string{
+	proto.MemoryUsage_RSS:              "RSS",
+	proto.MemoryUsage_CACHE:            "Cache",
+	proto.MemoryUsage_MAX_UASGE:        "Max Usage",
+	proto.MemoryUsage_KERNEL_USAGE:     "Kernel Usage",
+	proto.MemoryUsage_KERNEL_MAX_USAGE: "Kernel Max Usage",
+}
+
+func memoryUsageMeasuredFieldToStats(f proto.MemoryUsage_Fields) string {
+	if s, ok := memoryUsageMeasuredFields[f]; ok {
+		return s
+	}
+	return strconv.Itoa(int(f))
+}
+
+func memoryUsageMeasuredFieldFromStats(s string) (proto.MemoryUsage_Fields, bool) {
+	for f, name := range memoryUsageMeasuredFields {
+		if name == s {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// errFromString converts an error message sent over the wire back to an
+// error.
+func errFromString(s string) error {
+	if s == "" {
+		return nil
+	}
+	return errors.New(s)
+}
@@ -5,6 +5,7 @@ import (
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/plugins/device"
+	driver "github.com/hashicorp/nomad/plugins/drivers/base"
 )
 
 // PluginFactory returns a new plugin instance
@@ -21,6 +22,8 @@ func Serve(f PluginFactory) {
 	switch p := plugin.(type) {
 	case device.DevicePlugin:
 		device.Serve(p, logger)
+	case driver.DriverPlugin:
+		driver.Serve(p, logger)
 	default:
 		fmt.Println("Unsupported plugin type")
 	}
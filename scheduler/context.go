@@ -122,6 +122,11 @@ func (e *EvalContext) ProposedAllocs(nodeID string) ([]*structs.Allocation, erro
 		proposed = structs.RemoveAllocs(existingAlloc, update)
 	}
 
+	// Remove the allocations that are preempted by the plan
+	if preempted := e.plan.NodePreemptions[nodeID]; len(preempted) > 0 {
+		proposed = structs.RemoveAllocs(proposed, preempted)
+	}
+
 	// We create an index of the existing allocations so that if an inplace
 	// update occurs, we do not double count and we override the old allocation.
 	proposedIDs := make(map[string]*structs.Allocation, len(proposed))
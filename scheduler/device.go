@@ -0,0 +1,201 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// deviceAllocator is used to assign the free device instances of a node to
+// device requests.
+type deviceAllocator struct {
+	*structs.DeviceAccounter
+
+	ctx Context
+}
+
+// newDeviceAllocator returns a device allocator for the devices of the node.
+func newDeviceAllocator(ctx Context, n *structs.Node) *deviceAllocator {
+	return &deviceAllocator{
+		ctx:             ctx,
+		DeviceAccounter: structs.NewDeviceAccounter(n),
+	}
+}
+
+// AssignDevice picks free device instances for the request. When more than
+// one device group can satisfy the request, the group that best matches the
+// affinities of the request is chosen.
+func (d *deviceAllocator) AssignDevice(ask *structs.RequestedDevice) (*structs.AllocatedDeviceResource, error) {
+	var offer *structs.AllocatedDeviceResource
+	var offerScore float64
+
+	// Determine the total weight of the affinities so scores are comparable
+	var sumWeight float64
+	for _, a := range ask.Affinities {
+		sumWeight += math.Abs(a.Weight)
+	}
+
+	for id, devInst := range d.Devices {
+		// Check if the device works
+		if !nodeDeviceMatches(d.ctx, devInst.Device, ask) {
+			continue
+		}
+
+		// Check if we have enough unused instances to use this
+		if devInst.FreeCount() < int(ask.Count) {
+			continue
+		}
+
+		// Score the choice
+		var choiceScore float64
+		if sumWeight != 0 {
+			for _, a := range ask.Affinities {
+				lVal, lOk := resolveDeviceTarget(a.LTarget, devInst.Device)
+				rVal, rOk := resolveDeviceTarget(a.RTarget, devInst.Device)
+				if lOk && rOk && checkDeviceAffinity(d.ctx, a.Operand, lVal, rVal) {
+					choiceScore += a.Weight
+				}
+			}
+			choiceScore /= sumWeight
+		}
+
+		// Only use the device if it is better than what we have
+		if offer != nil && choiceScore <= offerScore {
+			continue
+		}
+
+		offerScore = choiceScore
+		offer = &structs.AllocatedDeviceResource{
+			Vendor:    id.Vendor,
+			Type:      id.Type,
+			Name:      id.Name,
+			DeviceIDs: make([]string, 0, ask.Count),
+		}
+
+		// Pick the free instances in the order they were fingerprinted
+		for _, instance := range devInst.Device.Instances {
+			if uint64(len(offer.DeviceIDs)) == ask.Count {
+				break
+			}
+			if used, ok := devInst.Instances[instance.ID]; ok && used == 0 {
+				offer.DeviceIDs = append(offer.DeviceIDs, instance.ID)
+			}
+		}
+	}
+
+	if offer == nil {
+		return nil, fmt.Errorf("no devices match request")
+	}
+
+	return offer, nil
+}
+
+// nodeDeviceMatches checks if the device group matches the ID and satisfies
+// the constraints of the request.
+func nodeDeviceMatches(ctx Context, d *structs.NodeDeviceResource, req *structs.RequestedDevice) bool {
+	if !req.ID().Matches(d.ID()) {
+		return false
+	}
+
+	for _, c := range req.Constraints {
+		lVal, lOk := resolveDeviceTarget(c.LTarget, d)
+		rVal, rOk := resolveDeviceTarget(c.RTarget, d)
+		if !lOk || !rOk {
+			return false
+		}
+
+		if !checkDeviceConstraint(ctx, c.Operand, lVal, rVal) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkDeviceConstraint checks if a device constraint is satisfied. Ordering
+// operands compare numeric values numerically.
+func checkDeviceConstraint(ctx Context, operand string, lVal, rVal interface{}) bool {
+	if match, ok := checkNumericOrder(operand, lVal, rVal); ok {
+		return match
+	}
+	return checkConstraint(ctx, operand, lVal, rVal)
+}
+
+// checkDeviceAffinity checks if a device affinity is satisfied. Ordering
+// operands compare numeric values numerically.
+func checkDeviceAffinity(ctx Context, operand string, lVal, rVal interface{}) bool {
+	if match, ok := checkNumericOrder(operand, lVal, rVal); ok {
+		return match
+	}
+	return checkAffinity(ctx, operand, lVal, rVal)
+}
+
+// checkNumericOrder compares device attributes with an ordering operand
+// numerically, as device attributes such as memory are commonly numbers.
+// Returns false for ok when the operand isn't an ordering or either value
+// isn't a number.
+func checkNumericOrder(op string, lVal, rVal interface{}) (match, ok bool) {
+	switch op {
+	case "<", "<=", ">", ">=":
+	default:
+		return false, false
+	}
+
+	lStr, lOk := lVal.(string)
+	rStr, rOk := rVal.(string)
+	if !lOk || !rOk {
+		return false, false
+	}
+
+	l, err := strconv.ParseFloat(lStr, 64)
+	if err != nil {
+		return false, false
+	}
+	r, err := strconv.ParseFloat(rStr, 64)
+	if err != nil {
+		return false, false
+	}
+
+	switch op {
+	case "<":
+		return l < r, true
+	case "<=":
+		return l <= r, true
+	case ">":
+		return l > r, true
+	default:
+		return l >= r, true
+	}
+}
+
+// resolveDeviceTarget is used to resolve the LTarget and RTarget of a device
+// constraint or affinity against a device group.
+func resolveDeviceTarget(target string, d *structs.NodeDeviceResource) (interface{}, bool) {
+	// If no prefix, this must be a literal value
+	if !strings.HasPrefix(target, "${") {
+		return target, true
+	}
+
+	// Handle the interpolations
+	switch {
+	case "${device.vendor}" == target:
+		return d.Vendor, true
+
+	case "${device.type}" == target:
+		return d.Type, true
+
+	case "${device.model}" == target:
+		return d.Name, true
+
+	case strings.HasPrefix(target, "${device.attr."):
+		attr := strings.TrimSuffix(strings.TrimPrefix(target, "${device.attr."), "}")
+		val, ok := d.Attributes[attr]
+		return val, ok
+
+	default:
+		return nil, false
+	}
+}
@@ -142,7 +142,12 @@ func (c *DriverChecker) hasDrivers(option *structs.Node) bool {
 				return false
 			}
 
-			return driverInfo.Detected && driverInfo.Healthy
+			// Every requested driver must be healthy, so only stop checking
+			// early if this one is not
+			if !driverInfo.Detected || !driverInfo.Healthy {
+				return false
+			}
+			continue
 		}
 
 		value, ok := option.Attributes[driverStr]
@@ -165,6 +170,225 @@ func (c *DriverChecker) hasDrivers(option *structs.Node) bool {
 	return true
 }
 
+// HostVolumeChecker is a FeasibilityChecker which returns whether a node has
+// the host volumes necessary to schedule a task group.
+type HostVolumeChecker struct {
+	ctx     Context
+	volumes map[string]*structs.VolumeRequest
+}
+
+// NewHostVolumeChecker creates a HostVolumeChecker. The volumes are set
+// per task group with SetVolumes.
+func NewHostVolumeChecker(ctx Context) *HostVolumeChecker {
+	return &HostVolumeChecker{
+		ctx: ctx,
+	}
+}
+
+// SetVolumes takes the volumes requested by the task group and keeps the
+// ones that are backed by host volumes.
+func (h *HostVolumeChecker) SetVolumes(volumes map[string]*structs.VolumeRequest) {
+	h.volumes = make(map[string]*structs.VolumeRequest)
+	for name, req := range volumes {
+		if req.Type != structs.VolumeTypeHost {
+			continue
+		}
+		h.volumes[name] = req
+	}
+}
+
+func (h *HostVolumeChecker) Feasible(candidate *structs.Node) bool {
+	if h.hasVolumes(candidate) {
+		return true
+	}
+
+	h.ctx.Metrics().FilterNode(candidate, "missing compatible host volumes")
+	return false
+}
+
+// hasVolumes is used to check if the node exposes every host volume requested
+// by the task group, and that writable requests aren't matched to read only
+// host volumes.
+func (h *HostVolumeChecker) hasVolumes(n *structs.Node) bool {
+	for _, req := range h.volumes {
+		vol, ok := n.HostVolumes[req.Source]
+		if !ok || vol == nil {
+			return false
+		}
+
+		if vol.ReadOnly && !req.ReadOnly {
+			return false
+		}
+	}
+	return true
+}
+
+// bridgeNetworkPlugins are the CNI plugins a node needs to set up the bridge
+// network of an allocation.
+var bridgeNetworkPlugins = []string{"bridge", "host-local", "loopback", "portmap"}
+
+// NetworkChecker is a FeasibilityChecker which returns whether a node supports
+// the network mode of the task group and has the host networks its ports are
+// pinned to.
+type NetworkChecker struct {
+	ctx          Context
+	mode         string
+	hostNetworks []string
+}
+
+// NewNetworkChecker creates a NetworkChecker. The network mode and host
+// networks are set per task group with SetTaskGroup.
+func NewNetworkChecker(ctx Context) *NetworkChecker {
+	return &NetworkChecker{
+		ctx: ctx,
+	}
+}
+
+// SetTaskGroup sets the network mode of the task group and collects the host
+// networks of the ports of the group and its tasks.
+func (c *NetworkChecker) SetTaskGroup(tg *structs.TaskGroup) {
+	c.mode = structs.NetworkModeHost
+	if len(tg.Networks) > 0 && tg.Networks[0].Mode != "" {
+		c.mode = tg.Networks[0].Mode
+	}
+
+	networks := append(structs.Networks{}, tg.Networks...)
+	for _, task := range tg.Tasks {
+		if task.Resources != nil {
+			networks = append(networks, task.Resources.Networks...)
+		}
+	}
+	c.hostNetworks = nil
+	seen := make(map[string]struct{})
+	for _, n := range networks {
+		for _, ports := range [][]structs.Port{n.ReservedPorts, n.DynamicPorts} {
+			for _, port := range ports {
+				name := port.HostNetwork
+				if name == "" || name == structs.DefaultHostNetwork {
+					continue
+				}
+				if _, ok := seen[name]; !ok {
+					seen[name] = struct{}{}
+					c.hostNetworks = append(c.hostNetworks, name)
+				}
+			}
+		}
+	}
+}
+
+func (c *NetworkChecker) Feasible(option *structs.Node) bool {
+	if !c.hasNetwork(option) {
+		c.ctx.Metrics().FilterNode(option, "missing network")
+		return false
+	}
+	if !c.hasHostNetworks(option) {
+		c.ctx.Metrics().FilterNode(option, "missing host network")
+		return false
+	}
+	return true
+}
+
+// hasHostNetworks is used to check that the node fingerprinted the host
+// networks the ports are pinned to.
+func (c *NetworkChecker) hasHostNetworks(option *structs.Node) bool {
+	for _, name := range c.hostNetworks {
+		if _, ok := option.Attributes["network.host_network."+name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hasNetwork is used to check that the node fingerprinted the CNI plugins or
+// configuration required by the network mode.
+func (c *NetworkChecker) hasNetwork(option *structs.Node) bool {
+	switch {
+	case c.mode == structs.NetworkModeHost:
+		return true
+	case c.mode == structs.NetworkModeBridge:
+		for _, plugin := range bridgeNetworkPlugins {
+			if _, ok := option.Attributes["plugins.cni.version."+plugin]; !ok {
+				return false
+			}
+		}
+		return true
+	case strings.HasPrefix(c.mode, structs.NetworkModeCNIPrefix):
+		name := strings.TrimPrefix(c.mode, structs.NetworkModeCNIPrefix)
+		_, ok := option.Attributes["plugins.cni.config."+name]
+		return ok
+	}
+	return false
+}
+
+// DeviceChecker is a FeasibilityChecker which returns whether a node has the
+// devices necessary to run the task group.
+type DeviceChecker struct {
+	ctx Context
+
+	// required is the set of requested devices that must exist on the node
+	required []*structs.RequestedDevice
+}
+
+// NewDeviceChecker creates a DeviceChecker. The requested devices are set per
+// task group with SetTaskGroup.
+func NewDeviceChecker(ctx Context) *DeviceChecker {
+	return &DeviceChecker{
+		ctx: ctx,
+	}
+}
+
+// SetTaskGroup collects the devices requested by the tasks of the group.
+func (c *DeviceChecker) SetTaskGroup(tg *structs.TaskGroup) {
+	c.required = nil
+	for _, task := range tg.Tasks {
+		if task.Resources == nil {
+			continue
+		}
+		c.required = append(c.required, task.Resources.Devices...)
+	}
+}
+
+func (c *DeviceChecker) Feasible(option *structs.Node) bool {
+	if c.hasDevices(option) {
+		return true
+	}
+
+	c.ctx.Metrics().FilterNode(option, "missing devices")
+	return false
+}
+
+// hasDevices is used to check that the node has enough healthy instances of
+// matching device groups to satisfy every device request.
+func (c *DeviceChecker) hasDevices(option *structs.Node) bool {
+	if len(c.required) == 0 {
+		return true
+	}
+
+	// Track the healthy instances of each device group not yet claimed by a
+	// request
+	available := make(map[*structs.NodeDeviceResource]uint64, len(option.Devices))
+	for _, d := range option.Devices {
+		available[d] = uint64(d.HealthyInstances())
+	}
+
+OUTER:
+	for _, req := range c.required {
+		for d, unused := range available {
+			if unused < req.Count || !nodeDeviceMatches(c.ctx, d, req) {
+				continue
+			}
+
+			available[d] -= req.Count
+			continue OUTER
+		}
+
+		// No device group can satisfy the request
+		return false
+	}
+
+	return true
+}
+
 // DistinctHostsIterator is a FeasibleIterator which returns nodes that pass the
 // distinct_hosts constraint. The constraint ensures that multiple allocations
 // do not exist on the same node.
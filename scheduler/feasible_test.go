@@ -190,6 +190,99 @@ func Test_HealthChecks(t *testing.T) {
 	}
 }
 
+func TestHostVolumeChecker(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+	nodes[1].HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"foo": {Name: "foo", Path: "/tmp/foo"},
+	}
+	nodes[2].HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"foo": {Name: "foo", Path: "/tmp/foo", ReadOnly: true},
+	}
+
+	cases := []struct {
+		Node             *structs.Node
+		RequestedVolumes map[string]*structs.VolumeRequest
+		Result           bool
+	}{
+		{ // Nil Volumes, no host volumes
+			Node:   nodes[0],
+			Result: true,
+		},
+		{ // Missing host volume
+			Node: nodes[0],
+			RequestedVolumes: map[string]*structs.VolumeRequest{
+				"foo": {Type: structs.VolumeTypeHost, Source: "foo"},
+			},
+			Result: false,
+		},
+		{ // Available host volume
+			Node: nodes[1],
+			RequestedVolumes: map[string]*structs.VolumeRequest{
+				"foo": {Type: structs.VolumeTypeHost, Source: "foo"},
+			},
+			Result: true,
+		},
+		{ // Writable request for a read only host volume
+			Node: nodes[2],
+			RequestedVolumes: map[string]*structs.VolumeRequest{
+				"foo": {Type: structs.VolumeTypeHost, Source: "foo"},
+			},
+			Result: false,
+		},
+		{ // Read only request for a read only host volume
+			Node: nodes[2],
+			RequestedVolumes: map[string]*structs.VolumeRequest{
+				"foo": {Type: structs.VolumeTypeHost, Source: "foo", ReadOnly: true},
+			},
+			Result: true,
+		},
+	}
+
+	checker := NewHostVolumeChecker(ctx)
+	for i, c := range cases {
+		checker.SetVolumes(c.RequestedVolumes)
+		if act := checker.Feasible(c.Node); act != c.Result {
+			t.Fatalf("case(%d) failed: got %v; want %v", i, act, c.Result)
+		}
+	}
+}
+
+func TestDriverChecker_HealthChecks_MultipleDrivers(t *testing.T) {
+	require := require.New(t)
+	_, ctx := testContext(t)
+
+	node := mock.Node()
+	node.Drivers = map[string]*structs.DriverInfo{
+		"foo": {
+			Detected:          true,
+			Healthy:           true,
+			HealthDescription: "running",
+			UpdateTime:        time.Now(),
+		},
+		"bar": {
+			Detected:          true,
+			Healthy:           false,
+			HealthDescription: "not running",
+			UpdateTime:        time.Now(),
+		},
+	}
+
+	// A healthy driver must not mask an unhealthy one
+	checker := NewDriverChecker(ctx, map[string]struct{}{
+		"foo": {},
+		"bar": {},
+	})
+	require.False(checker.Feasible(node))
+
+	node.Drivers["bar"].Healthy = true
+	require.True(checker.Feasible(node))
+}
+
 func TestConstraintChecker(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*structs.Node{
@@ -503,6 +596,227 @@ func TestCheckRegexpConstraint(t *testing.T) {
 
 // This test puts allocations on the node to test if it detects infeasibility of
 // nodes correctly and picks the only feasible one
+func TestDeviceChecker(t *testing.T) {
+	_, ctx := testContext(t)
+	node := mock.Node()
+	node.Devices = []*structs.NodeDeviceResource{
+		{
+			Vendor: "nvidia",
+			Type:   "gpu",
+			Name:   "Tesla K80",
+			Instances: []*structs.NodeDevice{
+				{ID: "GPU-1", Healthy: true},
+				{ID: "GPU-2", Healthy: true},
+				{ID: "GPU-3", Healthy: false},
+			},
+			Attributes: map[string]string{"memory": "11441"},
+		},
+	}
+
+	cases := []struct {
+		Devices []*structs.RequestedDevice
+		Result  bool
+	}{
+		{ // No devices
+			Result: true,
+		},
+		{ // Type only
+			Devices: []*structs.RequestedDevice{{Name: "gpu", Count: 2}},
+			Result:  true,
+		},
+		{ // Full name
+			Devices: []*structs.RequestedDevice{{Name: "nvidia/gpu/Tesla K80", Count: 1}},
+			Result:  true,
+		},
+		{ // Wrong vendor
+			Devices: []*structs.RequestedDevice{{Name: "amd/gpu", Count: 1}},
+			Result:  false,
+		},
+		{ // Unhealthy instances don't count
+			Devices: []*structs.RequestedDevice{{Name: "gpu", Count: 3}},
+			Result:  false,
+		},
+		{ // Requests share the instances
+			Devices: []*structs.RequestedDevice{
+				{Name: "gpu", Count: 1},
+				{Name: "nvidia/gpu", Count: 2},
+			},
+			Result: false,
+		},
+		{ // Satisfied constraint
+			Devices: []*structs.RequestedDevice{
+				{
+					Name:  "gpu",
+					Count: 1,
+					Constraints: []*structs.Constraint{
+						{LTarget: "${device.attr.memory}", RTarget: "8192", Operand: ">="},
+					},
+				},
+			},
+			Result: true,
+		},
+		{ // Unsatisfied constraint
+			Devices: []*structs.RequestedDevice{
+				{
+					Name:  "gpu",
+					Count: 1,
+					Constraints: []*structs.Constraint{
+						{LTarget: "${device.model}", RTarget: "Tesla V100", Operand: "="},
+					},
+				},
+			},
+			Result: false,
+		},
+	}
+
+	checker := NewDeviceChecker(ctx)
+	for i, c := range cases {
+		tg := &structs.TaskGroup{
+			Tasks: []*structs.Task{
+				{
+					Name: "web",
+					Resources: &structs.Resources{
+						Devices: c.Devices,
+					},
+				},
+			},
+		}
+		checker.SetTaskGroup(tg)
+		if act := checker.Feasible(node); act != c.Result {
+			t.Fatalf("case(%d) failed: got %v; want %v", i, act, c.Result)
+		}
+	}
+}
+
+func TestNetworkChecker(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+	}
+	for _, plugin := range []string{"bridge", "host-local", "loopback", "portmap"} {
+		nodes[1].Attributes["plugins.cni.version."+plugin] = "0.4.0"
+	}
+	nodes[1].Attributes["plugins.cni.config.mynet"] = "1"
+
+	cases := []struct {
+		Node   *structs.Node
+		Mode   string
+		Result bool
+	}{
+		{ // No group network
+			Node:   nodes[0],
+			Result: true,
+		},
+		{ // Host mode
+			Node:   nodes[0],
+			Mode:   structs.NetworkModeHost,
+			Result: true,
+		},
+		{ // Missing bridge plugins
+			Node:   nodes[0],
+			Mode:   structs.NetworkModeBridge,
+			Result: false,
+		},
+		{ // Bridge plugins
+			Node:   nodes[1],
+			Mode:   structs.NetworkModeBridge,
+			Result: true,
+		},
+		{ // CNI config
+			Node:   nodes[1],
+			Mode:   "cni/mynet",
+			Result: true,
+		},
+		{ // Missing CNI config
+			Node:   nodes[1],
+			Mode:   "cni/other",
+			Result: false,
+		},
+	}
+
+	checker := NewNetworkChecker(ctx)
+	for i, c := range cases {
+		tg := &structs.TaskGroup{Name: "web"}
+		if c.Mode != "" {
+			tg.Networks = []*structs.NetworkResource{{Mode: c.Mode}}
+		}
+		checker.SetTaskGroup(tg)
+		if act := checker.Feasible(c.Node); act != c.Result {
+			t.Fatalf("case(%d) failed: got %v; want %v", i, act, c.Result)
+		}
+	}
+}
+
+func TestNetworkChecker_HostNetwork(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+	}
+	nodes[1].Attributes["network.host_network.public"] = "eth1"
+
+	cases := []struct {
+		Node        *structs.Node
+		HostNetwork string
+		TaskPort    bool
+		Result      bool
+	}{
+		{ // Default network
+			Node:        nodes[0],
+			HostNetwork: structs.DefaultHostNetwork,
+			Result:      true,
+		},
+		{ // Missing host network of a group port
+			Node:        nodes[0],
+			HostNetwork: "public",
+			Result:      false,
+		},
+		{ // Missing host network of a task port
+			Node:        nodes[0],
+			HostNetwork: "public",
+			TaskPort:    true,
+			Result:      false,
+		},
+		{ // Host network of a group port
+			Node:        nodes[1],
+			HostNetwork: "public",
+			Result:      true,
+		},
+		{ // Host network of a task port
+			Node:        nodes[1],
+			HostNetwork: "public",
+			TaskPort:    true,
+			Result:      true,
+		},
+		{ // Missing other host network
+			Node:        nodes[1],
+			HostNetwork: "private",
+			Result:      false,
+		},
+	}
+
+	checker := NewNetworkChecker(ctx)
+	for i, c := range cases {
+		network := &structs.NetworkResource{
+			DynamicPorts: []structs.Port{{Label: "http", HostNetwork: c.HostNetwork}},
+		}
+		tg := &structs.TaskGroup{
+			Name:  "web",
+			Tasks: []*structs.Task{{Name: "web", Resources: &structs.Resources{}}},
+		}
+		if c.TaskPort {
+			tg.Tasks[0].Resources.Networks = []*structs.NetworkResource{network}
+		} else {
+			tg.Networks = []*structs.NetworkResource{network}
+		}
+		checker.SetTaskGroup(tg)
+		if act := checker.Feasible(c.Node); act != c.Result {
+			t.Fatalf("case(%d) failed: got %v; want %v", i, act, c.Result)
+		}
+	}
+}
+
 func TestDistinctHostsIterator_JobDistinctHosts(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*structs.Node{
@@ -126,7 +126,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) error {
 		structs.EvalTriggerNodeDrain, structs.EvalTriggerNodeUpdate,
 		structs.EvalTriggerRollingUpdate,
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
-		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc:
+		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
+		structs.EvalTriggerAllocStop, structs.EvalTriggerPreemption:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -417,7 +418,7 @@ func (s *GenericScheduler) computeJobAllocs() error {
 // destructive updates to place and the set of new placements to place.
 func (s *GenericScheduler) computePlacements(destructive, place []placementResult) error {
 	// Get the base nodes
-	nodes, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters)
+	nodes, byDC, err := readyNodesInDCs(s.state, s.job.Datacenters, s.job.NodePool)
 	if err != nil {
 		return err
 	}
@@ -487,11 +488,13 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 					NodeID:        option.Node.ID,
 					DeploymentID:  deploymentID,
 					TaskResources: option.TaskResources,
+					TaskDevices:   option.TaskDevices,
 					DesiredStatus: structs.AllocDesiredStatusRun,
 					ClientStatus:  structs.AllocClientStatusPending,
 
 					SharedResources: &structs.Resources{
-						DiskMB: tg.EphemeralDisk.SizeMB,
+						DiskMB:   tg.EphemeralDisk.SizeMB,
+						Networks: option.GroupNetworks,
 					},
 				}
 
@@ -516,6 +519,12 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 					}
 				}
 
+				// Preempt the allocations that make room for this one
+				for _, preempted := range option.PreemptedAllocs {
+					s.plan.AppendPreemptedAlloc(preempted, alloc.ID)
+					alloc.PreemptedAllocations = append(alloc.PreemptedAllocations, preempted.ID)
+				}
+
 				// Track the placement
 				s.plan.AppendAlloc(alloc)
 
@@ -263,6 +263,79 @@ func TestServiceSched_JobRegister_DiskConstraints(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_GroupNetwork(t *testing.T) {
+	require := require.New(t)
+	h := NewHarness(t)
+
+	// Create nodes, only two of which have the bridge CNI plugins
+	var bridgeNodes []string
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		if i > 0 {
+			for _, plugin := range bridgeNetworkPlugins {
+				node.Attributes["plugins.cni.version."+plugin] = "0.4.0"
+			}
+			node.ComputeClass()
+			bridgeNodes = append(bridgeNodes, node.ID)
+		}
+		require.NoError(h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Create a job with a bridge network reserving a static port so that
+	// each allocation needs its own node
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Count = 2
+	tg.Networks = []*structs.NetworkResource{
+		{
+			Mode:          structs.NetworkModeBridge,
+			MBits:         10,
+			ReservedPorts: []structs.Port{{Label: "admin", Value: 8080, To: 80}},
+			DynamicPorts:  []structs.Port{{Label: "http", To: 8080}},
+		},
+	}
+	tg.Tasks[0].Resources.Networks = nil
+	tg.Tasks[0].Services = nil
+	require.NoError(h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	require.NoError(h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	require.NoError(h.Process(NewServiceScheduler, eval))
+	require.Len(h.Plans, 1)
+
+	// Ensure the allocations were placed on the bridge nodes with the group
+	// network assigned
+	ws := memdb.NewWatchSet()
+	out, err := h.State.AllocsByJob(ws, job.Namespace, job.ID, false)
+	require.NoError(err)
+	require.Len(out, 2)
+	for _, alloc := range out {
+		require.Contains(bridgeNodes, alloc.NodeID)
+		require.Len(alloc.SharedResources.Networks, 1)
+
+		net := alloc.SharedResources.Networks[0]
+		require.Equal(structs.NetworkModeBridge, net.Mode)
+		require.NotEmpty(net.IP)
+		require.Equal([]structs.Port{{Label: "admin", Value: 8080, To: 80}}, net.ReservedPorts)
+		require.Len(net.DynamicPorts, 1)
+		require.NotZero(net.DynamicPorts[0].Value)
+		require.Equal(8080, net.DynamicPorts[0].To)
+	}
+	require.NotEqual(out[0].NodeID, out[1].NodeID)
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestServiceSched_JobRegister_DistinctHosts(t *testing.T) {
 	h := NewHarness(t)
 
@@ -972,6 +1045,64 @@ func TestServiceSched_JobRegister_AllocFail(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_QuotaLimit(t *testing.T) {
+	h := NewHarness(t)
+	require := require.New(t)
+
+	// Create some nodes
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		noErr(t, h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Attach a quota allowing four allocations to the namespace of the job
+	qs := mock.QuotaSpec()
+	noErr(t, h.State.UpsertQuotaSpecs(h.NextIndex(), []*structs.QuotaSpec{qs}))
+	ns := mock.Namespace()
+	ns.Quota = qs.Name
+	noErr(t, h.State.UpsertNamespaces(h.NextIndex(), []*structs.Namespace{ns}))
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	noErr(t, h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   ns.Name,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	noErr(t, h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	noErr(t, h.Process(NewServiceScheduler, eval))
+
+	// Ensure only the allocations within the quota were placed
+	require.Len(h.Plans, 1)
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	require.Len(planned, 4)
+
+	// Ensure the follow up eval is blocked on the quota
+	require.Len(h.CreateEvals, 1)
+	require.Equal(structs.EvalStatusBlocked, h.CreateEvals[0].Status)
+	require.Equal(qs.Name, h.CreateEvals[0].QuotaLimitReached)
+
+	// Ensure the failed allocations report the exhausted quota
+	require.Len(h.Evals, 1)
+	metrics, ok := h.Evals[0].FailedTGAllocs[job.TaskGroups[0].Name]
+	require.True(ok)
+	require.Equal([]string{"cpu exhausted (2500 > 2000)"}, metrics.QuotaExhausted)
+	require.EqualValues(5, metrics.CoalescedFailures)
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestServiceSched_JobRegister_CreateBlockedEval(t *testing.T) {
 	h := NewHarness(t)
 
@@ -2599,6 +2730,66 @@ func TestServiceSched_NodeDrain(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_AllocStop(t *testing.T) {
+	h := NewHarness(t)
+
+	// Create some nodes
+	var node *structs.Node
+	for i := 0; i < 2; i++ {
+		node = mock.Node()
+		noErr(t, h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Generate a fake job with an allocation that was asked to stop
+	job := mock.Job()
+	job.TaskGroups[0].Count = 1
+	noErr(t, h.State.UpsertJob(h.NextIndex(), job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = node.ID
+	alloc.Name = "my-job.web[0]"
+	alloc.DesiredTransition.Migrate = helper.BoolToPtr(true)
+	noErr(t, h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{alloc}))
+
+	// Create a mock evaluation to deal with the stop
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    50,
+		TriggeredBy: structs.EvalTriggerAllocStop,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	noErr(t, h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	err := h.Process(NewServiceScheduler, eval)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure a single plan that stops the alloc and places a replacement
+	if len(h.Plans) != 1 {
+		t.Fatalf("bad: %#v", h.Plans)
+	}
+	plan := h.Plans[0]
+	if len(plan.NodeUpdate[node.ID]) != 1 {
+		t.Fatalf("bad: %#v", plan)
+	}
+
+	var planned []*structs.Allocation
+	for _, allocList := range plan.NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	if len(planned) != 1 {
+		t.Fatalf("bad: %#v", plan)
+	}
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestServiceSched_NodeDrain_Down(t *testing.T) {
 	h := NewHarness(t)
 
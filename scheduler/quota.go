@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// QuotaIterator is a FeasibleIterator which yields no nodes if placing the
+// task group would exceed the quota attached to the namespace of the job in
+// the region.
+type QuotaIterator struct {
+	ctx    Context
+	source FeasibleIterator
+
+	// quota is the quota attached to the namespace of the job, and limit and
+	// used are its limit and usage in the region. They are nil if the job is
+	// not limited.
+	quota *structs.QuotaSpec
+	limit *structs.QuotaLimit
+	used  *structs.Resources
+
+	// exceeded are the dimensions of the limit placing the task group
+	// exceeds, and reported whether they were recorded in the metrics.
+	exceeded []string
+	reported bool
+}
+
+// NewQuotaIterator creates a QuotaIterator from a source.
+func NewQuotaIterator(ctx Context, source FeasibleIterator) *QuotaIterator {
+	return &QuotaIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *QuotaIterator) SetJob(job *structs.Job) {
+	iter.quota = nil
+	iter.limit = nil
+	iter.used = nil
+
+	state := iter.ctx.State()
+	ns, err := state.NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		iter.ctx.Logger().Printf(
+			"[ERR] scheduler.quota: failed to lookup namespace %q: %v", job.Namespace, err)
+		return
+	}
+	if ns == nil || ns.Quota == "" {
+		return
+	}
+
+	quota, err := state.QuotaSpecByName(nil, ns.Quota)
+	if err != nil {
+		iter.ctx.Logger().Printf(
+			"[ERR] scheduler.quota: failed to lookup quota %q: %v", ns.Quota, err)
+		return
+	}
+	if quota == nil {
+		return
+	}
+	limit := quota.RegionLimit(state.Config().Region)
+	if limit == nil {
+		return
+	}
+
+	usage, err := state.QuotaUsageByName(nil, quota.Name)
+	if err != nil {
+		iter.ctx.Logger().Printf(
+			"[ERR] scheduler.quota: failed to lookup usage of quota %q: %v", quota.Name, err)
+		return
+	}
+
+	iter.quota = quota
+	iter.limit = limit
+	iter.used = &structs.Resources{}
+	if usage != nil {
+		if used, ok := usage.Used[limit.Key()]; ok {
+			iter.used.AddQuotaResources(used.RegionLimit, 1)
+		}
+	}
+}
+
+func (iter *QuotaIterator) SetTaskGroup(tg *structs.TaskGroup) {
+	iter.exceeded = nil
+	iter.reported = false
+	if iter.limit == nil {
+		return
+	}
+
+	// Account for the placements and stops already made by the plan
+	used := iter.used.Copy()
+	if plan := iter.ctx.Plan(); plan != nil {
+		delta, err := iter.ctx.State().PlanQuotaDelta(nil, iter.quota.Name, plan)
+		if err != nil {
+			iter.ctx.Logger().Printf(
+				"[ERR] scheduler.quota: failed to compute usage of plan: %v", err)
+			return
+		}
+		used.AddQuotaResources(delta, 1)
+	}
+
+	iter.exceeded = iter.limit.ExceededBy(used, taskGroupConstraints(tg).size)
+}
+
+func (iter *QuotaIterator) Next() *structs.Node {
+	if len(iter.exceeded) == 0 {
+		return iter.source.Next()
+	}
+
+	// Record the exhausted dimensions once per placement
+	if !iter.reported {
+		iter.reported = true
+		iter.ctx.Metrics().ExhaustQuota(iter.exceeded)
+		iter.ctx.Eligibility().SetQuotaLimitReached(iter.quota.Name)
+	}
+	return nil
+}
+
+func (iter *QuotaIterator) Reset() {
+	iter.source.Reset()
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaIterator(t *testing.T) {
+	require := require.New(t)
+	state, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+	}
+	static := NewStaticIterator(ctx, nodes)
+
+	// Attach a quota to a namespace and use most of its CPU
+	qs := mock.QuotaSpec()
+	require.NoError(state.UpsertQuotaSpecs(1000, []*structs.QuotaSpec{qs}))
+	ns := mock.Namespace()
+	ns.Quota = qs.Name
+	require.NoError(state.UpsertNamespaces(1001, []*structs.Namespace{ns}))
+
+	existing := mock.Alloc()
+	existing.Namespace = ns.Name
+	existing.Job.Namespace = ns.Name
+	existing.Resources.CPU = 1500
+	require.NoError(state.UpsertAllocs(1002, []*structs.Allocation{existing}))
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	tg := job.TaskGroups[0]
+
+	quota := NewQuotaIterator(ctx, static)
+	quota.SetJob(job)
+	quota.SetTaskGroup(tg)
+
+	// The task group fits in the remaining quota
+	out := collectFeasible(quota)
+	require.Len(out, 2)
+	require.Empty(ctx.Metrics().QuotaExhausted)
+
+	// Once the plan places an allocation, it no longer does
+	planned := mock.Alloc()
+	planned.Namespace = ns.Name
+	planned.Resources = nil
+	ctx.Plan().NodeAllocation[nodes[0].ID] = []*structs.Allocation{planned}
+
+	ctx.Reset()
+	static.Reset()
+	quota.SetTaskGroup(tg)
+	out = collectFeasible(quota)
+	require.Empty(out)
+	require.Equal([]string{"cpu exhausted (2500 > 2000)"}, ctx.Metrics().QuotaExhausted)
+	require.Equal(qs.Name, ctx.Eligibility().QuotaLimitReached())
+
+	// Jobs of namespaces without a quota are not limited
+	job = mock.Job()
+	quota.SetJob(job)
+	quota.SetTaskGroup(job.TaskGroups[0])
+	static.Reset()
+	out = collectFeasible(quota)
+	require.Len(out, 2)
+}
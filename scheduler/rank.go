@@ -2,8 +2,8 @@ package scheduler
 
 import (
 	"fmt"
-
 	"math"
+	"sort"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -12,6 +12,11 @@ const (
 	// binPackingMaxFitScore is the maximum possible bin packing fitness score.
 	// This is used to normalize bin packing score to a value between 0 and 1
 	binPackingMaxFitScore = 18.0
+
+	// preemptionPriorityDelta is the minimum difference between the priority
+	// of the job being placed and the priority of a job for the allocations
+	// of the latter to be preempted
+	preemptionPriorityDelta = 10
 )
 
 // Rank is used to provide a score and various ranking metadata
@@ -22,6 +27,15 @@ type RankedNode struct {
 	FinalScore    float64
 	Scores        []float64
 	TaskResources map[string]*structs.Resources
+	TaskDevices   map[string][]*structs.AllocatedDeviceResource
+
+	// GroupNetworks is the network offered to the task group, shared by its
+	// tasks
+	GroupNetworks structs.Networks
+
+	// PreemptedAllocs is the set of allocations that must be preempted to
+	// place the task group on the node
+	PreemptedAllocs []*structs.Allocation
 
 	// Allocs is used to cache the proposed allocations on the
 	// node. This can be shared between iterators that require it.
@@ -53,6 +67,14 @@ func (r *RankedNode) SetTaskResources(task *structs.Task,
 	r.TaskResources[task.Name] = resource
 }
 
+func (r *RankedNode) SetTaskDevices(task *structs.Task,
+	devices []*structs.AllocatedDeviceResource) {
+	if r.TaskDevices == nil {
+		r.TaskDevices = make(map[string][]*structs.AllocatedDeviceResource)
+	}
+	r.TaskDevices[task.Name] = devices
+}
+
 // RankFeasibleIterator is used to iteratively yield nodes along
 // with ranking metadata. The iterators may manage some state for
 // performance optimizations.
@@ -145,6 +167,7 @@ type BinPackIterator struct {
 	evict     bool
 	priority  int
 	taskGroup *structs.TaskGroup
+	scoreFit  func(*structs.Node, *structs.Resources) float64
 }
 
 // NewBinPackIterator returns a BinPackIterator which tries to fit tasks
@@ -155,6 +178,7 @@ func NewBinPackIterator(ctx Context, source RankIterator, evict bool, priority i
 		source:   source,
 		evict:    evict,
 		priority: priority,
+		scoreFit: structs.ScoreFit,
 	}
 	return iter
 }
@@ -163,12 +187,28 @@ func (iter *BinPackIterator) SetPriority(p int) {
 	iter.priority = p
 }
 
+// SetEvict sets whether lower priority allocations may be preempted to place
+// the task group.
+func (iter *BinPackIterator) SetEvict(evict bool) {
+	iter.evict = evict
+}
+
+// SetSchedulerAlgorithm sets how the fit of the task group on a node is
+// scored. Spreading prefers the nodes with the most free resources while bin
+// packing prefers the nodes with the least.
+func (iter *BinPackIterator) SetSchedulerAlgorithm(algorithm structs.SchedulerAlgorithm) {
+	if algorithm == structs.SchedulerAlgorithmSpread {
+		iter.scoreFit = structs.ScoreFitSpread
+	} else {
+		iter.scoreFit = structs.ScoreFit
+	}
+}
+
 func (iter *BinPackIterator) SetTaskGroup(taskGroup *structs.TaskGroup) {
 	iter.taskGroup = taskGroup
 }
 
 func (iter *BinPackIterator) Next() *RankedNode {
-OUTER:
 	for {
 		// Get the next potential option
 		option := iter.source.Next()
@@ -185,66 +225,198 @@ OUTER:
 			continue
 		}
 
-		// Index the existing network usage
-		netIdx := structs.NewNetworkIndex()
-		netIdx.SetNode(option.Node)
-		netIdx.AddAllocs(proposed)
+		// Check if the task group fits next to the proposed allocations. If
+		// it does not and eviction is enabled, try to make room by preempting
+		// lower priority allocations.
+		util, dim, fit := iter.fit(option, proposed)
+		var preempted []*structs.Allocation
+		if !fit && iter.evict {
+			preempted, util, fit = iter.preempt(option, proposed)
+		}
+		option.PreemptedAllocs = preempted
+
+		// If it still does not fit, simply skip this node
+		if !fit {
+			iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
+			continue
+		}
+
+		// Score the fit normally otherwise
+		fitness := iter.scoreFit(option.Node, util)
+		normalizedFit := fitness / binPackingMaxFitScore
+		option.Scores = append(option.Scores, normalizedFit)
+		iter.ctx.Metrics().ScoreNode(option.Node, "binpack", normalizedFit)
 
-		// Assign the resources for each task
-		total := &structs.Resources{
-			DiskMB: iter.taskGroup.EphemeralDisk.SizeMB,
+		// Penalize nodes that require preemption so that nodes with free
+		// capacity are preferred. The penalty grows with the number of
+		// preempted allocations.
+		if n := len(preempted); n > 0 {
+			preemptionPenalty := -1 * float64(n) / float64(n+1)
+			option.Scores = append(option.Scores, preemptionPenalty)
+			iter.ctx.Metrics().ScoreNode(option.Node, "preemption", preemptionPenalty)
 		}
-		for _, task := range iter.taskGroup.Tasks {
-			taskResources := task.Resources.Copy()
+		return option
+	}
+}
+
+// fit assigns the resources of the task group on the option given the
+// proposed allocations of the node. It returns the resulting utilization of
+// the node, or the exhausted dimension if the task group does not fit.
+func (iter *BinPackIterator) fit(option *RankedNode, proposed []*structs.Allocation) (*structs.Resources, string, bool) {
+	// Index the existing network usage
+	netIdx := structs.NewNetworkIndex()
+	netIdx.SetNode(option.Node)
+	netIdx.AddAllocs(proposed)
+	defer netIdx.Release()
+
+	// Index the existing device usage
+	devAllocator := newDeviceAllocator(iter.ctx, option.Node)
+	devAllocator.AddAllocs(proposed)
+
+	total := &structs.Resources{
+		DiskMB: iter.taskGroup.EphemeralDisk.SizeMB,
+	}
+
+	// Assign the network shared by the tasks of the group
+	option.GroupNetworks = nil
+	if len(iter.taskGroup.Networks) > 0 {
+		ask := iter.taskGroup.Networks[0].Copy()
+		offer, err := netIdx.AssignNetwork(ask)
+		if offer == nil {
+			return nil, fmt.Sprintf("network: %s", err), false
+		}
+
+		// Reserve this to prevent the tasks from colliding
+		netIdx.AddReserved(offer)
+		option.GroupNetworks = structs.Networks{offer}
+		total.Networks = []*structs.NetworkResource{offer.Copy()}
+	}
 
-			// Check if we need a network resource
-			if len(taskResources.Networks) > 0 {
-				ask := taskResources.Networks[0]
-				offer, err := netIdx.AssignNetwork(ask)
+	// Assign the resources for each task
+	for _, task := range iter.taskGroup.Tasks {
+		taskResources := task.Resources.Copy()
+
+		// Check if we need a network resource
+		if len(taskResources.Networks) > 0 {
+			ask := taskResources.Networks[0]
+			offer, err := netIdx.AssignNetwork(ask)
+			if offer == nil {
+				return nil, fmt.Sprintf("network: %s", err), false
+			}
+
+			// Reserve this to prevent another task from colliding
+			netIdx.AddReserved(offer)
+
+			// Update the network ask to the offer
+			taskResources.Networks = []*structs.NetworkResource{offer}
+		}
+
+		// Check if we need to assign devices
+		if len(taskResources.Devices) > 0 {
+			var devices []*structs.AllocatedDeviceResource
+			for _, req := range taskResources.Devices {
+				offer, err := devAllocator.AssignDevice(req)
 				if offer == nil {
-					iter.ctx.Metrics().ExhaustedNode(option.Node,
-						fmt.Sprintf("network: %s", err))
-					netIdx.Release()
-					continue OUTER
+					return nil, fmt.Sprintf("devices: %s", err), false
 				}
 
 				// Reserve this to prevent another task from colliding
-				netIdx.AddReserved(offer)
-
-				// Update the network ask to the offer
-				taskResources.Networks = []*structs.NetworkResource{offer}
+				devAllocator.AddReserved(offer)
+				devices = append(devices, offer)
 			}
+			option.SetTaskDevices(task, devices)
+		}
 
-			// Store the task resource
-			option.SetTaskResources(task, taskResources)
+		// Store the task resource
+		option.SetTaskResources(task, taskResources)
 
-			// Accumulate the total resource requirement
-			total.Add(taskResources)
-		}
+		// Accumulate the total resource requirement
+		total.Add(taskResources)
+	}
 
-		// Add the resources we are trying to fit
-		proposed = append(proposed, &structs.Allocation{Resources: total})
+	// Add the resources we are trying to fit. The proposed allocations are
+	// copied as they may be cached on the option.
+	allocs := make([]*structs.Allocation, 0, len(proposed)+1)
+	allocs = append(allocs, proposed...)
+	allocs = append(allocs, &structs.Allocation{Resources: total})
 
-		// Check if these allocations fit, if they do not, simply skip this node
-		fit, dim, util, _ := structs.AllocsFit(option.Node, proposed, netIdx)
-		netIdx.Release()
-		if !fit {
-			iter.ctx.Metrics().ExhaustedNode(option.Node, dim)
+	// Check if these allocations fit
+	fit, dim, util, _ := structs.AllocsFit(option.Node, allocs, netIdx)
+	if !fit {
+		return nil, dim, false
+	}
+	return util, "", true
+}
+
+// preempt tries to make room for the task group on the option by preempting
+// allocations of jobs whose priority is at least preemptionPriorityDelta lower
+// than the priority of the job being placed. Allocations are preempted
+// greedily starting with the lowest priority ones, after which any allocation
+// that is not needed for the task group to fit is spared. It returns the
+// allocations to preempt and the resulting utilization of the node.
+func (iter *BinPackIterator) preempt(option *RankedNode, proposed []*structs.Allocation) ([]*structs.Allocation, *structs.Resources, bool) {
+	var candidates []*structs.Allocation
+	for _, alloc := range proposed {
+		if alloc.Job == nil || iter.priority-alloc.Job.Priority < preemptionPriorityDelta {
 			continue
 		}
+		candidates = append(candidates, alloc)
+	}
+	if len(candidates) == 0 {
+		return nil, nil, false
+	}
 
-		// XXX: For now we completely ignore evictions. We should use that flag
-		// to determine if its possible to evict other lower priority allocations
-		// to make room. This explodes the search space, so it must be done
-		// carefully.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Job.Priority < candidates[j].Job.Priority
+	})
 
-		// Score the fit normally otherwise
-		fitness := structs.ScoreFit(option.Node, util)
-		normalizedFit := fitness / binPackingMaxFitScore
-		option.Scores = append(option.Scores, normalizedFit)
-		iter.ctx.Metrics().ScoreNode(option.Node, "binpack", normalizedFit)
-		return option
+	// Preempt the lowest priority allocations until the task group fits
+	var preempted []*structs.Allocation
+	for i := range candidates {
+		if _, _, fit := iter.fit(option, allocsExcept(proposed, candidates[:i+1])); fit {
+			preempted = candidates[:i+1]
+			break
+		}
+	}
+	if preempted == nil {
+		return nil, nil, false
+	}
+
+	// Spare the allocations that are not needed, starting with the highest
+	// priority ones
+	for i := len(preempted) - 1; i >= 0; i-- {
+		without := make([]*structs.Allocation, 0, len(preempted)-1)
+		without = append(without, preempted[:i]...)
+		without = append(without, preempted[i+1:]...)
+		if _, _, fit := iter.fit(option, allocsExcept(proposed, without)); fit {
+			preempted = without
+		}
+	}
+
+	// Assign the resources again as the attempts above overwrite the task
+	// resources of the option
+	util, _, fit := iter.fit(option, allocsExcept(proposed, preempted))
+	if !fit {
+		return nil, nil, false
+	}
+	return preempted, util, true
+}
+
+// allocsExcept returns a new slice of the allocations without the ones to
+// remove.
+func allocsExcept(allocs, remove []*structs.Allocation) []*structs.Allocation {
+	removeSet := make(map[string]struct{}, len(remove))
+	for _, alloc := range remove {
+		removeSet[alloc.ID] = struct{}{}
+	}
+
+	out := make([]*structs.Allocation, 0, len(allocs))
+	for _, alloc := range allocs {
+		if _, ok := removeSet[alloc.ID]; !ok {
+			out = append(out, alloc)
+		}
 	}
+	return out
 }
 
 func (iter *BinPackIterator) Reset() {
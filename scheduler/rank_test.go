@@ -371,6 +371,220 @@ func TestBinPackIterator_ExistingAlloc_PlannedEvict(t *testing.T) {
 	}
 }
 
+func TestBinPackIterator_Preemption(t *testing.T) {
+	cases := []struct {
+		Name      string
+		Evict     bool
+		Priority  int
+		Preempted int
+		NoFit     bool
+	}{
+		{
+			Name:     "eviction disabled",
+			Evict:    false,
+			Priority: 50,
+			NoFit:    true,
+		},
+		{
+			Name:     "no lower priority allocations",
+			Evict:    true,
+			Priority: 25,
+			NoFit:    true,
+		},
+		{
+			Name:      "preempt lowest priority allocation",
+			Evict:     true,
+			Priority:  50,
+			Preempted: 0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			state, ctx := testContext(t)
+			nodes := []*RankedNode{
+				{
+					Node: &structs.Node{
+						ID: uuid.Generate(),
+						Resources: &structs.Resources{
+							CPU:      2048,
+							MemoryMB: 2048,
+						},
+					},
+				},
+			}
+			static := NewStaticRankIterator(ctx, nodes)
+
+			// Fill the node with the allocations of two low priority jobs
+			var allocs []*structs.Allocation
+			for i, priority := range []int{20, 30} {
+				job := mock.Job()
+				job.Priority = priority
+				allocs = append(allocs, &structs.Allocation{
+					Namespace: structs.DefaultNamespace,
+					ID:        uuid.Generate(),
+					EvalID:    uuid.Generate(),
+					NodeID:    nodes[0].Node.ID,
+					JobID:     job.ID,
+					Job:       job,
+					Resources: &structs.Resources{
+						CPU:      1024,
+						MemoryMB: 1024,
+					},
+					DesiredStatus: structs.AllocDesiredStatusRun,
+					ClientStatus:  structs.AllocClientStatusPending,
+					TaskGroup:     "web",
+				})
+				require.NoError(t, state.UpsertJobSummary(uint64(998+i), mock.JobSummary(job.ID)))
+			}
+			require.NoError(t, state.UpsertAllocs(1000, allocs))
+
+			taskGroup := &structs.TaskGroup{
+				EphemeralDisk: &structs.EphemeralDisk{},
+				Tasks: []*structs.Task{
+					{
+						Name: "web",
+						Resources: &structs.Resources{
+							CPU:      1024,
+							MemoryMB: 1024,
+						},
+					},
+				},
+			}
+			binp := NewBinPackIterator(ctx, static, c.Evict, c.Priority)
+			binp.SetTaskGroup(taskGroup)
+
+			out := collectRanked(binp)
+			if c.NoFit {
+				require.Empty(t, out)
+				return
+			}
+
+			require.Len(t, out, 1)
+			require.Len(t, out[0].PreemptedAllocs, 1)
+			require.Equal(t, allocs[c.Preempted].ID, out[0].PreemptedAllocs[0].ID)
+			require.Len(t, out[0].TaskResources, 1)
+		})
+	}
+}
+
+func TestBinPackIterator_Devices(t *testing.T) {
+	_, ctx := testContext(t)
+
+	devices := func() []*structs.NodeDeviceResource {
+		return []*structs.NodeDeviceResource{
+			{
+				Vendor: "nvidia",
+				Type:   "gpu",
+				Name:   "Tesla K80",
+				Instances: []*structs.NodeDevice{
+					{ID: "K80-1", Healthy: true},
+					{ID: "K80-2", Healthy: true},
+				},
+				Attributes: map[string]string{"memory": "11441"},
+			},
+			{
+				Vendor: "nvidia",
+				Type:   "gpu",
+				Name:   "Tesla V100",
+				Instances: []*structs.NodeDevice{
+					{ID: "V100-1", Healthy: true},
+				},
+				Attributes: map[string]string{"memory": "16160"},
+			},
+		}
+	}
+
+	nodes := []*RankedNode{
+		{
+			Node: &structs.Node{
+				Resources: &structs.Resources{
+					CPU:      2048,
+					MemoryMB: 2048,
+				},
+				Devices: devices(),
+			},
+		},
+		{
+			Node: &structs.Node{
+				// Missing the second K80
+				Resources: &structs.Resources{
+					CPU:      2048,
+					MemoryMB: 2048,
+				},
+				Devices: devices(),
+			},
+		},
+	}
+	nodes[1].Node.Devices[0].Instances[1].Healthy = false
+	static := NewStaticRankIterator(ctx, nodes)
+
+	taskGroup := &structs.TaskGroup{
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "train",
+				Resources: &structs.Resources{
+					CPU:      512,
+					MemoryMB: 512,
+					Devices: []*structs.RequestedDevice{
+						{
+							Name:  "nvidia/gpu",
+							Count: 1,
+							Affinities: []*structs.Affinity{
+								{
+									LTarget: "${device.model}",
+									RTarget: "Tesla V100",
+									Operand: "=",
+									Weight:  50,
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Name: "infer",
+				Resources: &structs.Resources{
+					CPU:      512,
+					MemoryMB: 512,
+					Devices: []*structs.RequestedDevice{
+						{
+							Name:  "gpu",
+							Count: 2,
+							Constraints: []*structs.Constraint{
+								{
+									LTarget: "${device.attr.memory}",
+									RTarget: "8000",
+									Operand: ">=",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	binp := NewBinPackIterator(ctx, static, false, 0)
+	binp.SetTaskGroup(taskGroup)
+
+	out := collectRanked(binp)
+	require.Len(t, out, 1)
+	require.Equal(t, nodes[0], out[0])
+
+	train := out[0].TaskDevices["train"]
+	require.Len(t, train, 1)
+	require.Equal(t, "Tesla V100", train[0].Name)
+	require.Equal(t, []string{"V100-1"}, train[0].DeviceIDs)
+
+	infer := out[0].TaskDevices["infer"]
+	require.Len(t, infer, 1)
+	require.Equal(t, "Tesla K80", infer[0].Name)
+	require.Equal(t, []string{"K80-1", "K80-2"}, infer[0].DeviceIDs)
+
+	require.Equal(t, 1, ctx.Metrics().DimensionExhausted["devices: no devices match request"])
+}
+
 func TestJobAntiAffinity_PlannedAlloc(t *testing.T) {
 	_, ctx := testContext(t)
 	nodes := []*RankedNode{
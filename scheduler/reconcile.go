@@ -194,8 +194,13 @@ func (a *allocReconciler) Compute() *reconcileResults {
 
 	// Detect if the deployment is paused
 	if a.deployment != nil {
-		a.deploymentPaused = a.deployment.Status == structs.DeploymentStatusPaused
+		a.deploymentPaused = a.deployment.Status == structs.DeploymentStatusPaused ||
+			a.deployment.Status == structs.DeploymentStatusPending
 		a.deploymentFailed = a.deployment.Status == structs.DeploymentStatusFailed
+	} else if a.awaitingPeerRegions() {
+		// The deployment created for the job will be pending, so hold off on
+		// the placements until the peer regions start it
+		a.deploymentPaused = true
 	}
 
 	// Reconcile each group
@@ -268,6 +273,28 @@ func (a *allocReconciler) cancelDeployments() {
 	}
 }
 
+// awaitingPeerRegions returns whether the deployment for the current version
+// of a multiregion job has yet to be created and will wait for the deployments
+// in the regions before it to complete.
+func (a *allocReconciler) awaitingPeerRegions() bool {
+	if a.batch || !a.job.MultiregionPending() {
+		return false
+	}
+
+	// The current version of the job has already been deployed
+	if d := a.oldDeployment; d != nil && d.JobCreateIndex == a.job.CreateIndex && d.JobVersion == a.job.Version {
+		return false
+	}
+
+	// Only groups with an update strategy are deployed
+	for _, tg := range a.job.TaskGroups {
+		if tg.Update != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // handleStop marks all allocations to be stopped, handling the lost case
 func (a *allocReconciler) handleStop(m allocMatrix) {
 	for group, as := range m {
@@ -325,6 +352,7 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 		dstate = &structs.DeploymentState{}
 		if tg.Update != nil {
 			dstate.AutoRevert = tg.Update.AutoRevert
+			dstate.AutoPromote = tg.Update.AutoPromote
 			dstate.ProgressDeadline = tg.Update.ProgressDeadline
 		}
 	}
@@ -511,6 +539,10 @@ func (a *allocReconciler) computeGroup(group string, all allocSet) bool {
 		// A previous group may have made the deployment already
 		if a.deployment == nil {
 			a.deployment = structs.NewDeployment(a.job)
+			if a.job.MultiregionPending() {
+				a.deployment.Status = structs.DeploymentStatusPending
+				a.deployment.StatusDescription = structs.DeploymentStatusDescriptionPendingForPeer
+			}
 			a.result.deployment = a.deployment
 		}
 
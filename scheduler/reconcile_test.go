@@ -2709,6 +2709,10 @@ func TestReconciler_PausedOrFailedDeployment_NoMorePlacements(t *testing.T) {
 			name:             "paused deployment",
 			deploymentStatus: structs.DeploymentStatusPaused,
 		},
+		{
+			name:             "pending deployment",
+			deploymentStatus: structs.DeploymentStatusPending,
+		},
 		{
 			name:             "failed deployment",
 			deploymentStatus: structs.DeploymentStatusFailed,
@@ -2758,6 +2762,66 @@ func TestReconciler_PausedOrFailedDeployment_NoMorePlacements(t *testing.T) {
 	}
 }
 
+// Tests the reconciler creates a pending deployment and makes no placements
+// for a multiregion job whose region waits for the regions before it
+func TestReconciler_Multiregion_PendingDeployment(t *testing.T) {
+	job := mock.Job()
+	job.TaskGroups[0].Update = noCanaryUpdate
+	job.Multiregion = &structs.Multiregion{
+		Strategy: &structs.MultiregionStrategy{MaxParallel: 1},
+		Regions: []*structs.MultiregionRegion{
+			{Name: "west"},
+			{Name: "east"},
+		},
+	}
+
+	t.Run("first region", func(t *testing.T) {
+		job := job.Copy()
+		job.Region = "west"
+
+		reconciler := NewAllocReconciler(testlog.Logger(t), allocUpdateFnIgnore, false, job.ID, job, nil, nil, nil, "")
+		r := reconciler.Compute()
+
+		d := structs.NewDeployment(job)
+		d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+			DesiredTotal: 10,
+		}
+
+		assertResults(t, r, &resultExpectation{
+			createDeployment: d,
+			place:            10,
+			desiredTGUpdates: map[string]*structs.DesiredUpdates{
+				job.TaskGroups[0].Name: {
+					Place: 10,
+				},
+			},
+		})
+	})
+
+	t.Run("waiting region", func(t *testing.T) {
+		job := job.Copy()
+		job.Region = "east"
+
+		reconciler := NewAllocReconciler(testlog.Logger(t), allocUpdateFnIgnore, false, job.ID, job, nil, nil, nil, "")
+		r := reconciler.Compute()
+
+		d := structs.NewDeployment(job)
+		d.Status = structs.DeploymentStatusPending
+		d.StatusDescription = structs.DeploymentStatusDescriptionPendingForPeer
+		d.TaskGroups[job.TaskGroups[0].Name] = &structs.DeploymentState{
+			DesiredTotal: 10,
+		}
+
+		assertResults(t, r, &resultExpectation{
+			createDeployment: d,
+			place:            0,
+			desiredTGUpdates: map[string]*structs.DesiredUpdates{
+				job.TaskGroups[0].Name: {},
+			},
+		})
+	})
+}
+
 // Tests the reconciler doesn't do any more destructive updates when the
 // deployment is paused or failed
 func TestReconciler_PausedOrFailedDeployment_NoMoreDestructiveUpdates(t *testing.T) {
@@ -20,9 +20,10 @@ const (
 // BuiltinSchedulers contains the built in registered schedulers
 // which are available
 var BuiltinSchedulers = map[string]Factory{
-	"service": NewServiceScheduler,
-	"batch":   NewBatchScheduler,
-	"system":  NewSystemScheduler,
+	"service":  NewServiceScheduler,
+	"batch":    NewBatchScheduler,
+	"system":   NewSystemScheduler,
+	"sysbatch": NewSysBatchScheduler,
 }
 
 // NewScheduler is used to instantiate and return a new scheduler
@@ -87,6 +88,22 @@ type State interface {
 	// LatestDeploymentByJobID returns the latest deployment matching the given
 	// job ID
 	LatestDeploymentByJobID(ws memdb.WatchSet, namespace, jobID string) (*structs.Deployment, error)
+
+	// SchedulerConfig returns the scheduler configuration
+	SchedulerConfig() (uint64, *structs.SchedulerConfiguration, error)
+
+	// NamespaceByName is used to lookup a namespace by name
+	NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error)
+
+	// QuotaSpecByName is used to lookup a quota specification by name
+	QuotaSpecByName(ws memdb.WatchSet, name string) (*structs.QuotaSpec, error)
+
+	// QuotaUsageByName is used to lookup the usage of a quota specification
+	QuotaUsageByName(ws memdb.WatchSet, name string) (*structs.QuotaUsage, error)
+
+	// PlanQuotaDelta returns how much the usage of the quota changes if the
+	// plan is applied
+	PlanQuotaDelta(ws memdb.WatchSet, quota string, plan *structs.Plan) (*structs.Resources, error)
 }
 
 // Planner interface is used to submit a task allocation plan.
@@ -36,6 +36,17 @@ type SelectOptions struct {
 	PreferredNodes []*structs.Node
 }
 
+// schedulerConfig returns the scheduler configuration of the cluster. A nil
+// configuration disables preemption and uses bin packing.
+func schedulerConfig(ctx Context) *structs.SchedulerConfiguration {
+	_, config, err := ctx.State().SchedulerConfig()
+	if err != nil {
+		ctx.Logger().Printf("[ERR] sched: failed to get scheduler configuration: %v", err)
+		return nil
+	}
+	return config
+}
+
 // GenericStack is the Stack used for the Generic scheduler. It is
 // designed to make better placement decisions at the cost of performance.
 type GenericStack struct {
@@ -43,11 +54,14 @@ type GenericStack struct {
 	ctx    Context
 	source *StaticIterator
 
-	wrappedChecks       *FeasibilityWrapper
-	quota               FeasibleIterator
-	jobConstraint       *ConstraintChecker
-	taskGroupDrivers    *DriverChecker
-	taskGroupConstraint *ConstraintChecker
+	wrappedChecks        *FeasibilityWrapper
+	quota                FeasibleIterator
+	jobConstraint        *ConstraintChecker
+	taskGroupDrivers     *DriverChecker
+	taskGroupConstraint  *ConstraintChecker
+	taskGroupHostVolumes *HostVolumeChecker
+	taskGroupDevices     *DeviceChecker
+	taskGroupNetwork     *NetworkChecker
 
 	distinctHostsConstraint    *DistinctHostsIterator
 	distinctPropertyConstraint *DistinctPropertyIterator
@@ -87,12 +101,21 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// Filter on task group constraints second
 	s.taskGroupConstraint = NewConstraintChecker(ctx, nil)
 
+	// Filter on task group host volumes
+	s.taskGroupHostVolumes = NewHostVolumeChecker(ctx)
+
+	// Filter on task group devices
+	s.taskGroupDevices = NewDeviceChecker(ctx)
+
+	// Filter on task group network mode
+	s.taskGroupNetwork = NewNetworkChecker(ctx)
+
 	// Create the feasibility wrapper which wraps all feasibility checks in
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
 	jobs := []FeasibilityChecker{s.jobConstraint}
-	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint}
+	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint, s.taskGroupHostVolumes, s.taskGroupDevices, s.taskGroupNetwork}
 	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs)
 
 	// Filter on distinct host constraints.
@@ -105,10 +128,9 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	rankSource := NewFeasibleRankIterator(ctx, s.distinctPropertyConstraint)
 
 	// Apply the bin packing, this depends on the resources needed
-	// by a particular task group. Only enable eviction for the service
-	// scheduler as that logic is expensive.
-	evict := !batch
-	s.binPack = NewBinPackIterator(ctx, rankSource, evict, 0)
+	// by a particular task group. Eviction is enabled when the job is set
+	// if the scheduler configuration allows preemption for its type.
+	s.binPack = NewBinPackIterator(ctx, rankSource, false, 0)
 
 	// Apply the job anti-affinity iterator. This is to avoid placing
 	// multiple allocations on the same node for this job.
@@ -157,6 +179,9 @@ func (s *GenericStack) SetJob(job *structs.Job) {
 	s.distinctHostsConstraint.SetJob(job)
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetPriority(job.Priority)
+	config := schedulerConfig(s.ctx)
+	s.binPack.SetEvict(config.PreemptionEnabled(job.Type))
+	s.binPack.SetSchedulerAlgorithm(config.EffectiveSchedulerAlgorithm(job.NodePool))
 	s.jobAntiAff.SetJob(job)
 	s.nodeAffinity.SetJob(job)
 	s.spread.SetJob(job)
@@ -195,6 +220,9 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) (*R
 	// Update the parameters of iterators
 	s.taskGroupDrivers.SetDrivers(tgConstr.drivers)
 	s.taskGroupConstraint.SetConstraints(tgConstr.constraints)
+	s.taskGroupHostVolumes.SetVolumes(tg.Volumes)
+	s.taskGroupDevices.SetTaskGroup(tg)
+	s.taskGroupNetwork.SetTaskGroup(tg)
 	s.distinctHostsConstraint.SetTaskGroup(tg)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
 	s.wrappedChecks.SetTaskGroup(tg.Name)
@@ -239,6 +267,9 @@ type SystemStack struct {
 	jobConstraint              *ConstraintChecker
 	taskGroupDrivers           *DriverChecker
 	taskGroupConstraint        *ConstraintChecker
+	taskGroupHostVolumes       *HostVolumeChecker
+	taskGroupDevices           *DeviceChecker
+	taskGroupNetwork           *NetworkChecker
 	distinctPropertyConstraint *DistinctPropertyIterator
 	binPack                    *BinPackIterator
 	scoreNorm                  *ScoreNormalizationIterator
@@ -266,12 +297,21 @@ func NewSystemStack(ctx Context) *SystemStack {
 	// Filter on task group constraints second
 	s.taskGroupConstraint = NewConstraintChecker(ctx, nil)
 
+	// Filter on task group host volumes
+	s.taskGroupHostVolumes = NewHostVolumeChecker(ctx)
+
+	// Filter on task group devices
+	s.taskGroupDevices = NewDeviceChecker(ctx)
+
+	// Filter on task group network mode
+	s.taskGroupNetwork = NewNetworkChecker(ctx)
+
 	// Create the feasibility wrapper which wraps all feasibility checks in
 	// which feasibility checking can be skipped if the computed node class has
 	// previously been marked as eligible or ineligible. Generally this will be
 	// checks that only needs to examine the single node to determine feasibility.
 	jobs := []FeasibilityChecker{s.jobConstraint}
-	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint}
+	tgs := []FeasibilityChecker{s.taskGroupDrivers, s.taskGroupConstraint, s.taskGroupHostVolumes, s.taskGroupDevices, s.taskGroupNetwork}
 	s.wrappedChecks = NewFeasibilityWrapper(ctx, s.quota, jobs, tgs)
 
 	// Filter on distinct property constraints.
@@ -281,9 +321,9 @@ func NewSystemStack(ctx Context) *SystemStack {
 	rankSource := NewFeasibleRankIterator(ctx, s.distinctPropertyConstraint)
 
 	// Apply the bin packing, this depends on the resources needed
-	// by a particular task group. Enable eviction as system jobs are high
-	// priority.
-	s.binPack = NewBinPackIterator(ctx, rankSource, true, 0)
+	// by a particular task group. Eviction is enabled when the job is set
+	// if the scheduler configuration allows preemption for its type.
+	s.binPack = NewBinPackIterator(ctx, rankSource, false, 0)
 
 	// Apply score normalization
 	s.scoreNorm = NewScoreNormalizationIterator(ctx, s.binPack)
@@ -299,6 +339,7 @@ func (s *SystemStack) SetJob(job *structs.Job) {
 	s.jobConstraint.SetConstraints(job.Constraints)
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetPriority(job.Priority)
+	s.binPack.SetEvict(schedulerConfig(s.ctx).PreemptionEnabled(job.Type))
 	s.ctx.Eligibility().SetJob(job)
 
 	if contextual, ok := s.quota.(ContextualIterator); ok {
@@ -318,6 +359,9 @@ func (s *SystemStack) Select(tg *structs.TaskGroup, options *SelectOptions) (*Ra
 	// Update the parameters of iterators
 	s.taskGroupDrivers.SetDrivers(tgConstr.drivers)
 	s.taskGroupConstraint.SetConstraints(tgConstr.constraints)
+	s.taskGroupHostVolumes.SetVolumes(tg.Volumes)
+	s.taskGroupDevices.SetTaskGroup(tg)
+	s.taskGroupNetwork.SetTaskGroup(tg)
 	s.wrappedChecks.SetTaskGroup(tg.Name)
 	s.distinctPropertyConstraint.SetTaskGroup(tg)
 	s.binPack.SetTaskGroup(tg)
@@ -1,7 +0,0 @@
-// +build !ent
-
-package scheduler
-
-func NewQuotaIterator(ctx Context, source FeasibleIterator) FeasibleIterator {
-	return source
-}
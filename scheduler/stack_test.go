@@ -317,6 +317,73 @@ func TestServiceStack_Select_BinPack_Overflow(t *testing.T) {
 	}
 }
 
+func TestServiceStack_Select_SchedulerAlgorithm(t *testing.T) {
+	state, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+	}
+	empty := nodes[0]
+	busy := nodes[1]
+	busy.Reserved.CPU = busy.Resources.CPU / 2
+	busy.Reserved.MemoryMB = busy.Resources.MemoryMB / 2
+
+	job := mock.Job()
+	job.NodePool = "spread-pool"
+
+	cases := []struct {
+		name     string
+		config   *structs.SchedulerConfiguration
+		expected *structs.Node
+	}{
+		{
+			name:     "default binpack",
+			config:   &structs.SchedulerConfiguration{},
+			expected: busy,
+		},
+		{
+			name: "spread",
+			config: &structs.SchedulerConfiguration{
+				SchedulerAlgorithm: structs.SchedulerAlgorithmSpread,
+			},
+			expected: empty,
+		},
+		{
+			name: "node pool override",
+			config: &structs.SchedulerConfiguration{
+				SchedulerAlgorithm: structs.SchedulerAlgorithmBinpack,
+				NodePoolSchedulerAlgorithms: map[string]structs.SchedulerAlgorithm{
+					"spread-pool": structs.SchedulerAlgorithmSpread,
+				},
+			},
+			expected: empty,
+		},
+		{
+			name: "other node pool override",
+			config: &structs.SchedulerConfiguration{
+				SchedulerAlgorithm: structs.SchedulerAlgorithmSpread,
+				NodePoolSchedulerAlgorithms: map[string]structs.SchedulerAlgorithm{
+					structs.NodePoolDefault: structs.SchedulerAlgorithmBinpack,
+				},
+			},
+			expected: empty,
+		},
+	}
+
+	for i, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.NoError(t, state.SchedulerSetConfig(uint64(100+i), c.config))
+
+			stack := NewGenericStack(false, ctx)
+			stack.SetNodes(nodes)
+			stack.SetJob(job)
+			node, _ := stack.Select(job.TaskGroups[0], &SelectOptions{})
+			require.NotNil(t, node)
+			require.Equal(t, c.expected, node.Node)
+		})
+	}
+}
+
 func TestSystemStack_SetNodes(t *testing.T) {
 	_, ctx := testContext(t)
 	stack := NewSystemStack(ctx)
@@ -16,12 +16,13 @@ const (
 	maxSystemScheduleAttempts = 5
 )
 
-// SystemScheduler is used for 'system' jobs. This scheduler is
-// designed for services that should be run on every client.
+// SystemScheduler is used for 'system' and 'sysbatch' jobs. This scheduler is
+// designed for services and batch work that should be run on every client.
 type SystemScheduler struct {
-	logger  *log.Logger
-	state   State
-	planner Planner
+	logger   *log.Logger
+	state    State
+	planner  Planner
+	sysbatch bool
 
 	eval       *structs.Evaluation
 	job        *structs.Job
@@ -49,6 +50,18 @@ func NewSystemScheduler(logger *log.Logger, state State, planner Planner) Schedu
 	}
 }
 
+// NewSysBatchScheduler is a factory function to instantiate a new sysbatch
+// scheduler. Allocations of sysbatch jobs that completed successfully are not
+// placed again on their node until the job is updated.
+func NewSysBatchScheduler(logger *log.Logger, state State, planner Planner) Scheduler {
+	return &SystemScheduler{
+		logger:   logger,
+		state:    state,
+		planner:  planner,
+		sysbatch: true,
+	}
+}
+
 // Process is used to handle a single evaluation.
 func (s *SystemScheduler) Process(eval *structs.Evaluation) error {
 	// Store the evaluation
@@ -58,7 +71,9 @@ func (s *SystemScheduler) Process(eval *structs.Evaluation) error {
 	switch eval.TriggeredBy {
 	case structs.EvalTriggerJobRegister, structs.EvalTriggerNodeUpdate,
 		structs.EvalTriggerJobDeregister, structs.EvalTriggerRollingUpdate,
-		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerNodeDrain:
+		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerNodeDrain,
+		structs.EvalTriggerAllocStop, structs.EvalTriggerPeriodicJob,
+		structs.EvalTriggerPreemption:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -100,7 +115,7 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Get the ready nodes in the required datacenters
 	if !s.job.Stopped() {
-		s.nodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters)
+		s.nodes, s.nodesByDC, err = readyNodesInDCs(s.state, s.job.Datacenters, s.job.NodePool)
 		if err != nil {
 			return false, fmt.Errorf("failed to get ready nodes: %v", err)
 		}
@@ -197,7 +212,7 @@ func (s *SystemScheduler) computeJobAllocs() error {
 	updateNonTerminalAllocsToLost(s.plan, tainted, allocs)
 
 	// Filter out the allocations in a terminal state
-	allocs, terminalAllocs := structs.FilterTerminalAllocs(allocs)
+	allocs, terminalAllocs := s.filterTerminalAllocs(allocs)
 
 	// Diff the required and existing allocations
 	diff := diffSystemAllocs(s.job, s.nodes, tainted, allocs, terminalAllocs)
@@ -257,6 +272,27 @@ func (s *SystemScheduler) computeJobAllocs() error {
 	return s.computePlacements(diff.place)
 }
 
+// filterTerminalAllocs splits the allocations into the non-terminal ones and
+// an index of the latest terminal allocations by name. For sysbatch jobs the
+// allocations of the current job version that completed successfully are kept
+// so that they are not placed again on their node.
+func (s *SystemScheduler) filterTerminalAllocs(allocs []*structs.Allocation) ([]*structs.Allocation, map[string]*structs.Allocation) {
+	if !s.sysbatch || s.job.Stopped() {
+		return structs.FilterTerminalAllocs(allocs)
+	}
+
+	var completed []*structs.Allocation
+	for _, alloc := range allocs {
+		if alloc.RanSuccessfully() && alloc.Job != nil &&
+			alloc.Job.JobModifyIndex == s.job.JobModifyIndex {
+			completed = append(completed, alloc)
+		}
+	}
+
+	allocs, terminalAllocs := structs.FilterTerminalAllocs(allocs)
+	return append(allocs, completed...), terminalAllocs
+}
+
 // computePlacements computes placements for allocations
 func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 	nodeByID := make(map[string]*structs.Node, len(s.nodes))
@@ -320,11 +356,13 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 				Metrics:       s.ctx.Metrics(),
 				NodeID:        option.Node.ID,
 				TaskResources: option.TaskResources,
+				TaskDevices:   option.TaskDevices,
 				DesiredStatus: structs.AllocDesiredStatusRun,
 				ClientStatus:  structs.AllocClientStatusPending,
 
 				SharedResources: &structs.Resources{
-					DiskMB: missing.TaskGroup.EphemeralDisk.SizeMB,
+					DiskMB:   missing.TaskGroup.EphemeralDisk.SizeMB,
+					Networks: option.GroupNetworks,
 				},
 			}
 
@@ -334,6 +372,12 @@ func (s *SystemScheduler) computePlacements(place []allocTuple) error {
 				alloc.PreviousAllocation = missing.Alloc.ID
 			}
 
+			// Preempt the allocations that make room for this one
+			for _, preempted := range option.PreemptedAllocs {
+				s.plan.AppendPreemptedAlloc(preempted, alloc.ID)
+				alloc.PreemptedAllocations = append(alloc.PreemptedAllocations, preempted.ID)
+			}
+
 			s.plan.AppendAlloc(alloc)
 		} else {
 			// Lazy initialize the failed map
@@ -505,6 +505,96 @@ func TestSystemSched_JobRegister_AllocFail(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestSystemSched_JobRegister_Preemption(t *testing.T) {
+	h := NewHarness(t)
+
+	// Enable preemption for system jobs
+	schedConfig := &structs.SchedulerConfiguration{
+		PreemptionConfig: structs.PreemptionConfig{
+			SystemSchedulerEnabled: true,
+		},
+	}
+	noErr(t, h.State.SchedulerSetConfig(h.NextIndex(), schedConfig))
+
+	// Create a node
+	node := mock.Node()
+	noErr(t, h.State.UpsertNode(h.NextIndex(), node))
+
+	// Fill the node with the allocation of a low priority job
+	lowJob := mock.Job()
+	lowJob.Priority = 20
+	noErr(t, h.State.UpsertJob(h.NextIndex(), lowJob))
+
+	lowAlloc := mock.Alloc()
+	lowAlloc.Job = lowJob
+	lowAlloc.JobID = lowJob.ID
+	lowAlloc.NodeID = node.ID
+	lowAlloc.Name = "my-job.web[0]"
+	lowAlloc.Resources = &structs.Resources{
+		CPU:      3700,
+		MemoryMB: 7000,
+	}
+	lowAlloc.TaskResources = map[string]*structs.Resources{
+		"web": {
+			CPU:      3700,
+			MemoryMB: 7000,
+		},
+	}
+	noErr(t, h.State.UpsertAllocs(h.NextIndex(), []*structs.Allocation{lowAlloc}))
+
+	// Create a system job
+	job := mock.SystemJob()
+	noErr(t, h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	noErr(t, h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	if err := h.Process(NewSystemScheduler, eval); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure a single plan
+	if len(h.Plans) != 1 {
+		t.Fatalf("bad: %#v", h.Plans)
+	}
+	plan := h.Plans[0]
+
+	// Ensure the plan placed the allocation and preempted the low priority one
+	planned := plan.NodeAllocation[node.ID]
+	if len(planned) != 1 {
+		t.Fatalf("bad: %#v", plan)
+	}
+	preempted := plan.NodePreemptions[node.ID]
+	if len(preempted) != 1 || preempted[0].ID != lowAlloc.ID {
+		t.Fatalf("bad: %#v", plan.NodePreemptions)
+	}
+	if !reflect.DeepEqual(planned[0].PreemptedAllocations, []string{lowAlloc.ID}) {
+		t.Fatalf("bad: %#v", planned[0].PreemptedAllocations)
+	}
+
+	// Ensure the low priority allocation was evicted
+	ws := memdb.NewWatchSet()
+	out, err := h.State.AllocByID(ws, lowAlloc.ID)
+	noErr(t, err)
+	if out.DesiredStatus != structs.AllocDesiredStatusEvict {
+		t.Fatalf("bad: %#v", out)
+	}
+	if out.PreemptedByAllocation != planned[0].ID {
+		t.Fatalf("bad: %#v", out)
+	}
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestSystemSched_JobModify(t *testing.T) {
 	h := NewHarness(t)
 
@@ -1529,3 +1619,151 @@ func TestSystemSched_QueuedAllocsMultTG(t *testing.T) {
 
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
+
+func TestSysBatchSched_JobRegister(t *testing.T) {
+	h := NewHarness(t)
+
+	// Create some nodes
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		noErr(t, h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Create a job
+	job := mock.SysBatchJob()
+	noErr(t, h.State.UpsertJob(h.NextIndex(), job))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	noErr(t, h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	if err := h.Process(NewSysBatchScheduler, eval); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure a single plan
+	if len(h.Plans) != 1 {
+		t.Fatalf("bad: %#v", h.Plans)
+	}
+	plan := h.Plans[0]
+
+	// Ensure the plan allocated on every node
+	var planned []*structs.Allocation
+	for _, allocList := range plan.NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	if len(planned) != 10 {
+		t.Fatalf("bad: %#v", plan)
+	}
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
+func TestSysBatchSched_CompletedAllocs(t *testing.T) {
+	h := NewHarness(t)
+
+	// Create some nodes
+	var nodes []*structs.Node
+	for i := 0; i < 2; i++ {
+		node := mock.Node()
+		nodes = append(nodes, node)
+		noErr(t, h.State.UpsertNode(h.NextIndex(), node))
+	}
+
+	// Create a job
+	job := mock.SysBatchJob()
+	noErr(t, h.State.UpsertJob(h.NextIndex(), job))
+	job, err := h.State.JobByID(nil, job.Namespace, job.ID)
+	noErr(t, err)
+
+	// Create an allocation that completed successfully on the first node and
+	// one that failed on the second node
+	var allocs []*structs.Allocation
+	for i, node := range nodes {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = "my-job.web[0]"
+		alloc.TaskGroup = "web"
+		alloc.ClientStatus = structs.AllocClientStatusComplete
+		alloc.TaskStates = map[string]*structs.TaskState{
+			"web": {
+				State:  structs.TaskStateDead,
+				Failed: i != 0,
+				Events: []*structs.TaskEvent{
+					structs.NewTaskEvent(structs.TaskTerminated).SetExitCode(i),
+				},
+			},
+		}
+		if i != 0 {
+			alloc.ClientStatus = structs.AllocClientStatusFailed
+		}
+		allocs = append(allocs, alloc)
+	}
+	noErr(t, h.State.UpsertAllocs(h.NextIndex(), allocs))
+
+	// Create a mock evaluation to re-evaluate the job
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerNodeUpdate,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	noErr(t, h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	if err := h.Process(NewSysBatchScheduler, eval); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Ensure only the failed allocation is replaced
+	if len(h.Plans) != 1 {
+		t.Fatalf("bad: %#v", h.Plans)
+	}
+	plan := h.Plans[0]
+	if len(plan.NodeAllocation) != 1 || len(plan.NodeAllocation[nodes[1].ID]) != 1 {
+		t.Fatalf("bad: %#v", plan.NodeAllocation)
+	}
+
+	// Updating the job runs it again on every node
+	job2 := job.Copy()
+	job2.Meta["version"] = "2"
+	noErr(t, h.State.UpsertJob(h.NextIndex(), job2))
+
+	eval2 := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	noErr(t, h.State.UpsertEvals(h.NextIndex(), []*structs.Evaluation{eval2}))
+
+	h = NewHarnessWithState(t, h.State)
+	if err := h.Process(NewSysBatchScheduler, eval2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if len(h.Plans) != 1 {
+		t.Fatalf("bad: %#v", h.Plans)
+	}
+	var planned []*structs.Allocation
+	for _, allocList := range h.Plans[0].NodeAllocation {
+		planned = append(planned, allocList...)
+	}
+	if len(planned) != 2 {
+		t.Fatalf("bad: %#v", h.Plans[0].NodeAllocation)
+	}
+}
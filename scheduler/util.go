@@ -5,9 +5,11 @@ import (
 	"log"
 	"math/rand"
 	"reflect"
+	"strings"
 
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ryanuber/go-glob"
 )
 
 // allocTuple is a tuple of the allocation name and potential alloc ID
@@ -109,7 +111,8 @@ func diffAllocs(job *structs.Job, taintedNodes map[string]*structs.Node,
 			// lost as the work was already successfully finished. However for
 			// service/system jobs, tasks should never complete. The check of
 			// batch type, defends against client bugs.
-			if exist.Job.Type == structs.JobTypeBatch && exist.RanSuccessfully() {
+			if (exist.Job.Type == structs.JobTypeBatch || exist.Job.Type == structs.JobTypeSysBatch) &&
+				exist.RanSuccessfully() {
 				goto IGNORE
 			}
 
@@ -219,12 +222,19 @@ func diffSystemAllocs(job *structs.Job, nodes []*structs.Node, taintedNodes map[
 	return result
 }
 
-// readyNodesInDCs returns all the ready nodes in the given datacenters and a
-// mapping of each data center to the count of ready nodes.
-func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]int, error) {
+// readyNodesInDCs returns all the ready nodes of the node pool in the given
+// datacenters and a mapping of each data center to the count of ready nodes.
+// Datacenters may be glob patterns such as "us-*", in which case the mapping
+// only contains the datacenters of the matching nodes.
+func readyNodesInDCs(state State, dcs []string, pool string) ([]*structs.Node, map[string]int, error) {
 	// Index the DCs
 	dcMap := make(map[string]int, len(dcs))
+	var patterns []string
 	for _, dc := range dcs {
+		if strings.Contains(dc, glob.GLOB) {
+			patterns = append(patterns, dc)
+			continue
+		}
 		dcMap[dc] = 0
 	}
 
@@ -252,7 +262,10 @@ func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]int
 		if node.SchedulingEligibility != structs.NodeSchedulingEligible {
 			continue
 		}
-		if _, ok := dcMap[node.Datacenter]; !ok {
+		if _, ok := dcMap[node.Datacenter]; !ok && !matchesDatacenter(patterns, node.Datacenter) {
+			continue
+		}
+		if !node.InNodePool(pool) {
 			continue
 		}
 		out = append(out, node)
@@ -261,6 +274,26 @@ func readyNodesInDCs(state State, dcs []string) ([]*structs.Node, map[string]int
 	return out, dcMap, nil
 }
 
+// matchesDatacenter returns whether the datacenter matches any of the glob
+// patterns.
+func matchesDatacenter(patterns []string, dc string) bool {
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, dc) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodePool returns the node pool of the job, treating jobs submitted before
+// node pools existed as being in the default pool.
+func nodePool(job *structs.Job) string {
+	if job.NodePool == "" {
+		return structs.NodePoolDefault
+	}
+	return job.NodePool
+}
+
 // retryMax is used to retry a callback until it returns success or
 // a maximum number of attempts is reached. An optional reset function may be
 // passed which is called after each failed iteration. If the reset function is
@@ -346,11 +379,32 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 		return true
 	}
 
+	// Moving the job to another node pool requires new placements
+	if nodePool(jobA) != nodePool(jobB) {
+		return true
+	}
+
 	// Check ephemeral disk
 	if !reflect.DeepEqual(a.EphemeralDisk, b.EphemeralDisk) {
 		return true
 	}
 
+	// Check the requested volumes
+	if !reflect.DeepEqual(a.Volumes, b.Volumes) {
+		return true
+	}
+
+	// Check the group network, whose ports can't be updated in place
+	if !reflect.DeepEqual(a.Networks, b.Networks) {
+		return true
+	}
+
+	// Check the Connect services, whose sidecar proxies are bootstrapped
+	// for a given registration
+	if connectServicesUpdated(a.Services, b.Services) {
+		return true
+	}
+
 	// Check each task
 	for _, at := range a.Tasks {
 		bt := b.LookupTask(at.Name)
@@ -378,6 +432,14 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 		if !reflect.DeepEqual(at.Templates, bt.Templates) {
 			return true
 		}
+		if !reflect.DeepEqual(at.VolumeMounts, bt.VolumeMounts) {
+			return true
+		}
+
+		// Inspect the requested devices
+		if !reflect.DeepEqual(at.Resources.Devices, bt.Resources.Devices) {
+			return true
+		}
 
 		// Check the metadata
 		if !reflect.DeepEqual(
@@ -409,6 +471,8 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 			return true
 		} else if ar.MemoryMB != br.MemoryMB {
 			return true
+		} else if ar.MemoryMaxMB != br.MemoryMaxMB {
+			return true
 		} else if ar.IOPS != br.IOPS {
 			return true
 		}
@@ -416,6 +480,30 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) bool {
 	return false
 }
 
+// connectServicesUpdated returns whether the Connect services of a task group
+// were added, removed or updated. The other group services are updated in
+// place.
+func connectServicesUpdated(servicesA, servicesB []*structs.Service) bool {
+	connectA := make(map[string]*structs.Service)
+	for _, s := range servicesA {
+		if s.Connect != nil {
+			connectA[s.Name] = s
+		}
+	}
+
+	for _, sb := range servicesB {
+		if sb.Connect == nil {
+			continue
+		}
+		sa, ok := connectA[sb.Name]
+		if !ok || !reflect.DeepEqual(sa, sb) {
+			return true
+		}
+		delete(connectA, sb.Name)
+	}
+	return len(connectA) != 0
+}
+
 // networkPortMap takes a network resource and returns a map of port labels to
 // values. The value for dynamic ports is disregarded even if it is set. This
 // makes this function suitable for comparing two network resources for changes.
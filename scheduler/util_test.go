@@ -312,7 +312,7 @@ func TestReadyNodesInDCs(t *testing.T) {
 	noErr(t, state.UpsertNode(1002, node3))
 	noErr(t, state.UpsertNode(1003, node4))
 
-	nodes, dc, err := readyNodesInDCs(state, []string{"dc1", "dc2"})
+	nodes, dc, err := readyNodesInDCs(state, []string{"dc1", "dc2"}, "")
 	if err != nil {
 		t.Fatalf("err: %v", err)
 	}
@@ -331,6 +331,91 @@ func TestReadyNodesInDCs(t *testing.T) {
 	}
 }
 
+func TestReadyNodesInDCs_NodePool(t *testing.T) {
+	state := state.TestStateStore(t)
+	node1 := mock.Node()
+	node2 := mock.Node()
+	node2.NodePool = "gpu"
+	node3 := mock.Node()
+	node3.NodePool = ""
+
+	noErr(t, state.UpsertNode(1000, node1))
+	noErr(t, state.UpsertNode(1001, node2))
+	noErr(t, state.UpsertNode(1002, node3))
+
+	cases := []struct {
+		pool     string
+		expected int
+	}{
+		{"", 2},
+		{structs.NodePoolDefault, 2},
+		{"gpu", 1},
+		{"batch", 0},
+		{structs.NodePoolAll, 3},
+	}
+	for _, c := range cases {
+		nodes, dc, err := readyNodesInDCs(state, []string{"dc1"}, c.pool)
+		noErr(t, err)
+		if len(nodes) != c.expected || dc["dc1"] != c.expected {
+			t.Fatalf("pool %q: expected %d nodes, got %d", c.pool, c.expected, len(nodes))
+		}
+	}
+}
+
+func TestReadyNodesInDCs_Wildcard(t *testing.T) {
+	state := state.TestStateStore(t)
+	node1 := mock.Node()
+	node1.Datacenter = "us-east-1"
+	node2 := mock.Node()
+	node2.Datacenter = "us-west-1"
+	node3 := mock.Node()
+	node3.Datacenter = "eu-west-1"
+
+	noErr(t, state.UpsertNode(1000, node1))
+	noErr(t, state.UpsertNode(1001, node2))
+	noErr(t, state.UpsertNode(1002, node3))
+
+	cases := []struct {
+		dcs      []string
+		expected map[string]int
+	}{
+		{
+			dcs:      []string{"us-*"},
+			expected: map[string]int{"us-east-1": 1, "us-west-1": 1},
+		},
+		{
+			dcs:      []string{"*-west-1"},
+			expected: map[string]int{"us-west-1": 1, "eu-west-1": 1},
+		},
+		{
+			dcs:      []string{"*"},
+			expected: map[string]int{"us-east-1": 1, "us-west-1": 1, "eu-west-1": 1},
+		},
+		{
+			dcs:      []string{"eu-*", "us-east-1", "ap-south-1"},
+			expected: map[string]int{"us-east-1": 1, "eu-west-1": 1, "ap-south-1": 0},
+		},
+		{
+			dcs:      []string{"ap-*"},
+			expected: map[string]int{},
+		},
+	}
+	for _, c := range cases {
+		nodes, dc, err := readyNodesInDCs(state, c.dcs, "")
+		noErr(t, err)
+		if !reflect.DeepEqual(dc, c.expected) {
+			t.Fatalf("datacenters %v: expected %v, got %v", c.dcs, c.expected, dc)
+		}
+		expected := 0
+		for _, count := range c.expected {
+			expected += count
+		}
+		if len(nodes) != expected {
+			t.Fatalf("datacenters %v: expected %d nodes, got %d", c.dcs, expected, len(nodes))
+		}
+	}
+}
+
 func TestRetryMax(t *testing.T) {
 	calls := 0
 	bad := func() (bool, error) {
@@ -570,6 +655,94 @@ func TestTasksUpdated(t *testing.T) {
 	if !tasksUpdated(j1, j18, name) {
 		t.Fatal("bad")
 	}
+
+	// Change the requested volumes
+	j19 := mock.Job()
+	j19.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+		"shared": {Name: "shared", Type: structs.VolumeTypeHost, Source: "shared"},
+	}
+	if !tasksUpdated(j1, j19, name) {
+		t.Fatal("bad")
+	}
+
+	// Change the volume mounts
+	j20 := mock.Job()
+	j20.TaskGroups[0].Tasks[0].VolumeMounts = []*structs.VolumeMount{
+		{Volume: "shared", Destination: "/srv"},
+	}
+	if !tasksUpdated(j1, j20, name) {
+		t.Fatal("bad")
+	}
+
+	// Change the requested devices
+	j21 := mock.Job()
+	j21.TaskGroups[0].Tasks[0].Resources.Devices = []*structs.RequestedDevice{
+		{Name: "nvidia/gpu", Count: 1},
+	}
+	if !tasksUpdated(j1, j21, name) {
+		t.Fatal("bad")
+	}
+
+	// Change the node pool
+	j22 := mock.Job()
+	j22.NodePool = "gpu"
+	if !tasksUpdated(j1, j22, name) {
+		t.Fatal("bad")
+	}
+
+	// Jobs without a node pool are in the default pool
+	j23 := mock.Job()
+	j23.NodePool = structs.NodePoolDefault
+	if tasksUpdated(j1, j23, name) {
+		t.Fatal("bad")
+	}
+
+	// Change the memory max
+	j24 := mock.Job()
+	j24.TaskGroups[0].Tasks[0].Resources.MemoryMaxMB = 1024
+	if !tasksUpdated(j1, j24, name) {
+		t.Fatal("bad")
+	}
+}
+
+func TestTasksUpdated_GroupServices(t *testing.T) {
+	j1 := mock.Job()
+	name := j1.TaskGroups[0].Name
+	j1.TaskGroups[0].Services = []*structs.Service{
+		{Name: "web", PortLabel: "http"},
+	}
+
+	// Group services without Connect are updated in place
+	j2 := j1.Copy()
+	j2.TaskGroups[0].Services[0].Tags = []string{"v2"}
+	if tasksUpdated(j1, j2, name) {
+		t.Fatal("bad")
+	}
+
+	// Adding Connect is destructive
+	j3 := j1.Copy()
+	j3.TaskGroups[0].Services[0].Connect = &structs.ConsulConnect{
+		SidecarService: &structs.ConsulSidecarService{Port: "connect-proxy-web"},
+	}
+	if !tasksUpdated(j1, j3, name) {
+		t.Fatal("bad")
+	}
+
+	// Updating a Connect service is destructive
+	j4 := j3.Copy()
+	j4.TaskGroups[0].Services[0].Connect.SidecarService.Proxy = &structs.ConsulProxy{
+		Upstreams: []*structs.ConsulUpstream{{DestinationName: "db", LocalBindPort: 5432}},
+	}
+	if !tasksUpdated(j3, j4, name) {
+		t.Fatal("bad")
+	}
+
+	// Removing a Connect service is destructive
+	j5 := j3.Copy()
+	j5.TaskGroups[0].Services = nil
+	if !tasksUpdated(j3, j5, name) {
+		t.Fatal("bad")
+	}
 }
 
 func TestEvictAndPlace_LimitLessThanAllocs(t *testing.T) {